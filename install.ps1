@@ -0,0 +1,181 @@
+# Socket CLI installation script for Windows.
+# Downloads and installs the appropriate Socket CLI binary for your platform.
+#
+# Usage: irm https://github.com/SocketDev/socket-cli/releases/latest/download/install.ps1 | iex
+#
+# `iex` takes no arguments, so arguments for `socket self install`, which
+# finishes the install, come from SOCKET_CLI_INSTALL_ARGS:
+#   $env:SOCKET_CLI_INSTALL_ARGS = '--skip-path'
+
+# Windows PowerShell 5.1 still offers TLS 1.0 first.
+[Net.ServicePointManager]::SecurityProtocol =
+  [Net.ServicePointManager]::SecurityProtocol -bor [Net.SecurityProtocolType]::Tls12
+
+function Write-Step([string]$Message) {
+  Write-Host "-> $Message" -ForegroundColor Cyan
+}
+
+function Write-Success([string]$Message) {
+  Write-Host "OK $Message" -ForegroundColor Green
+}
+
+function Write-Warn([string]$Message) {
+  Write-Host "!  $Message" -ForegroundColor Yellow
+}
+
+# Detect the platform triplet. PROCESSOR_ARCHITEW6432 is set for a 32-bit or
+# emulated PowerShell and holds the real architecture.
+function Get-SocketPlatform {
+  $arch = if ($env:PROCESSOR_ARCHITEW6432) {
+    $env:PROCESSOR_ARCHITEW6432
+  } else {
+    $env:PROCESSOR_ARCHITECTURE
+  }
+  switch ($arch) {
+    'AMD64' { return 'win32-x64' }
+    'ARM64' { return 'win32-arm64' }
+    default {
+      throw "Unsupported architecture: $arch. Socket CLI supports x64 and arm64, see https://github.com/SocketDev/socket-cli/issues"
+    }
+  }
+}
+
+# The registry manifest of the latest version of a package, $null when the
+# package is not published.
+function Get-LatestManifest([string]$PackageName) {
+  try {
+    return Invoke-RestMethod -UseBasicParsing "https://registry.npmjs.org/$PackageName/latest"
+  } catch {
+    return $null
+  }
+}
+
+# The SHA-512 of a file in the SSRI format npm publishes, e.g. "sha512-...".
+function Get-FileIntegrity([string]$File) {
+  $sha512 = [System.Security.Cryptography.SHA512]::Create()
+  $stream = [System.IO.File]::OpenRead($File)
+  try {
+    return 'sha512-' + [Convert]::ToBase64String($sha512.ComputeHash($stream))
+  } finally {
+    $stream.Dispose()
+    $sha512.Dispose()
+  }
+}
+
+# The directory install.sh and `socket self install` put a release in, named
+# by the SHA-256 of "<name>@<version>".
+function Get-DlxDir([string]$PackageName, [string]$Version) {
+  $sha256 = [System.Security.Cryptography.SHA256]::Create()
+  try {
+    $bytes = [System.Text.Encoding]::UTF8.GetBytes("$PackageName@$Version")
+    $hash = -join ($sha256.ComputeHash($bytes) | ForEach-Object { $_.ToString('x2') })
+  } finally {
+    $sha256.Dispose()
+  }
+  return Join-Path $HOME ".socket\_dlx\$hash"
+}
+
+# Put a directory first on the Windows user PATH. The value is kept
+# unexpanded, so entries like %USERPROFILE%\bin survive.
+function Add-UserPath([string]$Dir) {
+  $key = Get-Item -Path 'HKCU:\Environment'
+  $value = $key.GetValue('Path', '', 'DoNotExpandEnvironmentNames')
+  $entries = @($value -split ';' | Where-Object { $_ -and ($_.TrimEnd('\') -ne $Dir.TrimEnd('\')) })
+  Set-ItemProperty -Path 'HKCU:\Environment' -Name 'Path' -Type ExpandString -Value ((@($Dir) + $entries) -join ';')
+  # Setting a user variable broadcasts WM_SETTINGCHANGE to new terminals.
+  [Environment]::SetEnvironmentVariable('SOCKET_CLI_PATH_REFRESH', '1', 'User')
+  [Environment]::SetEnvironmentVariable('SOCKET_CLI_PATH_REFRESH', $null, 'User')
+}
+
+function Install-SocketCli {
+  # Set in the function, `iex` would otherwise change the calling session.
+  $ErrorActionPreference = 'Stop'
+
+  Write-Step 'Detecting your platform...'
+  $platform = Get-SocketPlatform
+  Write-Success "Platform detected: $platform"
+
+  # Prefer the current @socketsecurity/cli.exe.<triplet> tail; fall back to
+  # the frozen legacy @socketbin binaries until the new set is live.
+  Write-Step 'Fetching latest version from npm...'
+  $packageName = "@socketsecurity/cli.exe.$platform"
+  $manifest = Get-LatestManifest $packageName
+  if (-not $manifest) {
+    Write-Warn "$packageName is not available yet, using the legacy Socket CLI binary package"
+    $packageName = "@socketbin/cli-$platform"
+    $manifest = Get-LatestManifest $packageName
+    if (-not $manifest) {
+      throw 'Failed to fetch the latest version from the npm registry. This might be a temporary network issue, please try again.'
+    }
+  }
+  $version = $manifest.version
+  $expectedIntegrity = $manifest.dist.integrity
+  if (-not $expectedIntegrity -or -not $expectedIntegrity.StartsWith('sha512-')) {
+    throw "No sha512 integrity in the npm registry metadata for $packageName@$version. Refusing to install without a published checksum to verify against."
+  }
+  Write-Success "Found $packageName@$version"
+
+  $tempDir = Join-Path ([System.IO.Path]::GetTempPath()) "socket-cli-$([guid]::NewGuid())"
+  New-Item -ItemType Directory -Path $tempDir | Out-Null
+  try {
+    $tarball = Join-Path $tempDir 'package.tgz'
+    Write-Step 'Downloading Socket CLI...'
+    Invoke-WebRequest -UseBasicParsing -Uri $manifest.dist.tarball -OutFile $tarball
+
+    Write-Step 'Verifying integrity...'
+    $actualIntegrity = Get-FileIntegrity $tarball
+    if ($actualIntegrity -ne $expectedIntegrity) {
+      throw "Integrity check failed for $packageName@$version`n  expected: $expectedIntegrity`n  got:      $actualIntegrity`nNot installing. Please retry; if this persists, open an issue."
+    }
+    Write-Success 'Integrity verified (sha512)'
+
+    # tar.exe ships with Windows 10 1803 and later.
+    tar.exe -xzf $tarball -C $tempDir
+    if ($LASTEXITCODE -ne 0) {
+      throw "Could not extract $tarball"
+    }
+    $binary = Join-Path $tempDir 'package\bin\socket.exe'
+    if (-not (Test-Path $binary)) {
+      throw "Binary not found in $packageName@$version. This might be a temporary issue with the package, try again in a moment."
+    }
+
+    if ($packageName.StartsWith('@socketsecurity/')) {
+      # The cli.exe executables check the registry signature of the release,
+      # then add the `socket` command and put it on PATH themselves.
+      Write-Step 'Verifying signature...'
+      $installArgs = @()
+      if ($env:SOCKET_CLI_INSTALL_ARGS) {
+        $installArgs = @($env:SOCKET_CLI_INSTALL_ARGS -split '\s+' | Where-Object { $_ })
+      }
+      & $binary self install --from $tarball --release $version @installArgs
+      if ($LASTEXITCODE -ne 0) {
+        throw "socket self install failed with exit code $LASTEXITCODE"
+      }
+      return
+    }
+
+    $installDir = Join-Path (Get-DlxDir $packageName $version) 'package\bin'
+    New-Item -ItemType Directory -Force -Path $installDir | Out-Null
+    $installed = Join-Path $installDir 'socket.exe'
+    Copy-Item -Force $binary $installed
+    Write-Success "Binary ready at $installed"
+
+    $binDir = Join-Path $HOME '.socket\bin'
+    New-Item -ItemType Directory -Force -Path $binDir | Out-Null
+    Set-Content -Path (Join-Path $binDir 'socket.cmd') -Encoding ASCII -Value "@echo off`r`n`"$($installed.Replace('%', '%%'))`" %*"
+    Add-UserPath $binDir
+    Write-Success "Command ready: socket, on the Windows user PATH for new terminals"
+  } finally {
+    Remove-Item -Recurse -Force $tempDir -ErrorAction SilentlyContinue
+  }
+}
+
+Write-Host ''
+Write-Host 'Socket CLI Installer' -ForegroundColor Magenta
+Write-Host ''
+Install-SocketCli
+Write-Host ''
+Write-Host 'Quick start:'
+Write-Host '  socket --help           Get started with Socket'
+Write-Host '  socket self-update      Update to the latest version'
+Write-Host ''
@@ -1,6 +1,11 @@
 #!/usr/bin/env bash
 # Socket CLI installation script.
 # Downloads and installs the appropriate Socket CLI binary for your platform.
+#
+# Usage: curl -fsSL https://github.com/SocketDev/socket-cli/releases/latest/download/install.sh | bash
+#
+# Arguments are passed to `socket self install`, which finishes the install:
+#   curl -fsSL <url> | bash -s -- --bin-dir /usr/local/bin --skip-path
 
 set -euo pipefail
 
@@ -270,6 +275,7 @@ calculate_hash() {
 
 # Download and install Socket CLI.
 install_socket_cli() {
+  local self_install_args=("$@")
   local platform
   local version
   local package_name
@@ -394,6 +400,20 @@ install_socket_cli() {
     fi
   fi
 
+  # The cli.exe executables check the registry signature of the release,
+  # then add the `socket` command and put it on PATH themselves.
+  if [ "${package_name#@socketsecurity/}" != "$package_name" ]; then
+    step "Verifying signature..."
+    # The `+` form keeps an empty array safe under `set -u` in bash 3.2.
+    "$binary_path" self install --from "$temp_tarball" --release "$version" \
+      ${self_install_args[@]+"${self_install_args[@]}"}
+    rm -f "$temp_tarball"
+    trap - EXIT
+    echo ""
+    socket_brand "Happy securing!"
+    return
+  fi
+
   # Clean up tarball (EXIT trap also handles this in error paths).
   rm -f "$temp_tarball"
   trap - EXIT
@@ -456,7 +476,7 @@ main() {
   echo "  Secure your dependencies with Socket Security"
   echo ""
 
-  install_socket_cli
+  install_socket_cli "$@"
 }
 
 main "$@"
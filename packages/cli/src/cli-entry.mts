@@ -28,6 +28,14 @@ process.emitWarning = function (warning, ...args) {
     // Suppress the specific MaxListenersExceeded warning for AbortSignal.
     return
   }
+  if (
+    args[0] === 'ExperimentalWarning' &&
+    typeof warning === 'string' &&
+    warning.startsWith('SQLite')
+  ) {
+    // The scan history uses node:sqlite, which is not for the user to act on.
+    return
+  }
   Reflect.apply(originalEmitWarning, this, [warning, ...args])
 }
 
@@ -71,6 +79,8 @@ import {
   trackCliError,
   trackCliStart,
 } from './util/telemetry/integration.mts'
+import { flushUsageSpool } from './util/telemetry/usage.mts'
+import { getSelfUpdateDisabledReason } from './util/update/channel.mts'
 import { scheduleUpdateCheck } from './util/update/manager.mts'
 
 import { dlxManifest } from '@socketsecurity/lib-stable/dlx/manifest'
@@ -139,7 +149,13 @@ void (async () => {
   // Skip update checks in test environments or when explicitly disabled.
   // Note: Update checks create HTTP connections that may delay process exit by up to 30s
   // due to keep-alive timeouts. Set SOCKET_CLI_SKIP_UPDATE_CHECK=1 to disable.
-  if (!VITEST && !getCI() && !SOCKET_CLI_SKIP_UPDATE_CHECK) {
+  // Managed environments that turn off self-update get no notifications either.
+  if (
+    !VITEST &&
+    !getCI() &&
+    !SOCKET_CLI_SKIP_UPDATE_CHECK &&
+    !getSelfUpdateDisabledReason()
+  ) {
     const registryUrl = lookupRegistryUrl()
     // Unified update notifier handles both SEA and npm automatically.
     // Fire-and-forget: Don't await to avoid blocking on HTTP keep-alive timeouts.
@@ -165,6 +181,13 @@ void (async () => {
     runPreflightDownloads()
   }
 
+  // Send spooled anonymous usage events in the background, unless
+  // `socket telemetry` is about to inspect them. flushUsageSpool catches
+  // internally, so void can't drop a rejection.
+  if (!VITEST && process.argv[2] !== 'telemetry') {
+    void flushUsageSpool()
+  }
+
   try {
     await meowWithSubcommands(
       {
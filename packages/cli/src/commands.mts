@@ -2,16 +2,30 @@
 
 import { cmdAnalytics } from './commands/analytics/cmd-analytics.mts'
 import { cmdAsk } from './commands/ask/cmd-ask.mts'
+import { cmdAuditInstalled } from './commands/audit-installed/cmd-audit-installed.mts'
 import { cmdAuditLog } from './commands/audit-log/cmd-audit-log.mts'
+import { cmdBundle } from './commands/bundle/cmd-bundle.mts'
 import { cmdBundler } from './commands/bundler/cmd-bundler.mts'
+import { cmdCache } from './commands/cache/cmd-cache.mts'
 import { cmdCargo } from './commands/cargo/cmd-cargo.mts'
 import { cmdCI } from './commands/ci/cmd-ci.mts'
+import { cmdCompare } from './commands/compare/cmd-compare.mts'
+import { cmdCompletion } from './commands/completion/cmd-completion.mts'
 import { cmdConfig } from './commands/config/cmd-config.mts'
+import { cmdContainer } from './commands/container/cmd-container.mts'
+import { cmdDiagnose } from './commands/diagnose/cmd-diagnose.mts'
+import { cmdDoctor } from './commands/doctor/cmd-doctor.mts'
+import { cmdExplain } from './commands/explain/cmd-explain.mts'
 import { cmdFix } from './commands/fix/cmd-fix.mts'
 import { cmdGem } from './commands/gem/cmd-gem.mts'
+import { cmdGhapp } from './commands/ghapp/cmd-ghapp.mts'
 import { cmdGo } from './commands/go/cmd-go.mts'
+import { cmdGraph } from './commands/graph/cmd-graph.mts'
+import { cmdHistory } from './commands/history/cmd-history.mts'
+import { cmdHooks } from './commands/hooks/cmd-hooks.mts'
 import { cmdInstall } from './commands/install/cmd-install.mts'
 import { cmdJson } from './commands/json/cmd-json.mts'
+import { cmdLicense } from './commands/license/cmd-license.mts'
 import { cmdLogin } from './commands/login/cmd-login.mts'
 import { cmdLogout } from './commands/logout/cmd-logout.mts'
 import { cmdManifestCdxgen } from './commands/manifest/cmd-manifest-cdxgen.mts'
@@ -23,42 +37,68 @@ import { cmdNuget } from './commands/nuget/cmd-nuget.mts'
 import { cmdOops } from './commands/oops/cmd-oops.mts'
 import { cmdOptimize } from './commands/optimize/cmd-optimize.mts'
 import { cmdOrganizationDependencies } from './commands/organization/cmd-organization-dependencies.mts'
-import { cmdOrganizationPolicyLicense } from './commands/organization/cmd-organization-policy-license.mts'
 import { cmdOrganizationPolicySecurity } from './commands/organization/cmd-organization-policy-security.mts'
 import { cmdOrganization } from './commands/organization/cmd-organization.mts'
 import { cmdPackage } from './commands/package/cmd-package.mts'
 import { cmdPatch } from './commands/patch/cmd-patch.mts'
 import { cmdPip } from './commands/pip/cmd-pip.mts'
+import { cmdPipx } from './commands/pipx/cmd-pipx.mts'
+import { cmdPolicy } from './commands/policy/cmd-policy.mts'
 import { cmdPnpm } from './commands/pnpm/cmd-pnpm.mts'
+import { cmdPurl } from './commands/purl/cmd-purl.mts'
 import { cmdPyCli } from './commands/pycli/cmd-pycli.mts'
 import { cmdRawNpm } from './commands/raw-npm/cmd-raw-npm.mts'
 import { cmdRawNpx } from './commands/raw-npx/cmd-raw-npx.mts'
+import { cmdRegistry } from './commands/registry/cmd-registry.mts'
+import { cmdReport } from './commands/report/cmd-report.mts'
 import { cmdRepository } from './commands/repository/cmd-repository.mts'
+import { cmdSbom } from './commands/sbom/cmd-sbom.mts'
 import { cmdScan } from './commands/scan/cmd-scan.mts'
+import { cmdSchema } from './commands/schema/cmd-schema.mts'
+import { cmdSelf } from './commands/self/cmd-self.mts'
+import { cmdSelfUpdate } from './commands/self-update/cmd-self-update.mts'
 import { cmdSfw } from './commands/sfw/cmd-sfw.mts'
+import { cmdSuppressions } from './commands/suppressions/cmd-suppressions.mts'
+import { cmdTelemetry } from './commands/telemetry/cmd-telemetry.mts'
 import { cmdThreatFeed } from './commands/threat-feed/cmd-threat-feed.mts'
 import { cmdUninstall } from './commands/uninstall/cmd-uninstall.mts'
 import { cmdUv } from './commands/uv/cmd-uv.mts'
+import { cmdVerify } from './commands/verify/cmd-verify.mts'
+import { cmdVersion } from './commands/version/cmd-version.mts'
 import { cmdWhoami } from './commands/whoami/cmd-whoami.mts'
+import { cmdWhy } from './commands/why/cmd-why.mts'
 import { cmdWrapper } from './commands/wrapper/cmd-wrapper.mts'
 import { cmdYarn } from './commands/yarn/cmd-yarn.mts'
 
 export const rootCommands = {
   analytics: cmdAnalytics,
   ask: cmdAsk,
+  'audit-installed': cmdAuditInstalled,
   'audit-log': cmdAuditLog,
+  bundle: cmdBundle,
   bundler: cmdBundler,
+  cache: cmdCache,
   cargo: cmdCargo,
   cdxgen: cmdManifestCdxgen,
   ci: cmdCI,
+  compare: cmdCompare,
+  completion: cmdCompletion,
   config: cmdConfig,
+  container: cmdContainer,
   dependencies: cmdOrganizationDependencies,
+  diagnose: cmdDiagnose,
+  doctor: cmdDoctor,
+  explain: cmdExplain,
   fix: cmdFix,
   gem: cmdGem,
+  ghapp: cmdGhapp,
   go: cmdGo,
+  graph: cmdGraph,
+  history: cmdHistory,
+  hooks: cmdHooks,
   install: cmdInstall,
   json: cmdJson,
-  license: cmdOrganizationPolicyLicense,
+  license: cmdLicense,
   login: cmdLogin,
   logout: cmdLogout,
   manifest: cmdManifest,
@@ -72,18 +112,32 @@ export const rootCommands = {
   package: cmdPackage,
   patch: cmdPatch,
   pip: cmdPip,
+  pipx: cmdPipx,
   pnpm: cmdPnpm,
+  policy: cmdPolicy,
+  purl: cmdPurl,
   pycli: cmdPyCli,
   'raw-npm': cmdRawNpm,
   'raw-npx': cmdRawNpx,
+  registry: cmdRegistry,
+  report: cmdReport,
   repository: cmdRepository,
+  sbom: cmdSbom,
   scan: cmdScan,
+  schema: cmdSchema,
   security: cmdOrganizationPolicySecurity,
+  self: cmdSelf,
+  'self-update': cmdSelfUpdate,
   sfw: cmdSfw,
+  suppressions: cmdSuppressions,
+  telemetry: cmdTelemetry,
   'threat-feed': cmdThreatFeed,
   uninstall: cmdUninstall,
   uv: cmdUv,
+  verify: cmdVerify,
+  version: cmdVersion,
   whoami: cmdWhoami,
+  why: cmdWhy,
   wrapper: cmdWrapper,
   yarn: cmdYarn,
 }
@@ -118,26 +172,53 @@ export const rootCommandBuckets: Readonly<
   // Socket API — commands that hit the Socket.dev REST API.
   analytics: 'api',
   'audit-log': 'api',
+  bundle: 'api',
+  compare: 'api',
+  container: 'api',
+  explain: 'api',
+  ghapp: 'api',
+  graph: 'api',
+  license: 'api',
   organization: 'api',
   package: 'api',
+  report: 'api',
   repository: 'api',
+  sbom: 'api',
   scan: 'api',
   'threat-feed': 'api',
+  verify: 'api',
+  why: 'api',
   // Local tools — commands that wrap a local toolchain (npm, pip, …)
   // or operate on the local filesystem without API calls.
+  'audit-installed': 'tools',
+  history: 'tools',
+  hooks: 'tools',
   manifest: 'tools',
   npm: 'tools',
   npx: 'tools',
+  policy: 'tools',
+  purl: 'tools',
   pycli: 'tools',
   'raw-npm': 'tools',
   'raw-npx': 'tools',
+  registry: 'tools',
+  schema: 'tools',
   sfw: 'tools',
+  suppressions: 'tools',
   // CLI configuration — login / logout / install / etc.
+  cache: 'config',
+  completion: 'config',
   config: 'config',
+  diagnose: 'config',
+  doctor: 'config',
   install: 'config',
   login: 'config',
   logout: 'config',
+  self: 'config',
+  'self-update': 'config',
+  telemetry: 'config',
   uninstall: 'config',
+  version: 'config',
   whoami: 'config',
   wrapper: 'config',
 }
@@ -0,0 +1,272 @@
+/**
+ * Post-install verification for `socket audit-installed`.
+ *
+ * Checks every package of the installed node_modules tree against the npm
+ * lockfile and against the tarball the npm registry serves for its version.
+ * Manifest and lockfile scans trust that node_modules holds what the
+ * lockfile says; a poisoned cache, a compromised mirror or a manual edit
+ * breaks that without changing either file.
+ */
+
+import { pEach } from '@socketsecurity/lib-stable/promises/iterate'
+
+import {
+  findExtraneousPackages,
+  listInstalledPackages,
+  readNpmLockfile,
+} from './installed-tree.mts'
+import {
+  diffPackageContents,
+  fetchPackageDist,
+  fetchPackageTarball,
+  readTarballFiles,
+} from './package-contents.mts'
+import { NODE_MODULES } from '../../constants/packages.mts'
+import { isNpmRegistryVersion } from '../../util/lockfile/npm.mts'
+
+import type { InstalledPackage, NpmLockfileEntry } from './installed-tree.mts'
+import type { CResult } from '../../types.mts'
+
+export type AuditIssueKind =
+  | 'extraneous'
+  | 'lockfile-mismatch'
+  | 'missing-from-lockfile'
+  | 'tampered'
+
+export type AuditIssue = {
+  kind: AuditIssueKind
+  location: string
+  name: string
+  version: string
+  message: string
+  // The modified and missing files of tampered packages.
+  files?: string[] | undefined
+}
+
+export type UnverifiedPackage = {
+  location: string
+  name: string
+  version: string
+  reason: string
+}
+
+export type AuditInstalledResult = {
+  // The lockfile the tree was checked against, if the project has one.
+  lockfile?: string | undefined
+  packages: number
+  // Packages whose contents match their registry tarball.
+  verified: number
+  unverified: UnverifiedPackage[]
+  issues: AuditIssue[]
+}
+
+type NpmLockfilePackage = NpmLockfileEntry & {
+  inBundle?: boolean | undefined
+}
+
+const TARBALL_CONCURRENCY = 8
+
+const REGISTRY_TARBALL_HOST = 'registry.npmjs.org'
+
+/**
+ * Whether two integrity strings disagree. Only the algorithms both list are
+ * compared, old lockfiles may only have the sha1 the registry also lists.
+ */
+export function integritiesConflict(a: string, b: string): boolean {
+  const hashesOf = (integrity: string) =>
+    new Map(
+      integrity
+        .trim()
+        .split(/\s+/)
+        .map(hash => {
+          const index = hash.indexOf('-')
+          return [hash.slice(0, index), hash.slice(index + 1)] as const
+        }),
+    )
+  const hashesA = hashesOf(a)
+  const hashesB = hashesOf(b)
+  return [...hashesA].some(
+    ({ 0: algorithm, 1: hash }) =>
+      hashesB.has(algorithm) && hashesB.get(algorithm) !== hash,
+  )
+}
+
+function getTamperedMessage(modified: number, missing: number): string {
+  const counts = [
+    ...(modified ? [`${modified} modified`] : []),
+    ...(missing ? [`${missing} missing`] : []),
+  ]
+  const files = modified + missing === 1 ? 'file' : 'files'
+  return `${counts.join(', ')} ${files} compared to the registry tarball`
+}
+
+// Why a package cannot be compared with a registry tarball, if it can't.
+function getUnverifiedReason(
+  pkg: InstalledPackage,
+  entry: NpmLockfilePackage | undefined,
+): string | undefined {
+  if (entry?.inBundle) {
+    return 'bundled in its parent package'
+  }
+  if (!isNpmRegistryVersion(pkg.version)) {
+    return `version ${pkg.version} is not a registry version`
+  }
+  if (entry?.resolved) {
+    let host: string | undefined
+    try {
+      host = new URL(entry.resolved).host
+    } catch {}
+    if (host !== REGISTRY_TARBALL_HOST) {
+      return `installed from ${entry.resolved}`
+    }
+  }
+  return undefined
+}
+
+/**
+ * Audit the node_modules of cwd. Requests to the npm registry that fail
+ * fail the audit; packages that are not published there are reported as
+ * unverified.
+ */
+export async function auditInstalledPackages(
+  cwd: string,
+): Promise<CResult<AuditInstalledResult>> {
+  const lockfileCResult = await readNpmLockfile(cwd)
+  if (!lockfileCResult.ok) {
+    return lockfileCResult
+  }
+  const lockfile = lockfileCResult.data
+
+  const installed = await listInstalledPackages(cwd)
+  if (!installed.length) {
+    return {
+      ok: false,
+      message: 'No installed packages',
+      cause: `There are no packages in ${NODE_MODULES} of ${cwd}, install the dependencies first`,
+    }
+  }
+  const extraneous = await findExtraneousPackages(cwd, installed)
+
+  const issues: AuditIssue[] = []
+  const unverified: UnverifiedPackage[] = []
+  let verified = 0
+  // The first failed registry request, checked after all packages.
+  let failure = undefined as CResult<never> | undefined
+
+  const addIssue = (
+    pkg: InstalledPackage,
+    kind: AuditIssueKind,
+    message: string,
+    files?: string[] | undefined,
+  ) => {
+    issues.push({
+      kind,
+      location: pkg.location,
+      name: pkg.name,
+      version: pkg.version,
+      message,
+      ...(files ? { files } : {}),
+    })
+  }
+
+  const addUnverified = (pkg: InstalledPackage, reason: string) => {
+    unverified.push({
+      location: pkg.location,
+      name: pkg.name,
+      version: pkg.version,
+      reason,
+    })
+  }
+
+  await pEach(
+    installed,
+    async pkg => {
+      if (failure) {
+        return
+      }
+      const entry = lockfile?.packages[pkg.location] as
+        | NpmLockfilePackage
+        | undefined
+      if (lockfile && !entry) {
+        addIssue(pkg, 'missing-from-lockfile', `Not in ${lockfile.file}`)
+      } else if (entry?.version && entry.version !== pkg.version) {
+        addIssue(
+          pkg,
+          'lockfile-mismatch',
+          `Installed ${pkg.version}, ${lockfile!.file} has ${entry.version}`,
+        )
+      }
+      if (extraneous.has(pkg.location)) {
+        addIssue(
+          pkg,
+          'extraneous',
+          'Not a dependency of the project or of its dependencies',
+        )
+      }
+
+      const reason = getUnverifiedReason(pkg, entry)
+      if (reason) {
+        addUnverified(pkg, reason)
+        return
+      }
+      const distCResult = await fetchPackageDist(pkg.name, pkg.version)
+      if (!distCResult.ok) {
+        failure = distCResult
+        return
+      }
+      const dist = distCResult.data
+      if (!dist) {
+        addUnverified(pkg, 'not published on the npm registry')
+        return
+      }
+      if (
+        entry?.integrity &&
+        integritiesConflict(entry.integrity, dist.integrity)
+      ) {
+        addIssue(
+          pkg,
+          'lockfile-mismatch',
+          `The integrity in ${lockfile!.file} differs from the npm registry`,
+        )
+      }
+      const tarballCResult = await fetchPackageTarball(dist)
+      if (!tarballCResult.ok) {
+        failure = tarballCResult
+        return
+      }
+      const { missing, modified } = await diffPackageContents(
+        pkg.dir,
+        readTarballFiles(tarballCResult.data),
+      )
+      if (modified.length || missing.length) {
+        addIssue(
+          pkg,
+          'tampered',
+          getTamperedMessage(modified.length, missing.length),
+          [...modified, ...missing].sort(),
+        )
+      } else {
+        verified += 1
+      }
+    },
+    { concurrency: TARBALL_CONCURRENCY },
+  )
+  if (failure) {
+    return failure
+  }
+
+  const byLocation = (a: { location: string }, b: { location: string }) =>
+    a.location.localeCompare(b.location)
+  return {
+    ok: true,
+    data: {
+      lockfile: lockfile?.file,
+      packages: installed.length,
+      verified,
+      unverified: unverified.sort(byLocation),
+      issues: issues.sort(
+        (a, b) => byLocation(a, b) || a.kind.localeCompare(b.kind),
+      ),
+    },
+  }
+}
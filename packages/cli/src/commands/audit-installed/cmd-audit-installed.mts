@@ -0,0 +1,114 @@
+import path from 'node:path'
+
+import { handleAuditInstalled } from './handle-audit-installed.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'audit-installed'
+
+const description =
+  'Verify the installed node_modules against the lockfile and registry'
+
+const hidden = false
+
+export const cmdAuditInstalled = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] [CWD=.]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Walks the installed node_modules of the project and compares every
+    package with the tarball the npm registry serves for its version and
+    with the npm lockfile (npm-shrinkwrap.json, package-lock.json or
+    node_modules/.package-lock.json). Manifest scans trust that node_modules
+    holds what the lockfile says, this catches compromised caches and
+    mirrors and edits made after the install.
+
+    Reports packages that are:
+      - tampered: files differ from or are missing compared to the tarball
+      - lockfile-mismatch: the version or integrity differs from the lockfile
+      - missing-from-lockfile: installed but not in the lockfile
+      - extraneous: not a dependency of the project or of its dependencies
+
+    Any issue exits with code 3. Files install scripts add are not compared.
+    Linked, bundled, git and non-registry packages are listed as unverified.
+    Tarballs are downloaded from registry.npmjs.org.
+
+    Examples
+      $ ${command}
+      $ ${command} ./path/to/project --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags
+
+  const dryRun = !!cli.flags['dryRun']
+
+  let [cwd = '.'] = cli.input
+  cwd = path.resolve(process.cwd(), cwd)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: cli.input.length <= 1,
+      message: 'Audit one project at a time',
+      fail: 'too many arguments',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('installed package audit', {
+      cwd,
+    })
+    return
+  }
+
+  await handleAuditInstalled({ cwd, outputKind })
+}
@@ -0,0 +1,26 @@
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { auditInstalledPackages } from './audit-installed.mts'
+import { outputAuditInstalled } from './output-audit-installed.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export async function handleAuditInstalled({
+  cwd,
+  outputKind,
+}: {
+  cwd: string
+  outputKind: OutputKind
+}): Promise<void> {
+  debug(`Auditing the installed packages of ${cwd}`)
+
+  const spinner = getDefaultSpinner()
+  spinner.start('Comparing installed packages with the npm registry…')
+  const result = await auditInstalledPackages(cwd)
+  spinner.stop()
+
+  debugDir({ result })
+
+  await outputAuditInstalled(result, outputKind)
+}
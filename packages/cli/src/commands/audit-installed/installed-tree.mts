@@ -0,0 +1,235 @@
+/**
+ * The installed node_modules tree and npm lockfile for
+ * `socket audit-installed`.
+ *
+ * Packages are identified by their location from the project root, e.g.
+ * `node_modules/a/node_modules/@scope/b`, the same keys the `packages` of a
+ * v2 or v3 package-lock.json uses. Symlinked packages (workspaces, `npm
+ * link`) are left out, their contents are not installed from a tarball.
+ */
+
+import { existsSync, promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { NODE_MODULES, PACKAGE_JSON } from '../../constants/packages.mts'
+import { PACKAGE_LOCK_JSON } from '../../constants/paths.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type InstalledPackage = {
+  // Path from the project root, with forward slashes.
+  location: string
+  name: string
+  version: string
+  dir: string
+  // Names the package depends on, including optional and peer ones.
+  dependencies: string[]
+}
+
+export type NpmLockfileEntry = {
+  dev?: boolean | undefined
+  integrity?: string | undefined
+  link?: boolean | undefined
+  optional?: boolean | undefined
+  resolved?: string | undefined
+  version?: string | undefined
+}
+
+export type NpmLockfile = {
+  // File name relative to the project root.
+  file: string
+  packages: Record<string, NpmLockfileEntry>
+}
+
+type PackageJsonDeps = {
+  dependencies?: Record<string, string> | undefined
+  devDependencies?: Record<string, string> | undefined
+  name?: string | undefined
+  optionalDependencies?: Record<string, string> | undefined
+  peerDependencies?: Record<string, string> | undefined
+  version?: string | undefined
+}
+
+// npm-shrinkwrap.json wins over package-lock.json, the hidden lockfile npm
+// writes into node_modules is the fallback.
+const NPM_LOCKFILES = [
+  'npm-shrinkwrap.json',
+  PACKAGE_LOCK_JSON,
+  `${NODE_MODULES}/.package-lock.json`,
+]
+
+function getDependencyNames(
+  pkgJson: PackageJsonDeps,
+  includeDev: boolean,
+): string[] {
+  return [
+    ...new Set([
+      ...Object.keys(pkgJson.dependencies ?? {}),
+      ...Object.keys(pkgJson.optionalDependencies ?? {}),
+      ...Object.keys(pkgJson.peerDependencies ?? {}),
+      ...(includeDev ? Object.keys(pkgJson.devDependencies ?? {}) : []),
+    ]),
+  ]
+}
+
+async function readPackageJson(
+  dir: string,
+): Promise<PackageJsonDeps | undefined> {
+  try {
+    return JSON.parse(
+      await fs.readFile(path.join(dir, PACKAGE_JSON), 'utf8'),
+    ) as PackageJsonDeps
+  } catch {
+    return undefined
+  }
+}
+
+async function listPackageDirs(
+  nodeModulesDir: string,
+): Promise<Array<{ dir: string; name: string }>> {
+  let entries
+  try {
+    entries = await fs.readdir(nodeModulesDir, { withFileTypes: true })
+  } catch {
+    return []
+  }
+  const dirs: Array<{ dir: string; name: string }> = []
+  for (const entry of entries) {
+    // Skips .bin, .cache and the hidden lockfile.
+    if (entry.name.startsWith('.') || !entry.isDirectory()) {
+      continue
+    }
+    const dir = path.join(nodeModulesDir, entry.name)
+    if (!entry.name.startsWith('@')) {
+      dirs.push({ dir, name: entry.name })
+      continue
+    }
+    for (const scoped of await fs.readdir(dir, { withFileTypes: true })) {
+      if (scoped.isDirectory()) {
+        dirs.push({
+          dir: path.join(dir, scoped.name),
+          name: `${entry.name}/${scoped.name}`,
+        })
+      }
+    }
+  }
+  return dirs
+}
+
+/**
+ * Every package installed under the node_modules of cwd, nested ones
+ * included, sorted by location.
+ */
+export async function listInstalledPackages(
+  cwd: string,
+): Promise<InstalledPackage[]> {
+  const installed: InstalledPackage[] = []
+  const visit = async (parentDir: string, parentLocation: string) => {
+    const nodeModulesDir = path.join(parentDir, NODE_MODULES)
+    for (const { dir, name } of await listPackageDirs(nodeModulesDir)) {
+      const location = `${parentLocation ? `${parentLocation}/` : ''}${NODE_MODULES}/${name}`
+      const pkgJson = await readPackageJson(dir)
+      if (!pkgJson?.name || !pkgJson.version) {
+        continue
+      }
+      installed.push({
+        location,
+        name: pkgJson.name,
+        version: pkgJson.version,
+        dir,
+        dependencies: getDependencyNames(pkgJson, false),
+      })
+      await visit(dir, location)
+    }
+  }
+  await visit(cwd, '')
+  return installed.sort((a, b) => a.location.localeCompare(b.location))
+}
+
+/**
+ * Read the npm lockfile of cwd. Only lockfileVersion 2 and 3 list the
+ * installed locations.
+ */
+export async function readNpmLockfile(
+  cwd: string,
+): Promise<CResult<NpmLockfile | undefined>> {
+  const file = NPM_LOCKFILES.find(name => existsSync(path.join(cwd, name)))
+  if (!file) {
+    return { ok: true, data: undefined }
+  }
+  let lockfile: {
+    lockfileVersion?: number | undefined
+    packages?: Record<string, NpmLockfileEntry> | undefined
+  }
+  try {
+    lockfile = JSON.parse(await fs.readFile(path.join(cwd, file), 'utf8'))
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Invalid lockfile',
+      cause: `Could not parse ${file}: ${(e as Error).message}`,
+    }
+  }
+  if (!lockfile.packages) {
+    return {
+      ok: false,
+      message: 'Unsupported lockfile',
+      cause: `${file} is lockfileVersion ${lockfile.lockfileVersion ?? 1}, run \`npm install\` with npm 7 or later to upgrade it to version 3`,
+    }
+  }
+  return { ok: true, data: { file, packages: lockfile.packages } }
+}
+
+/**
+ * The location `from` resolves `name` to, walking up the node_modules
+ * folders the way Node.js does.
+ */
+export function resolveInstalledLocation(
+  from: string,
+  name: string,
+  locations: ReadonlySet<string>,
+): string | undefined {
+  let base = from
+  while (true) {
+    const candidate = `${base ? `${base}/` : ''}${NODE_MODULES}/${name}`
+    if (locations.has(candidate)) {
+      return candidate
+    }
+    if (!base) {
+      return undefined
+    }
+    const index = base.lastIndexOf(`/${NODE_MODULES}/`)
+    base = index === -1 ? '' : base.slice(0, index)
+  }
+}
+
+/**
+ * Locations no dependency of the project, dev dependencies included, leads
+ * to.
+ */
+export async function findExtraneousPackages(
+  cwd: string,
+  installed: InstalledPackage[],
+): Promise<Set<string>> {
+  const byLocation = new Map(installed.map(pkg => [pkg.location, pkg]))
+  const locations = new Set(byLocation.keys())
+  const rootPkgJson = (await readPackageJson(cwd)) ?? {}
+  const reached = new Set<string>()
+  const queue: Array<{ from: string; names: string[] }> = [
+    { from: '', names: getDependencyNames(rootPkgJson, true) },
+  ]
+  while (queue.length) {
+    const { from, names } = queue.pop()!
+    for (const name of names) {
+      const location = resolveInstalledLocation(from, name, locations)
+      if (location && !reached.has(location)) {
+        reached.add(location)
+        queue.push({
+          from: location,
+          names: byLocation.get(location)!.dependencies,
+        })
+      }
+    }
+  }
+  return new Set([...locations].filter(location => !reached.has(location)))
+}
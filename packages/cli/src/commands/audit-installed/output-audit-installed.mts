@@ -0,0 +1,126 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { LOG_SYMBOLS } from '@socketsecurity/lib-stable/logger/symbols'
+
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { AuditInstalledResult } from './audit-installed.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function plural(count: number, noun: string): string {
+  return `${count} ${noun}${count === 1 ? '' : 's'}`
+}
+
+function getSummary(result: AuditInstalledResult): string {
+  const against = result.lockfile
+    ? ` against ${result.lockfile} and the npm registry`
+    : ' against the npm registry'
+  return `Audited ${plural(result.packages, 'installed package')}${against}: ${result.verified} verified, ${result.unverified.length} unverified, ${plural(result.issues.length, 'issue')}`
+}
+
+export function formatAuditInstalled(result: AuditInstalledResult): string {
+  const lines = [getSummary(result)]
+  if (!result.lockfile) {
+    lines.push(
+      `${LOG_SYMBOLS['warn']} No npm lockfile, packages missing from the lockfile cannot be detected`,
+    )
+  }
+  if (result.issues.length) {
+    lines.push('')
+    for (const issue of result.issues) {
+      lines.push(
+        `${LOG_SYMBOLS['fail']} ${issue.location}@${issue.version} [${issue.kind}] ${issue.message}`,
+      )
+      for (const file of issue.files ?? []) {
+        lines.push(`    ${file}`)
+      }
+    }
+  }
+  if (result.unverified.length) {
+    lines.push('')
+    for (const pkg of result.unverified) {
+      lines.push(
+        `${LOG_SYMBOLS['warn']} ${pkg.location}@${pkg.version} unverified: ${pkg.reason}`,
+      )
+    }
+  }
+  if (!result.issues.length) {
+    lines.push('', `${LOG_SYMBOLS['success']} No issues found`)
+  }
+  return lines.join('\n')
+}
+
+export function formatAuditInstalledMarkdown(
+  result: AuditInstalledResult,
+): string {
+  const lines = [
+    mdHeader('Installed package audit'),
+    '',
+    mdKeyValue('Lockfile', result.lockfile ?? 'none'),
+    '',
+    getSummary(result),
+  ]
+  if (result.issues.length) {
+    lines.push(
+      '',
+      mdHeader('Issues', 2),
+      '',
+      mdTable(
+        result.issues.map(issue => ({
+          Package: `${issue.location}@${issue.version}`,
+          Issue: issue.kind,
+          Details: issue.files?.length
+            ? `${issue.message}: ${issue.files.join(', ')}`
+            : issue.message,
+        })),
+        ['Package', 'Issue', 'Details'],
+      ),
+    )
+  }
+  if (result.unverified.length) {
+    lines.push(
+      '',
+      mdHeader('Unverified', 2),
+      '',
+      mdTable(
+        result.unverified.map(pkg => ({
+          Package: `${pkg.location}@${pkg.version}`,
+          Reason: pkg.reason,
+        })),
+        ['Package', 'Reason'],
+      ),
+    )
+  }
+  return lines.join('\n')
+}
+
+export async function outputAuditInstalled(
+  result: CResult<AuditInstalledResult>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (result.data.issues.length) {
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === 'markdown') {
+    logger.log(formatAuditInstalledMarkdown(result.data))
+    return
+  }
+
+  logger.log(formatAuditInstalled(result.data))
+}
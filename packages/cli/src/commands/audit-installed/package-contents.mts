@@ -0,0 +1,195 @@
+/**
+ * Registry tarballs for `socket audit-installed`, and the comparison of an
+ * installed package directory with the files of its tarball.
+ *
+ * Files install scripts add (native build output, generated code) are not
+ * in the tarball and are ignored, only files that differ from or are
+ * missing compared to the tarball count. Nested node_modules are packages
+ * of their own and left out on both sides.
+ */
+
+import crypto from 'node:crypto'
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+import { gunzipSync } from 'node:zlib'
+
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { NODE_MODULES, PACKAGE_JSON } from '../../constants/packages.mts'
+import { listTarBufferEntries } from '../../util/container/tar.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import { socketHttpRequest } from '../../util/socket/api-http.mts'
+import {
+  escapePackageName,
+  fetchRegistryJson,
+} from '../verify/fetch-npm-provenance.mts'
+
+import type { CResult } from '../../types.mts'
+import type { NpmVersionManifest } from '../verify/fetch-npm-provenance.mts'
+
+export type PackageDist = {
+  integrity: string
+  tarball: string
+}
+
+export type PackageContentsDiff = {
+  // Files whose contents differ from the tarball.
+  modified: string[]
+  // Files of the tarball that are not installed.
+  missing: string[]
+}
+
+// Strongest first, as npm picks them.
+const INTEGRITY_ALGORITHMS = ['sha512', 'sha384', 'sha256', 'sha1']
+
+/**
+ * The dist of an npm package version, undefined when the version is not
+ * published.
+ */
+export async function fetchPackageDist(
+  name: string,
+  version: string,
+): Promise<CResult<PackageDist | undefined>> {
+  const manifestCResult = await fetchRegistryJson<NpmVersionManifest>(
+    `/${escapePackageName(name)}/${encodeURIComponent(version)}`,
+    `the ${name}@${version} manifest`,
+  )
+  if (!manifestCResult.ok) {
+    return manifestCResult
+  }
+  const dist = manifestCResult.data?.dist
+  if (!dist?.integrity || !dist.tarball) {
+    return { ok: true, data: undefined }
+  }
+  return {
+    ok: true,
+    data: { integrity: dist.integrity, tarball: dist.tarball },
+  }
+}
+
+/**
+ * Whether data matches a Subresource Integrity string. Only the strongest
+ * algorithm listed is checked.
+ */
+export function matchesIntegrity(data: Buffer, integrity: string): boolean {
+  const hashes = integrity.trim().split(/\s+/)
+  for (const algorithm of INTEGRITY_ALGORITHMS) {
+    const expected = hashes
+      .filter(hash => hash.startsWith(`${algorithm}-`))
+      .map(hash => hash.slice(algorithm.length + 1).replace(/\?.*$/, ''))
+    if (expected.length) {
+      const actual = crypto.createHash(algorithm).update(data).digest('base64')
+      return expected.includes(actual)
+    }
+  }
+  return false
+}
+
+/**
+ * Download a tarball and check it against its integrity.
+ */
+export async function fetchPackageTarball(
+  dist: PackageDist,
+): Promise<CResult<Buffer>> {
+  let response
+  try {
+    response = await socketHttpRequest(dist.tarball)
+  } catch (e) {
+    debug(`npm tarball request failed: ${dist.tarball}`)
+    debugDir(e)
+    return {
+      ok: false,
+      message: 'npm registry request failed',
+      cause: `Could not download ${dist.tarball}: ${getErrorCause(e)}`,
+    }
+  }
+  if (!response.ok) {
+    return {
+      ok: false,
+      message: 'npm registry request failed',
+      cause: `Downloading ${dist.tarball} failed with ${response.status} ${response.statusText}`,
+    }
+  }
+  if (!matchesIntegrity(response.body, dist.integrity)) {
+    return {
+      ok: false,
+      message: 'Integrity mismatch',
+      cause: `${dist.tarball} does not match the integrity the npm registry lists for it`,
+    }
+  }
+  return { ok: true, data: response.body }
+}
+
+function isInNodeModules(file: string): boolean {
+  return file.split('/').includes(NODE_MODULES)
+}
+
+/**
+ * The regular files of a package tarball by their path in the package. The
+ * top folder, `package/` for most packages, is stripped.
+ */
+export function readTarballFiles(tgz: Buffer): Map<string, Buffer> {
+  const tar = gunzipSync(tgz)
+  const files = new Map<string, Buffer>()
+  for (const entry of listTarBufferEntries(tar)) {
+    if (entry.type !== 'file') {
+      continue
+    }
+    const file = entry.name.split('/').slice(1).join('/')
+    if (file && !isInNodeModules(file)) {
+      files.set(file, tar.subarray(entry.offset, entry.offset + entry.size))
+    }
+  }
+  return files
+}
+
+// Older npm versions and other installers write `_` fields into the
+// installed package.json, e.g. _resolved and _integrity.
+function normalizePackageJson(data: Buffer): string | undefined {
+  try {
+    const json = JSON.parse(data.toString('utf8')) as Record<string, unknown>
+    return JSON.stringify(
+      Object.fromEntries(
+        Object.entries(json).filter(({ 0: key }) => !key.startsWith('_')),
+      ),
+    )
+  } catch {
+    return undefined
+  }
+}
+
+function isSameFile(file: string, installed: Buffer, packed: Buffer): boolean {
+  if (installed.equals(packed)) {
+    return true
+  }
+  if (file === PACKAGE_JSON) {
+    const normalized = normalizePackageJson(installed)
+    return normalized !== undefined && normalized === normalizePackageJson(packed)
+  }
+  return false
+}
+
+/**
+ * Compare the installed files of a package with the files of its tarball.
+ */
+export async function diffPackageContents(
+  dir: string,
+  files: Map<string, Buffer>,
+): Promise<PackageContentsDiff> {
+  const diff: PackageContentsDiff = { modified: [], missing: [] }
+  for (const { 0: file, 1: packed } of files) {
+    let installed
+    try {
+      installed = await fs.readFile(path.join(dir, file))
+    } catch {
+      diff.missing.push(file)
+      continue
+    }
+    if (!isSameFile(file, installed, packed)) {
+      diff.modified.push(file)
+    }
+  }
+  diff.modified.sort()
+  diff.missing.sort()
+  return diff
+}
@@ -0,0 +1,133 @@
+/**
+ * Export of the full audit log for SIEM ingestion.
+ *
+ * The API returns events newest first, one page at a time. Pages are fetched
+ * until they run out or reach events older than the --since cutoff, and the
+ * events are exported oldest first, so appending consecutive exports to the
+ * same file keeps it in order.
+ */
+
+import { fetchAuditLog } from './fetch-audit-log.mts'
+import { formatCsv } from '../../util/output/csv.mts'
+
+import type { AuditLogEvent } from './output-audit-log.mts'
+import type { CResult } from '../../types.mts'
+
+export const AUDIT_LOG_EXPORT_FORMATS = ['jsonl', 'csv'] as const
+
+export type AuditLogExportFormat = (typeof AUDIT_LOG_EXPORT_FORMATS)[number]
+
+export const AUDIT_LOG_CSV_COLUMNS = [
+  'event_id',
+  'created_at',
+  'type',
+  'user_id',
+  'user_email',
+  'ip_address',
+  'country_code',
+  'user_agent',
+  'status_code',
+  'payload',
+] as const
+
+const AUDIT_LOG_EXPORT_PER_PAGE = 100
+
+const RELATIVE_SINCE_UNITS: Record<string, number> = {
+  d: 86_400_000,
+  h: 3_600_000,
+  m: 60_000,
+}
+
+export type ExportAuditLogConfig = {
+  logType: string
+  orgSlug: string
+  // Epoch ms of the oldest event to export, all events when unset.
+  since?: number | undefined
+}
+
+/**
+ * Read --since: a date or date-time like `2025-04-01` or
+ * `2025-04-01T12:00:00Z`, or a time back from now like `7d`, `12h` or `30m`.
+ */
+export function parseAuditLogSince(
+  value: string,
+  now = Date.now(),
+): number | undefined {
+  const relative = /^(\d+)([dhm])$/.exec(value.trim())
+  if (relative) {
+    return now - Number(relative[1]) * RELATIVE_SINCE_UNITS[relative[2]!]!
+  }
+  const ts = Date.parse(value)
+  return Number.isNaN(ts) ? undefined : ts
+}
+
+function isBefore(event: AuditLogEvent, since: number): boolean {
+  const ts = Date.parse(event.created_at ?? '')
+  return !Number.isNaN(ts) && ts < since
+}
+
+export async function exportAuditLog(
+  config: ExportAuditLogConfig,
+): Promise<CResult<AuditLogEvent[]>> {
+  const { logType, orgSlug, since } = {
+    __proto__: null,
+    ...config,
+  } as ExportAuditLogConfig
+  const events: AuditLogEvent[] = []
+  let page = 1
+  while (page) {
+    const pageCResult = await fetchAuditLog(
+      {
+        logType,
+        orgSlug,
+        outputKind: 'json',
+        page,
+        perPage: AUDIT_LOG_EXPORT_PER_PAGE,
+      },
+      { commandPath: 'socket organization audit-log export' },
+    )
+    if (!pageCResult.ok) {
+      return pageCResult
+    }
+    const results = (pageCResult.data.results ?? []) as AuditLogEvent[]
+    let reachedSince = false
+    for (let i = 0, { length } = results; i < length; i += 1) {
+      const event = results[i]!
+      if (since !== undefined && isBefore(event, since)) {
+        reachedSince = true
+        continue
+      }
+      events.push(event)
+    }
+    page = reachedSince ? 0 : Number(pageCResult.data.nextPage) || 0
+  }
+  return { ok: true, data: events.reverse() }
+}
+
+export function formatAuditLogJsonl(events: AuditLogEvent[]): string {
+  return events.map(event => JSON.stringify(event)).join('\n')
+}
+
+export function formatAuditLogCsv(events: AuditLogEvent[]): string {
+  return formatCsv(
+    AUDIT_LOG_CSV_COLUMNS,
+    events.map(event =>
+      AUDIT_LOG_CSV_COLUMNS.map(column => {
+        const value: unknown = event[column]
+        if (value === undefined || value === null) {
+          return ''
+        }
+        return typeof value === 'object' ? JSON.stringify(value) : String(value)
+      }),
+    ),
+  )
+}
+
+export function formatAuditLogExport(
+  events: AuditLogEvent[],
+  format: AuditLogExportFormat,
+): string {
+  return format === 'csv'
+    ? formatAuditLogCsv(events)
+    : formatAuditLogJsonl(events)
+}
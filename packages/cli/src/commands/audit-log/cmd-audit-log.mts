@@ -98,6 +98,8 @@ export async function run(
 
     The page arg should be a positive integer, offset 1. Defaults to 1.
 
+    To export the whole log for a SIEM, see \`socket organization audit-log export\`.
+
     Options
       ${getFlagListOutput(helpConfig.flags)}
 
@@ -0,0 +1,24 @@
+import { exportAuditLog } from './audit-log-export.mts'
+import { outputAuditLogExport } from './output-audit-log-export.mts'
+
+import type { AuditLogExportFormat } from './audit-log-export.mts'
+
+export async function handleAuditLogExport({
+  cwd,
+  format,
+  logType,
+  orgSlug,
+  out,
+  since,
+}: {
+  cwd: string
+  format: AuditLogExportFormat
+  logType: string
+  orgSlug: string
+  out: string
+  since: number | undefined
+}): Promise<void> {
+  const result = await exportAuditLog({ logType, orgSlug, since })
+
+  await outputAuditLogExport(result, { cwd, format, out })
+}
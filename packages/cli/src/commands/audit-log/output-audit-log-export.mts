@@ -0,0 +1,57 @@
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { formatAuditLogExport } from './audit-log-export.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { fileLink } from '../../util/terminal/link.mts'
+
+import type { AuditLogExportFormat } from './audit-log-export.mts'
+import type { AuditLogEvent } from './output-audit-log.mts'
+import type { CResult } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export type OutputAuditLogExportConfig = {
+  cwd: string
+  format: AuditLogExportFormat
+  // Path to write to, stdout when empty or `-`.
+  out: string
+}
+
+export async function outputAuditLogExport(
+  result: CResult<AuditLogEvent[]>,
+  { cwd, format, out }: OutputAuditLogExportConfig,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const events = result.data
+  const content = formatAuditLogExport(events, format)
+  if (!out || out === '-') {
+    if (content) {
+      logger.log(content)
+    }
+    return
+  }
+  try {
+    await fs.writeFile(
+      path.resolve(cwd, out),
+      content ? `${content}\n` : '',
+      'utf8',
+    )
+  } catch (e) {
+    process.exitCode = 1
+    logger.fail(`There was an error trying to write ${out} to disk`)
+    logger.error(e)
+    return
+  }
+  logger.success(
+    `Wrote ${events.length} audit log ${pluralize('event', { count: events.length })} to ${fileLink(path.resolve(cwd, out), out)}`,
+  )
+}
@@ -0,0 +1,222 @@
+import { readFileSync } from 'node:fs'
+import path from 'node:path'
+
+import { handleBundleExport } from './handle-bundle-export.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { SOCKET_CLI_BUNDLE_SIGNING_KEY } from '../../env/socket-cli-bundle-signing-key.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { OFFLINE_BUNDLE_DEFAULT_FILENAME } from '../../util/offline/bundle.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { cmdFlagValueToArray } from '../../util/process/cmd.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'export'
+
+const description =
+  'Package scans, the security policy and the score cache into a signed offline bundle'
+
+const hidden = false
+
+export const cmdBundleExport: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+function readSigningKey(keyPath: string): string | undefined {
+  try {
+    return readFileSync(path.resolve(process.cwd(), keyPath), 'utf8')
+  } catch {
+    return undefined
+  }
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      output: {
+        type: 'string',
+        default: OFFLINE_BUNDLE_DEFAULT_FILENAME,
+        description: `Write the bundle to this file (default '${OFFLINE_BUNDLE_DEFAULT_FILENAME}')`,
+        shortFlag: 'o',
+      },
+      scan: {
+        type: 'string',
+        isMultiple: true,
+        description:
+          'ID of a scan to include. Accepts a comma-separated value or multiple flags.',
+      },
+      signingKey: {
+        type: 'string',
+        default: '',
+        description:
+          'Path to the PEM private key to sign the bundle with.\nMay set environment variable SOCKET_CLI_BUNDLE_SIGNING_KEY to the key itself instead.',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] --scan <SCAN_ID> --signing-key <FILE>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Packages the given scans, the security policy of the org and the local
+    package score cache into one gzipped archive signed with your key. Copy
+    it to an air-gapped machine together with the public key and run
+    \`socket bundle import\` there. \`socket scan report\`, \`socket scan check\`
+    and \`socket package score\` then run with --offline, without API access.
+
+    The key is a PEM private key (Ed25519, ECDSA or RSA), for example:
+      $ openssl genpkey -algorithm ed25519 -out bundle.key
+      $ openssl pkey -in bundle.key -pubout -out bundle.pub
+
+    Run \`socket package score\` first for packages the offline side looks
+    up, so their scores are in the cache.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} --scan 000aaaa1-0000-0a0a-00a0-00a0000000a0 --signing-key bundle.key
+      $ ${command} --scan 000aaaa1-0000-0a0a-00a0-00a0000000a0 --output acme.socket-bundle.json.gz --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    dryRun,
+    interactive,
+    json,
+    markdown,
+    org: orgFlag,
+    output,
+    signingKey: signingKeyPath,
+  } = cli.flags as {
+    dryRun: boolean
+    interactive: boolean
+    json: boolean
+    markdown: boolean
+    org: string
+    output: string
+    signingKey: string
+  }
+
+  const scanIds = cmdFlagValueToArray(cli.flags['scan'])
+
+  const privateKey = signingKeyPath
+    ? readSigningKey(signingKeyPath)
+    : SOCKET_CLI_BUNDLE_SIGNING_KEY
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      test: scanIds.length > 0,
+      message: 'Scan ID to include with --scan',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !!privateKey,
+      message: signingKeyPath
+        ? `The --signing-key file could not be read (saw: "${signingKeyPath}")`
+        : 'Signing key by --signing-key or the SOCKET_CLI_BUNDLE_SIGNING_KEY environment variable',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !!output,
+      message: 'Bundle file to write with --output',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scans and security policy for an offline bundle', {
+      organization: orgSlug,
+      scans: scanIds.join(', '),
+      output,
+    })
+    return
+  }
+
+  await handleBundleExport({
+    filepath: path.resolve(process.cwd(), output),
+    orgSlug,
+    outputKind,
+    privateKey: privateKey!,
+    scanIds,
+  })
+}
@@ -0,0 +1,168 @@
+import { readFileSync } from 'node:fs'
+import path from 'node:path'
+
+import { handleBundleImport } from './handle-bundle-import.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import {
+  getOfflineBundlePath,
+  getScoreCachePath,
+} from '../../constants/paths.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunPreview } from '../../util/dry-run/output.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'import'
+
+const description =
+  'Verify a signed offline bundle and import it for --offline runs'
+
+const hidden = false
+
+export const cmdBundleImport: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+function readPublicKey(keyPath: string): string | undefined {
+  try {
+    return readFileSync(path.resolve(process.cwd(), keyPath), 'utf8')
+  } catch {
+    return undefined
+  }
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      publicKey: {
+        type: 'string',
+        default: '',
+        description:
+          'Path to the PEM public key of the key the bundle was signed with',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <FILE> --public-key <FILE>
+
+    Checks the signature of a bundle made with \`socket bundle export\` and
+    imports it without calling the Socket API. A bundle that does not verify
+    with the public key is rejected before anything is written.
+
+    The scans and the org security policy are stored in the socket cache
+    directory, the package scores are added to the score cache. Use
+    \`socket scan report --offline\`, \`socket scan check --offline\` and
+    \`socket package score --offline\` to read them, or set
+    SOCKET_CLI_OFFLINE=1. Importing a newer bundle of the same org replaces
+    the policy and the scans it carries.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} socket-bundle.json.gz --public-key bundle.pub
+      $ ${command} /media/usb/acme.socket-bundle.json.gz --public-key bundle.pub --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    dryRun,
+    json,
+    markdown,
+    publicKey: publicKeyPath,
+  } = cli.flags as {
+    dryRun: boolean
+    json: boolean
+    markdown: boolean
+    publicKey: string
+  }
+
+  const [bundlePath = ''] = cli.input
+
+  const publicKey = publicKeyPath ? readPublicKey(publicKeyPath) : undefined
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      test: !!bundlePath,
+      message: 'Bundle file to import',
+      fail: 'missing',
+    },
+    {
+      test: !!publicKey,
+      message: publicKeyPath
+        ? `The --public-key file could not be read (saw: "${publicKeyPath}")`
+        : 'Public key to verify the bundle with --public-key',
+      fail: 'missing',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunPreview({
+      summary: 'Import an offline bundle',
+      actions: [
+        {
+          type: 'fetch',
+          description: 'Verify the bundle signature',
+          target: path.resolve(process.cwd(), bundlePath),
+        },
+        {
+          type: 'write',
+          description: 'Store the scans and security policy',
+          target: getOfflineBundlePath(),
+        },
+        {
+          type: 'write',
+          description: 'Add the package scores to the score cache',
+          target: getScoreCachePath(),
+        },
+      ],
+      wouldSucceed: true,
+    })
+    return
+  }
+
+  await handleBundleImport({
+    filepath: path.resolve(process.cwd(), bundlePath),
+    outputKind,
+    publicKey: publicKey!,
+  })
+}
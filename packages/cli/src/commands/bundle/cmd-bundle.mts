@@ -0,0 +1,26 @@
+import { cmdBundleExport } from './cmd-bundle-export.mts'
+import { cmdBundleImport } from './cmd-bundle-import.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Move scan data and policy to air-gapped machines'
+
+export const cmdBundle: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} bundle`,
+        importMeta,
+        subcommands: {
+          export: cmdBundleExport,
+          import: cmdBundleImport,
+        },
+      },
+      { description },
+    )
+  },
+}
@@ -0,0 +1,105 @@
+import crypto from 'node:crypto'
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputBundleExport } from './output-bundle-export.mts'
+import { fetchScanData } from '../scan/fetch-report-data.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import {
+  OFFLINE_BUNDLE_VERSION,
+  getBundleKeyId,
+  signOfflineBundle,
+  writeOfflineBundleArchive,
+} from '../../util/offline/bundle.mts'
+import { readScoreCacheEntries } from '../../util/socket/score-cache.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type {
+  OfflineBundle,
+  OfflineBundleScan,
+  SecurityPolicyData,
+} from '../../util/offline/bundle.mts'
+
+export type BundleExportConfig = {
+  filepath: string
+  orgSlug: string
+  privateKey: string
+  scanIds: string[]
+}
+
+export type BundleExportResult = {
+  filepath: string
+  // Hex SHA-256 of the public key, see getBundleKeyId.
+  keyId: string
+  orgSlug: string
+  scanIds: string[]
+  scoreEntries: number
+}
+
+export async function exportOfflineBundle({
+  filepath,
+  orgSlug,
+  privateKey,
+  scanIds,
+}: BundleExportConfig): Promise<CResult<BundleExportResult>> {
+  const scans: OfflineBundleScan[] = []
+  let securityPolicy: SecurityPolicyData | undefined
+  for (let i = 0, { length } = scanIds; i < length; i += 1) {
+    const scanId = scanIds[i]!
+    // License details let `socket scan report --license` run offline too.
+    const scanDataCResult = await fetchScanData(orgSlug, scanId, {
+      includeLicensePolicy: true,
+    })
+    if (!scanDataCResult.ok) {
+      return {
+        ...scanDataCResult,
+        cause: `Scan ${scanId}: ${scanDataCResult.cause ?? scanDataCResult.message}`,
+      }
+    }
+    scans.push({ scan: scanDataCResult.data.scan, scanId })
+    securityPolicy = scanDataCResult.data.securityPolicy
+  }
+
+  const bundle: OfflineBundle = {
+    createdAt: new Date().toISOString(),
+    orgSlug,
+    scans,
+    scoreCache: await readScoreCacheEntries(),
+    securityPolicy: securityPolicy!,
+    version: OFFLINE_BUNDLE_VERSION,
+  }
+  const envelopeCResult = signOfflineBundle(bundle, privateKey)
+  if (!envelopeCResult.ok) {
+    return envelopeCResult
+  }
+  try {
+    await writeOfflineBundleArchive(filepath, envelopeCResult.data)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Unable to write offline bundle',
+      cause: getErrorCause(e),
+    }
+  }
+  return {
+    ok: true,
+    data: {
+      filepath,
+      keyId: getBundleKeyId(crypto.createPrivateKey(privateKey)),
+      orgSlug,
+      scanIds,
+      scoreEntries: bundle.scoreCache.length,
+    },
+  }
+}
+
+export async function handleBundleExport({
+  outputKind,
+  ...config
+}: BundleExportConfig & { outputKind: OutputKind }): Promise<void> {
+  const result = await exportOfflineBundle(config)
+
+  debugDir({ result })
+
+  outputBundleExport(result, outputKind)
+}
@@ -0,0 +1,52 @@
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputBundleImport } from './output-bundle-import.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import {
+  readOfflineBundleArchive,
+  verifyOfflineBundle,
+} from '../../util/offline/bundle.mts'
+import { importOfflineBundle } from '../../util/offline/store.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { OfflineBundleImport } from '../../util/offline/store.mts'
+
+export type BundleImportConfig = {
+  filepath: string
+  publicKey: string
+}
+
+export async function importOfflineBundleArchive({
+  filepath,
+  publicKey,
+}: BundleImportConfig): Promise<CResult<OfflineBundleImport>> {
+  const envelopeCResult = await readOfflineBundleArchive(filepath)
+  if (!envelopeCResult.ok) {
+    return envelopeCResult
+  }
+  // Nothing is written before the signature checks out.
+  const bundleCResult = verifyOfflineBundle(envelopeCResult.data, publicKey)
+  if (!bundleCResult.ok) {
+    return bundleCResult
+  }
+  try {
+    return { ok: true, data: await importOfflineBundle(bundleCResult.data) }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Unable to import offline bundle',
+      cause: getErrorCause(e),
+    }
+  }
+}
+
+export async function handleBundleImport({
+  outputKind,
+  ...config
+}: BundleImportConfig & { outputKind: OutputKind }): Promise<void> {
+  const result = await importOfflineBundleArchive(config)
+
+  debugDir({ result })
+
+  outputBundleImport(result, outputKind)
+}
@@ -0,0 +1,51 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { BundleExportResult } from './handle-bundle-export.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export function outputBundleExport(
+  result: CResult<BundleExportResult>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { filepath, keyId, orgSlug, scanIds, scoreEntries } = result.data
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Offline bundle export'))
+    logger.log('')
+    logger.log(mdKeyValue('Bundle', filepath))
+    logger.log(mdKeyValue('Organization', orgSlug))
+    logger.log(mdKeyValue('Scans', scanIds.join(', ')))
+    logger.log(mdKeyValue('Score cache entries', scoreEntries))
+    logger.log(mdKeyValue('Signing key', keyId))
+    return
+  }
+
+  logger.success(
+    `Exported ${scanIds.length} ${pluralize('scan', { count: scanIds.length })} and ${scoreEntries} score cache ${scoreEntries === 1 ? 'entry' : 'entries'} of ${orgSlug} to ${filepath}`,
+  )
+  logger.log(`Signed with key ${keyId}`)
+  logger.log(
+    'Copy it to the offline machine with the public key and run `socket bundle import` there.',
+  )
+}
@@ -0,0 +1,49 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { OfflineBundleImport } from '../../util/offline/store.mts'
+
+const logger = getDefaultLogger()
+
+export function outputBundleImport(
+  result: CResult<OfflineBundleImport>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { createdAt, orgSlug, scanIds, scoreEntries } = result.data
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Offline bundle import'))
+    logger.log('')
+    logger.log(mdKeyValue('Organization', orgSlug))
+    logger.log(mdKeyValue('Exported', createdAt))
+    logger.log(mdKeyValue('Scans', scanIds.join(', ')))
+    logger.log(mdKeyValue('Score cache entries', scoreEntries))
+    return
+  }
+
+  logger.success(
+    `Imported ${scanIds.length} ${pluralize('scan', { count: scanIds.length })} and ${scoreEntries} score cache ${scoreEntries === 1 ? 'entry' : 'entries'} of ${orgSlug}, exported ${createdAt}`,
+  )
+  logger.log(
+    `Run \`socket scan report <SCAN_ID> --offline --org ${orgSlug}\` or \`socket scan check --offline\` to use them.`,
+  )
+}
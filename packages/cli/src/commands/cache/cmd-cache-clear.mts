@@ -0,0 +1,113 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { handleCacheClear } from './handle-cache-clear.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import {
+  LOCAL_CACHE_NAMES,
+  getLocalCachePath,
+  isLocalCacheName,
+} from '../../util/cache/local-caches.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunDelete } from '../../util/dry-run/output.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type { LocalCacheName } from '../../util/cache/local-caches.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'clear'
+
+const description = 'Remove every entry of the local response caches'
+
+const hidden = false
+
+export const cmdCacheClear: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] [CACHE ...]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Empties all caches, or only the named ones (${joinOr(LOCAL_CACHE_NAMES)}).
+    The next lookups then go to the network again. To keep recent entries use
+    \`socket cache prune --older-than\` instead.
+
+    Examples
+      $ ${command}
+      $ ${command} github
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, json, markdown } = cli.flags as {
+    dryRun: boolean
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: cli.input.every(isLocalCacheName),
+      message: `Expecting cache names of ${joinOr(LOCAL_CACHE_NAMES)}`,
+      fail: 'unknown cache',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  const names = cli.input.length
+    ? (cli.input as LocalCacheName[])
+    : LOCAL_CACHE_NAMES
+
+  if (dryRun) {
+    outputDryRunDelete(
+      'every cache entry',
+      names.map(name => getLocalCachePath(name)).join(', '),
+    )
+    return
+  }
+
+  await handleCacheClear({ names, outputKind })
+}
@@ -0,0 +1,128 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { handleCacheClear } from './handle-cache-clear.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import {
+  LOCAL_CACHE_NAMES,
+  getLocalCachePath,
+  isLocalCacheName,
+} from '../../util/cache/local-caches.mts'
+import { parseCacheDuration } from '../../util/cache/overrides.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunDelete } from '../../util/dry-run/output.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type { LocalCacheName } from '../../util/cache/local-caches.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'prune'
+
+const description = 'Remove the local cache entries older than a given age'
+
+const hidden = false
+
+export const cmdCachePrune: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      olderThan: {
+        type: 'string',
+        default: '',
+        description:
+          'Remove the entries written longer ago than this, e.g. 12h or 7d',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] --older-than <DURATION> [CACHE ...]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Removes the entries of all caches, or only of the named ones
+    (${joinOr(LOCAL_CACHE_NAMES)}), that were written longer ago than the
+    duration: a number followed by s, m, h, d or w. A bare number is seconds.
+
+    Examples
+      $ ${command} --older-than 7d
+      $ ${command} scores --older-than 12h
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, json, markdown, olderThan } = cli.flags as {
+    dryRun: boolean
+    json: boolean
+    markdown: boolean
+    olderThan: string
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const olderThanMs = parseCacheDuration(olderThan)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: olderThanMs !== undefined,
+      message: 'The --older-than flag expects a duration like 30m, 12h or 7d',
+      fail: olderThan ? 'bad' : 'missing',
+    },
+    {
+      test: cli.input.every(isLocalCacheName),
+      message: `Expecting cache names of ${joinOr(LOCAL_CACHE_NAMES)}`,
+      fail: 'unknown cache',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  const names = cli.input.length
+    ? (cli.input as LocalCacheName[])
+    : LOCAL_CACHE_NAMES
+
+  if (dryRun) {
+    outputDryRunDelete(
+      `the cache entries older than ${olderThan}`,
+      names.map(name => getLocalCachePath(name)).join(', '),
+    )
+    return
+  }
+
+  await handleCacheClear({ names, olderThanMs, outputKind })
+}
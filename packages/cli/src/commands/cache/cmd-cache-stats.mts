@@ -0,0 +1,103 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { handleCacheStats } from './handle-cache-stats.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import {
+  LOCAL_CACHE_NAMES,
+  isLocalCacheName,
+} from '../../util/cache/local-caches.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type { LocalCacheName } from '../../util/cache/local-caches.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'stats'
+
+const description = 'Show the size and age of the local response caches'
+
+const hidden = false
+
+export const cmdCacheStats: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] [CACHE ...]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Lists the entries, size and entry ages of the caches, or only of the named
+    ones: "github" holds GitHub API responses of \`socket fix\` and the GHSA
+    lookups, "scores" holds package scores of \`socket package\`.
+
+    Examples
+      $ ${command}
+      $ ${command} scores --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags as {
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: cli.input.every(isLocalCacheName),
+      message: `Expecting cache names of ${joinOr(LOCAL_CACHE_NAMES)}`,
+      fail: 'unknown cache',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  await handleCacheStats({
+    names: cli.input.length
+      ? (cli.input as LocalCacheName[])
+      : LOCAL_CACHE_NAMES,
+    outputKind,
+  })
+}
@@ -0,0 +1,28 @@
+import { cmdCacheClear } from './cmd-cache-clear.mts'
+import { cmdCachePrune } from './cmd-cache-prune.mts'
+import { cmdCacheStats } from './cmd-cache-stats.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Inspect and clear the local response caches'
+
+export const cmdCache: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} cache`,
+        importMeta,
+        subcommands: {
+          clear: cmdCacheClear,
+          prune: cmdCachePrune,
+          stats: cmdCacheStats,
+        },
+      },
+      { description },
+    )
+  },
+}
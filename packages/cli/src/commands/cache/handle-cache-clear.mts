@@ -0,0 +1,43 @@
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputCacheClear } from './output-cache-clear.mts'
+import { removeLocalCacheEntries } from '../../util/cache/local-caches.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type {
+  LocalCacheName,
+  LocalCacheRemoval,
+} from '../../util/cache/local-caches.mts'
+
+/**
+ * Shared by `socket cache clear` and `socket cache prune`, which passes the
+ * --older-than age.
+ */
+export async function handleCacheClear({
+  names,
+  olderThanMs,
+  outputKind,
+}: {
+  names: readonly LocalCacheName[]
+  olderThanMs?: number | undefined
+  outputKind: OutputKind
+}): Promise<void> {
+  let result: CResult<LocalCacheRemoval[]>
+  try {
+    result = {
+      ok: true,
+      data: await removeLocalCacheEntries(names, olderThanMs),
+    }
+  } catch (e) {
+    result = {
+      ok: false,
+      message: 'Failed to remove the cache entries',
+      cause: getErrorCause(e),
+    }
+  }
+
+  debugDir({ result })
+
+  outputCacheClear(result, outputKind)
+}
@@ -0,0 +1,34 @@
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputCacheStats } from './output-cache-stats.mts'
+import { getLocalCacheStats } from '../../util/cache/local-caches.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type {
+  LocalCacheName,
+  LocalCacheStats,
+} from '../../util/cache/local-caches.mts'
+
+export async function handleCacheStats({
+  names,
+  outputKind,
+}: {
+  names: readonly LocalCacheName[]
+  outputKind: OutputKind
+}): Promise<void> {
+  let result: CResult<LocalCacheStats[]>
+  try {
+    result = { ok: true, data: await getLocalCacheStats(names) }
+  } catch (e) {
+    result = {
+      ok: false,
+      message: 'Failed to read the local caches',
+      cause: getErrorCause(e),
+    }
+  }
+
+  debugDir({ result })
+
+  outputCacheStats(result, outputKind)
+}
@@ -0,0 +1,41 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { formatCacheBytes } from './output-cache-stats.mts'
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { LocalCacheRemoval } from '../../util/cache/local-caches.mts'
+
+const logger = getDefaultLogger()
+
+export function outputCacheClear(
+  result: CResult<LocalCacheRemoval[]>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Local caches'))
+    logger.log('')
+  }
+  for (let i = 0, { length } = result.data; i < length; i += 1) {
+    const { name, removedBytes, removedEntries } = result.data[i]!
+    logger.success(
+      `${name}: removed ${removedEntries} entries (${formatCacheBytes(removedBytes)})`,
+    )
+  }
+}
@@ -0,0 +1,75 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { LocalCacheStats } from '../../util/cache/local-caches.mts'
+
+const logger = getDefaultLogger()
+
+export function formatCacheBytes(bytes: number): string {
+  if (bytes < 1024) {
+    return `${bytes} B`
+  }
+  if (bytes < 1024 * 1024) {
+    return `${(bytes / 1024).toFixed(1)} KiB`
+  }
+  return `${(bytes / 1024 / 1024).toFixed(1)} MiB`
+}
+
+function formatEntryTime(ms: number | undefined): string {
+  return ms === undefined ? '-' : new Date(ms).toISOString()
+}
+
+export function outputCacheStats(
+  result: CResult<LocalCacheStats[]>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Local caches'))
+    logger.log('')
+    logger.log(
+      mdTable(
+        result.data.map(stats => ({
+          name: stats.name,
+          entries: String(stats.entries),
+          size: formatCacheBytes(stats.bytes),
+          oldest: formatEntryTime(stats.oldest),
+          newest: formatEntryTime(stats.newest),
+          path: stats.path,
+        })),
+        ['name', 'entries', 'size', 'oldest', 'newest', 'path'],
+        ['Cache', 'Entries', 'Size', 'Oldest', 'Newest', 'Path'],
+      ),
+    )
+    return
+  }
+
+  for (let i = 0, { length } = result.data; i < length; i += 1) {
+    const stats = result.data[i]!
+    logger.log(
+      `${stats.name}: ${stats.entries} entries, ${formatCacheBytes(stats.bytes)} in ${stats.path}`,
+    )
+    if (stats.entries) {
+      logger.log(
+        `  oldest ${formatEntryTime(stats.oldest)}, newest ${formatEntryTime(stats.newest)}`,
+      )
+    }
+  }
+}
@@ -0,0 +1,181 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { checkPackagesBeforeInstall } from '../../util/install-check/check.mts'
+import { findTyposquatTarget } from '../../util/install-check/typosquat.mts'
+import { resolveCrateVersion } from '../../util/rust/crates-index.mts'
+
+import type { CResult } from '../../types.mts'
+import type { CheckInstallPackagesOptions } from '../../util/install-check/check.mts'
+
+const logger = getDefaultLogger()
+
+export type CargoCrateRequest = {
+  name: string
+  // Cargo version requirement, e.g. `1.0` or `=0.4.2`.
+  req: string | undefined
+}
+
+// Alerts that prompt for crates even without an org policy: build scripts and
+// proc macros run at compile time, and typosquats are the common crates.io
+// attack.
+export const CARGO_WARN_ALERT_TYPES = [
+  'didYouMean',
+  'installScripts',
+  'networkAccess',
+]
+
+// cargo options (global and per-subcommand) that consume the next argument.
+const CARGO_VALUE_OPTIONS = new Set([
+  '--bin',
+  '--branch',
+  '--color',
+  '--config',
+  '--example',
+  '--features',
+  '--git',
+  '--index',
+  '--jobs',
+  '--lockfile-path',
+  '--manifest-path',
+  '--package',
+  '--path',
+  '--profile',
+  '--registry',
+  '--rename',
+  '--rev',
+  '--root',
+  '--tag',
+  '--target',
+  '--target-dir',
+  '--version',
+  '-C',
+  '-F',
+  '-Z',
+  '-j',
+  '-p',
+])
+
+// Sources other than crates.io; those crates are not looked up.
+const CARGO_NON_CRATES_IO_OPTIONS = new Set([
+  '--git',
+  '--index',
+  '--path',
+  '--registry',
+])
+
+const CARGO_NO_CHECK_ARGS = new Set(['--dry-run', '--help', '-h'])
+
+/**
+ * Work out which crates.io crates a `cargo add|install` invocation names.
+ * Returns undefined for other subcommands, dry runs, and installs from git,
+ * a path or another registry.
+ */
+export function getCargoInstallCrates(
+  args: readonly string[],
+): CargoCrateRequest[] | undefined {
+  const options = new Map<string, string>()
+  const positionals: string[] = []
+  let subcommand: string | undefined
+  for (let i = 0, { length } = args; i < length; i += 1) {
+    const arg = args[i]!
+    if (arg === '--') {
+      break
+    }
+    // rustup toolchain override, e.g. `cargo +nightly install`.
+    if (!subcommand && arg.startsWith('+')) {
+      continue
+    }
+    if (arg.startsWith('-')) {
+      const eqIndex = arg.indexOf('=')
+      const name = eqIndex === -1 ? arg : arg.slice(0, eqIndex)
+      if (CARGO_NO_CHECK_ARGS.has(name)) {
+        return undefined
+      }
+      if (CARGO_VALUE_OPTIONS.has(name)) {
+        let value: string | undefined
+        if (eqIndex === -1) {
+          i += 1
+          value = args[i]
+        } else {
+          value = arg.slice(eqIndex + 1)
+        }
+        if (subcommand && value !== undefined) {
+          options.set(name, value)
+        }
+      }
+      continue
+    }
+    if (subcommand) {
+      positionals.push(arg)
+    } else {
+      subcommand = arg
+    }
+  }
+  if (subcommand !== 'add' && subcommand !== 'install') {
+    return undefined
+  }
+  for (const option of CARGO_NON_CRATES_IO_OPTIONS) {
+    if (options.has(option)) {
+      return undefined
+    }
+  }
+  const version = options.get('--version')
+  const crates = positionals.map(spec => {
+    const atIndex = spec.indexOf('@')
+    return atIndex === -1
+      ? { name: spec, req: version }
+      : { name: spec.slice(0, atIndex), req: spec.slice(atIndex + 1) }
+  })
+  return crates.length ? crates : undefined
+}
+
+/**
+ * Preflight for `socket cargo add|install`: resolve the requested crates
+ * against the crates.io index and score them before handing off. Crates that
+ * look like typosquats warn before the lookup, the `didYouMean` alert then
+ * asks about them. Lookup failures only warn, Socket Firewall still screens
+ * the downloads.
+ */
+export async function checkCargoInstall(
+  args: readonly string[],
+  options?: Pick<CheckInstallPackagesOptions, 'interactive'> | undefined,
+): Promise<CResult<unknown>> {
+  const crates = getCargoInstallCrates(args)
+  if (!crates) {
+    return { ok: true, data: undefined }
+  }
+  for (let i = 0, { length } = crates; i < length; i += 1) {
+    const { name } = crates[i]!
+    const match = findTyposquatTarget('cargo', name)
+    if (match) {
+      logger.warn(`${name} looks like a typosquat of ${match.target}`)
+    }
+  }
+  const spinner = getDefaultSpinner()
+  spinner.start('Resolving crates to install…')
+  const versionCResults = await Promise.all(
+    crates.map(({ name, req }) => resolveCrateVersion(name, req)),
+  )
+  spinner.stop()
+  const purls: string[] = []
+  for (let i = 0, { length } = crates; i < length; i += 1) {
+    const versionCResult = versionCResults[i]!
+    if (!versionCResult.ok) {
+      logger.warn(
+        `Skipping the Socket pre-install check: ${versionCResult.cause ?? versionCResult.message}`,
+      )
+      return { ok: true, data: undefined }
+    }
+    // Unknown crates and unsatisfiable requirements are left for cargo to
+    // report.
+    if (versionCResult.data) {
+      purls.push(`pkg:cargo/${crates[i]!.name}@${versionCResult.data}`)
+    }
+  }
+  return await checkPackagesBeforeInstall(purls, {
+    commandPath: 'socket cargo',
+    interactive: options?.interactive,
+    warnAlertTypes: CARGO_WARN_ALERT_TYPES,
+  })
+}
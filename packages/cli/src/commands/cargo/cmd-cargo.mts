@@ -3,9 +3,11 @@
  *
  * Defined via `defineHandoffCommand`, which collapses the standard parse-flags
  * / filter-flags / spawn-sfw / forward-exit pattern into a single declarative
- * spec. See `util/cli/define-handoff.mts`.
+ * spec. `cargo add` and `cargo install` are scored before the hand-off through
+ * its `preflight` hook. See `util/cli/define-handoff.mts`.
  */
 
+import { checkCargoInstall } from './check-cargo-install.mts'
 import { defineHandoffCommand } from '../../util/cli/define-handoff.mts'
 
 export const cmdCargo = defineHandoffCommand({
@@ -13,6 +15,12 @@ export const cmdCargo = defineHandoffCommand({
   description: 'Run cargo with Socket Firewall security',
   spawnMode: 'dlx',
   examples: ['install ripgrep', 'build', 'add serde'],
+  helpNotes: [
+    'Crates named by `cargo add` and `cargo install` are checked against Socket',
+    'before cargo runs. Build scripts, network access and likely typosquats',
+    'prompt for confirmation unless your organization policy says otherwise.',
+  ],
+  preflight: (args, _binaryName, context) => checkCargoInstall(args, context),
   // cargo did not previously emit telemetry or support --dry-run.
   trackTelemetry: false,
   supportDryRun: false,
@@ -0,0 +1,237 @@
+/**
+ * Renovate and Dependabot pull requests for `socket ci enrich-bot-pr`.
+ *
+ * A bot pull request is told apart by its source branch, `renovate/…` or
+ * `dependabot/…`, or by its author. The package it bumps is the one its title
+ * names (`Bump lodash from 4.17.20 to 4.17.21`, `Update dependency lodash to
+ * v4.17.21`) among those whose versions changed in a lockfile, or the only
+ * one that changed when the title names none. The comment compares the
+ * scores and alert types of the old and the new version of just that package.
+ */
+
+import { naturalCompare } from '@socketsecurity/lib-stable/sorts/natural'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import {
+  getSocketDevAlertUrl,
+  getSocketDevPackageOverviewUrlFromPurl,
+} from '../../util/socket/url.mts'
+
+import type { CResult } from '../../types.mts'
+import type { PURL_Type } from '../../util/ecosystem/types.mts'
+import type { FixVersionChange } from '../fix/fix-changelog.mts'
+import type { BulkScoreRow } from '../package/bulk-score.mts'
+
+export const BOT_PR_COMMENT_MARKER = '<!-- socket-bot-pr-report -->'
+
+export const DEPENDENCY_BOTS = ['dependabot', 'renovate'] as const
+
+export type DependencyBot = (typeof DEPENDENCY_BOTS)[number]
+
+export type BotPrContext = {
+  // Login of the pull request author, e.g. `renovate[bot]`.
+  author: string
+  branch: string
+  title: string
+}
+
+export type BotPrVersionChange = FixVersionChange & {
+  ecosystem: PURL_Type
+}
+
+export type BotPrBump = {
+  ecosystem: PURL_Type
+  // Lockfiles the bump changed.
+  files: string[]
+  from: string
+  name: string
+  to: string
+}
+
+export type BotPrComparison = {
+  addedAlerts: string[]
+  after: BulkScoreRow
+  before: BulkScoreRow
+  bump: BotPrBump
+  removedAlerts: string[]
+}
+
+type ScoreName = keyof NonNullable<BulkScoreRow['score']>
+
+const SCORE_LABELS: Array<[ScoreName, string]> = [
+  ['supplyChain', 'Supply chain'],
+  ['vulnerability', 'Vulnerability'],
+  ['quality', 'Quality'],
+  ['maintenance', 'Maintenance'],
+  ['license', 'License'],
+]
+
+export function detectDependencyBot({
+  author,
+  branch,
+}: Pick<BotPrContext, 'author' | 'branch'>): DependencyBot | undefined {
+  const login = author.toLowerCase().replace(/\[bot\]$/, '')
+  return DEPENDENCY_BOTS.find(
+    bot => branch.startsWith(`${bot}/`) || login.startsWith(bot),
+  )
+}
+
+/**
+ * The package a Dependabot or Renovate title names, without a conventional
+ * commit prefix such as `chore(deps):`.
+ */
+export function parseBotPrTitle(title: string): string | undefined {
+  const subject = title.trim().replace(/^[\w-]+(?:\([^)]*\))?!?:\s*/, '')
+  const match =
+    /^bump\s+(\S+)\s+from\s+\S+\s+to\s+\S+/i.exec(subject) ??
+    /^update\s+(?:dependency\s+|module\s+|package\s+)?(\S+)\s+to\s+v?\S+/i.exec(
+      subject,
+    )
+  return match ? match[1]!.replace(/^[`'"]|[`'"]$/g, '') : undefined
+}
+
+// Maven titles say `group:artifact`, lockfiles `group/artifact`, and PyPI
+// names compare case and separator insensitively.
+function normalizeBumpName(name: string): string {
+  return name.toLowerCase().replaceAll(':', '/').replace(/[-_.]+/g, '-')
+}
+
+function getHighestVersion(versions: string[]): string | undefined {
+  return versions.toSorted(naturalCompare).at(-1)
+}
+
+/**
+ * The one package the pull request upgrades. Lockfile entries that were only
+ * added or removed are not bumps.
+ */
+export function selectBotPrBump(
+  changes: BotPrVersionChange[],
+  titleName?: string | undefined,
+): CResult<BotPrBump> {
+  const bumps = new Map<string, BotPrBump>()
+  for (let i = 0, { length } = changes; i < length; i += 1) {
+    const change = changes[i]!
+    const from = getHighestVersion(
+      change.from.filter(v => !change.to.includes(v)),
+    )
+    const to = getHighestVersion(
+      change.to.filter(v => !change.from.includes(v)),
+    )
+    if (!from || !to) {
+      continue
+    }
+    const key = `${change.ecosystem} ${change.name}`
+    const existing = bumps.get(key)
+    if (existing) {
+      existing.files.push(change.file)
+    } else {
+      bumps.set(key, {
+        ecosystem: change.ecosystem,
+        files: [change.file],
+        from,
+        name: change.name,
+        to,
+      })
+    }
+  }
+  const candidates = [...bumps.values()]
+  const named = titleName
+    ? candidates.filter(
+        bump => normalizeBumpName(bump.name) === normalizeBumpName(titleName),
+      )
+    : []
+  if (named.length === 1) {
+    return { ok: true, data: named[0]! }
+  }
+  if (candidates.length === 1) {
+    return { ok: true, data: candidates[0]! }
+  }
+  if (!candidates.length) {
+    return {
+      ok: false,
+      message: 'No package bump found',
+      cause:
+        'No lockfile of the pull request changes the version of a package',
+    }
+  }
+  const names = candidates.map(bump => bump.name).toSorted(naturalCompare)
+  return {
+    ok: false,
+    message: 'Not a single package bump',
+    cause: `The pull request bumps ${candidates.length} packages (${names.slice(0, 5).join(', ')}${names.length > 5 ? ', …' : ''}) and its title names ${titleName ? `\`${titleName}\`, which is not among them` : 'none of them'}; use \`socket ci report\` for grouped updates`,
+  }
+}
+
+export function compareBotPrBump(
+  bump: BotPrBump,
+  before: BulkScoreRow,
+  after: BulkScoreRow,
+): BotPrComparison {
+  return {
+    addedAlerts: after.alertTypes.filter(t => !before.alertTypes.includes(t)),
+    after,
+    before,
+    bump,
+    removedAlerts: before.alertTypes.filter(t => !after.alertTypes.includes(t)),
+  }
+}
+
+function formatScoreChange(before?: number, after?: number): string {
+  if (before === undefined || after === undefined) {
+    return ''
+  }
+  const delta = after - before
+  return delta > 0 ? `+${delta}` : delta < 0 ? `${delta}` : '0'
+}
+
+function formatAlertList(types: string[]): string[] {
+  return types.map(type => `- [\`${type}\`](${getSocketDevAlertUrl(type)})`)
+}
+
+function getPackageUrl(purl: string): string | undefined {
+  try {
+    return getSocketDevPackageOverviewUrlFromPurl(purl)
+  } catch {
+    return undefined
+  }
+}
+
+/**
+ * Render the comment for the comparison of the old and the new version.
+ */
+export function generateBotPrComment(comparison: BotPrComparison): string {
+  const { addedAlerts, after, before, bump, removedAlerts } = comparison
+  const addedCount = addedAlerts.length
+  const verdict = !after.found
+    ? `⚠️ Socket has no data on ${bump.name}@${bump.to} yet.`
+    : addedCount
+      ? `⚠️ **The upgrade adds ${addedCount} ${pluralize('alert type', { count: addedCount })}.**`
+      : '✅ The upgrade adds no alerts.'
+
+  const parts = [
+    BOT_PR_COMMENT_MARKER,
+    `## Socket: \`${bump.name}\` ${bump.from} → ${bump.to}`,
+    '',
+    verdict,
+    '',
+    `| Score | ${bump.from} | ${bump.to} | Change |`,
+    '| --- | --- | --- | --- |',
+    ...SCORE_LABELS.map(({ 0: key, 1: label }) => {
+      const from = before.score?.[key]
+      const to = after.score?.[key]
+      return `| ${label} | ${from ?? '–'} | ${to ?? '–'} | ${formatScoreChange(from, to)} |`
+    }),
+  ]
+  if (addedCount) {
+    parts.push('', '### New alerts', '', ...formatAlertList(addedAlerts))
+  }
+  if (removedAlerts.length) {
+    parts.push('', '### Resolved alerts', '', ...formatAlertList(removedAlerts))
+  }
+  const packageUrl = getPackageUrl(after.purl)
+  parts.push(
+    '',
+    `<sub>Changed in ${bump.files.map(file => `\`${file}\``).join(', ')}${packageUrl ? `. [View the package](${packageUrl})` : ''}.</sub>`,
+  )
+  return parts.join('\n')
+}
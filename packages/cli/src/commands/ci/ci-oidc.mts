@@ -0,0 +1,129 @@
+/**
+ * OIDC identity of the running CI job for `socket ci token`.
+ *
+ * GitHub Actions hands out ID tokens on request when the workflow has the
+ * `id-token: write` permission. GitLab CI puts them in variables declared
+ * under `id_tokens`; the CLI reads SOCKET_ID_TOKEN. Both are issued for the
+ * SOCKET_OIDC_AUDIENCE audience so Socket accepts them.
+ */
+
+import { env } from 'node:process'
+
+import { errorMessage } from '@socketsecurity/lib-stable/errors/message'
+import { envAsString } from '@socketsecurity/lib-stable/env/string'
+
+import {
+  socketHttpRequest,
+  tryReadResponseText,
+} from '../../util/socket/api-http.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type CiOidcProvider = 'github' | 'gitlab'
+
+export type CiOidcIdentity = {
+  idToken: string
+  provider: CiOidcProvider
+}
+
+export const SOCKET_OIDC_AUDIENCE = 'https://socket.dev'
+
+export const GITLAB_ID_TOKEN_VARIABLE = 'SOCKET_ID_TOKEN'
+
+/**
+ * The CI provider whose OIDC identity is available to this job.
+ */
+export function detectCiOidcProvider(): CiOidcProvider | undefined {
+  if (
+    envAsString(env['ACTIONS_ID_TOKEN_REQUEST_URL']) &&
+    envAsString(env['ACTIONS_ID_TOKEN_REQUEST_TOKEN'])
+  ) {
+    return 'github'
+  }
+  if (
+    envAsString(env['GITLAB_CI']) &&
+    envAsString(env[GITLAB_ID_TOKEN_VARIABLE])
+  ) {
+    return 'gitlab'
+  }
+  return undefined
+}
+
+/**
+ * Request a GitHub Actions ID token, for Socket by default. `socket scan
+ * attest` asks for one with the Sigstore audience instead.
+ */
+export async function fetchGithubIdToken(
+  audience = SOCKET_OIDC_AUDIENCE,
+): Promise<CResult<string>> {
+  const requestUrl = envAsString(env['ACTIONS_ID_TOKEN_REQUEST_URL'])
+  const requestToken = envAsString(env['ACTIONS_ID_TOKEN_REQUEST_TOKEN'])
+  let url: string
+  try {
+    const parsed = new URL(requestUrl)
+    parsed.searchParams.set('audience', audience)
+    url = parsed.href
+  } catch {
+    return {
+      ok: false,
+      message: 'GitHub OIDC token unavailable',
+      cause: 'ACTIONS_ID_TOKEN_REQUEST_URL is not a valid URL',
+    }
+  }
+  try {
+    const response = await socketHttpRequest(url, {
+      headers: {
+        accept: 'application/json',
+        authorization: `bearer ${requestToken}`,
+      },
+      method: 'GET',
+    })
+    let value: unknown
+    try {
+      value = (JSON.parse(tryReadResponseText(response) ?? '') as {
+        value?: unknown
+      })?.value
+    } catch {}
+    if (response.status !== 200 || typeof value !== 'string' || !value) {
+      return {
+        ok: false,
+        message: 'GitHub OIDC token unavailable',
+        cause: `GitHub answered with status ${response.status}; make sure the workflow has the \`id-token: write\` permission`,
+        data: { code: response.status },
+      }
+    }
+    return { ok: true, data: value }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'GitHub OIDC token unavailable',
+      cause: `Could not request an ID token from GitHub: ${errorMessage(e)}`,
+    }
+  }
+}
+
+export async function fetchCiOidcIdentity(): Promise<
+  CResult<CiOidcIdentity>
+> {
+  const provider = detectCiOidcProvider()
+  if (provider === 'github') {
+    const idTokenCResult = await fetchGithubIdToken()
+    return idTokenCResult.ok
+      ? { ok: true, data: { idToken: idTokenCResult.data, provider } }
+      : idTokenCResult
+  }
+  if (provider === 'gitlab') {
+    return {
+      ok: true,
+      data: {
+        idToken: envAsString(env[GITLAB_ID_TOKEN_VARIABLE]),
+        provider,
+      },
+    }
+  }
+  return {
+    ok: false,
+    message: 'No CI OIDC identity',
+    cause: `Run in GitHub Actions with the \`id-token: write\` permission, or in GitLab CI with an \`id_tokens\` entry named ${GITLAB_ID_TOKEN_VARIABLE} for the audience ${SOCKET_OIDC_AUDIENCE}`,
+  }
+}
@@ -0,0 +1,136 @@
+import { handleCiEnrichBotPr } from './handle-ci-enrich-bot-pr.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'enrich-bot-pr'
+
+const description =
+  'Compare the old and new version of the package a Renovate or Dependabot pull request bumps'
+
+const hidden = false
+
+export const cmdCiEnrichBotPr: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      github: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Post the comparison as a comment on the pull request, updating the previous one',
+      },
+      gitlab: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Post the comparison as a note on the merge request, updating the previous one',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Tells Renovate and Dependabot pull requests apart by their source branch
+    or author, finds the one package the pull request bumps in its lockfiles,
+    and compares the scores and alert types of the old and the new version.
+    No scan is needed, so this is quicker than \`socket ci report\` and only
+    shows the upgrade the pull request is about. Other pull requests are
+    skipped, so the step can run on all of them.
+
+    The lockfiles are compared with the merge base of \`origin/<base>\`, which
+    must be fetched. When several packages changed, the one the title names
+    is compared; grouped updates are left to \`socket ci report\`.
+
+    --github and --gitlab post the comparison as a comment that is updated
+    in place on later runs, with the same tokens and pipeline variables as
+    \`socket ci report\`.
+
+    Examples
+      $ ${command}
+      $ ${command} --github
+      $ ${command} --gitlab --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { github, gitlab, json, markdown } = cli.flags as {
+    github: boolean
+    gitlab: boolean
+    json: boolean
+    markdown: boolean
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: !github || !gitlab,
+      message: 'The `--github` and `--gitlab` flags can not be used at the same time',
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('bot pull request comparison', { github, gitlab })
+    return
+  }
+
+  await handleCiEnrichBotPr({
+    outputKind,
+    platform: github ? 'github' : gitlab ? 'gitlab' : undefined,
+  })
+}
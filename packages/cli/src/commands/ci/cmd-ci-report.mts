@@ -0,0 +1,169 @@
+import { handleCiReport } from './handle-ci-report.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'report'
+
+const description =
+  'Summarize the alert changes of a pull request, optionally as a PR or MR comment'
+
+const hidden = false
+
+export const cmdCiReport: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      after: {
+        type: 'string',
+        default: '',
+        description:
+          'Scan ID of the pull request head (default: latest scan of the source branch)',
+      },
+      before: {
+        type: 'string',
+        default: '',
+        description:
+          'Scan ID to compare against (default: latest scan of the target branch)',
+      },
+      github: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Post the report as a comment on the pull request, updating the previous one',
+      },
+      gitlab: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Post the report as a note on the merge request, updating the previous one',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Compares the alerts of two scans against the security policy and renders
+    a markdown summary: counts of new, blocking and resolved alerts, and a
+    collapsed section per new alert. Run \`socket ci\` on both branches first,
+    or pass the scan IDs to compare. By default the branches come from
+    GITHUB_BASE_REF and GITHUB_HEAD_REF, or from the GitLab merge request
+    pipeline variables.
+
+    With --github the summary is posted to the pull request. The comment is
+    updated in place on later runs rather than added again. This needs
+    GITHUB_TOKEN (or SOCKET_CLI_GITHUB_TOKEN) with permission to write pull
+    request comments, and the GITHUB_REPOSITORY and GITHUB_REF variables of a
+    GitHub Actions pull_request build.
+
+    With --gitlab the summary is posted as a merge request note in the same
+    way. This needs GITLAB_TOKEN with the \`api\` scope and a merge request
+    pipeline, where CI_PROJECT_PATH and CI_MERGE_REQUEST_IID are set. The
+    instance is taken from GITLAB_HOST or CI_SERVER_URL.
+
+    The exit code is non-zero when a new alert violates the security policy.
+
+    Examples
+      $ ${command} --github
+      $ ${command} --gitlab
+      $ ${command} --before 000aaaa1-0000-0a0a-00a0-00a0000000a0 --after 000bbbb2-0000-0b0b-00b0-00b0000000b0 --markdown
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { after, before, github, gitlab, json, markdown } = cli.flags as {
+    after: string
+    before: string
+    github: boolean
+    gitlab: boolean
+    json: boolean
+    markdown: boolean
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: !github || !gitlab,
+      message: 'The `--github` and `--gitlab` flags can not be used at the same time',
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: hasDefaultApiToken(),
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('CI report', {
+      after: after || '(latest scan of the source branch)',
+      before: before || '(latest scan of the target branch)',
+      github,
+      gitlab,
+    })
+    return
+  }
+
+  await handleCiReport({
+    afterScanId: after,
+    beforeScanId: before,
+    outputKind,
+    platform: github ? 'github' : gitlab ? 'gitlab' : undefined,
+  })
+}
@@ -0,0 +1,154 @@
+import { CI_TOKEN_SCOPES } from './fetch-ci-token.mts'
+import { handleCiToken } from './handle-ci-token.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'token'
+
+const description =
+  'Mint a short-lived API token that can only create scans, for CI jobs'
+
+const hidden = false
+
+const MIN_TTL_MINUTES = 5
+
+const MAX_TTL_MINUTES = 1440
+
+export const cmdCiToken: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      oidc: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Log in with the OIDC identity of the CI job even when an API token is set',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      ttl: {
+        type: 'number',
+        default: 60,
+        description: `Lifetime of the token in minutes, ${MIN_TTL_MINUTES} to ${MAX_TTL_MINUTES}`,
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Exchanges a long-lived credential for an API token that expires after
+    --ttl minutes and can only create scans, so the long-lived one does not
+    have to be stored as a CI secret of every repository.
+
+    The credential is the API token of the org when one is set. Otherwise,
+    or with --oidc, it is the OIDC identity of the CI job:
+
+    - GitHub Actions: give the workflow the \`id-token: write\` permission.
+    - GitLab CI: declare an \`id_tokens\` entry named SOCKET_ID_TOKEN with the
+      audience https://socket.dev.
+
+    An OIDC login needs --org or SOCKET_CLI_ORG_SLUG. Only the token is
+    printed, so it can be captured into SOCKET_CLI_API_TOKEN for the next
+    steps.
+
+    Examples
+      $ ${command}
+      $ ${command} --oidc --org my-org --ttl 15
+      $ SOCKET_CLI_API_TOKEN=$(${command} --oidc --org my-org) socket ci
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown, oidc, org, ttl } = cli.flags as {
+    json: boolean
+    markdown: boolean
+    oidc: boolean
+    org: string
+    ttl: number
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test:
+        Number.isInteger(ttl) &&
+        ttl >= MIN_TTL_MINUTES &&
+        ttl <= MAX_TTL_MINUTES,
+      message: `The --ttl flag must be a whole number of minutes from ${MIN_TTL_MINUTES} to ${MAX_TTL_MINUTES}`,
+      fail: `got ${ttl}`,
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('a short-lived CI token', {
+      credential: oidc
+        ? 'CI OIDC identity'
+        : 'API token, else CI OIDC identity',
+      org: org || '(default org)',
+      scopes: CI_TOKEN_SCOPES.join(', '),
+      ttlMinutes: ttl,
+    })
+    return
+  }
+
+  await handleCiToken({ oidc, orgSlug: org, outputKind, ttlMinutes: ttl })
+}
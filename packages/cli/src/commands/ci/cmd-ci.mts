@@ -1,5 +1,12 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { cmdCiEnrichBotPr } from './cmd-ci-enrich-bot-pr.mts'
+import { cmdCiReport } from './cmd-ci-report.mts'
+import { cmdCiToken } from './cmd-ci-token.mts'
 import { getDefaultOrgSlug } from './fetch-default-org-slug.mts'
 import { handleCi } from './handle-ci.mts'
+import { handleCiSmart } from './handle-ci-smart.mts'
+import { FAIL_ON_LEVELS } from '../../constants/reporting.mts'
 import { defineFlags } from '../../meow.mts'
 import { commonFlags } from '../../flags.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
@@ -10,10 +17,21 @@ import {
   gitBranch,
 } from '../../util/git/operations.mjs'
 import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+import { failOnFlags } from '../scan/fail-on-flags.mts'
 
 import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { FAIL_ON } from '../scan/types.mts'
 import type { MeowFlags } from '../../flags.mts'
 
+// `socket ci` runs the scan itself; `enrich-bot-pr`, `report` and `token`
+// are its subcommands.
+const CMD_ENRICH_BOT_PR = 'enrich-bot-pr'
+
+const CMD_REPORT = 'report'
+
+const CMD_TOKEN = 'token'
+
 const config = {
   commandName: 'ci',
   description:
@@ -27,6 +45,13 @@ const config = {
       description:
         'Auto generate manifest files where detected? See autoManifest flag in `socket scan create`',
     },
+    ...failOnFlags,
+    smart: {
+      type: 'boolean',
+      default: false,
+      description:
+        'Only scan the manifests the pull request changed and compare with the latest scan of the base branch',
+    },
   }),
   help: (command: string, _config: { flags: MeowFlags }) => `
     Usage
@@ -40,14 +65,42 @@ const config = {
     Socket API token. The exit code will be non-zero when the scan does not pass
     your security policy.
 
+    Exit codes: 0 when the scan passes, 3 when it violates the policy, 4 on
+    Socket API errors, 5 on auth errors and 2 on invalid usage. Other
+    failures exit with 1, so a pipeline can tell "found issues" from "the
+    tool broke". Pass --fail-on to gate on alert severity instead of on the
+    "error" policy level, e.g. --fail-on=critical, or --fail-on=none to only
+    fail when the scan itself fails.
+
     The --auto-manifest flag does the same as the one from \`socket scan create\`
     but is not enabled by default since the CI is less likely to be set up with
     all the necessary dev tooling. Enable it if you want the scan to include
     locally generated manifests like for gradle and sbt.
 
+    With --smart, only the manifests and lockfiles in dirs the pull request
+    changed since the base branch are uploaded, as a scan hidden from the
+    dashboard, and the build only fails on alerts that scan adds compared to
+    the latest scan of the base branch. The base branch must be fetched, at
+    \`origin/<base>\`, and needs an earlier \`${command}\` scan.
+
+    Run \`${command} report\` to summarize the alert changes of a pull request,
+    or \`${command} report --github\` to post that summary as a PR comment
+    (\`--gitlab\` for a merge request note).
+
+    Run \`${command} enrich-bot-pr --github\` on Renovate and Dependabot pull
+    requests to comment the score and alert changes of the bumped package.
+
+    Run \`${command} token\` to mint a short-lived API token for the CI job,
+    from the org API token or the OIDC identity of the job.
+
     Examples
       $ ${command}
       $ ${command} --auto-manifest
+      $ ${command} --fail-on=high
+      $ ${command} --smart
+      $ ${command} report --github
+      $ ${command} enrich-bot-pr --github
+      $ ${command} token --oidc --org my-org
   `,
   hidden: false,
 }
@@ -63,6 +116,25 @@ export async function run(
   importMeta: ImportMeta,
   { parentName }: CliCommandContext,
 ): Promise<void> {
+  if (argv[0] === CMD_ENRICH_BOT_PR) {
+    await cmdCiEnrichBotPr.run(argv.slice(1), importMeta, {
+      parentName: `${parentName} ${config.commandName}`,
+    })
+    return
+  }
+  if (argv[0] === CMD_REPORT) {
+    await cmdCiReport.run(argv.slice(1), importMeta, {
+      parentName: `${parentName} ${config.commandName}`,
+    })
+    return
+  }
+  if (argv[0] === CMD_TOKEN) {
+    await cmdCiToken.run(argv.slice(1), importMeta, {
+      parentName: `${parentName} ${config.commandName}`,
+    })
+    return
+  }
+
   const cli = meowOrExit({
     argv,
     config,
@@ -72,6 +144,28 @@ export async function run(
 
   const dryRun = cli.flags['dryRun']
   const autoManifest = cli.flags['autoManifest']
+  const failOn = String(cli.flags['failOn'] || '')
+  const smart = Boolean(cli.flags['smart'])
+
+  const wasValidInput = checkCommandInput(
+    'json',
+    {
+      nook: true,
+      test: !failOn || (FAIL_ON_LEVELS as readonly string[]).includes(failOn),
+      message: `The --fail-on flag must be ${joinOr(FAIL_ON_LEVELS.map(f => `'${f}'`))}`,
+      fail: `got ${failOn}`,
+    },
+    {
+      nook: true,
+      test: !smart || !autoManifest,
+      message:
+        'The --smart flag cannot be used with --auto-manifest, generated manifests are not in the git diff',
+      fail: 'omit one',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
 
   if (dryRun) {
     const orgSlugCResult = await getDefaultOrgSlug()
@@ -84,15 +178,22 @@ export async function run(
       autoManifest,
       branchName: branchName || '(default)',
       cwd,
+      ...(failOn ? { failOn } : {}),
       organizationSlug: orgSlugCResult.ok
         ? orgSlugCResult.data
         : '(from API token)',
       repoName: repoName || '(auto-detected)',
       report: true,
+      ...(smart ? { smart } : {}),
       targets: ['.'],
     })
     return
   }
 
-  await handleCi(autoManifest)
+  const failOnLevel = (failOn || undefined) as FAIL_ON | undefined
+  if (smart) {
+    await handleCiSmart(failOnLevel)
+    return
+  }
+  await handleCi(autoManifest, failOnLevel)
 }
@@ -0,0 +1,109 @@
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+
+import { getNetworkErrorDiagnostics } from '../../util/error/errors.mts'
+import { sendApiRequest } from '../../util/socket/api.mjs'
+import { getErrorMessageForHttpStatusCode } from '../../util/socket/api-error-messages.mts'
+import {
+  getDefaultApiBaseUrl,
+  socketHttpRequest,
+  tryReadResponseText,
+} from '../../util/socket/api-http.mts'
+
+import type { CiOidcIdentity } from './ci-oidc.mts'
+import type { CResult } from '../../types.mts'
+
+// A CI job only needs to create scans.
+export const CI_TOKEN_SCOPES = ['full-scans:create']
+
+export type CiTokenResponse = {
+  expires_at: string
+  scopes: string[]
+  token: string
+}
+
+export type FetchCiTokenOptions = {
+  // Exchanged instead of the API token when given.
+  identity?: CiOidcIdentity | undefined
+  orgSlug: string
+  ttlMinutes: number
+}
+
+async function exchangeOidcIdentity(
+  identity: CiOidcIdentity,
+  orgSlug: string,
+  ttlMinutes: number,
+): Promise<CResult<CiTokenResponse>> {
+  const path = 'oidc/ci-token'
+  const baseUrl = getDefaultApiBaseUrl()
+  const url = `${baseUrl}${baseUrl?.endsWith('/') ? '' : '/'}${path}`
+  const startTime = Date.now()
+  try {
+    const response = await socketHttpRequest(url, {
+      body: JSON.stringify({
+        id_token: identity.idToken,
+        org: orgSlug,
+        provider: identity.provider,
+        scopes: CI_TOKEN_SCOPES,
+        ttl_minutes: ttlMinutes,
+      }),
+      headers: { 'Content-Type': 'application/json' },
+      method: 'POST',
+    })
+    const { status } = response
+    if (status < 200 || status >= 300) {
+      debug(`OIDC token exchange failed: ${tryReadResponseText(response)}`)
+      return {
+        ok: false,
+        message: 'Socket API error',
+        cause: `${response.statusText} (reason: ${await getErrorMessageForHttpStatusCode(status)}) (path: ${path})`,
+        data: { code: status },
+      }
+    }
+    try {
+      return {
+        ok: true,
+        data: JSON.parse(
+          tryReadResponseText(response) ?? '',
+        ) as CiTokenResponse,
+      }
+    } catch {
+      return {
+        ok: false,
+        message: 'API request failed',
+        cause: `Unexpected error parsing response JSON (path: ${path})`,
+      }
+    }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'API request failed',
+      cause: `${getNetworkErrorDiagnostics(e, Date.now() - startTime)} (path: ${path})`,
+    }
+  }
+}
+
+/**
+ * Mint a short-lived API token that can only create scans, in return for the
+ * API token of the org or the OIDC identity of the CI job.
+ */
+export async function fetchCiToken(
+  options: FetchCiTokenOptions,
+): Promise<CResult<CiTokenResponse>> {
+  const { identity, orgSlug, ttlMinutes } = {
+    __proto__: null,
+    ...options,
+  } as FetchCiTokenOptions
+  if (identity) {
+    return await exchangeOidcIdentity(identity, orgSlug, ttlMinutes)
+  }
+  // Not wrapped by the SDK yet, so call the endpoint directly.
+  return await sendApiRequest<CiTokenResponse>(
+    `orgs/${encodeURIComponent(orgSlug)}/api-tokens/ci`,
+    {
+      method: 'POST',
+      body: { scopes: CI_TOKEN_SCOPES, ttl_minutes: ttlMinutes },
+      commandPath: 'socket ci token',
+      description: 'a CI token',
+    },
+  )
+}
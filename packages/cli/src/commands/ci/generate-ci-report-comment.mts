@@ -0,0 +1,137 @@
+/**
+ * Markdown body of the pull request comment posted by `socket ci report`.
+ *
+ * The comment leads with a one-line verdict and a count table, then lists
+ * every new alert in its own collapsed `<details>` section so large diffs
+ * stay readable. The hidden marker on the first line is how later runs find
+ * the comment to update instead of adding another one.
+ */
+
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { REPORT_LEVEL_ERROR } from '../../constants/reporting.mts'
+import {
+  getSocketDevAlertUrl,
+  getSocketDevPackageOverviewUrlFromPurl,
+} from '../../util/socket/url.mts'
+
+import type {
+  ScanAlertDiff,
+  ScanDiffAlert,
+} from '../scan/diff-scan-alerts.mts'
+
+export const CI_REPORT_COMMENT_MARKER = '<!-- socket-ci-report -->'
+
+// GitHub rejects comment bodies over 65536 characters.
+const MAX_COMMENT_LENGTH = 60_000
+
+export type CiReportCommentOptions = {
+  afterScanId: string
+  beforeScanId: string
+  reportUrl?: string | undefined
+}
+
+function getPackageUrl(purl: string): string | undefined {
+  try {
+    return getSocketDevPackageOverviewUrlFromPurl(purl)
+  } catch {
+    return undefined
+  }
+}
+
+export function formatAlertDetails(alert: ScanDiffAlert): string {
+  const icon = alert.action === REPORT_LEVEL_ERROR ? '🚫' : '⚠️'
+  const packageUrl = getPackageUrl(alert.purl)
+  const lines = [
+    '<details>',
+    `<summary>${icon} <code>${alert.type}</code> in <code>${alert.purl}</code></summary>`,
+    '',
+    `- Severity: ${alert.severity}`,
+    `- Policy action: ${alert.action || 'none'}`,
+  ]
+  if (alert.file) {
+    lines.push(`- Manifest: \`${alert.file}\``)
+  }
+  lines.push(`- About this alert: ${getSocketDevAlertUrl(alert.type)}`)
+  if (packageUrl) {
+    lines.push(`- Package: ${packageUrl}`)
+  }
+  lines.push('', '</details>')
+  return lines.join('\n')
+}
+
+/**
+ * Render the comment for an alert diff. Blocking alerts are listed before the
+ * rest; once the body nears GitHub's size limit the remaining alerts are
+ * summarized by count.
+ */
+export function generateCiReportComment(
+  diff: ScanAlertDiff,
+  options: CiReportCommentOptions,
+): string {
+  const { afterScanId, beforeScanId, reportUrl } = {
+    __proto__: null,
+    ...options,
+  } as CiReportCommentOptions
+  const { added, blocking, removed } = diff
+
+  const blockingCount = blocking.length
+  const verdict = blockingCount
+    ? `🚫 **${blockingCount} new ${pluralize('alert', { count: blockingCount })} ${blockingCount === 1 ? 'violates' : 'violate'} the security policy.**`
+    : added.length
+      ? '✅ No new alerts violate the security policy.'
+      : '✅ No new alerts.'
+
+  const parts = [
+    CI_REPORT_COMMENT_MARKER,
+    '## Socket Security report',
+    '',
+    verdict,
+    '',
+    '| Alerts | Count |',
+    '| --- | --- |',
+    `| New | ${added.length} |`,
+    `| Blocking | ${blockingCount} |`,
+    `| Resolved | ${removed.length} |`,
+  ]
+
+  const sorted = [
+    ...blocking,
+    ...added.filter(alert => alert.action !== REPORT_LEVEL_ERROR),
+  ]
+  if (sorted.length) {
+    parts.push('', '### New alerts', '')
+    let length = parts.join('\n').length
+    for (let i = 0, { length: count } = sorted; i < count; i += 1) {
+      const section = formatAlertDetails(sorted[i]!)
+      if (length + section.length > MAX_COMMENT_LENGTH) {
+        const rest = count - i
+        parts.push(`… and ${rest} more ${pluralize('alert', { count: rest })}.`)
+        break
+      }
+      parts.push(section)
+      length += section.length + 1
+    }
+  }
+
+  if (removed.length) {
+    parts.push(
+      '',
+      '<details>',
+      `<summary>✨ ${removed.length} resolved ${pluralize('alert', { count: removed.length })}</summary>`,
+      '',
+      ...removed
+        .slice(0, 100)
+        .map(alert => `- \`${alert.type}\` in \`${alert.purl}\``),
+      ...(removed.length > 100 ? [`- … and ${removed.length - 100} more`] : []),
+      '',
+      '</details>',
+    )
+  }
+
+  parts.push(
+    '',
+    `<sub>Compared scan \`${beforeScanId}\` with \`${afterScanId}\`${reportUrl ? `. [View the scan](${reportUrl})` : ''}.</sub>`,
+  )
+  return parts.join('\n')
+}
@@ -0,0 +1,206 @@
+import { env } from 'node:process'
+
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+import { SOCKET_PUBLIC_API_TOKEN } from '@socketsecurity/lib-stable/constants/socket'
+import { envAsString } from '@socketsecurity/lib-stable/env/string'
+import { readJsonSync } from '@socketsecurity/lib-stable/fs/read-json'
+
+import {
+  BOT_PR_COMMENT_MARKER,
+  compareBotPrBump,
+  detectDependencyBot,
+  generateBotPrComment,
+  parseBotPrTitle,
+  selectBotPrBump,
+} from './bot-pr.mts'
+import { postCiReportComment } from './handle-ci-report.mts'
+import { resolveCiBaseBranch } from './handle-ci-smart.mts'
+import { outputCiEnrichBotPr } from './output-ci-enrich-bot-pr.mts'
+import {
+  gitDiffChangedFiles,
+  gitMergeBase,
+  gitShowFile,
+  gitTopLevel,
+} from '../../util/git/operations.mjs'
+import { getDefaultApiToken } from '../../util/socket/sdk.mjs'
+import {
+  diffDependencyVersions,
+  getDependencyVersions,
+} from '../fix/fix-changelog.mts'
+import {
+  getDependencyPurl,
+  getLockfileEcosystem,
+} from '../hooks/handle-hooks-run.mts'
+import { getBulkScoreData } from '../package/bulk-score.mts'
+import { fetchPurlsShallowScore } from '../package/fetch-purls-shallow-score.mts'
+
+import type {
+  BotPrComparison,
+  BotPrContext,
+  BotPrVersionChange,
+  DependencyBot,
+} from './bot-pr.mts'
+import type { CiReportComment, CiReportPlatform } from './handle-ci-report.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export type CiEnrichBotPr = {
+  body?: string | undefined
+  // Undefined when the pull request is not from Renovate or Dependabot.
+  bot: DependencyBot | undefined
+  comment?: CiReportComment | undefined
+  comparison?: BotPrComparison | undefined
+}
+
+type PullRequestEvent = {
+  pull_request?: { title?: unknown; user?: { login?: unknown } } | undefined
+}
+
+/**
+ * Source branch, author and title of the pull request, from the GitLab merge
+ * request variables or the GitHub Actions event payload.
+ */
+export function getBotPrContext(): BotPrContext {
+  const gitlabBranch = envAsString(env['CI_MERGE_REQUEST_SOURCE_BRANCH_NAME'])
+  if (gitlabBranch) {
+    return {
+      author: envAsString(env['GITLAB_USER_LOGIN']),
+      branch: gitlabBranch,
+      title: envAsString(env['CI_MERGE_REQUEST_TITLE']),
+    }
+  }
+  const eventPath = envAsString(env['GITHUB_EVENT_PATH'])
+  const event = eventPath
+    ? (readJsonSync(eventPath, { throws: false }) as
+        | PullRequestEvent
+        | undefined)
+    : undefined
+  const pr = event?.pull_request
+  return {
+    author: typeof pr?.user?.login === 'string' ? pr.user.login : '',
+    branch: envAsString(env['GITHUB_HEAD_REF']),
+    title: typeof pr?.title === 'string' ? pr.title : '',
+  }
+}
+
+/**
+ * Versions the lockfiles of the pull request change since the merge base.
+ */
+async function getBotPrVersionChanges(
+  cwd: string,
+): Promise<CResult<BotPrVersionChange[]>> {
+  const baseBranch = await resolveCiBaseBranch(cwd)
+  // CI checkouts track the base branch on the remote, not as a local branch.
+  const from = await gitMergeBase('HEAD', `origin/${baseBranch}`, cwd)
+  if (!from) {
+    return {
+      ok: false,
+      message: 'Missing base branch',
+      cause: `Fetch origin/${baseBranch} so the pull request can be compared with it, e.g. with \`fetch-depth: 0\``,
+    }
+  }
+  debug(`Bot PR changes since ${baseBranch} (${from})`)
+  const filesCResult = await gitDiffChangedFiles(from, 'HEAD', cwd)
+  if (!filesCResult.ok) {
+    return filesCResult
+  }
+  const changes: BotPrVersionChange[] = []
+  for (const file of filesCResult.data) {
+    const ecosystem = getLockfileEcosystem(file)
+    if (!ecosystem) {
+      continue
+    }
+    const current = await gitShowFile(file, 'HEAD', cwd)
+    const previous = await gitShowFile(file, from, cwd)
+    const after =
+      current === undefined ? undefined : getDependencyVersions(file, current)
+    const before =
+      previous === undefined ? undefined : getDependencyVersions(file, previous)
+    if (after && before) {
+      for (const change of diffDependencyVersions(file, before, after)) {
+        changes.push({ ...change, ecosystem })
+      }
+    }
+  }
+  return { ok: true, data: changes }
+}
+
+export async function getCiEnrichBotPr({
+  platform,
+}: {
+  // Where to post the comparison; undefined only renders it.
+  platform?: CiReportPlatform | undefined
+}): Promise<CResult<CiEnrichBotPr>> {
+  const context = getBotPrContext()
+  const bot = detectDependencyBot(context)
+  if (!bot) {
+    return { ok: true, data: { bot } }
+  }
+  const cwd = await gitTopLevel(process.cwd())
+  if (!cwd) {
+    return {
+      ok: false,
+      message: 'Not a git repository',
+      cause: `${process.cwd()} is not in a git repository.`,
+    }
+  }
+
+  const changesCResult = await getBotPrVersionChanges(cwd)
+  if (!changesCResult.ok) {
+    return changesCResult
+  }
+  const bumpCResult = selectBotPrBump(
+    changesCResult.data,
+    parseBotPrTitle(context.title),
+  )
+  if (!bumpCResult.ok) {
+    return bumpCResult
+  }
+  const bump = bumpCResult.data
+
+  const purls = [
+    getDependencyPurl(bump.ecosystem, bump.name, bump.from),
+    getDependencyPurl(bump.ecosystem, bump.name, bump.to),
+  ]
+  const scoreCResult = await fetchPurlsShallowScore(purls, {
+    commandPath: 'socket ci enrich-bot-pr',
+    // Workflows Dependabot triggers get no secrets, so fall back to the public
+    // token for the scores.
+    sdkOpts: { apiToken: getDefaultApiToken() || SOCKET_PUBLIC_API_TOKEN },
+  })
+  if (!scoreCResult.ok) {
+    return scoreCResult
+  }
+  const { packages } = getBulkScoreData(
+    purls,
+    scoreCResult.data as unknown as SocketArtifact[],
+  )
+  const comparison = compareBotPrBump(bump, packages[0]!, packages[1]!)
+  const body = generateBotPrComment(comparison)
+  if (!platform) {
+    return { ok: true, data: { body, bot, comparison } }
+  }
+
+  const commentCResult = await postCiReportComment(
+    body,
+    platform,
+    BOT_PR_COMMENT_MARKER,
+  )
+  if (!commentCResult.ok) {
+    return commentCResult
+  }
+  return {
+    ok: true,
+    data: { body, bot, comment: commentCResult.data, comparison },
+  }
+}
+
+export async function handleCiEnrichBotPr({
+  outputKind,
+  platform,
+}: {
+  outputKind: OutputKind
+  platform?: CiReportPlatform | undefined
+}): Promise<void> {
+  await outputCiEnrichBotPr(await getCiEnrichBotPr({ platform }), outputKind)
+}
@@ -0,0 +1,321 @@
+import { env } from 'node:process'
+
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { envAsString } from '@socketsecurity/lib-stable/env/string'
+
+import { getDefaultOrgSlug } from './fetch-default-org-slug.mts'
+import {
+  CI_REPORT_COMMENT_MARKER,
+  generateCiReportComment,
+} from './generate-ci-report-comment.mts'
+import { detectCiPullRequestNumber } from './handle-ci.mts'
+import { outputCiReport } from './output-ci-report.mts'
+import { GITHUB_BASE_REF } from '../../env/github-base-ref.mts'
+import { SOCKET_CLI_GITHUB_TOKEN } from '../../env/socket-cli-github-token.mts'
+import { formatErrorWithDetail } from '../../util/error/errors.mts'
+import { GitHubProvider } from '../../util/git/github-provider.mts'
+import { GitLabProvider } from '../../util/git/gitlab-provider.mts'
+import { getRepoName } from '../../util/git/operations.mjs'
+import { ciRepoInfo } from '../fix/env-helpers.mts'
+import { fetchOrgFullScanList } from '../scan/fetch-list-scans.mts'
+import { fetchScanAlertDiff } from '../scan/fetch-scan-alert-diff.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type {
+  PrProvider,
+  UpsertCommentResult,
+} from '../../util/git/provider.mts'
+
+export type CiReportPlatform = 'github' | 'gitlab'
+
+export type CiReportComment = {
+  platform: CiReportPlatform
+  prNumber: number
+  repo: string
+  status: UpsertCommentResult
+}
+
+export type CiReport = {
+  afterScanId: string
+  beforeScanId: string
+  blocking: number
+  body: string
+  comment?: CiReportComment | undefined
+}
+
+export type LatestScan = {
+  id: string
+  reportUrl?: string | undefined
+}
+
+export async function fetchLatestScan(
+  orgSlug: string,
+  repoName: string,
+  branch: string,
+  commandPath = 'socket ci report',
+): Promise<CResult<LatestScan>> {
+  const listCResult = await fetchOrgFullScanList(
+    {
+      branch,
+      direction: 'desc',
+      from_time: '',
+      orgSlug,
+      page: 1,
+      perPage: 1,
+      repo: repoName,
+      sort: 'created_at',
+    },
+    { commandPath },
+  )
+  if (!listCResult.ok) {
+    return listCResult
+  }
+  const scan = listCResult.data.results[0]
+  if (!scan?.id) {
+    return {
+      ok: false,
+      message: 'No scan found',
+      cause: `There is no scan of ${repoName} on branch ${branch}; run \`socket ci\` on that branch first or pass the scan ID`,
+    }
+  }
+  return {
+    ok: true,
+    data: {
+      id: scan.id,
+      ...(scan.html_report_url ? { reportUrl: scan.html_report_url } : {}),
+    },
+  }
+}
+
+/**
+ * Work out which scans to compare. Scan IDs given as flags win; otherwise the
+ * latest scans of the pull request (or merge request) target and source
+ * branches are used.
+ */
+export async function resolveCiReportScans({
+  afterScanId,
+  beforeScanId,
+  orgSlug,
+  repoName,
+}: {
+  afterScanId: string
+  beforeScanId: string
+  orgSlug: string
+  repoName: string
+}): Promise<CResult<{ after: LatestScan; before: LatestScan }>> {
+  const baseRef =
+    GITHUB_BASE_REF ||
+    envAsString(env['CI_MERGE_REQUEST_TARGET_BRANCH_NAME'])
+  const headRef =
+    envAsString(env['GITHUB_HEAD_REF']) ||
+    envAsString(env['CI_MERGE_REQUEST_SOURCE_BRANCH_NAME'])
+  const resolve = async (
+    scanId: string,
+    branch: string,
+    flag: string,
+  ): Promise<CResult<LatestScan>> => {
+    if (scanId) {
+      return { ok: true, data: { id: scanId } }
+    }
+    if (!branch) {
+      return {
+        ok: false,
+        message: 'Missing scan to compare',
+        cause: `Pass ${flag} or run in a GitHub pull request or GitLab merge request pipeline`,
+      }
+    }
+    return await fetchLatestScan(orgSlug, repoName, branch)
+  }
+  const [before, after] = await Promise.all([
+    resolve(beforeScanId, baseRef, '--before'),
+    resolve(afterScanId, headRef, '--after'),
+  ])
+  if (!before.ok) {
+    return before
+  }
+  if (!after.ok) {
+    return after
+  }
+  return { ok: true, data: { after: after.data, before: before.data } }
+}
+
+type CiReportContext = {
+  owner: string
+  prNumber: number
+  provider: PrProvider
+  repo: string
+}
+
+function getGithubReportContext(): CResult<CiReportContext> {
+  if (!SOCKET_CLI_GITHUB_TOKEN) {
+    return {
+      ok: false,
+      message: 'Missing GitHub token',
+      cause:
+        'Set GITHUB_TOKEN (or SOCKET_CLI_GITHUB_TOKEN) to a token that can write pull request comments',
+    }
+  }
+  const repoInfo = ciRepoInfo()
+  const prNumber = detectCiPullRequestNumber()
+  if (!repoInfo || !prNumber) {
+    return {
+      ok: false,
+      message: 'Missing pull request context',
+      cause:
+        'Run in a GitHub Actions pull_request build, where GITHUB_REPOSITORY and GITHUB_REF name the pull request',
+    }
+  }
+  return {
+    ok: true,
+    data: { ...repoInfo, prNumber, provider: new GitHubProvider() },
+  }
+}
+
+function getGitlabReportContext(): CResult<CiReportContext> {
+  if (!envAsString(env['GITLAB_TOKEN'])) {
+    return {
+      ok: false,
+      message: 'Missing GitLab token',
+      cause:
+        'Set GITLAB_TOKEN to a token with the `api` scope; CI_JOB_TOKEN cannot write merge request notes',
+    }
+  }
+  // Nested groups are part of the owner: `group/subgroup/project`.
+  const projectPath = envAsString(env['CI_PROJECT_PATH'])
+  const slashIndex = projectPath.lastIndexOf('/')
+  const prNumber = Number(envAsString(env['CI_MERGE_REQUEST_IID']))
+  if (slashIndex === -1 || !Number.isInteger(prNumber) || prNumber <= 0) {
+    return {
+      ok: false,
+      message: 'Missing merge request context',
+      cause:
+        'Run in a GitLab merge request pipeline, where CI_PROJECT_PATH and CI_MERGE_REQUEST_IID name the merge request',
+    }
+  }
+  return {
+    ok: true,
+    data: {
+      owner: projectPath.slice(0, slashIndex),
+      prNumber,
+      provider: new GitLabProvider(),
+      repo: projectPath.slice(slashIndex + 1),
+    },
+  }
+}
+
+/**
+ * Post the report as a pull request comment or merge request note, updating
+ * the one from an earlier run when there is one. The marker tells the
+ * comments of different reports apart.
+ */
+export async function postCiReportComment(
+  body: string,
+  platform: CiReportPlatform,
+  marker = CI_REPORT_COMMENT_MARKER,
+): Promise<CResult<CiReportComment>> {
+  const contextCResult =
+    platform === 'gitlab' ? getGitlabReportContext() : getGithubReportContext()
+  if (!contextCResult.ok) {
+    return contextCResult
+  }
+  const { owner, prNumber, provider, repo } = contextCResult.data
+  try {
+    const status = await provider.upsertComment({
+      body,
+      marker,
+      owner,
+      prNumber,
+      repo,
+    })
+    return {
+      ok: true,
+      data: { platform, prNumber, repo: `${owner}/${repo}`, status },
+    }
+  } catch (e) {
+    debugDir(e)
+    const target =
+      platform === 'gitlab' ? `MR !${prNumber}` : `PR #${prNumber}`
+    return {
+      ok: false,
+      message: 'Failed to post the report comment',
+      cause: formatErrorWithDetail(`Unable to comment on ${target}`, e),
+    }
+  }
+}
+
+export async function getCiReport({
+  afterScanId,
+  beforeScanId,
+  platform,
+}: {
+  afterScanId: string
+  beforeScanId: string
+  // Where to post the report; undefined only renders it.
+  platform?: CiReportPlatform | undefined
+}): Promise<CResult<CiReport>> {
+  const orgSlugCResult = await getDefaultOrgSlug()
+  if (!orgSlugCResult.ok) {
+    return orgSlugCResult
+  }
+  const orgSlug = orgSlugCResult.data
+  const repoName = await getRepoName(process.cwd())
+
+  const scansCResult = await resolveCiReportScans({
+    afterScanId,
+    beforeScanId,
+    orgSlug,
+    repoName,
+  })
+  if (!scansCResult.ok) {
+    return scansCResult
+  }
+  const { after, before } = scansCResult.data
+  debug(`CI report for ${orgSlug}/${repoName}: ${before.id}..${after.id}`)
+
+  const diffCResult = await fetchScanAlertDiff({
+    id1: before.id,
+    id2: after.id,
+    orgSlug,
+  })
+  if (!diffCResult.ok) {
+    return diffCResult
+  }
+
+  const body = generateCiReportComment(diffCResult.data, {
+    afterScanId: after.id,
+    beforeScanId: before.id,
+    reportUrl: after.reportUrl,
+  })
+  const report: CiReport = {
+    afterScanId: after.id,
+    beforeScanId: before.id,
+    blocking: diffCResult.data.blocking.length,
+    body,
+  }
+  if (!platform) {
+    return { ok: true, data: report }
+  }
+
+  const commentCResult = await postCiReportComment(body, platform)
+  if (!commentCResult.ok) {
+    return commentCResult
+  }
+  return { ok: true, data: { ...report, comment: commentCResult.data } }
+}
+
+export async function handleCiReport({
+  afterScanId,
+  beforeScanId,
+  outputKind,
+  platform,
+}: {
+  afterScanId: string
+  beforeScanId: string
+  outputKind: OutputKind
+  platform?: CiReportPlatform | undefined
+}): Promise<void> {
+  await outputCiReport(
+    await getCiReport({ afterScanId, beforeScanId, platform }),
+    outputKind,
+  )
+}
@@ -0,0 +1,209 @@
+/**
+ * Diff-aware pull request scan behind `socket ci --smart`.
+ *
+ * Only the manifest dirs the pull request touches are scanned: every
+ * supported file in a dir with a changed manifest or lockfile is uploaded, so
+ * a changed package.json brings its lockfile along and the other way around.
+ * The partial scan is then compared with the latest scan of the base branch,
+ * and only the alerts it adds can fail the build. On a monorepo that turns a
+ * scan of every workspace into a scan of the few a pull request changes.
+ */
+
+import path from 'node:path'
+import { env } from 'node:process'
+
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { envAsString } from '@socketsecurity/lib-stable/env/string'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { getDefaultOrgSlug } from './fetch-default-org-slug.mts'
+import { detectCiPullRequestNumber } from './handle-ci.mts'
+import { fetchLatestScan } from './handle-ci-report.mts'
+import { outputCiSmart } from './output-ci-smart.mts'
+import { SCAN_TYPE_SOCKET } from '../../constants.mts'
+import { findSocketYmlSync } from '../../util/config.mts'
+import { getPackageFilesForScan } from '../../util/fs/path-resolve.mts'
+import {
+  detectDefaultBranch,
+  getBaseBranch,
+  getRepoName,
+  gitBranch,
+  gitChangedFilesSince,
+} from '../../util/git/operations.mjs'
+import { fetchCreateOrgFullScan } from '../scan/fetch-create-org-full-scan.mts'
+import { fetchScanAlertDiff } from '../scan/fetch-scan-alert-diff.mts'
+import { fetchSupportedScanFileNames } from '../scan/fetch-supported-scan-file-names.mts'
+import { isFailOnViolation } from '../scan/report-policy.mts'
+
+import type { CResult } from '../../types.mts'
+import type { ScanDiffAlert } from '../scan/diff-scan-alerts.mts'
+import type { FAIL_ON, REPORT_LEVEL } from '../scan/types.mts'
+
+const logger = getDefaultLogger()
+
+const COMMAND_PATH = 'socket ci'
+
+export type CiSmartScan = {
+  added: number
+  baseBranch: string
+  // Unset when no manifest changed and nothing was scanned.
+  baseScanId?: string | undefined
+  blocking: ScanDiffAlert[]
+  // Uploaded files, relative to the cwd.
+  manifests: string[]
+  reportUrl?: string | undefined
+  scanId?: string | undefined
+}
+
+/**
+ * The package files to upload: all of those in a dir with a changed package
+ * file. Package files are absolute, changed files relative to the cwd.
+ */
+export function selectChangedManifests(
+  packagePaths: string[],
+  changedFiles: string[],
+  cwd: string,
+): string[] {
+  const changed = new Set(changedFiles.map(p => path.resolve(cwd, p)))
+  const changedDirs = new Set(
+    packagePaths.filter(p => changed.has(p)).map(p => path.dirname(p)),
+  )
+  return packagePaths.filter(p => changedDirs.has(path.dirname(p)))
+}
+
+/**
+ * The branch the pull request (or merge request) goes into, the default
+ * branch of the remote outside one.
+ */
+export async function resolveCiBaseBranch(cwd: string): Promise<string> {
+  return (
+    envAsString(env['CI_MERGE_REQUEST_TARGET_BRANCH_NAME']) ||
+    (await getBaseBranch(cwd))
+  )
+}
+
+export async function getCiSmartScan(
+  failOn?: FAIL_ON | undefined,
+): Promise<CResult<CiSmartScan>> {
+  const orgSlugCResult = await getDefaultOrgSlug()
+  if (!orgSlugCResult.ok) {
+    return orgSlugCResult
+  }
+  const orgSlug = orgSlugCResult.data
+  const cwd = process.cwd()
+  const baseBranch = await resolveCiBaseBranch(cwd)
+  const branchName = (await gitBranch(cwd)) || (await detectDefaultBranch(cwd))
+  const repoName = await getRepoName(cwd)
+  debug(
+    `Smart CI scan for ${orgSlug}/${repoName}: ${baseBranch}..${branchName}`,
+  )
+
+  // CI checkouts track the base branch on the remote, not as a local branch.
+  const changedCResult = await gitChangedFilesSince(
+    `origin/${baseBranch}`,
+    cwd,
+  )
+  if (!changedCResult.ok) {
+    return changedCResult
+  }
+
+  const supportedFilesCResult = await fetchSupportedScanFileNames({ orgSlug })
+  if (!supportedFilesCResult.ok) {
+    return supportedFilesCResult
+  }
+  const socketYmlResult = findSocketYmlSync(cwd)
+  const packagePaths = await getPackageFilesForScan(
+    ['.'],
+    supportedFilesCResult.data,
+    {
+      config: socketYmlResult.ok ? socketYmlResult.data?.parsed : undefined,
+      cwd,
+      localLockfiles: true,
+    },
+  )
+  const selected = selectChangedManifests(
+    packagePaths,
+    changedCResult.data,
+    cwd,
+  )
+  const manifests = selected.map(p => path.relative(cwd, p))
+  debugDir({ changedFiles: changedCResult.data, manifests })
+
+  const empty: CiSmartScan = { added: 0, baseBranch, blocking: [], manifests }
+  if (!selected.length) {
+    logger.info(`No manifest changed since ${baseBranch}, nothing to scan`)
+    return { ok: true, data: empty }
+  }
+  logger.info(
+    `Scanning ${selected.length} changed ${pluralize('file', { count: selected.length })}`,
+  )
+
+  const baseCResult = await fetchLatestScan(
+    orgSlug,
+    repoName,
+    baseBranch,
+    COMMAND_PATH,
+  )
+  if (!baseCResult.ok) {
+    return baseCResult
+  }
+
+  const scanCResult = await fetchCreateOrgFullScan(
+    selected,
+    orgSlug,
+    {
+      branchName,
+      commitHash: '',
+      commitMessage: '',
+      committers: '',
+      pullRequest: detectCiPullRequestNumber(),
+      repoName,
+      scanType: SCAN_TYPE_SOCKET,
+    },
+    // A partial scan must not become the head scan of the branch.
+    { commandPath: COMMAND_PATH, cwd, pendingHead: false, tmp: true },
+  )
+  if (!scanCResult.ok) {
+    return scanCResult
+  }
+  const scanId = scanCResult.data.id ?? ''
+  const reportUrl = scanCResult.data.html_report_url ?? undefined
+
+  const diffCResult = await fetchScanAlertDiff({
+    id1: baseCResult.data.id,
+    id2: scanId,
+    orgSlug,
+  })
+  if (!diffCResult.ok) {
+    return diffCResult
+  }
+  // Alerts of the manifests left out of the partial scan show up as removed,
+  // so only the added ones mean anything here.
+  const { added } = diffCResult.data
+  return {
+    ok: true,
+    data: {
+      ...empty,
+      added: added.length,
+      baseScanId: baseCResult.data.id,
+      blocking: added.filter(alert =>
+        isFailOnViolation(failOn, alert.action as REPORT_LEVEL, alert.severity),
+      ),
+      reportUrl,
+      scanId,
+    },
+  }
+}
+
+export async function handleCiSmart(
+  failOn?: FAIL_ON | undefined,
+): Promise<void> {
+  debug('Starting smart CI scan')
+  debugDir({ failOn })
+
+  const result = await getCiSmartScan(failOn)
+  debugDir({ result })
+
+  await outputCiSmart(result)
+}
@@ -0,0 +1,85 @@
+import { fetchCiOidcIdentity } from './ci-oidc.mts'
+import { fetchCiToken } from './fetch-ci-token.mts'
+import { getDefaultOrgSlug } from './fetch-default-org-slug.mts'
+import { outputCiToken } from './output-ci-token.mts'
+import { SOCKET_CLI_ORG_SLUG } from '../../env/socket-cli-org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+
+import type { CiOidcIdentity, CiOidcProvider } from './ci-oidc.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type CiTokenSource = 'api-token' | CiOidcProvider
+
+export type CiToken = {
+  expiresAt: string
+  orgSlug: string
+  scopes: string[]
+  source: CiTokenSource
+  token: string
+}
+
+async function mintCiToken({
+  oidc,
+  orgSlug,
+  ttlMinutes,
+}: {
+  oidc: boolean
+  orgSlug: string
+  ttlMinutes: number
+}): Promise<CResult<CiToken>> {
+  // Without an API token the OIDC identity of the job is the credential.
+  let identity: CiOidcIdentity | undefined
+  if (oidc || !hasDefaultApiToken()) {
+    const identityCResult = await fetchCiOidcIdentity()
+    if (!identityCResult.ok) {
+      return identityCResult
+    }
+    identity = identityCResult.data
+    orgSlug ||= SOCKET_CLI_ORG_SLUG ?? ''
+    if (!orgSlug) {
+      return {
+        ok: false,
+        message: 'Missing org',
+        cause:
+          'Pass --org or set SOCKET_CLI_ORG_SLUG to name the org the OIDC identity logs in to',
+      }
+    }
+  } else if (!orgSlug) {
+    const orgSlugCResult = await getDefaultOrgSlug()
+    if (!orgSlugCResult.ok) {
+      return orgSlugCResult
+    }
+    orgSlug = orgSlugCResult.data
+  }
+
+  const tokenCResult = await fetchCiToken({ identity, orgSlug, ttlMinutes })
+  if (!tokenCResult.ok) {
+    return tokenCResult
+  }
+  const { expires_at, scopes, token } = tokenCResult.data
+  return {
+    ok: true,
+    data: {
+      expiresAt: expires_at,
+      orgSlug,
+      scopes,
+      source: identity?.provider ?? 'api-token',
+      token,
+    },
+  }
+}
+
+export async function handleCiToken({
+  oidc,
+  orgSlug,
+  outputKind,
+  ttlMinutes,
+}: {
+  oidc: boolean
+  orgSlug: string
+  outputKind: OutputKind
+  ttlMinutes: number
+}): Promise<void> {
+  const result = await mintCiToken({ oidc, orgSlug, ttlMinutes })
+  await outputCiToken(result, outputKind)
+}
@@ -14,6 +14,8 @@ import {
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 import { handleCreateNewScan } from '../scan/handle-create-new-scan.mts'
 
+import type { FAIL_ON } from '../scan/types.mts'
+
 const logger = getDefaultLogger()
 
 /**
@@ -27,9 +29,12 @@ export function detectCiPullRequestNumber(): number {
   return match ? Number(match[1]) : 0
 }
 
-export async function handleCi(autoManifest: boolean): Promise<void> {
+export async function handleCi(
+  autoManifest: boolean,
+  failOn?: FAIL_ON | undefined,
+): Promise<void> {
   debug('Starting CI scan')
-  debugDir({ autoManifest })
+  debugDir({ autoManifest, failOn })
 
   const orgSlugCResult = await getDefaultOrgSlug()
   if (!orgSlugCResult.ok) {
@@ -58,6 +63,7 @@ export async function handleCi(autoManifest: boolean): Promise<void> {
     committers: '',
     cwd,
     defaultBranch: false,
+    failOn,
     interactive: false,
     orgSlug,
     outputKind: 'json',
@@ -0,0 +1,48 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CiEnrichBotPr } from './handle-ci-enrich-bot-pr.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputCiEnrichBotPr(
+  result: CResult<CiEnrichBotPr>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { body, comment, comparison } = result.data
+  if (!body || !comparison) {
+    // Not a bot pull request; the job passes so the step can run on every PR.
+    logger.info('Not a Renovate or Dependabot pull request, nothing to enrich')
+    return
+  }
+  if (!comment) {
+    // Without --github or --gitlab the comment body is the report.
+    logger.log(body)
+    return
+  }
+  const target =
+    comment.platform === 'gitlab'
+      ? `${comment.repo}!${comment.prNumber}`
+      : `${comment.repo}#${comment.prNumber}`
+  const { bump } = comparison
+  logger.success(
+    `${comment.status === 'updated' ? 'Updated' : 'Posted'} the Socket comparison of ${bump.name} ${bump.from} → ${bump.to} on ${target}`,
+  )
+}
@@ -0,0 +1,52 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CiReport } from './handle-ci-report.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputCiReport(
+  result: CResult<CiReport>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (result.data.blocking) {
+    // Fail the build like `socket ci` does when the scan is unhealthy.
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { blocking, body, comment } = result.data
+  if (!comment) {
+    // Without --github or --gitlab the comment body is the report.
+    logger.log(body)
+    return
+  }
+  const target =
+    comment.platform === 'gitlab'
+      ? `${comment.repo}!${comment.prNumber}`
+      : `${comment.repo}#${comment.prNumber}`
+  logger.success(
+    `${comment.status === 'updated' ? 'Updated' : 'Posted'} the Socket report on ${target}`,
+  )
+  if (blocking) {
+    logger.fail(
+      `${blocking} new ${pluralize('alert', { count: blocking })} ${blocking === 1 ? 'violates' : 'violate'} the security policy`,
+    )
+  }
+}
@@ -0,0 +1,22 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CiSmartScan } from './handle-ci-smart.mts'
+import type { CResult } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputCiSmart(
+  result: CResult<CiSmartScan>,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (result.data.blocking.length) {
+    // Fail the build like `socket ci` does when the scan is unhealthy.
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+  // Always assume json mode, like `socket ci`.
+  logger.log(serializeResultJson(result))
+}
@@ -0,0 +1,52 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CiToken } from './handle-ci-token.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+const SOURCE_TEXT: Record<CiToken['source'], string> = {
+  'api-token': 'the org API token',
+  github: 'the GitHub Actions OIDC identity',
+  gitlab: 'the GitLab CI OIDC identity',
+}
+
+export async function outputCiToken(
+  result: CResult<CiToken>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { expiresAt, orgSlug, scopes, source, token } = result.data
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('CI token'))
+    logger.log('')
+    logger.log(`- Org: ${orgSlug}`)
+    logger.log(`- Scopes: ${scopes.join(', ')}`)
+    logger.log(`- Expires: ${expiresAt}`)
+    logger.log(`- Minted with: ${SOURCE_TEXT[source]}`)
+    logger.log('')
+    logger.log('```')
+    logger.log(token)
+    logger.log('```')
+    return
+  }
+  // Only the token goes to stdout, so it can be captured into a variable.
+  logger.log(token)
+}
@@ -0,0 +1,146 @@
+import { handleCompareRepos } from './handle-compare-repos.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'repos'
+
+const description =
+  'Compare the security posture of repositories by their latest scans'
+
+const hidden = false
+
+export const cmdCompareRepos = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <REPO> [REPO...]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Each REPO is a repository slug or a glob of them, e.g. 'web-*' or '*'
+    for every repository of the org. Archived repositories are only
+    compared when named exactly.
+
+    Fetches the latest scan of the default branch of each repository and
+    shows, side by side, the average package scores, the alert counts by
+    severity and whether the scan complies with the security policy of the
+    org: a scan with alerts the policy errors on fails. Repositories without
+    a scan of their default branch are listed apart. Every scan is fetched
+    in full, so comparing many repositories takes a while; use --json to
+    feed a quarterly review.
+
+    Examples
+      $ ${command} api web
+      $ ${command} 'web-*' --markdown > posture.md
+      $ ${command} '*' --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    parentName,
+    importMeta,
+  })
+
+  const { dryRun, interactive, json, markdown, org: orgFlag } = cli.flags
+
+  const patterns = cli.input
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(orgFlag, interactive, dryRun)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: patterns.length > 0,
+      message: 'At least one repository slug or glob as argument',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('repository comparison', {
+      organization: orgSlug,
+      repositories: patterns.join(', '),
+    })
+    return
+  }
+
+  await handleCompareRepos({
+    orgSlug,
+    outputKind,
+    patterns,
+  })
+}
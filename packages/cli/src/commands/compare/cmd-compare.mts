@@ -0,0 +1,24 @@
+import { cmdCompareRepos } from './cmd-compare-repos.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Compare the security posture across an organization'
+
+export const cmdCompare: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} compare`,
+        importMeta,
+        subcommands: {
+          repos: cmdCompareRepos,
+        },
+      },
+      { description },
+    )
+  },
+}
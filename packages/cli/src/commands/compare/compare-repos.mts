@@ -0,0 +1,127 @@
+/**
+ * Posture data of `socket compare repos`: the latest scan of the default
+ * branch of each selected repository, summarized into average package
+ * scores, alert counts by severity and the alerts that break the security
+ * policy of the org, so the repositories can be ranked side by side.
+ */
+
+import micromatch from 'micromatch'
+
+import { naturalCompare } from '@socketsecurity/lib-stable/sorts/natural'
+
+import { summarizeScanArtifacts } from '../repository/repo-trend.mts'
+
+import type {
+  RepoTrendScan,
+  RepoTrendScoreCategory,
+  RepoTrendSeverity,
+} from '../repository/repo-trend.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+export type SecurityPolicyData =
+  SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+export interface ComparableRepo {
+  archived?: boolean | null | undefined
+  default_branch?: string | null | undefined
+  name: string
+}
+
+export interface RepoPolicyCompliance {
+  // No alert of the scan has the error action of the security policy.
+  compliant: boolean
+  errors: number
+  warnings: number
+}
+
+export interface RepoPosture {
+  alerts: Record<RepoTrendSeverity, number>
+  branch: string
+  createdAt: string
+  packages: number
+  policy: RepoPolicyCompliance
+  repo: string
+  scanId: string
+  // Average over the packages of the scan, 0 to 100. Undefined when no
+  // package of the scan has a score.
+  score: Record<RepoTrendScoreCategory, number | undefined>
+}
+
+export interface RepoComparisonData {
+  repos: RepoPosture[]
+  // Selected repositories without a scan of their default branch.
+  unscanned: Array<{ branch: string; repo: string }>
+}
+
+/**
+ * The repositories matching one of the slugs or globs, by name. Archived
+ * repositories are only included when named exactly. Fails on a pattern
+ * that matches no repository, which is usually a typo.
+ */
+export function selectRepos<T extends ComparableRepo>(
+  repos: T[],
+  patterns: string[],
+): { selected: T[]; unmatched: string[] } {
+  const selected = new Set<T>()
+  const unmatched: string[] = []
+  for (const pattern of patterns) {
+    let matched = false
+    for (const repo of repos) {
+      if (
+        repo.name === pattern ||
+        (!repo.archived && micromatch.isMatch(repo.name, pattern))
+      ) {
+        matched = true
+        selected.add(repo)
+      }
+    }
+    if (!matched) {
+      unmatched.push(pattern)
+    }
+  }
+  return {
+    selected: [...selected].toSorted((a, b) => naturalCompare(a.name, b.name)),
+    unmatched,
+  }
+}
+
+/**
+ * Count the alerts of a scan the security policy errors or warns on. Alerts
+ * of rules that monitor, ignore or defer do not count.
+ */
+export function getPolicyCompliance(
+  artifacts: SocketArtifact[],
+  securityPolicy: SecurityPolicyData,
+): RepoPolicyCompliance {
+  const rules = securityPolicy.securityPolicyRules
+  let errors = 0
+  let warnings = 0
+  for (let i = 0, { length } = artifacts; i < length; i += 1) {
+    const artifactAlerts = artifacts[i]!.alerts ?? []
+    for (let j = 0, { length: count } = artifactAlerts; j < count; j += 1) {
+      const action = rules?.[artifactAlerts[j]!.type]?.action
+      if (action === 'error') {
+        errors += 1
+      } else if (action === 'warn') {
+        warnings += 1
+      }
+    }
+  }
+  return { compliant: !errors, errors, warnings }
+}
+
+export function summarizeRepoPosture(
+  repo: string,
+  branch: string,
+  scan: RepoTrendScan,
+  artifacts: SocketArtifact[],
+  securityPolicy: SecurityPolicyData,
+): RepoPosture {
+  return {
+    ...summarizeScanArtifacts(scan, artifacts),
+    branch,
+    policy: getPolicyCompliance(artifacts, securityPolicy),
+    repo,
+  }
+}
@@ -0,0 +1,135 @@
+import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
+
+import { selectRepos, summarizeRepoPosture } from './compare-repos.mts'
+import { outputCompareRepos } from './output-compare-repos.mts'
+import { fetchSecurityPolicy } from '../organization/fetch-security-policy.mts'
+import { fetchListAllRepos } from '../repository/fetch-list-all-repos.mts'
+import { fetchOrgFullScanList } from '../scan/fetch-list-scans.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+import { runApiBatches } from '../../util/socket/api-batch.mts'
+
+import type { RepoComparisonData, RepoPosture } from './compare-repos.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+// Full scans are large, so fewer of them are fetched at once than the
+// purl batches of other commands.
+const SCAN_FETCH_CONCURRENCY = 3
+
+export type CompareReposConfig = {
+  orgSlug: string
+  // Repository slugs or globs, e.g. `api` or `web-*`.
+  patterns: string[]
+}
+
+type RepoResult = {
+  branch: string
+  posture: RepoPosture | undefined
+  repo: string
+}
+
+export async function getRepoComparison({
+  orgSlug,
+  patterns,
+}: CompareReposConfig): Promise<CResult<RepoComparisonData>> {
+  const commandPath = 'socket compare repos'
+
+  const reposCResult = await fetchListAllRepos(orgSlug, { commandPath })
+  if (!reposCResult.ok) {
+    return reposCResult
+  }
+
+  const { selected, unmatched } = selectRepos(
+    reposCResult.data.results,
+    patterns,
+  )
+  if (unmatched.length) {
+    return {
+      ok: false,
+      message: 'Repository not found',
+      cause: `No repository of ${orgSlug} matches ${joinAnd(unmatched)}`,
+    }
+  }
+
+  const policyCResult = await fetchSecurityPolicy(orgSlug, { commandPath })
+  if (!policyCResult.ok) {
+    return policyCResult
+  }
+
+  const resultsCResult = await runApiBatches<
+    (typeof selected)[number],
+    RepoResult
+  >(
+    selected,
+    async batch => {
+      const repo = batch[0]!
+      const branch = repo.default_branch || 'main'
+      const listCResult = await fetchOrgFullScanList(
+        {
+          branch,
+          direction: 'desc',
+          from_time: '',
+          orgSlug,
+          page: 1,
+          perPage: 1,
+          repo: repo.name,
+          sort: 'created_at',
+        },
+        { commandPath },
+      )
+      if (!listCResult.ok) {
+        return listCResult
+      }
+      const scan = listCResult.data.results[0]
+      if (!scan?.id) {
+        return {
+          ok: true,
+          data: [{ branch, posture: undefined, repo: repo.name }],
+        }
+      }
+      const artifactsCResult = await fetchScan(orgSlug, scan.id)
+      if (!artifactsCResult.ok) {
+        return artifactsCResult
+      }
+      return {
+        ok: true,
+        data: [
+          {
+            branch,
+            posture: summarizeRepoPosture(
+              repo.name,
+              branch,
+              scan,
+              artifactsCResult.data,
+              policyCResult.data,
+            ),
+            repo: repo.name,
+          },
+        ],
+      }
+    },
+    { batchSize: 1, concurrency: SCAN_FETCH_CONCURRENCY },
+  )
+  if (!resultsCResult.ok) {
+    return resultsCResult
+  }
+
+  const repos: RepoPosture[] = []
+  const unscanned: RepoComparisonData['unscanned'] = []
+  for (const { branch, posture, repo } of resultsCResult.data) {
+    if (posture) {
+      repos.push(posture)
+    } else {
+      unscanned.push({ branch, repo })
+    }
+  }
+  return { ok: true, data: { repos, unscanned } }
+}
+
+export async function handleCompareRepos({
+  outputKind,
+  ...config
+}: CompareReposConfig & { outputKind: OutputKind }): Promise<void> {
+  const result = await getRepoComparison(config)
+
+  await outputCompareRepos(result, outputKind)
+}
@@ -0,0 +1,150 @@
+import chalkTable from 'chalk-table'
+import colors from 'yoctocolors-cjs'
+
+import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import {
+  REPO_TREND_ALERT_SEVERITIES,
+  REPO_TREND_SCORE_CATEGORIES,
+} from '../repository/repo-trend.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type {
+  RepoComparisonData,
+  RepoPolicyCompliance,
+  RepoPosture,
+} from './compare-repos.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+const TABLE_COLUMNS = [
+  'repo',
+  'date',
+  'packages',
+  ...REPO_TREND_SCORE_CATEGORIES,
+  ...REPO_TREND_ALERT_SEVERITIES,
+  'policy',
+]
+
+const TABLE_TITLES = [
+  'Repository',
+  'Scanned',
+  'Packages',
+  'Supply Chain',
+  'Maintenance',
+  'Quality',
+  'Vulnerability',
+  'License',
+  'Critical',
+  'High',
+  'Medium',
+  'Low',
+  'Policy',
+]
+
+export function formatPolicyCompliance(policy: RepoPolicyCompliance): string {
+  const { compliant, errors, warnings } = policy
+  const counts = [
+    ...(errors ? [`${errors} ${pluralize('error', { count: errors })}`] : []),
+    ...(warnings
+      ? [`${warnings} ${pluralize('warning', { count: warnings })}`]
+      : []),
+  ]
+  return [compliant ? 'pass' : 'fail', ...counts].join(', ')
+}
+
+export function getRepoPostureRow(
+  posture: RepoPosture,
+): Record<string, string> {
+  const row: Record<string, string> = {
+    date: posture.createdAt.slice(0, 10),
+    packages: String(posture.packages),
+    policy: formatPolicyCompliance(posture.policy),
+    repo: posture.repo,
+  }
+  for (const category of REPO_TREND_SCORE_CATEGORIES) {
+    row[category] = String(posture.score[category] ?? '')
+  }
+  for (const severity of REPO_TREND_ALERT_SEVERITIES) {
+    row[severity] = String(posture.alerts[severity])
+  }
+  return row
+}
+
+function getSummary(data: RepoComparisonData): string {
+  const { repos } = data
+  const compliant = repos.filter(r => r.policy.compliant).length
+  return `Latest default branch scans of ${repos.length} ${repos.length === 1 ? 'repository' : 'repositories'}: ${compliant} of them ${compliant === 1 ? 'complies' : 'comply'} with the security policy`
+}
+
+function getUnscannedNote(data: RepoComparisonData): string {
+  const names = data.unscanned.map(u => `${u.repo} (${u.branch})`)
+  return `No scan of the default branch of ${joinAnd(names)}`
+}
+
+export async function outputCompareRepos(
+  result: CResult<RepoComparisonData>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { data } = result
+  const rows = data.repos.map(getRepoPostureRow)
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Repository Comparison'))
+    logger.log('')
+    logger.log(`${getSummary(data)}.`)
+    if (data.unscanned.length) {
+      logger.log('')
+      logger.log(`${getUnscannedNote(data)}.`)
+    }
+    if (rows.length) {
+      logger.log('')
+      logger.log(mdTable(rows, TABLE_COLUMNS, TABLE_TITLES))
+    }
+    return
+  }
+
+  logger.log(getSummary(data))
+  if (rows.length) {
+    logger.log(
+      chalkTable(
+        {
+          columns: TABLE_COLUMNS.map((field, i) => ({
+            field,
+            name: colors.magenta(TABLE_TITLES[i]!),
+          })),
+        },
+        data.repos.map((posture, i) => {
+          const policy = rows[i]!['policy']!
+          return {
+            ...rows[i],
+            policy: posture.policy.compliant
+              ? colors.green(policy)
+              : colors.red(policy),
+          }
+        }),
+      ),
+    )
+  }
+  if (data.unscanned.length) {
+    logger.warn(getUnscannedNote(data))
+  }
+}
@@ -0,0 +1,101 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import {
+  COMPLETE_SUBCOMMAND,
+  COMPLETION_SHELLS,
+} from './completion-scripts.mts'
+import { handleCompletion } from './handle-completion.mts'
+import { handleCompletionComplete } from './handle-completion-complete.mts'
+import { OUTPUT_TEXT } from '../../constants/cli.mts'
+import { commonFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CompletionShell } from './completion-scripts.mts'
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'completion'
+
+const description =
+  'Print a tab completion script for bash, zsh, fish or PowerShell'
+
+const hidden = false
+
+export const cmdCompletion: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  // The printed scripts call back in here on every tab press.
+  if (argv[0] === COMPLETE_SUBCOMMAND) {
+    await handleCompletionComplete(argv.slice(1), importMeta)
+    return
+  }
+
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <${COMPLETION_SHELLS.join('|')}> [NAME=socket]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Prints a completion script to stdout. It completes subcommands and
+    flags as the installed CLI knows them, and the org slugs, repo names
+    and scan IDs that earlier commands like \`socket scan list\` and
+    \`socket repository list\` have shown.
+
+    Load it from your shell startup file:
+
+      bash        source <(socket completion bash)
+      zsh         source <(socket completion zsh)
+      fish        socket completion fish | source
+      PowerShell  socket completion powershell | Out-String | Invoke-Expression
+
+    The optional name argument completes a command name other than
+    "socket", like an alias for it.
+
+    Examples
+      $ ${command} zsh
+      $ ${command} bash sd
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { 0: shell = '', 1: name = 'socket' } = cli.input
+
+  const wasValidInput = checkCommandInput(OUTPUT_TEXT, {
+    test: COMPLETION_SHELLS.includes(shell as CompletionShell),
+    message: `The shell must be ${joinOr([...COMPLETION_SHELLS])}`,
+    fail: shell ? `got ${shell}` : 'missing',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
+  await handleCompletion({ name, shell: shell as CompletionShell })
+}
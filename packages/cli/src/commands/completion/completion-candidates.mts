@@ -0,0 +1,184 @@
+import { naturalCompare } from '@socketsecurity/lib-stable/sorts/natural'
+
+import { COMPLETION_SHELLS } from './completion-scripts.mts'
+import { HOOK_NAMES } from '../hooks/hook-scripts.mts'
+import { inspectCommand } from '../../util/cli/command-tree.mts'
+import { readCompletionHistory } from '../../util/cli/completion-history.mts'
+import { camelToKebab } from '../../util/data/strings.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type { CommandTreeNode } from '../../util/cli/command-tree.mts'
+import type { CompletionValueKind } from '../../util/cli/completion-history.mts'
+
+export interface CompletionCandidate {
+  description?: string | undefined
+  value: string
+}
+
+type ValueSource = CompletionValueKind | readonly string[]
+
+// Flags whose values are offered from the completion history.
+const FLAG_VALUES: Readonly<Record<string, ValueSource>> = {
+  org: 'org',
+  repo: 'repo',
+  scan: 'scan',
+}
+
+// Positional arguments with known values, by command path.
+const POSITIONAL_VALUES: Readonly<Record<string, readonly ValueSource[]>> = {
+  'compare repos': ['repo'],
+  completion: [COMPLETION_SHELLS],
+  'hooks run': [HOOK_NAMES],
+  'license check': ['scan'],
+  'license list': ['scan'],
+  'repository archive': ['repo'],
+  'repository del': ['repo'],
+  'repository label': ['repo'],
+  'repository set-default-branch': ['repo'],
+  'repository trend': ['repo'],
+  'repository update': ['repo'],
+  'repository view': ['repo'],
+  'scan del': ['scan'],
+  'scan diff': ['scan', 'scan'],
+  'scan metadata': ['scan'],
+  'scan report': ['scan'],
+  'scan view': ['scan'],
+}
+
+export interface GetCompletionCandidatesConfig {
+  // The word under the cursor, possibly empty.
+  current: string
+  importMeta: ImportMeta
+  name: string
+  root: CommandTreeNode
+  // The words between the command name and the cursor.
+  words: readonly string[]
+}
+
+function findFlagName(flags: MeowFlags, word: string): string | undefined {
+  const name = word.replace(/^--?/, '')
+  for (const key of Object.keys(flags)) {
+    const kebab = camelToKebab(key)
+    if (
+      kebab === name ||
+      (word.length === 2 && flags[key]!.shortFlag === name) ||
+      (flags[key]!.type === 'boolean' && `no-${kebab}` === name)
+    ) {
+      return key
+    }
+  }
+  return undefined
+}
+
+function firstLine(text: string | undefined): string | undefined {
+  return text?.split('\n', 1)[0]!.replace(/\t/g, ' ').trim() || undefined
+}
+
+async function getValues(
+  source: ValueSource | undefined,
+): Promise<CompletionCandidate[]> {
+  if (source === undefined) {
+    return []
+  }
+  const values =
+    typeof source === 'string'
+      ? (await readCompletionHistory())[source]
+      : source
+  return values.map(value => ({ value }))
+}
+
+/**
+ * Get what can be typed for the word under the cursor: subcommands, flags of
+ * the command the words lead to, or the values of a flag or argument.
+ */
+export async function getCompletionCandidates(
+  config: GetCompletionCandidatesConfig,
+): Promise<CompletionCandidate[]> {
+  const { current, importMeta, name, root, words } = config
+  const pending = [...words]
+  const commandPath: string[] = []
+  let node = root
+  let positionals = 0
+  let valueFlag: string | undefined
+
+  while (pending.length) {
+    const word = pending.shift()!
+    if (valueFlag !== undefined) {
+      valueFlag = undefined
+      continue
+    }
+    if (word.startsWith('-')) {
+      const flagName = word.includes('=')
+        ? undefined
+        : findFlagName(node.flags, word)
+      if (flagName && node.flags[flagName]!.type !== 'boolean') {
+        valueFlag = flagName
+      }
+      continue
+    }
+    if (!node.subcommands) {
+      positionals += 1
+      continue
+    }
+    const alias = node.aliases[word]
+    if (alias) {
+      pending.unshift(...alias.argv)
+      continue
+    }
+    const command = node.subcommands[word]
+    if (!command) {
+      return []
+    }
+    const child = await inspectCommand(
+      command,
+      [name, ...commandPath].join(' '),
+      importMeta,
+    )
+    if (!child) {
+      return []
+    }
+    commandPath.push(word)
+    node = child
+  }
+
+  let candidates: CompletionCandidate[]
+  if (valueFlag !== undefined) {
+    candidates = await getValues(FLAG_VALUES[valueFlag])
+  } else if (current.startsWith('-') && current.includes('=')) {
+    const flagWord = current.slice(0, current.indexOf('=') + 1)
+    const flagName = findFlagName(node.flags, flagWord.slice(0, -1))
+    candidates = (
+      await getValues(flagName ? FLAG_VALUES[flagName] : undefined)
+    ).map(c => ({ value: `${flagWord}${c.value}` }))
+  } else if (current.startsWith('-')) {
+    candidates = Object.keys(node.flags)
+      .filter(key => !node.flags[key]!.hidden)
+      .map(key => ({
+        description: firstLine(node.flags[key]!.description),
+        value: `--${camelToKebab(key)}`,
+      }))
+  } else if (node.subcommands) {
+    const { aliases, subcommands } = node
+    candidates = [
+      ...Object.keys(subcommands)
+        .filter(key => !subcommands[key]!.hidden)
+        .map(key => ({
+          description: firstLine(subcommands[key]!.description),
+          value: key,
+        })),
+      ...Object.keys(aliases)
+        .filter(key => !aliases[key]!.hidden)
+        .map(key => ({
+          description: firstLine(aliases[key]!.description),
+          value: key,
+        })),
+    ]
+  } else {
+    candidates = await getValues(
+      POSITIONAL_VALUES[commandPath.join(' ')]?.[positionals],
+    )
+  }
+  return candidates
+    .filter(c => c.value.startsWith(current))
+    .toSorted((a, b) => naturalCompare(a.value, b.value))
+}
@@ -0,0 +1,129 @@
+/**
+ * Shell scripts printed by `socket completion <shell>`.
+ *
+ * The scripts hold no command data. On each tab press they call
+ * `socket completion __complete --current=<word> -- <words before it>`, which
+ * prints one candidate per line as `value<TAB>description`, so completion
+ * always matches the installed CLI. When there are no candidates the shells
+ * fall back to completing file names.
+ */
+
+export const COMPLETION_SHELLS = ['bash', 'zsh', 'fish', 'powershell'] as const
+
+export type CompletionShell = (typeof COMPLETION_SHELLS)[number]
+
+// Keeps the background update check from running on every tab press.
+const COMPLETE_ENV = 'SOCKET_CLI_SKIP_UPDATE_CHECK=1'
+
+export const COMPLETE_SUBCOMMAND = '__complete'
+
+function getBashScript(name: string): string {
+  const fn = `_${toFunctionName(name)}_complete`
+  return `# ${name} completion for bash. Load it in ~/.bashrc with:
+#   source <(${name} completion bash)
+${fn}() {
+  local cur="\${COMP_WORDS[COMP_CWORD]}"
+  local IFS=$'\\n'
+  local candidates
+  candidates=$(${COMPLETE_ENV} ${name} completion ${COMPLETE_SUBCOMMAND} \\
+    --current="$cur" -- "\${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null \\
+    | cut -f1)
+  COMPREPLY=($(compgen -W "$candidates" -- "$cur"))
+}
+complete -o default -F ${fn} ${name}
+`
+}
+
+function getZshScript(name: string): string {
+  const fn = `_${toFunctionName(name)}`
+  return `#compdef ${name}
+# ${name} completion for zsh. Load it in ~/.zshrc, after compinit, with:
+#   source <(${name} completion zsh)
+${fn}() {
+  local -a candidates
+  local line value
+  for line in "\${(@f)$(${COMPLETE_ENV} ${name} completion ${COMPLETE_SUBCOMMAND} \\
+    --current="\${words[CURRENT]}" -- "\${(@)words[2,CURRENT-1]}" 2>/dev/null)}"; do
+    [[ -z $line ]] && continue
+    value=\${line%%$'\\t'*}
+    if [[ $line == *$'\\t'* ]]; then
+      candidates+=("\${value//:/\\\\:}:\${line#*$'\\t'}")
+    else
+      candidates+=("\${value//:/\\\\:}")
+    fi
+  done
+  if (( \${#candidates} )); then
+    _describe '${name}' candidates
+  else
+    _files
+  fi
+}
+compdef ${fn} ${name}
+`
+}
+
+function getFishScript(name: string): string {
+  const fn = `__${toFunctionName(name)}_complete`
+  return `# ${name} completion for fish. Load it in ~/.config/fish/config.fish with:
+#   ${name} completion fish | source
+function ${fn}
+    set -l current (commandline -ct)
+    set -l words (commandline -opc)
+    set -l candidates (env ${COMPLETE_ENV} ${name} completion ${COMPLETE_SUBCOMMAND} \\
+        "--current=$current" -- $words[2..-1] 2>/dev/null)
+    if test (count $candidates) -gt 0
+        printf '%s\\n' $candidates
+    else
+        __fish_complete_path $current
+    end
+end
+complete -c ${name} -f -a '(${fn})'
+`
+}
+
+function getPowerShellScript(name: string): string {
+  return `# ${name} completion for PowerShell. Load it in $PROFILE with:
+#   ${name} completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName '${name}' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements |
+        Where-Object { $_.Extent.EndOffset -lt $cursorPosition } |
+        Select-Object -Skip 1 |
+        ForEach-Object { $_.ToString() })
+    $previous = $env:SOCKET_CLI_SKIP_UPDATE_CHECK
+    $env:SOCKET_CLI_SKIP_UPDATE_CHECK = '1'
+    try {
+        $lines = & '${name}' completion ${COMPLETE_SUBCOMMAND} "--current=$wordToComplete" '--' @words 2>$null
+    } finally {
+        $env:SOCKET_CLI_SKIP_UPDATE_CHECK = $previous
+    }
+    foreach ($line in @($lines)) {
+        if (-not $line) { continue }
+        $value, $description = $line -split "\`t", 2
+        if (-not $description) { $description = $value }
+        [System.Management.Automation.CompletionResult]::new(
+            $value, $value, 'ParameterValue', $description)
+    }
+}
+`
+}
+
+export function getCompletionScript(
+  shell: CompletionShell,
+  name = 'socket',
+): string {
+  switch (shell) {
+    case 'bash':
+      return getBashScript(name)
+    case 'fish':
+      return getFishScript(name)
+    case 'powershell':
+      return getPowerShellScript(name)
+    case 'zsh':
+      return getZshScript(name)
+  }
+}
+
+function toFunctionName(name: string): string {
+  return name.replace(/[^A-Za-z0-9_]/g, '_')
+}
@@ -0,0 +1,33 @@
+import { getCompletionCandidates } from './completion-candidates.mts'
+import { outputCompletionCandidates } from './output-completion.mts'
+// The router imports every command, this one included; its exports are
+// only read once a tab press is answered.
+import { rootAliases, rootCommands } from '../../commands.mts'
+import { SOCKET_CLI_BIN_NAME } from '../../constants/packages.mts'
+import { commonFlags } from '../../flags.mts'
+
+const CURRENT_PREFIX = '--current='
+
+/**
+ * Answer a tab press from a script of `socket completion`. The argv is
+ * `--current=<word> -- <words before it>`.
+ */
+export async function handleCompletionComplete(
+  argv: readonly string[],
+  importMeta: ImportMeta,
+): Promise<void> {
+  const currentArg = argv.find(a => a.startsWith(CURRENT_PREFIX)) ?? ''
+  const dashesIndex = argv.indexOf('--')
+  const candidates = await getCompletionCandidates({
+    current: currentArg.slice(CURRENT_PREFIX.length),
+    importMeta,
+    name: SOCKET_CLI_BIN_NAME,
+    root: {
+      aliases: rootAliases,
+      flags: commonFlags,
+      subcommands: rootCommands,
+    },
+    words: dashesIndex === -1 ? [] : argv.slice(dashesIndex + 1),
+  })
+  await outputCompletionCandidates(candidates)
+}
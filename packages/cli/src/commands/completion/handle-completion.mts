@@ -0,0 +1,14 @@
+import { getCompletionScript } from './completion-scripts.mts'
+import { outputCompletion } from './output-completion.mts'
+
+import type { CompletionShell } from './completion-scripts.mts'
+
+export async function handleCompletion({
+  name,
+  shell,
+}: {
+  name: string
+  shell: CompletionShell
+}): Promise<void> {
+  await outputCompletion(getCompletionScript(shell, name))
+}
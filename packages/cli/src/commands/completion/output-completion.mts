@@ -0,0 +1,23 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import type { CompletionCandidate } from './completion-candidates.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputCompletion(script: string): Promise<void> {
+  // The script is the payload itself, meant for `source` or `eval`.
+  logger.log(script.trimEnd())
+}
+
+export async function outputCompletionCandidates(
+  candidates: CompletionCandidate[],
+): Promise<void> {
+  if (!candidates.length) {
+    return
+  }
+  logger.log(
+    candidates
+      .map(c => (c.description ? `${c.value}\t${c.description}` : c.value))
+      .join('\n'),
+  )
+}
@@ -0,0 +1,91 @@
+import path from 'node:path'
+
+import { handleConfigEffective } from './handle-config-effective.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { SOCKET_YML } from '../../constants/socket.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+const config = {
+  commandName: 'effective',
+  description: `Show the merged ${SOCKET_YML} that applies to a directory`,
+  flags: defineFlags({
+    ...commonFlags,
+    ...outputFlags,
+    path: {
+      type: 'string',
+      default: '.',
+      description: 'Directory to show the config of, relative to cwd',
+    },
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    A ${SOCKET_YML} in a monorepo package inherits the ones of its parent
+    directories, up to the repository root. Its issueRules, githubApp, cli
+    and wrapper.alerts keys override the inherited ones, and its
+    projectIgnorePaths add to theirs; a \`!pattern\` re-includes a path the
+    root ignores. Set \`inherit: false\` to stop inheriting.
+
+    Examples
+      $ ${command}
+      $ ${command} --path packages/foo --json
+  `,
+  hidden: false,
+}
+
+export const cmdConfigEffective = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags
+
+  const dryRun = cli.flags['dryRun']
+
+  const dir = path.resolve(process.cwd(), String(cli.flags['path'] || '.'))
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(outputKind, {
+    nook: true,
+    test: !json || !markdown,
+    message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+    fail: 'bad',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch(`the effective ${SOCKET_YML}`, { path: dir })
+    return
+  }
+
+  await handleConfigEffective({ dir, outputKind })
+}
@@ -1,15 +1,26 @@
-import { createConfigCommand } from './config-command-factory.mts'
+import {
+  configSourceFlags,
+  createConfigCommand,
+} from './config-command-factory.mts'
 import { handleConfigGet } from './handle-config-get.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
 
 export const cmdConfigGet = createConfigCommand({
   commandName: 'get',
   description: 'Get the value of a local CLI config item',
   hidden: false,
+  flags: {
+    ...commonFlags,
+    ...outputFlags,
+    ...configSourceFlags,
+  },
   helpUsage: 'KEY',
-  helpDescription: `Retrieve the value for given KEY at this time. If you have overridden the
-    config then the value will come from that override.
+  helpDescription: `Retrieve the effective value for given KEY at this time. The first of
+    these that sets the KEY wins: a flag, an env var, the \`cli\` section of
+    the repo socket.yml, the user config (or the --config flag standing in
+    for it) and the system config. Use --source to see which one it was.
 
     KEY is an enum. Valid keys:`,
-  helpExamples: ['defaultOrg'],
+  helpExamples: ['defaultOrg', 'defaultOrg --source'],
   handler: handleConfigGet,
 })
@@ -1,3 +1,4 @@
+import { configSourceFlags } from './config-command-factory.mts'
 import { outputConfigList } from './output-config-list.mts'
 import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
 import { outputDryRunFetch } from '../../util/dry-run/output.mts'
@@ -22,6 +23,7 @@ const config = {
       default: false,
       description: 'Show full tokens in plaintext (unsafe)',
     },
+    ...configSourceFlags,
   }),
   help: (command: string, helpConfig: { flags: MeowFlags }) => `
     Usage
@@ -30,8 +32,13 @@ const config = {
     Options
       ${getFlagListOutput(helpConfig.flags)}
 
+    Shows the effective value of each key. With --source, also shows
+    whether it comes from a flag, an env var, the repo socket.yml, the user
+    config or the system config.
+
     Examples
       $ ${command}
+      $ ${command} --source
   `,
   hidden: false,
 }
@@ -54,7 +61,7 @@ export async function run(
     parentName,
   })
 
-  const { full, json, markdown } = cli.flags
+  const { full, json, markdown, source } = cli.flags
 
   const dryRun = cli.flags['dryRun']
 
@@ -73,6 +80,7 @@ export async function run(
   if (dryRun) {
     outputDryRunFetch('configuration settings', {
       showFullTokens: full ? 'yes' : 'no (masked)',
+      ...(source ? { showSources: 'yes' } : {}),
     })
     return
   }
@@ -80,5 +88,6 @@ export async function run(
   await outputConfigList({
     full: full,
     outputKind,
+    ...(source ? { showSource: true } : {}),
   })
 }
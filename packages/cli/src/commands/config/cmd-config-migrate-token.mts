@@ -0,0 +1,86 @@
+import { handleConfigMigrateToken } from './handle-config-migrate-token.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { outputDryRunWrite } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'migrate-token'
+
+const config = {
+  commandName: CMD_NAME,
+  description: 'Move API tokens from the config file to the OS keychain',
+  flags: defineFlags({
+    ...commonFlags,
+    ...outputFlags,
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Moves the plaintext API token of every profile from the config file to
+    the OS keychain (macOS Keychain, Windows Credential Manager or
+    libsecret). The config file then only records that the token lives in
+    the keychain. \`socket login\` stores new tokens there already.
+
+    Examples
+      $ ${command}
+  `,
+  hidden: false,
+}
+
+export const cmdConfigMigrateToken = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags
+
+  const dryRun = cli.flags['dryRun']
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(outputKind, {
+    nook: true,
+    test: !json || !markdown,
+    message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+    fail: 'bad',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    // Runtime read so tests that mutate process.env['HOME'] pick up changes.
+    const configPath = `${process.env['HOME']}/.config/socket/config.json`
+    outputDryRunWrite(configPath, 'move API tokens to the OS keychain', [
+      'Store each plaintext API token in the OS keychain',
+      'Replace the tokens in the config file with a keychain reference',
+    ])
+    return
+  }
+
+  await handleConfigMigrateToken({ outputKind })
+}
@@ -1,5 +1,9 @@
-import { createConfigCommand } from './config-command-factory.mts'
+import {
+  configSourceFlags,
+  createConfigCommand,
+} from './config-command-factory.mts'
 import { handleConfigSet } from './handle-config-set.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
 
 export const CMD_NAME = 'set'
 
@@ -7,6 +11,11 @@ export const cmdConfigSet = createConfigCommand({
   commandName: CMD_NAME,
   description: 'Update the value of a local CLI config item',
   hidden: false,
+  flags: {
+    ...commonFlags,
+    ...outputFlags,
+    ...configSourceFlags,
+  },
   needsValue: true,
   helpUsage: '<KEY> <VALUE>',
   helpDescription: `This is a crude way of updating the local configuration for this CLI tool.
@@ -18,6 +27,10 @@ export const cmdConfigSet = createConfigCommand({
     Note: use \`socket config unset\` to restore to defaults. Setting a key
     to \`undefined\` will not allow default values to be set on it.
 
+    This updates the user config. A flag, an env var or the repo socket.yml
+    setting the same key still wins; use --source to see where the effective
+    value comes from after the update.
+
     Keys:`,
   helpExamples: ['apiProxy https://example.com'],
   handler: handleConfigSet,
@@ -0,0 +1,100 @@
+import { handleConfigUseProfile } from './handle-config-use-profile.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { DEFAULT_PROFILE_NAME } from '../../constants/config.mts'
+import { outputDryRunWrite } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getProfileNames, isValidProfileName } from '../../util/config.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'use-profile'
+
+const config = {
+  commandName: CMD_NAME,
+  description: 'Switch the active auth profile',
+  flags: defineFlags({
+    ...commonFlags,
+    ...outputFlags,
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <NAME>
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Makes NAME the active profile. Commands then use its API token, proxy,
+    base URL and default org. Create a profile with \`socket login --profile
+    NAME\`. Use "${DEFAULT_PROFILE_NAME}" to go back to the credentials stored
+    outside of any profile. SOCKET_CLI_PROFILE picks a profile for a single
+    shell instead.
+
+    Examples
+      $ ${command} work
+      $ ${command} ${DEFAULT_PROFILE_NAME}
+  `,
+  hidden: false,
+}
+
+export const cmdConfigUseProfile = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags
+
+  const dryRun = cli.flags['dryRun']
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const [profile = ''] = cli.input
+  const profileNames = getProfileNames()
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: isValidProfileName(profile),
+      message: `Profile name should be the first arg${profileNames.length ? ` (one of: ${[DEFAULT_PROFILE_NAME, ...profileNames].join(', ')})` : ''}`,
+      fail: profile ? 'invalid profile name' : 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    // Runtime read so tests that mutate process.env['HOME'] pick up changes.
+    const configPath = `${process.env['HOME']}/.config/socket/config.json`
+    outputDryRunWrite(configPath, `switch to profile "${profile}"`, [
+      `Set "activeProfile" to: ${profile}`,
+    ])
+    return
+  }
+
+  await handleConfigUseProfile({ outputKind, profile })
+}
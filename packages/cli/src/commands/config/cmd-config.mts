@@ -1,8 +1,11 @@
 import { cmdConfigAuto } from './cmd-config-auto.mts'
+import { cmdConfigEffective } from './cmd-config-effective.mts'
 import { cmdConfigGet } from './cmd-config-get.mts'
 import { cmdConfigList } from './cmd-config-list.mts'
+import { cmdConfigMigrateToken } from './cmd-config-migrate-token.mts'
 import { cmdConfigSet } from './cmd-config-set.mts'
 import { cmdConfigUnset } from './cmd-config-unset.mts'
+import { cmdConfigUseProfile } from './cmd-config-use-profile.mts'
 import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
 
 import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
@@ -20,10 +23,13 @@ export const cmdConfig: CliSubcommand = {
         importMeta,
         subcommands: {
           auto: cmdConfigAuto,
+          effective: cmdConfigEffective,
           get: cmdConfigGet,
           list: cmdConfigList,
+          'migrate-token': cmdConfigMigrateToken,
           set: cmdConfigSet,
           unset: cmdConfigUnset,
+          'use-profile': cmdConfigUseProfile,
         },
       },
       { description },
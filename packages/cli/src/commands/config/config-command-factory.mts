@@ -18,6 +18,15 @@ import type {
 } from '../../util/cli/with-subcommands.mjs'
 import type { LocalConfig } from '../../util/config.mts'
 
+export const configSourceFlags: MeowFlags = {
+  source: {
+    type: 'boolean',
+    default: false,
+    description:
+      'Show where the effective value comes from: a flag, an env var, the repo socket.yml, the user config or the system config',
+  },
+}
+
 export type ConfigCommandSpec = {
   commandName: string
   description: string
@@ -43,6 +52,7 @@ export type ConfigCommandSpec = {
     key: keyof LocalConfig
     value?: string | undefined
     outputKind: OutputKind
+    showSource?: boolean | undefined
   }) => Promise<void>
 }
 
@@ -162,6 +172,7 @@ ${spec.helpExamples.map(ex => `      $ ${command} ${ex}`).join('\n')}
         key: key as keyof LocalConfig,
         ...(spec.needsValue && value !== undefined ? { value } : {}),
         outputKind,
+        ...(cli.flags['source'] ? { showSource: true } : {}),
       })
     },
   }
@@ -20,6 +20,15 @@ export async function discoverConfigValue(
     }
   }
 
+  if (key === 'apiAuthScheme') {
+    return {
+      ok: false,
+      message: 'Auto discover failed',
+      cause:
+        'Set it to bearer only when your Socket deployment asks for it, otherwise unset this key',
+    }
+  }
+
   if (key === 'apiBaseUrl') {
     // Return the default value
     return {
@@ -30,6 +39,19 @@ export async function discoverConfigValue(
     }
   }
 
+  if (
+    key === 'apiMaxRetries' ||
+    key === 'apiRetryBackoff' ||
+    key === 'apiTimeout'
+  ) {
+    return {
+      ok: false,
+      message: 'Auto discover failed',
+      cause:
+        'Raise it on flaky networks, e.g. in CI, otherwise unset this key to use the defaults',
+    }
+  }
+
   if (key === 'apiProxy') {
     // I don't think we can auto-discover this with any order of reliability..?
     return {
@@ -49,6 +71,15 @@ export async function discoverConfigValue(
     }
   }
 
+  if (key === 'caCert') {
+    return {
+      ok: false,
+      message: 'Auto discover failed',
+      cause:
+        'Ask your network administrator for the CA certificate of the proxy, or unset this key',
+    }
+  }
+
   if (key === 'defaultOrg') {
     const hasApiToken = hasDefaultApiToken()
     if (!hasApiToken) {
@@ -84,6 +115,15 @@ export async function discoverConfigValue(
     }
   }
 
+  if (key === 'disableSelfUpdate') {
+    return {
+      ok: false,
+      message: 'Auto discover failed',
+      cause:
+        'Set it to true where an administrator manages the Socket CLI, otherwise unset this key',
+    }
+  }
+
   if (key === 'enforcedOrgs') {
     const hasApiToken = hasDefaultApiToken()
     if (!hasApiToken) {
@@ -112,6 +152,15 @@ export async function discoverConfigValue(
     }
   }
 
+  if (key === 'telemetry') {
+    return {
+      ok: false,
+      message: 'Auto discover failed',
+      cause:
+        'Telemetry is opt-in; use `socket telemetry enable` or `socket telemetry disable`',
+    }
+  }
+
   if (key === 'test') {
     return {
       ok: false,
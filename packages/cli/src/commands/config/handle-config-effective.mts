@@ -0,0 +1,44 @@
+import path from 'node:path'
+
+import { outputConfigEffective } from './output-config-effective.mts'
+import { findSocketYmlSync } from '../../util/config.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketYml } from '../../util/socket-yaml.mts'
+
+export type EffectiveSocketYml = {
+  // Undefined when no socket.yml applies.
+  config: SocketYml | undefined
+  dir: string
+  // The merged files, outermost first.
+  sources: string[]
+}
+
+export function getEffectiveSocketYml(
+  dir: string,
+): CResult<EffectiveSocketYml> {
+  const resolved = path.resolve(dir)
+  const ymlCResult = findSocketYmlSync(resolved)
+  if (!ymlCResult.ok) {
+    return ymlCResult
+  }
+  const found = ymlCResult.data
+  return {
+    ok: true,
+    data: {
+      config: found?.parsed,
+      dir: resolved,
+      sources: found?.paths ?? [],
+    },
+  }
+}
+
+export async function handleConfigEffective({
+  dir,
+  outputKind,
+}: {
+  dir: string
+  outputKind: OutputKind
+}) {
+  await outputConfigEffective(getEffectiveSocketYml(dir), outputKind)
+}
@@ -1,5 +1,5 @@
 import { outputConfigGet } from './output-config-get.mts'
-import { getConfigValue } from '../../util/config.mts'
+import { getConfigValue, getConfigValueSource } from '../../util/config.mts'
 
 import type { OutputKind } from '../../types.mts'
 import type { LocalConfig } from '../../util/config.mts'
@@ -7,11 +7,19 @@ import type { LocalConfig } from '../../util/config.mts'
 export async function handleConfigGet({
   key,
   outputKind,
+  showSource,
 }: {
   key: keyof LocalConfig
   outputKind: OutputKind
+  showSource?: boolean | undefined
 }) {
   const result = getConfigValue(key)
 
-  await outputConfigGet(key, result, outputKind)
+  if (showSource) {
+    await outputConfigGet(key, result, outputKind, {
+      source: getConfigValueSource(key),
+    })
+  } else {
+    await outputConfigGet(key, result, outputKind)
+  }
 }
@@ -0,0 +1,101 @@
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputConfigMigrateToken } from './output-config-migrate-token.mts'
+import {
+  CONFIG_KEY_API_TOKEN,
+  DEFAULT_PROFILE_NAME,
+} from '../../constants/config.mts'
+import {
+  getConfigValues,
+  getProfileConfig,
+  getProfileNames,
+  isConfigFromFlag,
+  updateProfileConfigValue,
+} from '../../util/config.mts'
+import {
+  isKeychainEnabled,
+  KEYCHAIN_API_TOKEN_REF,
+  storeKeychainToken,
+} from '../../util/keychain.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type TokenMigration = {
+  profile: string
+  reason?: string | undefined
+  status: 'failed' | 'in-keychain' | 'moved' | 'no-token'
+}
+
+export async function handleConfigMigrateToken({
+  outputKind,
+}: {
+  outputKind: OutputKind
+}) {
+  const result = migrateTokens()
+
+  debugDir({ result })
+
+  await outputConfigMigrateToken(result, outputKind)
+}
+
+function migrateTokens(): CResult<TokenMigration[]> {
+  if (isConfigFromFlag()) {
+    return {
+      ok: false,
+      message: 'Config is read-only',
+      cause:
+        'The config is overridden through an env var or flag, so the API tokens of the config file cannot be moved.',
+    }
+  }
+  if (!isKeychainEnabled()) {
+    return {
+      ok: false,
+      message: 'OS keychain unavailable',
+      cause:
+        'No supported OS credential manager was found, or SOCKET_CLI_NO_KEYCHAIN is set.',
+    }
+  }
+
+  const profiles = [DEFAULT_PROFILE_NAME, ...getProfileNames()]
+  const migrations: TokenMigration[] = []
+  for (let i = 0, { length } = profiles; i < length; i += 1) {
+    const profile = profiles[i]!
+    const apiToken =
+      profile === DEFAULT_PROFILE_NAME
+        ? getConfigValues().apiToken
+        : getProfileConfig(profile)?.apiToken
+    if (!apiToken) {
+      migrations.push({ profile, status: 'no-token' })
+      continue
+    }
+    if (apiToken === KEYCHAIN_API_TOKEN_REF) {
+      migrations.push({ profile, status: 'in-keychain' })
+      continue
+    }
+    const storeResult = storeKeychainToken(profile, apiToken)
+    if (storeResult.ok) {
+      updateProfileConfigValue(
+        profile,
+        CONFIG_KEY_API_TOKEN,
+        KEYCHAIN_API_TOKEN_REF,
+      )
+      migrations.push({ profile, status: 'moved' })
+    } else {
+      migrations.push({
+        profile,
+        reason: storeResult.cause,
+        status: 'failed',
+      })
+    }
+  }
+
+  if (migrations.some(m => m.status === 'failed')) {
+    return {
+      ok: false,
+      message: 'Some API tokens could not be moved to the OS keychain',
+      cause: 'They are still stored in the config file.',
+      data: migrations,
+    }
+  }
+  return { ok: true, data: migrations }
+}
@@ -1,7 +1,10 @@
 import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
 
 import { outputConfigSet } from './output-config-set.mts'
-import { updateConfigValue } from '../../util/config.mts'
+import {
+  getConfigValueSource,
+  updateConfigValue,
+} from '../../util/config.mts'
 import { InputError } from '../../util/error/errors.mts'
 
 import type { OutputKind } from '../../types.mts'
@@ -10,11 +13,13 @@ import type { LocalConfig } from '../../util/config.mts'
 export async function handleConfigSet({
   key,
   outputKind,
+  showSource,
   value,
 }: {
   key: keyof LocalConfig
   value?: string | undefined
   outputKind: OutputKind
+  showSource?: boolean | undefined
 }) {
   if (value === undefined) {
     throw new InputError(
@@ -30,5 +35,11 @@ export async function handleConfigSet({
   debug(`Config update ${result.ok ? 'succeeded' : 'failed'}`)
   debugDir({ result })
 
-  await outputConfigSet(result, outputKind)
+  if (showSource && result.ok) {
+    await outputConfigSet(result, outputKind, {
+      source: getConfigValueSource(key),
+    })
+  } else {
+    await outputConfigSet(result, outputKind)
+  }
 }
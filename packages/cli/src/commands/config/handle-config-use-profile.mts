@@ -0,0 +1,77 @@
+import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputConfigUseProfile } from './output-config-use-profile.mts'
+import {
+  CONFIG_KEY_ACTIVE_PROFILE,
+  CONFIG_KEY_DEFAULT_ORG,
+  DEFAULT_PROFILE_NAME,
+} from '../../constants/config.mts'
+import { getSocketCliProfile } from '../../env/socket-cli-profile.mts'
+import {
+  getConfigValueOrUndef,
+  getProfileConfig,
+  getProfileNames,
+  updateConfigValue,
+} from '../../util/config.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type UseProfileData = {
+  defaultOrg: string | undefined
+  // Set when the change only applies to this run.
+  note: string | undefined
+  profile: string
+}
+
+export async function handleConfigUseProfile({
+  outputKind,
+  profile,
+}: {
+  outputKind: OutputKind
+  profile: string
+}) {
+  debug(`Switching to profile ${profile}`)
+
+  const result = useProfile(profile)
+
+  debugDir({ result })
+
+  await outputConfigUseProfile(result, outputKind)
+}
+
+function useProfile(profile: string): CResult<UseProfileData> {
+  const isDefault = profile === DEFAULT_PROFILE_NAME
+  if (!isDefault && !getProfileConfig(profile)) {
+    const names = getProfileNames()
+    return {
+      ok: false,
+      message: 'Unknown profile',
+      cause: `There is no profile named "${profile}". ${names.length ? `Known profiles: ${joinAnd([DEFAULT_PROFILE_NAME, ...names])}.` : 'Create one with `socket login --profile <name>`.'}`,
+    }
+  }
+
+  const updateResult = updateConfigValue(
+    CONFIG_KEY_ACTIVE_PROFILE,
+    isDefault ? undefined : profile,
+  )
+  if (!updateResult.ok) {
+    return updateResult
+  }
+
+  const envProfile = getSocketCliProfile()
+  return {
+    ok: true,
+    message: `Now using profile '${profile}'`,
+    data: {
+      defaultOrg: isDefault
+        ? getConfigValueOrUndef(CONFIG_KEY_DEFAULT_ORG)
+        : getProfileConfig(profile)?.defaultOrg,
+      note:
+        envProfile && envProfile !== profile
+          ? `SOCKET_CLI_PROFILE is set to "${envProfile}" and takes precedence in this shell`
+          : updateResult.data,
+      profile,
+    },
+  }
+}
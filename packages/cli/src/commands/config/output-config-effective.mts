@@ -0,0 +1,60 @@
+import path from 'node:path'
+
+import { stringify as yamlStringify } from 'yaml'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { EffectiveSocketYml } from './handle-config-effective.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputConfigEffective(
+  result: CResult<EffectiveSocketYml>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { config, dir, sources } = result.data
+  const relSources = sources.map(source => path.relative(dir, source))
+
+  if (!config) {
+    logger.info(`No socket.yml applies to ${dir}`)
+    return
+  }
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Effective socket.yml'))
+    logger.log('')
+    logger.log(`For \`${dir}\`, merged from:`)
+    logger.log('')
+    logger.log(mdList(relSources.map(source => `\`${source}\``)))
+    logger.log('')
+    logger.log('```yaml')
+    logger.log(yamlStringify(config).trimEnd())
+    logger.log('```')
+    return
+  }
+
+  // Comments keep the output a valid socket.yml.
+  logger.log('# Merged from:')
+  for (const source of relSources) {
+    logger.log(`#   ${source}`)
+  }
+  logger.log(yamlStringify(config).trimEnd())
+}
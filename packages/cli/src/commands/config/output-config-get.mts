@@ -2,26 +2,44 @@
 /* oxlint-disable socket/no-logger-newline-literal -- CLI output formatting: multi-line user-facing messages where embedded \n produces the intended layout. Splitting into logger.log("") + logger.log(...) pairs is the canonical rewrite but doesnt preserve the visual flow for these specific outputs. */
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
-import { isConfigFromFlag } from '../../util/config.mts'
+import { formatConfigSource, isConfigFromFlag } from '../../util/config.mts'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
 import { mdHeader } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 
 import type { CResult, OutputKind } from '../../types.mts'
-import type { LocalConfig } from '../../util/config.mts'
+import type {
+  ConfigValueSource,
+  LocalConfig,
+} from '../../util/config.mts'
 const logger = getDefaultLogger()
 
 export async function outputConfigGet(
   key: keyof LocalConfig,
   result: CResult<LocalConfig[keyof LocalConfig]>,
   outputKind: OutputKind,
+  // Given for --source.
+  sourceInfo?: { source: ConfigValueSource | undefined } | undefined,
 ) {
   if (!result.ok) {
     process.exitCode = result.code ?? 1
   }
 
   if (outputKind === 'json') {
-    logger.log(serializeResultJson(result))
+    logger.log(
+      serializeResultJson(
+        result.ok && sourceInfo
+          ? {
+              ok: true,
+              data: {
+                value: result.data,
+                source: sourceInfo.source?.source ?? null,
+                origin: sourceInfo.source?.origin ?? null,
+              },
+            }
+          : result,
+      ),
+    )
     return
   }
   if (!result.ok) {
@@ -35,6 +53,10 @@ export async function outputConfigGet(
     logger.log(mdHeader('Config Value'))
     logger.log('')
     logger.log(`Config key '${key}' has value '${String(result.data)}'`)
+    if (sourceInfo) {
+      logger.log('')
+      logger.log(`Source: ${formatConfigSource(sourceInfo.source)}`)
+    }
     if (readOnly) {
       logger.log('')
       logger.log(
@@ -43,6 +65,9 @@ export async function outputConfigGet(
     }
   } else {
     logger.log(`${key}: ${String(result.data)}`)
+    if (sourceInfo) {
+      logger.log(`Source: ${formatConfigSource(sourceInfo.source)}`)
+    }
     if (readOnly) {
       logger.log('')
       logger.log(
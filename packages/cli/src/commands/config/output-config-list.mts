@@ -3,7 +3,9 @@
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
 import {
+  formatConfigSource,
   getConfigValue,
+  getConfigValueSource,
   getSupportedConfigKeys,
   isConfigFromFlag,
   isSensitiveConfigKey,
@@ -12,23 +14,32 @@ import { mdHeader } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 
 import type { OutputKind } from '../../types.mts'
+import type { ConfigSource } from '../../util/config.mts'
 const logger = getDefaultLogger()
 
 export async function outputConfigList({
   full,
   outputKind,
+  showSource,
 }: {
   full: boolean
   outputKind: OutputKind
+  showSource?: boolean | undefined
 }) {
   const readOnly = isConfigFromFlag()
   const supportedConfigKeys = getSupportedConfigKeys()
   if (outputKind === 'json') {
     let failed = false
     const obj: Record<string, unknown> = {}
+    const sources: Record<string, { origin: string; source: ConfigSource }> =
+      {}
     for (let i = 0, { length } = supportedConfigKeys; i < length; i += 1) {
       const key = supportedConfigKeys[i]!
       const result = getConfigValue(key)
+      const source = showSource ? getConfigValueSource(key) : undefined
+      if (source) {
+        sources[key] = { origin: source.origin, source: source.source }
+      }
       let value = result.data
       if (!result.ok) {
         value = `Failed to retrieve: ${result.message}`
@@ -53,6 +64,7 @@ export async function outputConfigList({
                 full,
                 config: obj,
                 readOnly,
+                ...(showSource ? { sources } : {}),
               }),
             }
           : {
@@ -61,6 +73,7 @@ export async function outputConfigList({
                 full,
                 config: obj,
                 readOnly,
+                ...(showSource ? { sources } : {}),
               },
             },
       ),
@@ -89,8 +102,11 @@ export async function outputConfigList({
           const displayValue = Array.isArray(value)
             ? value.join(', ') || '<none>'
             : String(value ?? '<none>')
+          const sourceSuffix = showSource
+            ? ` [${formatConfigSource(getConfigValueSource(key))}]`
+            : ''
           logger.log(
-            `- ${key}:${' '.repeat(Math.max(0, maxWidth - key.length + 3))} ${displayValue}`,
+            `- ${key}:${' '.repeat(Math.max(0, maxWidth - key.length + 3))} ${displayValue}${sourceSuffix}`,
           )
         }
       }
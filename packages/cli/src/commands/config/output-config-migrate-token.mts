@@ -0,0 +1,53 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { TokenMigration } from './handle-config-migrate-token.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+const logger = getDefaultLogger()
+
+const STATUS_TEXT: Record<TokenMigration['status'], string> = {
+  failed: 'could not be moved',
+  'in-keychain': 'already in the OS keychain',
+  moved: 'moved to the OS keychain',
+  'no-token': 'no API token stored',
+}
+
+export async function outputConfigMigrateToken(
+  result: CResult<TokenMigration[]>,
+  outputKind: OutputKind,
+) {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+
+  const migrations = (result.data ?? []) as TokenMigration[]
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Migrate API tokens'))
+    logger.log('')
+  }
+  for (let i = 0, { length } = migrations; i < length; i += 1) {
+    const { profile, reason, status } = migrations[i]!
+    logger.log(
+      `- ${profile}: ${STATUS_TEXT[status]}${reason ? ` (${reason})` : ''}`,
+    )
+  }
+  if (!result.ok) {
+    if (migrations.length) {
+      logger.log('')
+    }
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+  if (migrations.some(m => m.status === 'moved')) {
+    logger.log('')
+    logger.success('API tokens moved out of the config file')
+  }
+}
@@ -1,22 +1,39 @@
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
+import { formatConfigSource } from '../../util/config.mts'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
 import { mdHeader } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 
 import type { CResult, OutputKind } from '../../types.mts'
+import type { ConfigValueSource } from '../../util/config.mts'
 const logger = getDefaultLogger()
 
 export async function outputConfigSet(
   result: CResult<undefined | string>,
   outputKind: OutputKind,
+  // Given for --source, with the source of the value after the update.
+  sourceInfo?: { source: ConfigValueSource | undefined } | undefined,
 ) {
   if (!result.ok) {
     process.exitCode = result.code ?? 1
   }
 
   if (outputKind === 'json') {
-    logger.log(serializeResultJson(result))
+    logger.log(
+      serializeResultJson(
+        result.ok && sourceInfo
+          ? {
+              ...result,
+              data: {
+                note: result.data ?? null,
+                source: sourceInfo.source?.source ?? null,
+                origin: sourceInfo.source?.origin ?? null,
+              },
+            }
+          : result,
+      ),
+    )
     return
   }
   if (!result.ok) {
@@ -32,6 +49,10 @@ export async function outputConfigSet(
       logger.log('')
       logger.log(result.data)
     }
+    if (sourceInfo) {
+      logger.log('')
+      logSource(sourceInfo.source)
+    }
   } else {
     logger.log('OK')
     logger.log(result.message)
@@ -39,5 +60,17 @@ export async function outputConfigSet(
       logger.log('')
       logger.log(result.data)
     }
+    if (sourceInfo) {
+      logSource(sourceInfo.source)
+    }
+  }
+}
+
+function logSource(source: ConfigValueSource | undefined) {
+  logger.log(`Source of the effective value: ${formatConfigSource(source)}`)
+  if (source && source.source !== 'user') {
+    logger.log(
+      `Note: ${source.origin} takes precedence over the user config, so the updated value is not used.`,
+    )
   }
 }
@@ -0,0 +1,39 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { UseProfileData } from './handle-config-use-profile.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+const logger = getDefaultLogger()
+
+export async function outputConfigUseProfile(
+  result: CResult<UseProfileData>,
+  outputKind: OutputKind,
+) {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { defaultOrg, note } = result.data
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Use profile'))
+    logger.log('')
+  }
+  logger.success(result.message)
+  logger.log(`Default org: ${defaultOrg || '<none>'}`)
+  if (note) {
+    logger.log('')
+    logger.log(`Note: ${note}`)
+  }
+}
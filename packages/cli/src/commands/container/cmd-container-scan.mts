@@ -0,0 +1,228 @@
+import { existsSync } from 'node:fs'
+
+import { handleContainerScan } from './handle-container-scan.mts'
+import { outputDryRunUpload } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  formatImageReference,
+  parseImageReference,
+} from '../../util/container/image.mts'
+import { DEFAULT_IMAGE_PLATFORM } from '../../util/container/image-manifest.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+// Flags interface for type safety.
+export interface ContainerScanFlags {
+  branch: string
+  interactive: boolean
+  json: boolean
+  markdown: boolean
+  org: string
+  platform: string
+  repo: string
+  tmp: boolean
+}
+
+export const CMD_NAME = 'scan'
+
+const description =
+  'Create a scan from the packages installed in an OCI container image'
+
+const hidden = false
+
+export const cmdContainerScan: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      branch: {
+        type: 'string',
+        default: '',
+        description: 'Branch name. Defaults to the image tag',
+        shortFlag: 'b',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      platform: {
+        type: 'string',
+        default: '',
+        description: `Platform to scan for multi-platform images, e.g. linux/arm64. Defaults to ${DEFAULT_IMAGE_PLATFORM}`,
+      },
+      repo: {
+        type: 'string',
+        shortFlag: 'r',
+        description: 'Repository name. Defaults to the image name',
+      },
+      tmp: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Set the visibility (true/false) of the scan in your dashboard.',
+        shortFlag: 't',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <IMAGE>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    IMAGE is either a registry reference (pulled without a local Docker
+    daemon) or the path of a \`docker save\` / OCI layout tarball.
+
+    The image layers are flattened and scanned for:
+
+    - Language manifests and lockfiles, outside of installed packages
+    - Installed packages: dpkg (Debian, Ubuntu), apk (Alpine), npm
+      node_modules and Python site-packages
+
+    Private registries use the credentials stored by \`docker login\`.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} node:20-alpine
+      $ ${command} ghcr.io/acme/api:1.4.2 --platform linux/arm64
+      $ ${command} ./image.tar --repo api --branch main
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    branch: branchFlag,
+    interactive,
+    json,
+    markdown,
+    org: orgFlag,
+    platform,
+    repo: repoFlag,
+    tmp,
+  } = cli.flags as unknown as ContainerScanFlags
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const [image = ''] = cli.input
+
+  const isArchive = !!image && existsSync(image)
+
+  const imageRef = isArchive ? undefined : parseImageReference(image)
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!image,
+      message: 'Image reference or image tarball path',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !image || isArchive || !!imageRef,
+      message: 'IMAGE must be an image reference or an existing tarball',
+      fail: `invalid image "${image}"`,
+    },
+    {
+      nook: true,
+      test:
+        !platform || /^[a-z0-9]+\/[a-z0-9_]+(?:\/[a-z0-9]+)?$/.test(platform),
+      message: 'The --platform flag must look like os/arch[/variant]',
+      fail: `invalid platform "${platform}"`,
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    const details: Record<string, unknown> = {
+      organization: orgSlug,
+      image: imageRef ? formatImageReference(imageRef) : image,
+      platform: platform || DEFAULT_IMAGE_PLATFORM,
+    }
+    if (repoFlag) {
+      details['repository'] = repoFlag
+    }
+    if (branchFlag) {
+      details['branch'] = branchFlag
+    }
+    outputDryRunUpload('container scan', details)
+    return
+  }
+
+  await handleContainerScan({
+    branchName: branchFlag,
+    image,
+    imageRef,
+    interactive,
+    orgSlug,
+    outputKind,
+    platform: platform || undefined,
+    repoName: repoFlag,
+    tmp,
+  })
+}
@@ -0,0 +1,10 @@
+import { cmdContainerScan } from './cmd-container-scan.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdContainer = defineSubcommandGroup({
+  name: 'container',
+  description: 'Scan container images for vulnerable and malicious packages',
+  subcommands: {
+    scan: cmdContainerScan,
+  },
+})
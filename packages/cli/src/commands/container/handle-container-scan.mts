@@ -0,0 +1,213 @@
+import { mkdtempSync, promises as fs } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { debugDir, debugNs } from '@socketsecurity/lib-stable/debug/output'
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { DOT_SOCKET_DOT_CONTAINER_CDX_JSON } from '../../constants/paths.mts'
+import { readContainerFiles } from '../../util/container/filesystem.mts'
+import {
+  loadImageArchive,
+  parseImageReference,
+  pullRegistryImage,
+} from '../../util/container/image.mts'
+import {
+  collectInstalledPackages,
+  isContainerInstalledPath,
+  isContainerPackageDatabase,
+} from '../../util/container/packages.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import { createSupportedFilesFilter } from '../../util/fs/glob.mts'
+import { isLocalLockfilePath } from '../../util/lockfile/parsers.mts'
+import { lockfilesToCycloneDx } from '../../util/lockfile/sbom.mts'
+import { prepareLockfilesForUpload } from '../../util/lockfile/upload.mts'
+import { sanitizeName } from '../../util/sanitize-names.mts'
+import { fetchCreateOrgFullScan } from '../scan/fetch-create-org-full-scan.mts'
+import { fetchSupportedScanFileNames } from '../scan/fetch-supported-scan-file-names.mts'
+import { outputCreateNewScan } from '../scan/output-create-new-scan.mts'
+
+import type { OutputKind } from '../../types.mts'
+import type { ImageReference } from '../../util/container/image.mts'
+
+const logger = getDefaultLogger()
+
+export type HandleContainerScanConfig = {
+  branchName: string
+  image: string
+  // Set when image is a registry reference rather than a tarball.
+  imageRef: ImageReference | undefined
+  interactive: boolean
+  orgSlug: string
+  outputKind: OutputKind
+  platform: string | undefined
+  repoName: string
+  tmp: boolean
+}
+
+export async function handleContainerScan({
+  branchName,
+  image,
+  imageRef,
+  interactive,
+  orgSlug,
+  outputKind,
+  platform,
+  repoName,
+  tmp,
+}: HandleContainerScanConfig): Promise<void> {
+  const spinner = getDefaultSpinner()
+
+  const supportedFilesCResult = await fetchSupportedScanFileNames({
+    orgSlug,
+    spinner,
+  })
+  if (!supportedFilesCResult.ok) {
+    await outputCreateNewScan(supportedFilesCResult, {
+      interactive,
+      outputKind,
+    })
+    return
+  }
+  const isSupportedFile = createSupportedFilesFilter(supportedFilesCResult.data)
+  const isManifest = (filepath: string) =>
+    !isContainerInstalledPath(filepath) &&
+    (isSupportedFile(filepath) || isLocalLockfilePath(filepath))
+
+  spinner.start(`Loading image ${image}…`)
+  const imageCResult = imageRef
+    ? await pullRegistryImage(imageRef, { platform })
+    : loadImageArchive(path.resolve(image), { platform })
+  if (!imageCResult.ok) {
+    spinner.stop()
+    await outputCreateNewScan(imageCResult, { interactive, outputKind })
+    return
+  }
+  const containerImage = imageCResult.data
+  debugDir('inspect', {
+    digest: containerImage.digest,
+    layers: containerImage.layers.length,
+    name: containerImage.name,
+    platform: containerImage.platform,
+  })
+
+  const tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-container-'))
+  try {
+    let manifestPaths: string[]
+    let installedCount = 0
+    try {
+      const { files, skippedLayers } = await readContainerFiles(
+        containerImage,
+        filepath =>
+          isContainerPackageDatabase(filepath) || isManifest(filepath),
+        (index, total) => {
+          spinner.text = `Reading layer ${index + 1} of ${total} of ${containerImage.name}…`
+        },
+      )
+      if (skippedLayers.length) {
+        logger.warn(
+          `Skipped ${skippedLayers.length} ${pluralize('layer', { count: skippedLayers.length })} in an unsupported compression: ${skippedLayers.join(', ')}`,
+        )
+      }
+
+      manifestPaths = []
+      for (const [filepath, content] of files) {
+        if (isManifest(filepath)) {
+          const target = path.join(tmpDir, filepath)
+          await fs.mkdir(path.dirname(target), { recursive: true })
+          await fs.writeFile(target, content)
+          manifestPaths.push(target)
+        }
+      }
+
+      const installed = collectInstalledPackages(files)
+      for (const lockfile of installed) {
+        installedCount += lockfile.dependencies.length
+      }
+      if (installed.length) {
+        const sbomPath = path.join(tmpDir, DOT_SOCKET_DOT_CONTAINER_CDX_JSON)
+        await fs.writeFile(
+          sbomPath,
+          `${JSON.stringify(lockfilesToCycloneDx(installed), null, 2)}\n`,
+          'utf8',
+        )
+        manifestPaths.push(sbomPath)
+      }
+    } catch (e) {
+      spinner.stop()
+      debugDir('error', e)
+      await outputCreateNewScan(
+        {
+          ok: false,
+          message: 'Unable to read image layers',
+          cause: getErrorCause(e),
+        },
+        { interactive, outputKind },
+      )
+      return
+    } finally {
+      containerImage.close()
+    }
+
+    spinner.successAndStop(
+      `Found ${manifestPaths.length} ${pluralize('file', { count: manifestPaths.length })} and ${installedCount} installed ${pluralize('package', { count: installedCount })} in ${containerImage.name}${containerImage.platform ? ` (${containerImage.platform})` : ''}.`,
+    )
+    if (!manifestPaths.length) {
+      await outputCreateNewScan(
+        {
+          ok: false,
+          message: 'No packages found',
+          cause: `${containerImage.name} contains no supported manifests or installed package databases`,
+        },
+        { interactive, outputKind },
+      )
+      return
+    }
+
+    const nameRef = imageRef ?? parseImageReference(containerImage.name)
+    const lockfileScan = await prepareLockfilesForUpload(manifestPaths, {
+      cwd: tmpDir,
+      isSupportedByApi: isSupportedFile,
+    })
+    try {
+      debugNs(
+        'notice',
+        `Uploading ${lockfileScan.paths.length} files from ${containerImage.name}`,
+      )
+      const fullScanCResult = await fetchCreateOrgFullScan(
+        lockfileScan.paths,
+        orgSlug,
+        {
+          branchName: branchName || nameRef?.tag || '',
+          commitHash: '',
+          commitMessage: containerImage.digest
+            ? `${containerImage.name} ${containerImage.digest}`
+            : containerImage.name,
+          committers: '',
+          pullRequest: 0,
+          repoName:
+            repoName ||
+            sanitizeName(
+              nameRef?.repository.replace(/^library\//, '') ??
+                containerImage.name,
+            ),
+          scanType: undefined,
+        },
+        {
+          commandPath: 'socket container scan',
+          cwd: tmpDir,
+          spinner,
+          tmp,
+        },
+      )
+      await outputCreateNewScan(fullScanCResult, { interactive, outputKind })
+    } finally {
+      await lockfileScan.cleanup()
+    }
+  } finally {
+    await safeDelete(tmpDir, { force: true })
+  }
+}
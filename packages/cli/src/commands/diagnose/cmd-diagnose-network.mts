@@ -0,0 +1,97 @@
+import { handleDiagnoseNetwork } from './handle-diagnose-network.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'network'
+
+const description =
+  'Show the proxy and CA certificates Socket CLI connects with'
+
+const hidden = false
+
+export const cmdDiagnoseNetwork: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Reports the Socket API base URL, the proxy requests go through and where
+    it was configured, whether NO_PROXY bypasses it, the extra CA certificates
+    that are trusted, and the proxy and CA variables spawned package managers
+    and tools inherit.
+
+    Proxies come from SOCKET_CLI_API_PROXY, HTTPS_PROXY / HTTP_PROXY or the
+    apiProxy config value. CA certificates come from --cacert, the caCert
+    config value, NODE_EXTRA_CA_CERTS or SSL_CERT_FILE, in that order.
+
+    Examples
+      $ ${command}
+      $ ${command} --cacert ./corp-ca.pem --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags as {
+    json: boolean
+    markdown: boolean
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(outputKind, {
+    nook: true,
+    test: !json || !markdown,
+    message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+    fail: 'bad',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('network settings')
+    return
+  }
+
+  await handleDiagnoseNetwork(outputKind)
+}
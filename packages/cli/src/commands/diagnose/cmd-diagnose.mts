@@ -0,0 +1,24 @@
+import { cmdDiagnoseNetwork } from './cmd-diagnose-network.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Diagnose problems running Socket CLI'
+
+export const cmdDiagnose: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} diagnose`,
+        importMeta,
+        subcommands: {
+          network: cmdDiagnoseNetwork,
+        },
+      },
+      { description },
+    )
+  },
+}
@@ -0,0 +1,113 @@
+import { readFileSync } from 'node:fs'
+
+import { outputDiagnoseNetwork } from './output-diagnose-network.mts'
+import { API_V0_URL } from '../../constants/socket.mts'
+import {
+  getCaCert,
+  getNoProxy,
+  getProxyEnvKey,
+  isNodeExtraCaCertsLoaded,
+  isNoProxyUrl,
+} from '../../util/socket/network.mts'
+import {
+  getDefaultApiBaseUrl,
+  getDefaultProxyUrl,
+} from '../../util/socket/sdk.mjs'
+
+import type { OutputKind } from '../../types.mts'
+import type { CaCertSource } from '../../util/socket/network.mts'
+
+// Variables applyNetworkEnv() may hand to spawned tools.
+const CHILD_ENV_KEYS = [
+  'HTTPS_PROXY',
+  'HTTP_PROXY',
+  'NO_PROXY',
+  'NODE_EXTRA_CA_CERTS',
+]
+
+export type NetworkDiagnosis = {
+  apiBaseUrl: string
+  caCert: {
+    certificates: number
+    error?: string | undefined
+    nodeLoaded: boolean
+    path: string
+    source: CaCertSource
+  } | null
+  childEnv: Record<string, string>
+  noProxy: string
+  proxy: {
+    bypassed: boolean
+    source: string
+    url: string
+  } | null
+}
+
+// Proxy URLs may carry credentials, which do not belong in a report.
+function redactUrlPassword(value: string): string {
+  try {
+    const url = new URL(value)
+    if (url.password) {
+      url.password = '***'
+      return url.href
+    }
+  } catch {}
+  return value
+}
+
+function diagnoseCaCert(): NetworkDiagnosis['caCert'] {
+  const caCert = getCaCert()
+  if (!caCert) {
+    return null
+  }
+  const nodeLoaded = isNodeExtraCaCertsLoaded()
+  try {
+    const pem = readFileSync(caCert.path, 'utf8')
+    return {
+      certificates: pem.match(/-----BEGIN CERTIFICATE-----/g)?.length ?? 0,
+      nodeLoaded,
+      ...caCert,
+    }
+  } catch (e) {
+    return {
+      certificates: 0,
+      error: (e as Error).message,
+      nodeLoaded,
+      ...caCert,
+    }
+  }
+}
+
+export function diagnoseNetwork(): NetworkDiagnosis {
+  const apiBaseUrl = getDefaultApiBaseUrl() || API_V0_URL
+  const proxyUrl = getDefaultProxyUrl()
+  const childEnv: Record<string, string> = {}
+  for (const key of CHILD_ENV_KEYS) {
+    const value = process.env[key]
+    if (value) {
+      childEnv[key] = redactUrlPassword(value)
+    }
+  }
+  return {
+    apiBaseUrl,
+    caCert: diagnoseCaCert(),
+    childEnv,
+    noProxy: getNoProxy(),
+    proxy: proxyUrl
+      ? {
+          bypassed: isNoProxyUrl(apiBaseUrl),
+          source: getProxyEnvKey() ?? 'config',
+          url: redactUrlPassword(proxyUrl),
+        }
+      : null,
+  }
+}
+
+export async function handleDiagnoseNetwork(
+  outputKind: OutputKind,
+): Promise<void> {
+  await outputDiagnoseNetwork(
+    { ok: true, data: diagnoseNetwork() },
+    outputKind,
+  )
+}
@@ -0,0 +1,89 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { NetworkDiagnosis } from './handle-diagnose-network.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function getCaCertLines(caCert: NetworkDiagnosis['caCert']): string[] {
+  if (!caCert) {
+    return ['- CA certificates: Node.js defaults']
+  }
+  const lines = [`- CA certificates: ${caCert.path} (from ${caCert.source})`]
+  if (caCert.error) {
+    lines.push(`  - Not readable: ${caCert.error}`)
+  } else if (!caCert.certificates) {
+    lines.push('  - No PEM certificates found in the file')
+  } else {
+    lines.push(`  - Certificates: ${caCert.certificates}`)
+  }
+  if (caCert.source === 'NODE_EXTRA_CA_CERTS') {
+    lines.push('  - Loaded by Node.js at startup')
+  } else if (caCert.nodeLoaded) {
+    // Spawned tools keep the NODE_EXTRA_CA_CERTS file the user set.
+    lines.push('  - Spawned tools trust NODE_EXTRA_CA_CERTS instead')
+  } else {
+    lines.push('  - Spawned tools get it as NODE_EXTRA_CA_CERTS')
+  }
+  return lines
+}
+
+function getProxyLines(
+  proxy: NetworkDiagnosis['proxy'],
+  noProxy: string,
+): string[] {
+  const lines = proxy
+    ? [`- Proxy: ${proxy.url} (from ${proxy.source})`]
+    : ['- Proxy: none']
+  lines.push(`- NO_PROXY: ${noProxy || 'none'}`)
+  if (proxy?.bypassed) {
+    lines.push('  - The Socket API host matches NO_PROXY and skips the proxy')
+  }
+  return lines
+}
+
+export async function outputDiagnoseNetwork(
+  result: CResult<NetworkDiagnosis>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { apiBaseUrl, caCert, childEnv, noProxy, proxy } = result.data
+  const envEntries = Object.entries(childEnv)
+  const lines = [
+    `- Socket API: ${apiBaseUrl}`,
+    ...getProxyLines(proxy, noProxy),
+    ...getCaCertLines(caCert),
+    envEntries.length
+      ? '- Environment of spawned tools:'
+      : '- Environment of spawned tools: no proxy or CA variables',
+    ...envEntries.map(({ 0: key, 1: value }) => `  - ${key}=${value}`),
+  ]
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Network settings'))
+    logger.log('')
+  } else {
+    logger.log('Network settings:')
+    logger.log('')
+  }
+  for (const line of lines) {
+    logger.log(line)
+  }
+}
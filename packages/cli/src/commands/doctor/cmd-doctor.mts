@@ -0,0 +1,104 @@
+import { handleDoctor } from './handle-doctor.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'doctor'
+
+const description = 'Check the Socket CLI setup and suggest fixes'
+
+const hidden = false
+
+export const cmdDoctor: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Checks:
+      - Network: whether the Socket API is reachable, through the proxy and
+        with the CA certificates Socket CLI would use
+      - Authentication: the API token, whether the Socket API accepts it, the
+        orgs it can access and whether its scopes cover every command
+      - Config: values a higher layer (flag, env, repo, user, system)
+        overrides with another value
+      - Package managers: Node.js, npm, pnpm, yarn, bun and vlt with their
+        versions, and lockfiles in the current directory whose package
+        manager is missing
+      - PATH: socket, socket-npm, socket-npx, npm and npx binaries shadowed
+        by another of the same name, and wrapper aliases or shims that call a
+        socket which is not installed
+
+    Every warning and failure comes with a fix. The exit code is 1 when a
+    check fails, so CI can run this before other Socket commands.
+
+    Examples
+      $ ${command}
+      $ ${command} --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags as {
+    json: boolean
+    markdown: boolean
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(outputKind, {
+    nook: true,
+    test: !json || !markdown,
+    message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+    fail: 'bad',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('environment checks')
+    return
+  }
+
+  await handleDoctor(process.cwd(), outputKind)
+}
@@ -0,0 +1,468 @@
+/**
+ * Checks of `socket doctor`. Each check reports what it found and, when
+ * something is off, a fix the user can run or apply.
+ *
+ * The API checks run after the network check and are skipped when the Socket
+ * API is not reachable, so one network problem is not reported five times.
+ */
+
+import { existsSync } from 'node:fs'
+import path from 'node:path'
+
+import { whichRealSync } from '@socketsecurity/lib-stable/bin/which'
+import {
+  BUN,
+  NPM,
+  PNPM,
+  VLT,
+  YARN_CLASSIC,
+} from '@socketsecurity/lib-stable/constants/agents'
+
+import { CONFIG_KEY_DEFAULT_ORG } from '../../constants/config.mts'
+import { EXIT_CODE_AUTH_ERROR } from '../../constants/exit-codes.mts'
+import {
+  PACKAGE_LOCK_JSON,
+  PNPM_LOCK_YAML,
+  SOCKET_CLI_BIN_NAME,
+  SOCKET_CLI_NPM_BIN_NAME,
+  SOCKET_CLI_NPX_BIN_NAME,
+  YARN_LOCK,
+} from '../../constants/packages.mts'
+import { getBashRcPath, getZshRcPath } from '../../constants/paths.mts'
+import {
+  formatConfigSource,
+  getConfigValueLayers,
+  getConfigValueOrUndef,
+  getConfigValueSource,
+  getSupportedConfigKeys,
+  isSensitiveConfigKey,
+} from '../../util/config.mts'
+import { getAgentVersion } from '../../util/ecosystem/environment.mts'
+import { getRequirements } from '../../util/ecosystem/requirements.mts'
+import { getNetworkErrorDiagnostics } from '../../util/error/errors.mts'
+import { socketHttpRequest } from '../../util/socket/api-http.mts'
+import { getApiTimeoutMs } from '../../util/socket/retry.mts'
+import { getDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { diagnoseNetwork } from '../diagnose/handle-diagnose-network.mts'
+import { fetchOrgApiTokens } from '../organization/fetch-org-tokens.mts'
+import { fetchOrganization } from '../organization/fetch-organization-list.mts'
+import { hasSocketWrapperAlias } from '../wrapper/check-socket-wrapper-setup.mts'
+import {
+  WRAPPABLE_BINS,
+  isSocketWrapperShim,
+} from '../wrapper/wrapper-shims.mts'
+
+import type { Agent } from '../../util/ecosystem/environment.mts'
+
+export type DoctorCategory =
+  | 'auth'
+  | 'config'
+  | 'network'
+  | 'package-managers'
+  | 'path'
+
+export type DoctorCheckStatus = 'fail' | 'ok' | 'skip' | 'warn'
+
+export type DoctorCheck = {
+  category: DoctorCategory
+  // What to do about a warning or failure.
+  fix?: string | undefined
+  message: string
+  name: string
+  status: DoctorCheckStatus
+}
+
+type PackageManager = {
+  agent: Agent
+  bin: string
+  lockfiles: string[]
+}
+
+const PACKAGE_MANAGERS: PackageManager[] = [
+  { agent: NPM, bin: 'npm', lockfiles: [PACKAGE_LOCK_JSON] },
+  { agent: PNPM, bin: 'pnpm', lockfiles: [PNPM_LOCK_YAML] },
+  { agent: YARN_CLASSIC, bin: 'yarn', lockfiles: [YARN_LOCK] },
+  { agent: BUN, bin: 'bun', lockfiles: ['bun.lock', 'bun.lockb'] },
+  { agent: VLT, bin: 'vlt', lockfiles: ['vlt-lock.json'] },
+]
+
+// Binaries the Socket wrapper and its shims stand in for, or that are the
+// Socket CLI itself.
+const SHADOWABLE_BINS = [
+  SOCKET_CLI_BIN_NAME,
+  SOCKET_CLI_NPM_BIN_NAME,
+  SOCKET_CLI_NPX_BIN_NAME,
+  'npm',
+  'npx',
+]
+
+const REACHABILITY_TIMEOUT_MS = 10_000
+
+/**
+ * Every `bin` on the PATH in lookup order, the first one is the one a shell
+ * runs. Symlinks to the same file count once.
+ */
+export function findBinsOnPath(bin: string): string[] {
+  const found = whichRealSync(bin, { all: true, nothrow: true })
+  if (!found) {
+    return []
+  }
+  return [...new Set(Array.isArray(found) ? found : [found])]
+}
+
+export async function checkApiReachable(): Promise<DoctorCheck> {
+  const { apiBaseUrl, proxy } = diagnoseNetwork()
+  const startTime = Date.now()
+  try {
+    const response = await socketHttpRequest(apiBaseUrl, {
+      method: 'GET',
+      timeout: getApiTimeoutMs() ?? REACHABILITY_TIMEOUT_MS,
+    })
+    return {
+      category: 'network',
+      message: `Reached ${apiBaseUrl} in ${Date.now() - startTime}ms (HTTP ${response.status})${proxy && !proxy.bypassed ? ` through ${proxy.url}` : ''}`,
+      name: 'api-reachable',
+      status: 'ok',
+    }
+  } catch (e) {
+    return {
+      category: 'network',
+      fix: proxy
+        ? `Check that the proxy from ${proxy.source} is up, or exclude the Socket API host with NO_PROXY. Behind a TLS-inspecting proxy, pass its CA with --cacert.`
+        : 'Behind a corporate proxy, set HTTPS_PROXY (or the apiProxy config value) and pass its CA with --cacert. Run `socket diagnose network` for details.',
+      message: `Could not reach ${apiBaseUrl}: ${getNetworkErrorDiagnostics(e, Date.now() - startTime)}`,
+      name: 'api-reachable',
+      status: 'fail',
+    }
+  }
+}
+
+export function checkCaCert(): DoctorCheck {
+  const { caCert } = diagnoseNetwork()
+  if (!caCert) {
+    return {
+      category: 'network',
+      message: 'Using the CA certificates of Node.js',
+      name: 'ca-cert',
+      status: 'ok',
+    }
+  }
+  if (caCert.error || !caCert.certificates) {
+    return {
+      category: 'network',
+      fix: `Point ${caCert.source === 'config' ? 'the caCert config value' : caCert.source} at a readable PEM file, or unset it`,
+      message: `The CA file ${caCert.path} from ${caCert.source} ${caCert.error ? `is not readable: ${caCert.error}` : 'has no PEM certificates'}`,
+      name: 'ca-cert',
+      status: 'fail',
+    }
+  }
+  return {
+    category: 'network',
+    message: `Trusting ${caCert.certificates} extra CA certificates from ${caCert.path} (${caCert.source})`,
+    name: 'ca-cert',
+    status: 'ok',
+  }
+}
+
+/**
+ * The token, whether the Socket API accepts it, the orgs it can access and
+ * its scopes. Only the first check runs when the API is not reachable.
+ */
+export async function checkAuth(reachable: boolean): Promise<DoctorCheck[]> {
+  const apiToken = getDefaultApiToken()
+  if (!apiToken) {
+    return [
+      {
+        category: 'auth',
+        fix: 'Run `socket login`, or set SOCKET_CLI_API_TOKEN in CI',
+        message: 'No Socket API token is configured',
+        name: 'api-token',
+        status: 'fail',
+      },
+    ]
+  }
+  const checks: DoctorCheck[] = [
+    {
+      category: 'auth',
+      message: `API token from ${formatConfigSource(getConfigValueSource('apiToken'))}`,
+      name: 'api-token',
+      status: 'ok',
+    },
+  ]
+  if (!reachable) {
+    checks.push({
+      category: 'auth',
+      message: 'Skipped checking the token, the Socket API is not reachable',
+      name: 'api-token-valid',
+      status: 'skip',
+    })
+    return checks
+  }
+
+  const orgsCResult = await fetchOrganization({ description: '' })
+  if (!orgsCResult.ok) {
+    const rejected = orgsCResult.code === EXIT_CODE_AUTH_ERROR
+    checks.push({
+      category: 'auth',
+      fix: rejected
+        ? 'Create a new API token in the Socket dashboard and run `socket login` with it'
+        : undefined,
+      message: rejected
+        ? 'The Socket API rejected the token, it may be revoked or expired'
+        : `Could not list the orgs of the token: ${orgsCResult.cause || orgsCResult.message}`,
+      name: 'api-token-valid',
+      status: 'fail',
+    })
+    return checks
+  }
+  const orgSlugs = orgsCResult.data.organizations.map(org => org.slug)
+  checks.push({
+    category: 'auth',
+    message: `The token has access to ${orgSlugs.length ? orgSlugs.join(', ') : 'no orgs'}`,
+    name: 'api-token-valid',
+    status: orgSlugs.length ? 'ok' : 'warn',
+    ...(orgSlugs.length
+      ? {}
+      : { fix: 'Ask an org admin to create a token of your org' }),
+  })
+
+  const orgSlug = checkOrgAccess(checks, orgSlugs)
+  if (orgSlug) {
+    checks.push(await checkTokenScopes(apiToken, orgSlug))
+  }
+  return checks
+}
+
+/**
+ * Adds the org check to `checks` and returns the org to check the token
+ * scopes with, if there is one.
+ */
+function checkOrgAccess(
+  checks: DoctorCheck[],
+  orgSlugs: string[],
+): string | undefined {
+  const defaultOrg = getConfigValueOrUndef(CONFIG_KEY_DEFAULT_ORG)
+  if (defaultOrg) {
+    if (orgSlugs.includes(defaultOrg)) {
+      checks.push({
+        category: 'auth',
+        message: `The default org ${defaultOrg} (${formatConfigSource(getConfigValueSource(CONFIG_KEY_DEFAULT_ORG))}) is accessible`,
+        name: 'org-access',
+        status: 'ok',
+      })
+      return defaultOrg
+    }
+    checks.push({
+      category: 'auth',
+      fix: `Run \`socket config set defaultOrg ${orgSlugs[0] ?? '<org>'}\`, or use a token of ${defaultOrg}`,
+      message: `The default org ${defaultOrg} (${formatConfigSource(getConfigValueSource(CONFIG_KEY_DEFAULT_ORG))}) is not accessible with this token`,
+      name: 'org-access',
+      status: 'fail',
+    })
+    return undefined
+  }
+  if (orgSlugs.length > 1) {
+    checks.push({
+      category: 'auth',
+      fix: `Run \`socket config set defaultOrg ${orgSlugs[0]}\`, or pass --org`,
+      message: 'No default org is set, commands will ask which org to use',
+      name: 'org-access',
+      status: 'warn',
+    })
+  }
+  return orgSlugs.length === 1 ? orgSlugs[0] : undefined
+}
+
+async function checkTokenScopes(
+  apiToken: string,
+  orgSlug: string,
+): Promise<DoctorCheck> {
+  const tokensCResult = await fetchOrgApiTokens(orgSlug)
+  const token = tokensCResult.ok
+    ? tokensCResult.data.find(
+        t => !!t.token_prefix && apiToken.startsWith(t.token_prefix),
+      )
+    : undefined
+  if (!token) {
+    return {
+      category: 'auth',
+      message: tokensCResult.ok
+        ? `The token is not among the API tokens of ${orgSlug}`
+        : 'Could not read the token scopes, that needs the api-tokens:list scope',
+      name: 'token-scopes',
+      status: 'skip',
+    }
+  }
+  const missing = getMissingScopes(token.scopes)
+  if (!missing.length) {
+    return {
+      category: 'auth',
+      message: `The token scopes cover every command: ${token.scopes.join(', ')}`,
+      name: 'token-scopes',
+      status: 'ok',
+    }
+  }
+  return {
+    category: 'auth',
+    fix: `Commands that need them fail with 403. If you need them, create a token with \`socket organization tokens create --scopes ${[...token.scopes, ...missing].join(',')}\``,
+    message: `The token lacks scopes some commands need: ${missing.join(', ')}`,
+    name: 'token-scopes',
+    status: 'warn',
+  }
+}
+
+/**
+ * Scopes commands need, per data/command-api-requirements.json, that
+ * `scopes` does not include.
+ */
+export function getMissingScopes(scopes: string[]): string[] {
+  const granted = new Set(scopes)
+  const missing = new Set<string>()
+  for (const { permissions } of Object.values(getRequirements().api)) {
+    for (const permission of permissions) {
+      if (!granted.has(permission)) {
+        missing.add(permission)
+      }
+    }
+  }
+  return [...missing].sort()
+}
+
+/**
+ * Config keys whose value is shadowed by a higher layer with another value,
+ * which is the usual cause of "I set it but it does not apply".
+ */
+export function checkConfigLayers(): DoctorCheck[] {
+  const checks: DoctorCheck[] = []
+  let setCount = 0
+  for (const key of getSupportedConfigKeys()) {
+    const layers = getConfigValueLayers(key)
+    if (layers.length) {
+      setCount += 1
+    }
+    const { 0: effective, 1: shadowed } = layers
+    if (!effective || !shadowed || effective.value === shadowed.value) {
+      continue
+    }
+    const sensitive = isSensitiveConfigKey(key)
+    checks.push({
+      category: 'config',
+      fix:
+        effective.source === 'env' || effective.source === 'flag'
+          ? `Unset ${effective.origin} to use the ${shadowed.source} value`
+          : `Run \`socket config unset ${key}\` or edit ${effective.origin} to use the ${shadowed.source} value`,
+      message: `${key}${sensitive ? '' : ` = ${JSON.stringify(effective.value)}`} from ${formatConfigSource(effective)} overrides ${formatConfigSource(shadowed)}`,
+      name: `config-${key}`,
+      status: 'warn',
+    })
+  }
+  if (!checks.length) {
+    checks.push({
+      category: 'config',
+      message: `${setCount} config ${setCount === 1 ? 'key is' : 'keys are'} set, none shadowed by another layer`,
+      name: 'config-layers',
+      status: 'ok',
+    })
+  }
+  return checks
+}
+
+/**
+ * The package managers on the PATH with their versions, and whether the one
+ * the lockfile in `cwd` asks for is installed.
+ */
+export async function checkPackageManagers(
+  cwd: string,
+): Promise<DoctorCheck[]> {
+  const checks: DoctorCheck[] = [
+    {
+      category: 'package-managers',
+      message: `Node.js ${process.version} (${process.execPath})`,
+      name: 'node',
+      status: 'ok',
+    },
+  ]
+  for (const { agent, bin, lockfiles } of PACKAGE_MANAGERS) {
+    const lockfile = lockfiles.find(name => existsSync(path.join(cwd, name)))
+    const execPath = findBinsOnPath(bin)[0]
+    if (!execPath) {
+      if (lockfile) {
+        checks.push({
+          category: 'package-managers',
+          fix: `Install ${bin}, e.g. with \`corepack enable\` or from its website`,
+          message: `${lockfile} needs ${bin}, which is not on the PATH`,
+          name: bin,
+          status: 'fail',
+        })
+      }
+      continue
+    }
+    // oxlint-disable-next-line no-await-in-loop -- one --version spawn at a time keeps the output order stable.
+    const version = await getAgentVersion(agent, execPath, cwd)
+    checks.push({
+      category: 'package-managers',
+      message: `${bin} ${version?.version ?? '(unknown version)'} (${execPath})${lockfile ? `, used by ${lockfile}` : ''}`,
+      name: bin,
+      status: version ? 'ok' : 'warn',
+      ...(version
+        ? {}
+        : { fix: `Run \`${bin} --version\` to see why it does not start` }),
+    })
+  }
+  return checks
+}
+
+/**
+ * Binaries of the Socket CLI, its shims and npm that are on the PATH more
+ * than once, and wrapper aliases or shims that call a `socket` which is not
+ * there.
+ */
+export function checkShadowedBinaries(): DoctorCheck[] {
+  const checks: DoctorCheck[] = []
+  for (const bin of SHADOWABLE_BINS) {
+    // The shims of `socket wrapper` are meant to come first.
+    const found = findBinsOnPath(bin).filter(file => !isSocketWrapperShim(file))
+    if (found.length > 1) {
+      checks.push({
+        category: 'path',
+        fix: `Remove the copies you do not use, or reorder PATH so ${found[0]} is the one you want`,
+        message: `${found[0]} shadows ${found.slice(1).join(', ')}`,
+        name: bin,
+        status: 'warn',
+      })
+    }
+  }
+  const wrappers = [
+    ...[getBashRcPath(), getZshRcPath()]
+      .filter(file => hasSocketWrapperAlias(file))
+      .map(file => `the npm/npx aliases in ${file}`),
+    ...WRAPPABLE_BINS.filter(bin =>
+      isSocketWrapperShim(findBinsOnPath(bin)[0] ?? ''),
+    ).map(bin => `the ${bin} shim`),
+  ]
+  if (wrappers.length) {
+    const hasSocketBin = findBinsOnPath(SOCKET_CLI_BIN_NAME).length > 0
+    checks.push({
+      category: 'path',
+      message: hasSocketBin
+        ? `The wrapper runs Socket CLI through ${wrappers.join(', ')}`
+        : `The wrapper calls socket, which is not on the PATH, through ${wrappers.join(', ')}`,
+      name: 'wrapper',
+      status: hasSocketBin ? 'ok' : 'fail',
+      ...(hasSocketBin
+        ? {}
+        : {
+            fix: 'Install Socket CLI globally with `npm install -g socket`, or run `socket wrapper disable`',
+          }),
+    })
+  }
+  if (!checks.length) {
+    checks.push({
+      category: 'path',
+      message: 'No Socket CLI or npm binary is shadowed by another on the PATH',
+      name: 'shadowed',
+      status: 'ok',
+    })
+  }
+  return checks
+}
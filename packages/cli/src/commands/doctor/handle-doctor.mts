@@ -0,0 +1,44 @@
+import {
+  checkApiReachable,
+  checkAuth,
+  checkCaCert,
+  checkConfigLayers,
+  checkPackageManagers,
+  checkShadowedBinaries,
+} from './doctor-checks.mts'
+import { outputDoctor } from './output-doctor.mts'
+
+import type { DoctorCheck, DoctorCheckStatus } from './doctor-checks.mts'
+import type { OutputKind } from '../../types.mts'
+
+export type DoctorReport = {
+  checks: DoctorCheck[]
+  summary: Record<DoctorCheckStatus, number>
+}
+
+export async function runDoctorChecks(cwd: string): Promise<DoctorReport> {
+  const reachability = await checkApiReachable()
+  const checks = [
+    checkCaCert(),
+    reachability,
+    ...(await checkAuth(reachability.status === 'ok')),
+    ...checkConfigLayers(),
+    ...(await checkPackageManagers(cwd)),
+    ...checkShadowedBinaries(),
+  ]
+  const summary = { fail: 0, ok: 0, skip: 0, warn: 0 }
+  for (const { status } of checks) {
+    summary[status] += 1
+  }
+  return { checks, summary }
+}
+
+export async function handleDoctor(
+  cwd: string,
+  outputKind: OutputKind,
+): Promise<void> {
+  await outputDoctor(
+    { ok: true, data: await runDoctorChecks(cwd) },
+    outputKind,
+  )
+}
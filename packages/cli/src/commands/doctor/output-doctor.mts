@@ -0,0 +1,74 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { DoctorCategory, DoctorCheck } from './doctor-checks.mts'
+import type { DoctorReport } from './handle-doctor.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+// In the order the checks run.
+const CATEGORY_TITLES: Array<[DoctorCategory, string]> = [
+  ['network', 'Network'],
+  ['auth', 'Authentication'],
+  ['config', 'Config'],
+  ['package-managers', 'Package managers'],
+  ['path', 'PATH'],
+]
+
+function getCheckLines(check: DoctorCheck): string[] {
+  const lines = [`- [${check.status}] ${check.message}`]
+  if (check.fix) {
+    lines.push(`  Fix: ${check.fix}`)
+  }
+  return lines
+}
+
+export async function outputDoctor(
+  result: CResult<DoctorReport>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (result.data.summary.fail) {
+    // Lets scripts and CI gate on a healthy environment.
+    process.exitCode = 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { checks, summary } = result.data
+  const isMarkdown = outputKind === OUTPUT_MARKDOWN
+  logger.log(isMarkdown ? mdHeader('Socket doctor') : 'Socket doctor:')
+  for (const { 0: category, 1: title } of CATEGORY_TITLES) {
+    const categoryChecks = checks.filter(c => c.category === category)
+    if (!categoryChecks.length) {
+      continue
+    }
+    logger.log('')
+    logger.log(isMarkdown ? mdHeader(title, 2) : `${title}:`)
+    if (isMarkdown) {
+      logger.log('')
+    }
+    for (const check of categoryChecks) {
+      for (const line of getCheckLines(check)) {
+        logger.log(line)
+      }
+    }
+  }
+  logger.log('')
+  logger.log(
+    `${summary.ok} ok, ${summary.warn} warnings, ${summary.fail} failures, ${summary.skip} skipped`,
+  )
+}
@@ -0,0 +1,341 @@
+/**
+ * Explanations of single alert instances for `socket explain`.
+ *
+ * An alert is looked up in a scan by its key, the "Alert ID" shown by
+ * `socket scan report`. Its location is the file inside the package artifact
+ * and the character offsets the Socket API reports. When the package is
+ * installed in the local node_modules the offsets are resolved to a line and
+ * the source line is kept as a snippet. Why it matters and how to fix it come
+ * from the alert translations, with CVE props adding the patched version.
+ */
+
+import { existsSync, readFileSync } from 'node:fs'
+import path from 'node:path'
+
+import {
+  getAlertTranslation,
+  offsetToRegion,
+} from '../scan/generate-sarif-report.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+import {
+  getPkgFullNameFromPurl,
+  getSocketDevAlertUrl,
+  getSocketDevPackageFileUrl,
+  getSocketDevPackageOverviewUrlFromPurl,
+} from '../../util/socket/url.mts'
+
+import type { CResult } from '../../types.mts'
+import type {
+  SocketArtifact,
+  SocketArtifactAlert,
+} from '../../util/alert/artifact.mts'
+
+export type AlertLocation = {
+  file: string
+  start?: number | undefined
+  end?: number | undefined
+  // One-based, set when the file could be read locally.
+  line?: number | undefined
+  column?: number | undefined
+  snippet?: string | undefined
+  localPath?: string | undefined
+}
+
+export type AlertLink = {
+  label: string
+  url: string
+}
+
+export type AlertExplanation = {
+  alertId: string
+  type: string
+  title: string
+  emoji?: string | undefined
+  severity?: string | undefined
+  category?: string | undefined
+  action?: string | undefined
+  purl: string
+  manifest: string[]
+  description: string
+  // Props of this alert instance, e.g. the CVE id or env var names.
+  details: Array<{ name: string; value: string }>
+  location?: AlertLocation | undefined
+  remediation: string[]
+  links: AlertLink[]
+}
+
+export type FoundScanAlert = {
+  alert: SocketArtifactAlert
+  artifact: SocketArtifact
+}
+
+export type ExplainAlertOptions = {
+  // Directory whose node_modules is searched for the package files.
+  cwd?: string | undefined
+  scanId?: string | undefined
+}
+
+const MAX_SNIPPET_LENGTH = 160
+
+const MAX_DETAIL_LENGTH = 200
+
+// Props surfaced elsewhere in the explanation.
+const SKIPPED_DETAIL_PROPS = new Set([
+  'description',
+  'firstPatchedVersionIdentifier',
+  'title',
+  'url',
+])
+
+/**
+ * Find an alert in a scan by its ID. A prefix works when only one alert in the
+ * scan starts with it.
+ */
+export function findScanAlert(
+  artifacts: SocketArtifact[],
+  alertId: string,
+): CResult<FoundScanAlert> {
+  const matches: FoundScanAlert[] = []
+  for (const artifact of artifacts) {
+    for (const alert of artifact.alerts ?? []) {
+      if (!alert.key) {
+        continue
+      }
+      if (alert.key === alertId) {
+        return { ok: true, data: { alert, artifact } }
+      }
+      if (alert.key.startsWith(alertId)) {
+        matches.push({ alert, artifact })
+      }
+    }
+  }
+  if (matches.length === 1) {
+    return { ok: true, data: matches[0]! }
+  }
+  if (matches.length > 1) {
+    return {
+      ok: false,
+      message: 'Ambiguous alert ID',
+      cause: `${matches.length} alerts start with ${alertId}, pass more of the ID`,
+    }
+  }
+  return {
+    ok: false,
+    message: 'Alert not found',
+    cause: `The scan has no alert with ID ${alertId}; IDs are listed in the Alert ID column of \`socket scan report --markdown\``,
+  }
+}
+
+function formatDetailValue(value: unknown): string | undefined {
+  let formatted: string | undefined
+  if (
+    typeof value === 'string' ||
+    typeof value === 'number' ||
+    typeof value === 'boolean'
+  ) {
+    formatted = String(value)
+  } else if (
+    Array.isArray(value) &&
+    value.every(v => typeof v === 'string' || typeof v === 'number')
+  ) {
+    formatted = value.join(', ')
+  }
+  if (!formatted) {
+    return undefined
+  }
+  return formatted.length > MAX_DETAIL_LENGTH
+    ? `${formatted.slice(0, MAX_DETAIL_LENGTH - 1)}…`
+    : formatted
+}
+
+function getDetails(
+  props: Record<string, unknown> | undefined,
+): AlertExplanation['details'] {
+  const details: AlertExplanation['details'] = []
+  for (const { 0: name, 1: value } of Object.entries(props ?? {})) {
+    if (SKIPPED_DETAIL_PROPS.has(name)) {
+      continue
+    }
+    const formatted = formatDetailValue(value)
+    if (formatted !== undefined) {
+      details.push({ name, value: formatted })
+    }
+  }
+  return details
+}
+
+/**
+ * Where the package files of an npm artifact are installed under cwd, when
+ * the installed version is the scanned one.
+ */
+export function findInstalledPackageDir(
+  artifact: SocketArtifact,
+  cwd: string,
+): string | undefined {
+  if (artifact.type !== 'npm' || !artifact.name) {
+    return undefined
+  }
+  const pkgDir = path.join(
+    cwd,
+    'node_modules',
+    getPkgFullNameFromPurl(artifact),
+  )
+  try {
+    const pkgJson = JSON.parse(
+      readFileSync(path.join(pkgDir, 'package.json'), 'utf8'),
+    ) as { version?: unknown }
+    return pkgJson.version === artifact.version ? pkgDir : undefined
+  } catch {
+    return undefined
+  }
+}
+
+/**
+ * The file and line an alert points at. Offsets are resolved to a line only
+ * when the file can be read from the installed package.
+ */
+export function getAlertLocation(
+  artifact: SocketArtifact,
+  alert: SocketArtifactAlert,
+  cwd?: string | undefined,
+): AlertLocation | undefined {
+  const { end, file, start } = alert
+  if (!file) {
+    return undefined
+  }
+  const location: AlertLocation = {
+    file,
+    ...(typeof start === 'number' ? { start } : {}),
+    ...(typeof end === 'number' ? { end } : {}),
+  }
+  const pkgDir = cwd ? findInstalledPackageDir(artifact, cwd) : undefined
+  if (!pkgDir) {
+    return location
+  }
+  // Files are listed as in the published tarball, which puts them under
+  // `package/`.
+  const localPath = [
+    path.join(pkgDir, file),
+    path.join(pkgDir, file.replace(/^package\//, '')),
+  ].find(p => p.startsWith(pkgDir + path.sep) && existsSync(p))
+  if (!localPath) {
+    return location
+  }
+  location.localPath = localPath
+  if (typeof start !== 'number') {
+    return location
+  }
+  let content: string
+  try {
+    content = readFileSync(localPath, 'utf8')
+  } catch {
+    return location
+  }
+  const { startColumn, startLine } = offsetToRegion(content, start)
+  const lineText = (content.split(/\r?\n/)[startLine - 1] ?? '').trim()
+  location.line = startLine
+  location.column = startColumn
+  if (lineText) {
+    location.snippet =
+      lineText.length > MAX_SNIPPET_LENGTH
+        ? `${lineText.slice(0, MAX_SNIPPET_LENGTH - 1)}…`
+        : lineText
+  }
+  return location
+}
+
+function getRemediation(
+  artifact: SocketArtifact,
+  alert: SocketArtifactAlert,
+  suggestion: string | undefined,
+  scanId: string | undefined,
+): string[] {
+  const remediation: string[] = []
+  const pkgName = artifact.name ? getPkgFullNameFromPurl(artifact) : ''
+  const patched = alert.props?.['firstPatchedVersionIdentifier']
+  if (typeof patched === 'string' && patched) {
+    remediation.push(
+      `Upgrade ${pkgName} to ${patched} or later, \`socket fix\` can open the upgrade for you`,
+    )
+  }
+  if (suggestion) {
+    remediation.push(suggestion)
+  }
+  if (artifact.direct === false) {
+    remediation.push(
+      `${pkgName} is a transitive dependency; upgrading or replacing the direct dependency that pulls it in also removes it, see \`socket why ${pkgName}@${artifact.version}\``,
+    )
+  }
+  remediation.push(
+    `If the risk is acceptable, triage the alert with \`socket scan view ${scanId || '<SCAN_ID>'} --interactive\` or add an exception for ${alert.type} to socket.policy.yml`,
+  )
+  return remediation
+}
+
+/**
+ * The human-readable explanation of one alert on one artifact.
+ */
+export function explainAlert(
+  artifact: SocketArtifact,
+  alert: SocketArtifactAlert,
+  options?: ExplainAlertOptions | undefined,
+): AlertExplanation {
+  const { cwd, scanId } = {
+    __proto__: null,
+    ...options,
+  } as ExplainAlertOptions
+  const translation = getAlertTranslation(alert.type)
+  const props = alert.props
+  const purl = getArtifactPurlString(artifact)
+  const location = getAlertLocation(artifact, alert, cwd)
+
+  const links: AlertLink[] = [
+    { label: 'Alert type', url: getSocketDevAlertUrl(alert.type) },
+    {
+      label: 'Package',
+      url: getSocketDevPackageOverviewUrlFromPurl(artifact),
+    },
+  ]
+  if (location) {
+    links.push({
+      label: 'File',
+      url: getSocketDevPackageFileUrl(artifact, location.file),
+    })
+  }
+  const advisoryUrl = props?.['url']
+  if (typeof advisoryUrl === 'string' && advisoryUrl) {
+    links.push({ label: 'Advisory', url: advisoryUrl })
+  }
+
+  const propsDescription = props?.['description']
+  return {
+    alertId: alert.key,
+    type: alert.type,
+    title:
+      (typeof props?.['title'] === 'string' ? props['title'] : '') ||
+      translation.title ||
+      alert.type,
+    ...(translation.emoji ? { emoji: translation.emoji } : {}),
+    ...(alert.severity ? { severity: alert.severity } : {}),
+    ...(alert.category ? { category: alert.category } : {}),
+    ...(alert.action ? { action: alert.action } : {}),
+    purl,
+    manifest: artifact.manifestFiles?.map(o => o.file) ?? [],
+    description:
+      [
+        translation.description,
+        typeof propsDescription === 'string' ? propsDescription : '',
+      ]
+        .filter(Boolean)
+        .join('\n\n') || 'No description is available for this alert type.',
+    details: getDetails(props),
+    ...(location ? { location } : {}),
+    remediation: getRemediation(
+      artifact,
+      alert,
+      translation.suggestion,
+      scanId,
+    ),
+    links,
+  }
+}
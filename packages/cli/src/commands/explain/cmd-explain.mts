@@ -0,0 +1,158 @@
+import { handleExplain } from './handle-explain.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { readCompletionHistory } from '../../util/cli/completion-history.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'explain'
+
+const description = 'Explain an alert of a scan in detail'
+
+const hidden = false
+
+export const cmdExplain = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      scan: {
+        type: 'string',
+        default: '',
+        description:
+          'ID of the scan with the alert, defaults to the scan created or listed last on this machine',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <ALERT_ID>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Prints what a single alert of a scan is about: the file and line inside
+    the package that triggered it, why it matters, how to remediate it and
+    where to read more. Alert IDs are shown in the Alert ID column of
+    \`socket scan report --markdown\`; a unique prefix of an ID is enough.
+
+    The line is only known when the package is installed in the local
+    node_modules at the scanned version, otherwise the file and character
+    offsets reported by Socket are shown.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} QmWjY2Vz --scan 000aaaa1-0000-0a0a-00a0-00a0000000a0
+      $ ${command} QmWjY2Vz --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, interactive, json, markdown, org: orgFlag } = cli.flags
+
+  const [alertId = ''] = cli.input
+
+  const scanId = cli.flags['scan'] || (await readCompletionHistory()).scan[0]
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(orgFlag, interactive, dryRun)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!alertId,
+      message: 'Alert ID to explain',
+      fail: 'missing',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID by --scan, or a scan created or listed before',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput || !scanId) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scan to explain the alert', {
+      organization: orgSlug,
+      scanId,
+      alertId,
+    })
+    return
+  }
+
+  await handleExplain({
+    alertId,
+    cwd: process.cwd(),
+    orgSlug,
+    outputKind,
+    scanId,
+  })
+}
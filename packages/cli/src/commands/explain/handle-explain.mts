@@ -0,0 +1,39 @@
+import { explainAlert, findScanAlert } from './alert-explanation.mts'
+import { outputExplain } from './output-explain.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export type HandleExplainConfig = {
+  alertId: string
+  cwd: string
+  orgSlug: string
+  outputKind: OutputKind
+  scanId: string
+}
+
+export async function handleExplain({
+  alertId,
+  cwd,
+  orgSlug,
+  outputKind,
+  scanId,
+}: HandleExplainConfig): Promise<void> {
+  const scanCResult = await fetchScan(orgSlug, scanId)
+  if (!scanCResult.ok) {
+    await outputExplain(scanCResult, outputKind)
+    return
+  }
+
+  const foundCResult = findScanAlert(scanCResult.data, alertId)
+  if (!foundCResult.ok) {
+    await outputExplain(foundCResult, outputKind)
+    return
+  }
+
+  const { alert, artifact } = foundCResult.data
+  await outputExplain(
+    { ok: true, data: explainAlert(artifact, alert, { cwd, scanId }) },
+    outputKind,
+  )
+}
@@ -0,0 +1,135 @@
+import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import colors from 'yoctocolors-cjs'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { AlertExplanation, AlertLocation } from './alert-explanation.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function formatLocation(location: AlertLocation): string {
+  const { end, file, line, start } = location
+  if (line !== undefined) {
+    return `${file}:${line}:${location.column}`
+  }
+  if (start !== undefined) {
+    return `${file} (offset ${start}${end !== undefined ? `-${end}` : ''})`
+  }
+  return file
+}
+
+function getSummary(data: AlertExplanation): Array<[string, string]> {
+  const summary: Array<[string, string]> = [
+    ['Alert ID', data.alertId],
+    ['Type', data.type],
+    ['Package', data.purl],
+  ]
+  if (data.severity) {
+    summary.push(['Severity', data.severity])
+  }
+  if (data.category) {
+    summary.push(['Category', data.category])
+  }
+  if (data.action) {
+    summary.push(['Policy', data.action])
+  }
+  if (data.manifest.length) {
+    summary.push(['Introduced by', joinAnd(data.manifest)])
+  }
+  if (data.location) {
+    summary.push(['Location', formatLocation(data.location)])
+  }
+  return summary
+}
+
+export function formatExplanationMarkdown(data: AlertExplanation): string {
+  const lines = [
+    mdHeader(`${data.emoji ? `${data.emoji} ` : ''}${data.title}`),
+    '',
+    ...getSummary(data).map(
+      ({ 0: label, 1: value }) => `- ${mdKeyValue(label, value)}`,
+    ),
+  ]
+  if (data.location?.snippet) {
+    lines.push('', '```', data.location.snippet, '```')
+  }
+  lines.push('', mdHeader('Why it matters', 2), '', data.description)
+  if (data.details.length) {
+    lines.push(
+      '',
+      mdHeader('Details', 2),
+      '',
+      mdList(data.details.map(d => `${d.name}: ${d.value}`)),
+    )
+  }
+  lines.push(
+    '',
+    mdHeader('Remediation', 2),
+    '',
+    mdList(data.remediation),
+    '',
+    mdHeader('Links', 2),
+    '',
+    mdList(data.links.map(l => `[${l.label}](${l.url})`)),
+  )
+  return lines.join('\n')
+}
+
+export function formatExplanationText(data: AlertExplanation): string {
+  const lines = [
+    colors.bold(`${data.emoji ? `${data.emoji} ` : ''}${data.title}`),
+    '',
+    ...getSummary(data).map(
+      ({ 0: label, 1: value }) => `${colors.dim(`${label}:`)} ${value}`,
+    ),
+  ]
+  if (data.location?.snippet) {
+    lines.push('', `  ${colors.cyan(data.location.snippet)}`)
+  }
+  lines.push('', colors.bold('Why it matters'), data.description)
+  if (data.details.length) {
+    lines.push(
+      '',
+      colors.bold('Details'),
+      ...data.details.map(d => `- ${d.name}: ${d.value}`),
+    )
+  }
+  lines.push(
+    '',
+    colors.bold('Remediation'),
+    ...data.remediation.map(r => `- ${r}`),
+    '',
+    colors.bold('Links'),
+    ...data.links.map(l => `- ${l.label}: ${l.url}`),
+  )
+  return lines.join('\n')
+}
+
+export async function outputExplain(
+  result: CResult<AlertExplanation>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(formatExplanationMarkdown(result.data))
+    return
+  }
+  logger.log(formatExplanationText(result.data))
+}
@@ -140,6 +140,23 @@ Available styles:
     default: false,
     description: 'Silence all output except the final result',
   },
+  test: {
+    type: 'boolean',
+    default: false,
+    description:
+      'Verify the fix by running the test script after installing. Implies --verify.',
+  },
+  testScript: {
+    type: 'string',
+    default: 'test',
+    description: "The test script to run for fix attempts (default 'test')",
+  },
+  verify: {
+    type: 'boolean',
+    default: false,
+    description:
+      'Verify fixes by running the package manager install after applying them. In CI mode, fixes that fail verification are not opened as pull requests.',
+  },
 }
 
 export const hiddenFlags: MeowFlags = {
@@ -181,16 +198,4 @@ export const hiddenFlags: MeowFlags = {
     shortFlag: 'p',
     hidden: true,
   },
-  test: {
-    type: 'boolean',
-    default: false,
-    description: 'Verify the fix by running unit tests',
-    hidden: true,
-  },
-  testScript: {
-    type: 'string',
-    default: 'test',
-    description: "The test script to run for fix attempts (default 'test')",
-    hidden: true,
-  },
 }
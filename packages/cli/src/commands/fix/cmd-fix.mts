@@ -13,7 +13,11 @@ import { handleFix } from './handle-fix.mts'
 import { FLAG_ID } from '../../constants/cli.mts'
 import { ERROR_UNABLE_RESOLVE_ORG } from '../../constants/errors.mts'
 import { defineFlags } from '../../meow.mts'
-import { commonFlags, outputFlags } from '../../flags.mts'
+import { cacheFlags, commonFlags, outputFlags } from '../../flags.mts'
+import {
+  parseCacheDuration,
+  setCacheOverrides,
+} from '../../util/cache/overrides.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
 import { outputDryRunPreview } from '../../util/dry-run/output.mts'
 import { getEcosystemChoicesForMeow } from '../../util/ecosystem/types.mts'
@@ -40,6 +44,8 @@ export interface FixFlags {
   all: boolean
   applyFixes: boolean
   autopilot: boolean
+  cache: boolean
+  cacheTtl: string
   debug: boolean
   disableExternalToolChecks: boolean
   ecosystems: string[]
@@ -58,7 +64,10 @@ export interface FixFlags {
   rangeStyle: RangeStyle
   showAffectedDirectDependencies: boolean
   silence: boolean
+  test: boolean
+  testScript: string
   unknownFlags?: string[] | undefined
+  verify: boolean
 }
 
 export const CMD_NAME = 'fix'
@@ -85,6 +94,7 @@ export async function run(
     flags: defineFlags({
       ...commonFlags,
       ...outputFlags,
+      ...cacheFlags,
       ...generalFlags,
       ...hiddenFlags,
     }),
@@ -139,6 +149,8 @@ export async function run(
     all,
     applyFixes,
     autopilot,
+    cache,
+    cacheTtl,
     debug,
     disableExternalToolChecks,
     ecosystems,
@@ -156,11 +168,16 @@ export async function run(
     rangeStyle,
     showAffectedDirectDependencies,
     silence,
+    test,
+    testScript,
     // We patched in this feature with `npx custompatch meow` at
     // socket-cli/patches/meow#13.2.0.patch.
     unknownFlags = [],
   } = cli.flags as unknown as FixFlags
 
+  // Running the tests needs the install first.
+  const verify = (cli.flags as unknown as FixFlags).verify || test
+
   const dryRun = cli.flags['dryRun']
 
   const minSatisfying =
@@ -170,6 +187,8 @@ export async function run(
 
   const outputKind = getOutputKind(json, markdown)
 
+  const cacheTtlMs = parseCacheDuration(cacheTtl)
+
   // Process comma-separated values for ecosystems flag.
   const ecosystemsRaw = cmdFlagValueToArray(ecosystems)
 
@@ -213,11 +232,20 @@ export async function run(
       message: 'The --all and --id flags cannot be used together',
       fail: 'omit one',
     },
+    {
+      nook: true,
+      test: !cacheTtl || cacheTtlMs !== undefined,
+      message: 'The --cache-ttl flag expects a duration like 30m, 12h or 7d',
+      fail: 'bad',
+    },
   )
   if (!wasValidInput) {
     return
   }
 
+  // Covers the GitHub API lookups of the PR flow.
+  setCacheOverrides({ disabled: !cache, ttlMs: cacheTtlMs })
+
   // Detect the common mistake of passing a vulnerability ID (GHSA / CVE /
   // PURL) as a positional argument when the user meant to use `--id`.
   // Without this guard we treat the ID as a directory path, resolve to cwd,
@@ -285,8 +313,8 @@ export async function run(
   if (dryRun) {
     const actions: DryRunAction[] = [
       {
-        type: 'fetch',
-        description: 'Scan project dependencies for vulnerabilities',
+        type: 'upload',
+        description: 'Upload manifest files to scan for vulnerabilities',
         target: cwd,
         details: {
           organization: orgSlug,
@@ -320,6 +348,13 @@ export async function run(
         type: 'execute',
         description: 'Run package manager to install updated dependencies',
       })
+      if (test) {
+        actions.push({
+          type: 'execute',
+          description: 'Run the test script to verify the fixes',
+          target: testScript,
+        })
+      }
     }
 
     const targetDescription = all
@@ -364,6 +399,9 @@ export async function run(
     showAffectedDirectDependencies,
     silence,
     spinner,
+    test,
+    testScript,
     unknownFlags,
+    verify,
   })
 }
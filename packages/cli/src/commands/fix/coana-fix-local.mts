@@ -7,10 +7,14 @@ import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
 import { checkCiEnvVars, getCiEnvInstructions } from './env-helpers.mts'
+import { getFixChangelog } from './fix-changelog.mts'
+import { verifyFix } from './verify-fix.mts'
 import { FLAG_DRY_RUN } from '../../constants/cli.mts'
 import { spawnCoanaDlx } from '../../util/dlx/spawn.mjs'
+import { gitUnstagedModifiedFiles } from '../../util/git/operations.mjs'
 
 import type { GhsaFixResult } from './coana-fix-ci.mts'
+import type { FixVersionChange } from './fix-changelog.mts'
 import type { FixConfig } from './types.mts'
 import type { CResult } from '../../types.mts'
 const logger = getDefaultLogger()
@@ -23,7 +27,13 @@ export async function runLocalCoanaFix(
     shouldDiscoverGhsaIds: boolean
     tarHash: string
   },
-): Promise<CResult<{ fixedAll: boolean; ghsaDetails: GhsaFixResult[] }>> {
+): Promise<
+  CResult<{
+    changes?: FixVersionChange[] | undefined
+    fixedAll: boolean
+    ghsaDetails: GhsaFixResult[]
+  }>
+> {
   const {
     all,
     applyFixes,
@@ -41,6 +51,9 @@ export async function runLocalCoanaFix(
     prLimit,
     showAffectedDirectDependencies,
     spinner,
+    test,
+    testScript,
+    verify,
   } = fixConfig
   const { coanaSilenceArgs, coanaStdio, shouldDiscoverGhsaIds, tarHash } =
     context
@@ -134,9 +147,31 @@ export async function runLocalCoanaFix(
       await fs.writeFile(outputFile, tmpContent, 'utf8')
     }
 
+    if (applyFixes && verify) {
+      const verifyCResult = await verifyFix(cwd, {
+        spinner,
+        stdio: coanaStdio,
+        test,
+        testScript,
+      })
+      if (!verifyCResult.ok) {
+        return verifyCResult
+      }
+    }
+
+    // The changelog compares the working tree to HEAD, so it needs a git
+    // work tree and is empty without one.
+    const unstagedCResult = applyFixes
+      ? await gitUnstagedModifiedFiles(cwd)
+      : undefined
+    const changes = unstagedCResult?.ok
+      ? await getFixChangelog(cwd, unstagedCResult.data)
+      : []
+
     return {
       ok: true,
       data: {
+        changes,
         fixedAll: true,
         ghsaDetails: idsToProcess.map(id => ({
           ghsaId: id,
@@ -9,10 +9,12 @@ import {
   cleanupStaleBranch,
   cleanupSuccessfulPrLocalBranch,
 } from './branch-cleanup.mts'
+import { getFixChangelog } from './fix-changelog.mts'
 import { markGhsaFixed } from './ghsa-tracker.mts'
 import { getSocketFixBranchName, getSocketFixCommitMessage } from './git.mts'
 import { logPrEvent } from './pr-lifecycle-logger.mts'
 import { getSocketFixPrs, openSocketFixPr } from './pull-request.mts'
+import { verifyFix } from './verify-fix.mts'
 import { GQL_PR_STATE_OPEN } from '../../constants/github.mts'
 import { getErrorCause } from '../../util/error/errors.mjs'
 import {
@@ -77,6 +79,9 @@ export async function runGhsaFixLoop(
     minimumReleaseAge,
     showAffectedDirectDependencies,
     spinner,
+    test,
+    testScript,
+    verify,
   } = fixConfig
   const {
     adjustedLimit,
@@ -100,6 +105,15 @@ export async function runGhsaFixLoop(
   let overallFixed = false
   const ghsaFixResults: GhsaFixResult[] = []
 
+  const getModifiedFiles = async () => {
+    const unstagedCResult = await gitUnstagedModifiedFiles(cwd)
+    return unstagedCResult.ok
+      ? unstagedCResult.data.filter(relPath =>
+          scanBaseNames.has(path.basename(relPath)),
+        )
+      : []
+  }
+
   // Process each GHSA ID individually.
   // Use unprocessedIds instead of ids to skip already-fixed GHSAs.
   for (let i = 0, { length } = unprocessedIds; i < length; i += 1) {
@@ -145,20 +159,39 @@ export async function runGhsaFixLoop(
     }
 
     // Check for modified files after applying the fix.
-    const unstagedCResult = await gitUnstagedModifiedFiles(cwd)
-    const modifiedFiles = unstagedCResult.ok
-      ? unstagedCResult.data.filter(relPath =>
-          scanBaseNames.has(path.basename(relPath)),
-        )
-      : []
+    let modifiedFiles = await getModifiedFiles()
 
     if (!modifiedFiles.length) {
       debug(`skip: no changes for ${ghsaId}`)
       continue
     }
 
+    if (verify) {
+      const verifyCResult = await verifyFix(cwd, {
+        spinner,
+        stdio: coanaStdio,
+        test,
+        testScript,
+      })
+      if (!verifyCResult.ok) {
+        logger.error(
+          `${verifyCResult.message} for ${ghsaId}, skipping PR creation.`,
+        )
+        if (verifyCResult.cause) {
+          logger.error(verifyCResult.cause)
+        }
+        await gitResetAndClean(fixEnv.baseBranch, cwd)
+        continue
+      }
+      // The install may have rewritten the lockfile.
+      modifiedFiles = await getModifiedFiles()
+    }
+
     overallFixed = true
 
+    // Read before committing, while HEAD is still the base branch.
+    const changes = await getFixChangelog(cwd, modifiedFiles)
+
     const branch = getSocketFixBranchName(ghsaId)
 
     try {
@@ -297,6 +330,7 @@ export async function runGhsaFixLoop(
         [ghsaId],
         {
           baseBranch: fixEnv.baseBranch,
+          changes,
           cwd,
           ghsaDetails,
         },
@@ -12,6 +12,7 @@ import { handleApiCall } from '../../util/socket/api.mjs'
 import { setupSdk } from '../../util/socket/sdk.mjs'
 import { fetchSupportedScanFileNames } from '../scan/fetch-supported-scan-file-names.mts'
 
+import type { FixVersionChange } from './fix-changelog.mts'
 import type { FixConfig } from './types.mts'
 import type { CResult } from '../../types.mts'
 import type { GhsaFixResult } from './coana-fix-ci.mts'
@@ -20,7 +21,13 @@ export type { GhsaFixResult } from './coana-fix-ci.mts'
 
 export async function coanaFix(
   fixConfig: FixConfig,
-): Promise<CResult<{ fixedAll: boolean; ghsaDetails: GhsaFixResult[] }>> {
+): Promise<
+  CResult<{
+    changes?: FixVersionChange[] | undefined
+    fixedAll: boolean
+    ghsaDetails: GhsaFixResult[]
+  }>
+> {
   const { all, cwd, ghsas, orgSlug, outputKind, spinner } = fixConfig
 
   // Under json/markdown mode we route coana's chatter away from our
@@ -0,0 +1,224 @@
+/**
+ * Changelog of a fix: which dependency versions changed in the files a fix
+ * touched. Each file is read at HEAD and in the working tree, so the
+ * changelog describes the edits actually made rather than what was planned.
+ *
+ * package.json files contribute their declared ranges; lockfiles contribute
+ * resolved versions. package-lock.json and Yarn v1 lockfiles are read here,
+ * other lockfiles (including Yarn Berry) through the local lockfile parsers.
+ */
+
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import {
+  PACKAGE_JSON,
+  PACKAGE_LOCK_JSON,
+  YARN_LOCK,
+} from '../../constants/packages.mts'
+import { gitShowFile } from '../../util/git/operations.mjs'
+import { parseYarnLockV1 } from '../../util/lockfile/bun.mts'
+import { getLockfileParser } from '../../util/lockfile/parsers.mts'
+import { isYarnBerryLockfile } from '../../util/lockfile/yarn.mts'
+
+export type FixVersionChange = {
+  // Path relative to the fix cwd.
+  file: string
+  // Versions or ranges before the fix; empty when the package was added.
+  from: string[]
+  name: string
+  // Versions or ranges after the fix; empty when the package was removed.
+  to: string[]
+}
+
+const NPM_SHRINKWRAP_JSON = 'npm-shrinkwrap.json'
+
+const PACKAGE_JSON_SECTIONS = [
+  'dependencies',
+  'devDependencies',
+  'optionalDependencies',
+  'peerDependencies',
+  'overrides',
+  'resolutions',
+]
+
+type VersionMap = Map<string, Set<string>>
+
+function addVersion(versions: VersionMap, name: string, version: string) {
+  const set = versions.get(name) ?? new Set<string>()
+  set.add(version)
+  versions.set(name, set)
+}
+
+function addStringEntries(
+  versions: VersionMap,
+  value: unknown,
+  // Prefixes nested override keys, e.g. `parent>child`.
+  prefix = '',
+): void {
+  if (!value || typeof value !== 'object') {
+    return
+  }
+  for (const { 0: key, 1: entry } of Object.entries(value)) {
+    if (typeof entry === 'string') {
+      addVersion(versions, `${prefix}${key}`, entry)
+    } else if (entry && typeof entry === 'object') {
+      addStringEntries(versions, entry, `${prefix}${key}>`)
+    }
+  }
+}
+
+function parseJson(content: string): Record<string, unknown> | undefined {
+  try {
+    const parsed = JSON.parse(content)
+    return parsed && typeof parsed === 'object' ? parsed : undefined
+  } catch {}
+  return undefined
+}
+
+/**
+ * Package names mapped to their versions in one manifest or lockfile, or
+ * undefined when the file is not understood.
+ */
+export function getDependencyVersions(
+  filepath: string,
+  content: string,
+): VersionMap | undefined {
+  const basename = path.basename(filepath)
+  const versions: VersionMap = new Map()
+  if (basename === PACKAGE_JSON) {
+    const pkgJson = parseJson(content)
+    if (!pkgJson) {
+      return undefined
+    }
+    for (const section of PACKAGE_JSON_SECTIONS) {
+      addStringEntries(versions, pkgJson[section])
+    }
+    const pnpmConfig = pkgJson['pnpm'] as Record<string, unknown> | undefined
+    addStringEntries(versions, pnpmConfig?.['overrides'])
+    return versions
+  }
+  if (basename === PACKAGE_LOCK_JSON || basename === NPM_SHRINKWRAP_JSON) {
+    const packages = parseJson(content)?.['packages']
+    if (!packages || typeof packages !== 'object') {
+      return undefined
+    }
+    for (const { 0: key, 1: entry } of Object.entries(packages)) {
+      const { version } = (entry ?? {}) as { version?: unknown }
+      const index = key.lastIndexOf('node_modules/')
+      if (index !== -1 && typeof version === 'string') {
+        const name = key.slice(index + 'node_modules/'.length)
+        addVersion(versions, name, version)
+      }
+    }
+    return versions
+  }
+  if (basename === YARN_LOCK && !isYarnBerryLockfile(content)) {
+    for (const entry of parseYarnLockV1(content).values()) {
+      addVersion(versions, entry.name, entry.version)
+    }
+    return versions
+  }
+  const parser = getLockfileParser(filepath)
+  const parsed = parser?.parse(content, {
+    filepath,
+    readFile: () => undefined,
+  })
+  if (!parsed) {
+    return undefined
+  }
+  for (const dep of parsed.dependencies) {
+    addVersion(
+      versions,
+      dep.namespace ? `${dep.namespace}/${dep.name}` : dep.name,
+      dep.version,
+    )
+  }
+  return versions
+}
+
+/**
+ * The packages whose versions differ between two contents of a file.
+ */
+export function diffDependencyVersions(
+  file: string,
+  before: VersionMap,
+  after: VersionMap,
+): FixVersionChange[] {
+  const changes: FixVersionChange[] = []
+  const names = [...new Set([...before.keys(), ...after.keys()])].sort()
+  for (let i = 0, { length } = names; i < length; i += 1) {
+    const name = names[i]!
+    const from = [...(before.get(name) ?? [])].sort()
+    const to = [...(after.get(name) ?? [])].sort()
+    if (from.join('\n') !== to.join('\n')) {
+      changes.push({ file, from, name, to })
+    }
+  }
+  return changes
+}
+
+/**
+ * Changelog of the modified tracked files of a git working tree, compared to
+ * HEAD. Files that cannot be read or parsed are skipped.
+ */
+export async function getFixChangelog(
+  cwd: string,
+  modifiedFiles: string[],
+): Promise<FixVersionChange[]> {
+  const changes: FixVersionChange[] = []
+  for (let i = 0, { length } = modifiedFiles; i < length; i += 1) {
+    const file = modifiedFiles[i]!
+    let current: string
+    try {
+      current = await fs.readFile(path.join(cwd, file), 'utf8')
+    } catch {
+      continue
+    }
+    const previous = await gitShowFile(file, 'HEAD', cwd)
+    if (previous === undefined) {
+      continue
+    }
+    const before = getDependencyVersions(file, previous)
+    const after = getDependencyVersions(file, current)
+    if (before && after) {
+      changes.push(...diffDependencyVersions(file, before, after))
+    }
+  }
+  return changes
+}
+
+function formatVersions(versions: string[]): string {
+  return versions.length ? versions.join(', ') : '(none)'
+}
+
+/**
+ * One line per change, for terminal output.
+ */
+export function formatFixChangelog(changes: FixVersionChange[]): string[] {
+  return changes.map(
+    ({ file, from, name, to }) =>
+      `${name}: ${formatVersions(from)} → ${formatVersions(to)} (${file})`,
+  )
+}
+
+// Ranges like `^1.0.0 || ^2.0.0` would split a table cell.
+function escapeCell(value: string): string {
+  return value.replaceAll('|', '\\|')
+}
+
+/**
+ * GitHub flavored Markdown table of the changes, for pull request bodies.
+ */
+export function formatFixChangelogMarkdown(
+  changes: FixVersionChange[],
+): string {
+  return [
+    '| Package | From | To | File |',
+    '| --- | --- | --- | --- |',
+    ...changes.map(
+      ({ file, from, name, to }) =>
+        `| \`${name}\` | ${escapeCell(formatVersions(from))} | ${escapeCell(formatVersions(to))} | ${file} |`,
+    ),
+  ].join('\n')
+}
@@ -1,7 +1,9 @@
 import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
 
+import { formatFixChangelogMarkdown } from './fix-changelog.mts'
 import { SOCKET_WEBSITE_URL } from '../../constants/socket.mts'
 
+import type { FixVersionChange } from './fix-changelog.mts'
 import type { GhsaDetails } from '../../util/git/github.mts'
 
 const GITHUB_ADVISORIES_URL = 'https://github.com/advisories'
@@ -34,6 +36,24 @@ export function getSocketFixCommitMessage(
 export function getSocketFixPullRequestBody(
   ghsaIds: string[],
   ghsaDetails?: Map<string, GhsaDetails> | undefined,
+  changes?: FixVersionChange[] | undefined,
+): string {
+  const body = getSocketFixPullRequestSummary(ghsaIds, ghsaDetails)
+  if (!changes?.length) {
+    return body
+  }
+  return [
+    body,
+    '',
+    '**Changes:**',
+    '',
+    formatFixChangelogMarkdown(changes),
+  ].join('\n')
+}
+
+function getSocketFixPullRequestSummary(
+  ghsaIds: string[],
+  ghsaDetails?: Map<string, GhsaDetails> | undefined,
 ): string {
   const vulnCount = ghsaIds.length
   const firstGhsa = ghsaIds[0]
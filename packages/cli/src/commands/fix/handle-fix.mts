@@ -128,7 +128,10 @@ export async function handleFix({
   showAffectedDirectDependencies,
   silence,
   spinner,
+  test,
+  testScript,
   unknownFlags,
+  verify,
 }: HandleFixConfig) {
   debug(`Starting fix command for ${orgSlug}`)
   debugDir({
@@ -152,7 +155,10 @@ export async function handleFix({
     prLimit,
     rangeStyle,
     showAffectedDirectDependencies,
+    test,
+    testScript,
     unknownFlags,
+    verify,
   })
 
   await outputFixResult(
@@ -181,7 +187,10 @@ export async function handleFix({
       showAffectedDirectDependencies,
       silence,
       spinner,
+      test,
+      testScript,
       unknownFlags,
+      verify,
     }),
     outputKind,
   )
@@ -1,12 +1,22 @@
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
+import {
+  formatFixChangelog,
+  formatFixChangelogMarkdown,
+} from './fix-changelog.mts'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
 import { mdError, mdHeader } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 
+import type { FixVersionChange } from './fix-changelog.mts'
 import type { CResult, OutputKind } from '../../types.mts'
 const logger = getDefaultLogger()
 
+function getFixChanges(data: unknown): FixVersionChange[] {
+  const changes = (data as { changes?: unknown } | undefined)?.changes
+  return Array.isArray(changes) ? changes : []
+}
+
 export async function outputFixResult(
   result: CResult<unknown>,
   outputKind: OutputKind,
@@ -26,6 +36,11 @@ export async function outputFixResult(
     } else {
       logger.log(mdHeader('Fix Completed'))
       logger.log('')
+      const changes = getFixChanges(result.data)
+      if (changes.length) {
+        logger.log(formatFixChangelogMarkdown(changes))
+        logger.log('')
+      }
       logger.success('Finished!')
     }
     return
@@ -36,6 +51,15 @@ export async function outputFixResult(
     return
   }
 
+  const changes = getFixChanges(result.data)
+  if (changes.length) {
+    logger.log('')
+    logger.log('Dependency changes:')
+    for (const line of formatFixChangelog(changes)) {
+      logger.log(`  ${line}`)
+    }
+  }
+
   logger.log('')
   logger.success('Finished!')
 }
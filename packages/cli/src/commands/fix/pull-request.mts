@@ -29,6 +29,7 @@ import {
 import type { GhsaDetails, Pr } from '../../util/git/github.mts'
 import { createPrProvider } from '../../util/git/provider-factory.mts'
 
+import type { FixVersionChange } from './fix-changelog.mts'
 import type { OctokitResponse } from '@octokit/types'
 import type { JsonContent } from '@socketsecurity/lib-stable/fs/types'
 
@@ -341,6 +342,8 @@ export async function getSocketFixPrsWithContext(
 
 export type OpenSocketFixPrOptions = {
   baseBranch?: string | undefined
+  // Dependency changes listed in the pull request body.
+  changes?: FixVersionChange[] | undefined
   cwd?: string | undefined
   ghsaDetails?: Map<string, GhsaDetails> | undefined
   retries?: number | undefined
@@ -368,6 +371,7 @@ export async function openSocketFixPr(
 ): Promise<OpenPrResult> {
   const {
     baseBranch = 'main',
+    changes,
     ghsaDetails,
     retries = 3,
   } = {
@@ -384,7 +388,7 @@ export async function openSocketFixPr(
       title: getSocketFixPullRequestTitle(ghsaIds),
       head: branch,
       base: baseBranch,
-      body: getSocketFixPullRequestBody(ghsaIds, ghsaDetails),
+      body: getSocketFixPullRequestBody(ghsaIds, ghsaDetails, changes),
       retries,
     })
 
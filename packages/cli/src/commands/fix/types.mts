@@ -27,5 +27,8 @@ export type FixConfig = {
   showAffectedDirectDependencies: boolean
   silence: boolean
   spinner: SpinnerInstance | undefined
+  test: boolean
+  testScript: string
   unknownFlags: string[]
+  verify: boolean
 }
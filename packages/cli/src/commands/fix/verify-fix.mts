@@ -0,0 +1,104 @@
+/**
+ * Verification of applied fixes: the package manager installs the edited
+ * manifests and lockfile, and optionally the test script passes. Projects
+ * without a package.json have nothing to install and are skipped.
+ */
+
+import { existsSync } from 'node:fs'
+import path from 'node:path'
+
+import { WIN32 } from '@socketsecurity/lib-stable/constants/platform'
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import { PACKAGE_JSON } from '../../constants/packages.mts'
+import { detectAndValidatePackageEnvironment } from '../../util/ecosystem/environment.mjs'
+import { cmdPrefixMessage } from '../../util/process/cmd.mts'
+import { runAgentInstall } from '../optimize/agent-installer.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SpinnerInstance } from '@socketsecurity/lib-stable/spinner/types'
+
+export type VerifyFixOptions = {
+  spinner?: SpinnerInstance | undefined
+  stdio?: 'ignore' | 'inherit' | undefined
+  test?: boolean | undefined
+  testScript?: string | undefined
+}
+
+const CMD_NAME = 'socket fix'
+
+/**
+ * Install the dependencies of cwd, then run the test script when asked.
+ * Resolves with whether anything was verified.
+ */
+export async function verifyFix(
+  cwd: string,
+  options?: VerifyFixOptions | undefined,
+): Promise<CResult<boolean>> {
+  const {
+    spinner,
+    stdio = 'ignore',
+    test = false,
+    testScript = 'test',
+  } = { __proto__: null, ...options } as VerifyFixOptions
+
+  if (!existsSync(path.join(cwd, PACKAGE_JSON))) {
+    debug(`skip: no ${PACKAGE_JSON} in ${cwd} to verify`)
+    return { ok: true, data: false }
+  }
+
+  const pkgEnvCResult = await detectAndValidatePackageEnvironment(cwd, {
+    cmdName: CMD_NAME,
+  })
+  if (!pkgEnvCResult.ok) {
+    return pkgEnvCResult
+  }
+
+  const pkgEnvDetails = pkgEnvCResult.data
+  const { agent, agentExecPath, pkgPath } = pkgEnvDetails
+
+  spinner?.start(`Verifying the fix with ${agent} install…`)
+  try {
+    await runAgentInstall(pkgEnvDetails, { spinner, stdio })
+  } catch (e) {
+    spinner?.stop()
+    debug('Install verification failed')
+    debugDir(e)
+    return {
+      ok: false,
+      message: 'Install verification failed',
+      cause: cmdPrefixMessage(
+        CMD_NAME,
+        `${agent} install failed after applying the fix. Run '${agent} install' manually to see detailed error information.`,
+      ),
+    }
+  }
+
+  if (test) {
+    spinner?.start(`Verifying the fix with ${agent} run ${testScript}…`)
+    try {
+      await spawn(agentExecPath, ['run', testScript], {
+        cwd: pkgPath,
+        shell: WIN32,
+        spinner,
+        stdio,
+      })
+    } catch (e) {
+      spinner?.stop()
+      debug('Test verification failed')
+      debugDir(e)
+      return {
+        ok: false,
+        message: 'Test verification failed',
+        cause: cmdPrefixMessage(
+          CMD_NAME,
+          `${agent} run ${testScript} failed after applying the fix.`,
+        ),
+      }
+    }
+  }
+
+  spinner?.stop()
+  return { ok: true, data: true }
+}
@@ -0,0 +1,242 @@
+import { readFileSync } from 'node:fs'
+import path from 'node:path'
+
+import { handleGhappSync } from './handle-ghapp-sync.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { SOCKET_CLI_GITHUB_APP_ID } from '../../env/socket-cli-github-app-id.mts'
+import { SOCKET_CLI_GITHUB_APP_INSTALLATION_ID } from '../../env/socket-cli-github-app-installation-id.mts'
+import { SOCKET_CLI_GITHUB_APP_PRIVATE_KEY } from '../../env/socket-cli-github-app-private-key.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunUpload } from '../../util/dry-run/output.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { cmdFlagValueToArray } from '../../util/process/cmd.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'sync'
+
+const description =
+  'Scan the default branch of every repository of a GitHub App installation'
+
+const hidden = false
+
+export const cmdGhappSync: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+function readPrivateKey(keyPath: string): string | undefined {
+  try {
+    return readFileSync(path.resolve(process.cwd(), keyPath), 'utf8')
+  } catch {
+    return undefined
+  }
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      appId: {
+        type: 'string',
+        default: SOCKET_CLI_GITHUB_APP_ID,
+        description:
+          'ID of the GitHub App.\nMay set environment variable SOCKET_CLI_GITHUB_APP_ID instead.',
+      },
+      force: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Scan repositories even when the latest scan is of the current commit',
+      },
+      installationId: {
+        type: 'string',
+        default: SOCKET_CLI_GITHUB_APP_INSTALLATION_ID,
+        description:
+          'ID of the installation to sync, required when the app has more than one.\nMay set environment variable SOCKET_CLI_GITHUB_APP_INSTALLATION_ID instead.',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      privateKey: {
+        type: 'string',
+        default: '',
+        description:
+          'Path to the PEM private key of the GitHub App.\nMay set environment variable SOCKET_CLI_GITHUB_APP_PRIVATE_KEY to the key itself instead.',
+      },
+      repos: {
+        type: 'string',
+        isMultiple: true,
+        description:
+          'Only sync these repositories, by name or full name, e.g. `api` or `acme/api`. Accepts a comma-separated value or multiple flags.',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Authenticates as an installation of a GitHub App and creates a Socket scan
+    of the default branch of every repository the installation can access. The
+    manifest files are fetched through the GitHub API, no repository is cloned,
+    so one scheduled job covers the whole organization. Repositories whose
+    latest scan is of the current commit are skipped unless --force is set.
+
+    The app needs read access to "contents" and "metadata".
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} --app-id 12345 --private-key ./app.private-key.pem
+      $ ${command} --installation-id 678 --repos api,web --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    appId,
+    dryRun,
+    force,
+    installationId,
+    interactive,
+    json,
+    markdown,
+    org: orgFlag,
+    privateKey: privateKeyPath,
+  } = cli.flags as {
+    appId: string
+    dryRun: boolean
+    force: boolean
+    installationId: string
+    interactive: boolean
+    json: boolean
+    markdown: boolean
+    org: string
+    privateKey: string
+  }
+
+  const repoNames = cmdFlagValueToArray(cli.flags['repos'])
+
+  const privateKey = privateKeyPath
+    ? readPrivateKey(privateKeyPath)
+    : SOCKET_CLI_GITHUB_APP_PRIVATE_KEY
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: !!appId,
+      message:
+        'GitHub App ID by --app-id or the SOCKET_CLI_GITHUB_APP_ID environment variable',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !!privateKey,
+      message: privateKeyPath
+        ? `The --private-key file could not be read (saw: "${privateKeyPath}")`
+        : 'GitHub App private key by --private-key or the SOCKET_CLI_GITHUB_APP_PRIVATE_KEY environment variable',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !installationId || /^\d+$/.test(installationId),
+      message: `The --installation-id flag must be a number (saw: "${installationId}")`,
+      fail: 'invalid number',
+    },
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunUpload('scans of the GitHub App repositories', {
+      organization: orgSlug,
+      appId,
+      installationId: installationId || '(the only installation)',
+      repos: repoNames.length ? repoNames.join(', ') : '(all)',
+      force,
+    })
+    return
+  }
+
+  await handleGhappSync({
+    credentials: {
+      appId,
+      installationId: installationId || undefined,
+      privateKey: privateKey!,
+    },
+    force,
+    orgSlug,
+    outputKind,
+    repoNames,
+  })
+}
@@ -0,0 +1,24 @@
+import { cmdGhappSync } from './cmd-ghapp-sync.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Scan repositories as a GitHub App installation'
+
+export const cmdGhapp: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} ghapp`,
+        importMeta,
+        subcommands: {
+          sync: cmdGhappSync,
+        },
+      },
+      { description },
+    )
+  },
+}
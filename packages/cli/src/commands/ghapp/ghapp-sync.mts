@@ -0,0 +1,336 @@
+/**
+ * Repository sync behind `socket ghapp sync`.
+ *
+ * Authenticated as a GitHub App installation, every repository the
+ * installation can access gets a Socket scan of its default branch. The
+ * manifests are downloaded through the contents API into a temporary dir, no
+ * clone is made, so the sync runs in a serverless scheduled job. Repositories
+ * whose latest Socket scan of the default branch is of the current commit are
+ * left alone unless forced.
+ */
+
+import { promises as fs } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { SCAN_TYPE_SOCKET } from '../../constants.mts'
+import { getPackageFilesForScan } from '../../util/fs/path-resolve.mts'
+import {
+  GITHUB_ERR_ABUSE_DETECTION,
+  GITHUB_ERR_AUTH_FAILED,
+  GITHUB_ERR_GRAPHQL_RATE_LIMIT,
+  GITHUB_ERR_RATE_LIMIT,
+  getOctokit,
+  setOctokitAuth,
+  withGitHubRetry,
+} from '../../util/git/github.mts'
+import {
+  fetchGitHubAppInstallationToken,
+  isGitHubAppTokenExpiring,
+} from '../../util/git/github-app.mts'
+import {
+  getLastCommitDetails,
+  getRepoBranchTree,
+} from '../scan/create-scan-from-github-api.mts'
+import { fetchCreateOrgFullScan } from '../scan/fetch-create-org-full-scan.mts'
+import { fetchOrgFullScanList } from '../scan/fetch-list-scans.mts'
+import { fetchSupportedScanFileNames } from '../scan/fetch-supported-scan-file-names.mts'
+import { testAndDownloadManifestFiles } from '../scan/github-scan-manifest.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SupportedFiles } from '../../util/fs/glob.mts'
+import type {
+  GitHubAppCredentials,
+  GitHubAppInstallationToken,
+} from '../../util/git/github-app.mts'
+
+const logger = getDefaultLogger()
+
+const COMMAND_PATH = 'socket ghapp sync'
+
+// Every later repository would fail the same way.
+const BLOCKING_GITHUB_ERRORS = new Set([
+  GITHUB_ERR_ABUSE_DETECTION,
+  GITHUB_ERR_AUTH_FAILED,
+  GITHUB_ERR_GRAPHQL_RATE_LIMIT,
+  GITHUB_ERR_RATE_LIMIT,
+])
+
+export type GhappSyncStatus = 'created' | 'current' | 'failed' | 'skipped'
+
+export type GhappSyncRepoResult = {
+  branch: string
+  commitHash?: string | undefined
+  reason?: string | undefined
+  // Full name, e.g. `acme/api`.
+  repo: string
+  reportUrl?: string | undefined
+  scanId?: string | undefined
+  status: GhappSyncStatus
+}
+
+export type GhappSyncResult = {
+  account: string
+  installationId: number
+  repos: GhappSyncRepoResult[]
+}
+
+export type GhappSyncConfig = {
+  credentials: GitHubAppCredentials
+  force: boolean
+  orgSlug: string
+  // Only sync these repository names, all when empty.
+  repoNames: string[]
+}
+
+type InstallationRepo = {
+  archived?: boolean | undefined
+  default_branch?: string | undefined
+  disabled?: boolean | undefined
+  full_name: string
+  name: string
+  owner: { login: string }
+  size?: number | undefined
+}
+
+function formatReason(cResult: {
+  cause?: string | undefined
+  message: string
+}): string {
+  return cResult.cause
+    ? `${cResult.message}: ${cResult.cause}`
+    : cResult.message
+}
+
+/**
+ * Keeps the GitHub clients authenticated as the installation, with a new
+ * token whenever the current one is about to expire.
+ */
+function createInstallationSession(credentials: GitHubAppCredentials) {
+  let current: GitHubAppInstallationToken | undefined
+  return async function ensureToken(): Promise<
+    CResult<GitHubAppInstallationToken>
+  > {
+    if (current && !isGitHubAppTokenExpiring(current)) {
+      return { ok: true, data: current }
+    }
+    const tokenCResult = await fetchGitHubAppInstallationToken({
+      ...credentials,
+      // Stay on the installation picked first.
+      installationId: current
+        ? String(current.installationId)
+        : credentials.installationId,
+    })
+    if (tokenCResult.ok) {
+      current = tokenCResult.data
+      setOctokitAuth(current.token)
+    }
+    return tokenCResult
+  }
+}
+
+async function listInstallationRepos(): Promise<CResult<InstallationRepo[]>> {
+  const octokit = getOctokit()
+  return await withGitHubRetry(
+    () =>
+      octokit.paginate(octokit.apps.listReposAccessibleToInstallation, {
+        per_page: 100,
+      }) as Promise<InstallationRepo[]>,
+    'listing the repositories of the installation',
+  )
+}
+
+async function isScanCurrent(
+  orgSlug: string,
+  repoName: string,
+  branch: string,
+  commitHash: string,
+): Promise<boolean> {
+  const listCResult = await fetchOrgFullScanList(
+    {
+      branch,
+      direction: 'desc',
+      from_time: '',
+      orgSlug,
+      page: 1,
+      perPage: 1,
+      repo: repoName,
+      sort: 'created_at',
+    },
+    { commandPath: COMMAND_PATH },
+  )
+  if (!listCResult.ok) {
+    // No earlier scans to go by, e.g. the repo is new to Socket.
+    debugDir({
+      message: `Listing the scans of ${repoName} failed`,
+      listCResult,
+    })
+    return false
+  }
+  const [latest] = listCResult.data.results
+  return !!latest && latest.commit_hash === commitHash
+}
+
+async function syncRepo(
+  repo: InstallationRepo,
+  {
+    force,
+    orgSlug,
+    supportedFiles,
+  }: { force: boolean; orgSlug: string; supportedFiles: SupportedFiles },
+): Promise<GhappSyncRepoResult> {
+  const branch = repo.default_branch || ''
+  const owner = repo.owner.login
+  const base = { branch, repo: repo.full_name }
+  if (repo.archived || repo.disabled || !repo.size || !branch) {
+    return { ...base, reason: 'Archived or empty', status: 'skipped' }
+  }
+
+  const commitCResult = await getLastCommitDetails({
+    defaultBranch: branch,
+    orgGithub: owner,
+    repoSlug: repo.name,
+  })
+  if (!commitCResult.ok) {
+    return { ...base, reason: formatReason(commitCResult), status: 'failed' }
+  }
+  const { lastCommitMessage, lastCommitSha, lastCommitter } =
+    commitCResult.data
+  if (
+    !force &&
+    (await isScanCurrent(orgSlug, repo.name, branch, lastCommitSha))
+  ) {
+    return { ...base, commitHash: lastCommitSha, status: 'current' }
+  }
+
+  const treeCResult = await getRepoBranchTree({
+    defaultBranch: branch,
+    orgGithub: owner,
+    repoSlug: repo.name,
+  })
+  if (!treeCResult.ok) {
+    return { ...base, reason: formatReason(treeCResult), status: 'failed' }
+  }
+  if (!treeCResult.data.length) {
+    return { ...base, reason: 'No files', status: 'skipped' }
+  }
+
+  const tmpDir = await fs.mkdtemp(path.join(os.tmpdir(), 'socket-ghapp-'))
+  try {
+    const downloadCResult = await testAndDownloadManifestFiles({
+      defaultBranch: branch,
+      files: treeCResult.data,
+      orgGithub: owner,
+      repoSlug: repo.name,
+      tmpDir,
+    })
+    if (!downloadCResult.ok) {
+      return downloadCResult.message === 'No manifest files found'
+        ? { ...base, reason: 'No supported manifest files', status: 'skipped' }
+        : {
+            ...base,
+            reason: formatReason(downloadCResult),
+            status: 'failed',
+          }
+    }
+    const packagePaths = await getPackageFilesForScan(['.'], supportedFiles, {
+      cwd: tmpDir,
+    })
+    const scanCResult = await fetchCreateOrgFullScan(
+      packagePaths,
+      orgSlug,
+      {
+        branchName: branch,
+        commitHash: lastCommitSha,
+        commitMessage: lastCommitMessage,
+        committers: lastCommitter || '',
+        pullRequest: 0,
+        repoName: repo.name,
+        scanType: SCAN_TYPE_SOCKET,
+      },
+      { cwd: tmpDir, defaultBranch: true, pendingHead: true },
+    )
+    if (!scanCResult.ok) {
+      return { ...base, reason: formatReason(scanCResult), status: 'failed' }
+    }
+    return {
+      ...base,
+      commitHash: lastCommitSha,
+      reportUrl: scanCResult.data.html_report_url ?? undefined,
+      scanId: scanCResult.data.id ?? undefined,
+      status: 'created',
+    }
+  } finally {
+    await safeDelete(tmpDir, { force: true })
+  }
+}
+
+export async function syncGitHubAppRepos({
+  credentials,
+  force,
+  orgSlug,
+  repoNames,
+}: GhappSyncConfig): Promise<CResult<GhappSyncResult>> {
+  const ensureToken = createInstallationSession(credentials)
+  try {
+    const tokenCResult = await ensureToken()
+    if (!tokenCResult.ok) {
+      return tokenCResult
+    }
+    const { account, installationId } = tokenCResult.data
+    logger.info(`Authenticated as installation ${installationId} (${account})`)
+
+    const reposCResult = await listInstallationRepos()
+    if (!reposCResult.ok) {
+      return reposCResult
+    }
+    const repos = repoNames.length
+      ? reposCResult.data.filter(
+          repo =>
+            repoNames.includes(repo.name) || repoNames.includes(repo.full_name),
+        )
+      : reposCResult.data
+
+    const supportedFilesCResult = await fetchSupportedScanFileNames({
+      orgSlug,
+    })
+    if (!supportedFilesCResult.ok) {
+      return supportedFilesCResult
+    }
+
+    const results: GhappSyncRepoResult[] = []
+    for (let i = 0, { length } = repos; i < length; i += 1) {
+      const repo = repos[i]!
+      const refreshCResult = await ensureToken()
+      if (!refreshCResult.ok) {
+        return refreshCResult
+      }
+      logger.info(`Syncing ${repo.full_name} (${i + 1}/${length})`)
+      logger.group()
+      const result = await syncRepo(repo, {
+        force,
+        orgSlug,
+        supportedFiles: supportedFilesCResult.data,
+      })
+      logger.groupEnd()
+      results.push(result)
+      const blocking = [...BLOCKING_GITHUB_ERRORS].find(message =>
+        result.reason?.startsWith(message),
+      )
+      if (blocking) {
+        return {
+          ok: false,
+          message: blocking,
+          cause: `Stopped at ${repo.full_name} after ${i} of ${length} repositories: ${result.reason}`,
+        }
+      }
+    }
+    return { ok: true, data: { account, installationId, repos: results } }
+  } finally {
+    setOctokitAuth(undefined)
+  }
+}
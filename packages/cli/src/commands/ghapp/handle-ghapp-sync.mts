@@ -0,0 +1,41 @@
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { syncGitHubAppRepos } from './ghapp-sync.mts'
+import { outputGhappSync } from './output-ghapp-sync.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+
+import type { GhappSyncConfig, GhappSyncResult } from './ghapp-sync.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+export async function handleGhappSync({
+  outputKind,
+  ...config
+}: GhappSyncConfig & { outputKind: OutputKind }): Promise<void> {
+  let result: CResult<GhappSyncResult>
+  try {
+    result = await syncGitHubAppRepos(config)
+  } catch (e) {
+    result = {
+      ok: false,
+      message: 'Failed to sync the GitHub App repositories',
+      cause: getErrorCause(e),
+    }
+  }
+
+  if (
+    result.ok &&
+    result.data.repos.length &&
+    result.data.repos.every(repo => repo.status === 'failed')
+  ) {
+    const [first] = result.data.repos
+    result = {
+      ok: false,
+      message: 'All repositories failed to sync',
+      cause: `All ${result.data.repos.length} repositories failed, the first, ${first!.repo}, with: ${first!.reason}`,
+    }
+  }
+
+  debugDir({ result })
+
+  outputGhappSync(result, outputKind)
+}
@@ -0,0 +1,87 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { GhappSyncRepoResult, GhappSyncResult } from './ghapp-sync.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function formatDetails(repo: GhappSyncRepoResult): string {
+  if (repo.status === 'created') {
+    return repo.reportUrl || repo.scanId || ''
+  }
+  if (repo.status === 'current') {
+    return `Already scanned at ${repo.commitHash?.slice(0, 12)}`
+  }
+  return repo.reason || ''
+}
+
+function countStatus(
+  repos: GhappSyncRepoResult[],
+  status: GhappSyncRepoResult['status'],
+): number {
+  return repos.filter(repo => repo.status === status).length
+}
+
+export function outputGhappSync(
+  result: CResult<GhappSyncResult>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (result.data.repos.some(repo => repo.status === 'failed')) {
+    process.exitCode = 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { account, repos } = result.data
+  const failed = countStatus(repos, 'failed')
+  const summary = `Synced ${repos.length} ${repos.length === 1 ? 'repository' : 'repositories'} of ${account || 'the installation'}: ${countStatus(repos, 'created')} scanned, ${countStatus(repos, 'current')} up to date, ${countStatus(repos, 'skipped')} skipped, ${failed} failed`
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('GitHub App sync'))
+    logger.log('')
+    logger.log(summary)
+    logger.log('')
+    logger.log(
+      mdTable(
+        repos.map(repo => ({
+          repo: repo.repo,
+          branch: repo.branch,
+          status: repo.status,
+          details: formatDetails(repo),
+        })),
+        ['repo', 'branch', 'status', 'details'],
+        ['Repository', 'Branch', 'Status', 'Details'],
+      ),
+    )
+    return
+  }
+
+  logger.log('')
+  for (let i = 0, { length } = repos; i < length; i += 1) {
+    const repo = repos[i]!
+    const details = formatDetails(repo)
+    logger.log(
+      `${repo.repo} (${repo.branch || 'no branch'}): ${repo.status}${details ? `, ${details}` : ''}`,
+    )
+  }
+  logger.log('')
+  if (failed) {
+    logger.warn(summary)
+  } else {
+    logger.success(summary)
+  }
+}
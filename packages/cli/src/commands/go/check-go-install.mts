@@ -0,0 +1,157 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import {
+  getGoProxyConfig,
+  matchesGoPrivate,
+  resolveGoModule,
+} from '../../util/go/module-proxy.mts'
+import { checkPackagesBeforeInstall } from '../../util/install-check/check.mts'
+
+import type { CResult } from '../../types.mts'
+import type { CheckInstallPackagesOptions } from '../../util/install-check/check.mts'
+
+const logger = getDefaultLogger()
+
+export type GoInstallQuery = {
+  path: string
+  // Module query, e.g. `latest`, `v1.9.1` or a branch name.
+  query: string
+}
+
+// go build flags that consume the next argument.
+const GO_VALUE_FLAGS = new Set([
+  'C',
+  'asmflags',
+  'buildmode',
+  'compiler',
+  'coverpkg',
+  'covermode',
+  'exec',
+  'gccgoflags',
+  'gcflags',
+  'installsuffix',
+  'ldflags',
+  'mod',
+  'modfile',
+  'o',
+  'overlay',
+  'p',
+  'pgo',
+  'pkgdir',
+  'tags',
+  'toolexec',
+])
+
+// Pseudo-packages and toolchain switches that do not name a module.
+const GO_NON_MODULE_ARGS = new Set(['all', 'cmd', 'go', 'std', 'toolchain'])
+
+const GO_NO_CHECK_FLAGS = new Set(['h', 'help', 'n'])
+
+/**
+ * Work out which module queries a `go get|install` invocation makes. Local
+ * paths, standard library packages, removals (`@none`), version comparisons
+ * and `go install` without a version (resolved from go.mod) are left out.
+ */
+export function getGoInstallQueries(
+  args: readonly string[],
+): GoInstallQuery[] | undefined {
+  const [subcommand, ...rest] = args
+  if (subcommand !== 'get' && subcommand !== 'install') {
+    return undefined
+  }
+  const positionals: string[] = []
+  for (let i = 0, { length } = rest; i < length; i += 1) {
+    const arg = rest[i]!
+    if (arg === '--') {
+      positionals.push(...rest.slice(i + 1))
+      break
+    }
+    // Like the flag package, stop at the first non-flag argument.
+    if (!arg.startsWith('-') || arg === '-') {
+      positionals.push(...rest.slice(i))
+      break
+    }
+    const body = arg.replace(/^--?/, '')
+    const eqIndex = body.indexOf('=')
+    const name = eqIndex === -1 ? body : body.slice(0, eqIndex)
+    if (GO_NO_CHECK_FLAGS.has(name)) {
+      return undefined
+    }
+    if (eqIndex === -1 && GO_VALUE_FLAGS.has(name)) {
+      i += 1
+    }
+  }
+  const queries: GoInstallQuery[] = []
+  for (let i = 0, { length } = positionals; i < length; i += 1) {
+    const spec = positionals[i]!
+    const atIndex = spec.lastIndexOf('@')
+    const pkgPath = atIndex === -1 ? spec : spec.slice(0, atIndex)
+    const query = atIndex === -1 ? undefined : spec.slice(atIndex + 1)
+    if (
+      GO_NON_MODULE_ARGS.has(pkgPath) ||
+      pkgPath.startsWith('.') ||
+      pkgPath.startsWith('/') ||
+      // Non-module paths have no dot in their first element.
+      !pkgPath.split('/')[0]!.includes('.') ||
+      query === 'none' ||
+      /^[<>]/.test(query ?? '') ||
+      (query === undefined && subcommand === 'install')
+    ) {
+      continue
+    }
+    queries.push({ path: pkgPath, query: query ?? 'upgrade' })
+  }
+  return queries.length ? queries : undefined
+}
+
+/**
+ * Preflight for `socket go get|install`: resolve the requested modules through
+ * the configured module proxy and score them before handing off. Lookup
+ * failures only warn, Socket Firewall still screens the downloads.
+ */
+export async function checkGoInstall(
+  args: readonly string[],
+  binaryName: string,
+  options?: Pick<CheckInstallPackagesOptions, 'interactive'> | undefined,
+): Promise<CResult<unknown>> {
+  const queries = getGoInstallQueries(args)
+  if (!queries) {
+    return { ok: true, data: undefined }
+  }
+  const spinner = getDefaultSpinner()
+  spinner.start('Resolving modules to install…')
+  const configCResult = await getGoProxyConfig(binaryName)
+  if (!configCResult.ok || !configCResult.data.proxyUrl) {
+    spinner.stop()
+    logger.warn(
+      `Skipping the Socket pre-install check: ${configCResult.ok ? 'GOPROXY does not name a module proxy' : (configCResult.cause ?? configCResult.message)}`,
+    )
+    return { ok: true, data: undefined }
+  }
+  const { noProxy, proxyUrl } = configCResult.data
+  const publicQueries = queries.filter(q => !matchesGoPrivate(q.path, noProxy))
+  const moduleCResults = await Promise.all(
+    publicQueries.map(q => resolveGoModule(proxyUrl, q.path, q.query)),
+  )
+  spinner.stop()
+  const purls = new Set<string>()
+  for (let i = 0, { length } = moduleCResults; i < length; i += 1) {
+    const moduleCResult = moduleCResults[i]!
+    if (!moduleCResult.ok) {
+      logger.warn(
+        `Skipping the Socket pre-install check: ${moduleCResult.cause ?? moduleCResult.message}`,
+      )
+      return { ok: true, data: undefined }
+    }
+    // Unknown modules and versions are left for the go command to report.
+    if (moduleCResult.data) {
+      const { path, version } = moduleCResult.data
+      purls.add(`pkg:golang/${path}@${version}`)
+    }
+  }
+  return await checkPackagesBeforeInstall([...purls], {
+    commandPath: 'socket go',
+    interactive: options?.interactive,
+  })
+}
@@ -1,9 +1,12 @@
 /**
  * Socket go command — forwards go operations to Socket Firewall (sfw).
  *
- * Defined via `defineHandoffCommand`. See util/cli/define-handoff.mts.
+ * Defined via `defineHandoffCommand`. `go get` and `go install` module
+ * queries are resolved and scored before the hand-off through its `preflight`
+ * hook. See util/cli/define-handoff.mts.
  */
 
+import { checkGoInstall } from './check-go-install.mts'
 import { defineHandoffCommand } from '../../util/cli/define-handoff.mts'
 
 export const cmdGo = defineHandoffCommand({
@@ -16,8 +19,11 @@ export const cmdGo = defineHandoffCommand({
     'mod download',
   ],
   helpNotes: [
+    'Modules requested by `go get` and `go install` are checked against Socket',
+    'before the go command fetches them.',
     'Wrapper mode works best on Linux (macOS may have keychain issues).',
   ],
+  preflight: checkGoInstall,
   trackTelemetry: false,
   supportDryRun: false,
 })
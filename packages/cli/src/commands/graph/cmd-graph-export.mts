@@ -0,0 +1,189 @@
+import { GRAPH_FORMATS } from './dependency-graph.mts'
+import { handleGraphExport } from './handle-graph-export.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { GRAPH_FORMAT } from './dependency-graph.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+/**
+ * Pick the graph format from the output file extension.
+ */
+export function inferGraphFormat(filepath: string): GRAPH_FORMAT {
+  return filepath.endsWith('.graphml') ? 'graphml' : 'dot'
+}
+
+export const CMD_NAME = 'export'
+
+const description = 'Export the dependency graph of a scan as DOT or GraphML'
+
+const hidden = false
+
+export const cmdGraphExport: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      format: {
+        type: 'string',
+        default: '',
+        description:
+          'Graph format: dot or graphml. Defaults to the OUTPUT_FILE extension, or dot',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID> [OUTPUT_FILE]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Writes the resolved dependency tree of a completed full scan for Graphviz,
+    Gephi, yEd, NetworkX and other graph tools. Every package is a node keyed
+    by its purl, edges point from a package to its dependencies and a root
+    node for the scan points to the direct dependencies.
+
+    Packages with alerts carry their alert types and highest severity as
+    "alerts" and "severity" attributes; DOT also fills them by severity.
+
+    When no output path is given the graph is sent to stdout.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 | dot -Tsvg > deps.svg
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 ./deps.graphml
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format graphml > deps.xml
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    format: formatFlag,
+    interactive,
+    json,
+    markdown,
+    org: orgFlag,
+  } = cli.flags as {
+    format: string
+    interactive: boolean
+    json: boolean
+    markdown: boolean
+    org: string
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const [scanId = '', filepath = ''] = cli.input
+
+  const format = (formatFlag || inferGraphFormat(filepath)) as GRAPH_FORMAT
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID to export',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: GRAPH_FORMATS.includes(format),
+      message: `The --format flag must be one of: ${GRAPH_FORMATS.join(', ')}`,
+      fail: `unsupported format "${format}"`,
+    },
+    {
+      nook: true,
+      test: !markdown,
+      message: 'Markdown output is not supported for graph documents',
+      fail: 'remove --markdown',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scan for dependency graph export', {
+      organization: orgSlug,
+      scanId,
+      format,
+      output: filepath || 'stdout',
+    })
+    return
+  }
+
+  await handleGraphExport({
+    filepath,
+    format,
+    orgSlug,
+    outputKind,
+    scanId,
+  })
+}
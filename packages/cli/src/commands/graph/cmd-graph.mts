@@ -0,0 +1,10 @@
+import { cmdGraphExport } from './cmd-graph-export.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdGraph = defineSubcommandGroup({
+  name: 'graph',
+  description: 'Export the dependency graph of Socket scans',
+  subcommands: {
+    export: cmdGraphExport,
+  },
+})
@@ -0,0 +1,239 @@
+/**
+ * Dependency graph of a completed Socket full scan, rendered as Graphviz DOT
+ * or GraphML.
+ *
+ * Each artifact becomes a node keyed by its purl and each artifact
+ * dependency an edge from the dependent to the dependency. A root node for
+ * the scan depends on every direct dependency. Scans without a graph only
+ * know the top level ancestors the API reports, which then stand in for the
+ * missing edges. Nodes with alerts carry their alert types and the highest
+ * alert severity; DOT colors them by that severity.
+ */
+
+import { renderXmlDocument } from '../../util/output/xml.mts'
+import { POLICY_SEVERITIES } from '../../util/policy/socket-policy.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { XmlNode } from '../../util/output/xml.mts'
+import type { PolicySeverity } from '../../util/policy/socket-policy.mts'
+
+export type GRAPH_FORMAT = 'dot' | 'graphml'
+
+export const GRAPH_FORMATS: readonly GRAPH_FORMAT[] = ['dot', 'graphml']
+
+export type DependencyGraphNode = {
+  // Alert types, sorted and without duplicates.
+  alerts: string[]
+  direct: boolean
+  id: string
+  label: string
+  severity?: string | undefined
+}
+
+export type DependencyGraph = {
+  edges: Array<[from: string, to: string]>
+  // Whether the scan has dependency edges, otherwise they come from the top
+  // level ancestors.
+  hasGraph: boolean
+  nodes: DependencyGraphNode[]
+  root: DependencyGraphNode
+}
+
+const SEVERITY_COLORS: Record<string, string> = {
+  __proto__: null,
+  critical: '#d62728',
+  high: '#ff7f0e',
+  middle: '#ffdd57',
+  low: '#9ecae1',
+} as unknown as Record<string, string>
+
+function getSeverityIndex(severity: string | undefined): number {
+  return POLICY_SEVERITIES.indexOf(severity as PolicySeverity)
+}
+
+export function buildDependencyGraph(
+  scan: SocketArtifact[],
+  scanId: string,
+): DependencyGraph {
+  const purlById = new Map<string, string>()
+  const nodeByPurl = new Map<string, DependencyGraphNode>()
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    const purl = getArtifactPurlString(artifact)
+    if (artifact.id) {
+      purlById.set(artifact.id, purl)
+    }
+    // A package listed by several manifests is one node.
+    const existing = nodeByPurl.get(purl)
+    const alerts = new Set(existing?.alerts)
+    let severityIndex = getSeverityIndex(existing?.severity)
+    for (const alert of artifact.alerts ?? []) {
+      alerts.add(alert.type)
+      severityIndex = Math.max(severityIndex, getSeverityIndex(alert.severity))
+    }
+    nodeByPurl.set(purl, {
+      alerts: [...alerts].sort(),
+      direct: !!artifact.direct || !!existing?.direct,
+      id: purl,
+      label: purl,
+      ...(severityIndex === -1
+        ? {}
+        : { severity: POLICY_SEVERITIES[severityIndex] }),
+    })
+  }
+
+  const root: DependencyGraphNode = {
+    alerts: [],
+    direct: false,
+    id: `scan:${scanId}`,
+    label: `scan ${scanId}`,
+  }
+  const edgeKeys = new Set<string>()
+  const edges: DependencyGraph['edges'] = []
+  const addEdge = (from: string, to: string) => {
+    const key = `${from} ${to}`
+    if (from !== to && !edgeKeys.has(key)) {
+      edgeKeys.add(key)
+      edges.push([from, to])
+    }
+  }
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    const purl = getArtifactPurlString(artifact)
+    const deps = (artifact.dependencies ?? []) as string[]
+    for (let j = 0, { length: depCount } = deps; j < depCount; j += 1) {
+      const depPurl = purlById.get(deps[j]!)
+      if (depPurl) {
+        addEdge(purl, depPurl)
+      }
+    }
+  }
+  const hasGraph = edges.length > 0
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    const purl = getArtifactPurlString(artifact)
+    if (artifact.direct) {
+      addEdge(root.id, purl)
+    } else if (!hasGraph) {
+      const ancestors = (artifact.topLevelAncestors ?? []) as string[]
+      for (let j = 0, { length: count } = ancestors; j < count; j += 1) {
+        const ancestorPurl = purlById.get(ancestors[j]!)
+        if (ancestorPurl) {
+          addEdge(ancestorPurl, purl)
+        }
+      }
+    }
+  }
+
+  // Sorted so exporting the same scan again gives the same document.
+  edges.sort((a, b) => a[0].localeCompare(b[0]) || a[1].localeCompare(b[1]))
+  return {
+    edges,
+    hasGraph,
+    nodes: [...nodeByPurl.values()].sort((a, b) => a.id.localeCompare(b.id)),
+    root,
+  }
+}
+
+function quoteDot(value: string): string {
+  return `"${value.replaceAll('\\', '\\\\').replaceAll('"', '\\"')}"`
+}
+
+function dotNode(node: DependencyGraphNode, extra: string[] = []): string {
+  const attrs = [`label=${quoteDot(node.label)}`, ...extra]
+  // `alerts` and `severity` are not Graphviz attributes, analysis tools
+  // reading the DOT file pick them up.
+  if (node.alerts.length) {
+    attrs.push(
+      `alerts=${quoteDot(node.alerts.join(','))}`,
+      `tooltip=${quoteDot(node.alerts.join(', '))}`,
+    )
+  }
+  if (node.severity) {
+    attrs.push(
+      `severity=${quoteDot(node.severity)}`,
+      'style=filled',
+      `fillcolor=${quoteDot(SEVERITY_COLORS[node.severity]!)}`,
+    )
+  }
+  return `  ${quoteDot(node.id)} [${attrs.join(', ')}];`
+}
+
+export function renderDot(graph: DependencyGraph): string {
+  const lines = [
+    `digraph ${quoteDot(graph.root.label)} {`,
+    '  rankdir=LR;',
+    '  node [shape=box];',
+    dotNode(graph.root, ['shape=doubleoctagon']),
+    ...graph.nodes.map(node =>
+      dotNode(node, node.direct ? ['penwidth=2'] : []),
+    ),
+    ...graph.edges.map(
+      ({ 0: from, 1: to }) => `  ${quoteDot(from)} -> ${quoteDot(to)};`,
+    ),
+    '}',
+  ]
+  return `${lines.join('\n')}\n`
+}
+
+function graphMlNode(node: DependencyGraphNode): XmlNode {
+  return {
+    name: 'node',
+    attrs: { id: node.id },
+    children: [
+      { name: 'data', attrs: { key: 'label' }, text: node.label },
+      { name: 'data', attrs: { key: 'direct' }, text: String(node.direct) },
+      ...(node.alerts.length
+        ? [
+            {
+              name: 'data',
+              attrs: { key: 'alerts' },
+              text: node.alerts.join(','),
+            },
+          ]
+        : []),
+      ...(node.severity
+        ? [{ name: 'data', attrs: { key: 'severity' }, text: node.severity }]
+        : []),
+    ],
+  }
+}
+
+export function renderGraphMl(graph: DependencyGraph): string {
+  const keys: Array<[id: string, type: string]> = [
+    ['label', 'string'],
+    ['direct', 'boolean'],
+    ['alerts', 'string'],
+    ['severity', 'string'],
+  ]
+  return renderXmlDocument({
+    name: 'graphml',
+    attrs: { xmlns: 'http://graphml.graphdrawing.org/xmlns' },
+    children: [
+      ...keys.map(({ 0: id, 1: type }) => ({
+        name: 'key',
+        attrs: { id, for: 'node', 'attr.name': id, 'attr.type': type },
+      })),
+      {
+        name: 'graph',
+        attrs: { id: graph.root.id, edgedefault: 'directed' },
+        children: [
+          graphMlNode(graph.root),
+          ...graph.nodes.map(graphMlNode),
+          ...graph.edges.map(({ 0: source, 1: target }) => ({
+            name: 'edge',
+            attrs: { source, target },
+          })),
+        ],
+      },
+    ],
+  })
+}
+
+export function renderDependencyGraph(
+  graph: DependencyGraph,
+  format: GRAPH_FORMAT,
+): string {
+  return format === 'graphml' ? renderGraphMl(graph) : renderDot(graph)
+}
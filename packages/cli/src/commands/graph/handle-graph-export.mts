@@ -0,0 +1,30 @@
+import { outputGraphExport } from './output-graph-export.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+
+import type { GRAPH_FORMAT } from './dependency-graph.mts'
+import type { OutputKind } from '../../types.mts'
+
+export type HandleGraphExportConfig = {
+  filepath: string
+  format: GRAPH_FORMAT
+  orgSlug: string
+  outputKind: OutputKind
+  scanId: string
+}
+
+export async function handleGraphExport({
+  filepath,
+  format,
+  orgSlug,
+  outputKind,
+  scanId,
+}: HandleGraphExportConfig): Promise<void> {
+  const scanCResult = await fetchScan(orgSlug, scanId)
+
+  await outputGraphExport(scanCResult, {
+    filepath,
+    format,
+    outputKind,
+    scanId,
+  })
+}
@@ -0,0 +1,65 @@
+import fs from 'node:fs/promises'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import {
+  buildDependencyGraph,
+  renderDependencyGraph,
+} from './dependency-graph.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { serializeResultJson } from '../../util/output/result-json.mts'
+import { fileLink } from '../../util/terminal/link.mts'
+
+import type { GRAPH_FORMAT } from './dependency-graph.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+const logger = getDefaultLogger()
+
+export type OutputGraphExportConfig = {
+  filepath: string
+  format: GRAPH_FORMAT
+  outputKind: OutputKind
+  scanId: string
+}
+
+export async function outputGraphExport(
+  result: CResult<SocketArtifact[]>,
+  { filepath, format, outputKind, scanId }: OutputGraphExportConfig,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+    if (outputKind === 'json') {
+      logger.log(serializeResultJson(result))
+      return
+    }
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const graph = buildDependencyGraph(result.data, scanId)
+  const document = renderDependencyGraph(graph, format)
+
+  if (filepath && filepath !== '-') {
+    try {
+      await fs.writeFile(filepath, document, 'utf8')
+      logger.success(
+        `Exported the ${format} dependency graph (${graph.nodes.length} packages, ${graph.edges.length} edges) to ${fileLink(filepath)}`,
+      )
+      if (!graph.hasGraph) {
+        logger.warn(
+          'The scan has no dependency edges, packages hang off their top level ancestors',
+        )
+      }
+    } catch (e) {
+      process.exitCode = 1
+      logger.fail(
+        `There was an error trying to write the ${format} dependency graph to disk`,
+      )
+      logger.error(e)
+    }
+    return
+  }
+
+  logger.log(document)
+}
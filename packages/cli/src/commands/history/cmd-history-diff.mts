@@ -0,0 +1,92 @@
+import { handleHistoryDiff } from './handle-history-diff.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'diff'
+
+const description = 'Compare the alerts of two scans from the local history'
+
+const hidden = false
+
+export const cmdHistoryDiff: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID> <SCAN_ID>
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Lists the alerts the second scan added and removed compared to the first,
+    like \`socket scan diff\` does, from the recorded results of both scans and
+    without calling the API.
+
+    Examples
+      $ ${command} aaa0aa0a-aaaa-0000-0a0a-0000000a00a0 aaa1aa1a-aaaa-1111-1a1a-1111111a11a1
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags as {
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const [beforeId = '', afterId = ''] = cli.input
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!(beforeId && afterId) && cli.input.length === 2,
+      message: 'Specify two Scan IDs',
+      fail: cli.input.length > 2 ? 'too many' : 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  await handleHistoryDiff({ afterId, beforeId, outputKind })
+}
@@ -0,0 +1,99 @@
+import { handleHistoryExport } from './handle-history-export.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'export'
+
+const description = 'Export the local history of scan results to a JSON file'
+
+const hidden = false
+
+const HISTORY_EXPORT_DEFAULT_FILENAME = 'socket-history.json'
+
+export const cmdHistoryExport: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      output: {
+        type: 'string',
+        default: HISTORY_EXPORT_DEFAULT_FILENAME,
+        description: `Write the history to this file (default '${HISTORY_EXPORT_DEFAULT_FILENAME}')`,
+        shortFlag: 'o',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Writes every recorded scan and its alerts to a JSON file, to back up the
+    history or to load it into other tools.
+
+    Examples
+      $ ${command}
+      $ ${command} --output backups/socket-history.json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown, output } = cli.flags as {
+    json: boolean
+    markdown: boolean
+    output: string
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!output,
+      message: 'The file to export to',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  await handleHistoryExport({ filepath: output, outputKind })
+}
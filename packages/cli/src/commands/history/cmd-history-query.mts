@@ -0,0 +1,103 @@
+import { handleHistoryQuery } from './handle-history-query.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'query'
+
+const description = 'Find alerts in the local history of scan results'
+
+const hidden = false
+
+export const cmdHistoryQuery: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] QUERY
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Answers from the scans the CLI has fetched before, without calling the
+    API. Scans are recorded when they are created, viewed, diffed or reported
+    on. A query is a few plain words:
+
+      - new, fixed or open: alerts first seen, gone or still present
+      - critical, high, medium or low: the severities
+      - last 30 days, past week, last 12h or since 2026-01-31: the time
+      - repo NAME, branch NAME, type ALERT_TYPE or package NAME
+
+    Alerts are followed along the scans of each repo and branch by package
+    name and alert type, so a version bump that keeps an alert does not make
+    it new.
+
+    Examples
+      $ ${command} "new criticals in last 30 days"
+      $ ${command} fixed high alerts repo my-app since 2026-01-31
+      $ ${command} open type malware --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags as {
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const text = cli.input.join(' ').trim()
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!text,
+      message: 'Query of the history, e.g. "new criticals in last 30 days"',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  await handleHistoryQuery({ outputKind, text })
+}
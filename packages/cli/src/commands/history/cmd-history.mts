@@ -0,0 +1,28 @@
+import { cmdHistoryDiff } from './cmd-history-diff.mts'
+import { cmdHistoryExport } from './cmd-history-export.mts'
+import { cmdHistoryQuery } from './cmd-history-query.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Query the local history of scan results'
+
+export const cmdHistory: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} history`,
+        importMeta,
+        subcommands: {
+          diff: cmdHistoryDiff,
+          export: cmdHistoryExport,
+          query: cmdHistoryQuery,
+        },
+      },
+      { description },
+    )
+  },
+}
@@ -0,0 +1,24 @@
+import { outputHistoryDiff } from './output-history-diff.mts'
+import { diffHistoryScans } from '../../util/history/query.mts'
+import { readScanHistory } from '../../util/history/store.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export async function handleHistoryDiff({
+  afterId,
+  beforeId,
+  outputKind,
+}: {
+  afterId: string
+  beforeId: string
+  outputKind: OutputKind
+}): Promise<void> {
+  const historyCResult = await readScanHistory()
+
+  outputHistoryDiff(
+    historyCResult.ok
+      ? diffHistoryScans(historyCResult.data, beforeId, afterId)
+      : historyCResult,
+    outputKind,
+  )
+}
@@ -0,0 +1,60 @@
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { outputHistoryExport } from './output-history-export.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import {
+  HISTORY_EXPORT_VERSION,
+  readScanHistory,
+} from '../../util/history/store.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { HistoryExport } from '../../util/history/store.mts'
+
+export type HistoryExportResult = {
+  alerts: number
+  filepath: string
+  scans: number
+}
+
+export async function exportScanHistory(
+  filepath: string,
+): Promise<CResult<HistoryExportResult>> {
+  const historyCResult = await readScanHistory()
+  if (!historyCResult.ok) {
+    return historyCResult
+  }
+  const { alerts, scans } = historyCResult.data
+  const data: HistoryExport = {
+    alerts,
+    exportedAt: new Date().toISOString(),
+    scans,
+    version: HISTORY_EXPORT_VERSION,
+  }
+  const resolved = path.resolve(process.cwd(), filepath)
+  try {
+    await fs.writeFile(resolved, `${JSON.stringify(data, null, 2)}\n`, 'utf8')
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Failed to write the history export',
+      cause: getErrorCause(e),
+    }
+  }
+  return {
+    ok: true,
+    data: { alerts: alerts.length, filepath: resolved, scans: scans.length },
+  }
+}
+
+export async function handleHistoryExport({
+  filepath,
+  outputKind,
+}: {
+  filepath: string
+  outputKind: OutputKind
+}): Promise<void> {
+  const result = await exportScanHistory(filepath)
+
+  outputHistoryExport(result, outputKind)
+}
@@ -0,0 +1,38 @@
+import { outputHistoryQuery } from './output-history-query.mts'
+import {
+  parseHistoryQuery,
+  runHistoryQuery,
+} from '../../util/history/query.mts'
+import { readScanHistory } from '../../util/history/store.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { HistoryQueryRow } from '../../util/history/query.mts'
+
+export async function queryScanHistory(
+  text: string,
+): Promise<CResult<HistoryQueryRow[]>> {
+  const queryCResult = parseHistoryQuery(text)
+  if (!queryCResult.ok) {
+    return { ...queryCResult, code: 2 }
+  }
+  const historyCResult = await readScanHistory()
+  if (!historyCResult.ok) {
+    return historyCResult
+  }
+  return {
+    ok: true,
+    data: runHistoryQuery(historyCResult.data, queryCResult.data),
+  }
+}
+
+export async function handleHistoryQuery({
+  outputKind,
+  text,
+}: {
+  outputKind: OutputKind
+  text: string
+}): Promise<void> {
+  const result = await queryScanHistory(text)
+
+  outputHistoryQuery(result, outputKind, text)
+}
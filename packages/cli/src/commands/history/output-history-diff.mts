@@ -0,0 +1,68 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { HistoryScanDiff } from '../../util/history/query.mts'
+import type { HistoryAlert } from '../../util/history/store.mts'
+
+const logger = getDefaultLogger()
+
+function formatAlert(alert: HistoryAlert): string {
+  return `${alert.severity} ${alert.type} in ${alert.purl}`
+}
+
+export function outputHistoryDiff(
+  result: CResult<HistoryScanDiff>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { added, after, before, removed } = result.data
+  const sections: Array<[string, HistoryAlert[]]> = [
+    ['Added', added],
+    ['Removed', removed],
+  ]
+  const summary = `${added.length} ${pluralize('alert', { count: added.length })} added and ${removed.length} removed from ${before.scanId} to ${after.scanId}`
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Scan history diff'))
+    logger.log('')
+    logger.log(summary)
+    for (const { 0: title, 1: alerts } of sections) {
+      if (alerts.length) {
+        logger.log('')
+        logger.log(mdHeader(title, 2))
+        logger.log('')
+        logger.log(mdList(alerts.map(formatAlert)))
+      }
+    }
+    return
+  }
+
+  logger.log(summary)
+  for (const { 0: title, 1: alerts } of sections) {
+    if (alerts.length) {
+      logger.log('')
+      logger.log(`${title}:`)
+      for (const alert of alerts) {
+        logger.log(`  ${title === 'Added' ? '+' : '-'} ${formatAlert(alert)}`)
+      }
+    }
+  }
+}
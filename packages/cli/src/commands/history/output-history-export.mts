@@ -0,0 +1,45 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { HistoryExportResult } from './handle-history-export.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export function outputHistoryExport(
+  result: CResult<HistoryExportResult>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { alerts, filepath, scans } = result.data
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Scan history export'))
+    logger.log('')
+    logger.log(mdKeyValue('File', filepath))
+    logger.log(mdKeyValue('Scans', scans))
+    logger.log(mdKeyValue('Alerts', alerts))
+    return
+  }
+
+  logger.success(
+    `Exported ${scans} ${pluralize('scan', { count: scans })} and ${alerts} ${pluralize('alert', { count: alerts })} to ${filepath}`,
+  )
+}
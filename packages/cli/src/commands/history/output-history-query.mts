@@ -0,0 +1,101 @@
+import chalkTable from 'chalk-table'
+import colors from 'yoctocolors-cjs'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { HistoryQueryRow } from '../../util/history/query.mts'
+
+const logger = getDefaultLogger()
+
+const QUERY_COLUMNS = [
+  'severity',
+  'type',
+  'purl',
+  'stream',
+  'firstSeen',
+  'status',
+]
+
+const QUERY_TITLES = [
+  'Severity',
+  'Type',
+  'Package',
+  'Repo',
+  'First seen',
+  'Status',
+]
+
+function formatDay(ms: number): string {
+  return new Date(ms).toISOString().slice(0, 10)
+}
+
+export function getHistoryQueryTableRows(
+  rows: HistoryQueryRow[],
+): Array<Record<string, string>> {
+  return rows.map(row => ({
+    firstSeen: formatDay(row.firstSeen),
+    purl: row.purl,
+    severity: row.severity,
+    status:
+      row.status === 'fixed' ? `fixed ${formatDay(row.fixedAt!)}` : 'open',
+    stream: `${row.repo ?? '-'}${row.branch ? ` (${row.branch})` : ''}`,
+    type: row.type,
+  }))
+}
+
+export function outputHistoryQuery(
+  result: CResult<HistoryQueryRow[]>,
+  outputKind: OutputKind,
+  text: string,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const rows = result.data
+  const summary = `${rows.length} ${pluralize('alert', { count: rows.length })} for "${text}"`
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Scan history'))
+    logger.log('')
+    logger.log(summary)
+    if (rows.length) {
+      logger.log('')
+      logger.log(
+        mdTable(getHistoryQueryTableRows(rows), QUERY_COLUMNS, QUERY_TITLES),
+      )
+    }
+    return
+  }
+
+  logger.log(summary)
+  if (rows.length) {
+    logger.log(
+      chalkTable(
+        {
+          columns: QUERY_COLUMNS.map((field, i) => ({
+            field,
+            name: colors.magenta(QUERY_TITLES[i]!),
+          })),
+        },
+        getHistoryQueryTableRows(rows),
+      ),
+    )
+  }
+}
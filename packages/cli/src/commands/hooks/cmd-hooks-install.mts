@@ -0,0 +1,165 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { handleHooksInstall } from './handle-hooks-install.mts'
+import { HOOKS_FAIL_ON, HOOK_NAMES } from './hook-scripts.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { cmdFlagValueToArray } from '../../util/process/cmd.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { HandleHooksInstallConfig } from './handle-hooks-install.mts'
+import type { HookName, HooksFailOn } from './hook-scripts.mts'
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'install'
+
+const description =
+  'Install git hooks that check new dependencies before a commit or push'
+
+const hidden = false
+
+const FRAMEWORKS = ['auto', 'git', 'pre-commit']
+
+export const cmdHooksInstall: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      failOn: {
+        type: 'string',
+        default: 'error',
+        description: `Alerts that fail the hook: ${joinOr([...HOOKS_FAIL_ON])}`,
+      },
+      force: {
+        type: 'boolean',
+        default: false,
+        description: 'Replace existing hooks that socket did not write',
+      },
+      framework: {
+        type: 'string',
+        default: 'auto',
+        description: `Where to install the hooks: ${joinOr(FRAMEWORKS)}`,
+      },
+      hook: {
+        type: 'string',
+        isMultiple: true,
+        default: [...HOOK_NAMES],
+        description: `Hooks to install, ${joinOr([...HOOK_NAMES])}. Accepts a comma-separated value or multiple flags.`,
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Installs hooks that run \`socket hooks run\` before every commit and
+    push. The hook reads the lockfiles the commit or push changes, looks up
+    only the package versions it adds, and fails when one of them has an
+    alert whose security policy action is "error", or malware. Lookups are
+    cached, so commits that touch no lockfile cost nothing.
+
+    --fail-on sets the strictness: "error" (default) fails on blocking
+    alerts, "warn" also on warnings and when packages could not be checked,
+    "never" only reports. A socket.policy.yml in the repository overrides
+    the org policy.
+
+    With --framework auto the hooks are added to .pre-commit-config.yaml
+    when the repository uses the pre-commit framework, and written to the
+    git hooks directory otherwise. Existing hooks that socket did not write
+    are kept unless --force moves them aside.
+
+    Skip the check once with \`git commit --no-verify\`, or with
+    SOCKET_CLI_SKIP_HOOKS=1. Remove the hooks with \`socket hooks uninstall\`.
+
+    Examples
+      $ ${command}
+      $ ${command} --hook pre-push --fail-on warn
+      $ ${command} --framework pre-commit
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, failOn, force, framework, json, markdown } = cli.flags as {
+    dryRun: boolean
+    failOn: string
+    force: boolean
+    framework: string
+    json: boolean
+    markdown: boolean
+  }
+  const hooks = [...new Set(cmdFlagValueToArray(cli.flags['hook']))]
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: HOOKS_FAIL_ON.includes(failOn as HooksFailOn),
+      message: `The --fail-on flag must be ${joinOr([...HOOKS_FAIL_ON])}`,
+      fail: `got ${failOn}`,
+    },
+    {
+      nook: true,
+      test: FRAMEWORKS.includes(framework),
+      message: `The --framework flag must be ${joinOr(FRAMEWORKS)}`,
+      fail: `got ${framework}`,
+    },
+    {
+      nook: true,
+      test:
+        hooks.length > 0 &&
+        hooks.every(hook => HOOK_NAMES.includes(hook as HookName)),
+      message: `The --hook flag must be ${joinOr([...HOOK_NAMES])}`,
+      fail: `got ${hooks.join(', ') || 'nothing'}`,
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  await handleHooksInstall({
+    cwd: process.cwd(),
+    dryRun,
+    failOn: failOn as HooksFailOn,
+    force,
+    framework: framework as HandleHooksInstallConfig['framework'],
+    hooks: hooks as HookName[],
+    outputKind,
+  })
+}
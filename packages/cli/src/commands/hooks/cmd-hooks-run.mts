@@ -0,0 +1,142 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { handleHooksRun } from './handle-hooks-run.mts'
+import { HOOKS_FAIL_ON, HOOK_NAMES } from './hook-scripts.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { HookName, HooksFailOn } from './hook-scripts.mts'
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'run'
+
+const description =
+  'Check the dependencies a commit or push adds, as the installed hooks do'
+
+const hidden = false
+
+export const cmdHooksRun: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+async function readPushRefs(): Promise<string> {
+  // Git pipes the pushed refs to pre-push hooks; a terminal means a manual run.
+  if (process.stdin.isTTY) {
+    return ''
+  }
+  let input = ''
+  for await (const chunk of process.stdin) {
+    input += chunk
+  }
+  return input
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      failOn: {
+        type: 'string',
+        default: 'error',
+        description: `Alerts that fail the check: ${joinOr([...HOOKS_FAIL_ON])}`,
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <${HOOK_NAMES.join('|')}> [REMOTE]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    This is what the hooks of \`socket hooks install\` run. For pre-commit
+    it compares the staged lockfiles with HEAD; for pre-push it reads the
+    pushed refs from stdin like git passes them, and compares each with the
+    remote tip, or with the default branch of REMOTE for new branches.
+
+    Only package versions the lockfiles gain are looked up, through the
+    local score cache. The exit code is 3 when --fail-on is met.
+    SOCKET_CLI_SKIP_HOOKS=1 makes the check pass without looking.
+
+    Examples
+      $ ${command} pre-commit
+      $ ${command} pre-commit --fail-on warn --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, failOn, json, markdown } = cli.flags as {
+    dryRun: boolean
+    failOn: string
+    json: boolean
+    markdown: boolean
+  }
+  const { 0: hook = '', 1: remote } = cli.input
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: HOOK_NAMES.includes(hook as HookName),
+      message: `The hook to run must be ${joinOr([...HOOK_NAMES])}`,
+      fail: hook ? `got ${hook}` : 'missing',
+    },
+    {
+      nook: true,
+      test: HOOKS_FAIL_ON.includes(failOn as HooksFailOn),
+      message: `The --fail-on flag must be ${joinOr([...HOOKS_FAIL_ON])}`,
+      fail: `got ${failOn}`,
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch(`package verdicts for the ${hook} hook`, {
+      failOn,
+    })
+    return
+  }
+
+  await handleHooksRun({
+    cwd: process.cwd(),
+    failOn: failOn as HooksFailOn,
+    hook: hook as HookName,
+    outputKind,
+    pushRefs: hook === 'pre-push' ? await readPushRefs() : undefined,
+    remote,
+  })
+}
@@ -0,0 +1,93 @@
+import { handleHooksUninstall } from './handle-hooks-uninstall.mts'
+import { PRE_COMMIT_CONFIG_YAML } from './hook-scripts.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunWrite } from '../../util/dry-run/output.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'uninstall'
+
+const description = 'Remove the git hooks installed by `socket hooks install`'
+
+const hidden = false
+
+export const cmdHooksUninstall: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Deletes the hook scripts socket wrote, restoring hooks that
+    \`socket hooks install --force\` moved aside, and removes the socket
+    entries from ${PRE_COMMIT_CONFIG_YAML}. Other hooks are left alone.
+
+    Examples
+      $ ${command}
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, json, markdown } = cli.flags as {
+    dryRun: boolean
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(outputKind, {
+    nook: true,
+    test: !json || !markdown,
+    message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+    fail: 'bad',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunWrite(
+      `the git hooks directory and ${PRE_COMMIT_CONFIG_YAML}`,
+      'remove the Socket git hooks',
+    )
+    return
+  }
+
+  await handleHooksUninstall({ cwd: process.cwd(), outputKind })
+}
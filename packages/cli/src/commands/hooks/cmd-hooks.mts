@@ -0,0 +1,28 @@
+import { cmdHooksInstall } from './cmd-hooks-install.mts'
+import { cmdHooksRun } from './cmd-hooks-run.mts'
+import { cmdHooksUninstall } from './cmd-hooks-uninstall.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Check new dependencies in git hooks before commit and push'
+
+export const cmdHooks: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} hooks`,
+        importMeta,
+        subcommands: {
+          install: cmdHooksInstall,
+          run: cmdHooksRun,
+          uninstall: cmdHooksUninstall,
+        },
+      },
+      { description },
+    )
+  },
+}
@@ -0,0 +1,246 @@
+import { existsSync, promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { normalizePath } from '@socketsecurity/lib-stable/paths/normalize'
+
+import {
+  PRE_COMMIT_CONFIG_YAML,
+  SOCKET_HOOK_BACKUP_SUFFIX,
+  addPreCommitFrameworkHooks,
+  getGitHookScript,
+  isSocketHookScript,
+} from './hook-scripts.mts'
+import { outputHooksInstall } from './output-hooks-install.mts'
+import { outputDryRunDiff } from '../../util/dry-run/output.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import { gitHooksDir, gitTopLevel } from '../../util/git/operations.mjs'
+
+import type { HookName, HooksFailOn } from './hook-scripts.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type HooksFramework = 'git' | 'pre-commit'
+
+export type HooksInstallResult = {
+  // Foreign hooks moved aside by --force.
+  backups: string[]
+  failOn: HooksFailOn
+  // Written hook scripts or the updated pre-commit framework config.
+  files: string[]
+  framework: HooksFramework
+  hooks: HookName[]
+}
+
+export type HandleHooksInstallConfig = {
+  cwd: string
+  // Print the files that would be written instead of writing them.
+  dryRun?: boolean | undefined
+  failOn: HooksFailOn
+  force: boolean
+  // `auto` uses the pre-commit framework when the repo has a config for it.
+  framework: HooksFramework | 'auto'
+  hooks: HookName[]
+  outputKind: OutputKind
+}
+
+async function readFileOrUndefined(
+  filepath: string,
+): Promise<string | undefined> {
+  try {
+    return await fs.readFile(filepath, 'utf8')
+  } catch {}
+  return undefined
+}
+
+// A file the install writes, with its content before, if any.
+type HookFileChange = {
+  after: string
+  before: string | undefined
+  path: string
+}
+
+type HooksInstallPlan = {
+  changes: HookFileChange[]
+  // Foreign hooks to move aside first, with --force.
+  foreign: string[]
+  framework: HooksFramework
+}
+
+async function planPreCommitFrameworkHooks(
+  topLevel: string,
+  hooks: HookName[],
+  failOn: HooksFailOn,
+): Promise<CResult<HooksInstallPlan>> {
+  const configPath = path.join(topLevel, PRE_COMMIT_CONFIG_YAML)
+  const before = await readFileOrUndefined(configPath)
+  const configCResult = addPreCommitFrameworkHooks(before ?? '', hooks, failOn)
+  if (!configCResult.ok) {
+    return configCResult
+  }
+  return {
+    ok: true,
+    data: {
+      changes: [{ after: configCResult.data, before, path: configPath }],
+      foreign: [],
+      framework: 'pre-commit',
+    },
+  }
+}
+
+async function planGitHooks(
+  topLevel: string,
+  hooks: HookName[],
+  failOn: HooksFailOn,
+  force: boolean,
+): Promise<CResult<HooksInstallPlan>> {
+  const hooksDir = await gitHooksDir(topLevel)
+  if (!hooksDir) {
+    return {
+      ok: false,
+      message: 'Git Error',
+      cause: 'Could not find the hooks directory of this repository.',
+    }
+  }
+  // Check every hook before writing any, so a refusal leaves nothing behind.
+  const changes: HookFileChange[] = []
+  const foreign: string[] = []
+  for (const hook of hooks) {
+    const hookPath = path.join(hooksDir, hook)
+    const content = await readFileOrUndefined(hookPath)
+    const isForeign = content !== undefined && !isSocketHookScript(content)
+    if (isForeign) {
+      foreign.push(hookPath)
+    }
+    changes.push({
+      after: getGitHookScript(hook, failOn),
+      // A foreign hook is moved aside, so the hook is written anew.
+      before: isForeign ? undefined : content,
+      path: hookPath,
+    })
+  }
+  if (foreign.length && !force) {
+    return {
+      ok: false,
+      message: 'Hook already exists',
+      cause: `${foreign.join(', ')} was not written by socket. Rerun with --force to move it aside to ${path.basename(foreign[0]!)}${SOCKET_HOOK_BACKUP_SUFFIX}, or use --framework pre-commit.`,
+    }
+  }
+  return { ok: true, data: { changes, foreign, framework: 'git' } }
+}
+
+async function applyHooksInstallPlan(
+  plan: HooksInstallPlan,
+): Promise<CResult<{ backups: string[]; files: string[] }>> {
+  const { changes, foreign, framework } = plan
+  const backups: string[] = []
+  const files: string[] = []
+  try {
+    for (const hookPath of foreign) {
+      const backupPath = `${hookPath}${SOCKET_HOOK_BACKUP_SUFFIX}`
+      await fs.rename(hookPath, backupPath)
+      backups.push(backupPath)
+    }
+    for (const change of changes) {
+      if (framework === 'pre-commit') {
+        await fs.writeFile(change.path, change.after, 'utf8')
+      } else {
+        await fs.mkdir(path.dirname(change.path), { recursive: true })
+        await fs.writeFile(change.path, change.after, {
+          encoding: 'utf8',
+          mode: 0o755,
+        })
+        // writeFile only applies the mode to new files.
+        await fs.chmod(change.path, 0o755)
+      }
+      files.push(change.path)
+    }
+  } catch (e) {
+    return {
+      ok: false,
+      message:
+        framework === 'pre-commit'
+          ? `Failed to write ${PRE_COMMIT_CONFIG_YAML}`
+          : 'Failed to write git hooks',
+      cause: getErrorCause(e),
+    }
+  }
+  return { ok: true, data: { backups, files } }
+}
+
+/**
+ * Print the files the install would write as diffs against the repository.
+ */
+function outputHooksInstallDryRun(
+  topLevel: string,
+  { changes, foreign }: HooksInstallPlan,
+  failOn: HooksFailOn,
+): void {
+  const relative = (file: string) =>
+    normalizePath(path.relative(topLevel, file))
+  outputDryRunDiff(
+    `install git hooks, failing on ${failOn}`,
+    changes.map(change => ({ ...change, path: relative(change.path) })),
+    foreign.map(
+      hookPath =>
+        `Move ${relative(hookPath)} aside to ${relative(hookPath)}${SOCKET_HOOK_BACKUP_SUFFIX}`,
+    ),
+  )
+}
+
+export async function handleHooksInstall({
+  cwd,
+  dryRun,
+  failOn,
+  force,
+  framework,
+  hooks,
+  outputKind,
+}: HandleHooksInstallConfig): Promise<void> {
+  const topLevel = await gitTopLevel(cwd)
+  if (!topLevel) {
+    outputHooksInstall(
+      {
+        ok: false,
+        message: 'Not a git repository',
+        cause: `Run \`socket hooks install\` inside the git repository to protect, ${cwd} is not in one.`,
+      },
+      outputKind,
+    )
+    return
+  }
+
+  const resolvedFramework: HooksFramework =
+    framework === 'auto'
+      ? existsSync(path.join(topLevel, PRE_COMMIT_CONFIG_YAML))
+        ? 'pre-commit'
+        : 'git'
+      : framework
+
+  const planCResult =
+    resolvedFramework === 'pre-commit'
+      ? await planPreCommitFrameworkHooks(topLevel, hooks, failOn)
+      : await planGitHooks(topLevel, hooks, failOn, force)
+  if (!planCResult.ok) {
+    outputHooksInstall(planCResult, outputKind)
+    return
+  }
+  if (dryRun) {
+    outputHooksInstallDryRun(topLevel, planCResult.data, failOn)
+    return
+  }
+
+  const installCResult = await applyHooksInstallPlan(planCResult.data)
+  outputHooksInstall(
+    installCResult.ok
+      ? {
+          ok: true,
+          data: {
+            ...installCResult.data,
+            failOn,
+            framework: resolvedFramework,
+            hooks,
+          },
+        }
+      : installCResult,
+    outputKind,
+  )
+}
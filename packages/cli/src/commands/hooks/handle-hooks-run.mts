@@ -0,0 +1,272 @@
+import path from 'node:path'
+
+import { SOCKET_PUBLIC_API_TOKEN } from '@socketsecurity/lib-stable/constants/socket'
+
+import { outputHooksRun } from './output-hooks-run.mts'
+import { PACKAGE_LOCK_JSON, YARN_LOCK } from '../../constants/packages.mts'
+import { SOCKET_CLI_OFFLINE } from '../../env/socket-cli-offline.mts'
+import { getSocketCliSkipHooks } from '../../env/socket-cli-skip-hooks.mts'
+import {
+  GIT_EMPTY_TREE,
+  gitDiffChangedFiles,
+  gitMergeBase,
+  gitResolveCommit,
+  gitShowFile,
+  gitTopLevel,
+} from '../../util/git/operations.mjs'
+import { summarizeInstallRisks } from '../../util/install-check/check.mts'
+import { fetchPublishTimes } from '../../util/install-check/publish-time.mts'
+import { getLockfileParser } from '../../util/lockfile/parsers.mts'
+import { findSocketPolicySync } from '../../util/policy/socket-policy.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+import { getDefaultApiToken } from '../../util/socket/sdk.mjs'
+import {
+  diffDependencyVersions,
+  getDependencyVersions,
+} from '../fix/fix-changelog.mts'
+import { fetchPurlsShallowScore } from '../package/fetch-purls-shallow-score.mts'
+
+import type { HookName, HooksFailOn } from './hook-scripts.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { PURL_Type } from '../../util/ecosystem/types.mts'
+import type { InstallRisk } from '../../util/install-check/check.mts'
+
+export type HooksRunResult = {
+  blocked: InstallRisk[]
+  // Purls of the package versions the commit or push adds.
+  checked: string[]
+  // Why the packages could not be checked, when they could not.
+  error?: string | undefined
+  hook: HookName
+  passed: boolean
+  skipped: boolean
+  warned: InstallRisk[]
+}
+
+export type HandleHooksRunConfig = {
+  cwd: string
+  failOn: HooksFailOn
+  hook: HookName
+  outputKind: OutputKind
+  // The `<local ref> <local sha> <remote ref> <remote sha>` lines git feeds
+  // a pre-push hook on stdin.
+  pushRefs?: string | undefined
+  // Name of the remote pushed to, the first argument of a pre-push hook.
+  remote?: string | undefined
+}
+
+type RevisionRange = {
+  from: string
+  // Undefined compares with the index.
+  to: string | undefined
+}
+
+const NULL_SHA = /^0+$/
+
+const NPM_LOCKFILES = new Set([
+  PACKAGE_LOCK_JSON,
+  'npm-shrinkwrap.json',
+  YARN_LOCK,
+])
+
+// Only lockfiles pin the versions that will be installed; package.json
+// ranges are left to the lockfile change that usually comes with them.
+export function getLockfileEcosystem(file: string): PURL_Type | undefined {
+  if (NPM_LOCKFILES.has(path.basename(file))) {
+    return 'npm'
+  }
+  return getLockfileParser(file)?.ecosystem
+}
+
+/**
+ * Purl of a `namespace/name` as keyed by the lockfile version maps.
+ */
+export function getDependencyPurl(
+  type: PURL_Type,
+  fullName: string,
+  version: string,
+): string {
+  const slash = fullName.lastIndexOf('/')
+  return getArtifactPurlString({
+    name: slash === -1 ? fullName : fullName.slice(slash + 1),
+    namespace: slash === -1 ? undefined : fullName.slice(0, slash),
+    type,
+    version,
+  } as SocketArtifact)
+}
+
+async function getPushRanges(
+  pushRefs: string,
+  remote: string | undefined,
+  cwd: string,
+): Promise<RevisionRange[]> {
+  const ranges: RevisionRange[] = []
+  for (const line of pushRefs.split(/\r?\n/)) {
+    const { 1: localSha, 3: remoteSha } = line.trim().split(/\s+/)
+    // Deleting a remote ref adds nothing.
+    if (!localSha || NULL_SHA.test(localSha)) {
+      continue
+    }
+    let from =
+      remoteSha && !NULL_SHA.test(remoteSha)
+        ? await gitResolveCommit(remoteSha, cwd)
+        : undefined
+    // A new branch, or one whose remote tip is not fetched, is compared
+    // with the default branch of the remote.
+    if (!from && remote) {
+      from = await gitMergeBase(localSha, `refs/remotes/${remote}/HEAD`, cwd)
+    }
+    ranges.push({ from: from ?? GIT_EMPTY_TREE, to: localSha })
+  }
+  return ranges
+}
+
+async function getRanges(
+  hook: HookName,
+  pushRefs: string | undefined,
+  remote: string | undefined,
+  cwd: string,
+): Promise<RevisionRange[]> {
+  if (hook === 'pre-commit') {
+    const head = await gitResolveCommit('HEAD', cwd)
+    return [{ from: head ?? GIT_EMPTY_TREE, to: undefined }]
+  }
+  if (pushRefs?.trim()) {
+    return await getPushRanges(pushRefs, remote, cwd)
+  }
+  // The pre-commit framework passes the pushed range in the environment
+  // instead of on stdin.
+  const fromRef = process.env['PRE_COMMIT_FROM_REF']
+  const toRef = process.env['PRE_COMMIT_TO_REF']
+  if (fromRef && toRef) {
+    return [{ from: fromRef, to: toRef }]
+  }
+  return []
+}
+
+/**
+ * Purls of the package versions that lockfiles gain in a range.
+ */
+async function getAddedPurls(
+  range: RevisionRange,
+  cwd: string,
+): Promise<CResult<string[]>> {
+  const filesCResult = await gitDiffChangedFiles(range.from, range.to, cwd)
+  if (!filesCResult.ok) {
+    return filesCResult
+  }
+  const purls: string[] = []
+  for (const file of filesCResult.data) {
+    const ecosystem = getLockfileEcosystem(file)
+    if (!ecosystem) {
+      continue
+    }
+    // An empty revision reads the index.
+    const current = await gitShowFile(file, range.to ?? '', cwd)
+    const after =
+      current === undefined ? undefined : getDependencyVersions(file, current)
+    if (!after) {
+      continue
+    }
+    const previous = await gitShowFile(file, range.from, cwd)
+    const before =
+      (previous === undefined
+        ? undefined
+        : getDependencyVersions(file, previous)) ?? new Map()
+    for (const change of diffDependencyVersions(file, before, after)) {
+      for (const version of change.to) {
+        if (!change.from.includes(version)) {
+          purls.push(getDependencyPurl(ecosystem, change.name, version))
+        }
+      }
+    }
+  }
+  return { ok: true, data: purls }
+}
+
+async function runHook({
+  cwd,
+  failOn,
+  hook,
+  pushRefs,
+  remote,
+}: HandleHooksRunConfig): Promise<CResult<HooksRunResult>> {
+  const result: HooksRunResult = {
+    blocked: [],
+    checked: [],
+    hook,
+    passed: true,
+    skipped: false,
+    warned: [],
+  }
+  if (getSocketCliSkipHooks()) {
+    return { ok: true, data: { ...result, skipped: true } }
+  }
+  const topLevel = await gitTopLevel(cwd)
+  if (!topLevel) {
+    return {
+      ok: false,
+      message: 'Not a git repository',
+      cause: `${cwd} is not in a git repository.`,
+    }
+  }
+
+  const purls = new Set<string>()
+  for (const range of await getRanges(hook, pushRefs, remote, topLevel)) {
+    const purlsCResult = await getAddedPurls(range, topLevel)
+    if (!purlsCResult.ok) {
+      return purlsCResult
+    }
+    for (const purl of purlsCResult.data) {
+      purls.add(purl)
+    }
+  }
+  result.checked = [...purls].sort()
+  if (!result.checked.length) {
+    return { ok: true, data: result }
+  }
+
+  const policyCResult = findSocketPolicySync(topLevel)
+  if (!policyCResult.ok) {
+    return policyCResult
+  }
+  const scoreCResult = await fetchPurlsShallowScore(result.checked, {
+    commandPath: `socket hooks run ${hook}`,
+    offline: SOCKET_CLI_OFFLINE,
+    // Unauthenticated users still get malware verdicts from the public token.
+    sdkOpts: { apiToken: getDefaultApiToken() || SOCKET_PUBLIC_API_TOKEN },
+  })
+  if (!scoreCResult.ok) {
+    // A hook that fails whenever the API is unreachable gets uninstalled, so
+    // only the strictest level refuses unchecked packages.
+    result.error = scoreCResult.cause || scoreCResult.message
+    result.passed = failOn !== 'warn'
+    return { ok: true, data: result }
+  }
+
+  const artifacts = scoreCResult.data as unknown as SocketArtifact[]
+  const localPolicy = policyCResult.data?.policy
+  const summary = summarizeInstallRisks(
+    artifacts,
+    [],
+    localPolicy,
+    undefined,
+    // Offline runs have no registry to ask, so the window is not applied.
+    localPolicy?.quarantine && !SOCKET_CLI_OFFLINE
+      ? await fetchPublishTimes(artifacts)
+      : undefined,
+  )
+  result.blocked = summary.blocked
+  result.warned = summary.warned
+  result.passed =
+    failOn === 'never' ||
+    (!summary.blocked.length && (failOn !== 'warn' || !summary.warned.length))
+  return { ok: true, data: result }
+}
+
+export async function handleHooksRun(
+  config: HandleHooksRunConfig,
+): Promise<void> {
+  outputHooksRun(await runHook(config), config.outputKind)
+}
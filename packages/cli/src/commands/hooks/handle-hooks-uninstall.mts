@@ -0,0 +1,94 @@
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import {
+  HOOK_NAMES,
+  PRE_COMMIT_CONFIG_YAML,
+  SOCKET_HOOK_BACKUP_SUFFIX,
+  isSocketHookScript,
+  removePreCommitFrameworkHooks,
+} from './hook-scripts.mts'
+import { outputHooksUninstall } from './output-hooks-uninstall.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import { gitHooksDir, gitTopLevel } from '../../util/git/operations.mjs'
+
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type HooksUninstallResult = {
+  // Hook scripts deleted, or the pre-commit framework config edited.
+  removed: string[]
+  // Hooks moved back from where `socket hooks install --force` put them.
+  restored: string[]
+}
+
+export type HandleHooksUninstallConfig = {
+  cwd: string
+  outputKind: OutputKind
+}
+
+async function readFileOrUndefined(
+  filepath: string,
+): Promise<string | undefined> {
+  try {
+    return await fs.readFile(filepath, 'utf8')
+  } catch {}
+  return undefined
+}
+
+async function uninstallHooks(
+  topLevel: string,
+): Promise<CResult<HooksUninstallResult>> {
+  const removed: string[] = []
+  const restored: string[] = []
+  try {
+    const hooksDir = (await gitHooksDir(topLevel)) ?? ''
+    for (const hook of hooksDir ? HOOK_NAMES : []) {
+      const hookPath = path.join(hooksDir, hook)
+      const content = await readFileOrUndefined(hookPath)
+      if (content === undefined || !isSocketHookScript(content)) {
+        continue
+      }
+      await fs.rm(hookPath)
+      removed.push(hookPath)
+      const backupPath = `${hookPath}${SOCKET_HOOK_BACKUP_SUFFIX}`
+      if ((await readFileOrUndefined(backupPath)) !== undefined) {
+        await fs.rename(backupPath, hookPath)
+        restored.push(hookPath)
+      }
+    }
+    const configPath = path.join(topLevel, PRE_COMMIT_CONFIG_YAML)
+    const source = await readFileOrUndefined(configPath)
+    const updated =
+      source === undefined ? undefined : removePreCommitFrameworkHooks(source)
+    if (updated !== undefined) {
+      await fs.writeFile(configPath, updated, 'utf8')
+      removed.push(configPath)
+    }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Failed to remove git hooks',
+      cause: getErrorCause(e),
+    }
+  }
+  return { ok: true, data: { removed, restored } }
+}
+
+export async function handleHooksUninstall({
+  cwd,
+  outputKind,
+}: HandleHooksUninstallConfig): Promise<void> {
+  const topLevel = await gitTopLevel(cwd)
+  if (!topLevel) {
+    outputHooksUninstall(
+      {
+        ok: false,
+        message: 'Not a git repository',
+        cause: `${cwd} is not in a git repository.`,
+      },
+      outputKind,
+    )
+    return
+  }
+  outputHooksUninstall(await uninstallHooks(topLevel), outputKind)
+}
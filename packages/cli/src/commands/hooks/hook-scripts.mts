@@ -0,0 +1,163 @@
+/**
+ * The git hooks `socket hooks install` writes, either as scripts in the hooks
+ * directory or as `repo: local` entries of a pre-commit framework config.
+ * Both run `socket hooks run <hook>`; the script form also passes when
+ * `socket` is not on PATH, so a teammate without the CLI can still commit.
+ */
+
+import { isMap, isSeq, parseDocument } from 'yaml'
+
+import type { CResult } from '../../types.mts'
+
+export const HOOK_NAMES = ['pre-commit', 'pre-push'] as const
+
+export type HookName = (typeof HOOK_NAMES)[number]
+
+// `error` fails on alerts whose policy action is error, `warn` also on warn
+// actions and on packages that could not be checked, `never` only reports.
+export const HOOKS_FAIL_ON = ['error', 'warn', 'never'] as const
+
+export type HooksFailOn = (typeof HOOKS_FAIL_ON)[number]
+
+export const PRE_COMMIT_CONFIG_YAML = '.pre-commit-config.yaml'
+
+// Hook scripts carrying this line were written by socket and may be replaced
+// or removed without asking.
+export const SOCKET_HOOK_MARKER = '# socket-cli-hook'
+
+// Suffix of a foreign hook moved aside by `socket hooks install --force`.
+export const SOCKET_HOOK_BACKUP_SUFFIX = '.socket-backup'
+
+export function getHookCommand(hook: HookName, failOn: HooksFailOn): string {
+  return `socket hooks run ${hook} --fail-on ${failOn}`
+}
+
+export function getPreCommitHookId(hook: HookName): string {
+  return `socket-${hook}`
+}
+
+export function getGitHookScript(hook: HookName, failOn: HooksFailOn): string {
+  const action = hook === 'pre-push' ? 'push' : 'commit'
+  return `#!/bin/sh
+${SOCKET_HOOK_MARKER}
+# Checks the dependencies this ${action} adds against Socket.
+# Written by \`socket hooks install\`, removed by \`socket hooks uninstall\`.
+# Skip it once with \`git ${action} --no-verify\` or SOCKET_CLI_SKIP_HOOKS=1.
+case "$SOCKET_CLI_SKIP_HOOKS" in
+  ''|0|false) ;;
+  *) exit 0 ;;
+esac
+if ! command -v socket >/dev/null 2>&1; then
+  echo "socket: command not found, skipping the Socket ${hook} check" >&2
+  exit 0
+fi
+exec ${getHookCommand(hook, failOn)} "$@"
+`
+}
+
+export function isSocketHookScript(content: string): boolean {
+  // Hooks edited on Windows may have CRLF line endings.
+  return content.split(/\r?\n/).includes(SOCKET_HOOK_MARKER)
+}
+
+function parsePreCommitConfig(source: string) {
+  const doc = parseDocument(source, { keepSourceTokens: true })
+  if (doc.errors.length) {
+    return undefined
+  }
+  return doc
+}
+
+// Drop the socket hooks from the `repo: local` entries of a parsed config,
+// and local entries left without hooks. Returns whether anything changed.
+function removeSocketHooks(
+  doc: ReturnType<typeof parseDocument>,
+  ids: string[],
+): boolean {
+  const repos = doc.get('repos', true)
+  if (!isSeq(repos)) {
+    return false
+  }
+  let changed = false
+  repos.items = repos.items.filter(repo => {
+    if (!isMap(repo) || repo.get('repo') !== 'local') {
+      return true
+    }
+    const hooks = repo.get('hooks', true)
+    if (!isSeq(hooks)) {
+      return true
+    }
+    const { length } = hooks.items
+    hooks.items = hooks.items.filter(
+      hook => !isMap(hook) || !ids.includes(String(hook.get('id'))),
+    )
+    if (hooks.items.length === length) {
+      return true
+    }
+    changed = true
+    return hooks.items.length > 0
+  })
+  return changed
+}
+
+function stringifyPreCommitConfig(
+  doc: ReturnType<typeof parseDocument>,
+): string {
+  return doc.toString({ indent: 2, lineWidth: 0, minContentWidth: 0 })
+}
+
+/**
+ * Add (or replace) the socket hooks in the text of a pre-commit framework
+ * config. Comments and the other repos survive.
+ */
+export function addPreCommitFrameworkHooks(
+  source: string,
+  hooks: readonly HookName[],
+  failOn: HooksFailOn,
+): CResult<string> {
+  const doc = parsePreCommitConfig(source)
+  if (!doc) {
+    return {
+      ok: false,
+      message: `Invalid ${PRE_COMMIT_CONFIG_YAML}`,
+      cause: 'The file is not valid YAML, fix it before adding hooks to it.',
+    }
+  }
+  if (!source.trim()) {
+    doc.contents = doc.createNode({ repos: [] })
+  }
+  removeSocketHooks(doc, HOOK_NAMES.map(getPreCommitHookId))
+  const entry = doc.createNode({
+    repo: 'local',
+    hooks: hooks.map(hook => ({
+      id: getPreCommitHookId(hook),
+      name: `Socket ${hook} dependency check`,
+      entry: getHookCommand(hook, failOn),
+      language: 'system',
+      pass_filenames: false,
+      always_run: true,
+      stages: [hook],
+    })),
+  })
+  const repos = doc.get('repos', true)
+  if (isSeq(repos)) {
+    repos.add(entry)
+  } else {
+    doc.set('repos', [entry])
+  }
+  return { ok: true, data: stringifyPreCommitConfig(doc) }
+}
+
+/**
+ * Remove the socket hooks from the text of a pre-commit framework config.
+ * Returns undefined when the config has none.
+ */
+export function removePreCommitFrameworkHooks(
+  source: string,
+): string | undefined {
+  const doc = parsePreCommitConfig(source)
+  if (!doc || !removeSocketHooks(doc, HOOK_NAMES.map(getPreCommitHookId))) {
+    return undefined
+  }
+  return stringifyPreCommitConfig(doc)
+}
@@ -0,0 +1,46 @@
+import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { HooksInstallResult } from './handle-hooks-install.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export function outputHooksInstall(
+  result: CResult<HooksInstallResult>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { backups, failOn, files, framework, hooks } = result.data
+  for (const backup of backups) {
+    logger.info(`Moved the existing hook aside to ${backup}`)
+  }
+  logger.success(
+    `Installed the Socket ${joinAnd(hooks)} ${pluralize('hook', { count: hooks.length })} in ${joinAnd(files)}, failing on ${failOn === 'never' ? 'nothing' : `${failOn} alerts`}`,
+  )
+  if (framework === 'pre-commit') {
+    logger.info(
+      `Run \`pre-commit install --hook-type ${hooks.join(' --hook-type ')}\` if the framework is not installed for its ${pluralize('hook type', { count: hooks.length })} yet.`,
+    )
+  }
+  logger.info(
+    'Skip a check once with `--no-verify` or SOCKET_CLI_SKIP_HOOKS=1, remove the hooks with `socket hooks uninstall`.',
+  )
+}
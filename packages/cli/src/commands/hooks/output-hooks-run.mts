@@ -0,0 +1,68 @@
+import { debugNs } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { formatInstallRisk } from '../../util/install-check/check.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { HooksRunResult } from './handle-hooks-run.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export function outputHooksRun(
+  result: CResult<HooksRunResult>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (!result.data.passed) {
+    // The check ran but the commit or push should not go through.
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { blocked, checked, error, hook, passed, skipped, warned } =
+    result.data
+  if (skipped) {
+    debugNs('notice', `Skipped the Socket ${hook} check`)
+    return
+  }
+  // Stay quiet on the many commits that touch no lockfile.
+  if (!checked.length) {
+    debugNs('notice', 'No new package versions to check')
+    return
+  }
+
+  const count = `${checked.length} new package ${pluralize('version', { count: checked.length })}`
+  if (error && passed) {
+    logger.warn(`Socket could not check ${count}: ${error}`)
+  } else if (error) {
+    logger.fail(`Socket could not check ${count}: ${error}`)
+  }
+  for (const risk of blocked) {
+    logger.fail(formatInstallRisk(risk))
+  }
+  for (const risk of warned) {
+    logger.warn(formatInstallRisk(risk))
+  }
+  if (!passed) {
+    const gitCommand = hook === 'pre-push' ? 'git push' : 'git commit'
+    logger.fail(
+      `Socket ${hook} check failed. Update the dependencies, or skip the check once with \`${gitCommand} --no-verify\` or SOCKET_CLI_SKIP_HOOKS=1.`,
+    )
+  } else if (!error && !blocked.length && !warned.length) {
+    logger.success(`Socket checked ${count}`)
+  }
+}
@@ -0,0 +1,40 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { HooksUninstallResult } from './handle-hooks-uninstall.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export function outputHooksUninstall(
+  result: CResult<HooksUninstallResult>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { removed, restored } = result.data
+  if (!removed.length) {
+    logger.info('No Socket hooks are installed in this repository')
+    return
+  }
+  for (const file of removed) {
+    logger.success(`Removed the Socket hooks from ${file}`)
+  }
+  for (const file of restored) {
+    logger.info(`Restored the previous hook ${file}`)
+  }
+}
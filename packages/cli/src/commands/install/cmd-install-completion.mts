@@ -27,7 +27,8 @@ const config = {
     Afterwards you should be able to type \`socket \` and then press tab to
     have bash auto-complete/suggest the sub/command or flags.
 
-    Currently only supports bash.
+    Currently only supports bash. For zsh, fish and PowerShell, and completion
+    of org slugs, repo names and scan IDs, see \`socket completion\`.
 
     The optional name argument allows you to enable tab completion on a command
     name other than "socket". Mostly for debugging but also useful if you use a
@@ -0,0 +1,169 @@
+import { handleLicenseCheck } from './handle-license-check.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'check'
+
+const description = 'Check the licenses of a scan against socket.policy.yml'
+
+const hidden = false
+
+export const cmdLicenseCheck: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      allowUnknown: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Report packages without a known license but do not fail on them',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      policy: {
+        type: 'string',
+        default: '',
+        description:
+          'Path to the policy file, defaults to the nearest socket.policy.yml',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Checks the declared license of every package in a completed scan against
+    the \`licenses\` allow and deny lists of a socket.policy.yml, see
+    \`socket policy lint --help\`. An SPDX expression with OR passes when any
+    alternative is acceptable. The check fails, with exit code 3, on denied
+    licenses and on packages without a known license (missing or
+    NOASSERTION), unless --allow-unknown is given. Exceptions for the
+    \`licensePolicyViolation\` alert waive both.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --policy legal/socket.policy.yml
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --allow-unknown --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    allowUnknown,
+    dryRun,
+    interactive,
+    json,
+    markdown,
+    org: orgFlag,
+    policy,
+  } = cli.flags
+
+  const [scanId = ''] = cli.input
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(orgFlag, interactive, dryRun)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID to check',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scan for license check', {
+      organization: orgSlug,
+      scanId,
+      policy: policy || 'nearest socket.policy.yml',
+      allowUnknown,
+    })
+    return
+  }
+
+  await handleLicenseCheck({
+    allowUnknown,
+    cwd: process.cwd(),
+    orgSlug,
+    outputKind,
+    policy,
+    scanId,
+  })
+}
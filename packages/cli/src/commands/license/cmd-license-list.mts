@@ -0,0 +1,183 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { handleLicenseList } from './handle-license-list.mts'
+import {
+  LICENSE_CSV_COLUMNS,
+  LICENSE_CSV_DEFAULT_COLUMNS,
+} from './output-license-list.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, csvFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getCsvColumns, getUnknownCsvColumns } from '../../util/output/csv.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { LicenseCsvColumn } from './output-license-list.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'list'
+
+const description = 'List the licenses of the packages in a scan'
+
+const hidden = false
+
+export const cmdLicenseList: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      ...csvFlags,
+      csv: {
+        type: 'boolean',
+        default: false,
+        description: 'Output the packages and their licenses as CSV',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Lists every package of a completed scan with its declared SPDX license
+    expression, and rolls them up into the number of packages per license.
+    Packages without license data are listed as NOASSERTION.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    With --csv, pick the columns, in order, with --columns: purl, package,
+    version, ecosystem, license and direct. The default is
+    ${LICENSE_CSV_DEFAULT_COLUMNS.join(',')}. Use --no-csv-header to leave out the header row.
+
+    Examples
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --csv > licenses.csv
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --csv --columns=package,version,license
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --markdown
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { csv, dryRun, interactive, json, markdown, org: orgFlag } = cli.flags
+
+  const [scanId = ''] = cli.input
+
+  const columns = getCsvColumns(
+    cli.flags['columns'],
+    LICENSE_CSV_DEFAULT_COLUMNS,
+  )
+
+  const unknownColumns = getUnknownCsvColumns(columns, LICENSE_CSV_COLUMNS)
+
+  const csvHeader = !!cli.flags['csvHeader']
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(orgFlag, interactive, dryRun)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID to list the licenses of',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: [json, markdown, csv].filter(Boolean).length <= 1,
+      message: `Only one of \`${FLAG_JSON}\`, \`${FLAG_MARKDOWN}\` and \`--csv\` can be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: !!csv || (!cli.flags['columns'] && csvHeader),
+      message: 'The --columns and --no-csv-header flags require --csv',
+      fail: 'add --csv',
+    },
+    {
+      nook: true,
+      test: !unknownColumns.length,
+      message: `Every --columns name must be ${joinOr(LICENSE_CSV_COLUMNS.map(c => `'${c}'`))}`,
+      fail: `got ${unknownColumns.join(', ')}`,
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scan for license inventory', {
+      organization: orgSlug,
+      scanId,
+      ...(csv ? { columns: columns.join(', '), csvHeader } : {}),
+    })
+    return
+  }
+
+  await handleLicenseList({
+    columns: columns as LicenseCsvColumn[],
+    csvHeader,
+    orgSlug,
+    outputKind: csv ? 'csv' : outputKind,
+    scanId,
+  })
+}
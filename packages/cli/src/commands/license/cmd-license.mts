@@ -0,0 +1,44 @@
+import { cmdLicenseCheck } from './cmd-license-check.mts'
+import { cmdLicenseList } from './cmd-license-list.mts'
+import { cmdOrganizationPolicyLicense } from '../organization/cmd-organization-policy-license.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Report and check the licenses of scanned packages'
+
+// `socket license` used to be a shortcut for the org license policy. It stays
+// the default subcommand so that keeps working, and runs under its canonical
+// `socket organization policy license` path so its help and API requirements
+// match that command.
+const cmdLicensePolicy: CliSubcommand = {
+  description: cmdOrganizationPolicyLicense.description,
+  hidden: cmdOrganizationPolicyLicense.hidden,
+  async run(argv, importMeta, { parentName }) {
+    await cmdOrganizationPolicyLicense.run(argv, importMeta, {
+      parentName: `${parentName.replace(/ license$/, '')} organization policy`,
+    })
+  },
+}
+
+export const cmdLicense: CliSubcommand = {
+  description,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} license`,
+        importMeta,
+        subcommands: {
+          check: cmdLicenseCheck,
+          list: cmdLicenseList,
+          policy: cmdLicensePolicy,
+        },
+      },
+      {
+        description,
+        defaultSub: 'policy',
+      },
+    )
+  },
+}
@@ -0,0 +1,83 @@
+import path from 'node:path'
+
+import { checkLicenses } from './license-inventory.mts'
+import { outputLicenseCheck } from './output-license-check.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+import { SOCKET_POLICY_YML } from '../../constants/socket.mts'
+import {
+  findSocketPolicySync,
+  readSocketPolicySync,
+} from '../../util/policy/socket-policy.mts'
+
+import type { LicenseCheckResult } from './license-inventory.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { FoundSocketPolicy } from '../../util/policy/socket-policy.mts'
+
+export type HandleLicenseCheckConfig = {
+  allowUnknown: boolean
+  cwd: string
+  orgSlug: string
+  outputKind: OutputKind
+  policy: string
+  scanId: string
+}
+
+function resolvePolicy(
+  cwd: string,
+  policy: string,
+): CResult<FoundSocketPolicy> {
+  if (policy) {
+    const policyPath = path.resolve(cwd, policy)
+    const policyCResult = readSocketPolicySync(policyPath)
+    return policyCResult.ok
+      ? { ok: true, data: { path: policyPath, policy: policyCResult.data } }
+      : policyCResult
+  }
+  const foundCResult = findSocketPolicySync(cwd)
+  if (!foundCResult.ok) {
+    return foundCResult
+  }
+  if (!foundCResult.data) {
+    return {
+      ok: false,
+      message: 'No license policy',
+      cause: `There is no ${SOCKET_POLICY_YML} in ${cwd} or above it; pass one with --policy`,
+    }
+  }
+  return { ok: true, data: foundCResult.data }
+}
+
+export async function handleLicenseCheck({
+  allowUnknown,
+  cwd,
+  orgSlug,
+  outputKind,
+  policy,
+  scanId,
+}: HandleLicenseCheckConfig): Promise<void> {
+  const policyCResult = resolvePolicy(cwd, policy)
+  if (!policyCResult.ok) {
+    await outputLicenseCheck(policyCResult, outputKind)
+    return
+  }
+  const { path: policyPath, policy: socketPolicy } = policyCResult.data
+
+  const scanCResult = await fetchScan(orgSlug, scanId)
+
+  const result: CResult<LicenseCheckResult> = scanCResult.ok
+    ? {
+        ok: true,
+        data: checkLicenses(scanCResult.data, socketPolicy, {
+          allowUnknown,
+          policyPath,
+          scanId,
+        }),
+      }
+    : scanCResult
+
+  await outputLicenseCheck(result, outputKind, {
+    hasLicenseLists:
+      !!socketPolicy.licenses.allow.length ||
+      !!socketPolicy.licenses.deny.length,
+  })
+}
@@ -0,0 +1,35 @@
+import { getLicenseInventory } from './license-inventory.mts'
+import { outputLicenseList } from './output-license-list.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+
+import type {
+  LicenseCsvColumn,
+  LicenseListOutputKind,
+} from './output-license-list.mts'
+
+export type HandleLicenseListConfig = {
+  // Columns and header row of the csv output, see --columns.
+  columns?: readonly LicenseCsvColumn[] | undefined
+  csvHeader?: boolean | undefined
+  orgSlug: string
+  outputKind: LicenseListOutputKind
+  scanId: string
+}
+
+export async function handleLicenseList({
+  columns,
+  csvHeader,
+  orgSlug,
+  outputKind,
+  scanId,
+}: HandleLicenseListConfig): Promise<void> {
+  const scanCResult = await fetchScan(orgSlug, scanId)
+
+  await outputLicenseList(
+    scanCResult.ok
+      ? { ok: true, data: getLicenseInventory(scanId, scanCResult.data) }
+      : scanCResult,
+    outputKind,
+    { columns, header: csvHeader },
+  )
+}
@@ -0,0 +1,154 @@
+/**
+ * License inventory of a scan for `socket license list`, and the check of
+ * that inventory against the license lists of a `socket.policy.yml` for
+ * `socket license check`.
+ *
+ * Each package keeps its declared SPDX expression; the roll-up counts the
+ * packages per license id in those expressions. Packages without license
+ * data, or with `NOASSERTION`, are reported as unknown.
+ */
+
+import { SPDX_NOASSERTION } from '../sbom/generate-spdx.mts'
+import {
+  getLocalLicenseViolation,
+  isPolicyException,
+  LOCAL_LICENSE_POLICY_ALERT,
+  splitSpdxAlternatives,
+} from '../../util/policy/evaluate.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { SocketPolicy } from '../../util/policy/socket-policy.mts'
+
+export type LicensePackage = {
+  direct: boolean
+  // License ids in the expression, without `WITH` exceptions.
+  ids: string[]
+  // The declared SPDX expression, or NOASSERTION when unknown.
+  license: string
+  purl: string
+}
+
+export type LicenseSummary = {
+  id: string
+  packages: number
+}
+
+export type LicenseInventory = {
+  licenses: LicenseSummary[]
+  packages: LicensePackage[]
+  scanId: string
+  unknown: number
+}
+
+export type LicenseViolation = {
+  license: string
+  purl: string
+}
+
+export type LicenseCheckResult = {
+  allowUnknown: boolean
+  denied: LicenseViolation[]
+  passed: boolean
+  policyPath: string
+  scanId: string
+  unknown: string[]
+}
+
+const UNKNOWN_LICENSES = new Set([SPDX_NOASSERTION, 'UNKNOWN'])
+
+export function isUnknownLicense(expression: string | undefined): boolean {
+  const trimmed = expression?.trim()
+  return !trimmed || UNKNOWN_LICENSES.has(trimmed.toUpperCase())
+}
+
+// Scans can list a package more than once, e.g. once per manifest.
+function getUniqueArtifacts(artifacts: SocketArtifact[]): SocketArtifact[] {
+  const byPurl = new Map<string, SocketArtifact>()
+  for (let i = 0, { length } = artifacts; i < length; i += 1) {
+    const artifact = artifacts[i]!
+    const purl = getArtifactPurlString(artifact)
+    const seen = byPurl.get(purl)
+    if (!seen || (!seen.license && artifact.license)) {
+      byPurl.set(purl, artifact)
+    }
+  }
+  return [...byPurl.values()]
+}
+
+export function getLicenseInventory(
+  scanId: string,
+  artifacts: SocketArtifact[],
+): LicenseInventory {
+  const counts = new Map<string, number>()
+  const packages: LicensePackage[] = []
+  let unknown = 0
+  for (const artifact of getUniqueArtifacts(artifacts)) {
+    const expression = artifact.license?.trim()
+    const isUnknown = isUnknownLicense(expression)
+    const ids = isUnknown
+      ? [SPDX_NOASSERTION]
+      : [...new Set(splitSpdxAlternatives(expression!).flat())]
+    if (isUnknown) {
+      unknown += 1
+    }
+    for (const id of ids) {
+      counts.set(id, (counts.get(id) ?? 0) + 1)
+    }
+    packages.push({
+      direct: !!artifact.direct,
+      ids,
+      license: isUnknown ? SPDX_NOASSERTION : expression!,
+      purl: getArtifactPurlString(artifact),
+    })
+  }
+  return {
+    licenses: [...counts]
+      .map(({ 0: id, 1: count }) => ({ id, packages: count }))
+      .toSorted((a, b) => b.packages - a.packages || a.id.localeCompare(b.id)),
+    packages: packages.toSorted((a, b) => a.purl.localeCompare(b.purl)),
+    scanId,
+    unknown,
+  }
+}
+
+/**
+ * Check every package of a scan against the license lists of a policy.
+ * Packages with an unknown license fail the check unless `allowUnknown` is
+ * set or a policy exception for `licensePolicyViolation` covers them.
+ */
+export function checkLicenses(
+  artifacts: SocketArtifact[],
+  policy: SocketPolicy,
+  {
+    allowUnknown,
+    policyPath,
+    scanId,
+  }: { allowUnknown: boolean; policyPath: string; scanId: string },
+): LicenseCheckResult {
+  const denied: LicenseViolation[] = []
+  const unknown: string[] = []
+  for (const artifact of getUniqueArtifacts(artifacts)) {
+    const purl = getArtifactPurlString(artifact)
+    if (isUnknownLicense(artifact.license)) {
+      if (!isPolicyException(policy, artifact, LOCAL_LICENSE_POLICY_ALERT)) {
+        unknown.push(purl)
+      }
+      continue
+    }
+    const license = getLocalLicenseViolation(policy, artifact)
+    if (license) {
+      denied.push({ license, purl })
+    }
+  }
+  denied.sort((a, b) => a.purl.localeCompare(b.purl))
+  unknown.sort()
+  return {
+    allowUnknown,
+    denied,
+    passed: !denied.length && (allowUnknown || !unknown.length),
+    policyPath,
+    scanId,
+    unknown,
+  }
+}
@@ -0,0 +1,113 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { LicenseCheckResult } from './license-inventory.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export type OutputLicenseCheckOptions = {
+  // Whether the policy has an allow or deny list at all.
+  hasLicenseLists?: boolean | undefined
+}
+
+function getVerdict(data: LicenseCheckResult): string {
+  const { allowUnknown, denied, passed, unknown } = data
+  if (passed) {
+    return 'The scan passed the license policy'
+  }
+  const problems = []
+  if (denied.length) {
+    problems.push(
+      `${denied.length} ${pluralize('package', { count: denied.length })} with a denied license`,
+    )
+  }
+  if (unknown.length && !allowUnknown) {
+    problems.push(
+      `${unknown.length} ${pluralize('package', { count: unknown.length })} with an unknown license`,
+    )
+  }
+  return `The scan failed the license policy: ${problems.join(' and ')}`
+}
+
+export async function outputLicenseCheck(
+  result: CResult<LicenseCheckResult>,
+  outputKind: OutputKind,
+  options?: OutputLicenseCheckOptions | undefined,
+): Promise<void> {
+  const { hasLicenseLists = true } = {
+    __proto__: null,
+    ...options,
+  } as OutputLicenseCheckOptions
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (!result.data.passed) {
+    // The check ran but the scan has licenses the policy does not accept.
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { data } = result
+  const { allowUnknown, denied, passed, policyPath, unknown } = data
+  const noListsNote = `${policyPath} has no license allow or deny list, so only unknown licenses are checked`
+  const deniedLines = denied.map(v => `${v.purl}: ${v.license}`)
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('License Check'))
+    logger.log('')
+    logger.log(`Result: ${passed ? 'passed' : 'failed'}`)
+    if (!hasLicenseLists) {
+      logger.log('')
+      logger.log(`Note: ${noListsNote}.`)
+    }
+    if (denied.length) {
+      logger.log('')
+      logger.log(mdHeader('Denied licenses', 2))
+      logger.log('')
+      logger.log(mdList(deniedLines))
+    }
+    if (unknown.length) {
+      logger.log('')
+      logger.log(
+        mdHeader(`Unknown licenses${allowUnknown ? ' (allowed)' : ''}`, 2),
+      )
+      logger.log('')
+      logger.log(mdList(unknown))
+    }
+    return
+  }
+
+  if (!hasLicenseLists) {
+    logger.warn(noListsNote)
+  }
+  for (const line of deniedLines) {
+    logger.error(line)
+  }
+  for (const purl of unknown) {
+    const line = `${purl}: unknown license`
+    if (allowUnknown) {
+      logger.warn(line)
+    } else {
+      logger.error(line)
+    }
+  }
+  if (passed) {
+    logger.success(getVerdict(data))
+  } else {
+    logger.fail(getVerdict(data))
+  }
+}
@@ -0,0 +1,153 @@
+import chalkTable from 'chalk-table'
+import colors from 'yoctocolors-cjs'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { SPDX_NOASSERTION } from '../sbom/generate-spdx.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { formatCsvRecords } from '../../util/output/csv.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+import { getPurlObject } from '../../util/purl/parse.mts'
+
+import type { LicenseInventory } from './license-inventory.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { FormatCsvOptions } from '../../util/output/csv.mts'
+
+const logger = getDefaultLogger()
+
+export type LicenseListOutputKind = OutputKind | 'csv'
+
+export const LICENSE_CSV_COLUMNS = [
+  'purl',
+  'package',
+  'version',
+  'ecosystem',
+  'license',
+  'direct',
+] as const
+
+export type LicenseCsvColumn = (typeof LICENSE_CSV_COLUMNS)[number]
+
+export const LICENSE_CSV_DEFAULT_COLUMNS: readonly LicenseCsvColumn[] = [
+  'purl',
+  'license',
+  'direct',
+]
+
+export type LicenseCsvOptions = FormatCsvOptions & {
+  columns?: readonly LicenseCsvColumn[] | undefined
+}
+
+export function formatLicenseCsv(
+  inventory: LicenseInventory,
+  { columns = LICENSE_CSV_DEFAULT_COLUMNS, ...options }: LicenseCsvOptions = {},
+): string {
+  return formatCsvRecords(
+    columns,
+    inventory.packages.map(p => {
+      const purlObj = getPurlObject(p.purl, { throws: false })
+      const name = purlObj?.name ?? ''
+      return {
+        direct: String(p.direct),
+        ecosystem: purlObj?.type ?? '',
+        license: p.license,
+        package: purlObj?.namespace ? `${purlObj.namespace}/${name}` : name,
+        purl: p.purl,
+        version: purlObj?.version ?? '',
+      }
+    }),
+    options,
+  )
+}
+
+function getSummary(inventory: LicenseInventory): string {
+  const { licenses, packages, unknown } = inventory
+  const known = licenses.filter(l => l.id !== SPDX_NOASSERTION).length
+  return `${packages.length} ${pluralize('package', { count: packages.length })} under ${known} ${pluralize('license', { count: known })}; ${unknown} without a known license.`
+}
+
+export async function outputLicenseList(
+  result: CResult<LicenseInventory>,
+  outputKind: LicenseListOutputKind,
+  csvOptions?: LicenseCsvOptions | undefined,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const inventory = result.data
+
+  if (outputKind === 'csv') {
+    logger.log(formatLicenseCsv(inventory, csvOptions))
+    return
+  }
+
+  const licenseRows = inventory.licenses.map(l => ({
+    id: l.id,
+    packages: String(l.packages),
+  }))
+  const packageRows = inventory.packages.map(p => ({
+    direct: p.direct ? 'yes' : '',
+    license: p.license,
+    purl: p.purl,
+  }))
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('License Inventory'))
+    logger.log('')
+    logger.log(`Scan ${inventory.scanId}: ${getSummary(inventory)}`)
+    logger.log('')
+    logger.log(mdHeader('Licenses', 2))
+    logger.log('')
+    logger.log(
+      mdTable(licenseRows, ['id', 'packages'], ['License', 'Packages']),
+    )
+    logger.log('')
+    logger.log(mdHeader('Packages', 2))
+    logger.log('')
+    logger.log(
+      mdTable(
+        packageRows,
+        ['purl', 'license', 'direct'],
+        ['Package', 'License', 'Direct'],
+      ),
+    )
+    return
+  }
+
+  logger.log(getSummary(inventory))
+  logger.log(
+    chalkTable(
+      {
+        columns: [
+          { field: 'id', name: colors.magenta('License') },
+          { field: 'packages', name: colors.magenta('Packages') },
+        ],
+      },
+      licenseRows,
+    ),
+  )
+  logger.log(
+    chalkTable(
+      {
+        columns: [
+          { field: 'purl', name: colors.magenta('Package') },
+          { field: 'license', name: colors.magenta('License') },
+          { field: 'direct', name: colors.magenta('Direct') },
+        ],
+      },
+      packageRows,
+    ),
+  )
+}
@@ -1,21 +1,90 @@
 import {
+  CONFIG_KEY_ACTIVE_PROFILE,
   CONFIG_KEY_API_BASE_URL,
   CONFIG_KEY_API_PROXY,
   CONFIG_KEY_API_TOKEN,
   CONFIG_KEY_ENFORCED_ORGS,
+  DEFAULT_PROFILE_NAME,
 } from '../../constants/config.mts'
-import { updateConfigValue } from '../../util/config.mts'
+import {
+  getActiveProfileName,
+  isApiTokenInKeychain,
+  isConfigFromFlag,
+  updateConfigValue,
+  updateProfileConfigValue,
+} from '../../util/config.mts'
+import {
+  deleteKeychainToken,
+  isKeychainEnabled,
+  KEYCHAIN_API_TOKEN_REF,
+  storeKeychainToken,
+} from '../../util/keychain.mts'
 import { invalidateDefaultApiToken } from '../../util/socket/sdk.mts'
 
+import type { CResult } from '../../types.mts'
+import type { LocalConfig, ProfileConfigKey } from '../../util/config.mts'
+
+export type ApiTokenStorage = 'config' | 'keychain'
+
+function updateLoginValue<Key extends ProfileConfigKey>(
+  profile: string | undefined,
+  key: Key,
+  value: LocalConfig[Key],
+) {
+  return profile
+    ? updateProfileConfigValue(profile, key, value)
+    : updateConfigValue(key, value)
+}
+
 export function applyLogin(
   apiToken: string,
   enforcedOrgs: string[],
   apiBaseUrl: string | undefined,
   apiProxy: string | undefined,
-) {
-  updateConfigValue(CONFIG_KEY_ENFORCED_ORGS, enforcedOrgs)
-  updateConfigValue(CONFIG_KEY_API_TOKEN, apiToken)
-  updateConfigValue(CONFIG_KEY_API_BASE_URL, apiBaseUrl)
-  updateConfigValue(CONFIG_KEY_API_PROXY, apiProxy)
+  profile?: string | undefined,
+  useKeychain = true,
+): CResult<ApiTokenStorage> {
+  // A named profile keeps its own credentials and becomes the active one.
+  updateLoginValue(profile, CONFIG_KEY_ENFORCED_ORGS, enforcedOrgs)
+  const saveResult = saveApiToken(apiToken, profile, useKeychain)
+  updateLoginValue(profile, CONFIG_KEY_API_BASE_URL, apiBaseUrl)
+  updateLoginValue(profile, CONFIG_KEY_API_PROXY, apiProxy)
+  if (profile) {
+    updateConfigValue(CONFIG_KEY_ACTIVE_PROFILE, profile)
+  }
   invalidateDefaultApiToken()
+  return saveResult
+}
+
+/**
+ * Store the API token of a profile, the active one when none is given. The
+ * token goes to the OS keychain when possible and to the config file
+ * otherwise; a message explains why the keychain was not used.
+ */
+export function saveApiToken(
+  apiToken: string,
+  profile: string | undefined,
+  useKeychain = true,
+): CResult<ApiTokenStorage> {
+  const account = profile || getActiveProfileName() || DEFAULT_PROFILE_NAME
+  // A read-only config is not persisted, neither is the keychain.
+  let keychainFailure: string | undefined
+  if (useKeychain && isKeychainEnabled() && !isConfigFromFlag()) {
+    const storeResult = storeKeychainToken(account, apiToken)
+    if (storeResult.ok) {
+      updateLoginValue(profile, CONFIG_KEY_API_TOKEN, KEYCHAIN_API_TOKEN_REF)
+      return { ok: true, data: 'keychain' }
+    }
+    keychainFailure = `${storeResult.message}, API token stored in the config file: ${storeResult.cause}`
+  }
+  const wasInKeychain = isApiTokenInKeychain(account)
+  updateLoginValue(profile, CONFIG_KEY_API_TOKEN, apiToken)
+  if (wasInKeychain) {
+    deleteKeychainToken(account)
+  }
+  return {
+    ok: true,
+    ...(keychainFailure ? { message: keychainFailure } : {}),
+    data: 'config',
+  }
 }
@@ -1,23 +1,29 @@
 import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
 import { SOCKET_PUBLIC_API_TOKEN } from '@socketsecurity/lib-stable/constants/socket'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
 import {
   confirm,
   password,
   select,
 } from '@socketsecurity/lib-stable/stdio/prompts'
+import open from 'open'
 
 import { applyLogin } from './apply-login.mts'
+import { deviceLogin } from './device-login.mts'
 import {
   CONFIG_KEY_API_BASE_URL,
   CONFIG_KEY_API_PROXY,
   CONFIG_KEY_API_TOKEN,
   CONFIG_KEY_DEFAULT_ORG,
+  DEFAULT_PROFILE_NAME,
 } from '../../constants/config.mts'
 import {
   getConfigValueOrUndef,
+  getProfileConfig,
   isConfigFromFlag,
   updateConfigValue,
+  updateProfileConfigValue,
 } from '../../util/config.mts'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
 import { getEnterpriseOrgs, getOrgSlugs } from '../../util/organization.mts'
@@ -26,28 +32,79 @@ import { socketDocsLink } from '../../util/terminal/link.mts'
 import { setupTabCompletion } from '../install/setup-tab-completion.mts'
 import { fetchOrganization } from '../organization/fetch-organization-list.mts'
 
+import type { CResult } from '../../types.mts'
 import type { Choice } from '@socketsecurity/lib-stable/stdio/prompts'
 const logger = getDefaultLogger()
 
 export type OrgChoice = Choice<string>
 export type OrgChoices = OrgChoice[]
 
+async function readApiToken(
+  apiBaseUrl: string | undefined,
+  sso: boolean,
+): Promise<CResult<string>> {
+  if (!sso) {
+    const apiTokenInput = await password({
+      message: `Enter your ${socketDocsLink('/docs/api-keys', 'Socket.dev API token')} (leave blank to use a limited public token)`,
+    })
+    if (apiTokenInput === undefined) {
+      return { ok: false, message: 'Canceled', cause: 'Canceled by user' }
+    }
+    return { ok: true, data: apiTokenInput || SOCKET_PUBLIC_API_TOKEN }
+  }
+  const spinner = getDefaultSpinner()
+  const result = await deviceLogin({
+    apiBaseUrl,
+    async onAuthorization({
+      userCode,
+      verificationUri,
+      verificationUriComplete,
+    }) {
+      logger.log(`Open ${verificationUri} and enter the code: ${userCode}`)
+      try {
+        await open(verificationUriComplete ?? verificationUri)
+      } catch {
+        // The URL is logged above, headless machines open it elsewhere.
+      }
+      spinner?.start('Waiting for the SSO login in the browser…')
+    },
+  })
+  spinner?.stop()
+  return result
+}
+
 export async function attemptLogin(
   apiBaseUrl: string | undefined,
   apiProxy: string | undefined,
+  profile?: string | undefined,
+  useKeychain = true,
+  sso = false,
 ) {
-  apiBaseUrl ??= getConfigValueOrUndef(CONFIG_KEY_API_BASE_URL) ?? undefined
-  apiProxy ??= getConfigValueOrUndef(CONFIG_KEY_API_PROXY) ?? undefined
-  const apiTokenInput = await password({
-    message: `Enter your ${socketDocsLink('/docs/api-keys', 'Socket.dev API token')} (leave blank to use a limited public token)`,
-  })
-
-  if (apiTokenInput === undefined) {
-    logger.fail('Canceled by user')
-    return { ok: false, message: 'Canceled', cause: 'Canceled by user' }
+  // The top-level credentials are stored outside of `profiles`.
+  const storedProfile =
+    profile && profile !== DEFAULT_PROFILE_NAME
+      ? (getProfileConfig(profile) ?? {})
+      : undefined
+  apiBaseUrl ??=
+    (storedProfile
+      ? storedProfile.apiBaseUrl
+      : getConfigValueOrUndef(CONFIG_KEY_API_BASE_URL)) ?? undefined
+  apiProxy ??=
+    (storedProfile
+      ? storedProfile.apiProxy
+      : getConfigValueOrUndef(CONFIG_KEY_API_PROXY)) ?? undefined
+  const apiTokenCResult = await readApiToken(apiBaseUrl, sso)
+  if (!apiTokenCResult.ok) {
+    if (apiTokenCResult.message === 'Canceled') {
+      logger.fail('Canceled by user')
+      return apiTokenCResult
+    }
+    process.exitCode = 1
+    logger.fail(failMsgWithBadge(apiTokenCResult.message, apiTokenCResult.cause))
+    return undefined
   }
 
-  const apiToken = apiTokenInput || SOCKET_PUBLIC_API_TOKEN
+  const apiToken = apiTokenCResult.data
 
   const sockSdkCResult = await setupSdk({ apiBaseUrl, apiProxy, apiToken })
   if (!sockSdkCResult.ok) {
@@ -166,15 +223,33 @@ export async function attemptLogin(
 
   const defaultOrg = orgSlugs[0]?.trim()
   if (defaultOrg) {
-    updateConfigValue(CONFIG_KEY_DEFAULT_ORG, defaultOrg)
+    if (profile) {
+      updateProfileConfigValue(profile, CONFIG_KEY_DEFAULT_ORG, defaultOrg)
+    } else {
+      updateConfigValue(CONFIG_KEY_DEFAULT_ORG, defaultOrg)
+    }
   }
 
-  const previousPersistedToken = getConfigValueOrUndef(CONFIG_KEY_API_TOKEN)
+  const previousPersistedToken = storedProfile
+    ? storedProfile.apiToken
+    : getConfigValueOrUndef(CONFIG_KEY_API_TOKEN)
   try {
-    applyLogin(apiToken, enforcedOrgs, apiBaseUrl, apiProxy)
+    const saveResult = applyLogin(
+      apiToken,
+      enforcedOrgs,
+      apiBaseUrl,
+      apiProxy,
+      profile,
+      useKeychain,
+    )
     logger.success(
-      `API credentials ${previousPersistedToken === apiToken ? 'refreshed' : previousPersistedToken ? 'updated' : 'set'}`,
+      `API credentials ${previousPersistedToken === apiToken ? 'refreshed' : previousPersistedToken ? 'updated' : 'set'}${profile ? ` for profile '${profile}', now the active profile` : ''}`,
     )
+    if (saveResult?.ok && saveResult.data === 'keychain') {
+      logger.log('API token stored in the OS keychain')
+    } else if (saveResult?.message) {
+      logger.warn(saveResult.message)
+    }
     if (isConfigFromFlag()) {
       logger.log('')
       logger.warn(
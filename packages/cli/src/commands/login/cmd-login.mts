@@ -5,19 +5,24 @@ import { outputDryRunWrite } from '../../util/dry-run/output.mts'
 import { defineFlags } from '../../meow.mts'
 import { commonFlags } from '../../flags.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { isValidProfileName } from '../../util/config.mts'
 import { InputError } from '../../util/error/errors.mjs'
 import {
   getFlagApiRequirementsOutput,
   getFlagListOutput,
 } from '../../util/output/formatting.mts'
+import { resolveApiBaseUrl } from '../../util/socket/api-endpoint.mts'
 
+import type { MeowFlag, MeowFlags } from '../../flags.mts'
 import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
-import type { MeowFlags } from '../../flags.mts'
 
 // Flags interface for type safety.
 export interface LoginFlags {
   apiBaseUrl?: string | undefined
   apiProxy?: string | undefined
+  keychain?: boolean | undefined
+  profile?: string | undefined
+  sso?: boolean | undefined
 }
 
 export const CMD_NAME = 'login'
@@ -46,13 +51,34 @@ export async function run(
       apiBaseUrl: {
         type: 'string',
         default: '',
-        description: 'API server to connect to for login',
+        description:
+          'API server to connect to for login, stored with the credentials',
       },
       apiProxy: {
         type: 'string',
         default: '',
         description: 'Proxy to use when making connection to API server',
       },
+      keychain: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Store the API token in the config file instead of the OS keychain, e.g. in headless CI',
+        // Hidden to allow custom documenting of the negated `--no-keychain` variant.
+        hidden: true,
+      },
+      profile: {
+        type: 'string',
+        default: '',
+        description:
+          'Store the API token and orgs in this named profile and make it the active one',
+      },
+      sso: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Log in through your SSO provider in the browser instead of entering an API token',
+      },
     }),
     help: (command: string, helpConfig: { flags: MeowFlags }) => `
     Usage
@@ -63,12 +89,42 @@ export async function run(
 
     Logs into the Socket API by prompting for an API token
 
+    With --profile the token and orgs are stored in a named profile, so you
+    can keep a login per Socket org and switch between them with
+    \`socket config use-profile\`.
+
+    With --api-base-url you log into a single-tenant, on-prem or regional
+    Socket deployment; the URL is stored with the credentials. A bare origin
+    gets the API version appended, e.g. https://socket.example.com/v0/. Set
+    the apiAuthScheme config value to bearer when its gateway expects a
+    Bearer token.
+
+    The API token is kept in the OS keychain (macOS Keychain, Windows
+    Credential Manager or libsecret) when one is available, and in the
+    config file otherwise.
+
+    With --sso you log in through the browser: open the URL shown, enter
+    the code and sign in with your SSO provider. Use it when your org
+    enforces SSO. The issuer is the Socket API unless SOCKET_CLI_SSO_ISSUER
+    is set.
+
     Options
-      ${getFlagListOutput(helpConfig.flags)}
+      ${getFlagListOutput({
+        ...helpConfig.flags,
+        // Explicitly document the negated --no-keychain variant.
+        noKeychain: {
+          ...helpConfig.flags['keychain'],
+          hidden: false,
+        } as MeowFlag,
+      })}
 
     Examples
       $ ${command}
       $ ${command} --api-proxy=http://localhost:1234
+      $ ${command} --api-base-url=https://socket.example.com --profile onprem
+      $ ${command} --profile work
+      $ ${command} --no-keychain
+      $ ${command} --sso
   `,
   }
 
@@ -80,14 +136,37 @@ export async function run(
   })
 
   const dryRun = cli.flags['dryRun']
+  const { apiBaseUrl, apiProxy, keychain, profile, sso } =
+    cli.flags as unknown as LoginFlags
+
+  if (profile && !isValidProfileName(profile)) {
+    throw new InputError(
+      `Invalid profile name "${profile}"; profile names start with a letter or digit and may contain letters, digits, \`.\`, \`_\` and \`-\``,
+    )
+  }
+
+  let resolvedApiBaseUrl = apiBaseUrl
+  if (apiBaseUrl) {
+    const urlCResult = resolveApiBaseUrl(apiBaseUrl)
+    if (!urlCResult.ok) {
+      throw new InputError(`${urlCResult.message}: ${urlCResult.cause}`)
+    }
+    resolvedApiBaseUrl = urlCResult.data
+  }
 
   if (dryRun) {
     // Runtime read so tests that mutate process.env['HOME'] pick up changes.
     const configPath = `${process.env['HOME']}/.config/socket/config.json`
     const changes = [
-      'Prompt for Socket API token',
+      sso
+        ? 'Log in through the SSO provider in the browser'
+        : 'Prompt for Socket API token',
       'Verify token with Socket API',
-      'Save API token to config',
+      profile
+        ? `Save API token to profile "${profile}" and make it active`
+        : keychain
+          ? 'Save API token to the OS keychain, or to config if unavailable'
+          : 'Save API token to config',
       'Optionally set default organization',
       'Optionally install bash completion',
     ]
@@ -101,7 +180,5 @@ export async function run(
     )
   }
 
-  const { apiBaseUrl, apiProxy } = cli.flags
-
-  await attemptLogin(apiBaseUrl, apiProxy)
+  await attemptLogin(resolvedApiBaseUrl, apiProxy, profile, keychain, sso)
 }
@@ -0,0 +1,318 @@
+/**
+ * OAuth 2.0 device authorization grant (RFC 8628) for `socket login --sso`.
+ *
+ * Users on SSO-enforced orgs authenticate in the browser: the CLI asks the
+ * issuer for a device code, shows the verification URL and user code, and
+ * polls the token endpoint until the user approved, denied or the code
+ * expired. The endpoints come from the issuer metadata (RFC 8414). The access
+ * token issued is a Socket API token.
+ */
+
+import { setTimeout as sleep } from 'node:timers/promises'
+
+import { errorMessage } from '@socketsecurity/lib-stable/errors/message'
+
+import { getSocketCliSsoIssuer } from '../../env/socket-cli-sso-issuer.mts'
+import {
+  getDefaultApiBaseUrl,
+  socketHttpRequest,
+  tryReadResponseText,
+} from '../../util/socket/api-http.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type DeviceAuthorization = {
+  deviceCode: string
+  // Seconds.
+  expiresIn: number
+  // Seconds between token requests.
+  interval: number
+  userCode: string
+  verificationUri: string
+  verificationUriComplete: string | undefined
+}
+
+export type DeviceLoginEndpoints = {
+  deviceAuthorizationEndpoint: string
+  tokenEndpoint: string
+}
+
+export type DeviceLoginOptions = {
+  apiBaseUrl?: string | undefined
+  // Shows the user where to go.
+  onAuthorization: (authorization: DeviceAuthorization) => void | Promise<void>
+  wait?: ((ms: number) => Promise<unknown>) | undefined
+}
+
+export const SSO_CLIENT_ID = 'socket-cli'
+
+const DEVICE_CODE_GRANT_TYPE = 'urn:ietf:params:oauth:grant-type:device_code'
+
+const OAUTH_WELL_KNOWN_PATH = '/.well-known/oauth-authorization-server'
+
+// RFC 8628 section 3.2: clients use this when the server sends no interval.
+const DEFAULT_POLL_INTERVAL_S = 5
+
+const DEFAULT_EXPIRES_IN_S = 900
+
+// RFC 8628 section 3.5: slow_down adds five seconds to the interval.
+const SLOW_DOWN_INCREMENT_S = 5
+
+async function postForm(
+  url: string,
+  form: Record<string, string>,
+): Promise<CResult<Record<string, unknown>>> {
+  try {
+    const response = await socketHttpRequest(url, {
+      body: new URLSearchParams(form).toString(),
+      headers: {
+        accept: 'application/json',
+        'content-type': 'application/x-www-form-urlencoded',
+      },
+      method: 'POST',
+    })
+    const json = parseJsonObject(tryReadResponseText(response))
+    if (!json) {
+      return {
+        ok: false,
+        message: 'SSO login failed',
+        cause: `${url} answered with status ${response.status} and no JSON body`,
+        data: { code: response.status },
+      }
+    }
+    return response.status >= 200 && response.status < 300
+      ? { ok: true, data: json }
+      : {
+          ok: false,
+          message: 'SSO login failed',
+          cause: describeOAuthError(json, response.status),
+          data: { code: response.status, ...json },
+        }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'SSO login failed',
+      cause: `Could not reach ${url}: ${errorMessage(e)}`,
+    }
+  }
+}
+
+function describeOAuthError(
+  json: Record<string, unknown>,
+  status: number,
+): string {
+  const error = typeof json['error'] === 'string' ? json['error'] : undefined
+  const description =
+    typeof json['error_description'] === 'string'
+      ? json['error_description']
+      : undefined
+  return (
+    [error, description].filter(Boolean).join(': ') ||
+    `Unexpected status ${status}`
+  )
+}
+
+function parseJsonObject(
+  text: string | undefined,
+): Record<string, unknown> | undefined {
+  try {
+    const parsed: unknown = JSON.parse(text ?? '')
+    return parsed && typeof parsed === 'object' && !Array.isArray(parsed)
+      ? (parsed as Record<string, unknown>)
+      : undefined
+  } catch {
+    return undefined
+  }
+}
+
+function toPositiveNumber(value: unknown, fallback: number): number {
+  const number = Number(value)
+  return Number.isFinite(number) && number > 0 ? number : fallback
+}
+
+/**
+ * SOCKET_CLI_SSO_ISSUER, else the origin of the Socket API base URL.
+ */
+export function getSsoIssuer(apiBaseUrl?: string | undefined): string {
+  const issuer = getSocketCliSsoIssuer()
+  if (issuer) {
+    return issuer
+  }
+  return new URL(apiBaseUrl || getDefaultApiBaseUrl() || '').origin
+}
+
+export async function fetchDeviceLoginEndpoints(
+  issuer: string,
+): Promise<CResult<DeviceLoginEndpoints>> {
+  let url: string
+  try {
+    url = new URL(OAUTH_WELL_KNOWN_PATH, issuer).href
+  } catch {
+    return {
+      ok: false,
+      message: 'SSO login failed',
+      cause: `Invalid SSO issuer URL: ${issuer}`,
+    }
+  }
+  try {
+    const response = await socketHttpRequest(url, {
+      headers: { accept: 'application/json' },
+      method: 'GET',
+    })
+    const metadata = parseJsonObject(tryReadResponseText(response))
+    const deviceAuthorizationEndpoint =
+      metadata?.['device_authorization_endpoint']
+    const tokenEndpoint = metadata?.['token_endpoint']
+    if (
+      response.status < 200 ||
+      response.status >= 300 ||
+      typeof deviceAuthorizationEndpoint !== 'string' ||
+      typeof tokenEndpoint !== 'string'
+    ) {
+      return {
+        ok: false,
+        message: 'SSO login unavailable',
+        cause: `${issuer} does not offer the OAuth device authorization grant (status ${response.status}). Log in with an API token instead.`,
+        data: { code: response.status },
+      }
+    }
+    return { ok: true, data: { deviceAuthorizationEndpoint, tokenEndpoint } }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'SSO login failed',
+      cause: `Could not reach ${url}: ${errorMessage(e)}`,
+    }
+  }
+}
+
+export async function requestDeviceAuthorization(
+  endpoint: string,
+): Promise<CResult<DeviceAuthorization>> {
+  const result = await postForm(endpoint, { client_id: SSO_CLIENT_ID })
+  if (!result.ok) {
+    return result
+  }
+  const json = result.data
+  const {
+    device_code: deviceCode,
+    user_code: userCode,
+    verification_uri: verificationUri,
+    verification_uri_complete: verificationUriComplete,
+  } = json
+  if (
+    typeof deviceCode !== 'string' ||
+    typeof userCode !== 'string' ||
+    typeof verificationUri !== 'string'
+  ) {
+    return {
+      ok: false,
+      message: 'SSO login failed',
+      cause: 'The device authorization response misses the device code, user code or verification URL',
+    }
+  }
+  return {
+    ok: true,
+    data: {
+      deviceCode,
+      expiresIn: toPositiveNumber(json['expires_in'], DEFAULT_EXPIRES_IN_S),
+      interval: toPositiveNumber(json['interval'], DEFAULT_POLL_INTERVAL_S),
+      userCode,
+      verificationUri,
+      verificationUriComplete:
+        typeof verificationUriComplete === 'string'
+          ? verificationUriComplete
+          : undefined,
+    },
+  }
+}
+
+/**
+ * Poll the token endpoint until the user approved the device code. Resolves
+ * with the access token.
+ */
+export async function pollDeviceToken(
+  tokenEndpoint: string,
+  authorization: DeviceAuthorization,
+  wait: (ms: number) => Promise<unknown> = sleep,
+): Promise<CResult<string>> {
+  let interval = authorization.interval
+  const deadline = Date.now() + authorization.expiresIn * 1000
+  while (Date.now() < deadline) {
+    // eslint-disable-next-line no-await-in-loop
+    await wait(interval * 1000)
+    // eslint-disable-next-line no-await-in-loop
+    const result = await postForm(tokenEndpoint, {
+      client_id: SSO_CLIENT_ID,
+      device_code: authorization.deviceCode,
+      grant_type: DEVICE_CODE_GRANT_TYPE,
+    })
+    if (result.ok) {
+      const accessToken = result.data['access_token']
+      if (typeof accessToken !== 'string' || !accessToken) {
+        return {
+          ok: false,
+          message: 'SSO login failed',
+          cause: 'The token response has no access token',
+        }
+      }
+      return { ok: true, data: accessToken }
+    }
+    const error = (result.data as { error?: unknown } | undefined)?.error
+    if (error === 'authorization_pending') {
+      continue
+    }
+    if (error === 'slow_down') {
+      interval += SLOW_DOWN_INCREMENT_S
+      continue
+    }
+    if (error === 'access_denied') {
+      return {
+        ok: false,
+        message: 'SSO login denied',
+        cause: 'The login request was denied in the browser',
+      }
+    }
+    if (error === 'expired_token') {
+      break
+    }
+    return result
+  }
+  return {
+    ok: false,
+    message: 'SSO login expired',
+    cause: 'The code was not entered in time. Run `socket login --sso` again.',
+  }
+}
+
+export async function deviceLogin(
+  options: DeviceLoginOptions,
+): Promise<CResult<string>> {
+  const { apiBaseUrl, onAuthorization, wait } = {
+    __proto__: null,
+    ...options,
+  } as DeviceLoginOptions
+  let issuer: string
+  try {
+    issuer = getSsoIssuer(apiBaseUrl)
+  } catch {
+    return {
+      ok: false,
+      message: 'SSO login failed',
+      cause: `Invalid Socket API base URL: ${apiBaseUrl}`,
+    }
+  }
+  const endpointsResult = await fetchDeviceLoginEndpoints(issuer)
+  if (!endpointsResult.ok) {
+    return endpointsResult
+  }
+  const { deviceAuthorizationEndpoint, tokenEndpoint } = endpointsResult.data
+  const authorizationResult = await requestDeviceAuthorization(
+    deviceAuthorizationEndpoint,
+  )
+  if (!authorizationResult.ok) {
+    return authorizationResult
+  }
+  await onAuthorization(authorizationResult.data)
+  return await pollDeviceToken(tokenEndpoint, authorizationResult.data, wait)
+}
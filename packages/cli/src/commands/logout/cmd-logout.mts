@@ -6,11 +6,18 @@ import {
   CONFIG_KEY_API_PROXY,
   CONFIG_KEY_API_TOKEN,
   CONFIG_KEY_ENFORCED_ORGS,
+  DEFAULT_PROFILE_NAME,
 } from '../../constants/config.mts'
 import { defineFlags } from '../../meow.mts'
 import { commonFlags } from '../../flags.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
-import { isConfigFromFlag, updateConfigValue } from '../../util/config.mts'
+import {
+  getActiveProfileName,
+  isApiTokenInKeychain,
+  isConfigFromFlag,
+  updateConfigValue,
+} from '../../util/config.mts'
+import { deleteKeychainToken } from '../../util/keychain.mts'
 import { invalidateDefaultApiToken } from '../../util/socket/sdk.mts'
 
 import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
@@ -27,6 +34,10 @@ const hidden = false
 // Helper functions.
 
 export function applyLogout(): void {
+  const account = getActiveProfileName() ?? DEFAULT_PROFILE_NAME
+  if (!isConfigFromFlag() && isApiTokenInKeychain(account)) {
+    deleteKeychainToken(account)
+  }
   updateConfigValue(CONFIG_KEY_API_TOKEN, undefined)
   updateConfigValue(CONFIG_KEY_API_BASE_URL, undefined)
   updateConfigValue(CONFIG_KEY_API_PROXY, undefined)
@@ -68,6 +79,7 @@ export async function run(
       $ ${command} [options]
 
     Logs out of the Socket API and clears all Socket credentials from disk
+    and the OS keychain
 
     Examples
       $ ${command}
@@ -0,0 +1,161 @@
+import path from 'node:path'
+
+import { handleManifestGenerate } from './handle-manifest-generate.mts'
+import {
+  DEFAULT_GRADLE_CONFIGURATION,
+  RESOLVED_MANIFEST_CDX_JSON,
+} from './resolve-loose-manifests.mts'
+import { REQUIREMENTS_TXT } from '../../constants/paths.mjs'
+import { outputDryRunExecute } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+// Flags interface for type safety.
+export interface ManifestGenerateFlags {
+  configuration: string
+  dryRun: boolean
+  gradleBin: string
+  json: boolean
+  markdown: boolean
+  out: string
+  pipBin: string
+}
+
+const config = {
+  commandName: 'generate',
+  description:
+    'Resolve loose requirements locally into a pinned manifest for scanning',
+  flags: defineFlags({
+    ...commonFlags,
+    ...outputFlags,
+    configuration: {
+      type: 'string',
+      default: DEFAULT_GRADLE_CONFIGURATION,
+      description: 'Gradle configuration to resolve',
+    },
+    gradleBin: {
+      type: 'string',
+      default: '',
+      description:
+        'Gradle executable to resolve with, default: CWD/gradlew or gradle',
+    },
+    out: {
+      type: 'string',
+      default: RESOLVED_MANIFEST_CDX_JSON,
+      description:
+        'Output path (relative to cwd) of the CycloneDX SBOM, or - for stdout',
+    },
+    pipBin: {
+      type: 'string',
+      default: 'pip',
+      description: 'pip command (22.2 or newer) to resolve with, e.g. "pip3"',
+    },
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] [CWD=.]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Some projects only declare loose requirements, like a ${REQUIREMENTS_TXT}
+    with version ranges or a Gradle build without dependency locking. A scan
+    of those cannot tell what would actually install. This command asks the
+    ecosystem's own resolver for the pinned dependency set and writes it as a
+    CycloneDX SBOM that \`socket scan create\` picks up:
+
+    - ${REQUIREMENTS_TXT} (and requirements-*.txt) files that do not pin every
+      requirement with == are resolved with \`pip install --dry-run --report\`
+    - a build.gradle(.kts) without a gradle.lockfile is resolved with the
+      \`dependencies\` task of the root project, for --configuration
+
+    No packages are installed, but the resolvers may download metadata and
+    gradle runs your build scripts.
+
+    Examples
+
+      $ ${command}
+      $ ${command} ./service --pip-bin "python3 -m pip"
+      $ ${command} --configuration compileClasspath --out -
+  `,
+  hidden: false,
+}
+
+export const cmdManifestGenerate = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { configuration, dryRun, gradleBin, json, markdown, out, pipBin } =
+    cli.flags as unknown as ManifestGenerateFlags
+
+  let [cwd = '.'] = cli.input
+  // Note: path.resolve vs .join:
+  // If given path is absolute then cwd should not affect it.
+  cwd = path.resolve(process.cwd(), cwd)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: cli.input.length <= 1,
+      message: 'Can only accept one DIR (make sure to escape spaces!)',
+      fail: `received ${cli.input.length}`,
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !!out && (out !== '-' || outputKind === 'text'),
+      message: 'The SBOM can only be sent to stdout with text output',
+      fail: 'pass --out <file>',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunExecute(
+      'dependency resolvers',
+      [cwd, '--out', out],
+      'resolve loose requirements into a pinned CycloneDX SBOM',
+    )
+    return
+  }
+
+  await handleManifestGenerate({
+    configuration,
+    cwd,
+    gradleBin,
+    out,
+    outputKind,
+    pipBin,
+  })
+}
@@ -1,6 +1,7 @@
 import { cmdManifestAuto } from './cmd-manifest-auto.mts'
 import { cmdManifestCdxgen } from './cmd-manifest-cdxgen.mts'
 import { cmdManifestConda } from './cmd-manifest-conda.mts'
+import { cmdManifestGenerate } from './cmd-manifest-generate.mts'
 import { cmdManifestGradle } from './cmd-manifest-gradle.mts'
 import { cmdManifestKotlin } from './cmd-manifest-kotlin.mts'
 import { cmdManifestScala } from './cmd-manifest-scala.mts'
@@ -18,6 +19,7 @@ export const cmdManifest = defineSubcommandGroup({
     auto: cmdManifestAuto,
     cdxgen: cmdManifestCdxgen,
     conda: cmdManifestConda,
+    generate: cmdManifestGenerate,
     gradle: cmdManifestGradle,
     kotlin: cmdManifestKotlin,
     scala: cmdManifestScala,
@@ -8,6 +8,7 @@ import { debugLog } from '@socketsecurity/lib-stable/debug/output'
 
 import { ENVIRONMENT_YAML, ENVIRONMENT_YML } from '../../constants/paths.mjs'
 import { SOCKET_JSON } from '../../constants/socket.mts'
+import { GRADLE_LOCKFILE } from '../../util/lockfile/gradle.mts'
 
 import type { SocketJson } from '../../util/socket/json.mts'
 
@@ -50,6 +51,9 @@ export async function detectManifestActions(
       'notice',
       `[DEBUG] - gradle auto-detection is disabled in ${SOCKET_JSON}`,
     )
+  } else if (existsSync(path.join(cwd, GRADLE_LOCKFILE))) {
+    // Locked builds are scanned from their lockfiles, no pom conversion needed.
+    debugLog('notice', `[DEBUG] - Detected a gradle ${GRADLE_LOCKFILE}`)
   } else if (existsSync(path.join(cwd, 'gradlew'))) {
     debugLog('notice', '[DEBUG] - Detected a gradle build file')
     output.gradle = true
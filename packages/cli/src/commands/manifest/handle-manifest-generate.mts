@@ -0,0 +1,63 @@
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { outputManifestGenerate } from './output-manifest-generate.mts'
+import {
+  findLooseManifests,
+  resolveLooseManifest,
+} from './resolve-loose-manifests.mts'
+import { GRADLE_LOCKFILE } from '../../util/lockfile/gradle.mts'
+
+import type { ResolveLooseManifestOptions } from './resolve-loose-manifests.mts'
+import type { OutputKind } from '../../types.mts'
+import type { ParsedLockfile } from '../../util/lockfile/types.mts'
+
+export type HandleManifestGenerateConfig = ResolveLooseManifestOptions & {
+  // Path of the generated SBOM relative to cwd, or `-` for stdout.
+  out: string
+  outputKind: OutputKind
+}
+
+export async function handleManifestGenerate({
+  out,
+  outputKind,
+  ...options
+}: HandleManifestGenerateConfig): Promise<void> {
+  const { cwd } = options
+  const manifests = findLooseManifests(cwd)
+  if (!manifests.length) {
+    await outputManifestGenerate(
+      {
+        ok: false,
+        message: 'Nothing to resolve',
+        cause: `Found no requirements file with version ranges and no Gradle build without a ${GRADLE_LOCKFILE} in ${cwd}`,
+      },
+      { cwd, out, outputKind },
+    )
+    return
+  }
+
+  const spinner = getDefaultSpinner()
+  const lockfiles: ParsedLockfile[] = []
+  for (const manifest of manifests) {
+    spinner.start(`Resolving the dependencies of ${manifest.file}…`)
+    const resolveCResult = await resolveLooseManifest(manifest, options)
+    spinner.stop()
+    if (!resolveCResult.ok) {
+      await outputManifestGenerate(
+        { ...resolveCResult, message: `Unable to resolve ${manifest.file}` },
+        { cwd, out, outputKind },
+      )
+      return
+    }
+    lockfiles.push({
+      dependencies: resolveCResult.data,
+      ecosystem: manifest.ecosystem,
+      file: manifest.file,
+    })
+  }
+
+  await outputManifestGenerate(
+    { ok: true, data: lockfiles },
+    { cwd, out, outputKind },
+  )
+}
@@ -0,0 +1,90 @@
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { lockfilesToCycloneDx } from '../../util/lockfile/sbom.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mts'
+import { fileLink } from '../../util/terminal/link.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { ParsedLockfile } from '../../util/lockfile/types.mts'
+
+const logger = getDefaultLogger()
+
+export type OutputManifestGenerateConfig = {
+  cwd: string
+  out: string
+  outputKind: OutputKind
+}
+
+export async function outputManifestGenerate(
+  result: CResult<ParsedLockfile[]>,
+  { cwd, out, outputKind }: OutputManifestGenerateConfig,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+    if (outputKind === 'json') {
+      logger.log(serializeResultJson(result))
+      return
+    }
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const bom = `${JSON.stringify(lockfilesToCycloneDx(result.data), null, 2)}\n`
+  if (out === '-') {
+    logger.log(bom)
+    return
+  }
+  try {
+    await fs.writeFile(path.resolve(cwd, out), bom, 'utf8')
+  } catch (e) {
+    process.exitCode = 1
+    logger.fail(`There was an error trying to write ${out} to disk`)
+    logger.error(e)
+    return
+  }
+
+  const manifests = result.data.map(({ dependencies, ecosystem, file }) => ({
+    ecosystem,
+    file,
+    packages: dependencies.length,
+  }))
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson({ ok: true, data: { manifests, out } }))
+    return
+  }
+
+  if (outputKind === 'markdown') {
+    const arr = [mdHeader('Resolved Manifests'), '']
+    for (const { ecosystem, file, packages } of manifests) {
+      arr.push(
+        `- \`${file}\` (${ecosystem}): ${packages} pinned ${pluralize('package', { count: packages })}`,
+      )
+    }
+    arr.push('')
+    arr.push(
+      `The pinned dependency set was written to \`${out}\`. Scan it with \`socket scan create\`.`,
+    )
+    logger.log(arr.join('\n'))
+    return
+  }
+
+  for (const { file, packages } of manifests) {
+    if (packages) {
+      logger.success(
+        `Resolved ${packages} pinned ${pluralize('package', { count: packages })} for ${file}`,
+      )
+    } else {
+      logger.warn(`The resolver reported no packages for ${file}`)
+    }
+  }
+  logger.log(
+    `Wrote ${fileLink(path.resolve(cwd, out), out)}, scan it with \`socket scan create\``,
+  )
+}
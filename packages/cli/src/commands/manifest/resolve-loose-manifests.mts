@@ -0,0 +1,249 @@
+/**
+ * Local resolution of manifests that only declare loose requirements.
+ *
+ * A `requirements.txt` with version ranges, or a Gradle build without
+ * dependency locking, does not say what would actually install. These
+ * helpers find such manifests in a directory and ask the ecosystem's own
+ * resolver for the pinned dependency set: pip's dry-run installation report
+ * for Python, and the `dependencies` task of the build for Gradle.
+ */
+
+import { existsSync, readdirSync, readFileSync } from 'node:fs'
+import path from 'node:path'
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import { getErrorCause } from '../../util/error/errors.mts'
+import { GRADLE_LOCKFILE } from '../../util/lockfile/gradle.mts'
+import { PDM_LOCK } from '../../util/lockfile/pdm.mts'
+import { UV_LOCK } from '../../util/lockfile/uv.mts'
+import { getPurlObject } from '../../util/purl/parse.mts'
+import { resolvePipInstall } from '../../util/python/pip-report.mts'
+
+import type { CResult } from '../../types.mts'
+import type { PURL_Type } from '../../util/ecosystem/types.mts'
+import type { LockfileDependency } from '../../util/lockfile/types.mts'
+
+export type LooseManifest = {
+  ecosystem: Extract<PURL_Type, 'maven' | 'pypi'>
+  // Relative to the project dir.
+  file: string
+}
+
+export type ResolveLooseManifestOptions = {
+  // Gradle configuration whose resolution is reported.
+  configuration?: string | undefined
+  cwd: string
+  // Gradle executable, by default the project `gradlew` or `gradle`.
+  gradleBin?: string | undefined
+  // pip command, may include arguments, e.g. `python3 -m pip`.
+  pipBin?: string | undefined
+}
+
+export const GRADLE_BUILD_FILES = ['build.gradle', 'build.gradle.kts']
+
+export const DEFAULT_GRADLE_CONFIGURATION = 'runtimeClasspath'
+
+// Default output of `socket manifest generate`, picked up by `socket scan
+// create` like any other CycloneDX SBOM.
+export const RESOLVED_MANIFEST_CDX_JSON = 'socket.resolved.cdx.json'
+
+const REQUIREMENTS_FILE_REGEXP = /^requirements(?:[-_.][\w.-]*)?\.txt$/i
+
+// `name==1.2.3` or `name===1.2.3`, optionally with extras.
+const PINNED_REQUIREMENT_REGEXP = /^[\w.-]+(?:\[[^\]]*\])?\s*={2,3}\s*[^\s*,]+$/
+
+/**
+ * Whether every requirement of a requirements file pins an exact version.
+ * Option lines such as `-r` or `--index-url` are not requirements.
+ */
+export function isPinnedRequirements(content: string): boolean {
+  const lines = content.replace(/\\\r?\n/g, ' ').split(/\r?\n/)
+  for (let i = 0, { length } = lines; i < length; i += 1) {
+    // Comments start with ` #`, environment markers with `;`.
+    const requirement = lines[i]!
+      .replace(/(?:^|\s)#.*$/, '')
+      .split(';')[0]!
+      .replace(/\s--hash=\S+/g, '')
+      .trim()
+    if (!requirement || requirement.startsWith('-')) {
+      continue
+    }
+    if (!PINNED_REQUIREMENT_REGEXP.test(requirement)) {
+      return false
+    }
+  }
+  return true
+}
+
+/**
+ * The manifests in cwd whose dependency set is not pinned: requirements files
+ * with ranges in projects without a `uv.lock` or `pdm.lock`, and Gradle
+ * builds without a `gradle.lockfile`.
+ */
+export function findLooseManifests(cwd: string): LooseManifest[] {
+  const manifests: LooseManifest[] = []
+  let entries: string[]
+  try {
+    entries = readdirSync(cwd).sort()
+  } catch {
+    return manifests
+  }
+  // Locked Python projects are scanned from their lockfile.
+  const hasPythonLock = entries.includes(UV_LOCK) || entries.includes(PDM_LOCK)
+  for (const entry of entries) {
+    if (
+      !hasPythonLock &&
+      REQUIREMENTS_FILE_REGEXP.test(entry) &&
+      !isPinnedRequirements(readFileSync(path.join(cwd, entry), 'utf8'))
+    ) {
+      manifests.push({ ecosystem: 'pypi', file: entry })
+    }
+  }
+  const gradleBuild = GRADLE_BUILD_FILES.find(file => entries.includes(file))
+  if (gradleBuild && !existsSync(path.join(cwd, GRADLE_LOCKFILE))) {
+    manifests.push({ ecosystem: 'maven', file: gradleBuild })
+  }
+  return manifests
+}
+
+// Gradle marks constraints `(c)` and unresolved dependencies `(n)`. Those
+// are not part of the resolution.
+const GRADLE_SKIPPED_NODE_REGEXP = /\((?:c|n)\)$| FAILED$/
+
+const GRADLE_TREE_LINE_REGEXP = /^((?:[| ] {4})*)[+\\]--- (.+)$/
+
+/**
+ * Parse the tree printed by `gradle dependencies --configuration <name>`.
+ * Conflict resolution is applied: `a:b:1.0 -> 1.1` resolves to 1.1. Project
+ * dependencies are transparent, their dependencies count as direct.
+ */
+export function parseGradleDependencyTree(
+  output: string,
+): LockfileDependency[] {
+  const dependencies = new Map<string, LockfileDependency>()
+  // Id of the dependency at each depth of the tree, undefined for the root
+  // and for projects.
+  const parents: Array<string | undefined> = []
+  const lines = output.split(/\r?\n/)
+  for (let i = 0, { length } = lines; i < length; i += 1) {
+    const match = GRADLE_TREE_LINE_REGEXP.exec(lines[i]!)
+    if (!match) {
+      continue
+    }
+    const depth = match[1]!.length / 5
+    const node = match[2]!.trim()
+    const parentId = depth ? parents[depth - 1] : undefined
+    parents.length = depth + 1
+    if (node.startsWith('project ')) {
+      parents[depth] = parentId
+      continue
+    }
+    parents[depth] = undefined
+    if (GRADLE_SKIPPED_NODE_REGEXP.test(node)) {
+      continue
+    }
+    // `(*)` marks a dependency whose subtree was listed before.
+    const coordinate = node.replace(/\s+\(\*\)$/, '')
+    const arrowIndex = coordinate.indexOf(' -> ')
+    const requested = (
+      arrowIndex === -1 ? coordinate : coordinate.slice(0, arrowIndex)
+    ).split(':')
+    const resolved =
+      arrowIndex === -1 ? undefined : coordinate.slice(arrowIndex + 4)
+    // A substitution names another module, otherwise only the version of the
+    // requested one changes.
+    const [group, name, version] = resolved?.includes(':')
+      ? resolved.split(':')
+      : [requested[0], requested[1], resolved ?? requested[2]]
+    if (!group || !name || !version) {
+      continue
+    }
+    const id = `${group}:${name}:${version}`
+    parents[depth] = id
+    let dependency = dependencies.get(id)
+    if (!dependency) {
+      dependency = {
+        id,
+        type: 'maven',
+        namespace: group,
+        name,
+        version,
+        direct: false,
+      }
+      dependencies.set(id, dependency)
+    }
+    if (parentId === undefined) {
+      dependency.direct = true
+    } else {
+      const parent = dependencies.get(parentId)!
+      parent.dependencies ??= []
+      if (!parent.dependencies.includes(id)) {
+        parent.dependencies.push(id)
+      }
+    }
+  }
+  return [...dependencies.values()]
+}
+
+function getPypiDependencies(purls: string[]): LockfileDependency[] {
+  const dependencies: LockfileDependency[] = []
+  for (const purl of purls) {
+    const purlObj = getPurlObject(purl, { throws: false })
+    if (purlObj?.name) {
+      dependencies.push({
+        id: purl,
+        type: 'pypi',
+        name: purlObj.name,
+        version: purlObj.version ?? '',
+      })
+    }
+  }
+  return dependencies
+}
+
+async function resolveGradleBuild(
+  options: ResolveLooseManifestOptions,
+): Promise<CResult<LockfileDependency[]>> {
+  const { configuration = DEFAULT_GRADLE_CONFIGURATION, cwd } = options
+  const gradlew = path.join(cwd, 'gradlew')
+  const bin = options.gradleBin || (existsSync(gradlew) ? gradlew : 'gradle')
+  const args = ['-q', 'dependencies', '--configuration', configuration]
+  let stdout: string
+  try {
+    const result = await spawn(bin, args, { cwd, stdio: 'pipe' })
+    stdout = String(result.stdout ?? '')
+  } catch (e) {
+    debugDir('error', e)
+    return {
+      ok: false,
+      message: 'Unable to resolve packages',
+      cause: `\`${bin} ${args.join(' ')}\` failed: ${getErrorCause(e)}`,
+    }
+  }
+  return { ok: true, data: parseGradleDependencyTree(stdout) }
+}
+
+/**
+ * The pinned dependencies the ecosystem's resolver picks for a manifest.
+ */
+export async function resolveLooseManifest(
+  manifest: LooseManifest,
+  options: ResolveLooseManifestOptions,
+): Promise<CResult<LockfileDependency[]>> {
+  if (manifest.ecosystem === 'maven') {
+    return await resolveGradleBuild(options)
+  }
+  const { cwd, pipBin = 'pip' } = options
+  const pipCommand = pipBin.split(' ').filter(Boolean)
+  const resolveCResult = await resolvePipInstall(
+    pipCommand,
+    ['-r', manifest.file],
+    { cwd, ignoreInstalled: true },
+  )
+  if (!resolveCResult.ok) {
+    return resolveCResult
+  }
+  return { ok: true, data: getPypiDependencies(resolveCResult.data) }
+}
@@ -0,0 +1,67 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { getNpmInstallSpecs } from './npm-install-specs.mts'
+import { SOCKET_CLI_OFFLINE } from '../../env/socket-cli-offline.mts'
+import { SOCKET_CLI_VERIFY_PROVENANCE } from '../../env/socket-cli-verify-provenance.mts'
+import { verifyNpmPackage } from '../verify/verify-provenance.mts'
+
+import type { CResult } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+/**
+ * Preflight for `socket npm install` when SOCKET_CLI_VERIFY_PROVENANCE is
+ * set: run the `socket verify` checks on each named package before handing
+ * off. Packages that cannot be looked up only warn. With SOCKET_CLI_OFFLINE
+ * the Socket cross-reference comes from the local score cache, the registry
+ * checks still need the npm registry.
+ */
+export async function checkNpmProvenance(
+  args: readonly string[],
+): Promise<CResult<unknown>> {
+  if (!SOCKET_CLI_VERIFY_PROVENANCE) {
+    return { ok: true, data: undefined }
+  }
+  const specs = getNpmInstallSpecs(args)
+  if (!specs?.length) {
+    return { ok: true, data: undefined }
+  }
+  const failures: string[] = []
+  const spinner = getDefaultSpinner()
+  for (let i = 0, { length } = specs; i < length; i += 1) {
+    const { name, spec, version } = specs[i]!
+    spinner.start(`Verifying the provenance of ${spec}…`)
+    const verifyCResult = await verifyNpmPackage(name, version, {
+      commandPath: 'socket npm',
+      offline: SOCKET_CLI_OFFLINE,
+      requireProvenance: false,
+    })
+    spinner.stop()
+    if (!verifyCResult.ok) {
+      logger.warn(
+        `Skipping the provenance check of ${spec}: ${verifyCResult.cause ?? verifyCResult.message}`,
+      )
+      continue
+    }
+    const { checks, purl, verified } = verifyCResult.data
+    for (const { check, message, status } of checks) {
+      if (status === 'fail') {
+        failures.push(`${purl} ${check}: ${message}`)
+      } else if (status === 'warn') {
+        logger.warn(`${purl} ${check}: ${message}`)
+      }
+    }
+    if (verified) {
+      logger.success(`Verified the provenance of ${purl}`)
+    }
+  }
+  if (failures.length) {
+    return {
+      ok: false,
+      message: 'Provenance verification failed',
+      cause: `${failures.join('\n')}\nRun \`socket verify <purl>\` for details`,
+    }
+  }
+  return { ok: true, data: undefined }
+}
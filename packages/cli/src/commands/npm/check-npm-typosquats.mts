@@ -0,0 +1,140 @@
+import path from 'node:path'
+
+import isInteractive from '@socketregistry/is-interactive/index.cjs'
+import { SOCKET_PUBLIC_API_TOKEN } from '@socketsecurity/lib-stable/constants/socket'
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { getNpmInstallSpecs } from './npm-install-specs.mts'
+import { SOCKET_POLICY_YML } from '../../constants/socket.mts'
+import { SOCKET_CLI_OFFLINE } from '../../env/socket-cli-offline.mts'
+import { findSocketYmlSync } from '../../util/config.mts'
+import {
+  confirmRiskyInstall,
+  offerToRecordAcceptedRisks,
+} from '../../util/install-check/check.mts'
+import {
+  findTyposquatTarget,
+  getTyposquatVerdicts,
+} from '../../util/install-check/typosquat.mts'
+import { isPolicyException } from '../../util/policy/evaluate.mts'
+import { findSocketPolicySync } from '../../util/policy/socket-policy.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+import { getDefaultApiToken } from '../../util/socket/sdk.mts'
+import { fetchPurlsShallowScore } from '../package/fetch-purls-shallow-score.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { InstallRisk } from '../../util/install-check/check.mts'
+import type { TyposquatMatch } from '../../util/install-check/typosquat.mts'
+
+const logger = getDefaultLogger()
+
+export type CheckNpmTyposquatsOptions = {
+  // Defaults to whether stdin/stdout are a TTY.
+  interactive?: boolean | undefined
+}
+
+/**
+ * Preflight for `socket npm install`: warn about package names that look
+ * like typosquats of popular packages. The local verdict is instant; Socket's
+ * `didYouMean` alert then confirms it, which asks before installing, or
+ * clears it. Without anyone to ask, the socket.yml `wrapper.nonInteractive`
+ * answer applies. A `socket.policy.yml` exception for `didYouMean` accepts a
+ * package, and accepting one at the prompt can record such an exception.
+ * Without the API (or with SOCKET_CLI_OFFLINE and nothing cached) the local
+ * warning stands and the install proceeds.
+ */
+export async function checkNpmTyposquats(
+  args: readonly string[],
+  options?: CheckNpmTyposquatsOptions | undefined,
+): Promise<CResult<unknown>> {
+  const { interactive = isInteractive() } = {
+    __proto__: null,
+    ...options,
+  } as CheckNpmTyposquatsOptions
+
+  const matches: TyposquatMatch[] = []
+  for (const { name } of getNpmInstallSpecs(args) ?? []) {
+    const match = findTyposquatTarget('npm', name)
+    if (match) {
+      matches.push(match)
+      logger.warn(`${name} looks like a typosquat of ${match.target}`)
+    }
+  }
+  if (!matches.length) {
+    return { ok: true, data: undefined }
+  }
+
+  const spinner = getDefaultSpinner()
+  spinner.start('Checking the suspected typosquats with Socket…')
+  const scoreCResult = await fetchPurlsShallowScore(
+    matches.map(m => `pkg:npm/${m.name}`),
+    {
+      commandPath: 'socket npm',
+      offline: SOCKET_CLI_OFFLINE,
+      // Unauthenticated users still get typosquat verdicts from the public
+      // token.
+      sdkOpts: { apiToken: getDefaultApiToken() || SOCKET_PUBLIC_API_TOKEN },
+    },
+  )
+  spinner.stop()
+  if (!scoreCResult.ok) {
+    debug(
+      `Typosquat lookup failed: ${scoreCResult.cause ?? scoreCResult.message}`,
+    )
+  }
+
+  const verdicts = getTyposquatVerdicts(
+    matches,
+    scoreCResult.ok
+      ? (scoreCResult.data as unknown as SocketArtifact[])
+      : undefined,
+  )
+  const policyCResult = findSocketPolicySync(process.cwd())
+  if (!policyCResult.ok) {
+    debug(
+      `Ignoring the local policy: ${policyCResult.cause ?? policyCResult.message}`,
+    )
+  }
+  const found = policyCResult.ok ? policyCResult.data : undefined
+  const confirmed: InstallRisk[] = []
+  for (const { artifact, name, status, target } of verdicts) {
+    if (status === 'confirmed' && artifact) {
+      if (found && isPolicyException(found.policy, artifact, 'didYouMean')) {
+        logger.info(
+          `${name} is accepted by ${path.basename(found.path)}, skipping the typosquat prompt`,
+        )
+        continue
+      }
+      confirmed.push({
+        alerts: [{ action: 'warn', severity: 'unknown', type: 'didYouMean' }],
+        purl: getArtifactPurlString(artifact),
+      })
+      logger.fail(`Socket confirms ${name} is a likely typosquat of ${target}`)
+    } else if (status === 'cleared') {
+      logger.info(`Socket does not flag ${name} as a typosquat`)
+    }
+  }
+  if (!confirmed.length) {
+    return { ok: true, data: undefined }
+  }
+  const ymlCResult = findSocketYmlSync(process.cwd())
+  const promptDefault = ymlCResult.ok
+    ? ymlCResult.data?.parsed.wrapper?.nonInteractive
+    : undefined
+  if (!(await confirmRiskyInstall(interactive, promptDefault))) {
+    return {
+      ok: false,
+      message: 'Install cancelled',
+      cause: `${confirmed.map(r => r.purl).join(', ')} were not accepted. Nothing was installed.`,
+    }
+  }
+  await offerToRecordAcceptedRisks(
+    confirmed,
+    found?.path ?? path.join(process.cwd(), SOCKET_POLICY_YML),
+    interactive,
+  )
+  return { ok: true, data: undefined }
+}
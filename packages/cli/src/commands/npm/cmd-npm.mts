@@ -1,22 +1,70 @@
 /**
  * Socket npm command — forwards npm operations to Socket Firewall (sfw).
  *
- * Defined via `defineHandoffCommand`. See util/cli/define-handoff.mts.
+ * Defined via `defineHandoffCommand`. The packages of `npm install` are
+ * checked for likely typosquats through its `preflight` hook, and verified
+ * with SOCKET_CLI_VERIFY_PROVENANCE set. With --sandbox-scripts the install
+ * scripts run in a sandbox, see sandbox-install-scripts.mts.
+ *
+ * See util/cli/define-handoff.mts.
  */
 
 import { NPM } from '@socketsecurity/lib-stable/constants/agents'
 
+import { checkNpmProvenance } from './check-npm-provenance.mts'
+import { checkNpmTyposquats } from './check-npm-typosquats.mts'
+import { prepareSandboxedInstall } from './sandbox-install-scripts.mts'
 import { defineHandoffCommand } from '../../util/cli/define-handoff.mts'
 
+import type { CResult } from '../../types.mts'
+import type { HandoffPreflightContext } from '../../util/cli/define-handoff.mts'
+
 export const CMD_NAME = NPM
 
+async function checkNpmInstall(
+  args: readonly string[],
+  _binaryName: string,
+  context: HandoffPreflightContext,
+): Promise<CResult<unknown>> {
+  const typosquatsCResult = await checkNpmTyposquats(args, context)
+  if (!typosquatsCResult.ok) {
+    return typosquatsCResult
+  }
+  return await checkNpmProvenance(args)
+}
+
 export const cmdNpm = defineHandoffCommand({
   name: NPM,
   description: 'Run npm with Socket Firewall security',
   // Use `auto` so SEA builds extract the npm shim from VFS while CLI
   // installs fall back to the dlx download path.
   spawnMode: 'auto',
-  examples: ['', 'install cowsay', 'install -g cowsay'],
+  examples: [
+    '',
+    'install cowsay',
+    'install -g cowsay',
+    'install --sandbox-scripts',
+  ],
+  flags: {
+    sandboxScripts: {
+      type: 'boolean',
+      default: false,
+      description:
+        'Run the install scripts of new packages in a sandbox and record what they attempt',
+    },
+  },
+  helpNotes: [
+    'Packages named by `npm install` are checked for likely typosquats before installing.',
+    'Set SOCKET_CLI_VERIFY_PROVENANCE=1 to verify package provenance before installing.',
+    'With SOCKET_CLI_OFFLINE=1 these checks use the local score cache. Socket Firewall',
+    'itself still checks the packages with the Socket API.',
+    'With --sandbox-scripts, install scripts run without network and with a read-only',
+    'filesystem, and .socket.install-scripts.json records the programs, network endpoints',
+    'and files they tried to use. `socket scan create` uploads it with the scan.',
+    'Needs Linux with bubblewrap (bwrap) and strace.',
+  ],
+  preflight: checkNpmInstall,
+  prepareHandoff: prepareSandboxedInstall,
   showApiRequirements: true,
   wrapperHint: true,
 })
@@ -0,0 +1,54 @@
+import { safeNpa } from '../../util/npm/package-arg.mts'
+
+const NPM_INSTALL_COMMANDS = new Set(['add', 'i', 'install'])
+
+const NPM_REGISTRY_SPEC_TYPES = new Set(['range', 'tag', 'version'])
+
+export type NpmInstallSpec = {
+  name: string
+  spec: string
+  version: string
+}
+
+/**
+ * Return the registry packages among package specs, e.g. the positionals of
+ * `npm install` or `pnpm add`. Local, git and url specs are left out since
+ * they are not looked up on the registry.
+ */
+export function getNpmRegistrySpecs(
+  specs: readonly string[],
+): NpmInstallSpec[] {
+  const registrySpecs: NpmInstallSpec[] = []
+  for (const spec of specs) {
+    let parsed = safeNpa(spec)
+    if (parsed?.type === 'alias') {
+      parsed = parsed.subSpec
+    }
+    if (
+      parsed?.name &&
+      parsed.fetchSpec &&
+      NPM_REGISTRY_SPEC_TYPES.has(parsed.type)
+    ) {
+      // A bare name is the `*` range, which installs `latest`.
+      const version = parsed.fetchSpec === '*' ? 'latest' : parsed.fetchSpec
+      registrySpecs.push({ name: parsed.name, spec, version })
+    }
+  }
+  return registrySpecs
+}
+
+/**
+ * Return the registry packages named by an npm install, or undefined when
+ * argv is not an install.
+ */
+export function getNpmInstallSpecs(
+  args: readonly string[],
+): NpmInstallSpec[] | undefined {
+  const commandIndex = args.findIndex(arg => !arg.startsWith('-'))
+  if (commandIndex === -1 || !NPM_INSTALL_COMMANDS.has(args[commandIndex]!)) {
+    return undefined
+  }
+  return getNpmRegistrySpecs(
+    args.slice(commandIndex + 1).filter(arg => !arg.startsWith('-')),
+  )
+}
@@ -0,0 +1,308 @@
+/**
+ * `socket npm install --sandbox-scripts`: install with --ignore-scripts,
+ * then run the lifecycle scripts of the packages the install added or
+ * changed in a sandbox, see script-sandbox.mts.
+ *
+ * Scripts run deepest package first, an approximation of npm running the
+ * scripts of dependencies before those of their dependents. A transcript of
+ * what every script attempted is written to .socket.install-scripts.json
+ * in the project, which `socket scan create` uploads with the scan.
+ */
+
+import { existsSync, promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import {
+  findScriptSandboxTools,
+  runSandboxedScript,
+} from './script-sandbox.mts'
+import { NODE_MODULES, PACKAGE_JSON } from '../../constants/packages.mts'
+import { DOT_SOCKET_DOT_INSTALL_SCRIPTS_JSON } from '../../constants/paths.mts'
+import { listInstalledPackages } from '../audit-installed/installed-tree.mts'
+
+import type { ScriptSandboxTools } from './script-sandbox.mts'
+import type { StraceSummary } from './strace-transcript.mts'
+import type { CResult } from '../../types.mts'
+import type { HandoffPlan } from '../../util/cli/define-handoff.mts'
+import type { InstalledPackage } from '../audit-installed/installed-tree.mts'
+
+const logger = getDefaultLogger()
+
+// npm commands that run the install scripts of dependencies.
+const NPM_SCRIPT_INSTALL_COMMANDS = new Set(['add', 'ci', 'i', 'install'])
+
+const INSTALL_SCRIPT_EVENTS = ['preinstall', 'install', 'postinstall']
+
+// The project's own scripts --ignore-scripts skips as well.
+const PROJECT_SCRIPT_EVENTS = [...INSTALL_SCRIPT_EVENTS, 'prepare']
+
+export type InstallScript = {
+  event: string
+  script: string
+}
+
+export type InstallScriptTranscript = StraceSummary & {
+  location: string
+  name: string
+  version: string
+  event: string
+  script: string
+  exitCode: number
+}
+
+export type InstallScriptsReport = {
+  sandbox: 'bubblewrap'
+  updatedAt: string
+  transcripts: InstallScriptTranscript[]
+}
+
+/**
+ * Whether npm args are an install that runs scripts of dependencies.
+ */
+export function isNpmScriptsInstall(args: readonly string[]): boolean {
+  const command = args.find(arg => !arg.startsWith('-'))
+  return !!command && NPM_SCRIPT_INSTALL_COMMANDS.has(command)
+}
+
+async function readScripts(
+  dir: string,
+): Promise<Record<string, string | undefined>> {
+  try {
+    const { scripts } = JSON.parse(
+      await fs.readFile(path.join(dir, PACKAGE_JSON), 'utf8'),
+    ) as { scripts?: Record<string, string | undefined> | undefined }
+    return scripts ?? {}
+  } catch {
+    return {}
+  }
+}
+
+/**
+ * The install scripts of an installed package in the order npm runs them.
+ * Like npm, a package with a binding.gyp and no install or preinstall
+ * script gets `node-gyp rebuild` as its install script.
+ */
+export async function getInstallScripts(
+  pkg: InstalledPackage,
+): Promise<InstallScript[]> {
+  const scripts = await readScripts(pkg.dir)
+  if (
+    !scripts['install'] &&
+    !scripts['preinstall'] &&
+    existsSync(path.join(pkg.dir, 'binding.gyp'))
+  ) {
+    scripts['install'] = 'node-gyp rebuild'
+  }
+  return INSTALL_SCRIPT_EVENTS.filter(event => scripts[event]).map(event => ({
+    event,
+    script: scripts[event]!,
+  }))
+}
+
+function getDepth(location: string): number {
+  return location.split(`${NODE_MODULES}/`).length
+}
+
+// The environment npm gives lifecycle scripts, without the config of the
+// user: the package's name and version and the .bin folders up to the
+// project.
+function getScriptEnv(
+  cwd: string,
+  pkg: InstalledPackage,
+  { event, script }: InstallScript,
+): Record<string, string> {
+  const binDirs: string[] = []
+  for (let dir = pkg.dir; dir.startsWith(cwd); dir = path.dirname(dir)) {
+    if (path.basename(dir) !== NODE_MODULES) {
+      binDirs.push(path.join(dir, NODE_MODULES, '.bin'))
+    }
+    if (dir === cwd) {
+      break
+    }
+  }
+  return {
+    HOME: process.env['HOME'] || '/tmp',
+    INIT_CWD: cwd,
+    PATH: [
+      ...binDirs,
+      path.dirname(process.execPath),
+      '/usr/local/bin',
+      '/usr/bin',
+      '/bin',
+    ].join(path.delimiter),
+    TMPDIR: '/tmp',
+    npm_lifecycle_event: event,
+    npm_lifecycle_script: script,
+    npm_node_execpath: process.execPath,
+    npm_package_json: path.join(pkg.dir, PACKAGE_JSON),
+    npm_package_name: pkg.name,
+    npm_package_version: pkg.version,
+  }
+}
+
+async function readReport(
+  reportPath: string,
+): Promise<InstallScriptsReport | undefined> {
+  try {
+    return JSON.parse(
+      await fs.readFile(reportPath, 'utf8'),
+    ) as InstallScriptsReport
+  } catch {
+    return undefined
+  }
+}
+
+function formatEndpoints(transcript: InstallScriptTranscript): string {
+  return transcript.network.map(n => `${n.address}:${n.port}`).join(', ')
+}
+
+/**
+ * Run the install scripts of the packages the install added or changed,
+ * compared to the locations and versions installed before, and update the
+ * transcript of the project. Fails when a script exits with an error.
+ */
+export async function runSandboxedInstallScripts(
+  cwd: string,
+  tools: ScriptSandboxTools,
+  before: ReadonlyMap<string, string>,
+): Promise<CResult<InstallScriptsReport | undefined>> {
+  const installed = await listInstalledPackages(cwd)
+  const changed = installed
+    .filter(pkg => before.get(pkg.location) !== pkg.version)
+    .sort(
+      (a, b) =>
+        getDepth(b.location) - getDepth(a.location) ||
+        a.location.localeCompare(b.location),
+    )
+
+  const transcripts: InstallScriptTranscript[] = []
+  const failures: string[] = []
+  const spinner = getDefaultSpinner()
+  for (const pkg of changed) {
+    for (const installScript of await getInstallScripts(pkg)) {
+      const { event, script } = installScript
+      spinner.start(
+        `Running ${pkg.name}@${pkg.version} ${event} in the sandbox…`,
+      )
+      const { exitCode, output, summary } = await runSandboxedScript(tools, {
+        cwd,
+        env: getScriptEnv(cwd, pkg, installScript),
+        pkgDir: pkg.dir,
+        script,
+      })
+      spinner.stop()
+      const transcript = {
+        location: pkg.location,
+        name: pkg.name,
+        version: pkg.version,
+        event,
+        script,
+        exitCode,
+        ...summary,
+      }
+      transcripts.push(transcript)
+      if (transcript.network.length) {
+        logger.warn(
+          `${pkg.name}@${pkg.version} ${event} tried to reach ${formatEndpoints(transcript)}`,
+        )
+      }
+      if (exitCode !== 0) {
+        failures.push(
+          `${pkg.name}@${pkg.version} ${event} exited with code ${exitCode}`,
+        )
+        if (output.trim()) {
+          logger.error(output.trimEnd())
+        }
+        // npm skips the remaining scripts of a package whose script failed.
+        break
+      }
+    }
+  }
+
+  const projectScripts = await readScripts(cwd)
+  const skipped = PROJECT_SCRIPT_EVENTS.filter(event => projectScripts[event])
+  if (skipped.length) {
+    logger.warn(
+      `The project's own ${skipped.join(', ')} ${skipped.length === 1 ? 'script was' : 'scripts were'} not run, run ${skipped.length === 1 ? 'it' : 'them'} with \`npm run\``,
+    )
+  }
+
+  const reportPath = path.join(cwd, DOT_SOCKET_DOT_INSTALL_SCRIPTS_JSON)
+  const previous = await readReport(reportPath)
+  if (!transcripts.length && !previous) {
+    return { ok: true, data: undefined }
+  }
+  // Transcripts of earlier installs stay while their package is unchanged.
+  const ran = new Set(transcripts.map(t => t.location))
+  const versions = new Map(installed.map(pkg => [pkg.location, pkg.version]))
+  const report: InstallScriptsReport = {
+    sandbox: 'bubblewrap',
+    updatedAt: new Date().toISOString(),
+    transcripts: [
+      ...(previous?.transcripts ?? []).filter(
+        t => !ran.has(t.location) && versions.get(t.location) === t.version,
+      ),
+      ...transcripts,
+    ].sort((a, b) => a.location.localeCompare(b.location)),
+  }
+  await fs.writeFile(
+    reportPath,
+    `${JSON.stringify(report, null, 2)}\n`,
+    'utf8',
+  )
+
+  if (transcripts.length) {
+    logger.success(
+      `Ran ${transcripts.length} install ${transcripts.length === 1 ? 'script' : 'scripts'} in the sandbox, the transcript is in ${DOT_SOCKET_DOT_INSTALL_SCRIPTS_JSON}`,
+    )
+  }
+  if (failures.length) {
+    return {
+      ok: false,
+      message: 'Install scripts failed in the sandbox',
+      cause: `${failures.join('\n')}\nSee ${DOT_SOCKET_DOT_INSTALL_SCRIPTS_JSON} for what they attempted; once reviewed, \`npm rebuild <package>\` runs them without the sandbox`,
+    }
+  }
+  return { ok: true, data: report }
+}
+
+/**
+ * The hand-off of `socket npm` with --sandbox-scripts: forward the install
+ * with --ignore-scripts and run the scripts sandboxed after it succeeds.
+ * Other npm commands are forwarded unchanged.
+ */
+export async function prepareSandboxedInstall(
+  args: readonly string[],
+  flags: Record<string, unknown>,
+  cwd = process.cwd(),
+): Promise<CResult<HandoffPlan>> {
+  if (!flags['sandboxScripts'] || !isNpmScriptsInstall(args)) {
+    return { ok: true, data: { args } }
+  }
+  if (args.includes('-g') || args.includes('--global')) {
+    return {
+      ok: false,
+      message: 'Global installs cannot be sandboxed',
+      cause:
+        '--sandbox-scripts runs the install scripts of a project, drop -g or --sandbox-scripts',
+    }
+  }
+  const toolsCResult = await findScriptSandboxTools()
+  if (!toolsCResult.ok) {
+    return toolsCResult
+  }
+  const before = new Map(
+    (await listInstalledPackages(cwd)).map(pkg => [pkg.location, pkg.version]),
+  )
+  return {
+    ok: true,
+    data: {
+      args: [...args, '--ignore-scripts'],
+      afterExit: () =>
+        runSandboxedInstallScripts(cwd, toolsCResult.data, before),
+    },
+  }
+}
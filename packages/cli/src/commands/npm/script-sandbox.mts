@@ -0,0 +1,189 @@
+/**
+ * Run a package script in a sandbox, for `socket npm --sandbox-scripts`.
+ *
+ * The sandbox is bubblewrap (https://github.com/containers/bubblewrap) with
+ * every namespace unshared, so there is no network, and a read-only view of
+ * the filesystem: only the package folder and a private /tmp are writable,
+ * the home folder is an empty tmpfs with just the project mounted back, and
+ * the environment is cleared, so tokens in env vars and dotfiles stay out
+ * of reach. Inside, strace records what the script attempted.
+ *
+ * Only Linux is supported, with `bwrap` and `strace` on PATH.
+ */
+
+import { existsSync, promises as fs } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { whichReal } from '@socketsecurity/lib-stable/bin/which'
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+import { isSpawnError } from '@socketsecurity/lib-stable/process/spawn/errors'
+
+import { STRACE_ARGS, summarizeStrace } from './strace-transcript.mts'
+
+import type { StraceSummary } from './strace-transcript.mts'
+import type { CResult } from '../../types.mts'
+
+export type ScriptSandboxTools = {
+  bwrap: string
+  strace: string
+}
+
+export type SandboxedScript = {
+  // The project root, mounted read-only.
+  cwd: string
+  env: Record<string, string>
+  pkgDir: string
+  script: string
+}
+
+export type SandboxedScriptResult = {
+  exitCode: number
+  // Combined stdout and stderr of the script.
+  output: string
+  summary: StraceSummary
+}
+
+/**
+ * Find bwrap and strace, failing on platforms other than Linux.
+ */
+export async function findScriptSandboxTools(): Promise<
+  CResult<ScriptSandboxTools>
+> {
+  if (process.platform !== 'linux') {
+    return {
+      ok: false,
+      message: 'Sandboxed install scripts are not supported',
+      cause: `--sandbox-scripts needs Linux with bubblewrap and strace, ${process.platform} is not supported`,
+    }
+  }
+  const tools: Partial<ScriptSandboxTools> = {}
+  const missing: string[] = []
+  for (const name of ['bwrap', 'strace'] as const) {
+    const toolPath = await whichReal(name, { nothrow: true })
+    if (toolPath && !Array.isArray(toolPath)) {
+      tools[name] = toolPath
+    } else {
+      missing.push(name)
+    }
+  }
+  if (missing.length) {
+    return {
+      ok: false,
+      message: 'Sandbox tools not found',
+      cause: `--sandbox-scripts runs install scripts with bubblewrap and traces them with strace; install ${missing.join(' and ')} (e.g. \`apt install bubblewrap strace\`) and make sure ${missing.length === 1 ? 'it is' : 'they are'} on PATH`,
+    }
+  }
+  return { ok: true, data: tools as ScriptSandboxTools }
+}
+
+/**
+ * The bwrap arguments for a script, up to the command to run. Later mounts
+ * win, so the paths mounted back are listed after the tmpfs that hide
+ * them.
+ */
+export function getBwrapArgs(options: {
+  env: Record<string, string>
+  home?: string | undefined
+  pkgDir: string
+  readablePaths: string[]
+  traceDir: string
+}): string[] {
+  const { env, home, pkgDir, readablePaths, traceDir } = options
+  return [
+    '--unshare-all',
+    '--die-with-parent',
+    '--new-session',
+    '--ro-bind',
+    '/',
+    '/',
+    '--dev',
+    '/dev',
+    '--proc',
+    '/proc',
+    '--tmpfs',
+    '/tmp',
+    ...(home ? ['--tmpfs', home] : []),
+    ...readablePaths.flatMap(dir => ['--ro-bind', dir, dir]),
+    '--bind',
+    pkgDir,
+    pkgDir,
+    '--bind',
+    traceDir,
+    traceDir,
+    '--chdir',
+    pkgDir,
+    '--clearenv',
+    ...Object.entries(env).flatMap(({ 0: key, 1: value }) => [
+      '--setenv',
+      key,
+      value,
+    ]),
+  ]
+}
+
+function getHomeDir(): string | undefined {
+  const home = os.homedir()
+  return home && home !== path.parse(home).root ? home : undefined
+}
+
+/**
+ * Run a script with `sh -c` in the sandbox. A script that fails resolves
+ * with its exit code rather than throwing.
+ */
+export async function runSandboxedScript(
+  tools: ScriptSandboxTools,
+  { cwd, env, pkgDir, script }: SandboxedScript,
+): Promise<SandboxedScriptResult> {
+  const home = getHomeDir()
+  // Node.js is often installed in the home folder, e.g. by nvm.
+  const readablePaths = [cwd, path.dirname(path.dirname(process.execPath))]
+  const traceDir = await fs.mkdtemp(
+    path.join(os.tmpdir(), 'socket-sandbox-scripts-'),
+  )
+  const traceFile = path.join(traceDir, 'strace.log')
+  try {
+    let exitCode = 0
+    let output = ''
+    try {
+      const result = await spawn(
+        tools.bwrap,
+        [
+          ...getBwrapArgs({ env, home, pkgDir, readablePaths, traceDir }),
+          '--',
+          tools.strace,
+          ...STRACE_ARGS,
+          '-o',
+          traceFile,
+          '/bin/sh',
+          '-c',
+          script,
+        ],
+        { stdio: 'pipe' },
+      )
+      output = `${result.stdout ?? ''}${result.stderr ?? ''}`
+    } catch (e) {
+      if (!isSpawnError(e)) {
+        throw e
+      }
+      exitCode = typeof e.code === 'number' ? e.code : 1
+      output = `${e.stdout ?? ''}${e.stderr ?? ''}`
+    }
+    const trace = existsSync(traceFile)
+      ? await fs.readFile(traceFile, 'utf8')
+      : ''
+    return {
+      exitCode,
+      output,
+      summary: summarizeStrace(trace, {
+        cwd: pkgDir,
+        home,
+        readablePaths,
+        writablePaths: [pkgDir, '/tmp', '/dev'],
+      }),
+    }
+  } finally {
+    await safeDelete(traceDir, { force: true })
+  }
+}
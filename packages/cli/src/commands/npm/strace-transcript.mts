@@ -0,0 +1,207 @@
+/**
+ * Summaries of the strace output of sandboxed install scripts, for
+ * `socket npm --sandbox-scripts`.
+ *
+ * The scripts run under `strace -f -o <file>`, which writes one line per
+ * system call prefixed with the process ID. A call that blocks is split
+ * into an `<unfinished ...>` line and a later `<... name resumed>` line,
+ * which are joined again before parsing. Only what a reviewer cares about
+ * is kept: the programs run, the network endpoints contacted, the files
+ * written outside of the package and the files read in the home folder.
+ */
+
+import path from 'node:path'
+
+export type ScriptNetworkAttempt = {
+  address: string
+  port: number
+  syscall: string
+  // Whether the sandbox refused the call.
+  denied: boolean
+}
+
+export type ScriptFileWrite = {
+  path: string
+  denied: boolean
+}
+
+export type StraceSummary = {
+  commands: string[]
+  network: ScriptNetworkAttempt[]
+  fileWrites: ScriptFileWrite[]
+  homeReads: string[]
+}
+
+export type StraceSummaryOptions = {
+  // Folder relative paths resolve against, the package folder.
+  cwd: string
+  // Home folder of the user, hidden in the sandbox.
+  home?: string | undefined
+  // Folders in the home folder the sandbox exposes, e.g. the project.
+  readablePaths?: string[] | undefined
+  // Folders the script may write to without the write being reported.
+  writablePaths: string[]
+}
+
+// Enough to review, a misbehaving script can make millions of calls.
+const MAX_ENTRIES = 200
+
+const NETWORK_SYSCALLS = new Set(['connect', 'sendmsg', 'sendto'])
+
+const OPEN_SYSCALLS = new Set(['open', 'openat'])
+
+const WRITE_SYSCALLS = new Set([
+  'chmod',
+  'creat',
+  'fchmodat',
+  'link',
+  'linkat',
+  'mkdir',
+  'mkdirat',
+  'rename',
+  'renameat',
+  'renameat2',
+  'rmdir',
+  'symlink',
+  'symlinkat',
+  'unlink',
+  'unlinkat',
+])
+
+const UNFINISHED_SUFFIX = ' <unfinished ...>'
+
+// The arguments of strace to trace the calls summarized here.
+export const STRACE_ARGS = [
+  '-f',
+  '-qq',
+  '-s',
+  '256',
+  '-e',
+  `trace=execve,${[...NETWORK_SYSCALLS, ...OPEN_SYSCALLS, ...WRITE_SYSCALLS].join(',')}`,
+]
+
+function readQuotedStrings(text: string): string[] {
+  return [...text.matchAll(/"((?:[^"\\]|\\.)*)"/g)].map(m =>
+    m[1]!.replace(/\\(["\\])/g, '$1'),
+  )
+}
+
+function isInside(file: string, dirs: string[]): boolean {
+  return dirs.some(
+    dir => file === dir || file.startsWith(`${dir}${path.sep}`),
+  )
+}
+
+function readNetworkAddress(
+  args: string,
+): { address: string; port: number } | undefined {
+  const port = /sin6?_port=htons\((\d+)\)/.exec(args)?.[1]
+  const address =
+    /sin_addr=inet_addr\("([^"]+)"\)/.exec(args)?.[1] ??
+    /inet_pton\(AF_INET6, "([^"]+)"/.exec(args)?.[1]
+  return port && address ? { address, port: Number(port) } : undefined
+}
+
+/**
+ * The system calls of a strace output file, with split calls joined.
+ */
+export function readStraceCalls(
+  text: string,
+): Array<{ args: string; result: string; syscall: string }> {
+  const pending = new Map<string, string>()
+  const calls: Array<{ args: string; result: string; syscall: string }> = []
+  for (const line of text.split('\n')) {
+    const { 1: pid = '', 2: rest = line } =
+      /^(\d+)\s+(.*)$/.exec(line) ?? ([] as string[])
+    if (rest.endsWith(UNFINISHED_SUFFIX)) {
+      pending.set(pid, rest.slice(0, -UNFINISHED_SUFFIX.length))
+      continue
+    }
+    let call = rest
+    const resumed = /^<\.\.\. \w+ resumed>(.*)$/.exec(rest)
+    if (resumed) {
+      call = `${pending.get(pid) ?? ''}${resumed[1]}`
+      pending.delete(pid)
+    }
+    const match = /^(\w+)\((.*)\)\s+=\s+(.+)$/.exec(call)
+    if (match) {
+      calls.push({ syscall: match[1]!, args: match[2]!, result: match[3]! })
+    }
+  }
+  return calls
+}
+
+/**
+ * Summarize the strace output of one script.
+ */
+export function summarizeStrace(
+  text: string,
+  options: StraceSummaryOptions,
+): StraceSummary {
+  const { cwd, home, readablePaths = [], writablePaths } = {
+    __proto__: null,
+    ...options,
+  } as StraceSummaryOptions
+  const commands = new Set<string>()
+  const network = new Map<string, ScriptNetworkAttempt>()
+  const fileWrites = new Map<string, ScriptFileWrite>()
+  const homeReads = new Set<string>()
+
+  const addWrite = (file: string, denied: boolean) => {
+    const resolved = path.resolve(cwd, file)
+    if (!isInside(resolved, writablePaths) && fileWrites.size < MAX_ENTRIES) {
+      const seen = fileWrites.get(resolved)
+      fileWrites.set(resolved, {
+        path: resolved,
+        denied: denied && (seen?.denied ?? true),
+      })
+    }
+  }
+
+  for (const { args, result, syscall } of readStraceCalls(text)) {
+    const denied = result.startsWith('-1 ')
+    if (syscall === 'execve') {
+      const [file, ...argv] = readQuotedStrings(args)
+      const command = argv.join(' ') || file
+      if (command && !denied && commands.size < MAX_ENTRIES) {
+        commands.add(command)
+      }
+    } else if (NETWORK_SYSCALLS.has(syscall)) {
+      const endpoint = readNetworkAddress(args)
+      if (endpoint && network.size < MAX_ENTRIES) {
+        const key = `${endpoint.address}:${endpoint.port}`
+        network.set(key, {
+          ...endpoint,
+          syscall,
+          denied: denied && (network.get(key)?.denied ?? true),
+        })
+      }
+    } else if (OPEN_SYSCALLS.has(syscall)) {
+      const file = readQuotedStrings(args)[0]
+      if (!file) {
+        continue
+      }
+      if (/O_WRONLY|O_RDWR|O_CREAT|O_TRUNC/.test(args)) {
+        addWrite(file, denied)
+      } else if (home && homeReads.size < MAX_ENTRIES) {
+        const resolved = path.resolve(cwd, file)
+        if (
+          isInside(resolved, [home]) &&
+          !isInside(resolved, [...readablePaths, ...writablePaths])
+        ) {
+          homeReads.add(resolved)
+        }
+      }
+    } else if (WRITE_SYSCALLS.has(syscall)) {
+      for (const file of readQuotedStrings(args)) {
+        addWrite(file, denied)
+      }
+    }
+  }
+  return {
+    commands: [...commands],
+    network: [...network.values()],
+    fileWrites: [...fileWrites.values()],
+    homeReads: [...homeReads].sort(),
+  }
+}
@@ -20,7 +20,10 @@ import {
 import { lockSrcIncludes } from './lockfile-includes-by-agent.mts'
 import { listPackages } from './ls-by-agent.mts'
 import { CMD_NAME } from './shared.mts'
-import { updateManifest } from './update-manifest-by-agent.mts'
+import {
+  getPkgJsonChange,
+  updateManifest,
+} from './update-manifest-by-agent.mts'
 import { globWorkspace } from '../../util/fs/glob.mts'
 import { safeNpa } from '../../util/npm/package-arg.mts'
 import { cmdPrefixMessage } from '../../util/process/cmd.mts'
@@ -29,10 +32,13 @@ import { getMajor } from '../../util/semver.mts'
 import type { GetOverridesResult } from './get-overrides-by-agent.mts'
 import type { EnvDetails } from '../../util/ecosystem/environment.mjs'
 import type { AliasResult } from '../../util/npm/package-arg.mts'
+import type { UnifiedDiffFile } from '../../util/output/unified-diff.mts'
 import type { Logger } from '@socketsecurity/lib-stable/logger/logger'
 import type { SpinnerInstance } from '@socketsecurity/lib-stable/spinner/types'
 
 export type AddOverridesOptions = {
+  // Collect the manifest changes in state.files instead of writing them.
+  dryRun?: boolean | undefined
   logger?: Logger | undefined
   pin?: boolean | undefined
   prod?: boolean | undefined
@@ -42,6 +48,8 @@ export type AddOverridesOptions = {
 export type AddOverridesState = {
   added: Set<string>
   addedInWorkspaces: Set<string>
+  // The changes a dry run would write.
+  files?: UnifiedDiffFile[] | undefined
   updated: Set<string>
   updatedInWorkspaces: Set<string>
   warnedPnpmWorkspaceRequiresNpm: boolean
@@ -62,6 +70,7 @@ export async function addOverrides(
     pkgPath: rootPath,
   } = pkgEnvDetails
   const {
+    dryRun,
     logger,
     pin,
     prod,
@@ -278,6 +287,7 @@ export async function addOverrides(
           pkgEnvDetails,
           path.dirname(workspacePkgJsonPath),
           {
+            dryRun,
             logger,
             pin,
             prod,
@@ -307,16 +317,31 @@ export async function addOverrides(
 
   if (state.added.size > 0 || state.updated.size > 0) {
     pkgEnvDetails.editablePkgJson.update(Object.fromEntries(depEntries))
+    const files: UnifiedDiffFile[] = []
     if (isWorkspaceRoot) {
       for (const { overrides, type } of overridesDataObjects) {
         // updateManifest is async because the pnpm 11+ path writes to
         // pnpm-workspace.yaml; older pnpm and other agents resolve
         // synchronously inside this call.
         // each agent's overrides write must complete before the next.
-        await updateManifest(type, pkgEnvDetails, toSortedObject(overrides))
+        const yamlFile = await updateManifest(
+          type,
+          pkgEnvDetails,
+          toSortedObject(overrides),
+          { dryRun },
+        )
+        if (yamlFile) {
+          files.push(yamlFile)
+        }
+      }
+    }
+    if (dryRun) {
+      if (isWorkspaceRoot) {
+        state.files = [getPkgJsonChange(pkgEnvDetails), ...files]
       }
+    } else {
+      await pkgEnvDetails.editablePkgJson.save()
     }
-    await pkgEnvDetails.editablePkgJson.save()
   }
 
   return state
@@ -0,0 +1,127 @@
+import { existsSync, readFileSync, writeFileSync } from 'node:fs'
+import path from 'node:path'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import {
+  addSafeOverrides,
+  getSafeOverrides,
+  getTransitivePurls,
+  readLockedPackages,
+} from './safe-overrides.mts'
+import { CMD_NAME } from './shared.mts'
+import { updateDependencies } from './update-dependencies.mts'
+import { fetchPurlsShallowScore } from '../package/fetch-purls-shallow-score.mts'
+
+import type { SafeOverride, UnfixablePackage } from './safe-overrides.mts'
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { EnvDetails } from '../../util/ecosystem/environment.mjs'
+
+export type SafeOverridesConfig = {
+  pin: boolean
+  prod: boolean
+}
+
+export type SafeOverridesResult = {
+  // Written and picked up by the lockfile.
+  overrides: SafeOverride[]
+  // Vulnerable packages without a patched version.
+  unfixable: UnfixablePackage[]
+  // Written, but the lockfile still resolves the vulnerable version.
+  unresolved: SafeOverride[]
+}
+
+function backupFiles(filepaths: string[]): () => void {
+  const backups = filepaths
+    .filter(filepath => existsSync(filepath))
+    .map(filepath => ({ content: readFileSync(filepath), filepath }))
+  return () => {
+    for (const { content, filepath } of backups) {
+      writeFileSync(filepath, content)
+    }
+  }
+}
+
+export async function applySafeOverrides(
+  pkgEnvDetails: EnvDetails,
+  { pin, prod }: SafeOverridesConfig,
+): Promise<CResult<SafeOverridesResult>> {
+  const logger = getDefaultLogger()
+  const spinner = getDefaultSpinner()
+
+  const safeCResult = await fetchSafeOverrides(pkgEnvDetails, { prod })
+  if (!safeCResult.ok) {
+    return safeCResult
+  }
+  const { overrides, unfixable } = safeCResult.data
+  if (!overrides.length) {
+    return { ok: true, data: { overrides: [], unfixable, unresolved: [] } }
+  }
+
+  const { lockPath, pkgPath } = pkgEnvDetails
+  // A failed install must not leave half-applied overrides behind.
+  const restore = backupFiles([
+    path.join(pkgPath, 'package.json'),
+    path.join(pkgPath, 'pnpm-workspace.yaml'),
+    lockPath,
+  ])
+
+  spinner?.start()
+
+  await addSafeOverrides(pkgEnvDetails, overrides, { pin })
+
+  const installCResult = await updateDependencies(pkgEnvDetails, {
+    cmdName: CMD_NAME,
+    logger,
+    spinner: spinner ?? undefined,
+  })
+  spinner?.stop()
+  if (!installCResult.ok) {
+    restore()
+    return {
+      ...installCResult,
+      cause: `${installCResult.cause ?? installCResult.message}. The overrides were reverted.`,
+    }
+  }
+
+  // The lockfile resolves an override when the vulnerable version is gone.
+  const installed = new Set(
+    readLockedPackages(pkgEnvDetails).map(p => `${p.name}@${p.version}`),
+  )
+  const isUnresolved = (o: SafeOverride) =>
+    installed.has(`${o.name}@${o.version}`)
+  return {
+    ok: true,
+    data: {
+      overrides: overrides.filter(o => !isUnresolved(o)),
+      unfixable,
+      unresolved: overrides.filter(isUnresolved),
+    },
+  }
+}
+
+/**
+ * The safe version overrides of the alerted transitive dependencies in the
+ * lockfile, fetched from the Socket API.
+ */
+export async function fetchSafeOverrides(
+  pkgEnvDetails: EnvDetails,
+  { prod }: Pick<SafeOverridesConfig, 'prod'>,
+): Promise<CResult<Omit<SafeOverridesResult, 'unresolved'>>> {
+  const purls = getTransitivePurls(readLockedPackages(pkgEnvDetails), { prod })
+  if (!purls.length) {
+    return { ok: true, data: { overrides: [], unfixable: [] } }
+  }
+  const scoreCResult = await fetchPurlsShallowScore(purls, {
+    commandPath: CMD_NAME,
+  })
+  if (!scoreCResult.ok) {
+    return scoreCResult
+  }
+  return {
+    ok: true,
+    data: getSafeOverrides(scoreCResult.data as unknown as SocketArtifact[]),
+  }
+}
@@ -1,20 +1,31 @@
 import path from 'node:path'
 
+import { normalizePath } from '@socketsecurity/lib-stable/paths/normalize'
+
 import { handleOptimize } from './handle-optimize.mts'
+import { outputSafeOverrides } from './output-safe-overrides.mts'
+import {
+  previewOptimization,
+  previewSafeOverrides,
+} from './preview-optimization.mts'
 import { CMD_NAME as CMD_NAME_FULL } from './shared.mts'
 import { defineFlags } from '../../meow.mts'
 import { commonFlags } from '../../flags.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
-import { outputDryRunPreview } from '../../util/dry-run/output.mts'
+import {
+  outputDryRunDiff,
+  outputDryRunPreview,
+} from '../../util/dry-run/output.mts'
 import { detectAndValidatePackageEnvironment } from '../../util/ecosystem/environment.mjs'
 import {
   getFlagApiRequirementsOutput,
   getFlagListOutput,
 } from '../../util/output/formatting.mts'
 import { getOutputKind } from '../../util/output/mode.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
 
 import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
-import type { DryRunAction } from '../../util/dry-run/output.mts'
 import type { MeowFlags } from '../../flags.mts'
 
 export const CMD_NAME = 'optimize'
@@ -50,6 +61,12 @@ export async function run(
         default: false,
         description: 'Add overrides for production dependencies only',
       },
+      safeVersions: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Pin alerted transitive dependencies to patched versions instead of adding @socketregistry overrides',
+      },
     }),
     help: (command: string, helpConfig: { flags: MeowFlags }) => `
     Usage
@@ -61,9 +78,17 @@ export async function run(
     Options
       ${getFlagListOutput(helpConfig.flags)}
 
+    With --safe-versions the transitive dependencies with vulnerability
+    alerts are pinned to their first patched version with \`overrides\`
+    (npm, pnpm) or \`resolutions\` (yarn, bun). Use this when the direct
+    dependency that pulls them in can't be bumped yet. The command reinstalls
+    and checks that the lockfile no longer resolves the vulnerable versions;
+    a failed install reverts package.json and the lockfile.
+
     Examples
       $ ${command}
       $ ${command} ./path/to/project --pin
+      $ ${command} --safe-versions --prod
   `,
   }
 
@@ -76,7 +101,7 @@ export async function run(
 
   const dryRun = cli.flags['dryRun']
 
-  const { json, markdown, pin, prod } = cli.flags
+  const { json, markdown, pin, prod, safeVersions } = cli.flags
 
   let [cwd = '.'] = cli.input
   // Note: path.resolve vs .join:
@@ -85,6 +110,16 @@ export async function run(
 
   const outputKind = getOutputKind(json, markdown)
 
+  const wasValidInput = checkCommandInput(outputKind, {
+    nook: true,
+    test: !safeVersions || !!dryRun || hasDefaultApiToken(),
+    message: 'The --safe-versions flag requires a Socket API token for access',
+    fail: 'try `socket login`',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
   if (dryRun) {
     // Detect package environment to show meaningful dry-run output.
     const pkgEnvCResult = await detectAndValidatePackageEnvironment(cwd, {
@@ -108,43 +143,97 @@ export async function run(
     }
 
     const pkgEnvDetails = pkgEnvCResult.data
-    const { agent, agentVersion, pkgPath } = pkgEnvDetails
-
-    const actions: DryRunAction[] = [
-      {
-        type: 'fetch',
-        description: `Detected ${agent} v${agentVersion.version}`,
-        target: pkgPath,
-      },
-      {
-        type: 'fetch',
-        description: 'Analyze dependencies against @socketregistry overrides',
-        target: 'package.json and lockfile',
-      },
-      {
-        type: 'modify',
-        description: 'Add or update overrides section in package.json',
-        target: path.join(pkgPath, 'package.json'),
-        details: {
-          pin: pin
-            ? 'Yes - pin to specific versions'
-            : 'No - use version ranges',
-          prod: prod
-            ? 'Yes - production dependencies only'
-            : 'No - all dependencies',
-        },
-      },
-      {
-        type: 'execute',
-        description: `Run ${agent} to install optimized dependencies`,
-      },
-    ]
+    const { agent, agentVersion, lockName, pkgPath } = pkgEnvDetails
+    const relative = (file: string) =>
+      normalizePath(path.relative(pkgPath, file))
+    const installChange = `Run ${agent} install to update ${lockName}`
+
+    if (safeVersions) {
+      // Without a token the alerts can't be fetched, so only the plan is
+      // shown.
+      if (!hasDefaultApiToken()) {
+        outputDryRunPreview({
+          summary: `Pin alerted transitive dependencies to safe versions (${agent} v${agentVersion.version})`,
+          actions: [
+            {
+              type: 'fetch',
+              description: `Detected ${agent} v${agentVersion.version}`,
+              target: pkgPath,
+            },
+            {
+              type: 'fetch',
+              description:
+                'Fetch alerts of the transitive dependencies in the lockfile',
+              target: lockName,
+            },
+            {
+              type: 'modify',
+              description: 'Add overrides for the first patched versions',
+              target: path.join(pkgPath, 'package.json'),
+              details: {
+                pin: pin
+                  ? 'Yes - pin to the patched versions'
+                  : 'No - use ^ ranges of the patched versions',
+                prod: prod
+                  ? 'Yes - production dependencies only'
+                  : 'No - all dependencies',
+              },
+            },
+            {
+              type: 'execute',
+              description: `Run ${agent} and verify ${lockName} resolves the patched versions`,
+            },
+          ],
+          wouldSucceed: true,
+        })
+        return
+      }
+      const previewCResult = await previewSafeOverrides(pkgEnvDetails, {
+        pin,
+        prod,
+      })
+      if (!previewCResult.ok) {
+        await outputSafeOverrides(previewCResult, outputKind)
+        return
+      }
+      const { files, overrides, unfixable } = previewCResult.data
+      outputDryRunDiff(
+        `pin alerted transitive dependencies to safe versions (${agent} v${agentVersion.version})`,
+        files.map(file => ({ ...file, path: relative(file.path) })),
+        [
+          ...overrides.map(
+            o => `Pin ${o.name}@${o.version} to ${o.safeVersion}`,
+          ),
+          ...unfixable.map(
+            p => `Leave ${p.name}@${p.version}, no patched version`,
+          ),
+          ...(files.length ? [installChange] : []),
+        ],
+      )
+      return
+    }
 
-    outputDryRunPreview({
-      summary: `Optimize dependencies with @socketregistry overrides (${agent} v${agentVersion.version})`,
-      actions,
-      wouldSucceed: true,
-    })
+    const { added, files, updated } = await previewOptimization(
+      pkgEnvDetails,
+      { pin, prod },
+    )
+    outputDryRunDiff(
+      `optimize dependencies with @socketregistry overrides (${agent} v${agentVersion.version})`,
+      files.map(file => ({ ...file, path: relative(file.path) })),
+      files.length
+        ? [
+            ...added.map(name => `Add an override with ${name}`),
+            ...updated.map(name => `Update the override with ${name}`),
+            pin
+              ? 'Overrides pin to specific versions'
+              : 'Overrides use version ranges',
+            prod
+              ? 'Overrides for production dependencies only'
+              : 'Overrides for all dependencies',
+            installChange,
+          ]
+        : ['No overrides to add or update'],
+    )
     return
   }
 
@@ -153,5 +242,6 @@ export async function run(
     pin: pin,
     outputKind,
     prod: prod,
+    safeVersions,
   })
 }
@@ -3,7 +3,9 @@ import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
 import { applyOptimization } from './apply-optimization.mts'
+import { applySafeOverrides } from './apply-safe-overrides.mts'
 import { outputOptimizeResult } from './output-optimize-result.mts'
+import { outputSafeOverrides } from './output-safe-overrides.mts'
 import { CMD_NAME } from './shared.mts'
 import { detectAndValidatePackageEnvironment } from '../../util/ecosystem/environment.mjs'
 import { cmdPrefixMessage } from '../../util/process/cmd.mts'
@@ -15,16 +17,18 @@ export async function handleOptimize({
   outputKind,
   pin,
   prod,
+  safeVersions = false,
 }: {
   cwd: string
   outputKind: OutputKind
   pin: boolean
   prod: boolean
+  safeVersions?: boolean | undefined
 }) {
   const logger = getDefaultLogger()
 
   debug(`Starting optimization for ${cwd}`)
-  debugDir({ cwd, outputKind, pin, prod })
+  debugDir({ cwd, outputKind, pin, prod, safeVersions })
 
   const pkgEnvCResult = await detectAndValidatePackageEnvironment(cwd, {
     cmdName: CMD_NAME,
@@ -77,6 +81,20 @@ export async function handleOptimize({
     return
   }
 
+  if (safeVersions) {
+    logger.info(
+      `Pinning alerted transitive dependencies for ${agent} v${agentVersion.version}.`,
+    )
+    logger.error('')
+
+    debug('Applying safe version overrides')
+    const safeCResult = await applySafeOverrides(pkgEnvDetails, { pin, prod })
+    debug(`Safe version overrides ${safeCResult.ok ? 'succeeded' : 'failed'}`)
+    debugDir({ safeCResult })
+    await outputSafeOverrides(safeCResult, outputKind)
+    return
+  }
+
   logger.info(`Optimizing packages for ${agent} v${agentVersion.version}.`)
   logger.error('')
 
@@ -0,0 +1,101 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdError, mdHeader, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { SafeOverridesResult } from './apply-safe-overrides.mts'
+import type { SafeOverride, UnfixablePackage } from './safe-overrides.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+function formatOverride(o: SafeOverride): string {
+  return `${o.name}@${o.version} → ${o.safeVersion} (${o.alertTypes.join(', ')})`
+}
+
+function formatUnfixable(p: UnfixablePackage): string {
+  return `${p.name}@${p.version} (${p.alertTypes.join(', ')})`
+}
+
+export async function outputSafeOverrides(
+  result: CResult<SafeOverridesResult>,
+  outputKind: OutputKind,
+) {
+  const logger = getDefaultLogger()
+
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (result.data.unresolved.length) {
+    // The vulnerable versions are still installed.
+    process.exitCode = 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+
+  if (outputKind === 'markdown') {
+    if (!result.ok) {
+      logger.log(mdError(result.message, result.cause))
+      return
+    }
+
+    const { overrides, unfixable, unresolved } = result.data
+    logger.log(mdHeader('Safe Version Overrides'))
+    logger.log('')
+    if (overrides.length) {
+      logger.log(mdHeader('Pinned', 2))
+      logger.log(mdList(overrides.map(formatOverride)))
+      logger.log('')
+    }
+    if (unresolved.length) {
+      logger.log(mdHeader('Not Resolved', 2))
+      logger.log(mdList(unresolved.map(formatOverride)))
+      logger.log('')
+    }
+    if (unfixable.length) {
+      logger.log(mdHeader('No Patched Version', 2))
+      logger.log(mdList(unfixable.map(formatUnfixable)))
+      logger.log('')
+    }
+    if (!overrides.length && !unresolved.length) {
+      logger.log('No alerted transitive dependencies to pin.')
+    }
+    return
+  }
+
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { overrides, unfixable, unresolved } = result.data
+  if (overrides.length) {
+    logger.success(
+      `Pinned ${overrides.length} transitive ${pluralize('dependency', { count: overrides.length })} to safe versions:`,
+    )
+    for (const o of overrides) {
+      logger.log(`  ${formatOverride(o)}`)
+    }
+  }
+  if (unresolved.length) {
+    logger.fail(
+      `The lockfile still resolves ${unresolved.length} vulnerable ${pluralize('version', { count: unresolved.length })} after the install:`,
+    )
+    for (const o of unresolved) {
+      logger.log(`  ${formatOverride(o)}`)
+    }
+  }
+  if (unfixable.length) {
+    logger.warn(
+      `${unfixable.length} vulnerable ${pluralize('package', { count: unfixable.length })} ${unfixable.length === 1 ? 'has' : 'have'} no patched version yet:`,
+    )
+    for (const p of unfixable) {
+      logger.log(`  ${formatUnfixable(p)}`)
+    }
+  }
+  if (!overrides.length && !unresolved.length) {
+    logger.log('Scan complete. No alerted transitive dependencies to pin.')
+  }
+}
@@ -0,0 +1,62 @@
+/**
+ * Dry runs of `socket optimize`. The overrides are added to package.json in
+ * memory only, pnpm-workspace.yaml is left unwritten, and the changes are
+ * handed back for outputDryRunDiff(). The lockfile only changes with the
+ * install that follows, so it can't be diffed up front.
+ */
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { addOverrides } from './add-overrides.mts'
+import { fetchSafeOverrides } from './apply-safe-overrides.mts'
+import { addSafeOverrides } from './safe-overrides.mts'
+
+import type { OptimizeConfig } from './apply-optimization.mts'
+import type { SafeOverride, UnfixablePackage } from './safe-overrides.mts'
+import type { CResult } from '../../types.mts'
+import type { EnvDetails } from '../../util/ecosystem/environment.mjs'
+import type { UnifiedDiffFile } from '../../util/output/unified-diff.mts'
+
+export type OptimizationPreview = {
+  added: string[]
+  files: UnifiedDiffFile[]
+  updated: string[]
+}
+
+export type SafeOverridesPreview = {
+  files: UnifiedDiffFile[]
+  overrides: SafeOverride[]
+  unfixable: UnfixablePackage[]
+}
+
+export async function previewOptimization(
+  pkgEnvDetails: EnvDetails,
+  { pin, prod }: OptimizeConfig,
+): Promise<OptimizationPreview> {
+  const state = await addOverrides(pkgEnvDetails, pkgEnvDetails.pkgPath, {
+    dryRun: true,
+    logger: getDefaultLogger(),
+    pin,
+    prod,
+  })
+  return {
+    added: [...state.added].toSorted(),
+    files: state.files ?? [],
+    updated: [...state.updated].toSorted(),
+  }
+}
+
+export async function previewSafeOverrides(
+  pkgEnvDetails: EnvDetails,
+  { pin, prod }: OptimizeConfig,
+): Promise<CResult<SafeOverridesPreview>> {
+  const safeCResult = await fetchSafeOverrides(pkgEnvDetails, { prod })
+  if (!safeCResult.ok) {
+    return safeCResult
+  }
+  const { overrides, unfixable } = safeCResult.data
+  const files = overrides.length
+    ? await addSafeOverrides(pkgEnvDetails, overrides, { dryRun: true, pin })
+    : []
+  return { ok: true, data: { files, overrides, unfixable } }
+}
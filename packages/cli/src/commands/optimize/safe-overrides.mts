@@ -0,0 +1,326 @@
+/**
+ * Safe version overrides of `socket optimize --safe-versions`.
+ *
+ * Transitive dependencies with vulnerability alerts are pinned to the first
+ * patched version with npm `overrides`, pnpm overrides or yarn and bun
+ * `resolutions`. npm and pnpm keys name the vulnerable version, e.g.
+ * `minimist@1.2.5`, so other versions of the package are left alone; yarn
+ * and bun resolutions can only name the package.
+ *
+ * Direct dependencies are not overridden: bump them in package.json instead,
+ * e.g. with `socket fix`.
+ */
+
+import { readFileSync } from 'node:fs'
+
+// socket-lint: allow bare-semver -- lib-stable 6.0.9 doesn't publish ./external/semver; semver is bundled at build so no runtime dep leaks.
+import semver from 'semver'
+
+import {
+  NPM,
+  PNPM,
+  YARN_CLASSIC,
+} from '@socketsecurity/lib-stable/constants/agents'
+import { toSortedObject } from '@socketsecurity/lib-stable/objects/sort'
+
+import { getDependencyEntries } from './get-dependency-entries.mts'
+import { getOverridesData } from './get-overrides-by-agent.mts'
+import {
+  getPkgJsonChange,
+  updateManifest,
+} from './update-manifest-by-agent.mts'
+import { parseYarnLockV1 } from '../../util/lockfile/bun.mts'
+import { parseLockfile } from '../../util/lockfile/parsers.mts'
+
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { EnvDetails } from '../../util/ecosystem/environment.mjs'
+import type { UnifiedDiffFile } from '../../util/output/unified-diff.mts'
+
+export type LockedPackage = {
+  dev: boolean
+  // Declared by the project or one of its workspaces.
+  direct: boolean
+  name: string
+  version: string
+}
+
+export type SafeOverride = {
+  // Alert types the safe version fixes, e.g. `criticalCVE`.
+  alertTypes: string[]
+  name: string
+  safeVersion: string
+  // The vulnerable version in the lockfile.
+  version: string
+}
+
+export type UnfixablePackage = {
+  alertTypes: string[]
+  name: string
+  version: string
+}
+
+type NpmLockEntry = {
+  dependencies?: Record<string, unknown> | undefined
+  dev?: boolean | undefined
+  devDependencies?: Record<string, unknown> | undefined
+  link?: boolean | undefined
+  name?: string | undefined
+  optionalDependencies?: Record<string, unknown> | undefined
+  peerDependencies?: Record<string, unknown> | undefined
+  version?: string | undefined
+}
+
+type NpmLockfile = {
+  dependencies?: Record<string, NpmLockEntry> | undefined
+  packages?: Record<string, NpmLockEntry> | undefined
+}
+
+const NODE_MODULES_PREFIX = 'node_modules/'
+
+/**
+ * The installed packages of a package-lock.json. Packages declared by the
+ * root or a workspace entry are direct.
+ */
+export function parseNpmLockPackages(lockSrc: string): LockedPackage[] {
+  let lockfile: NpmLockfile
+  try {
+    lockfile = JSON.parse(lockSrc) as NpmLockfile
+  } catch {
+    return []
+  }
+  const packages: LockedPackage[] = []
+  if (lockfile.packages) {
+    const declared = new Set<string>()
+    const entries = Object.entries(lockfile.packages)
+    for (const { 0: key, 1: entry } of entries) {
+      if (!key.includes(NODE_MODULES_PREFIX)) {
+        for (const deps of [
+          entry.dependencies,
+          entry.devDependencies,
+          entry.optionalDependencies,
+          entry.peerDependencies,
+        ]) {
+          for (const name of Object.keys(deps ?? {})) {
+            declared.add(name)
+          }
+        }
+      }
+    }
+    for (const { 0: key, 1: entry } of entries) {
+      const index = key.lastIndexOf(NODE_MODULES_PREFIX)
+      if (index === -1 || entry.link || !entry.version) {
+        continue
+      }
+      const installName = key.slice(index + NODE_MODULES_PREFIX.length)
+      packages.push({
+        dev: !!entry.dev,
+        direct: declared.has(installName),
+        // Aliased packages record the real name.
+        name: entry.name ?? installName,
+        version: entry.version,
+      })
+    }
+    return packages
+  }
+  // Lockfile v1 nests the dependencies of each package.
+  const walk = (deps: Record<string, NpmLockEntry>, depth: number) => {
+    for (const { 0: name, 1: entry } of Object.entries(deps)) {
+      if (entry.version && semver.valid(entry.version)) {
+        packages.push({
+          dev: !!entry.dev,
+          direct: depth === 0,
+          name,
+          version: entry.version,
+        })
+      }
+      if (entry.dependencies) {
+        walk(entry.dependencies as Record<string, NpmLockEntry>, depth + 1)
+      }
+    }
+  }
+  walk(lockfile.dependencies ?? {}, 0)
+  return packages
+}
+
+/**
+ * The installed packages of the lockfile, read from disk so a fresh install
+ * shows up.
+ */
+export function readLockedPackages(pkgEnvDetails: EnvDetails): LockedPackage[] {
+  const { agent, lockPath, pkgPath } = pkgEnvDetails
+  let lockSrc: string
+  try {
+    lockSrc = readFileSync(lockPath, 'utf8')
+  } catch {
+    return []
+  }
+  if (agent === NPM) {
+    return parseNpmLockPackages(lockSrc)
+  }
+  // The root manifest declares the direct dependencies of single packages.
+  const declared = new Set(
+    getDependencyEntries(pkgEnvDetails).flatMap(({ 1: deps }) =>
+      Object.keys(deps),
+    ),
+  )
+  if (agent === YARN_CLASSIC) {
+    const entries = new Set(parseYarnLockV1(lockSrc).values())
+    return [...entries].map(entry => ({
+      dev: false,
+      direct: declared.has(entry.name),
+      name: entry.name,
+      version: entry.version,
+    }))
+  }
+  const parsed = parseLockfile(lockPath, pkgPath)
+  return (parsed?.dependencies ?? [])
+    .filter(dep => dep.type === 'npm')
+    .map(dep => {
+      const name = dep.namespace ? `${dep.namespace}/${dep.name}` : dep.name
+      return {
+        dev: !!dep.dev,
+        direct: !!dep.direct || declared.has(name),
+        name,
+        version: dep.version,
+      }
+    })
+}
+
+/**
+ * The transitive packages worth looking up, as purls.
+ */
+export function getTransitivePurls(
+  packages: LockedPackage[],
+  { prod = false }: { prod?: boolean | undefined } = {},
+): string[] {
+  const directNames = new Set(packages.filter(p => p.direct).map(p => p.name))
+  const purls = new Set<string>()
+  for (const pkg of packages) {
+    if (!directNames.has(pkg.name) && !(prod && pkg.dev)) {
+      purls.add(`pkg:npm/${pkg.name.replace(/^@/, '%40')}@${pkg.version}`)
+    }
+  }
+  return [...purls].toSorted()
+}
+
+/**
+ * Pick the safe version of every alerted package: the highest first patched
+ * version of its vulnerability alerts. Packages with vulnerabilities that
+ * have no patched version are unfixable.
+ */
+export function getSafeOverrides(artifacts: SocketArtifact[]): {
+  overrides: SafeOverride[]
+  unfixable: UnfixablePackage[]
+} {
+  const overrides = new Map<string, SafeOverride>()
+  const unfixable = new Map<string, UnfixablePackage>()
+  for (const artifact of artifacts) {
+    const { name: pkgName = '', namespace, version = '' } = artifact
+    if (!pkgName || !semver.valid(version)) {
+      continue
+    }
+    const name = namespace ? `${namespace}/${pkgName}` : pkgName
+    const key = `${name}@${version}`
+    let safeVersion = ''
+    const fixedTypes = new Set<string>()
+    const unfixedTypes = new Set<string>()
+    for (const alert of artifact.alerts ?? []) {
+      const patched = alert.props?.['firstPatchedVersionIdentifier']
+      if (
+        typeof patched === 'string' &&
+        semver.valid(patched) &&
+        semver.gt(patched, version)
+      ) {
+        fixedTypes.add(alert.type)
+        if (!safeVersion || semver.gt(patched, safeVersion)) {
+          safeVersion = patched
+        }
+      } else if (alert.props?.['vulnerableVersionRange']) {
+        unfixedTypes.add(alert.type)
+      }
+    }
+    if (safeVersion) {
+      overrides.set(key, {
+        alertTypes: [...fixedTypes].toSorted(),
+        name,
+        safeVersion,
+        version,
+      })
+    } else if (unfixedTypes.size) {
+      unfixable.set(key, {
+        alertTypes: [...unfixedTypes].toSorted(),
+        name,
+        version,
+      })
+    }
+  }
+  const byKey = <T extends { name: string; version: string }>(a: T, b: T) =>
+    a.name.localeCompare(b.name) || semver.compare(a.version, b.version)
+  return {
+    overrides: [...overrides.values()].toSorted(byKey),
+    unfixable: [...unfixable.values()].toSorted(byKey),
+  }
+}
+
+/**
+ * The override key of the agent, see the module comment.
+ */
+export function getSafeOverrideKey(
+  agent: EnvDetails['agent'],
+  override: Pick<SafeOverride, 'name' | 'version'>,
+): string {
+  return agent === NPM || agent === PNPM
+    ? `${override.name}@${override.version}`
+    : override.name
+}
+
+/**
+ * Write the overrides into the manifest of the agent, or with `dryRun` only
+ * compute them. Resolves to the changed files, empty when every override was
+ * already there.
+ */
+export async function addSafeOverrides(
+  pkgEnvDetails: EnvDetails,
+  safeOverrides: SafeOverride[],
+  {
+    dryRun = false,
+    pin = false,
+  }: { dryRun?: boolean | undefined; pin?: boolean | undefined } = {},
+): Promise<UnifiedDiffFile[]> {
+  const { agent, editablePkgJson } = pkgEnvDetails
+  const { overrides, type } = getOverridesData(pkgEnvDetails)
+  const nextOverrides: Record<string, unknown> = { ...overrides }
+  const specs = new Map<string, string>()
+  for (const override of safeOverrides) {
+    const key = getSafeOverrideKey(agent, override)
+    const oldSafeVersion = specs.get(key)?.replace(/^\^/, '')
+    // Resolutions by name take the highest safe version of the package.
+    if (!oldSafeVersion || semver.gt(override.safeVersion, oldSafeVersion)) {
+      specs.set(key, pin ? override.safeVersion : `^${override.safeVersion}`)
+    }
+  }
+  let changedCount = 0
+  for (const { 0: key, 1: spec } of specs) {
+    if (nextOverrides[key] !== spec) {
+      nextOverrides[key] = spec
+      changedCount += 1
+    }
+  }
+  if (!changedCount) {
+    return []
+  }
+  const yamlFile = await updateManifest(
+    type,
+    pkgEnvDetails,
+    toSortedObject(nextOverrides) as typeof overrides,
+    { dryRun },
+  )
+  const files = [getPkgJsonChange(pkgEnvDetails)]
+  if (yamlFile) {
+    files.push(yamlFile)
+  }
+  if (!dryRun) {
+    await editablePkgJson.save()
+  }
+  return files
+}
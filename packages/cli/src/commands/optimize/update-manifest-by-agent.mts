@@ -1,3 +1,5 @@
+import path from 'node:path'
+
 import {
   BUN,
   OVERRIDES,
@@ -7,18 +9,26 @@ import {
   YARN_BERRY,
   YARN_CLASSIC,
 } from '@socketsecurity/lib-stable/constants/agents'
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
 import {
   hasKeys,
   isObject,
 } from '@socketsecurity/lib-stable/objects/predicates'
 
 import { updatePnpmWorkspaceYamlOverrides } from './update-pnpm-workspace-yaml.mts'
+import { PACKAGE_JSON } from '../../constants/packages.mts'
 
 import type { Overrides } from './types.mts'
 import type { EnvDetails } from '../../util/ecosystem/environment.mjs'
 import type { Agent } from '../../util/ecosystem/environment.mjs'
+import type { UnifiedDiffFile } from '../../util/output/unified-diff.mts'
 import type { EditablePackageJson } from '@socketsecurity/lib-stable/packages/types'
 
+export type UpdateManifestOptions = {
+  // Leave pnpm-workspace.yaml unwritten.
+  dryRun?: boolean | undefined
+}
+
 const depFields = [
   'dependencies',
   'devDependencies',
@@ -52,6 +62,24 @@ export function getLowestEntryIndex(
   return getEntryIndexes(entries, keys)?.[0] ?? -1
 }
 
+/**
+ * package.json on disk and as it would be saved with the in-memory changes of
+ * `editablePkgJson`, keeping the indentation of the file.
+ */
+export function getPkgJsonChange(
+  pkgEnvDetails: Pick<EnvDetails, 'editablePkgJson' | 'pkgPath'>,
+): UnifiedDiffFile {
+  const pkgJsonPath = path.join(pkgEnvDetails.pkgPath, PACKAGE_JSON)
+  const raw = safeReadFileSync(pkgJsonPath)
+  const before = Buffer.isBuffer(raw) ? raw.toString('utf8') : raw
+  const indent = (before && /^([ \t]+)"/m.exec(before)?.[1]) || 2
+  return {
+    after: `${JSON.stringify(pkgEnvDetails.editablePkgJson.content, null, indent)}\n`,
+    before,
+    path: pkgJsonPath,
+  }
+}
+
 /**
  * Apply overrides to the host repo's manifest, picking the correct destination
  * based on agent + version:
@@ -65,40 +93,48 @@ export function getLowestEntryIndex(
  * The `pkgEnvDetails` parameter carries `agentVersion` (a SemVer instance)
  * needed to disambiguate pnpm versions. Callers reach this via
  * `applyOptimization()` which already has the env in scope.
+ *
+ * package.json is only updated in memory, callers save it. Resolves to the
+ * pnpm-workspace.yaml change when the overrides went there.
  */
 export async function updateManifest(
   agent: Agent,
   pkgEnvDetails: EnvDetails,
   overrides: Overrides,
-): Promise<void> {
+  options?: UpdateManifestOptions | undefined,
+): Promise<UnifiedDiffFile | undefined> {
   const { editablePkgJson } = pkgEnvDetails
   switch (agent) {
     case BUN:
       updateResolutionsField(editablePkgJson, overrides)
-      return
+      return undefined
     case PNPM:
       if (usesPnpmWorkspaceOverrides(pkgEnvDetails)) {
         // Route to pnpm-workspace.yaml. Also clear any stale
         // `pnpm.overrides` in package.json — pnpm 11 ignores it, but
         // leaving it there is misleading + drift-prone.
         updatePnpmField(editablePkgJson, {})
-        await updatePnpmWorkspaceYamlOverrides(pkgEnvDetails.pkgPath, overrides)
+        return await updatePnpmWorkspaceYamlOverrides(
+          pkgEnvDetails.pkgPath,
+          overrides,
+          options,
+        )
       } else {
         updatePnpmField(editablePkgJson, overrides)
       }
-      return
+      return undefined
     case VLT:
       updateOverridesField(editablePkgJson, overrides)
-      return
+      return undefined
     case YARN_BERRY:
       updateResolutionsField(editablePkgJson, overrides)
-      return
+      return undefined
     case YARN_CLASSIC:
       updateResolutionsField(editablePkgJson, overrides)
-      return
+      return undefined
     default:
       updateOverridesField(editablePkgJson, overrides)
-      return
+      return undefined
   }
 }
 
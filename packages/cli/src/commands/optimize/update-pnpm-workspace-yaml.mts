@@ -17,6 +17,7 @@ import { isMap, parseDocument } from 'yaml'
 import type { Document } from 'yaml'
 
 import type { Overrides } from './types.mts'
+import type { UnifiedDiffFile } from '../../util/output/unified-diff.mts'
 
 /**
  * Merge `overrides` into `pnpm-workspace.yaml` at
@@ -28,11 +29,15 @@ import type { Overrides } from './types.mts'
  * - When the file is missing entirely, a minimal one is created.
  * - Comments and other keys (catalog, packages, minimumReleaseAge, etc.) are
  *   preserved.
+ *
+ * Resolves to the file before and after the merge. With `dryRun` it is not
+ * written.
  */
 export async function updatePnpmWorkspaceYamlOverrides(
   repoRoot: string,
   overrides: Overrides,
-): Promise<void> {
+  { dryRun = false }: { dryRun?: boolean | undefined } = {},
+): Promise<UnifiedDiffFile> {
   const yamlPath = path.join(repoRoot, 'pnpm-workspace.yaml')
   const existing = existsSync(yamlPath)
     ? await safeReadFile(yamlPath, { encoding: 'utf8' })
@@ -67,5 +72,8 @@ export async function updatePnpmWorkspaceYamlOverrides(
     minContentWidth: 0,
   })
 
-  writeFileSync(yamlPath, output, 'utf8')
+  if (!dryRun) {
+    writeFileSync(yamlPath, output, 'utf8')
+  }
+  return { after: output, before: existing, path: yamlPath }
 }
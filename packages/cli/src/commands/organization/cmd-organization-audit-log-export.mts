@@ -0,0 +1,187 @@
+import {
+  AUDIT_LOG_EXPORT_FORMATS,
+  parseAuditLogSince,
+} from '../audit-log/audit-log-export.mts'
+import { handleAuditLogExport } from '../audit-log/handle-audit-log-export.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { AuditLogExportFormat } from '../audit-log/audit-log-export.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'export'
+
+const description =
+  'Export the audit log of an organization as JSON Lines or CSV'
+
+const hidden = false
+
+export const cmdOrganizationAuditLogExport: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      format: {
+        type: 'string',
+        default: 'jsonl',
+        description: `Format of the export, one of ${AUDIT_LOG_EXPORT_FORMATS.join(', ')}`,
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      output: {
+        type: 'string',
+        default: '',
+        description: 'Write the export to this file instead of stdout',
+        shortFlag: 'o',
+      },
+      since: {
+        type: 'string',
+        default: '',
+        description:
+          'Only export events from this date or time on, like 2025-04-01 or 7d',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] [FILTER]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Pages through the audit events of the organization, such as scan
+    creations, policy changes and alert triage, and writes all of them for
+    ingestion by a SIEM. Events are written oldest first with every field
+    the API returns: one JSON object per line with --format jsonl, or one
+    row per event with the payload as JSON with --format csv.
+
+    --since takes a date or ISO date-time, or a time back from now in days,
+    hours or minutes (7d, 12h, 30m). Without it the whole log is exported.
+
+    The FILTER arg is an event type, as listed by \`socket audit-log --help\`.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} --since 7d --output audit.jsonl
+      $ ${command} updateAlertTriage --since 2025-04-01 --format csv
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, interactive, org: orgFlag } = cli.flags
+
+  const format = String(cli.flags['format'])
+
+  const out = String(cli.flags['output'] || '')
+
+  const sinceFlag = String(cli.flags['since'] || '')
+
+  const since = sinceFlag ? parseAuditLogSince(sinceFlag) : undefined
+
+  const [typeFilter = ''] = cli.input
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(orgFlag, interactive, dryRun)
+
+  const wasValidInput = checkCommandInput(
+    'text',
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: (AUDIT_LOG_EXPORT_FORMATS as readonly string[]).includes(format),
+      message: `The --format flag must be one of ${AUDIT_LOG_EXPORT_FORMATS.join(', ')}`,
+      fail: `got ${format}`,
+    },
+    {
+      nook: true,
+      test: !sinceFlag || since !== undefined,
+      message: 'The --since flag must be a date, date-time or like 7d',
+      fail: `got ${sinceFlag}`,
+    },
+    {
+      nook: true,
+      test: /^[a-zA-Z]*$/.test(typeFilter),
+      message: 'The filter must be an a-zA-Z string, it is an enum',
+      fail: 'it was given but not a-zA-Z',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('audit log export', {
+      organization: orgSlug,
+      filter: typeFilter || 'any',
+      format,
+      output: out || 'stdout',
+      since: since === undefined ? 'all' : new Date(since).toISOString(),
+    })
+    return
+  }
+
+  await handleAuditLogExport({
+    cwd: process.cwd(),
+    format: format as AuditLogExportFormat,
+    logType: typeFilter
+      ? typeFilter.charAt(0).toUpperCase() + typeFilter.slice(1)
+      : '',
+    orgSlug,
+    out,
+    since,
+  })
+}
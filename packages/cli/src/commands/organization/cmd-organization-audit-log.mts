@@ -0,0 +1,10 @@
+import { cmdOrganizationAuditLogExport } from './cmd-organization-audit-log-export.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdOrganizationAuditLog = defineSubcommandGroup({
+  name: 'audit-log',
+  description: 'Export the audit log of an organization',
+  subcommands: {
+    export: cmdOrganizationAuditLogExport,
+  },
+})
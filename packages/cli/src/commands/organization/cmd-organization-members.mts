@@ -0,0 +1,14 @@
+import { cmdOrganizationMembersInvite } from './cmd-organization-members-invite.mts'
+import { cmdOrganizationMembersList } from './cmd-organization-members-list.mts'
+import { cmdOrganizationMembersRemove } from './cmd-organization-members-remove.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdOrganizationMembers = defineSubcommandGroup({
+  name: 'members',
+  description: 'Manage who has access to an organization',
+  subcommands: {
+    invite: cmdOrganizationMembersInvite,
+    list: cmdOrganizationMembersList,
+    remove: cmdOrganizationMembersRemove,
+  },
+})
@@ -0,0 +1,184 @@
+import {
+  getInvalidTokenScopes,
+  handleCreateOrgToken,
+} from './handle-org-tokens.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { outputDryRunUpload } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { cmdFlagValueToArray } from '../../util/process/cmd.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'create'
+
+const description = 'Create an API token with the given scopes'
+
+const hidden = false
+
+export const cmdOrganizationTokensCreate: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      name: {
+        type: 'string',
+        default: '',
+        description: 'Name of the token, to tell it apart in the token list',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      scopes: {
+        type: 'string',
+        isMultiple: true,
+        description:
+          'API permission the token is granted, like full-scans:create. Accepts multiple flags or a comma separated list',
+      },
+      ttl: {
+        type: 'number',
+        default: 0,
+        description:
+          'Lifetime of the token in days, the token does not expire when 0',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    The new token can only do what its scopes allow. The permissions each
+    command needs are listed in its API Token Requirements. The token is
+    only shown once; in text mode it is the only thing printed to stdout,
+    so it can be captured straight into a secret store.
+
+    To rotate a token, create its replacement with the same scopes, update
+    the secret and revoke the old token with
+    \`socket organization tokens revoke\`.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} --name ci --scopes full-scans:create --ttl 90
+      $ ${command} --scopes repo:list,repo:update --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, interactive, json, markdown, org: orgFlag } = cli.flags
+
+  const name = String(cli.flags['name'])
+
+  const scopes = cmdFlagValueToArray(cli.flags['scopes'])
+
+  const invalidScopes = getInvalidTokenScopes(scopes)
+
+  const ttl = Number(cli.flags['ttl'])
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(orgFlag, interactive, dryRun)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!scopes.length,
+      message: 'At least one scope by --scopes',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !invalidScopes.length,
+      message: 'Scopes must be API permissions like full-scans:create',
+      fail: `got ${invalidScopes.join(', ')}`,
+    },
+    {
+      nook: true,
+      test: Number.isInteger(ttl) && ttl >= 0,
+      message: 'The --ttl flag must be a whole number of days',
+      fail: `got ${ttl}`,
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunUpload('API token', {
+      organization: orgSlug,
+      name,
+      scopes: scopes.join(', '),
+      ttlDays: ttl || 'never expires',
+    })
+    return
+  }
+
+  await handleCreateOrgToken(
+    { name, orgSlug, scopes, ttlDays: ttl },
+    outputKind,
+  )
+}
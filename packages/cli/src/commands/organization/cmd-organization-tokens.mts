@@ -0,0 +1,14 @@
+import { cmdOrganizationTokensCreate } from './cmd-organization-tokens-create.mts'
+import { cmdOrganizationTokensList } from './cmd-organization-tokens-list.mts'
+import { cmdOrganizationTokensRevoke } from './cmd-organization-tokens-revoke.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdOrganizationTokens = defineSubcommandGroup({
+  name: 'tokens',
+  description: 'Manage the API tokens of an organization',
+  subcommands: {
+    create: cmdOrganizationTokensCreate,
+    list: cmdOrganizationTokensList,
+    revoke: cmdOrganizationTokensRevoke,
+  },
+})
@@ -1,9 +1,12 @@
+import { cmdOrganizationAuditLog } from './cmd-organization-audit-log.mts'
 import { cmdOrganizationDependencies } from './cmd-organization-dependencies.mts'
 import { cmdOrganizationList } from './cmd-organization-list.mts'
+import { cmdOrganizationMembers } from './cmd-organization-members.mts'
 import { cmdOrganizationPolicyLicense } from './cmd-organization-policy-license.mts'
 import { cmdOrganizationPolicySecurity } from './cmd-organization-policy-security.mts'
 import { cmdOrganizationPolicy } from './cmd-organization-policy.mts'
 import { cmdOrganizationQuota } from './cmd-organization-quota.mts'
+import { cmdOrganizationTokens } from './cmd-organization-tokens.mts'
 import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
 
 export const cmdOrganization = defineSubcommandGroup({
@@ -15,6 +18,9 @@ export const cmdOrganization = defineSubcommandGroup({
     list: cmdOrganizationList,
     quota: cmdOrganizationQuota,
     policy: cmdOrganizationPolicy,
+    members: cmdOrganizationMembers,
+    tokens: cmdOrganizationTokens,
+    'audit-log': cmdOrganizationAuditLog,
   },
   aliases: {
     deps: {
@@ -0,0 +1,74 @@
+import { queryApiSafeJson, sendApiRequest } from '../../util/socket/api.mjs'
+
+import type { CResult } from '../../types.mts'
+
+export const ORG_MEMBER_ROLES = ['admin', 'member']
+
+export type OrgMember = {
+  id: string
+  email: string
+  name?: string | null | undefined
+  role: string
+  // Invited members have not accepted the invitation yet.
+  status?: 'active' | 'invited' | undefined
+}
+
+type OrgMemberPage = {
+  results: OrgMember[]
+  nextPage?: number | null | undefined
+}
+
+const ORG_MEMBERS_PER_PAGE = 100
+
+// Organization members are not wrapped by the SDK yet, so these call the
+// endpoints directly.
+
+export async function fetchOrgMembers(
+  orgSlug: string,
+): Promise<CResult<OrgMember[]>> {
+  const members: OrgMember[] = []
+  let page: number | null | undefined = 1
+  while (page) {
+    const pageCResult: CResult<OrgMemberPage> =
+      await queryApiSafeJson<OrgMemberPage>(
+        `orgs/${encodeURIComponent(orgSlug)}/members?per_page=${ORG_MEMBERS_PER_PAGE}&page=${page}`,
+        'organization members',
+      )
+    if (!pageCResult.ok) {
+      return pageCResult
+    }
+    members.push(...(pageCResult.data.results ?? []))
+    page = pageCResult.data.nextPage
+  }
+  return { ok: true, data: members }
+}
+
+export async function fetchInviteOrgMember(
+  orgSlug: string,
+  email: string,
+  role: string,
+): Promise<CResult<OrgMember>> {
+  return await sendApiRequest<OrgMember>(
+    `orgs/${encodeURIComponent(orgSlug)}/members/invite`,
+    {
+      method: 'POST',
+      body: { email, role },
+      commandPath: 'socket organization members invite',
+      description: `an invitation for ${email}`,
+    },
+  )
+}
+
+export async function fetchRemoveOrgMember(
+  orgSlug: string,
+  memberId: string,
+): Promise<CResult<unknown>> {
+  return await sendApiRequest(
+    `orgs/${encodeURIComponent(orgSlug)}/members/${encodeURIComponent(memberId)}/remove`,
+    {
+      method: 'POST',
+      commandPath: 'socket organization members remove',
+      description: 'the member removal',
+    },
+  )
+}
@@ -0,0 +1,84 @@
+import { queryApiSafeJson, sendApiRequest } from '../../util/socket/api.mjs'
+
+import type { CResult } from '../../types.mts'
+
+export type OrgApiToken = {
+  id: string
+  name?: string | null | undefined
+  scopes: string[]
+  created_at: string
+  // Unset for tokens that never expire.
+  expires_at?: string | null | undefined
+  last_used_at?: string | null | undefined
+  // The first characters of the token, the rest is never returned again.
+  token_prefix?: string | undefined
+}
+
+export type CreatedOrgApiToken = OrgApiToken & {
+  token: string
+}
+
+export type CreateOrgApiTokenRequest = {
+  name: string
+  scopes: string[]
+  ttl_days?: number | undefined
+}
+
+type OrgApiTokenPage = {
+  results: OrgApiToken[]
+  nextPage?: number | null | undefined
+}
+
+const ORG_API_TOKENS_PER_PAGE = 100
+
+// API token administration is not wrapped by the SDK yet, so these call the
+// endpoints directly.
+
+export async function fetchOrgApiTokens(
+  orgSlug: string,
+): Promise<CResult<OrgApiToken[]>> {
+  const tokens: OrgApiToken[] = []
+  let page: number | null | undefined = 1
+  while (page) {
+    const pageCResult: CResult<OrgApiTokenPage> =
+      await queryApiSafeJson<OrgApiTokenPage>(
+        `orgs/${encodeURIComponent(orgSlug)}/api-tokens?per_page=${ORG_API_TOKENS_PER_PAGE}&page=${page}`,
+        'organization API tokens',
+      )
+    if (!pageCResult.ok) {
+      return pageCResult
+    }
+    tokens.push(...(pageCResult.data.results ?? []))
+    page = pageCResult.data.nextPage
+  }
+  return { ok: true, data: tokens }
+}
+
+export async function fetchCreateOrgApiToken(
+  orgSlug: string,
+  request: CreateOrgApiTokenRequest,
+): Promise<CResult<CreatedOrgApiToken>> {
+  return await sendApiRequest<CreatedOrgApiToken>(
+    `orgs/${encodeURIComponent(orgSlug)}/api-tokens`,
+    {
+      method: 'POST',
+      body: request,
+      commandPath: 'socket organization tokens create',
+      description: 'a new API token',
+    },
+  )
+}
+
+export async function fetchRevokeOrgApiToken(
+  orgSlug: string,
+  tokenId: string,
+): Promise<CResult<unknown>> {
+  return await sendApiRequest(
+    `orgs/${encodeURIComponent(orgSlug)}/api-tokens/${encodeURIComponent(tokenId)}/revoke`,
+    {
+      method: 'POST',
+      commandPath: 'socket organization tokens revoke',
+      description: 'the token revocation',
+    },
+  )
+}
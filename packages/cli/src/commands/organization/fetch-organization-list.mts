@@ -1,3 +1,5 @@
+import { recordCompletionValues } from '../../util/cli/completion-history.mts'
+import { getOrgSlugs } from '../../util/organization.mts'
 import { handleApiCall } from '../../util/socket/api.mjs'
 import { setupSdk } from '../../util/socket/sdk.mjs'
 
@@ -63,10 +65,13 @@ export async function fetchOrganization(
     return orgsCResult
   }
 
+  const organizations = Object.values(orgsCResult.data.organizations)
+  await recordCompletionValues('org', getOrgSlugs(organizations))
+
   return {
     ...orgsCResult,
     data: {
-      organizations: Object.values(orgsCResult.data.organizations),
+      organizations,
     },
   }
 }
@@ -0,0 +1,94 @@
+import {
+  fetchInviteOrgMember,
+  fetchOrgMembers,
+  fetchRemoveOrgMember,
+} from './fetch-org-members.mts'
+import {
+  outputInviteOrgMember,
+  outputOrgMembers,
+  outputRemoveOrgMember,
+} from './output-org-members.mts'
+
+import type { OrgMember } from './fetch-org-members.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+/**
+ * Find a member by ID or, case insensitively, by email.
+ */
+export function findOrgMember(
+  members: OrgMember[],
+  member: string,
+): CResult<OrgMember> {
+  const email = member.toLowerCase()
+  const found = members.find(
+    m => m.id === member || m.email.toLowerCase() === email,
+  )
+  if (!found) {
+    return {
+      ok: false,
+      message: 'Member not found',
+      cause: `No member of the organization has the ID or email ${member}`,
+    }
+  }
+  return { ok: true, data: found }
+}
+
+export async function handleOrgMembersList(
+  orgSlug: string,
+  outputKind: OutputKind,
+): Promise<void> {
+  const result = await fetchOrgMembers(orgSlug)
+  await outputOrgMembers(result, outputKind)
+}
+
+export async function handleInviteOrgMember(
+  {
+    email,
+    orgSlug,
+    role,
+  }: {
+    email: string
+    orgSlug: string
+    role: string
+  },
+  outputKind: OutputKind,
+): Promise<void> {
+  const result = await fetchInviteOrgMember(orgSlug, email, role)
+  await outputInviteOrgMember(result, outputKind)
+}
+
+export async function removeOrgMember(
+  orgSlug: string,
+  member: string,
+): Promise<CResult<OrgMember>> {
+  const membersCResult = await fetchOrgMembers(orgSlug)
+  if (!membersCResult.ok) {
+    return membersCResult
+  }
+  const foundCResult = findOrgMember(membersCResult.data, member)
+  if (!foundCResult.ok) {
+    return foundCResult
+  }
+  const removeCResult = await fetchRemoveOrgMember(
+    orgSlug,
+    foundCResult.data.id,
+  )
+  if (!removeCResult.ok) {
+    return removeCResult
+  }
+  return foundCResult
+}
+
+export async function handleRemoveOrgMember(
+  {
+    member,
+    orgSlug,
+  }: {
+    member: string
+    orgSlug: string
+  },
+  outputKind: OutputKind,
+): Promise<void> {
+  const result = await removeOrgMember(orgSlug, member)
+  await outputRemoveOrgMember(result, outputKind)
+}
@@ -0,0 +1,76 @@
+import {
+  fetchCreateOrgApiToken,
+  fetchOrgApiTokens,
+  fetchRevokeOrgApiToken,
+} from './fetch-org-tokens.mts'
+import {
+  outputCreateOrgToken,
+  outputOrgTokens,
+  outputRevokeOrgToken,
+} from './output-org-tokens.mts'
+
+import type { CreateOrgApiTokenRequest } from './fetch-org-tokens.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+// Scopes name an API permission, like `full-scans:create`.
+const TOKEN_SCOPE_REGEXP = /^[a-z][a-z-]*:[a-z][a-z-]*$/
+
+export function getInvalidTokenScopes(scopes: string[]): string[] {
+  return scopes.filter(scope => !TOKEN_SCOPE_REGEXP.test(scope))
+}
+
+export async function handleOrgTokensList(
+  orgSlug: string,
+  outputKind: OutputKind,
+): Promise<void> {
+  const result = await fetchOrgApiTokens(orgSlug)
+  await outputOrgTokens(result, outputKind)
+}
+
+export async function handleCreateOrgToken(
+  {
+    name,
+    orgSlug,
+    scopes,
+    ttlDays,
+  }: {
+    name: string
+    orgSlug: string
+    scopes: string[]
+    ttlDays: number
+  },
+  outputKind: OutputKind,
+): Promise<void> {
+  const request: CreateOrgApiTokenRequest = { name, scopes }
+  // Without a lifetime the token does not expire.
+  if (ttlDays) {
+    request.ttl_days = ttlDays
+  }
+  const result = await fetchCreateOrgApiToken(orgSlug, request)
+  await outputCreateOrgToken(result, outputKind)
+}
+
+export async function revokeOrgToken(
+  orgSlug: string,
+  tokenId: string,
+): Promise<CResult<{ id: string }>> {
+  const revokeCResult = await fetchRevokeOrgApiToken(orgSlug, tokenId)
+  if (!revokeCResult.ok) {
+    return revokeCResult
+  }
+  return { ok: true, data: { id: tokenId } }
+}
+
+export async function handleRevokeOrgToken(
+  {
+    orgSlug,
+    tokenId,
+  }: {
+    orgSlug: string
+    tokenId: string
+  },
+  outputKind: OutputKind,
+): Promise<void> {
+  const result = await revokeOrgToken(orgSlug, tokenId)
+  await outputRevokeOrgToken(result, outputKind)
+}
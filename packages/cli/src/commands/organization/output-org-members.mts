@@ -0,0 +1,93 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { OrgMember } from './fetch-org-members.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function getMemberRows(members: OrgMember[]): Array<Record<string, string>> {
+  return members.map(m => ({
+    email: m.email,
+    id: m.id,
+    name: m.name || '',
+    role: m.role,
+    status: m.status ?? 'active',
+  }))
+}
+
+export async function outputOrgMembers(
+  result: CResult<OrgMember[]>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const members = result.data
+  if (!members.length) {
+    logger.info('The organization has no members')
+    return
+  }
+  const table = mdTable(
+    getMemberRows(members),
+    ['email', 'name', 'role', 'status', 'id'],
+    ['Email', 'Name', 'Role', 'Status', 'ID'],
+  )
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Organization members'))
+    logger.log('')
+  }
+  logger.log(table)
+}
+
+export async function outputInviteOrgMember(
+  result: CResult<OrgMember>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+  logger.success(`Invited ${result.data.email} as ${result.data.role}`)
+}
+
+export async function outputRemoveOrgMember(
+  result: CResult<OrgMember>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+  logger.success(`Removed ${result.data.email} from the organization`)
+}
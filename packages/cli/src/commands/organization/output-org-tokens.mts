@@ -0,0 +1,116 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CreatedOrgApiToken, OrgApiToken } from './fetch-org-tokens.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function getTokenRows(tokens: OrgApiToken[]): Array<Record<string, string>> {
+  return tokens.map(t => ({
+    created: t.created_at,
+    expires: t.expires_at || 'never',
+    id: t.id,
+    lastUsed: t.last_used_at || 'never',
+    name: t.name || '',
+    prefix: t.token_prefix ? `${t.token_prefix}…` : '',
+    scopes: t.scopes.join(', '),
+  }))
+}
+
+export async function outputOrgTokens(
+  result: CResult<OrgApiToken[]>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const tokens = result.data
+  if (!tokens.length) {
+    logger.info('The organization has no API tokens')
+    return
+  }
+  const table = mdTable(
+    getTokenRows(tokens),
+    ['name', 'prefix', 'scopes', 'created', 'expires', 'lastUsed', 'id'],
+    ['Name', 'Token', 'Scopes', 'Created', 'Expires', 'Last used', 'ID'],
+  )
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Organization API tokens'))
+    logger.log('')
+  }
+  logger.log(table)
+}
+
+export async function outputCreateOrgToken(
+  result: CResult<CreatedOrgApiToken>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { expires_at, id, name, scopes, token } = result.data
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('API token'))
+    logger.log('')
+    logger.log(`- Name: ${name || ''}`)
+    logger.log(`- ID: ${id}`)
+    logger.log(`- Scopes: ${scopes.join(', ')}`)
+    logger.log(`- Expires: ${expires_at || 'never'}`)
+    logger.log('')
+    logger.log('```')
+    logger.log(token)
+    logger.log('```')
+    return
+  }
+  // The token is only shown once. Only the token goes to stdout, so it can be
+  // captured into a variable or secret store.
+  logger.error(
+    `Created API token ${id}, store it now as it will not be shown again`,
+  )
+  logger.log(token)
+}
+
+export async function outputRevokeOrgToken(
+  result: CResult<{ id: string }>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  logger.success(`Revoked API token ${result.data.id}`)
+}
@@ -0,0 +1,228 @@
+/**
+ * Bulk mode of `socket package score --input`: the purl list it reads, and
+ * the one row per requested package it reports, as JSON, CSV, Markdown or a
+ * text table.
+ *
+ * Bulk lookups go through the batched shallow score endpoint, so the scores
+ * are those of each package itself, not of its dependencies.
+ */
+
+import { formatCsv } from '../../util/output/csv.mts'
+
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export const BULK_SCORE_ALERT_SEVERITIES = [
+  'critical',
+  'high',
+  'middle',
+  'low',
+] as const
+
+export type BulkScoreSeverity = (typeof BULK_SCORE_ALERT_SEVERITIES)[number]
+
+export interface BulkScoreRow {
+  alerts: Record<BulkScoreSeverity, number>
+  alertTypes: string[]
+  found: boolean
+  purl: string
+  score:
+    | {
+        license: number
+        maintenance: number
+        quality: number
+        supplyChain: number
+        vulnerability: number
+      }
+    | undefined
+}
+
+export interface BulkScoreData {
+  missing: string[]
+  packages: BulkScoreRow[]
+}
+
+export interface PurlListLine {
+  line: number
+  text: string
+}
+
+export const BULK_SCORE_CSV_COLUMNS = [
+  'purl',
+  'found',
+  'supplyChain',
+  'maintenance',
+  'quality',
+  'vulnerability',
+  'license',
+  ...BULK_SCORE_ALERT_SEVERITIES,
+  'alertTypes',
+] as const
+
+/**
+ * Read one purl per line. Blank lines and `#` comments are skipped, the
+ * `pkg:` prefix is optional, and repeated purls are looked up once.
+ */
+export function parsePurlList(text: string): {
+  invalid: PurlListLine[]
+  purls: string[]
+} {
+  const invalid: PurlListLine[] = []
+  const purls = new Set<string>()
+  const lines = text.split(/\r?\n/)
+  for (let i = 0, { length } = lines; i < length; i += 1) {
+    const entry = lines[i]!.replace(/\s+#.*$/, '').trim()
+    if (!entry || entry.startsWith('#')) {
+      continue
+    }
+    if (!/^(?:pkg:)?[a-zA-Z]+\/./.test(entry) || /\s/.test(entry)) {
+      invalid.push({ line: i + 1, text: entry })
+      continue
+    }
+    purls.add(entry.startsWith('pkg:') ? entry : `pkg:${entry}`)
+  }
+  return { invalid, purls: [...purls] }
+}
+
+function getArtifactKeys(artifact: SocketArtifact): string[] {
+  const { name, namespace, type, version } = artifact
+  const ns = namespace ? `${namespace}/` : ''
+  const keys = [
+    `pkg:${type}/${ns}${name}@${version}`,
+    `pkg:${type}/${name}@${version}`,
+    `pkg:${type}/${ns}${name}`,
+    `pkg:${type}/${name}`,
+  ]
+  if (artifact.inputPurl) {
+    keys.unshift(artifact.inputPurl)
+  }
+  return keys
+}
+
+function safeDecode(purl: string): string {
+  try {
+    return decodeURIComponent(purl)
+  } catch {
+    return purl
+  }
+}
+
+/**
+ * Match the artifacts of a bulk lookup back to the purls that were asked for.
+ * Packages that appear more than once, like one PyPI release per platform,
+ * keep their lowest scores and all of their alert types.
+ */
+export function getBulkScoreData(
+  purls: string[],
+  artifacts: SocketArtifact[],
+): BulkScoreData {
+  const byKey = new Map<string, SocketArtifact[]>()
+  for (let i = 0, { length } = artifacts; i < length; i += 1) {
+    const artifact = artifacts[i]!
+    for (const key of new Set(getArtifactKeys(artifact))) {
+      const matches = byKey.get(key)
+      if (matches) {
+        matches.push(artifact)
+      } else {
+        byKey.set(key, [artifact])
+      }
+    }
+  }
+
+  const missing: string[] = []
+  const packages: BulkScoreRow[] = []
+  for (let i = 0, { length } = purls; i < length; i += 1) {
+    const purl = purls[i]!
+    const decoded = safeDecode(purl)
+    const matches =
+      byKey.get(purl) ??
+      byKey.get(decoded) ??
+      byKey.get(decoded.replace(/@latest$/, ''))
+    if (!matches) {
+      missing.push(purl)
+      packages.push({
+        alerts: { critical: 0, high: 0, low: 0, middle: 0 },
+        alertTypes: [],
+        found: false,
+        purl,
+        score: undefined,
+      })
+      continue
+    }
+    const alerts = new Map<string, BulkScoreSeverity | undefined>()
+    let score: NonNullable<BulkScoreRow['score']> | undefined
+    for (let j = 0, { length: count } = matches; j < count; j += 1) {
+      const artifact = matches[j]!
+      const artifactAlerts = artifact.alerts ?? []
+      for (
+        let k = 0, { length: alertCount } = artifactAlerts;
+        k < alertCount;
+        k += 1
+      ) {
+        const alert = artifactAlerts[k]!
+        alerts.set(
+          `${alert.type}:${alert.severity}`,
+          alert.severity as BulkScoreSeverity | undefined,
+        )
+      }
+      const s = artifact.score
+      if (s) {
+        const next = {
+          license: Math.floor((s.license ?? 0) * 100),
+          maintenance: Math.floor((s.maintenance ?? 0) * 100),
+          quality: Math.floor((s.quality ?? 0) * 100),
+          supplyChain: Math.floor((s.supplyChain ?? 0) * 100),
+          vulnerability: Math.floor((s.vulnerability ?? 0) * 100),
+        }
+        score = score
+          ? {
+              license: Math.min(score.license, next.license),
+              maintenance: Math.min(score.maintenance, next.maintenance),
+              quality: Math.min(score.quality, next.quality),
+              supplyChain: Math.min(score.supplyChain, next.supplyChain),
+              vulnerability: Math.min(score.vulnerability, next.vulnerability),
+            }
+          : next
+      }
+    }
+    const counts: Record<BulkScoreSeverity, number> = {
+      critical: 0,
+      high: 0,
+      low: 0,
+      middle: 0,
+    }
+    const types = new Set<string>()
+    for (const { 0: key, 1: severity } of alerts) {
+      if (severity && severity in counts) {
+        counts[severity] += 1
+      }
+      types.add(key.slice(0, key.lastIndexOf(':')))
+    }
+    packages.push({
+      alerts: counts,
+      alertTypes: [...types].toSorted(),
+      found: true,
+      purl,
+      score,
+    })
+  }
+  return { missing, packages }
+}
+
+export function getBulkScoreCells(row: BulkScoreRow): string[] {
+  const { alerts, score } = row
+  return [
+    row.purl,
+    String(row.found),
+    String(score?.supplyChain ?? ''),
+    String(score?.maintenance ?? ''),
+    String(score?.quality ?? ''),
+    String(score?.vulnerability ?? ''),
+    String(score?.license ?? ''),
+    ...BULK_SCORE_ALERT_SEVERITIES.map(severity => String(alerts[severity])),
+    row.alertTypes.join(' '),
+  ]
+}
+
+export function formatBulkScoreCsv(data: BulkScoreData): string {
+  return formatCsv(BULK_SCORE_CSV_COLUMNS, data.packages.map(getBulkScoreCells))
+}
@@ -0,0 +1,152 @@
+import { handlePackageDiff } from './handle-package-diff.mts'
+import { parsePackageDiffSpec } from './package-diff.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'diff'
+
+const description =
+  'Compare two published versions of a package: files, install scripts, capabilities, dependencies and scores'
+
+const hidden = false
+
+export const cmdPackageDiff = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      summary: {
+        type: 'boolean',
+        default: false,
+        description: 'Only print the summary, not the diff of each file',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <NAME@VERSION> <NAME@VERSION>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Downloads the tarballs of two npm package versions, checks them against
+    the integrity the registry lists, and prints a summary of what changed
+    followed by the unified diff of each changed file. The summary lists:
+
+      - files that were added, removed or modified
+      - preinstall, install and postinstall scripts that were added or changed
+      - capabilities the new version's code uses that the old one did not:
+        network access, the filesystem and child processes
+      - dependencies, optional and peer dependencies that changed
+      - the change of each Socket score
+
+    Capabilities are found by the modules the code requires or imports, so
+    code that builds module names at runtime is not caught. Binary files and
+    files over 1 MB are only listed. Without an API token the scores are
+    looked up with the public token; when they cannot be fetched the rest of
+    the comparison is still printed.
+
+    Each version is given as \`name@version\` or as an npm purl, and must be
+    exact. The names may differ, e.g. to compare a fork with its original.
+
+    Examples
+      $ ${command} lodash@4.17.20 lodash@4.17.21
+      $ ${command} @sigstore/cli@0.7.0 @sigstore/cli@0.8.0 --summary
+      $ ${command} pkg:npm/express@4.18.2 pkg:npm/express@4.19.0 --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown, summary } = cli.flags as {
+    json: boolean
+    markdown: boolean
+    summary: boolean
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const { 0: rawBefore = '', 1: rawAfter = '' } = cli.input
+
+  const before = rawBefore ? parsePackageDiffSpec(rawBefore) : undefined
+  const after = rawAfter ? parsePackageDiffSpec(rawAfter) : undefined
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!rawBefore && !!rawAfter,
+      message: 'The two package versions to compare',
+      fail: 'missing',
+    },
+    {
+      test: (!rawBefore || !!before) && (!rawAfter || !!after),
+      message:
+        'Each version must be given as name@version or pkg:npm/name@version',
+      fail: 'invalid',
+    },
+    {
+      nook: true,
+      test: cli.input.length <= 2,
+      message: 'Compare two versions at a time',
+      fail: 'too many arguments',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+  )
+  if (!wasValidInput || !before || !after) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('package diff', {
+      after: `${after.name}@${after.version}`,
+      before: `${before.name}@${before.version}`,
+      summary,
+    })
+    return
+  }
+
+  await handlePackageDiff({
+    after,
+    before,
+    outputKind,
+    summaryOnly: summary,
+  })
+}
@@ -0,0 +1,147 @@
+import { handlePackageFiles } from './handle-package-files.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { getPurlObject, normalizePurl } from '../../util/purl/parse.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'files'
+
+const description =
+  'List the files of a published package and compare them with its source repository'
+
+const hidden = false
+
+export const cmdPackageFiles = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      changed: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Only list the files that are not in the source tree or differ from it',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <PURL>
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Downloads the tarball of an npm package version, checks it against the
+    integrity the registry lists, and lists its files. When the version has
+    a provenance attestation, each file is compared with the git tree of the
+    commit it was built from: \`+\` marks files only in the artifact, \`~\`
+    files that differ from the source. Without provenance the gitHead npm
+    recorded at publish time is used, which is not attested.
+
+    Build output is expected to be only in the artifact, and npm rewrites
+    package.json when publishing. Other files that are not in the repository
+    are worth a closer look: they were added outside the reviewed source.
+
+    Only GitHub repositories are compared; the tree is fetched with the
+    GitHub API, using SOCKET_CLI_GITHUB_TOKEN when set. The version defaults
+    to the latest one. A "purl" is a standard package name formatting:
+    \`pkg:npm/name@version\`, the \`pkg:\` prefix is optional.
+
+    Examples
+      $ ${command} pkg:npm/sigstore@3.0.0
+      $ ${command} npm/%40sigstore/cli --changed
+      $ ${command} pkg:npm/lodash@4.17.21 --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { changed, json, markdown } = cli.flags as {
+    changed: boolean
+    json: boolean
+    markdown: boolean
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const rawPurl = cli.input[0] ?? ''
+
+  const purlObj = rawPurl
+    ? getPurlObject(normalizePurl(rawPurl), { throws: false })
+    : undefined
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!rawPurl,
+      message: 'The package url (purl) to inspect',
+      fail: 'missing',
+    },
+    {
+      test: !rawPurl || !!purlObj,
+      message: 'The package url (purl) must be valid',
+      fail: 'invalid purl',
+    },
+    {
+      test: !purlObj || purlObj.type === 'npm',
+      message: 'Only npm package tarballs can be inspected',
+      fail: `saw ${purlObj?.type}`,
+    },
+    {
+      nook: true,
+      test: cli.input.length <= 1,
+      message: 'Inspect one package at a time',
+      fail: 'too many arguments',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+  )
+  if (!wasValidInput || !purlObj) {
+    return
+  }
+
+  const name = purlObj.namespace
+    ? `${purlObj.namespace}/${purlObj.name}`
+    : purlObj.name
+  const version = purlObj.version || undefined
+
+  if (dryRun) {
+    outputDryRunFetch('package files', {
+      changed,
+      package: `${name}@${version ?? 'latest'}`,
+    })
+    return
+  }
+
+  await handlePackageFiles({ changed, name, outputKind, version })
+}
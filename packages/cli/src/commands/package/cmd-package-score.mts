@@ -1,8 +1,22 @@
+import { existsSync } from 'node:fs'
+import path from 'node:path'
+
 import { handlePurlDeepScore } from './handle-purl-deep-score.mts'
+import { handlePurlsBulkScore } from './handle-purls-bulk-score.mts'
 import { parsePackageSpecifiers } from './parse-package-specifiers.mts'
 import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { SOCKET_CLI_OFFLINE } from '../../env/socket-cli-offline.mts'
 import { defineFlags } from '../../meow.mts'
-import { commonFlags, outputFlags } from '../../flags.mts'
+import {
+  cacheFlags,
+  commonFlags,
+  outputFlags,
+  templateFlags,
+} from '../../flags.mts'
+import {
+  parseCacheDuration,
+  setCacheOverrides,
+} from '../../util/cache/overrides.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
 import {
   getFlagApiRequirementsOutput,
@@ -40,10 +54,30 @@ export async function run(
     flags: defineFlags({
       ...commonFlags,
       ...outputFlags,
+      ...templateFlags,
+      ...cacheFlags,
+      csv: {
+        type: 'boolean',
+        default: false,
+        description: 'Output the scores read with --input as CSV',
+      },
+      input: {
+        type: 'string',
+        default: '',
+        description:
+          'Score the purls in this file, one per line, or - to read them from stdin',
+      },
+      offline: {
+        type: 'boolean',
+        default: SOCKET_CLI_OFFLINE,
+        description:
+          'Serve results from the local score cache without calling the Socket API',
+      },
     }),
     help: (command: string, helpConfig: { flags: MeowFlags }) => `
     Usage
       $ ${command} [options] <<ECOSYSTEM> <NAME> | <PURL>>
+      $ ${command} [options] --input <FILE|->
 
     API Token Requirements
       ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
@@ -56,6 +90,23 @@ export async function run(
 
     When you want to know whether to trust a package, this is the command to run.
 
+    Results are cached on disk for a day. With --offline (or SOCKET_CLI_OFFLINE=1)
+    the score is served from that cache, however old, and the command fails
+    when the package was never looked up on this machine. Use --no-cache to
+    skip the cache for one run, --cache-ttl to change how long entries stay
+    fresh, and \`socket cache\` to inspect or clear it.
+
+    With --input the command scores a whole list of packages instead, like a
+    vendored dependency list without a manifest. The list holds one purl per
+    line; blank lines and # comments are skipped. The packages are looked up
+    in batches and reported one row each, with --json, --csv, --markdown or
+    as a table. In this mode the scores are those of each package itself,
+    like \`socket package shallow\` reports them, not of its dependencies.
+
+    Use --output-template=<file> to render the score with your own Handlebars-
+    style template instead; the context is the \`data\` of the --json output.
+    See \`socket scan report --help\` for the template syntax.
+
     See also the \`socket package shallow\` command, which returns the shallow
     score for any number of packages. That will not reflect the dependency scores.
 
@@ -75,6 +126,9 @@ export async function run(
       $ ${command} npm eslint@1.0.0 --json
       $ ${command} pkg:golang/github.com/steelpoor/tlsproxy@v0.0.0-20250304082521-29051ed19c60
       $ ${command} nuget/needpluscommonlibrary@1.0.0 --markdown
+      $ ${command} npm eslint --output-template=score.hbs
+      $ ${command} --input purls.txt --csv > scores.csv
+      $ cat purls.txt | ${command} --input - --json
   `,
   }
 
@@ -85,7 +139,9 @@ export async function run(
     parentName,
   })
 
-  const { json, markdown } = cli.flags
+  const { cache, cacheTtl, csv, input, json, markdown, offline } = cli.flags
+
+  const outputTemplate = String(cli.flags['outputTemplate'] || '')
 
   const dryRun = cli.flags['dryRun']
 
@@ -95,6 +151,77 @@ export async function run(
 
   const outputKind = getOutputKind(json, markdown)
 
+  const cacheTtlMs = parseCacheDuration(cacheTtl)
+
+  const wasValidCacheInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !cacheTtl || cacheTtlMs !== undefined,
+      message: 'The --cache-ttl flag expects a duration like 30m, 12h or 7d',
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: cache || !offline,
+      message:
+        'The --offline flag reads the cache, so it cannot be combined with --no-cache',
+      fail: 'omit one',
+    },
+  )
+  if (!wasValidCacheInput) {
+    return
+  }
+
+  setCacheOverrides({ disabled: !cache, ttlMs: cacheTtlMs })
+
+  if (input) {
+    const wasValidBulkInput = checkCommandInput(
+      outputKind,
+      {
+        test: !ecosystem,
+        message: 'The --input flag replaces the package arguments',
+        fail: 'omit the package or --input',
+      },
+      {
+        nook: true,
+        test: [json, markdown, csv].filter(Boolean).length <= 1,
+        message: 'Pick only one of the json, markdown and csv flags',
+        fail: 'omit all but one',
+      },
+      {
+        nook: true,
+        test: !outputTemplate,
+        message: 'The --output-template flag cannot be combined with --input',
+        fail: 'omit one',
+      },
+      {
+        nook: true,
+        test: hasApiToken || !!offline,
+        message: 'This command requires a Socket API token for access',
+        fail: 'try `socket login`',
+      },
+    )
+    if (!wasValidBulkInput) {
+      return
+    }
+
+    if (dryRun) {
+      outputDryRunFetch('package scores', {
+        input: input === '-' ? 'stdin' : input,
+      })
+      return
+    }
+
+    await handlePurlsBulkScore({
+      cwd: process.cwd(),
+      input,
+      offline: !!offline,
+      outputKind: csv ? 'csv' : outputKind,
+    })
+    return
+  }
+
   const { purls, valid } = parsePackageSpecifiers(ecosystem, purl ? [purl] : [])
 
   const wasValidInput = checkCommandInput(
@@ -117,7 +244,26 @@ export async function run(
     },
     {
       nook: true,
-      test: hasApiToken,
+      test: !csv,
+      message: 'The --csv flag needs --input',
+      fail: 'omit --csv',
+    },
+    {
+      nook: true,
+      test: !outputTemplate || (!json && !markdown),
+      message:
+        'The --output-template flag cannot be combined with --json or --markdown',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !outputTemplate || existsSync(outputTemplate),
+      message: 'The --output-template file must exist',
+      fail: 'not found',
+    },
+    {
+      nook: true,
+      test: hasApiToken || !!offline,
       message: 'This command requires a Socket API token for access',
       fail: 'try `socket login`',
     },
@@ -133,5 +279,10 @@ export async function run(
     return
   }
 
-  await handlePurlDeepScore(purls[0] || '', outputKind)
+  await handlePurlDeepScore(
+    purls[0] || '',
+    outputKind,
+    !!offline,
+    outputTemplate ? path.resolve(process.cwd(), outputTemplate) : '',
+  )
 }
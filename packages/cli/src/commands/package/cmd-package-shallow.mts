@@ -1,8 +1,13 @@
 import { handlePurlsShallowScore } from './handle-purls-shallow-score.mts'
 import { parsePackageSpecifiers } from './parse-package-specifiers.mts'
 import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { SOCKET_CLI_OFFLINE } from '../../env/socket-cli-offline.mts'
 import { defineFlags } from '../../meow.mts'
-import { commonFlags, outputFlags } from '../../flags.mts'
+import { cacheFlags, commonFlags, outputFlags } from '../../flags.mts'
+import {
+  parseCacheDuration,
+  setCacheOverrides,
+} from '../../util/cache/overrides.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
 import {
   getFlagApiRequirementsOutput,
@@ -46,6 +51,13 @@ export async function run(
     flags: defineFlags({
       ...commonFlags,
       ...outputFlags,
+      ...cacheFlags,
+      offline: {
+        type: 'boolean',
+        default: SOCKET_CLI_OFFLINE,
+        description:
+          'Serve results from the local score cache without calling the Socket API',
+      },
     }),
     help: (command: string, helpConfig: { flags: MeowFlags }) => `
     Usage
@@ -69,6 +81,12 @@ export async function run(
     If the first arg is an ecosystem, remaining args that are not a purl are
     assumed to be scoped to that ecosystem. The \`pkg:\` prefix is optional.
 
+    Results are cached on disk for a day. With --offline (or SOCKET_CLI_OFFLINE=1)
+    packages are served from that cache, however old, and the command fails
+    listing any package that was never looked up on this machine. Use
+    --no-cache to skip the cache for one run, --cache-ttl to change how long
+    entries stay fresh, and \`socket cache\` to inspect or clear it.
+
     Note: if a package cannot be found, it may be too old or perhaps was removed
           before we had the opportunity to process it.
 
@@ -90,7 +108,7 @@ export async function run(
     parentName,
   })
 
-  const { json, markdown } = cli.flags
+  const { cache, cacheTtl, json, markdown, offline } = cli.flags
 
   const dryRun = cli.flags['dryRun']
 
@@ -100,6 +118,8 @@ export async function run(
 
   const { purls, valid } = parsePackageSpecifiers(ecosystem, pkgs)
 
+  const cacheTtlMs = parseCacheDuration(cacheTtl)
+
   const wasValidInput = checkCommandInput(
     outputKind,
     {
@@ -119,11 +139,26 @@ export async function run(
       message: 'The json and markdown flags cannot be both set, pick one',
       fail: 'omit one',
     },
+    {
+      nook: true,
+      test: !cacheTtl || cacheTtlMs !== undefined,
+      message: 'The --cache-ttl flag expects a duration like 30m, 12h or 7d',
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: cache || !offline,
+      message:
+        'The --offline flag reads the cache, so it cannot be combined with --no-cache',
+      fail: 'omit one',
+    },
   )
   if (!wasValidInput) {
     return
   }
 
+  setCacheOverrides({ disabled: !cache, ttlMs: cacheTtlMs })
+
   if (dryRun) {
     outputDryRunFetch('package information', {
       packages: purls.length ? purls.join(', ') : '(none)',
@@ -133,6 +168,7 @@ export async function run(
   }
 
   await handlePurlsShallowScore({
+    offline: !!offline,
     outputKind,
     purls,
   })
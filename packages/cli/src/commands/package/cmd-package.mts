@@ -1,3 +1,5 @@
+import { cmdPackageDiff } from './cmd-package-diff.mts'
+import { cmdPackageFiles } from './cmd-package-files.mts'
 import { cmdPackageScore } from './cmd-package-score.mts'
 import { cmdPackageShallow } from './cmd-package-shallow.mts'
 import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
@@ -9,6 +11,8 @@ export const cmdPackage = defineSubcommandGroup({
   description,
   hidden: false,
   subcommands: {
+    diff: cmdPackageDiff,
+    files: cmdPackageFiles,
     score: cmdPackageScore,
     shallow: cmdPackageShallow,
   },
@@ -1,8 +1,13 @@
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
 import { queryApiSafeJson } from '../../util/socket/api.mjs'
+import {
+  readScoreCache,
+  storeScoreCacheEntries,
+} from '../../util/socket/score-cache.mts'
 
 import type { CResult } from '../../types.mts'
+import type { JsonContent } from '@socketsecurity/lib-stable/fs/types'
 
 const logger = getDefaultLogger()
 
@@ -55,13 +60,42 @@ export interface PurlDataResponse {
   }
 }
 
+export type FetchPurlDeepScoreOptions = {
+  offline?: boolean | undefined
+}
+
 export async function fetchPurlDeepScore(
   purl: string,
+  options?: FetchPurlDeepScoreOptions | undefined,
 ): Promise<CResult<PurlDataResponse>> {
+  const { offline } = {
+    __proto__: null,
+    ...options,
+  } as FetchPurlDeepScoreOptions
+
+  const cached = await readScoreCache('deep', purl, { allowStale: offline })
+  if (cached) {
+    logger.info(`Using cached deep score data for this purl: ${purl}`)
+    return { ok: true, data: cached as unknown as PurlDataResponse }
+  }
+  if (offline) {
+    return {
+      ok: false,
+      message: 'Package data not cached',
+      cause: `Offline mode is on and the local score cache has no deep score entry for ${purl}. Run the same lookup once with network access to populate the cache, or drop --offline.`,
+    }
+  }
+
   logger.info(`Requesting deep score data for this purl: ${purl}`)
 
-  return await queryApiSafeJson<PurlDataResponse>(
+  const result = await queryApiSafeJson<PurlDataResponse>(
     `purl/score/${encodeURIComponent(purl)}`,
     'the deep package scores',
   )
+  if (result.ok) {
+    await storeScoreCacheEntries('deep', [
+      [purl, result.data as unknown as JsonContent],
+    ])
+  }
+  return result
 }
@@ -1,61 +1,148 @@
 import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
 import { handleApiCall } from '../../util/socket/api.mjs'
+import { runApiBatches } from '../../util/socket/api-batch.mts'
+import {
+  readScoreCache,
+  storeScoreCacheEntries,
+} from '../../util/socket/score-cache.mts'
 import { setupSdk } from '../../util/socket/sdk.mjs'
 
 import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
 import type { SetupSdkOptions } from '../../util/socket/sdk.mjs'
+import type { JsonContent } from '@socketsecurity/lib-stable/fs/types'
 import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
 
 export type FetchPurlsShallowScoreOptions = {
   commandPath?: string | undefined
+  offline?: boolean | undefined
   sdkOpts?: SetupSdkOptions | undefined
 }
 
+type ShallowScoreData = SocketSdkSuccessResult<'batchPackageFetch'>
+
+function formatPurlList(purls: string[]): string {
+  return purls.length > 3
+    ? `${purls.slice(0, 3).join(', ')} … and ${purls.length - 3} more`
+    : joinAnd(purls)
+}
+
 export async function fetchPurlsShallowScore(
   purls: string[],
   options?: FetchPurlsShallowScoreOptions | undefined,
-): Promise<CResult<SocketSdkSuccessResult<'batchPackageFetch'>>> {
-  const { commandPath, sdkOpts } = {
+): Promise<CResult<ShallowScoreData>> {
+  const { commandPath, offline, sdkOpts } = {
     __proto__: null,
     ...options,
   } as FetchPurlsShallowScoreOptions
 
+  const logger = getDefaultLogger()
+
+  // Offline mode accepts stale entries: an old verdict beats no verdict on
+  // a runner that cannot reach the API.
+  const cachedEntries = await Promise.all(
+    purls.map(purl => readScoreCache('shallow', purl, { allowStale: offline })),
+  )
+  const cached: SocketArtifact[] = []
+  const uncachedPurls: string[] = []
+  for (let i = 0, { length } = purls; i < length; i += 1) {
+    const artifact = cachedEntries[i]
+    if (artifact) {
+      cached.push(artifact as unknown as SocketArtifact)
+    } else {
+      uncachedPurls.push(purls[i]!)
+    }
+  }
+
+  if (offline && uncachedPurls.length) {
+    return {
+      ok: false,
+      message: 'Package data not cached',
+      cause: `Offline mode is on and the local score cache has no entry for ${formatPurlList(uncachedPurls)}. Run the same lookup once with network access to populate the cache, or drop --offline.`,
+    }
+  }
+  if (cached.length && !uncachedPurls.length) {
+    logger.info(
+      `Using cached shallow score data for ${purls.length} package urls (purl): ${formatPurlList(purls)}`,
+    )
+    return { ok: true, data: cached as unknown as ShallowScoreData }
+  }
+
   const sockSdkCResult = await setupSdk(sdkOpts)
   if (!sockSdkCResult.ok) {
     return sockSdkCResult
   }
   const sockSdk = sockSdkCResult.data
 
-  const displayPurls =
-    purls.length > 3
-      ? `${purls.slice(0, 3).join(', ')} … and ${purls.length - 3} more`
-      : joinAnd(purls)
-  const logger = getDefaultLogger()
   logger.info(
-    `Requesting shallow score data for ${purls.length} package urls (purl): ${displayPurls}`,
+    `Requesting shallow score data for ${uncachedPurls.length} package urls (purl): ${formatPurlList(uncachedPurls)}`,
   )
 
-  const batchPackageCResult = await handleApiCall<'batchPackageFetch'>(
-    sockSdk.batchPackageFetch(
-      { components: purls.map(purl => ({ purl })) },
-      {
-        alerts: 'true',
-      },
-    ),
-    {
-      commandPath,
-      description: 'looking up package',
+  // Large lock files hold thousands of purls: send them in batches through
+  // a pool of concurrent requests that backs off when rate limited.
+  const batchPackageCResult = await runApiBatches<string, SocketArtifact>(
+    uncachedPurls,
+    async (batch, offset) => {
+      const batchCResult = await handleApiCall<'batchPackageFetch'>(
+        sockSdk.batchPackageFetch(
+          { components: batch.map(purl => ({ purl })) },
+          {
+            alerts: 'true',
+          },
+        ),
+        {
+          commandPath,
+          description: 'looking up package',
+        },
+      )
+      if (!batchCResult.ok) {
+        return batchCResult
+      }
+      // Type assertion needed due to SDK result type mismatch.
+      const artifacts = Array.isArray(batchCResult.data)
+        ? (batchCResult.data as unknown as SocketArtifact[])
+        : []
+      // Key entries by the purl that was asked for so the next lookup of the
+      // same specifier hits, even when the API canonicalized it.
+      await storeScoreCacheEntries(
+        'shallow',
+        artifacts.map(artifact => [
+          artifact.inputPurl ??
+            (typeof artifact.batchIndex === 'number'
+              ? batch[artifact.batchIndex]
+              : undefined) ??
+            getArtifactPurlString(artifact),
+          artifact as unknown as JsonContent,
+        ]),
+      )
+      // Batch indexes count from the start of the whole lookup.
+      return {
+        ok: true,
+        data: artifacts.map(artifact =>
+          typeof artifact.batchIndex === 'number'
+            ? { ...artifact, batchIndex: artifact.batchIndex + offset }
+            : artifact,
+        ),
+      }
     },
   )
   if (!batchPackageCResult.ok) {
     return batchPackageCResult
   }
 
-  // Type assertion needed due to SDK result type mismatch.
+  const fetched = batchPackageCResult.data as unknown as ShallowScoreData
+
+  if (!cached.length) {
+    return { ok: true, data: fetched }
+  }
   return {
     ok: true,
-    data: batchPackageCResult.data as SocketSdkSuccessResult<'batchPackageFetch'>,
+    data: [
+      ...cached,
+      ...(fetched as unknown as SocketArtifact[]),
+    ] as unknown as ShallowScoreData,
   }
 }
@@ -0,0 +1,29 @@
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputPackageDiff } from './output-package-diff.mts'
+import { getPackageDiff } from './package-diff.mts'
+
+import type { PackageDiffSpec } from './package-diff.mts'
+import type { OutputKind } from '../../types.mts'
+
+export async function handlePackageDiff({
+  after,
+  before,
+  outputKind,
+  summaryOnly,
+}: {
+  after: PackageDiffSpec
+  before: PackageDiffSpec
+  outputKind: OutputKind
+  summaryOnly: boolean
+}): Promise<void> {
+  debug(
+    `Comparing ${before.name}@${before.version} with ${after.name}@${after.version}`,
+  )
+
+  const result = await getPackageDiff(before, after)
+
+  debugDir({ result })
+
+  outputPackageDiff(result, outputKind, { summaryOnly })
+}
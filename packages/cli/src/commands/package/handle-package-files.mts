@@ -0,0 +1,26 @@
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputPackageFiles } from './output-package-files.mts'
+import { getPackageFiles } from './package-files.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export async function handlePackageFiles({
+  changed,
+  name,
+  outputKind,
+  version,
+}: {
+  changed: boolean
+  name: string
+  outputKind: OutputKind
+  version?: string | undefined
+}): Promise<void> {
+  debug(`Inspecting the files of ${name}@${version ?? 'latest'}`)
+
+  const result = await getPackageFiles(name, version)
+
+  debugDir({ result })
+
+  outputPackageFiles(result, outputKind, { changed })
+}
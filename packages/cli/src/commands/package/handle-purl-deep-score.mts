@@ -8,14 +8,17 @@ import type { OutputKind } from '../../types.mts'
 export async function handlePurlDeepScore(
   purl: string,
   outputKind: OutputKind,
+  offline = false,
+  // Template file to render the score with, see --output-template.
+  outputTemplate = '',
 ) {
   debug(`Fetching deep score for ${purl}`)
-  debugDir({ purl, outputKind })
+  debugDir({ purl, outputKind, offline })
 
-  const result = await fetchPurlDeepScore(purl)
+  const result = await fetchPurlDeepScore(purl, { offline })
 
   debug(`Deep score ${result.ok ? 'fetched successfully' : 'fetch failed'}`)
   debugDir({ result })
 
-  await outputPurlsDeepScore(purl, result, outputKind)
+  await outputPurlsDeepScore(purl, result, outputKind, outputTemplate)
 }
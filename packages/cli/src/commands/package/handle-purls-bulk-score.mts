@@ -0,0 +1,115 @@
+import { existsSync, promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { getBulkScoreData, parsePurlList } from './bulk-score.mts'
+import { fetchPurlsShallowScore } from './fetch-purls-shallow-score.mts'
+import { outputPurlsBulkScore } from './output-purls-bulk-score.mts'
+
+import type { BulkScoreData } from './bulk-score.mts'
+import type { BulkScoreOutputKind } from './output-purls-bulk-score.mts'
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+async function readStdin(): Promise<string> {
+  let input = ''
+  for await (const chunk of process.stdin) {
+    input += chunk
+  }
+  return input
+}
+
+export async function readPurlListInput(
+  input: string,
+  cwd: string,
+): Promise<CResult<string[]>> {
+  let text: string
+  if (input === '-') {
+    if (process.stdin.isTTY) {
+      return {
+        ok: false,
+        message: 'No purls to score',
+        cause: 'Pipe the purl list to stdin, one purl per line',
+      }
+    }
+    text = await readStdin()
+  } else {
+    const filepath = path.resolve(cwd, input)
+    if (!existsSync(filepath)) {
+      return {
+        ok: false,
+        message: 'Purl list not found',
+        cause: `There is no file at ${filepath}`,
+      }
+    }
+    text = await fs.readFile(filepath, 'utf8')
+  }
+
+  const { invalid, purls } = parsePurlList(text)
+  if (invalid.length) {
+    const shown = invalid
+      .slice(0, 5)
+      .map(entry => `line ${entry.line}: ${entry.text}`)
+    if (invalid.length > shown.length) {
+      shown.push(`… and ${invalid.length - shown.length} more`)
+    }
+    return {
+      ok: false,
+      message: `The purl list has ${invalid.length} invalid ${pluralize('line', { count: invalid.length })}`,
+      cause: `Expected one purl like pkg:npm/lodash@4.17.21 per line; ${shown.join(', ')}`,
+    }
+  }
+  if (!purls.length) {
+    return {
+      ok: false,
+      message: 'No purls to score',
+      cause: `${input === '-' ? 'stdin' : input} holds no purls`,
+    }
+  }
+  return { ok: true, data: purls }
+}
+
+export async function handlePurlsBulkScore({
+  cwd,
+  input,
+  offline,
+  outputKind,
+}: {
+  cwd: string
+  input: string
+  offline: boolean
+  outputKind: BulkScoreOutputKind
+}): Promise<void> {
+  const purlsCResult = await readPurlListInput(input, cwd)
+  if (!purlsCResult.ok) {
+    await outputPurlsBulkScore(purlsCResult, outputKind)
+    return
+  }
+  const purls = purlsCResult.data
+
+  debug(`Fetching bulk scores for ${purls.length} packages`)
+  debugDir({ input, offline, outputKind })
+
+  const packageData = await fetchPurlsShallowScore(purls, {
+    commandPath: 'socket package score',
+    offline,
+  })
+
+  debug(
+    `Bulk scores ${packageData.ok ? 'fetched successfully' : 'fetch failed'}`,
+  )
+
+  const result: CResult<BulkScoreData> = packageData.ok
+    ? {
+        ok: true,
+        data: getBulkScoreData(
+          purls,
+          packageData.data as unknown as SocketArtifact[],
+        ),
+      }
+    : packageData
+
+  await outputPurlsBulkScore(result, outputKind)
+}
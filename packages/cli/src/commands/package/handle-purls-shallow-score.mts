@@ -7,17 +7,20 @@ import type { CResult, OutputKind } from '../../types.mts'
 import type { SocketArtifact } from '../../util/alert/artifact.mts'
 
 export async function handlePurlsShallowScore({
+  offline,
   outputKind,
   purls,
 }: {
+  offline?: boolean | undefined
   outputKind: OutputKind
   purls: string[]
 }) {
   debug(`Fetching shallow scores for ${purls.length} packages`)
-  debugDir({ purls, outputKind })
+  debugDir({ purls, outputKind, offline })
 
   const packageData = await fetchPurlsShallowScore(purls, {
     commandPath: 'socket package shallow',
+    offline,
   })
 
   debug(
@@ -0,0 +1,266 @@
+import chalkTable from 'chalk-table'
+import colors from 'yoctocolors-cjs'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type {
+  DependencyChange,
+  InstallScriptChange,
+  PackageCapability,
+  PackageDiffFile,
+  PackageDiffResult,
+  PackageScore,
+} from './package-diff.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+const CAPABILITY_LABELS: Record<PackageCapability, string> = {
+  __proto__: null,
+  childProcess: 'child processes',
+  filesystem: 'filesystem',
+  network: 'network',
+} as unknown as Record<PackageCapability, string>
+
+const SCORE_LABELS: Array<[keyof PackageScore, string]> = [
+  ['supplyChain', 'Supply chain'],
+  ['vulnerability', 'Vulnerability'],
+  ['quality', 'Quality'],
+  ['maintenance', 'Maintenance'],
+  ['license', 'License'],
+]
+
+const SUMMARY_COLUMNS = ['check', 'change']
+
+const SUMMARY_TITLES = ['Check', 'Change']
+
+type ValueChange = {
+  after?: string | undefined
+  before?: string | undefined
+}
+
+type ValueChangeKind = 'added' | 'changed' | 'removed'
+
+const CHANGE_MARKERS: Record<ValueChangeKind, string> = {
+  __proto__: null,
+  added: '+',
+  changed: '~',
+  removed: '-',
+} as unknown as Record<ValueChangeKind, string>
+
+function getChangeKind(change: ValueChange): ValueChangeKind {
+  return change.before === undefined
+    ? 'added'
+    : change.after === undefined
+      ? 'removed'
+      : 'changed'
+}
+
+function countChanges(changes: ValueChange[]): Array<[number, string]> {
+  const kinds = changes.map(getChangeKind)
+  return (['added', 'removed', 'changed'] as const).map(
+    (kind): [number, string] => [kinds.filter(k => k === kind).length, kind],
+  )
+}
+
+function formatCounts(counts: Array<[number, string]>): string {
+  const parts = counts
+    .filter(({ 0: count }) => count)
+    .map(({ 0: count, 1: label }) => `${count} ${label}`)
+  return parts.length ? parts.join(', ') : 'no changes'
+}
+
+function formatScoreChange(
+  before: number | undefined,
+  after: number | undefined,
+): string {
+  if (before === undefined || after === undefined) {
+    return `${before ?? '–'} → ${after ?? '–'}`
+  }
+  const delta = after - before
+  return `${before} → ${after} (${delta > 0 ? `+${delta}` : delta})`
+}
+
+/**
+ * One row per kind of change, then one per score.
+ */
+export function getPackageDiffSummary(
+  result: PackageDiffResult,
+): Array<Record<string, string>> {
+  const { capabilities, dependencies, files, installScripts, scores } = result
+  const rows = [
+    {
+      check: 'Files',
+      change: formatCounts([
+        [files.filter(f => f.status === 'added').length, 'added'],
+        [files.filter(f => f.status === 'removed').length, 'removed'],
+        [files.filter(f => f.status === 'modified').length, 'modified'],
+      ]),
+    },
+    {
+      check: 'Install scripts',
+      change: installScripts.length
+        ? installScripts.map(s => `${s.name} ${getChangeKind(s)}`).join(', ')
+        : 'no changes',
+    },
+    {
+      check: 'New capabilities',
+      change: capabilities.length
+        ? capabilities.map(c => CAPABILITY_LABELS[c.capability]).join(', ')
+        : 'none',
+    },
+    {
+      check: 'Dependencies',
+      change: formatCounts(countChanges(dependencies)),
+    },
+  ]
+  if (!scores) {
+    rows.push({
+      check: 'Scores',
+      change: `unavailable: ${result.scoreError ?? 'unknown error'}`,
+    })
+    return rows
+  }
+  for (const { 0: key, 1: label } of SCORE_LABELS) {
+    rows.push({
+      check: `${label} score`,
+      change: formatScoreChange(scores.before?.[key], scores.after?.[key]),
+    })
+  }
+  return rows
+}
+
+function formatInstallScript(change: InstallScriptChange): string {
+  const kind = getChangeKind(change)
+  return `${CHANGE_MARKERS[kind]} ${change.name}: ${kind === 'removed' ? change.before : change.after}`
+}
+
+function formatDependency(change: DependencyChange): string {
+  const kind = getChangeKind(change)
+  const range =
+    kind === 'added'
+      ? change.after
+      : kind === 'removed'
+        ? change.before
+        : `${change.before} → ${change.after}`
+  return `${CHANGE_MARKERS[kind]} ${change.name} ${range} (${change.field})`
+}
+
+function formatFileDiff(file: PackageDiffFile): string {
+  if (file.diff !== undefined) {
+    return file.diff
+  }
+  const a = file.status === 'added' ? '/dev/null' : `a/${file.path}`
+  const b = file.status === 'removed' ? '/dev/null' : `b/${file.path}`
+  return file.skipped === 'binary'
+    ? `Binary files ${a} and ${b} differ`
+    : `Files ${a} and ${b} differ and are too large to diff`
+}
+
+function getDetailSections(
+  result: PackageDiffResult,
+): Array<[string, string[]]> {
+  const sections: Array<[string, string[]]> = []
+  if (result.installScripts.length) {
+    sections.push([
+      'Install scripts',
+      result.installScripts.map(formatInstallScript),
+    ])
+  }
+  for (const { capability, files } of result.capabilities) {
+    sections.push([
+      `New ${CAPABILITY_LABELS[capability]} use`,
+      files.map(file => `+ ${file}`),
+    ])
+  }
+  if (result.dependencies.length) {
+    sections.push(['Dependencies', result.dependencies.map(formatDependency)])
+  }
+  return sections
+}
+
+export function formatPackageDiff(
+  result: PackageDiffResult,
+  summaryOnly: boolean,
+): string {
+  const lines = [
+    `${result.before.purl} → ${result.after.purl}`,
+    chalkTable(
+      {
+        columns: SUMMARY_COLUMNS.map((field, i) => ({
+          field,
+          name: colors.magenta(SUMMARY_TITLES[i]!),
+        })),
+      },
+      getPackageDiffSummary(result),
+    ),
+  ]
+  for (const { 0: title, 1: items } of getDetailSections(result)) {
+    lines.push('', `${title}:`, ...items.map(item => `  ${item}`))
+  }
+  if (!summaryOnly && result.files.length) {
+    lines.push('', ...result.files.map(formatFileDiff))
+  }
+  return lines.join('\n')
+}
+
+export function formatPackageDiffMarkdown(
+  result: PackageDiffResult,
+  summaryOnly: boolean,
+): string {
+  const parts = [
+    mdHeader(`Diff of ${result.before.purl} → ${result.after.purl}`),
+    '',
+    mdTable(getPackageDiffSummary(result), SUMMARY_COLUMNS, SUMMARY_TITLES),
+  ]
+  for (const { 0: title, 1: items } of getDetailSections(result)) {
+    parts.push(
+      '',
+      mdHeader(title, 2),
+      '',
+      mdList(items.map(item => `\`${item}\``)),
+    )
+  }
+  if (!summaryOnly && result.files.length) {
+    parts.push(
+      '',
+      mdHeader('Files', 2),
+      '',
+      '```diff',
+      ...result.files.map(formatFileDiff),
+      '```',
+    )
+  }
+  return parts.join('\n')
+}
+
+export function outputPackageDiff(
+  result: CResult<PackageDiffResult>,
+  outputKind: OutputKind,
+  { summaryOnly }: { summaryOnly: boolean },
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(formatPackageDiffMarkdown(result.data, summaryOnly))
+    return
+  }
+
+  logger.log(formatPackageDiff(result.data, summaryOnly))
+}
@@ -0,0 +1,148 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+import { formatCacheBytes } from '../cache/output-cache-stats.mts'
+
+import type {
+  PackageFile,
+  PackageFileStatus,
+  PackageFilesResult,
+} from './package-files.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+const STATUS_MARKERS: Record<PackageFileStatus, string> = {
+  __proto__: null,
+  added: '+',
+  modified: '~',
+  same: ' ',
+} as unknown as Record<PackageFileStatus, string>
+
+const STATUS_LABELS: Record<PackageFileStatus, string> = {
+  __proto__: null,
+  added: 'only in the artifact',
+  modified: 'differs from the source',
+  same: '',
+} as unknown as Record<PackageFileStatus, string>
+
+function getListedFiles(
+  result: PackageFilesResult,
+  changed: boolean,
+): PackageFile[] {
+  return changed
+    ? result.files.filter(f => f.status === 'added' || f.status === 'modified')
+    : result.files
+}
+
+function getSourceLine(result: PackageFilesResult): string {
+  const { source, sourceError } = result
+  if (!source) {
+    return `Not compared with a source tree: ${sourceError ?? 'unknown source'}`
+  }
+  const at = source.ref
+    ? `${source.commit.slice(0, 12)} (${source.ref})`
+    : source.commit.slice(0, 12)
+  const where = source.directory ? `/${source.directory}` : ''
+  return `Compared with ${source.repository}${where} at ${at}, ${source.origin === 'provenance' ? 'from the provenance attestation' : 'from the unattested gitHead'}`
+}
+
+function getSummaryLine(result: PackageFilesResult): string | undefined {
+  if (!result.source) {
+    return undefined
+  }
+  const { added, modified } = result
+  if (!added && !modified) {
+    return 'Every file matches the source tree.'
+  }
+  return [
+    ...(added
+      ? [`${added} ${pluralize('file', { count: added })} only in the artifact`]
+      : []),
+    ...(modified
+      ? [
+          `${modified} ${pluralize('file', { count: modified })} differing from the source`,
+        ]
+      : []),
+  ].join(', ')
+}
+
+export function formatPackageFiles(
+  result: PackageFilesResult,
+  changed: boolean,
+): string {
+  const files = getListedFiles(result, changed)
+  const lines = [
+    `${result.purl}: ${result.files.length} ${pluralize('file', { count: result.files.length })}, ${formatCacheBytes(result.size)}`,
+    getSourceLine(result),
+    '',
+  ]
+  const width = Math.max(0, ...files.map(f => f.path.length))
+  for (const file of files) {
+    const marker = file.status ? STATUS_MARKERS[file.status] : ' '
+    const label = file.status ? STATUS_LABELS[file.status] : ''
+    lines.push(
+      `  ${marker} ${file.path.padEnd(width)}  ${formatCacheBytes(file.size).padStart(10)}${label ? `  ${label}` : ''}`.trimEnd(),
+    )
+  }
+  const summary = getSummaryLine(result)
+  if (summary) {
+    lines.push('', summary)
+  }
+  return lines.join('\n')
+}
+
+export function formatPackageFilesMarkdown(
+  result: PackageFilesResult,
+  changed: boolean,
+): string {
+  const summary = getSummaryLine(result)
+  return [
+    mdHeader(`Files of ${result.purl}`),
+    '',
+    mdKeyValue('Files', String(result.files.length)),
+    mdKeyValue('Size', formatCacheBytes(result.size)),
+    '',
+    getSourceLine(result),
+    ...(summary ? ['', summary] : []),
+    '',
+    mdTable(
+      getListedFiles(result, changed).map(file => ({
+        File: file.path,
+        Size: formatCacheBytes(file.size),
+        Status: file.status ? STATUS_LABELS[file.status] || 'same' : '',
+      })),
+      ['File', 'Size', 'Status'],
+    ),
+  ].join('\n')
+}
+
+export function outputPackageFiles(
+  result: CResult<PackageFilesResult>,
+  outputKind: OutputKind,
+  { changed }: { changed: boolean },
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(formatPackageFilesMarkdown(result.data, changed))
+    return
+  }
+
+  logger.log(formatPackageFiles(result.data, changed))
+}
@@ -0,0 +1,116 @@
+import chalkTable from 'chalk-table'
+import colors from 'yoctocolors-cjs'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import {
+  BULK_SCORE_ALERT_SEVERITIES,
+  formatBulkScoreCsv,
+} from './bulk-score.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { BulkScoreData, BulkScoreRow } from './bulk-score.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export type BulkScoreOutputKind = OutputKind | 'csv'
+
+function formatAlertCounts(row: BulkScoreRow): string {
+  return BULK_SCORE_ALERT_SEVERITIES.map(severity => row.alerts[severity]).join(
+    '/',
+  )
+}
+
+function getSummary(data: BulkScoreData): string {
+  const { missing, packages } = data
+  const risky = packages.filter(
+    row => row.alerts.critical || row.alerts.high,
+  ).length
+  return `Scored ${packages.length - missing.length} of ${packages.length} ${pluralize('package', { count: packages.length })}; ${risky} with critical or high alerts, ${missing.length} not found.`
+}
+
+function getTableRows(data: BulkScoreData): Array<Record<string, string>> {
+  return data.packages.map(row => ({
+    alerts: row.found ? formatAlertCounts(row) : '',
+    license: String(row.score?.license ?? ''),
+    maintenance: String(row.score?.maintenance ?? ''),
+    purl: row.purl,
+    quality: String(row.score?.quality ?? ''),
+    supplyChain: String(row.score?.supplyChain ?? ''),
+    types: row.found ? row.alertTypes.join(', ') : 'not found',
+    vulnerability: String(row.score?.vulnerability ?? ''),
+  }))
+}
+
+const TABLE_COLUMNS = [
+  'purl',
+  'supplyChain',
+  'maintenance',
+  'quality',
+  'vulnerability',
+  'license',
+  'alerts',
+  'types',
+]
+
+const TABLE_TITLES = [
+  'Package',
+  'Supply Chain',
+  'Maintenance',
+  'Quality',
+  'Vulnerability',
+  'License',
+  'Alerts (C/H/M/L)',
+  'Alert types',
+]
+
+export async function outputPurlsBulkScore(
+  result: CResult<BulkScoreData>,
+  outputKind: BulkScoreOutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === 'csv') {
+    logger.log(formatBulkScoreCsv(result.data))
+    return
+  }
+
+  const rows = getTableRows(result.data)
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Package Scores'))
+    logger.log('')
+    logger.log(getSummary(result.data))
+    logger.log('')
+    logger.log(mdTable(rows, TABLE_COLUMNS, TABLE_TITLES))
+    return
+  }
+
+  logger.log(getSummary(result.data))
+  logger.log(
+    chalkTable(
+      {
+        columns: TABLE_COLUMNS.map((field, i) => ({
+          field,
+          name: colors.magenta(TABLE_TITLES[i]!),
+        })),
+      },
+      rows,
+    ),
+  )
+}
@@ -3,6 +3,7 @@ import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
 import { mdTable } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
+import { renderTemplateFile } from '../../util/output/template.mts'
 
 import type { PurlDataResponse } from './fetch-purl-deep-score.mts'
 import type { CResult, OutputKind } from '../../types.mts'
@@ -188,6 +189,7 @@ export async function outputPurlsDeepScore(
   purl: string,
   result: CResult<PurlDataResponse>,
   outputKind: OutputKind,
+  outputTemplate = '',
 ): Promise<void> {
   if (!result.ok) {
     process.exitCode = result.code ?? 1
@@ -202,6 +204,17 @@ export async function outputPurlsDeepScore(
     return
   }
 
+  if (outputTemplate) {
+    const renderCResult = renderTemplateFile(outputTemplate, result.data)
+    if (!renderCResult.ok) {
+      process.exitCode = renderCResult.code ?? 1
+      logger.fail(failMsgWithBadge(renderCResult.message, renderCResult.cause))
+      return
+    }
+    logger.log(renderCResult.data)
+    return
+  }
+
   if (outputKind === 'markdown') {
     const md = createMarkdownReport(result.data)
     logger.success(`Score report for "${result.data.purl}" ("${purl}"):`)
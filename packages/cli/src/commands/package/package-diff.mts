@@ -0,0 +1,396 @@
+/**
+ * Comparison of two published npm package versions for `socket package diff`.
+ *
+ * Both tarballs are downloaded from the registry and checked against their
+ * integrity, then compared file by file. On top of the line diff, the changes
+ * a reviewer of an upgrade looks for first are pulled out: install scripts
+ * that appear or change, dependency changes, and capabilities the new
+ * version's code uses that the old one did not. Capabilities are found by
+ * matching the modules the code requires or imports, so code that builds
+ * module names at runtime is not caught; treat them as a reading list, not a
+ * verdict. The Socket scores of both versions are compared when they can be
+ * fetched.
+ */
+
+import { SOCKET_PUBLIC_API_TOKEN } from '@socketsecurity/lib-stable/constants/socket'
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { getBulkScoreData } from './bulk-score.mts'
+import { fetchPurlsShallowScore } from './fetch-purls-shallow-score.mts'
+import { formatUnifiedDiff } from '../../util/output/unified-diff.mts'
+import { getDefaultApiToken } from '../../util/socket/sdk.mjs'
+import {
+  fetchPackageDist,
+  fetchPackageTarball,
+  readTarballFiles,
+} from '../audit-installed/package-contents.mts'
+
+import type { BulkScoreRow } from './bulk-score.mts'
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export type PackageDiffSpec = {
+  name: string
+  version: string
+}
+
+export type PackageDiffFileStatus = 'added' | 'modified' | 'removed'
+
+export type PackageDiffFile = {
+  // Unified diff of the contents, unless the file was skipped.
+  diff?: string | undefined
+  path: string
+  sizeAfter?: number | undefined
+  sizeBefore?: number | undefined
+  // Why the contents were not diffed.
+  skipped?: 'binary' | 'too large' | undefined
+  status: PackageDiffFileStatus
+}
+
+export const INSTALL_SCRIPTS = ['preinstall', 'install', 'postinstall'] as const
+
+export type InstallScriptName = (typeof INSTALL_SCRIPTS)[number]
+
+export type InstallScriptChange = {
+  after?: string | undefined
+  before?: string | undefined
+  name: InstallScriptName
+}
+
+export const DEPENDENCY_FIELDS = [
+  'dependencies',
+  'optionalDependencies',
+  'peerDependencies',
+] as const
+
+export type DependencyField = (typeof DEPENDENCY_FIELDS)[number]
+
+export type DependencyChange = {
+  after?: string | undefined
+  before?: string | undefined
+  field: DependencyField
+  name: string
+}
+
+export type PackageCapability = 'childProcess' | 'filesystem' | 'network'
+
+export type PackageCapabilityChange = {
+  capability: PackageCapability
+  // Files of the new version that use it.
+  files: string[]
+}
+
+export type PackageScore = NonNullable<BulkScoreRow['score']>
+
+export type PackageDiffVersion = {
+  name: string
+  purl: string
+  version: string
+}
+
+export type PackageDiffResult = {
+  after: PackageDiffVersion
+  before: PackageDiffVersion
+  // Capabilities the new version uses that the old one does not.
+  capabilities: PackageCapabilityChange[]
+  dependencies: DependencyChange[]
+  files: PackageDiffFile[]
+  installScripts: InstallScriptChange[]
+  // Why the scores could not be compared.
+  scoreError?: string | undefined
+  scores?:
+    | { after?: PackageScore | undefined; before?: PackageScore | undefined }
+    | undefined
+}
+
+// Files past this size, like bundles and source maps, are only counted.
+const MAX_DIFF_FILE_SIZE = 1024 * 1024
+
+// How far into a file to look for a NUL byte, as git does.
+const BINARY_SNIFF_LENGTH = 8000
+
+const SCRIPT_FILE_REGEXP = /\.[cm]?[jt]sx?$/
+
+const TYPE_DECLARATION_REGEXP = /\.d\.[cm]?ts$/
+
+function getModuleRegExp(modules: string): RegExp {
+  return new RegExp(
+    String.raw`(?:\brequire\s*\(\s*|\bimport\s*\(\s*|\bfrom\s*|\bimport\s+)['"\`](?:node:)?(?:${modules})(?:/[\w/]*)?['"\`]`,
+  )
+}
+
+const CAPABILITY_REGEXPS: Array<[PackageCapability, RegExp[]]> = [
+  ['childProcess', [getModuleRegExp('child_process')]],
+  ['filesystem', [getModuleRegExp('fs')]],
+  [
+    'network',
+    [
+      getModuleRegExp('dgram|dns|http|http2|https|net|tls'),
+      /\bfetch\s*\(/,
+      /\bnew\s+(?:WebSocket|XMLHttpRequest)\b/,
+    ],
+  ],
+]
+
+/**
+ * A version to compare, given as `name@version` or as an npm purl. The
+ * version must be exact.
+ */
+export function parsePackageDiffSpec(
+  spec: string,
+): PackageDiffSpec | undefined {
+  let raw: string
+  try {
+    raw = decodeURIComponent(spec.trim().replace(/^(?:pkg:)?npm\//, ''))
+  } catch {
+    return undefined
+  }
+  const at = raw.lastIndexOf('@')
+  // Purls of other ecosystems keep their `pkg:` prefix.
+  if (at <= 0 || raw.startsWith('pkg:')) {
+    return undefined
+  }
+  const name = raw.slice(0, at)
+  const version = raw.slice(at + 1)
+  return name && version ? { name, version } : undefined
+}
+
+function isBinary(data: Buffer): boolean {
+  return data.subarray(0, BINARY_SNIFF_LENGTH).includes(0)
+}
+
+function getSkipReason(
+  ...datas: Array<Buffer | undefined>
+): PackageDiffFile['skipped'] {
+  if (datas.some(data => data && isBinary(data))) {
+    return 'binary'
+  }
+  if (datas.some(data => data && data.length > MAX_DIFF_FILE_SIZE)) {
+    return 'too large'
+  }
+  return undefined
+}
+
+/**
+ * The files that were added, removed or modified between two tarballs, with
+ * the unified diff of each text file.
+ */
+export function diffPackageFiles(
+  before: Map<string, Buffer>,
+  after: Map<string, Buffer>,
+): PackageDiffFile[] {
+  const files: PackageDiffFile[] = []
+  for (const path of new Set([...before.keys(), ...after.keys()])) {
+    const a = before.get(path)
+    const b = after.get(path)
+    if (a && b && a.equals(b)) {
+      continue
+    }
+    const entry: PackageDiffFile = {
+      path,
+      ...(b ? { sizeAfter: b.length } : {}),
+      ...(a ? { sizeBefore: a.length } : {}),
+      status: !a ? 'added' : !b ? 'removed' : 'modified',
+    }
+    const skipped = getSkipReason(a, b)
+    const diff = skipped
+      ? undefined
+      : formatUnifiedDiff({
+          after: b?.toString('utf8'),
+          before: a?.toString('utf8'),
+          path,
+        })
+    if (skipped || diff === undefined) {
+      entry.skipped = skipped ?? 'too large'
+    } else {
+      entry.diff = diff
+    }
+    files.push(entry)
+  }
+  return files.sort((a, b) => a.path.localeCompare(b.path))
+}
+
+/**
+ * The script files of a tarball that use each capability.
+ */
+export function detectCapabilities(
+  files: Map<string, Buffer>,
+): Map<PackageCapability, string[]> {
+  const found = new Map<PackageCapability, string[]>()
+  for (const { 0: path, 1: data } of files) {
+    if (!SCRIPT_FILE_REGEXP.test(path) || TYPE_DECLARATION_REGEXP.test(path)) {
+      continue
+    }
+    const code = data.toString('utf8')
+    for (const { 0: capability, 1: regexps } of CAPABILITY_REGEXPS) {
+      if (regexps.some(regexp => regexp.test(code))) {
+        const paths = found.get(capability)
+        if (paths) {
+          paths.push(path)
+        } else {
+          found.set(capability, [path])
+        }
+      }
+    }
+  }
+  return found
+}
+
+export function getNewCapabilities(
+  before: Map<PackageCapability, string[]>,
+  after: Map<PackageCapability, string[]>,
+): PackageCapabilityChange[] {
+  return CAPABILITY_REGEXPS.map(({ 0: capability }) => capability)
+    .filter(capability => after.has(capability) && !before.has(capability))
+    .map(capability => ({
+      capability,
+      files: after.get(capability)!.toSorted((a, b) => a.localeCompare(b)),
+    }))
+}
+
+function readPackageJson(files: Map<string, Buffer>): Record<string, unknown> {
+  const data = files.get('package.json')
+  try {
+    const json = data ? JSON.parse(data.toString('utf8')) : undefined
+    return json && typeof json === 'object' ? json : {}
+  } catch {
+    return {}
+  }
+}
+
+function getStringRecord(value: unknown): Record<string, string> {
+  const record = { __proto__: null } as unknown as Record<string, string>
+  if (value && typeof value === 'object') {
+    for (const { 0: key, 1: entry } of Object.entries(value)) {
+      if (typeof entry === 'string') {
+        record[key] = entry
+      }
+    }
+  }
+  return record
+}
+
+export function diffInstallScripts(
+  before: Record<string, unknown>,
+  after: Record<string, unknown>,
+): InstallScriptChange[] {
+  const a = getStringRecord(before['scripts'])
+  const b = getStringRecord(after['scripts'])
+  return INSTALL_SCRIPTS.filter(name => a[name] !== b[name]).map(name => ({
+    ...(b[name] !== undefined ? { after: b[name] } : {}),
+    ...(a[name] !== undefined ? { before: a[name] } : {}),
+    name,
+  }))
+}
+
+export function diffDependencies(
+  before: Record<string, unknown>,
+  after: Record<string, unknown>,
+): DependencyChange[] {
+  const changes: DependencyChange[] = []
+  for (const field of DEPENDENCY_FIELDS) {
+    const a = getStringRecord(before[field])
+    const b = getStringRecord(after[field])
+    const names = new Set([...Object.keys(a), ...Object.keys(b)])
+    for (const name of [...names].sort((x, y) => x.localeCompare(y))) {
+      if (a[name] !== b[name]) {
+        changes.push({
+          ...(b[name] !== undefined ? { after: b[name] } : {}),
+          ...(a[name] !== undefined ? { before: a[name] } : {}),
+          field,
+          name,
+        })
+      }
+    }
+  }
+  return changes
+}
+
+async function fetchPackageFiles(
+  spec: PackageDiffSpec,
+): Promise<CResult<Map<string, Buffer>>> {
+  const distCResult = await fetchPackageDist(spec.name, spec.version)
+  if (!distCResult.ok) {
+    return distCResult
+  }
+  if (!distCResult.data) {
+    return {
+      ok: false,
+      message: 'Version not found',
+      cause: `The npm registry lists no tarball for ${spec.name}@${spec.version}`,
+    }
+  }
+  const tarballCResult = await fetchPackageTarball(distCResult.data)
+  if (!tarballCResult.ok) {
+    return tarballCResult
+  }
+  return { ok: true, data: readTarballFiles(tarballCResult.data) }
+}
+
+function getDiffVersion(spec: PackageDiffSpec): PackageDiffVersion {
+  return {
+    name: spec.name,
+    purl: `pkg:npm/${spec.name}@${spec.version}`,
+    version: spec.version,
+  }
+}
+
+/**
+ * Download two versions of npm packages and compare them.
+ */
+export async function getPackageDiff(
+  beforeSpec: PackageDiffSpec,
+  afterSpec: PackageDiffSpec,
+): Promise<CResult<PackageDiffResult>> {
+  const beforeCResult = await fetchPackageFiles(beforeSpec)
+  if (!beforeCResult.ok) {
+    return beforeCResult
+  }
+  const afterCResult = await fetchPackageFiles(afterSpec)
+  if (!afterCResult.ok) {
+    return afterCResult
+  }
+  const before = getDiffVersion(beforeSpec)
+  const after = getDiffVersion(afterSpec)
+  const beforePackageJson = readPackageJson(beforeCResult.data)
+  const afterPackageJson = readPackageJson(afterCResult.data)
+
+  // The diff stands on its own, so a failed score lookup is only reported.
+  let scoreError: string | undefined
+  let scores: PackageDiffResult['scores']
+  const purls = [before.purl, after.purl]
+  const scoreCResult = await fetchPurlsShallowScore(purls, {
+    commandPath: 'socket package diff',
+    sdkOpts: { apiToken: getDefaultApiToken() || SOCKET_PUBLIC_API_TOKEN },
+  })
+  if (scoreCResult.ok) {
+    const { packages } = getBulkScoreData(
+      purls,
+      scoreCResult.data as unknown as SocketArtifact[],
+    )
+    scores = {
+      ...(packages[1]?.score ? { after: packages[1].score } : {}),
+      ...(packages[0]?.score ? { before: packages[0].score } : {}),
+    }
+  } else {
+    debugDir({ scoreCResult })
+    scoreError = scoreCResult.cause ?? scoreCResult.message
+  }
+
+  return {
+    ok: true,
+    data: {
+      after,
+      before,
+      capabilities: getNewCapabilities(
+        detectCapabilities(beforeCResult.data),
+        detectCapabilities(afterCResult.data),
+      ),
+      dependencies: diffDependencies(beforePackageJson, afterPackageJson),
+      files: diffPackageFiles(beforeCResult.data, afterCResult.data),
+      installScripts: diffInstallScripts(beforePackageJson, afterPackageJson),
+      ...(scoreError ? { scoreError } : {}),
+      ...(scores ? { scores } : {}),
+    },
+  }
+}
@@ -0,0 +1,263 @@
+/**
+ * Static inspection of a published npm tarball for `socket package files`.
+ *
+ * The tarball is downloaded from the registry and checked against its
+ * integrity. When the version has a provenance attestation, the files are
+ * compared with the git tree of the commit it was built from: a blob hash
+ * that differs marks a file as modified, a path the tree does not have marks
+ * it as only in the artifact. Without provenance the `gitHead` npm recorded
+ * at publish time is used, which the publisher could have set to anything.
+ * Build output is expected to be only in the artifact; code that is neither
+ * built nor in the repository is what a reviewer should read first.
+ */
+
+import crypto from 'node:crypto'
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { getOctokit, withGitHubRetry } from '../../util/git/github.mts'
+import {
+  fetchPackageTarball,
+  readTarballFiles,
+} from '../audit-installed/package-contents.mts'
+import { fetchNpmProvenanceData } from '../verify/fetch-npm-provenance.mts'
+import { normalizeRepositoryUrl } from '../verify/provenance-attestation.mts'
+import { getProvenanceReport } from '../verify/verify-provenance.mts'
+
+import type { CResult } from '../../types.mts'
+import type { NpmVersionManifest } from '../verify/fetch-npm-provenance.mts'
+import type { ProvenanceClaims } from '../verify/verify-provenance.mts'
+
+// `added` files are in the artifact but not in the source tree.
+export type PackageFileStatus = 'added' | 'modified' | 'same'
+
+export type PackageFile = {
+  path: string
+  size: number
+  // Undefined when there is no source tree to compare with.
+  status?: PackageFileStatus | undefined
+}
+
+export type PackageSource = {
+  commit: string
+  // Package directory in a monorepo, from the package.json `repository`.
+  directory?: string | undefined
+  // Where the commit came from; `gitHead` is not attested.
+  origin: 'gitHead' | 'provenance'
+  ref?: string | undefined
+  // As `host/owner/repo`.
+  repository: string
+}
+
+export type PackageFilesResult = {
+  added: number
+  files: PackageFile[]
+  modified: number
+  name: string
+  purl: string
+  size: number
+  source?: PackageSource | undefined
+  // Why the files were not compared with a source tree.
+  sourceError?: string | undefined
+  version: string
+}
+
+const GITHUB_HOST = 'github.com'
+
+/**
+ * The object id git gives a file with these contents.
+ */
+export function getGitBlobSha(data: Buffer): string {
+  return crypto
+    .createHash('sha1')
+    .update(`blob ${data.length}\0`)
+    .update(data)
+    .digest('hex')
+}
+
+function getRepositoryDirectory(
+  manifest: NpmVersionManifest,
+): string | undefined {
+  const { repository } = manifest
+  const directory =
+    typeof repository === 'object' ? repository.directory : undefined
+  return directory?.replace(/^\.?\/+|\/+$/g, '') || undefined
+}
+
+/**
+ * The commit a version was built from: the one its provenance attests, else
+ * the `gitHead` of its manifest.
+ */
+export function getPackageSource(
+  manifest: NpmVersionManifest,
+  provenance: ProvenanceClaims | undefined,
+): PackageSource | undefined {
+  const directory = getRepositoryDirectory(manifest)
+  const attested = normalizeRepositoryUrl(provenance?.repository)
+  if (attested && provenance?.commit) {
+    return {
+      commit: provenance.commit,
+      ...(directory ? { directory } : {}),
+      origin: 'provenance',
+      ...(provenance.ref ? { ref: provenance.ref } : {}),
+      repository: attested,
+    }
+  }
+  const { repository } = manifest
+  const declared = normalizeRepositoryUrl(
+    typeof repository === 'string' ? repository : repository?.url,
+  )
+  if (declared && manifest.gitHead) {
+    return {
+      commit: manifest.gitHead,
+      ...(directory ? { directory } : {}),
+      origin: 'gitHead',
+      repository: declared,
+    }
+  }
+  return undefined
+}
+
+/**
+ * Compare tarball files with the blob hashes of a source tree, by their path
+ * below the package directory.
+ */
+export function comparePackageFiles(
+  files: Map<string, Buffer>,
+  tree?: Map<string, string> | undefined,
+  directory?: string | undefined,
+): PackageFile[] {
+  const result: PackageFile[] = []
+  for (const { 0: file, 1: data } of files) {
+    const entry: PackageFile = { path: file, size: data.length }
+    if (tree) {
+      const sha = tree.get(directory ? `${directory}/${file}` : file)
+      entry.status =
+        sha === undefined
+          ? 'added'
+          : sha === getGitBlobSha(data)
+            ? 'same'
+            : 'modified'
+    }
+    result.push(entry)
+  }
+  return result.sort((a, b) => a.path.localeCompare(b.path))
+}
+
+/**
+ * The blob hashes of a GitHub commit by path. Other git providers are not
+ * supported yet.
+ */
+export async function fetchSourceTree(
+  source: PackageSource,
+): Promise<CResult<Map<string, string>>> {
+  const { 0: host, 1: owner, 2: repo } = source.repository.split('/')
+  if (host !== GITHUB_HOST) {
+    return {
+      ok: false,
+      message: 'Unsupported provider',
+      cause: `Only GitHub repositories can be compared, ${source.repository} is hosted on ${host}`,
+    }
+  }
+  if (!owner || !repo) {
+    return {
+      ok: false,
+      message: 'Unsupported repository',
+      cause: `Not a GitHub repository: ${source.repository}`,
+    }
+  }
+  const octokit = getOctokit()
+  const result = await withGitHubRetry(
+    async () => {
+      const { data } = await octokit.git.getTree({
+        owner,
+        repo,
+        tree_sha: source.commit,
+        recursive: 'true',
+      })
+      return data
+    },
+    `fetching the file tree of ${source.repository} at ${source.commit}`,
+  )
+  if (!result.ok) {
+    return result
+  }
+  if (result.data.truncated) {
+    return {
+      ok: false,
+      message: 'Source tree too large',
+      cause: `GitHub truncated the file tree of ${source.repository} at ${source.commit}`,
+    }
+  }
+  const tree = new Map<string, string>()
+  for (const entry of result.data.tree ?? []) {
+    if (entry.type === 'blob' && entry.path && entry.sha) {
+      tree.set(entry.path, entry.sha)
+    }
+  }
+  return { ok: true, data: tree }
+}
+
+/**
+ * Download and list the files of an npm package version, and compare them
+ * with its source tree when it has one.
+ */
+export async function getPackageFiles(
+  name: string,
+  version?: string | undefined,
+): Promise<CResult<PackageFilesResult>> {
+  const dataCResult = await fetchNpmProvenanceData(name, version)
+  if (!dataCResult.ok) {
+    return dataCResult
+  }
+  const { manifest } = dataCResult.data
+  const { dist } = manifest
+  if (!dist?.integrity || !dist.tarball) {
+    return {
+      ok: false,
+      message: 'Tarball not found',
+      cause: `The npm registry lists no tarball for ${name}@${manifest.version}`,
+    }
+  }
+  const tarballCResult = await fetchPackageTarball({
+    integrity: dist.integrity,
+    tarball: dist.tarball,
+  })
+  if (!tarballCResult.ok) {
+    return tarballCResult
+  }
+  const tarballFiles = readTarballFiles(tarballCResult.data)
+
+  const { provenance } = getProvenanceReport(dataCResult.data)
+  const source = getPackageSource(manifest, provenance)
+  let sourceError: string | undefined
+  let tree: Map<string, string> | undefined
+  if (source) {
+    const treeCResult = await fetchSourceTree(source)
+    if (treeCResult.ok) {
+      tree = treeCResult.data
+    } else {
+      debugDir({ treeCResult })
+      sourceError = treeCResult.cause ?? treeCResult.message
+    }
+  } else {
+    sourceError =
+      'The version has no provenance attestation or gitHead to compare with'
+  }
+
+  const files = comparePackageFiles(tarballFiles, tree, source?.directory)
+  return {
+    ok: true,
+    data: {
+      added: files.filter(f => f.status === 'added').length,
+      files,
+      modified: files.filter(f => f.status === 'modified').length,
+      name: manifest.name,
+      purl: `pkg:npm/${manifest.name}@${manifest.version}`,
+      size: files.reduce((sum, f) => sum + f.size, 0),
+      ...(tree && source ? { source } : {}),
+      ...(sourceError ? { sourceError } : {}),
+      version: manifest.version,
+    },
+  }
+}
@@ -0,0 +1,67 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { checkPackagesBeforeInstall } from '../../util/install-check/check.mts'
+import { resolvePipInstall } from '../../util/python/pip-report.mts'
+
+import type { CResult } from '../../types.mts'
+import type { CheckInstallPackagesOptions } from '../../util/install-check/check.mts'
+
+const logger = getDefaultLogger()
+
+// Args that make pip resolve nothing or print its own report.
+const PIP_NO_CHECK_ARGS = new Set([
+  '--dry-run',
+  '--help',
+  '--report',
+  '--version',
+  '-V',
+  '-h',
+])
+
+/**
+ * Return the arguments after `install` when argv is a pip install that should
+ * be checked, or undefined otherwise.
+ */
+export function getPipInstallArgs(
+  args: readonly string[],
+): string[] | undefined {
+  const subcommandIndex = args.findIndex(arg => !arg.startsWith('-'))
+  if (subcommandIndex === -1 || args[subcommandIndex] !== 'install') {
+    return undefined
+  }
+  if (args.some(arg => PIP_NO_CHECK_ARGS.has(arg.split('=')[0]!))) {
+    return undefined
+  }
+  return args.slice(subcommandIndex + 1)
+}
+
+/**
+ * Preflight for `socket pip install`: resolve the wheels/sdists pip would
+ * install and score them before handing off. Resolution failures only warn,
+ * Socket Firewall still screens the downloads.
+ */
+export async function checkPipInstall(
+  args: readonly string[],
+  binaryName: string,
+  options?: Pick<CheckInstallPackagesOptions, 'interactive'> | undefined,
+): Promise<CResult<unknown>> {
+  const installArgs = getPipInstallArgs(args)
+  if (!installArgs) {
+    return { ok: true, data: undefined }
+  }
+  const spinner = getDefaultSpinner()
+  spinner.start('Resolving packages to install…')
+  const resolveCResult = await resolvePipInstall([binaryName], installArgs)
+  spinner.stop()
+  if (!resolveCResult.ok) {
+    logger.warn(
+      `Skipping the Socket pre-install check: ${resolveCResult.cause ?? resolveCResult.message}`,
+    )
+    return { ok: true, data: undefined }
+  }
+  return await checkPackagesBeforeInstall(resolveCResult.data, {
+    commandPath: 'socket pip',
+    interactive: options?.interactive,
+  })
+}
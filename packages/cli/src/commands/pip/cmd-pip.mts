@@ -3,13 +3,15 @@
  *
  * Defined via `defineHandoffCommand`. The pip-specific binary picker (pip ↔
  * pip3 with auto-fallback when one is missing) is wired through the factory's
- * `binaryPicker` hook.
+ * `binaryPicker` hook, and `pip install` is scored before the hand-off through
+ * its `preflight` hook.
  *
  * See util/cli/define-handoff.mts.
  */
 
 import { whichReal } from '@socketsecurity/lib-stable/bin/which'
 
+import { checkPipInstall } from './check-pip-install.mts'
 import { defineHandoffCommand } from '../../util/cli/define-handoff.mts'
 
 /**
@@ -46,6 +48,10 @@ export const cmdPip = defineHandoffCommand({
   spawnMode: 'dlx',
   examples: ['install flask', 'install -r requirements.txt', 'list'],
   binaryPicker: ctx => getPipBinName(ctx.invokedAs),
+  helpNotes: [
+    'Packages resolved by `pip install` are checked against Socket before installing.',
+  ],
+  preflight: checkPipInstall,
   trackTelemetry: false,
   supportDryRun: false,
 })
@@ -0,0 +1,137 @@
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { checkPackagesBeforeInstall } from '../../util/install-check/check.mts'
+import { resolvePipInstall } from '../../util/python/pip-report.mts'
+
+import type { CResult } from '../../types.mts'
+import type { CheckInstallPackagesOptions } from '../../util/install-check/check.mts'
+
+export type PipxInstallTarget = {
+  // Extra pip arguments forwarded by pipx (--index-url, --pip-args).
+  pipArgs: string[]
+  python: string | undefined
+  specs: string[]
+}
+
+// pipx options that consume the next argument.
+const PIPX_VALUE_OPTIONS = new Set([
+  '--backend',
+  '--index-url',
+  '--pip-args',
+  '--preinstall',
+  '--python',
+  '--spec',
+  '--suffix',
+  '-i',
+])
+
+const PIPX_NO_CHECK_ARGS = new Set(['--help', '-h'])
+
+function isLocalScript(spec: string): boolean {
+  return spec.endsWith('.py') || /^[a-z]+:\/\//i.test(spec)
+}
+
+/**
+ * Work out what a `pipx install|inject|run` invocation would put into its
+ * venv. Returns undefined for other subcommands and for `pipx run` of a local
+ * script or URL, which pipx runs with their inline metadata instead.
+ */
+export function getPipxInstallTarget(
+  args: readonly string[],
+): PipxInstallTarget | undefined {
+  const [subcommand, ...rest] = args
+  if (
+    (subcommand !== 'install' &&
+      subcommand !== 'inject' &&
+      subcommand !== 'run') ||
+    rest.some(arg => PIPX_NO_CHECK_ARGS.has(arg))
+  ) {
+    return undefined
+  }
+  const options = new Map<string, string>()
+  const positionals: string[] = []
+  for (let i = 0, { length } = rest; i < length; i += 1) {
+    const arg = rest[i]!
+    // Everything after the app name belongs to the app under `pipx run`.
+    if (subcommand === 'run' && positionals.length) {
+      break
+    }
+    if (arg === '--') {
+      positionals.push(...rest.slice(i + 1))
+      break
+    }
+    if (arg.startsWith('-')) {
+      const eqIndex = arg.indexOf('=')
+      const name = eqIndex === -1 ? arg : arg.slice(0, eqIndex)
+      if (PIPX_VALUE_OPTIONS.has(name)) {
+        let value: string | undefined
+        if (eqIndex === -1) {
+          i += 1
+          value = rest[i]
+        } else {
+          value = arg.slice(eqIndex + 1)
+        }
+        if (value !== undefined) {
+          options.set(name === '-i' ? '--index-url' : name, value)
+        }
+      }
+      continue
+    }
+    positionals.push(arg)
+  }
+
+  let specs: string[]
+  if (subcommand === 'run') {
+    const spec = options.get('--spec') ?? positionals[0]
+    specs = spec && !isLocalScript(spec) ? [spec] : []
+  } else if (subcommand === 'inject') {
+    // The first positional names the existing venv.
+    specs = positionals.slice(1)
+  } else {
+    specs = positionals
+  }
+  if (!specs.length) {
+    return undefined
+  }
+
+  const indexUrl = options.get('--index-url')
+  const extraPipArgs = options.get('--pip-args')
+  return {
+    pipArgs: [
+      ...(indexUrl ? ['--index-url', indexUrl] : []),
+      ...(extraPipArgs ? extraPipArgs.split(/\s+/).filter(Boolean) : []),
+    ],
+    python: options.get('--python'),
+    specs,
+  }
+}
+
+/**
+ * Preflight for `socket pipx`: resolve the distributions pipx would install
+ * into a fresh venv and score them. Socket Firewall does not proxy pipx, so a
+ * failed resolution blocks rather than falling through unchecked.
+ */
+export async function checkPipxInstall(
+  args: readonly string[],
+  options?: Pick<CheckInstallPackagesOptions, 'interactive'> | undefined,
+): Promise<CResult<unknown>> {
+  const target = getPipxInstallTarget(args)
+  if (!target) {
+    return { ok: true, data: undefined }
+  }
+  const spinner = getDefaultSpinner()
+  spinner.start('Resolving packages to install…')
+  const resolveCResult = await resolvePipInstall(
+    [target.python ?? 'python3', '-m', 'pip'],
+    [...target.pipArgs, ...target.specs],
+    { ignoreInstalled: true },
+  )
+  spinner.stop()
+  if (!resolveCResult.ok) {
+    return resolveCResult
+  }
+  return await checkPackagesBeforeInstall(resolveCResult.data, {
+    commandPath: 'socket pipx',
+    interactive: options?.interactive,
+  })
+}
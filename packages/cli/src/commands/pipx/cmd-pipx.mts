@@ -0,0 +1,27 @@
+/**
+ * Socket pipx command — checks the packages pipx would install against Socket,
+ * then runs pipx.
+ *
+ * Defined via `defineHandoffCommand` in `direct` spawn mode: Socket Firewall
+ * does not proxy pipx, so `pipx install|inject|run` is resolved with pip's
+ * installation report and scored in the `preflight` hook instead.
+ *
+ * See util/cli/define-handoff.mts.
+ */
+
+import { checkPipxInstall } from './check-pipx-install.mts'
+import { defineHandoffCommand } from '../../util/cli/define-handoff.mts'
+
+export const cmdPipx = defineHandoffCommand({
+  name: 'pipx',
+  description: 'Run pipx with Socket pre-install checks',
+  spawnMode: 'direct',
+  examples: ['install black', 'run cowsay -t moo', 'inject black flake8'],
+  helpNotes: [
+    'Packages resolved by `pipx install`, `inject` and `run` are checked against',
+    'Socket before pipx creates or updates the venv.',
+  ],
+  preflight: (args, _binaryName, context) => checkPipxInstall(args, context),
+  showApiRequirements: true,
+  trackTelemetry: false,
+})
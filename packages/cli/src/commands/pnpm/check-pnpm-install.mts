@@ -0,0 +1,98 @@
+import { PNPM } from '@socketsecurity/lib-stable/constants/agents'
+
+import { checkNodeInstall } from '../../util/install-check/node-install.mts'
+import { getNpmRegistrySpecs } from '../npm/npm-install-specs.mts'
+
+import type { CResult } from '../../types.mts'
+import type { CheckInstallPackagesOptions } from '../../util/install-check/check.mts'
+import type { NodeInstallRequest } from '../../util/install-check/node-install.mts'
+
+const PNPM_ADD_COMMANDS = new Set(['add', 'i', 'install'])
+
+// pnpm options that consume the next argument.
+const PNPM_VALUE_OPTIONS = new Set([
+  '--changed-files-ignore-pattern',
+  '--child-concurrency',
+  '--config',
+  '--dir',
+  '--filter',
+  '--filter-prod',
+  '--lockfile-dir',
+  '--loglevel',
+  '--modules-dir',
+  '--network-concurrency',
+  '--registry',
+  '--reporter',
+  '--store-dir',
+  '--test-pattern',
+  '--virtual-store-dir',
+  '--workspace-concurrency',
+  '-C',
+  '-F',
+])
+
+// Packages from another registry are not looked up.
+const PNPM_NO_CHECK_ARGS = new Set(['--help', '--registry', '-h'])
+
+/**
+ * Work out what a `pnpm add|install` invocation installs. Returns undefined
+ * for other commands and installs from another registry.
+ */
+export function getPnpmInstallRequest(
+  args: readonly string[],
+): NodeInstallRequest | undefined {
+  const positionals: string[] = []
+  let command: string | undefined
+  let dir: string | undefined
+  for (let i = 0, { length } = args; i < length; i += 1) {
+    const arg = args[i]!
+    if (arg === '--') {
+      break
+    }
+    if (arg.startsWith('-')) {
+      const eqIndex = arg.indexOf('=')
+      const name = eqIndex === -1 ? arg : arg.slice(0, eqIndex)
+      if (PNPM_NO_CHECK_ARGS.has(name)) {
+        return undefined
+      }
+      if (PNPM_VALUE_OPTIONS.has(name)) {
+        let value: string | undefined
+        if (eqIndex === -1) {
+          i += 1
+          value = args[i]
+        } else {
+          value = arg.slice(eqIndex + 1)
+        }
+        if (name === '--dir' || name === '-C') {
+          dir = value
+        }
+      }
+      continue
+    }
+    if (command) {
+      positionals.push(arg)
+    } else {
+      command = arg
+    }
+  }
+  if (!command || !PNPM_ADD_COMMANDS.has(command)) {
+    return undefined
+  }
+  // `pnpm install <pkg>` is an alias of `pnpm add <pkg>`.
+  if (command !== 'add' && !positionals.length) {
+    return { dir, fromLockfile: true, specs: [] }
+  }
+  const specs = getNpmRegistrySpecs(positionals)
+  return specs.length ? { dir, fromLockfile: false, specs } : undefined
+}
+
+/**
+ * Preflight for `socket pnpm add|install`, see
+ * util/install-check/node-install.mts.
+ */
+export async function checkPnpmInstall(
+  args: readonly string[],
+  options?: Pick<CheckInstallPackagesOptions, 'interactive'> | undefined,
+): Promise<CResult<unknown>> {
+  return await checkNodeInstall(PNPM, getPnpmInstallRequest(args), options)
+}
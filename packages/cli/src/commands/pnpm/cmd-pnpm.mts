@@ -1,11 +1,16 @@
 /**
  * Socket pnpm command — forwards pnpm operations to Socket Firewall (sfw).
  *
- * Defined via `defineHandoffCommand`. See util/cli/define-handoff.mts.
+ * Defined via `defineHandoffCommand`. The packages of `pnpm add|install` are
+ * scored before installing through its `preflight` hook, see
+ * check-pnpm-install.mts.
+ *
+ * See util/cli/define-handoff.mts.
  */
 
 import { PNPM } from '@socketsecurity/lib-stable/constants/agents'
 
+import { checkPnpmInstall } from './check-pnpm-install.mts'
 import { defineHandoffCommand } from '../../util/cli/define-handoff.mts'
 
 export const CMD_NAME = PNPM
@@ -16,6 +21,13 @@ export const cmdPnpm = defineHandoffCommand({
   spawnMode: 'dlx',
   hidden: true,
   examples: ['', 'install', 'add package-name', 'dlx package-name'],
+  helpNotes: [
+    'Packages named by `pnpm add`, and the direct dependencies locked for a bare',
+    '`pnpm install`, are checked with Socket first. Alerts the org policy blocks',
+    'stop the install and alerts it warns about ask before installing.',
+  ],
+  preflight: (args, _binaryName, context) =>
+    checkPnpmInstall(args, context),
   showApiRequirements: true,
   wrapperHint: true,
 })
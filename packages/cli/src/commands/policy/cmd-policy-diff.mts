@@ -0,0 +1,164 @@
+import path from 'node:path'
+
+import { handlePolicyDiff } from './handle-policy-diff.mts'
+import {
+  SOCKET_ORG_POLICY_JSON,
+  SOCKET_POLICY_YML,
+  SOCKET_YML,
+} from '../../constants/socket.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { findSocketOrgPolicyPathSync } from '../../util/policy/org-policy.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'diff'
+
+const description =
+  'Show where the repo policy settings diverge from the org security policy'
+
+const hidden = false
+
+export const cmdPolicyDiff: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      file: {
+        type: 'string',
+        description: `Org policy written by \`socket policy pull\` to compare against (default: the nearest ${SOCKET_ORG_POLICY_JSON}, else the Socket API)`,
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Lists every setting of the nearest \`${SOCKET_POLICY_YML}\` (block, warn and
+    exceptions) and the \`wrapper.alerts\` of the nearest \`${SOCKET_YML}\` that
+    acts on an alert type differently than the org security policy. Each is
+    weakened, when the repo gates more loosely than the org, or strengthened.
+
+    The org policy comes from the nearest \`${SOCKET_ORG_POLICY_JSON}\`, or
+    the Socket API when there is none. Only fetching it needs an API token.
+
+    Examples
+      $ ${command}
+      $ ${command} --file ./config/${SOCKET_ORG_POLICY_JSON} --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    file,
+    json,
+    markdown,
+    org: orgFlag,
+  } = cli.flags as {
+    file: string | undefined
+    json: boolean
+    markdown: boolean
+    org: string
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const interactive = !!cli.flags['interactive']
+
+  const cwd = process.cwd()
+
+  const orgPolicyPath = file
+    ? path.resolve(cwd, file)
+    : findSocketOrgPolicyPathSync(cwd)
+
+  // The org only matters when the policy is fetched.
+  const orgSlug = orgPolicyPath
+    ? ''
+    : (await determineOrgSlug(orgFlag || '', interactive, dryRun))[0]
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgPolicyPath || !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'dot is an invalid org, most likely you forgot the org name here?',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !!orgPolicyPath || hasDefaultApiToken(),
+      message: `This command requires a Socket API token for access, or a ${SOCKET_ORG_POLICY_JSON}`,
+      fail: 'try `socket login` or `socket policy pull`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  // Comparing against a pulled file only reads, which dry runs allow.
+  if (dryRun && !orgPolicyPath) {
+    outputDryRunFetch('organization security policy', {
+      organization: orgSlug,
+    })
+    return
+  }
+
+  await handlePolicyDiff({ cwd, orgPolicyPath, orgSlug, outputKind })
+}
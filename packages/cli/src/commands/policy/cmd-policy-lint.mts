@@ -0,0 +1,85 @@
+import path from 'node:path'
+
+import { handlePolicyLint } from './handle-policy-lint.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { SOCKET_POLICY_YML } from '../../constants/socket.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+const config = {
+  commandName: 'lint',
+  description: `Validate a \`${SOCKET_POLICY_YML}\` policy file`,
+  flags: defineFlags({
+    ...commonFlags,
+    ...outputFlags,
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] [FILE]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Checks the policy file for unknown keys, bad severities, conflicting
+    license lists and exceptions that would waive everything. Without FILE the
+    nearest \`${SOCKET_POLICY_YML}\` in the current directory or its parents is
+    used. Exits with code 1 when the file has errors.
+
+    \`socket scan report\`, \`socket scan create --report\` and the pre-install
+    checks of the package manager wrappers apply this file on top of your
+    organization policy.
+
+    Examples
+      $ ${command}
+      $ ${command} ./config/${SOCKET_POLICY_YML} --json
+  `,
+  hidden: false,
+}
+
+export const cmdPolicyLint = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(outputKind, {
+    nook: true,
+    test: !json || !markdown,
+    message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+    fail: 'bad',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
+  const [filepath] = cli.input
+
+  await handlePolicyLint({
+    cwd: process.cwd(),
+    filepath: filepath ? path.resolve(process.cwd(), filepath) : undefined,
+    outputKind,
+  })
+}
@@ -0,0 +1,155 @@
+import path from 'node:path'
+
+import { handlePolicyPull } from './handle-policy-pull.mts'
+import { SOCKET_ORG_POLICY_JSON } from '../../constants/socket.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'pull'
+
+const description = `Save the org security policy into a ${SOCKET_ORG_POLICY_JSON}`
+
+const hidden = false
+
+export const cmdPolicyPull: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      file: {
+        type: 'string',
+        default: SOCKET_ORG_POLICY_JSON,
+        description: `Where to write the org policy (default '${SOCKET_ORG_POLICY_JSON}')`,
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Records the action of every alert type the org security policy sets.
+    Commit the file: \`socket policy diff\` compares the repo socket.policy.yml
+    and socket.yml against it without an API token, and the history of the
+    file shows when the org policy changed.
+
+    Examples
+      $ ${command}
+      $ ${command} --file ./config/${SOCKET_ORG_POLICY_JSON}
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    file,
+    json,
+    markdown,
+    org: orgFlag,
+  } = cli.flags as {
+    file: string
+    json: boolean
+    markdown: boolean
+    org: string
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const interactive = !!cli.flags['interactive']
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'dot is an invalid org, most likely you forgot the org name here?',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  const filepath = path.resolve(process.cwd(), file || SOCKET_ORG_POLICY_JSON)
+
+  if (dryRun) {
+    outputDryRunFetch('organization security policy', {
+      organization: orgSlug,
+      file: filepath,
+    })
+    return
+  }
+
+  await handlePolicyPull({ filepath, orgSlug, outputKind })
+}
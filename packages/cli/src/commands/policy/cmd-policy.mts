@@ -0,0 +1,14 @@
+import { cmdPolicyDiff } from './cmd-policy-diff.mts'
+import { cmdPolicyLint } from './cmd-policy-lint.mts'
+import { cmdPolicyPull } from './cmd-policy-pull.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdPolicy = defineSubcommandGroup({
+  name: 'policy',
+  description: 'Work with the repository socket.policy.yml',
+  subcommands: {
+    diff: cmdPolicyDiff,
+    lint: cmdPolicyLint,
+    pull: cmdPolicyPull,
+  },
+})
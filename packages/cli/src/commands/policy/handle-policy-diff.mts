@@ -0,0 +1,92 @@
+import { outputPolicyDiff } from './output-policy-diff.mts'
+import { findSocketYmlSync } from '../../util/config.mts'
+import {
+  createSocketOrgPolicy,
+  diffLocalPolicy,
+  readSocketOrgPolicySync,
+} from '../../util/policy/org-policy.mts'
+import { findSocketPolicySync } from '../../util/policy/socket-policy.mts'
+import { fetchSecurityPolicy } from '../organization/fetch-security-policy.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type {
+  PolicyOverride,
+  SocketOrgPolicy,
+} from '../../util/policy/org-policy.mts'
+
+export type PolicyDiffReport = {
+  org: string
+  // The pulled org policy compared against, undefined when fetched.
+  orgPolicyPath?: string | undefined
+  overrides: PolicyOverride[]
+  weakened: number
+}
+
+/**
+ * Compare the socket.policy.yml and socket.yml found from cwd with the org
+ * security policy, read from orgPolicyPath when given and fetched otherwise.
+ */
+export async function getPolicyDiff({
+  cwd,
+  orgPolicyPath,
+  orgSlug,
+}: {
+  cwd: string
+  orgPolicyPath: string | undefined
+  orgSlug: string
+}): Promise<CResult<PolicyDiffReport>> {
+  let orgPolicyCResult: CResult<SocketOrgPolicy>
+  if (orgPolicyPath) {
+    orgPolicyCResult = readSocketOrgPolicySync(orgPolicyPath)
+  } else {
+    const fetchCResult = await fetchSecurityPolicy(orgSlug, {
+      commandPath: 'socket policy diff',
+    })
+    orgPolicyCResult = fetchCResult.ok
+      ? { ok: true, data: createSocketOrgPolicy(orgSlug, fetchCResult.data) }
+      : fetchCResult
+  }
+  if (!orgPolicyCResult.ok) {
+    return orgPolicyCResult
+  }
+  const policyCResult = findSocketPolicySync(cwd)
+  if (!policyCResult.ok) {
+    return policyCResult
+  }
+  const ymlCResult = findSocketYmlSync(cwd)
+  if (!ymlCResult.ok) {
+    return ymlCResult
+  }
+  const found = ymlCResult.data
+  const wrapper = found?.parsed.wrapper
+  const overrides = diffLocalPolicy(orgPolicyCResult.data, {
+    policy: policyCResult.data,
+    wrapper: found && wrapper ? { path: found.path, wrapper } : undefined,
+  })
+  return {
+    ok: true,
+    data: {
+      org: orgPolicyCResult.data.org,
+      ...(orgPolicyPath ? { orgPolicyPath } : {}),
+      overrides,
+      weakened: overrides.filter(o => o.effect === 'weakened').length,
+    },
+  }
+}
+
+export async function handlePolicyDiff({
+  cwd,
+  orgPolicyPath,
+  orgSlug,
+  outputKind,
+}: {
+  cwd: string
+  orgPolicyPath: string | undefined
+  orgSlug: string
+  outputKind: OutputKind
+}): Promise<void> {
+  await outputPolicyDiff(
+    await getPolicyDiff({ cwd, orgPolicyPath, orgSlug }),
+    outputKind,
+  )
+}
@@ -0,0 +1,62 @@
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+
+import { outputPolicyLint } from './output-policy-lint.mts'
+import { SOCKET_POLICY_YML } from '../../constants/socket.mts'
+import { lintSocketPolicy } from '../../util/policy/socket-policy-lint.mts'
+import { findSocketPolicyPathSync } from '../../util/policy/socket-policy.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { PolicyLintIssue } from '../../util/policy/socket-policy-lint.mts'
+
+export type PolicyLintReport = {
+  issues: PolicyLintIssue[]
+  path: string
+}
+
+export function lintPolicyFile(
+  filepath: string | undefined,
+  cwd: string,
+): CResult<PolicyLintReport> {
+  const policyPath = filepath ?? findSocketPolicyPathSync(cwd)
+  if (!policyPath) {
+    return {
+      ok: false,
+      message: 'No policy file found',
+      cause: `There is no ${SOCKET_POLICY_YML} in ${cwd} or its parent directories`,
+    }
+  }
+  const content = safeReadFileSync(policyPath)
+  if (content === undefined) {
+    return {
+      ok: false,
+      message: 'Policy file not found',
+      cause: `Unable to read ${policyPath}`,
+    }
+  }
+  const { issues } = lintSocketPolicy(
+    Buffer.isBuffer(content) ? content.toString('utf8') : content,
+  )
+  const report = { issues, path: policyPath }
+  const errorCount = issues.filter(issue => issue.level === 'error').length
+  if (errorCount) {
+    return {
+      ok: false,
+      message: 'Policy file has errors',
+      cause: `${policyPath} has ${errorCount} error${errorCount === 1 ? '' : 's'}`,
+      data: report,
+    }
+  }
+  return { ok: true, data: report }
+}
+
+export async function handlePolicyLint({
+  cwd,
+  filepath,
+  outputKind,
+}: {
+  cwd: string
+  filepath: string | undefined
+  outputKind: OutputKind
+}): Promise<void> {
+  await outputPolicyLint(lintPolicyFile(filepath, cwd), outputKind)
+}
@@ -0,0 +1,57 @@
+import { promises as fs } from 'node:fs'
+
+import { outputPolicyPull } from './output-policy-pull.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import { createSocketOrgPolicy } from '../../util/policy/org-policy.mts'
+import { fetchSecurityPolicy } from '../organization/fetch-security-policy.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type PolicyPullResult = {
+  org: string
+  path: string
+  rules: number
+}
+
+export async function handlePolicyPull({
+  filepath,
+  orgSlug,
+  outputKind,
+}: {
+  filepath: string
+  orgSlug: string
+  outputKind: OutputKind
+}): Promise<void> {
+  const policyCResult = await fetchSecurityPolicy(orgSlug, {
+    commandPath: 'socket policy pull',
+  })
+  if (!policyCResult.ok) {
+    await outputPolicyPull(policyCResult, outputKind)
+    return
+  }
+
+  const orgPolicy = createSocketOrgPolicy(orgSlug, policyCResult.data)
+  let result: CResult<PolicyPullResult>
+  try {
+    await fs.writeFile(
+      filepath,
+      `${JSON.stringify(orgPolicy, null, 2)}\n`,
+      'utf8',
+    )
+    result = {
+      ok: true,
+      data: {
+        org: orgSlug,
+        path: filepath,
+        rules: Object.keys(orgPolicy.rules).length,
+      },
+    }
+  } catch (e) {
+    result = {
+      ok: false,
+      message: 'Failed to write org policy file',
+      cause: `Unable to write ${filepath}: ${getErrorCause(e)}`,
+    }
+  }
+  await outputPolicyPull(result, outputKind)
+}
@@ -0,0 +1,82 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { PolicyDiffReport } from './handle-policy-diff.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { PolicyOverride } from '../../util/policy/org-policy.mts'
+
+const logger = getDefaultLogger()
+
+export function formatPolicyOverride(override: PolicyOverride): string {
+  const { alert, local, org = 'default', scope, source } = override
+  return `${alert}: ${local} (org: ${org})${scope ? ` for ${scope}` : ''}, from ${source}`
+}
+
+export async function outputPolicyDiff(
+  result: CResult<PolicyDiffReport>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { org, orgPolicyPath, overrides, weakened } = result.data
+  const against = orgPolicyPath
+    ? `the ${org} security policy in ${orgPolicyPath}`
+    : `the ${org} security policy`
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Policy Diff'))
+    logger.log('')
+    logger.log(`Compared against ${against}.`)
+    logger.log('')
+    logger.log(
+      overrides.length
+        ? mdTable(
+            overrides.map(o => ({
+              alert: o.alert,
+              effect: o.effect,
+              local: o.local,
+              org: o.org ?? 'default',
+              scope: o.scope ?? '',
+              source: o.source,
+            })),
+            ['alert', 'effect', 'local', 'org', 'scope', 'source'],
+            ['Alert', 'Effect', 'Local', 'Org', 'Scope', 'Source'],
+          )
+        : 'No local overrides.',
+    )
+    return
+  }
+
+  if (!overrides.length) {
+    logger.success(`The repo follows ${against}`)
+    return
+  }
+  for (let i = 0, { length } = overrides; i < length; i += 1) {
+    const override = overrides[i]!
+    const message = `${override.effect}: ${formatPolicyOverride(override)}`
+    if (override.effect === 'weakened') {
+      logger.warn(message)
+    } else {
+      logger.info(message)
+    }
+  }
+  logger.log('')
+  logger.log(
+    `${overrides.length} local ${pluralize('override', { count: overrides.length })} of ${against}, ${weakened} weakened`,
+  )
+}
@@ -0,0 +1,72 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+import { formatPolicyLintIssue } from '../../util/policy/socket-policy-lint.mts'
+
+import type { PolicyLintReport } from './handle-policy-lint.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputPolicyLint(
+  result: CResult<PolicyLintReport>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+
+  const report = result.ok
+    ? result.data
+    : (result.data as PolicyLintReport | undefined)
+
+  if (outputKind === 'markdown') {
+    if (!report) {
+      logger.fail(failMsgWithBadge(result.message, result.cause))
+      return
+    }
+    logger.log(mdHeader('Policy Lint'))
+    logger.log('')
+    logger.log(`File: \`${report.path}\``)
+    logger.log('')
+    logger.log(
+      report.issues.length
+        ? mdList(
+            report.issues.map(
+              issue => `**${issue.level}**: ${formatPolicyLintIssue(issue)}`,
+            ),
+          )
+        : 'No problems found.',
+    )
+    return
+  }
+
+  if (report) {
+    const { issues } = report
+    for (let i = 0, { length } = issues; i < length; i += 1) {
+      const issue = issues[i]!
+      const message = formatPolicyLintIssue(issue)
+      if (issue.level === 'error') {
+        logger.error(message)
+      } else {
+        logger.warn(message)
+      }
+    }
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+  logger.success(
+    result.data.issues.length
+      ? `${result.data.path} is valid, with warnings`
+      : `${result.data.path} is valid`,
+  )
+}
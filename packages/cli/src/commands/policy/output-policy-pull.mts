@@ -0,0 +1,40 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { PolicyPullResult } from './handle-policy-pull.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputPolicyPull(
+  result: CResult<PolicyPullResult>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { org, path, rules } = result.data
+  const summary = `Wrote ${rules} ${pluralize('rule', { count: rules })} of the ${org} security policy to ${path}`
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Org Policy'))
+    logger.log('')
+    logger.log(summary)
+    return
+  }
+  logger.success(summary)
+}
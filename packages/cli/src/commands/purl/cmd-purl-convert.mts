@@ -0,0 +1,110 @@
+import { handlePurlConvert } from './handle-purl-convert.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+const config = {
+  commandName: 'convert',
+  description: 'Convert between ecosystem coordinates and purls',
+  flags: defineFlags({
+    ...commonFlags,
+    ...outputFlags,
+    input: {
+      type: 'string',
+      default: '',
+      description:
+        'Read one purl or coordinate per line from this file, or - to read them from stdin',
+    },
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <PURL|COORDINATE...>
+      $ ${command} [options] --input <FILE|->
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Each coordinate becomes a purl and each purl a coordinate, one result per
+    line. Coordinates name the ecosystem, a colon and the package the way its
+    tooling writes it:
+
+      maven:<group>:<artifact>[:<version>]
+      pypi:<name>[==<version>]
+      npm:[@<scope>/]<name>[@<version>]
+      golang:<module>[@<version>]
+
+    Other ecosystems use <ecosystem>:[<namespace>/]<name>[@<version>]. Purl
+    qualifiers and subpaths have no coordinate form and are dropped.
+
+    Examples
+      $ ${command} maven:org.apache.commons:commons-lang3:3.14.0
+      $ ${command} pkg:maven/org.apache.commons/commons-lang3@3.14.0
+      $ ${command} --input deps.txt --json
+  `,
+  hidden: false,
+}
+
+export const cmdPurlConvert = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { input, json, markdown } = cli.flags as {
+    input: string
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!input || cli.input.length > 0,
+      message: 'At least one purl or coordinate, or --input',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !input || !cli.input.length,
+      message: 'The --input flag replaces the arguments',
+      fail: 'omit the arguments or --input',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  await handlePurlConvert({
+    args: cli.input,
+    cwd: process.cwd(),
+    input,
+    outputKind,
+  })
+}
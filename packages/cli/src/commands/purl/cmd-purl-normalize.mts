@@ -0,0 +1,102 @@
+import { handlePurlNormalize } from './handle-purl-normalize.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+const config = {
+  commandName: 'normalize',
+  description: 'Normalize a list of purls and coordinates',
+  flags: defineFlags({
+    ...commonFlags,
+    ...outputFlags,
+    input: {
+      type: 'string',
+      default: '',
+      description:
+        'Read one purl or coordinate per line from this file, or - to read them from stdin',
+    },
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <PURL|COORDINATE...>
+      $ ${command} [options] --input <FILE|->
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Writes the canonical form of every purl and coordinate, one per line and
+    each once, ready for \`socket package score --input -\`. Nothing is
+    written when an entry is not valid, the command exits with code 1 and
+    lists the bad entries instead.
+
+    Examples
+      $ ${command} npm/lodash@4.17.21 maven:com.google.guava:guava:33.0.0-jre
+      $ cat deps.txt | ${command} --input - | socket package score --input -
+  `,
+  hidden: false,
+}
+
+export const cmdPurlNormalize = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { input, json, markdown } = cli.flags as {
+    input: string
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!input || cli.input.length > 0,
+      message: 'At least one purl or coordinate, or --input',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !input || !cli.input.length,
+      message: 'The --input flag replaces the arguments',
+      fail: 'omit the arguments or --input',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  await handlePurlNormalize({
+    args: cli.input,
+    cwd: process.cwd(),
+    input,
+    outputKind,
+  })
+}
@@ -0,0 +1,101 @@
+import { handlePurlParse } from './handle-purl-parse.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+const config = {
+  commandName: 'parse',
+  description: 'Validate purls and show their components',
+  flags: defineFlags({
+    ...commonFlags,
+    ...outputFlags,
+    input: {
+      type: 'string',
+      default: '',
+      description:
+        'Read one purl per line from this file, or - to read them from stdin',
+    },
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <PURL...>
+      $ ${command} [options] --input <FILE|->
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Prints the type, namespace, name, version, qualifiers and subpath of each
+    purl. The \`pkg:\` prefix is optional. Exits with code 1 when a purl is
+    not valid.
+
+    Examples
+      $ ${command} pkg:npm/%40babel/core@7.24.0
+      $ ${command} pypi/requests@2.31.0 --json
+  `,
+  hidden: false,
+}
+
+export const cmdPurlParse = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { input, json, markdown } = cli.flags as {
+    input: string
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!input || cli.input.length > 0,
+      message: 'At least one purl, or --input',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !input || !cli.input.length,
+      message: 'The --input flag replaces the arguments',
+      fail: 'omit the arguments or --input',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  await handlePurlParse({
+    args: cli.input,
+    cwd: process.cwd(),
+    input,
+    outputKind,
+  })
+}
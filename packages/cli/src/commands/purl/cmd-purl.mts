@@ -0,0 +1,14 @@
+import { cmdPurlConvert } from './cmd-purl-convert.mts'
+import { cmdPurlNormalize } from './cmd-purl-normalize.mts'
+import { cmdPurlParse } from './cmd-purl-parse.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdPurl = defineSubcommandGroup({
+  name: 'purl',
+  description: 'Parse, normalize and convert package URLs',
+  subcommands: {
+    convert: cmdPurlConvert,
+    normalize: cmdPurlNormalize,
+    parse: cmdPurlParse,
+  },
+})
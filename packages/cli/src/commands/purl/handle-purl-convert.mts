@@ -0,0 +1,78 @@
+import { outputPurlConvert } from './output-purl-convert.mts'
+import { readPurlInputs } from './purl-input.mts'
+import {
+  coordinateToPurl,
+  isCoordinate,
+  parsePurlParts,
+  purlToCoordinate,
+} from '../../util/purl/coordinates.mts'
+
+import type { PurlInputEntry, PurlInputIssue } from './purl-input.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type PurlConversion = {
+  from: string
+  to: string
+}
+
+export type PurlConvertReport = {
+  conversions: PurlConversion[]
+  invalid: PurlInputIssue[]
+}
+
+/**
+ * Turn coordinates into purls and purls into coordinates, whichever each
+ * entry is.
+ */
+export function getPurlConvertReport(
+  entries: readonly PurlInputEntry[],
+): CResult<PurlConvertReport> {
+  const report: PurlConvertReport = { conversions: [], invalid: [] }
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const entry = entries[i]!
+    const { text } = entry
+    let converted: CResult<string>
+    if (isCoordinate(text)) {
+      converted = coordinateToPurl(text)
+    } else {
+      const partsCResult = parsePurlParts(text)
+      converted = partsCResult.ok
+        ? { ok: true, data: purlToCoordinate(partsCResult.data) }
+        : partsCResult
+    }
+    if (converted.ok) {
+      report.conversions.push({ from: text, to: converted.data })
+    } else {
+      report.invalid.push({ ...entry, cause: converted.cause ?? '' })
+    }
+  }
+  if (report.invalid.length) {
+    return {
+      ok: false,
+      message: 'Unable to convert every entry',
+      cause: `${report.invalid.length} of ${entries.length} entries are not valid purls or coordinates`,
+      data: report,
+    }
+  }
+  return { ok: true, data: report }
+}
+
+export async function handlePurlConvert({
+  args,
+  cwd,
+  input,
+  outputKind,
+}: {
+  args: readonly string[]
+  cwd: string
+  input: string
+  outputKind: OutputKind
+}): Promise<void> {
+  const entriesCResult = await readPurlInputs({ args, cwd, input })
+  await outputPurlConvert(
+    entriesCResult.ok
+      ? getPurlConvertReport(entriesCResult.data)
+      : entriesCResult,
+    outputKind,
+  )
+}
@@ -0,0 +1,58 @@
+import { outputPurlNormalize } from './output-purl-normalize.mts'
+import { readPurlInputs } from './purl-input.mts'
+import { normalizePurlOrCoordinate } from '../../util/purl/coordinates.mts'
+
+import type { PurlInputEntry, PurlInputIssue } from './purl-input.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type PurlNormalizeReport = {
+  invalid: PurlInputIssue[]
+  // Canonical purls in input order, each listed once.
+  purls: string[]
+}
+
+export function getPurlNormalizeReport(
+  entries: readonly PurlInputEntry[],
+): CResult<PurlNormalizeReport> {
+  const invalid: PurlInputIssue[] = []
+  const purls = new Set<string>()
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const entry = entries[i]!
+    const purlCResult = normalizePurlOrCoordinate(entry.text)
+    if (purlCResult.ok) {
+      purls.add(purlCResult.data)
+    } else {
+      invalid.push({ ...entry, cause: purlCResult.cause ?? '' })
+    }
+  }
+  const report = { invalid, purls: [...purls] }
+  if (invalid.length) {
+    return {
+      ok: false,
+      message: 'Unable to normalize every entry',
+      cause: `${invalid.length} of ${entries.length} entries are not valid purls or coordinates`,
+      data: report,
+    }
+  }
+  return { ok: true, data: report }
+}
+
+export async function handlePurlNormalize({
+  args,
+  cwd,
+  input,
+  outputKind,
+}: {
+  args: readonly string[]
+  cwd: string
+  input: string
+  outputKind: OutputKind
+}): Promise<void> {
+  const entriesCResult = await readPurlInputs({ args, cwd, input })
+  await outputPurlNormalize(
+    entriesCResult.ok
+      ? getPurlNormalizeReport(entriesCResult.data)
+      : entriesCResult,
+    outputKind,
+  )
+}
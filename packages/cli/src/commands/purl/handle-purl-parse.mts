@@ -0,0 +1,56 @@
+import { outputPurlParse } from './output-purl-parse.mts'
+import { readPurlInputs } from './purl-input.mts'
+import { parsePurlParts } from '../../util/purl/coordinates.mts'
+
+import type { PurlInputEntry, PurlInputIssue } from './purl-input.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { PurlParts } from '../../util/purl/coordinates.mts'
+
+export type PurlParseReport = {
+  invalid: PurlInputIssue[]
+  purls: PurlParts[]
+}
+
+export function getPurlParseReport(
+  entries: readonly PurlInputEntry[],
+): CResult<PurlParseReport> {
+  const report: PurlParseReport = { invalid: [], purls: [] }
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const entry = entries[i]!
+    const partsCResult = parsePurlParts(entry.text)
+    if (partsCResult.ok) {
+      report.purls.push(partsCResult.data)
+    } else {
+      report.invalid.push({ ...entry, cause: partsCResult.cause ?? '' })
+    }
+  }
+  if (report.invalid.length) {
+    return {
+      ok: false,
+      message: 'Invalid purls',
+      cause: `${report.invalid.length} of ${entries.length} purls are not valid`,
+      data: report,
+    }
+  }
+  return { ok: true, data: report }
+}
+
+export async function handlePurlParse({
+  args,
+  cwd,
+  input,
+  outputKind,
+}: {
+  args: readonly string[]
+  cwd: string
+  input: string
+  outputKind: OutputKind
+}): Promise<void> {
+  const entriesCResult = await readPurlInputs({ args, cwd, input })
+  await outputPurlParse(
+    entriesCResult.ok
+      ? getPurlParseReport(entriesCResult.data)
+      : entriesCResult,
+    outputKind,
+  )
+}
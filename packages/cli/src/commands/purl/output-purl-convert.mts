@@ -0,0 +1,60 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { formatPurlInputIssue } from './purl-input.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { PurlConvertReport } from './handle-purl-convert.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputPurlConvert(
+  result: CResult<PurlConvertReport>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+
+  const report = result.ok
+    ? result.data
+    : (result.data as PurlConvertReport | undefined)
+
+  if (outputKind === 'markdown') {
+    if (!report) {
+      logger.fail(failMsgWithBadge(result.message, result.cause))
+      return
+    }
+    logger.log(mdHeader('Purl Conversion'))
+    logger.log('')
+    if (report.conversions.length) {
+      logger.log(mdTable(report.conversions, ['from', 'to'], ['From', 'To']))
+    }
+    if (report.invalid.length) {
+      logger.log('')
+      logger.log(mdHeader('Invalid', 2))
+      logger.log('')
+      logger.log(mdList(report.invalid.map(formatPurlInputIssue)))
+    }
+    return
+  }
+
+  if (!result.ok) {
+    for (const issue of report?.invalid ?? []) {
+      logger.error(formatPurlInputIssue(issue))
+    }
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+  // One result per line, so the output can be piped on.
+  for (const { to } of result.data.conversions) {
+    logger.log(to)
+  }
+}
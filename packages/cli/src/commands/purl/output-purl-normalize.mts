@@ -0,0 +1,61 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { formatPurlInputIssue } from './purl-input.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { PurlNormalizeReport } from './handle-purl-normalize.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputPurlNormalize(
+  result: CResult<PurlNormalizeReport>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+
+  const report = result.ok
+    ? result.data
+    : (result.data as PurlNormalizeReport | undefined)
+
+  if (outputKind === 'markdown') {
+    if (!report) {
+      logger.fail(failMsgWithBadge(result.message, result.cause))
+      return
+    }
+    logger.log(mdHeader('Normalized Purls'))
+    logger.log('')
+    if (report.purls.length) {
+      logger.log(mdList(report.purls.map(purl => `\`${purl}\``)))
+    }
+    if (report.invalid.length) {
+      logger.log('')
+      logger.log(mdHeader('Invalid', 2))
+      logger.log('')
+      logger.log(mdList(report.invalid.map(formatPurlInputIssue)))
+    }
+    return
+  }
+
+  // A partial list is not printed, `socket package score --input -` would
+  // quietly skip the entries that failed.
+  if (!result.ok) {
+    for (const issue of report?.invalid ?? []) {
+      logger.error(formatPurlInputIssue(issue))
+    }
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+  for (const purl of result.data.purls) {
+    logger.log(purl)
+  }
+}
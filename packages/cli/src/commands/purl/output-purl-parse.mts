@@ -0,0 +1,112 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { formatPurlInputIssue } from './purl-input.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { PurlParseReport } from './handle-purl-parse.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { PurlParts } from '../../util/purl/coordinates.mts'
+
+const logger = getDefaultLogger()
+
+function formatQualifiers(parts: PurlParts): string {
+  return Object.entries(parts.qualifiers ?? {})
+    .map(({ 0: key, 1: value }) => `${key}=${value}`)
+    .join('&')
+}
+
+export async function outputPurlParse(
+  result: CResult<PurlParseReport>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+
+  const report = result.ok
+    ? result.data
+    : (result.data as PurlParseReport | undefined)
+
+  if (outputKind === 'markdown') {
+    if (!report) {
+      logger.fail(failMsgWithBadge(result.message, result.cause))
+      return
+    }
+    logger.log(mdHeader('Purls'))
+    logger.log('')
+    if (report.purls.length) {
+      logger.log(
+        mdTable(
+          report.purls.map(parts => ({
+            name: parts.name,
+            namespace: parts.namespace ?? '',
+            purl: parts.purl,
+            qualifiers: formatQualifiers(parts),
+            subpath: parts.subpath ?? '',
+            type: parts.type,
+            version: parts.version ?? '',
+          })),
+          [
+            'purl',
+            'type',
+            'namespace',
+            'name',
+            'version',
+            'qualifiers',
+            'subpath',
+          ],
+          [
+            'Purl',
+            'Type',
+            'Namespace',
+            'Name',
+            'Version',
+            'Qualifiers',
+            'Subpath',
+          ],
+        ),
+      )
+    }
+    if (report.invalid.length) {
+      logger.log('')
+      logger.log(mdHeader('Invalid', 2))
+      logger.log('')
+      logger.log(mdList(report.invalid.map(formatPurlInputIssue)))
+    }
+    return
+  }
+
+  if (report) {
+    const { invalid, purls } = report
+    for (let i = 0, { length } = purls; i < length; i += 1) {
+      const parts = purls[i]!
+      const qualifiers = formatQualifiers(parts)
+      logger.log(parts.purl)
+      for (const { 0: label, 1: value } of [
+        ['type', parts.type],
+        ['namespace', parts.namespace],
+        ['name', parts.name],
+        ['version', parts.version],
+        ['qualifiers', qualifiers],
+        ['subpath', parts.subpath],
+      ]) {
+        if (value) {
+          logger.log(`  ${label}: ${value}`)
+        }
+      }
+    }
+    for (let i = 0, { length } = invalid; i < length; i += 1) {
+      logger.error(formatPurlInputIssue(invalid[i]!))
+    }
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+  }
+}
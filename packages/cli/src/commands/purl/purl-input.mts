@@ -0,0 +1,76 @@
+import { existsSync, promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import type { CResult } from '../../types.mts'
+
+export type PurlInputEntry = {
+  // Line of the --input list the entry came from.
+  line?: number | undefined
+  text: string
+}
+
+export type PurlInputIssue = PurlInputEntry & {
+  cause: string
+}
+
+async function readStdin(): Promise<string> {
+  let input = ''
+  for await (const chunk of process.stdin) {
+    input += chunk
+  }
+  return input
+}
+
+/**
+ * The purls or coordinates to work on: the command arguments, or one per
+ * line of the --input file or stdin. Blank lines and `#` comments are
+ * skipped, like the lists `socket package score --input` reads.
+ */
+export async function readPurlInputs({
+  args,
+  cwd,
+  input,
+}: {
+  args: readonly string[]
+  cwd: string
+  input: string
+}): Promise<CResult<PurlInputEntry[]>> {
+  if (!input) {
+    return { ok: true, data: args.map(text => ({ text })) }
+  }
+  let text: string
+  if (input === '-') {
+    if (process.stdin.isTTY) {
+      return {
+        ok: false,
+        message: 'No purls given',
+        cause: 'Pipe the list to stdin, one purl or coordinate per line',
+      }
+    }
+    text = await readStdin()
+  } else {
+    const filepath = path.resolve(cwd, input)
+    if (!existsSync(filepath)) {
+      return {
+        ok: false,
+        message: 'Purl list not found',
+        cause: `There is no file at ${filepath}`,
+      }
+    }
+    text = await fs.readFile(filepath, 'utf8')
+  }
+  const entries: PurlInputEntry[] = []
+  const lines = text.split(/\r?\n/)
+  for (let i = 0, { length } = lines; i < length; i += 1) {
+    const entry = lines[i]!.replace(/\s+#.*$/, '').trim()
+    if (entry && !entry.startsWith('#')) {
+      entries.push({ line: i + 1, text: entry })
+    }
+  }
+  return { ok: true, data: entries }
+}
+
+export function formatPurlInputIssue(issue: PurlInputIssue): string {
+  const where = issue.line === undefined ? '' : `line ${issue.line}: `
+  return `${where}${issue.text}: ${issue.cause}`
+}
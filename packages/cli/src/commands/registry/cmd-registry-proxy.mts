@@ -0,0 +1,161 @@
+import { NPM_REGISTRY_URL } from '@socketsecurity/lib-stable/constants/agents'
+import { isUrl } from '@socketsecurity/lib-stable/url/predicates'
+
+import { handleRegistryProxy } from './handle-registry-proxy.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'proxy'
+
+const description =
+  'Run a local npm registry that refuses packages failing Socket policy'
+
+const hidden = false
+
+export const cmdRegistryProxy: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      host: {
+        type: 'string',
+        default: '127.0.0.1',
+        description: 'Address to listen on',
+      },
+      port: {
+        type: 'number',
+        default: 8765,
+        description: 'Port to listen on, 0 picks a free one',
+      },
+      quarantine: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Also hide failing versions from package metadata so installs resolve to a clean version',
+      },
+      upstream: {
+        type: 'string',
+        default: NPM_REGISTRY_URL,
+        description: 'Registry to forward requests to',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Serves an npm-compatible registry that forwards to --upstream. Package
+    metadata has its tarball URLs pointed at the proxy, and each tarball is
+    checked against Socket before it is served, so every tool that talks to
+    the registry is protected, not just \`socket npm\`.
+
+    Packages with an alert whose org security policy action is "error", and
+    malware, are refused with a 403. A socket.policy.yml found from the
+    working directory overrides the org policy. Packages that can not be
+    checked are refused too. With --quarantine, refused versions are also
+    dropped from later metadata responses and dist-tags like "latest" move
+    to the newest older version.
+
+    Point package managers at the printed URL, for example with
+    \`npm config set registry http://127.0.0.1:8765/\`, or the registry
+    setting of pnpm and yarn.
+
+    With --json every checked package prints one line of JSON.
+
+    Examples
+      $ ${command}
+      $ ${command} --port 4873 --quarantine
+      $ ${command} --upstream https://npm.corp.example/ --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, host, json, markdown, port, quarantine, upstream } =
+    cli.flags as {
+      dryRun: boolean
+      host: string
+      json: boolean
+      markdown: boolean
+      port: number
+      quarantine: boolean
+      upstream: string
+    }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: Number.isInteger(port) && port >= 0 && port <= 65_535,
+      message: 'The --port flag must be a port number from 0 to 65535',
+      fail: `got ${port}`,
+    },
+    {
+      nook: true,
+      test: isUrl(upstream) && /^https?:/.test(upstream),
+      message: 'The --upstream flag must be an http or https registry URL',
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('package verdicts for a local registry proxy', {
+      listen: `${host}:${port}`,
+      quarantine: quarantine ? 'yes' : 'no',
+      upstream,
+    })
+    return
+  }
+
+  await handleRegistryProxy({
+    cwd: process.cwd(),
+    host,
+    outputKind,
+    port,
+    quarantine,
+    upstreamUrl: upstream,
+  })
+}
@@ -0,0 +1,24 @@
+import { cmdRegistryProxy } from './cmd-registry-proxy.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Protect package registry traffic with Socket'
+
+export const cmdRegistry: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} registry`,
+        importMeta,
+        subcommands: {
+          proxy: cmdRegistryProxy,
+        },
+      },
+      { description },
+    )
+  },
+}
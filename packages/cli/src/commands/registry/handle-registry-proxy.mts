@@ -0,0 +1,151 @@
+import { SOCKET_PUBLIC_API_TOKEN } from '@socketsecurity/lib-stable/constants/socket'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { outputRegistryProxyEvent } from './output-registry-proxy.mts'
+import { createRegistryProxy } from './registry-proxy.mts'
+import { summarizeInstallRisks } from '../../util/install-check/check.mts'
+import { fetchPublishTimes } from '../../util/install-check/publish-time.mts'
+import { findSocketPolicySync } from '../../util/policy/socket-policy.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+import { getDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { fetchPurlsShallowScore } from '../package/fetch-purls-shallow-score.mts'
+
+import type {
+  RegistryPackage,
+  RegistryPackageChecker,
+  RegistryPackageVerdict,
+} from './registry-proxy.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { SocketPolicy } from '../../util/policy/socket-policy.mts'
+
+const logger = getDefaultLogger()
+
+export type HandleRegistryProxyConfig = {
+  cwd: string
+  host: string
+  outputKind: OutputKind
+  port: number
+  quarantine: boolean
+  // Stops the proxy. Without it the command runs until interrupted.
+  signal?: AbortSignal | undefined
+  upstreamUrl: string
+}
+
+function getNpmPurl({ name, version }: RegistryPackage): string {
+  const slash = name.indexOf('/')
+  return getArtifactPurlString({
+    name: slash === -1 ? name : name.slice(slash + 1),
+    namespace: slash === -1 ? undefined : name.slice(0, slash),
+    type: 'npm',
+    version,
+  } as SocketArtifact)
+}
+
+/**
+ * Judge packages the way `socket pip install` and friends do: by the org
+ * policy action of their alerts, overridden by a repo socket.policy.yml.
+ */
+export function createRegistryPackageChecker(
+  localPolicy: SocketPolicy | undefined,
+): RegistryPackageChecker {
+  return async packages => {
+    const purls = packages.map(getNpmPurl)
+    const scoreCResult = await fetchPurlsShallowScore(purls, {
+      commandPath: 'socket registry proxy',
+      // Unauthenticated users still get malware verdicts from the public token.
+      sdkOpts: { apiToken: getDefaultApiToken() || SOCKET_PUBLIC_API_TOKEN },
+    })
+    if (!scoreCResult.ok) {
+      return scoreCResult
+    }
+    const artifacts = scoreCResult.data as unknown as SocketArtifact[]
+    const summary = summarizeInstallRisks(
+      artifacts,
+      [],
+      localPolicy,
+      undefined,
+      localPolicy?.quarantine ? await fetchPublishTimes(artifacts) : undefined,
+    )
+    const verdicts = new Map<string, RegistryPackageVerdict>()
+    for (const risk of summary.blocked) {
+      verdicts.set(risk.purl, { action: 'block', ...risk })
+    }
+    for (const risk of summary.warned) {
+      verdicts.set(risk.purl, { action: 'warn', ...risk })
+    }
+    return {
+      ok: true,
+      data: purls.map(
+        purl => verdicts.get(purl) ?? { action: 'allow', alerts: [], purl },
+      ),
+    }
+  }
+}
+
+export async function handleRegistryProxy({
+  cwd,
+  host,
+  outputKind,
+  port,
+  quarantine,
+  signal,
+  upstreamUrl,
+}: HandleRegistryProxyConfig): Promise<void> {
+  const policyCResult = findSocketPolicySync(cwd)
+  if (!policyCResult.ok) {
+    outputRegistryProxyEvent(policyCResult, outputKind)
+    return
+  }
+
+  const server = createRegistryProxy({
+    checkPackages: createRegistryPackageChecker(policyCResult.data?.policy),
+    onEvent: event =>
+      outputRegistryProxyEvent({ ok: true, data: event }, outputKind),
+    quarantine,
+    upstreamUrl,
+  })
+
+  const listenCResult = await new Promise<CResult<string>>(resolve => {
+    server.once('error', e =>
+      resolve({
+        ok: false,
+        message: 'Unable to start the registry proxy',
+        cause: (e as Error).message,
+      }),
+    )
+    server.listen(port, host, () => {
+      const address = server.address()
+      const boundPort =
+        address && typeof address === 'object' ? address.port : port
+      const boundHost = host.includes(':') ? `[${host}]` : host
+      resolve({ ok: true, data: `http://${boundHost}:${boundPort}/` })
+    })
+  })
+  if (!listenCResult.ok) {
+    outputRegistryProxyEvent(listenCResult, outputKind)
+    return
+  }
+
+  const proxyUrl = listenCResult.data
+  logger.info(
+    `Socket registry proxy listening on ${proxyUrl}, forwarding to ${upstreamUrl}`,
+  )
+  logger.info(
+    `Point npm at it with \`npm config set registry ${proxyUrl}\`. Press Ctrl+C to stop.`,
+  )
+
+  await new Promise<void>(resolve => {
+    server.on('close', resolve)
+    const stop = () => {
+      server.close()
+      // Keep-alive connections of the package manager would hold it open.
+      server.closeAllConnections()
+    }
+    if (signal?.aborted) {
+      stop()
+    } else {
+      signal?.addEventListener('abort', stop, { once: true })
+    }
+  })
+}
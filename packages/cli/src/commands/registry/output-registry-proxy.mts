@@ -0,0 +1,51 @@
+import { debugNs } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { formatInstallRisk } from '../../util/install-check/check.mts'
+import { toJsonEnvelope } from '../../util/output/json-envelope.mts'
+
+import type { RegistryProxyEvent } from './registry-proxy.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export function outputRegistryProxyEvent(
+  result: CResult<RegistryProxyEvent>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  // Each event is one line of JSON so the stream can be consumed as NDJSON.
+  if (outputKind === 'json') {
+    logger.log(JSON.stringify(toJsonEnvelope(result)))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const event = result.data
+  const risk = formatInstallRisk(event)
+  switch (event.action) {
+    case 'block':
+      logger.fail(`Blocked ${risk}`)
+      break
+    case 'error':
+      logger.fail(
+        `Refused ${event.purl}, it could not be checked: ${event.reason}`,
+      )
+      break
+    case 'quarantine':
+      logger.warn(`Quarantined ${risk}`)
+      break
+    case 'warn':
+      logger.warn(`Allowed ${risk}`)
+      break
+    default:
+      debugNs('notice', `Allowed ${event.purl}`)
+  }
+}
@@ -0,0 +1,459 @@
+/**
+ * A local npm-compatible registry that forwards to an upstream registry and
+ * checks every package tarball against Socket before serving it.
+ *
+ * Package metadata (packuments) passes through with its tarball URLs pointed
+ * back at the proxy, so clients download every tarball through it. A tarball
+ * that fails the policy is refused with a 403, whose `error` field npm, pnpm
+ * and yarn print. With quarantine on, failing versions are also dropped from
+ * later metadata responses and dist-tags move off them to the newest
+ * remaining version, so resolvers pick a clean version instead of failing
+ * the install. Every other request, e.g. audit, search or publish, goes
+ * upstream unchanged.
+ */
+
+import { createServer, request as httpRequest } from 'node:http'
+import { request as httpsRequest } from 'node:https'
+
+import { compareVersions, isPrerelease } from '../../util/semver.mts'
+import { getProxyAgent, isNoProxyUrl } from '../../util/socket/network.mts'
+import { getDefaultProxyUrl, getExtraCaCerts } from '../../util/socket/sdk.mjs'
+
+import type { CResult } from '../../types.mts'
+import type { InstallRiskAlert } from '../../util/install-check/check.mts'
+import type {
+  IncomingHttpHeaders,
+  IncomingMessage,
+  Server,
+  ServerResponse,
+} from 'node:http'
+
+export type RegistryPackage = {
+  name: string
+  version: string
+}
+
+export type RegistryPackageVerdict = {
+  action: 'allow' | 'block' | 'warn'
+  alerts: InstallRiskAlert[]
+  purl: string
+}
+
+// Resolves one verdict per package, in the order they were passed.
+export type RegistryPackageChecker = (
+  packages: RegistryPackage[],
+) => Promise<CResult<RegistryPackageVerdict[]>>
+
+export type RegistryProxyEvent = {
+  // `error` means the package could not be checked and was refused.
+  action: 'allow' | 'block' | 'error' | 'quarantine' | 'warn'
+  alerts: InstallRiskAlert[]
+  purl: string
+  reason?: string | undefined
+}
+
+export type RegistryProxyOptions = {
+  checkPackages: RegistryPackageChecker
+  onEvent: (event: RegistryProxyEvent) => void
+  quarantine: boolean
+  upstreamUrl: string
+}
+
+export type NpmRegistryRequest =
+  | { kind: 'packument'; name: string }
+  | { kind: 'tarball'; name: string; version: string }
+
+type NpmPackument = {
+  'dist-tags'?: Record<string, string> | undefined
+  versions?: Record<string, { dist?: { tarball?: string } }> | undefined
+}
+
+// Headers that describe a single hop, or the client's view of the body.
+const HOP_HEADERS = new Set([
+  'connection',
+  'host',
+  'keep-alive',
+  'proxy-authorization',
+  'proxy-connection',
+  'transfer-encoding',
+  'upgrade',
+])
+
+function getPackageKey({ name, version }: RegistryPackage): string {
+  return `${name}@${version}`
+}
+
+function getForwardHeaders(
+  headers: IncomingHttpHeaders,
+  overrides: Record<string, string | undefined>,
+): Record<string, string | string[]> {
+  const forwarded: Record<string, string | string[]> = {}
+  for (const { 0: key, 1: value } of Object.entries(headers)) {
+    if (value !== undefined && !HOP_HEADERS.has(key) && !(key in overrides)) {
+      forwarded[key] = value
+    }
+  }
+  for (const { 0: key, 1: value } of Object.entries(overrides)) {
+    if (value !== undefined) {
+      forwarded[key] = value
+    }
+  }
+  return forwarded
+}
+
+function getResponseHeaders(
+  headers: IncomingHttpHeaders,
+  omit: readonly string[] = [],
+): Record<string, string | string[]> {
+  const result: Record<string, string | string[]> = {}
+  for (const { 0: key, 1: value } of Object.entries(headers)) {
+    if (value !== undefined && !HOP_HEADERS.has(key) && !omit.includes(key)) {
+      result[key] = value
+    }
+  }
+  return result
+}
+
+function readBody(stream: IncomingMessage): Promise<Buffer> {
+  return new Promise((resolve, reject) => {
+    const chunks: Buffer[] = []
+    stream.on('data', (chunk: Buffer) => chunks.push(chunk))
+    stream.on('end', () => resolve(Buffer.concat(chunks)))
+    stream.on('error', reject)
+  })
+}
+
+function requestUpstream(
+  req: IncomingMessage,
+  url: URL,
+  headers: Record<string, string | string[]>,
+): Promise<IncomingMessage> {
+  const proxyUrl = isNoProxyUrl(url.href) ? undefined : getDefaultProxyUrl()
+  const ca = getExtraCaCerts()
+  const request = url.protocol === 'http:' ? httpRequest : httpsRequest
+  return new Promise((resolve, reject) => {
+    const upstreamReq = request(url, {
+      headers,
+      method: req.method,
+      ...(proxyUrl ? { agent: getProxyAgent(url.href, proxyUrl, ca) } : {}),
+      ...(ca ? { ca } : {}),
+    })
+    upstreamReq.on('response', resolve)
+    upstreamReq.on('error', reject)
+    req.pipe(upstreamReq)
+  })
+}
+
+function writeRegistryError(
+  res: ServerResponse,
+  statusCode: number,
+  error: string,
+): void {
+  res.writeHead(statusCode, { 'content-type': 'application/json' })
+  res.end(JSON.stringify({ error }))
+}
+
+function formatAlerts(alerts: InstallRiskAlert[]): string {
+  return alerts.map(alert => `${alert.type} (${alert.severity})`).join(', ')
+}
+
+/**
+ * The package a registry path asks for: its packument, e.g. `/lodash` or
+ * `/@scope%2fname`, or a tarball, e.g. `/@scope/name/-/name-1.0.0.tgz`.
+ * Other paths, like `/-/npm/v1/security/audits`, resolve to undefined.
+ */
+export function parseNpmRegistryPath(
+  pathname: string,
+): NpmRegistryRequest | undefined {
+  let decoded: string
+  try {
+    decoded = decodeURIComponent(pathname)
+  } catch {
+    return undefined
+  }
+  const parts = decoded.split('/').filter(Boolean)
+  if (!parts.length || parts[0]!.startsWith('-')) {
+    return undefined
+  }
+  const nameLength = parts[0]!.startsWith('@') ? 2 : 1
+  if (parts.length < nameLength) {
+    return undefined
+  }
+  const name = parts.slice(0, nameLength).join('/')
+  const rest = parts.slice(nameLength)
+  if (!rest.length) {
+    return { kind: 'packument', name }
+  }
+  const file = rest[1]
+  const basename = name.slice(name.lastIndexOf('/') + 1)
+  if (
+    rest.length === 2 &&
+    rest[0] === '-' &&
+    file?.startsWith(`${basename}-`) &&
+    file.endsWith('.tgz')
+  ) {
+    return {
+      kind: 'tarball',
+      name,
+      version: file.slice(basename.length + 1, -'.tgz'.length),
+    }
+  }
+  return undefined
+}
+
+/**
+ * Point the tarball URLs of a packument at the proxy and drop quarantined
+ * versions, moving dist-tags that named them to the newest older version.
+ * Returns the versions that were dropped.
+ */
+export function rewritePackument(
+  packument: NpmPackument,
+  {
+    proxyBaseUrl,
+    quarantined = new Set(),
+    upstreamBaseUrl,
+  }: {
+    proxyBaseUrl: string
+    quarantined?: ReadonlySet<string> | undefined
+    upstreamBaseUrl: string
+  },
+): string[] {
+  const versions = packument.versions ?? {}
+  const removed: string[] = []
+  for (const version of Object.keys(versions)) {
+    if (quarantined.has(version)) {
+      delete versions[version]
+      removed.push(version)
+      continue
+    }
+    const dist = versions[version]?.dist
+    if (dist?.tarball?.startsWith(upstreamBaseUrl)) {
+      dist.tarball = `${proxyBaseUrl}${dist.tarball.slice(upstreamBaseUrl.length)}`
+    }
+  }
+  const distTags = packument['dist-tags']
+  if (removed.length && distTags) {
+    const remaining = Object.keys(versions).toSorted(compareVersions)
+    for (const { 0: tag, 1: target } of Object.entries(distTags)) {
+      if (!quarantined.has(target)) {
+        continue
+      }
+      // `latest` should not move to a prerelease.
+      const replacement = remaining.findLast(
+        version =>
+          compareVersions(version, target) < 0 &&
+          (isPrerelease(target) || !isPrerelease(version)),
+      )
+      if (replacement) {
+        distTags[tag] = replacement
+      } else {
+        delete distTags[tag]
+      }
+    }
+  }
+  return removed
+}
+
+export function createRegistryProxy({
+  checkPackages,
+  onEvent,
+  quarantine,
+  upstreamUrl,
+}: RegistryProxyOptions): Server {
+  const upstreamBaseUrl = upstreamUrl.endsWith('/')
+    ? upstreamUrl
+    : `${upstreamUrl}/`
+  // Verdicts are shared by concurrent requests for the same package.
+  const verdicts = new Map<string, Promise<CResult<RegistryPackageVerdict>>>()
+  // Quarantined versions by package name.
+  const quarantinedVersions = new Map<string, Set<string>>()
+
+  function quarantineVersion(
+    pkg: RegistryPackage,
+    verdict: RegistryPackageVerdict,
+  ): void {
+    let versions = quarantinedVersions.get(pkg.name)
+    if (!versions) {
+      versions = new Set()
+      quarantinedVersions.set(pkg.name, versions)
+    }
+    if (!versions.has(pkg.version)) {
+      versions.add(pkg.version)
+      onEvent({
+        action: 'quarantine',
+        alerts: verdict.alerts,
+        purl: verdict.purl,
+      })
+    }
+  }
+
+  async function getVerdicts(
+    packages: RegistryPackage[],
+  ): Promise<Array<CResult<RegistryPackageVerdict>>> {
+    const unchecked = packages.filter(pkg => !verdicts.has(getPackageKey(pkg)))
+    if (unchecked.length) {
+      const batch = checkPackages(unchecked)
+      for (let i = 0, { length } = unchecked; i < length; i += 1) {
+        const key = getPackageKey(unchecked[i]!)
+        const verdict = batch.then(
+          (result): CResult<RegistryPackageVerdict> =>
+            result.ok ? { ok: true, data: result.data[i]! } : result,
+        )
+        verdicts.set(key, verdict)
+        // Failed checks are retried by the next request.
+        void verdict.then(result => {
+          if (!result.ok) {
+            verdicts.delete(key)
+          }
+        })
+      }
+    }
+    return await Promise.all(
+      packages.map(pkg => verdicts.get(getPackageKey(pkg))!),
+    )
+  }
+
+  async function handleTarball(
+    pkg: RegistryPackage,
+    res: ServerResponse,
+  ): Promise<boolean> {
+    const { 0: result } = await getVerdicts([pkg])
+    if (!result!.ok) {
+      const reason = result!.cause ?? result!.message
+      onEvent({
+        action: 'error',
+        alerts: [],
+        purl: getPackageKey(pkg),
+        reason,
+      })
+      writeRegistryError(
+        res,
+        503,
+        `Socket could not check ${getPackageKey(pkg)}: ${reason}`,
+      )
+      return false
+    }
+    const verdict = result!.data
+    if (verdict.action === 'block') {
+      if (quarantine) {
+        quarantineVersion(pkg, verdict)
+      }
+      onEvent({ action: 'block', alerts: verdict.alerts, purl: verdict.purl })
+      writeRegistryError(
+        res,
+        403,
+        `Socket blocked ${verdict.purl}: ${formatAlerts(verdict.alerts)}`,
+      )
+      return false
+    }
+    onEvent({
+      action: verdict.action,
+      alerts: verdict.alerts,
+      purl: verdict.purl,
+    })
+    return true
+  }
+
+  async function sendPackument(
+    name: string,
+    req: IncomingMessage,
+    upstreamRes: IncomingMessage,
+    res: ServerResponse,
+  ): Promise<void> {
+    const body = await readBody(upstreamRes)
+    let packument: NpmPackument
+    try {
+      packument = JSON.parse(body.toString('utf8'))
+    } catch {
+      res.writeHead(
+        upstreamRes.statusCode ?? 502,
+        getResponseHeaders(upstreamRes.headers),
+      )
+      res.end(body)
+      return
+    }
+    if (quarantine) {
+      // Check the versions dist-tags name, which is what most installs
+      // resolve to, before the client sees them.
+      const tagged = [
+        ...new Set(Object.values(packument['dist-tags'] ?? {})),
+      ].map(version => ({ name, version }))
+      const results = tagged.length ? await getVerdicts(tagged) : []
+      for (let i = 0, { length } = results; i < length; i += 1) {
+        const result = results[i]!
+        if (result.ok && result.data.action === 'block') {
+          quarantineVersion(tagged[i]!, result.data)
+        }
+      }
+    }
+    rewritePackument(packument, {
+      proxyBaseUrl: `http://${req.headers.host}/`,
+      quarantined: quarantinedVersions.get(name),
+      upstreamBaseUrl,
+    })
+    const rewritten = JSON.stringify(packument)
+    res.writeHead(upstreamRes.statusCode ?? 200, {
+      ...getResponseHeaders(upstreamRes.headers, [
+        'content-encoding',
+        'content-length',
+        'etag',
+      ]),
+      'content-length': String(Buffer.byteLength(rewritten)),
+    })
+    res.end(req.method === 'HEAD' ? undefined : rewritten)
+  }
+
+  async function handleRequest(
+    req: IncomingMessage,
+    res: ServerResponse,
+  ): Promise<void> {
+    const url = new URL(req.url ?? '/', 'http://localhost')
+    const target =
+      req.method === 'GET' || req.method === 'HEAD'
+        ? parseNpmRegistryPath(url.pathname)
+        : undefined
+    if (target?.kind === 'tarball' && !(await handleTarball(target, res))) {
+      return
+    }
+    const isPackument = target?.kind === 'packument'
+    const upstreamRes = await requestUpstream(
+      req,
+      new URL(`${url.pathname.slice(1)}${url.search}`, upstreamBaseUrl),
+      getForwardHeaders(
+        req.headers,
+        isPackument
+          ? {
+              // The body is rewritten, so it has to arrive uncompressed and
+              // in full, without a 304 that would keep a stale copy.
+              'accept-encoding': 'identity',
+              'if-modified-since': undefined,
+              'if-none-match': undefined,
+            }
+          : {},
+      ),
+    )
+    if (isPackument && upstreamRes.statusCode === 200) {
+      await sendPackument(target.name, req, upstreamRes, res)
+      return
+    }
+    res.writeHead(
+      upstreamRes.statusCode ?? 502,
+      getResponseHeaders(upstreamRes.headers),
+    )
+    upstreamRes.pipe(res)
+  }
+
+  return createServer((req, res) => {
+    handleRequest(req, res).catch(e => {
+      if (res.headersSent) {
+        res.destroy(e as Error)
+      } else {
+        writeRegistryError(
+          res,
+          502,
+          `Upstream registry request failed: ${(e as Error).message}`,
+        )
+      }
+    })
+  })
+}
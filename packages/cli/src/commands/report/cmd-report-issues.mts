@@ -0,0 +1,244 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { handleReportIssues } from './handle-report-issues.mts'
+import { ISSUE_SEVERITIES, ISSUE_TRACKERS } from './issue-tracker.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { SOCKET_CLI_JIRA_API_TOKEN } from '../../env/socket-cli-jira-api-token.mts'
+import { SOCKET_CLI_JIRA_EMAIL } from '../../env/socket-cli-jira-email.mts'
+import { SOCKET_CLI_JIRA_URL } from '../../env/socket-cli-jira-url.mts'
+import { outputDryRunUpload } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { readCompletionHistory } from '../../util/cli/completion-history.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { IssueSeverity, IssueTrackerName } from './issue-tracker.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'issues'
+
+const description =
+  'File issue tracker tickets for the high and critical alerts of a scan'
+
+const hidden = false
+
+export const cmdReportIssues: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      issueType: {
+        type: 'string',
+        default: 'Bug',
+        description: 'Issue type of the tickets',
+      },
+      jiraUrl: {
+        type: 'string',
+        default: '',
+        description:
+          'Jira site to file the tickets on, defaults to the SOCKET_CLI_JIRA_URL environment variable',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      project: {
+        type: 'string',
+        default: '',
+        description: 'Key of the project to file the tickets in, e.g. SEC',
+      },
+      scan: {
+        type: 'string',
+        default: '',
+        description:
+          'ID of the scan to file tickets for, defaults to the scan created or listed last on this machine',
+      },
+      severity: {
+        type: 'string',
+        default: 'high',
+        description:
+          'Lowest alert severity to file a ticket for, "high" or "critical"',
+      },
+      tracker: {
+        type: 'string',
+        default: 'jira',
+        description: `Issue tracker to file the tickets in, one of ${ISSUE_TRACKERS.join(', ')}`,
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] --project <KEY>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Creates one ticket per high or critical alert of the scan with the
+    package, the path inside it, the remediation and links to the report.
+    Tickets are labelled "socket-alert" plus a fingerprint of the alert, and
+    alerts that already have a ticket in the project are skipped, so the
+    command can run after every scan without filing duplicates.
+
+    Jira tickets are created as the account of the SOCKET_CLI_JIRA_EMAIL and
+    SOCKET_CLI_JIRA_API_TOKEN environment variables.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} --project SEC
+      $ ${command} --tracker jira --project SEC --severity critical
+      $ ${command} --project SEC --jira-url https://acme.atlassian.net --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, interactive, json, markdown, org: orgFlag } = cli.flags
+
+  const issueType = String(cli.flags['issueType'])
+
+  const project = String(cli.flags['project'])
+
+  const severity = String(cli.flags['severity'])
+
+  const tracker = String(cli.flags['tracker'])
+
+  const jiraUrl = String(cli.flags['jiraUrl'] || '') || SOCKET_CLI_JIRA_URL
+
+  const scanId = cli.flags['scan'] || (await readCompletionHistory()).scan[0]
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(orgFlag, interactive, dryRun)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!project,
+      message: 'Project key by --project',
+      fail: 'missing',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID by --scan, or a scan created or listed before',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: (ISSUE_TRACKERS as readonly string[]).includes(tracker),
+      message: `The --tracker flag must be ${joinOr(ISSUE_TRACKERS.map(t => `'${t}'`))}`,
+      fail: 'unsupported tracker',
+    },
+    {
+      nook: true,
+      test: (ISSUE_SEVERITIES as readonly string[]).includes(severity),
+      message: `The --severity flag must be ${joinOr(ISSUE_SEVERITIES.map(s => `'${s}'`))}`,
+      fail: 'unsupported severity',
+    },
+    {
+      nook: true,
+      test: !!jiraUrl,
+      message: 'The Jira site by --jira-url or SOCKET_CLI_JIRA_URL',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: dryRun || (!!SOCKET_CLI_JIRA_EMAIL && !!SOCKET_CLI_JIRA_API_TOKEN),
+      message:
+        'Jira credentials by SOCKET_CLI_JIRA_EMAIL and SOCKET_CLI_JIRA_API_TOKEN',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput || !scanId) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunUpload('issue tracker tickets', {
+      organization: orgSlug,
+      scanId,
+      tracker,
+      site: jiraUrl,
+      project,
+      issueType,
+      severity: `${severity} and above`,
+    })
+    return
+  }
+
+  await handleReportIssues(
+    {
+      cwd: process.cwd(),
+      issueType,
+      jira: {
+        apiToken: SOCKET_CLI_JIRA_API_TOKEN,
+        baseUrl: jiraUrl,
+        email: SOCKET_CLI_JIRA_EMAIL,
+      },
+      minSeverity: severity as IssueSeverity,
+      orgSlug,
+      project,
+      scanId,
+      tracker: tracker as IssueTrackerName,
+    },
+    outputKind,
+  )
+}
@@ -0,0 +1,193 @@
+import isInteractive from '@socketregistry/is-interactive/index.cjs'
+
+import { handleReportOpen } from './handle-report-open.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { readCompletionHistory } from '../../util/cli/completion-history.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'open'
+
+const description =
+  'Open the report of a scan, a package or an alert on socket.dev'
+
+const hidden = false
+
+export const cmdReportOpen: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      alert: {
+        type: 'string',
+        default: '',
+        description: 'Link to the page of an alert type, e.g. installScripts',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      package: {
+        type: 'string',
+        default: '',
+        description:
+          'Link to the page of a package, as a purl, e.g. pkg:npm/lodash@4.17.21',
+      },
+      print: {
+        type: 'boolean',
+        default: false,
+        description: 'Print the links instead of opening a browser',
+      },
+      scan: {
+        type: 'string',
+        default: '',
+        description:
+          'ID of the scan to link to, defaults to the scan created or listed last on this machine',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Opens the most specific link in the browser: the alert given by --alert,
+    else the package given by --package, else the report of the scan. All
+    links are printed as well. With --print, without a TTY or with --json or
+    --markdown the links are only printed, ready to paste into a ticket or
+    an incident channel.
+
+    Package and alert pages are public. Viewing a scan report requires access
+    to the organization on socket.dev, and the Socket API has no time-limited
+    share links for people outside of it yet.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command}
+      $ ${command} --scan 000aaaa1-0000-0a0a-00a0-00a0000000a0 --print
+      $ ${command} --package pkg:npm/lodash@4.17.21
+      $ ${command} --alert installScripts --package pkg:npm/esbuild@0.20.0
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, interactive, json, markdown, org: orgFlag } = cli.flags
+
+  const alertType = String(cli.flags['alert'] || '')
+
+  const purl = String(cli.flags['package'] || '')
+
+  const print = !!cli.flags['print']
+
+  // A package or alert link does not need a scan, so only fall back to the
+  // last scan when neither is asked for.
+  const scanId =
+    String(cli.flags['scan'] || '') ||
+    (alertType || purl ? '' : ((await readCompletionHistory()).scan[0] ?? ''))
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = scanId
+    ? await determineOrgSlug(orgFlag, interactive, dryRun)
+    : ['']
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!(scanId || alertType || purl),
+      message:
+        'Scan ID by --scan or a scan created or listed before, --package or --alert',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !scanId || !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: !scanId || hasApiToken,
+      message: 'Linking to a scan requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('report links', {
+      organization: orgSlug,
+      scanId,
+      package: purl,
+      alert: alertType,
+    })
+    return
+  }
+
+  await handleReportOpen(
+    {
+      alertType,
+      open: !print && outputKind === 'text' && isInteractive(),
+      orgSlug,
+      purl,
+      scanId,
+    },
+    outputKind,
+  )
+}
@@ -0,0 +1,12 @@
+import { cmdReportIssues } from './cmd-report-issues.mts'
+import { cmdReportOpen } from './cmd-report-open.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdReport = defineSubcommandGroup({
+  name: 'report',
+  description: 'Report the results of a scan to other tools',
+  subcommands: {
+    issues: cmdReportIssues,
+    open: cmdReportOpen,
+  },
+})
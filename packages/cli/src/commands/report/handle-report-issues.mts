@@ -0,0 +1,119 @@
+import { getIssueTickets } from './issue-tracker.mts'
+import { JiraTracker } from './jira-tracker.mts'
+import { outputReportIssues } from './output-report-issues.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+import { fetchScanMetadata } from '../scan/fetch-scan-metadata.mts'
+
+import type {
+  IssueSeverity,
+  IssueTracker,
+  IssueTrackerName,
+} from './issue-tracker.mts'
+import type { JiraTrackerOptions } from './jira-tracker.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type ReportedIssue = {
+  alertId: string
+  fingerprint: string
+  key: string
+  purl: string
+  severity: string
+  summary: string
+  url: string
+}
+
+export type ReportIssuesResult = {
+  created: ReportedIssue[]
+  project: string
+  scanId: string
+  // Alerts that already had a ticket from an earlier run.
+  skipped: number
+  tracker: IssueTrackerName
+}
+
+export type ReportIssuesConfig = {
+  cwd: string
+  issueType: string
+  jira: Omit<JiraTrackerOptions, 'issueType'>
+  minSeverity: IssueSeverity
+  orgSlug: string
+  project: string
+  scanId: string
+  tracker: IssueTrackerName
+}
+
+function getIssueTracker(config: ReportIssuesConfig): IssueTracker {
+  return new JiraTracker({ ...config.jira, issueType: config.issueType })
+}
+
+export async function reportScanIssues(
+  config: ReportIssuesConfig,
+): Promise<CResult<ReportIssuesResult>> {
+  const { cwd, minSeverity, orgSlug, project, scanId, tracker } = {
+    __proto__: null,
+    ...config,
+  } as ReportIssuesConfig
+
+  const scanCResult = await fetchScan(orgSlug, scanId)
+  if (!scanCResult.ok) {
+    return scanCResult
+  }
+  // The report link is a nicety, tickets are still worth filing without it.
+  const metadataCResult = await fetchScanMetadata(orgSlug, scanId, {
+    commandPath: 'socket report issues',
+  })
+  const reportUrl = metadataCResult.ok
+    ? metadataCResult.data.html_report_url
+    : undefined
+
+  const issueTracker = getIssueTracker(config)
+  const fingerprintsCResult = await issueTracker.listFingerprints(project)
+  if (!fingerprintsCResult.ok) {
+    return fingerprintsCResult
+  }
+  const fingerprints = fingerprintsCResult.data
+
+  const tickets = getIssueTickets(scanCResult.data, {
+    cwd,
+    minSeverity,
+    reportUrl,
+    scanId,
+  })
+  const created: ReportedIssue[] = []
+  let skipped = 0
+  for (const ticket of tickets) {
+    if (fingerprints.has(ticket.fingerprint)) {
+      skipped += 1
+      continue
+    }
+    const issueCResult = await issueTracker.createIssue(project, ticket)
+    if (!issueCResult.ok) {
+      return {
+        ...issueCResult,
+        cause: `${issueCResult.cause ?? issueCResult.message}; created ${created.length} of ${tickets.length - skipped} tickets before the failure`,
+      }
+    }
+    const { explanation } = ticket
+    created.push({
+      alertId: explanation.alertId,
+      fingerprint: ticket.fingerprint,
+      key: issueCResult.data.key,
+      purl: explanation.purl,
+      severity: explanation.severity!,
+      summary: ticket.summary,
+      url: issueCResult.data.url,
+    })
+  }
+
+  return {
+    ok: true,
+    data: { created, project, scanId, skipped, tracker },
+  }
+}
+
+export async function handleReportIssues(
+  config: ReportIssuesConfig,
+  outputKind: OutputKind,
+): Promise<void> {
+  await outputReportIssues(await reportScanIssues(config), outputKind)
+}
@@ -0,0 +1,97 @@
+import { outputReportOpen } from './output-report-open.mts'
+import { getPurlObject } from '../../util/purl/parse.mts'
+import {
+  getSocketDevAlertUrl,
+  getSocketDevPackageOverviewUrlFromPurl,
+} from '../../util/socket/url.mts'
+import { fetchScanMetadata } from '../scan/fetch-scan-metadata.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type ReportLinkKind = 'alert' | 'package' | 'scan'
+
+export type ReportLink = {
+  kind: ReportLinkKind
+  url: string
+}
+
+export type ReportOpenResult = {
+  links: ReportLink[]
+  // The most specific link, the one opened in the browser.
+  url: string
+}
+
+export type ReportOpenConfig = {
+  alertType: string
+  orgSlug: string
+  open: boolean
+  purl: string
+  scanId: string
+}
+
+/**
+ * Links for a scan report, a package and an alert type, most specific first.
+ */
+export function getReportLinks({
+  alertType,
+  purl,
+  reportUrl,
+}: {
+  alertType?: string | undefined
+  purl?: string | undefined
+  reportUrl?: string | undefined
+}): CResult<ReportOpenResult> {
+  const links: ReportLink[] = []
+  if (alertType) {
+    links.push({ kind: 'alert', url: getSocketDevAlertUrl(alertType) })
+  }
+  if (purl) {
+    if (!getPurlObject(purl, { throws: false })) {
+      return {
+        ok: false,
+        message: 'Invalid package',
+        cause: `"${purl}" is not a valid package URL, e.g. pkg:npm/lodash@4.17.21`,
+      }
+    }
+    links.push({
+      kind: 'package',
+      url: getSocketDevPackageOverviewUrlFromPurl(purl),
+    })
+  }
+  if (reportUrl) {
+    links.push({ kind: 'scan', url: reportUrl })
+  }
+  if (!links.length) {
+    return {
+      ok: false,
+      message: 'No report link',
+      cause: 'The API did not return a report URL for the scan',
+    }
+  }
+  return { ok: true, data: { links, url: links[0]!.url } }
+}
+
+export async function handleReportOpen(
+  config: ReportOpenConfig,
+  outputKind: OutputKind,
+): Promise<void> {
+  const { alertType, orgSlug, purl, scanId } = config
+
+  let reportUrl: string | undefined
+  if (scanId) {
+    const metadataCResult = await fetchScanMetadata(orgSlug, scanId, {
+      commandPath: 'socket report open',
+    })
+    if (!metadataCResult.ok) {
+      await outputReportOpen(metadataCResult, outputKind, config.open)
+      return
+    }
+    reportUrl = metadataCResult.data.html_report_url
+  }
+
+  await outputReportOpen(
+    getReportLinks({ alertType, purl, reportUrl }),
+    outputKind,
+    config.open,
+  )
+}
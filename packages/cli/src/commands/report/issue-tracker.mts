@@ -0,0 +1,120 @@
+/**
+ * Issue tracker tickets for `socket report issues`.
+ *
+ * Every high or critical alert of a scan becomes one ticket. Tickets carry a
+ * fingerprint label derived from the package and the alert key, so running
+ * the command again for a later scan of the same project skips the alerts
+ * that already have a ticket instead of filing them twice.
+ */
+
+import crypto from 'node:crypto'
+
+import { explainAlert } from '../explain/alert-explanation.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { AlertExplanation } from '../explain/alert-explanation.mts'
+
+export const ISSUE_TRACKERS = ['jira'] as const
+
+export type IssueTrackerName = (typeof ISSUE_TRACKERS)[number]
+
+export const ISSUE_SEVERITIES = ['critical', 'high'] as const
+
+export type IssueSeverity = (typeof ISSUE_SEVERITIES)[number]
+
+// Label on every ticket, to find the tickets of earlier runs.
+export const ISSUE_LABEL = 'socket-alert'
+
+export type IssueTicket = {
+  explanation: AlertExplanation
+  // Label identifying the alert on the package across scans.
+  fingerprint: string
+  reportUrl?: string | undefined
+  scanId: string
+  summary: string
+}
+
+export type CreatedIssue = {
+  key: string
+  url: string
+}
+
+export interface IssueTracker {
+  // Fingerprints of the tickets already in the project.
+  listFingerprints(project: string): Promise<CResult<Set<string>>>
+  createIssue(
+    project: string,
+    ticket: IssueTicket,
+  ): Promise<CResult<CreatedIssue>>
+}
+
+export type GetIssueTicketsOptions = {
+  cwd?: string | undefined
+  minSeverity: IssueSeverity
+  reportUrl?: string | undefined
+  scanId: string
+}
+
+// Trackers cap summaries, Jira at 255 characters.
+const MAX_SUMMARY_LENGTH = 255
+
+export function getIssueFingerprint(purl: string, alertKey: string): string {
+  const hash = crypto
+    .createHash('sha256')
+    .update(`${purl} ${alertKey}`)
+    .digest('hex')
+  return `socket-${hash.slice(0, 16)}`
+}
+
+function getSummary(explanation: AlertExplanation): string {
+  const pkg = explanation.purl.replace(/^pkg:[^/]+\//, '')
+  const summary = `[Socket] ${explanation.severity}: ${explanation.title} in ${pkg}`
+  return summary.length > MAX_SUMMARY_LENGTH
+    ? `${summary.slice(0, MAX_SUMMARY_LENGTH - 1)}…`
+    : summary
+}
+
+/**
+ * The tickets for the alerts of the scan at or above minSeverity, most
+ * severe first. An alert reported on several manifests is one ticket.
+ */
+export function getIssueTickets(
+  artifacts: SocketArtifact[],
+  options: GetIssueTicketsOptions,
+): IssueTicket[] {
+  const { cwd, minSeverity, reportUrl, scanId } = {
+    __proto__: null,
+    ...options,
+  } as GetIssueTicketsOptions
+  const severities = ISSUE_SEVERITIES.slice(
+    0,
+    ISSUE_SEVERITIES.indexOf(minSeverity) + 1,
+  ) as readonly string[]
+
+  const tickets = new Map<string, IssueTicket>()
+  for (const artifact of artifacts) {
+    for (const alert of artifact.alerts ?? []) {
+      if (!alert.severity || !severities.includes(alert.severity)) {
+        continue
+      }
+      const explanation = explainAlert(artifact, alert, { cwd, scanId })
+      const fingerprint = getIssueFingerprint(explanation.purl, alert.key)
+      if (!tickets.has(fingerprint)) {
+        tickets.set(fingerprint, {
+          explanation,
+          fingerprint,
+          reportUrl,
+          scanId,
+          summary: getSummary(explanation),
+        })
+      }
+    }
+  }
+  return [...tickets.values()].sort(
+    (a, b) =>
+      severities.indexOf(a.explanation.severity!) -
+        severities.indexOf(b.explanation.severity!) ||
+      a.summary.localeCompare(b.summary),
+  )
+}
@@ -0,0 +1,220 @@
+/**
+ * Jira Cloud issue tracker for `socket report issues`.
+ *
+ * Uses the REST API with an email and API token. Tickets are plain text
+ * descriptions in Jira wiki markup, labelled with ISSUE_LABEL and the alert
+ * fingerprint so later runs can find them with a JQL search.
+ */
+
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+
+import { ISSUE_LABEL } from './issue-tracker.mts'
+import { getNetworkErrorDiagnostics } from '../../util/error/errors.mts'
+import {
+  socketHttpRequest,
+  tryReadResponseText,
+} from '../../util/socket/api-http.mts'
+
+import type {
+  CreatedIssue,
+  IssueTicket,
+  IssueTracker,
+} from './issue-tracker.mts'
+import type { CResult } from '../../types.mts'
+
+export type JiraTrackerOptions = {
+  apiToken: string
+  // Base URL of the site, e.g. https://acme.atlassian.net.
+  baseUrl: string
+  email: string
+  issueType: string
+}
+
+type JiraSearchResponse = {
+  issues?: Array<{ key: string; fields?: { labels?: string[] } }> | undefined
+  nextPageToken?: string | undefined
+}
+
+// `text` reads as monospace in wiki markup as {{text}}.
+function toWikiMarkup(text: string): string {
+  return text.replace(/`([^`]+)`/g, '{{$1}}')
+}
+
+/**
+ * The description of the ticket in Jira wiki markup: package, path,
+ * remediation and links, ending with the report of the scan.
+ */
+export function formatJiraDescription(ticket: IssueTicket): string {
+  const { explanation, reportUrl, scanId } = ticket
+  const { location } = explanation
+  const lines = [
+    `h3. ${explanation.emoji ? `${explanation.emoji} ` : ''}${explanation.title}`,
+    '',
+    `*Package:* {{${explanation.purl}}}`,
+    `*Severity:* ${explanation.severity}`,
+    `*Alert type:* ${explanation.type}`,
+  ]
+  if (location) {
+    const line = location.line !== undefined ? `:${location.line}` : ''
+    lines.push(`*Path:* {{${location.file}${line}}}`)
+  }
+  if (explanation.manifest.length) {
+    lines.push(
+      `*Manifest:* ${explanation.manifest.map(file => `{{${file}}}`).join(', ')}`,
+    )
+  }
+  lines.push('', toWikiMarkup(explanation.description))
+  if (explanation.remediation.length) {
+    lines.push('', 'h4. Remediation')
+    for (const step of explanation.remediation) {
+      lines.push(`* ${toWikiMarkup(step)}`)
+    }
+  }
+  lines.push('', 'h4. Links')
+  if (reportUrl) {
+    lines.push(`* [Scan report|${reportUrl}]`)
+  }
+  for (const link of explanation.links) {
+    lines.push(`* [${link.label}|${link.url}]`)
+  }
+  lines.push(
+    '',
+    `Created by {{socket report issues}} from scan ${scanId}, alert ${explanation.alertId}.`,
+  )
+  return lines.join('\n')
+}
+
+// Jira explains rejected fields in errorMessages and errors.
+function getJiraErrorDetail(text: string | undefined): string {
+  try {
+    const { errorMessages = [], errors = {} } = JSON.parse(text ?? '') as {
+      errorMessages?: string[]
+      errors?: Record<string, string>
+    }
+    const messages = [
+      ...errorMessages,
+      ...Object.entries(errors).map(
+        ({ 0: field, 1: msg }) => `${field}: ${msg}`,
+      ),
+    ]
+    return messages.length ? `: ${messages.join('; ')}` : ''
+  } catch {
+    return ''
+  }
+}
+
+export class JiraTracker implements IssueTracker {
+  private options: JiraTrackerOptions
+
+  constructor(options: JiraTrackerOptions) {
+    this.options = {
+      ...options,
+      baseUrl: options.baseUrl.replace(/\/+$/, ''),
+    }
+  }
+
+  private async request(
+    path: string,
+    init?: { body?: unknown; method?: 'GET' | 'POST' } | undefined,
+  ): Promise<CResult<unknown>> {
+    const { apiToken, baseUrl, email } = this.options
+    const startTime = Date.now()
+    try {
+      const response = await socketHttpRequest(`${baseUrl}${path}`, {
+        ...(init?.body === undefined
+          ? {}
+          : { body: JSON.stringify(init.body) }),
+        headers: {
+          Accept: 'application/json',
+          Authorization: `Basic ${Buffer.from(`${email}:${apiToken}`).toString('base64')}`,
+          'Content-Type': 'application/json',
+        },
+        method: init?.method ?? 'GET',
+      })
+      const { status } = response
+      const text = tryReadResponseText(response)
+      if (status < 200 || status >= 300) {
+        debug(`Jira request failed: ${text}`)
+        return {
+          ok: false,
+          message: 'Jira API error',
+          cause: `${status} ${response.statusText}${getJiraErrorDetail(text)} (path: ${path})`,
+          data: { code: status },
+        }
+      }
+      try {
+        return { ok: true, data: text ? JSON.parse(text) : undefined }
+      } catch {
+        return {
+          ok: false,
+          message: 'Jira API error',
+          cause: `Unexpected error parsing response JSON (path: ${path})`,
+        }
+      }
+    } catch (e) {
+      return {
+        ok: false,
+        message: 'Jira request failed',
+        cause: `${getNetworkErrorDiagnostics(e, Date.now() - startTime)} (path: ${path})`,
+      }
+    }
+  }
+
+  async listFingerprints(project: string): Promise<CResult<Set<string>>> {
+    const jql = `project = "${project}" AND labels = "${ISSUE_LABEL}"`
+    const fingerprints = new Set<string>()
+    let nextPageToken: string | undefined
+    do {
+      const params = new URLSearchParams({
+        fields: 'labels',
+        jql,
+        maxResults: '100',
+      })
+      if (nextPageToken) {
+        params.set('nextPageToken', nextPageToken)
+      }
+      const searchCResult = await this.request(
+        `/rest/api/2/search/jql?${params}`,
+      )
+      if (!searchCResult.ok) {
+        return searchCResult
+      }
+      const data = (searchCResult.data ?? {}) as JiraSearchResponse
+      for (const issue of data.issues ?? []) {
+        for (const label of issue.fields?.labels ?? []) {
+          if (label !== ISSUE_LABEL && label.startsWith('socket-')) {
+            fingerprints.add(label)
+          }
+        }
+      }
+      nextPageToken = data.nextPageToken
+    } while (nextPageToken)
+    return { ok: true, data: fingerprints }
+  }
+
+  async createIssue(
+    project: string,
+    ticket: IssueTicket,
+  ): Promise<CResult<CreatedIssue>> {
+    const createCResult = await this.request('/rest/api/2/issue', {
+      body: {
+        fields: {
+          description: formatJiraDescription(ticket),
+          issuetype: { name: this.options.issueType },
+          labels: [ISSUE_LABEL, ticket.fingerprint],
+          project: { key: project },
+          summary: ticket.summary,
+        },
+      },
+      method: 'POST',
+    })
+    if (!createCResult.ok) {
+      return createCResult
+    }
+    const { key } = createCResult.data as { key: string }
+    return {
+      ok: true,
+      data: { key, url: `${this.options.baseUrl}/browse/${key}` },
+    }
+  }
+}
@@ -0,0 +1,69 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { ReportIssuesResult } from './handle-report-issues.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function getSummaryLine(data: ReportIssuesResult): string {
+  const { created, project, skipped } = data
+  const count = created.length
+  const skippedNote = skipped
+    ? `, ${skipped} ${pluralize('alert', { count: skipped })} already had one`
+    : ''
+  return `Created ${count} ${pluralize('ticket', { count })} in ${project}${skippedNote}`
+}
+
+export async function outputReportIssues(
+  result: CResult<ReportIssuesResult>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { data } = result
+  const table = data.created.length
+    ? mdTable(
+        data.created.map(issue => ({
+          key: issue.key,
+          severity: issue.severity,
+          summary: issue.summary,
+          url: issue.url,
+        })),
+        ['key', 'severity', 'summary', 'url'],
+        ['Ticket', 'Severity', 'Summary', 'URL'],
+      )
+    : ''
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader(`Tickets for scan ${data.scanId}`))
+    logger.log('')
+    logger.log(`${getSummaryLine(data)}.`)
+    if (table) {
+      logger.log('')
+      logger.log(table)
+    }
+    return
+  }
+
+  if (table) {
+    logger.log(table)
+    logger.log('')
+  }
+  logger.success(getSummaryLine(data))
+}
@@ -0,0 +1,62 @@
+import open from 'open'
+import terminalLink from 'terminal-link'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { ReportLinkKind, ReportOpenResult } from './handle-report-open.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+const LINK_LABELS: Record<ReportLinkKind, string> = {
+  alert: 'Alert',
+  package: 'Package',
+  scan: 'Scan report',
+}
+
+export async function outputReportOpen(
+  result: CResult<ReportOpenResult>,
+  outputKind: OutputKind,
+  shouldOpen: boolean,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { links, url } = result.data
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Report links'))
+    logger.log('')
+    logger.log(
+      mdList(links.map(link => `${LINK_LABELS[link.kind]}: <${link.url}>`)),
+    )
+    return
+  }
+
+  if (!shouldOpen) {
+    // Plain URLs, one per line, so they can be piped or pasted as is.
+    for (const link of links) {
+      logger.log(link.url)
+    }
+    return
+  }
+
+  for (const link of links) {
+    logger.log(`${LINK_LABELS[link.kind]}: ${terminalLink(link.url, link.url)}`)
+  }
+  await open(url)
+}
@@ -0,0 +1,30 @@
+import { handleArchiveRepo } from './handle-archive-repo.mts'
+import { createRepositoryCommand } from './repository-command-factory.mts'
+
+export const CMD_NAME = 'archive'
+
+export const cmdRepositoryArchive = createRepositoryCommand({
+  commandName: CMD_NAME,
+  description: 'Archive a repository in an organization',
+  extraFlags: {
+    unarchive: {
+      default: false,
+      description: 'Restore an archived repository instead',
+      type: 'boolean',
+    },
+  },
+  handler: async ({ flags, orgSlug, outputKind, repoName }) => {
+    await handleArchiveRepo(
+      {
+        archived: !flags['unarchive'],
+        orgSlug,
+        repoName,
+      },
+      outputKind,
+    )
+  },
+  helpDescription:
+    'Archived repositories keep their scans but are hidden from the dashboard.',
+  helpExamples: ['test-repo', 'test-repo --unarchive'],
+  mutation: 'repository (archive)',
+})
@@ -1,12 +1,39 @@
+import { getCreateRepoBody } from './fetch-create-repo.mts'
 import { handleCreateRepo } from './handle-create-repo.mts'
 import { createRepositoryCommand } from './repository-command-factory.mts'
 import { stringFlagValue } from '../../flags.mts'
 
+import type { FetchCreateRepoConfig } from './fetch-create-repo.mts'
+
 export const CMD_NAME = 'create'
 
+function getCreateRepoConfig(
+  flags: Record<string, unknown>,
+  orgSlug: string,
+  repoName: string,
+): FetchCreateRepoConfig {
+  const visibility = stringFlagValue(flags['visibility'], 'private')
+  return {
+    defaultBranch: stringFlagValue(flags['defaultBranch']),
+    description: stringFlagValue(flags['repoDescription']),
+    homepage: stringFlagValue(flags['homepage']),
+    orgSlug,
+    repoName,
+    visibility: visibility === 'public' ? 'public' : 'private',
+  }
+}
+
 export const cmdRepositoryCreate = createRepositoryCommand({
   commandName: CMD_NAME,
   description: 'Create a repository in an organization',
+  dryRunRequest: ({ flags, orgSlug, repoName }) => ({
+    method: 'POST',
+    path: `orgs/${orgSlug}/repos`,
+    body: {
+      name: repoName,
+      ...getCreateRepoBody(getCreateRepoConfig(flags, orgSlug, repoName)),
+    },
+  }),
   extraFlags: {
     defaultBranch: {
       default: 'main',
@@ -30,16 +57,8 @@ export const cmdRepositoryCreate = createRepositoryCommand({
     },
   },
   handler: async ({ flags, orgSlug, outputKind, repoName }) => {
-    const visibility = stringFlagValue(flags['visibility'], 'private')
     await handleCreateRepo(
-      {
-        defaultBranch: stringFlagValue(flags['defaultBranch']),
-        description: stringFlagValue(flags['repoDescription']),
-        homepage: stringFlagValue(flags['homepage']),
-        orgSlug,
-        repoName: repoName,
-        visibility: visibility === 'public' ? 'public' : 'private',
-      },
+      getCreateRepoConfig(flags, orgSlug, repoName),
       outputKind,
     )
   },
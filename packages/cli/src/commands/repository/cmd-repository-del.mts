@@ -6,6 +6,10 @@ export const CMD_NAME = 'del'
 export const cmdRepositoryDel = createRepositoryCommand({
   commandName: CMD_NAME,
   description: 'Delete a repository in an organization',
+  dryRunRequest: ({ orgSlug, repoName }) => ({
+    method: 'DELETE',
+    path: `orgs/${orgSlug}/repos/${repoName}`,
+  }),
   handler: async ({ orgSlug, outputKind, repoName }) => {
     await handleDeleteRepo(orgSlug, repoName, outputKind)
   },
@@ -0,0 +1,32 @@
+import { handleLabelRepo } from './handle-label-repo.mts'
+import { createRepositoryCommand } from './repository-command-factory.mts'
+
+export const CMD_NAME = 'label'
+
+export const cmdRepositoryLabel = createRepositoryCommand({
+  commandName: CMD_NAME,
+  description: 'Add or remove labels of a repository in an organization',
+  extraArgs: [{ name: 'LABEL', message: 'Label name as second argument' }],
+  extraFlags: {
+    remove: {
+      default: false,
+      description: 'Remove the labels from the repository instead',
+      type: 'boolean',
+    },
+  },
+  handler: async ({ args, flags, orgSlug, outputKind, repoName }) => {
+    await handleLabelRepo(
+      {
+        labels: args,
+        orgSlug,
+        remove: !!flags['remove'],
+        repoName,
+      },
+      outputKind,
+    )
+  },
+  helpDescription:
+    'More than one LABEL may be given. Labels that do not exist in the\n    organization yet are created.',
+  helpExamples: ['test-repo team-payments', 'test-repo legacy --remove'],
+  mutation: 'repository (labels)',
+})
@@ -0,0 +1,17 @@
+import { handleSetDefaultBranch } from './handle-set-default-branch.mts'
+import { createRepositoryCommand } from './repository-command-factory.mts'
+
+export const CMD_NAME = 'set-default-branch'
+
+export const cmdRepositorySetDefaultBranch = createRepositoryCommand({
+  commandName: CMD_NAME,
+  description: 'Set the default branch of a repository in an organization',
+  extraArgs: [{ name: 'BRANCH', message: 'Branch name as second argument' }],
+  handler: async ({ args, orgSlug, outputKind, repoName }) => {
+    await handleSetDefaultBranch(orgSlug, repoName, args[0]!, outputKind)
+  },
+  helpDescription:
+    'Scans of the default branch are the baseline that pull request scans are\n    compared with. Other repository fields are left as they are.',
+  helpExamples: ['test-repo develop'],
+  mutation: 'repository (default branch)',
+})
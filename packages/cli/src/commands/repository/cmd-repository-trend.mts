@@ -0,0 +1,196 @@
+import { handleRepoTrend } from './handle-repo-trend.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'trend'
+
+const description = 'Show score and alert trends of a repository over time'
+
+const hidden = false
+
+export const cmdRepositoryTrend = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      branch: {
+        type: 'string',
+        default: '',
+        description:
+          'Branch whose scans to compare, defaults to the default branch of the repository',
+      },
+      csv: {
+        type: 'boolean',
+        default: false,
+        description: 'Output as CSV',
+      },
+      days: {
+        type: 'number',
+        default: 365,
+        description: 'Number of days back to look for scans',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      points: {
+        type: 'number',
+        default: 12,
+        description: 'Most scans to sample over the time window',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <REPO>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Samples up to --points scans of the branch spread over the last --days
+    days, one for each equal period, and shows for each the average package
+    scores and the alert counts by severity. The terminal output draws a
+    sparkline per metric, oldest scan first, with its first and last value;
+    use --json or --csv to feed a dashboard. Every sampled scan is fetched
+    in full, so more points take longer.
+
+    Examples
+      $ ${command} test-repo
+      $ ${command} test-repo --days 90 --points 6
+      $ ${command} test-repo --branch release --csv > trend.csv
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    parentName,
+    importMeta,
+  })
+
+  const {
+    branch,
+    csv,
+    days,
+    dryRun,
+    interactive,
+    json,
+    markdown,
+    org: orgFlag,
+    points,
+  } = cli.flags
+
+  const [repoName = ''] = cli.input
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(orgFlag, interactive, dryRun)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!repoName,
+      message: 'Repository name as first argument',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: [json, markdown, csv].filter(Boolean).length <= 1,
+      message: `Only one of \`${FLAG_JSON}\`, \`${FLAG_MARKDOWN}\` and \`--csv\` can be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: typeof days === 'number' && Number.isInteger(days) && days > 0,
+      message: 'The --days value must be a positive whole number',
+      fail: 'unexpected value',
+    },
+    {
+      nook: true,
+      test:
+        typeof points === 'number' && Number.isInteger(points) && points > 1,
+      message: 'The --points value must be a whole number above 1',
+      fail: 'unexpected value',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  // Re-assert the checkCommandInput guards for the type system — meow's
+  // number-typed flags deliver the raw string for garbage input.
+  if (typeof days !== 'number' || typeof points !== 'number') {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('repository trend', {
+      organization: orgSlug,
+      repository: repoName,
+      branch: branch || undefined,
+      days,
+      points,
+    })
+    return
+  }
+
+  await handleRepoTrend({
+    branch,
+    days,
+    orgSlug,
+    outputKind: csv ? 'csv' : outputKind,
+    points,
+    repoName,
+  })
+}
@@ -1,12 +1,35 @@
+import { getUpdateRepoBody } from './fetch-update-repo.mts'
 import { handleUpdateRepo } from './handle-update-repo.mts'
 import { createRepositoryCommand } from './repository-command-factory.mts'
 import { stringFlagValue } from '../../flags.mts'
 
+import type { FetchUpdateRepoConfig } from './fetch-update-repo.mts'
+
 export const CMD_NAME = 'update'
 
+function getUpdateRepoConfig(
+  flags: Record<string, unknown>,
+  orgSlug: string,
+  repoName: string,
+): FetchUpdateRepoConfig {
+  return {
+    defaultBranch: stringFlagValue(flags['defaultBranch']),
+    description: stringFlagValue(flags['repoDescription']),
+    homepage: stringFlagValue(flags['homepage']),
+    orgSlug,
+    repoName,
+    visibility: stringFlagValue(flags['visibility'], 'private'),
+  }
+}
+
 export const cmdRepositoryUpdate = createRepositoryCommand({
   commandName: CMD_NAME,
   description: 'Update a repository in an organization',
+  dryRunRequest: ({ flags, orgSlug, repoName }) => ({
+    method: 'POST',
+    path: `orgs/${orgSlug}/repos/${repoName}`,
+    body: getUpdateRepoBody(getUpdateRepoConfig(flags, orgSlug, repoName)),
+  }),
   extraFlags: {
     defaultBranch: {
       default: 'main',
@@ -35,14 +58,7 @@ export const cmdRepositoryUpdate = createRepositoryCommand({
   },
   handler: async ({ flags, orgSlug, outputKind, repoName }) => {
     await handleUpdateRepo(
-      {
-        defaultBranch: stringFlagValue(flags['defaultBranch']),
-        description: stringFlagValue(flags['repoDescription']),
-        homepage: stringFlagValue(flags['homepage']),
-        orgSlug,
-        repoName: repoName,
-        visibility: stringFlagValue(flags['visibility'], 'private'),
-      },
+      getUpdateRepoConfig(flags, orgSlug, repoName),
       outputKind,
     )
   },
@@ -1,6 +1,10 @@
+import { cmdRepositoryArchive } from './cmd-repository-archive.mts'
 import { cmdRepositoryCreate } from './cmd-repository-create.mts'
 import { cmdRepositoryDel } from './cmd-repository-del.mts'
+import { cmdRepositoryLabel } from './cmd-repository-label.mts'
 import { cmdRepositoryList } from './cmd-repository-list.mts'
+import { cmdRepositorySetDefaultBranch } from './cmd-repository-set-default-branch.mts'
+import { cmdRepositoryTrend } from './cmd-repository-trend.mts'
 import { cmdRepositoryUpdate } from './cmd-repository-update.mts'
 import { cmdRepositoryView } from './cmd-repository-view.mts'
 import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
@@ -22,7 +26,11 @@ export const cmdRepository: CliSubcommand = {
           view: cmdRepositoryView,
           list: cmdRepositoryList,
           del: cmdRepositoryDel,
+          trend: cmdRepositoryTrend,
           update: cmdRepositoryUpdate,
+          archive: cmdRepositoryArchive,
+          'set-default-branch': cmdRepositorySetDefaultBranch,
+          label: cmdRepositoryLabel,
         },
       },
       { description },
@@ -19,18 +19,24 @@ export type FetchCreateRepoOptions = {
   sdkOpts?: SetupSdkOptions | undefined
 }
 
+/**
+ * The body of `POST orgs/{org}/repos`, the SDK adds the name.
+ */
+export function getCreateRepoBody(config: FetchCreateRepoConfig) {
+  const { defaultBranch, description, homepage, visibility } = config
+  return {
+    default_branch: defaultBranch,
+    description,
+    homepage,
+    visibility,
+  }
+}
+
 export async function fetchCreateRepo(
   config: FetchCreateRepoConfig,
   options?: FetchCreateRepoOptions | undefined,
 ): Promise<CResult<SocketSdkSuccessResult<'createRepository'>['data']>> {
-  const {
-    defaultBranch,
-    description,
-    homepage,
-    orgSlug,
-    repoName,
-    visibility,
-  } = config
+  const { orgSlug, repoName } = config
 
   const { commandPath, sdkOpts } = {
     __proto__: null,
@@ -44,12 +50,7 @@ export async function fetchCreateRepo(
   const sockSdk = sockSdkCResult.data
 
   return await handleApiCall<'createRepository'>(
-    sockSdk.createRepository(orgSlug, repoName, {
-      default_branch: defaultBranch,
-      description,
-      homepage,
-      visibility,
-    }),
+    sockSdk.createRepository(orgSlug, repoName, getCreateRepoBody(config)),
     {
       commandPath,
       description: 'to create a repository',
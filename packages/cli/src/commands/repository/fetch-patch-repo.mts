@@ -0,0 +1,49 @@
+import { handleApiCall } from '../../util/socket/api.mjs'
+import { setupSdk } from '../../util/socket/sdk.mjs'
+
+import type { CResult } from '../../types.mts'
+import type { SetupSdkOptions } from '../../util/socket/sdk.mjs'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+// Fields of a repository to change. Unlike `fetchUpdateRepo`, which sends
+// every field, the others keep their value.
+export type RepoPatch = {
+  archived?: boolean | undefined
+  default_branch?: string | undefined
+}
+
+export type FetchPatchRepoOptions = {
+  commandPath?: string | undefined
+  description?: string | undefined
+  sdkOpts?: SetupSdkOptions | undefined
+}
+
+export async function fetchPatchRepo(
+  orgSlug: string,
+  repoName: string,
+  patch: RepoPatch,
+  options?: FetchPatchRepoOptions | undefined,
+): Promise<CResult<SocketSdkSuccessResult<'updateRepository'>['data']>> {
+  const {
+    commandPath,
+    description = 'to update a repository',
+    sdkOpts,
+  } = {
+    __proto__: null,
+    ...options,
+  } as FetchPatchRepoOptions
+
+  const sockSdkCResult = await setupSdk(sdkOpts)
+  if (!sockSdkCResult.ok) {
+    return sockSdkCResult
+  }
+  const sockSdk = sockSdkCResult.data
+
+  return await handleApiCall<'updateRepository'>(
+    sockSdk.updateRepository(orgSlug, repoName, patch),
+    {
+      commandPath,
+      description,
+    },
+  )
+}
@@ -0,0 +1,75 @@
+import { queryApiSafeJson, sendApiRequest } from '../../util/socket/api.mjs'
+
+import type { CResult } from '../../types.mts'
+
+export type RepoLabel = {
+  id: string
+  name: string
+  repository_ids?: string[] | undefined
+}
+
+type RepoLabelPage = {
+  results: RepoLabel[]
+  nextPage?: number | null | undefined
+}
+
+const REPO_LABELS_PER_PAGE = 100
+
+// Repository labels are not wrapped by the SDK yet, so these call the
+// endpoints directly.
+
+export async function fetchRepoLabels(
+  orgSlug: string,
+): Promise<CResult<RepoLabel[]>> {
+  const labels: RepoLabel[] = []
+  let page: number | null | undefined = 1
+  while (page) {
+    const pageCResult: CResult<RepoLabelPage> =
+      await queryApiSafeJson<RepoLabelPage>(
+        `orgs/${encodeURIComponent(orgSlug)}/repos/labels?per_page=${REPO_LABELS_PER_PAGE}&page=${page}`,
+        'repository labels',
+      )
+    if (!pageCResult.ok) {
+      return pageCResult
+    }
+    labels.push(...(pageCResult.data.results ?? []))
+    page = pageCResult.data.nextPage
+  }
+  return { ok: true, data: labels }
+}
+
+export async function fetchCreateRepoLabel(
+  orgSlug: string,
+  name: string,
+): Promise<CResult<RepoLabel>> {
+  return await sendApiRequest<RepoLabel>(
+    `orgs/${encodeURIComponent(orgSlug)}/repos/labels`,
+    {
+      method: 'POST',
+      body: { name },
+      commandPath: 'socket repository label',
+      description: `label ${name}`,
+    },
+  )
+}
+
+export type RepoLabelAction = 'associate' | 'disassociate'
+
+/**
+ * Add the label to a repository, or take it off again.
+ */
+export async function fetchAssignRepoLabel(
+  orgSlug: string,
+  labelId: string,
+  repositoryId: string,
+  action: RepoLabelAction,
+): Promise<CResult<unknown>> {
+  return await sendApiRequest(
+    `orgs/${encodeURIComponent(orgSlug)}/repos/labels/${encodeURIComponent(labelId)}/${action}`,
+    {
+      method: 'POST',
+      body: { repository_id: repositoryId },
+      commandPath: 'socket repository label',
+    },
+  )
+}
@@ -0,0 +1,81 @@
+import { handleApiCall } from '../../util/socket/api.mjs'
+import { setupSdk } from '../../util/socket/sdk.mjs'
+
+import type { CResult } from '../../types.mts'
+import type { SetupSdkOptions } from '../../util/socket/sdk.mjs'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+export type RepoScanHistoryItem =
+  SocketSdkSuccessResult<'listFullScans'>['data']['results'][number]
+
+export type FetchRepoScanHistoryConfig = {
+  branch: string
+  orgSlug: string
+  repoName: string
+  // Unix timestamp in seconds of the oldest scan to list.
+  since: number
+}
+
+export type FetchRepoScanHistoryOptions = {
+  commandPath?: string | undefined
+  sdkOpts?: SetupSdkOptions | undefined
+}
+
+/**
+ * List every scan of a repo branch since a point in time, oldest first.
+ */
+export async function fetchRepoScanHistory(
+  config: FetchRepoScanHistoryConfig,
+  options?: FetchRepoScanHistoryOptions | undefined,
+): Promise<CResult<RepoScanHistoryItem[]>> {
+  const { branch, orgSlug, repoName, since } = {
+    __proto__: null,
+    ...config,
+  } as FetchRepoScanHistoryConfig
+  const { commandPath, sdkOpts } = {
+    __proto__: null,
+    ...options,
+  } as FetchRepoScanHistoryOptions
+
+  const sockSdkCResult = await setupSdk(sdkOpts)
+  if (!sockSdkCResult.ok) {
+    return sockSdkCResult
+  }
+  const sockSdk = sockSdkCResult.data
+
+  const rows: RepoScanHistoryItem[] = []
+  let protection = 0
+  let nextPage = 0
+  while (nextPage >= 0) {
+    if (++protection > 100) {
+      return {
+        ok: false,
+        message: 'Infinite loop detected',
+        cause: `Either there are over 100 pages of scans or the fetch has run into an infinite loop. Breaking it off now. nextPage=${nextPage}`,
+      }
+    }
+    const scanListCResult = await handleApiCall<'listFullScans'>(
+      sockSdk.listFullScans(orgSlug, {
+        ...(branch ? { branch } : {}),
+        direction: 'asc',
+        from: String(since),
+        page: nextPage,
+        per_page: 100, // max
+        repo: repoName,
+        sort: 'created_at',
+      }),
+      {
+        commandPath,
+        description: 'list of scans',
+      },
+    )
+    if (!scanListCResult.ok) {
+      return scanListCResult
+    }
+
+    rows.push(...scanListCResult.data.results)
+    nextPage = Number(scanListCResult.data.nextPage ?? -1)
+  }
+
+  return { ok: true, data: rows }
+}
@@ -19,10 +19,10 @@ export type FetchUpdateRepoOptions = {
   sdkOpts?: SetupSdkOptions | undefined
 }
 
-export async function fetchUpdateRepo(
-  config: FetchUpdateRepoConfig,
-  options?: FetchUpdateRepoOptions | undefined,
-): Promise<CResult<SocketSdkSuccessResult<'updateRepository'>['data']>> {
+/**
+ * The body of `POST orgs/{org}/repos/{repo}`.
+ */
+export function getUpdateRepoBody(config: FetchUpdateRepoConfig) {
   const {
     defaultBranch,
     description,
@@ -30,7 +30,22 @@ export async function fetchUpdateRepo(
     orgSlug,
     repoName,
     visibility,
-  } = {
+  } = config
+  return {
+    default_branch: defaultBranch,
+    description,
+    homepage,
+    name: repoName,
+    orgSlug,
+    visibility,
+  }
+}
+
+export async function fetchUpdateRepo(
+  config: FetchUpdateRepoConfig,
+  options?: FetchUpdateRepoOptions | undefined,
+): Promise<CResult<SocketSdkSuccessResult<'updateRepository'>['data']>> {
+  const { orgSlug, repoName } = {
     __proto__: null,
     ...config,
   } as FetchUpdateRepoConfig
@@ -47,14 +62,7 @@ export async function fetchUpdateRepo(
   const sockSdk = sockSdkCResult.data
 
   return await handleApiCall<'updateRepository'>(
-    sockSdk.updateRepository(orgSlug, repoName, {
-      default_branch: defaultBranch,
-      description,
-      homepage,
-      name: repoName,
-      orgSlug,
-      visibility,
-    }),
+    sockSdk.updateRepository(orgSlug, repoName, getUpdateRepoBody(config)),
     {
       commandPath,
       description: 'to update a repository',
@@ -0,0 +1,29 @@
+import { fetchPatchRepo } from './fetch-patch-repo.mts'
+import { outputArchiveRepo } from './output-archive-repo.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export async function handleArchiveRepo(
+  {
+    archived,
+    orgSlug,
+    repoName,
+  }: {
+    archived: boolean
+    orgSlug: string
+    repoName: string
+  },
+  outputKind: OutputKind,
+): Promise<void> {
+  const data = await fetchPatchRepo(
+    orgSlug,
+    repoName,
+    { archived },
+    {
+      commandPath: 'socket repository archive',
+      description: `to ${archived ? 'archive' : 'unarchive'} a repository`,
+    },
+  )
+
+  await outputArchiveRepo(data, { archived, repoName }, outputKind)
+}
@@ -0,0 +1,96 @@
+import {
+  fetchAssignRepoLabel,
+  fetchCreateRepoLabel,
+  fetchRepoLabels,
+} from './fetch-repo-labels.mts'
+import { fetchViewRepo } from './fetch-view-repo.mts'
+import { outputLabelRepo } from './output-label-repo.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type LabelRepoResult = {
+  action: 'added' | 'removed'
+  // Labels whose assignment changed.
+  changed: string[]
+  repository: string
+  // Labels that were already in the requested state.
+  unchanged: string[]
+}
+
+export type LabelRepoConfig = {
+  labels: string[]
+  orgSlug: string
+  remove: boolean
+  repoName: string
+}
+
+const COMMAND_PATH = 'socket repository label'
+
+export async function labelRepo({
+  labels,
+  orgSlug,
+  remove,
+  repoName,
+}: LabelRepoConfig): Promise<CResult<LabelRepoResult>> {
+  const repoCResult = await fetchViewRepo(orgSlug, repoName, {
+    commandPath: COMMAND_PATH,
+  })
+  if (!repoCResult.ok) {
+    return repoCResult
+  }
+  const repositoryId = repoCResult.data.id
+
+  const labelsCResult = await fetchRepoLabels(orgSlug)
+  if (!labelsCResult.ok) {
+    return labelsCResult
+  }
+  const orgLabels = labelsCResult.data
+
+  const changed: string[] = []
+  const unchanged: string[] = []
+  for (const name of new Set(labels)) {
+    let label = orgLabels.find(l => l.name === name)
+    if (!label) {
+      if (remove) {
+        unchanged.push(name)
+        continue
+      }
+      const createCResult = await fetchCreateRepoLabel(orgSlug, name)
+      if (!createCResult.ok) {
+        return createCResult
+      }
+      label = createCResult.data
+    }
+    if (label.repository_ids?.includes(repositoryId) === !remove) {
+      unchanged.push(name)
+      continue
+    }
+    const assignCResult = await fetchAssignRepoLabel(
+      orgSlug,
+      label.id,
+      repositoryId,
+      remove ? 'disassociate' : 'associate',
+    )
+    if (!assignCResult.ok) {
+      return assignCResult
+    }
+    changed.push(name)
+  }
+
+  return {
+    ok: true,
+    data: {
+      action: remove ? 'removed' : 'added',
+      changed,
+      repository: repoName,
+      unchanged,
+    },
+  }
+}
+
+export async function handleLabelRepo(
+  config: LabelRepoConfig,
+  outputKind: OutputKind,
+): Promise<void> {
+  await outputLabelRepo(await labelRepo(config), outputKind)
+}
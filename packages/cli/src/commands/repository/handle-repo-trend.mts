@@ -0,0 +1,105 @@
+import { fetchRepoScanHistory } from './fetch-repo-scan-history.mts'
+import { fetchViewRepo } from './fetch-view-repo.mts'
+import { outputRepoTrend } from './output-repo-trend.mts'
+import { selectTrendScans, summarizeScanArtifacts } from './repo-trend.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+import { runApiBatches } from '../../util/socket/api-batch.mts'
+
+import type { RepoTrendOutputKind } from './output-repo-trend.mts'
+import type { RepoTrendData, RepoTrendPoint } from './repo-trend.mts'
+import type { CResult } from '../../types.mts'
+
+// Full scans are large, so fewer of them are fetched at once than the
+// purl batches of other commands.
+const SCAN_FETCH_CONCURRENCY = 3
+
+export type RepoTrendConfig = {
+  branch: string
+  days: number
+  orgSlug: string
+  points: number
+  repoName: string
+}
+
+export async function getRepoTrend({
+  branch: branchFlag,
+  days,
+  orgSlug,
+  points,
+  repoName,
+}: RepoTrendConfig): Promise<CResult<RepoTrendData>> {
+  const commandPath = 'socket repository trend'
+
+  let branch = branchFlag
+  if (!branch) {
+    const repoCResult = await fetchViewRepo(orgSlug, repoName, { commandPath })
+    if (!repoCResult.ok) {
+      return repoCResult
+    }
+    branch = repoCResult.data.default_branch || 'main'
+  }
+
+  const sinceMs = Date.now() - days * 24 * 60 * 60 * 1000
+  const scansCResult = await fetchRepoScanHistory(
+    {
+      branch,
+      orgSlug,
+      repoName,
+      since: Math.floor(sinceMs / 1000),
+    },
+    { commandPath },
+  )
+  if (!scansCResult.ok) {
+    return scansCResult
+  }
+
+  const scans = selectTrendScans(scansCResult.data, points)
+  if (!scans.length) {
+    return {
+      ok: false,
+      message: 'No scans found',
+      cause: `There are no scans of ${repoName} on branch ${branch} in the last ${days} days`,
+    }
+  }
+
+  const pointsCResult = await runApiBatches<
+    (typeof scans)[number],
+    RepoTrendPoint
+  >(
+    scans,
+    async batch => {
+      const scan = batch[0]!
+      const artifactsCResult = await fetchScan(orgSlug, scan.id)
+      if (!artifactsCResult.ok) {
+        return artifactsCResult
+      }
+      return {
+        ok: true,
+        data: [summarizeScanArtifacts(scan, artifactsCResult.data)],
+      }
+    },
+    { batchSize: 1, concurrency: SCAN_FETCH_CONCURRENCY },
+  )
+  if (!pointsCResult.ok) {
+    return pointsCResult
+  }
+
+  return {
+    ok: true,
+    data: {
+      branch,
+      points: pointsCResult.data,
+      repo: repoName,
+      since: new Date(sinceMs).toISOString(),
+    },
+  }
+}
+
+export async function handleRepoTrend({
+  outputKind,
+  ...config
+}: RepoTrendConfig & { outputKind: RepoTrendOutputKind }): Promise<void> {
+  const result = await getRepoTrend(config)
+
+  await outputRepoTrend(result, outputKind)
+}
@@ -0,0 +1,23 @@
+import { fetchPatchRepo } from './fetch-patch-repo.mts'
+import { outputSetDefaultBranch } from './output-set-default-branch.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export async function handleSetDefaultBranch(
+  orgSlug: string,
+  repoName: string,
+  branch: string,
+  outputKind: OutputKind,
+): Promise<void> {
+  const data = await fetchPatchRepo(
+    orgSlug,
+    repoName,
+    { default_branch: branch },
+    {
+      commandPath: 'socket repository set-default-branch',
+      description: 'to set the default branch of a repository',
+    },
+  )
+
+  await outputSetDefaultBranch(data, { branch, repoName }, outputKind)
+}
@@ -0,0 +1,31 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+const logger = getDefaultLogger()
+
+export async function outputArchiveRepo(
+  result: CResult<SocketSdkSuccessResult<'updateRepository'>['data']>,
+  { archived, repoName }: { archived: boolean; repoName: string },
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  logger.success(
+    `Repository \`${repoName}\` ${archived ? 'archived' : 'unarchived'} successfully`,
+  )
+}
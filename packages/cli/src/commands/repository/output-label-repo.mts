@@ -0,0 +1,40 @@
+import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { LabelRepoResult } from './handle-label-repo.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+const logger = getDefaultLogger()
+
+export async function outputLabelRepo(
+  result: CResult<LabelRepoResult>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { action, changed, repository, unchanged } = result.data
+  if (changed.length) {
+    logger.success(
+      `${pluralize('Label', { count: changed.length })} ${joinAnd(changed)} ${action} ${action === 'added' ? 'to' : 'from'} repository \`${repository}\``,
+    )
+  }
+  if (unchanged.length) {
+    logger.info(
+      `Repository \`${repository}\` ${action === 'added' ? 'already has' : 'does not have'} ${pluralize('label', { count: unchanged.length })} ${joinAnd(unchanged)}`,
+    )
+  }
+}
@@ -3,6 +3,7 @@ import colors from 'yoctocolors-cjs'
 
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
+import { recordCompletionValues } from '../../util/cli/completion-history.mts'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
 import { serializeResultJson } from '../../util/output/result-json.mts'
 
@@ -22,6 +23,9 @@ export async function outputListRepos(
 ): Promise<void> {
   if (!result.ok) {
     process.exitCode = result.code ?? 1
+  } else {
+    // Offer the listed repos to shell completion.
+    await recordCompletionValues('repo', result.data.results.map(r => r.name))
   }
 
   if (outputKind === 'json') {
@@ -0,0 +1,157 @@
+import colors from 'yoctocolors-cjs'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import {
+  REPO_TREND_ALERT_SEVERITIES,
+  REPO_TREND_SCORE_CATEGORIES,
+  formatRepoTrendCsv,
+  getRepoTrendCells,
+  getSparkline,
+} from './repo-trend.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { RepoTrendData, RepoTrendPoint } from './repo-trend.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export type RepoTrendOutputKind = OutputKind | 'csv'
+
+type TrendMetric = {
+  // Whether a rising value is an improvement.
+  higherIsBetter: boolean
+  label: string
+  value: (point: RepoTrendPoint) => number | undefined
+}
+
+const METRICS: TrendMetric[] = [
+  {
+    higherIsBetter: true,
+    label: 'Supply chain',
+    value: p => p.score.supplyChain,
+  },
+  {
+    higherIsBetter: true,
+    label: 'Maintenance',
+    value: p => p.score.maintenance,
+  },
+  { higherIsBetter: true, label: 'Quality', value: p => p.score.quality },
+  {
+    higherIsBetter: true,
+    label: 'Vulnerability',
+    value: p => p.score.vulnerability,
+  },
+  { higherIsBetter: true, label: 'License', value: p => p.score.license },
+  {
+    higherIsBetter: false,
+    label: 'Critical alerts',
+    value: p => p.alerts.critical,
+  },
+  { higherIsBetter: false, label: 'High alerts', value: p => p.alerts.high },
+  {
+    higherIsBetter: false,
+    label: 'Medium alerts',
+    value: p => p.alerts.middle,
+  },
+  { higherIsBetter: false, label: 'Low alerts', value: p => p.alerts.low },
+  { higherIsBetter: false, label: 'Packages', value: p => p.packages },
+]
+
+const TABLE_COLUMNS = [
+  'date',
+  'scanId',
+  'packages',
+  ...REPO_TREND_SCORE_CATEGORIES,
+  ...REPO_TREND_ALERT_SEVERITIES,
+]
+
+const TABLE_TITLES = [
+  'Date',
+  'Scan',
+  'Packages',
+  'Supply Chain',
+  'Maintenance',
+  'Quality',
+  'Vulnerability',
+  'License',
+  'Critical',
+  'High',
+  'Medium',
+  'Low',
+]
+
+function formatChange(metric: TrendMetric, points: RepoTrendPoint[]): string {
+  const values = points
+    .map(metric.value)
+    .filter((v): v is number => v !== undefined)
+  if (!values.length) {
+    return ''
+  }
+  const first = values[0]!
+  const last = values.at(-1)!
+  const delta = last - first
+  const change = `${first} → ${last}`
+  if (!delta) {
+    return change
+  }
+  const color = delta > 0 === metric.higherIsBetter ? colors.green : colors.red
+  return `${change} ${color(`(${delta > 0 ? '+' : ''}${delta})`)}`
+}
+
+function getTitle(data: RepoTrendData): string {
+  const { branch, points, repo } = data
+  const from = points[0]!.createdAt.slice(0, 10)
+  const to = points.at(-1)!.createdAt.slice(0, 10)
+  return `Trend of ${repo} on ${branch}: ${points.length} ${pluralize('scan', { count: points.length })} from ${from} to ${to}`
+}
+
+export async function outputRepoTrend(
+  result: CResult<RepoTrendData>,
+  outputKind: RepoTrendOutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === 'csv') {
+    logger.log(formatRepoTrendCsv(result.data))
+    return
+  }
+
+  const { points } = result.data
+
+  if (outputKind === 'markdown') {
+    const rows = points.map(point => {
+      const cells = getRepoTrendCells(point)
+      return Object.fromEntries(TABLE_COLUMNS.map((c, i) => [c, cells[i]!]))
+    })
+    logger.log(mdHeader('Repository Trend'))
+    logger.log('')
+    logger.log(`${getTitle(result.data)}.`)
+    logger.log('')
+    logger.log(mdTable(rows, TABLE_COLUMNS, TABLE_TITLES))
+    return
+  }
+
+  const width = Math.max(...METRICS.map(m => m.label.length)) + 2
+  logger.log(getTitle(result.data))
+  logger.log('')
+  for (const metric of METRICS) {
+    logger.log(
+      `  ${metric.label.padEnd(width)}${getSparkline(points.map(metric.value))}  ${formatChange(metric, points)}`,
+    )
+  }
+}
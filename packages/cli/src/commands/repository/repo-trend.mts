@@ -0,0 +1,173 @@
+/**
+ * Trend data of `socket repository trend`: a sample of the scans of one
+ * branch over a time window, each summarized into average package scores
+ * and alert counts, rendered as sparklines, a table or CSV.
+ */
+
+import { formatCsv } from '../../util/output/csv.mts'
+
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export const REPO_TREND_SCORE_CATEGORIES = [
+  'supplyChain',
+  'maintenance',
+  'quality',
+  'vulnerability',
+  'license',
+] as const
+
+export type RepoTrendScoreCategory =
+  (typeof REPO_TREND_SCORE_CATEGORIES)[number]
+
+export const REPO_TREND_ALERT_SEVERITIES = [
+  'critical',
+  'high',
+  'middle',
+  'low',
+] as const
+
+export type RepoTrendSeverity = (typeof REPO_TREND_ALERT_SEVERITIES)[number]
+
+export interface RepoTrendPoint {
+  alerts: Record<RepoTrendSeverity, number>
+  createdAt: string
+  packages: number
+  scanId: string
+  // Average over the packages of the scan, 0 to 100. Undefined when no
+  // package of the scan has a score.
+  score: Record<RepoTrendScoreCategory, number | undefined>
+}
+
+export interface RepoTrendData {
+  branch: string
+  points: RepoTrendPoint[]
+  repo: string
+  since: string
+}
+
+export interface RepoTrendScan {
+  created_at?: string | null | undefined
+  id: string
+}
+
+export const REPO_TREND_CSV_COLUMNS = [
+  'date',
+  'scanId',
+  'packages',
+  ...REPO_TREND_SCORE_CATEGORIES,
+  ...REPO_TREND_ALERT_SEVERITIES,
+] as const
+
+const SPARK_CHARS = '▁▂▃▄▅▆▇█'
+
+/**
+ * Pick at most `points` scans spread over time: the time range of the scans
+ * is cut into equal periods and the last scan of each one stands for it.
+ * Periods without a scan are left out.
+ */
+export function selectTrendScans<T extends RepoTrendScan>(
+  scans: T[],
+  points: number,
+): T[] {
+  const dated = scans
+    .filter(s => s.created_at && !Number.isNaN(Date.parse(s.created_at)))
+    .toSorted((a, b) => Date.parse(a.created_at!) - Date.parse(b.created_at!))
+  if (dated.length <= points) {
+    return dated
+  }
+  const first = Date.parse(dated[0]!.created_at!)
+  const last = Date.parse(dated.at(-1)!.created_at!)
+  const period = (last - first) / points
+  const picked = new Map<number, T>()
+  for (let i = 0, { length } = dated; i < length; i += 1) {
+    const scan = dated[i]!
+    const offset = Date.parse(scan.created_at!) - first
+    const bucket = period
+      ? Math.min(points - 1, Math.floor(offset / period))
+      : 0
+    picked.set(bucket, scan)
+  }
+  return [...picked.values()]
+}
+
+export function summarizeScanArtifacts(
+  scan: RepoTrendScan,
+  artifacts: SocketArtifact[],
+): RepoTrendPoint {
+  const alerts: Record<RepoTrendSeverity, number> = {
+    critical: 0,
+    high: 0,
+    low: 0,
+    middle: 0,
+  }
+  const sums: Record<RepoTrendScoreCategory, number> = {
+    license: 0,
+    maintenance: 0,
+    quality: 0,
+    supplyChain: 0,
+    vulnerability: 0,
+  }
+  let scored = 0
+  for (let i = 0, { length } = artifacts; i < length; i += 1) {
+    const artifact = artifacts[i]!
+    const artifactAlerts = artifact.alerts ?? []
+    for (let j = 0, { length: count } = artifactAlerts; j < count; j += 1) {
+      const severity = artifactAlerts[j]!.severity as RepoTrendSeverity
+      if (severity in alerts) {
+        alerts[severity] += 1
+      }
+    }
+    const s = artifact.score
+    if (s) {
+      scored += 1
+      for (const category of REPO_TREND_SCORE_CATEGORIES) {
+        sums[category] += s[category] ?? 0
+      }
+    }
+  }
+  const score = {} as RepoTrendPoint['score']
+  for (const category of REPO_TREND_SCORE_CATEGORIES) {
+    score[category] = scored
+      ? Math.round((sums[category] / scored) * 100)
+      : undefined
+  }
+  return {
+    alerts,
+    createdAt: scan.created_at ?? '',
+    packages: artifacts.length,
+    scanId: scan.id,
+    score,
+  }
+}
+
+/**
+ * Draw values as a row of block characters, scaled between their lowest and
+ * highest value. Missing values are left blank.
+ */
+export function getSparkline(values: Array<number | undefined>): string {
+  const known = values.filter((v): v is number => v !== undefined)
+  const min = Math.min(...known)
+  const range = Math.max(...known) - min
+  const top = SPARK_CHARS.length - 1
+  return values
+    .map(v =>
+      v === undefined
+        ? ' '
+        : SPARK_CHARS[range ? Math.round(((v - min) / range) * top) : 0],
+    )
+    .join('')
+}
+
+export function getRepoTrendCells(point: RepoTrendPoint): string[] {
+  return [
+    point.createdAt.slice(0, 10),
+    point.scanId,
+    String(point.packages),
+    ...REPO_TREND_SCORE_CATEGORIES.map(c => String(point.score[c] ?? '')),
+    ...REPO_TREND_ALERT_SEVERITIES.map(s => String(point.alerts[s])),
+  ]
+}
+
+export function formatRepoTrendCsv(data: RepoTrendData): string {
+  return formatCsv(REPO_TREND_CSV_COLUMNS, data.points.map(getRepoTrendCells))
+}
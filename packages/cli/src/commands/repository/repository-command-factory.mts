@@ -21,6 +21,7 @@ import { checkCommandInput } from '../../util/validation/check-input.mts'
 
 import type { MeowFlags } from '../../flags.mts'
 import type { OutputKind } from '../../types.mjs'
+import type { DryRunRequest } from '../../util/dry-run/output.mts'
 import type {
   CliCommandConfig,
   CliCommandContext,
@@ -28,11 +29,29 @@ import type {
 
 const logger = getDefaultLogger()
 
+export type RepositoryCommandArg = {
+  // Shown in the usage, e.g. `BRANCH`.
+  name: string
+  message: string
+}
+
 export type RepositoryCommandSpec = {
   commandName: string
   description: string
+  // The API call of the command, shown by --dry-run.
+  dryRunRequest?:
+    | ((params: {
+        args: string[]
+        orgSlug: string
+        repoName: string
+        flags: Record<string, unknown>
+      }) => DryRunRequest)
+    | undefined
+  // Required arguments after the repository name.
+  extraArgs?: RepositoryCommandArg[] | undefined
   extraFlags?: MeowFlags | undefined
   handler: (params: {
+    args: string[]
     orgSlug: string
     repoName: string
     outputKind: OutputKind
@@ -41,6 +60,8 @@ export type RepositoryCommandSpec = {
   helpDescription?: string | undefined
   helpExamples: string[]
   hidden?: boolean | undefined
+  // Dry-run description of the change, for commands that write the repo.
+  mutation?: string | undefined
   needsRepoName?: boolean | undefined
 }
 
@@ -92,7 +113,7 @@ export function createRepositoryCommand(spec: RepositoryCommandSpec) {
         },
         help: (command, helpConfig) => `
     Usage
-      $ ${command} [options]${spec.needsRepoName !== false ? ' <REPO>' : ''}
+      $ ${command} [options]${spec.needsRepoName !== false ? ' <REPO>' : ''}${(spec.extraArgs ?? []).map(a => ` <${a.name}>`).join('')}
 
     API Token Requirements
       ${getFlagApiRequirementsOutput(`${parentName}:${spec.commandName}`)}
@@ -121,7 +142,7 @@ ${spec.helpExamples.map(ex => `      $ ${command} ${ex}`).join('\n')}
 
       const noLegacy = !cli.flags['repoName']
 
-      const [repoName = ''] = cli.input
+      const [repoName = '', ...args] = cli.input
 
       const hasApiToken = hasDefaultApiToken()
 
@@ -156,6 +177,15 @@ ${spec.helpExamples.map(ex => `      $ ${command} ${ex}`).join('\n')}
           test: !!repoName,
         })
       }
+      const extraArgs = spec.extraArgs ?? []
+      for (let i = 0, { length } = extraArgs; i < length; i += 1) {
+        validations.push({
+          fail: 'missing',
+          message: extraArgs[i]!.message,
+          nook: false,
+          test: !!args[i],
+        })
+      }
 
       validations.push(
         {
@@ -179,18 +209,44 @@ ${spec.helpExamples.map(ex => `      $ ${command} ${ex}`).join('\n')}
 
       if (dryRun) {
         const identifier = repoName ? `${orgSlug}/${repoName}` : orgSlug
+        const request = spec.dryRunRequest?.({
+          args,
+          flags: cli.flags,
+          orgSlug,
+          repoName,
+        })
         if (spec.commandName === 'create') {
-          outputDryRunUpload('repository', {
-            organization: orgSlug,
-            repository: repoName,
-          })
+          outputDryRunUpload(
+            'repository',
+            {
+              organization: orgSlug,
+              repository: repoName,
+            },
+            request,
+          )
         } else if (spec.commandName === 'update') {
-          outputDryRunUpload('repository (update)', {
-            organization: orgSlug,
-            repository: repoName,
-          })
+          outputDryRunUpload(
+            'repository (update)',
+            {
+              organization: orgSlug,
+              repository: repoName,
+            },
+            request,
+          )
         } else if (spec.commandName === 'del') {
-          outputDryRunDelete('repository', identifier)
+          outputDryRunDelete('repository', identifier, request)
+        } else if (spec.mutation) {
+          outputDryRunUpload(
+            spec.mutation,
+            {
+              organization: orgSlug,
+              repository: repoName,
+              ...Object.fromEntries(
+                extraArgs.map((a, i) => [a.name.toLowerCase(), args[i]]),
+              ),
+            },
+            request,
+          )
         } else {
           outputDryRunFetch(`repository ${identifier}`, {
             organization: orgSlug,
@@ -201,6 +257,7 @@ ${spec.helpExamples.map(ex => `      $ ${command} ${ex}`).join('\n')}
       }
 
       await spec.handler({
+        args,
         flags: cli.flags,
         orgSlug,
         outputKind,
@@ -0,0 +1,223 @@
+import { handleSbomExport } from './handle-sbom-export.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { SBOM_ENCODING, SBOM_FORMAT } from './types.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+// Flags interface for type safety.
+export interface SbomExportFlags {
+  encoding: string
+  format: string
+  interactive: boolean
+  json: boolean
+  markdown: boolean
+  org: string
+}
+
+export const SBOM_ENCODINGS: Readonly<Record<SBOM_FORMAT, SBOM_ENCODING[]>> = {
+  __proto__: null,
+  cyclonedx: ['json', 'xml'],
+  spdx: ['json', 'tag-value'],
+} as unknown as Record<SBOM_FORMAT, SBOM_ENCODING[]>
+
+/**
+ * Pick the document encoding from the output file extension.
+ */
+export function inferSbomEncoding(filepath: string): SBOM_ENCODING {
+  if (filepath.endsWith('.xml')) {
+    return 'xml'
+  }
+  if (filepath.endsWith('.spdx') || filepath.endsWith('.tv')) {
+    return 'tag-value'
+  }
+  return 'json'
+}
+
+export const CMD_NAME = 'export'
+
+const description = 'Export a completed scan as a CycloneDX or SPDX SBOM'
+
+const hidden = false
+
+export const cmdSbomExport: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      encoding: {
+        type: 'string',
+        default: '',
+        description:
+          'Document encoding: json, xml (cyclonedx) or tag-value (spdx). Defaults to the OUTPUT_FILE extension, or json',
+      },
+      format: {
+        type: 'string',
+        default: 'cyclonedx',
+        description: 'SBOM standard to export. Supported: cyclonedx, spdx',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID> [OUTPUT_FILE]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Converts a completed full scan into a CycloneDX 1.5 or SPDX 2.3 document.
+    Every package is listed with its purl and declared license expression.
+
+    - cyclonedx: JSON or XML. Socket scores and alerts are included as
+      "socket:*" component properties and the dependency graph is preserved.
+    - spdx: JSON or tag-value. The resolved dependency tree is expressed as
+      DEPENDS_ON relationships, starting from the scan's direct dependencies.
+
+    When no output path is given the document is sent to stdout.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 > bom.json
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 ./bom.xml
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format cyclonedx --encoding xml
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format spdx ./bom.spdx.json
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format spdx ./bom.spdx
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    encoding: encodingFlag,
+    format,
+    interactive,
+    json,
+    markdown,
+    org: orgFlag,
+  } = cli.flags as unknown as SbomExportFlags
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const [scanId = '', filepath = ''] = cli.input
+
+  const encoding = (encodingFlag ||
+    inferSbomEncoding(filepath)) as SBOM_ENCODING
+
+  const supportedEncodings = SBOM_ENCODINGS[format as SBOM_FORMAT]
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID to export',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !!supportedEncodings,
+      message: 'The --format flag must be one of: cyclonedx, spdx',
+      fail: `unsupported format "${format}"`,
+    },
+    {
+      nook: true,
+      test: !supportedEncodings || supportedEncodings.includes(encoding),
+      message: `The --encoding flag must be one of: ${(supportedEncodings ?? []).join(', ')}`,
+      fail: `unsupported encoding "${encoding}"`,
+    },
+    {
+      nook: true,
+      test: !markdown,
+      message: 'Markdown output is not supported for SBOM documents',
+      fail: 'remove --markdown',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scan for SBOM export', {
+      organization: orgSlug,
+      scanId,
+      format,
+      encoding,
+      output: filepath || 'stdout',
+    })
+    return
+  }
+
+  await handleSbomExport({
+    encoding,
+    filepath,
+    format: format as SBOM_FORMAT,
+    orgSlug,
+    outputKind,
+    scanId,
+  })
+}
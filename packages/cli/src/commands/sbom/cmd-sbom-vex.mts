@@ -0,0 +1,212 @@
+import { handleSbomVex } from './handle-sbom-vex.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { VEX_FORMAT } from './types.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+// Flags interface for type safety.
+export interface SbomVexFlags {
+  author: string
+  format: string
+  interactive: boolean
+  json: boolean
+  markdown: boolean
+  org: string
+  reachability: boolean
+}
+
+export const VEX_FORMATS: readonly VEX_FORMAT[] = ['openvex', 'csaf']
+
+export const CMD_NAME = 'vex'
+
+const description =
+  'Export the triage of the vulnerabilities of a scan as an OpenVEX or CSAF VEX document'
+
+const hidden = false
+
+export const cmdSbomVex: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      author: {
+        type: 'string',
+        default: '',
+        description:
+          'Author of the VEX statements. Defaults to the organization slug',
+      },
+      format: {
+        type: 'string',
+        default: 'openvex',
+        description: 'VEX standard to export. Supported: openvex, csaf',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      reachability: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Mark the vulnerabilities of packages the sources in the current dir do not import as not affected',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID> [OUTPUT_FILE]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Writes one VEX statement for each CVE or GHSA alert of a completed scan,
+    so downstream consumers of your SBOM see how each one was triaged:
+
+    - not_affected: the alert is waived by an exception in socket.policy.yml
+      (its reason becomes the impact statement), or ignored by the org
+      triage. With --reachability, also the packages the project sources do
+      not import, justified as vulnerable_code_not_in_execute_path.
+    - under_investigation: every other vulnerability.
+
+    Products are identified by purl. Pass the document to --vex of
+    \`socket scan report\` or \`socket scan check\` to leave out the alerts it
+    marks as not affected or fixed.
+
+    When no output path is given the document is sent to stdout.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 > socket.openvex.json
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format csaf ./vex.csaf.json
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --reachability --author "ACME Security"
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    author: authorFlag,
+    format,
+    interactive,
+    json,
+    markdown,
+    org: orgFlag,
+    reachability,
+  } = cli.flags as unknown as SbomVexFlags
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const [scanId = '', filepath = ''] = cli.input
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID to export',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: VEX_FORMATS.includes(format as VEX_FORMAT),
+      message: `The --format flag must be one of: ${VEX_FORMATS.join(', ')}`,
+      fail: `unsupported format "${format}"`,
+    },
+    {
+      nook: true,
+      test: !markdown,
+      message: 'Markdown output is not supported for VEX documents',
+      fail: 'remove --markdown',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  const author = authorFlag || orgSlug
+
+  if (dryRun) {
+    outputDryRunFetch('scan for VEX export', {
+      organization: orgSlug,
+      scanId,
+      format,
+      author,
+      reachability,
+      output: filepath || 'stdout',
+    })
+    return
+  }
+
+  await handleSbomVex({
+    author,
+    cwd: process.cwd(),
+    filepath,
+    format: format as VEX_FORMAT,
+    importReachability: reachability,
+    orgSlug,
+    outputKind,
+    scanId,
+  })
+}
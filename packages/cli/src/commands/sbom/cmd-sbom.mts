@@ -0,0 +1,12 @@
+import { cmdSbomExport } from './cmd-sbom-export.mts'
+import { cmdSbomVex } from './cmd-sbom-vex.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdSbom = defineSubcommandGroup({
+  name: 'sbom',
+  description: 'Export Socket scans as Software Bill of Materials documents',
+  subcommands: {
+    export: cmdSbomExport,
+    vex: cmdSbomVex,
+  },
+})
@@ -0,0 +1,318 @@
+/**
+ * CycloneDX 1.5 rendering of a completed Socket full scan.
+ *
+ * Each scan artifact becomes a `library` component keyed by its purl. Licenses
+ * are emitted as SPDX expressions, Socket scores and alerts are carried as
+ * `socket:*` properties so tools like Dependency-Track keep the Socket context,
+ * and the artifact dependency edges become the `dependencies` graph. The scan
+ * itself is described by `metadata.component`, which depends on every direct
+ * dependency.
+ */
+
+import crypto from 'node:crypto'
+
+import { getCliVersion } from '../../env/cli-version.mts'
+import { renderXmlDocument } from '../../util/output/xml.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { XmlNode } from '../../util/output/xml.mts'
+
+export const CYCLONEDX_SPEC_VERSION = '1.5'
+
+export type CycloneDxProperty = { name: string; value: string }
+
+export type CycloneDxComponent = {
+  type: 'application' | 'library'
+  'bom-ref': string
+  group?: string | undefined
+  name: string
+  version?: string | undefined
+  purl?: string | undefined
+  author?: string | undefined
+  licenses?: Array<{ expression: string }> | undefined
+  properties?: CycloneDxProperty[] | undefined
+}
+
+export type CycloneDxDependency = { ref: string; dependsOn: string[] }
+
+export type CycloneDxBom = {
+  bomFormat: 'CycloneDX'
+  specVersion: string
+  serialNumber: string
+  version: number
+  metadata: {
+    timestamp: string
+    tools: {
+      components: Array<{
+        type: 'application'
+        group: string
+        name: string
+        version: string
+      }>
+    }
+    component: CycloneDxComponent
+    properties: CycloneDxProperty[]
+  }
+  components: CycloneDxComponent[]
+  dependencies: CycloneDxDependency[]
+}
+
+export type GenerateCycloneDxOptions = {
+  orgSlug: string
+  scanId: string
+  serialNumber?: string | undefined
+  timestamp?: string | undefined
+}
+
+/**
+ * Stable bom-ref for an artifact. Purls are unique per scan except when the
+ * same package version is resolved under multiple artifact ids, so the id is
+ * appended only for collisions.
+ */
+export function getArtifactRefs(
+  scan: SocketArtifact[],
+): Map<SocketArtifact, string> {
+  const refs = new Map<SocketArtifact, string>()
+  const seen = new Set<string>()
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    let ref = getArtifactPurlString(artifact)
+    if (seen.has(ref)) {
+      ref = `${ref}#${artifact.id ?? i}`
+    }
+    seen.add(ref)
+    refs.set(artifact, ref)
+  }
+  return refs
+}
+
+export function getArtifactProperties(
+  artifact: SocketArtifact,
+): CycloneDxProperty[] {
+  const properties: CycloneDxProperty[] = []
+  const score = artifact.score as Record<string, number> | undefined
+  if (score) {
+    const keys = Object.keys(score).sort()
+    for (let i = 0, { length } = keys; i < length; i += 1) {
+      const key = keys[i]!
+      properties.push({
+        name: `socket:score:${key}`,
+        value: String(score[key]),
+      })
+    }
+  }
+  if (artifact.direct !== undefined) {
+    properties.push({ name: 'socket:direct', value: String(!!artifact.direct) })
+  }
+  if (artifact.dev !== undefined) {
+    properties.push({ name: 'socket:dev', value: String(!!artifact.dev) })
+  }
+  const alerts = artifact.alerts ?? []
+  for (let i = 0, { length } = alerts; i < length; i += 1) {
+    const alert = alerts[i]!
+    properties.push({
+      name: `socket:alert:${alert.type}`,
+      value: alert.severity ?? 'unknown',
+    })
+  }
+  const manifestFiles = artifact.manifestFiles ?? []
+  for (let i = 0, { length } = manifestFiles; i < length; i += 1) {
+    properties.push({ name: 'socket:manifest', value: manifestFiles[i]!.file })
+  }
+  return properties
+}
+
+/**
+ * Build the artifact dependency graph keyed by bom-ref. Shared by the
+ * CycloneDX and SPDX exporters.
+ */
+export function getDependencyEdges(
+  scan: SocketArtifact[],
+  refs: Map<SocketArtifact, string>,
+): CycloneDxDependency[] {
+  const refById = new Map<string, string>()
+  for (const [artifact, ref] of refs) {
+    if (artifact.id) {
+      refById.set(artifact.id, ref)
+    }
+  }
+  return scan.map(artifact => {
+    const deps = (artifact.dependencies ?? []) as string[]
+    const dependsOn: string[] = []
+    for (let i = 0, { length } = deps; i < length; i += 1) {
+      const ref = refById.get(deps[i]!)
+      if (ref && !dependsOn.includes(ref)) {
+        dependsOn.push(ref)
+      }
+    }
+    return { ref: refs.get(artifact)!, dependsOn }
+  })
+}
+
+export function generateCycloneDx(
+  scan: SocketArtifact[],
+  { orgSlug, scanId, serialNumber, timestamp }: GenerateCycloneDxOptions,
+): CycloneDxBom {
+  const refs = getArtifactRefs(scan)
+  const rootRef = `socket-scan:${scanId}`
+
+  const components: CycloneDxComponent[] = scan.map(artifact => {
+    const author = Array.isArray(artifact.author)
+      ? artifact.author.join(', ')
+      : (artifact.author as string | undefined)
+    const properties = getArtifactProperties(artifact)
+    return {
+      type: 'library',
+      'bom-ref': refs.get(artifact)!,
+      ...(artifact.namespace ? { group: artifact.namespace } : {}),
+      name: artifact.name ?? '',
+      ...(artifact.version ? { version: artifact.version } : {}),
+      purl: getArtifactPurlString(artifact),
+      ...(author ? { author } : {}),
+      ...(artifact.license
+        ? { licenses: [{ expression: artifact.license }] }
+        : {}),
+      ...(properties.length ? { properties } : {}),
+    }
+  })
+
+  const directRefs = scan
+    .filter(artifact => artifact.direct)
+    .map(artifact => refs.get(artifact)!)
+
+  return {
+    bomFormat: 'CycloneDX',
+    specVersion: CYCLONEDX_SPEC_VERSION,
+    serialNumber: `urn:uuid:${serialNumber ?? crypto.randomUUID()}`,
+    version: 1,
+    metadata: {
+      timestamp: timestamp ?? new Date().toISOString(),
+      tools: {
+        components: [
+          {
+            type: 'application',
+            group: 'socket.dev',
+            name: 'socket',
+            version: getCliVersion() || '0.0.0',
+          },
+        ],
+      },
+      component: {
+        type: 'application',
+        'bom-ref': rootRef,
+        name: scanId,
+      },
+      properties: [
+        { name: 'socket:org', value: orgSlug },
+        { name: 'socket:scan_id', value: scanId },
+      ],
+    },
+    components,
+    dependencies: [
+      { ref: rootRef, dependsOn: directRefs },
+      ...getDependencyEdges(scan, refs),
+    ],
+  }
+}
+
+function propertiesToXml(
+  properties: CycloneDxProperty[] | undefined,
+): XmlNode | undefined {
+  if (!properties?.length) {
+    return undefined
+  }
+  return {
+    name: 'properties',
+    children: properties.map(p => ({
+      name: 'property',
+      attrs: { name: p.name },
+      text: p.value,
+    })),
+  }
+}
+
+function componentToXml(component: CycloneDxComponent): XmlNode {
+  const children: Array<XmlNode | undefined> = [
+    component.author ? { name: 'author', text: component.author } : undefined,
+    component.group ? { name: 'group', text: component.group } : undefined,
+    { name: 'name', text: component.name },
+    component.version
+      ? { name: 'version', text: component.version }
+      : undefined,
+    component.licenses?.length
+      ? {
+          name: 'licenses',
+          children: component.licenses.map(l => ({
+            name: 'expression',
+            text: l.expression,
+          })),
+        }
+      : undefined,
+    component.purl ? { name: 'purl', text: component.purl } : undefined,
+    propertiesToXml(component.properties),
+  ]
+  return {
+    name: 'component',
+    attrs: { type: component.type, 'bom-ref': component['bom-ref'] },
+    children: children.filter(Boolean) as XmlNode[],
+  }
+}
+
+/**
+ * Serialize a CycloneDX BOM using the 1.5 XML schema. Element order follows
+ * the XSD sequence, which is stricter than the JSON schema.
+ */
+export function cycloneDxToXml(bom: CycloneDxBom): string {
+  const { metadata } = bom
+  return renderXmlDocument({
+    name: 'bom',
+    attrs: {
+      xmlns: `http://cyclonedx.org/schema/bom/${bom.specVersion}`,
+      serialNumber: bom.serialNumber,
+      version: bom.version,
+    },
+    children: [
+      {
+        name: 'metadata',
+        children: [
+          { name: 'timestamp', text: metadata.timestamp },
+          {
+            name: 'tools',
+            children: [
+              {
+                name: 'components',
+                children: metadata.tools.components.map(tool => ({
+                  name: 'component',
+                  attrs: { type: tool.type },
+                  children: [
+                    { name: 'group', text: tool.group },
+                    { name: 'name', text: tool.name },
+                    { name: 'version', text: tool.version },
+                  ],
+                })),
+              },
+            ],
+          },
+          componentToXml(metadata.component),
+          propertiesToXml(metadata.properties)!,
+        ],
+      },
+      {
+        name: 'components',
+        children: bom.components.map(componentToXml),
+      },
+      {
+        name: 'dependencies',
+        children: bom.dependencies.map(dep => ({
+          name: 'dependency',
+          attrs: { ref: dep.ref },
+          children: dep.dependsOn.map(ref => ({
+            name: 'dependency',
+            attrs: { ref },
+          })),
+        })),
+      },
+    ],
+  })
+}
@@ -0,0 +1,251 @@
+/**
+ * SPDX 2.3 rendering of a completed Socket full scan, as JSON or tag-value.
+ *
+ * The scan is the document's described package. Every artifact becomes an SPDX
+ * package with a purl external reference, and the resolved dependency tree is
+ * expressed as DEPENDS_ON relationships: the root depends on each direct
+ * dependency and each artifact depends on its own resolved dependencies.
+ */
+
+import crypto from 'node:crypto'
+
+import { getArtifactRefs } from './generate-cyclonedx.mts'
+import { SOCKET_WEBSITE_URL } from '../../constants/socket.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export const SPDX_VERSION = 'SPDX-2.3'
+
+export const SPDX_NOASSERTION = 'NOASSERTION'
+
+export type SpdxPackage = {
+  SPDXID: string
+  name: string
+  versionInfo?: string | undefined
+  supplier?: string | undefined
+  downloadLocation: string
+  filesAnalyzed: false
+  licenseConcluded: string
+  licenseDeclared: string
+  copyrightText: string
+  externalRefs?:
+    | Array<{
+        referenceCategory: 'PACKAGE-MANAGER'
+        referenceType: 'purl'
+        referenceLocator: string
+      }>
+    | undefined
+  comment?: string | undefined
+}
+
+export type SpdxRelationship = {
+  spdxElementId: string
+  relationshipType: 'DESCRIBES' | 'DEPENDS_ON'
+  relatedSpdxElement: string
+}
+
+export type SpdxDocument = {
+  spdxVersion: string
+  dataLicense: 'CC0-1.0'
+  SPDXID: 'SPDXRef-DOCUMENT'
+  name: string
+  documentNamespace: string
+  creationInfo: {
+    created: string
+    creators: string[]
+  }
+  packages: SpdxPackage[]
+  relationships: SpdxRelationship[]
+}
+
+export type GenerateSpdxOptions = {
+  orgSlug: string
+  scanId: string
+  timestamp?: string | undefined
+  uuid?: string | undefined
+}
+
+const ROOT_SPDX_ID = 'SPDXRef-Scan'
+
+/**
+ * Convert an arbitrary string into a valid SPDX identifier suffix. SPDX ids
+ * only allow letters, numbers, `.` and `-`.
+ */
+export function toSpdxId(value: string): string {
+  return `SPDXRef-Package-${value.replace(/[^A-Za-z0-9.-]+/g, '-')}`
+}
+
+export function generateSpdx(
+  scan: SocketArtifact[],
+  { orgSlug, scanId, timestamp, uuid }: GenerateSpdxOptions,
+): SpdxDocument {
+  const refs = getArtifactRefs(scan)
+  const spdxIds = new Map<SocketArtifact, string>()
+  const usedIds = new Set<string>()
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    let id = toSpdxId(refs.get(artifact)!.replace(/^pkg:/, ''))
+    if (usedIds.has(id)) {
+      id = `${id}-${i}`
+    }
+    usedIds.add(id)
+    spdxIds.set(artifact, id)
+  }
+
+  const packages: SpdxPackage[] = [
+    {
+      SPDXID: ROOT_SPDX_ID,
+      name: scanId,
+      downloadLocation: SPDX_NOASSERTION,
+      filesAnalyzed: false,
+      licenseConcluded: SPDX_NOASSERTION,
+      licenseDeclared: SPDX_NOASSERTION,
+      copyrightText: SPDX_NOASSERTION,
+      comment: `Socket full scan ${scanId} of organization ${orgSlug}`,
+    },
+  ]
+  const relationships: SpdxRelationship[] = [
+    {
+      spdxElementId: 'SPDXRef-DOCUMENT',
+      relationshipType: 'DESCRIBES',
+      relatedSpdxElement: ROOT_SPDX_ID,
+    },
+  ]
+
+  const idByArtifactId = new Map<string, string>()
+  for (const [artifact, id] of spdxIds) {
+    if (artifact.id) {
+      idByArtifactId.set(artifact.id, id)
+    }
+  }
+
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    const id = spdxIds.get(artifact)!
+    const name = artifact.namespace
+      ? `${artifact.namespace}/${artifact.name}`
+      : (artifact.name ?? '')
+    const author = Array.isArray(artifact.author)
+      ? artifact.author[0]
+      : (artifact.author as string | undefined)
+    const alertTypes = (artifact.alerts ?? []).map(a => a.type)
+    packages.push({
+      SPDXID: id,
+      name,
+      ...(artifact.version ? { versionInfo: artifact.version } : {}),
+      ...(author ? { supplier: `Person: ${author}` } : {}),
+      downloadLocation: SPDX_NOASSERTION,
+      filesAnalyzed: false,
+      licenseConcluded: SPDX_NOASSERTION,
+      licenseDeclared: artifact.license || SPDX_NOASSERTION,
+      copyrightText: SPDX_NOASSERTION,
+      externalRefs: [
+        {
+          referenceCategory: 'PACKAGE-MANAGER',
+          referenceType: 'purl',
+          referenceLocator: getArtifactPurlString(artifact),
+        },
+      ],
+      ...(alertTypes.length
+        ? { comment: `Socket alerts: ${[...new Set(alertTypes)].join(', ')}` }
+        : {}),
+    })
+
+    if (artifact.direct) {
+      relationships.push({
+        spdxElementId: ROOT_SPDX_ID,
+        relationshipType: 'DEPENDS_ON',
+        relatedSpdxElement: id,
+      })
+    }
+    const deps = (artifact.dependencies ?? []) as string[]
+    const seen = new Set<string>()
+    for (let j = 0, { length: depCount } = deps; j < depCount; j += 1) {
+      const depId = idByArtifactId.get(deps[j]!)
+      if (depId && !seen.has(depId)) {
+        seen.add(depId)
+        relationships.push({
+          spdxElementId: id,
+          relationshipType: 'DEPENDS_ON',
+          relatedSpdxElement: depId,
+        })
+      }
+    }
+  }
+
+  return {
+    spdxVersion: SPDX_VERSION,
+    dataLicense: 'CC0-1.0',
+    SPDXID: 'SPDXRef-DOCUMENT',
+    name: `socket-scan-${scanId}`,
+    documentNamespace: `${SOCKET_WEBSITE_URL}/spdx/${orgSlug}/${scanId}-${uuid ?? crypto.randomUUID()}`,
+    creationInfo: {
+      created: timestamp ?? new Date().toISOString().replace(/\.\d+Z$/, 'Z'),
+      creators: [`Tool: socket-${getCliVersion() || '0.0.0'}`],
+    },
+    packages,
+    relationships,
+  }
+}
+
+// Tag-value text fields that may span lines must be wrapped in <text> tags.
+function tvText(value: string): string {
+  return value.includes('\n') ? `<text>${value}</text>` : value
+}
+
+/**
+ * Serialize an SPDX document using the tag-value format.
+ */
+export function spdxToTagValue(doc: SpdxDocument): string {
+  const lines: string[] = [
+    `SPDXVersion: ${doc.spdxVersion}`,
+    `DataLicense: ${doc.dataLicense}`,
+    `SPDXID: ${doc.SPDXID}`,
+    `DocumentName: ${doc.name}`,
+    `DocumentNamespace: ${doc.documentNamespace}`,
+  ]
+  for (let i = 0, { length } = doc.creationInfo.creators; i < length; i += 1) {
+    lines.push(`Creator: ${doc.creationInfo.creators[i]}`)
+  }
+  lines.push(`Created: ${doc.creationInfo.created}`)
+
+  for (let i = 0, { length } = doc.packages; i < length; i += 1) {
+    const pkg = doc.packages[i]!
+    lines.push('', `PackageName: ${pkg.name}`, `SPDXID: ${pkg.SPDXID}`)
+    if (pkg.versionInfo) {
+      lines.push(`PackageVersion: ${pkg.versionInfo}`)
+    }
+    if (pkg.supplier) {
+      lines.push(`PackageSupplier: ${pkg.supplier}`)
+    }
+    lines.push(
+      `PackageDownloadLocation: ${pkg.downloadLocation}`,
+      `FilesAnalyzed: ${pkg.filesAnalyzed}`,
+      `PackageLicenseConcluded: ${pkg.licenseConcluded}`,
+      `PackageLicenseDeclared: ${pkg.licenseDeclared}`,
+      `PackageCopyrightText: ${tvText(pkg.copyrightText)}`,
+    )
+    const externalRefs = pkg.externalRefs ?? []
+    for (let j = 0, { length: refCount } = externalRefs; j < refCount; j += 1) {
+      const ref = externalRefs[j]!
+      lines.push(
+        `ExternalRef: ${ref.referenceCategory} ${ref.referenceType} ${ref.referenceLocator}`,
+      )
+    }
+    if (pkg.comment) {
+      lines.push(`PackageComment: ${tvText(pkg.comment)}`)
+    }
+  }
+
+  lines.push('')
+  for (let i = 0, { length } = doc.relationships; i < length; i += 1) {
+    const rel = doc.relationships[i]!
+    lines.push(
+      `Relationship: ${rel.spdxElementId} ${rel.relationshipType} ${rel.relatedSpdxElement}`,
+    )
+  }
+
+  return `${lines.join('\n')}\n`
+}
@@ -0,0 +1,322 @@
+/**
+ * VEX statements for the vulnerability alerts of a completed Socket full
+ * scan, rendered as an OpenVEX 0.2.0 or a CSAF 2.0 VEX document.
+ *
+ * Alerts that were triaged are `not_affected`: those waived by an exception
+ * in socket.policy.yml, those ignored by the org triage, and, with import
+ * reachability, those of packages the project sources cannot reach. Every
+ * other vulnerability alert is `under_investigation`.
+ */
+
+import crypto from 'node:crypto'
+
+import { getAlertTranslation } from '../scan/generate-sarif-report.mts'
+import { SOCKET_WEBSITE_URL } from '../../constants/socket.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { findPolicyException } from '../../util/policy/evaluate.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+import { getAlertVulnerabilityIds } from '../../util/vex/vex.mts'
+
+import type { ImportReachability } from '../scan/import-reachability.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { SocketPolicy } from '../../util/policy/socket-policy.mts'
+import type { VexStatus } from '../../util/vex/vex.mts'
+
+export const OPENVEX_CONTEXT = 'https://openvex.dev/ns/v0.2.0'
+
+export const VEX_JUSTIFICATION_NOT_IN_EXECUTE_PATH =
+  'vulnerable_code_not_in_execute_path'
+
+export type VexFinding = {
+  // The first id names the vulnerability, the others are aliases.
+  ids: string[]
+  impactStatement?: string | undefined
+  justification?: string | undefined
+  purl: string
+  status: VexStatus
+  title?: string | undefined
+}
+
+export type GetVexFindingsOptions = {
+  // socket.policy.yml whose exceptions mark alerts as not affected.
+  policy?: SocketPolicy | undefined
+  // Import reachability by artifact id, see `socket scan report`.
+  reachability?: Map<string, ImportReachability> | undefined
+}
+
+export type OpenVexStatement = {
+  vulnerability: { name: string; aliases?: string[] | undefined }
+  products: Array<{ '@id': string }>
+  status: VexStatus
+  justification?: string | undefined
+  impact_statement?: string | undefined
+}
+
+export type OpenVexDocument = {
+  '@context': typeof OPENVEX_CONTEXT
+  '@id': string
+  author: string
+  timestamp: string
+  version: 1
+  tooling: string
+  statements: OpenVexStatement[]
+}
+
+export type CsafProductStatus = {
+  known_not_affected?: string[] | undefined
+  under_investigation?: string[] | undefined
+}
+
+export type CsafVulnerability = {
+  cve?: string | undefined
+  ids?: Array<{ system_name: string; text: string }> | undefined
+  notes?: Array<{ category: 'summary'; text: string }> | undefined
+  product_status: CsafProductStatus
+  flags?: Array<{ label: string; product_ids: string[] }> | undefined
+  threats?:
+    | Array<{ category: 'impact'; details: string; product_ids: string[] }>
+    | undefined
+}
+
+export type CsafVexDocument = {
+  document: {
+    category: 'csaf_vex'
+    csaf_version: '2.0'
+    publisher: { category: 'vendor'; name: string; namespace: string }
+    title: string
+    tracking: {
+      current_release_date: string
+      generator: { engine: { name: string; version: string } }
+      id: string
+      initial_release_date: string
+      revision_history: Array<{
+        date: string
+        number: string
+        summary: string
+      }>
+      status: 'final'
+      version: '1'
+    }
+  }
+  product_tree: {
+    full_product_names: Array<{
+      name: string
+      product_id: string
+      product_identification_helper: { purl: string }
+    }>
+  }
+  vulnerabilities: CsafVulnerability[]
+}
+
+export type GenerateVexOptions = {
+  author: string
+  orgSlug: string
+  scanId: string
+  timestamp?: string | undefined
+  uuid?: string | undefined
+}
+
+function getTriage(
+  artifact: SocketArtifact,
+  alertType: string,
+  action: string | undefined,
+  { policy, reachability }: GetVexFindingsOptions,
+): Pick<VexFinding, 'impactStatement' | 'justification' | 'status'> {
+  const exception = policy
+    ? findPolicyException(policy, artifact, alertType)
+    : undefined
+  if (exception) {
+    return { impactStatement: exception.reason, status: 'not_affected' }
+  }
+  if (action === 'ignore') {
+    return {
+      impactStatement: 'Ignored by the organization triage in Socket',
+      status: 'not_affected',
+    }
+  }
+  if (artifact.id && reachability?.get(artifact.id) === 'unreachable') {
+    return {
+      impactStatement: 'The project sources do not import the package',
+      justification: VEX_JUSTIFICATION_NOT_IN_EXECUTE_PATH,
+      status: 'not_affected',
+    }
+  }
+  return { status: 'under_investigation' }
+}
+
+/**
+ * One finding per vulnerability of each package of the scan. Alerts without
+ * a CVE or GHSA id are left out since VEX cannot name them.
+ */
+export function getVexFindings(
+  scan: SocketArtifact[],
+  options: GetVexFindingsOptions = {},
+): VexFinding[] {
+  const findings = new Map<string, VexFinding>()
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    const purl = getArtifactPurlString(artifact)
+    const alerts = artifact.alerts ?? []
+    for (let j = 0, { length: alertCount } = alerts; j < alertCount; j += 1) {
+      const alert = alerts[j]!
+      const ids = getAlertVulnerabilityIds(alert)
+      // The same alert is listed once per file it was found in.
+      const key = `${purl} ${ids[0]}`
+      if (!ids.length || findings.has(key)) {
+        continue
+      }
+      const title =
+        typeof alert.props?.['title'] === 'string'
+          ? alert.props['title']
+          : getAlertTranslation(alert.type).title
+      findings.set(key, {
+        ids,
+        purl,
+        ...(title ? { title } : {}),
+        ...getTriage(artifact, alert.type, alert.action, options),
+      })
+    }
+  }
+  return [...findings.values()].sort(
+    (a, b) =>
+      a.ids[0]!.localeCompare(b.ids[0]!) || a.purl.localeCompare(b.purl),
+  )
+}
+
+function getTimestamp(timestamp: string | undefined): string {
+  return timestamp ?? new Date().toISOString().replace(/\.\d+Z$/, 'Z')
+}
+
+/**
+ * Render findings as OpenVEX. Findings that share a vulnerability and triage
+ * become one statement listing every package.
+ */
+export function generateOpenVex(
+  findings: VexFinding[],
+  { author, orgSlug, scanId, timestamp, uuid }: GenerateVexOptions,
+): OpenVexDocument {
+  const statements = new Map<string, OpenVexStatement>()
+  for (const finding of findings) {
+    const { ids, impactStatement, justification, purl, status } = finding
+    const key = [ids[0], status, justification, impactStatement].join('\0')
+    const statement = statements.get(key)
+    if (statement) {
+      statement.products.push({ '@id': purl })
+      continue
+    }
+    statements.set(key, {
+      vulnerability: {
+        name: ids[0]!,
+        ...(ids.length > 1 ? { aliases: ids.slice(1) } : {}),
+      },
+      products: [{ '@id': purl }],
+      status,
+      ...(justification ? { justification } : {}),
+      ...(impactStatement ? { impact_statement: impactStatement } : {}),
+    })
+  }
+  return {
+    '@context': OPENVEX_CONTEXT,
+    '@id': `${SOCKET_WEBSITE_URL}/vex/${orgSlug}/${scanId}-${uuid ?? crypto.randomUUID()}`,
+    author,
+    timestamp: getTimestamp(timestamp),
+    version: 1,
+    tooling: `socket-${getCliVersion() || '0.0.0'}`,
+    statements: [...statements.values()],
+  }
+}
+
+/**
+ * Render findings as a CSAF 2.0 document of the VEX profile. Each package is
+ * a product and each vulnerability sorts the products by status.
+ */
+export function generateCsafVex(
+  findings: VexFinding[],
+  { author, scanId, timestamp }: GenerateVexOptions,
+): CsafVexDocument {
+  const productIds = new Map<string, string>()
+  for (const { purl } of findings) {
+    if (!productIds.has(purl)) {
+      productIds.set(
+        purl,
+        `CSAFPID-${String(productIds.size + 1).padStart(4, '0')}`,
+      )
+    }
+  }
+
+  const vulnerabilities = new Map<string, CsafVulnerability>()
+  for (const finding of findings) {
+    const { ids, impactStatement, justification, status, title } = finding
+    const productId = productIds.get(finding.purl)!
+    let vulnerability = vulnerabilities.get(ids[0]!)
+    if (!vulnerability) {
+      const cve = ids.find(id => /^CVE-/i.test(id))
+      const otherIds = ids.filter(id => id !== cve)
+      vulnerability = {
+        ...(cve ? { cve } : {}),
+        ...(otherIds.length
+          ? {
+              ids: otherIds.map(text => ({
+                system_name: 'GitHub Advisory Database',
+                text,
+              })),
+            }
+          : {}),
+        ...(title ? { notes: [{ category: 'summary', text: title }] } : {}),
+        product_status: {},
+      }
+      vulnerabilities.set(ids[0]!, vulnerability)
+    }
+    const listKey =
+      status === 'not_affected' ? 'known_not_affected' : 'under_investigation'
+    ;(vulnerability.product_status[listKey] ??= []).push(productId)
+    if (justification) {
+      ;(vulnerability.flags ??= []).push({
+        label: justification,
+        product_ids: [productId],
+      })
+    }
+    if (impactStatement) {
+      ;(vulnerability.threats ??= []).push({
+        category: 'impact',
+        details: impactStatement,
+        product_ids: [productId],
+      })
+    }
+  }
+
+  const date = getTimestamp(timestamp)
+  return {
+    document: {
+      category: 'csaf_vex',
+      csaf_version: '2.0',
+      publisher: {
+        category: 'vendor',
+        name: author,
+        namespace: SOCKET_WEBSITE_URL,
+      },
+      title: `VEX statements for Socket scan ${scanId}`,
+      tracking: {
+        current_release_date: date,
+        generator: {
+          engine: { name: 'socket', version: getCliVersion() || '0.0.0' },
+        },
+        id: `socket-vex-${scanId}`,
+        initial_release_date: date,
+        revision_history: [
+          { date, number: '1', summary: 'Generated from the Socket scan' },
+        ],
+        status: 'final',
+        version: '1',
+      },
+    },
+    product_tree: {
+      full_product_names: [...productIds].map(({ 0: purl, 1: id }) => ({
+        name: purl,
+        product_id: id,
+        product_identification_helper: { purl },
+      })),
+    },
+    vulnerabilities: [...vulnerabilities.values()],
+  }
+}
@@ -0,0 +1,34 @@
+import { outputSbomExport } from './output-sbom-export.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+
+import type { SBOM_ENCODING, SBOM_FORMAT } from './types.mts'
+import type { OutputKind } from '../../types.mts'
+
+export type HandleSbomExportConfig = {
+  encoding: SBOM_ENCODING
+  filepath: string
+  format: SBOM_FORMAT
+  orgSlug: string
+  outputKind: OutputKind
+  scanId: string
+}
+
+export async function handleSbomExport({
+  encoding,
+  filepath,
+  format,
+  orgSlug,
+  outputKind,
+  scanId,
+}: HandleSbomExportConfig): Promise<void> {
+  const scanCResult = await fetchScan(orgSlug, scanId)
+
+  await outputSbomExport(scanCResult, {
+    encoding,
+    filepath,
+    format,
+    orgSlug,
+    outputKind,
+    scanId,
+  })
+}
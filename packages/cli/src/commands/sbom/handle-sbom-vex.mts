@@ -0,0 +1,81 @@
+import { getVexFindings } from './generate-vex.mts'
+import { outputSbomVex } from './output-sbom-vex.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+import { getImportReachability } from '../scan/import-reachability.mts'
+import { findSocketYmlSync } from '../../util/config.mts'
+import { findSocketPolicySync } from '../../util/policy/socket-policy.mts'
+import { findImportedPackages } from '../../util/reachability/source-imports.mts'
+
+import type { VexFinding } from './generate-vex.mts'
+import type { VEX_FORMAT } from './types.mts'
+import type { ImportReachability } from '../scan/import-reachability.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type HandleSbomVexConfig = {
+  author: string
+  // Directory to search upward from for socket.policy.yml, and the sources
+  // to read for import reachability.
+  cwd: string
+  filepath: string
+  format: VEX_FORMAT
+  // Mark the vulnerabilities of packages the sources cannot reach as not
+  // affected.
+  importReachability: boolean
+  orgSlug: string
+  outputKind: OutputKind
+  scanId: string
+}
+
+export async function handleSbomVex({
+  author,
+  cwd,
+  filepath,
+  format,
+  importReachability,
+  orgSlug,
+  outputKind,
+  scanId,
+}: HandleSbomVexConfig): Promise<void> {
+  const outputConfig = {
+    author,
+    filepath,
+    format,
+    orgSlug,
+    outputKind,
+    scanId,
+  }
+
+  const policyCResult = findSocketPolicySync(cwd)
+  if (!policyCResult.ok) {
+    await outputSbomVex(policyCResult, outputConfig)
+    return
+  }
+
+  const scanCResult = await fetchScan(orgSlug, scanId)
+  if (!scanCResult.ok) {
+    await outputSbomVex(scanCResult, outputConfig)
+    return
+  }
+  const scan = scanCResult.data
+
+  let reachability: Map<string, ImportReachability> | undefined
+  if (importReachability) {
+    const socketYmlResult = findSocketYmlSync(cwd)
+    const importedNames = await findImportedPackages(cwd, {
+      socketConfig: socketYmlResult.ok
+        ? socketYmlResult.data?.parsed
+        : undefined,
+    })
+    reachability = getImportReachability(scan, importedNames)
+  }
+
+  const result: CResult<VexFinding[]> = {
+    ok: true,
+    data: getVexFindings(scan, {
+      policy: policyCResult.data?.policy,
+      reachability,
+    }),
+  }
+
+  await outputSbomVex(result, outputConfig)
+}
@@ -0,0 +1,95 @@
+import fs from 'node:fs/promises'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { cycloneDxToXml, generateCycloneDx } from './generate-cyclonedx.mts'
+import { generateSpdx, spdxToTagValue } from './generate-spdx.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { serializeResultJson } from '../../util/output/result-json.mts'
+import { fileLink } from '../../util/terminal/link.mts'
+
+import type { SBOM_ENCODING, SBOM_FORMAT } from './types.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+const logger = getDefaultLogger()
+
+export type OutputSbomExportConfig = {
+  encoding: SBOM_ENCODING
+  filepath: string
+  format: SBOM_FORMAT
+  orgSlug: string
+  outputKind: OutputKind
+  scanId: string
+}
+
+export function renderSbom(
+  scan: SocketArtifact[],
+  {
+    encoding,
+    format,
+    orgSlug,
+    scanId,
+  }: Pick<
+    OutputSbomExportConfig,
+    'encoding' | 'format' | 'orgSlug' | 'scanId'
+  >,
+): string {
+  if (format === 'spdx') {
+    const doc = generateSpdx(scan, { orgSlug, scanId })
+    return encoding === 'tag-value'
+      ? spdxToTagValue(doc)
+      : `${JSON.stringify(doc, null, 2)}\n`
+  }
+  const bom = generateCycloneDx(scan, { orgSlug, scanId })
+  return encoding === 'xml'
+    ? cycloneDxToXml(bom)
+    : `${JSON.stringify(bom, null, 2)}\n`
+}
+
+export async function outputSbomExport(
+  result: CResult<SocketArtifact[]>,
+  {
+    encoding,
+    filepath,
+    format,
+    orgSlug,
+    outputKind,
+    scanId,
+  }: OutputSbomExportConfig,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+    if (outputKind === 'json') {
+      logger.log(serializeResultJson(result))
+      return
+    }
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const document = renderSbom(result.data, {
+    encoding,
+    format,
+    orgSlug,
+    scanId,
+  })
+
+  if (filepath && filepath !== '-') {
+    try {
+      await fs.writeFile(filepath, document, 'utf8')
+      logger.success(
+        `Exported ${format} SBOM (${result.data.length} components) to ${fileLink(filepath)}`,
+      )
+    } catch (e) {
+      process.exitCode = 1
+      logger.fail(
+        `There was an error trying to write the ${format} SBOM to disk`,
+      )
+      logger.error(e)
+    }
+    return
+  }
+
+  logger.log(document)
+}
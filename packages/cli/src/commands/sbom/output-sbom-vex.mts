@@ -0,0 +1,90 @@
+import fs from 'node:fs/promises'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { generateCsafVex, generateOpenVex } from './generate-vex.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { serializeResultJson } from '../../util/output/result-json.mts'
+import { fileLink } from '../../util/terminal/link.mts'
+
+import type { VexFinding } from './generate-vex.mts'
+import type { VEX_FORMAT } from './types.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export type OutputSbomVexConfig = {
+  author: string
+  filepath: string
+  format: VEX_FORMAT
+  orgSlug: string
+  outputKind: OutputKind
+  scanId: string
+}
+
+export function renderVex(
+  findings: VexFinding[],
+  {
+    author,
+    format,
+    orgSlug,
+    scanId,
+  }: Pick<OutputSbomVexConfig, 'author' | 'format' | 'orgSlug' | 'scanId'>,
+): string {
+  const options = { author, orgSlug, scanId }
+  const doc =
+    format === 'csaf'
+      ? generateCsafVex(findings, options)
+      : generateOpenVex(findings, options)
+  return `${JSON.stringify(doc, null, 2)}\n`
+}
+
+export async function outputSbomVex(
+  result: CResult<VexFinding[]>,
+  {
+    author,
+    filepath,
+    format,
+    orgSlug,
+    outputKind,
+    scanId,
+  }: OutputSbomVexConfig,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+    if (outputKind === 'json') {
+      logger.log(serializeResultJson(result))
+      return
+    }
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const document = renderVex(result.data, {
+    author,
+    format,
+    orgSlug,
+    scanId,
+  })
+
+  if (filepath && filepath !== '-') {
+    const notAffected = result.data.filter(
+      f => f.status === 'not_affected',
+    ).length
+    try {
+      await fs.writeFile(filepath, document, 'utf8')
+      logger.success(
+        `Exported ${format} VEX (${result.data.length} findings, ${notAffected} not affected) to ${fileLink(filepath)}`,
+      )
+    } catch (e) {
+      process.exitCode = 1
+      logger.fail(
+        `There was an error trying to write the ${format} VEX to disk`,
+      )
+      logger.error(e)
+    }
+    return
+  }
+
+  logger.log(document)
+}
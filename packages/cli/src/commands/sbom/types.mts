@@ -0,0 +1,5 @@
+export type SBOM_FORMAT = 'cyclonedx' | 'spdx'
+
+export type SBOM_ENCODING = 'json' | 'tag-value' | 'xml'
+
+export type VEX_FORMAT = 'csaf' | 'openvex'
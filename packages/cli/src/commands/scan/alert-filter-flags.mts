@@ -0,0 +1,34 @@
+import { POLICY_SEVERITIES } from '../../util/policy/socket-policy.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+
+export const alertFilterFlags: MeowFlags = {
+  alertCategory: {
+    type: 'string',
+    isMultiple: true,
+    description:
+      'Only include alerts of these categories, e.g. `secrets` for credentials embedded in packages or `supplyChainRisk`. Accepts a comma-separated value or multiple flags.',
+  },
+  alertType: {
+    type: 'string',
+    isMultiple: true,
+    description:
+      'Only include alerts of these types, e.g. `malware` or `criticalCVE`. Accepts a comma-separated value or multiple flags.',
+  },
+  directOnly: {
+    type: 'boolean',
+    default: false,
+    description: 'Only include direct dependencies',
+  },
+  ecosystem: {
+    type: 'string',
+    isMultiple: true,
+    description:
+      'Only include packages of these ecosystems, e.g. `npm` or `pypi`. Accepts a comma-separated value or multiple flags.',
+  },
+  severity: {
+    type: 'string',
+    default: '',
+    description: `Only include alerts of at least this severity: ${POLICY_SEVERITIES.map(s => `'${s}'`).join(', ')}`,
+  },
+}
@@ -0,0 +1,27 @@
+import type { MeowFlags } from '../../flags.mts'
+
+export const allReposFlags: MeowFlags = {
+  allRepos: {
+    type: 'boolean',
+    default: false,
+    description:
+      'Scan every repository of --github-org or --gitlab-group, or every git clone in the TARGET dir, and report them ranked by new critical alerts',
+  },
+  githubOrg: {
+    type: 'string',
+    default: '',
+    description:
+      'With --all-repos, the GitHub organization whose repositories to scan. Uses SOCKET_CLI_GITHUB_TOKEN',
+  },
+  gitlabGroup: {
+    type: 'string',
+    default: '',
+    description:
+      'With --all-repos, the GitLab group whose repositories, subgroups included, to scan. Uses GITLAB_TOKEN and GITLAB_HOST',
+  },
+  repoConcurrency: {
+    type: 'number',
+    default: 4,
+    description: 'With --all-repos, the number of repositories to scan at once',
+  },
+}
@@ -0,0 +1,122 @@
+/**
+ * Waiting for the analysis of a full scan, for `socket scan create --wait`
+ * and `socket scan await`.
+ *
+ * A scan is created as soon as its manifests are uploaded, and Socket
+ * analyses it in the background. Waiting polls the cached scan results until
+ * they are ready or the timeout passes, so a CI job can gate on the outcome.
+ * Once the analysis is done a summary can be POSTed as JSON to a callback
+ * URL, e.g. to trigger a deploy or a follow-up job.
+ */
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { fetchScan } from './fetch-scan.mts'
+import { postScanNotification } from './scan-notify.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+const logger = getDefaultLogger()
+
+export const DEFAULT_SCAN_WAIT_TIMEOUT = '10m'
+
+export const SCAN_ANALYZED_EVENT = 'scan.analyzed'
+
+const ALERT_SEVERITIES = ['critical', 'high', 'middle', 'low'] as const
+
+export type ScanAlertSeverity = (typeof ALERT_SEVERITIES)[number]
+
+export type ScanWaitOptions = {
+  // POSTed a summary of the scan once its analysis is done.
+  callbackUrl?: string | undefined
+  timeoutMs: number
+}
+
+export type ScanAnalysisSummary = {
+  alerts: Record<ScanAlertSeverity, number>
+  // Set when the summary could not be posted to the callback URL.
+  callbackError?: string | undefined
+  elapsedMs: number
+  orgSlug: string
+  packages: number
+  scanId: string
+}
+
+/**
+ * Count the packages of a scan and their alerts by severity.
+ */
+export function summarizeScanArtifacts(
+  artifacts: SocketArtifact[],
+): Pick<ScanAnalysisSummary, 'alerts' | 'packages'> {
+  const alerts = {
+    __proto__: null,
+    critical: 0,
+    high: 0,
+    low: 0,
+    middle: 0,
+  } as unknown as Record<ScanAlertSeverity, number>
+  for (const artifact of artifacts) {
+    for (const alert of artifact.alerts ?? []) {
+      const severity = alert.severity as ScanAlertSeverity
+      if (ALERT_SEVERITIES.includes(severity)) {
+        alerts[severity] += 1
+      }
+    }
+  }
+  return { alerts, packages: artifacts.length }
+}
+
+export function getScanCallbackPayload(
+  summary: ScanAnalysisSummary,
+): Record<string, unknown> {
+  const { alerts, elapsedMs, orgSlug, packages, scanId } = summary
+  return {
+    event: SCAN_ANALYZED_EVENT,
+    alerts: { ...alerts },
+    elapsedMs,
+    orgSlug,
+    packages,
+    scanId,
+  }
+}
+
+/**
+ * Wait until the analysis of the scan is done and summarize it. A failure to
+ * post to the callback URL is only warned about, the analysis is still done.
+ */
+export async function awaitScanAnalysis(
+  orgSlug: string,
+  scanId: string,
+  options: ScanWaitOptions,
+): Promise<CResult<ScanAnalysisSummary>> {
+  const { callbackUrl, timeoutMs } = {
+    __proto__: null,
+    ...options,
+  } as ScanWaitOptions
+  const startedAt = Date.now()
+  const scanCResult = await fetchScan(orgSlug, scanId, { timeoutMs })
+  if (!scanCResult.ok) {
+    // Keep the scan ID, a later `socket scan await` can resume waiting.
+    return { ...scanCResult, data: { scanId } }
+  }
+  const summary: ScanAnalysisSummary = {
+    ...summarizeScanArtifacts(scanCResult.data),
+    elapsedMs: Date.now() - startedAt,
+    orgSlug,
+    scanId,
+  }
+  if (callbackUrl) {
+    const postCResult = await postScanNotification(
+      callbackUrl,
+      getScanCallbackPayload(summary),
+    )
+    if (!postCResult.ok) {
+      summary.callbackError = postCResult.cause ?? postCResult.message
+      logger.warn(
+        `${postCResult.message}${postCResult.cause ? `: ${postCResult.cause}` : ''}`,
+      )
+    }
+  }
+  return { ok: true, data: summary }
+}
@@ -0,0 +1,241 @@
+import path from 'node:path'
+
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { failOnFlags } from './fail-on-flags.mts'
+import { handleScanAttest } from './handle-scan-attest.mts'
+import { SOCKET_SCAN_PREDICATE_TYPE } from './scan-attestation.mts'
+import { FAIL_ON_LEVELS } from '../../constants/reporting.mts'
+import { SOCKET_BASELINE_JSON } from '../../constants/socket.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunUpload } from '../../util/dry-run/output.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { cmdFlagValueToArray } from '../../util/process/cmd.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { FAIL_ON } from './types.mts'
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'attest'
+
+const DEFAULT_BUNDLE_PATH = 'socket-scan.sigstore.json'
+
+const description = 'Sign an attestation of the policy result of a scan'
+
+const hidden = false
+
+export const cmdScanAttest: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      ...failOnFlags,
+      baseline: {
+        type: 'string',
+        default: '',
+        description: `Baseline file of known alerts to leave out of the result (default: the nearest ${SOCKET_BASELINE_JSON})`,
+      },
+      identityToken: {
+        type: 'string',
+        default: '',
+        description:
+          'OIDC token for the "sigstore" audience to sign with. Defaults to SIGSTORE_ID_TOKEN, or the job identity in GitHub Actions',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      out: {
+        type: 'string',
+        default: DEFAULT_BUNDLE_PATH,
+        description: `Path to write the Sigstore bundle to (default: ${DEFAULT_BUNDLE_PATH})`,
+      },
+      subject: {
+        type: 'string',
+        isMultiple: true,
+        description:
+          'Artifact file to attest, by its sha256 digest, instead of the scanned commit. Accepts multiple flags',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Evaluates the scan against the security policy, as \`socket scan report\`
+    does, and signs an in-toto attestation of the result with Sigstore keyless
+    signing. The statement names the scanned commit, or the --subject files,
+    and its "${SOCKET_SCAN_PREDICATE_TYPE}" predicate records the scan,
+    the digests of the policies it was evaluated against and whether it
+    passed. The signature is recorded in the public Rekor transparency log.
+
+    A deployment gate verifies the bundle of a --subject artifact with the
+    signer identity it trusts, e.g.:
+      \`cosign verify-blob-attestation --bundle ${DEFAULT_BUNDLE_PATH} \\
+        --type ${SOCKET_SCAN_PREDICATE_TYPE} \\
+        --certificate-identity-regexp '^https://github.com/acme/' \\
+        --certificate-oidc-issuer https://token.actions.githubusercontent.com \\
+        --new-bundle-format app.tar.gz\`
+
+    In GitHub Actions the job needs the \`id-token: write\` permission.
+    Elsewhere, pass an OIDC token issued for the "sigstore" audience with
+    --identity-token or SIGSTORE_ID_TOKEN. SOCKET_CLI_FULCIO_URL and
+    SOCKET_CLI_REKOR_URL point at a private Sigstore instance.
+
+    The attestation is written whether the scan passed or not; a failed
+    result exits with code 3.
+
+    Examples
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --subject dist/app.tar.gz --fail-on=high
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    baseline: baselineFlag,
+    failOn,
+    identityToken,
+    json,
+    markdown,
+    org: orgFlag,
+    out,
+  } = cli.flags as {
+    baseline: string
+    failOn: string
+    identityToken: string
+    json: boolean
+    markdown: boolean
+    org: string
+    out: string
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const interactive = !!cli.flags['interactive']
+
+  const subjectPaths = cmdFlagValueToArray(cli.flags['subject'])
+
+  const [scanId = ''] = cli.input
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'dot is an invalid org, most likely you forgot the org name here?',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID to attest',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !failOn || (FAIL_ON_LEVELS as readonly string[]).includes(failOn),
+      message: `The --fail-on flag must be ${joinOr(FAIL_ON_LEVELS.map(f => `'${f}'`))}`,
+      fail: `got ${failOn}`,
+    },
+    {
+      nook: true,
+      test: !!out,
+      message: 'The --out flag must be a file path',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  const cwd = process.cwd()
+  const bundlePath = path.resolve(cwd, out)
+
+  if (dryRun) {
+    outputDryRunUpload('a signed scan attestation to the Rekor log', {
+      organization: orgSlug,
+      scanId,
+      subjects: subjectPaths.length ? subjectPaths.join(', ') : 'the commit',
+      ...(failOn ? { failOn } : {}),
+      bundle: bundlePath,
+    })
+    return
+  }
+
+  await handleScanAttest({
+    baselinePath: baselineFlag ? path.resolve(cwd, baselineFlag) : undefined,
+    bundlePath,
+    cwd,
+    failOn: (failOn || undefined) as FAIL_ON | undefined,
+    identityToken: identityToken || undefined,
+    orgSlug,
+    outputKind,
+    scanId,
+    subjectPaths: subjectPaths.map(p => path.resolve(cwd, p)),
+  })
+}
@@ -0,0 +1,186 @@
+import { DEFAULT_SCAN_WAIT_TIMEOUT } from './await-scan.mts'
+import { handleScanAwait } from './handle-scan-await.mts'
+import { parseCacheDuration } from '../../util/cache/overrides.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'await'
+
+const description = 'Wait for the analysis of a scan to finish'
+
+const hidden = false
+
+export const cmdScanAwait: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      callbackUrl: {
+        type: 'string',
+        default: '',
+        description:
+          'URL to POST a JSON summary of the scan to once its analysis is done',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      waitTimeout: {
+        type: 'string',
+        default: DEFAULT_SCAN_WAIT_TIMEOUT,
+        description: `How long to wait for the analysis, e.g. "90s", "30m" or "1h" (default ${DEFAULT_SCAN_WAIT_TIMEOUT})`,
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Resumes waiting for a scan created without --wait, e.g. in a later CI
+    step. Prints the package count and the alert count of each severity once
+    the analysis is done, and exits with code 1 when it is not done within
+    --wait-timeout. With --callback-url that summary is also POSTed as JSON
+    with the event "scan.analyzed"; a failed callback only warns.
+
+    Examples
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --wait-timeout=30m --json
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --callback-url=https://ci.example.com/hook
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    callbackUrl,
+    json,
+    markdown,
+    org: orgFlag,
+    waitTimeout,
+  } = cli.flags as {
+    callbackUrl: string
+    json: boolean
+    markdown: boolean
+    org: string
+    waitTimeout: string
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const interactive = !!cli.flags['interactive']
+
+  const [scanId = ''] = cli.input
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const timeoutMs = parseCacheDuration(waitTimeout)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail:
+        orgSlug === '.'
+          ? 'dot is an invalid org, most likely you forgot the org name here?'
+          : 'missing',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID to wait for as argument',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: timeoutMs !== undefined,
+      message:
+        'The --wait-timeout flag must be a duration like "90s", "30m" or "1h"',
+      fail: 'invalid duration',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput || timeoutMs === undefined) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scan analysis', {
+      callbackUrl,
+      organization: orgSlug,
+      scanId,
+      timeout: waitTimeout,
+    })
+    return
+  }
+
+  await handleScanAwait(orgSlug, scanId, outputKind, {
+    callbackUrl: callbackUrl || undefined,
+    timeoutMs,
+  })
+}
@@ -0,0 +1,166 @@
+import path from 'node:path'
+
+import { handleScanBaselineWrite } from './handle-scan-baseline-write.mts'
+import { SOCKET_BASELINE_JSON } from '../../constants/socket.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'write'
+
+const description = `Snapshot the alerts of a scan into a ${SOCKET_BASELINE_JSON}`
+
+const hidden = false
+
+export const cmdScanBaselineWrite: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      file: {
+        type: 'string',
+        default: SOCKET_BASELINE_JSON,
+        description: `Where to write the baseline (default '${SOCKET_BASELINE_JSON}')`,
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Records every alert in the scan, by package and alert type, whatever its
+    policy action. Commit the file: \`socket scan report\` and
+    \`socket scan create --report\` leave the recorded alerts out and only
+    fail on new ones. The same alert in an upgraded version of a package
+    counts as new.
+
+    Run it again after fixing alerts to shrink the baseline.
+
+    Examples
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --file ./config/${SOCKET_BASELINE_JSON}
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    file,
+    json,
+    markdown,
+    org: orgFlag,
+  } = cli.flags as {
+    file: string
+    json: boolean
+    markdown: boolean
+    org: string
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const interactive = !!cli.flags['interactive']
+
+  const [scanId = ''] = cli.input
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'dot is an invalid org, most likely you forgot the org name here?',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID to take the baseline from',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  const filepath = path.resolve(process.cwd(), file || SOCKET_BASELINE_JSON)
+
+  if (dryRun) {
+    outputDryRunFetch('scan alerts for a baseline', {
+      organization: orgSlug,
+      scanId,
+      file: filepath,
+    })
+    return
+  }
+
+  await handleScanBaselineWrite({ filepath, orgSlug, outputKind, scanId })
+}
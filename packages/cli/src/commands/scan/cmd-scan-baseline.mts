@@ -0,0 +1,10 @@
+import { cmdScanBaselineWrite } from './cmd-scan-baseline-write.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdScanBaseline = defineSubcommandGroup({
+  name: 'baseline',
+  description: 'Manage the baseline of known alerts that reports leave out',
+  subcommands: {
+    write: cmdScanBaselineWrite,
+  },
+})
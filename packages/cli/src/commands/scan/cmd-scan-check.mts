@@ -0,0 +1,260 @@
+import path from 'node:path'
+
+import { handleScanCheck } from './handle-scan-check.mts'
+import { REPORT_FORMAT_JUNIT } from '../../constants/reporting.mts'
+import { SOCKET_CLI_OFFLINE } from '../../env/socket-cli-offline.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { REGO_DEFAULT_PACKAGE } from '../../util/policy/rego.mts'
+import { cmdFlagValueToArray } from '../../util/process/cmd.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'check'
+
+const description = 'Check a scan result against a Rego policy'
+
+const hidden = false
+
+export const cmdScanCheck: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      format: {
+        type: 'string',
+        default: '',
+        description: `Render the result in an alternative format instead of the default json/markdown/text. Supported: '${REPORT_FORMAT_JUNIT}'`,
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      offline: {
+        type: 'boolean',
+        default: SOCKET_CLI_OFFLINE,
+        description:
+          'Read the scan and security policy from an imported offline bundle without calling the Socket API',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      package: {
+        type: 'string',
+        default: REGO_DEFAULT_PACKAGE,
+        description: `Rego package that holds the deny and warn rules (default '${REGO_DEFAULT_PACKAGE}')`,
+      },
+      policy: {
+        type: 'string',
+        default: '',
+        description:
+          'Path to a Rego policy file, or a directory of them, to evaluate',
+      },
+      vex: {
+        type: 'string',
+        isMultiple: true,
+        description:
+          'OpenVEX, CSAF or CycloneDX VEX document whose not_affected and fixed statements leave out matching vulnerability alerts. Accepts multiple flags',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <SCAN_ID> --policy <FILE>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Evaluates the policy with the Open Policy Agent CLI, which must be on
+    PATH as \`opa\`. The policy is queried at \`data.<package>\`: every entry
+    of its \`deny\` rule fails the check, entries of \`warn\` are reported.
+    Entries are message strings or objects with a \`msg\` field.
+
+    The input document holds the scan and every package in it, as returned
+    by the API, with alerts, scores, license, dev/direct flags, manifest
+    files and reachability data:
+      \`{
+        scan: { id, org },
+        securityPolicy: { rules },
+        packages: [{ purl, name, version, license, dev, direct, score,
+                     alerts: [{ type, severity, action, … }], … }]
+      }\`
+
+    For example, to block GPL licensed packages outside dev dependencies:
+      \`package socket
+
+      deny contains msg if {
+        some pkg in input.packages
+        not pkg.dev
+        startswith(pkg.license, "GPL")
+        msg := sprintf("%s is GPL licensed", [pkg.purl])
+      }\`
+
+    Use --format=${REPORT_FORMAT_JUNIT} to emit JUnit XML for CI test reporters: one failing
+    test case per \`deny\` entry and one passing case per \`warn\` entry.
+
+    With --vex, vulnerability alerts that a VEX document marks as
+    not_affected or fixed are removed from the input, matched by CVE or GHSA
+    id and package purl.
+
+    On an air-gapped machine, import a bundle made with \`socket bundle export\`
+    and check with --offline (or SOCKET_CLI_OFFLINE=1). The scan and the org
+    security policy are then read from the bundle and no API token is needed.
+
+    Examples
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --policy policy.rego
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --policy ./policies --package acme.supply_chain --json
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --policy policy.rego --format=junit > policy-junit.xml
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --policy policy.rego --vex triage.openvex.json
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --policy policy.rego --offline --org acme
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    format,
+    json,
+    markdown,
+    offline,
+    org: orgFlag,
+    package: regoPackage,
+    policy,
+  } = cli.flags as {
+    format: string
+    json: boolean
+    markdown: boolean
+    offline: boolean
+    org: string
+    package: string
+    policy: string
+  }
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const interactive = !!cli.flags['interactive']
+
+  const vexPaths = cmdFlagValueToArray(cli.flags['vex'])
+
+  const [scanId = ''] = cli.input
+
+  const hasApiToken = hasDefaultApiToken()
+
+  // Offline runs cannot auto-discover the org through the API.
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive && !offline,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'dot is an invalid org, most likely you forgot the org name here?',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID to check',
+      fail: 'missing',
+    },
+    {
+      test: !!policy,
+      message: 'Rego policy to evaluate with --policy',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: /^[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*$/.test(regoPackage),
+      message: 'The --package flag must be a dotted Rego package name',
+      fail: 'invalid package',
+    },
+    {
+      nook: true,
+      test: !format || format === REPORT_FORMAT_JUNIT,
+      message: `The --format flag must be '${REPORT_FORMAT_JUNIT}'`,
+      fail: 'unsupported format',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: hasApiToken || offline,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scan policy check', {
+      organization: orgSlug,
+      scanId,
+      policy,
+      package: regoPackage,
+      format: format || undefined,
+      ...(vexPaths.length ? { vex: vexPaths.join(', ') } : {}),
+      ...(offline ? { offline } : {}),
+    })
+    return
+  }
+
+  await handleScanCheck({
+    format: format === REPORT_FORMAT_JUNIT ? format : undefined,
+    offline,
+    orgSlug,
+    outputKind,
+    policyPath: path.resolve(process.cwd(), policy),
+    regoPackage,
+    scanId,
+    vexPaths: vexPaths.map(p => path.resolve(process.cwd(), p)),
+  })
+}
@@ -0,0 +1,132 @@
+import path from 'node:path'
+
+import { handleScanAllRepos } from './handle-scan-all-repos.mts'
+import { outputDryRunUpload } from '../../util/dry-run/output.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { RepoSource } from './discover-repos.mts'
+import type { OutputKind } from '../../types.mts'
+
+export interface ScanCreateAllReposOptions {
+  allRepos: boolean
+  cwd: string
+  dryRun: boolean
+  flags: Record<string, unknown>
+  githubOrg: string
+  gitlabGroup: string
+  hasApiToken: boolean
+  inputTargets: readonly string[]
+  orgSlug: string
+  outputKind: OutputKind
+  repoConcurrency: number | string
+}
+
+// Flags of a single scan that make no sense across many repositories.
+const SINGLE_REPO_FLAGS = [
+  ['fromSbom', '--from-sbom'],
+  ['reach', '--reach'],
+  ['report', '--report'],
+  ['repo', '--repo'],
+  ['changedSince', '--changed-since'],
+  ['perWorkspace', '--per-workspace'],
+  ['workspaceFilter', '--workspace-filter'],
+] as const
+
+/**
+ * `socket scan create --all-repos`: validate the flags and scan every
+ * repository of a GitHub organization, a GitLab group or a dir of clones.
+ */
+export async function runScanCreateAllRepos({
+  allRepos,
+  cwd,
+  dryRun,
+  flags,
+  githubOrg,
+  gitlabGroup,
+  hasApiToken,
+  inputTargets,
+  orgSlug,
+  outputKind,
+  repoConcurrency,
+}: ScanCreateAllReposOptions): Promise<void> {
+  const concurrency = Number(repoConcurrency)
+  const singleRepoFlags = SINGLE_REPO_FLAGS.filter(([key]) => {
+    const value = flags[key]
+    return Array.isArray(value) ? value.length > 0 : !!value
+  }).map(([, name]) => name)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: allRepos,
+      message: 'The --github-org and --gitlab-group flags require --all-repos',
+      fail: 'add --all-repos',
+    },
+    {
+      nook: true,
+      test: !flags['json'] || !flags['markdown'],
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !githubOrg || !gitlabGroup,
+      message: 'The --github-org and --gitlab-group flags cannot be both set',
+      fail: 'pick one',
+    },
+    {
+      nook: true,
+      test: inputTargets.length <= (githubOrg || gitlabGroup ? 0 : 1),
+      message:
+        'With --all-repos, TARGET is the one dir of clones to scan, and is not used with --github-org or --gitlab-group',
+      fail: 'too many targets',
+    },
+    {
+      nook: true,
+      test: !singleRepoFlags.length,
+      message: `The --all-repos flag cannot be used with ${singleRepoFlags.join(', ')}`,
+      fail: 'omit them',
+    },
+    {
+      nook: true,
+      test: Number.isInteger(concurrency) && concurrency >= 1,
+      message: `The --repo-concurrency flag must be a positive integer (saw: "${repoConcurrency}")`,
+      fail: 'invalid number',
+    },
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  const source: RepoSource = githubOrg
+    ? { kind: 'github', org: githubOrg }
+    : gitlabGroup
+      ? { kind: 'gitlab', group: gitlabGroup }
+      : { kind: 'local', dir: path.resolve(cwd, inputTargets[0] || '.') }
+
+  if (dryRun) {
+    outputDryRunUpload('scans of all repositories', {
+      organization: orgSlug,
+      ...(source.kind === 'github' ? { githubOrg: source.org } : {}),
+      ...(source.kind === 'gitlab' ? { gitlabGroup: source.group } : {}),
+      ...(source.kind === 'local' ? { clones: source.dir } : {}),
+      repoConcurrency: concurrency,
+    })
+    return
+  }
+
+  await handleScanAllRepos({ concurrency, orgSlug, outputKind, source })
+}
@@ -1,6 +1,8 @@
-import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
+import { joinAnd, joinOr } from '@socketsecurity/lib-stable/arrays/join'
 
 import { reachabilityFlags } from './reachability-flags.mts'
+import { SCAN_NOTIFY_CHANNELS } from './scan-notify.mts'
+import { FAIL_ON_LEVELS, REPORT_FORMATS } from '../../constants/reporting.mts'
 import { InputError } from '../../util/error/errors.mts'
 import { getEcosystemChoicesForMeow } from '../../util/ecosystem/types.mts'
 import { checkCommandInput } from '../../util/validation/check-input.mts'
@@ -22,18 +24,33 @@ export interface ReachabilityFlagUsageInput {
 }
 
 export interface ScanCreateInputCheckOptions {
+  basics?: boolean | undefined
   branchName: string
+  callbackUrl?: string | undefined
+  failOn?: string | undefined
+  fromSbom?: string | undefined
   hasApiToken: boolean
+  hasTargetInput?: boolean | undefined
   isUsingAnyReachabilityFlags: boolean
   json: boolean
   makeDefaultBranch: boolean
   markdown: boolean
+  notifyChannel?: string | undefined
+  onlyReachable?: boolean | undefined
   orgSlug: string
   outputKind: OutputKind
   pendingHead: boolean
+  perWorkspace?: boolean | undefined
   reach: boolean
   reachTargetValidation: ReachabilityTargetValidation
+  report?: boolean | undefined
+  reportFormat?: string | undefined
+  selectsWorkspaces?: boolean | undefined
   targets: string[]
+  wait?: boolean | undefined
+  // Undefined when --wait-timeout is not a valid duration.
+  waitTimeoutMs?: number | undefined
+  webhookUrl?: string | undefined
 }
 
 /**
@@ -112,18 +129,32 @@ export function validateScanCreateInput(
   config: ScanCreateInputCheckOptions,
 ): boolean {
   const {
+    basics,
     branchName,
+    callbackUrl,
+    failOn,
+    fromSbom,
     hasApiToken,
+    hasTargetInput,
     isUsingAnyReachabilityFlags,
     json,
     makeDefaultBranch,
     markdown,
+    notifyChannel,
+    onlyReachable,
     orgSlug,
     outputKind,
     pendingHead,
+    perWorkspace,
     reach,
     reachTargetValidation,
+    report,
+    reportFormat,
+    selectsWorkspaces,
     targets,
+    wait,
+    waitTimeoutMs,
+    webhookUrl,
   } = { __proto__: null, ...config } as typeof config
 
   return checkCommandInput(
@@ -157,6 +188,113 @@ export function validateScanCreateInput(
       message: 'When --make-default-branch is set, --branch is mandatory',
       fail: 'missing branch name',
     },
+    {
+      nook: true,
+      test:
+        !reportFormat ||
+        (REPORT_FORMATS as readonly string[]).includes(reportFormat),
+      message: `The --format flag must be ${joinOr(REPORT_FORMATS.map(f => `'${f}'`))}`,
+      fail: 'unsupported format',
+    },
+    {
+      nook: true,
+      test: !reportFormat || !!report,
+      message: 'The --format flag requires --report',
+      fail: 'add --report',
+    },
+    {
+      nook: true,
+      test: !failOn || (FAIL_ON_LEVELS as readonly string[]).includes(failOn),
+      message: `The --fail-on flag must be ${joinOr(FAIL_ON_LEVELS.map(f => `'${f}'`))}`,
+      fail: `got ${failOn}`,
+    },
+    {
+      nook: true,
+      test: !failOn || !!report,
+      message: 'The --fail-on flag requires --report',
+      fail: 'add --report',
+    },
+    {
+      nook: true,
+      test: !onlyReachable || !!report,
+      message: 'The --only-reachable flag requires --report',
+      fail: 'add --report',
+    },
+    {
+      nook: true,
+      test: !perWorkspace || !!report,
+      message: 'The --per-workspace flag requires --report',
+      fail: 'add --report',
+    },
+    {
+      nook: true,
+      test: !(perWorkspace || (selectsWorkspaces && report)) || !reportFormat,
+      message: 'Per-workspace reports cannot be combined with --format',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !selectsWorkspaces || !hasTargetInput,
+      message:
+        'The --workspace-filter and --changed-since flags pick the targets, do not pass TARGET arguments too',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !selectsWorkspaces || !reach,
+      message:
+        'The --workspace-filter and --changed-since flags cannot be combined with --reach',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !fromSbom || !hasTargetInput,
+      message:
+        'The --from-sbom flag replaces the TARGET arguments, do not pass both',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test:
+        !fromSbom || !(reach || basics || selectsWorkspaces || perWorkspace),
+      message:
+        'The --from-sbom flag cannot be combined with --reach, --basics, --workspace-filter, --changed-since or --per-workspace',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test:
+        !notifyChannel ||
+        (SCAN_NOTIFY_CHANNELS as readonly string[]).includes(notifyChannel),
+      message: `The --notify flag must be ${joinOr(SCAN_NOTIFY_CHANNELS.map(c => `'${c}'`))}`,
+      fail: 'unsupported channel',
+    },
+    {
+      nook: true,
+      test: !notifyChannel || !!webhookUrl,
+      message:
+        'The --notify flag requires --webhook-url or SOCKET_CLI_NOTIFY_WEBHOOK_URL',
+      fail: 'missing webhook URL',
+    },
+    {
+      nook: true,
+      test: !wait || !report,
+      message: 'The --report flag already waits for the analysis, omit --wait',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !wait || waitTimeoutMs !== undefined,
+      message:
+        'The --wait-timeout flag must be a duration like "90s", "30m" or "1h"',
+      fail: 'invalid duration',
+    },
+    {
+      nook: true,
+      test: !callbackUrl || !!wait,
+      message: 'The --callback-url flag requires --wait',
+      fail: 'add --wait',
+    },
     {
       nook: true,
       test: !pendingHead || !!branchName,
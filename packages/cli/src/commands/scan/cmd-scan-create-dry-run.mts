@@ -0,0 +1,128 @@
+/**
+ * The --dry-run output of `socket scan create`: the scan settings and the
+ * full-scans request the command would make, without uploading anything.
+ */
+
+import { getCreateOrgFullScanQuery } from './fetch-create-org-full-scan.mts'
+import { SCAN_TYPE_SOCKET, SCAN_TYPE_SOCKET_TIER1 } from '../../constants.mts'
+import { outputDryRunUpload } from '../../util/dry-run/output.mts'
+
+export type ScanCreateDryRunConfig = {
+  branchName: string
+  callbackUrl: string
+  changedSince: string
+  commitHash: string
+  commitMessage: string
+  committers: string
+  failOn: string
+  fromSbom: string
+  makeDefaultBranch: boolean
+  notifyChannel: string
+  onlyReachable: boolean
+  orgSlug: string
+  pendingHead: boolean
+  perWorkspace: boolean
+  pullRequest: number
+  reach: boolean
+  reachEcosystems: string[]
+  repoName: string
+  report: boolean
+  selectsWorkspaces: boolean
+  targets: string[]
+  tmp: boolean
+  wait: boolean
+  waitTimeout: string
+  workspace: string
+  workspaceFilter: string[]
+}
+
+export function outputScanCreateDryRun({
+  branchName,
+  callbackUrl,
+  changedSince,
+  commitHash,
+  commitMessage,
+  committers,
+  failOn,
+  fromSbom,
+  makeDefaultBranch,
+  notifyChannel,
+  onlyReachable,
+  orgSlug,
+  pendingHead,
+  perWorkspace,
+  pullRequest,
+  reach,
+  reachEcosystems,
+  repoName,
+  report,
+  selectsWorkspaces,
+  targets,
+  tmp,
+  wait,
+  waitTimeout,
+  workspace,
+  workspaceFilter,
+}: ScanCreateDryRunConfig): void {
+  const details: Record<string, unknown> = {
+    organization: orgSlug,
+    ...(fromSbom ? { sbom: fromSbom } : { targets: targets.join(', ') }),
+  }
+  if (repoName) {
+    details['repository'] = repoName
+  }
+  if (branchName) {
+    details['branch'] = branchName
+  }
+  if (reach) {
+    details['reachabilityAnalysis'] = 'enabled'
+    if (reachEcosystems.length > 0) {
+      details['ecosystems'] = reachEcosystems.join(', ')
+    }
+  }
+  if (onlyReachable) {
+    details['reportAlerts'] = 'only reachable'
+  }
+  if (changedSince) {
+    details['changedSince'] = changedSince
+  }
+  if (workspaceFilter.length) {
+    details['workspaceFilter'] = workspaceFilter.join(', ')
+  }
+  if (report && (perWorkspace || selectsWorkspaces)) {
+    details['report'] = 'per workspace'
+  }
+  if (failOn) {
+    details['failOn'] = failOn
+  }
+  if (notifyChannel) {
+    details['notify'] = notifyChannel
+  }
+  if (wait) {
+    details['wait'] = waitTimeout
+    if (callbackUrl) {
+      details['callbackUrl'] = callbackUrl
+    }
+  }
+  outputDryRunUpload('scan', details, {
+    method: 'POST',
+    path: `orgs/${orgSlug}/full-scans`,
+    query: getCreateOrgFullScanQuery(
+      {
+        branchName,
+        commitHash,
+        commitMessage,
+        committers,
+        pullRequest,
+        repoName,
+        scanType:
+          reach && !fromSbom ? SCAN_TYPE_SOCKET_TIER1 : SCAN_TYPE_SOCKET,
+        workspace,
+      },
+      { defaultBranch: makeDefaultBranch, pendingHead, tmp },
+    ),
+    body: fromSbom
+      ? `the packages of ${fromSbom}`
+      : 'the supported manifest files of the targets, listed by --read-only',
+  })
+}
@@ -1,16 +1,74 @@
 /**
- * Flag schema for `socket scan create`.
+ * Flag schema and flag types for `socket scan create`.
  *
  * Extracted from cmd-scan-create.mts to keep that file under the 1000-line
  * File-size hard cap. Defining the (large) flag set here lets the main command
  * file focus on the run() orchestration logic.
  */
 
+import { DEFAULT_SCAN_WAIT_TIMEOUT } from './await-scan.mts'
 import { constants } from '../../constants.mts'
+import { REPORT_FORMATS } from '../../constants/reporting.mts'
 import { commonFlags, outputFlags } from '../../flags.mts'
 
+import type { REPORT_LEVEL } from './types.mts'
 import type { MeowFlags } from '../../flags.mts'
 
+// Flags interface for type safety.
+export interface ScanCreateFlags {
+  allRepos: boolean
+  autoManifest?: boolean | undefined
+  basics?: boolean | undefined
+  branch: string
+  callbackUrl: string
+  changedSince: string
+  commitHash: string
+  commitMessage: string
+  committers: string
+  cwd: string
+  defaultBranch: boolean
+  failOn: string
+  format: string
+  fromSbom: string
+  githubOrg: string
+  gitlabGroup: string
+  makeDefaultBranch: boolean
+  interactive: boolean
+  json: boolean
+  markdown: boolean
+  notify: string
+  onlyReachable: boolean
+  org: string
+  perWorkspace: boolean
+  pullRequest: number
+  reach: boolean
+  reachAnalysisMemoryLimit: number
+  reachAnalysisTimeout: number
+  reachConcurrency: number
+  reachDebug: boolean
+  reachDetailedAnalysisLogFile: boolean
+  reachDisableAnalytics: boolean
+  reachDisableExternalToolChecks: boolean
+  reachEnableAnalysisSplitting: boolean
+  reachLazyMode: boolean
+  reachMinSeverity: string
+  reachSkipCache: boolean
+  reachUseOnlyPregeneratedSboms: boolean
+  reachUseUnreachableFromPrecomputation: boolean
+  reachVersion: string
+  readOnly: boolean
+  repo: string
+  repoConcurrency: number
+  report?: boolean | undefined
+  reportLevel: REPORT_LEVEL
+  setAsAlertsPage: boolean
+  tmp: boolean
+  wait: boolean
+  waitTimeout: string
+  webhookUrl: string
+  workspace: string
+}
+
 export const generalFlags: MeowFlags = {
   ...commonFlags,
   ...outputFlags,
@@ -31,6 +89,12 @@ export const generalFlags: MeowFlags = {
     description: 'Branch name',
     shortFlag: 'b',
   },
+  callbackUrl: {
+    type: 'string',
+    default: '',
+    description:
+      'URL to POST a JSON summary of the scan to once its analysis is done, requires --wait',
+  },
   commitHash: {
     type: 'string',
     default: '',
@@ -71,18 +135,41 @@ export const generalFlags: MeowFlags = {
       'Deprecated alias for --make-default-branch. Kept working for back-compat; emits a deprecation warning on use.',
     hidden: true,
   },
+  format: {
+    type: 'string',
+    default: '',
+    description: `Render the --report output in an alternative format. Supported: ${REPORT_FORMATS.map(f => `'${f}'`).join(', ')}`,
+  },
+  fromSbom: {
+    type: 'string',
+    default: '',
+    description:
+      'Scan the components of this CycloneDX or SPDX JSON document instead of the manifest files of the TARGETs',
+  },
   interactive: {
     type: 'boolean',
     default: true,
     description:
       'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
   },
+  notify: {
+    type: 'string',
+    default: '',
+    description:
+      'Post a summary of the scan to a chat webhook when it is created, "slack" or "teams"',
+  },
   pullRequest: {
     type: 'number',
     default: 0,
     description: 'Pull request number',
     shortFlag: 'pr',
   },
+  onlyReachable: {
+    type: 'boolean',
+    default: false,
+    description:
+      'Leave out alerts of packages the project sources do not import, directly or through other packages, from the --report output',
+  },
   org: {
     type: 'string',
     default: '',
@@ -98,7 +185,7 @@ export const generalFlags: MeowFlags = {
     type: 'boolean',
     default: false,
     description:
-      'Similar to --dry-run except it can read from remote, stops before it would create an actual report',
+      'Similar to --dry-run except it can read from remote, lists the files it would upload and stops before it would create an actual report',
   },
   repo: {
     type: 'string',
@@ -129,6 +216,23 @@ export const generalFlags: MeowFlags = {
       'Set the visibility (true/false) of the scan in your dashboard.',
     shortFlag: 't',
   },
+  wait: {
+    type: 'boolean',
+    default: false,
+    description:
+      'Wait for the analysis of the scan to finish and fail when it does not within --wait-timeout. Defaults to --no-wait, which returns once the scan is created',
+  },
+  waitTimeout: {
+    type: 'string',
+    default: DEFAULT_SCAN_WAIT_TIMEOUT,
+    description: `How long --wait waits for the analysis, e.g. "90s", "30m" or "1h" (default ${DEFAULT_SCAN_WAIT_TIMEOUT})`,
+  },
+  webhookUrl: {
+    type: 'string',
+    default: '',
+    description:
+      'Incoming webhook URL for --notify, defaults to the SOCKET_CLI_NOTIFY_WEBHOOK_URL environment variable',
+  },
   workspace: {
     type: 'string',
     default: '',
@@ -0,0 +1,130 @@
+/**
+ * Help text of `socket scan create`, kept apart from the run() orchestration
+ * in cmd-scan-create.mts.
+ */
+
+import { allReposFlags } from './all-repos-flags.mts'
+import { generalFlags } from './cmd-scan-create-flags.mts'
+import { failOnFlags } from './fail-on-flags.mts'
+import { excludePathsFlag, reachabilityFlags } from './reachability-flags.mts'
+import { workspaceFlags } from './workspace-flags.mts'
+import { REQUIREMENTS_TXT } from '../../constants.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { socketDashboardLink } from '../../util/terminal/link.mts'
+
+export function getScanCreateHelp(
+  command: string,
+  commandPath: string,
+): string {
+  return `
+    Usage
+      $ ${command} [options] [TARGET...]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(commandPath)}
+
+    Options
+      ${getFlagListOutput({ ...generalFlags, ...excludePathsFlag, ...workspaceFlags, ...failOnFlags, ...allReposFlags })}
+
+    Reachability Options (when --reach is used)
+      ${getFlagListOutput(reachabilityFlags)}
+
+    Uploads the specified dependency manifest files for Go, Gradle, JavaScript,
+    Kotlin, Python, and Scala. Files like "package.json" and "${REQUIREMENTS_TXT}".
+    If any folder is specified, the ones found in there recursively are uploaded.
+
+    Details on TARGET:
+
+    - Defaults to the current dir (cwd) if none given
+    - Multiple targets can be specified
+    - If a target is a file, only that file is checked
+    - If it is a dir, the dir is scanned for any supported manifest files
+    - Dirs MUST be within the current dir (cwd), you can use --cwd to change it
+    - Supports globbing such as "**/package.json", "**/${REQUIREMENTS_TXT}", etc.
+    - Ignores files specified in your project's ".gitignore"
+    - Ignores files specified in your "socket.yml" file's "projectIgnorePaths"
+    - Also a sensible set of default ignores from the "ignore-by-default" module
+
+    The --repo and --branch flags tell Socket to associate this Scan with that
+    repo/branch. The names will show up on your dashboard on the Socket website.
+
+    Note: on a first scan you probably want to pass --make-default-branch so
+          Socket records this branch ("main", "master", etc.) as your repo's
+          default branch. Subsequent scans don't need the flag unless you're
+          reassigning the default-branch pointer to a different branch.
+
+    The ${socketDashboardLink('/org/YOURORG/alerts', '"alerts page"')} will show
+    the results from the last scan designated as the "pending head" on the branch
+    configured on Socket to be the "default branch". When creating a scan the
+    --set-as-alerts-page flag will default to true to update this. You can prevent
+    this by using --no-set-as-alerts-page. This flag is ignored for any branch that
+    is not designated as the "default branch". It is disabled when using --tmp.
+
+    With --report, the command exits with code 3 when the scan violates the
+    policy, like \`socket scan report\`. Pass --fail-on to fail on alert
+    severity instead of on the "error" policy level, e.g. --fail-on=critical.
+    Socket API errors exit with 4, auth errors with 5.
+
+    With --reach, the --report output also tags each alert with whether the
+    project's JavaScript and TypeScript sources import its package, directly
+    or through other packages. Pass --only-reachable to leave out the alerts
+    of packages they cannot reach.
+
+    In a monorepo, --workspace-filter and --changed-since scan only some of
+    the npm, yarn, pnpm or bun workspaces under the current dir instead of
+    the TARGETs. With --report, each workspace then passes or fails on its
+    own. Pass --per-workspace to report every workspace separately.
+
+    With --from-sbom, the scan is made of the components of an existing
+    CycloneDX or SPDX JSON document, e.g. one produced by a container image
+    build, instead of the manifest files of the TARGETs. Components are
+    identified by their purl; those without one are skipped.
+
+    With --notify slack or --notify teams, a summary of the new scan is posted
+    to the chat incoming webhook of --webhook-url, or of the
+    SOCKET_CLI_NOTIFY_WEBHOOK_URL environment variable: the new critical
+    alerts and the alert diff compared with the latest scan of the repo's
+    default branch, and a link to the report. A failed notification only
+    warns, the scan is still created.
+
+    By default the command returns as soon as the scan is created, while
+    Socket analyses it in the background; \`socket scan await <ID>\` waits
+    for that analysis later. With --wait it waits for the analysis itself,
+    and fails when it is not done within --wait-timeout, so CI can gate on
+    it. --callback-url is then POSTed a JSON summary of the analysis: the
+    scan ID, its package count and its alert count of each severity.
+
+    The manifest files are uploaded in a single request. A failed upload is
+    retried as a whole, see --max-retries and --timeout. The Socket API has
+    no chunked or resumable upload yet, so each retry of a large upload
+    starts over.
+
+    With --all-repos, every repository of the GitHub organization of
+    --github-org or the GitLab group of --gitlab-group is shallow-cloned at
+    its default branch and scanned, or every git clone directly inside the
+    TARGET dir. Up to --repo-concurrency repositories are scanned at once.
+    The report ranks the repositories by the new critical alerts compared
+    with the previous scan of their default branch. Archived and empty
+    repositories are skipped. The command exits with code 1 when any
+    repository failed to scan.
+
+    You can use \`socket scan setup\` to configure certain repo flag defaults.
+
+    Examples
+      $ ${command}
+      $ ${command} ./proj --json
+      $ ${command} --repo=test-repo --branch=main ./package.json
+      $ ${command} --report --format=sarif > socket.sarif
+      $ ${command} --report --fail-on=high .
+      $ ${command} --reach --report --only-reachable .
+      $ ${command} --report --changed-since=origin/main
+      $ ${command} --from-sbom=sbom.cdx.json --repo=my-image --report
+      $ ${command} --notify=slack --webhook-url=https://hooks.slack.com/services/...
+      $ ${command} --wait --wait-timeout=20m --callback-url=https://ci.example.com/hook
+      $ ${command} --all-repos --github-org=acme --repo-concurrency=8
+      $ ${command} --all-repos ./clones --markdown
+  `
+}
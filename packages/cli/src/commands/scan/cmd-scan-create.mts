@@ -4,77 +4,41 @@ import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
 const logger = getDefaultLogger()
 
-import { applyScanCreateDefaults } from './cmd-scan-create-defaults.mts'
+import { allReposFlags } from './all-repos-flags.mts'
+import { runScanCreateAllRepos } from './cmd-scan-create-all-repos.mts'
 import {
   computeReachabilityFlagUsage,
   validateReachEcosystems,
   validateScanCreateInput,
 } from './cmd-scan-create-checks.mts'
+import { applyScanCreateDefaults } from './cmd-scan-create-defaults.mts'
+import { outputScanCreateDryRun } from './cmd-scan-create-dry-run.mts'
+import { getScanCreateHelp } from './cmd-scan-create-help.mts'
 import { resolveScanCreateTargetsAndOrg } from './cmd-scan-create-interactive.mts'
 import { validateScanCreateNumericFlags } from './cmd-scan-create-numeric-flags.mts'
 import { assertNoNegationPatterns } from './exclude-paths.mts'
+import { failOnFlags } from './fail-on-flags.mts'
 import { handleCreateNewScan } from './handle-create-new-scan.mts'
+import { handleCreateSbomScan } from './handle-create-sbom-scan.mts'
 import { excludePathsFlag, reachabilityFlags } from './reachability-flags.mts'
 import { validateReachabilityTarget } from './validate-reachability-target.mts'
-import { REQUIREMENTS_TXT } from '../../constants.mts'
-import { outputDryRunUpload } from '../../util/dry-run/output.mts'
+import { workspaceFlags } from './workspace-flags.mts'
+import { SOCKET_CLI_NOTIFY_WEBHOOK_URL } from '../../env/socket-cli-notify-webhook-url.mts'
+import { parseCacheDuration } from '../../util/cache/overrides.mts'
 import { defineFlags } from '../../meow.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mts'
-import {
-  getFlagApiRequirementsOutput,
-  getFlagListOutput,
-} from '../../util/output/formatting.mts'
 import { getOutputKind } from '../../util/output/mode.mts'
 import { cmdFlagValueToArray } from '../../util/process/cmd.mts'
 import { readOrDefaultSocketJsonUp } from '../../util/socket/json.mts'
 import { determineOrgSlug } from '../../util/socket/org-slug.mts'
 import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
-import { socketDashboardLink } from '../../util/terminal/link.mts'
 
-import type { REPORT_LEVEL } from './types.mts'
+import type { ScanCreateFlags } from './cmd-scan-create-flags.mts'
+import type { ScanNotifyChannel } from './scan-notify.mts'
+import type { FAIL_ON, REPORT_FORMAT } from './types.mts'
 import type { CliCommandContext } from '../../util/cli/with-subcommands.mts'
 import type { PURL_Type } from '../../util/ecosystem/types.mts'
 
-// Flags interface for type safety.
-export interface ScanCreateFlags {
-  autoManifest?: boolean | undefined
-  basics?: boolean | undefined
-  branch: string
-  commitHash: string
-  commitMessage: string
-  committers: string
-  cwd: string
-  defaultBranch: boolean
-  makeDefaultBranch: boolean
-  interactive: boolean
-  json: boolean
-  markdown: boolean
-  org: string
-  pullRequest: number
-  reach: boolean
-  reachAnalysisMemoryLimit: number
-  reachAnalysisTimeout: number
-  reachConcurrency: number
-  reachDebug: boolean
-  reachDetailedAnalysisLogFile: boolean
-  reachDisableAnalytics: boolean
-  reachDisableExternalToolChecks: boolean
-  reachEnableAnalysisSplitting: boolean
-  reachLazyMode: boolean
-  reachMinSeverity: string
-  reachSkipCache: boolean
-  reachUseOnlyPregeneratedSboms: boolean
-  reachUseUnreachableFromPrecomputation: boolean
-  reachVersion: string
-  readOnly: boolean
-  repo: string
-  report?: boolean | undefined
-  reportLevel: REPORT_LEVEL
-  setAsAlertsPage: boolean
-  tmp: boolean
-  workspace: string
-}
-
 export const CMD_NAME = 'create'
 
 const description = 'Create a new Socket scan and report'
@@ -119,59 +83,13 @@ export async function run(
     flags: defineFlags({
       ...generalFlags,
       ...excludePathsFlag,
+      ...workspaceFlags,
+      ...failOnFlags,
+      ...allReposFlags,
       ...reachabilityFlags,
     }),
-    help: (command: string) => `
-    Usage
-      $ ${command} [options] [TARGET...]
-
-    API Token Requirements
-      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
-
-    Options
-      ${getFlagListOutput({ ...generalFlags, ...excludePathsFlag })}
-
-    Reachability Options (when --reach is used)
-      ${getFlagListOutput(reachabilityFlags)}
-
-    Uploads the specified dependency manifest files for Go, Gradle, JavaScript,
-    Kotlin, Python, and Scala. Files like "package.json" and "${REQUIREMENTS_TXT}".
-    If any folder is specified, the ones found in there recursively are uploaded.
-
-    Details on TARGET:
-
-    - Defaults to the current dir (cwd) if none given
-    - Multiple targets can be specified
-    - If a target is a file, only that file is checked
-    - If it is a dir, the dir is scanned for any supported manifest files
-    - Dirs MUST be within the current dir (cwd), you can use --cwd to change it
-    - Supports globbing such as "**/package.json", "**/${REQUIREMENTS_TXT}", etc.
-    - Ignores files specified in your project's ".gitignore"
-    - Ignores files specified in your "socket.yml" file's "projectIgnorePaths"
-    - Also a sensible set of default ignores from the "ignore-by-default" module
-
-    The --repo and --branch flags tell Socket to associate this Scan with that
-    repo/branch. The names will show up on your dashboard on the Socket website.
-
-    Note: on a first scan you probably want to pass --make-default-branch so
-          Socket records this branch ("main", "master", etc.) as your repo's
-          default branch. Subsequent scans don't need the flag unless you're
-          reassigning the default-branch pointer to a different branch.
-
-    The ${socketDashboardLink('/org/YOURORG/alerts', '"alerts page"')} will show
-    the results from the last scan designated as the "pending head" on the branch
-    configured on Socket to be the "default branch". When creating a scan the
-    --set-as-alerts-page flag will default to true to update this. You can prevent
-    this by using --no-set-as-alerts-page. This flag is ignored for any branch that
-    is not designated as the "default branch". It is disabled when using --tmp.
-
-    You can use \`socket scan setup\` to configure certain repo flag defaults.
-
-    Examples
-      $ ${command}
-      $ ${command} ./proj --json
-      $ ${command} --repo=test-repo --branch=main ./package.json
-  `,
+    help: (command: string) =>
+      getScanCreateHelp(command, `${parentName}:${CMD_NAME}`),
   }
 
   // `--make-default-branch` (and its deprecated alias `--default-branch`)
@@ -211,16 +129,27 @@ export async function run(
   })
 
   const {
+    allRepos,
+    callbackUrl,
+    changedSince,
     commitHash,
     commitMessage,
     committers,
     cwd: cwdOverride,
     defaultBranch: legacyDefaultBranch,
+    failOn,
+    format: reportFormat,
+    fromSbom,
+    githubOrg,
+    gitlabGroup,
     interactive,
     makeDefaultBranch: makeDefaultBranchFlag,
     json,
     markdown,
+    notify: notifyChannel,
+    onlyReachable,
     org: orgFlag,
+    perWorkspace,
     pullRequest,
     reach,
     reachAnalysisMemoryLimit,
@@ -238,9 +167,13 @@ export async function run(
     reachUseUnreachableFromPrecomputation,
     reachVersion,
     readOnly,
+    repoConcurrency,
     reportLevel,
     setAsAlertsPage: pendingHeadFlag,
     tmp,
+    wait,
+    waitTimeout,
+    webhookUrl: webhookUrlFlag,
   } = cli.flags as unknown as ScanCreateFlags
 
   // Merge the legacy --default-branch flag into the primary. Both are
@@ -297,8 +230,30 @@ export async function run(
 
   const pendingHead = tmp ? false : pendingHeadFlag
 
+  const webhookUrl = webhookUrlFlag || SOCKET_CLI_NOTIFY_WEBHOOK_URL
+
+  const waitTimeoutMs = parseCacheDuration(waitTimeout)
+
+  if (allRepos || githubOrg || gitlabGroup) {
+    await runScanCreateAllRepos({
+      allRepos,
+      cwd,
+      dryRun,
+      flags: cli.flags,
+      githubOrg,
+      gitlabGroup,
+      hasApiToken,
+      inputTargets: cli.input,
+      orgSlug,
+      outputKind,
+      repoConcurrency,
+    })
+    return
+  }
+
   const suggestResult = await resolveScanCreateTargetsAndOrg({
-    autoManifest,
+    // An imported SBOM needs no generated manifests, skip the hint.
+    autoManifest: autoManifest || !!fromSbom,
     cli,
     cwd,
     dryRun,
@@ -319,6 +274,9 @@ export async function run(
 
   const reachExcludePaths = cmdFlagValueToArray(cli.flags['reachExcludePaths'])
 
+  const workspaceFilter = cmdFlagValueToArray(cli.flags['workspaceFilter'])
+  const selectsWorkspaces = !!changedSince || !!workspaceFilter.length
+
   const isUsingAnyReachabilityFlags = computeReachabilityFlagUsage({
     reachAnalysisMemoryLimit,
     reachAnalysisTimeout,
@@ -342,44 +300,37 @@ export async function run(
       }
 
   const wasValidInput = validateScanCreateInput({
+    basics,
     branchName,
+    callbackUrl,
+    failOn,
+    fromSbom,
     hasApiToken,
+    hasTargetInput: cli.input.length > 0,
     isUsingAnyReachabilityFlags,
     json,
     makeDefaultBranch,
     markdown,
+    notifyChannel,
+    onlyReachable,
     orgSlug,
     outputKind,
     pendingHead,
+    perWorkspace,
     reach,
     reachTargetValidation,
+    report,
+    reportFormat,
+    selectsWorkspaces,
     targets,
+    wait,
+    waitTimeoutMs,
+    webhookUrl,
   })
   if (!wasValidInput) {
     return
   }
 
-  if (dryRun) {
-    const details: Record<string, unknown> = {
-      organization: orgSlug,
-      targets: targets.join(', '),
-    }
-    if (repoName) {
-      details['repository'] = repoName
-    }
-    if (branchName) {
-      details['branch'] = branchName
-    }
-    if (reach) {
-      details['reachabilityAnalysis'] = 'enabled'
-      if (reachEcosystems.length > 0) {
-        details['ecosystems'] = reachEcosystems.join(', ')
-      }
-    }
-    outputDryRunUpload('scan', details)
-    return
-  }
-
   // Validate numeric flag conversions.
   const {
     validatedPullRequest,
@@ -393,6 +344,75 @@ export async function run(
     reachConcurrency,
   })
 
+  if (dryRun) {
+    outputScanCreateDryRun({
+      branchName,
+      callbackUrl,
+      changedSince,
+      commitHash,
+      commitMessage,
+      committers,
+      failOn,
+      fromSbom,
+      makeDefaultBranch,
+      notifyChannel,
+      onlyReachable,
+      orgSlug,
+      pendingHead,
+      perWorkspace,
+      pullRequest: validatedPullRequest,
+      reach,
+      reachEcosystems,
+      repoName,
+      report,
+      selectsWorkspaces,
+      targets,
+      tmp,
+      wait,
+      waitTimeout,
+      workspace,
+      workspaceFilter,
+    })
+    return
+  }
+
+  const notify = notifyChannel
+    ? { channel: notifyChannel as ScanNotifyChannel, webhookUrl }
+    : undefined
+
+  const scanWait =
+    wait && waitTimeoutMs !== undefined
+      ? { callbackUrl: callbackUrl || undefined, timeoutMs: waitTimeoutMs }
+      : undefined
+
+  if (fromSbom) {
+    await handleCreateSbomScan({
+      branchName,
+      commitHash: commitHash || '',
+      commitMessage: commitMessage || '',
+      committers: committers || '',
+      cwd,
+      defaultBranch: makeDefaultBranch,
+      failOn: (failOn || undefined) as FAIL_ON | undefined,
+      interactive,
+      notify,
+      orgSlug,
+      outputKind,
+      pendingHead,
+      pullRequest: validatedPullRequest,
+      readOnly,
+      repoName,
+      report,
+      reportFormat: (reportFormat || undefined) as REPORT_FORMAT | undefined,
+      reportLevel,
+      sbomPath: fromSbom,
+      tmp,
+      wait: scanWait,
+      workspace: workspace || '',
+    })
+    return
+  }
+
   await handleCreateNewScan({
     autoManifest: autoManifest,
     basics: Boolean(basics),
@@ -402,7 +422,10 @@ export async function run(
     committers: (committers && committers) || '',
     cwd,
     defaultBranch: makeDefaultBranch,
+    failOn: (failOn || undefined) as FAIL_ON | undefined,
     interactive: interactive,
+    notify,
+    onlyReachable,
     orgSlug,
     outputKind,
     pendingHead: pendingHead,
@@ -431,9 +454,15 @@ export async function run(
     readOnly: readOnly,
     repoName,
     report,
+    reportFormat: (reportFormat || undefined) as REPORT_FORMAT | undefined,
     reportLevel,
     targets,
     tmp: tmp,
+    wait: scanWait,
     workspace: (workspace && workspace) || '',
+    workspaces:
+      perWorkspace || selectsWorkspaces
+        ? { changedSince, workspaceFilter }
+        : undefined,
   })
 }
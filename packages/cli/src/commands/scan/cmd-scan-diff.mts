@@ -1,3 +1,10 @@
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { alertFilterFlags } from './alert-filter-flags.mts'
+import {
+  getScanAlertFilter,
+  getScanAlertFilterDetails,
+} from './filter-scan-alerts.mts'
 import { handleDiffScan } from './handle-diff-scan.mts'
 import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
 import { outputDryRunFetch } from '../../util/dry-run/output.mts'
@@ -10,6 +17,7 @@ import {
   getFlagListOutput,
 } from '../../util/output/formatting.mts'
 import { getOutputKind } from '../../util/output/mode.mjs'
+import { POLICY_SEVERITIES } from '../../util/policy/socket-policy.mts'
 import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
 import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
 import { checkCommandInput } from '../../util/validation/check-input.mts'
@@ -19,6 +27,7 @@ import type { MeowFlags } from '../../flags.mts'
 
 // Flags interface for type safety.
 export interface ScanDiffFlags {
+  alerts: boolean
   depth: number
   dryRun: boolean
   file: string
@@ -51,6 +60,13 @@ export async function run(
     flags: defineFlags({
       ...commonFlags,
       ...outputFlags,
+      ...alertFilterFlags,
+      alerts: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Compare alerts of both scans against the security policy and exit with code 3 when new alerts violate it. Use --no-alerts to only show package changes.',
+      },
       depth: {
         type: 'number',
         default: 2,
@@ -87,6 +103,16 @@ export async function run(
     can be pretty large depending on the size of your repo and time range. It is
     best stored to disk (with --json) to be further analyzed by other tools.
 
+    New and resolved alerts are listed alongside the package changes. The
+    command exits with code 3 only when newly introduced alerts have an
+    "error" action in the organization security policy, which makes it
+    suitable as a pull request gate. Alerts are matched by package and alert
+    type, so upgrading a package that keeps an existing alert does not fail.
+
+    Use --severity, --alert-type, --ecosystem and --direct-only to only show
+    some of the packages and alerts. Only the alerts they include are compared,
+    so they also narrow down what fails the command.
+
     Note: While it will work in any order, the first Scan ID is assumed to be the
           older ID, even if it is a newer Scan. This is only relevant for the
           added/removed list (similar to diffing two files with git).
@@ -97,6 +123,8 @@ export async function run(
     Examples
       $ ${command} aaa0aa0a-aaaa-0000-0a0a-0000000a00a0 aaa1aa1a-aaaa-1111-1a1a-1111111a11a1
       $ ${command} aaa0aa0a-aaaa-0000-0a0a-0000000a00a0 aaa1aa1a-aaaa-1111-1a1a-1111111a11a1 --json
+      $ ${command} aaa0aa0a-aaaa-0000-0a0a-0000000a00a0 aaa1aa1a-aaaa-1111-1a1a-1111111a11a1 --no-alerts
+      $ ${command} aaa0aa0a-aaaa-0000-0a0a-0000000a00a0 aaa1aa1a-aaaa-1111-1a1a-1111111a11a1 --severity=high --ecosystem=npm
   `,
   }
 
@@ -111,6 +139,7 @@ export async function run(
   const SOCKET_SBOM_URL_PREFIX_LENGTH = SOCKET_SBOM_URL_PREFIX.length
 
   const {
+    alerts,
     depth,
     dryRun,
     file,
@@ -121,6 +150,10 @@ export async function run(
 
   const interactive = cli.flags['interactive']
 
+  const alertFilter = getScanAlertFilter(cli.flags)
+
+  const severity = alertFilter?.severity ?? ''
+
   let [id1 = '', id2 = ''] = cli.input
   // Support dropping in full socket urls to an sbom.
   if (id1.startsWith(SOCKET_SBOM_URL_PREFIX)) {
@@ -165,6 +198,14 @@ export async function run(
       message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
       fail: 'bad',
     },
+    {
+      nook: true,
+      test:
+        !severity ||
+        (POLICY_SEVERITIES as readonly string[]).includes(severity),
+      message: `The --severity flag must be ${joinOr(POLICY_SEVERITIES.map(s => `'${s}'`))}`,
+      fail: `got ${severity}`,
+    },
     {
       nook: true,
       test: hasApiToken,
@@ -182,11 +223,15 @@ export async function run(
       scanId1: id1,
       scanId2: id2,
       depth,
+      alerts,
+      ...getScanAlertFilterDetails(alertFilter),
     })
     return
   }
 
   await handleDiffScan({
+    alertFilter,
+    alerts,
     id1,
     id2,
     depth,
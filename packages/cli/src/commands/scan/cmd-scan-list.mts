@@ -1,4 +1,9 @@
+import {
+  SCAN_LIST_API_SORTS,
+  SCAN_LIST_LOCAL_SORTS,
+} from './fetch-list-scans.mts'
 import { handleListScans } from './handle-list-scans.mts'
+import { parseAuditLogSince } from '../audit-log/audit-log-export.mts'
 import { outputDryRunFetch } from '../../util/dry-run/output.mts'
 import { InputError } from '../../util/error/errors.mts'
 import { V1_MIGRATION_GUIDE_URL } from '../../constants/socket.mts'
@@ -45,10 +50,20 @@ export async function run(
     flags: defineFlags({
       ...commonFlags,
       ...outputFlags,
+      all: {
+        type: 'boolean',
+        default: false,
+        description: 'Fetch every page of scans from --page on',
+      },
       branch: {
         type: 'string',
         description: 'Filter to show only scans with this branch name',
       },
+      committer: {
+        type: 'string',
+        default: '',
+        description: 'Filter to show only scans with this committer',
+      },
       direction: {
         type: 'string',
         shortFlag: 'd',
@@ -67,6 +82,12 @@ export async function run(
         description:
           'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
       },
+      limit: {
+        type: 'number',
+        default: 0,
+        description:
+          'Show at most this many scans, fetching as many pages as needed',
+      },
       page: {
         type: 'number',
         shortFlag: 'p',
@@ -84,12 +105,24 @@ export async function run(
         description:
           'Force override the organization slug, overrides the default org from config',
       },
+      since: {
+        type: 'string',
+        default: '',
+        description:
+          'Filter to show only scans created from this date or time on, like 2025-04-01 or 7d',
+      },
       sort: {
         type: 'string',
         shortFlag: 's',
         default: 'created_at',
         description:
-          'Sorting option (`name` or `created_at`) - default is `created_at`',
+          'Sorting option (`name`, `created_at`, `branch`, `repo` or `status`) - default is `created_at`',
+      },
+      status: {
+        type: 'string',
+        default: '',
+        description:
+          'Filter to show only scans in this state, like `pending` or `scan`',
       },
       untilTime: {
         type: 'string',
@@ -109,12 +142,23 @@ export async function run(
     branch to filter by. (Note: If you don't specify a repo then you must use
     \`--branch\` to filter by branch across all repos).
 
+    --since takes a date or ISO date-time, or a time back from now in days,
+    hours or minutes (7d, 12h, 30m). The list endpoint cannot filter by
+    --committer or --status, so pages are fetched until --per-page scans
+    match, or --limit scans. Sorting by branch, repo or status orders only
+    the fetched scans. Without these filters one page is fetched, unless
+    --limit or --all is set. When --limit stops partway through a page, the
+    next page in the --json output is that page, which lists some of the
+    scans again.
+
     Options
       ${getFlagListOutput(helpConfig.flags)}
 
     Examples
       $ ${command}
       $ ${command} webtools badbranch --markdown
+      $ ${command} --since 7d --committer jdoe --limit 10
+      $ ${command} webtools --all --sort branch --json
   `,
   }
 
@@ -125,7 +169,23 @@ export async function run(
     parentName,
   })
 
-  const { branch: branchFlag, json, markdown, org: orgFlag } = cli.flags
+  const {
+    all,
+    branch: branchFlag,
+    committer,
+    json,
+    markdown,
+    org: orgFlag,
+    status,
+  } = cli.flags as {
+    all: boolean
+    branch: string
+    committer: string
+    json: boolean
+    markdown: boolean
+    org: string
+    status: string
+  }
 
   const dryRun = cli.flags['dryRun']
 
@@ -137,6 +197,14 @@ export async function run(
 
   const branch = branchFlag || branchArg || ''
 
+  const sinceFlag = String(cli.flags['since'] || '')
+
+  const since = sinceFlag ? parseAuditLogSince(sinceFlag) : undefined
+
+  const fromTime = String(cli.flags['fromTime'] || '')
+
+  const sort = String(cli.flags['sort'] || 'created_at')
+
   const hasApiToken = hasDefaultApiToken()
 
   const { 0: orgSlug } = await determineOrgSlug(
@@ -180,6 +248,26 @@ export async function run(
         'You should not set --branch and also give a second arg for branch name',
       fail: 'received flag and second arg',
     },
+    {
+      nook: true,
+      test: !sinceFlag || since !== undefined,
+      message: 'The --since flag must be a date, date-time or like 7d',
+      fail: 'invalid date',
+    },
+    {
+      nook: true,
+      test: !sinceFlag || !fromTime,
+      message: 'The --since and --from-time flags cannot be used together',
+      fail: 'pick one',
+    },
+    {
+      nook: true,
+      test: [...SCAN_LIST_API_SORTS, ...SCAN_LIST_LOCAL_SORTS].includes(
+        sort as (typeof SCAN_LIST_API_SORTS)[number],
+      ),
+      message: `The --sort flag must be one of ${[...SCAN_LIST_API_SORTS, ...SCAN_LIST_LOCAL_SORTS].join(', ')}`,
+      fail: 'unknown sort',
+    },
   )
   if (!wasValidInput) {
     return
@@ -188,16 +276,26 @@ export async function run(
   // Validate numeric pagination parameters.
   const validatedPage = Number(cli.flags['page'] || 1)
   const validatedPerPage = Number(cli.flags['perPage'] || 30)
+  const validatedLimit = Number(cli.flags['limit'] || 0)
+
+  // The list endpoint takes unix seconds.
+  const from_time =
+    since === undefined ? fromTime : String(Math.floor(since / 1000))
 
   if (dryRun) {
     outputDryRunFetch('scans', {
       organization: orgSlug,
       repo: repo || undefined,
       branch: branch || undefined,
-      sort: cli.flags['sort'] || 'created_at',
+      from: from_time || undefined,
+      committer: committer || undefined,
+      status: status || undefined,
+      sort,
       direction: cli.flags['direction'] || 'desc',
       page: validatedPage,
       perPage: validatedPerPage,
+      ...(all ? { all: true } : {}),
+      ...(validatedLimit ? { limit: validatedLimit } : {}),
     })
     return
   }
@@ -212,16 +310,29 @@ export async function run(
       `--per-page must be a positive integer (saw: "${cli.flags['perPage']}"); pass a number like --per-page=30`,
     )
   }
+  if (
+    Number.isNaN(validatedLimit) ||
+    validatedLimit < 0 ||
+    !Number.isInteger(validatedLimit)
+  ) {
+    throw new InputError(
+      `--limit must be a positive integer (saw: "${cli.flags['limit']}"); pass a number like --limit=100`,
+    )
+  }
 
   await handleListScans({
+    ...(all ? { all } : {}),
     branch: branch ? branch : '',
+    ...(committer ? { committer } : {}),
     direction: cli.flags['direction'] || '',
-    from_time: cli.flags['fromTime'] || '',
+    from_time,
+    ...(validatedLimit ? { limit: validatedLimit } : {}),
     orgSlug,
     outputKind,
     page: validatedPage,
     perPage: validatedPerPage,
     repo: repo ? repo : '',
-    sort: cli.flags['sort'] || '',
+    sort,
+    ...(status ? { status } : {}),
   })
 }
@@ -0,0 +1,80 @@
+/**
+ * Flag schema of `socket scan report`, next to the shared output, template,
+ * CSV, workspace, --fail-on and alert filter flags it is combined with.
+ */
+
+import { FOLD_SETTING_NONE } from '../../constants/cli.mts'
+import { REPORT_FORMATS, REPORT_LEVEL_WARN } from '../../constants/reporting.mts'
+import { SOCKET_BASELINE_JSON } from '../../constants/socket.mts'
+import { SOCKET_CLI_OFFLINE } from '../../env/socket-cli-offline.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+
+export const scanReportFlags: MeowFlags = {
+  baseline: {
+    type: 'string',
+    default: '',
+    description: `Baseline file of known alerts to leave out of the report (default: the nearest ${SOCKET_BASELINE_JSON})`,
+  },
+  fold: {
+    type: 'string',
+    default: FOLD_SETTING_NONE,
+    description: `Fold reported alerts to some degree (default '${FOLD_SETTING_NONE}')`,
+  },
+  format: {
+    type: 'string',
+    default: '',
+    description: `Render the report in an alternative format instead of the default json/markdown/text. Supported: ${REPORT_FORMATS.map(f => `'${f}'`).join(', ')}`,
+  },
+  interactive: {
+    type: 'boolean',
+    default: true,
+    description:
+      'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+  },
+  offline: {
+    type: 'boolean',
+    default: SOCKET_CLI_OFFLINE,
+    description:
+      'Read the scan and security policy from an imported offline bundle without calling the Socket API',
+  },
+  org: {
+    type: 'string',
+    description:
+      'Force override the organization slug, overrides the default org from config',
+  },
+  output: {
+    type: 'string',
+    default: '',
+    description:
+      'Write the report to this file instead of stdout, like the OUTPUT_PATH argument',
+    shortFlag: 'o',
+  },
+  reportLevel: {
+    type: 'string',
+    default: REPORT_LEVEL_WARN,
+    description: `Which policy level alerts should be reported (default '${REPORT_LEVEL_WARN}')`,
+  },
+  short: {
+    type: 'boolean',
+    default: false,
+    description: 'Report only the healthy status',
+  },
+  showEvidence: {
+    type: 'boolean',
+    default: false,
+    description:
+      'List the file and a redacted snippet of each secret alert, for credentials embedded in dependencies',
+  },
+  vex: {
+    type: 'string',
+    isMultiple: true,
+    description:
+      'OpenVEX, CSAF or CycloneDX VEX document whose not_affected and fixed statements leave out matching vulnerability alerts. Accepts multiple flags',
+  },
+  license: {
+    type: 'boolean',
+    default: false,
+    description: 'Also report the license policy status. Default: false',
+  },
+}
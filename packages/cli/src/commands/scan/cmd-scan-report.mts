@@ -1,20 +1,59 @@
+import { existsSync } from 'node:fs'
+import path from 'node:path'
+
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { alertFilterFlags } from './alert-filter-flags.mts'
+import { scanReportFlags } from './cmd-scan-report-flags.mts'
+import { failOnFlags } from './fail-on-flags.mts'
+import {
+  getScanAlertFilter,
+  getScanAlertFilterDetails,
+} from './filter-scan-alerts.mts'
+import {
+  SCAN_REPORT_CSV_COLUMNS,
+  SCAN_REPORT_CSV_DEFAULT_COLUMNS,
+} from './generate-csv-report.mts'
 import { handleScanReport } from './handle-scan-report.mts'
-import { FOLD_SETTING_NONE } from '../../constants/cli.mts'
+import { workspaceFlags } from './workspace-flags.mts'
 import { outputDryRunFetch } from '../../util/dry-run/output.mts'
-import { REPORT_LEVEL_WARN } from '../../constants/reporting.mts'
+import {
+  FAIL_ON_LEVELS,
+  REPORT_FORMAT_CSV,
+  REPORT_FORMAT_GITLAB_CODE_QUALITY,
+  REPORT_FORMAT_GITLAB_SAST,
+  REPORT_FORMAT_HTML,
+  REPORT_FORMAT_JUNIT,
+  REPORT_FORMAT_SARIF,
+  REPORT_FORMATS,
+} from '../../constants/reporting.mts'
 import { defineFlags } from '../../meow.mts'
-import { commonFlags, outputFlags } from '../../flags.mts'
+import {
+  commonFlags,
+  csvFlags,
+  outputFlags,
+  templateFlags,
+} from '../../flags.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
 import {
   getFlagApiRequirementsOutput,
   getFlagListOutput,
 } from '../../util/output/formatting.mts'
+import { getCsvColumns, getUnknownCsvColumns } from '../../util/output/csv.mts'
 import { getOutputKind } from '../../util/output/mode.mjs'
+import { POLICY_SEVERITIES } from '../../util/policy/socket-policy.mts'
+import { cmdFlagValueToArray } from '../../util/process/cmd.mts'
 import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
 import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
 import { checkCommandInput } from '../../util/validation/check-input.mts'
 
-import type { FOLD_SETTING, REPORT_LEVEL } from './types.mts'
+import type { ScanReportCsvColumn } from './generate-csv-report.mts'
+import type {
+  FAIL_ON,
+  FOLD_SETTING,
+  REPORT_FORMAT,
+  REPORT_LEVEL,
+} from './types.mts'
 import type {
   CliCommandContext,
   CliSubcommand,
@@ -23,10 +62,14 @@ import type { MeowFlags } from '../../flags.mts'
 
 // Flags interface for type safety.
 export interface ScanReportFlags {
+  failOn: string
   fold: FOLD_SETTING
+  format: string
   json: boolean
   markdown: boolean
   org: string
+  output: string
+  outputTemplate: string
   reportLevel: REPORT_LEVEL
 }
 
@@ -55,37 +98,12 @@ export async function run(
     flags: defineFlags({
       ...commonFlags,
       ...outputFlags,
-      fold: {
-        type: 'string',
-        default: FOLD_SETTING_NONE,
-        description: `Fold reported alerts to some degree (default '${FOLD_SETTING_NONE}')`,
-      },
-      interactive: {
-        type: 'boolean',
-        default: true,
-        description:
-          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
-      },
-      org: {
-        type: 'string',
-        description:
-          'Force override the organization slug, overrides the default org from config',
-      },
-      reportLevel: {
-        type: 'string',
-        default: REPORT_LEVEL_WARN,
-        description: `Which policy level alerts should be reported (default '${REPORT_LEVEL_WARN}')`,
-      },
-      short: {
-        type: 'boolean',
-        default: false,
-        description: 'Report only the healthy status',
-      },
-      license: {
-        type: 'boolean',
-        default: false,
-        description: 'Also report the license policy status. Default: false',
-      },
+      ...templateFlags,
+      ...csvFlags,
+      ...workspaceFlags,
+      ...failOnFlags,
+      ...alertFilterFlags,
+      ...scanReportFlags,
     }),
     help: (command: string, helpConfig: { flags: MeowFlags }) => `
     Usage
@@ -97,7 +115,7 @@ export async function run(
     Options
       ${getFlagListOutput(helpConfig.flags)}
 
-    When no output path is given the contents is sent to stdout.
+    When no output path (or --output) is given the contents is sent to stdout.
 
     By default the result is a nested object that looks like this:
       \`{
@@ -117,6 +135,77 @@ export async function run(
     By default only the warn and error policy level alerts are reported. You can
     override this and request more ('defer' < 'ignore' < 'monitor' < 'warn' < 'error')
 
+    Use --format=${REPORT_FORMAT_SARIF} to emit a SARIF 2.1.0 log that can be uploaded to
+    GitHub Code Scanning. Results point at the manifest file (and line, when the
+    manifest exists in the current dir) that introduced the offending package.
+    The --fold and --short flags do not apply to SARIF output.
+
+    Use --format=${REPORT_FORMAT_GITLAB_CODE_QUALITY} or --format=${REPORT_FORMAT_GITLAB_SAST} to emit the same
+    results as a GitLab Code Quality or SAST report. Declare the file under
+    \`artifacts:reports:codequality\` or \`artifacts:reports:sast\` in
+    .gitlab-ci.yml and the alerts show up in the merge request.
+
+    Use --format=${REPORT_FORMAT_JUNIT} to emit JUnit XML for CI test reporters, with one
+    test case per alert. Alerts that make the report unhealthy are failures;
+    the others pass and carry their details as output.
+
+    Use --format=${REPORT_FORMAT_HTML} to emit a self-contained HTML page with sortable alert and
+    package tables, a score breakdown and a dependency graph. Selecting an
+    alert highlights the paths from direct dependencies that introduce the
+    alerted package.
+
+    Use --format=${REPORT_FORMAT_CSV} to emit one row per alert for spreadsheets. Pick the
+    columns, in order, with --columns: package, version, ecosystem, purl,
+    severity, alertType, policy, directDep, filePath and url. The default is
+    ${SCAN_REPORT_CSV_DEFAULT_COLUMNS.join(',')}. Use
+    --no-csv-header to leave out the header row.
+
+    Use --output-template=<file> to render the report with your own template,
+    e.g. as Slack blocks, Confluence markup or a ticket body. Templates use a
+    Handlebars-style syntax: \`{{orgSlug}}\`, \`{{#each alerts}}...{{/each}}\`,
+    \`{{#if healthy}}...{{else}}...{{/if}}\` and the helpers \`json\`, \`upper\`,
+    \`lower\`, \`length\`, \`join\`, \`default\` and \`eq\`. The context has
+    \`orgSlug\`, \`scanId\`, \`healthy\`, \`reportLevel\`, \`counts\` (per policy
+    level and \`total\`) and \`alerts\`, each with \`type\`, \`title\`,
+    \`description\`, \`fix\`, \`message\`, \`level\`, \`policy\`, \`severity\`,
+    \`purl\`, \`manifest\`, \`line\` and \`url\`.
+
+    The report is unhealthy when an alert has the "error" policy level. Use
+    --fail-on to fail on alert severity instead, e.g. --fail-on=critical to
+    only fail on critical alerts or --fail-on=none to never fail. It decides
+    the exit code for every output format.
+
+    Exit codes: 0 when the report is healthy, 3 when it violates the policy
+    or --fail-on, 4 on Socket API errors, 5 on auth errors and 2 on invalid
+    usage. Other failures exit with 1.
+
+    Alerts recorded in a baseline file (see \`socket scan baseline write\`)
+    are left out, so only new alerts make the report unhealthy.
+
+    With --vex, vulnerability alerts that a VEX document marks as
+    not_affected or fixed are left out as well, matched by CVE or GHSA id
+    and package purl. See \`socket sbom vex\` to export your own triage.
+
+    On an air-gapped machine, import a bundle made with \`socket bundle export\`
+    and report with --offline (or SOCKET_CLI_OFFLINE=1). The scan and the org
+    security policy are then read from the bundle and no API token is needed.
+
+    Use --severity, --alert-type, --ecosystem and --direct-only to only
+    report some of the alerts, e.g. --severity=high --ecosystem=npm. Alerts
+    they leave out do not make the report unhealthy either.
+
+    Credentials and keys embedded in the files of dependencies are secret
+    alerts, in a category of their own: --alert-category=secrets only reports
+    them. With --show-evidence each lists the file it was found in and the
+    line, with the secret redacted. The line is read from the package
+    installed under the current dir when its version matches the scan.
+
+    In a monorepo, --per-workspace reports each npm, yarn, pnpm or bun
+    workspace under the current dir on its own, attributing packages by the
+    manifest files that pull them in. Every workspace passes or fails
+    independently and the command exits with code 3 when any fails. Use
+    --workspace-filter and --changed-since to only report some of them.
+
     Short responses look like this:
       --json:     \`{healthy:bool}\`
       --markdown: \`healthy = bool\`
@@ -125,6 +214,17 @@ export async function run(
     Examples
       $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --json --fold=version
       $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --license --markdown --short
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --fail-on=high
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format=sarif socket.sarif
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format=gitlab-code-quality gl-code-quality-report.json
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format=junit socket-junit.xml
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format=html --output report.html
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --format=csv --columns=package,version,severity alerts.csv
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --output-template=slack.hbs
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --vex vendor.openvex.json
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --changed-since=origin/main
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --alert-type=malware,criticalCVE --direct-only
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --alert-category=secrets --show-evidence --markdown
   `,
   }
 
@@ -136,28 +236,63 @@ export async function run(
   })
 
   const {
+    failOn,
     fold,
+    format,
     json,
     markdown,
     org: orgFlag,
+    output,
+    outputTemplate,
     reportLevel,
   } = cli.flags as unknown as ScanReportFlags
 
+  const baselineFlag = String(cli.flags['baseline'] || '')
+
   const dryRun = cli.flags['dryRun']
 
   const interactive = cli.flags['interactive']
 
   const includeLicensePolicy = cli.flags['license']
 
+  const offline = !!cli.flags['offline']
+
   const short = cli.flags['short']
 
-  const [scanId = '', filepath = ''] = cli.input
+  const showEvidence = !!cli.flags['showEvidence']
+
+  const changedSince = String(cli.flags['changedSince'] || '')
+
+  const workspaceFilter = cmdFlagValueToArray(cli.flags['workspaceFilter'])
+
+  const vexPaths = cmdFlagValueToArray(cli.flags['vex'])
+
+  const alertFilter = getScanAlertFilter(cli.flags)
+
+  const columns = getCsvColumns(
+    cli.flags['columns'],
+    SCAN_REPORT_CSV_DEFAULT_COLUMNS,
+  )
+
+  const unknownColumns = getUnknownCsvColumns(columns, SCAN_REPORT_CSV_COLUMNS)
+
+  const csvHeader = !!cli.flags['csvHeader']
+
+  const severity = alertFilter?.severity ?? ''
+
+  const perWorkspace =
+    !!cli.flags['perWorkspace'] || !!changedSince || !!workspaceFilter.length
+
+  const [scanId = '', outputPath = ''] = cli.input
+
+  const filepath = output || outputPath
 
   const hasApiToken = hasDefaultApiToken()
 
+  // Offline runs cannot auto-discover the org through the API.
   const { 0: orgSlug } = await determineOrgSlug(
     orgFlag || '',
-    interactive,
+    interactive && !offline,
     dryRun,
   )
 
@@ -184,7 +319,79 @@ export async function run(
     },
     {
       nook: true,
-      test: hasApiToken,
+      test: !format || (REPORT_FORMATS as readonly string[]).includes(format),
+      message: `The --format flag must be ${joinOr(REPORT_FORMATS.map(f => `'${f}'`))}`,
+      fail: 'unsupported format',
+    },
+    {
+      nook: true,
+      test:
+        format === REPORT_FORMAT_CSV || (!cli.flags['columns'] && csvHeader),
+      message: `The --columns and --no-csv-header flags require --format=${REPORT_FORMAT_CSV}`,
+      fail: 'add --format=csv',
+    },
+    {
+      nook: true,
+      test: !unknownColumns.length,
+      message: `Every --columns name must be ${joinOr(SCAN_REPORT_CSV_COLUMNS.map(c => `'${c}'`))}`,
+      fail: `got ${unknownColumns.join(', ')}`,
+    },
+    {
+      nook: true,
+      test: !failOn || (FAIL_ON_LEVELS as readonly string[]).includes(failOn),
+      message: `The --fail-on flag must be ${joinOr(FAIL_ON_LEVELS.map(f => `'${f}'`))}`,
+      fail: `got ${failOn}`,
+    },
+    {
+      nook: true,
+      test:
+        !severity ||
+        (POLICY_SEVERITIES as readonly string[]).includes(severity),
+      message: `The --severity flag must be ${joinOr(POLICY_SEVERITIES.map(s => `'${s}'`))}`,
+      fail: `got ${severity}`,
+    },
+    {
+      nook: true,
+      test: !format || !markdown,
+      message: 'The --format and --markdown flags cannot be both set',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !output || !outputPath,
+      message: 'The --output flag and the OUTPUT_PATH argument cannot be both set',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !outputTemplate || (!format && !json && !markdown),
+      message:
+        'The --output-template flag cannot be combined with --format, --json or --markdown',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !outputTemplate || existsSync(outputTemplate),
+      message: 'The --output-template file must exist',
+      fail: 'not found',
+    },
+    {
+      nook: true,
+      test: !perWorkspace || (!format && !outputTemplate),
+      message:
+        'Per-workspace reports cannot be combined with --format or --output-template',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !showEvidence || (!format && !outputTemplate && !perWorkspace),
+      message:
+        'The --show-evidence flag cannot be combined with --format, --output-template or per-workspace reports',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: hasApiToken || offline,
       message: 'This command requires a Socket API token for access',
       fail: 'try `socket login`',
     },
@@ -198,21 +405,52 @@ export async function run(
       organization: orgSlug,
       scanId,
       fold,
+      ...(format ? { format } : {}),
+      ...(format === REPORT_FORMAT_CSV
+        ? { columns: columns.join(', '), csvHeader }
+        : {}),
+      ...(outputTemplate ? { outputTemplate } : {}),
       reportLevel,
+      ...(failOn ? { failOn } : {}),
+      ...(baselineFlag ? { baseline: baselineFlag } : {}),
       includeLicense: includeLicensePolicy,
       short,
+      ...(showEvidence ? { showEvidence } : {}),
+      ...(vexPaths.length ? { vex: vexPaths.join(', ') } : {}),
+      ...getScanAlertFilterDetails(alertFilter),
+      ...(perWorkspace ? { perWorkspace } : {}),
+      ...(changedSince ? { changedSince } : {}),
+      ...(workspaceFilter.length
+        ? { workspaceFilter: workspaceFilter.join(', ') }
+        : {}),
+      ...(offline ? { offline } : {}),
     })
     return
   }
 
   await handleScanReport({
+    alertFilter,
+    baselinePath: baselineFlag
+      ? path.resolve(process.cwd(), baselineFlag)
+      : undefined,
+    columns: columns as ScanReportCsvColumn[],
+    csvHeader,
+    failOn: (failOn || undefined) as FAIL_ON | undefined,
     orgSlug,
     scanId,
     includeLicensePolicy,
+    offline,
     outputKind,
     filepath,
     fold,
+    format: (format || undefined) as REPORT_FORMAT | undefined,
+    outputTemplate: outputTemplate
+      ? path.resolve(process.cwd(), outputTemplate)
+      : undefined,
     short,
+    showEvidence,
     reportLevel,
+    vexPaths: vexPaths.map(p => path.resolve(process.cwd(), p)),
+    workspaces: perWorkspace ? { changedSince, workspaceFilter } : undefined,
   })
 }
@@ -1,4 +1,14 @@
-import { handleScanView } from './handle-scan-view.mts'
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { alertFilterFlags } from './alert-filter-flags.mts'
+import {
+  getScanAlertFilter,
+  getScanAlertFilterDetails,
+} from './filter-scan-alerts.mts'
+import {
+  handleScanView,
+  handleScanViewInteractive,
+} from './handle-scan-view.mts'
 import { streamScan } from './stream-scan.mts'
 import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
 import { outputDryRunFetch } from '../../util/dry-run/output.mts'
@@ -10,10 +20,12 @@ import {
   getFlagListOutput,
 } from '../../util/output/formatting.mts'
 import { getOutputKind } from '../../util/output/mode.mjs'
+import { POLICY_SEVERITIES } from '../../util/policy/socket-policy.mts'
 import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
 import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
 import { checkCommandInput } from '../../util/validation/check-input.mts'
 
+import type { ScanViewTriageTarget } from './handle-scan-view.mts'
 import type {
   CliCommandContext,
   CliSubcommand,
@@ -44,11 +56,12 @@ export async function run(
     flags: defineFlags({
       ...commonFlags,
       ...outputFlags,
+      ...alertFilterFlags,
       interactive: {
         type: 'boolean',
         default: true,
         description:
-          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no. Passed explicitly, opens a terminal UI to triage the alerts.',
       },
       org: {
         type: 'string',
@@ -61,6 +74,12 @@ export async function run(
         description:
           'Only valid with --json. Streams the response as "ndjson" (chunks of valid json blobs).',
       },
+      triageTarget: {
+        type: 'string',
+        default: 'local',
+        description:
+          'Where --interactive saves ignored and accepted alerts: "local" (socket.policy.yml exceptions) or "org" (the org triage settings)',
+      },
     }),
     help: (command: string, helpConfig: { flags: MeowFlags }) => `
     Usage
@@ -71,12 +90,23 @@ export async function run(
 
     When no output path is given the contents is sent to stdout.
 
+    With --interactive in a terminal the alerts are listed grouped by package
+    or severity. Expand one to see the dependency path that brings it in, and
+    press "i" to ignore or "a" to accept it with a reason. Decisions are added
+    as exceptions to the nearest socket.policy.yml, or saved to the org
+    triage settings with --triage-target=org.
+
+    Use --severity, --alert-type, --ecosystem and --direct-only to only show
+    some of the packages and alerts, e.g. --severity=high --ecosystem=npm.
+
     Options
       ${getFlagListOutput(helpConfig.flags)}
 
     Examples
       $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0
       $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 ./stream.txt
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --interactive
+      $ ${command} 000aaaa1-0000-0a0a-00a0-00a0000000a0 --severity=critical --direct-only
   `,
   }
 
@@ -87,14 +117,22 @@ export async function run(
     parentName,
   })
 
-  const { json, markdown, org: orgFlag, stream } = cli.flags
+  const { json, markdown, org: orgFlag, stream, triageTarget } = cli.flags
 
   const dryRun = cli.flags['dryRun']
 
   const interactive = cli.flags['interactive']
 
+  // --interactive defaults to true for prompts, so only passing it explicitly
+  // opens the terminal UI.
+  const tui = argv.includes('--interactive')
+
   const [scanId = '', file = ''] = cli.input
 
+  const alertFilter = getScanAlertFilter(cli.flags)
+
+  const severity = alertFilter?.severity ?? ''
+
   const hasApiToken = hasDefaultApiToken()
 
   const { 0: orgSlug } = await determineOrgSlug(
@@ -136,6 +174,41 @@ export async function run(
       message: 'You can only use --stream when using --json',
       fail: 'Either remove --stream or add --json',
     },
+    {
+      nook: true,
+      test: !stream || !alertFilter,
+      message:
+        'The --stream flag cannot be combined with --severity, --alert-type, --ecosystem or --direct-only',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test:
+        !severity ||
+        (POLICY_SEVERITIES as readonly string[]).includes(severity),
+      message: `The --severity flag must be ${joinOr(POLICY_SEVERITIES.map(s => `'${s}'`))}`,
+      fail: `got ${severity}`,
+    },
+    {
+      nook: true,
+      test: !tui || (!json && !markdown && !file),
+      message:
+        'The terminal UI of --interactive can not be combined with --json, --markdown or an output file',
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test:
+        !tui || dryRun || (!!process.stdin.isTTY && !!process.stdout.isTTY),
+      message: 'The terminal UI of --interactive needs a terminal',
+      fail: 'stdin or stdout is not a TTY',
+    },
+    {
+      nook: true,
+      test: triageTarget === 'local' || triageTarget === 'org',
+      message: 'The --triage-target flag must be "local" or "org"',
+      fail: `received "${triageTarget}"`,
+    },
   )
   if (!wasValidInput) {
     return
@@ -146,16 +219,24 @@ export async function run(
       organization: orgSlug,
       scanId,
       stream: stream || undefined,
+      ...getScanAlertFilterDetails(alertFilter),
     })
     return
   }
 
-  if (json && stream) {
+  if (tui) {
+    await handleScanViewInteractive(
+      orgSlug,
+      scanId,
+      triageTarget as ScanViewTriageTarget,
+      alertFilter,
+    )
+  } else if (json && stream) {
     await streamScan(orgSlug, scanId, {
       commandPath: 'socket scan view',
       file,
     })
   } else {
-    await handleScanView(orgSlug, scanId, file, outputKind)
+    await handleScanView(orgSlug, scanId, file, outputKind, alertFilter)
   }
 }
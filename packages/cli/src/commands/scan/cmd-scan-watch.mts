@@ -0,0 +1,196 @@
+import path from 'node:path'
+
+import { applyScanCreateDefaults } from './cmd-scan-create-defaults.mts'
+import { handleScanWatch } from './handle-scan-watch.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { determineOrgSlug } from '../../util/socket/org-slug.mjs'
+import { readOrDefaultSocketJsonUp } from '../../util/socket/json.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'watch'
+
+const description =
+  'Rescan manifests on change and show new alerts as they appear'
+
+const hidden = false
+
+export const cmdScanWatch = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      debounce: {
+        type: 'number',
+        default: 1000,
+        description:
+          'Milliseconds to wait after the last file change before rescanning',
+      },
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] [CWD=.]
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Watches the package manifests and lockfiles under the target directory.
+    Each change triggers a temporary scan, which does not show up in the
+    dashboard, and prints the alerts it added or resolved compared to the
+    previous scan. Alerts with an "error" action in the organization security
+    policy are marked as blocking. Changes that leave every manifest as it
+    was do not trigger a scan.
+
+    With --json every scan prints one line of JSON.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command}
+      $ ${command} ./proj --debounce 3000
+      $ ${command} --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const {
+    debounce,
+    dryRun,
+    json,
+    markdown,
+    org: orgFlag,
+  } = cli.flags as {
+    debounce: number
+    dryRun: boolean
+    json: boolean
+    markdown: boolean
+    org: string
+  }
+
+  const interactive = !!cli.flags['interactive']
+
+  const processCwd = process.cwd()
+  const [cwdInput = '.'] = cli.input
+  const cwd = path.resolve(processCwd, cwdInput)
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(
+    orgFlag || '',
+    interactive,
+    dryRun,
+  )
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: cli.input.length <= 1,
+      message: 'Specify at most one directory to watch',
+      fail: 'received too many arguments',
+    },
+    {
+      nook: true,
+      test: Number.isFinite(debounce) && debounce >= 0,
+      message: 'The --debounce flag must be a number of milliseconds',
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scan alerts on manifest changes', {
+      organization: orgSlug,
+      directory: cwd,
+      debounce: `${debounce}ms`,
+    })
+    return
+  }
+
+  const sockJson = await readOrDefaultSocketJsonUp(cwd)
+  const { branchName, repoName } = await applyScanCreateDefaults(
+    cwd,
+    sockJson,
+    {
+      autoManifest: false,
+      branchName: '',
+      repoName: '',
+      report: false,
+      workspace: '',
+    },
+  )
+
+  await handleScanWatch({
+    branchName,
+    cwd,
+    debounceMs: debounce,
+    orgSlug,
+    outputKind,
+    repoName,
+  })
+}
@@ -1,3 +1,7 @@
+import { cmdScanAttest } from './cmd-scan-attest.mts'
+import { cmdScanAwait } from './cmd-scan-await.mts'
+import { cmdScanBaseline } from './cmd-scan-baseline.mts'
+import { cmdScanCheck } from './cmd-scan-check.mts'
 import { cmdScanCreate } from './cmd-scan-create.mts'
 import { cmdScanDel } from './cmd-scan-del.mts'
 import { cmdScanDiff } from './cmd-scan-diff.mts'
@@ -8,12 +12,17 @@ import { cmdScanReach } from './cmd-scan-reach.mts'
 import { cmdScanReport } from './cmd-scan-report.mts'
 import { cmdScanSetup } from './cmd-scan-setup.mts'
 import { cmdScanView } from './cmd-scan-view.mts'
+import { cmdScanWatch } from './cmd-scan-watch.mts'
 import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
 
 export const cmdScan = defineSubcommandGroup({
   name: 'scan',
   description: 'Manage Socket scans',
   subcommands: {
+    attest: cmdScanAttest,
+    await: cmdScanAwait,
+    baseline: cmdScanBaseline,
+    check: cmdScanCheck,
     create: cmdScanCreate,
     del: cmdScanDel,
     diff: cmdScanDiff,
@@ -24,6 +33,7 @@ export const cmdScan = defineSubcommandGroup({
     report: cmdScanReport,
     setup: cmdScanSetup,
     view: cmdScanView,
+    watch: cmdScanWatch,
   },
   aliases: {
     meta: {
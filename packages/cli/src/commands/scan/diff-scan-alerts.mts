@@ -0,0 +1,108 @@
+/**
+ * Alert-level comparison of two full scans for `socket scan diff`.
+ *
+ * Alerts are matched by package identity (ecosystem, namespace and name) and
+ * alert type, ignoring the version. Bumping `lodash` from one vulnerable
+ * version to another therefore does not report the same alert as new, while a
+ * fresh alert type on an existing package does. An alert is "blocking" when
+ * it is new and the org security policy action for its type is `error`, which
+ * is what `socket scan diff` uses to decide its exit code.
+ */
+
+import { UNKNOWN_VALUE } from '@socketsecurity/lib-stable/constants/sentinels'
+
+import { REPORT_LEVEL_ERROR } from '../../constants/reporting.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+
+import type { REPORT_LEVEL } from './types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+export type ScanDiffAlert = {
+  action: REPORT_LEVEL | ''
+  file?: string | undefined
+  purl: string
+  severity: string
+  type: string
+}
+
+export type ScanAlertDiff = {
+  added: ScanDiffAlert[]
+  blocking: ScanDiffAlert[]
+  removed: ScanDiffAlert[]
+}
+
+export function getAlertIdentity(
+  artifact: SocketArtifact,
+  alertType: string,
+): string {
+  return `${artifact.type}:${artifact.namespace ?? ''}/${artifact.name ?? ''}:${alertType}`
+}
+
+function collectAlerts(
+  scan: SocketArtifact[],
+  securityRules: Record<string, { action?: string | undefined } | undefined>,
+): Map<string, ScanDiffAlert[]> {
+  const alertsByIdentity = new Map<string, ScanDiffAlert[]>()
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    const alerts = artifact.alerts ?? []
+    if (!alerts.length) {
+      continue
+    }
+    const purl = getArtifactPurlString(artifact)
+    for (let j = 0, { length: alertCount } = alerts; j < alertCount; j += 1) {
+      const alert = alerts[j]!
+      const identity = getAlertIdentity(artifact, alert.type)
+      let entries = alertsByIdentity.get(identity)
+      if (!entries) {
+        entries = []
+        alertsByIdentity.set(identity, entries)
+      }
+      entries.push({
+        action: (securityRules[alert.type]?.action ?? '') as REPORT_LEVEL | '',
+        ...(alert.file ? { file: alert.file } : {}),
+        purl,
+        severity: alert.severity ?? UNKNOWN_VALUE,
+        type: alert.type,
+      })
+    }
+  }
+  return alertsByIdentity
+}
+
+/**
+ * Compare the alerts of the "before" and "after" scans against the org
+ * security policy.
+ */
+export function getScanAlertDiff(
+  before: SocketArtifact[],
+  after: SocketArtifact[],
+  securityPolicy: SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data'],
+): ScanAlertDiff {
+  const securityRules = (securityPolicy.securityPolicyRules ?? {}) as Record<
+    string,
+    { action?: string | undefined } | undefined
+  >
+  const beforeAlerts = collectAlerts(before, securityRules)
+  const afterAlerts = collectAlerts(after, securityRules)
+
+  const added: ScanDiffAlert[] = []
+  for (const [identity, entries] of afterAlerts) {
+    if (!beforeAlerts.has(identity)) {
+      added.push(...entries)
+    }
+  }
+  const removed: ScanDiffAlert[] = []
+  for (const [identity, entries] of beforeAlerts) {
+    if (!afterAlerts.has(identity)) {
+      removed.push(...entries)
+    }
+  }
+
+  return {
+    added,
+    blocking: added.filter(alert => alert.action === REPORT_LEVEL_ERROR),
+    removed,
+  }
+}
@@ -0,0 +1,246 @@
+/**
+ * Repository discovery for `socket scan create --all-repos`.
+ *
+ * Repositories come from a GitHub organization, a GitLab group (with its
+ * subgroups) or a local directory whose subdirectories are git clones.
+ * Archived and empty remote repositories are skipped. Remote repositories
+ * are shallow-cloned at their default branch before they are scanned; the
+ * token goes to git through GIT_CONFIG_* variables so it never shows up in
+ * the process list or in the clone's config.
+ */
+
+import { existsSync, promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { Gitlab } from '@gitbeaker/rest'
+
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import { SOCKET_CLI_GITHUB_TOKEN } from '../../env/socket-cli-github-token.mts'
+import { formatErrorWithDetail } from '../../util/error/errors.mts'
+import { getOctokit } from '../../util/git/github.mts'
+import { withGitHubRetry } from '../../util/git/github-errors.mts'
+import {
+  getGitLabHost,
+  getGitLabToken,
+} from '../../util/git/gitlab-provider.mts'
+import {
+  getGitPath,
+  getRepoInfo,
+  gitBranch,
+  gitResolveCommit,
+} from '../../util/git/operations.mts'
+import { extractName } from '../../util/sanitize-names.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type RepoSource =
+  | { kind: 'github'; org: string }
+  | { kind: 'gitlab'; group: string }
+  | { kind: 'local'; dir: string }
+
+export type DiscoveredRepo = {
+  // Repository name the scan is associated with at Socket.
+  name: string
+  branch: string
+  // Set for remote repositories, which are cloned before the scan.
+  cloneUrl?: string | undefined
+  // Set for local clones.
+  dir?: string | undefined
+  // Whether the branch is the repository's default branch.
+  isDefaultBranch: boolean
+}
+
+export function describeRepoSource(source: RepoSource): string {
+  if (source.kind === 'github') {
+    return `the GitHub organization ${source.org}`
+  }
+  if (source.kind === 'gitlab') {
+    return `the GitLab group ${source.group}`
+  }
+  return `the clones in ${source.dir}`
+}
+
+async function discoverGithubRepos(
+  org: string,
+): Promise<CResult<DiscoveredRepo[]>> {
+  const octokit = getOctokit()
+  const listCResult = await withGitHubRetry(
+    () =>
+      octokit.paginate(octokit.repos.listForOrg, {
+        org,
+        per_page: 100,
+        type: 'all',
+      }),
+    `listing the repositories of ${org}`,
+  )
+  if (!listCResult.ok) {
+    return listCResult
+  }
+  const repos: DiscoveredRepo[] = []
+  for (const repo of listCResult.data) {
+    // An empty repository reports a size of 0 and has nothing to clone.
+    if (repo.archived || !repo.size || !repo.clone_url) {
+      debug(`Skipping archived or empty repository ${repo.full_name}`)
+      continue
+    }
+    repos.push({
+      name: extractName(repo.name),
+      branch: repo.default_branch || '',
+      cloneUrl: repo.clone_url,
+      isDefaultBranch: true,
+    })
+  }
+  return { ok: true, data: repos }
+}
+
+async function discoverGitlabRepos(
+  group: string,
+): Promise<CResult<DiscoveredRepo[]>> {
+  try {
+    const gitlab = new Gitlab({
+      host: getGitLabHost(),
+      token: getGitLabToken(),
+    })
+    const projects = await gitlab.Groups.allProjects(group, {
+      archived: false,
+      includeSubgroups: true,
+    })
+    const repos: DiscoveredRepo[] = []
+    for (const project of projects) {
+      if (project.empty_repo || !project.http_url_to_repo) {
+        debug(`Skipping empty repository ${project.path_with_namespace}`)
+        continue
+      }
+      repos.push({
+        name: extractName(project.path),
+        branch: project.default_branch || '',
+        cloneUrl: project.http_url_to_repo,
+        isDefaultBranch: true,
+      })
+    }
+    return { ok: true, data: repos }
+  } catch (e) {
+    debugDir({ message: 'Listing the GitLab group projects failed', error: e })
+    return {
+      ok: false,
+      message: 'Failed to list the GitLab group projects',
+      cause: formatErrorWithDetail(`Listing the projects of ${group}`, e),
+    }
+  }
+}
+
+async function discoverLocalRepos(
+  dir: string,
+): Promise<CResult<DiscoveredRepo[]>> {
+  let entries: Array<{ isDirectory(): boolean; name: string }>
+  try {
+    entries = await fs.readdir(dir, { withFileTypes: true })
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Failed to read the directory of clones',
+      cause: formatErrorWithDetail(`Reading ${dir}`, e),
+    }
+  }
+  const repos: DiscoveredRepo[] = []
+  const names = entries
+    .filter(entry => entry.isDirectory())
+    .map(entry => entry.name)
+    .sort()
+  for (const name of names) {
+    const repoDir = path.join(dir, name)
+    // `.git` is a file in worktrees and submodule checkouts.
+    if (!existsSync(path.join(repoDir, '.git'))) {
+      continue
+    }
+    const repoInfo = await getRepoInfo(repoDir)
+    repos.push({
+      name: extractName(repoInfo?.repo || name),
+      branch: (await gitBranch(repoDir)) || '',
+      dir: repoDir,
+      isDefaultBranch: false,
+    })
+  }
+  return { ok: true, data: repos }
+}
+
+export async function discoverRepos(
+  source: RepoSource,
+): Promise<CResult<DiscoveredRepo[]>> {
+  if (source.kind === 'github') {
+    return await discoverGithubRepos(source.org)
+  }
+  if (source.kind === 'gitlab') {
+    return await discoverGitlabRepos(source.group)
+  }
+  return await discoverLocalRepos(source.dir)
+}
+
+function getCloneAuthHeader(source: RepoSource): string | undefined {
+  let credentials: string | undefined
+  if (source.kind === 'github') {
+    credentials = SOCKET_CLI_GITHUB_TOKEN
+      ? `x-access-token:${SOCKET_CLI_GITHUB_TOKEN}`
+      : undefined
+  } else if (source.kind === 'gitlab') {
+    credentials = `oauth2:${getGitLabToken()}`
+  }
+  return credentials
+    ? `Authorization: Basic ${Buffer.from(credentials).toString('base64')}`
+    : undefined
+}
+
+/**
+ * Shallow-clone a remote repository at its branch into `dir`, and return
+ * the commit that was cloned.
+ */
+export async function cloneDiscoveredRepo(
+  repo: DiscoveredRepo,
+  source: RepoSource,
+  dir: string,
+): Promise<CResult<{ commitHash: string }>> {
+  const authHeader = getCloneAuthHeader(source)
+  try {
+    const gitBin = await getGitPath()
+    await spawn(
+      gitBin,
+      [
+        'clone',
+        '--depth',
+        '1',
+        '--quiet',
+        '--single-branch',
+        ...(repo.branch ? ['--branch', repo.branch] : []),
+        repo.cloneUrl!,
+        dir,
+      ],
+      {
+        env: {
+          ...process.env,
+          // Fail instead of prompting for credentials.
+          GIT_TERMINAL_PROMPT: '0',
+          ...(authHeader
+            ? {
+                GIT_CONFIG_COUNT: '1',
+                GIT_CONFIG_KEY_0: 'http.extraHeader',
+                GIT_CONFIG_VALUE_0: authHeader,
+              }
+            : {}),
+        },
+      },
+    )
+  } catch (e) {
+    debugDir({ message: `git clone of ${repo.cloneUrl} failed`, error: e })
+    return {
+      ok: false,
+      message: 'Failed to clone the repository',
+      cause: `git clone of ${repo.cloneUrl} failed`,
+    }
+  }
+  return {
+    ok: true,
+    data: { commitHash: (await gitResolveCommit('HEAD', dir)) || '' },
+  }
+}
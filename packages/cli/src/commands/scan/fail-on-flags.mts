@@ -0,0 +1,11 @@
+import { FAIL_ON_LEVELS } from '../../constants/reporting.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+
+export const failOnFlags: MeowFlags = {
+  failOn: {
+    type: 'string',
+    default: '',
+    description: `Fail on alerts of at least this severity instead of on the policy error level: ${FAIL_ON_LEVELS.map(f => `'${f}'`).join(', ')}. 'any' fails on every alert the policy does not ignore, 'none' never fails`,
+  },
+}
@@ -21,18 +21,22 @@ export type FetchCreateOrgFullScanOptions = {
   commandPath?: string | undefined
   cwd?: string | undefined
   defaultBranch?: boolean | undefined
+  // Lets the Socket API recognize a retried upload of the same scan.
+  idempotencyKey?: string | undefined
   pendingHead?: boolean | undefined
   sdkOpts?: SetupSdkOptions | undefined
   spinner?: SpinnerInstance | undefined
   tmp?: boolean | undefined
 }
 
-export async function fetchCreateOrgFullScan(
-  packagePaths: string[],
-  orgSlug: string,
+/**
+ * The query parameters of the full scan upload, as sent to
+ * `POST orgs/{org}/full-scans`.
+ */
+export function getCreateOrgFullScanQuery(
   config: FetchCreateOrgFullScanConfigs,
   options?: FetchCreateOrgFullScanOptions | undefined,
-): Promise<CResult<SocketSdkSuccessResult<'CreateOrgFullScan'>['data']>> {
+): Record<string, string | boolean> {
   const {
     branchName,
     commitHash,
@@ -44,14 +48,40 @@ export async function fetchCreateOrgFullScan(
     workspace,
   } = { __proto__: null, ...config } as FetchCreateOrgFullScanConfigs
 
+  const { defaultBranch, idempotencyKey, pendingHead, tmp } = {
+    __proto__: null,
+    ...options,
+  } as FetchCreateOrgFullScanOptions
+
+  return {
+    ...(branchName ? { branch: branchName } : {}),
+    ...(commitHash ? { commit_hash: commitHash } : {}),
+    ...(commitMessage ? { commit_message: commitMessage } : {}),
+    ...(committers ? { committers } : {}),
+    ...(defaultBranch !== undefined
+      ? { make_default_branch: defaultBranch }
+      : {}),
+    ...(idempotencyKey ? { idempotency_key: idempotencyKey } : {}),
+    ...(pullRequest ? { pull_request: String(pullRequest) } : {}),
+    ...(repoName ? { repo: repoName } : {}),
+    ...(scanType ? { scan_type: scanType } : {}),
+    ...(workspace ? { workspace } : {}),
+    ...(pendingHead !== undefined ? { set_as_pending_head: pendingHead } : {}),
+    ...(tmp !== undefined ? { tmp: tmp } : {}),
+  }
+}
+
+export async function fetchCreateOrgFullScan(
+  packagePaths: string[],
+  orgSlug: string,
+  config: FetchCreateOrgFullScanConfigs,
+  options?: FetchCreateOrgFullScanOptions | undefined,
+): Promise<CResult<SocketSdkSuccessResult<'CreateOrgFullScan'>['data']>> {
   const {
     commandPath,
     cwd = process.cwd(),
-    defaultBranch,
-    pendingHead,
     sdkOpts,
     spinner,
-    tmp,
   } = { __proto__: null, ...options } as FetchCreateOrgFullScanOptions
 
   const sockSdkCResult = await setupSdk(sdkOpts)
@@ -63,21 +93,7 @@ export async function fetchCreateOrgFullScan(
   return await handleApiCall<'createFullScan'>(
     sockSdk.createFullScan(orgSlug, packagePaths, {
       pathsRelativeTo: cwd,
-      ...(branchName ? { branch: branchName } : {}),
-      ...(commitHash ? { commit_hash: commitHash } : {}),
-      ...(commitMessage ? { commit_message: commitMessage } : {}),
-      ...(committers ? { committers } : {}),
-      ...(defaultBranch !== undefined
-        ? { make_default_branch: defaultBranch }
-        : {}),
-      ...(pullRequest ? { pull_request: String(pullRequest) } : {}),
-      ...(repoName ? { repo: repoName } : {}),
-      ...(scanType ? { scan_type: scanType } : {}),
-      ...(workspace ? { workspace } : {}),
-      ...(pendingHead !== undefined
-        ? { set_as_pending_head: pendingHead }
-        : {}),
-      ...(tmp !== undefined ? { tmp: tmp } : {}),
+      ...getCreateOrgFullScanQuery(config, options),
       // eslint-disable-next-line typescript-eslint/no-explicit-any -- SDK option shape varies by spread; downstream validates against canonical API contract.
     } as any),
     {
@@ -1,10 +1,24 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { naturalCompare } from '@socketsecurity/lib-stable/sorts/natural'
+
 import { handleApiCall } from '../../util/socket/api.mjs'
 import { setupSdk } from '../../util/socket/sdk.mjs'
 
+import type { ScanListItem } from './output-list-scans.mts'
 import type { CResult } from '../../types.mts'
 import type { SetupSdkOptions } from '../../util/socket/sdk.mjs'
 import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
 
+const logger = getDefaultLogger()
+
+// Sorted by the list endpoint.
+export const SCAN_LIST_API_SORTS = ['created_at', 'name'] as const
+
+// Sorted after fetching, so only within the fetched pages.
+export const SCAN_LIST_LOCAL_SORTS = ['branch', 'repo', 'status'] as const
+
+export type ScanListLocalSort = (typeof SCAN_LIST_LOCAL_SORTS)[number]
+
 export type FetchOrgFullScanListConfig = {
   branch: string
   direction: string
@@ -57,3 +71,113 @@ export async function fetchOrgFullScanList(
     },
   )
 }
+
+export type ScanListFilters = {
+  // Fetch every page instead of only config.page.
+  all?: boolean | undefined
+  // Case-insensitive, matched against the committers of each scan.
+  committer?: string | undefined
+  // Stop after this many matching scans, fetching as many pages as needed.
+  // Defaults to a page of them with committer or status.
+  limit?: number | undefined
+  // Matched against the scan_state of each scan.
+  status?: string | undefined
+}
+
+export function getScanListStatus(scan: ScanListItem): string {
+  return (scan as { scan_state?: string | null | undefined }).scan_state ?? ''
+}
+
+export function matchesScanListFilters(
+  scan: ScanListItem,
+  filters: ScanListFilters,
+): boolean {
+  const { committer, status } = filters
+  if (status && getScanListStatus(scan) !== status) {
+    return false
+  }
+  if (committer) {
+    const lowered = committer.toLowerCase()
+    return (scan.committers ?? []).some(c => c.toLowerCase() === lowered)
+  }
+  return true
+}
+
+/**
+ * Fetch scans from config.page on, keeping the ones that match the committer
+ * and status filters, which the list endpoint does not support. Pages are
+ * fetched until `limit` scans match, a page of them when filtering, or with
+ * `all` until the last page. Without any of these one page is fetched.
+ *
+ * When `limit` leaves out matches of the last page fetched, nextPage is that
+ * page, so that none are skipped. Fetching it lists the others again.
+ */
+export async function fetchOrgFullScanPages(
+  config: FetchOrgFullScanListConfig,
+  filters: ScanListFilters,
+  options?: FetchOrgFullScanListOptions | undefined,
+): Promise<CResult<SocketSdkSuccessResult<'listFullScans'>['data']>> {
+  const { all, committer, status } = filters
+  // Filtering a single page would leave it short of matches that later pages
+  // have.
+  const limit =
+    filters.limit || (!all && (committer || status) ? config.perPage : 0)
+  const rows: ScanListItem[] = []
+  // The page each of the rows is on.
+  const rowPages: number[] = []
+  const fetchedPages = new Set<number>()
+  let lastData: SocketSdkSuccessResult<'listFullScans'>['data'] | undefined
+  let page = config.page
+  while (true) {
+    fetchedPages.add(page)
+    const pageCResult = await fetchOrgFullScanList({ ...config, page }, options)
+    if (!pageCResult.ok) {
+      return pageCResult
+    }
+    lastData = pageCResult.data
+    for (const scan of lastData.results) {
+      if (matchesScanListFilters(scan, filters)) {
+        rows.push(scan)
+        rowPages.push(page)
+      }
+    }
+    const nextPage = Number(lastData.nextPage ?? -1)
+    if ((limit && rows.length >= limit) || !(all || limit) || nextPage < 0) {
+      break
+    }
+    if (fetchedPages.has(nextPage)) {
+      logger.warn(
+        `The scan list points back to page ${nextPage} after page ${page}, listing the scans fetched so far`,
+      )
+      break
+    }
+    page = nextPage
+  }
+  if (limit && rows.length > limit) {
+    return {
+      ok: true,
+      data: {
+        ...lastData!,
+        nextPage: rowPages[limit]!,
+        results: rows.slice(0, limit),
+      },
+    }
+  }
+  return { ok: true, data: { ...lastData!, results: rows } }
+}
+
+/**
+ * Sort scans by a field the list endpoint cannot sort by.
+ */
+export function sortScanList(
+  scans: ScanListItem[],
+  sort: ScanListLocalSort,
+  direction: string,
+): ScanListItem[] {
+  const sign = direction === 'asc' ? 1 : -1
+  const getValue = (scan: ScanListItem) =>
+    sort === 'status' ? getScanListStatus(scan) : String(scan[sort] ?? '')
+  return scans.toSorted(
+    (a, b) => sign * naturalCompare(getValue(a), getValue(b)),
+  )
+}
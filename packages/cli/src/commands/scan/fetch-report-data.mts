@@ -3,6 +3,7 @@ import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
 
 import { formatErrorWithDetail } from '../../util/error/errors.mjs'
+import { readOfflineScanData } from '../../util/offline/store.mts'
 import {
   handleApiCallNoSpinner,
   queryApiSafeText,
@@ -18,6 +19,8 @@ const logger = getDefaultLogger()
 
 export type FetchScanData = {
   includeLicensePolicy?: boolean | undefined
+  // Read the scan and policy of an imported offline bundle instead.
+  offline?: boolean | undefined
   sdkOpts?: SetupSdkOptions | undefined
 }
 
@@ -35,10 +38,13 @@ export async function fetchScanData(
     securityPolicy: SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
   }>
 > {
-  const { includeLicensePolicy, sdkOpts } = {
+  const { includeLicensePolicy, offline, sdkOpts } = {
     __proto__: null,
     ...options,
   } as FetchScanData
+  if (offline) {
+    return await readOfflineScanData(orgSlug, scanId)
+  }
   const spinner = getDefaultSpinner()
   const sockSdkCResult = await setupSdk(sdkOpts)
   if (!sockSdkCResult.ok) {
@@ -0,0 +1,50 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { getScanAlertDiff } from './diff-scan-alerts.mts'
+import { fetchScan } from './fetch-scan.mts'
+import { filterScanArtifacts } from './filter-scan-alerts.mts'
+import { fetchSecurityPolicy } from '../organization/fetch-security-policy.mts'
+
+import type { ScanAlertDiff } from './diff-scan-alerts.mts'
+import type { ScanAlertFilter } from './filter-scan-alerts.mts'
+import type { CResult } from '../../types.mts'
+const logger = getDefaultLogger()
+
+export async function fetchScanAlertDiff({
+  alertFilter,
+  id1,
+  id2,
+  orgSlug,
+}: {
+  // Only compare the alerts the filter includes.
+  alertFilter?: ScanAlertFilter | undefined
+  id1: string
+  id2: string
+  orgSlug: string
+}): Promise<CResult<ScanAlertDiff>> {
+  logger.info('Comparing alerts of both scans against the security policy')
+
+  const [before, after, policy] = await Promise.all([
+    fetchScan(orgSlug, id1),
+    fetchScan(orgSlug, id2),
+    fetchSecurityPolicy(orgSlug, { commandPath: 'socket scan diff' }),
+  ])
+  if (!before.ok) {
+    return before
+  }
+  if (!after.ok) {
+    return after
+  }
+  if (!policy.ok) {
+    return policy
+  }
+
+  return {
+    ok: true,
+    data: getScanAlertDiff(
+      filterScanArtifacts(before.data, alertFilter),
+      filterScanArtifacts(after.data, alertFilter),
+      policy.data,
+    ),
+  }
+}
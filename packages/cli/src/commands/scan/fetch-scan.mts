@@ -1,43 +1,72 @@
 import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
 import { sleep } from '@socketsecurity/lib-stable/promises/timers'
 
+import { recordScanHistory } from '../../util/history/store.mts'
+import { formatDuration, startProgress } from '../../util/output/progress.mts'
 import { queryApiSafeTextWithStatus } from '../../util/socket/api.mjs'
 
 import type { CResult } from '../../types.mts'
 import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { Progress } from '../../util/output/progress.mts'
 
 export const CACHED_POLL_INITIAL_DELAY_MS = 1000
 export const CACHED_POLL_MAX_DELAY_MS = 10_000
 export const CACHED_POLL_TIMEOUT_MS = 10 * 60 * 1000
 
+export type FetchScanOptions = {
+  // How long to wait for the results to be computed.
+  timeoutMs?: number | undefined
+}
+
 export async function fetchScan(
   orgSlug: string,
   scanId: string,
+  options?: FetchScanOptions | undefined,
 ): Promise<CResult<SocketArtifact[]>> {
+  const { timeoutMs = CACHED_POLL_TIMEOUT_MS } = {
+    __proto__: null,
+    ...options,
+  } as FetchScanOptions
   // Serve pre-computed results from the immutable store (`?cached=true`):
   // a 200 carries the ndjson body, a 202 means the server enqueued a
   // background job to compute them — poll with backoff until the results
   // are ready, so callers only ever observe the final scan.
   const path = `orgs/${orgSlug}/full-scans/${encodeURIComponent(scanId)}?cached=true`
-  const deadline = Date.now() + CACHED_POLL_TIMEOUT_MS
+  const deadline = Date.now() + timeoutMs
   let delayMs = CACHED_POLL_INITIAL_DELAY_MS
-  for (;;) {
-    const result = await queryApiSafeTextWithStatus(path, 'a scan')
-    if (!result.ok) {
-      return result
-    }
-    if (result.data.status !== 202) {
-      return parseArtifactsNdjson(result.data.text)
-    }
-    if (Date.now() >= deadline) {
-      return {
-        ok: false,
-        message: 'Scan results not ready',
-        cause: `The Socket API is still computing cached results for scan ${scanId} after ${CACHED_POLL_TIMEOUT_MS / 60_000} minutes (path: ${path}). Retry in a few minutes — the server keeps computing in the background.`,
+  // Shown once the server says the results are still being computed.
+  let progress: Progress | undefined
+  try {
+    for (;;) {
+      const result = await queryApiSafeTextWithStatus(path, 'a scan')
+      if (!result.ok) {
+        return result
+      }
+      if (result.data.status !== 202) {
+        const artifactsCResult = parseArtifactsNdjson(result.data.text)
+        if (artifactsCResult.ok) {
+          // Keep the results for `socket history`.
+          await recordScanHistory({
+            artifacts: artifactsCResult.data,
+            orgSlug,
+            scanId,
+          })
+        }
+        return artifactsCResult
+      }
+      if (Date.now() >= deadline) {
+        return {
+          ok: false,
+          message: 'Scan results not ready',
+          cause: `The Socket API is still computing cached results for scan ${scanId} after ${formatDuration(timeoutMs)} (path: ${path}). Retry in a few minutes — the server keeps computing in the background.`,
+        }
       }
+      progress ??= startProgress(`Waiting for the analysis of scan ${scanId}`)
+      await sleep(delayMs)
+      delayMs = Math.min(delayMs * 2, CACHED_POLL_MAX_DELAY_MS)
     }
-    await sleep(delayMs)
-    delayMs = Math.min(delayMs * 2, CACHED_POLL_MAX_DELAY_MS)
+  } finally {
+    progress?.stop()
   }
 }
 
@@ -0,0 +1,35 @@
+import { sendApiRequest } from '../../util/socket/api.mjs'
+
+import type { CResult } from '../../types.mts'
+
+export type AlertTriageState =
+  | 'block'
+  | 'ignore'
+  | 'inherit'
+  | 'monitor'
+  | 'warn'
+
+export type AlertTriageEntry = {
+  alertKey: string
+  note?: string | undefined
+  state: AlertTriageState
+}
+
+/**
+ * Set the org triage state of alerts, as the dashboard's triage view does.
+ * The state applies to the alert key in every scan of the org.
+ */
+export async function fetchUpdateAlertTriage(
+  orgSlug: string,
+  entries: AlertTriageEntry[],
+): Promise<CResult<unknown>> {
+  // Not wrapped by the SDK yet, so call the endpoint directly.
+  return await sendApiRequest(
+    `orgs/${encodeURIComponent(orgSlug)}/triage/alerts`,
+    {
+      method: 'POST',
+      body: { alertTriage: entries },
+      commandPath: 'socket scan view',
+    },
+  )
+}
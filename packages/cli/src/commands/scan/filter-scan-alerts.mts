@@ -0,0 +1,137 @@
+/**
+ * Client-side alert filters of `socket scan view`, `socket scan diff` and
+ * `socket scan report`, see alert-filter-flags.mts. Secret alerts filter
+ * under their own "secrets" category, see secret-alerts.mts.
+ *
+ * The filters slice the structured results before they are rendered, so text,
+ * markdown and json output all show the same subset. Package filters drop
+ * whole packages; alert filters drop the other alerts, and the packages left
+ * without any. Reports keep every package and only drop alerts.
+ */
+
+import { getAlertCategory } from './secret-alerts.mts'
+import { isSeverityAtLeast } from '../../util/policy/evaluate.mts'
+import { cmdFlagValueToArray } from '../../util/process/cmd.mts'
+
+import type { PolicySeverity } from '../../util/policy/socket-policy.mts'
+
+export type ScanAlertFilter = {
+  alertCategories: string[]
+  alertTypes: string[]
+  directOnly: boolean
+  ecosystems: string[]
+  severity?: PolicySeverity | undefined
+}
+
+// The fields filtered on, shared by scan artifacts and diff scan artifacts.
+type FilterableArtifact = {
+  alerts?:
+    | Array<{
+        category?: string | undefined
+        severity?: string | undefined
+        type: string
+      }>
+    | undefined
+  direct?: boolean | undefined
+  type?: string | undefined
+}
+
+/**
+ * The filter from --alert-category, --alert-type, --direct-only, --ecosystem
+ * and --severity, or undefined when none is set.
+ */
+export function getScanAlertFilter(
+  flags: Record<string, unknown>,
+): ScanAlertFilter | undefined {
+  const alertCategories = cmdFlagValueToArray(flags['alertCategory'])
+  const alertTypes = cmdFlagValueToArray(flags['alertType'])
+  const directOnly = !!flags['directOnly']
+  const ecosystems = cmdFlagValueToArray(flags['ecosystem']).map(e =>
+    e.toLowerCase(),
+  )
+  const severity = String(flags['severity'] || '')
+  if (
+    !alertCategories.length &&
+    !alertTypes.length &&
+    !directOnly &&
+    !ecosystems.length &&
+    !severity
+  ) {
+    return undefined
+  }
+  return {
+    alertCategories,
+    alertTypes,
+    directOnly,
+    ecosystems,
+    ...(severity ? { severity: severity as PolicySeverity } : {}),
+  }
+}
+
+/**
+ * The filter as dry-run details.
+ */
+export function getScanAlertFilterDetails(
+  filter: ScanAlertFilter | undefined,
+): Record<string, boolean | string> {
+  if (!filter) {
+    return {}
+  }
+  return {
+    ...(filter.alertCategories.length
+      ? { alertCategory: filter.alertCategories.join(', ') }
+      : {}),
+    ...(filter.alertTypes.length
+      ? { alertType: filter.alertTypes.join(', ') }
+      : {}),
+    ...(filter.directOnly ? { directOnly: true } : {}),
+    ...(filter.ecosystems.length
+      ? { ecosystem: filter.ecosystems.join(', ') }
+      : {}),
+    ...(filter.severity ? { severity: filter.severity } : {}),
+  }
+}
+
+/**
+ * The artifacts and alerts the filter includes. With keepPackages, left out
+ * packages stay without their alerts, so dependency paths through them
+ * remain intact.
+ */
+export function filterScanArtifacts<T extends FilterableArtifact>(
+  artifacts: T[],
+  filter: ScanAlertFilter | undefined,
+  { keepPackages = false }: { keepPackages?: boolean | undefined } = {},
+): T[] {
+  if (!filter) {
+    return artifacts
+  }
+  const { alertCategories, alertTypes, directOnly, ecosystems, severity } =
+    filter
+  const filtersAlerts =
+    !!alertCategories.length || !!alertTypes.length || !!severity
+  const results: T[] = []
+  for (let i = 0, { length } = artifacts; i < length; i += 1) {
+    const artifact = artifacts[i]!
+    const included =
+      (!directOnly || !!artifact.direct) &&
+      (!ecosystems.length ||
+        ecosystems.includes(String(artifact.type).toLowerCase()))
+    const alerts = !included
+      ? []
+      : filtersAlerts
+        ? (artifact.alerts ?? []).filter(
+            alert =>
+              (!alertCategories.length ||
+                alertCategories.includes(getAlertCategory(alert) ?? '')) &&
+              (!alertTypes.length || alertTypes.includes(alert.type)) &&
+              (!severity || isSeverityAtLeast(alert.severity, severity)),
+          )
+        : artifact.alerts
+    if (included && (!filtersAlerts || alerts?.length)) {
+      results.push(filtersAlerts ? { ...artifact, alerts } : artifact)
+    } else if (keepPackages) {
+      results.push({ ...artifact, alerts: [] })
+    }
+  }
+  return results
+}
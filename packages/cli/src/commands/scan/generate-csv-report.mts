@@ -0,0 +1,83 @@
+/**
+ * CSV rendering of `socket scan report --format=csv`, one row per alert.
+ *
+ * Rows come from the SARIF rendering, so they carry the same manifest
+ * locations, baseline filtering and report level as the other formats. The
+ * package columns are looked up in the scan by purl.
+ */
+
+import { formatCsvRecords } from '../../util/output/csv.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+
+import type { SarifLog } from './generate-sarif-report.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { FormatCsvOptions } from '../../util/output/csv.mts'
+
+export const SCAN_REPORT_CSV_COLUMNS = [
+  'package',
+  'version',
+  'ecosystem',
+  'purl',
+  'severity',
+  'alertType',
+  'policy',
+  'directDep',
+  'filePath',
+  'url',
+] as const
+
+export type ScanReportCsvColumn = (typeof SCAN_REPORT_CSV_COLUMNS)[number]
+
+export const SCAN_REPORT_CSV_DEFAULT_COLUMNS: readonly ScanReportCsvColumn[] =
+  ['package', 'version', 'severity', 'alertType', 'directDep', 'filePath']
+
+export function generateCsvReport(
+  scan: SocketArtifact[],
+  sarif: SarifLog,
+  {
+    columns = SCAN_REPORT_CSV_DEFAULT_COLUMNS,
+    ...options
+  }: FormatCsvOptions & {
+    columns?: readonly ScanReportCsvColumn[] | undefined
+  } = {},
+): string {
+  // Scans list a package once per manifest, it is direct when any says so.
+  const byPurl = new Map<
+    string,
+    { artifact: SocketArtifact; direct: boolean }
+  >()
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    const purl = getArtifactPurlString(artifact)
+    const seen = byPurl.get(purl)
+    byPurl.set(purl, {
+      artifact: seen?.artifact ?? artifact,
+      direct: !!seen?.direct || !!artifact.direct,
+    })
+  }
+  const results = sarif.runs[0]?.results ?? []
+  const records = results.map(result => {
+    const { purl } = result.properties
+    const found = byPurl.get(purl)
+    const { name = '', namespace, type = '', version = '' } =
+      found?.artifact ?? {}
+    return {
+      alertType: result.ruleId,
+      directDep: String(!!found?.direct),
+      ecosystem: type,
+      // A package pulled in by several manifests has one location each.
+      filePath: [
+        ...new Set(
+          result.locations.map(l => l.physicalLocation.artifactLocation.uri),
+        ),
+      ].join(';'),
+      package: namespace ? `${namespace}/${name}` : name,
+      policy: result.properties.policy,
+      purl,
+      severity: result.properties.severity,
+      url: result.properties.url,
+      version,
+    }
+  })
+  return `${formatCsvRecords(columns, records, options)}\n`
+}
@@ -0,0 +1,214 @@
+/**
+ * GitLab report artifacts of a Socket scan, derived from the SARIF rendering
+ * so both agree on which alerts are reported and where.
+ *
+ * - Code Quality (https://docs.gitlab.com/ci/testing/code_quality/): a JSON
+ *   array of issues shown in the merge request widget and the diff. Alerts
+ *   the policy blocks are `blocker`s, the rest are graded by alert severity.
+ * - SAST (https://docs.gitlab.com/development/integrations/secure/): a
+ *   security report (schema 15) shown in the merge request security widget
+ *   and the vulnerability report.
+ *
+ * Both point at the manifest that introduced the package. GitLab requires a
+ * path, so alerts without a known manifest use the package purl instead.
+ */
+
+import { createHash } from 'node:crypto'
+
+import { REPORT_LEVEL_ERROR } from '../../constants/reporting.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+
+import type { SarifLog, SarifResult } from './generate-sarif-report.mts'
+
+export const GITLAB_SAST_SCHEMA_VERSION = '15.0.7'
+
+export type GitlabCodeQualitySeverity =
+  | 'info'
+  | 'minor'
+  | 'major'
+  | 'critical'
+  | 'blocker'
+
+export type GitlabCodeQualityIssue = {
+  categories: string[]
+  check_name: string
+  description: string
+  fingerprint: string
+  location: {
+    lines: { begin: number }
+    path: string
+  }
+  severity: GitlabCodeQualitySeverity
+  type: 'issue'
+}
+
+export type GitlabSastSeverity =
+  | 'Critical'
+  | 'High'
+  | 'Medium'
+  | 'Low'
+  | 'Info'
+  | 'Unknown'
+
+export type GitlabSastVulnerability = {
+  description: string
+  id: string
+  identifiers: Array<{
+    name: string
+    type: string
+    url: string
+    value: string
+  }>
+  links: Array<{ url: string }>
+  location: {
+    file: string
+    start_line?: number | undefined
+  }
+  name: string
+  severity: GitlabSastSeverity
+  solution?: string | undefined
+}
+
+export type GitlabSastReport = {
+  scan: {
+    analyzer: GitlabSastTool
+    end_time: string
+    scanner: GitlabSastTool
+    start_time: string
+    status: 'success'
+    type: 'sast'
+  }
+  version: string
+  vulnerabilities: GitlabSastVulnerability[]
+}
+
+type GitlabSastTool = {
+  id: string
+  name: string
+  vendor: { name: string }
+  version: string
+}
+
+const CODE_QUALITY_SEVERITY = {
+  __proto__: null,
+  critical: 'critical',
+  high: 'major',
+  middle: 'minor',
+  low: 'info',
+} as unknown as Record<string, GitlabCodeQualitySeverity | undefined>
+
+const SAST_SEVERITY = {
+  __proto__: null,
+  critical: 'Critical',
+  high: 'High',
+  middle: 'Medium',
+  low: 'Low',
+} as unknown as Record<string, GitlabSastSeverity | undefined>
+
+function getResultLocation(result: SarifResult): {
+  line?: number | undefined
+  path: string
+} {
+  const physical = result.locations[0]?.physicalLocation
+  if (!physical) {
+    return { path: result.properties.purl }
+  }
+  return {
+    path: physical.artifactLocation.uri,
+    ...(physical.region ? { line: physical.region.startLine } : {}),
+  }
+}
+
+function hashResult(result: SarifResult): string {
+  return createHash('sha256')
+    .update(result.partialFingerprints.socketAlertKey)
+    .digest('hex')
+}
+
+// A stable UUID-shaped id, so the same alert keeps its id across pipelines.
+function toUuid(hex: string): string {
+  return [
+    hex.slice(0, 8),
+    hex.slice(8, 12),
+    hex.slice(12, 16),
+    hex.slice(16, 20),
+    hex.slice(20, 32),
+  ].join('-')
+}
+
+// GitLab expects second precision without a zone suffix.
+function toGitlabTime(date: Date): string {
+  return date.toISOString().slice(0, 19)
+}
+
+export function generateGitlabCodeQualityReport(
+  sarif: SarifLog,
+): GitlabCodeQualityIssue[] {
+  const results = sarif.runs[0]?.results ?? []
+  return results.map(result => {
+    const { line, path } = getResultLocation(result)
+    return {
+      categories: ['Security'],
+      check_name: result.ruleId,
+      description: result.message.text,
+      fingerprint: hashResult(result).slice(0, 32),
+      location: { lines: { begin: line ?? 1 }, path },
+      severity:
+        result.properties.policy === REPORT_LEVEL_ERROR
+          ? 'blocker'
+          : (CODE_QUALITY_SEVERITY[result.properties.severity] ?? 'info'),
+      type: 'issue',
+    }
+  })
+}
+
+export function generateGitlabSastReport(
+  sarif: SarifLog,
+  { startTime = new Date() }: { startTime?: Date | undefined } = {},
+): GitlabSastReport {
+  const run = sarif.runs[0]
+  const rules = run?.tool.driver.rules ?? []
+  const results = run?.results ?? []
+  const tool: GitlabSastTool = {
+    id: 'socket',
+    name: 'Socket',
+    vendor: { name: 'Socket' },
+    version: getCliVersion() || '0.0.0',
+  }
+  return {
+    scan: {
+      analyzer: tool,
+      end_time: toGitlabTime(new Date()),
+      scanner: tool,
+      start_time: toGitlabTime(startTime),
+      status: 'success',
+      type: 'sast',
+    },
+    version: GITLAB_SAST_SCHEMA_VERSION,
+    vulnerabilities: results.map(result => {
+      const rule = rules[result.ruleIndex]
+      const { line, path } = getResultLocation(result)
+      const solution = rule?.help.text
+      return {
+        description: rule?.fullDescription.text || result.message.text,
+        id: toUuid(hashResult(result)),
+        identifiers: [
+          {
+            name: rule?.name || result.ruleId,
+            type: 'socket_alert',
+            url: rule?.helpUri || result.properties.url,
+            value: result.ruleId,
+          },
+        ],
+        links: [{ url: result.properties.url }],
+        location: {
+          file: path,
+          ...(line === undefined ? {} : { start_line: line }),
+        },
+        name: result.message.text,
+        severity: SAST_SEVERITY[result.properties.severity] ?? 'Unknown',
+        ...(solution ? { solution } : {}),
+      }
+    }),
+  }
+}
@@ -0,0 +1,222 @@
+/**
+ * Inline styles and script of the HTML scan report. The script reads the
+ * report data from the `report-data` element, sorts the tables and draws
+ * the dependency graph.
+ */
+
+export const REPORT_STYLES = `
+body { font: 14px/1.5 system-ui, sans-serif; margin: 0; color: #1d2433; background: #f6f7f9; }
+header, section { max-width: 1200px; margin: 0 auto; padding: 16px 24px; }
+header h1 { margin-bottom: 4px; }
+.meta { color: #5b6478; margin: 0; }
+.status { display: inline-block; padding: 4px 12px; border-radius: 4px; font-weight: 600; }
+.status.pass { background: #dcf5e3; color: #13602d; }
+.status.fail { background: #fde2e1; color: #9b1c1c; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; }
+.card { background: #fff; border: 1px solid #dde1e8; border-radius: 6px; padding: 12px 16px; min-width: 120px; }
+.card .value { display: block; font-size: 24px; font-weight: 600; }
+.card .label { color: #5b6478; }
+table { border-collapse: collapse; width: 100%; background: #fff; }
+th, td { border: 1px solid #dde1e8; padding: 4px 8px; text-align: left; vertical-align: top; }
+table.sortable th { cursor: pointer; user-select: none; background: #eef0f4; }
+table.sortable th[aria-sort=ascending]::after { content: " \\25B2"; }
+table.sortable th[aria-sort=descending]::after { content: " \\25BC"; }
+tr[data-package] { cursor: pointer; }
+tr.selected td { background: #fff4d6; }
+tr.level-error td:first-child { color: #9b1c1c; font-weight: 600; }
+.empty { color: #5b6478; }
+#graph-wrap { overflow: auto; background: #fff; border: 1px solid #dde1e8; max-height: 640px; }
+#graph .edge { fill: none; stroke: #a8b0bf; stroke-width: 1.2; }
+#graph .node rect { fill: #fff; stroke: #7a8396; rx: 4; }
+#graph .node.direct rect { stroke-width: 2; }
+#graph .node.alerted rect { stroke: #c0392b; fill: #fdecea; }
+#graph .node text { font-size: 12px; pointer-events: none; }
+#graph .node { cursor: pointer; }
+#graph.highlight .edge, #graph.highlight .node { opacity: 0.2; }
+#graph.highlight .edge.on { opacity: 1; stroke: #c0392b; stroke-width: 2; }
+#graph.highlight .node.on { opacity: 1; }
+`
+
+// Plain ES5 so the report opens in any browser without a build step.
+export const REPORT_SCRIPT = `
+(function () {
+  var data = JSON.parse(document.getElementById('report-data').textContent)
+
+  function sortValue(row, index) {
+    var cell = row.cells[index]
+    var value = cell.getAttribute('data-sort')
+    return value === null ? cell.textContent : value
+  }
+
+  Array.prototype.forEach.call(
+    document.querySelectorAll('table.sortable th'),
+    function (th) {
+      th.addEventListener('click', function () {
+        var table = th.parentNode.parentNode.parentNode
+        var index = Array.prototype.indexOf.call(th.parentNode.children, th)
+        var ascending = th.getAttribute('aria-sort') !== 'ascending'
+        var headers = table.querySelectorAll('th')
+        Array.prototype.forEach.call(headers, function (header) {
+          header.removeAttribute('aria-sort')
+        })
+        th.setAttribute('aria-sort', ascending ? 'ascending' : 'descending')
+        var body = table.tBodies[0]
+        var rows = Array.prototype.slice.call(body.rows)
+        rows.sort(function (a, b) {
+          var x = sortValue(a, index)
+          var y = sortValue(b, index)
+          var numeric = x !== '' && y !== '' && !isNaN(x) && !isNaN(y)
+          var order = numeric ? Number(x) - Number(y) : x.localeCompare(y)
+          return ascending ? order : -order
+        })
+        rows.forEach(function (row) {
+          body.appendChild(row)
+        })
+      })
+    },
+  )
+
+  var svg = document.getElementById('graph')
+  var nodes = data.graph.nodes
+  if (!svg || !nodes.length) {
+    return
+  }
+  var NS = 'http://www.w3.org/2000/svg'
+  var packages = {}
+  data.packages.forEach(function (pkg) {
+    packages[pkg.id] = pkg
+  })
+  var parents = {}
+  var children = {}
+  nodes.forEach(function (id) {
+    parents[id] = []
+    children[id] = []
+  })
+  data.graph.edges.forEach(function (edge) {
+    children[edge[0]].push(edge[1])
+    parents[edge[1]].push(edge[0])
+  })
+
+  // Columns by distance from the direct dependencies.
+  var depth = {}
+  var queue = nodes.filter(function (id) {
+    return packages[id].direct || !parents[id].length
+  })
+  queue.forEach(function (id) {
+    depth[id] = 0
+  })
+  for (var q = 0; q < queue.length; q += 1) {
+    children[queue[q]].forEach(function (childId) {
+      if (depth[childId] === undefined) {
+        depth[childId] = depth[queue[q]] + 1
+        queue.push(childId)
+      }
+    })
+  }
+  var columns = []
+  var position = {}
+  nodes.forEach(function (id) {
+    var column = depth[id] || 0
+    columns[column] = columns[column] || []
+    position[id] = { x: 16 + column * 260, y: 16 + columns[column].length * 30 }
+    columns[column].push(id)
+  })
+  var height = 0
+  columns.forEach(function (column) {
+    height = Math.max(height, column.length)
+  })
+  svg.setAttribute('width', 16 + columns.length * 260)
+  svg.setAttribute('height', 16 + height * 30)
+
+  function create(name, attrs, parent) {
+    var el = document.createElementNS(NS, name)
+    Object.keys(attrs).forEach(function (key) {
+      el.setAttribute(key, attrs[key])
+    })
+    parent.appendChild(el)
+    return el
+  }
+
+  var edgeEls = data.graph.edges.map(function (edge) {
+    var from = position[edge[0]]
+    var to = position[edge[1]]
+    var x1 = from.x + 220
+    var y1 = from.y + 11
+    var x2 = to.x
+    var y2 = to.y + 11
+    var mid = (x1 + x2) / 2
+    // Arrays stringify as "x,y".
+    var d =
+      'M' + [x1, y1] + ' C' + [mid, y1] + ' ' + [mid, y2] + ' ' + [x2, y2]
+    return { edge: edge, el: create('path', { class: 'edge', d: d }, svg) }
+  })
+  var nodeEls = {}
+  nodes.forEach(function (id) {
+    var pkg = packages[id]
+    var g = create(
+      'g',
+      {
+        class:
+          'node' + (pkg.direct ? ' direct' : '') + (pkg.alerts ? ' alerted' : ''),
+        transform: 'translate(' + position[id].x + ',' + position[id].y + ')',
+      },
+      svg,
+    )
+    create('rect', { width: 220, height: 22 }, g)
+    var label = pkg.purl.replace(/^pkg:/, '')
+    create('text', { x: 6, y: 15 }, g).textContent =
+      label.length > 32 ? label.slice(0, 31) + '\\u2026' : label
+    create('title', {}, g).textContent =
+      pkg.purl + (pkg.alerts ? ' (' + pkg.alerts + ' alerts)' : '')
+    g.addEventListener('click', function (event) {
+      event.stopPropagation()
+      highlight(id)
+    })
+    nodeEls[id] = g
+  })
+
+  function highlight(id) {
+    var on = {}
+    on[id] = true
+    var stack = [id]
+    while (stack.length) {
+      ;(parents[stack.pop()] || []).forEach(function (parentId) {
+        if (!on[parentId]) {
+          on[parentId] = true
+          stack.push(parentId)
+        }
+      })
+    }
+    svg.classList.toggle('highlight', !!nodeEls[id])
+    Object.keys(nodeEls).forEach(function (nodeId) {
+      nodeEls[nodeId].classList.toggle('on', !!on[nodeId])
+    })
+    edgeEls.forEach(function (item) {
+      item.el.classList.toggle('on', !!on[item.edge[0]] && !!on[item.edge[1]])
+    })
+    Array.prototype.forEach.call(
+      document.querySelectorAll('tr[data-package]'),
+      function (row) {
+        var selected = row.getAttribute('data-package') === id
+        row.classList.toggle('selected', selected)
+      },
+    )
+  }
+
+  svg.addEventListener('click', function () {
+    highlight('')
+  })
+  Array.prototype.forEach.call(
+    document.querySelectorAll('tr[data-package]'),
+    function (row) {
+      row.addEventListener('click', function () {
+        var id = row.getAttribute('data-package')
+        highlight(id)
+        if (nodeEls[id]) {
+          document.getElementById('graph-section').scrollIntoView()
+        }
+      })
+    },
+  )
+})()
+`
@@ -0,0 +1,162 @@
+/**
+ * Table and summary markup of the HTML scan report. Cells carry a
+ * `data-sort` value where the text doesn't sort on its own, and rows carry
+ * the id of their package for the graph highlighting.
+ */
+
+import { escapeHtml } from '../../util/output/html.mts'
+
+import type {
+  HtmlReportAlert,
+  HtmlReportData,
+  HtmlReportPackage,
+} from './generate-html-report.mts'
+
+export const HTML_SCORE_KEYS = [
+  'overall',
+  'supplyChain',
+  'quality',
+  'maintenance',
+  'vulnerability',
+  'license',
+] as const
+
+export type HtmlScoreKey = (typeof HTML_SCORE_KEYS)[number]
+
+export const SCORE_LABELS: Record<HtmlScoreKey, string> = {
+  __proto__: null,
+  license: 'License',
+  maintenance: 'Maintenance',
+  overall: 'Overall',
+  quality: 'Quality',
+  supplyChain: 'Supply chain',
+  vulnerability: 'Vulnerability',
+} as unknown as Record<HtmlScoreKey, string>
+
+// Sort ranks, so the most severe rows sort last ascending and first
+// descending.
+const POLICY_RANK: Record<string, number | undefined> = {
+  __proto__: null,
+  defer: 0,
+  ignore: 1,
+  monitor: 2,
+  warn: 3,
+  error: 4,
+} as unknown as Record<string, number | undefined>
+
+const SEVERITY_RANK: Record<string, number | undefined> = {
+  __proto__: null,
+  low: 0,
+  middle: 1,
+  high: 2,
+  critical: 3,
+} as unknown as Record<string, number | undefined>
+
+function renderCell(text: string, sortValue?: string | number): string {
+  return sortValue === undefined
+    ? `<td>${escapeHtml(text)}</td>`
+    : `<td data-sort="${escapeHtml(sortValue)}">${escapeHtml(text)}</td>`
+}
+
+export function renderTable(
+  id: string,
+  headers: string[],
+  rows: string[],
+  emptyText: string,
+): string {
+  if (!rows.length) {
+    return `<p class="empty">${escapeHtml(emptyText)}</p>`
+  }
+  return [
+    `<table class="sortable" id="${id}">`,
+    `<thead><tr>${headers.map(h => `<th scope="col">${escapeHtml(h)}</th>`).join('')}</tr></thead>`,
+    '<tbody>',
+    ...rows,
+    '</tbody>',
+    '</table>',
+  ].join('\n')
+}
+
+export function renderAlertRows(alerts: HtmlReportAlert[]): string[] {
+  return alerts.map(alert =>
+    [
+      `<tr class="level-${alert.level}" data-package="${escapeHtml(alert.packageId)}">`,
+      renderCell(alert.policy, POLICY_RANK[alert.policy] ?? -1),
+      renderCell(alert.severity, SEVERITY_RANK[alert.severity] ?? -1),
+      `<td><a href="${escapeHtml(alert.url)}">${escapeHtml(alert.title)}</a> <code>${escapeHtml(alert.type)}</code></td>`,
+      renderCell(alert.purl),
+      renderCell(alert.introducedBy.join(', ')),
+      renderCell(alert.manifest),
+      '</tr>',
+    ].join(''),
+  )
+}
+
+export function renderPackageRows(packages: HtmlReportPackage[]): string[] {
+  return packages.map(pkg =>
+    [
+      `<tr data-package="${escapeHtml(pkg.id)}">`,
+      renderCell(pkg.purl),
+      renderCell(pkg.direct ? 'yes' : 'no'),
+      renderCell(String(pkg.alerts), pkg.alerts),
+      ...HTML_SCORE_KEYS.map(key => {
+        const value = pkg.score?.[key]
+        return value === undefined
+          ? renderCell('', -1)
+          : renderCell(String(value), value)
+      }),
+      '</tr>',
+    ].join(''),
+  )
+}
+
+export function renderSummary(data: HtmlReportData): string {
+  const policyCounts = new Map<string, number>()
+  for (let i = 0, { length } = data.alerts; i < length; i += 1) {
+    const { policy } = data.alerts[i]!
+    policyCounts.set(policy, (policyCounts.get(policy) ?? 0) + 1)
+  }
+  const cards = [
+    ['Packages', String(data.packages.length)],
+    [
+      'Direct dependencies',
+      String(data.packages.filter(p => p.direct).length),
+    ],
+    ['Alerts', String(data.alerts.length)],
+    ...['error', 'warn', 'monitor', 'ignore']
+      .filter(policy => policyCounts.has(policy))
+      .map(policy => [`Policy ${policy}`, String(policyCounts.get(policy))]),
+  ]
+  const lowest = HTML_SCORE_KEYS.map(key => {
+    let lowestPkg: HtmlReportPackage | undefined
+    for (let i = 0, { length } = data.packages; i < length; i += 1) {
+      const pkg = data.packages[i]!
+      const value = pkg.score?.[key]
+      if (
+        value !== undefined &&
+        (lowestPkg === undefined || value < lowestPkg.score![key]!)
+      ) {
+        lowestPkg = pkg
+      }
+    }
+    return lowestPkg
+      ? `<tr><th scope="row">${SCORE_LABELS[key]}</th><td>${lowestPkg.score![key]}</td><td>${escapeHtml(lowestPkg.purl)}</td></tr>`
+      : ''
+  }).filter(Boolean)
+  return [
+    '<div class="cards">',
+    ...cards.map(
+      ({ 0: label, 1: value }) =>
+        `<div class="card"><span class="value">${escapeHtml(value!)}</span><span class="label">${escapeHtml(label!)}</span></div>`,
+    ),
+    '</div>',
+    ...(lowest.length
+      ? [
+          '<h3>Lowest scores</h3>',
+          '<table class="scores"><thead><tr><th scope="col">Score</th><th scope="col">Lowest</th><th scope="col">Package</th></tr></thead><tbody>',
+          ...lowest,
+          '</tbody></table>',
+        ]
+      : []),
+  ].join('\n')
+}
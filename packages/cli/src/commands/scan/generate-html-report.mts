@@ -0,0 +1,285 @@
+/**
+ * Self-contained HTML rendering of `socket scan report`.
+ *
+ * Alerts come from the SARIF rendering, so policy levels, the baseline and
+ * the report level apply as in the other formats. The report has sortable
+ * alert and package tables, a score breakdown, and a dependency graph of the
+ * packages on the paths from direct dependencies to alerted packages.
+ * Selecting an alert or a package highlights the paths that introduce it.
+ *
+ * Tables are rendered up front so the report reads without scripts; the
+ * inline script only adds sorting and draws the graph.
+ */
+
+import { REPORT_SCRIPT, REPORT_STYLES } from './generate-html-report-assets.mts'
+import {
+  HTML_SCORE_KEYS,
+  SCORE_LABELS,
+  renderAlertRows,
+  renderPackageRows,
+  renderSummary,
+  renderTable,
+} from './generate-html-report-rows.mts'
+import {
+  escapeHtml,
+  renderHtmlDocument,
+  toScriptJson,
+} from '../../util/output/html.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+
+import type { HtmlScoreKey } from './generate-html-report-rows.mts'
+import type { SarifLevel, SarifLog } from './generate-sarif-report.mts'
+import type { REPORT_LEVEL } from './types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export { HTML_SCORE_KEYS } from './generate-html-report-rows.mts'
+export type { HtmlScoreKey } from './generate-html-report-rows.mts'
+
+export type HtmlReportAlert = {
+  introducedBy: string[]
+  level: SarifLevel
+  manifest: string
+  packageId: string
+  policy: REPORT_LEVEL
+  purl: string
+  severity: string
+  title: string
+  type: string
+  url: string
+}
+
+export type HtmlReportPackage = {
+  alerts: number
+  direct: boolean
+  id: string
+  purl: string
+  // Scores as percentages.
+  score?: Partial<Record<HtmlScoreKey, number>> | undefined
+}
+
+export type HtmlReportData = {
+  alerts: HtmlReportAlert[]
+  generatedAt: string
+  // Packages on the paths from direct dependencies to alerted packages, and
+  // the dependency edges between them.
+  graph: { edges: Array<[string, string]>; nodes: string[] }
+  healthy: boolean
+  orgSlug: string
+  packages: HtmlReportPackage[]
+  scanId: string
+}
+
+export type GenerateHtmlReportOptions = {
+  generatedAt?: Date | undefined
+  orgSlug: string
+  scanId: string
+}
+
+function getArtifactId(artifact: SocketArtifact): string {
+  return artifact.id || getArtifactPurlString(artifact)
+}
+
+/**
+ * Ids of every package that depends on the target, directly or not.
+ */
+export function getAncestorIds(
+  targetId: string,
+  parents: Map<string, string[]>,
+): Set<string> {
+  const ancestors = new Set<string>()
+  const queue = [targetId]
+  while (queue.length) {
+    const id = queue.pop()!
+    const ids = parents.get(id) ?? []
+    for (let i = 0, { length } = ids; i < length; i += 1) {
+      const parentId = ids[i]!
+      if (!ancestors.has(parentId) && parentId !== targetId) {
+        ancestors.add(parentId)
+        queue.push(parentId)
+      }
+    }
+  }
+  return ancestors
+}
+
+export function getHtmlReportData(
+  scan: SocketArtifact[],
+  sarif: SarifLog,
+  { generatedAt = new Date(), orgSlug, scanId }: GenerateHtmlReportOptions,
+): HtmlReportData {
+  const artifactById = new Map<string, SocketArtifact>()
+  const idByPurl = new Map<string, string>()
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    const id = getArtifactId(artifact)
+    artifactById.set(id, artifact)
+    const purl = getArtifactPurlString(artifact)
+    if (!idByPurl.has(purl)) {
+      idByPurl.set(purl, id)
+    }
+  }
+
+  const edges: Array<[string, string]> = []
+  const parents = new Map<string, string[]>()
+  for (const [id, artifact] of artifactById) {
+    const deps = (artifact.dependencies ?? []) as string[]
+    for (let i = 0, { length } = deps; i < length; i += 1) {
+      const childId = deps[i]!
+      if (!artifactById.has(childId) || childId === id) {
+        continue
+      }
+      const childParents = parents.get(childId) ?? []
+      if (!childParents.includes(id)) {
+        childParents.push(id)
+        parents.set(childId, childParents)
+        edges.push([id, childId])
+      }
+    }
+  }
+
+  const results = sarif.runs[0]?.results ?? []
+  const rules = sarif.runs[0]?.tool.driver.rules ?? []
+  const alertCounts = new Map<string, number>()
+  const graphIds = new Set<string>()
+  const introducedByCache = new Map<string, string[]>()
+  const getIntroducedBy = (id: string): string[] => {
+    let purls = introducedByCache.get(id)
+    if (purls) {
+      return purls
+    }
+    const ancestors = getAncestorIds(id, parents)
+    for (const ancestorId of ancestors) {
+      graphIds.add(ancestorId)
+    }
+    graphIds.add(id)
+    const candidates = [id, ...ancestors].filter(
+      ancestorId => artifactById.get(ancestorId)?.direct,
+    )
+    // Without a dependency graph fall back to the ancestors the API reports.
+    const topLevelIds = candidates.length
+      ? candidates
+      : ((artifactById.get(id)?.topLevelAncestors ?? []) as string[]).filter(
+          ancestorId => artifactById.has(ancestorId),
+        )
+    purls = topLevelIds
+      .map(topLevelId => getArtifactPurlString(artifactById.get(topLevelId)!))
+      .sort()
+    introducedByCache.set(id, purls)
+    return purls
+  }
+
+  const alerts = results.map(result => {
+    const { purl } = result.properties
+    const packageId = idByPurl.get(purl) ?? purl
+    alertCounts.set(packageId, (alertCounts.get(packageId) ?? 0) + 1)
+    return {
+      introducedBy: artifactById.has(packageId)
+        ? getIntroducedBy(packageId)
+        : [],
+      level: result.level,
+      manifest:
+        result.locations[0]?.physicalLocation.artifactLocation.uri ?? '',
+      packageId,
+      policy: result.properties.policy,
+      purl,
+      severity: result.properties.severity,
+      title: rules[result.ruleIndex]?.shortDescription.text ?? result.ruleId,
+      type: result.ruleId,
+      url: result.properties.url,
+    }
+  })
+
+  const packages = [...artifactById].map(([id, artifact]) => {
+    const score: Partial<Record<HtmlScoreKey, number>> = {}
+    const artifactScore = (artifact.score ?? {}) as Record<string, unknown>
+    for (let i = 0, { length } = HTML_SCORE_KEYS; i < length; i += 1) {
+      const key = HTML_SCORE_KEYS[i]!
+      const value = artifactScore[key]
+      if (typeof value === 'number') {
+        score[key] = Math.round(value * 100)
+      }
+    }
+    return {
+      alerts: alertCounts.get(id) ?? 0,
+      direct: !!artifact.direct,
+      id,
+      purl: getArtifactPurlString(artifact),
+      ...(Object.keys(score).length ? { score } : {}),
+    }
+  })
+
+  return {
+    alerts,
+    generatedAt: generatedAt.toISOString(),
+    graph: {
+      edges: edges.filter(
+        ({ 0: from, 1: to }) => graphIds.has(from) && graphIds.has(to),
+      ),
+      nodes: [...artifactById.keys()].filter(id => graphIds.has(id)),
+    },
+    healthy: !results.some(r => r.level === 'error'),
+    orgSlug,
+    packages,
+    scanId,
+  }
+}
+export function generateHtmlReport(
+  scan: SocketArtifact[],
+  sarif: SarifLog,
+  options: GenerateHtmlReportOptions,
+): string {
+  const data = getHtmlReportData(scan, sarif, options)
+  const { orgSlug, scanId } = data
+  const body = [
+    '<header>',
+    '<h1>Socket scan report</h1>',
+    `<p class="meta">Organization ${escapeHtml(orgSlug)} · Scan ${escapeHtml(scanId)} · Generated ${escapeHtml(data.generatedAt)}</p>`,
+    data.healthy
+      ? '<p class="status pass">The scan passes the organization policies</p>'
+      : '<p class="status fail">The scan violates one or more policies set to the "error" level</p>',
+    '</header>',
+    '<section id="summary-section">',
+    '<h2>Summary</h2>',
+    renderSummary(data),
+    '</section>',
+    '<section id="alerts-section">',
+    '<h2>Alerts</h2>',
+    renderTable(
+      'alerts',
+      ['Policy', 'Severity', 'Alert', 'Package', 'Introduced by', 'Manifest'],
+      renderAlertRows(data.alerts),
+      'The scan has no alerts at the reported policy levels.',
+    ),
+    '</section>',
+    '<section id="graph-section">',
+    '<h2>Dependency graph</h2>',
+    data.graph.nodes.length
+      ? [
+          '<p class="meta">Packages on the paths from direct dependencies to alerted packages. Select an alert, a package or a node to highlight the paths that introduce it.</p>',
+          '<div id="graph-wrap"><svg id="graph" xmlns="http://www.w3.org/2000/svg"></svg></div>',
+        ].join('\n')
+      : '<p class="empty">No alerted packages to show.</p>',
+    '</section>',
+    '<section id="packages-section">',
+    '<h2>Packages</h2>',
+    renderTable(
+      'packages',
+      [
+        'Package',
+        'Direct',
+        'Alerts',
+        ...HTML_SCORE_KEYS.map(key => SCORE_LABELS[key]),
+      ],
+      renderPackageRows(data.packages),
+      'The scan has no packages.',
+    ),
+    '</section>',
+    `<script type="application/json" id="report-data">${toScriptJson(data)}</script>`,
+  ].join('\n')
+  return renderHtmlDocument({
+    body,
+    script: REPORT_SCRIPT,
+    styles: REPORT_STYLES,
+    title: `Socket scan report ${scanId}`,
+  })
+}
@@ -0,0 +1,113 @@
+/**
+ * JUnit XML renderings of `socket scan report` and `socket scan check`.
+ *
+ * Report results come from the SARIF rendering, one test case per alert.
+ * Only `error` results (the alerts that make the report unhealthy) fail, so
+ * the test summary agrees with the exit code. Lower levels pass with their
+ * details as output. Policy checks get one failing case per `deny` entry and
+ * one passing case per `warn` entry.
+ */
+
+import { renderJunitReport } from '../../util/output/junit.mts'
+
+import type { SarifLog, SarifResult } from './generate-sarif-report.mts'
+import type { JunitTestCase } from '../../util/output/junit.mts'
+import type {
+  RegoPolicyResult,
+  RegoViolation,
+} from '../../util/policy/rego.mts'
+
+function formatResultDetails(result: SarifResult, help: string): string {
+  const physical = result.locations[0]?.physicalLocation
+  const lines = [
+    result.message.text,
+    '',
+    `Package: ${result.properties.purl}`,
+    `Severity: ${result.properties.severity}`,
+    `Policy action: ${result.properties.policy}`,
+  ]
+  if (physical) {
+    lines.push(
+      `Manifest: ${physical.artifactLocation.uri}${physical.region ? `:${physical.region.startLine}` : ''}`,
+    )
+  }
+  lines.push(`More info: ${result.properties.url}`)
+  if (help) {
+    lines.push('', help)
+  }
+  return lines.join('\n')
+}
+
+export function generateJunitReport(
+  sarif: SarifLog,
+  { timestamp }: { timestamp?: Date | undefined } = {},
+): string {
+  const run = sarif.runs[0]
+  const rules = run?.tool.driver.rules ?? []
+  const results = run?.results ?? []
+  const testCases: JunitTestCase[] = results.map(result => {
+    const details = formatResultDetails(
+      result,
+      rules[result.ruleIndex]?.help.text ?? '',
+    )
+    // Group cases by manifest, like test classes, falling back to the purl.
+    const classname =
+      result.locations[0]?.physicalLocation.artifactLocation.uri ||
+      result.properties.purl
+    const name = `${result.ruleId} in ${result.properties.purl}`
+    return result.level === 'error'
+      ? {
+          classname,
+          failure: {
+            details,
+            message: result.message.text,
+            type: result.ruleId,
+          },
+          name,
+        }
+      : { classname, name, systemOut: details }
+  })
+  return renderJunitReport({
+    emptyCaseName: 'No alerts reported',
+    name: 'Socket',
+    testCases,
+    timestamp,
+  })
+}
+
+function formatViolationDetails(violation: RegoViolation): string {
+  return violation.details
+    ? `${violation.msg}\n\n${JSON.stringify(violation.details, null, 2)}`
+    : violation.msg
+}
+
+export function generatePolicyCheckJunitReport(
+  result: RegoPolicyResult,
+  {
+    regoPackage,
+    timestamp,
+  }: { regoPackage: string; timestamp?: Date | undefined },
+): string {
+  const testCases: JunitTestCase[] = [
+    ...result.deny.map(violation => ({
+      classname: regoPackage,
+      failure: {
+        details: formatViolationDetails(violation),
+        message: violation.msg,
+        type: 'deny',
+      },
+      name: violation.msg,
+    })),
+    ...result.warn.map(violation => ({
+      classname: regoPackage,
+      name: violation.msg,
+      systemOut: formatViolationDetails(violation),
+    })),
+  ]
+  return renderJunitReport({
+    emptyCaseName: 'Policy passed',
+    name: 'Socket policy check',
+    testCases,
+    timestamp,
+  })
+}
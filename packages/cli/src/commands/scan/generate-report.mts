@@ -1,5 +1,12 @@
 import { UNKNOWN_VALUE } from '@socketsecurity/lib-stable/constants/sentinels'
 
+import {
+  getAlertAction,
+  getLocalPolicyAlerts,
+  isFailOnViolation,
+  isReportedAction,
+} from './report-policy.mts'
+import { getAlertCategory, getSecretEvidence } from './secret-alerts.mts'
 import {
   FOLD_SETTING_FILE,
   FOLD_SETTING_PKG,
@@ -12,11 +19,17 @@ import {
   REPORT_LEVEL_MONITOR,
   REPORT_LEVEL_WARN,
 } from '../../constants/reporting.mts'
+import { getLocalTrust } from '../../util/policy/evaluate.mts'
 import { getSocketDevPackageOverviewUrlFromPurl } from '../../util/socket/url.mts'
 
-import type { FOLD_SETTING, REPORT_LEVEL } from './types.mts'
+import type { ImportReachability } from './import-reachability.mts'
+import type { ArtifactAlert, ReportPolicy } from './report-policy.mts'
+import type { SecretEvidence } from './secret-alerts.mts'
+import type { FAIL_ON, FOLD_SETTING, REPORT_LEVEL } from './types.mts'
 import type { CResult } from '../../types.mts'
 import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { FoundSocketBaseline } from '../../util/policy/baseline.mts'
+import type { SocketPolicy } from '../../util/policy/socket-policy.mts'
 import type { SpinnerInstance } from '@socketsecurity/lib-stable/spinner/types'
 import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
 
@@ -35,6 +48,7 @@ export interface ScanReport {
   orgSlug: string
   scanId: string
   options: {
+    failOn?: FAIL_ON | undefined
     fold: FOLD_SETTING
     reportLevel: REPORT_LEVEL
   }
@@ -43,8 +57,16 @@ export interface ScanReport {
 }
 
 export type ReportLeafNode = {
+  // Key of the alert, for `socket explain`.
+  alertId?: string | undefined
   type: string
+  // "secrets" for embedded credentials, else the Socket API category.
+  category?: string | undefined
+  // File and redacted snippet of a secret alert, see --show-evidence.
+  evidence?: SecretEvidence | undefined
   policy: REPORT_LEVEL
+  // Set when the report was generated with import reachability.
+  reachability?: ImportReachability | undefined
   url: string
   manifest: string[]
 }
@@ -56,8 +78,10 @@ export function addAlert(
   ecosystem: string,
   pkgName: string,
   version: string,
-  alert: NonNullable<SocketArtifact['alerts']>[number],
+  alert: ArtifactAlert,
   policyAction: REPORT_LEVEL,
+  reachability?: ImportReachability | undefined,
+  evidence?: SecretEvidence | undefined,
 ): void {
   if (!violations.has(ecosystem)) {
     violations.set(ecosystem, new Map())
@@ -66,7 +90,10 @@ export function addAlert(
   if (fold === FOLD_SETTING_PKG) {
     const existing = ecoMap.get(pkgName) as ReportLeafNode | undefined
     if (!existing || isStricterPolicy(existing.policy, policyAction)) {
-      ecoMap.set(pkgName, createLeaf(art, alert, policyAction))
+      ecoMap.set(
+        pkgName,
+        createLeaf(art, alert, policyAction, reachability, evidence),
+      )
     }
   } else {
     if (!ecoMap.has(pkgName)) {
@@ -76,7 +103,10 @@ export function addAlert(
     if (fold === FOLD_SETTING_VERSION) {
       const existing = pkgMap.get(version) as ReportLeafNode | undefined
       if (!existing || isStricterPolicy(existing.policy, policyAction)) {
-        pkgMap.set(version, createLeaf(art, alert, policyAction))
+        pkgMap.set(
+          version,
+          createLeaf(art, alert, policyAction, reachability, evidence),
+        )
       }
     } else {
       if (!pkgMap.has(version)) {
@@ -88,7 +118,10 @@ export function addAlert(
       if (fold === FOLD_SETTING_FILE) {
         const existing = verMap.get(file) as ReportLeafNode | undefined
         if (!existing || isStricterPolicy(existing.policy, policyAction)) {
-          verMap.set(file, createLeaf(art, alert, policyAction))
+          verMap.set(
+            file,
+            createLeaf(art, alert, policyAction, reachability, evidence),
+          )
         }
       } else {
         if (!verMap.has(file)) {
@@ -98,7 +131,10 @@ export function addAlert(
         const fileMap: FileMap = verMap.get(file) as FileMap
         const existing = fileMap.get(key) as ReportLeafNode | undefined
         if (!existing || isStricterPolicy(existing.policy, policyAction)) {
-          fileMap.set(key, createLeaf(art, alert, policyAction))
+          fileMap.set(
+            key,
+            createLeaf(art, alert, policyAction, reachability, evidence),
+          )
         }
       }
     }
@@ -107,12 +143,19 @@ export function addAlert(
 
 export function createLeaf(
   art: SocketArtifact,
-  alert: NonNullable<SocketArtifact['alerts']>[number],
+  alert: ArtifactAlert,
   policyAction: REPORT_LEVEL,
+  reachability?: ImportReachability | undefined,
+  evidence?: SecretEvidence | undefined,
 ): ReportLeafNode {
+  const category = getAlertCategory(alert)
   const leaf: ReportLeafNode = {
+    ...(alert.key ? { alertId: alert.key } : {}),
     type: alert.type,
+    ...(category ? { category } : {}),
+    ...(evidence ? { evidence } : {}),
     policy: policyAction,
+    ...(reachability ? { reachability } : {}),
     url: getSocketDevPackageOverviewUrlFromPurl(art),
     manifest: art.manifestFiles?.map((o: { file: string }) => o.file) ?? [],
   }
@@ -125,15 +168,31 @@ export function generateReport(
   scan: SocketArtifact[],
   securityPolicy: SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data'],
   {
+    baseline,
+    evidenceCwd,
+    failOn,
     fold,
+    localPolicy,
     orgSlug,
+    reachability,
     reportLevel,
     scanId,
     short,
     spinner,
   }: {
+    // Alerts recorded in a socket.baseline.json are left out of the report.
+    baseline?: FoundSocketBaseline | undefined
+    // Add the evidence of secret alerts, with snippets read from the
+    // packages installed under this directory. See --show-evidence.
+    evidenceCwd?: string | undefined
+    // Alert threshold that makes the report unhealthy, see isFailOnViolation.
+    failOn?: FAIL_ON | undefined
     fold: FOLD_SETTING
+    // A repo socket.policy.yml whose matching rules override the org policy.
+    localPolicy?: SocketPolicy | undefined
     orgSlug: string
+    // Import reachability by artifact id, added to each reported alert.
+    reachability?: Map<string, ImportReachability> | undefined
     reportLevel: REPORT_LEVEL
     scanId: string
     short?: boolean | undefined
@@ -154,7 +213,7 @@ export function generateReport(
   //           reported issue -> policy action
 
   // In the context of a report;
-  // - the alert.severity is irrelevant
+  // - the alert.severity is irrelevant, unless --fail-on is set
   // - the securityPolicyDefault is irrelevant
   // - the report defaults to healthy:true with no alerts
   // - the appearance of an alert will trigger the policy action;
@@ -162,6 +221,15 @@ export function generateReport(
   //   - warn: healthy unchanged, add alerts to report
   //   - monitor/ignore: no action
   //   - defer: unknown (no action)
+  // - when a local socket.policy.yml rule matches an alert, its action
+  //   replaces the org policy action; a license outside its allow/deny
+  //   lists is reported as an error, as is a distrusted package, and the
+  //   warn alerts of trusted packages are ignored
+  // - alerts recorded in a socket.baseline.json are skipped entirely, so
+  //   only new alerts can make the report unhealthy
+  // - with --fail-on, the alert.severity rather than the error action
+  //   decides whether the report is healthy; which alerts are listed
+  //   still follows the policy action
 
   // Note: the server will emit alerts for license policy violations but
   //       those are only included if you set the flag when requesting the scan
@@ -176,7 +244,8 @@ export function generateReport(
   let healthy = true
 
   const securityRules = securityPolicy.securityPolicyRules
-  if (securityRules) {
+  const policy: ReportPolicy = { baseline, localPolicy, securityRules }
+  if (securityRules || localPolicy) {
     // Note: reportLevel: error > warn > monitor > ignore > defer
     for (let i = 0, { length } = scan; i < length; i += 1) {
       const artifact = scan[i]!
@@ -186,109 +255,58 @@ export function generateReport(
         type: ecosystem,
         version = UNKNOWN_VALUE,
       } = artifact
+      const artifactReachability = artifact.id
+        ? reachability?.get(artifact.id)
+        : undefined
+      const trust = localPolicy && getLocalTrust(localPolicy, artifact)
 
       // oxlint-disable-next-line socket/prefer-cached-for-loop -- call result is consumed (not a standalone statement)
-      alerts?.forEach(
-        (alert: NonNullable<SocketArtifact['alerts']>[number]) => {
-          const alertName = alert.type // => policy[type]
-          const action = (securityRules[alertName]?.action ||
-            '') as REPORT_LEVEL
-          switch (action) {
-            case REPORT_LEVEL_ERROR: {
-              healthy = false
-              if (!short) {
-                addAlert(
-                  artifact,
-                  violations,
-                  fold,
-                  ecosystem,
-                  pkgName,
-                  version,
-                  alert,
-                  action,
-                )
-              }
-              break
-            }
-            case REPORT_LEVEL_WARN: {
-              if (!short && reportLevel !== REPORT_LEVEL_ERROR) {
-                addAlert(
-                  artifact,
-                  violations,
-                  fold,
-                  ecosystem,
-                  pkgName,
-                  version,
-                  alert,
-                  action,
-                )
-              }
-              break
-            }
-            case REPORT_LEVEL_MONITOR: {
-              if (
-                !short &&
-                reportLevel !== REPORT_LEVEL_WARN &&
-                reportLevel !== REPORT_LEVEL_ERROR
-              ) {
-                addAlert(
-                  artifact,
-                  violations,
-                  fold,
-                  ecosystem,
-                  pkgName,
-                  version,
-                  alert,
-                  action,
-                )
-              }
-              break
-            }
-
-            case REPORT_LEVEL_IGNORE: {
-              if (
-                !short &&
-                reportLevel !== REPORT_LEVEL_MONITOR &&
-                reportLevel !== REPORT_LEVEL_WARN &&
-                reportLevel !== REPORT_LEVEL_ERROR
-              ) {
-                addAlert(
-                  artifact,
-                  violations,
-                  fold,
-                  ecosystem,
-                  pkgName,
-                  version,
-                  alert,
-                  action,
-                )
-              }
-              break
-            }
-
-            case REPORT_LEVEL_DEFER: {
-              // Not sure but ignore for now. Defer to later ;)
-              if (!short && reportLevel === REPORT_LEVEL_DEFER) {
-                addAlert(
-                  artifact,
-                  violations,
-                  fold,
-                  ecosystem,
-                  pkgName,
-                  version,
-                  alert,
-                  action,
-                )
-              }
-              break
-            }
+      alerts?.forEach((alert: ArtifactAlert) => {
+        const action = getAlertAction(policy, artifact, alert, trust)
+        if (action === undefined) {
+          return
+        }
+        if (isFailOnViolation(failOn, action, alert.severity)) {
+          healthy = false
+        }
+        if (!short && isReportedAction(action, reportLevel)) {
+          addAlert(
+            artifact,
+            violations,
+            fold,
+            ecosystem,
+            pkgName,
+            version,
+            alert,
+            action,
+            artifactReachability,
+            evidenceCwd
+              ? getSecretEvidence(artifact, alert, evidenceCwd)
+              : undefined,
+          )
+        }
+      })
 
-            default: {
-              // This value was not emitted from the Socket API at the time of writing.
-            }
-          }
-        },
-      )
+      const localAlerts = getLocalPolicyAlerts(policy, artifact, trust)
+      for (let j = 0, { length: count } = localAlerts; j < count; j += 1) {
+        const alert = localAlerts[j]!
+        if (isFailOnViolation(failOn, REPORT_LEVEL_ERROR, alert.severity)) {
+          healthy = false
+        }
+        if (!short) {
+          addAlert(
+            artifact,
+            violations,
+            fold,
+            ecosystem,
+            pkgName,
+            version,
+            alert,
+            REPORT_LEVEL_ERROR,
+            artifactReachability,
+          )
+        }
+      }
     }
   }
 
@@ -305,15 +323,16 @@ export function generateReport(
     healthy,
     orgSlug,
     scanId,
-    options: { fold, reportLevel },
+    options: { ...(failOn ? { failOn } : {}), fold, reportLevel },
     alerts: violations,
   }
 
   if (!healthy) {
     return {
       ok: true,
-      message:
-        'The report contains at least one alert that violates the policies set by your organization',
+      message: failOn
+        ? `The report contains at least one alert that meets --fail-on ${failOn}`
+        : 'The report contains at least one alert that violates the policies set by your organization',
       data: report,
     }
   }
@@ -0,0 +1,434 @@
+/**
+ * SARIF 2.1.0 rendering of a Socket scan, for upload to GitHub Code Scanning
+ * and other SARIF consumers.
+ *
+ * Mapping:
+ *
+ * - Every distinct alert type becomes one `rule`. The rule carries the
+ *   human-readable title/description from `data/alert-translations.json` and a
+ *   `security-severity` derived from the alert severity so GitHub can bucket
+ *   it into critical/high/medium/low.
+ * - Every (package, alert) pair whose policy action meets the report level
+ *   becomes one `result`. The SARIF `level` comes from the org policy action,
+ *   not the alert severity, so `error` results are exactly the ones that make
+ *   `socket scan report` unhealthy. A matching local `socket.policy.yml` rule
+ *   overrides the org action, and local license violations become
+ *   `licensePolicyViolation` errors, distrusted packages `distrustedPackage`
+ *   errors. Trusted packages leave out their `warn` results. Alerts in a
+ *   `socket.baseline.json` are left out.
+ * - Result locations point at the manifest file(s) that introduced the package.
+ *   When the manifest can be read locally the artifact's manifest offsets (or,
+ *   failing that, the first mention of the package name) are resolved to a
+ *   line/column region. Packages without a manifest point at the scanned
+ *   manifest, or at the repository root when the scan names none.
+ */
+
+import { UNKNOWN_VALUE } from '@socketsecurity/lib-stable/constants/sentinels'
+
+import alertTranslations from '../../../data/alert-translations.json' with { type: 'json' }
+import {
+  REPORT_LEVEL_DEFER,
+  REPORT_LEVEL_ERROR,
+  REPORT_LEVEL_IGNORE,
+  REPORT_LEVEL_MONITOR,
+  REPORT_LEVEL_WARN,
+} from '../../constants/reporting.mts'
+import { SOCKET_WEBSITE_URL } from '../../constants/socket.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { isBaselinedAlert } from '../../util/policy/baseline.mts'
+import {
+  applyLocalTrust,
+  getLocalLicenseViolation,
+  getLocalPolicyAction,
+  getLocalTrust,
+  LOCAL_DISTRUST_POLICY_ALERT,
+  LOCAL_LICENSE_POLICY_ALERT,
+} from '../../util/policy/evaluate.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+import {
+  getSocketDevAlertUrl,
+  getSocketDevPackageOverviewUrlFromPurl,
+} from '../../util/socket/url.mts'
+
+import type { REPORT_LEVEL } from './types.mts'
+import type {
+  SocketArtifact,
+  SocketArtifactAlert,
+} from '../../util/alert/artifact.mts'
+import type { FoundSocketBaseline } from '../../util/policy/baseline.mts'
+import type { SocketPolicy } from '../../util/policy/socket-policy.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+export const SARIF_SCHEMA_URL = 'https://json.schemastore.org/sarif-2.1.0.json'
+
+export const SARIF_VERSION = '2.1.0'
+
+// The location of results the scan attributes to no file at all.
+export const SARIF_ROOT_URI = '.'
+
+export type SarifLevel = 'error' | 'warning' | 'note' | 'none'
+
+export type SarifRegion = {
+  startLine: number
+  startColumn: number
+}
+
+export type SarifLocation = {
+  physicalLocation: {
+    artifactLocation: { uri: string }
+    region?: SarifRegion | undefined
+  }
+}
+
+export type SarifRule = {
+  id: string
+  name: string
+  shortDescription: { text: string }
+  fullDescription: { text: string }
+  help: { text: string }
+  helpUri: string
+  defaultConfiguration: { level: SarifLevel }
+  properties: {
+    'security-severity': string
+    tags: string[]
+  }
+}
+
+export type SarifResult = {
+  ruleId: string
+  ruleIndex: number
+  level: SarifLevel
+  message: { text: string }
+  locations: SarifLocation[]
+  partialFingerprints: { socketAlertKey: string }
+  properties: {
+    purl: string
+    policy: REPORT_LEVEL
+    severity: string
+    url: string
+  }
+}
+
+export type SarifLog = {
+  $schema: string
+  version: string
+  runs: Array<{
+    tool: {
+      driver: {
+        name: string
+        informationUri: string
+        version: string
+        rules: SarifRule[]
+      }
+    }
+    results: SarifResult[]
+  }>
+}
+
+export type GenerateSarifReportOptions = {
+  // Alerts recorded in a socket.baseline.json produce no results.
+  baseline?: FoundSocketBaseline | undefined
+  // A repo socket.policy.yml whose matching rules override the org policy.
+  localPolicy?: SocketPolicy | undefined
+  reportLevel: REPORT_LEVEL
+  // Returns the manifest contents for a scan-relative path, or undefined when
+  // the file is not available locally. Used to resolve line numbers.
+  readManifest?: ((file: string) => string | undefined) | undefined
+}
+
+export type AlertTranslation = {
+  description?: string | undefined
+  emoji?: string | undefined
+  suggestion?: string | undefined
+  title?: string | undefined
+}
+
+const POLICY_RANK = {
+  __proto__: null,
+  [REPORT_LEVEL_DEFER]: 0,
+  [REPORT_LEVEL_IGNORE]: 1,
+  [REPORT_LEVEL_MONITOR]: 2,
+  [REPORT_LEVEL_WARN]: 3,
+  [REPORT_LEVEL_ERROR]: 4,
+} as unknown as Record<string, number | undefined>
+
+// GitHub Code Scanning buckets results by this numeric score:
+// >= 9.0 critical, >= 7.0 high, >= 4.0 medium, otherwise low.
+const SEVERITY_SCORE = {
+  __proto__: null,
+  critical: '9.5',
+  high: '8.0',
+  middle: '5.5',
+  low: '2.0',
+} as unknown as Record<string, string | undefined>
+
+export function getAlertTranslation(type: string): AlertTranslation {
+  const translations = alertTranslations.alerts as Record<
+    string,
+    AlertTranslation | undefined
+  >
+  return translations[type] ?? {}
+}
+
+/**
+ * Whether an alert with the given policy action is included at the given
+ * report level. Mirrors the level filtering of `generateReport`.
+ */
+export function isReportedPolicyAction(
+  action: string,
+  reportLevel: REPORT_LEVEL,
+): boolean {
+  const actionRank = POLICY_RANK[action]
+  if (actionRank === undefined) {
+    return false
+  }
+  if (action === REPORT_LEVEL_ERROR) {
+    return true
+  }
+  return actionRank >= (POLICY_RANK[reportLevel] ?? 0)
+}
+
+/**
+ * Convert a zero-based character offset into a one-based SARIF region.
+ */
+export function offsetToRegion(content: string, offset: number): SarifRegion {
+  const clamped = Math.max(0, Math.min(offset, content.length))
+  let startLine = 1
+  let lineStart = 0
+  for (let i = 0; i < clamped; i += 1) {
+    if (content.charCodeAt(i) === 10 /*\n*/) {
+      startLine += 1
+      lineStart = i + 1
+    }
+  }
+  return { startLine, startColumn: clamped - lineStart + 1 }
+}
+
+/**
+ * Locate the dependency within a manifest. Prefers the offset reported by the
+ * API and falls back to the first quoted (or bare) mention of the package name.
+ */
+export function findDependencyRegion(
+  content: string,
+  pkgName: string,
+  start?: number | undefined,
+): SarifRegion | undefined {
+  if (typeof start === 'number' && start >= 0 && start <= content.length) {
+    return offsetToRegion(content, start)
+  }
+  if (!pkgName || pkgName === UNKNOWN_VALUE) {
+    return undefined
+  }
+  let index = content.indexOf(`"${pkgName}"`)
+  if (index === -1) {
+    index = content.indexOf(pkgName)
+  }
+  return index === -1 ? undefined : offsetToRegion(content, index)
+}
+
+export function policyActionToSarifLevel(action: REPORT_LEVEL): SarifLevel {
+  switch (action) {
+    case REPORT_LEVEL_ERROR:
+      return 'error'
+    case REPORT_LEVEL_WARN:
+      return 'warning'
+    case REPORT_LEVEL_MONITOR:
+    case REPORT_LEVEL_IGNORE:
+      return 'note'
+    default:
+      return 'none'
+  }
+}
+
+function getArtifactLocations(
+  artifact: SocketArtifact,
+  pkgName: string,
+  readManifest: GenerateSarifReportOptions['readManifest'],
+): SarifLocation[] {
+  const manifestFiles = (artifact.manifestFiles ?? []) as Array<{
+    file: string
+    start?: number | undefined
+  }>
+  const locations: SarifLocation[] = []
+  for (let i = 0, { length } = manifestFiles; i < length; i += 1) {
+    const { file, start } = manifestFiles[i]!
+    if (!file) {
+      continue
+    }
+    const content = readManifest?.(file)
+    const region =
+      content === undefined
+        ? undefined
+        : findDependencyRegion(content, pkgName, start)
+    locations.push({
+      physicalLocation: {
+        artifactLocation: { uri: file.replaceAll('\\', '/') },
+        ...(region ? { region } : {}),
+      },
+    })
+  }
+  return locations
+}
+
+/**
+ * The location of results whose package names no manifest. GitHub Code
+ * Scanning requires one for every result, so this is the first manifest of
+ * the scan, or the repository root.
+ */
+function getScanLocation(scan: SocketArtifact[]): SarifLocation {
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const manifestFiles = (scan[i]!.manifestFiles ?? []) as Array<{
+      file: string
+    }>
+    const file = manifestFiles.find(m => m.file)?.file
+    if (file) {
+      return {
+        physicalLocation: {
+          artifactLocation: { uri: file.replaceAll('\\', '/') },
+        },
+      }
+    }
+  }
+  return { physicalLocation: { artifactLocation: { uri: SARIF_ROOT_URI } } }
+}
+
+function createRule(alert: SocketArtifactAlert): SarifRule {
+  const { description, suggestion, title } = getAlertTranslation(alert.type)
+  const name = title || alert.type
+  const helpUri = getSocketDevAlertUrl(alert.type)
+  return {
+    id: alert.type,
+    name,
+    shortDescription: { text: name },
+    fullDescription: { text: description || name },
+    help: { text: suggestion || `See ${helpUri}` },
+    helpUri,
+    defaultConfiguration: { level: 'warning' },
+    properties: {
+      'security-severity': SEVERITY_SCORE[alert.severity ?? ''] ?? '2.0',
+      tags: [
+        'security',
+        'supply-chain',
+        ...(alert.category ? [alert.category] : []),
+      ],
+    },
+  }
+}
+
+export function generateSarifReport(
+  scan: SocketArtifact[],
+  securityPolicy: SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data'],
+  {
+    baseline,
+    localPolicy,
+    readManifest,
+    reportLevel,
+  }: GenerateSarifReportOptions,
+): SarifLog {
+  const rules: SarifRule[] = []
+  const ruleIndexes = new Map<string, number>()
+  const results: SarifResult[] = []
+  const securityRules = securityPolicy.securityPolicyRules ?? {}
+  let scanLocation: SarifLocation | undefined
+
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    const licenseViolation =
+      localPolicy && getLocalLicenseViolation(localPolicy, artifact)
+    const trust = localPolicy && getLocalTrust(localPolicy, artifact)
+    const alerts: SocketArtifactAlert[] = [
+      ...(artifact.alerts ?? []),
+      ...(licenseViolation
+        ? [
+            {
+              type: LOCAL_LICENSE_POLICY_ALERT,
+              severity: 'high',
+            } as SocketArtifactAlert,
+          ]
+        : []),
+      ...(trust === 'distrusted'
+        ? [
+            {
+              type: LOCAL_DISTRUST_POLICY_ALERT,
+              severity: 'critical',
+            } as SocketArtifactAlert,
+          ]
+        : []),
+    ]
+    if (!alerts.length) {
+      continue
+    }
+    const { name: pkgName = UNKNOWN_VALUE, version = UNKNOWN_VALUE } =
+      artifact
+    const locations = getArtifactLocations(artifact, pkgName, readManifest)
+    if (!locations.length) {
+      scanLocation ??= getScanLocation(scan)
+      locations.push(scanLocation)
+    }
+    const purl = getArtifactPurlString(artifact)
+    const url = getSocketDevPackageOverviewUrlFromPurl(artifact)
+
+    for (let j = 0, { length: alertCount } = alerts; j < alertCount; j += 1) {
+      const alert = alerts[j]!
+      if (baseline && isBaselinedAlert(baseline, artifact, alert.type)) {
+        continue
+      }
+      const localAction =
+        alert.type === LOCAL_LICENSE_POLICY_ALERT ||
+        alert.type === LOCAL_DISTRUST_POLICY_ALERT
+          ? REPORT_LEVEL_ERROR
+          : localPolicy && getLocalPolicyAction(localPolicy, artifact, alert)
+      const action = applyLocalTrust(
+        localAction || securityRules[alert.type]?.action || '',
+        trust,
+      ) as REPORT_LEVEL
+      if (!isReportedPolicyAction(action, reportLevel)) {
+        continue
+      }
+      let ruleIndex = ruleIndexes.get(alert.type)
+      if (ruleIndex === undefined) {
+        ruleIndex = rules.length
+        ruleIndexes.set(alert.type, ruleIndex)
+        rules.push(createRule(alert))
+      }
+      const { title } = getAlertTranslation(alert.type)
+      const where = alert.file ? ` (in ${alert.file})` : ''
+      results.push({
+        ruleId: alert.type,
+        ruleIndex,
+        level: policyActionToSarifLevel(action),
+        message: {
+          text: `${title || alert.type}: ${pkgName}@${version}${where}`,
+        },
+        locations,
+        partialFingerprints: {
+          socketAlertKey: alert.key || `${purl}:${alert.type}`,
+        },
+        properties: {
+          purl,
+          policy: action,
+          severity: alert.severity ?? UNKNOWN_VALUE,
+          url,
+        },
+      })
+    }
+  }
+
+  return {
+    $schema: SARIF_SCHEMA_URL,
+    version: SARIF_VERSION,
+    runs: [
+      {
+        tool: {
+          driver: {
+            name: 'Socket',
+            informationUri: SOCKET_WEBSITE_URL,
+            version: getCliVersion() || '0.0.0',
+            rules,
+          },
+        },
+        results,
+      },
+    ],
+  }
+}
@@ -0,0 +1,83 @@
+/**
+ * Template context of `socket scan report --output-template`.
+ *
+ * Alerts come from the SARIF rendering, so they carry the same manifest
+ * locations, baseline filtering and report level as the other formats. The
+ * report is unhealthy when any alert is at the `error` level, matching the
+ * exit code.
+ */
+
+import type { SarifLevel, SarifLog } from './generate-sarif-report.mts'
+import type { REPORT_LEVEL } from './types.mts'
+
+export type ScanReportTemplateAlert = {
+  description: string
+  fix: string
+  level: SarifLevel
+  line?: number | undefined
+  manifest: string
+  message: string
+  policy: REPORT_LEVEL
+  purl: string
+  severity: string
+  title: string
+  type: string
+  url: string
+}
+
+export type ScanReportTemplateContext = {
+  alerts: ScanReportTemplateAlert[]
+  counts: Record<REPORT_LEVEL | 'total', number>
+  healthy: boolean
+  orgSlug: string
+  reportLevel: REPORT_LEVEL
+  scanId: string
+}
+
+export function generateScanReportTemplateContext(
+  sarif: SarifLog,
+  {
+    orgSlug,
+    reportLevel,
+    scanId,
+  }: { orgSlug: string; reportLevel: REPORT_LEVEL; scanId: string },
+): ScanReportTemplateContext {
+  const run = sarif.runs[0]
+  const rules = run?.tool.driver.rules ?? []
+  const results = run?.results ?? []
+  const counts: ScanReportTemplateContext['counts'] = {
+    defer: 0,
+    error: 0,
+    ignore: 0,
+    monitor: 0,
+    warn: 0,
+    total: results.length,
+  }
+  const alerts = results.map(result => {
+    const rule = rules[result.ruleIndex]
+    const physical = result.locations[0]?.physicalLocation
+    counts[result.properties.policy] += 1
+    return {
+      description: rule?.fullDescription.text ?? '',
+      fix: rule?.help.text ?? '',
+      level: result.level,
+      line: physical?.region?.startLine,
+      manifest: physical?.artifactLocation.uri ?? '',
+      message: result.message.text,
+      policy: result.properties.policy,
+      purl: result.properties.purl,
+      severity: result.properties.severity,
+      title: rule?.shortDescription.text ?? result.ruleId,
+      type: result.ruleId,
+      url: result.properties.url,
+    }
+  })
+  return {
+    alerts,
+    counts,
+    healthy: !results.some(r => r.level === 'error'),
+    orgSlug,
+    reportLevel,
+    scanId,
+  }
+}
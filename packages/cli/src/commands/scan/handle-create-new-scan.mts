@@ -9,6 +9,7 @@ import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
 
 const logger = getDefaultLogger()
 
+import { awaitScanAnalysis } from './await-scan.mts'
 import { applyFullExcludePaths } from './exclude-paths.mts'
 import { fetchCreateOrgFullScan } from './fetch-create-org-full-scan.mts'
 import { fetchSupportedScanFileNames } from './fetch-supported-scan-file-names.mts'
@@ -16,6 +17,9 @@ import { finalizeTier1Scan } from './finalize-tier1-scan.mts'
 import { handleScanReport } from './handle-scan-report.mts'
 import { outputCreateNewScan } from './output-create-new-scan.mts'
 import { performReachabilityAnalysis } from './perform-reachability-analysis.mts'
+import { prepareScanUpload } from './prepare-scan-upload.mts'
+import { notifyCreatedScan } from './scan-notify.mts'
+import { resolveWorkspaceSelection } from './scan-workspaces.mts'
 import {
   DOT_SOCKET_DOT_FACTS_JSON,
   FOLD_SETTING_VERSION,
@@ -34,17 +38,23 @@ import { runSocketBasics } from '../../util/basics/spawn.mts'
 export function excludeFactsJson(paths: string[]): string[] {
   return paths.filter(p => path.basename(p) !== DOT_SOCKET_DOT_FACTS_JSON)
 }
-import { compressSocketFactsForUpload } from '../../util/coana/compress-facts.mts'
+import { recordCompletionValues } from '../../util/cli/completion-history.mts'
 import { findSocketYmlSync } from '../../util/config.mts'
 import { getPackageFilesForScan } from '../../util/fs/path-resolve.mts'
+import { getMonorepoWorkspaceTargets } from '../../util/fs/workspaces.mts'
+import { recordScanHistory } from '../../util/history/store.mts'
+import { startProgress } from '../../util/output/progress.mts'
 import { readOrDefaultSocketJson } from '../../util/socket/json.mts'
 import { socketDocsLink } from '../../util/terminal/link.mts'
 import { checkCommandInput } from '../../util/validation/check-input.mts'
 import { detectManifestActions } from '../manifest/detect-manifest-actions.mts'
 import { generateAutoManifest } from '../manifest/generate_auto_manifest.mts'
 
+import type { ScanAnalysisSummary, ScanWaitOptions } from './await-scan.mts'
 import type { ReachabilityOptions } from './perform-reachability-analysis.mts'
-import type { REPORT_LEVEL } from './types.mts'
+import type { ScanNotifyOptions } from './scan-notify.mts'
+import type { WorkspaceSelectionOptions } from './scan-workspaces.mts'
+import type { FAIL_ON, REPORT_FORMAT, REPORT_LEVEL } from './types.mts'
 import type { OutputKind } from '../../types.mts'
 import type { Remap } from '@socketsecurity/lib-stable/objects/types'
 
@@ -57,7 +67,14 @@ export type HandleCreateNewScanConfig = {
   committers: string
   cwd: string
   defaultBranch: boolean
+  // Alert severity that fails the --report instead of the policy error level.
+  failOn?: FAIL_ON | undefined
   interactive: boolean
+  // Post a summary of the new scan to a chat webhook.
+  notify?: ScanNotifyOptions | undefined
+  // Leave out alerts of packages the sources cannot reach in the --report
+  // output.
+  onlyReachable?: boolean | undefined
   orgSlug: string
   pendingHead: boolean
   pullRequest: number
@@ -70,10 +87,16 @@ export type HandleCreateNewScanConfig = {
   readOnly: boolean
   repoName: string
   report: boolean
+  reportFormat?: REPORT_FORMAT | undefined
   reportLevel: REPORT_LEVEL
   targets: string[]
   tmp: boolean
+  // Wait for the analysis of the new scan before printing the result.
+  wait?: ScanWaitOptions | undefined
   workspace?: string | undefined
+  // Scan only the selected monorepo workspaces instead of the targets, and
+  // report each on its own with --report.
+  workspaces?: WorkspaceSelectionOptions | undefined
 }
 
 export async function handleCreateNewScan({
@@ -85,7 +108,10 @@ export async function handleCreateNewScan({
   committers,
   cwd,
   defaultBranch,
+  failOn,
   interactive,
+  notify,
+  onlyReachable,
   orgSlug,
   outputKind,
   pendingHead,
@@ -94,10 +120,13 @@ export async function handleCreateNewScan({
   readOnly,
   repoName,
   report,
+  reportFormat,
   reportLevel,
   targets,
   tmp,
+  wait,
   workspace,
+  workspaces,
 }: HandleCreateNewScanConfig): Promise<void> {
   debugNs(
     'notice',
@@ -154,7 +183,10 @@ export async function handleCreateNewScan({
     `Fetched supported file types for ${Object.keys(supportedFilesCResult.data).length} ecosystems`,
   )
 
-  spinner.start('Searching for local files to include in scan…')
+  const searchProgress = startProgress(
+    'Searching for local files to include in scan',
+    { spinner },
+  )
 
   const supportedFiles = supportedFilesCResult.data
 
@@ -172,12 +204,37 @@ export async function handleCreateNewScan({
       target: targets[0]!,
     })
 
-  const packagePaths = await getPackageFilesForScan(targets, supportedFiles, {
-    config: effectiveSocketConfig,
-    cwd,
-  })
+  let scanTargets = targets
+  if (workspaces?.changedSince || workspaces?.workspaceFilter?.length) {
+    const selectionCResult = await resolveWorkspaceSelection(cwd, workspaces)
+    if (!selectionCResult.ok) {
+      searchProgress.stop()
+      await outputCreateNewScan(selectionCResult, { interactive, outputKind })
+      return
+    }
+    const { selected } = selectionCResult.data
+    if (!selected.length) {
+      searchProgress.stop()
+      logger.info(
+        `No workspace changed since ${workspaces.changedSince}, nothing to scan`,
+      )
+      return
+    }
+    debugDir('inspect', { workspaces: selected })
+    scanTargets = getMonorepoWorkspaceTargets(cwd, selected)
+  }
 
-  spinner.successAndStop(
+  const packagePaths = await getPackageFilesForScan(
+    scanTargets,
+    supportedFiles,
+    {
+      config: effectiveSocketConfig,
+      cwd,
+      localLockfiles: true,
+    },
+  )
+
+  searchProgress.succeed(
     `Found ${packagePaths.length} ${pluralize('file', { count: packagePaths.length })} to include in scan.`,
   )
 
@@ -200,6 +257,9 @@ export async function handleCreateNewScan({
   debugDir('inspect', { packagePaths })
 
   if (readOnly) {
+    for (const packagePath of packagePaths) {
+      logger.log(path.relative(cwd, packagePath))
+    }
     logger.log('[ReadOnly] Bailing now')
     debugNs('notice', 'Read-only mode, exiting early')
     return
@@ -308,17 +368,11 @@ export async function handleCreateNewScan({
     }
   }
 
-  // Brotli-compress any .socket.facts.json paths in scanPaths just before
-  // upload. depscan's api-v0 multipart boundary streams brotli decode based
-  // on the .br filename suffix. Coana keeps writing plain .socket.facts.json
-  // on disk, so the local read path (extractTier1ReachabilityScanId) stays
-  // correct. The cleanup() in the finally block removes the sibling .br
-  // files whether the upload succeeded or threw.
-  const compressed = await compressSocketFactsForUpload(scanPaths)
+  const upload = await prepareScanUpload(scanPaths, { cwd, supportedFiles })
   let fullScanCResult: Awaited<ReturnType<typeof fetchCreateOrgFullScan>>
   try {
     fullScanCResult = await fetchCreateOrgFullScan(
-      compressed.paths,
+      upload.paths,
       orgSlug,
       {
         commitHash,
@@ -340,32 +394,71 @@ export async function handleCreateNewScan({
       },
     )
   } finally {
-    await compressed.cleanup()
+    await upload.cleanup()
   }
 
   const scanId = fullScanCResult.ok ? fullScanCResult.data?.id : undefined
+  await recordCompletionValues('scan', [scanId])
+  if (scanId) {
+    await recordScanHistory({
+      branch: branchName,
+      orgSlug,
+      repo: repoName,
+      scanId,
+    })
+  }
 
   if (reach && scanId && tier1ReachabilityScanId) {
     await finalizeTier1Scan(tier1ReachabilityScanId, scanId)
   }
 
+  if (notify && scanId) {
+    await notifyCreatedScan(notify, {
+      branchName,
+      orgSlug,
+      repoName,
+      reportUrl: fullScanCResult.ok
+        ? fullScanCResult.data?.html_report_url
+        : undefined,
+      scanId,
+    })
+  }
+
   if (fullScanCResult.ok && reachabilityReport) {
     // The facts file is an upload artifact, not user-facing output — remove
     // it once the scan is submitted so it doesn't linger in the project.
     await safeDelete(path.resolve(cwd, reachabilityReport), { force: true })
   }
 
+  // With --wait the scan is only printed once its analysis is done.
+  let analysis: ScanAnalysisSummary | undefined
+  if (wait && scanId) {
+    spinner.stop()
+    const awaitCResult = await awaitScanAnalysis(orgSlug, scanId, wait)
+    if (!awaitCResult.ok) {
+      await outputCreateNewScan(awaitCResult, { interactive, outputKind })
+      return
+    }
+    analysis = awaitCResult.data
+  }
+
   if (report && fullScanCResult.ok) {
     if (scanId) {
       await handleScanReport({
+        cwd,
         filepath: '-',
         fold: FOLD_SETTING_VERSION,
+        failOn,
+        format: reportFormat,
+        importReachability: reach.runReachabilityAnalysis,
         includeLicensePolicy: true,
+        onlyReachable,
         orgSlug,
         outputKind,
         reportLevel,
         scanId,
         short: false,
+        workspaces,
       })
     } else {
       await outputCreateNewScan(
@@ -384,6 +477,10 @@ export async function handleCreateNewScan({
   } else {
     spinner.stop()
 
-    await outputCreateNewScan(fullScanCResult, { interactive, outputKind })
+    await outputCreateNewScan(fullScanCResult, {
+      analysis,
+      interactive,
+      outputKind,
+    })
   }
 }
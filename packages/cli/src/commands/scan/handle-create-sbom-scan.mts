@@ -0,0 +1,205 @@
+import { debugDir, debugNs } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { awaitScanAnalysis } from './await-scan.mts'
+import { fetchCreateOrgFullScan } from './fetch-create-org-full-scan.mts'
+import { handleScanReport } from './handle-scan-report.mts'
+import { outputCreateNewScan } from './output-create-new-scan.mts'
+import { notifyCreatedScan } from './scan-notify.mts'
+import { FOLD_SETTING_VERSION, SCAN_TYPE_SOCKET } from '../../constants.mts'
+import { recordCompletionValues } from '../../util/cli/completion-history.mts'
+import { recordScanHistory } from '../../util/history/store.mts'
+import { prepareSbomForUpload } from '../../util/lockfile/sbom-import.mts'
+
+import type { ScanAnalysisSummary, ScanWaitOptions } from './await-scan.mts'
+import type { ScanNotifyOptions } from './scan-notify.mts'
+import type { FAIL_ON, REPORT_FORMAT, REPORT_LEVEL } from './types.mts'
+import type { OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export type HandleCreateSbomScanConfig = {
+  branchName: string
+  commitHash: string
+  commitMessage: string
+  committers: string
+  cwd: string
+  defaultBranch: boolean
+  // Alert severity that fails the --report instead of the policy error level.
+  failOn?: FAIL_ON | undefined
+  interactive: boolean
+  // Post a summary of the new scan to a chat webhook.
+  notify?: ScanNotifyOptions | undefined
+  orgSlug: string
+  outputKind: OutputKind
+  pendingHead: boolean
+  pullRequest: number
+  readOnly: boolean
+  repoName: string
+  report: boolean
+  reportFormat?: REPORT_FORMAT | undefined
+  reportLevel: REPORT_LEVEL
+  // CycloneDX or SPDX JSON document to take the components from.
+  sbomPath: string
+  tmp: boolean
+  // Wait for the analysis of the new scan before printing the result.
+  wait?: ScanWaitOptions | undefined
+  workspace?: string | undefined
+}
+
+export async function handleCreateSbomScan({
+  branchName,
+  commitHash,
+  commitMessage,
+  committers,
+  cwd,
+  defaultBranch,
+  failOn,
+  interactive,
+  notify,
+  orgSlug,
+  outputKind,
+  pendingHead,
+  pullRequest,
+  readOnly,
+  repoName,
+  report,
+  reportFormat,
+  reportLevel,
+  sbomPath,
+  tmp,
+  wait,
+  workspace,
+}: HandleCreateSbomScanConfig): Promise<void> {
+  debugNs(
+    'notice',
+    `Creating new scan for ${orgSlug}/${workspace ? `${workspace}/` : ''}${repoName} from ${sbomPath}`,
+  )
+
+  const spinner = getDefaultSpinner()
+
+  const sbomScanCResult = await prepareSbomForUpload(sbomPath, { cwd })
+  if (!sbomScanCResult.ok) {
+    await outputCreateNewScan(sbomScanCResult, { interactive, outputKind })
+    return
+  }
+  const sbomScan = sbomScanCResult.data
+  debugDir('inspect', {
+    ecosystems: sbomScan.lockfiles.map(l => l.ecosystem),
+    format: sbomScan.format,
+    skipped: sbomScan.skipped,
+  })
+
+  logger.success(
+    `Importing ${sbomScan.components} ${pluralize('component', { count: sbomScan.components })} from ${sbomPath}`,
+  )
+  if (sbomScan.skipped) {
+    logger.warn(
+      `Skipped ${sbomScan.skipped} ${pluralize('component', { count: sbomScan.skipped })} without a purl of a supported ecosystem`,
+    )
+  }
+
+  if (readOnly) {
+    await sbomScan.cleanup()
+    logger.log('[ReadOnly] Bailing now')
+    return
+  }
+
+  let fullScanCResult: Awaited<ReturnType<typeof fetchCreateOrgFullScan>>
+  try {
+    fullScanCResult = await fetchCreateOrgFullScan(
+      [sbomScan.path],
+      orgSlug,
+      {
+        commitHash,
+        commitMessage,
+        committers,
+        pullRequest,
+        repoName,
+        branchName,
+        scanType: SCAN_TYPE_SOCKET,
+        workspace,
+      },
+      {
+        cwd,
+        defaultBranch,
+        pendingHead,
+        tmp,
+      },
+    )
+  } finally {
+    await sbomScan.cleanup()
+  }
+
+  const scanId = fullScanCResult.ok ? fullScanCResult.data?.id : undefined
+  await recordCompletionValues('scan', [scanId])
+  if (scanId) {
+    await recordScanHistory({
+      branch: branchName,
+      orgSlug,
+      repo: repoName,
+      scanId,
+    })
+  }
+
+  if (notify && scanId) {
+    await notifyCreatedScan(notify, {
+      branchName,
+      orgSlug,
+      repoName,
+      reportUrl: fullScanCResult.ok
+        ? fullScanCResult.data?.html_report_url
+        : undefined,
+      scanId,
+    })
+  }
+
+  // With --wait the scan is only printed once its analysis is done.
+  let analysis: ScanAnalysisSummary | undefined
+  if (wait && scanId) {
+    spinner.stop()
+    const awaitCResult = await awaitScanAnalysis(orgSlug, scanId, wait)
+    if (!awaitCResult.ok) {
+      await outputCreateNewScan(awaitCResult, { interactive, outputKind })
+      return
+    }
+    analysis = awaitCResult.data
+  }
+
+  if (!report || !fullScanCResult.ok) {
+    spinner.stop()
+    await outputCreateNewScan(fullScanCResult, {
+      analysis,
+      interactive,
+      outputKind,
+    })
+    return
+  }
+  if (!scanId) {
+    await outputCreateNewScan(
+      {
+        ok: false,
+        message: 'Missing Scan ID',
+        cause: 'Server did not respond with a scan ID',
+        data: fullScanCResult.data,
+      },
+      { interactive, outputKind },
+    )
+    return
+  }
+  await handleScanReport({
+    cwd,
+    filepath: '-',
+    fold: FOLD_SETTING_VERSION,
+    failOn,
+    format: reportFormat,
+    includeLicensePolicy: true,
+    orgSlug,
+    outputKind,
+    reportLevel,
+    scanId,
+    short: false,
+  })
+}
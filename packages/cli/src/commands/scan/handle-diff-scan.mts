@@ -1,9 +1,41 @@
 import { fetchDiffScan } from './fetch-diff-scan.mts'
+import { fetchScanAlertDiff } from './fetch-scan-alert-diff.mts'
+import { filterScanArtifacts } from './filter-scan-alerts.mts'
 import { outputDiffScan } from './output-diff-scan.mts'
 
-import type { OutputKind } from '../../types.mts'
+import type { ScanAlertFilter } from './filter-scan-alerts.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+type DiffScanData = SocketSdkSuccessResult<'GetOrgDiffScan'>['data']
+
+/**
+ * The package changes of the diff, with the packages the filter leaves out
+ * removed from every group.
+ */
+export function filterDiffScanArtifacts(
+  data: DiffScanData,
+  alertFilter: ScanAlertFilter,
+): DiffScanData {
+  const { added, removed, replaced, unchanged, updated } = data.artifacts
+  return {
+    ...data,
+    artifacts: {
+      ...data.artifacts,
+      added: filterScanArtifacts(added, alertFilter),
+      removed: filterScanArtifacts(removed, alertFilter),
+      replaced: filterScanArtifacts(replaced, alertFilter),
+      ...(unchanged
+        ? { unchanged: filterScanArtifacts(unchanged, alertFilter) }
+        : {}),
+      updated: filterScanArtifacts(updated, alertFilter),
+    },
+  }
+}
 
 export async function handleDiffScan({
+  alertFilter,
+  alerts,
   depth,
   file,
   id1,
@@ -11,6 +43,8 @@ export async function handleDiffScan({
   orgSlug,
   outputKind,
 }: {
+  alertFilter?: ScanAlertFilter | undefined
+  alerts?: boolean | undefined
   depth: number
   file: string
   id1: string
@@ -18,13 +52,22 @@ export async function handleDiffScan({
   orgSlug: string
   outputKind: OutputKind
 }): Promise<void> {
-  const data = await fetchDiffScan({
+  let data: CResult<DiffScanData> = await fetchDiffScan({
     id1,
     id2,
     orgSlug,
   })
+  if (data.ok && alertFilter) {
+    data = { ...data, data: filterDiffScanArtifacts(data.data, alertFilter) }
+  }
+
+  const alertDiff =
+    alerts && data.ok
+      ? await fetchScanAlertDiff({ alertFilter, id1, id2, orgSlug })
+      : undefined
 
   await outputDiffScan(data, {
+    alertDiff,
     depth,
     file,
     outputKind,
@@ -1,44 +1,82 @@
-import { fetchOrgFullScanList } from './fetch-list-scans.mts'
+import {
+  SCAN_LIST_LOCAL_SORTS,
+  fetchOrgFullScanList,
+  fetchOrgFullScanPages,
+  sortScanList,
+} from './fetch-list-scans.mts'
 import { outputListScans } from './output-list-scans.mts'
 
+import type { ScanListLocalSort } from './fetch-list-scans.mts'
 import type { OutputKind } from '../../types.mts'
 
 export async function handleListScans({
+  all,
   branch,
+  committer,
   direction,
   from_time,
+  limit,
   orgSlug,
   outputKind,
   page,
   perPage,
   repo,
   sort,
+  status,
 }: {
+  all?: boolean | undefined
   branch: string
+  committer?: string | undefined
   direction: string
   from_time: string
+  limit?: number | undefined
   orgSlug: string
   outputKind: OutputKind
   page: number
   perPage: number
   repo: string
   sort: string
+  status?: string | undefined
 }): Promise<void> {
-  const data = await fetchOrgFullScanList(
-    {
-      branch,
-      direction,
-      from_time,
-      orgSlug,
-      page,
-      perPage,
-      repo,
-      sort,
-    },
-    {
-      commandPath: 'socket scan list',
-    },
-  )
+  const localSort = SCAN_LIST_LOCAL_SORTS.includes(sort as ScanListLocalSort)
+    ? (sort as ScanListLocalSort)
+    : undefined
+  const config = {
+    branch,
+    direction,
+    from_time,
+    orgSlug,
+    page,
+    perPage,
+    repo,
+    // The API sorts by created_at unless told otherwise.
+    sort: localSort ? '' : sort,
+  }
+  const options = {
+    commandPath: 'socket scan list',
+  }
+
+  if (!all && !limit && !committer && !status && !localSort) {
+    const data = await fetchOrgFullScanList(config, options)
+    await outputListScans(data, outputKind)
+    return
+  }
 
-  await outputListScans(data, outputKind)
+  const data = await fetchOrgFullScanPages(
+    config,
+    { all, committer, limit, status },
+    options,
+  )
+  await outputListScans(
+    data.ok && localSort
+      ? {
+          ...data,
+          data: {
+            ...data.data,
+            results: sortScanList(data.data.results, localSort, direction),
+          },
+        }
+      : data,
+    outputKind,
+  )
 }
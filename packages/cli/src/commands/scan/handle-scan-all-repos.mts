@@ -0,0 +1,65 @@
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { describeRepoSource, discoverRepos } from './discover-repos.mts'
+import { outputScanAllRepos } from './output-scan-all-repos.mts'
+import { scanAllRepos } from './scan-all-repos.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+
+import type { RepoSource } from './discover-repos.mts'
+import type { RepoScanResult } from './scan-all-repos.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+export async function handleScanAllRepos({
+  concurrency,
+  orgSlug,
+  outputKind,
+  source,
+}: {
+  concurrency: number
+  orgSlug: string
+  outputKind: OutputKind
+  source: RepoSource
+}): Promise<void> {
+  const spinner = getDefaultSpinner()
+  let result: CResult<RepoScanResult[]>
+  try {
+    spinner.start(
+      `Discovering repositories in ${describeRepoSource(source)}…`,
+    )
+    const reposCResult = await discoverRepos(source)
+    spinner.stop()
+    result = reposCResult.ok
+      ? await scanAllRepos({
+          concurrency,
+          orgSlug,
+          repos: reposCResult.data,
+          source,
+          spinner,
+        })
+      : reposCResult
+  } catch (e) {
+    spinner.stop()
+    result = {
+      ok: false,
+      message: 'Failed to scan the repositories',
+      cause: getErrorCause(e),
+    }
+  }
+
+  if (
+    result.ok &&
+    result.data.length &&
+    result.data.every(repo => repo.status === 'failed')
+  ) {
+    result = {
+      ok: false,
+      message: 'Failed to scan the repositories',
+      cause: `All ${result.data.length} repositories failed, the first with: ${result.data[0]!.reason}`,
+    }
+  }
+
+  debugDir({ result })
+
+  outputScanAllRepos(result, { outputKind, source })
+}
@@ -0,0 +1,212 @@
+import { promises as fs } from 'node:fs'
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { fetchScanData } from './fetch-report-data.mts'
+import { fetchScanMetadata } from './fetch-scan-metadata.mts'
+import { generateReport } from './generate-report.mts'
+import { outputScanAttest } from './output-scan-attest.mts'
+import {
+  SOCKET_SCAN_PREDICATE_TYPE,
+  buildScanAttestation,
+  getFileSubject,
+} from './scan-attestation.mts'
+import { FOLD_SETTING_NONE } from '../../constants/cli.mts'
+import { REPORT_LEVEL_MONITOR } from '../../constants/reporting.mts'
+import { SIGSTORE_ID_TOKEN } from '../../env/sigstore-id-token.mts'
+import { SOCKET_CLI_FULCIO_URL } from '../../env/socket-cli-fulcio-url.mts'
+import { SOCKET_CLI_REKOR_URL } from '../../env/socket-cli-rekor-url.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import {
+  findSocketBaselineSync,
+  readSocketBaselineSync,
+} from '../../util/policy/baseline.mts'
+import { findSocketPolicySync } from '../../util/policy/socket-policy.mts'
+import {
+  SIGSTORE_OIDC_AUDIENCE,
+  signStatementKeyless,
+} from '../../util/sigstore/keyless.mts'
+import { detectCiOidcProvider, fetchGithubIdToken } from '../ci/ci-oidc.mts'
+
+import type { ScanReport } from './generate-report.mts'
+import type { FAIL_ON } from './types.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { InTotoSubject } from '../../util/sigstore/dsse.mts'
+
+export type ScanAttestResult = {
+  bundlePath: string
+  // Subject alternative name of the signing certificate.
+  certificateIdentity: string
+  logIndex: number
+  passed: boolean
+  predicateType: string
+  scanId: string
+  subjects: InTotoSubject[]
+}
+
+export type HandleScanAttestConfig = {
+  // Explicit socket.baseline.json; otherwise the nearest one is used.
+  baselinePath?: string | undefined
+  bundlePath: string
+  cwd: string
+  failOn?: FAIL_ON | undefined
+  // OIDC token by --identity-token; otherwise SIGSTORE_ID_TOKEN or the
+  // GitHub Actions identity.
+  identityToken?: string | undefined
+  orgSlug: string
+  outputKind: OutputKind
+  scanId: string
+  subjectPaths: string[]
+}
+
+async function resolveIdentityToken(
+  identityToken: string | undefined,
+): Promise<CResult<string>> {
+  if (identityToken || SIGSTORE_ID_TOKEN) {
+    return { ok: true, data: identityToken || SIGSTORE_ID_TOKEN }
+  }
+  if (detectCiOidcProvider() === 'github') {
+    return await fetchGithubIdToken(SIGSTORE_OIDC_AUDIENCE)
+  }
+  return {
+    ok: false,
+    message: 'No OIDC identity to sign with',
+    cause: `Run in GitHub Actions with the \`id-token: write\` permission, or pass an ID token for the "${SIGSTORE_OIDC_AUDIENCE}" audience with --identity-token or SIGSTORE_ID_TOKEN`,
+  }
+}
+
+async function attestScan({
+  baselinePath,
+  bundlePath,
+  cwd,
+  failOn,
+  identityToken,
+  orgSlug,
+  scanId,
+  subjectPaths,
+}: HandleScanAttestConfig): Promise<CResult<ScanAttestResult>> {
+  // A local policy or baseline that does not parse fails the attestation
+  // rather than silently attesting against the org policy alone.
+  const policyCResult = findSocketPolicySync(cwd)
+  if (!policyCResult.ok) {
+    return policyCResult
+  }
+  const baselineCResult = baselinePath
+    ? readSocketBaselineSync(baselinePath)
+    : findSocketBaselineSync(cwd)
+  if (!baselineCResult.ok) {
+    return baselineCResult
+  }
+
+  const subjects: InTotoSubject[] = []
+  for (const subjectPath of subjectPaths) {
+    try {
+      subjects.push(getFileSubject(subjectPath))
+    } catch (e) {
+      return {
+        ok: false,
+        message: 'Subject file not readable',
+        cause: `Unable to read ${subjectPath}: ${getErrorCause(e)}`,
+      }
+    }
+  }
+
+  const metadataCResult = await fetchScanMetadata(orgSlug, scanId, {
+    commandPath: 'socket scan attest',
+  })
+  if (!metadataCResult.ok) {
+    return metadataCResult
+  }
+  const metadata = metadataCResult.data
+  if (!subjects.length && !metadata.commit_hash) {
+    return {
+      ok: false,
+      message: 'Nothing to attest',
+      cause: `Scan ${scanId} has no commit hash; create the scan with --commit-hash or name the artifacts to attest with --subject`,
+    }
+  }
+
+  const scanDataCResult = await fetchScanData(orgSlug, scanId)
+  if (!scanDataCResult.ok) {
+    return scanDataCResult
+  }
+  const { scan, securityPolicy } = scanDataCResult.data
+  const localPolicy = policyCResult.data
+  const baseline = baselineCResult.data
+  const reportCResult = generateReport(scan, securityPolicy, {
+    baseline,
+    failOn,
+    fold: FOLD_SETTING_NONE,
+    localPolicy: localPolicy?.policy,
+    orgSlug,
+    reportLevel: REPORT_LEVEL_MONITOR,
+    scanId,
+  })
+  if (!reportCResult.ok) {
+    return reportCResult
+  }
+
+  const statement = buildScanAttestation({
+    baseline,
+    cwd,
+    failOn,
+    localPolicy,
+    metadata,
+    orgSlug,
+    report: reportCResult.data as ScanReport,
+    scanId,
+    securityPolicy,
+    subjects,
+  })
+
+  // Fetched last, the GitHub Actions tokens are short-lived.
+  const tokenCResult = await resolveIdentityToken(identityToken)
+  if (!tokenCResult.ok) {
+    return tokenCResult
+  }
+  const signCResult = await signStatementKeyless(statement, {
+    fulcioUrl: SOCKET_CLI_FULCIO_URL || undefined,
+    identityToken: tokenCResult.data,
+    rekorUrl: SOCKET_CLI_REKOR_URL || undefined,
+  })
+  if (!signCResult.ok) {
+    return signCResult
+  }
+
+  try {
+    await fs.writeFile(
+      bundlePath,
+      `${JSON.stringify(signCResult.data.bundle, null, 2)}\n`,
+      'utf8',
+    )
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Failed to write attestation bundle',
+      cause: `Unable to write ${bundlePath}: ${getErrorCause(e)}`,
+    }
+  }
+
+  return {
+    ok: true,
+    data: {
+      bundlePath,
+      certificateIdentity: signCResult.data.certificateIdentity,
+      logIndex: signCResult.data.logIndex,
+      passed: statement.predicate.result.passed,
+      predicateType: SOCKET_SCAN_PREDICATE_TYPE,
+      scanId,
+      subjects: statement.subject,
+    },
+  }
+}
+
+export async function handleScanAttest(
+  config: HandleScanAttestConfig,
+): Promise<void> {
+  const result = await attestScan(config)
+
+  debugDir({ result })
+
+  await outputScanAttest(result, config.outputKind)
+}
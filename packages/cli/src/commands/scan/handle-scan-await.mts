@@ -0,0 +1,16 @@
+import { awaitScanAnalysis } from './await-scan.mts'
+import { outputScanAwait } from './output-scan-await.mts'
+
+import type { ScanWaitOptions } from './await-scan.mts'
+import type { OutputKind } from '../../types.mts'
+
+export async function handleScanAwait(
+  orgSlug: string,
+  scanId: string,
+  outputKind: OutputKind,
+  options: ScanWaitOptions,
+): Promise<void> {
+  const result = await awaitScanAnalysis(orgSlug, scanId, options)
+
+  outputScanAwait(result, outputKind)
+}
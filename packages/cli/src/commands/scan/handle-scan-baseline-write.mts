@@ -0,0 +1,52 @@
+import { promises as fs } from 'node:fs'
+
+import { fetchScan } from './fetch-scan.mts'
+import { outputScanBaselineWrite } from './output-scan-baseline-write.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import { createSocketBaseline } from '../../util/policy/baseline.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type ScanBaselineWriteResult = {
+  alerts: number
+  path: string
+}
+
+export async function handleScanBaselineWrite({
+  filepath,
+  orgSlug,
+  outputKind,
+  scanId,
+}: {
+  filepath: string
+  orgSlug: string
+  outputKind: OutputKind
+  scanId: string
+}): Promise<void> {
+  const scanCResult = await fetchScan(orgSlug, scanId)
+  if (!scanCResult.ok) {
+    await outputScanBaselineWrite(scanCResult, outputKind)
+    return
+  }
+
+  const baseline = createSocketBaseline(scanCResult.data, scanId)
+  let result: CResult<ScanBaselineWriteResult>
+  try {
+    await fs.writeFile(
+      filepath,
+      `${JSON.stringify(baseline, null, 2)}\n`,
+      'utf8',
+    )
+    result = {
+      ok: true,
+      data: { alerts: baseline.alerts.length, path: filepath },
+    }
+  } catch (e) {
+    result = {
+      ok: false,
+      message: 'Failed to write baseline file',
+      cause: `Unable to write ${filepath}: ${getErrorCause(e)}`,
+    }
+  }
+  await outputScanBaselineWrite(result, outputKind)
+}
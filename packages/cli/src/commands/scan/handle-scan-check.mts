@@ -0,0 +1,75 @@
+import { existsSync } from 'node:fs'
+
+import { fetchScanData } from './fetch-report-data.mts'
+import { outputScanCheck } from './output-scan-check.mts'
+import { buildRegoInput, evaluateRegoPolicy } from '../../util/policy/rego.mts'
+import { applyVexStatements, readVexFilesSync } from '../../util/vex/vex.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export type HandleScanCheckConfig = {
+  format?: 'junit' | undefined
+  // Read the scan and policy of an imported offline bundle, see --offline.
+  offline?: boolean | undefined
+  orgSlug: string
+  outputKind: OutputKind
+  policyPath: string
+  regoPackage: string
+  scanId: string
+  // VEX documents whose not_affected and fixed statements leave out the
+  // matching vulnerability alerts.
+  vexPaths?: string[] | undefined
+}
+
+export async function handleScanCheck({
+  format,
+  offline = false,
+  orgSlug,
+  outputKind,
+  policyPath,
+  regoPackage,
+  scanId,
+  vexPaths = [],
+}: HandleScanCheckConfig): Promise<void> {
+  if (!existsSync(policyPath)) {
+    await outputScanCheck(
+      {
+        ok: false,
+        message: 'Policy file not found',
+        cause: `Unable to read ${policyPath}`,
+      },
+      outputKind,
+    )
+    return
+  }
+
+  const vexCResult = readVexFilesSync(vexPaths)
+  if (!vexCResult.ok) {
+    await outputScanCheck(vexCResult, outputKind)
+    return
+  }
+
+  const scanDataCResult = await fetchScanData(orgSlug, scanId, { offline })
+  if (!scanDataCResult.ok) {
+    await outputScanCheck(scanDataCResult, outputKind)
+    return
+  }
+
+  const { securityPolicy } = scanDataCResult.data
+  const { scan } = applyVexStatements(
+    scanDataCResult.data.scan,
+    vexCResult.data,
+  )
+  const input = buildRegoInput({
+    artifacts: scan,
+    orgSlug,
+    scanId,
+    securityPolicyRules: securityPolicy.securityPolicyRules,
+  })
+
+  await outputScanCheck(
+    await evaluateRegoPolicy(policyPath, input, regoPackage),
+    outputKind,
+    { format, regoPackage },
+  )
+}
@@ -1,42 +1,221 @@
 import { fetchScanData } from './fetch-report-data.mts'
+import { filterScanArtifacts } from './filter-scan-alerts.mts'
+import {
+  filterReachableAlerts,
+  getImportReachability,
+} from './import-reachability.mts'
 import { outputScanReport } from './output-scan-report.mts'
+import { outputWorkspaceScanReport } from './output-workspace-scan-report.mts'
+import { resolveWorkspaceSelection } from './scan-workspaces.mts'
+import { findSocketYmlSync } from '../../util/config.mts'
+import {
+  findSocketBaselineSync,
+  readSocketBaselineSync,
+} from '../../util/policy/baseline.mts'
+import { findSocketPolicySync } from '../../util/policy/socket-policy.mts'
+import { findImportedPackages } from '../../util/reachability/source-imports.mts'
+import { applyVexStatements, readVexFilesSync } from '../../util/vex/vex.mts'
 
-import type { FOLD_SETTING, REPORT_LEVEL } from './types.mts'
+import type { ScanAlertFilter } from './filter-scan-alerts.mts'
+import type { ScanReportCsvColumn } from './generate-csv-report.mts'
+import type { ImportReachability } from './import-reachability.mts'
+import type {
+  WorkspaceSelection,
+  WorkspaceSelectionOptions,
+} from './scan-workspaces.mts'
+import type {
+  FAIL_ON,
+  FOLD_SETTING,
+  REPORT_FORMAT,
+  REPORT_LEVEL,
+} from './types.mts'
 import type { OutputKind } from '../../types.mts'
 
 export type HandleScanReportConfig = {
+  // Only report the alerts the filter includes, see --severity.
+  alertFilter?: ScanAlertFilter | undefined
+  // Explicit socket.baseline.json; otherwise the nearest one is used.
+  baselinePath?: string | undefined
+  // Columns and header row of format csv, see --columns.
+  columns?: readonly ScanReportCsvColumn[] | undefined
+  csvHeader?: boolean | undefined
+  // Alert severity that fails the report instead of the policy error level.
+  failOn?: FAIL_ON | undefined
   orgSlug: string
   scanId: string
   includeLicensePolicy: boolean
+  // Read the scan and policy of an imported offline bundle, see --offline.
+  offline?: boolean | undefined
   outputKind: OutputKind
   filepath: string
   fold: FOLD_SETTING
+  format?: REPORT_FORMAT | undefined
+  // Tag alerts with the import reachability of their package, read from the
+  // sources under cwd.
+  importReachability?: boolean | undefined
+  // Leave out alerts of packages the sources cannot reach. Implies
+  // importReachability.
+  onlyReachable?: boolean | undefined
+  // Template file to render the report with, see --output-template.
+  outputTemplate?: string | undefined
+  // Directory to search upward from for socket.policy.yml and
+  // socket.baseline.json.
+  cwd?: string | undefined
   reportLevel: REPORT_LEVEL
   short: boolean
+  // Add the file and redacted snippet of each secret alert, read from the
+  // packages installed under cwd when present.
+  showEvidence?: boolean | undefined
+  // VEX documents whose not_affected and fixed statements leave out the
+  // matching vulnerability alerts.
+  vexPaths?: string[] | undefined
+  // Report each selected monorepo workspace under cwd on its own. Not
+  // combined with format or outputTemplate.
+  workspaces?: WorkspaceSelectionOptions | undefined
 }
 
 export async function handleScanReport({
+  alertFilter,
+  baselinePath,
+  columns,
+  csvHeader,
+  cwd = process.cwd(),
+  failOn,
   filepath,
   fold,
+  format,
+  importReachability = false,
   includeLicensePolicy,
+  offline = false,
+  onlyReachable = false,
   orgSlug,
   outputKind,
+  outputTemplate,
   reportLevel,
   scanId,
   short,
+  showEvidence = false,
+  vexPaths = [],
+  workspaces,
 }: HandleScanReportConfig): Promise<void> {
-  const scanDataCResult = await fetchScanData(orgSlug, scanId, {
-    includeLicensePolicy,
-  })
-
-  await outputScanReport(scanDataCResult, {
+  const outputConfig = {
+    columns,
+    csvHeader,
+    evidenceCwd: showEvidence ? cwd : undefined,
+    failOn,
     filepath,
     fold,
+    format,
     scanId: scanId,
     includeLicensePolicy,
     orgSlug,
     outputKind,
+    outputTemplate,
     reportLevel,
     short,
+  }
+
+  // An invalid local policy or baseline fails the report rather than silently
+  // falling back to the org policy.
+  const policyCResult = findSocketPolicySync(cwd)
+  if (!policyCResult.ok) {
+    await outputScanReport(policyCResult, outputConfig)
+    return
+  }
+
+  const baselineCResult = baselinePath
+    ? readSocketBaselineSync(baselinePath)
+    : findSocketBaselineSync(cwd)
+  if (!baselineCResult.ok) {
+    await outputScanReport(baselineCResult, outputConfig)
+    return
+  }
+
+  const vexCResult = readVexFilesSync(vexPaths)
+  if (!vexCResult.ok) {
+    await outputScanReport(vexCResult, outputConfig)
+    return
+  }
+
+  let workspaceSelection: WorkspaceSelection | undefined
+  if (workspaces) {
+    const selectionCResult = await resolveWorkspaceSelection(cwd, workspaces)
+    if (!selectionCResult.ok) {
+      await outputScanReport(selectionCResult, outputConfig)
+      return
+    }
+    workspaceSelection = selectionCResult.data
+  }
+
+  let scanDataCResult = await fetchScanData(orgSlug, scanId, {
+    includeLicensePolicy,
+    offline,
+  })
+  if (scanDataCResult.ok && vexCResult.data.length) {
+    const { scan } = applyVexStatements(
+      scanDataCResult.data.scan,
+      vexCResult.data,
+    )
+    scanDataCResult = {
+      ...scanDataCResult,
+      data: { ...scanDataCResult.data, scan },
+    }
+  }
+  if (scanDataCResult.ok && alertFilter) {
+    scanDataCResult = {
+      ...scanDataCResult,
+      data: {
+        ...scanDataCResult.data,
+        scan: filterScanArtifacts(scanDataCResult.data.scan, alertFilter, {
+          keepPackages: true,
+        }),
+      },
+    }
+  }
+
+  let reportDataCResult = scanDataCResult
+  let reachability: Map<string, ImportReachability> | undefined
+  if (scanDataCResult.ok && (importReachability || onlyReachable)) {
+    const socketYmlResult = findSocketYmlSync(cwd)
+    const importedNames = await findImportedPackages(cwd, {
+      socketConfig: socketYmlResult.ok
+        ? socketYmlResult.data?.parsed
+        : undefined,
+    })
+    const { scan } = scanDataCResult.data
+    reachability = getImportReachability(scan, importedNames)
+    if (onlyReachable) {
+      reportDataCResult = {
+        ...scanDataCResult,
+        data: {
+          ...scanDataCResult.data,
+          scan: filterReachableAlerts(scan, reachability),
+        },
+      }
+    }
+  }
+
+  if (workspaceSelection) {
+    await outputWorkspaceScanReport(reportDataCResult, workspaceSelection, {
+      baseline: baselineCResult.data,
+      failOn,
+      filepath,
+      fold,
+      localPolicy: policyCResult.data?.policy,
+      orgSlug,
+      outputKind,
+      reachability,
+      reportLevel,
+      scanId,
+      short,
+    })
+    return
+  }
+
+  await outputScanReport(reportDataCResult, {
+    ...outputConfig,
+    baseline: baselineCResult.data,
+    localPolicy: policyCResult.data?.policy,
+    reachability,
   })
 }
@@ -1,15 +1,111 @@
+import path from 'node:path'
+
 import { fetchScan } from './fetch-scan.mts'
-import { outputScanView } from './output-scan-view.mts'
+import { fetchUpdateAlertTriage } from './fetch-update-alert-triage.mts'
+import { filterScanArtifacts } from './filter-scan-alerts.mts'
+import { outputScanView, outputScanViewTriage } from './output-scan-view.mts'
+import { getTriageItems } from './scan-view-triage.mts'
+import { runScanViewTui } from './scan-view-tui.mts'
+import { SOCKET_POLICY_YML } from '../../constants/socket.mts'
+import { addSocketPolicyException } from '../../util/policy/add-policy-exception.mts'
+import { findSocketPolicyPathSync } from '../../util/policy/socket-policy.mts'
+
+import type { ScanAlertFilter } from './filter-scan-alerts.mts'
+import type { TriageItem, TriageStatus } from './scan-view-triage.mts'
+import type { CResult, OutputKind } from '../../types.mts'
 
-import type { OutputKind } from '../../types.mts'
+export type ScanViewTriageTarget = 'local' | 'org'
 
 export async function handleScanView(
   orgSlug: string,
   scanId: string,
   filePath: string,
   outputKind: OutputKind,
+  alertFilter?: ScanAlertFilter | undefined,
+): Promise<void> {
+  const data = await fetchScan(orgSlug, scanId)
+
+  await outputScanView(
+    data.ok && alertFilter
+      ? { ...data, data: filterScanArtifacts(data.data, alertFilter) }
+      : data,
+    orgSlug,
+    scanId,
+    filePath,
+    outputKind,
+  )
+}
+
+/**
+ * Write one triage decision. Locally it becomes a `socket.policy.yml`
+ * exception for the alert type on that exact package version; for the org it
+ * sets the triage state of the alert key, `ignore` or `monitor` for accepted.
+ */
+export async function triageScanAlert(
+  item: TriageItem,
+  status: Exclude<TriageStatus, 'open'>,
+  reason: string,
+  {
+    orgSlug,
+    policyPath,
+    target,
+  }: { orgSlug: string; policyPath: string; target: ScanViewTriageTarget },
+): Promise<CResult<string>> {
+  const verb = status === 'ignored' ? 'Ignored' : 'Accepted'
+  if (target === 'org') {
+    if (!item.alertKey) {
+      return {
+        ok: false,
+        message: 'Cannot triage this alert',
+        cause: 'The scan did not report a key for it',
+      }
+    }
+    const result = await fetchUpdateAlertTriage(orgSlug, [
+      {
+        alertKey: item.alertKey,
+        note: reason,
+        state: status === 'ignored' ? 'ignore' : 'monitor',
+      },
+    ])
+    if (!result.ok) {
+      return result
+    }
+    return { ok: true, data: `${verb} ${item.type} for ${orgSlug}` }
+  }
+  const result = addSocketPolicyException(policyPath, {
+    alerts: [item.type],
+    packages: [item.purl],
+    paths: [],
+    reason: status === 'accepted' ? `Accepted: ${reason}` : reason,
+  })
+  if (!result.ok) {
+    return result
+  }
+  return {
+    ok: true,
+    data: `${verb} ${item.type}; added an exception to ${path.basename(policyPath)}`,
+  }
+}
+
+export async function handleScanViewInteractive(
+  orgSlug: string,
+  scanId: string,
+  target: ScanViewTriageTarget,
+  alertFilter?: ScanAlertFilter | undefined,
 ): Promise<void> {
   const data = await fetchScan(orgSlug, scanId)
+  if (!data.ok) {
+    await outputScanView(data, orgSlug, scanId, '', 'text')
+    return
+  }
+
+  const policyPath =
+    findSocketPolicyPathSync() ?? path.join(process.cwd(), SOCKET_POLICY_YML)
+  const artifacts = filterScanArtifacts(data.data, alertFilter)
+  const items = await runScanViewTui(getTriageItems(artifacts), {
+    onTriage: (item, status, reason) =>
+      triageScanAlert(item, status, reason, { orgSlug, policyPath, target }),
+  })
 
-  await outputScanView(data, orgSlug, scanId, filePath, outputKind)
+  outputScanViewTriage(items, target === 'org' ? '' : policyPath)
 }
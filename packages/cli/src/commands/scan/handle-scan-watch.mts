@@ -0,0 +1,359 @@
+/**
+ * `socket scan watch`: rescan the working tree whenever a package manifest or
+ * lockfile changes and report how the alerts moved.
+ *
+ * Every round uploads the current manifests as a temporary full scan, so
+ * nothing shows up in the dashboard, and compares its alerts with the
+ * previous round against the org security policy (see diff-scan-alerts.mts).
+ * Rounds are incremental: file events are debounced, and a round that finds
+ * every manifest byte-identical to the previous one is skipped without an
+ * upload.
+ */
+
+import { createHash } from 'node:crypto'
+import { watch } from 'node:fs'
+import { readFile } from 'node:fs/promises'
+import path from 'node:path'
+
+import { debugDir, debugNs } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { getScanAlertDiff } from './diff-scan-alerts.mts'
+import { fetchCreateOrgFullScan } from './fetch-create-org-full-scan.mts'
+import { fetchScan } from './fetch-scan.mts'
+import { fetchSupportedScanFileNames } from './fetch-supported-scan-file-names.mts'
+import { outputScanWatch } from './output-scan-watch.mts'
+import { SCAN_TYPE_SOCKET } from '../../constants.mts'
+import { REPORT_LEVEL_ERROR } from '../../constants/reporting.mts'
+import { findSocketYmlSync } from '../../util/config.mts'
+import { createSupportedFilesFilter } from '../../util/fs/glob.mts'
+import { getPackageFilesForScan } from '../../util/fs/path-resolve.mts'
+import { isLocalLockfilePath } from '../../util/lockfile/parsers.mts'
+import { prepareLockfilesForUpload } from '../../util/lockfile/upload.mts'
+import { fetchSecurityPolicy } from '../organization/fetch-security-policy.mts'
+
+import type { ScanAlertDiff } from './diff-scan-alerts.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { SupportedFiles } from '../../util/fs/glob.mts'
+
+const logger = getDefaultLogger()
+
+const IGNORED_WATCH_DIRS = new Set(['.git', 'node_modules'])
+
+export type HandleScanWatchConfig = {
+  branchName: string
+  cwd: string
+  debounceMs: number
+  orgSlug: string
+  outputKind: OutputKind
+  repoName: string
+  // Stops watching. Without it the command runs until interrupted.
+  signal?: AbortSignal | undefined
+}
+
+export type ScanWatchEvent = {
+  alerts: ScanAlertDiff
+  // Relative paths of the manifests that changed since the previous round,
+  // empty for the initial scan.
+  changedFiles: string[]
+  fileCount: number
+  initial: boolean
+  scanId: string
+  // All alerts of the current scan and those violating the policy.
+  totalAlerts: number
+  totalBlocking: number
+}
+
+type WatchRound = {
+  artifacts: SocketArtifact[]
+  hashes: Map<string, string>
+}
+
+/**
+ * Files added, changed or removed between two rounds, sorted.
+ */
+export function getChangedScanFiles(
+  before: Map<string, string>,
+  after: Map<string, string>,
+): string[] {
+  const changed = new Set<string>()
+  for (const { 0: file, 1: hash } of after) {
+    if (before.get(file) !== hash) {
+      changed.add(file)
+    }
+  }
+  for (const file of before.keys()) {
+    if (!after.has(file)) {
+      changed.add(file)
+    }
+  }
+  return [...changed].sort()
+}
+
+/**
+ * Whether a file event is worth a new round: a supported manifest or a
+ * locally parsed lockfile outside `.git` and `node_modules`.
+ */
+export function isWatchedScanFile(
+  relPath: string,
+  isSupportedFile: (filepath: string) => boolean,
+): boolean {
+  const segments = relPath.split(/[\\/]/)
+  if (segments.some(s => IGNORED_WATCH_DIRS.has(s))) {
+    return false
+  }
+  const normalized = segments.join('/')
+  return isSupportedFile(normalized) || isLocalLockfilePath(normalized)
+}
+
+async function hashScanFiles(
+  cwd: string,
+  filepaths: string[],
+): Promise<Map<string, string>> {
+  const hashes = new Map<string, string>()
+  await Promise.all(
+    filepaths.map(async filepath => {
+      try {
+        const content = await readFile(filepath)
+        hashes.set(
+          path.relative(cwd, filepath),
+          createHash('sha256').update(content).digest('hex'),
+        )
+      } catch {
+        // Deleted between the glob and the read, the next round sees it.
+      }
+    }),
+  )
+  return hashes
+}
+
+function countBlocking(
+  artifacts: SocketArtifact[],
+  securityRules: Record<string, { action?: string | undefined } | undefined>,
+): { blocking: number; total: number } {
+  let blocking = 0
+  let total = 0
+  for (let i = 0, { length } = artifacts; i < length; i += 1) {
+    const alerts = artifacts[i]!.alerts ?? []
+    for (let j = 0, { length: alertCount } = alerts; j < alertCount; j += 1) {
+      total += 1
+      if (securityRules[alerts[j]!.type]?.action === REPORT_LEVEL_ERROR) {
+        blocking += 1
+      }
+    }
+  }
+  return { blocking, total }
+}
+
+async function createWatchScan(
+  filepaths: string[],
+  {
+    branchName,
+    cwd,
+    orgSlug,
+    repoName,
+    supportedFiles,
+  }: {
+    branchName: string
+    cwd: string
+    orgSlug: string
+    repoName: string
+    supportedFiles: SupportedFiles
+  },
+): Promise<CResult<{ artifacts: SocketArtifact[]; scanId: string }>> {
+  const lockfileScan = await prepareLockfilesForUpload(filepaths, {
+    cwd,
+    isSupportedByApi: createSupportedFilesFilter(supportedFiles),
+  })
+  let fullScanCResult: Awaited<ReturnType<typeof fetchCreateOrgFullScan>>
+  try {
+    fullScanCResult = await fetchCreateOrgFullScan(
+      lockfileScan.paths,
+      orgSlug,
+      {
+        branchName,
+        commitHash: '',
+        commitMessage: '',
+        committers: '',
+        pullRequest: 0,
+        repoName,
+        scanType: SCAN_TYPE_SOCKET,
+      },
+      {
+        commandPath: 'socket scan watch',
+        cwd,
+        tmp: true,
+      },
+    )
+  } finally {
+    await lockfileScan.cleanup()
+  }
+  if (!fullScanCResult.ok) {
+    return fullScanCResult
+  }
+  const scanId = fullScanCResult.data?.id
+  if (!scanId) {
+    return {
+      ok: false,
+      message: 'Missing Scan ID',
+      cause: 'Server did not respond with a scan ID',
+    }
+  }
+  const scanCResult = await fetchScan(orgSlug, scanId)
+  if (!scanCResult.ok) {
+    return scanCResult
+  }
+  return { ok: true, data: { artifacts: scanCResult.data, scanId } }
+}
+
+export async function handleScanWatch({
+  branchName,
+  cwd,
+  debounceMs,
+  orgSlug,
+  outputKind,
+  repoName,
+  signal,
+}: HandleScanWatchConfig): Promise<void> {
+  const spinner = getDefaultSpinner()
+
+  const supportedFilesCResult = await fetchSupportedScanFileNames({
+    orgSlug,
+    spinner,
+  })
+  if (!supportedFilesCResult.ok) {
+    outputScanWatch(supportedFilesCResult, outputKind)
+    return
+  }
+  const supportedFiles = supportedFilesCResult.data
+
+  const policyCResult = await fetchSecurityPolicy(orgSlug, {
+    commandPath: 'socket scan watch',
+  })
+  if (!policyCResult.ok) {
+    outputScanWatch(policyCResult, outputKind)
+    return
+  }
+  const securityPolicy = policyCResult.data
+  const securityRules = (securityPolicy.securityPolicyRules ?? {}) as Record<
+    string,
+    { action?: string | undefined } | undefined
+  >
+
+  // Load socket.yml so projectIgnorePaths is respected when collecting files.
+  const socketYmlResult = findSocketYmlSync(cwd)
+  const socketConfig = socketYmlResult.ok
+    ? socketYmlResult.data?.parsed
+    : undefined
+  const isSupportedFile = createSupportedFilesFilter(supportedFiles)
+
+  let previous: WatchRound | undefined
+
+  async function runRound(): Promise<void> {
+    const filepaths = await getPackageFilesForScan([cwd], supportedFiles, {
+      config: socketConfig,
+      cwd,
+      localLockfiles: true,
+    })
+    const hashes = await hashScanFiles(cwd, filepaths)
+    const changedFiles = previous
+      ? getChangedScanFiles(previous.hashes, hashes)
+      : []
+    if (previous && !changedFiles.length) {
+      debugNs('notice', 'Manifests unchanged, skipping scan')
+      return
+    }
+    if (!filepaths.length) {
+      outputScanWatch(
+        {
+          ok: false,
+          message: 'No manifest files found',
+          cause: `No supported manifest or lockfile found in ${cwd}`,
+        },
+        outputKind,
+      )
+      return
+    }
+
+    spinner.start(
+      previous
+        ? `Rescanning after changes to ${changedFiles.join(', ')}…`
+        : 'Creating the initial scan…',
+    )
+    const scanCResult = await createWatchScan(filepaths, {
+      branchName,
+      cwd,
+      orgSlug,
+      repoName,
+      supportedFiles,
+    })
+    spinner.stop()
+    if (!scanCResult.ok) {
+      outputScanWatch(scanCResult, outputKind)
+      return
+    }
+
+    const { artifacts, scanId } = scanCResult.data
+    const { blocking, total } = countBlocking(artifacts, securityRules)
+    outputScanWatch(
+      {
+        ok: true,
+        data: {
+          alerts: previous
+            ? getScanAlertDiff(previous.artifacts, artifacts, securityPolicy)
+            : { added: [], blocking: [], removed: [] },
+          changedFiles,
+          fileCount: hashes.size,
+          initial: !previous,
+          scanId,
+          totalAlerts: total,
+          totalBlocking: blocking,
+        },
+      },
+      outputKind,
+    )
+    previous = { artifacts, hashes }
+  }
+
+  // Rounds run one at a time, in the order they were scheduled.
+  let queue: Promise<void> = runRound()
+  await queue
+  if (signal?.aborted) {
+    return
+  }
+
+  if (outputKind === 'text') {
+    logger.info(`Watching ${cwd} for manifest changes. Press Ctrl+C to stop.`)
+  }
+
+  let timer: NodeJS.Timeout | undefined
+  await new Promise<void>(resolve => {
+    const watcher = watch(
+      cwd,
+      { recursive: true, signal },
+      (_eventType, filename) => {
+        if (!filename || !isWatchedScanFile(filename, isSupportedFile)) {
+          return
+        }
+        debugDir('inspect', { changed: filename })
+        clearTimeout(timer)
+        timer = setTimeout(() => {
+          // A failed round must not stop the ones after it.
+          queue = queue.then(runRound).catch(e => {
+            logger.fail(`Scan failed: ${(e as Error).message}`)
+          })
+        }, debounceMs)
+      },
+    )
+    watcher.on('close', resolve)
+    watcher.on('error', e => {
+      logger.fail(`Stopped watching: ${(e as Error).message}`)
+      process.exitCode = 1
+      watcher.close()
+    })
+  })
+  clearTimeout(timer)
+  await queue
+}
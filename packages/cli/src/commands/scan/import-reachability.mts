@@ -0,0 +1,84 @@
+/**
+ * Import based reachability of scan artifacts, see `--only-reachable`. An
+ * npm package is `imported` when the project sources import it by name and
+ * `reachable` when an imported package depends on it, directly or not. Other
+ * npm packages are `unreachable`. Packages of other ecosystems are `unknown`
+ * since only JavaScript and TypeScript sources are read.
+ */
+
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export type ImportReachability =
+  | 'imported'
+  | 'reachable'
+  | 'unknown'
+  | 'unreachable'
+
+function getArtifactPackageName(artifact: SocketArtifact): string {
+  const { name = '', namespace } = artifact
+  return namespace ? `${namespace}/${name}` : name
+}
+
+/**
+ * The reachability of each artifact of a scan, by artifact id.
+ */
+export function getImportReachability(
+  scan: SocketArtifact[],
+  importedNames: Set<string>,
+): Map<string, ImportReachability> {
+  const byId = new Map<string, SocketArtifact>()
+  const reachability = new Map<string, ImportReachability>()
+  const queue: string[] = []
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    const artifact = scan[i]!
+    if (!artifact.id) {
+      continue
+    }
+    byId.set(artifact.id, artifact)
+    if (artifact.type !== 'npm') {
+      reachability.set(artifact.id, 'unknown')
+    } else if (importedNames.has(getArtifactPackageName(artifact))) {
+      reachability.set(artifact.id, 'imported')
+      queue.push(artifact.id)
+    }
+  }
+  // Walk the dependency graph down from the imported packages.
+  while (queue.length) {
+    const artifact = byId.get(queue.shift()!)!
+    for (const depId of artifact.dependencies ?? []) {
+      if (byId.has(depId) && !reachability.has(depId)) {
+        reachability.set(depId, 'reachable')
+        queue.push(depId)
+      }
+    }
+  }
+  // Scans without a dependency graph still name the direct dependencies
+  // that pulled each package in.
+  for (const { 0: id, 1: artifact } of byId) {
+    if (reachability.has(id)) {
+      continue
+    }
+    const viaImported = (artifact.topLevelAncestors ?? []).some(
+      ancestorId => reachability.get(ancestorId) === 'imported',
+    )
+    reachability.set(id, viaImported ? 'reachable' : 'unreachable')
+  }
+  return reachability
+}
+
+/**
+ * The scan without the alerts of unreachable packages. Packages of unknown
+ * reachability keep their alerts.
+ */
+export function filterReachableAlerts(
+  scan: SocketArtifact[],
+  reachability: Map<string, ImportReachability>,
+): SocketArtifact[] {
+  return scan.map(artifact =>
+    artifact.alerts?.length &&
+    artifact.id &&
+    reachability.get(artifact.id) === 'unreachable'
+      ? { ...artifact, alerts: [] }
+      : artifact,
+  )
+}
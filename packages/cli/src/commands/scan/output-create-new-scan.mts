@@ -5,16 +5,20 @@ import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
 import { confirm } from '@socketsecurity/lib-stable/stdio/prompts'
 
+import { formatScanAnalysis } from './output-scan-await.mts'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
 import { mdHeader } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 
+import type { ScanAnalysisSummary } from './await-scan.mts'
 import type { CResult, OutputKind } from '../../types.mts'
 import type { SpinnerInstance } from '@socketsecurity/lib-stable/spinner/types'
 import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
 const logger = getDefaultLogger()
 
 export type CreateNewScanOptions = {
+  // The analysis of the new scan, when it was waited for with --wait.
+  analysis?: ScanAnalysisSummary | undefined
   interactive?: boolean | undefined
   outputKind?: OutputKind | undefined
   spinner?: SpinnerInstance | undefined
@@ -25,6 +29,7 @@ export async function outputCreateNewScan(
   options?: CreateNewScanOptions | undefined,
 ) {
   const {
+    analysis,
     interactive = false,
     outputKind = 'text',
     spinner = getDefaultSpinner(),
@@ -39,7 +44,13 @@ export async function outputCreateNewScan(
   spinner?.stop()
 
   if (outputKind === 'json') {
-    logger.log(serializeResultJson(result))
+    logger.log(
+      serializeResultJson(
+        result.ok && analysis
+          ? { ...result, data: { ...result.data, analysis } }
+          : result,
+      ),
+    )
     if (wasSpinning) {
       spinner?.start()
     }
@@ -67,6 +78,10 @@ export async function outputCreateNewScan(
         `A [new Scan](${result.data.html_report_url}) was created with ID: ${result.data.id}`,
       )
       logger.log('')
+      if (analysis) {
+        logger.log(formatScanAnalysis(analysis))
+        logger.log('')
+      }
     } else {
       logger.log(
         'The server did not return a Scan ID while trying to create a new Scan. This could be an indication something went wrong.',
@@ -81,6 +96,9 @@ export async function outputCreateNewScan(
 
   logger.log('')
   logger.success('Scan completed successfully!')
+  if (analysis) {
+    logger.success(formatScanAnalysis(analysis))
+  }
 
   const htmlReportUrl = result.data.html_report_url
   if (htmlReportUrl) {
@@ -4,23 +4,80 @@ import util from 'node:util'
 import colors from 'yoctocolors-cjs'
 
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
 
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
 import { SOCKET_WEBSITE_URL } from '../../constants/socket.mts'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
 import { mdHeader } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 import { fileLink } from '../../util/terminal/link.mts'
 
+import type { ScanAlertDiff, ScanDiffAlert } from './diff-scan-alerts.mts'
 import type { CResult, OutputKind } from '../../types.mts'
 import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
 const logger = getDefaultLogger()
 
+export function formatDiffAlert(alert: ScanDiffAlert): string {
+  const where = alert.file ? ` (in ${alert.file})` : ''
+  const policy = alert.action ? `, policy: ${alert.action}` : ''
+  return `${alert.type} in ${alert.purl}${where} [${alert.severity}${policy}]`
+}
+
+function logDiffAlerts(alerts: ScanDiffAlert[], prefix: string): void {
+  const head = alerts.slice(0, 10)
+  for (let i = 0, { length } = head; i < length; i += 1) {
+    logger.log(`${prefix}${formatDiffAlert(head[i]!)}`)
+  }
+  if (alerts.length > 10) {
+    logger.log(`${prefix}… and ${alerts.length - 10} more`)
+  }
+}
+
+export function outputAlertDiffSummary(
+  alertDiff: ScanAlertDiff,
+  addedPackageCount: number,
+  outputKind: OutputKind,
+): void {
+  const md = outputKind === 'markdown'
+  if (md) {
+    logger.log(mdHeader('Alerts', 2))
+    logger.log('')
+  } else {
+    logger.log('')
+    logger.log('Alert changes:')
+  }
+  logger.log(`- New packages: ${addedPackageCount}`)
+  logger.log(`- New alerts: ${alertDiff.added.length}`)
+  logDiffAlerts(alertDiff.added, '  - ')
+  logger.log(`- Resolved alerts: ${alertDiff.removed.length}`)
+  logDiffAlerts(alertDiff.removed, '  - ')
+  logger.log('')
+  if (alertDiff.blocking.length) {
+    const count = alertDiff.blocking.length
+    const msg = `${count} new ${pluralize('alert', { count })} violating the security policy`
+    if (md) {
+      logger.log(`**${msg}**`)
+      logger.log('')
+    } else {
+      logger.fail(msg)
+    }
+  } else if (!md) {
+    logger.success('No new alerts violate the security policy')
+  }
+}
+
 export async function handleJson(
   data: CResult<SocketSdkSuccessResult<'GetOrgDiffScan'>['data']>,
   file: string,
   dashboardMessage: string,
+  alertDiff?: ScanAlertDiff | undefined,
 ) {
-  const json = serializeResultJson(data)
+  const json = serializeResultJson(
+    data.ok && alertDiff
+      ? { ...data, data: { ...data.data, alerts: alertDiff } }
+      : data,
+  )
 
   if (file && file !== '-') {
     logger.log(`Writing json to \`${file}\``)
@@ -45,6 +102,7 @@ export async function handleJson(
 
 export async function handleMarkdown(
   data: SocketSdkSuccessResult<'GetOrgDiffScan'>['data'],
+  alertDiff?: ScanAlertDiff | undefined,
 ) {
   const SOCKET_SBOM_URL_PREFIX = `${SOCKET_WEBSITE_URL}/dashboard/org/SocketDev/sbom/`
 
@@ -126,6 +184,9 @@ export async function handleMarkdown(
   }
 
   logger.log('')
+  if (alertDiff) {
+    outputAlertDiffSummary(alertDiff, data.artifacts.added.length, 'markdown')
+  }
   logger.log(`## Scan ${data.before.id}`)
   logger.log('')
   logger.log(
@@ -167,10 +228,12 @@ export async function handleMarkdown(
 export async function outputDiffScan(
   result: CResult<SocketSdkSuccessResult<'GetOrgDiffScan'>['data']>,
   {
+    alertDiff: alertDiffResult,
     depth,
     file,
     outputKind,
   }: {
+    alertDiff?: CResult<ScanAlertDiff> | undefined
     depth: number
     file: string
     outputKind: OutputKind
@@ -189,6 +252,24 @@ export async function outputDiffScan(
     return
   }
 
+  // The alert comparison decides the exit code, so failing to compute it must
+  // not let a policy gate pass silently.
+  if (alertDiffResult && !alertDiffResult.ok) {
+    process.exitCode = alertDiffResult.code ?? 1
+    if (outputKind === 'json') {
+      logger.log(serializeResultJson(alertDiffResult))
+      return
+    }
+    logger.fail(
+      failMsgWithBadge(alertDiffResult.message, alertDiffResult.cause),
+    )
+    return
+  }
+  const alertDiff = alertDiffResult?.data
+  if (alertDiff?.blocking.length) {
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+
   const dashboardUrl = result.data.diff_report_url
   const dashboardMessage = dashboardUrl
     ? `\n View this diff scan in the Socket dashboard: ${colors.cyan(dashboardUrl)}`
@@ -198,12 +279,12 @@ export async function outputDiffScan(
   // won't get truncated. The only way to dump the full raw JSON to stdout is
   // to use `--json --file -` (the dash is a standard notation for stdout)
   if (outputKind === 'json' || file) {
-    await handleJson(result, file, dashboardMessage)
+    await handleJson(result, file, dashboardMessage, alertDiff)
     return
   }
 
   if (outputKind === 'markdown') {
-    await handleMarkdown(result.data)
+    await handleMarkdown(result.data, alertDiff)
     return
   }
 
@@ -219,6 +300,13 @@ export async function outputDiffScan(
       maxArrayLength: undefined,
     }),
   )
+  if (alertDiff) {
+    outputAlertDiffSummary(
+      alertDiff,
+      result.data.artifacts.added.length,
+      'text',
+    )
+  }
   logger.error('')
   logger.info(
     ' 📝 To display the detailed report in the terminal, use the --json flag. For a friendlier report, use the --markdown flag.',
@@ -3,6 +3,8 @@ import colors from 'yoctocolors-cjs'
 
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
+import { getScanListStatus } from './fetch-list-scans.mts'
+import { recordCompletionValues } from '../../util/cli/completion-history.mts'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 
@@ -19,6 +21,10 @@ export async function outputListScans(
 ): Promise<void> {
   if (!result.ok) {
     process.exitCode = result.code ?? 1
+  } else {
+    // Offer the listed scans and repos to shell completion.
+    await recordCompletionValues('scan', result.data.results.map(d => d.id))
+    await recordCompletionValues('repo', result.data.results.map(d => d.repo))
   }
 
   if (outputKind === 'json') {
@@ -36,6 +42,8 @@ export async function outputListScans(
       { field: 'report_url', name: colors.magenta('Scan URL') },
       { field: 'repo', name: colors.magenta('Repo') },
       { field: 'branch', name: colors.magenta('Branch') },
+      { field: 'committers', name: colors.magenta('Committers') },
+      { field: 'status', name: colors.magenta('Status') },
       { field: 'created_at', name: colors.magenta('Created at') },
     ],
   }
@@ -54,6 +62,8 @@ export async function outputListScans(
         : '',
       repo: d.repo,
       branch: d.branch,
+      committers: (d.committers ?? []).join(', '),
+      status: getScanListStatus(d),
     }
   })
 
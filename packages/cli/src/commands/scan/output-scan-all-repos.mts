@@ -0,0 +1,88 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { describeRepoSource } from './discover-repos.mts'
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { RepoSource } from './discover-repos.mts'
+import type { RepoScanResult } from './scan-all-repos.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function formatNewCriticals(repo: RepoScanResult): string {
+  if (repo.status !== 'scanned') {
+    return '-'
+  }
+  return repo.newCriticals === undefined ? 'n/a' : String(repo.newCriticals)
+}
+
+function formatDetails(repo: RepoScanResult): string {
+  return repo.status === 'scanned' ? repo.reportUrl || '' : repo.reason || ''
+}
+
+export function outputScanAllRepos(
+  result: CResult<RepoScanResult[]>,
+  { outputKind, source }: { outputKind: OutputKind; source: RepoSource },
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (result.data.some(repo => repo.status === 'failed')) {
+    process.exitCode = 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const repos = result.data
+  if (!repos.length) {
+    logger.info(`Found no repositories in ${describeRepoSource(source)}`)
+    return
+  }
+  const scanned = repos.filter(repo => repo.status === 'scanned').length
+  const failed = repos.filter(repo => repo.status === 'failed').length
+  const summary = `Scanned ${scanned} of ${repos.length} ${repos.length === 1 ? 'repository' : 'repositories'}${failed ? `, ${failed} failed` : ''}`
+
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Repository scans'))
+    logger.log('')
+    logger.log(`${summary}, ranked by new critical alerts.`)
+    logger.log('')
+    logger.log(
+      mdTable(
+        repos.map(repo => ({
+          repo: repo.repo,
+          branch: repo.branch,
+          status: repo.status,
+          newCriticals: formatNewCriticals(repo),
+          details: formatDetails(repo),
+        })),
+        ['repo', 'branch', 'status', 'newCriticals', 'details'],
+        ['Repository', 'Branch', 'Status', 'New criticals', 'Details'],
+      ),
+    )
+    return
+  }
+
+  for (let i = 0, { length } = repos; i < length; i += 1) {
+    const repo = repos[i]!
+    const details = formatDetails(repo)
+    logger.log(
+      `${repo.repo} (${repo.branch || 'no branch'}): ${repo.status}, new criticals: ${formatNewCriticals(repo)}${details ? `, ${details}` : ''}`,
+    )
+  }
+  logger.log('')
+  if (failed) {
+    logger.warn(summary)
+  } else {
+    logger.success(summary)
+  }
+}
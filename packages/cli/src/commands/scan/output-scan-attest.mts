@@ -0,0 +1,69 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { ScanAttestResult } from './handle-scan-attest.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export function getRekorSearchUrl(logIndex: number): string {
+  return `https://search.sigstore.dev/?logIndex=${logIndex}`
+}
+
+export async function outputScanAttest(
+  result: CResult<ScanAttestResult>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (!result.data.passed) {
+    // The attestation is written either way, it records the failure.
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const {
+    bundlePath,
+    certificateIdentity,
+    logIndex,
+    passed,
+    scanId,
+    subjects,
+  } = result.data
+  const subjectNames = subjects.map(s => s.name).join(', ')
+  const verdict = passed ? 'passed' : 'failed'
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Scan Attestation'))
+    logger.log('')
+    logger.log(mdKeyValue('Scan ID', scanId))
+    logger.log(mdKeyValue('Result', verdict))
+    logger.log(mdKeyValue('Subjects', subjectNames))
+    logger.log(mdKeyValue('Signed by', certificateIdentity || '<unknown>'))
+    logger.log(mdKeyValue('Rekor entry', getRekorSearchUrl(logIndex)))
+    logger.log(mdKeyValue('Bundle', bundlePath))
+    return
+  }
+
+  logger.log(`Subjects: ${subjectNames}`)
+  logger.log(`Signed by: ${certificateIdentity || '<unknown>'}`)
+  logger.log(`Rekor entry: ${getRekorSearchUrl(logIndex)}`)
+  const summary = `Wrote the attestation that scan ${scanId} ${verdict} the policy to ${bundlePath}`
+  if (passed) {
+    logger.success(summary)
+  } else {
+    logger.warn(summary)
+  }
+}
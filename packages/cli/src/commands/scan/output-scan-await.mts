@@ -0,0 +1,46 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { formatDuration } from '../../util/output/progress.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { ScanAnalysisSummary } from './await-scan.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export function formatScanAnalysis(summary: ScanAnalysisSummary): string {
+  const { alerts, elapsedMs, packages, scanId } = summary
+  return `Analysis of scan ${scanId} done after ${formatDuration(elapsedMs)}: ${packages} ${pluralize('package', { count: packages })}, ${alerts.critical} critical, ${alerts.high} high, ${alerts.middle} middle and ${alerts.low} low alerts`
+}
+
+export function outputScanAwait(
+  result: CResult<ScanAnalysisSummary>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Scan Analysis'))
+    logger.log('')
+    logger.log(formatScanAnalysis(result.data))
+    logger.log('')
+    return
+  }
+
+  logger.success(formatScanAnalysis(result.data))
+}
@@ -0,0 +1,96 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { generatePolicyCheckJunitReport } from './generate-junit-report.mts'
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import { REPORT_FORMAT_JUNIT } from '../../constants/reporting.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+import { REGO_DEFAULT_PACKAGE } from '../../util/policy/rego.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { RegoPolicyResult } from '../../util/policy/rego.mts'
+
+const logger = getDefaultLogger()
+
+export type OutputScanCheckOptions = {
+  format?: 'junit' | undefined
+  regoPackage?: string | undefined
+}
+
+export async function outputScanCheck(
+  result: CResult<RegoPolicyResult>,
+  outputKind: OutputKind,
+  options?: OutputScanCheckOptions | undefined,
+): Promise<void> {
+  const { format, regoPackage = REGO_DEFAULT_PACKAGE } = {
+    __proto__: null,
+    ...options,
+  } as OutputScanCheckOptions
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (result.data.deny.length) {
+    // The check ran but the policy denied the scan.
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+
+  if (format === REPORT_FORMAT_JUNIT && result.ok) {
+    logger.log(generatePolicyCheckJunitReport(result.data, { regoPackage }))
+    return
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(
+      serializeResultJson(
+        result.ok
+          ? {
+              ok: true,
+              data: { passed: !result.data.deny.length, ...result.data },
+            }
+          : result,
+      ),
+    )
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { deny, warn } = result.data
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Policy Check'))
+    logger.log('')
+    logger.log(`Result: ${deny.length ? 'failed' : 'passed'}`)
+    if (deny.length) {
+      logger.log('')
+      logger.log(mdHeader('Denied', 2))
+      logger.log('')
+      logger.log(mdList(deny.map(v => v.msg)))
+    }
+    if (warn.length) {
+      logger.log('')
+      logger.log(mdHeader('Warnings', 2))
+      logger.log('')
+      logger.log(mdList(warn.map(v => v.msg)))
+    }
+    return
+  }
+
+  for (let i = 0, { length } = warn; i < length; i += 1) {
+    logger.warn(warn[i]!.msg)
+  }
+  for (let i = 0, { length } = deny; i < length; i += 1) {
+    logger.error(deny[i]!.msg)
+  }
+  if (deny.length) {
+    logger.fail(
+      `The scan failed the policy with ${deny.length} ${pluralize('violation', { count: deny.length })}`,
+    )
+  } else {
+    logger.success('The scan passed the policy')
+  }
+}
@@ -0,0 +1,146 @@
+/**
+ * The markdown scan report, with its health status, settings, the alerts at
+ * the report level and a section for secrets found in dependencies.
+ */
+
+import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { SECRET_ALERT_CATEGORY } from './secret-alerts.mts'
+import { FOLD_SETTING_NONE } from '../../constants/cli.mts'
+import { REPORT_LEVEL_DEFER } from '../../constants/reporting.mts'
+import { walkNestedMap } from '../../util/data/walk-nested-map.mjs'
+import { mdTable } from '../../util/output/markdown.mts'
+
+import type { ReportLeafNode, ScanReport } from './generate-report.mts'
+
+/**
+ * The markdown section listing the secret alerts of a report, with their
+ * files and redacted snippets when the report was generated with evidence.
+ * Empty when the report has none.
+ */
+function toMarkdownSecretsSection(report: ScanReport): string {
+  const rows: Array<Record<string, string>> = []
+  for (const { keys, value } of walkNestedMap(report.alerts)) {
+    const leaf = value as ReportLeafNode
+    if (leaf.category !== SECRET_ALERT_CATEGORY) {
+      continue
+    }
+    const { evidence } = leaf
+    rows.push({
+      'Alert Type': leaf.type,
+      Evidence: evidence?.snippet?.replaceAll('|', '\\|') ?? '',
+      File: evidence?.file ?? '',
+      'Introduced by': keys[2] || '<unknown>',
+      Line: evidence?.line ? String(evidence.line) : '',
+      Package: keys[1] || '<unknown>',
+    })
+  }
+  if (!rows.length) {
+    return ''
+  }
+  const hasEvidence = rows.some(row => row['File'])
+  return `
+## Secrets in dependencies
+
+${rows.length} ${pluralize('alert', { count: rows.length })} for credentials or keys embedded in the files of dependencies. ${hasEvidence ? 'Matched secrets are redacted from the snippets.' : 'Pass --show-evidence for the files they were found in and redacted snippets.'}
+
+${mdTable(
+  rows,
+  hasEvidence
+    ? ['Package', 'Introduced by', 'File', 'Line', 'Evidence']
+    : ['Package', 'Introduced by', 'Alert Type'],
+)}`
+}
+
+// socket-lint: allow boolean-trap -- collapsing into an options object would
+// change call sites in
+// test/unit/commands/scan/output-scan-report-markdown.test.mts, which is out
+// of scope for this pass.
+export function toMarkdownReport(
+  report: ScanReport,
+  includeLicensePolicy?: boolean | undefined,
+): string {
+  const reportLevel = report.options.reportLevel
+
+  const alertFolding =
+    report.options.fold === FOLD_SETTING_NONE
+      ? 'none'
+      : `up to ${report.options.fold}`
+
+  const flatData = Array.from(walkNestedMap(report.alerts)).map(
+    ({ keys, value }: { keys: string[]; value: ReportLeafNode }) => {
+      const { alertId, manifest, policy, reachability, type, url } = value
+      return {
+        'Alert ID': alertId ?? '',
+        'Alert Type': type,
+        Package: keys[1] || '<unknown>',
+        'Introduced by': keys[2] || '<unknown>',
+        url,
+        'Manifest file': joinAnd(manifest),
+        Policy: policy,
+        Reachability: reachability ?? '',
+      }
+    },
+  )
+
+  const minPolicyLevel =
+    reportLevel === REPORT_LEVEL_DEFER ? 'everything' : reportLevel
+
+  // Only reports generated with import reachability have the column.
+  const hasReachability = flatData.some(row => row.Reachability)
+  const hasAlertIds = flatData.some(row => row['Alert ID'])
+
+  const md = `${`
+# Scan Policy Report
+
+This report tells you whether the results of a Socket scan results violate the
+security${includeLicensePolicy ? ' or license' : ''} policy set by your organization.
+
+## Health status
+
+${
+  report.healthy
+    ? `The scan *PASSES* all requirements set by your security${includeLicensePolicy ? ' and license' : ''} policy.`
+    : report.options.failOn
+      ? `The scan *VIOLATES* the --fail-on ${report.options.failOn} threshold.`
+      : 'The scan *VIOLATES* one or more policies set to the "error" level.'
+}
+
+## Settings
+
+Configuration used to generate this report:
+
+- Organization: ${report.orgSlug}
+- Scan ID: ${report.scanId}
+- Alert folding: ${alertFolding}
+- Minimal policy level for alert to be included in report: ${minPolicyLevel}
+- Include license alerts: ${includeLicensePolicy ? 'yes' : 'no'}
+
+## Alerts
+
+${
+  report.alerts.size
+    ? `All the alerts from the scan with a policy set to at least "${reportLevel}".`
+    : `The scan contained no alerts with a policy set to at least "${reportLevel}".`
+}
+
+${
+  !report.alerts.size
+    ? ''
+    : mdTable(flatData, [
+        'Policy',
+        ...(hasAlertIds ? ['Alert ID' as const] : []),
+        'Alert Type',
+        'Package',
+        'Introduced by',
+        'url',
+        'Manifest file',
+        ...(hasReachability ? ['Reachability' as const] : []),
+      ])
+}
+${toMarkdownSecretsSection(report)}
+  `.trim()}\n`
+
+  return md
+}
@@ -0,0 +1,203 @@
+/**
+ * The scan report formats derived from a SARIF log: SARIF itself, the GitLab,
+ * JUnit, HTML and CSV reports, and user templates. Each sets the policy
+ * violation exit code the way a regular report does.
+ */
+
+import { readFileSync } from 'node:fs'
+import fs from 'node:fs/promises'
+import path from 'node:path'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { generateCsvReport } from './generate-csv-report.mts'
+import {
+  generateGitlabCodeQualityReport,
+  generateGitlabSastReport,
+} from './generate-gitlab-report.mts'
+import { generateHtmlReport } from './generate-html-report.mts'
+import { generateJunitReport } from './generate-junit-report.mts'
+import { generateReport } from './generate-report.mts'
+import { generateSarifReport } from './generate-sarif-report.mts'
+import { generateScanReportTemplateContext } from './generate-template-report.mts'
+import { FOLD_SETTING_NONE } from '../../constants/cli.mts'
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import {
+  REPORT_FORMAT_CSV,
+  REPORT_FORMAT_GITLAB_CODE_QUALITY,
+  REPORT_FORMAT_GITLAB_SAST,
+  REPORT_FORMAT_HTML,
+  REPORT_FORMAT_JUNIT,
+  REPORT_FORMAT_SARIF,
+} from '../../constants/reporting.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { renderTemplateFile } from '../../util/output/template.mts'
+
+import type { ScanReportCsvColumn } from './generate-csv-report.mts'
+import type { SarifLog } from './generate-sarif-report.mts'
+import type { FAIL_ON, REPORT_FORMAT, REPORT_LEVEL } from './types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { FoundSocketBaseline } from '../../util/policy/baseline.mts'
+import type { SocketPolicy } from '../../util/policy/socket-policy.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+const logger = getDefaultLogger()
+
+export type ReportData = {
+  scan: SocketArtifact[]
+  securityPolicy: SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+}
+
+type SarifOptions = {
+  baseline?: FoundSocketBaseline | undefined
+  cwd?: string | undefined
+  failOn?: FAIL_ON | undefined
+  localPolicy?: SocketPolicy | undefined
+  reportLevel: REPORT_LEVEL
+}
+
+function buildSarifReport(
+  data: ReportData,
+  {
+    baseline,
+    cwd = process.cwd(),
+    failOn,
+    localPolicy,
+    reportLevel,
+  }: SarifOptions,
+): SarifLog {
+  const sarif = generateSarifReport(data.scan, data.securityPolicy, {
+    baseline,
+    localPolicy,
+    readManifest: (file: string) => {
+      try {
+        return readFileSync(path.resolve(cwd, file), 'utf8')
+      } catch {
+        return undefined
+      }
+    },
+    reportLevel,
+  })
+
+  // Error-level results are exactly the policy violations that make a
+  // regular report unhealthy, so keep the same exit code contract. With
+  // --fail-on the severities decide, as they do for a regular report.
+  let violates = sarif.runs[0]!.results.some(r => r.level === 'error')
+  if (failOn) {
+    const shortReport = generateReport(data.scan, data.securityPolicy, {
+      baseline,
+      failOn,
+      fold: FOLD_SETTING_NONE,
+      localPolicy,
+      orgSlug: '',
+      reportLevel,
+      scanId: '',
+      short: true,
+    })
+    violates = shortReport.ok && !shortReport.data.healthy
+  }
+  if (violates) {
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+  return sarif
+}
+
+async function writeReport(
+  content: string,
+  filepath: string,
+  label: string,
+): Promise<void> {
+  if (filepath && filepath !== '-') {
+    logger.error(`Writing ${label} report to`, filepath)
+    await fs.writeFile(filepath, content)
+    return
+  }
+  logger.log(content)
+}
+
+/**
+ * Write a SARIF log, or one of the GitLab, JUnit, HTML or CSV reports derived
+ * from it.
+ */
+export async function outputSarifReport(
+  data: ReportData,
+  {
+    columns,
+    csvHeader,
+    filepath,
+    format = REPORT_FORMAT_SARIF,
+    orgSlug = '',
+    scanId = '',
+    ...sarifOptions
+  }: SarifOptions & {
+    columns?: readonly ScanReportCsvColumn[] | undefined
+    csvHeader?: boolean | undefined
+    filepath: string
+    format?: REPORT_FORMAT | undefined
+    orgSlug?: string | undefined
+    scanId?: string | undefined
+  },
+): Promise<void> {
+  const startTime = new Date()
+  const sarif = buildSarifReport(data, sarifOptions)
+
+  let content: string
+  if (format === REPORT_FORMAT_HTML) {
+    content = generateHtmlReport(data.scan, sarif, {
+      generatedAt: startTime,
+      orgSlug,
+      scanId,
+    })
+  } else if (format === REPORT_FORMAT_JUNIT) {
+    content = generateJunitReport(sarif, { timestamp: startTime })
+  } else if (format === REPORT_FORMAT_CSV) {
+    content = generateCsvReport(data.scan, sarif, {
+      columns,
+      header: csvHeader,
+    })
+  } else {
+    const report =
+      format === REPORT_FORMAT_GITLAB_CODE_QUALITY
+        ? generateGitlabCodeQualityReport(sarif)
+        : format === REPORT_FORMAT_GITLAB_SAST
+          ? generateGitlabSastReport(sarif, { startTime })
+          : sarif
+    content = `${JSON.stringify(report, null, 2)}\n`
+  }
+  await writeReport(content, filepath, format)
+}
+
+/**
+ * Render the report with a user template, see --output-template.
+ */
+export async function outputTemplateReport(
+  data: ReportData,
+  {
+    filepath,
+    orgSlug,
+    outputTemplate,
+    scanId,
+    ...sarifOptions
+  }: SarifOptions & {
+    filepath: string
+    orgSlug: string
+    outputTemplate: string
+    scanId: string
+  },
+): Promise<void> {
+  const sarif = buildSarifReport(data, sarifOptions)
+  const renderCResult = renderTemplateFile(
+    outputTemplate,
+    generateScanReportTemplateContext(sarif, {
+      orgSlug,
+      reportLevel: sarifOptions.reportLevel,
+      scanId,
+    }),
+  )
+  if (!renderCResult.ok) {
+    process.exitCode = renderCResult.code ?? 1
+    logger.fail(failMsgWithBadge(renderCResult.message, renderCResult.cause))
+    return
+  }
+  await writeReport(renderCResult.data, filepath, 'template')
+}
@@ -1,36 +1,59 @@
 import fs from 'node:fs/promises'
 
-import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
 
 import { generateReport } from './generate-report.mts'
+import { toMarkdownReport } from './output-scan-report-markdown.mts'
 import {
-  FOLD_SETTING_NONE,
-  OUTPUT_JSON,
-  OUTPUT_TEXT,
-} from '../../constants/cli.mts'
-import { REPORT_LEVEL_DEFER } from '../../constants/reporting.mts'
+  outputSarifReport,
+  outputTemplateReport,
+} from './output-scan-report-sarif.mts'
+import { OUTPUT_JSON, OUTPUT_TEXT } from '../../constants/cli.mts'
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
 import { mapToObject } from '../../util/data/map-to-object.mjs'
-import { walkNestedMap } from '../../util/data/walk-nested-map.mjs'
 import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
-import { mdTable } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 
-import type { ReportLeafNode, ScanReport } from './generate-report.mts'
-import type { FOLD_SETTING, REPORT_LEVEL } from './types.mts'
+import type { ScanReportCsvColumn } from './generate-csv-report.mts'
+import type { ScanReport } from './generate-report.mts'
+import type { ImportReachability } from './import-reachability.mts'
+import type {
+  FAIL_ON,
+  FOLD_SETTING,
+  REPORT_FORMAT,
+  REPORT_LEVEL,
+} from './types.mts'
 import type { CResult, OutputKind } from '../../types.mts'
 import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { FoundSocketBaseline } from '../../util/policy/baseline.mts'
+import type { SocketPolicy } from '../../util/policy/socket-policy.mts'
 import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
 const logger = getDefaultLogger()
 
 export type OutputScanReportConfig = {
+  baseline?: FoundSocketBaseline | undefined
+  // Columns and header row of --format=csv, see --columns.
+  columns?: readonly ScanReportCsvColumn[] | undefined
+  csvHeader?: boolean | undefined
+  // Directory the evidence of secret alerts is read under, see
+  // --show-evidence.
+  evidenceCwd?: string | undefined
+  // Alert threshold that fails the report, see --fail-on.
+  failOn?: FAIL_ON | undefined
   orgSlug: string
   scanId: string
   includeLicensePolicy: boolean
   outputKind: OutputKind
   filepath: string
   fold: FOLD_SETTING
+  format?: REPORT_FORMAT | undefined
+  localPolicy?: SocketPolicy | undefined
+  // Template file to render the report with, see --output-template.
+  outputTemplate?: string | undefined
+  // Import reachability by artifact id, shown with each alert.
+  reachability?: Map<string, ImportReachability> | undefined
   reportLevel: REPORT_LEVEL
   short: boolean
 }
@@ -41,11 +64,20 @@ export async function outputScanReport(
     securityPolicy: SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
   }>,
   {
+    baseline,
+    columns,
+    csvHeader,
+    evidenceCwd,
+    failOn,
     filepath,
     fold,
+    format,
     includeLicensePolicy,
+    localPolicy,
     orgSlug,
     outputKind,
+    outputTemplate,
+    reachability,
     reportLevel,
     scanId,
     short,
@@ -64,14 +96,49 @@ export async function outputScanReport(
     return
   }
 
+  if (outputTemplate) {
+    await outputTemplateReport(result.data, {
+      baseline,
+      failOn,
+      filepath,
+      localPolicy,
+      orgSlug,
+      outputTemplate,
+      reportLevel,
+      scanId,
+    })
+    return
+  }
+
+  if (format) {
+    await outputSarifReport(result.data, {
+      baseline,
+      columns,
+      csvHeader,
+      failOn,
+      filepath,
+      format,
+      localPolicy,
+      orgSlug,
+      reportLevel,
+      scanId,
+    })
+    return
+  }
+
   const spinner = getDefaultSpinner()
   const scanReport = generateReport(
     result.data.scan,
     result.data.securityPolicy,
     {
+      baseline,
+      evidenceCwd,
+      failOn,
       orgSlug,
       scanId,
       fold,
+      localPolicy,
+      reachability,
       reportLevel,
       short,
       spinner,
@@ -93,7 +160,7 @@ export async function outputScanReport(
 
   if (!scanReport.data.healthy) {
     // When report contains healthy: false, process should exit with non-zero code.
-    process.exitCode = 1
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
   }
 
   // I don't think we emit the default error message with banner for an unhealthy report, do we?
@@ -165,83 +232,3 @@ export function toJsonReport(
     data: newReport,
   })
 }
-
-// socket-lint: allow boolean-trap -- collapsing into an options object would
-// change call sites in test/unit/commands/scan/output-scan-report.test.mts,
-// which is out of scope for this pass.
-export function toMarkdownReport(
-  report: ScanReport,
-  includeLicensePolicy?: boolean | undefined,
-): string {
-  const reportLevel = report.options.reportLevel
-
-  const alertFolding =
-    report.options.fold === FOLD_SETTING_NONE
-      ? 'none'
-      : `up to ${report.options.fold}`
-
-  const flatData = Array.from(walkNestedMap(report.alerts)).map(
-    ({ keys, value }: { keys: string[]; value: ReportLeafNode }) => {
-      const { manifest, policy, type, url } = value
-      return {
-        'Alert Type': type,
-        Package: keys[1] || '<unknown>',
-        'Introduced by': keys[2] || '<unknown>',
-        url,
-        'Manifest file': joinAnd(manifest),
-        Policy: policy,
-      }
-    },
-  )
-
-  const minPolicyLevel =
-    reportLevel === REPORT_LEVEL_DEFER ? 'everything' : reportLevel
-
-  const md = `${`
-# Scan Policy Report
-
-This report tells you whether the results of a Socket scan results violate the
-security${includeLicensePolicy ? ' or license' : ''} policy set by your organization.
-
-## Health status
-
-${
-  report.healthy
-    ? `The scan *PASSES* all requirements set by your security${includeLicensePolicy ? ' and license' : ''} policy.`
-    : 'The scan *VIOLATES* one or more policies set to the "error" level.'
-}
-
-## Settings
-
-Configuration used to generate this report:
-
-- Organization: ${report.orgSlug}
-- Scan ID: ${report.scanId}
-- Alert folding: ${alertFolding}
-- Minimal policy level for alert to be included in report: ${minPolicyLevel}
-- Include license alerts: ${includeLicensePolicy ? 'yes' : 'no'}
-
-## Alerts
-
-${
-  report.alerts.size
-    ? `All the alerts from the scan with a policy set to at least "${reportLevel}".`
-    : `The scan contained no alerts with a policy set to at least "${reportLevel}".`
-}
-
-${
-  !report.alerts.size
-    ? ''
-    : mdTable(flatData, [
-        'Policy',
-        'Alert Type',
-        'Package',
-        'Introduced by',
-        'url',
-        'Manifest file',
-      ])
-}
-  `.trim()}\n`
-
-  return md
-}
@@ -8,8 +8,10 @@ import { mdTable } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 import { fileLink } from '../../util/terminal/link.mts'
 
+import type { TriageItem } from './scan-view-triage.mts'
 import type { CResult, OutputKind } from '../../types.mts'
 import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
 const logger = getDefaultLogger()
 
 export async function outputScanView(
@@ -111,3 +113,33 @@ View this report at: ${SOCKET_WEBSITE_URL}/dashboard/org/${orgSlug}/sbom/${scanI
     logger.log(report)
   }
 }
+
+/**
+ * Summary printed after `--interactive` exits. An empty policyPath means the
+ * decisions went to the org triage API.
+ */
+export function outputScanViewTriage(
+  items: TriageItem[],
+  policyPath: string,
+): void {
+  let ignored = 0
+  let accepted = 0
+  for (let i = 0, { length } = items; i < length; i += 1) {
+    const { status } = items[i]!
+    if (status === 'ignored') {
+      ignored += 1
+    } else if (status === 'accepted') {
+      accepted += 1
+    }
+  }
+  if (!ignored && !accepted) {
+    logger.info('No alerts were triaged')
+    return
+  }
+  const where = policyPath
+    ? `exceptions in ${fileLink(policyPath)}`
+    : 'the org triage settings'
+  logger.success(
+    `Ignored ${ignored} and accepted ${accepted} of ${items.length} alerts; saved to ${where}`,
+  )
+}
@@ -0,0 +1,71 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { formatDiffAlert } from './output-diff-scan.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { toJsonEnvelope } from '../../util/output/json-envelope.mts'
+
+import type { ScanWatchEvent } from './handle-scan-watch.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function formatTime(date: Date): string {
+  return date.toTimeString().slice(0, 8)
+}
+
+/**
+ * One line of the compact delta printed after each round, e.g.
+ * `[12:00:00] package.json: +2 alerts (1 blocking), -1 resolved`.
+ */
+export function formatScanWatchSummary(
+  event: ScanWatchEvent,
+  now: Date = new Date(),
+): string {
+  const { alerts } = event
+  if (event.initial) {
+    return `[${formatTime(now)}] Watching ${event.fileCount} ${pluralize('file', { count: event.fileCount })}: ${event.totalAlerts} ${pluralize('alert', { count: event.totalAlerts })} (${event.totalBlocking} blocking)`
+  }
+  const files = event.changedFiles.join(', ')
+  if (!alerts.added.length && !alerts.removed.length) {
+    return `[${formatTime(now)}] ${files}: no alert changes`
+  }
+  return `[${formatTime(now)}] ${files}: +${alerts.added.length} ${pluralize('alert', { count: alerts.added.length })} (${alerts.blocking.length} blocking), -${alerts.removed.length} resolved`
+}
+
+export function outputScanWatch(
+  result: CResult<ScanWatchEvent>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  // Each round is one line of JSON so the stream can be consumed as NDJSON.
+  if (outputKind === 'json') {
+    logger.log(JSON.stringify(toJsonEnvelope(result)))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const event = result.data
+  const summary = formatScanWatchSummary(event)
+  if (event.alerts.blocking.length) {
+    logger.fail(summary)
+  } else if (event.alerts.added.length) {
+    logger.warn(summary)
+  } else {
+    logger.success(summary)
+  }
+  const blocking = new Set(event.alerts.blocking)
+  for (let i = 0, { length } = event.alerts.added; i < length; i += 1) {
+    const alert = event.alerts.added[i]!
+    logger.log(`  ${blocking.has(alert) ? '!' : '+'} ${formatDiffAlert(alert)}`)
+  }
+  for (let i = 0, { length } = event.alerts.removed; i < length; i += 1) {
+    logger.log(`  - ${formatDiffAlert(event.alerts.removed[i]!)}`)
+  }
+}
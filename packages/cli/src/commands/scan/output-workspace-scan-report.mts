@@ -0,0 +1,234 @@
+import fs from 'node:fs/promises'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { generateWorkspaceReports } from './scan-workspaces.mts'
+import { OUTPUT_JSON } from '../../constants/cli.mts'
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import { mapToObject } from '../../util/data/map-to-object.mjs'
+import { walkNestedMap } from '../../util/data/walk-nested-map.mjs'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { ReportLeafNode, ScanReport } from './generate-report.mts'
+import type { ImportReachability } from './import-reachability.mts'
+import type {
+  WorkspaceScanReport,
+  WorkspaceSelection,
+} from './scan-workspaces.mts'
+import type { FAIL_ON, FOLD_SETTING, REPORT_LEVEL } from './types.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { FoundSocketBaseline } from '../../util/policy/baseline.mts'
+import type { SocketPolicy } from '../../util/policy/socket-policy.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+const logger = getDefaultLogger()
+
+export type OutputWorkspaceScanReportConfig = {
+  baseline?: FoundSocketBaseline | undefined
+  // Alert threshold that fails a workspace, see --fail-on.
+  failOn?: FAIL_ON | undefined
+  filepath: string
+  fold: FOLD_SETTING
+  localPolicy?: SocketPolicy | undefined
+  orgSlug: string
+  outputKind: OutputKind
+  // Import reachability by artifact id, shown with each alert.
+  reachability?: Map<string, ImportReachability> | undefined
+  reportLevel: REPORT_LEVEL
+  scanId: string
+  short: boolean
+}
+
+type WorkspaceAlertRow = {
+  'Alert Type': string
+  Package: string
+  Policy: string
+  Version: string
+}
+
+function getAlertRows(
+  report: WorkspaceScanReport['report'],
+): WorkspaceAlertRow[] {
+  if (!('alerts' in report)) {
+    return []
+  }
+  return Array.from(walkNestedMap((report as ScanReport).alerts)).map(
+    ({ keys, value }: { keys: string[]; value: ReportLeafNode }) => ({
+      'Alert Type': value.type,
+      Package: keys[1] || '<unknown>',
+      Policy: value.policy,
+      Version: keys[2] || '',
+    }),
+  )
+}
+
+function formatWorkspace({ name, path }: WorkspaceScanReport): string {
+  return name === path ? path : `${name} (${path})`
+}
+
+export function toJsonWorkspaceReport(
+  reports: WorkspaceScanReport[],
+  { orgSlug, scanId }: { orgSlug: string; scanId: string },
+): string {
+  return serializeResultJson({
+    ok: true,
+    data: {
+      healthy: reports.every(r => r.report.healthy),
+      orgSlug,
+      scanId,
+      workspaces: reports.map(({ name, path, report }) => ({
+        name,
+        path,
+        ...('alerts' in report
+          ? { ...report, alerts: mapToObject((report as ScanReport).alerts) }
+          : report),
+      })),
+    },
+  })
+}
+
+export function toMarkdownWorkspaceReport(
+  reports: WorkspaceScanReport[],
+  { orgSlug, scanId }: { orgSlug: string; scanId: string },
+): string {
+  const lines = [
+    mdHeader('Scan Policy Report per Workspace'),
+    '',
+    `Organization: ${orgSlug}, Scan ID: ${scanId}`,
+    '',
+    mdTable(
+      reports.map(r => ({
+        Workspace: r.name,
+        Path: r.path,
+        Status: r.report.healthy ? 'PASS' : 'FAIL',
+        Alerts: String(getAlertRows(r.report).length),
+      })),
+      ['Workspace', 'Path', 'Status', 'Alerts'],
+    ),
+  ]
+  for (let i = 0, { length } = reports; i < length; i += 1) {
+    const rows = getAlertRows(reports[i]!.report)
+    if (rows.length) {
+      lines.push(
+        '',
+        mdHeader(formatWorkspace(reports[i]!), 2),
+        '',
+        mdTable(rows, ['Policy', 'Alert Type', 'Package', 'Version']),
+      )
+    }
+  }
+  return `${lines.join('\n')}\n`
+}
+
+function logWorkspaceReports(reports: WorkspaceScanReport[]): void {
+  if (!reports.length) {
+    logger.info('No workspace selected, nothing to report')
+    return
+  }
+  for (let i = 0, { length } = reports; i < length; i += 1) {
+    const workspace = reports[i]!
+    const rows = getAlertRows(workspace.report)
+    const summary = `${formatWorkspace(workspace)}: ${workspace.report.healthy ? 'OK' : 'ERR'}, ${rows.length} ${pluralize('alert', { count: rows.length })}`
+    if (workspace.report.healthy) {
+      logger.success(summary)
+    } else {
+      logger.fail(summary)
+    }
+    for (let j = 0, { length: rowCount } = rows; j < rowCount; j += 1) {
+      const row = rows[j]!
+      const version = row.Version ? `@${row.Version}` : ''
+      logger.log(
+        `  ${row.Policy}: ${row['Alert Type']} in ${row.Package}${version}`,
+      )
+    }
+  }
+  const failed = reports.filter(r => !r.report.healthy).length
+  if (failed) {
+    logger.fail(
+      `${failed} of ${reports.length} ${pluralize('workspace', { count: reports.length })} violate the policies`,
+    )
+  } else {
+    logger.success(
+      `All ${reports.length} ${pluralize('workspace', { count: reports.length })} pass the policies`,
+    )
+  }
+}
+
+/**
+ * The report of `socket scan report` and `socket scan create --report` split
+ * per monorepo workspace. Exits with code 3 when any workspace fails.
+ */
+export async function outputWorkspaceScanReport(
+  result: CResult<{
+    scan: SocketArtifact[]
+    securityPolicy: SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+  }>,
+  selection: WorkspaceSelection,
+  {
+    baseline,
+    failOn,
+    filepath,
+    fold,
+    localPolicy,
+    orgSlug,
+    outputKind,
+    reachability,
+    reportLevel,
+    scanId,
+    short,
+  }: OutputWorkspaceScanReportConfig,
+): Promise<void> {
+  const reportsCResult = result.ok
+    ? generateWorkspaceReports(
+        result.data.scan,
+        result.data.securityPolicy,
+        selection,
+        {
+          baseline,
+          failOn,
+          fold,
+          localPolicy,
+          orgSlug,
+          reachability,
+          reportLevel,
+          scanId,
+          short,
+        },
+      )
+    : result
+  if (!reportsCResult.ok) {
+    process.exitCode = reportsCResult.code ?? 1
+    if (outputKind === OUTPUT_JSON) {
+      logger.log(serializeResultJson(reportsCResult))
+      return
+    }
+    logger.fail(failMsgWithBadge(reportsCResult.message, reportsCResult.cause))
+    return
+  }
+
+  const reports = reportsCResult.data
+  if (reports.some(r => !r.report.healthy)) {
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+
+  let content: string | undefined
+  if (outputKind === OUTPUT_JSON || filepath.endsWith('.json')) {
+    content = toJsonWorkspaceReport(reports, { orgSlug, scanId })
+  } else if (outputKind === 'markdown' || filepath.endsWith('.md')) {
+    content = toMarkdownWorkspaceReport(reports, { orgSlug, scanId })
+  }
+  if (content === undefined) {
+    logWorkspaceReports(reports)
+    return
+  }
+  if (filepath && filepath !== '-') {
+    logger.error('Writing workspace report to', filepath)
+    await fs.writeFile(filepath, content)
+    return
+  }
+  logger.log(content)
+}
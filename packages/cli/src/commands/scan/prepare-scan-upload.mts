@@ -0,0 +1,81 @@
+/**
+ * The files `socket scan create` uploads for a new scan: the collected scan
+ * paths plus the install script transcript, with lockfiles resolved locally
+ * where the Socket API needs it and facts files compressed.
+ */
+
+import { existsSync } from 'node:fs'
+import path from 'node:path'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { DOT_SOCKET_DOT_INSTALL_SCRIPTS_JSON } from '../../constants/paths.mts'
+import { compressSocketFactsForUpload } from '../../util/coana/compress-facts.mts'
+import { createSupportedFilesFilter } from '../../util/fs/glob.mts'
+import { prepareLockfilesForUpload } from '../../util/lockfile/upload.mts'
+
+import type { SupportedFiles } from '../../util/fs/glob.mts'
+
+const logger = getDefaultLogger()
+
+export type ScanUpload = {
+  // Removes the generated SBOM and compressed files, call once uploaded.
+  cleanup: () => Promise<void>
+  paths: string[]
+}
+
+export async function prepareScanUpload(
+  scanPaths: string[],
+  { cwd, supportedFiles }: { cwd: string; supportedFiles: SupportedFiles },
+): Promise<ScanUpload> {
+  // The transcript `socket npm --sandbox-scripts` records goes with the
+  // scan as evidence of what the install scripts attempted.
+  const installScriptsPath = path.join(cwd, DOT_SOCKET_DOT_INSTALL_SCRIPTS_JSON)
+  const uploadPaths =
+    existsSync(installScriptsPath) && !scanPaths.includes(installScriptsPath)
+      ? [...scanPaths, installScriptsPath]
+      : scanPaths
+
+  // Lockfiles the Socket API cannot ingest (or fully resolve) on its own are
+  // parsed locally and uploaded as one generated CycloneDX SBOM instead.
+  const lockfileScan = await prepareLockfilesForUpload(uploadPaths, {
+    cwd,
+    isSupportedByApi: createSupportedFilesFilter(supportedFiles),
+  })
+  if (lockfileScan.lockfiles.length) {
+    logger.info(
+      `Resolved ${lockfileScan.lockfiles.length} ${pluralize('lockfile', { count: lockfileScan.lockfiles.length })} locally: ${lockfileScan.lockfiles.map(l => l.file).join(', ')}`,
+    )
+  }
+  // Socket analyzes the published version of a patched package, so the
+  // patches themselves are worth a review.
+  const patched = lockfileScan.lockfiles.flatMap(l =>
+    l.dependencies.filter(d => d.patches?.length),
+  )
+  if (patched.length) {
+    logger.warn(
+      `Found ${patched.length} locally patched ${pluralize('package', { count: patched.length })}, alerts are those of the published versions:`,
+    )
+    for (const dep of patched) {
+      logger.warn(
+        `  ${dep.namespace ? `${dep.namespace}/` : ''}${dep.name}@${dep.version} (${dep.patches!.map(p => p.path).join(', ')})`,
+      )
+    }
+  }
+
+  // Brotli-compress any .socket.facts.json paths just before upload.
+  // depscan's api-v0 multipart boundary streams brotli decode based on the
+  // .br filename suffix. Coana keeps writing plain .socket.facts.json on
+  // disk, so the local read path (extractTier1ReachabilityScanId) stays
+  // correct. The cleanup removes the sibling .br files whether the upload
+  // succeeded or threw.
+  const compressed = await compressSocketFactsForUpload(lockfileScan.paths)
+  return {
+    cleanup: async () => {
+      await compressed.cleanup()
+      await lockfileScan.cleanup()
+    },
+    paths: compressed.paths,
+  }
+}
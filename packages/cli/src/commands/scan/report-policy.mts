@@ -0,0 +1,151 @@
+/**
+ * The policy side of scan reports: the action an alert gets once a
+ * socket.baseline.json and a local socket.policy.yml apply, the alerts the
+ * local policy raises itself, and whether an action is listed at the report
+ * level or makes the report unhealthy.
+ */
+
+import {
+  FAIL_ON_ANY,
+  FAIL_ON_NONE,
+  REPORT_LEVEL_DEFER,
+  REPORT_LEVEL_ERROR,
+  REPORT_LEVEL_IGNORE,
+  REPORT_LEVEL_MONITOR,
+  REPORT_LEVEL_WARN,
+} from '../../constants/reporting.mts'
+import { isBaselinedAlert } from '../../util/policy/baseline.mts'
+import {
+  applyLocalTrust,
+  getLocalLicenseViolation,
+  getLocalPolicyAction,
+  isSeverityAtLeast,
+  LOCAL_DISTRUST_POLICY_ALERT,
+  LOCAL_LICENSE_POLICY_ALERT,
+} from '../../util/policy/evaluate.mts'
+
+import type { FAIL_ON, REPORT_LEVEL } from './types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { FoundSocketBaseline } from '../../util/policy/baseline.mts'
+import type { LocalTrust } from '../../util/policy/evaluate.mts'
+import type { SocketPolicy } from '../../util/policy/socket-policy.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+export type ArtifactAlert = NonNullable<SocketArtifact['alerts']>[number]
+
+type SecurityPolicy = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+export type ReportPolicy = {
+  // Alerts recorded in a socket.baseline.json are left out of the report.
+  baseline?: FoundSocketBaseline | undefined
+  // A repo socket.policy.yml whose matching rules override the org policy.
+  localPolicy?: SocketPolicy | undefined
+  securityRules?: SecurityPolicy['securityPolicyRules'] | undefined
+}
+
+/**
+ * Whether an alert makes the report unhealthy. Without --fail-on that is an
+ * `error` policy action; with it, the alert severity decides, and alerts the
+ * policy ignores never count.
+ */
+export function isFailOnViolation(
+  failOn: FAIL_ON | undefined,
+  action: REPORT_LEVEL,
+  severity: string | undefined,
+): boolean {
+  if (!failOn) {
+    return action === REPORT_LEVEL_ERROR
+  }
+  if (failOn === FAIL_ON_NONE || action === REPORT_LEVEL_IGNORE) {
+    return false
+  }
+  return failOn === FAIL_ON_ANY || isSeverityAtLeast(severity, failOn)
+}
+
+/**
+ * Whether an alert with this policy action is listed at the report level.
+ * The levels rank error > warn > monitor > ignore > defer.
+ */
+export function isReportedAction(
+  action: REPORT_LEVEL,
+  reportLevel: REPORT_LEVEL,
+): boolean {
+  switch (action) {
+    case REPORT_LEVEL_ERROR: {
+      return true
+    }
+    case REPORT_LEVEL_WARN: {
+      return reportLevel !== REPORT_LEVEL_ERROR
+    }
+    case REPORT_LEVEL_MONITOR: {
+      return (
+        reportLevel !== REPORT_LEVEL_WARN && reportLevel !== REPORT_LEVEL_ERROR
+      )
+    }
+    case REPORT_LEVEL_IGNORE: {
+      return (
+        reportLevel !== REPORT_LEVEL_MONITOR &&
+        reportLevel !== REPORT_LEVEL_WARN &&
+        reportLevel !== REPORT_LEVEL_ERROR
+      )
+    }
+    case REPORT_LEVEL_DEFER: {
+      // Not sure but ignore for now. Defer to later ;)
+      return reportLevel === REPORT_LEVEL_DEFER
+    }
+    default: {
+      // This value was not emitted from the Socket API at the time of writing.
+      return false
+    }
+  }
+}
+
+/**
+ * The policy action of an alert, or undefined when the baseline records it.
+ * A matching local rule replaces the org policy action, and trusted packages
+ * have their warn alerts ignored.
+ */
+export function getAlertAction(
+  { baseline, localPolicy, securityRules }: ReportPolicy,
+  artifact: SocketArtifact,
+  alert: ArtifactAlert,
+  trust: LocalTrust | undefined,
+): REPORT_LEVEL | undefined {
+  if (baseline && isBaselinedAlert(baseline, artifact, alert.type)) {
+    return undefined
+  }
+  return applyLocalTrust(
+    (localPolicy && getLocalPolicyAction(localPolicy, artifact, alert)) ||
+      securityRules?.[alert.type]?.action ||
+      '',
+    trust,
+  ) as REPORT_LEVEL
+}
+
+/**
+ * The alerts the local policy raises itself, each an error: a license outside
+ * its allow/deny lists and a distrusted package. Alerts the baseline records
+ * are left out.
+ */
+export function getLocalPolicyAlerts(
+  { baseline, localPolicy }: ReportPolicy,
+  artifact: SocketArtifact,
+  trust: LocalTrust | undefined,
+): ArtifactAlert[] {
+  const alerts: ArtifactAlert[] = []
+  if (localPolicy && getLocalLicenseViolation(localPolicy, artifact)) {
+    alerts.push({
+      type: LOCAL_LICENSE_POLICY_ALERT,
+      severity: 'high',
+    } as ArtifactAlert)
+  }
+  if (trust === 'distrusted') {
+    alerts.push({
+      type: LOCAL_DISTRUST_POLICY_ALERT,
+      severity: 'critical',
+    } as ArtifactAlert)
+  }
+  return baseline
+    ? alerts.filter(a => !isBaselinedAlert(baseline, artifact, a.type))
+    : alerts
+}
@@ -0,0 +1,242 @@
+/**
+ * Worker pool behind `socket scan create --all-repos`.
+ *
+ * Every discovered repository is scanned on its own: remote repositories are
+ * shallow-cloned into a temporary directory first, then the manifests are
+ * collected with the repository's socket.yml and uploaded as a full scan.
+ * Each new scan is compared with the previous scan of the default branch so
+ * the consolidated report can rank the repositories by new critical alerts.
+ * A failing repository is recorded and does not stop the others.
+ */
+
+import { promises as fs } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+import { pEach } from '@socketsecurity/lib-stable/promises/iterate'
+
+import { cloneDiscoveredRepo } from './discover-repos.mts'
+import { fetchCreateOrgFullScan } from './fetch-create-org-full-scan.mts'
+import { fetchSupportedScanFileNames } from './fetch-supported-scan-file-names.mts'
+import { getScanNotifySummary } from './scan-notify.mts'
+import { SCAN_TYPE_SOCKET } from '../../constants.mts'
+import { findSocketYmlSync } from '../../util/config.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import { getPackageFilesForScan } from '../../util/fs/path-resolve.mts'
+import { gitResolveCommit } from '../../util/git/operations.mts'
+
+import type { DiscoveredRepo, RepoSource } from './discover-repos.mts'
+import type { CResult } from '../../types.mts'
+import type { SupportedFiles } from '../../util/fs/glob.mts'
+import type { SpinnerInstance } from '@socketsecurity/lib-stable/spinner/types'
+
+export type RepoScanStatus = 'failed' | 'scanned' | 'skipped'
+
+export type RepoScanResult = {
+  branch: string
+  // Unset when there was no earlier scan of the default branch to compare
+  // with, or the comparison failed.
+  newCriticals?: number | undefined
+  reason?: string | undefined
+  repo: string
+  reportUrl?: string | undefined
+  scanId?: string | undefined
+  status: RepoScanStatus
+}
+
+export type ScanAllReposConfig = {
+  concurrency: number
+  orgSlug: string
+  repos: DiscoveredRepo[]
+  source: RepoSource
+  spinner?: SpinnerInstance | undefined
+}
+
+function formatReason(cResult: {
+  cause?: string | undefined
+  message: string
+}): string {
+  return cResult.cause
+    ? `${cResult.message}: ${cResult.cause}`
+    : cResult.message
+}
+
+/**
+ * Failed repositories go last, the others by new critical alerts with the
+ * most first and then by name.
+ */
+export function rankRepoScanResults(
+  results: RepoScanResult[],
+): RepoScanResult[] {
+  return results.slice().sort((a, b) => {
+    const aFailed = a.status === 'failed' ? 1 : 0
+    const bFailed = b.status === 'failed' ? 1 : 0
+    if (aFailed !== bFailed) {
+      return aFailed - bFailed
+    }
+    const byCriticals = (b.newCriticals ?? 0) - (a.newCriticals ?? 0)
+    return byCriticals || a.repo.localeCompare(b.repo)
+  })
+}
+
+async function scanRepoDir(
+  repo: DiscoveredRepo,
+  dir: string,
+  commitHash: string,
+  orgSlug: string,
+  supportedFiles: SupportedFiles,
+): Promise<RepoScanResult> {
+  const { branch, isDefaultBranch, name } = repo
+  const socketYmlResult = findSocketYmlSync(dir)
+  const packagePaths = await getPackageFilesForScan(['.'], supportedFiles, {
+    config: socketYmlResult.ok ? socketYmlResult.data?.parsed : undefined,
+    cwd: dir,
+    localLockfiles: true,
+  })
+  if (!packagePaths.length) {
+    return {
+      branch,
+      reason: 'No supported manifest files',
+      repo: name,
+      status: 'skipped',
+    }
+  }
+
+  const scanCResult = await fetchCreateOrgFullScan(
+    packagePaths,
+    orgSlug,
+    {
+      branchName: branch,
+      commitHash,
+      commitMessage: '',
+      committers: '',
+      pullRequest: 0,
+      repoName: name,
+      scanType: SCAN_TYPE_SOCKET,
+    },
+    {
+      cwd: dir,
+      ...(isDefaultBranch ? { defaultBranch: true, pendingHead: true } : {}),
+    },
+  )
+  if (!scanCResult.ok) {
+    return {
+      branch,
+      reason: formatReason(scanCResult),
+      repo: name,
+      status: 'failed',
+    }
+  }
+  const scanId = scanCResult.data.id ?? ''
+  const reportUrl = scanCResult.data.html_report_url ?? undefined
+
+  const summaryCResult = await getScanNotifySummary({
+    branchName: branch,
+    orgSlug,
+    repoName: name,
+    reportUrl,
+    scanId,
+  })
+  if (!summaryCResult.ok) {
+    debugDir({
+      message: `Comparing the scan of ${name} failed`,
+      summaryCResult,
+    })
+  }
+  const summary = summaryCResult.ok ? summaryCResult.data : undefined
+  return {
+    branch,
+    newCriticals: summary?.baseScanId ? summary.newCriticals.length : undefined,
+    repo: name,
+    reportUrl,
+    scanId,
+    status: 'scanned',
+  }
+}
+
+async function scanRepo(
+  repo: DiscoveredRepo,
+  source: RepoSource,
+  orgSlug: string,
+  supportedFiles: SupportedFiles,
+): Promise<RepoScanResult> {
+  if (repo.dir) {
+    const commitHash = (await gitResolveCommit('HEAD', repo.dir)) || ''
+    return await scanRepoDir(
+      repo,
+      repo.dir,
+      commitHash,
+      orgSlug,
+      supportedFiles,
+    )
+  }
+  const tmpDir = await fs.mkdtemp(path.join(os.tmpdir(), 'socket-all-repos-'))
+  try {
+    const cloneDir = path.join(tmpDir, repo.name)
+    const cloneCResult = await cloneDiscoveredRepo(repo, source, cloneDir)
+    if (!cloneCResult.ok) {
+      return {
+        branch: repo.branch,
+        reason: formatReason(cloneCResult),
+        repo: repo.name,
+        status: 'failed',
+      }
+    }
+    return await scanRepoDir(
+      repo,
+      cloneDir,
+      cloneCResult.data.commitHash,
+      orgSlug,
+      supportedFiles,
+    )
+  } finally {
+    await safeDelete(tmpDir, { force: true })
+  }
+}
+
+export async function scanAllRepos({
+  concurrency,
+  orgSlug,
+  repos,
+  source,
+  spinner,
+}: ScanAllReposConfig): Promise<CResult<RepoScanResult[]>> {
+  const supportedFilesCResult = await fetchSupportedScanFileNames({
+    orgSlug,
+    spinner,
+  })
+  if (!supportedFilesCResult.ok) {
+    return supportedFilesCResult
+  }
+  const supportedFiles = supportedFilesCResult.data
+
+  const results: RepoScanResult[] = []
+  let done = 0
+  spinner?.start(`Scanning ${repos.length} repositories…`)
+  await pEach(
+    repos,
+    async repo => {
+      let result: RepoScanResult
+      try {
+        result = await scanRepo(repo, source, orgSlug, supportedFiles)
+      } catch (e) {
+        debugDir({ message: `Scanning ${repo.name} failed`, error: e })
+        result = {
+          branch: repo.branch,
+          reason: getErrorCause(e),
+          repo: repo.name,
+          status: 'failed',
+        }
+      }
+      results.push(result)
+      done += 1
+      debug(`${repo.name}: ${result.status}`)
+      spinner?.text(`Scanned ${done} of ${repos.length} repositories…`)
+    },
+    { concurrency },
+  )
+  spinner?.stop()
+  return { ok: true, data: rankRepoScanResults(results) }
+}
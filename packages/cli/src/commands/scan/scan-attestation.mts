@@ -0,0 +1,181 @@
+/**
+ * The in-toto statement behind `socket scan attest`.
+ *
+ * The predicate records which commit was scanned, the policy the scan was
+ * evaluated against and the result, so a deployment gate that verified the
+ * signature can check "this commit passed Socket" without calling the
+ * Socket API. The policies are recorded by digest: a gate pins the digests
+ * it trusts rather than reading the rules.
+ */
+
+import { createHash } from 'node:crypto'
+import { readFileSync } from 'node:fs'
+import path from 'node:path'
+
+import { getCliVersion } from '../../env/cli-version.mts'
+import { walkNestedMap } from '../../util/data/walk-nested-map.mts'
+import { createInTotoStatement } from '../../util/sigstore/dsse.mts'
+
+import type { ReportLeafNode, ScanReport } from './generate-report.mts'
+import type { FAIL_ON } from './types.mts'
+import type { FoundSocketBaseline } from '../../util/policy/baseline.mts'
+import type { FoundSocketPolicy } from '../../util/policy/socket-policy.mts'
+import type {
+  InTotoStatement,
+  InTotoSubject,
+} from '../../util/sigstore/dsse.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+export const SOCKET_SCAN_PREDICATE_TYPE =
+  'https://socket.dev/attestation/scan/v1'
+
+type ScanMetadata = SocketSdkSuccessResult<'getFullScanMetadata'>['data']
+
+type SecurityPolicy = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+export type FileDigest = {
+  digest: { sha256: string }
+  path: string
+}
+
+export type ScanAttestationPredicate = {
+  evaluatedAt: string
+  policy: {
+    // socket.baseline.json whose alerts were left out.
+    baseline?: FileDigest | undefined
+    failOn?: FAIL_ON | undefined
+    // socket.policy.yml whose rules override the org policy.
+    localPolicy?: FileDigest | undefined
+    securityPolicy: {
+      default: string
+      digest: { sha256: string }
+    }
+  }
+  result: {
+    alerts: { error: number; monitor: number; warn: number }
+    passed: boolean
+  }
+  scan: {
+    branch?: string | undefined
+    commit?: string | undefined
+    createdAt?: string | undefined
+    id: string
+    orgSlug: string
+    reportUrl?: string | undefined
+    repo?: string | undefined
+  }
+  socketCli: { version: string }
+}
+
+export type BuildScanAttestationOptions = {
+  baseline?: FoundSocketBaseline | undefined
+  cwd: string
+  failOn?: FAIL_ON | undefined
+  localPolicy?: FoundSocketPolicy | undefined
+  metadata: ScanMetadata
+  now?: Date | undefined
+  orgSlug: string
+  report: ScanReport
+  scanId: string
+  securityPolicy: SecurityPolicy
+  // Artifacts to attest instead of the scanned commit.
+  subjects?: InTotoSubject[] | undefined
+}
+
+function sha256(data: string | Buffer): string {
+  return createHash('sha256').update(data).digest('hex')
+}
+
+// Sorted so the digest does not depend on the order the API lists the rules.
+function stableStringify(value: unknown): string {
+  return JSON.stringify(value, (_key, v) =>
+    v && typeof v === 'object' && !Array.isArray(v)
+      ? Object.fromEntries(
+          Object.entries(v).sort(([a], [b]) => (a < b ? -1 : a > b ? 1 : 0)),
+        )
+      : v,
+  )
+}
+
+function getFileDigest(filepath: string, cwd: string): FileDigest {
+  return {
+    digest: { sha256: sha256(readFileSync(filepath)) },
+    path: path.relative(cwd, filepath) || path.basename(filepath),
+  }
+}
+
+/**
+ * Subject for an artifact file, e.g. the image or tarball about to deploy.
+ */
+export function getFileSubject(filepath: string): InTotoSubject {
+  return {
+    digest: { sha256: sha256(readFileSync(filepath)) },
+    name: path.basename(filepath),
+  }
+}
+
+export function countReportAlerts(
+  report: ScanReport,
+): ScanAttestationPredicate['result']['alerts'] {
+  const alerts = { error: 0, monitor: 0, warn: 0 }
+  for (const { value } of walkNestedMap(report.alerts)) {
+    const { policy } = value as ReportLeafNode
+    if (policy === 'error' || policy === 'monitor' || policy === 'warn') {
+      alerts[policy] += 1
+    }
+  }
+  return alerts
+}
+
+export function buildScanAttestation({
+  baseline,
+  cwd,
+  failOn,
+  localPolicy,
+  metadata,
+  now = new Date(),
+  orgSlug,
+  report,
+  scanId,
+  securityPolicy,
+  subjects,
+}: BuildScanAttestationOptions): InTotoStatement<ScanAttestationPredicate> {
+  const commit = metadata.commit_hash || undefined
+  const repo = metadata.repo || undefined
+  return createInTotoStatement(
+    subjects?.length
+      ? subjects
+      : [{ digest: { gitCommit: commit! }, name: repo || scanId }],
+    SOCKET_SCAN_PREDICATE_TYPE,
+    {
+      evaluatedAt: now.toISOString(),
+      policy: {
+        ...(baseline ? { baseline: getFileDigest(baseline.path, cwd) } : {}),
+        ...(failOn ? { failOn } : {}),
+        ...(localPolicy
+          ? { localPolicy: getFileDigest(localPolicy.path, cwd) }
+          : {}),
+        securityPolicy: {
+          default: securityPolicy.securityPolicyDefault,
+          digest: {
+            sha256: sha256(stableStringify(securityPolicy.securityPolicyRules)),
+          },
+        },
+      },
+      result: {
+        alerts: countReportAlerts(report),
+        passed: report.healthy,
+      },
+      scan: {
+        branch: metadata.branch || undefined,
+        commit,
+        createdAt: metadata.created_at || undefined,
+        id: scanId,
+        orgSlug,
+        reportUrl: metadata.html_report_url || undefined,
+        repo,
+      },
+      socketCli: { version: getCliVersion() },
+    },
+  )
+}
@@ -0,0 +1,388 @@
+/**
+ * Chat notifications for `socket scan create --notify`.
+ *
+ * The new scan is compared with the latest other scan of the repository's
+ * default branch, the same alert diff `socket ci report` uses, and a summary
+ * is posted to a Slack or Microsoft Teams incoming webhook: the new critical
+ * alerts, the diff counts and a link to the report. Slack gets Block Kit
+ * blocks, Teams an Adaptive Card, both with a plain text fallback.
+ */
+
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { fetchOrgFullScanList } from './fetch-list-scans.mts'
+import { fetchScanAlertDiff } from './fetch-scan-alert-diff.mts'
+import { getNetworkErrorDiagnostics } from '../../util/error/errors.mts'
+import {
+  socketHttpRequest,
+  tryReadResponseText,
+} from '../../util/socket/api-http.mts'
+import { fetchViewRepo } from '../repository/fetch-view-repo.mts'
+
+import type { ScanDiffAlert } from './diff-scan-alerts.mts'
+import type { CResult } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export const SCAN_NOTIFY_CHANNELS = ['slack', 'teams'] as const
+
+export type ScanNotifyChannel = (typeof SCAN_NOTIFY_CHANNELS)[number]
+
+// Chat messages stay short, the report link has the rest.
+const MAX_LISTED_ALERTS = 10
+
+const COMMAND_PATH = 'socket scan create'
+
+export type ScanNotifyOptions = {
+  channel: ScanNotifyChannel
+  webhookUrl: string
+}
+
+export type ScanNotifyConfig = ScanNotifyOptions & {
+  branchName: string
+  orgSlug: string
+  repoName: string
+  reportUrl?: string | undefined
+  scanId: string
+}
+
+export type ScanNotifySummary = {
+  baseBranch: string
+  // Unset when the default branch has no other scan to compare with.
+  baseScanId?: string | undefined
+  branchName: string
+  counts?: { added: number; blocking: number; removed: number } | undefined
+  newCriticals: ScanDiffAlert[]
+  orgSlug: string
+  repoName: string
+  reportUrl?: string | undefined
+  scanId: string
+}
+
+type ChatFact = { title: string; value: string }
+
+async function fetchBaseScanId(
+  orgSlug: string,
+  repoName: string,
+  branch: string,
+  scanId: string,
+): Promise<CResult<string | undefined>> {
+  // The new scan may itself be the latest one of the default branch.
+  const listCResult = await fetchOrgFullScanList(
+    {
+      branch,
+      direction: 'desc',
+      from_time: '',
+      orgSlug,
+      page: 1,
+      perPage: 2,
+      repo: repoName,
+      sort: 'created_at',
+    },
+    { commandPath: COMMAND_PATH },
+  )
+  if (!listCResult.ok) {
+    return listCResult
+  }
+  const base = listCResult.data.results.find(scan => scan.id !== scanId)
+  return { ok: true, data: base?.id }
+}
+
+/**
+ * Compare the new scan with the default branch and collect what goes into
+ * the message.
+ */
+export async function getScanNotifySummary(
+  config: Omit<ScanNotifyConfig, keyof ScanNotifyOptions>,
+): Promise<CResult<ScanNotifySummary>> {
+  const { branchName, orgSlug, repoName, reportUrl, scanId } = {
+    __proto__: null,
+    ...config,
+  } as ScanNotifyConfig
+
+  const repoCResult = await fetchViewRepo(orgSlug, repoName, {
+    commandPath: COMMAND_PATH,
+  })
+  if (!repoCResult.ok) {
+    return repoCResult
+  }
+  const baseBranch = repoCResult.data.default_branch || 'main'
+  const baseCResult = await fetchBaseScanId(
+    orgSlug,
+    repoName,
+    baseBranch,
+    scanId,
+  )
+  if (!baseCResult.ok) {
+    return baseCResult
+  }
+  const summary: ScanNotifySummary = {
+    baseBranch,
+    branchName,
+    newCriticals: [],
+    orgSlug,
+    repoName,
+    reportUrl,
+    scanId,
+  }
+  const baseScanId = baseCResult.data
+  if (!baseScanId) {
+    return { ok: true, data: summary }
+  }
+
+  const diffCResult = await fetchScanAlertDiff({
+    id1: baseScanId,
+    id2: scanId,
+    orgSlug,
+  })
+  if (!diffCResult.ok) {
+    return diffCResult
+  }
+  const { added, blocking, removed } = diffCResult.data
+  summary.baseScanId = baseScanId
+  summary.counts = {
+    added: added.length,
+    blocking: blocking.length,
+    removed: removed.length,
+  }
+  summary.newCriticals = added.filter(alert => alert.severity === 'critical')
+  return { ok: true, data: summary }
+}
+
+function getTitle(summary: ScanNotifySummary): string {
+  const { branchName, repoName } = summary
+  return `Socket scan of ${repoName}${branchName ? ` (${branchName})` : ''}`
+}
+
+function getVerdict(summary: ScanNotifySummary): string {
+  const { baseBranch, baseScanId, newCriticals } = summary
+  if (!baseScanId) {
+    return `No earlier scan of ${baseBranch} to compare with`
+  }
+  const count = newCriticals.length
+  return count
+    ? `${count} new critical ${pluralize('alert', { count })} compared with ${baseBranch}`
+    : `No new critical alerts compared with ${baseBranch}`
+}
+
+function getFacts(summary: ScanNotifySummary): ChatFact[] {
+  const facts: ChatFact[] = [
+    { title: 'Organization', value: summary.orgSlug },
+    { title: 'Scan', value: summary.scanId },
+  ]
+  const { counts } = summary
+  if (counts) {
+    facts.push(
+      { title: 'New alerts', value: String(counts.added) },
+      { title: 'Blocking', value: String(counts.blocking) },
+      { title: 'Resolved', value: String(counts.removed) },
+    )
+  }
+  return facts
+}
+
+function getAlertLines(
+  summary: ScanNotifySummary,
+  formatCode: (text: string) => string,
+): string[] {
+  const { newCriticals } = summary
+  const lines = newCriticals
+    .slice(0, MAX_LISTED_ALERTS)
+    .map(alert => `${formatCode(alert.type)} in ${formatCode(alert.purl)}`)
+  const rest = newCriticals.length - MAX_LISTED_ALERTS
+  if (rest > 0) {
+    lines.push(`…and ${rest} more`)
+  }
+  return lines
+}
+
+/**
+ * Slack incoming webhook payload, using Block Kit.
+ */
+export function formatSlackPayload(
+  summary: ScanNotifySummary,
+): Record<string, unknown> {
+  const title = getTitle(summary)
+  const verdict = getVerdict(summary)
+  const icon = summary.newCriticals.length
+    ? ':rotating_light:'
+    : ':white_check_mark:'
+  const blocks: Array<Record<string, unknown>> = [
+    { type: 'header', text: { type: 'plain_text', text: title } },
+    {
+      type: 'section',
+      text: { type: 'mrkdwn', text: `${icon} *${verdict}*` },
+    },
+    {
+      type: 'section',
+      fields: getFacts(summary).map(({ title, value }) => ({
+        type: 'mrkdwn',
+        text: `*${title}*\n${value}`,
+      })),
+    },
+  ]
+  const alertLines = getAlertLines(summary, text => `\`${text}\``)
+  if (alertLines.length) {
+    blocks.push({
+      type: 'section',
+      text: {
+        type: 'mrkdwn',
+        text: alertLines.map(line => `• ${line}`).join('\n'),
+      },
+    })
+  }
+  if (summary.reportUrl) {
+    blocks.push({
+      type: 'actions',
+      elements: [
+        {
+          type: 'button',
+          text: { type: 'plain_text', text: 'View report' },
+          url: summary.reportUrl,
+        },
+      ],
+    })
+  }
+  return { text: `${title}: ${verdict}`, blocks }
+}
+
+/**
+ * Microsoft Teams incoming webhook payload, a message with an Adaptive Card.
+ */
+export function formatTeamsPayload(
+  summary: ScanNotifySummary,
+): Record<string, unknown> {
+  const title = getTitle(summary)
+  const verdict = getVerdict(summary)
+  const body: Array<Record<string, unknown>> = [
+    {
+      type: 'TextBlock',
+      size: 'Large',
+      weight: 'Bolder',
+      text: title,
+      wrap: true,
+    },
+    {
+      type: 'TextBlock',
+      color: summary.newCriticals.length ? 'Attention' : 'Good',
+      weight: 'Bolder',
+      text: verdict,
+      wrap: true,
+    },
+    { type: 'FactSet', facts: getFacts(summary) },
+  ]
+  const alertLines = getAlertLines(summary, text => `\`${text}\``)
+  if (alertLines.length) {
+    body.push({
+      type: 'TextBlock',
+      text: alertLines.map(line => `- ${line}`).join('\n'),
+      wrap: true,
+    })
+  }
+  return {
+    type: 'message',
+    summary: `${title}: ${verdict}`,
+    attachments: [
+      {
+        contentType: 'application/vnd.microsoft.card.adaptive',
+        content: {
+          $schema: 'http://adaptivecards.io/schemas/adaptive-card.json',
+          type: 'AdaptiveCard',
+          version: '1.4',
+          body,
+          ...(summary.reportUrl
+            ? {
+                actions: [
+                  {
+                    type: 'Action.OpenUrl',
+                    title: 'View report',
+                    url: summary.reportUrl,
+                  },
+                ],
+              }
+            : {}),
+        },
+      },
+    ],
+  }
+}
+
+export async function postScanNotification(
+  webhookUrl: string,
+  payload: Record<string, unknown>,
+): Promise<CResult<undefined>> {
+  const startTime = Date.now()
+  try {
+    const response = await socketHttpRequest(webhookUrl, {
+      body: JSON.stringify(payload),
+      headers: { 'Content-Type': 'application/json' },
+      method: 'POST',
+    })
+    const { status } = response
+    if (status < 200 || status >= 300) {
+      debug(
+        `Webhook rejected the notification: ${tryReadResponseText(response)}`,
+      )
+      return {
+        ok: false,
+        message: 'Failed to post the notification',
+        cause: `The webhook responded with ${status} ${response.statusText}`,
+        data: { code: status },
+      }
+    }
+    return { ok: true, data: undefined }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Failed to post the notification',
+      cause: getNetworkErrorDiagnostics(e, Date.now() - startTime),
+    }
+  }
+}
+
+/**
+ * Post the summary of a new scan to the webhook of the channel.
+ */
+export async function notifyScan(
+  config: ScanNotifyConfig,
+): Promise<CResult<ScanNotifySummary>> {
+  const { channel, webhookUrl } = {
+    __proto__: null,
+    ...config,
+  } as ScanNotifyConfig
+  const summaryCResult = await getScanNotifySummary(config)
+  if (!summaryCResult.ok) {
+    return summaryCResult
+  }
+  const payload =
+    channel === 'teams'
+      ? formatTeamsPayload(summaryCResult.data)
+      : formatSlackPayload(summaryCResult.data)
+  const postCResult = await postScanNotification(webhookUrl, payload)
+  if (!postCResult.ok) {
+    return postCResult
+  }
+  return summaryCResult
+}
+
+/**
+ * Notify after `socket scan create` uploaded a scan. A failed notification
+ * only warns, the scan itself was created.
+ */
+export async function notifyCreatedScan(
+  notify: ScanNotifyOptions,
+  config: Omit<ScanNotifyConfig, keyof ScanNotifyOptions>,
+): Promise<void> {
+  const notifyCResult = await notifyScan({ ...config, ...notify })
+  if (!notifyCResult.ok) {
+    logger.warn(
+      `${notifyCResult.message}${notifyCResult.cause ? `: ${notifyCResult.cause}` : ''}`,
+    )
+    return
+  }
+  logger.success(
+    `Posted the scan summary to ${notify.channel === 'teams' ? 'Microsoft Teams' : 'Slack'}`,
+  )
+}
@@ -0,0 +1,277 @@
+/**
+ * View state of `socket scan view --interactive`: the rows of the triage
+ * items grouped by package or by severity, the cursor, the reason prompt and
+ * the key handling. The key loop in scan-view-tui.mts feeds keys to
+ * `reduceTriageView` and renders the state with `renderTriageView` (see
+ * scan-view-triage.mts).
+ */
+
+import type { TriageItem, TriageStatus } from './scan-view-triage.mts'
+
+export type TriageGroupBy = 'package' | 'severity'
+
+export type TriageRow =
+  | { type: 'group'; count: number; label: string }
+  | { type: 'item'; index: number }
+
+export type TriagePrompt = {
+  status: Exclude<TriageStatus, 'open'>
+  value: string
+}
+
+export type TriageViewState = {
+  // Index into rows; always an item row while there are items.
+  cursor: number
+  expanded: boolean
+  groupBy: TriageGroupBy
+  items: TriageItem[]
+  message: string
+  prompt?: TriagePrompt | undefined
+  rows: TriageRow[]
+  scrollTop: number
+}
+
+export type TriageKey = {
+  ctrl?: boolean | undefined
+  name?: string | undefined
+  sequence?: string | undefined
+}
+
+export type TriageCommand =
+  | { type: 'none' }
+  | { type: 'quit' }
+  | {
+      type: 'triage'
+      index: number
+      reason: string
+      status: Exclude<TriageStatus, 'open'>
+    }
+
+const SEVERITY_ORDER = ['critical', 'high', 'middle', 'low']
+
+function getSeverityRank(severity: string): number {
+  const index = SEVERITY_ORDER.indexOf(severity)
+  return index === -1 ? SEVERITY_ORDER.length : index
+}
+
+function compareItems(a: TriageItem, b: TriageItem): number {
+  return (
+    getSeverityRank(a.severity) - getSeverityRank(b.severity) ||
+    a.purl.localeCompare(b.purl) ||
+    a.type.localeCompare(b.type)
+  )
+}
+
+/**
+ * Group rows followed by their item rows. Packages are ordered by their worst
+ * alert, severities from critical down.
+ */
+export function getTriageRows(
+  items: TriageItem[],
+  groupBy: TriageGroupBy,
+): TriageRow[] {
+  const indexes = items.map((_, i) => i)
+  indexes.sort((a, b) => compareItems(items[a]!, items[b]!))
+  const groups = new Map<string, number[]>()
+  for (let i = 0, { length } = indexes; i < length; i += 1) {
+    const index = indexes[i]!
+    const item = items[index]!
+    const label = groupBy === 'package' ? item.purl : item.severity
+    const group = groups.get(label) ?? []
+    group.push(index)
+    groups.set(label, group)
+  }
+  const rows: TriageRow[] = []
+  for (const [label, group] of groups) {
+    rows.push({ type: 'group', count: group.length, label })
+    for (let i = 0, { length } = group; i < length; i += 1) {
+      rows.push({ type: 'item', index: group[i]! })
+    }
+  }
+  return rows
+}
+
+function findItemRow(rows: TriageRow[], index: number): number {
+  const row = rows.findIndex(r => r.type === 'item' && r.index === index)
+  return row === -1 ? rows.findIndex(r => r.type === 'item') : row
+}
+
+export function createTriageViewState(
+  items: TriageItem[],
+  groupBy: TriageGroupBy = 'package',
+): TriageViewState {
+  const rows = getTriageRows(items, groupBy)
+  return {
+    cursor: Math.max(0, rows.findIndex(r => r.type === 'item')),
+    expanded: false,
+    groupBy,
+    items,
+    message: '',
+    rows,
+    scrollTop: 0,
+  }
+}
+
+export function getSelectedTriageIndex(
+  state: TriageViewState,
+): number | undefined {
+  const row = state.rows[state.cursor]
+  return row?.type === 'item' ? row.index : undefined
+}
+
+function moveCursor(state: TriageViewState, delta: number): TriageViewState {
+  const { rows } = state
+  let { cursor } = state
+  const step = delta < 0 ? -1 : 1
+  for (let moved = 0; moved < Math.abs(delta); ) {
+    let next = cursor + step
+    while (next >= 0 && next < rows.length && rows[next]!.type !== 'item') {
+      next += step
+    }
+    if (next < 0 || next >= rows.length) {
+      break
+    }
+    cursor = next
+    moved += 1
+  }
+  return { ...state, cursor }
+}
+
+/**
+ * Apply a key press. Returns the next state and what the key loop should do:
+ * quit, or write a triage decision and then call `setTriageStatus`.
+ */
+export function reduceTriageView(
+  state: TriageViewState,
+  key: TriageKey,
+  pageSize = 10,
+): { command: TriageCommand; state: TriageViewState } {
+  const none: TriageCommand = { type: 'none' }
+  if (key.ctrl && key.name === 'c') {
+    return { command: { type: 'quit' }, state }
+  }
+  const { prompt } = state
+  if (prompt) {
+    if (key.name === 'escape') {
+      return {
+        command: none,
+        state: { ...state, message: 'Cancelled', prompt: undefined },
+      }
+    }
+    if (key.name === 'return' || key.name === 'enter') {
+      const reason = prompt.value.trim()
+      const index = getSelectedTriageIndex(state)
+      if (!reason || index === undefined) {
+        return {
+          command: none,
+          state: { ...state, message: 'A reason is required' },
+        }
+      }
+      return {
+        command: { type: 'triage', index, reason, status: prompt.status },
+        state: { ...state, message: '', prompt: undefined },
+      }
+    }
+    if (key.name === 'backspace') {
+      return {
+        command: none,
+        state: {
+          ...state,
+          prompt: { ...prompt, value: prompt.value.slice(0, -1) },
+        },
+      }
+    }
+    const { sequence = '' } = key
+    // Printable input only; arrows and other escapes are ignored.
+    if (!key.ctrl && sequence && !/[\u0000-\u001f\u007f]/.test(sequence)) {
+      return {
+        command: none,
+        state: {
+          ...state,
+          prompt: { ...prompt, value: prompt.value + sequence },
+        },
+      }
+    }
+    return { command: none, state }
+  }
+
+  const cleared = state.message ? { ...state, message: '' } : state
+  switch (key.name) {
+    case 'q':
+    case 'escape':
+      return { command: { type: 'quit' }, state }
+    case 'up':
+    case 'k':
+      return { command: none, state: moveCursor(cleared, -1) }
+    case 'down':
+    case 'j':
+      return { command: none, state: moveCursor(cleared, 1) }
+    case 'pageup':
+      return { command: none, state: moveCursor(cleared, -pageSize) }
+    case 'pagedown':
+      return { command: none, state: moveCursor(cleared, pageSize) }
+    case 'home':
+      return { command: none, state: moveCursor(cleared, -state.rows.length) }
+    case 'end':
+      return { command: none, state: moveCursor(cleared, state.rows.length) }
+    case 'return':
+    case 'enter':
+    case 'space':
+      return {
+        command: none,
+        state: { ...cleared, expanded: !state.expanded },
+      }
+    case 'right':
+      return { command: none, state: { ...cleared, expanded: true } }
+    case 'left':
+      return { command: none, state: { ...cleared, expanded: false } }
+    case 'g':
+    case 'tab': {
+      const groupBy = state.groupBy === 'package' ? 'severity' : 'package'
+      const rows = getTriageRows(state.items, groupBy)
+      const index = getSelectedTriageIndex(state)
+      return {
+        command: none,
+        state: {
+          ...cleared,
+          cursor: Math.max(0, findItemRow(rows, index ?? -1)),
+          groupBy,
+          rows,
+          scrollTop: 0,
+        },
+      }
+    }
+    case 'i':
+    case 'a': {
+      if (getSelectedTriageIndex(state) === undefined) {
+        return { command: none, state: cleared }
+      }
+      return {
+        command: none,
+        state: {
+          ...cleared,
+          prompt: {
+            status: key.name === 'i' ? 'ignored' : 'accepted',
+            value: '',
+          },
+        },
+      }
+    }
+    default:
+      return { command: none, state: cleared }
+  }
+}
+
+/**
+ * Record the outcome of a triage write on the item.
+ */
+export function setTriageStatus(
+  state: TriageViewState,
+  index: number,
+  status: TriageStatus,
+  message: string,
+): TriageViewState {
+  const items = state.items.slice()
+  items[index] = { ...items[index]!, status }
+  return { ...state, items, message }
+}
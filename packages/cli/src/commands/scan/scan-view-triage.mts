@@ -0,0 +1,250 @@
+/**
+ * Items and rendering of `socket scan view --interactive`.
+ *
+ * Every alert of the scan becomes a triage item. Items are listed grouped by
+ * package or by severity, and the selected one can be expanded to show the
+ * dependency path that brings it in, from a direct dependency down. Nothing
+ * here touches the terminal: the key loop in scan-view-tui.mts feeds keys to
+ * `reduceTriageView` (see scan-view-triage-state.mts) and prints what
+ * `renderTriageView` returns.
+ */
+
+import colors from 'yoctocolors-cjs'
+
+import { UNKNOWN_VALUE } from '@socketsecurity/lib-stable/constants/sentinels'
+
+import { getAlertTranslation } from './generate-sarif-report.mts'
+import { getSelectedTriageIndex } from './scan-view-triage-state.mts'
+import { getArtifactPurlString } from '../../util/purl/parse.mts'
+
+import type { TriageRow, TriageViewState } from './scan-view-triage-state.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export type TriageStatus = 'accepted' | 'ignored' | 'open'
+
+export type TriageItem = {
+  // Org policy action, when the API reports one.
+  action: string
+  alertKey: string
+  // Purls from a direct dependency down to the alerted package.
+  dependencyPath: string[]
+  purl: string
+  severity: string
+  status: TriageStatus
+  title: string
+  type: string
+}
+
+export type TriageViewSize = {
+  columns: number
+  rows: number
+}
+
+const SEVERITY_COLORS: Record<string, (text: string) => string> = {
+  __proto__: null,
+  critical: colors.magenta,
+  high: colors.red,
+  middle: colors.yellow,
+  low: colors.blue,
+} as unknown as Record<string, (text: string) => string>
+
+const STATUS_LABELS: Record<TriageStatus, string> = {
+  accepted: 'accepted',
+  ignored: 'ignored',
+  open: '',
+}
+
+const HELP_LINE = '↑/↓ move  enter path  g group  i ignore  a accept  q quit'
+
+function getArtifactId(artifact: SocketArtifact): string {
+  return artifact.id || getArtifactPurlString(artifact)
+}
+
+/**
+ * Ids from the nearest direct dependency down to the target, found by a
+ * breadth-first walk up the dependents. Falls back to the top level ancestors
+ * the API reports when the scan has no dependency graph.
+ */
+export function getDependencyPathIds(
+  targetId: string,
+  parents: Map<string, string[]>,
+  artifactById: Map<string, SocketArtifact>,
+): string[] {
+  const childOf = new Map<string, string>()
+  const queue = [targetId]
+  for (let i = 0; i < queue.length; i += 1) {
+    const id = queue[i]!
+    if (artifactById.get(id)?.direct) {
+      const path = [id]
+      let current = id
+      while (current !== targetId) {
+        current = childOf.get(current)!
+        path.push(current)
+      }
+      return path
+    }
+    const ids = parents.get(id) ?? []
+    for (let j = 0, { length } = ids; j < length; j += 1) {
+      const parentId = ids[j]!
+      if (parentId !== targetId && !childOf.has(parentId)) {
+        childOf.set(parentId, id)
+        queue.push(parentId)
+      }
+    }
+  }
+  const topLevelId = (
+    (artifactById.get(targetId)?.topLevelAncestors ?? []) as string[]
+  ).find(id => id !== targetId && artifactById.has(id))
+  return topLevelId ? [topLevelId, targetId] : [targetId]
+}
+
+export function getTriageItems(scan: SocketArtifact[]): TriageItem[] {
+  const artifactById = new Map<string, SocketArtifact>()
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    artifactById.set(getArtifactId(scan[i]!), scan[i]!)
+  }
+  const parents = new Map<string, string[]>()
+  for (const [id, artifact] of artifactById) {
+    const deps = (artifact.dependencies ?? []) as string[]
+    for (let i = 0, { length } = deps; i < length; i += 1) {
+      const childId = deps[i]!
+      if (!artifactById.has(childId) || childId === id) {
+        continue
+      }
+      const childParents = parents.get(childId) ?? []
+      if (!childParents.includes(id)) {
+        childParents.push(id)
+        parents.set(childId, childParents)
+      }
+    }
+  }
+
+  const items: TriageItem[] = []
+  for (const [id, artifact] of artifactById) {
+    const alerts = artifact.alerts ?? []
+    if (!alerts.length) {
+      continue
+    }
+    const purl = getArtifactPurlString(artifact)
+    const dependencyPath = getDependencyPathIds(id, parents, artifactById).map(
+      pathId => getArtifactPurlString(artifactById.get(pathId)!),
+    )
+    for (let i = 0, { length } = alerts; i < length; i += 1) {
+      const alert = alerts[i]!
+      items.push({
+        action: alert.action ?? '',
+        alertKey: alert.key ?? '',
+        dependencyPath,
+        purl,
+        severity: alert.severity ?? UNKNOWN_VALUE,
+        status: 'open',
+        title: getAlertTranslation(alert.type).title || alert.type,
+        type: alert.type,
+      })
+    }
+  }
+  return items
+}
+
+function truncate(text: string, width: number): string {
+  return text.length > width
+    ? `${text.slice(0, Math.max(0, width - 1))}…`
+    : text
+}
+
+function colorSeverity(severity: string, text: string): string {
+  return (SEVERITY_COLORS[severity] ?? colors.gray)(text)
+}
+
+function renderRow(
+  state: TriageViewState,
+  row: TriageRow,
+  selected: boolean,
+  columns: number,
+): string {
+  if (row.type === 'group') {
+    const label = `${row.label} (${row.count})`
+    return state.groupBy === 'severity'
+      ? colors.bold(colorSeverity(row.label, truncate(label, columns)))
+      : colors.bold(truncate(label, columns))
+  }
+  const item = state.items[row.index]!
+  const status = STATUS_LABELS[item.status]
+  const detail = state.groupBy === 'package' ? item.severity : item.purl
+  const text = truncate(
+    `  ${selected ? '›' : ' '} ${item.title} · ${detail}${status ? ` [${status}]` : ''}`,
+    columns,
+  )
+  if (selected) {
+    return colors.inverse(text)
+  }
+  return item.status === 'open' ? text : colors.dim(text)
+}
+
+function renderDetails(item: TriageItem, columns: number): string[] {
+  const lines = [
+    `${item.title} (${item.type})`,
+    `severity ${item.severity}${item.action ? ` · policy ${item.action}` : ''}`,
+    'Dependency path:',
+    ...item.dependencyPath.map(
+      (purl, i) => `${' '.repeat(2 + i * 2)}${i ? '└─ ' : ''}${purl}`,
+    ),
+  ]
+  return lines.map(line => truncate(line, columns))
+}
+
+/**
+ * The screen as lines, at most `rows` of them. Adjusts `scrollTop` so the
+ * cursor stays visible, which is why the state is returned too.
+ */
+export function renderTriageView(
+  state: TriageViewState,
+  { columns, rows }: TriageViewSize,
+): { lines: string[]; state: TriageViewState } {
+  const open = state.items.filter(item => item.status === 'open').length
+  const header = colors.bold(
+    truncate(
+      `Scan alerts: ${state.items.length} (${open} open) · grouped by ${state.groupBy}`,
+      columns,
+    ),
+  )
+  const selectedIndex = getSelectedTriageIndex(state)
+  const details =
+    state.expanded && selectedIndex !== undefined
+      ? renderDetails(state.items[selectedIndex]!, columns)
+      : []
+  const footer = state.prompt
+    ? `${state.prompt.status === 'ignored' ? 'Ignore' : 'Accept'} reason (enter to save, esc to cancel): ${state.prompt.value}`
+    : state.message || HELP_LINE
+  const listHeight = Math.max(
+    1,
+    rows - 3 - (details.length ? details.length + 1 : 0),
+  )
+
+  let { scrollTop } = state
+  if (state.cursor < scrollTop) {
+    scrollTop = state.cursor
+    // Keep the group header of the first item in view.
+    if (scrollTop > 0 && state.rows[scrollTop - 1]?.type === 'group') {
+      scrollTop -= 1
+    }
+  } else if (state.cursor >= scrollTop + listHeight) {
+    scrollTop = state.cursor - listHeight + 1
+  }
+
+  const lines = [header, '']
+  if (!state.rows.length) {
+    lines.push('No alerts in this scan.')
+  }
+  const visible = state.rows.slice(scrollTop, scrollTop + listHeight)
+  for (let i = 0, { length } = visible; i < length; i += 1) {
+    lines.push(
+      renderRow(state, visible[i]!, scrollTop + i === state.cursor, columns),
+    )
+  }
+  if (details.length) {
+    lines.push(colors.gray('─'.repeat(Math.min(columns, 40))), ...details)
+  }
+  lines.push(truncate(footer, columns))
+  return { lines: lines.slice(0, rows), state: { ...state, scrollTop } }
+}
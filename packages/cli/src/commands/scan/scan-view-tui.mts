@@ -0,0 +1,125 @@
+/**
+ * Key loop of `socket scan view --interactive`. Draws the triage view on the
+ * alternate screen, so the shell scrollback is left as it was on exit, and
+ * hands triage decisions to the caller to write.
+ */
+
+import readline from 'node:readline'
+
+import {
+  createTriageViewState,
+  reduceTriageView,
+  setTriageStatus,
+} from './scan-view-triage-state.mts'
+import { renderTriageView } from './scan-view-triage.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+
+import type { TriageKey, TriageViewState } from './scan-view-triage-state.mts'
+import type { TriageItem, TriageStatus } from './scan-view-triage.mts'
+import type { CResult } from '../../types.mts'
+
+export type ScanViewTuiOptions = {
+  input?: NodeJS.ReadStream | undefined
+  // Writes the decision and returns the message to show.
+  onTriage: (
+    item: TriageItem,
+    status: Exclude<TriageStatus, 'open'>,
+    reason: string,
+  ) => Promise<CResult<string>>
+  output?: NodeJS.WriteStream | undefined
+}
+
+const ENTER_SCREEN = '\x1b[?1049h\x1b[?25l'
+const LEAVE_SCREEN = '\x1b[?25h\x1b[?1049l'
+const CLEAR_SCREEN = '\x1b[H\x1b[2J'
+
+/**
+ * Run the terminal UI until the user quits. Resolves with the items and the
+ * status each ended with.
+ */
+export async function runScanViewTui(
+  items: TriageItem[],
+  options: ScanViewTuiOptions,
+): Promise<TriageItem[]> {
+  const {
+    input = process.stdin,
+    onTriage,
+    output = process.stdout,
+  } = { __proto__: null, ...options } as ScanViewTuiOptions
+  let state: TriageViewState = createTriageViewState(items)
+  let busy = false
+
+  const getSize = () => ({
+    columns: output.columns || 80,
+    rows: output.rows || 24,
+  })
+  const render = () => {
+    const rendered = renderTriageView(state, getSize())
+    state = rendered.state
+    output.write(`${CLEAR_SCREEN}${rendered.lines.join('\n')}`)
+  }
+
+  readline.emitKeypressEvents(input)
+  const wasRaw = input.isRaw
+  input.setRawMode(true)
+  input.resume()
+  output.write(ENTER_SCREEN)
+
+  try {
+    await new Promise<void>(resolve => {
+      const onKeypress = (sequence: string | undefined, key?: TriageKey) => {
+        if (busy) {
+          return
+        }
+        const { command, state: next } = reduceTriageView(
+          state,
+          key ?? { sequence },
+          Math.max(1, getSize().rows - 4),
+        )
+        state = next
+        if (command.type === 'quit') {
+          input.off('keypress', onKeypress)
+          output.off('resize', render)
+          resolve()
+          return
+        }
+        if (command.type === 'triage') {
+          busy = true
+          state = { ...state, message: 'Saving…' }
+          render()
+          const { index, reason, status } = command
+          void onTriage(state.items[index]!, status, reason)
+            .then(
+              result => {
+                state = result.ok
+                  ? setTriageStatus(state, index, status, result.data)
+                  : {
+                      ...state,
+                      message: result.cause
+                        ? `${result.message}: ${result.cause}`
+                        : result.message,
+                    }
+              },
+              e => {
+                state = { ...state, message: getErrorCause(e) }
+              },
+            )
+            .finally(() => {
+              busy = false
+              render()
+            })
+          return
+        }
+        render()
+      }
+      input.on('keypress', onKeypress)
+      output.on('resize', render)
+      render()
+    })
+  } finally {
+    output.write(LEAVE_SCREEN)
+    input.setRawMode(wasRaw)
+    input.pause()
+  }
+  return state.items
+}
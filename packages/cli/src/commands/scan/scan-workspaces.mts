@@ -0,0 +1,109 @@
+/**
+ * Monorepo workspace selection and per-workspace reports for `socket scan
+ * create` and `socket scan report`, see util/fs/workspaces.mts.
+ *
+ * Each selected workspace gets a report of the packages its manifests pull
+ * in, generated the same way as the regular scan report, so every workspace
+ * passes or fails on its own.
+ */
+
+import { joinOr } from '@socketsecurity/lib-stable/arrays/join'
+
+import { generateReport } from './generate-report.mts'
+import {
+  filterMonorepoWorkspaces,
+  getChangedMonorepoWorkspaces,
+  getMonorepoWorkspaces,
+  groupArtifactsByWorkspace,
+} from '../../util/fs/workspaces.mts'
+import { gitChangedFilesSince } from '../../util/git/operations.mts'
+
+import type { ScanReport } from './generate-report.mts'
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+import type { MonorepoWorkspace } from '../../util/fs/workspaces.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+export type WorkspaceSelection = {
+  // Every workspace of the monorepo, to attribute manifest files.
+  all: MonorepoWorkspace[]
+  selected: MonorepoWorkspace[]
+}
+
+export type WorkspaceSelectionOptions = {
+  // Git ref to compare the working tree with.
+  changedSince?: string | undefined
+  // Name or path globs.
+  workspaceFilter?: string[] | undefined
+}
+
+export type WorkspaceScanReport = MonorepoWorkspace & {
+  report: ScanReport | { healthy: boolean }
+}
+
+type GenerateReportOptions = Parameters<typeof generateReport>[2]
+
+/**
+ * The workspaces to scan: those matching --workspace-filter, then those with
+ * changes since --changed-since. Every workspace without either option.
+ */
+export async function resolveWorkspaceSelection(
+  cwd: string,
+  options?: WorkspaceSelectionOptions | undefined,
+): Promise<CResult<WorkspaceSelection>> {
+  const { changedSince, workspaceFilter = [] } = {
+    __proto__: null,
+    ...options,
+  } as WorkspaceSelectionOptions
+
+  const all = await getMonorepoWorkspaces(cwd)
+  let selected = all
+  if (workspaceFilter.length) {
+    selected = filterMonorepoWorkspaces(all, workspaceFilter)
+    if (!selected.length) {
+      return {
+        ok: false,
+        message: 'No matching workspace',
+        cause: `No workspace name or path matches ${joinOr(workspaceFilter.map(p => `\`${p}\``))}. The workspaces are: ${all.map(w => (w.name === w.path ? w.path : `${w.name} (${w.path})`)).join(', ')}`,
+      }
+    }
+  }
+  if (changedSince) {
+    const changedCResult = await gitChangedFilesSince(changedSince, cwd)
+    if (!changedCResult.ok) {
+      return changedCResult
+    }
+    // Attribute the changes with every workspace, then narrow the selection.
+    const changed = new Set(
+      getChangedMonorepoWorkspaces(all, changedCResult.data),
+    )
+    selected = selected.filter(w => changed.has(w))
+  }
+  return { ok: true, data: { all, selected } }
+}
+
+/**
+ * One report per selected workspace. Fails when generating any of them does.
+ */
+export function generateWorkspaceReports(
+  scan: SocketArtifact[],
+  securityPolicy: SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data'],
+  selection: WorkspaceSelection,
+  options: GenerateReportOptions,
+): CResult<WorkspaceScanReport[]> {
+  const groups = groupArtifactsByWorkspace(scan, selection.all)
+  const reports: WorkspaceScanReport[] = []
+  for (let i = 0, { length } = selection.selected; i < length; i += 1) {
+    const workspace = selection.selected[i]!
+    const reportCResult = generateReport(
+      groups.get(workspace) ?? [],
+      securityPolicy,
+      options,
+    )
+    if (!reportCResult.ok) {
+      return reportCResult
+    }
+    reports.push({ ...workspace, report: reportCResult.data })
+  }
+  return { ok: true, data: reports }
+}
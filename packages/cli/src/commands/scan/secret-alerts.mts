@@ -0,0 +1,130 @@
+/**
+ * Alerts for credentials and keys embedded in the files of a scanned package,
+ * reported by `socket scan report` in a category of their own, "secrets", so
+ * they can be filtered with --alert-category and triaged apart from the
+ * other supply chain risks.
+ *
+ * With --show-evidence a secret alert carries the file it was found in and
+ * the line, a snippet of it read from the package installed under cwd or
+ * taken from the alert props. The matched secret and anything else that
+ * looks like one is redacted from the snippet, so reports stay safe to share
+ * in CI logs.
+ */
+
+import { readFileSync } from 'node:fs'
+
+import { getAlertLocation } from '../explain/alert-explanation.mts'
+
+import type {
+  SocketArtifact,
+  SocketArtifactAlert,
+} from '../../util/alert/artifact.mts'
+
+export const SECRET_ALERT_CATEGORY = 'secrets'
+
+// Alert types the Socket API reports for embedded credentials.
+export const SECRET_ALERT_TYPES: readonly string[] = ['exposedSecret']
+
+export type SecretEvidence = {
+  file: string
+  // One-based, set when the file could be read locally.
+  line?: number | undefined
+  snippet?: string | undefined
+}
+
+// Visible prefix of a redacted secret, enough to tell an AWS key from a
+// GitHub token.
+const REDACTED_PREFIX_LENGTH = 4
+
+const REDACTED_MASK = '****'
+
+// Quoted values and long unquoted tokens, the shapes credentials take in
+// source and config files.
+const SECRET_LIKE_PATTERN = /(["'`])([^"'`\s]{8,})\1|[A-Za-z0-9+/=_-]{20,}/g
+
+export function isSecretAlert(alert: { type: string }): boolean {
+  return SECRET_ALERT_TYPES.includes(alert.type)
+}
+
+/**
+ * The category an alert is filtered and reported under: "secrets" for secret
+ * alerts, otherwise the category the Socket API gives it.
+ */
+export function getAlertCategory(alert: {
+  category?: string | undefined
+  type: string
+}): string | undefined {
+  return isSecretAlert(alert) ? SECRET_ALERT_CATEGORY : alert.category
+}
+
+export function redactSecret(secret: string): string {
+  return secret.length > REDACTED_PREFIX_LENGTH * 2
+    ? `${secret.slice(0, REDACTED_PREFIX_LENGTH)}${REDACTED_MASK}`
+    : REDACTED_MASK
+}
+
+/**
+ * A snippet with the matched secret, and every other quoted value or long
+ * token, redacted.
+ */
+export function redactSecretSnippet(
+  snippet: string,
+  secret?: string | undefined,
+): string {
+  const redacted = secret?.trim()
+    ? snippet.replaceAll(secret.trim(), redactSecret(secret.trim()))
+    : snippet
+  return redacted.replace(
+    SECRET_LIKE_PATTERN,
+    (match: string, quote: string | undefined, value: string | undefined) =>
+      match.includes(REDACTED_MASK)
+        ? match
+        : quote && value
+          ? `${quote}${redactSecret(value)}${quote}`
+          : redactSecret(match),
+  )
+}
+
+function readMatchedSecret(
+  localPath: string,
+  start: number,
+  end: number,
+): string | undefined {
+  try {
+    return readFileSync(localPath, 'utf8').slice(start, end) || undefined
+  } catch {
+    return undefined
+  }
+}
+
+/**
+ * Where a secret alert was found, with a redacted snippet of the line.
+ * Undefined for other alerts and when the Socket API reports no file.
+ */
+export function getSecretEvidence(
+  artifact: SocketArtifact,
+  alert: SocketArtifactAlert,
+  cwd?: string | undefined,
+): SecretEvidence | undefined {
+  if (!isSecretAlert(alert)) {
+    return undefined
+  }
+  const location = getAlertLocation(artifact, alert, cwd)
+  if (!location) {
+    return undefined
+  }
+  const { end, localPath, start } = location
+  const secret =
+    localPath && typeof start === 'number' && typeof end === 'number'
+      ? readMatchedSecret(localPath, start, end)
+      : undefined
+  const propsSnippet = alert.props?.['snippet']
+  const snippet =
+    location.snippet ??
+    (typeof propsSnippet === 'string' ? propsSnippet.trim() : '')
+  return {
+    file: location.file,
+    ...(location.line ? { line: location.line } : {}),
+    ...(snippet ? { snippet: redactSecretSnippet(snippet, secret) } : {}),
+  }
+}
@@ -1,3 +1,13 @@
 export type FOLD_SETTING = 'pkg' | 'version' | 'file' | 'none'
 
 export type REPORT_LEVEL = 'defer' | 'ignore' | 'monitor' | 'warn' | 'error'
+
+export type REPORT_FORMAT =
+  | 'sarif'
+  | 'gitlab-code-quality'
+  | 'gitlab-sast'
+  | 'junit'
+  | 'html'
+  | 'csv'
+
+export type FAIL_ON = 'critical' | 'high' | 'any' | 'none'
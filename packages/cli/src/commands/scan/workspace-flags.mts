@@ -0,0 +1,22 @@
+import type { MeowFlags } from '../../flags.mts'
+
+export const workspaceFlags: MeowFlags = {
+  changedSince: {
+    type: 'string',
+    default: '',
+    description:
+      'Only include the monorepo workspaces with files changed since this git ref, e.g. `origin/main`. A changed root lockfile selects every workspace.',
+  },
+  perWorkspace: {
+    type: 'boolean',
+    default: false,
+    description:
+      'Report each monorepo workspace separately, with its own pass/fail result. Implied by --workspace-filter and --changed-since.',
+  },
+  workspaceFilter: {
+    type: 'string',
+    isMultiple: true,
+    description:
+      'Only include the monorepo workspaces whose package name or path matches, e.g. `@acme/api`, `@acme/*` or `packages/*`. Accepts a comma-separated value or multiple flags.',
+  },
+}
@@ -0,0 +1,79 @@
+import { handleSchemaPrint } from './handle-schema-print.mts'
+import { OUTPUT_TEXT } from '../../constants/cli.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import {
+  JSON_OUTPUT_VERSION,
+  getJsonOutputCommands,
+} from '../../util/output/json-envelope.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+const config = {
+  commandName: 'print',
+  description: "Print the JSON Schema of a command's --json output",
+  flags: defineFlags({
+    ...commonFlags,
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <COMMAND...>
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    With --json every command prints one envelope:
+      \`{ "version": "${JSON_OUTPUT_VERSION}", "command": "scan report", "ok": true, "data": … }\`
+    or, when it fails, \`ok: false\` with a \`message\` and \`cause\`. The
+    \`version\` changes only with incompatible changes to the envelope or to
+    a published \`data\` schema, so tooling can check it before reading
+    \`data\`.
+
+    Commands that pass a Socket API response through leave \`data\`
+    unconstrained; it follows the API.
+
+    Commands with --json output:
+      ${getJsonOutputCommands().join('\n      ')}
+
+    Examples
+      $ ${command} scan report
+      $ ${command} suppressions list > suppressions-list.schema.json
+  `,
+  hidden: false,
+}
+
+export const cmdSchemaPrint = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const command = cli.input.join(' ')
+
+  const wasValidInput = checkCommandInput(OUTPUT_TEXT, {
+    test: !!command,
+    message: 'The command to print the schema of, e.g. `scan report`',
+    fail: 'missing',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
+  await handleSchemaPrint(command)
+}
@@ -0,0 +1,10 @@
+import { cmdSchemaPrint } from './cmd-schema-print.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdSchema = defineSubcommandGroup({
+  name: 'schema',
+  description: 'Print the JSON Schemas of the --json output',
+  subcommands: {
+    print: cmdSchemaPrint,
+  },
+})
@@ -0,0 +1,22 @@
+import { outputSchemaPrint } from './output-schema-print.mts'
+import { getJsonOutputSchema } from '../../util/output/json-envelope.mts'
+
+import type { CResult } from '../../types.mts'
+
+export function getSchemaForCommand(
+  command: string,
+): CResult<Record<string, unknown>> {
+  const schema = getJsonOutputSchema(command)
+  if (!schema) {
+    return {
+      ok: false,
+      message: 'Unknown command',
+      cause: `\`socket ${command}\` has no --json output; run \`socket schema print --help\` for the list of commands`,
+    }
+  }
+  return { ok: true, data: schema }
+}
+
+export async function handleSchemaPrint(command: string): Promise<void> {
+  await outputSchemaPrint(getSchemaForCommand(command))
+}
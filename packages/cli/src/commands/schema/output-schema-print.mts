@@ -0,0 +1,19 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+
+import type { CResult } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export async function outputSchemaPrint(
+  result: CResult<Record<string, unknown>>,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+  // The schema is the payload itself, not wrapped in an envelope.
+  logger.log(JSON.stringify(result.data, null, 2))
+}
@@ -0,0 +1,130 @@
+import { handleSelfUpdate } from './handle-self-update.mts'
+import { outputDryRunWrite } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { getSeaBinaryPath } from '../../util/sea/detect.mts'
+import {
+  UPDATE_CHANNELS,
+  getChannelDistTag,
+  getDefaultUpdateChannel,
+  isUpdateChannel,
+} from '../../util/update/channel.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'self-update'
+
+const description = 'Update the Socket CLI executable to the latest release'
+
+const hidden = false
+
+export const cmdSelfUpdate = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      channel: {
+        type: 'string',
+        default: '',
+        description: `Release channel to update from: ${UPDATE_CHANNELS.join(' or ')}. Defaults to beta for prereleases, else stable`,
+      },
+      force: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Install the latest release of the channel even when it is not newer',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Replaces the standalone Socket CLI executable with the latest release of
+    the stable or beta channel, downloaded from the npm registry. The release
+    must be signed by a current npm registry key and match its signed
+    integrity, or nothing is replaced. Installs through npm, pnpm or yarn are
+    updated with that package manager instead.
+
+    Switching to stable from a newer beta needs --force.
+
+    Managed environments turn self-update and update notifications off with
+    \`socket config set disableSelfUpdate true\` or SOCKET_CLI_NO_SELF_UPDATE=1.
+    Use \`socket version --check\` to see if a newer release is available
+    without updating.
+
+    Examples
+      $ ${command}
+      $ ${command} --channel beta
+      $ ${command} --channel stable --force
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const force = !!cli.flags['force']
+
+  const channelFlag = String(cli.flags['channel'] || '')
+  const channel = channelFlag || getDefaultUpdateChannel(getCliVersion() || '')
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: isUpdateChannel(channel),
+      message: `The --channel flag must be ${UPDATE_CHANNELS.join(' or ')}`,
+      fail: `got ${channel}`,
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+  )
+  if (!wasValidInput || !isUpdateChannel(channel)) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunWrite(
+      getSeaBinaryPath() ?? process.argv[0] ?? CMD_NAME,
+      `replace the Socket CLI executable with the latest ${channel} release (dist-tag ${getChannelDistTag(channel)})`,
+    )
+    return
+  }
+
+  await handleSelfUpdate({ channel, force, outputKind })
+}
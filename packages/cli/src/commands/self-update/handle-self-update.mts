@@ -0,0 +1,27 @@
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { outputSelfUpdate } from './output-self-update.mts'
+import { selfUpdate } from './self-update.mts'
+
+import type { OutputKind } from '../../types.mts'
+import type { UpdateChannel } from '../../util/update/channel.mts'
+
+export async function handleSelfUpdate({
+  channel,
+  force,
+  outputKind,
+}: {
+  channel: UpdateChannel
+  force: boolean
+  outputKind: OutputKind
+}): Promise<void> {
+  const spinner = getDefaultSpinner()
+  spinner.start(`Checking the latest ${channel} release…`)
+  const result = await selfUpdate({ channel, force })
+  spinner.stop()
+
+  debugDir({ result })
+
+  await outputSelfUpdate(result, outputKind)
+}
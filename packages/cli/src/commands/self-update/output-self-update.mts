@@ -0,0 +1,57 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { SelfUpdateResult } from './self-update.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function getSummary(result: SelfUpdateResult): string {
+  return result.updated
+    ? `Updated the Socket CLI from ${result.current} to ${result.latest} (${result.channel})`
+    : `The Socket CLI ${result.current} is up to date, the latest ${result.channel} release is ${result.latest}`
+}
+
+export async function outputSelfUpdate(
+  result: CResult<SelfUpdateResult>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { data } = result
+  if (outputKind === 'markdown') {
+    logger.log(
+      [
+        mdHeader('Self-update'),
+        '',
+        getSummary(data),
+        '',
+        mdKeyValue('Executable', data.path),
+        mdKeyValue('Channel', data.channel),
+        mdKeyValue('Signature', data.signature ?? 'not checked'),
+      ].join('\n'),
+    )
+    return
+  }
+
+  if (data.updated) {
+    logger.success(getSummary(data))
+    logger.log(`  ${data.signature}`)
+  } else {
+    logger.info(getSummary(data))
+  }
+}
@@ -0,0 +1,261 @@
+/**
+ * `socket self-update`: replace the running standalone executable with the
+ * latest release of a channel.
+ *
+ * Releases are the `@socketsecurity/cli.exe.<triplet>` packages on the npm
+ * registry. Before anything is written the version must carry a registry
+ * signature of its name, version and integrity, made with a current npm
+ * registry key, and the downloaded tarball must match that integrity. The
+ * new executable is written next to the old one and renamed over it, so an
+ * interrupted update leaves the old executable in place.
+ *
+ * Installs through a package manager are left to that package manager.
+ */
+
+import { existsSync, promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { WIN32 } from '@socketsecurity/lib-stable/constants/platform'
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import { getCliName } from '../../env/cli-name.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { SOCKET_CLI_BIN_NAME } from '../../constants/packages.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import {
+  canSelfUpdate,
+  getSeaBinaryPath,
+  isSeaBinary,
+} from '../../util/sea/detect.mts'
+import {
+  getChannelDistTag,
+  getCliExePackageName,
+  getCliExeTriplet,
+  getSelfUpdateDisabledReason,
+} from '../../util/update/channel.mts'
+import { isUpdateAvailable } from '../../util/update/checker.mts'
+import {
+  fetchPackageTarball,
+  readTarballFiles,
+} from '../audit-installed/package-contents.mts'
+import { fetchNpmProvenanceData } from '../verify/fetch-npm-provenance.mts'
+import { getRegistrySignatureCheck } from '../verify/registry-signature.mts'
+
+import type { CResult } from '../../types.mts'
+import type { UpdateChannel } from '../../util/update/channel.mts'
+
+export type CliExeRelease = {
+  integrity: string
+  name: string
+  // How the signature was verified, e.g. the registry key.
+  signature: string
+  tarball: string
+  version: string
+}
+
+export type SelfUpdateResult = {
+  channel: UpdateChannel
+  current: string
+  latest: string
+  path: string
+  signature?: string | undefined
+  updated: boolean
+}
+
+export type SelfUpdateOptions = {
+  channel: UpdateChannel
+  // Reinstall even when the running version is the latest.
+  force?: boolean | undefined
+}
+
+export function getCliExeBinaryName(triplet: string): string {
+  return triplet.startsWith('win32-') ? 'socket.exe' : 'socket'
+}
+
+/**
+ * The latest release of a channel for a platform, or the given version of
+ * it, failing unless the registry signed it.
+ */
+export async function fetchCliExeRelease(
+  triplet: string,
+  channel: UpdateChannel,
+  version?: string | undefined,
+): Promise<CResult<CliExeRelease>> {
+  const name = getCliExePackageName(triplet)
+  const dataCResult = await fetchNpmProvenanceData(
+    name,
+    version || getChannelDistTag(channel),
+  )
+  if (!dataCResult.ok) {
+    return dataCResult
+  }
+  const { manifest } = dataCResult.data
+  const signatureCheck = getRegistrySignatureCheck(manifest, dataCResult.data)
+  if (signatureCheck.status !== 'pass') {
+    return {
+      ok: false,
+      message: 'Signature verification failed',
+      cause: `${name}@${manifest.version}: ${signatureCheck.message}. The executable was not updated.`,
+    }
+  }
+  const { integrity, tarball } = manifest.dist ?? {}
+  if (!integrity || !tarball) {
+    return {
+      ok: false,
+      message: 'Release not found',
+      cause: `The npm registry lists no tarball for ${name}@${manifest.version}`,
+    }
+  }
+  return {
+    ok: true,
+    data: {
+      integrity,
+      name,
+      signature: signatureCheck.message,
+      tarball,
+      version: manifest.version,
+    },
+  }
+}
+
+async function replaceExecutable(
+  binPath: string,
+  contents: Buffer,
+): Promise<void> {
+  const tmpPath = path.join(
+    path.dirname(binPath),
+    `.${path.basename(binPath)}.${process.pid}.new`,
+  )
+  await fs.writeFile(tmpPath, contents, { mode: 0o755 })
+  try {
+    if (WIN32 && existsSync(binPath)) {
+      // A running executable cannot be overwritten on Windows, only renamed.
+      const oldPath = `${binPath}.old`
+      await safeDelete(oldPath, { force: true })
+      await fs.rename(binPath, oldPath)
+    }
+    await fs.rename(tmpPath, binPath)
+  } catch (e) {
+    await safeDelete(tmpPath, { force: true })
+    throw e
+  }
+}
+
+/**
+ * Install the executable of a release tarball at binPath. The tarball is
+ * expected to be checked against the integrity of the release already.
+ */
+export async function writeCliExe(
+  binPath: string,
+  release: CliExeRelease,
+  triplet: string,
+  tgz: Buffer,
+): Promise<CResult<undefined>> {
+  const binFile = `bin/${getCliExeBinaryName(triplet)}`
+  const contents = readTarballFiles(tgz).get(binFile)
+  if (!contents?.length) {
+    return {
+      ok: false,
+      message: 'Executable not found in release',
+      cause: `${release.name}@${release.version} has no ${binFile}`,
+    }
+  }
+  try {
+    await replaceExecutable(binPath, contents)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Could not replace the executable',
+      cause: `Installing ${release.name}@${release.version} at ${binPath} failed: ${getErrorCause(e)}`,
+    }
+  }
+  return { ok: true, data: undefined }
+}
+
+/**
+ * Download a release, check it against its signed integrity and install its
+ * executable at binPath.
+ */
+export async function installCliExe(
+  binPath: string,
+  release: CliExeRelease,
+  triplet: string,
+): Promise<CResult<undefined>> {
+  const tgzCResult = await fetchPackageTarball(release)
+  if (!tgzCResult.ok) {
+    return tgzCResult
+  }
+  return await writeCliExe(binPath, release, triplet, tgzCResult.data)
+}
+
+/**
+ * Update the running standalone executable to the latest release of a
+ * channel. A channel whose latest release is older than the running version
+ * is only installed with force.
+ */
+export async function selfUpdate(
+  options: SelfUpdateOptions,
+): Promise<CResult<SelfUpdateResult>> {
+  const { channel, force = false } = {
+    __proto__: null,
+    ...options,
+  } as SelfUpdateOptions
+
+  const disabledReason = getSelfUpdateDisabledReason()
+  if (disabledReason) {
+    return {
+      ok: false,
+      message: 'Self-update is disabled',
+      cause: `${disabledReason}. The Socket CLI on this machine is updated by its administrator.`,
+    }
+  }
+
+  const binPath = getSeaBinaryPath()
+  if (!isSeaBinary() || !binPath) {
+    const name = getCliName() || SOCKET_CLI_BIN_NAME
+    return {
+      ok: false,
+      message: 'Self-update is only available for the standalone executable',
+      cause: `This Socket CLI was installed with a package manager, update it with \`npm install -g ${name}@${getChannelDistTag(channel)}\` or the package manager that installed it`,
+    }
+  }
+  if (!canSelfUpdate()) {
+    return {
+      ok: false,
+      message: 'This executable is not managed by Socket',
+      cause: `${binPath} was not installed by the Socket installer, update it the way it was installed`,
+    }
+  }
+  const triplet = getCliExeTriplet()
+  if (!triplet) {
+    return {
+      ok: false,
+      message: 'Unsupported platform',
+      cause: `No standalone executable is published for ${process.platform}-${process.arch}`,
+    }
+  }
+
+  const releaseCResult = await fetchCliExeRelease(triplet, channel)
+  if (!releaseCResult.ok) {
+    return releaseCResult
+  }
+  const release = releaseCResult.data
+  const current = getCliVersion() || '0.0.0'
+  const result: SelfUpdateResult = {
+    channel,
+    current,
+    latest: release.version,
+    path: binPath,
+    signature: release.signature,
+    updated: false,
+  }
+  if (!force && !isUpdateAvailable(current, release.version)) {
+    return { ok: true, data: result }
+  }
+
+  const installCResult = await installCliExe(binPath, release, triplet)
+  if (!installCResult.ok) {
+    return installCResult
+  }
+  return { ok: true, data: { ...result, updated: true } }
+}
@@ -0,0 +1,171 @@
+import path from 'node:path'
+
+import { handleSelfInstall } from './handle-self-install.mts'
+import { getDefaultSelfBinDir } from './self-install.mts'
+import { outputDryRunWrite } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import {
+  UPDATE_CHANNELS,
+  getDefaultUpdateChannel,
+  isUpdateChannel,
+} from '../../util/update/channel.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'install'
+
+const description =
+  'Install the standalone Socket CLI executable and put it on PATH'
+
+const hidden = false
+
+export const cmdSelfInstall = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      binDir: {
+        type: 'string',
+        default: '',
+        description: `Directory for the \`socket\` command. Defaults to ${process.platform === 'win32' ? '~\\.socket\\bin' : '~/.local/bin'}`,
+      },
+      channel: {
+        type: 'string',
+        default: '',
+        description: `Release channel to install from: ${UPDATE_CHANNELS.join(' or ')}. Defaults to beta for prereleases, else stable`,
+      },
+      from: {
+        type: 'string',
+        default: '',
+        description:
+          'Install from a release tarball downloaded already. It must match the signed integrity of the release',
+      },
+      release: {
+        type: 'string',
+        default: '',
+        description:
+          'Install this version, e.g. 2.1.0, instead of the latest of the channel',
+      },
+      skipPath: {
+        type: 'boolean',
+        default: false,
+        description: 'Do not put the `socket` command directory on PATH',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Installs the standalone Socket CLI executable of this platform, which
+    needs no Node.js or npm, and a \`socket\` command that runs it. The
+    release is downloaded from the npm registry and must be signed by a
+    current npm registry key and match its signed integrity, or nothing is
+    installed.
+
+    The command directory is put on PATH in ~/.bashrc and ~/.zshrc, on the
+    Windows user PATH, and in GitHub Actions for the later steps of the job.
+    Keep the executable current with \`socket self-update\`.
+
+    Without a Socket CLI, bootstrap with the one-line installers, which
+    verify the download and then run this command:
+
+      curl -fsSL https://github.com/SocketDev/socket-cli/releases/latest/download/install.sh | bash
+      irm https://github.com/SocketDev/socket-cli/releases/latest/download/install.ps1 | iex
+
+    Examples
+      $ ${command}
+      $ ${command} --channel beta
+      $ ${command} --release 2.1.0 --bin-dir /usr/local/bin --skip-path
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const binDir = String(cli.flags['binDir'] || '')
+
+  const from = String(cli.flags['from'] || '')
+
+  const release = String(cli.flags['release'] || '')
+
+  const skipPath = !!cli.flags['skipPath']
+
+  const channelFlag = String(cli.flags['channel'] || '')
+  const channel =
+    channelFlag || getDefaultUpdateChannel(release || getCliVersion() || '')
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: isUpdateChannel(channel),
+      message: `The --channel flag must be ${UPDATE_CHANNELS.join(' or ')}`,
+      fail: `got ${channel}`,
+    },
+    {
+      nook: true,
+      test: !release || /^\d+\.\d+\.\d+(?:-[\w.]+)?$/.test(release),
+      message: 'The --release flag must be a version, e.g. 2.1.0',
+      fail: `got ${release}`,
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+  )
+  if (!wasValidInput || !isUpdateChannel(channel)) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunWrite(
+      path.resolve(binDir || getDefaultSelfBinDir()),
+      `install the ${release ? `${release} release` : `latest ${channel} release`} of the standalone Socket CLI executable${skipPath ? '' : ' and put it on PATH'}`,
+    )
+    return
+  }
+
+  await handleSelfInstall({
+    binDir,
+    channel,
+    from,
+    outputKind,
+    release,
+    skipPath,
+  })
+}
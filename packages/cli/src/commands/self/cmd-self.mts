@@ -0,0 +1,24 @@
+import { cmdSelfInstall } from './cmd-self-install.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Install the standalone Socket CLI executable'
+
+export const cmdSelf: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} self`,
+        importMeta,
+        subcommands: {
+          install: cmdSelfInstall,
+        },
+      },
+      { description },
+    )
+  },
+}
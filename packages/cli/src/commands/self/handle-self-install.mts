@@ -0,0 +1,43 @@
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { outputSelfInstall } from './output-self-install.mts'
+import { selfInstall } from './self-install.mts'
+
+import type { OutputKind } from '../../types.mts'
+import type { UpdateChannel } from '../../util/update/channel.mts'
+
+export async function handleSelfInstall({
+  binDir,
+  channel,
+  from,
+  outputKind,
+  release,
+  skipPath,
+}: {
+  binDir: string
+  channel: UpdateChannel
+  from: string
+  outputKind: OutputKind
+  release: string
+  skipPath: boolean
+}): Promise<void> {
+  const spinner = getDefaultSpinner()
+  spinner.start(
+    release
+      ? `Installing the ${release} release…`
+      : `Installing the latest ${channel} release…`,
+  )
+  const result = await selfInstall({
+    binDir: binDir || undefined,
+    channel,
+    from: from || undefined,
+    updatePath: !skipPath,
+    version: release || undefined,
+  })
+  spinner.stop()
+
+  debugDir({ result })
+
+  await outputSelfInstall(result, outputKind)
+}
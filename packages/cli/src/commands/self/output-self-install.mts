@@ -0,0 +1,82 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { getSelfPathLine } from './self-install.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { SelfInstallResult } from './self-install.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function getPathLines(result: SelfInstallResult): string[] {
+  const lines = [
+    ...result.modifiedFiles.map(file => `Updated ${file}`),
+    ...(result.userPathUpdated ? ['Updated the Windows user PATH'] : []),
+    ...(result.workflowPathUpdated
+      ? ['Added the command directory to PATH for the later steps of the job']
+      : []),
+  ]
+  if (result.onPath) {
+    return lines
+  }
+  if (process.platform === 'win32') {
+    // cmd.exe and PowerShell only read PATH when they start.
+    return [
+      ...lines,
+      result.userPathUpdated
+        ? 'The `socket` command applies to new terminals.'
+        : `To use the \`socket\` command, add ${result.binDir} to PATH.`,
+    ]
+  }
+  return [
+    ...lines,
+    result.modifiedFiles.length
+      ? 'The `socket` command applies to new terminals. To use it in this one, run:'
+      : 'To use the `socket` command, put its directory on PATH, e.g. in your shell profile:',
+    `    ${getSelfPathLine(result.binDir)}`,
+  ]
+}
+
+export async function outputSelfInstall(
+  result: CResult<SelfInstallResult>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { data } = result
+  const summary = `Installed the Socket CLI ${data.version} (${data.channel}) at ${data.launcher}`
+  if (outputKind === 'markdown') {
+    logger.log(
+      [
+        mdHeader('Self-install'),
+        '',
+        summary,
+        '',
+        mdKeyValue('Command', data.launcher),
+        mdKeyValue('Executable', data.executable),
+        mdKeyValue('On PATH', data.onPath ? 'yes' : 'no'),
+        mdKeyValue('Signature', data.signature),
+      ].join('\n'),
+    )
+    return
+  }
+
+  logger.success(summary)
+  logger.log(`  ${data.signature}`)
+  for (const line of getPathLines(data)) {
+    logger.log(line)
+  }
+}
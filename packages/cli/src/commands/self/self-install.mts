@@ -0,0 +1,299 @@
+/**
+ * `socket self install`: install the standalone executable of the platform
+ * and put it on PATH, so a machine needs no Node.js or npm to run the CLI.
+ *
+ * The executable comes from the `@socketsecurity/cli.exe.<triplet>` release
+ * on the npm registry, checked like `socket self-update` checks it: the
+ * version must carry a registry signature and the tarball must match its
+ * signed integrity. It goes where the one-line installers put it, under
+ * ~/.socket/_dlx/, so `socket self-update` keeps it current.
+ *
+ * A `socket` launcher in the bin directory points at it: a symlink in
+ * ~/.local/bin, or a socket.cmd in ~/.socket/bin on Windows. The bin
+ * directory is put on PATH by a marked line in ~/.bashrc and ~/.zshrc, on the
+ * Windows user PATH, and in GitHub Actions also for the later steps of the
+ * job.
+ *
+ * install.sh and install.ps1 download and verify the tarball themselves, then
+ * hand it to `socket self install --from` to finish the installation.
+ */
+
+import crypto from 'node:crypto'
+import { promises as fs } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { getDlxCachePath } from '@socketsecurity/lib-stable/dlx/binary'
+import { safeDelete, safeMkdir } from '@socketsecurity/lib-stable/fs/safe'
+import { getSocketHomePath } from '@socketsecurity/lib-stable/paths/socket'
+
+import { getGithubPath } from '../../env/github-path.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import { getCliExeTriplet } from '../../util/update/channel.mts'
+import { matchesIntegrity } from '../audit-installed/package-contents.mts'
+import {
+  fetchCliExeRelease,
+  getCliExeBinaryName,
+  installCliExe,
+  writeCliExe,
+} from '../self-update/self-update.mts'
+import { getShellRcPaths, updateRcFiles } from '../wrapper/shell-profiles.mts'
+import {
+  addWindowsPathEntry,
+  hasWindowsPathEntry,
+  updateWindowsUserPath,
+} from '../wrapper/windows-user-path.mts'
+import { toGitBashPath } from '../wrapper/wrapper-shim-sh.mts'
+import { isDirOnPath } from '../wrapper/wrapper-shims.mts'
+
+import type { CResult } from '../../types.mts'
+import type { UpdateChannel } from '../../util/update/channel.mts'
+import type { CliExeRelease } from '../self-update/self-update.mts'
+
+const PATH_MARKER = 'Added by `socket self install`.'
+
+export type SelfInstallOptions = {
+  // Where the `socket` launcher goes, see getDefaultSelfBinDir.
+  binDir?: string | undefined
+  channel: UpdateChannel
+  // A release tarball downloaded already, e.g. by install.sh.
+  from?: string | undefined
+  // Put the bin directory on PATH.
+  updatePath: boolean
+  // Install this version instead of the latest of the channel.
+  version?: string | undefined
+}
+
+export type SelfInstallResult = {
+  binDir: string
+  channel: UpdateChannel
+  // The executable, which `socket self-update` replaces.
+  executable: string
+  // The `socket` command in the bin directory.
+  launcher: string
+  modifiedFiles: string[]
+  onPath: boolean
+  signature: string
+  userPathUpdated: boolean
+  version: string
+  workflowPathUpdated: boolean
+}
+
+export function getDefaultSelfBinDir(): string {
+  return process.platform === 'win32'
+    ? path.join(getSocketHomePath(), 'bin')
+    : path.join(os.homedir(), '.local', 'bin')
+}
+
+/**
+ * Where the executable of a release goes. The directory is named by the
+ * SHA-256 of `<name>@<version>`, like install.sh names it.
+ */
+export function getSelfInstallPath(
+  release: Pick<CliExeRelease, 'name' | 'version'>,
+  triplet: string,
+): string {
+  const hash = crypto
+    .createHash('sha256')
+    .update(`${release.name}@${release.version}`)
+    .digest('hex')
+  return path.join(
+    getDlxCachePath(),
+    hash,
+    'package',
+    'bin',
+    getCliExeBinaryName(triplet),
+  )
+}
+
+export function getSelfPathLine(binDir: string): string {
+  const dir = process.platform === 'win32' ? toGitBashPath(binDir) : binDir
+  return `export PATH="${dir}:$PATH" # ${PATH_MARKER}`
+}
+
+/**
+ * The batch launcher of the executable on Windows. cmd.exe and PowerShell
+ * both run it, with the exit code of the executable.
+ */
+export function renderCmdLauncher(executable: string): string {
+  return `@echo off\r\nrem ${PATH_MARKER}\r\n"${executable.replaceAll('%', '%%')}" %*\r\n`
+}
+
+async function writeLauncher(
+  binDir: string,
+  executable: string,
+): Promise<CResult<string>> {
+  const win32 = process.platform === 'win32'
+  const launcher = path.join(binDir, win32 ? 'socket.cmd' : 'socket')
+  try {
+    await safeMkdir(binDir, { recursive: true })
+    if (win32) {
+      await fs.writeFile(launcher, renderCmdLauncher(executable), 'utf8')
+    } else {
+      // Replaces the launcher of an earlier install, like install.sh does.
+      await safeDelete(launcher, { force: true })
+      await fs.symlink(executable, launcher)
+    }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Could not write the socket launcher',
+      cause: `${launcher}: ${getErrorCause(e)}`,
+    }
+  }
+  return { ok: true, data: launcher }
+}
+
+type PathChange = Pick<
+  SelfInstallResult,
+  'modifiedFiles' | 'userPathUpdated' | 'workflowPathUpdated'
+>
+
+async function addBinDirToPath(binDir: string): Promise<CResult<PathChange>> {
+  const pathLine = getSelfPathLine(binDir)
+  const rcCResult = await updateRcFiles(getShellRcPaths(), lines => {
+    // A line of an install to another bin directory is replaced.
+    const kept = lines.filter(l => l === pathLine || !l.includes(PATH_MARKER))
+    if (kept.includes(pathLine)) {
+      return kept
+    }
+    return kept.at(-1) === ''
+      ? [...kept.slice(0, -1), pathLine, '']
+      : [...kept, pathLine, '']
+  })
+  if (!rcCResult.ok) {
+    return rcCResult
+  }
+  let userPathUpdated = false
+  if (process.platform === 'win32') {
+    const userPathCResult = await updateWindowsUserPath(value =>
+      hasWindowsPathEntry(value, binDir)
+        ? value
+        : addWindowsPathEntry(value, binDir),
+    )
+    if (!userPathCResult.ok) {
+      return userPathCResult
+    }
+    userPathUpdated = userPathCResult.data
+  }
+  let workflowPathUpdated = false
+  const githubPath = getGithubPath()
+  if (githubPath && !isDirOnPath(binDir)) {
+    try {
+      await fs.appendFile(githubPath, `${binDir}\n`, 'utf8')
+      workflowPathUpdated = true
+    } catch (e) {
+      return {
+        ok: false,
+        message: 'Could not update the workflow PATH',
+        cause: `${githubPath}: ${getErrorCause(e)}`,
+      }
+    }
+  }
+  return {
+    ok: true,
+    data: {
+      modifiedFiles: rcCResult.data,
+      userPathUpdated,
+      workflowPathUpdated,
+    },
+  }
+}
+
+/**
+ * Install the standalone executable of a signed release and put its bin
+ * directory on PATH. Installing a version again repairs the installation.
+ */
+export async function selfInstall(
+  options: SelfInstallOptions,
+): Promise<CResult<SelfInstallResult>> {
+  const { channel, from, updatePath, version } = {
+    __proto__: null,
+    ...options,
+  } as SelfInstallOptions
+  const binDir = path.resolve(options.binDir || getDefaultSelfBinDir())
+
+  const triplet = getCliExeTriplet()
+  if (!triplet) {
+    return {
+      ok: false,
+      message: 'Unsupported platform',
+      cause: `No standalone executable is published for ${process.platform}-${process.arch}, install the Socket CLI with npm instead`,
+    }
+  }
+
+  const releaseCResult = await fetchCliExeRelease(triplet, channel, version)
+  if (!releaseCResult.ok) {
+    return releaseCResult
+  }
+  const release = releaseCResult.data
+  const executable = getSelfInstallPath(release, triplet)
+
+  try {
+    await safeMkdir(path.dirname(executable), { recursive: true })
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Could not create the install directory',
+      cause: `${path.dirname(executable)}: ${getErrorCause(e)}`,
+    }
+  }
+  let installCResult: CResult<undefined>
+  if (from) {
+    let tgz: Buffer
+    try {
+      tgz = await fs.readFile(from)
+    } catch (e) {
+      return {
+        ok: false,
+        message: 'Could not read the release tarball',
+        cause: `${from}: ${getErrorCause(e)}`,
+      }
+    }
+    if (!matchesIntegrity(tgz, release.integrity)) {
+      return {
+        ok: false,
+        message: 'Integrity check failed',
+        cause: `${from} does not match the integrity the npm registry signed for ${release.name}@${release.version}. Nothing was installed.`,
+      }
+    }
+    installCResult = await writeCliExe(executable, release, triplet, tgz)
+  } else {
+    installCResult = await installCliExe(executable, release, triplet)
+  }
+  if (!installCResult.ok) {
+    return installCResult
+  }
+
+  const launcherCResult = await writeLauncher(binDir, executable)
+  if (!launcherCResult.ok) {
+    return launcherCResult
+  }
+
+  let pathChange: PathChange = {
+    modifiedFiles: [],
+    userPathUpdated: false,
+    workflowPathUpdated: false,
+  }
+  if (updatePath) {
+    const pathCResult = await addBinDirToPath(binDir)
+    if (!pathCResult.ok) {
+      return pathCResult
+    }
+    pathChange = pathCResult.data
+  }
+
+  return {
+    ok: true,
+    data: {
+      ...pathChange,
+      binDir,
+      channel,
+      executable,
+      launcher: launcherCResult.data,
+      onPath: isDirOnPath(binDir),
+      signature: release.signature,
+      version: release.version,
+    },
+  }
+}
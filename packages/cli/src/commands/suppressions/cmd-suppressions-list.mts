@@ -0,0 +1,112 @@
+import {
+  DEFAULT_EXPIRING_DAYS,
+  handleSuppressionsList,
+} from './handle-suppressions-list.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mjs'
+import { SOCKET_POLICY_YML } from '../../constants/socket.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+const config = {
+  commandName: 'list',
+  description: `List the exceptions in \`${SOCKET_POLICY_YML}\` with their reasons and expiry dates`,
+  flags: defineFlags({
+    ...commonFlags,
+    ...outputFlags,
+    days: {
+      type: 'number',
+      default: DEFAULT_EXPIRING_DAYS,
+      description: `How many days ahead an expiry counts as upcoming (default ${DEFAULT_EXPIRING_DAYS})`,
+    },
+    expiring: {
+      type: 'boolean',
+      default: false,
+      description:
+        'Only list exceptions that have expired or expire within --days',
+    },
+  }),
+  help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Reads the nearest \`${SOCKET_POLICY_YML}\` in the current directory or its
+    parents. Every exception needs a \`reason\`; one with an \`expires\` date
+    (YYYY-MM-DD) stops applying after that day, so the alerts it waived fail
+    again.
+
+      \`exceptions:
+        - packages: ['pkg:npm/lodash@4.17.20']
+          alerts: [criticalCVE]
+          reason: No fixed release yet, tracked in SEC-123
+          expires: 2026-12-31\`
+
+    Examples
+      $ ${command}
+      $ ${command} --expiring --days 14 --json
+  `,
+  hidden: false,
+}
+
+export const cmdSuppressionsList = {
+  description: config.description,
+  hidden: config.hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { days, expiring, json, markdown } = cli.flags as {
+    days: number
+    expiring: boolean
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: Number.isInteger(days) && days >= 0,
+      message: 'The --days flag must be a whole number of days',
+      fail: 'invalid',
+    },
+  )
+  if (!wasValidInput) {
+    return
+  }
+
+  await handleSuppressionsList({
+    cwd: process.cwd(),
+    expiringWithinDays: days,
+    onlyExpiring: expiring,
+    outputKind,
+  })
+}
@@ -0,0 +1,10 @@
+import { cmdSuppressionsList } from './cmd-suppressions-list.mts'
+import { defineSubcommandGroup } from '../../util/cli/define-subcommand-group.mts'
+
+export const cmdSuppressions = defineSubcommandGroup({
+  name: 'suppressions',
+  description: 'Review the alert exceptions in socket.policy.yml',
+  subcommands: {
+    list: cmdSuppressionsList,
+  },
+})
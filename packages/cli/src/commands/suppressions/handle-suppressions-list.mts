@@ -0,0 +1,120 @@
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+
+import { outputSuppressionsList } from './output-suppressions-list.mts'
+import { SOCKET_POLICY_YML } from '../../constants/socket.mts'
+import {
+  formatPolicyLintIssue,
+  lintSocketPolicy,
+} from '../../util/policy/socket-policy-lint.mts'
+import {
+  findSocketPolicyPathSync,
+  isPolicyExceptionExpired,
+} from '../../util/policy/socket-policy.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { SocketPolicyException } from '../../util/policy/socket-policy.mts'
+
+const DAY_MS = 24 * 60 * 60 * 1000
+
+export const DEFAULT_EXPIRING_DAYS = 30
+
+export type SuppressionStatus = 'active' | 'expiring' | 'expired'
+
+export type SuppressionEntry = SocketPolicyException & {
+  // Whole days until the exception expires; negative once expired.
+  daysLeft?: number | undefined
+  status: SuppressionStatus
+}
+
+export type SuppressionsList = {
+  path: string
+  suppressions: SuppressionEntry[]
+}
+
+export function getSuppressionEntry(
+  exception: SocketPolicyException,
+  expiringWithinDays: number,
+  now = Date.now(),
+): SuppressionEntry {
+  if (!exception.expires) {
+    return { ...exception, status: 'active' }
+  }
+  // Both are UTC midnights, so this is a whole number of days.
+  const today = Math.floor(now / DAY_MS) * DAY_MS
+  const daysLeft = (Date.parse(exception.expires) - today) / DAY_MS
+  return {
+    ...exception,
+    daysLeft,
+    status: isPolicyExceptionExpired(exception, now)
+      ? 'expired'
+      : daysLeft <= expiringWithinDays
+        ? 'expiring'
+        : 'active',
+  }
+}
+
+/**
+ * List the exceptions of the nearest policy file. With `onlyExpiring` just
+ * the expired ones and those expiring within `expiringWithinDays` are kept.
+ */
+export function listSuppressions(
+  cwd: string,
+  {
+    expiringWithinDays = DEFAULT_EXPIRING_DAYS,
+    now = Date.now(),
+    onlyExpiring = false,
+  }: {
+    expiringWithinDays?: number | undefined
+    now?: number | undefined
+    onlyExpiring?: boolean | undefined
+  } = {},
+): CResult<SuppressionsList> {
+  const policyPath = findSocketPolicyPathSync(cwd)
+  if (!policyPath) {
+    return {
+      ok: false,
+      message: 'No policy file found',
+      cause: `There is no ${SOCKET_POLICY_YML} in ${cwd} or its parent directories`,
+    }
+  }
+  const content = safeReadFileSync(policyPath)
+  if (content === undefined) {
+    return {
+      ok: false,
+      message: 'Policy file not found',
+      cause: `Unable to read ${policyPath}`,
+    }
+  }
+  const { issues, policy } = lintSocketPolicy(
+    Buffer.isBuffer(content) ? content.toString('utf8') : content,
+  )
+  const errors = issues.filter(issue => issue.level === 'error')
+  if (errors.length) {
+    return {
+      ok: false,
+      message: `Invalid policy file ${policyPath}`,
+      cause: `${errors.map(formatPolicyLintIssue).join('; ')}; run \`socket policy lint\` for details`,
+    }
+  }
+  const suppressions = policy.exceptions
+    .map(exception => getSuppressionEntry(exception, expiringWithinDays, now))
+    .filter(entry => !onlyExpiring || entry.status !== 'active')
+  return { ok: true, data: { path: policyPath, suppressions } }
+}
+
+export async function handleSuppressionsList({
+  cwd,
+  expiringWithinDays,
+  onlyExpiring,
+  outputKind,
+}: {
+  cwd: string
+  expiringWithinDays: number
+  onlyExpiring: boolean
+  outputKind: OutputKind
+}): Promise<void> {
+  await outputSuppressionsList(
+    listSuppressions(cwd, { expiringWithinDays, onlyExpiring }),
+    outputKind,
+  )
+}
@@ -0,0 +1,98 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdTable } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type {
+  SuppressionEntry,
+  SuppressionsList,
+} from './handle-suppressions-list.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export function formatSuppressionTarget(entry: SuppressionEntry): string {
+  const parts = [
+    entry.packages.join(', '),
+    entry.paths.join(', '),
+    entry.alerts.join(', '),
+  ].filter(Boolean)
+  return parts.length ? parts.join(' / ') : 'everything'
+}
+
+export function formatSuppressionExpiry(entry: SuppressionEntry): string {
+  const { daysLeft, expires } = entry
+  if (!expires || daysLeft === undefined) {
+    return 'never'
+  }
+  if (daysLeft < 0) {
+    return `${expires} (expired ${-daysLeft} ${pluralize('day', { count: -daysLeft })} ago)`
+  }
+  if (daysLeft === 0) {
+    return `${expires} (today)`
+  }
+  return `${expires} (in ${daysLeft} ${pluralize('day', { count: daysLeft })})`
+}
+
+export async function outputSuppressionsList(
+  result: CResult<SuppressionsList>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { path, suppressions } = result.data
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Suppressions'))
+    logger.log('')
+    logger.log(`Exceptions in ${path}.`)
+    logger.log('')
+    if (!suppressions.length) {
+      logger.log('None.')
+      return
+    }
+    logger.log(
+      mdTable(
+        suppressions.map(entry => ({
+          expires: formatSuppressionExpiry(entry),
+          reason: entry.reason,
+          status: entry.status,
+          target: formatSuppressionTarget(entry),
+        })),
+        ['target', 'reason', 'expires', 'status'],
+        ['Exception', 'Reason', 'Expires', 'Status'],
+      ),
+    )
+    return
+  }
+
+  if (!suppressions.length) {
+    logger.log(`No matching exceptions in ${path}`)
+    return
+  }
+  logger.log(
+    `${suppressions.length} ${pluralize('exception', { count: suppressions.length })} in ${path}:`,
+  )
+  for (let i = 0, { length } = suppressions; i < length; i += 1) {
+    const entry = suppressions[i]!
+    const line = `${formatSuppressionTarget(entry)}: ${entry.reason}; expires ${formatSuppressionExpiry(entry)}`
+    if (entry.status === 'active') {
+      logger.log(`  ${line}`)
+    } else {
+      logger.warn(line)
+    }
+  }
+}
@@ -0,0 +1,94 @@
+import { handleTelemetryOptIn } from './handle-telemetry-opt-in.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { CONFIG_KEY_TELEMETRY } from '../../constants/config.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { defineFlags } from '../../meow.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { outputDryRunWrite } from '../../util/dry-run/output.mts'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { MeowFlags } from '../../flags.mts'
+import type {
+  CliCommandContext,
+  CliSubcommand,
+} from '../../util/cli/with-subcommands.mjs'
+
+export const CMD_NAME = 'disable'
+
+const description = 'Opt out of anonymous usage telemetry'
+
+const hidden = false
+
+export const cmdTelemetryDisable: CliSubcommand = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Sets the ${CONFIG_KEY_TELEMETRY} key of the user config to false and removes
+    the spooled events that were not sent yet, along with the installation ID.
+
+    Examples
+      $ ${command}
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, json, markdown } = cli.flags as {
+    dryRun: boolean
+    json: boolean
+    markdown: boolean
+  }
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(outputKind, {
+    nook: true,
+    test: !json || !markdown,
+    message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+    fail: 'bad',
+  })
+  if (!wasValidInput) {
+    return
+  }
+
+  if (dryRun) {
+    // Runtime read so tests that mutate process.env['HOME'] pick up changes.
+    const configPath = `${process.env['HOME']}/.config/socket/config.json`
+    outputDryRunWrite(configPath, 'opt out of anonymous usage telemetry', [
+      `Set "${CONFIG_KEY_TELEMETRY}" to: false`,
+      'Remove the spooled usage events and the installation ID',
+    ])
+    return
+  }
+
+  await handleTelemetryOptIn({ enabled: false, outputKind })
+}
@@ -0,0 +1,28 @@
+import { cmdTelemetryDisable } from './cmd-telemetry-disable.mts'
+import { cmdTelemetryEnable } from './cmd-telemetry-enable.mts'
+import { cmdTelemetryStatus } from './cmd-telemetry-status.mts'
+import { meowWithSubcommands } from '../../util/cli/with-subcommands.mjs'
+
+import type { CliSubcommand } from '../../util/cli/with-subcommands.mjs'
+
+const description = 'Opt in to or out of anonymous usage telemetry'
+
+export const cmdTelemetry: CliSubcommand = {
+  description,
+  hidden: false,
+  async run(argv, importMeta, { parentName }) {
+    await meowWithSubcommands(
+      {
+        argv,
+        name: `${parentName} telemetry`,
+        importMeta,
+        subcommands: {
+          disable: cmdTelemetryDisable,
+          enable: cmdTelemetryEnable,
+          status: cmdTelemetryStatus,
+        },
+      },
+      { description },
+    )
+  },
+}
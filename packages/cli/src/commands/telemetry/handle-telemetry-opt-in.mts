@@ -0,0 +1,67 @@
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputTelemetryOptIn } from './output-telemetry-opt-in.mts'
+import { CONFIG_KEY_TELEMETRY } from '../../constants/config.mts'
+import { updateConfigValue } from '../../util/config.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+import {
+  clearUsageSpool,
+  getUsageTelemetryState,
+} from '../../util/telemetry/usage.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+
+export type TelemetryOptInData = {
+  // Whether telemetry is on now, which another config layer or DO_NOT_TRACK
+  // may decide instead of the user config.
+  enabled: boolean
+  note: string | undefined
+}
+
+async function optIn(enabled: boolean): Promise<CResult<TelemetryOptInData>> {
+  const updateResult = updateConfigValue(CONFIG_KEY_TELEMETRY, enabled)
+  if (!updateResult.ok) {
+    return updateResult
+  }
+  if (!enabled) {
+    try {
+      await clearUsageSpool()
+    } catch (e) {
+      return {
+        ok: false,
+        message: 'Failed to remove the spooled usage events',
+        cause: getErrorCause(e),
+      }
+    }
+  }
+  const state = getUsageTelemetryState()
+  return {
+    ok: true,
+    message: enabled
+      ? 'Opted in to anonymous usage telemetry'
+      : 'Opted out of anonymous usage telemetry and removed the spooled events',
+    data: {
+      enabled: state.enabled,
+      note:
+        state.enabled === enabled
+          ? updateResult.data
+          : `${state.reason}, so telemetry is ${state.enabled ? 'still on' : 'still off'}`,
+    },
+  }
+}
+
+export async function handleTelemetryOptIn({
+  enabled,
+  outputKind,
+}: {
+  enabled: boolean
+  outputKind: OutputKind
+}): Promise<void> {
+  debug(`${enabled ? 'Enabling' : 'Disabling'} usage telemetry`)
+
+  const result = await optIn(enabled)
+
+  debugDir({ result })
+
+  outputTelemetryOptIn(result, outputKind)
+}
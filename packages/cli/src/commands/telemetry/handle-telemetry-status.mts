@@ -0,0 +1,57 @@
+import {
+  outputTelemetryPayload,
+  outputTelemetryStatus,
+} from './output-telemetry-status.mts'
+import {
+  getUsageEndpoint,
+  getUsageInstallationId,
+  getUsagePayload,
+  getUsageSpoolPath,
+  getUsageTelemetryState,
+  readUsageSpool,
+} from '../../util/telemetry/usage.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export type TelemetryStatusData = {
+  enabled: boolean
+  endpoint: string
+  installationId: string | undefined
+  // Why telemetry is on or off.
+  reason: string
+  spoolPath: string
+  spooledEvents: number
+}
+
+export async function handleTelemetryStatus({
+  dryRun,
+  outputKind,
+}: {
+  dryRun: boolean
+  outputKind: OutputKind
+}): Promise<void> {
+  if (dryRun) {
+    outputTelemetryPayload(
+      await getUsagePayload(),
+      getUsageEndpoint(),
+      outputKind,
+    )
+    return
+  }
+
+  const { enabled, reason } = getUsageTelemetryState()
+  outputTelemetryStatus(
+    {
+      ok: true,
+      data: {
+        enabled,
+        endpoint: getUsageEndpoint(),
+        installationId: await getUsageInstallationId(),
+        reason,
+        spoolPath: getUsageSpoolPath(),
+        spooledEvents: (await readUsageSpool()).length,
+      },
+    },
+    outputKind,
+  )
+}
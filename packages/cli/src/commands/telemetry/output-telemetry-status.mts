@@ -0,0 +1,78 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { DRY_RUN_LABEL, OUTPUT_JSON } from '../../constants/cli.mts'
+import { out } from '../../util/dry-run/output.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { TelemetryStatusData } from './handle-telemetry-status.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+import type { UsagePayload } from '../../util/telemetry/usage.mts'
+
+const logger = getDefaultLogger()
+
+export function outputTelemetryStatus(
+  result: CResult<TelemetryStatusData>,
+  outputKind: OutputKind,
+): void {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const {
+    enabled,
+    endpoint,
+    installationId,
+    reason,
+    spoolPath,
+    spooledEvents,
+  } = result.data
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Telemetry'))
+    logger.log('')
+  }
+  logger.log(`Telemetry: ${enabled ? 'on' : 'off'} (${reason})`)
+  logger.log(`Spooled events: ${spooledEvents} in ${spoolPath}`)
+  logger.log(`Installation ID: ${installationId || '<none>'}`)
+  logger.log(`Endpoint: ${endpoint}`)
+}
+
+/**
+ * The --dry-run of `socket telemetry status`: the payload the spooled events
+ * are sent as, in full, so users can check what leaves their machine.
+ */
+export function outputTelemetryPayload(
+  payload: UsagePayload,
+  endpoint: string,
+  outputKind: OutputKind,
+): void {
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson({ ok: true, data: { endpoint, payload } }))
+    return
+  }
+
+  out('')
+  out(
+    `${DRY_RUN_LABEL}: Would send ${payload.events.length} spooled usage events to ${endpoint}`,
+  )
+  out('')
+  if (outputKind === 'markdown') {
+    logger.log(mdHeader('Telemetry payload'))
+    logger.log('')
+    logger.log('```json')
+    logger.log(JSON.stringify(payload, null, 2))
+    logger.log('```')
+    return
+  }
+  logger.log(JSON.stringify(payload, null, 2))
+}
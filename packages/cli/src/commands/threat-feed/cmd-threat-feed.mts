@@ -3,6 +3,7 @@ import { NPM } from '@socketsecurity/lib-stable/constants/agents'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
 import { handleThreatFeed } from './handle-threat-feed.mts'
+import { parseEcosystems } from './threat-scope.mts'
 import { outputDryRunFetch } from '../../util/dry-run/output.mts'
 import { InputError } from '../../util/error/errors.mts'
 import { defineFlags } from '../../meow.mts'
@@ -42,6 +43,9 @@ const TYPE_FILTERS = new Set([
   'vuln',
 ])
 
+// Seconds between polls with --follow, each poll costs API quota.
+const MIN_FOLLOW_INTERVAL = 10
+
 const description = '[Beta] View the threat-feed'
 
 const hidden = false
@@ -72,19 +76,43 @@ export async function run(
       eco: {
         type: 'string',
         default: '',
-        description: 'Only show threats for a particular ecosystem',
+        description:
+          'Only show threats for these ecosystems, a comma-separated list',
       },
       filter: {
         type: 'string',
         default: 'mal',
         description: 'Filter what type of threats to return',
       },
+      follow: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Keep polling and print new threats as they are published, until interrupted',
+      },
       interactive: {
         type: 'boolean',
         default: true,
         description:
           'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
       },
+      interval: {
+        type: 'number',
+        default: 60,
+        description: `Seconds between polls with --follow, at least ${MIN_FOLLOW_INTERVAL}`,
+      },
+      jsonl: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Output one JSON object per threat per line, for piping into other tools',
+      },
+      namespace: {
+        type: 'string',
+        isMultiple: true,
+        description:
+          'Only show threats in this namespace, e.g. @acme or org.example (repeatable)',
+      },
       org: {
         type: 'string',
         description:
@@ -163,11 +191,18 @@ export async function run(
 
     Note: The version filter is a prefix search, pkg name is a substring search.
 
+    To watch for new threats to the ecosystems and namespaces you use, add
+    --follow. It prints the latest page, then polls every --interval seconds
+    and prints threats as they are published, oldest first, until interrupted.
+    With --jsonl every threat is one JSON object per line, ready to pipe into
+    a SIEM or chat alerting. --page and --direction do not apply to --follow.
+
     Examples
       $ ${command}
       $ ${command} maven --json
       $ ${command} typo
       $ ${command} npm joke 1.0.0 --per-page=5 --page=2 --direction=asc
+      $ ${command} --eco npm,pypi --namespace @acme --follow --jsonl
   `,
   }
 
@@ -190,6 +225,12 @@ export async function run(
 
   const dryRun = cli.flags['dryRun']
 
+  const follow = !!cli.flags['follow']
+
+  const jsonl = !!cli.flags['jsonl']
+
+  const namespaces = (cli.flags['namespace'] as string[] | undefined) ?? []
+
   const interactive = cli.flags['interactive']
 
   let ecoFilter = eco || ''
@@ -252,6 +293,12 @@ export async function run(
 
   const outputKind = getOutputKind(json, markdown)
 
+  const interval = Number(cli.flags['interval'])
+
+  const unknownEcosystems = parseEcosystems(ecoFilter).filter(
+    e => !ECOSYSTEMS.has(e),
+  )
+
   const wasValidInput = checkCommandInput(
     outputKind,
     {
@@ -266,6 +313,31 @@ export async function run(
       message: 'The json and markdown flags cannot be both set, pick one',
       fail: 'omit one',
     },
+    {
+      nook: true,
+      test: !jsonl || (!json && !markdown),
+      message: 'The --jsonl flag cannot be combined with --json or --markdown',
+      fail: 'omit one',
+    },
+    {
+      nook: true,
+      test: !follow || (!json && !markdown),
+      message:
+        'The --follow flag streams threats, use --jsonl instead of --json or --markdown',
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: !follow || interval >= MIN_FOLLOW_INTERVAL,
+      message: `The --interval flag must be at least ${MIN_FOLLOW_INTERVAL} seconds`,
+      fail: `got ${cli.flags['interval']}`,
+    },
+    {
+      nook: true,
+      test: !unknownEcosystems.length,
+      message: `The --eco flag must list known ecosystems: ${joinAnd(Array.from(ECOSYSTEMS))}`,
+      fail: `unknown ${joinAnd(unknownEcosystems)}`,
+    },
     {
       nook: true,
       test: hasApiToken,
@@ -290,6 +362,9 @@ export async function run(
       perPage: validatedPerPage,
       page: cli.flags['page'] || '1',
       direction: cli.flags['direction'] || 'desc',
+      namespaces: namespaces.length ? namespaces.join(', ') : undefined,
+      follow: follow ? `every ${interval}s` : undefined,
+      format: jsonl ? 'jsonl' : undefined,
     })
     return
   }
@@ -303,6 +378,10 @@ export async function run(
     direction: cli.flags['direction'] || 'desc',
     ecosystem: ecoFilter,
     filter: typeFilter,
+    follow,
+    interval,
+    jsonl,
+    namespaces,
     outputKind,
     orgSlug,
     page: cli.flags['page'] || '1',
@@ -0,0 +1,112 @@
+import { setTimeout as sleep } from 'node:timers/promises'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { fetchThreatFeed } from './fetch-threat-feed.mts'
+import { outputThreatBatch } from './output-threat-feed.mts'
+import { filterThreatResults, getApiEcosystem } from './threat-scope.mts'
+
+import type { ThreatScope } from './threat-scope.mts'
+import type { ThreatResult } from './types.mts'
+import type { CResult } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+// Pages fetched per poll to catch up on threats published since the last
+// one, so a burst between polls is not lost.
+const MAX_CATCH_UP_PAGES = 10
+
+// Threat IDs remembered to skip those already printed.
+const MAX_SEEN_IDS = 10_000
+
+export type FollowThreatFeedOptions = {
+  filter: string
+  // Seconds between polls.
+  interval: number
+  jsonl: boolean
+  orgSlug: string
+  perPage: number
+  pkg: string
+  scope: ThreatScope
+  signal?: AbortSignal | undefined
+  version: string
+}
+
+/**
+ * Poll the threat feed and print threats as they are published, oldest
+ * first, until the signal aborts. The first poll prints the latest page.
+ * A first poll that fails is returned; later failures are warned about and
+ * retried on the next poll, so a network blip does not end the stream.
+ */
+export async function followThreatFeed(
+  options: FollowThreatFeedOptions,
+): Promise<CResult<undefined>> {
+  const {
+    filter,
+    interval,
+    jsonl,
+    orgSlug,
+    perPage,
+    pkg,
+    scope,
+    signal,
+    version,
+  } = { __proto__: null, ...options } as FollowThreatFeedOptions
+  const seen = new Set<number>()
+  const ecosystem = getApiEcosystem(scope)
+
+  for (let poll = 0; !signal?.aborted; poll += 1) {
+    const fresh: ThreatResult[] = []
+    let page = '1'
+    let pollCResult: CResult<undefined> = { ok: true, data: undefined }
+    for (let pages = 0; pages < (poll ? MAX_CATCH_UP_PAGES : 1); pages += 1) {
+      const feedCResult = await fetchThreatFeed({
+        direction: 'desc',
+        ecosystem,
+        filter,
+        orgSlug,
+        page,
+        perPage,
+        pkg,
+        version,
+      })
+      if (!feedCResult.ok) {
+        pollCResult = feedCResult
+        break
+      }
+      const { nextPage, results } = feedCResult.data
+      const unseen = results.filter(r => !seen.has(r.id))
+      fresh.push(...unseen)
+      if (unseen.length < results.length || !nextPage) {
+        break
+      }
+      page = nextPage
+    }
+    if (!pollCResult.ok) {
+      if (!poll) {
+        return pollCResult
+      }
+      logger.warn(
+        `Polling the threat feed failed, retrying in ${interval}s: ${pollCResult.message}`,
+      )
+    }
+
+    for (const { id } of fresh) {
+      seen.add(id)
+    }
+    for (const id of seen) {
+      if (seen.size <= MAX_SEEN_IDS) {
+        break
+      }
+      seen.delete(id)
+    }
+    outputThreatBatch(filterThreatResults(fresh, scope).reverse(), jsonl)
+
+    try {
+      await sleep(interval * 1000, undefined, { signal })
+    } catch {
+      break
+    }
+  }
+  return { ok: true, data: undefined }
+}
@@ -1,5 +1,12 @@
 import { fetchThreatFeed } from './fetch-threat-feed.mts'
+import { followThreatFeed } from './follow-threat-feed.mts'
 import { outputThreatFeed } from './output-threat-feed.mts'
+import {
+  filterThreatResults,
+  getApiEcosystem,
+  isThreatScopeNarrowed,
+  parseEcosystems,
+} from './threat-scope.mts'
 
 import type { OutputKind } from '../../types.mts'
 
@@ -7,6 +14,10 @@ export async function handleThreatFeed({
   direction,
   ecosystem,
   filter,
+  follow = false,
+  interval = 60,
+  jsonl = false,
+  namespaces = [],
   orgSlug,
   outputKind,
   page,
@@ -15,8 +26,13 @@ export async function handleThreatFeed({
   version,
 }: {
   direction: string
+  // One ecosystem or a comma-separated list of them.
   ecosystem: string
   filter: string
+  follow?: boolean | undefined
+  interval?: number | undefined
+  jsonl?: boolean | undefined
+  namespaces?: string[] | undefined
   outputKind: OutputKind
   orgSlug: string
   page: string
@@ -24,9 +40,28 @@ export async function handleThreatFeed({
   pkg: string
   version: string
 }): Promise<void> {
+  const scope = { ecosystems: parseEcosystems(ecosystem), namespaces }
+
+  if (follow) {
+    const followCResult = await followThreatFeed({
+      filter,
+      interval,
+      jsonl,
+      orgSlug,
+      perPage,
+      pkg,
+      scope,
+      version,
+    })
+    if (!followCResult.ok) {
+      await outputThreatFeed(followCResult, outputKind)
+    }
+    return
+  }
+
   const data = await fetchThreatFeed({
     direction,
-    ecosystem,
+    ecosystem: getApiEcosystem(scope),
     filter,
     orgSlug,
     page,
@@ -35,5 +70,17 @@ export async function handleThreatFeed({
     version,
   })
 
-  await outputThreatFeed(data, outputKind)
+  await outputThreatFeed(
+    data.ok && isThreatScopeNarrowed(scope)
+      ? {
+          ...data,
+          data: {
+            ...data.data,
+            results: filterThreatResults(data.data.results, scope),
+          },
+        }
+      : data,
+    outputKind,
+    jsonl,
+  )
 }
@@ -5,7 +5,7 @@ import { mdTable } from '../../util/output/markdown.mts'
 import { serializeResultJson } from '../../util/output/result-json.mjs'
 import { getPurlObject } from '../../util/purl/parse.mts'
 
-import type { ThreadFeedResponse } from './types.mts'
+import type { ThreadFeedResponse, ThreatResult } from './types.mts'
 import type { CResult, OutputKind } from '../../types.mts'
 
 const logger = getDefaultLogger()
@@ -32,9 +32,48 @@ export function formatThreatFeedTable(data: ThreadFeedResponse): string {
   ])
 }
 
+/**
+ * One JSON object per line and threat, for piping into a SIEM or alerting.
+ */
+export function formatThreatFeedJsonl(results: ThreatResult[]): string {
+  return results.map(r => JSON.stringify(r)).join('\n')
+}
+
+/**
+ * A threat on one line, for the text output of --follow.
+ */
+export function formatThreatLine(result: ThreatResult): string {
+  return [
+    result.createdAt,
+    result.threatType,
+    result.purl,
+    result.locationHtmlUrl || result.packageHtmlUrl,
+  ]
+    .filter(Boolean)
+    .join('  ')
+}
+
+/**
+ * Print threats as they arrive with --follow, oldest first.
+ */
+export function outputThreatBatch(
+  results: ThreatResult[],
+  jsonl: boolean,
+): void {
+  if (!results.length) {
+    return
+  }
+  logger.log(
+    jsonl
+      ? formatThreatFeedJsonl(results)
+      : results.map(formatThreatLine).join('\n'),
+  )
+}
+
 export async function outputThreatFeed(
   result: CResult<ThreadFeedResponse>,
   outputKind: OutputKind,
+  jsonl = false,
 ) {
   if (!result.ok) {
     process.exitCode = result.code ?? 1
@@ -54,5 +93,10 @@ export async function outputThreatFeed(
     return
   }
 
+  if (jsonl) {
+    logger.log(formatThreatFeedJsonl(result.data.results))
+    return
+  }
+
   logger.log(formatThreatFeedTable(result.data))
 }
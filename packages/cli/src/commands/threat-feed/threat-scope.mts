@@ -0,0 +1,81 @@
+import { getPurlObject } from '../../util/purl/parse.mts'
+
+import type { ThreatResult } from './types.mts'
+
+/**
+ * The ecosystems and namespaces threats are narrowed to. The API filters one
+ * ecosystem at a time, so several ecosystems and any namespaces are matched
+ * here against the purl of each threat.
+ */
+export type ThreatScope = {
+  ecosystems: string[]
+  namespaces: string[]
+}
+
+function normalizeNamespace(namespace: string): string {
+  return namespace.trim().replace(/^@/, '').toLowerCase()
+}
+
+/**
+ * Parse the --eco value, a comma-separated list of ecosystems.
+ */
+export function parseEcosystems(eco: string): string[] {
+  return eco
+    .split(',')
+    .map(e => e.trim())
+    .filter(Boolean)
+}
+
+/**
+ * The ecosystem to ask the API for: the only one in scope, or all of them.
+ */
+export function getApiEcosystem(scope: ThreatScope): string {
+  return scope.ecosystems.length === 1 ? scope.ecosystems[0]! : ''
+}
+
+export function isThreatScopeNarrowed(scope: ThreatScope): boolean {
+  return scope.ecosystems.length > 1 || scope.namespaces.length > 0
+}
+
+/**
+ * Whether a threat is in scope. A namespace matches itself and the
+ * namespaces nested in it, e.g. `org.example` matches `org.example.util`
+ * and `github.com/acme` matches `github.com/acme/tools`. The `@` of npm
+ * scopes is optional.
+ */
+export function matchesThreatScope(
+  result: ThreatResult,
+  scope: ThreatScope,
+): boolean {
+  const purlObj = getPurlObject(result.purl, { throws: false })
+  if (!purlObj) {
+    return false
+  }
+  if (scope.ecosystems.length && !scope.ecosystems.includes(purlObj.type)) {
+    return false
+  }
+  if (!scope.namespaces.length) {
+    return true
+  }
+  const namespace = normalizeNamespace(purlObj.namespace ?? '')
+  return (
+    !!namespace &&
+    scope.namespaces.some(want => {
+      const prefix = normalizeNamespace(want)
+      return (
+        namespace === prefix ||
+        namespace.startsWith(`${prefix}/`) ||
+        namespace.startsWith(`${prefix}.`)
+      )
+    })
+  )
+}
+
+export function filterThreatResults(
+  results: ThreatResult[],
+  scope: ThreatScope,
+): ThreatResult[] {
+  return isThreatScopeNarrowed(scope)
+    ? results.filter(r => matchesThreatScope(r, scope))
+    : results
+}
@@ -0,0 +1,156 @@
+import { handleVerify } from './handle-verify.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import { getPurlObject, normalizePurl } from '../../util/purl/parse.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'verify'
+
+const description =
+  'Verify the provenance and signatures of a published package'
+
+const hidden = false
+
+export const cmdVerify = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      requireProvenance: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Fail when the package was published without a provenance attestation',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <PURL>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Checks an npm package version against its registry signatures and its
+    SLSA provenance attestation: the attestation signature, that it covers
+    the published tarball, that the workflow identity of its Sigstore
+    certificate matches the claimed workflow, and that the source repository
+    matches the package.json. The package is also cross-referenced with the
+    ownership and manifest alerts Socket reports for it.
+
+    Mismatches exit with code 3. Packages published without provenance only
+    warn unless --require-provenance is set. The attestation certificate chain
+    and transparency log entry are not verified, so the attestation checks
+    warn with "unverified certificate" when they would otherwise pass.
+
+    The version defaults to the latest one. A "purl" is a standard package
+    name formatting: \`pkg:npm/name@version\`, the \`pkg:\` prefix is optional.
+
+    Set SOCKET_CLI_VERIFY_PROVENANCE=1 to run these checks on the packages
+    named by \`socket npm install\` before installing them.
+
+    Examples
+      $ ${command} pkg:npm/sigstore@3.0.0
+      $ ${command} npm/%40sigstore/cli --require-provenance
+      $ ${command} pkg:npm/lodash@4.17.21 --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown, requireProvenance } = cli.flags
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const rawPurl = cli.input[0] ?? ''
+
+  const purlObj = rawPurl
+    ? getPurlObject(normalizePurl(rawPurl), { throws: false })
+    : undefined
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      test: !!rawPurl,
+      message: 'The package url (purl) to verify',
+      fail: 'missing',
+    },
+    {
+      test: !rawPurl || !!purlObj,
+      message: 'The package url (purl) must be valid',
+      fail: 'invalid purl',
+    },
+    {
+      test: !purlObj || purlObj.type === 'npm',
+      message: 'Only npm packages have provenance attestations to verify',
+      fail: `saw ${purlObj?.type}`,
+    },
+    {
+      nook: true,
+      test: cli.input.length <= 1,
+      message: 'Verify one package at a time',
+      fail: 'too many arguments',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+  )
+  if (!wasValidInput || !purlObj) {
+    return
+  }
+
+  const name = purlObj.namespace
+    ? `${purlObj.namespace}/${purlObj.name}`
+    : purlObj.name
+  const version = purlObj.version || undefined
+
+  if (dryRun) {
+    outputDryRunFetch('package provenance', {
+      package: `${name}@${version ?? 'latest'}`,
+      requireProvenance,
+    })
+    return
+  }
+
+  await handleVerify({
+    name,
+    outputKind,
+    requireProvenance: !!requireProvenance,
+    version,
+  })
+}
@@ -0,0 +1,189 @@
+/**
+ * npm registry data for `socket verify`: the version manifest, its Sigstore
+ * attestations and the registry's signing keys.
+ */
+
+import { NPM_REGISTRY_URL } from '@socketsecurity/lib-stable/constants/agents'
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { getErrorCause } from '../../util/error/errors.mts'
+import { socketHttpRequest } from '../../util/socket/api-http.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type NpmRepositoryField =
+  | string
+  | {
+      // Package directory in a monorepo.
+      directory?: string | undefined
+      type?: string | undefined
+      url?: string | undefined
+    }
+
+export type NpmDistSignature = {
+  keyid: string
+  sig: string
+}
+
+export type NpmVersionManifest = {
+  dist?:
+    | {
+        attestations?:
+          | {
+              provenance?: { predicateType?: string | undefined } | undefined
+              url?: string | undefined
+            }
+          | undefined
+        integrity?: string | undefined
+        signatures?: NpmDistSignature[] | undefined
+        tarball?: string | undefined
+      }
+    | undefined
+  gitHead?: string | undefined
+  name: string
+  repository?: NpmRepositoryField | undefined
+  version: string
+}
+
+export type NpmRegistryKey = {
+  expires: string | null
+  key: string
+  keyid: string
+  keytype: string
+  scheme: string
+}
+
+export type SigstoreBundle = {
+  dsseEnvelope?:
+    | {
+        payload: string
+        payloadType: string
+        signatures: Array<{ keyid?: string | undefined; sig: string }>
+      }
+    | undefined
+  mediaType?: string | undefined
+  verificationMaterial?:
+    | {
+        certificate?: { rawBytes: string } | undefined
+        publicKey?: { hint?: string | undefined } | undefined
+        x509CertificateChain?:
+          | { certificates: Array<{ rawBytes: string }> }
+          | undefined
+      }
+    | undefined
+}
+
+export type NpmAttestation = {
+  bundle: SigstoreBundle
+  predicateType: string
+}
+
+export type NpmProvenanceData = {
+  attestations: NpmAttestation[]
+  keys: NpmRegistryKey[]
+  manifest: NpmVersionManifest
+}
+
+// `@scope/name` is requested as `@scope%2fname`.
+export function escapePackageName(name: string): string {
+  return name.replace('/', '%2f')
+}
+
+// A 404 reads as undefined data, for packages that are not published.
+export async function fetchRegistryJson<T>(
+  pathname: string,
+  description: string,
+): Promise<CResult<T | undefined>> {
+  const url = new URL(pathname, NPM_REGISTRY_URL).href
+  let response
+  try {
+    response = await socketHttpRequest(url, {
+      headers: { accept: 'application/json' },
+    })
+  } catch (e) {
+    debug(`npm registry request failed: ${url}`)
+    debugDir(e)
+    return {
+      ok: false,
+      message: 'npm registry request failed',
+      cause: `Could not fetch ${description} from ${url}: ${getErrorCause(e)}`,
+    }
+  }
+  if (response.status === 404) {
+    return { ok: true, data: undefined }
+  }
+  if (!response.ok) {
+    return {
+      ok: false,
+      message: 'npm registry request failed',
+      cause: `Fetching ${description} from ${url} failed with ${response.status} ${response.statusText}`,
+    }
+  }
+  try {
+    return { ok: true, data: JSON.parse(response.text()) as T }
+  } catch {
+    return {
+      ok: false,
+      message: 'npm registry request failed',
+      cause: `The npm registry returned invalid JSON for ${description}`,
+    }
+  }
+}
+
+/**
+ * Fetch the manifest, attestations and registry keys of an npm package
+ * version. The version may also be a dist-tag, `latest` by default.
+ */
+export async function fetchNpmProvenanceData(
+  name: string,
+  version = 'latest',
+): Promise<CResult<NpmProvenanceData>> {
+  const escapedName = escapePackageName(name)
+
+  const manifestCResult = await fetchRegistryJson<NpmVersionManifest>(
+    `/${escapedName}/${encodeURIComponent(version)}`,
+    `the ${name}@${version} manifest`,
+  )
+  if (!manifestCResult.ok) {
+    return manifestCResult
+  }
+  const manifest = manifestCResult.data
+  if (!manifest?.version) {
+    return {
+      ok: false,
+      message: 'Package not found',
+      cause: `${name}@${version} is not published on the npm registry`,
+    }
+  }
+
+  // Packages published without provenance have no attestations endpoint.
+  let attestations: NpmAttestation[] = []
+  if (manifest.dist?.attestations) {
+    const attestationsCResult = await fetchRegistryJson<{
+      attestations?: NpmAttestation[] | undefined
+    }>(
+      `/-/npm/v1/attestations/${escapedName}@${manifest.version}`,
+      `the ${name}@${manifest.version} attestations`,
+    )
+    if (!attestationsCResult.ok) {
+      return attestationsCResult
+    }
+    attestations = attestationsCResult.data?.attestations ?? []
+  }
+
+  const keysCResult = await fetchRegistryJson<{
+    keys?: NpmRegistryKey[] | undefined
+  }>('/-/npm/v1/keys', 'the npm registry signing keys')
+  if (!keysCResult.ok) {
+    return keysCResult
+  }
+
+  return {
+    ok: true,
+    data: {
+      attestations,
+      keys: keysCResult.data?.keys ?? [],
+      manifest,
+    },
+  }
+}
@@ -0,0 +1,29 @@
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { outputVerify } from './output-verify.mts'
+import { verifyNpmPackage } from './verify-provenance.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export async function handleVerify({
+  name,
+  outputKind,
+  requireProvenance,
+  version,
+}: {
+  name: string
+  outputKind: OutputKind
+  requireProvenance: boolean
+  version?: string | undefined
+}): Promise<void> {
+  debug(`Verifying provenance of ${name}@${version ?? 'latest'}`)
+
+  const result = await verifyNpmPackage(name, version, {
+    commandPath: 'socket verify',
+    requireProvenance,
+  })
+
+  debugDir({ result })
+
+  await outputVerify(result, outputKind)
+}
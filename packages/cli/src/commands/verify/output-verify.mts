@@ -0,0 +1,122 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { LOG_SYMBOLS } from '@socketsecurity/lib-stable/logger/symbols'
+
+import { EXIT_CODE_POLICY_VIOLATION } from '../../constants/exit-codes.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import {
+  mdHeader,
+  mdKeyValue,
+  mdList,
+  mdTable,
+} from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type {
+  ProvenanceCheckStatus,
+  ProvenanceReport,
+} from './verify-provenance.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+const STATUS_SYMBOLS: Record<ProvenanceCheckStatus, string> = {
+  __proto__: null,
+  fail: LOG_SYMBOLS['fail'],
+  pass: LOG_SYMBOLS['success'],
+  warn: LOG_SYMBOLS['warn'],
+} as unknown as Record<ProvenanceCheckStatus, string>
+
+function getClaimRows(report: ProvenanceReport): Array<[string, string]> {
+  const { provenance } = report
+  const rows: Array<[string, string]> = []
+  if (provenance?.repository) {
+    rows.push(['Source', provenance.repository])
+  }
+  if (provenance?.workflow) {
+    rows.push([
+      'Workflow',
+      `${provenance.workflow}${provenance.ref ? `@${provenance.ref}` : ''}`,
+    ])
+  }
+  if (provenance?.commit) {
+    rows.push(['Commit', provenance.commit])
+  }
+  if (provenance?.builder) {
+    rows.push(['Builder', provenance.builder])
+  }
+  return rows
+}
+
+export function formatProvenanceReport(report: ProvenanceReport): string {
+  const lines = [
+    `${report.purl}: ${report.verified ? 'verified' : 'verification failed'}`,
+  ]
+  const claimRows = getClaimRows(report)
+  if (claimRows.length) {
+    lines.push('')
+    for (const { 0: label, 1: value } of claimRows) {
+      lines.push(`  ${`${label}:`.padEnd(10)}${value}`)
+    }
+  }
+  lines.push('')
+  const width = Math.max(...report.checks.map(c => c.check.length))
+  for (const { check, message, status } of report.checks) {
+    lines.push(`  ${STATUS_SYMBOLS[status]} ${check.padEnd(width)}  ${message}`)
+  }
+  return lines.join('\n')
+}
+
+export function formatProvenanceReportMarkdown(
+  report: ProvenanceReport,
+): string {
+  const claimRows = getClaimRows(report)
+  return [
+    mdHeader(`Provenance of ${report.purl}`),
+    '',
+    mdKeyValue('Result', report.verified ? 'verified' : 'verification failed'),
+    ...(claimRows.length
+      ? [
+          '',
+          mdList(
+            claimRows.map(({ 0: label, 1: value }) => mdKeyValue(label, value)),
+          ),
+        ]
+      : []),
+    '',
+    mdTable(
+      report.checks.map(({ check, message, status }) => ({
+        Check: check,
+        Status: status,
+        Details: message,
+      })),
+      ['Check', 'Status', 'Details'],
+    ),
+  ].join('\n')
+}
+
+export async function outputVerify(
+  result: CResult<ProvenanceReport>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  } else if (!result.data.verified) {
+    process.exitCode = EXIT_CODE_POLICY_VIOLATION
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  if (outputKind === 'markdown') {
+    logger.log(formatProvenanceReportMarkdown(result.data))
+    return
+  }
+
+  logger.log(formatProvenanceReport(result.data))
+}
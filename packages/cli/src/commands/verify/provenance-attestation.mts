@@ -0,0 +1,341 @@
+/**
+ * SLSA provenance attestations of npm package versions: decoding the in-toto
+ * statement of a Sigstore bundle, reading its source and build claims and
+ * checking them against the version manifest.
+ *
+ * The DSSE signature is checked against the signing certificate embedded in
+ * the bundle. The certificate chain up to the Sigstore trust root and the
+ * Rekor transparency log inclusion are not verified, so anyone can mint such
+ * a certificate. Checks that rely on it only warn, and never pass.
+ */
+
+import { X509Certificate, verify } from 'node:crypto'
+
+import { dssePae } from '../../util/sigstore/dsse.mts'
+
+import type {
+  NpmAttestation,
+  NpmRepositoryField,
+  NpmVersionManifest,
+  SigstoreBundle,
+} from './fetch-npm-provenance.mts'
+import type { ProvenanceCheck, ProvenanceClaims } from './verify-provenance.mts'
+
+export const SLSA_PROVENANCE_PREDICATE_PREFIX = 'https://slsa.dev/provenance/'
+
+export type SlsaPredicate = {
+  // SLSA v1.
+  buildDefinition?:
+    | {
+        externalParameters?:
+          | {
+              workflow?:
+                | {
+                    path?: string | undefined
+                    ref?: string | undefined
+                    repository?: string | undefined
+                  }
+                | undefined
+            }
+          | undefined
+        resolvedDependencies?:
+          | Array<{
+              digest?: Record<string, string> | undefined
+              uri?: string | undefined
+            }>
+          | undefined
+      }
+    | undefined
+  runDetails?: { builder?: { id?: string | undefined } } | undefined
+  // SLSA v0.2.
+  builder?: { id?: string | undefined } | undefined
+  invocation?:
+    | {
+        configSource?:
+          | {
+              digest?: Record<string, string> | undefined
+              entryPoint?: string | undefined
+              uri?: string | undefined
+            }
+          | undefined
+      }
+    | undefined
+}
+
+export type InTotoStatement = {
+  predicate?: SlsaPredicate | undefined
+  predicateType?: string | undefined
+  subject?:
+    | Array<{
+        digest?: Record<string, string> | undefined
+        name?: string | undefined
+      }>
+    | undefined
+}
+
+/**
+ * A repository URL as `host/owner/repo`, lowercased, for comparisons. Accepts
+ * npm shorthands like `github:owner/repo` and `owner/repo`, git URLs and
+ * the `git+https://host/owner/repo@ref` form of provenance statements.
+ */
+export function normalizeRepositoryUrl(
+  url: string | undefined,
+): string | undefined {
+  let value = url?.trim()
+  if (!value) {
+    return undefined
+  }
+  const shorthand = /^(github|gitlab|bitbucket):(.+)$/.exec(value)
+  if (shorthand) {
+    const host =
+      shorthand[1] === 'bitbucket' ? 'bitbucket.org' : `${shorthand[1]}.com`
+    value = `${host}/${shorthand[2]}`
+  } else if (/^[\w.-]+\/[\w.-]+$/.test(value)) {
+    value = `github.com/${value}`
+  }
+  value = value
+    .replace(/^git\+/, '')
+    .replace(/^[a-z]+:\/\//i, '')
+    .replace(/^[^@/]+@([^:/]+):/, '$1/')
+    .replace(/^[^@/]+@/, '')
+    .replace(/[#?].*$/, '')
+    .replace(/@[^@]*$/, '')
+    .replace(/\.git$/, '')
+    .replace(/\/+$/, '')
+  return value.toLowerCase() || undefined
+}
+
+export function getManifestRepositoryUrl(
+  repository: NpmRepositoryField | undefined,
+): string | undefined {
+  return typeof repository === 'string' ? repository : repository?.url
+}
+
+export function decodeStatement(
+  bundle: SigstoreBundle,
+): InTotoStatement | undefined {
+  const payload = bundle.dsseEnvelope?.payload
+  if (!payload) {
+    return undefined
+  }
+  try {
+    return JSON.parse(Buffer.from(payload, 'base64').toString('utf8'))
+  } catch {
+    return undefined
+  }
+}
+
+function getSigningCertificate(
+  bundle: SigstoreBundle,
+): X509Certificate | undefined {
+  const material = bundle.verificationMaterial
+  const rawBytes =
+    material?.certificate?.rawBytes ??
+    material?.x509CertificateChain?.certificates[0]?.rawBytes
+  if (!rawBytes) {
+    return undefined
+  }
+  try {
+    return new X509Certificate(Buffer.from(rawBytes, 'base64'))
+  } catch {
+    return undefined
+  }
+}
+
+/**
+ * Whether one of the DSSE envelope signatures verifies against the signing
+ * certificate, or undefined when the bundle has no certificate. The
+ * certificate itself is not verified.
+ */
+export function verifyBundleSignature(
+  bundle: SigstoreBundle,
+): boolean | undefined {
+  const certificate = getSigningCertificate(bundle)
+  const envelope = bundle.dsseEnvelope
+  if (!certificate || !envelope) {
+    return undefined
+  }
+  const pae = dssePae(
+    envelope.payloadType,
+    Buffer.from(envelope.payload, 'base64'),
+  )
+  return envelope.signatures.some(({ sig }) => {
+    try {
+      return verify(
+        'sha256',
+        pae,
+        certificate.publicKey,
+        Buffer.from(sig, 'base64'),
+      )
+    } catch {
+      return false
+    }
+  })
+}
+
+/**
+ * The source and build claims of an SLSA v0.2 or v1 provenance statement.
+ */
+export function getProvenanceClaims(
+  statement: InTotoStatement,
+): ProvenanceClaims {
+  const predicate = statement.predicate ?? {}
+  const predicateType = statement.predicateType ?? ''
+  const { buildDefinition } = predicate
+  if (buildDefinition) {
+    const workflow = buildDefinition.externalParameters?.workflow
+    const source = buildDefinition.resolvedDependencies?.[0]
+    const sourceUri = source?.uri
+    return {
+      builder: predicate.runDetails?.builder?.id,
+      commit: source?.digest?.['gitCommit'],
+      predicateType,
+      ref: workflow?.ref ?? sourceUri?.split('@').at(-1),
+      repository: workflow?.repository ?? sourceUri?.replace(/@[^@]*$/, ''),
+      workflow: workflow?.path,
+    }
+  }
+  const configSource = predicate.invocation?.configSource
+  const uri = configSource?.uri
+  const atIndex = uri ? uri.lastIndexOf('@') : -1
+  return {
+    builder: predicate.builder?.id,
+    commit: configSource?.digest?.['sha1'],
+    predicateType,
+    ref: atIndex === -1 ? undefined : uri!.slice(atIndex + 1),
+    repository: atIndex === -1 ? uri : uri!.slice(0, atIndex),
+    workflow: configSource?.entryPoint,
+  }
+}
+
+/**
+ * A check that would pass if the signing certificate were verified.
+ */
+function unverifiedCheck(check: string, message: string): ProvenanceCheck {
+  return {
+    check,
+    message: `${message} (unverified certificate)`,
+    status: 'warn',
+  }
+}
+
+/**
+ * Checks of a provenance attestation against the version it was read for:
+ * its signature, its subject, the workflow identity of its certificate and
+ * the repository the package.json declares.
+ */
+export function getProvenanceChecks(
+  manifest: NpmVersionManifest,
+  attestation: NpmAttestation,
+  statement: InTotoStatement,
+  claims: ProvenanceClaims,
+): ProvenanceCheck[] {
+  const checks: ProvenanceCheck[] = []
+  const { bundle } = attestation
+
+  const signatureValid = verifyBundleSignature(bundle)
+  checks.push(
+    signatureValid === undefined
+      ? {
+          check: 'provenance-signature',
+          message: 'The attestation has no signing certificate',
+          status: 'fail',
+        }
+      : signatureValid
+        ? unverifiedCheck(
+            'provenance-signature',
+            'The attestation is signed by its certificate',
+          )
+        : {
+            check: 'provenance-signature',
+            message: 'The attestation signature does not verify',
+            status: 'fail',
+          },
+  )
+
+  // The statement subject must be this exact tarball.
+  const integrity = manifest.dist?.integrity ?? ''
+  const expectedDigest = integrity.startsWith('sha512-')
+    ? Buffer.from(integrity.slice(7), 'base64').toString('hex')
+    : undefined
+  const expectedName = `pkg:npm/${manifest.name}@${manifest.version}`
+  const subject = statement.subject?.find(
+    s => decodeURIComponent(s.name ?? '') === expectedName,
+  )
+  checks.push(
+    subject && expectedDigest && subject.digest?.['sha512'] === expectedDigest
+      ? unverifiedCheck(
+          'provenance-subject',
+          'The attestation covers this tarball',
+        )
+      : {
+          check: 'provenance-subject',
+          message: subject
+            ? 'The attested digest does not match the package integrity'
+            : `The attestation does not name ${expectedName}`,
+          status: 'fail',
+        },
+  )
+
+  // Fulcio certificates name the workflow that requested them.
+  const certificate = getSigningCertificate(bundle)
+  const identities = (certificate?.subjectAltName ?? '')
+    .split(/,\s*/)
+    .filter(entry => entry.startsWith('URI:'))
+    .map(entry => entry.slice(4))
+  const claimedRepository = normalizeRepositoryUrl(claims.repository)
+  if (!identities.length || !claims.workflow || !claimedRepository) {
+    checks.push({
+      check: 'workflow-identity',
+      message: 'The attestation does not name a workflow identity',
+      status: 'warn',
+    })
+  } else {
+    const claimedIdentity =
+      `${claimedRepository}/${claims.workflow}@${claims.ref ?? ''}`.toLowerCase()
+    const matches = identities.some(
+      identity =>
+        identity
+          .replace(/^https:\/\//, '')
+          .replace(/\/{2,}/g, '/')
+          .toLowerCase() === claimedIdentity.replace(/\/{2,}/g, '/'),
+    )
+    checks.push(
+      matches
+        ? unverifiedCheck(
+            'workflow-identity',
+            `Built by ${claims.workflow} in ${claimedRepository}`,
+          )
+        : {
+            check: 'workflow-identity',
+            message: `The signing certificate names ${identities.join(', ')}, not the claimed ${claims.workflow} workflow`,
+            status: 'fail',
+          },
+    )
+  }
+
+  const declaredRepository = normalizeRepositoryUrl(
+    getManifestRepositoryUrl(manifest.repository),
+  )
+  if (!declaredRepository) {
+    checks.push({
+      check: 'source-repository',
+      message: `The package.json declares no repository, the provenance names ${claimedRepository ?? 'none'}`,
+      status: 'warn',
+    })
+  } else {
+    checks.push(
+      declaredRepository === claimedRepository
+        ? unverifiedCheck(
+            'source-repository',
+            `Built from the declared repository ${declaredRepository}`,
+          )
+        : {
+            check: 'source-repository',
+            message: `The package.json declares ${declaredRepository} but it was built from ${claimedRepository ?? 'an unknown repository'}`,
+            status: 'fail',
+          },
+    )
+  }
+  return checks
+}
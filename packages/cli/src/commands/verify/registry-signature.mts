@@ -0,0 +1,85 @@
+/**
+ * Registry signature check of npm package versions, shared by `socket
+ * verify` and `socket self-update`.
+ */
+
+import { verify } from 'node:crypto'
+
+import type {
+  NpmProvenanceData,
+  NpmRegistryKey,
+  NpmVersionManifest,
+} from './fetch-npm-provenance.mts'
+import type { ProvenanceCheck } from './verify-provenance.mts'
+
+/**
+ * Whether a current npm registry key signed the name, version and integrity
+ * of a version. Signatures of expired keys fail. Also used by `socket
+ * self-update` for the executables it downloads.
+ */
+export function getRegistrySignatureCheck(
+  manifest: NpmVersionManifest,
+  data: NpmProvenanceData,
+): ProvenanceCheck {
+  const check = 'registry-signature'
+  const { integrity, signatures = [] } = manifest.dist ?? {}
+  if (!integrity || !signatures.length) {
+    return {
+      check,
+      message: 'The registry did not sign this version',
+      status: 'warn',
+    }
+  }
+  const message = Buffer.from(
+    `${manifest.name}@${manifest.version}:${integrity}`,
+  )
+  let expiredKey: NpmRegistryKey | undefined
+  let unknownKeyId: string | undefined
+  for (let i = 0, { length } = signatures; i < length; i += 1) {
+    const { keyid, sig } = signatures[i]!
+    const key = data.keys.find(k => k.keyid === keyid)
+    if (!key) {
+      unknownKeyId = keyid
+      continue
+    }
+    try {
+      if (
+        verify(
+          'sha256',
+          message,
+          { key: Buffer.from(key.key, 'base64'), format: 'der', type: 'spki' },
+          Buffer.from(sig, 'base64'),
+        )
+      ) {
+        if (key.expires && Date.parse(key.expires) <= Date.now()) {
+          expiredKey = key
+          continue
+        }
+        return {
+          check,
+          message: `Signed by the npm registry key ${keyid}`,
+          status: 'pass',
+        }
+      }
+    } catch {}
+  }
+  if (expiredKey) {
+    return {
+      check,
+      message: `Signed by the npm registry key ${expiredKey.keyid}, which expired on ${expiredKey.expires}`,
+      status: 'fail',
+    }
+  }
+  if (unknownKeyId && signatures.length === 1) {
+    return {
+      check,
+      message: `Signed with ${unknownKeyId}, which is not a current npm registry key`,
+      status: 'warn',
+    }
+  }
+  return {
+    check,
+    message: 'The registry signature does not match the package integrity',
+    status: 'fail',
+  }
+}
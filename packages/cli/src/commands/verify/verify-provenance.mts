@@ -0,0 +1,223 @@
+/**
+ * Provenance verification of npm package versions for `socket verify`.
+ *
+ * Key Functions: - verifyNpmPackage: Fetch and verify a package version -
+ * getProvenanceReport: Check a version's registry signatures and SLSA
+ * provenance attestation and cross-reference its source repository and
+ * workflow claims - getSocketMetadataCheck: Cross-reference the ownership and
+ * manifest alerts Socket reports for the package.
+ *
+ * The registry signature check lives in `./registry-signature.mts` and the
+ * attestation checks in `./provenance-attestation.mts`.
+ */
+
+import { fetchNpmProvenanceData } from './fetch-npm-provenance.mts'
+import {
+  SLSA_PROVENANCE_PREDICATE_PREFIX,
+  decodeStatement,
+  getManifestRepositoryUrl,
+  getProvenanceChecks,
+  getProvenanceClaims,
+  normalizeRepositoryUrl,
+} from './provenance-attestation.mts'
+import { getRegistrySignatureCheck } from './registry-signature.mts'
+import { fetchPurlsShallowScore } from '../package/fetch-purls-shallow-score.mts'
+
+import type { NpmProvenanceData } from './fetch-npm-provenance.mts'
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export type ProvenanceCheckStatus = 'fail' | 'pass' | 'warn'
+
+export type ProvenanceCheck = {
+  check: string
+  message: string
+  status: ProvenanceCheckStatus
+}
+
+export type ProvenanceClaims = {
+  builder?: string | undefined
+  commit?: string | undefined
+  predicateType: string
+  ref?: string | undefined
+  repository?: string | undefined
+  workflow?: string | undefined
+}
+
+export type ProvenanceReport = {
+  checks: ProvenanceCheck[]
+  name: string
+  provenance?: ProvenanceClaims | undefined
+  purl: string
+  // Repository declared in the package.json, normalized.
+  repository?: string | undefined
+  verified: boolean
+  version: string
+}
+
+export type GetProvenanceReportOptions = {
+  requireProvenance?: boolean | undefined
+}
+
+// Socket alerts that contradict the registry's ownership or manifest claims.
+const SOCKET_FAIL_ALERT_TYPES = new Set(['manifestConfusion'])
+
+const SOCKET_WARN_ALERT_TYPES = new Set(['newAuthor', 'unstableOwnership'])
+
+/**
+ * Verify a package version from its registry data. The report is verified
+ * when no check fails. Missing provenance only warns unless
+ * requireProvenance is set.
+ */
+export function getProvenanceReport(
+  data: NpmProvenanceData,
+  options?: GetProvenanceReportOptions | undefined,
+): ProvenanceReport {
+  const { requireProvenance = false } = {
+    __proto__: null,
+    ...options,
+  } as GetProvenanceReportOptions
+  const { manifest } = data
+  const checks: ProvenanceCheck[] = [
+    getRegistrySignatureCheck(manifest, data),
+  ]
+
+  const attestation = data.attestations.find(a =>
+    a.predicateType.startsWith(SLSA_PROVENANCE_PREDICATE_PREFIX),
+  )
+  const statement = attestation
+    ? decodeStatement(attestation.bundle)
+    : undefined
+  let provenance: ProvenanceClaims | undefined
+  if (!attestation || !statement) {
+    checks.push({
+      check: 'provenance',
+      message: attestation
+        ? 'The provenance attestation could not be read'
+        : 'Published without a provenance attestation',
+      status: attestation || requireProvenance ? 'fail' : 'warn',
+    })
+  } else {
+    provenance = getProvenanceClaims(statement)
+    checks.push({
+      check: 'provenance',
+      message: `SLSA provenance (${provenance.predicateType})`,
+      status: 'pass',
+    })
+    checks.push(
+      ...getProvenanceChecks(manifest, attestation, statement, provenance),
+    )
+  }
+
+  const repository = normalizeRepositoryUrl(
+    getManifestRepositoryUrl(manifest.repository),
+  )
+  return {
+    checks,
+    name: manifest.name,
+    ...(provenance ? { provenance } : {}),
+    purl: `pkg:npm/${manifest.name}@${manifest.version}`,
+    ...(repository ? { repository } : {}),
+    verified: checks.every(c => c.status !== 'fail'),
+    version: manifest.version,
+  }
+}
+
+/**
+ * Cross-reference the ownership and manifest alerts Socket reports for the
+ * package with the registry's claims.
+ */
+export function getSocketMetadataCheck(
+  artifact: SocketArtifact | undefined,
+): ProvenanceCheck {
+  const check = 'socket-metadata'
+  if (!artifact) {
+    return {
+      check,
+      message: 'Socket has no data for this version yet',
+      status: 'warn',
+    }
+  }
+  const alertTypes = [...new Set((artifact.alerts ?? []).map(a => a.type))]
+  const failTypes = alertTypes.filter(t => SOCKET_FAIL_ALERT_TYPES.has(t))
+  const warnTypes = alertTypes.filter(t => SOCKET_WARN_ALERT_TYPES.has(t))
+  if (failTypes.length) {
+    return {
+      check,
+      message: `Socket reports ${failTypes.join(', ')}: the published manifest differs from the tarball`,
+      status: 'fail',
+    }
+  }
+  if (warnTypes.length) {
+    return {
+      check,
+      message: `Socket reports ${warnTypes.join(', ')}`,
+      status: 'warn',
+    }
+  }
+  return {
+    check,
+    message: 'Socket reports no ownership or manifest alerts',
+    status: 'pass',
+  }
+}
+
+export type VerifyNpmPackageOptions = GetProvenanceReportOptions & {
+  commandPath?: string | undefined
+  // Take Socket's data from the local score cache only.
+  offline?: boolean | undefined
+  // Cross-reference the package with Socket's data. Defaults to true.
+  socket?: boolean | undefined
+}
+
+/**
+ * Fetch and verify an npm package version, a dist-tag or `latest` when the
+ * version is omitted.
+ */
+export async function verifyNpmPackage(
+  name: string,
+  version?: string | undefined,
+  options?: VerifyNpmPackageOptions | undefined,
+): Promise<CResult<ProvenanceReport>> {
+  const {
+    commandPath,
+    offline,
+    requireProvenance,
+    socket = true,
+  } = { __proto__: null, ...options } as VerifyNpmPackageOptions
+
+  const dataCResult = await fetchNpmProvenanceData(name, version)
+  if (!dataCResult.ok) {
+    return dataCResult
+  }
+  const report = getProvenanceReport(dataCResult.data, { requireProvenance })
+  if (!socket) {
+    return { ok: true, data: report }
+  }
+
+  // Socket's data is a cross-reference, the registry checks stand without it.
+  const socketCResult = await fetchPurlsShallowScore([report.purl], {
+    commandPath,
+    offline,
+  })
+  const checks = [
+    ...report.checks,
+    socketCResult.ok
+      ? getSocketMetadataCheck(
+          (socketCResult.data as unknown as SocketArtifact[])[0],
+        )
+      : {
+          check: 'socket-metadata',
+          message: `Skipped: ${socketCResult.cause ?? socketCResult.message}`,
+          status: 'warn' as const,
+        },
+  ]
+  return {
+    ok: true,
+    data: {
+      ...report,
+      checks,
+      verified: checks.every(c => c.status !== 'fail'),
+    },
+  }
+}
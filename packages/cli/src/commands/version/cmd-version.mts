@@ -0,0 +1,126 @@
+import { handleVersion } from './handle-version.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { getFlagListOutput } from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mjs'
+import {
+  UPDATE_CHANNELS,
+  getChannelDistTag,
+  getDefaultUpdateChannel,
+  isUpdateChannel,
+} from '../../util/update/channel.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'version'
+
+const description = 'Show the Socket CLI version and check for updates'
+
+const hidden = false
+
+export const cmdVersion = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      channel: {
+        type: 'string',
+        default: '',
+        description: `Release channel to check with --check: ${UPDATE_CHANNELS.join(' or ')}. Defaults to beta for prereleases, else stable`,
+      },
+      check: {
+        type: 'boolean',
+        default: false,
+        description:
+          'Check the npm registry for a newer release of the channel',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options]
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Shows the version of the Socket CLI and how it was installed. With --check
+    the latest release of the stable or beta channel is looked up and the
+    command to update to it is shown.
+
+    Examples
+      $ ${command}
+      $ ${command} --check
+      $ ${command} --check --channel beta --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { json, markdown } = cli.flags
+
+  const dryRun = !!cli.flags['dryRun']
+
+  const check = !!cli.flags['check']
+
+  const channelFlag = String(cli.flags['channel'] || '')
+  const channel = channelFlag || getDefaultUpdateChannel(getCliVersion() || '')
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: isUpdateChannel(channel),
+      message: `The --channel flag must be ${UPDATE_CHANNELS.join(' or ')}`,
+      fail: `got ${channel}`,
+    },
+    {
+      nook: true,
+      test: !channelFlag || check,
+      message: 'The --channel flag is only used with --check',
+      fail: 'missing --check',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: 'The json and markdown flags cannot be both set, pick one',
+      fail: 'omit one',
+    },
+  )
+  if (!wasValidInput || !isUpdateChannel(channel)) {
+    return
+  }
+
+  if (dryRun && check) {
+    outputDryRunFetch('the latest Socket CLI release', {
+      channel,
+      distTag: getChannelDistTag(channel),
+    })
+    return
+  }
+
+  await handleVersion({ channel, check, outputKind })
+}
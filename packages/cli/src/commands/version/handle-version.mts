@@ -0,0 +1,81 @@
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { outputVersion } from './output-version.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { getSeaBinaryPath, isSeaBinary } from '../../util/sea/detect.mts'
+import {
+  fetchChannelVersion,
+  getSelfUpdateDisabledReason,
+  getUpdatePackageName,
+} from '../../util/update/channel.mts'
+import { isUpdateAvailable } from '../../util/update/checker.mts'
+
+import type { CResult, OutputKind } from '../../types.mts'
+import type { UpdateChannel } from '../../util/update/channel.mts'
+
+export type VersionInfo = {
+  // Present with --check.
+  channel?: UpdateChannel | undefined
+  // Why updates are turned off, see getSelfUpdateDisabledReason.
+  disabledReason?: string | undefined
+  install: 'npm' | 'standalone'
+  latest?: string | undefined
+  packageName?: string | undefined
+  path?: string | undefined
+  updateAvailable?: boolean | undefined
+  version: string
+}
+
+export async function handleVersion({
+  channel,
+  check,
+  outputKind,
+}: {
+  channel: UpdateChannel
+  check: boolean
+  outputKind: OutputKind
+}): Promise<void> {
+  const info: VersionInfo = {
+    install: isSeaBinary() ? 'standalone' : 'npm',
+    path: getSeaBinaryPath(),
+    version: getCliVersion() || '0.0.0',
+  }
+  if (!check) {
+    await outputVersion({ ok: true, data: info }, outputKind)
+    return
+  }
+
+  const packageName = getUpdatePackageName()
+  if (!packageName) {
+    await outputVersion(
+      {
+        ok: false,
+        message: 'Unsupported platform',
+        cause: `No standalone executable is published for ${process.platform}-${process.arch}`,
+      },
+      outputKind,
+    )
+    return
+  }
+
+  const spinner = getDefaultSpinner()
+  spinner.start(`Checking the latest ${channel} release…`)
+  const latestCResult = await fetchChannelVersion(packageName, channel)
+  spinner.stop()
+
+  const result: CResult<VersionInfo> = latestCResult.ok
+    ? {
+        ok: true,
+        data: {
+          ...info,
+          channel,
+          disabledReason: getSelfUpdateDisabledReason(),
+          latest: latestCResult.data,
+          packageName,
+          updateAvailable: isUpdateAvailable(info.version, latestCResult.data),
+        },
+      }
+    : latestCResult
+
+  await outputVersion(result, outputKind)
+}
@@ -0,0 +1,92 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { getCliName } from '../../env/cli-name.mts'
+import { SOCKET_CLI_BIN_NAME } from '../../constants/packages.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdKeyValue } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+import { getChannelDistTag } from '../../util/update/channel.mts'
+
+import type { VersionInfo } from './handle-version.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+/**
+ * How to update to the latest release, undefined when there is nothing to
+ * update or updates are turned off.
+ */
+export function getUpdateHint(info: VersionInfo): string | undefined {
+  if (!info.updateAvailable || info.disabledReason || !info.channel) {
+    return undefined
+  }
+  if (info.install === 'standalone') {
+    return info.channel === 'stable'
+      ? 'socket self-update'
+      : `socket self-update --channel ${info.channel}`
+  }
+  const name = getCliName() || SOCKET_CLI_BIN_NAME
+  return `npm install -g ${name}@${getChannelDistTag(info.channel)}`
+}
+
+function getCheckSummary(info: VersionInfo): string {
+  if (!info.updateAvailable) {
+    return `The Socket CLI is up to date, the latest ${info.channel} release is ${info.latest}`
+  }
+  const hint = getUpdateHint(info)
+  return hint
+    ? `Version ${info.latest} is available on the ${info.channel} channel, run \`${hint}\` to update`
+    : `Version ${info.latest} is available on the ${info.channel} channel. Updates are turned off: ${info.disabledReason}`
+}
+
+export async function outputVersion(
+  result: CResult<VersionInfo>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === 'json') {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { data } = result
+  const install =
+    data.install === 'standalone' ? 'standalone executable' : 'npm package'
+  if (outputKind === 'markdown') {
+    const lines = [
+      mdHeader('Socket CLI version'),
+      '',
+      mdKeyValue('Version', data.version),
+      mdKeyValue('Installed as', install),
+    ]
+    if (data.path) {
+      lines.push(mdKeyValue('Executable', data.path))
+    }
+    if (data.latest) {
+      lines.push(
+        mdKeyValue(`Latest ${data.channel} release`, data.latest),
+        '',
+        getCheckSummary(data),
+      )
+    }
+    logger.log(lines.join('\n'))
+    return
+  }
+
+  logger.log(`${data.version} (${install})`)
+  if (!data.latest) {
+    return
+  }
+  if (data.updateAvailable) {
+    logger.warn(getCheckSummary(data))
+  } else {
+    logger.success(getCheckSummary(data))
+  }
+}
@@ -0,0 +1,156 @@
+import { handleWhy } from './handle-why.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { outputDryRunFetch } from '../../util/dry-run/output.mts'
+import { defineFlags } from '../../meow.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
+import { readCompletionHistory } from '../../util/cli/completion-history.mts'
+import { meowOrExit } from '../../util/cli/with-subcommands.mts'
+import {
+  getFlagApiRequirementsOutput,
+  getFlagListOutput,
+} from '../../util/output/formatting.mts'
+import { getOutputKind } from '../../util/output/mode.mts'
+import { determineOrgSlug } from '../../util/socket/org-slug.mts'
+import { hasDefaultApiToken } from '../../util/socket/sdk.mts'
+import { checkCommandInput } from '../../util/validation/check-input.mts'
+
+import type { CliCommandContext } from '../../util/cli/with-subcommands.mts'
+import type { MeowFlags } from '../../flags.mts'
+
+export const CMD_NAME = 'why'
+
+const description =
+  'Show which direct dependencies pull a package into a scan'
+
+const hidden = false
+
+export const cmdWhy = {
+  description,
+  hidden,
+  run,
+}
+
+export async function run(
+  argv: string[] | readonly string[],
+  importMeta: ImportMeta,
+  { parentName }: CliCommandContext,
+): Promise<void> {
+  const config = {
+    commandName: CMD_NAME,
+    description,
+    hidden,
+    flags: defineFlags({
+      ...commonFlags,
+      ...outputFlags,
+      interactive: {
+        type: 'boolean',
+        default: true,
+        description:
+          'Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.',
+      },
+      org: {
+        type: 'string',
+        default: '',
+        description:
+          'Force override the organization slug, overrides the default org from config',
+      },
+      scan: {
+        type: 'string',
+        default: '',
+        description:
+          'ID of the scan to trace, defaults to the scan created or listed last on this machine',
+      },
+    }),
+    help: (command: string, helpConfig: { flags: MeowFlags }) => `
+    Usage
+      $ ${command} [options] <PACKAGE>
+
+    API Token Requirements
+      ${getFlagApiRequirementsOutput(`${parentName}:${CMD_NAME}`)}
+
+    Prints every dependency chain of the scanned dependency graph from a
+    direct dependency of the project down to PACKAGE, so you know which
+    direct dependency to bump to get rid of an alerted transitive one.
+    PACKAGE is a name, name@version or purl; without a version every
+    scanned version is traced.
+
+    Options
+      ${getFlagListOutput(helpConfig.flags)}
+
+    Examples
+      $ ${command} lodash@4.17.20
+      $ ${command} @babel/traverse --scan 000aaaa1-0000-0a0a-00a0-00a0000000a0
+      $ ${command} pkg:pypi/urllib3@1.26.5 --json
+  `,
+  }
+
+  const cli = meowOrExit({
+    argv,
+    config,
+    importMeta,
+    parentName,
+  })
+
+  const { dryRun, interactive, json, markdown, org: orgFlag } = cli.flags
+
+  const [pkg = ''] = cli.input
+
+  const scanId = cli.flags['scan'] || (await readCompletionHistory()).scan[0]
+
+  const hasApiToken = hasDefaultApiToken()
+
+  const { 0: orgSlug } = await determineOrgSlug(orgFlag, interactive, dryRun)
+
+  const outputKind = getOutputKind(json, markdown)
+
+  const wasValidInput = checkCommandInput(
+    outputKind,
+    {
+      nook: true,
+      test: !!orgSlug,
+      message: 'Org name by default setting, --org, or auto-discovered',
+      fail: 'missing',
+    },
+    {
+      test: !!pkg,
+      message: 'Package to trace',
+      fail: 'missing',
+    },
+    {
+      test: !!scanId,
+      message: 'Scan ID by --scan, or a scan created or listed before',
+      fail: 'missing',
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
+    },
+    {
+      nook: true,
+      test: hasApiToken,
+      message: 'This command requires a Socket API token for access',
+      fail: 'try `socket login`',
+    },
+  )
+  if (!wasValidInput || !scanId) {
+    return
+  }
+
+  if (dryRun) {
+    outputDryRunFetch('scan dependency graph', {
+      organization: orgSlug,
+      scanId,
+      package: pkg,
+    })
+    return
+  }
+
+  await handleWhy({
+    orgSlug,
+    outputKind,
+    pkg,
+    scanId,
+  })
+}
@@ -0,0 +1,217 @@
+/**
+ * Dependency chains for `socket why`.
+ *
+ * The scan's dependency graph is walked up from every artifact matching the
+ * queried package to the project's direct dependencies. Each walk that ends
+ * at a direct dependency is one chain, listed from the direct dependency down
+ * to the package. Scans without a graph only know the top level ancestors the
+ * API reports, so their chains skip the packages in between.
+ */
+
+import { getArtifactPurlString, getPurlObject } from '../../util/purl/parse.mts'
+import { getPkgFullNameFromPurl } from '../../util/socket/url.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../../util/alert/artifact.mts'
+
+export type PackageQuery = {
+  name: string
+  type?: string | undefined
+  version?: string | undefined
+}
+
+export type WhyPackage = {
+  purl: string
+  direct: boolean
+  // Purls from a direct dependency down to the package.
+  chains: string[][]
+  // Set when there were more chains than MAX_DEPENDENCY_CHAINS.
+  truncated: boolean
+}
+
+export type WhyResult = {
+  scanId: string
+  query: string
+  // Whether the scan has a dependency graph, otherwise chains only hold the
+  // top level ancestor and the package.
+  hasGraph: boolean
+  packages: WhyPackage[]
+  // The direct dependencies to bump, the heads of all chains.
+  directDependencies: string[]
+}
+
+export const MAX_DEPENDENCY_CHAINS = 100
+
+/**
+ * Read `name`, `name@version` or a purl. Scoped npm names keep their `@`.
+ */
+export function parsePackageQuery(spec: string): PackageQuery | undefined {
+  const trimmed = spec.trim()
+  if (trimmed.startsWith('pkg:')) {
+    const purlObj = getPurlObject(trimmed, { throws: false })
+    if (!purlObj?.name) {
+      return undefined
+    }
+    return {
+      name: getPkgFullNameFromPurl(purlObj),
+      type: purlObj.type,
+      ...(purlObj.version ? { version: purlObj.version } : {}),
+    }
+  }
+  const atIndex = trimmed.lastIndexOf('@')
+  const name = atIndex > 0 ? trimmed.slice(0, atIndex) : trimmed
+  const version = atIndex > 0 ? trimmed.slice(atIndex + 1) : ''
+  if (!name) {
+    return undefined
+  }
+  return { name, ...(version ? { version } : {}) }
+}
+
+function getArtifactId(artifact: SocketArtifact): string {
+  return artifact.id || getArtifactPurlString(artifact)
+}
+
+function matchesQuery(artifact: SocketArtifact, query: PackageQuery): boolean {
+  if (!artifact.name || (query.type && artifact.type !== query.type)) {
+    return false
+  }
+  if (query.version && artifact.version !== query.version) {
+    return false
+  }
+  return (
+    getPkgFullNameFromPurl(artifact).toLowerCase() === query.name.toLowerCase()
+  )
+}
+
+/**
+ * Every chain of ids from a direct dependency down to targetId, walking the
+ * dependents depth first. Walks stop at the first direct dependency and skip
+ * cycles.
+ */
+export function getDependencyChainIds(
+  targetId: string,
+  parents: Map<string, string[]>,
+  artifactById: Map<string, SocketArtifact>,
+  maxChains = MAX_DEPENDENCY_CHAINS,
+): { chains: string[][]; truncated: boolean } {
+  const chains: string[][] = []
+  let truncated = false
+  // Ids from targetId up to the package being visited.
+  const path: string[] = []
+  const onPath = new Set<string>()
+  const visit = (id: string): void => {
+    if (truncated) {
+      return
+    }
+    path.push(id)
+    onPath.add(id)
+    if (artifactById.get(id)?.direct) {
+      if (chains.length === maxChains) {
+        truncated = true
+      } else {
+        chains.push([...path].reverse())
+      }
+    } else {
+      const ids = parents.get(id) ?? []
+      for (let i = 0, { length } = ids; i < length; i += 1) {
+        if (!onPath.has(ids[i]!)) {
+          visit(ids[i]!)
+        }
+      }
+    }
+    path.pop()
+    onPath.delete(id)
+  }
+  visit(targetId)
+  return { chains, truncated }
+}
+
+/**
+ * The dependency chains of the scan packages matching the query.
+ */
+export function getWhyResult(
+  scan: SocketArtifact[],
+  spec: string,
+  scanId: string,
+): CResult<WhyResult> {
+  const query = parsePackageQuery(spec)
+  if (!query) {
+    return {
+      ok: false,
+      message: 'Invalid package',
+      cause: `Expected a package name, name@version or purl, got ${spec}`,
+    }
+  }
+
+  const artifactById = new Map<string, SocketArtifact>()
+  for (let i = 0, { length } = scan; i < length; i += 1) {
+    artifactById.set(getArtifactId(scan[i]!), scan[i]!)
+  }
+  const parents = new Map<string, string[]>()
+  for (const { 0: id, 1: artifact } of artifactById) {
+    const deps = (artifact.dependencies ?? []) as string[]
+    for (let i = 0, { length } = deps; i < length; i += 1) {
+      const childId = deps[i]!
+      if (!artifactById.has(childId) || childId === id) {
+        continue
+      }
+      const childParents = parents.get(childId) ?? []
+      if (!childParents.includes(id)) {
+        childParents.push(id)
+        parents.set(childId, childParents)
+      }
+    }
+  }
+  const hasGraph = parents.size > 0
+
+  const toPurl = (id: string) => getArtifactPurlString(artifactById.get(id)!)
+  const packages: WhyPackage[] = []
+  const directDependencies = new Set<string>()
+  for (const { 0: id, 1: artifact } of artifactById) {
+    if (!matchesQuery(artifact, query)) {
+      continue
+    }
+    let chainIds: string[][]
+    let truncated = false
+    if (hasGraph) {
+      const found = getDependencyChainIds(id, parents, artifactById)
+      chainIds = found.chains
+      truncated = found.truncated
+    } else if (artifact.direct) {
+      chainIds = [[id]]
+    } else {
+      chainIds = ((artifact.topLevelAncestors ?? []) as string[])
+        .filter(ancestorId => ancestorId !== id && artifactById.has(ancestorId))
+        .map(ancestorId => [ancestorId, id])
+    }
+    const chains = chainIds.map(ids => ids.map(toPurl))
+    for (const chain of chains) {
+      directDependencies.add(chain[0]!)
+    }
+    packages.push({
+      purl: getArtifactPurlString(artifact),
+      direct: !!artifact.direct,
+      chains,
+      truncated,
+    })
+  }
+  if (!packages.length) {
+    return {
+      ok: false,
+      message: 'Package not found',
+      cause: `Scan ${scanId} has no package matching ${spec}`,
+    }
+  }
+  packages.sort((a, b) => a.purl.localeCompare(b.purl))
+
+  return {
+    ok: true,
+    data: {
+      scanId,
+      query: spec,
+      hasGraph,
+      packages,
+      directDependencies: [...directDependencies].sort(),
+    },
+  }
+}
@@ -0,0 +1,26 @@
+import { getWhyResult } from './dependency-chains.mts'
+import { outputWhy } from './output-why.mts'
+import { fetchScan } from '../scan/fetch-scan.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export type HandleWhyConfig = {
+  orgSlug: string
+  outputKind: OutputKind
+  pkg: string
+  scanId: string
+}
+
+export async function handleWhy({
+  orgSlug,
+  outputKind,
+  pkg,
+  scanId,
+}: HandleWhyConfig): Promise<void> {
+  const scanCResult = await fetchScan(orgSlug, scanId)
+
+  await outputWhy(
+    scanCResult.ok ? getWhyResult(scanCResult.data, pkg, scanId) : scanCResult,
+    outputKind,
+  )
+}
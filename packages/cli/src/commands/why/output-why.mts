@@ -0,0 +1,108 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+import colors from 'yoctocolors-cjs'
+
+import { MAX_DEPENDENCY_CHAINS } from './dependency-chains.mts'
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader, mdList } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type { WhyPackage, WhyResult } from './dependency-chains.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+const NO_GRAPH_NOTE =
+  'The scan has no dependency graph, so chains only show the top level ancestor'
+
+// `pkg:npm/lodash@4.17.20` reads as `lodash@4.17.20`.
+function formatPurl(purl: string): string {
+  return purl.replace(/^pkg:[^/]+\//, '')
+}
+
+function getChainLines(pkg: WhyPackage): string[] {
+  const lines = pkg.chains.map(chain => chain.map(formatPurl).join(' > '))
+  if (pkg.truncated) {
+    lines.push(
+      `...and more, only the first ${MAX_DEPENDENCY_CHAINS} are listed`,
+    )
+  }
+  if (!lines.length) {
+    lines.push('No chain from a direct dependency was found')
+  }
+  return lines
+}
+
+function getPackageTitle(pkg: WhyPackage): string {
+  const count = pkg.chains.length
+  const direct = pkg.direct ? ' (direct dependency)' : ''
+  const chains = `${count}${pkg.truncated ? '+' : ''} ${pluralize('chain', { count })}`
+  return `${formatPurl(pkg.purl)}${direct}: ${chains}`
+}
+
+function getBumpNote(data: WhyResult): string {
+  const target =
+    data.directDependencies.length === 1
+      ? 'this direct dependency'
+      : 'one of these direct dependencies'
+  return `To remove ${data.query}, bump or replace ${target}`
+}
+
+export async function outputWhy(
+  result: CResult<WhyResult>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { data } = result
+  const directDependencies = data.directDependencies.map(formatPurl)
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader(`Why ${data.query}`))
+    if (!data.hasGraph) {
+      logger.log('')
+      logger.log(`Note: ${NO_GRAPH_NOTE}.`)
+    }
+    for (const pkg of data.packages) {
+      logger.log('')
+      logger.log(mdHeader(getPackageTitle(pkg), 2))
+      logger.log('')
+      logger.log(mdList(getChainLines(pkg)))
+    }
+    if (directDependencies.length) {
+      logger.log('')
+      logger.log(mdHeader('Direct dependencies', 2))
+      logger.log('')
+      logger.log(`${getBumpNote(data)}:`)
+      logger.log('')
+      logger.log(mdList(directDependencies))
+    }
+    return
+  }
+
+  if (!data.hasGraph) {
+    logger.warn(NO_GRAPH_NOTE)
+  }
+  for (const pkg of data.packages) {
+    logger.log(colors.bold(getPackageTitle(pkg)))
+    for (const line of getChainLines(pkg)) {
+      logger.log(`  ${line}`)
+    }
+    logger.log('')
+  }
+  if (directDependencies.length) {
+    logger.info(`${getBumpNote(data)}: ${directDependencies.join(', ')}`)
+  }
+}
@@ -7,21 +7,7 @@ import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 const logger = getDefaultLogger()
 
 export function checkSocketWrapperSetup(file: string): boolean {
-  let fileContent: string
-  try {
-    fileContent = readFileSync(file, 'utf8')
-  } catch {
-    // File may have been deleted or become unreadable.
-    return false
-  }
-
-  const linesWithSocketAlias = fileContent
-    .split('\n')
-    .filter(
-      l => l === 'alias npm="socket npm"' || l === 'alias npx="socket npx"',
-    )
-
-  if (linesWithSocketAlias.length) {
+  if (hasSocketWrapperAlias(file)) {
     logger.log(
       `The Socket npm/npx wrapper is set up in your bash profile (${file}).`,
     )
@@ -37,3 +23,21 @@ export function checkSocketWrapperSetup(file: string): boolean {
   }
   return false
 }
+
+/**
+ * Whether the shell profile `file` has the aliases of `socket wrapper`.
+ */
+export function hasSocketWrapperAlias(file: string): boolean {
+  let fileContent: string
+  try {
+    fileContent = readFileSync(file, 'utf8')
+  } catch {
+    // File may have been deleted or become unreadable.
+    return false
+  }
+  return fileContent
+    .split(/\r?\n/)
+    .some(
+      l => l === 'alias npm="socket npm"' || l === 'alias npx="socket npx"',
+    )
+}
@@ -1,19 +1,26 @@
 /* oxlint-disable-next-line socket/no-file-scope-oxlint-disable -- legitimate file-scope: domain-grouped layout or test fixture; per-call would produce many redundant disables. */
 /* oxlint-disable socket/no-npx-dlx -- product feature name / command wrapping npx; the literal is intentional. */
 
-import { existsSync } from 'node:fs'
+import path from 'node:path'
 
-import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { normalizePath } from '@socketsecurity/lib-stable/paths/normalize'
 
-import { addSocketWrapper } from './add-socket-wrapper.mts'
-import { checkSocketWrapperSetup } from './check-socket-wrapper-setup.mts'
+import { handleWrapperChange, handleWrapperStatus } from './handle-wrapper.mts'
+import { outputWrapperChange } from './output-wrapper.mts'
 import { postinstallWrapper } from './postinstall-wrapper.mts'
-import { removeSocketWrapper } from './remove-socket-wrapper.mts'
-import { outputDryRunWrite } from '../../util/dry-run/output.mts'
-import { getBashRcPath, getZshRcPath } from '../../constants/paths.mjs'
+import {
+  DEFAULT_WRAPPED_BINS,
+  WRAPPABLE_BINS,
+  getShimDir,
+  getWrapperFileChanges,
+  isWrappableBin,
+} from './wrapper-shims.mts'
+import { FLAG_JSON, FLAG_MARKDOWN } from '../../constants/cli.mts'
+import { outputDryRunDiff } from '../../util/dry-run/output.mts'
 import { defineFlags } from '../../meow.mts'
-import { commonFlags } from '../../flags.mts'
+import { commonFlags, outputFlags } from '../../flags.mts'
 import { meowOrExit } from '../../util/cli/with-subcommands.mjs'
+import { tildify } from '../../util/fs/home-path.mts'
 import { getFlagListOutput } from '../../util/output/formatting.mts'
 import { getOutputKind } from '../../util/output/mode.mjs'
 import { checkCommandInput } from '../../util/validation/check-input.mts'
@@ -21,17 +28,26 @@ import { checkCommandInput } from '../../util/validation/check-input.mts'
 import type { CliCommandContext } from '../../util/cli/with-subcommands.mjs'
 import type { MeowFlags } from '../../flags.mts'
 
-const logger = getDefaultLogger()
+const ENABLE_ACTIONS = new Set(['enable', 'enabled', 'on'])
+
+const DISABLE_ACTIONS = new Set(['disable', 'disabled', 'off'])
 
 const config = {
   commandName: 'wrapper',
   description: 'Enable or disable the Socket npm/pnpm exec wrapper',
   flags: defineFlags({
     ...commonFlags,
+    ...outputFlags,
+    project: {
+      type: 'boolean',
+      default: false,
+      description:
+        'Put the shims in .socket/shims of the current directory instead of the user shim directory',
+    },
   }),
   help: (command: string, helpConfig: { flags: MeowFlags }) => `
     Usage
-      $ ${command} <"on" | "off">
+      $ ${command} <"enable" | "disable" | "status"> [package-manager ...]
 
     Options
       ${getFlagListOutput(helpConfig.flags)}
@@ -40,9 +56,26 @@ const config = {
     machine, it will automatically actually run \`socket npm\` / \`socket npx\`
     instead.
 
+    The wrapper is a set of shims on PATH, one per package manager
+    (${WRAPPABLE_BINS.join(', ')}). Without a package manager, enable wraps
+    ${DEFAULT_WRAPPED_BINS.join(' and ')} and disable unwraps everything. "on" and "off" still
+    work as enable and disable.
+
+    User shims go in a Socket directory that ~/.bashrc and ~/.zshrc put on
+    PATH. With --project they go in .socket/shims of the current directory,
+    for a tool like direnv to put on PATH. Shims are outside every package
+    manager install and find the real binary when they run, so package
+    manager upgrades do not break or remove them.
+
+    Status shows what each scope wraps, whether the shell reaches the shims
+    and the binary each shim ends up running.
+
     Examples
-      $ ${command} on
-      $ ${command} off
+      $ ${command} enable
+      $ ${command} enable pnpm yarn
+      $ ${command} enable --project npm
+      $ ${command} disable
+      $ ${command} status --json
   `,
   hidden: false,
 }
@@ -71,149 +104,99 @@ export async function run(
     parentName,
   })
 
-  // Feature request: Implement json/markdown output for wrapper command status.
-  const { json, markdown } = cli.flags
+  const { json, markdown, project } = cli.flags as {
+    json: boolean
+    markdown: boolean
+    project: boolean
+  }
 
-  const dryRun = cli.flags['dryRun']
+  const dryRun = !!cli.flags['dryRun']
 
-  let enable = false
-  let disable = false
-  const [arg] = cli.input
-  if (arg === 'enable' || arg === 'enabled' || arg === 'on') {
-    enable = true
-    disable = false
-  } else if (arg === 'disable' || arg === 'disabled' || arg === 'off') {
-    enable = false
-    disable = true
-  }
+  const [arg = '', ...binArgs] = cli.input
+  const action = ENABLE_ACTIONS.has(arg)
+    ? 'enable'
+    : DISABLE_ACTIONS.has(arg)
+      ? 'disable'
+      : arg === 'status'
+        ? 'status'
+        : undefined
+  const unknownBins = binArgs.filter(name => !isWrappableBin(name))
 
   const outputKind = getOutputKind(json, markdown)
 
   const wasValidInput = checkCommandInput(
     outputKind,
     {
-      test: enable || disable,
-      message: 'Must specify "on" or "off" argument',
+      test: !!action,
+      message: 'Must specify "enable", "disable" or "status" argument',
       fail: 'missing',
     },
     {
       nook: true,
-      test: cli.input.length <= 1,
-      message: 'expecting exactly one argument',
-      fail: 'got multiple',
+      test: action !== 'status' || !binArgs.length,
+      message: 'status does not take package managers',
+      fail: 'got some',
+    },
+    {
+      nook: true,
+      test: !unknownBins.length,
+      message: `Package managers must be among ${WRAPPABLE_BINS.join(', ')}`,
+      fail: `got ${unknownBins.join(', ')}`,
+    },
+    {
+      nook: true,
+      test: !json || !markdown,
+      message: `The \`${FLAG_JSON}\` and \`${FLAG_MARKDOWN}\` flags can not be used at the same time`,
+      fail: 'bad',
     },
   )
-  if (!wasValidInput) {
+  if (!wasValidInput || !action) {
+    return
+  }
+
+  const cwd = process.cwd()
+
+  if (action === 'status') {
+    await handleWrapperStatus(cwd, outputKind)
     return
   }
 
-  const bashRcPath = getBashRcPath()
-  const zshRcPath = getZshRcPath()
+  const scope = project ? 'project' : 'user'
+  const bins = binArgs.filter(isWrappableBin)
+  const wrappedBins = bins.length
+    ? bins
+    : action === 'enable'
+      ? DEFAULT_WRAPPED_BINS
+      : [...WRAPPABLE_BINS]
 
   if (dryRun) {
-    const files = []
-    if (existsSync(bashRcPath)) {
-      files.push(bashRcPath)
+    const filesCResult = getWrapperFileChanges(action, scope, wrappedBins, cwd)
+    if (!filesCResult.ok) {
+      await outputWrapperChange(filesCResult, outputKind)
+      return
     }
-    if (existsSync(zshRcPath)) {
-      files.push(zshRcPath)
-    }
-    const changes = enable
-      ? [
-          'Add shell aliases/functions to wrap npm/pnpm exec commands',
-          'Redirect npm/pnpm exec calls to socket npm/socket npx',
-        ]
-      : [
-          'Remove Socket wrapper aliases/functions from shell config',
-          'Restore original npm/pnpm exec behavior',
-        ]
-    outputDryRunWrite(
-      files.join(', '),
-      enable
+    const shimDir = getShimDir(scope, cwd)
+    outputDryRunDiff(
+      action === 'enable'
         ? 'enable Socket npm/pnpm exec wrapper'
         : 'disable Socket npm/pnpm exec wrapper',
-      changes,
+      filesCResult.data.map(file => ({
+        ...file,
+        path:
+          scope === 'project'
+            ? normalizePath(path.relative(cwd, file.path))
+            : tildify(file.path),
+      })),
+      process.platform === 'win32' && scope === 'user'
+        ? [
+            action === 'enable'
+              ? `Put ${shimDir} on the Windows user PATH`
+              : `Take ${shimDir} off the Windows user PATH once no shim is left`,
+          ]
+        : [],
     )
     return
   }
-  const modifiedFiles: string[] = []
-  const skippedFiles: string[] = []
-
-  if (enable) {
-    if (existsSync(bashRcPath)) {
-      if (!checkSocketWrapperSetup(bashRcPath)) {
-        await addSocketWrapper(bashRcPath)
-        modifiedFiles.push(bashRcPath)
-      } else {
-        skippedFiles.push(bashRcPath)
-      }
-    }
-    if (existsSync(zshRcPath)) {
-      if (!checkSocketWrapperSetup(zshRcPath)) {
-        await addSocketWrapper(zshRcPath)
-        modifiedFiles.push(zshRcPath)
-      } else {
-        skippedFiles.push(zshRcPath)
-      }
-    }
-  } else {
-    if (existsSync(bashRcPath)) {
-      removeSocketWrapper(bashRcPath)
-      modifiedFiles.push(bashRcPath)
-    }
-    if (existsSync(zshRcPath)) {
-      removeSocketWrapper(zshRcPath)
-      modifiedFiles.push(zshRcPath)
-    }
-  }
-
-  if (!existsSync(bashRcPath) && !existsSync(zshRcPath)) {
-    logger.fail('There was an issue setting up the alias in your bash profile')
-    return
-  }
 
-  // Output results in requested format.
-  if (outputKind === 'json') {
-    const result = {
-      action: enable ? 'enabled' : 'disabled',
-      modifiedFiles,
-      skippedFiles,
-      success: modifiedFiles.length > 0 || skippedFiles.length > 0,
-    }
-    logger.log(JSON.stringify(result, null, 2))
-  } else if (outputKind === 'markdown') {
-    const arr = []
-    arr.push(`# Socket Wrapper ${enable ? 'Enabled' : 'Disabled'}`)
-    arr.push('')
-
-    if (modifiedFiles.length > 0) {
-      arr.push('## Modified Files')
-      arr.push('')
-      for (let i = 0, { length } = modifiedFiles; i < length; i += 1) {
-        const file = modifiedFiles[i]
-        arr.push(`- \`${file}\``)
-      }
-      arr.push('')
-    }
-
-    if (skippedFiles.length > 0) {
-      arr.push('## Skipped Files (already configured)')
-      arr.push('')
-      for (let i = 0, { length } = skippedFiles; i < length; i += 1) {
-        const file = skippedFiles[i]
-        arr.push(`- \`${file}\``)
-      }
-      arr.push('')
-    }
-
-    arr.push('## Status')
-    arr.push('')
-    arr.push(
-      `Socket npm/npx wrapper has been **${enable ? 'enabled' : 'disabled'}**.`,
-    )
-    arr.push('')
-
-    logger.log(arr.join('\n'))
-  }
-  // Text mode output is already handled by add/remove functions.
+  await handleWrapperChange(action, scope, wrappedBins, cwd, outputKind)
 }
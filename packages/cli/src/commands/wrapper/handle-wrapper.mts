@@ -0,0 +1,33 @@
+import { outputWrapperChange, outputWrapperStatus } from './output-wrapper.mts'
+import {
+  disableWrapperShims,
+  enableWrapperShims,
+  getWrapperStatus,
+} from './wrapper-shims.mts'
+
+import type { WrappableBin, WrapperScope } from './wrapper-shims.mts'
+import type { OutputKind } from '../../types.mts'
+
+export async function handleWrapperChange(
+  action: 'disable' | 'enable',
+  scope: WrapperScope,
+  bins: WrappableBin[],
+  cwd: string,
+  outputKind: OutputKind,
+): Promise<void> {
+  const result =
+    action === 'enable'
+      ? await enableWrapperShims(scope, bins, cwd)
+      : await disableWrapperShims(scope, bins, cwd)
+  await outputWrapperChange(result, outputKind)
+}
+
+export async function handleWrapperStatus(
+  cwd: string,
+  outputKind: OutputKind,
+): Promise<void> {
+  await outputWrapperStatus(
+    { ok: true, data: getWrapperStatus(cwd) },
+    outputKind,
+  )
+}
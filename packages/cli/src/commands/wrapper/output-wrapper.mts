@@ -0,0 +1,147 @@
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { OUTPUT_JSON, OUTPUT_MARKDOWN } from '../../constants/cli.mts'
+import { failMsgWithBadge } from '../../util/error/fail-msg-with-badge.mts'
+import { mdHeader } from '../../util/output/markdown.mts'
+import { serializeResultJson } from '../../util/output/result-json.mjs'
+
+import type {
+  WrapperChange,
+  WrapperScopeStatus,
+  WrapperStatus,
+} from './wrapper-shims.mts'
+import type { CResult, OutputKind } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+function getActivationLines(change: WrapperChange): string[] {
+  if (change.shimDirOnPath) {
+    return []
+  }
+  if (change.scope === 'project') {
+    return [
+      'The project shims are not on PATH. Add them for this project, e.g. with direnv:',
+      '',
+      '    echo "PATH_add .socket/shims" >> .envrc',
+    ]
+  }
+  if (process.platform === 'win32') {
+    // cmd.exe and PowerShell only read PATH when they start.
+    return [
+      'The shims are on the Windows user PATH and apply to new terminals.',
+    ]
+  }
+  if (change.modifiedFiles.length) {
+    return [
+      'The shims apply to new terminals. To use them in this one, run:',
+      '',
+      `    source ${change.modifiedFiles[0]}`,
+    ]
+  }
+  return [
+    'No shell profile puts the shims on PATH. Add this to the profile of your shell:',
+    '',
+    `    export PATH="${change.shimDir}:$PATH"`,
+  ]
+}
+
+export async function outputWrapperChange(
+  result: CResult<WrapperChange>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const change = result.data
+  const enabled = change.action === 'enabled'
+  const bins = change.bins.join(', ')
+  const summary = change.bins.length
+    ? `${enabled ? 'Wrapped' : 'Unwrapped'} ${bins} with ${change.scope} shims in ${change.shimDir}`
+    : `No ${change.scope} shims to remove in ${change.shimDir}`
+  const lines = [
+    ...change.modifiedFiles.map(file => `Updated ${file}`),
+    ...(change.userPathUpdated ? ['Updated the Windows user PATH'] : []),
+    ...(enabled ? getActivationLines(change) : []),
+  ]
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader(`Socket Wrapper ${enabled ? 'Enabled' : 'Disabled'}`))
+    logger.log('')
+    logger.log(summary)
+    if (lines.length) {
+      logger.log('')
+    }
+  } else {
+    logger.success(summary)
+  }
+  for (const line of lines) {
+    logger.log(line)
+  }
+}
+
+function getScopeLines(status: WrapperScopeStatus): string[] {
+  const shimmed = status.bins.filter(bin => bin.shimmed)
+  const title = `${status.scope === 'user' ? 'User' : 'Project'} shims (${status.shimDir})`
+  if (!shimmed.length) {
+    return [`- ${title}: none`]
+  }
+  const lines = [`- ${title}: ${status.onPath ? 'on PATH' : 'not on PATH'}`]
+  for (const bin of shimmed) {
+    const state = bin.active
+      ? 'wrapped'
+      : 'installed, but another one comes first on PATH'
+    lines.push(
+      `  - ${bin.name}: ${state}, runs ${bin.target ?? '(not installed)'}`,
+    )
+  }
+  return lines
+}
+
+export async function outputWrapperStatus(
+  result: CResult<WrapperStatus>,
+  outputKind: OutputKind,
+): Promise<void> {
+  if (!result.ok) {
+    process.exitCode = result.code ?? 1
+  }
+
+  if (outputKind === OUTPUT_JSON) {
+    logger.log(serializeResultJson(result))
+    return
+  }
+  if (!result.ok) {
+    logger.fail(failMsgWithBadge(result.message, result.cause))
+    return
+  }
+
+  const { aliasFiles, pathFiles, scopes } = result.data
+  const lines = [
+    ...scopes.flatMap(status => getScopeLines(status)),
+    `- Shell profiles with the user shims on PATH: ${pathFiles.join(', ') || 'none'}`,
+  ]
+  if (aliasFiles.length) {
+    lines.push(
+      `- Legacy npm/npx aliases: ${aliasFiles.join(', ')} (\`socket wrapper enable\` replaces them)`,
+    )
+  }
+
+  if (outputKind === OUTPUT_MARKDOWN) {
+    logger.log(mdHeader('Socket wrapper'))
+  } else {
+    logger.log('Socket wrapper:')
+  }
+  logger.log('')
+  for (const line of lines) {
+    logger.log(line)
+  }
+}
@@ -16,11 +16,14 @@ export function removeSocketWrapper(filepath: string): void {
   }
 
   const linesWithoutSocketAlias = content
-    .split('\n')
+    .split(/\r?\n/)
     .filter(
       l => l !== 'alias npm="socket npm"' && l !== 'alias npx="socket npx"',
     )
-  const updatedContent = linesWithoutSocketAlias.join('\n')
+  // Keep CRLF line endings of profiles edited on Windows.
+  const updatedContent = linesWithoutSocketAlias.join(
+    content.includes('\r\n') ? '\r\n' : '\n',
+  )
   try {
     writeFileSync(filepath, updatedContent, 'utf8')
   } catch (e) {
@@ -0,0 +1,91 @@
+/* oxlint-disable-next-line socket/no-file-scope-oxlint-disable -- legitimate file-scope: domain-grouped layout or test fixture; per-call would produce many redundant disables. */
+/* oxlint-disable socket/no-npx-dlx -- product feature name / command wrapping npx; the literal is intentional. */
+
+/**
+ * Editing of the shell profiles (~/.bashrc and ~/.zshrc) that `socket
+ * wrapper` and `socket self install` put their PATH lines in.
+ */
+
+import { existsSync, promises as fs } from 'node:fs'
+
+import { getBashRcPath, getZshRcPath } from '../../constants/paths.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+
+import type { CResult } from '../../types.mts'
+
+// The aliases of earlier versions, which the shims replace.
+export const LEGACY_ALIASES = [
+  'alias npm="socket npm"',
+  'alias npx="socket npx"',
+]
+
+export function getShellRcPaths(): string[] {
+  return [getBashRcPath(), getZshRcPath()].filter(file => existsSync(file))
+}
+
+export function updateRcContent(
+  content: string,
+  update: (lines: string[]) => string[],
+): string {
+  // Keep the line endings of the profile, e.g. CRLF on Windows.
+  const eol = content.includes('\r\n') ? '\r\n' : '\n'
+  return update(content.split(/\r?\n/)).join(eol)
+}
+
+// The profile lines with the PATH line added and the legacy aliases dropped.
+export function addShimPathLine(lines: string[], pathLine: string): string[] {
+  const kept = lines.filter(l => !LEGACY_ALIASES.includes(l))
+  if (kept.includes(pathLine)) {
+    return kept
+  }
+  // Keep the trailing newline of the profile.
+  return kept.at(-1) === ''
+    ? [...kept.slice(0, -1), pathLine, '']
+    : [...kept, pathLine, '']
+}
+
+export function removeShimPathLine(
+  lines: string[],
+  pathLine: string,
+): string[] {
+  return lines.filter(l => l !== pathLine && !LEGACY_ALIASES.includes(l))
+}
+
+/**
+ * Rewrite the lines of shell profiles, returning the profiles that changed.
+ */
+export async function updateRcFiles(
+  files: string[],
+  update: (lines: string[]) => string[],
+): Promise<CResult<string[]>> {
+  const modifiedFiles: string[] = []
+  for (const file of files) {
+    let content: string
+    try {
+      // oxlint-disable-next-line no-await-in-loop -- two shell profiles at most.
+      content = await fs.readFile(file, 'utf8')
+    } catch (e) {
+      return {
+        ok: false,
+        message: 'Could not read shell profile',
+        cause: `${file}: ${getErrorCause(e)}`,
+      }
+    }
+    const updated = updateRcContent(content, update)
+    if (updated === content) {
+      continue
+    }
+    try {
+      // oxlint-disable-next-line no-await-in-loop -- two shell profiles at most.
+      await fs.writeFile(file, updated, 'utf8')
+    } catch (e) {
+      return {
+        ok: false,
+        message: 'Could not update shell profile',
+        cause: `${file}: ${getErrorCause(e)}; check that it is writable`,
+      }
+    }
+    modifiedFiles.push(file)
+  }
+  return { ok: true, data: modifiedFiles }
+}
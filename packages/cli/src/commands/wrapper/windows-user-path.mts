@@ -0,0 +1,129 @@
+/**
+ * The Windows user PATH, where `socket wrapper enable` puts the user shim
+ * directory for cmd.exe and PowerShell. Neither reads a shell profile, so
+ * changing ~/.bashrc alone leaves them unwrapped.
+ *
+ * The value lives in HKCU\Environment and is read and written through
+ * PowerShell. Entries like %USERPROFILE%\bin are kept unexpanded, and `setx`
+ * is avoided because it cuts values longer than 1024 characters. New
+ * terminals see the change, running ones keep their PATH.
+ */
+
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import { getErrorCause } from '../../util/error/errors.mts'
+
+import type { CResult } from '../../types.mts'
+
+// Passes the new value without quoting it into the script.
+const USER_PATH_ENV = 'SOCKET_CLI_WINDOWS_USER_PATH'
+
+const READ_USER_PATH_SCRIPT =
+  "(Get-Item -Path 'HKCU:\\Environment').GetValue('Path', '', 'DoNotExpandEnvironmentNames')"
+
+// Setting any user variable broadcasts WM_SETTINGCHANGE, so Explorer and the
+// terminals it starts pick up the new PATH without signing out.
+const WRITE_USER_PATH_SCRIPT = [
+  `Set-ItemProperty -Path 'HKCU:\\Environment' -Name 'Path' -Type ExpandString -Value $env:${USER_PATH_ENV}`,
+  "[Environment]::SetEnvironmentVariable('SOCKET_CLI_PATH_REFRESH', '1', 'User')",
+  "[Environment]::SetEnvironmentVariable('SOCKET_CLI_PATH_REFRESH', $null, 'User')",
+].join('; ')
+
+function normalizeWindowsPathEntry(entry: string): string {
+  return entry
+    .trim()
+    .replace(/[\\/]+$/, '')
+    .replaceAll('/', '\\')
+    .toLowerCase()
+}
+
+function splitWindowsPath(value: string): string[] {
+  return value.split(';').filter(entry => !!entry.trim())
+}
+
+/**
+ * Whether a `;` separated Windows PATH has `dir`, ignoring case and trailing
+ * separators like Windows does.
+ */
+export function hasWindowsPathEntry(value: string, dir: string): boolean {
+  const wanted = normalizeWindowsPathEntry(dir)
+  return splitWindowsPath(value).some(
+    entry => normalizeWindowsPathEntry(entry) === wanted,
+  )
+}
+
+/**
+ * `dir` first in a Windows PATH, so the shims in it come before the package
+ * managers they wrap.
+ */
+export function addWindowsPathEntry(value: string, dir: string): string {
+  const wanted = normalizeWindowsPathEntry(dir)
+  return [
+    dir,
+    ...splitWindowsPath(value).filter(
+      entry => normalizeWindowsPathEntry(entry) !== wanted,
+    ),
+  ].join(';')
+}
+
+export function removeWindowsPathEntry(value: string, dir: string): string {
+  const wanted = normalizeWindowsPathEntry(dir)
+  return splitWindowsPath(value)
+    .filter(entry => normalizeWindowsPathEntry(entry) !== wanted)
+    .join(';')
+}
+
+async function runPowerShell(
+  script: string,
+  env?: Record<string, string> | undefined,
+): Promise<string> {
+  const result = await spawn(
+    'powershell.exe',
+    ['-NoProfile', '-NonInteractive', '-Command', script],
+    { env: { ...process.env, ...env } },
+  )
+  return String(result.stdout).trim()
+}
+
+export async function readWindowsUserPath(): Promise<CResult<string>> {
+  try {
+    return { ok: true, data: await runPowerShell(READ_USER_PATH_SCRIPT) }
+  } catch (e) {
+    debug('Failed to read the Windows user PATH')
+    debugDir(e)
+    return {
+      ok: false,
+      message: 'Could not read the Windows user PATH',
+      cause: getErrorCause(e),
+    }
+  }
+}
+
+/**
+ * Apply `update` to the Windows user PATH. Returns whether it changed.
+ */
+export async function updateWindowsUserPath(
+  update: (value: string) => string,
+): Promise<CResult<boolean>> {
+  const readCResult = await readWindowsUserPath()
+  if (!readCResult.ok) {
+    return readCResult
+  }
+  const updated = update(readCResult.data)
+  if (updated === readCResult.data) {
+    return { ok: true, data: false }
+  }
+  try {
+    await runPowerShell(WRITE_USER_PATH_SCRIPT, { [USER_PATH_ENV]: updated })
+  } catch (e) {
+    debug('Failed to write the Windows user PATH')
+    debugDir(e)
+    return {
+      ok: false,
+      message: 'Could not update the Windows user PATH',
+      cause: `${getErrorCause(e)}; add the shim directory to PATH in the Environment Variables settings instead`,
+    }
+  }
+  return { ok: true, data: true }
+}
@@ -0,0 +1,32 @@
+/**
+ * The Windows .cmd shim of `socket wrapper`. There is no .ps1 shim:
+ * PowerShell prefers one over the .cmd file, and the default execution policy
+ * refuses to run it.
+ */
+
+import { SHIM_MARKER } from './wrapper-shim-sh.mts'
+
+// A literal % in a batch file is written %%.
+function escapeBatch(value: string): string {
+  return value.replaceAll('%', '%%')
+}
+
+/**
+ * The .cmd shim of `name`, which cmd.exe and PowerShell both run. It drops
+ * its directory from PATH with or without a trailing backslash; the
+ * replacements ignore case, like Windows paths do.
+ */
+export function renderCmdShim(name: string, shimDir: string): string {
+  const dir = escapeBatch(shimDir)
+  return [
+    '@echo off',
+    `rem ${SHIM_MARKER}`,
+    'setlocal',
+    'set "PATH=;%PATH%;"',
+    `set "PATH=%PATH:;${dir};=;%"`,
+    `set "PATH=%PATH:;${dir}\\;=;%"`,
+    'set "PATH=%PATH:~1,-1%"',
+    `socket ${name} %*`,
+    '',
+  ].join('\r\n')
+}
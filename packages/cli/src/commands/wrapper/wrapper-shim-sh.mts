@@ -0,0 +1,58 @@
+/**
+ * The sh shim of `socket wrapper` and the shell profile line that puts the
+ * shim directory on PATH. Every platform gets the sh shim; on Windows it is
+ * for Git Bash, which spells the directory as /c/Users/….
+ */
+
+export const SHIM_MARKER = 'Managed by `socket wrapper`, do not edit.'
+
+/**
+ * A Windows path as Git Bash spells it, e.g. `C:\Users\jo` as `/c/Users/jo`.
+ * Other paths are returned as they are.
+ */
+export function toGitBashPath(file: string): string {
+  const match = /^([A-Za-z]):[\\/]?(.*)$/.exec(file)
+  if (!match) {
+    return file
+  }
+  const rest = match[2]!.replaceAll('\\', '/')
+  return `/${match[1]!.toLowerCase()}${rest ? `/${rest}` : ''}`
+}
+
+/**
+ * The shell profile line that puts `shimDir` on PATH.
+ */
+export function getShimPathLine(shimDir: string): string {
+  const dir = process.platform === 'win32' ? toGitBashPath(shimDir) : shimDir
+  return `export PATH="${dir}:$PATH" # ${SHIM_MARKER}`
+}
+
+function quoteShellSingle(value: string): string {
+  return `'${value.replaceAll("'", `'\\''`)}'`
+}
+
+/**
+ * The sh shim of `name`, for POSIX shells and Git Bash.
+ */
+export function renderShShim(name: string, shimDir: string): string {
+  const shellShimDir =
+    process.platform === 'win32' ? toGitBashPath(shimDir) : shimDir
+  return [
+    '#!/bin/sh',
+    `# ${SHIM_MARKER}`,
+    `shim_dir=${quoteShellSingle(shellShimDir)}`,
+    // Drop this directory from PATH so Socket CLI finds the real binary.
+    'path=',
+    'set -f',
+    'IFS=:',
+    'for dir in $PATH; do',
+    '  [ "$dir" = "$shim_dir" ] || path="${path:+$path:}$dir"',
+    'done',
+    'unset IFS',
+    'set +f',
+    'PATH=$path',
+    'export PATH',
+    `exec socket ${name} "$@"`,
+    '',
+  ].join('\n')
+}
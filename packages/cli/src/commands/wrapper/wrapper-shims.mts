@@ -0,0 +1,450 @@
+/* oxlint-disable-next-line socket/no-file-scope-oxlint-disable -- legitimate file-scope: domain-grouped layout or test fixture; per-call would produce many redundant disables. */
+/* oxlint-disable socket/no-npx-dlx -- product feature name / command wrapping npx; the literal is intentional. */
+
+/**
+ * PATH shims of `socket wrapper`. A shim is a small script named like the
+ * package manager it wraps (npm, npx, pnpm, yarn) that runs `socket <name>`
+ * with the same arguments.
+ *
+ * Shims live in their own directory, per user or per project, so upgrading or
+ * reinstalling a package manager never touches them. They do not record where
+ * the real package manager is: each shim drops its own directory from PATH
+ * before it runs Socket CLI, which then finds whatever package manager is
+ * installed at that time.
+ *
+ * For the user scope the directory is put on PATH by a marked line in
+ * ~/.bashrc and ~/.zshrc. The `alias npm="socket npm"` lines of earlier
+ * versions are replaced by the shims on enable and removed on disable.
+ *
+ * On Windows every shim also gets a .cmd file, which cmd.exe and PowerShell
+ * both run, and the user scope goes on the Windows user PATH as well (see
+ * windows-user-path.mts). The sh shim and the profile line are for Git Bash
+ * there.
+ *
+ * The shims are rendered by wrapper-shim-sh.mts and wrapper-shim-cmd.mts, the
+ * profiles edited by shell-profiles.mts.
+ */
+
+import { existsSync, promises as fs, readFileSync, realpathSync } from 'node:fs'
+import path from 'node:path'
+
+import { whichRealSync } from '@socketsecurity/lib-stable/bin/which'
+import { safeDelete, safeMkdir } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  LEGACY_ALIASES,
+  addShimPathLine,
+  getShellRcPaths,
+  removeShimPathLine,
+  updateRcContent,
+  updateRcFiles,
+} from './shell-profiles.mts'
+import {
+  addWindowsPathEntry,
+  hasWindowsPathEntry,
+  removeWindowsPathEntry,
+  updateWindowsUserPath,
+} from './windows-user-path.mts'
+import { renderCmdShim } from './wrapper-shim-cmd.mts'
+import {
+  SHIM_MARKER,
+  getShimPathLine,
+  renderShShim,
+} from './wrapper-shim-sh.mts'
+import { getSocketShimsPath } from '../../constants/paths.mts'
+import { getErrorCause } from '../../util/error/errors.mts'
+
+import type { CResult } from '../../types.mts'
+import type { UnifiedDiffFile } from '../../util/output/unified-diff.mts'
+
+export const WRAPPABLE_BINS = ['npm', 'npx', 'pnpm', 'yarn'] as const
+
+export type WrappableBin = (typeof WRAPPABLE_BINS)[number]
+
+// What `socket wrapper enable` wraps when no package manager is named.
+export const DEFAULT_WRAPPED_BINS: WrappableBin[] = ['npm', 'npx']
+
+export type WrapperScope = 'project' | 'user'
+
+export type WrapperChange = {
+  action: 'disabled' | 'enabled'
+  bins: WrappableBin[]
+  // Shell profiles that got or lost the PATH line or the legacy aliases.
+  modifiedFiles: string[]
+  scope: WrapperScope
+  shimDir: string
+  shimDirOnPath: boolean
+  // Whether the Windows user PATH got or lost the shim directory.
+  userPathUpdated: boolean
+}
+
+export type WrappedBinStatus = {
+  // Whether running the name in a shell reaches this shim.
+  active: boolean
+  name: WrappableBin
+  shimmed: boolean
+  // The package manager the shim ends up running, if installed.
+  target: string | undefined
+}
+
+export type WrapperScopeStatus = {
+  bins: WrappedBinStatus[]
+  onPath: boolean
+  scope: WrapperScope
+  shimDir: string
+}
+
+export type WrapperStatus = {
+  // Shell profiles with the `alias npm="socket npm"` lines of earlier versions.
+  aliasFiles: string[]
+  // Shell profiles that put the user shim directory on PATH.
+  pathFiles: string[]
+  scopes: WrapperScopeStatus[]
+}
+
+export function isWrappableBin(name: string): name is WrappableBin {
+  return (WRAPPABLE_BINS as readonly string[]).includes(name)
+}
+
+export function getShimDir(scope: WrapperScope, cwd: string): string {
+  return scope === 'project'
+    ? path.join(cwd, '.socket', 'shims')
+    : getSocketShimsPath()
+}
+
+function getShimFileNames(name: string): string[] {
+  return process.platform === 'win32' ? [name, `${name}.cmd`] : [name]
+}
+
+/**
+ * The shim of `name`, keyed by file name. Windows also gets a .cmd file for
+ * cmd.exe and PowerShell.
+ */
+export function renderShim(
+  name: WrappableBin,
+  shimDir: string,
+): Record<string, string> {
+  const shims: Record<string, string> = {
+    [name]: renderShShim(name, shimDir),
+  }
+  if (process.platform === 'win32') {
+    shims[`${name}.cmd`] = renderCmdShim(name, shimDir)
+  }
+  return shims
+}
+
+/**
+ * Whether `file` is a shim written by `socket wrapper`. Files of the same name
+ * that something else put there are left alone.
+ */
+export function isSocketWrapperShim(file: string): boolean {
+  try {
+    return readFileSync(file, 'utf8').includes(SHIM_MARKER)
+  } catch {
+    return false
+  }
+}
+
+export function isDirOnPath(dir: string): boolean {
+  if (process.platform === 'win32') {
+    return hasWindowsPathEntry(process.env['PATH'] ?? '', path.resolve(dir))
+  }
+  const resolved = path.resolve(dir)
+  return (process.env['PATH'] ?? '')
+    .split(path.delimiter)
+    .some(entry => !!entry && path.resolve(entry) === resolved)
+}
+
+function safeRealpath(file: string): string {
+  try {
+    return realpathSync(file)
+  } catch {
+    return file
+  }
+}
+
+// Windows paths compare without regard to case.
+function normalizeRealpath(file: string): string {
+  const realpath = safeRealpath(file)
+  return process.platform === 'win32' ? realpath.toLowerCase() : realpath
+}
+
+function whichAll(name: string): string[] {
+  const found = whichRealSync(name, { all: true, nothrow: true })
+  if (!found) {
+    return []
+  }
+  return Array.isArray(found) ? found : [found]
+}
+
+function readFileOrUndefined(file: string): string | undefined {
+  try {
+    return readFileSync(file, 'utf8')
+  } catch {
+    return undefined
+  }
+}
+
+function getForeignShimResult(file: string): CResult<never> {
+  return {
+    ok: false,
+    message: 'Shim directory has a foreign file',
+    cause: `${file} was not written by \`socket wrapper\`; move it away and retry`,
+  }
+}
+
+/**
+ * The files enabling or disabling the shims of `bins` in `scope` would write,
+ * as they are now and after, without writing anything. Fails where the change
+ * would. The Windows user PATH is left out.
+ */
+export function getWrapperFileChanges(
+  action: 'disable' | 'enable',
+  scope: WrapperScope,
+  bins: WrappableBin[],
+  cwd: string,
+): CResult<UnifiedDiffFile[]> {
+  const shimDir = getShimDir(scope, cwd)
+  const files: UnifiedDiffFile[] = []
+  for (const name of bins) {
+    if (action === 'enable') {
+      const shims = renderShim(name, shimDir)
+      for (const fileName of Object.keys(shims)) {
+        const file = path.join(shimDir, fileName)
+        if (existsSync(file) && !isSocketWrapperShim(file)) {
+          return getForeignShimResult(file)
+        }
+        files.push({
+          after: shims[fileName]!,
+          before: readFileOrUndefined(file),
+          path: file,
+        })
+      }
+    } else {
+      for (const fileName of getShimFileNames(name)) {
+        const file = path.join(shimDir, fileName)
+        if (isSocketWrapperShim(file)) {
+          files.push({ before: readFileOrUndefined(file), path: file })
+        }
+      }
+    }
+  }
+  // Disabling leaves the profiles alone while other shims remain.
+  const updatesRcFiles =
+    scope === 'user' &&
+    (action === 'enable' ||
+      !WRAPPABLE_BINS.some(
+        name =>
+          !bins.includes(name) && isSocketWrapperShim(path.join(shimDir, name)),
+      ))
+  if (updatesRcFiles) {
+    const pathLine = getShimPathLine(shimDir)
+    for (const file of getShellRcPaths()) {
+      const before = readFileOrUndefined(file)
+      if (before === undefined) {
+        continue
+      }
+      const after = updateRcContent(before, lines =>
+        action === 'enable'
+          ? addShimPathLine(lines, pathLine)
+          : removeShimPathLine(lines, pathLine),
+      )
+      if (after !== before) {
+        files.push({ after, before, path: file })
+      }
+    }
+  }
+  return { ok: true, data: files }
+}
+
+/**
+ * Writes the shims of `bins` in `scope`. For the user scope, also puts the
+ * shim directory on PATH in the shell profiles and drops the legacy aliases
+ * the shims replace.
+ */
+export async function enableWrapperShims(
+  scope: WrapperScope,
+  bins: WrappableBin[],
+  cwd: string,
+): Promise<CResult<WrapperChange>> {
+  const shimDir = getShimDir(scope, cwd)
+  try {
+    await safeMkdir(shimDir, { recursive: true })
+    for (const name of bins) {
+      const shims = renderShim(name, shimDir)
+      for (const fileName of Object.keys(shims)) {
+        const file = path.join(shimDir, fileName)
+        if (existsSync(file) && !isSocketWrapperShim(file)) {
+          return getForeignShimResult(file)
+        }
+        // oxlint-disable-next-line no-await-in-loop -- a handful of small files.
+        await fs.writeFile(file, shims[fileName]!, { mode: 0o755 })
+        // oxlint-disable-next-line no-await-in-loop -- see above.
+        await fs.chmod(file, 0o755)
+      }
+    }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Could not write the wrapper shims',
+      cause: `${shimDir}: ${getErrorCause(e)}`,
+    }
+  }
+
+  let modifiedFiles: string[] = []
+  let userPathUpdated = false
+  if (scope === 'user') {
+    const pathLine = getShimPathLine(shimDir)
+    const rcCResult = await updateRcFiles(getShellRcPaths(), lines =>
+      addShimPathLine(lines, pathLine),
+    )
+    if (!rcCResult.ok) {
+      return rcCResult
+    }
+    modifiedFiles = rcCResult.data
+    if (process.platform === 'win32') {
+      const userPathCResult = await updateWindowsUserPath(value =>
+        hasWindowsPathEntry(value, shimDir)
+          ? value
+          : addWindowsPathEntry(value, shimDir),
+      )
+      if (!userPathCResult.ok) {
+        return userPathCResult
+      }
+      userPathUpdated = userPathCResult.data
+    }
+  }
+
+  return {
+    ok: true,
+    data: {
+      action: 'enabled',
+      bins,
+      modifiedFiles,
+      scope,
+      shimDir,
+      shimDirOnPath: isDirOnPath(shimDir),
+      userPathUpdated,
+    },
+  }
+}
+
+/**
+ * Removes the shims of `bins` in `scope`. Once no shim is left in the user
+ * scope, the PATH line and the legacy aliases leave the shell profiles too.
+ */
+export async function disableWrapperShims(
+  scope: WrapperScope,
+  bins: WrappableBin[],
+  cwd: string,
+): Promise<CResult<WrapperChange>> {
+  const shimDir = getShimDir(scope, cwd)
+  const removed: WrappableBin[] = []
+  try {
+    for (const name of bins) {
+      const files = getShimFileNames(name)
+        .map(fileName => path.join(shimDir, fileName))
+        .filter(file => isSocketWrapperShim(file))
+      if (files.length) {
+        // oxlint-disable-next-line no-await-in-loop -- a handful of small files.
+        await safeDelete(files, { force: true })
+        removed.push(name)
+      }
+    }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Could not remove the wrapper shims',
+      cause: `${shimDir}: ${getErrorCause(e)}`,
+    }
+  }
+
+  let modifiedFiles: string[] = []
+  let userPathUpdated = false
+  const remaining = WRAPPABLE_BINS.filter(name =>
+    isSocketWrapperShim(path.join(shimDir, name)),
+  )
+  if (scope === 'user' && !remaining.length) {
+    const pathLine = getShimPathLine(shimDir)
+    const rcCResult = await updateRcFiles(getShellRcPaths(), lines =>
+      removeShimPathLine(lines, pathLine),
+    )
+    if (!rcCResult.ok) {
+      return rcCResult
+    }
+    modifiedFiles = rcCResult.data
+    if (process.platform === 'win32') {
+      const userPathCResult = await updateWindowsUserPath(value =>
+        removeWindowsPathEntry(value, shimDir),
+      )
+      if (!userPathCResult.ok) {
+        return userPathCResult
+      }
+      userPathUpdated = userPathCResult.data
+    }
+  }
+
+  return {
+    ok: true,
+    data: {
+      action: 'disabled',
+      bins: removed,
+      modifiedFiles,
+      scope,
+      shimDir,
+      shimDirOnPath: isDirOnPath(shimDir),
+      userPathUpdated,
+    },
+  }
+}
+
+/**
+ * What is wrapped in each scope, whether the shell reaches the shims and the
+ * package manager each shim ends up running.
+ */
+export function getWrapperStatus(cwd: string): WrapperStatus {
+  const rcFiles = getShellRcPaths()
+  const readRc = (file: string) => {
+    try {
+      return readFileSync(file, 'utf8').split(/\r?\n/)
+    } catch {
+      return []
+    }
+  }
+  const userShimDir = getShimDir('user', cwd)
+  const pathLine = getShimPathLine(userShimDir)
+  const scopes = (['project', 'user'] as const).map(scope => {
+    const shimDir = getShimDir(scope, cwd)
+    const bins = WRAPPABLE_BINS.map(name => {
+      const shimFile = path.join(shimDir, name)
+      const shimmed = isSocketWrapperShim(shimFile)
+      const found = whichAll(name)
+      // On Windows the shell finds npm.cmd rather than npm.
+      const shimRealpaths = getShimFileNames(name).map(fileName =>
+        normalizeRealpath(path.join(shimDir, fileName)),
+      )
+      const target = found.find(
+        file =>
+          !isSocketWrapperShim(file) &&
+          !shimRealpaths.includes(normalizeRealpath(file)),
+      )
+      return {
+        active:
+          shimmed &&
+          found[0] !== undefined &&
+          shimRealpaths.includes(normalizeRealpath(found[0])),
+        name,
+        shimmed,
+        target,
+      }
+    })
+    return { bins, onPath: isDirOnPath(shimDir), scope, shimDir }
+  })
+  return {
+    aliasFiles: rcFiles.filter(file =>
+      readRc(file).some(l => LEGACY_ALIASES.includes(l)),
+    ),
+    pathFiles: rcFiles.filter(file => readRc(file).includes(pathLine)),
+    scopes,
+  }
+}
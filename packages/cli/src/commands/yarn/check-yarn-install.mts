@@ -0,0 +1,94 @@
+import { YARN } from '@socketsecurity/lib-stable/constants/agents'
+
+import { checkNodeInstall } from '../../util/install-check/node-install.mts'
+import { getNpmRegistrySpecs } from '../npm/npm-install-specs.mts'
+
+import type { CResult } from '../../types.mts'
+import type { CheckInstallPackagesOptions } from '../../util/install-check/check.mts'
+import type { NodeInstallRequest } from '../../util/install-check/node-install.mts'
+
+// yarn options (Classic and Berry) that consume the next argument.
+const YARN_VALUE_OPTIONS = new Set([
+  '--cache-folder',
+  '--cwd',
+  '--global-folder',
+  '--link-folder',
+  '--mode',
+  '--modules-folder',
+  '--mutex',
+  '--network-concurrency',
+  '--network-timeout',
+  '--registry',
+])
+
+// Packages from another registry are not looked up.
+const YARN_NO_CHECK_ARGS = new Set(['--help', '--registry', '-h'])
+
+/**
+ * Work out what a `yarn add|install` invocation installs, including Yarn
+ * Classic `yarn global add` and Berry `yarn workspace <name> add`. A bare
+ * `yarn` is an install. Returns undefined for other commands and installs
+ * from another registry.
+ */
+export function getYarnInstallRequest(
+  args: readonly string[],
+): NodeInstallRequest | undefined {
+  const positionals: string[] = []
+  let dir: string | undefined
+  for (let i = 0, { length } = args; i < length; i += 1) {
+    const arg = args[i]!
+    if (arg === '--') {
+      break
+    }
+    if (arg.startsWith('-')) {
+      const eqIndex = arg.indexOf('=')
+      const name = eqIndex === -1 ? arg : arg.slice(0, eqIndex)
+      if (YARN_NO_CHECK_ARGS.has(name)) {
+        return undefined
+      }
+      if (YARN_VALUE_OPTIONS.has(name)) {
+        let value: string | undefined
+        if (eqIndex === -1) {
+          i += 1
+          value = args[i]
+        } else {
+          value = arg.slice(eqIndex + 1)
+        }
+        if (name === '--cwd') {
+          dir = value
+        }
+      }
+      continue
+    }
+    positionals.push(arg)
+  }
+  let commandIndex = 0
+  if (positionals[0] === 'global') {
+    commandIndex = 1
+  } else if (positionals[0] === 'workspace') {
+    // The workspace is named by the next positional.
+    commandIndex = 2
+  }
+  const command = positionals[commandIndex]
+  if (command === undefined || command === 'install') {
+    return commandIndex || positionals.length > 1
+      ? undefined
+      : { dir, fromLockfile: true, specs: [] }
+  }
+  if (command !== 'add') {
+    return undefined
+  }
+  const specs = getNpmRegistrySpecs(positionals.slice(commandIndex + 1))
+  return specs.length ? { dir, fromLockfile: false, specs } : undefined
+}
+
+/**
+ * Preflight for `socket yarn add|install`, see
+ * util/install-check/node-install.mts.
+ */
+export async function checkYarnInstall(
+  args: readonly string[],
+  options?: Pick<CheckInstallPackagesOptions, 'interactive'> | undefined,
+): Promise<CResult<unknown>> {
+  return await checkNodeInstall(YARN, getYarnInstallRequest(args), options)
+}
@@ -1,11 +1,16 @@
 /**
  * Socket yarn command — forwards yarn operations to Socket Firewall (sfw).
  *
- * Defined via `defineHandoffCommand`. See util/cli/define-handoff.mts.
+ * Defined via `defineHandoffCommand`. The packages of `yarn add|install` are
+ * scored before installing through its `preflight` hook, see
+ * check-yarn-install.mts.
+ *
+ * See util/cli/define-handoff.mts.
  */
 
 import { YARN } from '@socketsecurity/lib-stable/constants/agents'
 
+import { checkYarnInstall } from './check-yarn-install.mts'
 import { defineHandoffCommand } from '../../util/cli/define-handoff.mts'
 
 export const CMD_NAME = YARN
@@ -16,6 +21,13 @@ export const cmdYarn = defineHandoffCommand({
   spawnMode: 'dlx',
   hidden: true,
   examples: ['', 'install', 'add package-name'],
+  helpNotes: [
+    'Packages named by `yarn add`, and the direct dependencies locked for a bare',
+    '`yarn install`, are checked with Socket first. Alerts the org policy blocks',
+    'stop the install and alerts it warns about ask before installing.',
+  ],
+  preflight: (args, _binaryName, context) =>
+    checkYarnInstall(args, context),
   showApiRequirements: true,
   wrapperHint: true,
 })
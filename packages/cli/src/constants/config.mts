@@ -2,9 +2,22 @@
  * Configuration key constants for Socket CLI settings.
  */
 
+export const CONFIG_KEY_API_AUTH_SCHEME = 'apiAuthScheme'
 export const CONFIG_KEY_API_BASE_URL = 'apiBaseUrl'
+export const CONFIG_KEY_API_MAX_RETRIES = 'apiMaxRetries'
 export const CONFIG_KEY_API_PROXY = 'apiProxy'
+export const CONFIG_KEY_API_RETRY_BACKOFF = 'apiRetryBackoff'
+export const CONFIG_KEY_API_TIMEOUT = 'apiTimeout'
 export const CONFIG_KEY_API_TOKEN = 'apiToken'
+export const CONFIG_KEY_CA_CERT = 'caCert'
 export const CONFIG_KEY_DEFAULT_ORG = 'defaultOrg'
+export const CONFIG_KEY_DISABLE_SELF_UPDATE = 'disableSelfUpdate'
 export const CONFIG_KEY_ENFORCED_ORGS = 'enforcedOrgs'
 export const CONFIG_KEY_ORG = 'org'
+export const CONFIG_KEY_TELEMETRY = 'telemetry'
+export const CONFIG_KEY_ACTIVE_PROFILE = 'activeProfile'
+export const CONFIG_KEY_PROFILES = 'profiles'
+
+// Name of the credentials stored at the top level of the config, outside of
+// any named profile.
+export const DEFAULT_PROFILE_NAME = 'default'
@@ -57,6 +57,7 @@ import { SOCKET_CLI_MODE } from '../env/socket-cli-mode.mts'
 import { SOCKET_CLI_MODELS_PATH } from '../env/socket-cli-models-path.mts'
 import { SOCKET_CLI_NO_API_TOKEN } from '../env/socket-cli-no-api-token.mts'
 import { SOCKET_CLI_NPM_PATH } from '../env/socket-cli-npm-path.mts'
+import { SOCKET_CLI_OFFLINE } from '../env/socket-cli-offline.mts'
 import { SOCKET_CLI_OPTIMIZE } from '../env/socket-cli-optimize.mts'
 import { SOCKET_CLI_ORG_SLUG } from '../env/socket-cli-org-slug.mts'
 import { SOCKET_CLI_PYCLI_LOCAL_PATH } from '../env/socket-cli-pycli-local-path.mts'
@@ -65,6 +66,7 @@ import { SOCKET_CLI_SEA_NODE_VERSION } from '../env/socket-cli-sea-node-version.
 import { SOCKET_CLI_SFW_LOCAL_PATH } from '../env/socket-cli-sfw-local-path.mts'
 import { SOCKET_CLI_SKIP_UPDATE_CHECK } from '../env/socket-cli-skip-update-check.mts'
 import { SOCKET_CLI_SOCKET_PATCH_LOCAL_PATH } from '../env/socket-cli-socket-patch-local-path.mts'
+import { SOCKET_CLI_VERIFY_PROVENANCE } from '../env/socket-cli-verify-provenance.mts'
 import { SOCKET_CLI_VIEW_ALL_RISKS } from '../env/socket-cli-view-all-risks.mts'
 import { getSocketPatchVersion } from '../env/socket-patch-version.mts'
 import { getSynpVersion } from '../env/synp-version.mts'
@@ -119,6 +121,7 @@ export {
   SOCKET_CLI_MODELS_PATH,
   SOCKET_CLI_NO_API_TOKEN,
   SOCKET_CLI_NPM_PATH,
+  SOCKET_CLI_OFFLINE,
   SOCKET_CLI_OPTIMIZE,
   SOCKET_CLI_ORG_SLUG,
   SOCKET_CLI_PYCLI_LOCAL_PATH,
@@ -127,6 +130,7 @@ export {
   SOCKET_CLI_SFW_LOCAL_PATH,
   SOCKET_CLI_SKIP_UPDATE_CHECK,
   SOCKET_CLI_SOCKET_PATCH_LOCAL_PATH,
+  SOCKET_CLI_VERIFY_PROVENANCE,
   SOCKET_CLI_VIEW_ALL_RISKS,
   TEMP,
   TERM,
@@ -217,6 +221,7 @@ const envSnapshot = {
   SOCKET_CLI_MODELS_PATH,
   SOCKET_CLI_NO_API_TOKEN,
   SOCKET_CLI_NPM_PATH,
+  SOCKET_CLI_OFFLINE,
   SOCKET_CLI_OPTIMIZE,
   SOCKET_CLI_ORG_SLUG,
   SOCKET_CLI_PYCLI_LOCAL_PATH,
@@ -225,6 +230,7 @@ const envSnapshot = {
   SOCKET_CLI_SFW_LOCAL_PATH,
   SOCKET_CLI_SKIP_UPDATE_CHECK,
   SOCKET_CLI_SOCKET_PATCH_LOCAL_PATH,
+  SOCKET_CLI_VERIFY_PROVENANCE,
   SOCKET_CLI_VIEW_ALL_RISKS,
   TEMP,
   TERM,
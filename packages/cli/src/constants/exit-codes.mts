@@ -0,0 +1,18 @@
+/**
+ * Process exit codes of the Socket CLI.
+ *
+ * CI pipelines rely on these to tell "issues were found" from "the tool
+ * broke", so existing codes must not change meaning.
+ */
+
+export const EXIT_CODE_OK = 0
+// Anything not covered by a more specific code.
+export const EXIT_CODE_ERROR = 1
+// Invalid flags, arguments or input.
+export const EXIT_CODE_USAGE = 2
+// The scan or check found issues that meet the failure threshold.
+export const EXIT_CODE_POLICY_VIOLATION = 3
+// The Socket API failed or could not be reached.
+export const EXIT_CODE_API_ERROR = 4
+// The Socket API token is missing, invalid or lacks permission.
+export const EXIT_CODE_AUTH_ERROR = 5
@@ -67,6 +67,10 @@ export const UPDATE_STORE_DIR = '.socket/_dlx'
 
 // Derived Paths (CLI-specific)
 export const DOT_SOCKET_DOT_FACTS_JSON = `${DOT_SOCKET_DIR}.facts.json`
+export const DOT_SOCKET_DOT_INSTALL_SCRIPTS_JSON = `${DOT_SOCKET_DIR}.install-scripts.json`
+export const DOT_SOCKET_DOT_LOCKFILES_CDX_JSON = `${DOT_SOCKET_DIR}.lockfiles.cdx.json`
+export const DOT_SOCKET_DOT_CONTAINER_CDX_JSON = `${DOT_SOCKET_DIR}.container.cdx.json`
+export const DOT_SOCKET_DOT_SBOM_CDX_JSON = `${DOT_SOCKET_DIR}.sbom.cdx.json`
 
 // Update Store
 export const UPDATE_STORE_FILE_NAME = '.dlx-manifest.json'
@@ -119,6 +123,10 @@ export function getBlessedPath(): string {
   return path.join(externalPath, 'blessed')
 }
 
+export function getCompletionHistoryPath(): string {
+  return path.join(getSocketCachePath(), 'completion-history.json')
+}
+
 export function getDistBinPath(): string {
   return path.join(distPath, 'bin')
 }
@@ -177,10 +185,18 @@ export function getNmYarnPath(): string | undefined {
   }
 }
 
+export function getOfflineBundlePath(): string {
+  return path.join(getSocketCachePath(), 'offline')
+}
+
 export function getPackageJsonPath(): string {
   return path.join(rootPath, 'package.json')
 }
 
+export function getScoreCachePath(): string {
+  return path.join(getSocketCachePath(), 'scores')
+}
+
 export function getSocketAppDataPath(): string | undefined {
   // Get the OS app data directory:
   // - Win: %LOCALAPPDATA% or fallback to %USERPROFILE%/AppData/Local
@@ -236,6 +252,28 @@ export function getSocketCachePath(): string {
   }
 }
 
+// Where `socket telemetry enable` spools usage events until they are sent.
+export function getSocketTelemetryPath(): string {
+  return path.join(getSocketCachePath(), 'telemetry')
+}
+
+/**
+ * The system-wide CLI config an administrator can provide, as plain JSON:
+ *
+ * - Win: %PROGRAMDATA%\socket\config.json
+ * - Mac and Linux: /etc/socket/config.json
+ */
+export function getSocketSystemConfigPath(): string {
+  if (process.platform === 'win32') {
+    return path.join(
+      process.env['PROGRAMDATA'] || 'C:\\ProgramData',
+      'socket',
+      'config.json',
+    )
+  }
+  return '/etc/socket/config.json'
+}
+
 export function getSocketRegistryPath(): string {
   const appDataPath = getSocketAppDataPath()
   /* c8 ignore start - HOME/USERPROFILE/LOCALAPPDATA/XDG_DATA_HOME all unset is essentially impossible in any real environment */
@@ -248,6 +286,35 @@ export function getSocketRegistryPath(): string {
   return path.join(appDataPath, 'registry')
 }
 
+// The SQLite database of scan summaries and alerts that `socket history`
+// queries. It is user data rather than a cache, so `socket cache clear` keeps
+// it.
+export function getSocketHistoryPath(): string {
+  const appDataPath = getSocketAppDataPath()
+  /* c8 ignore start - see getSocketRegistryPath */
+  if (!appDataPath) {
+    throw new Error(
+      'could not determine the Socket app-data directory for the scan history; export HOME (or LOCALAPPDATA on Windows) and retry',
+    )
+  }
+  /* c8 ignore stop */
+  return path.join(path.dirname(appDataPath), 'history.db')
+}
+
+// Where `socket wrapper enable` puts the per-user package manager shims. It
+// is outside every package manager install, so upgrades do not remove them.
+export function getSocketShimsPath(): string {
+  const appDataPath = getSocketAppDataPath()
+  /* c8 ignore start - see getSocketRegistryPath */
+  if (!appDataPath) {
+    throw new Error(
+      'could not determine the Socket app-data directory for the wrapper shims; export HOME (or LOCALAPPDATA on Windows) and retry',
+    )
+  }
+  /* c8 ignore stop */
+  return path.join(path.dirname(appDataPath), 'shims')
+}
+
 export function getZshRcPath(): string {
   return path.join(os.homedir(), '.zshrc')
 }
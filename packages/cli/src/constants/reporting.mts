@@ -8,3 +8,31 @@ export const REPORT_LEVEL_ERROR = 'error'
 export const REPORT_LEVEL_IGNORE = 'ignore'
 export const REPORT_LEVEL_MONITOR = 'monitor'
 export const REPORT_LEVEL_WARN = 'warn'
+
+// Alternative report renderings selected with `--format`.
+export const REPORT_FORMAT_CSV = 'csv'
+export const REPORT_FORMAT_GITLAB_CODE_QUALITY = 'gitlab-code-quality'
+export const REPORT_FORMAT_GITLAB_SAST = 'gitlab-sast'
+export const REPORT_FORMAT_HTML = 'html'
+export const REPORT_FORMAT_JUNIT = 'junit'
+export const REPORT_FORMAT_SARIF = 'sarif'
+export const REPORT_FORMATS = [
+  REPORT_FORMAT_SARIF,
+  REPORT_FORMAT_GITLAB_CODE_QUALITY,
+  REPORT_FORMAT_GITLAB_SAST,
+  REPORT_FORMAT_JUNIT,
+  REPORT_FORMAT_HTML,
+  REPORT_FORMAT_CSV,
+] as const
+
+// Alert thresholds selected with `--fail-on`.
+export const FAIL_ON_ANY = 'any'
+export const FAIL_ON_CRITICAL = 'critical'
+export const FAIL_ON_HIGH = 'high'
+export const FAIL_ON_NONE = 'none'
+export const FAIL_ON_LEVELS = [
+  FAIL_ON_CRITICAL,
+  FAIL_ON_HIGH,
+  FAIL_ON_ANY,
+  FAIL_ON_NONE,
+] as const
@@ -16,7 +16,11 @@ export const SOCKET_SETTINGS_API_TOKENS_URL =
 export const SOCKET_STATUS_URL = 'https://status.socket.dev'
 
 // Socket Configuration Files
+export const SOCKET_BASELINE_JSON = 'socket.baseline.json'
 export const SOCKET_JSON = 'socket.json'
+export const SOCKET_ORG_POLICY_JSON = 'socket.org-policy.json'
+export const SOCKET_POLICY_YAML = 'socket.policy.yaml'
+export const SOCKET_POLICY_YML = 'socket.policy.yml'
 export const SOCKET_YAML = 'socket.yaml'
 export const SOCKET_YML = 'socket.yml'
 
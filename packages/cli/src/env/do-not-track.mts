@@ -0,0 +1,17 @@
+/**
+ * DO_NOT_TRACK environment variable.
+ *
+ * The cross-tool opt-out from telemetry, see https://consoledonottrack.com.
+ * Vetoes `socket telemetry enable` and the telemetry config key.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+import { envAsBoolean } from '@socketsecurity/lib-stable/env/boolean'
+
+export function getDoNotTrack(): boolean {
+  return envAsBoolean(process.env['DO_NOT_TRACK'])
+}
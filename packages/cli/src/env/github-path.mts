@@ -0,0 +1,13 @@
+/**
+ * GITHUB_PATH environment variable. The file GitHub Actions reads PATH
+ * entries for later steps of a job from.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getGithubPath(): string | undefined {
+  return process.env['GITHUB_PATH'] || undefined
+}
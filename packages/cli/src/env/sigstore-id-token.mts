@@ -0,0 +1,7 @@
+/**
+ * SIGSTORE_ID_TOKEN environment variable. OIDC identity token, issued for the
+ * `sigstore` audience, that `socket scan attest` signs with outside GitHub
+ * Actions, e.g. a GitLab CI `id_tokens` entry.
+ */
+
+export const SIGSTORE_ID_TOKEN = process.env['SIGSTORE_ID_TOKEN'] || ''
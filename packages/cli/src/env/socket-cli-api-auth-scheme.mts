@@ -0,0 +1,15 @@
+/**
+ * SOCKET_CLI_API_AUTH_SCHEME environment variable.
+ *
+ * How Socket API requests send the API token, like the `apiAuthScheme` config
+ * value: basic or bearer.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getSocketCliApiAuthScheme(): string | undefined {
+  return process.env['SOCKET_CLI_API_AUTH_SCHEME'] || undefined
+}
@@ -0,0 +1,17 @@
+/**
+ * SOCKET_CLI_API_CONCURRENCY environment variable.
+ *
+ * Most Socket API requests a batched lookup may have in flight at once.
+ * Undefined when unset or not a positive integer; the caller decides the
+ * default.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getSocketCliApiConcurrency(): number | undefined {
+  const value = Number(process.env['SOCKET_CLI_API_CONCURRENCY'])
+  return Number.isInteger(value) && value > 0 ? value : undefined
+}
@@ -0,0 +1,17 @@
+/**
+ * SOCKET_CLI_API_MAX_RETRIES environment variable.
+ *
+ * Retries of a failed Socket API request, like --max-retries. Undefined when
+ * unset or not a non-negative integer; the caller decides the default.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getSocketCliApiMaxRetries(): number | undefined {
+  const raw = process.env['SOCKET_CLI_API_MAX_RETRIES']
+  const value = Number(raw)
+  return raw && Number.isInteger(value) && value >= 0 ? value : undefined
+}
@@ -0,0 +1,18 @@
+/**
+ * SOCKET_CLI_API_RETRY_BACKOFF environment variable.
+ *
+ * Milliseconds to wait before the first retry of a failed Socket API request,
+ * like --retry-backoff. Undefined when unset or not a non-negative integer;
+ * the caller decides the default.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getSocketCliApiRetryBackoff(): number | undefined {
+  const raw = process.env['SOCKET_CLI_API_RETRY_BACKOFF']
+  const value = Number(raw)
+  return raw && Number.isInteger(value) && value >= 0 ? value : undefined
+}
@@ -0,0 +1,9 @@
+/**
+ * SOCKET_CLI_BUNDLE_SIGNING_KEY environment variable. The PEM private key that
+ * `socket bundle export` signs offline bundles with, so a scheduled job can
+ * keep it as a secret instead of a file. Escaped `\n` line breaks are accepted.
+ */
+
+export const SOCKET_CLI_BUNDLE_SIGNING_KEY = (
+  process.env['SOCKET_CLI_BUNDLE_SIGNING_KEY'] || ''
+).replaceAll('\\n', '\n')
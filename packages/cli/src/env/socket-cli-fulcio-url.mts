@@ -0,0 +1,6 @@
+/**
+ * SOCKET_CLI_FULCIO_URL environment variable. Fulcio certificate authority of
+ * `socket scan attest`, for a private Sigstore instance.
+ */
+
+export const SOCKET_CLI_FULCIO_URL = process.env['SOCKET_CLI_FULCIO_URL'] || ''
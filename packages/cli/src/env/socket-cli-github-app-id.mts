@@ -0,0 +1,7 @@
+/**
+ * SOCKET_CLI_GITHUB_APP_ID environment variable. Default GitHub App ID for
+ * `socket ghapp sync`.
+ */
+
+export const SOCKET_CLI_GITHUB_APP_ID =
+  process.env['SOCKET_CLI_GITHUB_APP_ID'] || ''
@@ -0,0 +1,8 @@
+/**
+ * SOCKET_CLI_GITHUB_APP_INSTALLATION_ID environment variable. Default GitHub
+ * App installation for `socket ghapp sync`, needed when the app is installed
+ * on more than one account.
+ */
+
+export const SOCKET_CLI_GITHUB_APP_INSTALLATION_ID =
+  process.env['SOCKET_CLI_GITHUB_APP_INSTALLATION_ID'] || ''
@@ -0,0 +1,9 @@
+/**
+ * SOCKET_CLI_GITHUB_APP_PRIVATE_KEY environment variable. The PEM private key
+ * of the GitHub App for `socket ghapp sync`, so a scheduled job can keep it as
+ * a secret instead of a file. Escaped `\n` line breaks are accepted.
+ */
+
+export const SOCKET_CLI_GITHUB_APP_PRIVATE_KEY = (
+  process.env['SOCKET_CLI_GITHUB_APP_PRIVATE_KEY'] || ''
+).replaceAll('\\n', '\n')
@@ -0,0 +1,8 @@
+/**
+ * SOCKET_CLI_JIRA_API_TOKEN environment variable. API token of the Jira
+ * account in SOCKET_CLI_JIRA_EMAIL, only read from the environment so it
+ * stays out of shell history and CI logs.
+ */
+
+export const SOCKET_CLI_JIRA_API_TOKEN =
+  process.env['SOCKET_CLI_JIRA_API_TOKEN'] || ''
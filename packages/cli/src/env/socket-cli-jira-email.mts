@@ -0,0 +1,7 @@
+/**
+ * SOCKET_CLI_JIRA_EMAIL environment variable. Email of the Jira account that
+ * `socket report issues` creates tickets as, paired with
+ * SOCKET_CLI_JIRA_API_TOKEN.
+ */
+
+export const SOCKET_CLI_JIRA_EMAIL = process.env['SOCKET_CLI_JIRA_EMAIL'] || ''
@@ -0,0 +1,6 @@
+/**
+ * SOCKET_CLI_JIRA_URL environment variable. Default Jira site for
+ * `socket report issues --tracker jira`, e.g. https://acme.atlassian.net.
+ */
+
+export const SOCKET_CLI_JIRA_URL = process.env['SOCKET_CLI_JIRA_URL'] || ''
@@ -0,0 +1,15 @@
+/**
+ * SOCKET_CLI_LOG_FILE environment variable.
+ *
+ * File the structured log records are appended to, like --log-file.
+ * Undefined when unset.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getSocketCliLogFile(): string | undefined {
+  return process.env['SOCKET_CLI_LOG_FILE'] || undefined
+}
@@ -0,0 +1,15 @@
+/**
+ * SOCKET_CLI_LOG_FORMAT environment variable.
+ *
+ * Format of the structured log records, json or text, like --log-format.
+ * Undefined when unset; util/log/structured.mts validates it.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getSocketCliLogFormat(): string | undefined {
+  return process.env['SOCKET_CLI_LOG_FORMAT'] || undefined
+}
@@ -0,0 +1,15 @@
+/**
+ * SOCKET_CLI_LOG_LEVEL environment variable.
+ *
+ * Lowest level of the records written to the structured log, like
+ * --log-level. Undefined when unset; util/log/structured.mts validates it.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getSocketCliLogLevel(): string | undefined {
+  return process.env['SOCKET_CLI_LOG_LEVEL'] || undefined
+}
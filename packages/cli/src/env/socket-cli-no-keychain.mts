@@ -0,0 +1,17 @@
+/**
+ * SOCKET_CLI_NO_KEYCHAIN environment variable.
+ *
+ * Keeps API tokens out of the OS credential manager, e.g. in headless CI
+ * where no keyring is unlocked. Tokens are then stored in the config file.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+import { envAsBoolean } from '@socketsecurity/lib-stable/env/boolean'
+
+export function getSocketCliNoKeychain(): boolean {
+  return envAsBoolean(process.env['SOCKET_CLI_NO_KEYCHAIN'])
+}
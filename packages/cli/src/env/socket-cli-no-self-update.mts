@@ -0,0 +1,17 @@
+/**
+ * SOCKET_CLI_NO_SELF_UPDATE environment variable.
+ *
+ * Turns off `socket self-update` and update notifications, for managed
+ * environments where the Socket CLI is rolled out by an administrator.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+import { envAsBoolean } from '@socketsecurity/lib-stable/env/boolean'
+
+export function getSocketCliNoSelfUpdate(): boolean {
+  return envAsBoolean(process.env['SOCKET_CLI_NO_SELF_UPDATE'])
+}
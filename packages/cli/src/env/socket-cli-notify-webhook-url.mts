@@ -0,0 +1,8 @@
+/**
+ * SOCKET_CLI_NOTIFY_WEBHOOK_URL environment variable. Default incoming webhook
+ * URL for `socket scan create --notify`, so the URL can be kept as a CI secret
+ * instead of a flag value.
+ */
+
+export const SOCKET_CLI_NOTIFY_WEBHOOK_URL =
+  process.env['SOCKET_CLI_NOTIFY_WEBHOOK_URL'] || ''
@@ -0,0 +1,10 @@
+/**
+ * SOCKET_CLI_OFFLINE environment variable snapshot. Serves package score
+ * lookups from the local score cache without calling the Socket API.
+ */
+
+import { env } from 'node:process'
+
+import { envAsBoolean } from '@socketsecurity/lib-stable/env/boolean'
+
+export const SOCKET_CLI_OFFLINE = envAsBoolean(env['SOCKET_CLI_OFFLINE'])
@@ -0,0 +1,14 @@
+/**
+ * SOCKET_CLI_PROFILE environment variable.
+ *
+ * Named auth profile to use instead of the `activeProfile` config value.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getSocketCliProfile(): string | undefined {
+  return process.env['SOCKET_CLI_PROFILE'] || undefined
+}
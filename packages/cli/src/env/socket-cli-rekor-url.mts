@@ -0,0 +1,6 @@
+/**
+ * SOCKET_CLI_REKOR_URL environment variable. Rekor transparency log of
+ * `socket scan attest`, for a private Sigstore instance.
+ */
+
+export const SOCKET_CLI_REKOR_URL = process.env['SOCKET_CLI_REKOR_URL'] || ''
@@ -0,0 +1,17 @@
+/**
+ * SOCKET_CLI_SKIP_HOOKS environment variable.
+ *
+ * Lets the git hooks written by `socket hooks install` pass without checking,
+ * e.g. `SOCKET_CLI_SKIP_HOOKS=1 git push` when the Socket API is down.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+import { envAsBoolean } from '@socketsecurity/lib-stable/env/boolean'
+
+export function getSocketCliSkipHooks(): boolean {
+  return envAsBoolean(process.env['SOCKET_CLI_SKIP_HOOKS'])
+}
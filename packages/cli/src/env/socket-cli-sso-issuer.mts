@@ -0,0 +1,16 @@
+/**
+ * SOCKET_CLI_SSO_ISSUER environment variable.
+ *
+ * OAuth issuer `socket login --sso` discovers its device authorization and
+ * token endpoints from. Empty string when unset; the origin of the Socket API
+ * base URL is used then.
+ *
+ * Read lazily so tests that mutate process.env after module load see the latest
+ * value.
+ */
+
+import process from 'node:process'
+
+export function getSocketCliSsoIssuer(): string {
+  return process.env['SOCKET_CLI_SSO_ISSUER'] ?? ''
+}
@@ -0,0 +1,12 @@
+/**
+ * SOCKET_CLI_VERIFY_PROVENANCE environment variable snapshot. Runs the
+ * `socket verify` checks on the packages of a Socket wrapped npm install.
+ */
+
+import { env } from 'node:process'
+
+import { envAsBoolean } from '@socketsecurity/lib-stable/env/boolean'
+
+export const SOCKET_CLI_VERIFY_PROVENANCE = envAsBoolean(
+  env['SOCKET_CLI_VERIFY_PROVENANCE'],
+)
@@ -217,6 +217,13 @@ export const commonFlags = defineFlags({
     // Hidden to allow custom documenting of the negated `--no-animate-header` variant.
     hidden: true,
   },
+  apiBaseUrl: {
+    type: 'string',
+    description:
+      'Base URL of the Socket API, e.g. of a single-tenant or regional deployment (config: apiBaseUrl)',
+    // Only show in root command.
+    hidden: true,
+  },
   banner: {
     type: 'boolean',
     default: true,
@@ -224,6 +231,14 @@ export const commonFlags = defineFlags({
     // Hidden to allow custom documenting of the negated `--no-banner` variant.
     hidden: true,
   },
+  cacert: {
+    type: 'string',
+    default: '',
+    description:
+      'Trust the CA certificates in this PEM file, e.g. of a TLS-inspecting corporate proxy',
+    // Only show in root command.
+    hidden: true,
+  },
   compactHeader: {
     type: 'boolean',
     default: false,
@@ -268,6 +283,26 @@ export const commonFlags = defineFlags({
     // Only show in root command.
     hidden: true,
   },
+  logFile: {
+    type: 'string',
+    description:
+      'Append the structured log to this file instead of writing it to stderr',
+    // Only show in root command.
+    hidden: true,
+  },
+  logFormat: {
+    type: 'string',
+    description: 'Format of the structured log: text (default) or json',
+    // Only show in root command.
+    hidden: true,
+  },
+  logLevel: {
+    type: 'string',
+    description:
+      'Write a structured log with trace and API request IDs, from this level: error, warn, info, debug or trace',
+    // Only show in root command.
+    hidden: true,
+  },
   maxOldSpaceSize: {
     type: 'number',
     get default() {
@@ -277,6 +312,13 @@ export const commonFlags = defineFlags({
     // Only show in root command in debug mode.
     hidden: true,
   },
+  maxRetries: {
+    type: 'number',
+    description:
+      'Retry failed Socket API requests this many times (config: apiMaxRetries)',
+    // Only show in root command.
+    hidden: true,
+  },
   maxSemiSpaceSize: {
     type: 'number',
     get default() {
@@ -292,6 +334,13 @@ export const commonFlags = defineFlags({
     description:
       'Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.',
   },
+  retryBackoff: {
+    type: 'number',
+    description:
+      'Milliseconds before the first retry, doubled with jitter for each next one (config: apiRetryBackoff)',
+    // Only show in root command.
+    hidden: true,
+  },
   spinner: {
     type: 'boolean',
     default: true,
@@ -299,6 +348,13 @@ export const commonFlags = defineFlags({
     // Hidden to allow custom documenting of the negated `--no-spinner` variant.
     hidden: true,
   },
+  timeout: {
+    type: 'number',
+    description:
+      'Milliseconds before a Socket API request times out (config: apiTimeout)',
+    // Only show in root command.
+    hidden: true,
+  },
 })
 
 export const outputFlags = defineFlags({
@@ -315,3 +371,42 @@ export const outputFlags = defineFlags({
     shortFlag: 'm',
   },
 })
+
+export const templateFlags = defineFlags({
+  outputTemplate: {
+    type: 'string',
+    default: '',
+    description:
+      'Render the result with a Handlebars-style template file instead of the default output',
+  },
+})
+
+export const csvFlags = defineFlags({
+  columns: {
+    type: 'string',
+    default: '',
+    description:
+      'Comma-separated columns of the CSV output, in order. See the help text for the available columns',
+  },
+  csvHeader: {
+    type: 'boolean',
+    default: true,
+    description:
+      'Start the CSV output with a header row. Use --no-csv-header to leave it out.',
+  },
+})
+
+export const cacheFlags = defineFlags({
+  cache: {
+    type: 'boolean',
+    default: true,
+    description:
+      'Read and write the local response cache. Use --no-cache to bypass it for this run.',
+  },
+  cacheTtl: {
+    type: 'string',
+    default: '',
+    description:
+      'Treat cached responses older than this (e.g. 30m, 12h, 7d) as stale for this run',
+  },
+})
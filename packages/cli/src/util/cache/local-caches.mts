@@ -0,0 +1,142 @@
+/**
+ * The on-disk response caches of the CLI, as exposed by `socket cache`. Each
+ * cache is a flat directory of JSON entries under the socket cache directory:
+ * `github` holds GitHub API responses (see util/git/github-cache.mts) and
+ * `scores` holds package score and alert lookups (see
+ * util/socket/score-cache.mts). Ages are taken from file mtimes, which the
+ * caches' atomic rename-on-write keeps equal to the entry timestamp.
+ */
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  getGithubCachePath,
+  getScoreCachePath,
+} from '../../constants/paths.mts'
+
+export const LOCAL_CACHE_NAMES = ['github', 'scores'] as const
+
+export type LocalCacheName = (typeof LOCAL_CACHE_NAMES)[number]
+
+export type LocalCacheStats = {
+  name: LocalCacheName
+  path: string
+  entries: number
+  bytes: number
+  // Timestamps (ms since epoch) of the oldest and newest entry.
+  oldest: number | undefined
+  newest: number | undefined
+}
+
+export type LocalCacheRemoval = {
+  name: LocalCacheName
+  path: string
+  removedEntries: number
+  removedBytes: number
+}
+
+type LocalCacheFile = {
+  filepath: string
+  mtimeMs: number
+  size: number
+}
+
+export function isLocalCacheName(name: string): name is LocalCacheName {
+  return (LOCAL_CACHE_NAMES as readonly string[]).includes(name)
+}
+
+export function getLocalCachePath(name: LocalCacheName): string {
+  return name === 'github' ? getGithubCachePath() : getScoreCachePath()
+}
+
+async function readLocalCacheFiles(
+  cachePath: string,
+): Promise<LocalCacheFile[]> {
+  let names: string[]
+  try {
+    names = await fs.readdir(cachePath)
+  } catch {
+    return []
+  }
+  const files: LocalCacheFile[] = []
+  for (let i = 0, { length } = names; i < length; i += 1) {
+    const name = names[i]!
+    // Leftover `.json.tmp.<pid>` files of interrupted writes count too.
+    if (!name.includes('.json')) {
+      continue
+    }
+    const filepath = path.join(cachePath, name)
+    try {
+      const stats = await fs.stat(filepath)
+      if (stats.isFile()) {
+        files.push({ filepath, mtimeMs: stats.mtimeMs, size: stats.size })
+      }
+    } catch {
+      // Removed by a concurrent prune.
+    }
+  }
+  return files
+}
+
+export async function getLocalCacheStats(
+  names: readonly LocalCacheName[] = LOCAL_CACHE_NAMES,
+): Promise<LocalCacheStats[]> {
+  const result: LocalCacheStats[] = []
+  for (let i = 0, { length } = names; i < length; i += 1) {
+    const name = names[i]!
+    const cachePath = getLocalCachePath(name)
+    const files = await readLocalCacheFiles(cachePath)
+    let bytes = 0
+    let oldest: number | undefined
+    let newest: number | undefined
+    for (let j = 0, { length: filesLength } = files; j < filesLength; j += 1) {
+      const { mtimeMs, size } = files[j]!
+      bytes += size
+      oldest = oldest === undefined ? mtimeMs : Math.min(oldest, mtimeMs)
+      newest = newest === undefined ? mtimeMs : Math.max(newest, mtimeMs)
+    }
+    result.push({
+      name,
+      path: cachePath,
+      entries: files.length,
+      bytes,
+      oldest,
+      newest,
+    })
+  }
+  return result
+}
+
+/**
+ * Remove the entries of the given caches, or with `olderThanMs` only the
+ * entries written more than that many milliseconds ago.
+ */
+export async function removeLocalCacheEntries(
+  names: readonly LocalCacheName[] = LOCAL_CACHE_NAMES,
+  olderThanMs?: number | undefined,
+): Promise<LocalCacheRemoval[]> {
+  const cutoff =
+    olderThanMs === undefined
+      ? Number.POSITIVE_INFINITY
+      : Date.now() - olderThanMs
+  const result: LocalCacheRemoval[] = []
+  for (let i = 0, { length } = names; i < length; i += 1) {
+    const name = names[i]!
+    const cachePath = getLocalCachePath(name)
+    const files = await readLocalCacheFiles(cachePath)
+    let removedEntries = 0
+    let removedBytes = 0
+    for (let j = 0, { length: filesLength } = files; j < filesLength; j += 1) {
+      const file = files[j]!
+      if (file.mtimeMs < cutoff) {
+        await safeDelete(file.filepath, { force: true })
+        removedEntries += 1
+        removedBytes += file.size
+      }
+    }
+    result.push({ name, path: cachePath, removedEntries, removedBytes })
+  }
+  return result
+}
@@ -0,0 +1,46 @@
+/**
+ * Per-run overrides of the on-disk response caches, set from the --no-cache
+ * and --cache-ttl flags of the commands that read them. The score cache and
+ * the GitHub API cache consult these before every read and write so a CI job
+ * with a baked cache directory can bypass it or narrow its TTL for one run.
+ */
+
+export type CacheOverrides = {
+  // Skip reading and writing the caches altogether.
+  disabled: boolean
+  // Replaces the TTL of every cache when set.
+  ttlMs: number | undefined
+}
+
+const DURATION_UNIT_MS: Record<string, number> = {
+  s: 1000,
+  m: 60 * 1000,
+  h: 60 * 60 * 1000,
+  d: 24 * 60 * 60 * 1000,
+  w: 7 * 24 * 60 * 60 * 1000,
+}
+
+let overrides: CacheOverrides = { disabled: false, ttlMs: undefined }
+
+export function getCacheOverrides(): CacheOverrides {
+  return overrides
+}
+
+export function setCacheOverrides(
+  newOverrides: Partial<CacheOverrides>,
+): void {
+  overrides = { disabled: false, ttlMs: undefined, ...newOverrides }
+}
+
+/**
+ * Parse a duration like `90s`, `30m`, `12h`, `7d` or `2w` into milliseconds. A
+ * bare number is taken as seconds. Returns `undefined` for anything else.
+ */
+export function parseCacheDuration(value: string): number | undefined {
+  const match = /^(\d+(?:\.\d+)?)\s*([smhdw]?)$/i.exec(value.trim())
+  if (!match) {
+    return undefined
+  }
+  const unit = (match[2] || 's').toLowerCase()
+  return Math.round(Number(match[1]) * DURATION_UNIT_MS[unit]!)
+}
@@ -0,0 +1,61 @@
+/**
+ * Command tree introspection for shell completion.
+ *
+ * A `CliSubcommand` only exposes `run`, so the subcommands and flags of a
+ * command are known only once it starts parsing its argv. While capturing,
+ * `meowWithSubcommands` and `meowOrExit` hand what they were given to
+ * `captureCommandTreeNode`, which throws it back to `inspectCommand` before
+ * anything is parsed, printed or run.
+ */
+
+import type { MeowFlags } from '../../flags.mts'
+import type { CliAliases, CliSubcommand } from './with-subcommands-shared.mts'
+
+export interface CommandTreeNode {
+  aliases: CliAliases
+  flags: MeowFlags
+  // Undefined for leaf commands.
+  subcommands: Record<string, CliSubcommand> | undefined
+}
+
+class CommandTreeCapture {
+  readonly node: CommandTreeNode
+
+  constructor(node: CommandTreeNode) {
+    this.node = node
+  }
+}
+
+let capturing = false
+
+export function captureCommandTreeNode(node: CommandTreeNode): never {
+  throw new CommandTreeCapture(node)
+}
+
+export function isCapturingCommandTree(): boolean {
+  return capturing
+}
+
+/**
+ * Get the subcommands and flags of `command` without running it. Returns
+ * undefined for commands that do not go through the router, or that fail
+ * before reaching it.
+ */
+export async function inspectCommand(
+  command: CliSubcommand,
+  parentName: string,
+  importMeta: ImportMeta,
+): Promise<CommandTreeNode | undefined> {
+  const previous = capturing
+  capturing = true
+  try {
+    await command.run([], importMeta, { parentName })
+  } catch (e) {
+    if (e instanceof CommandTreeCapture) {
+      return e.node
+    }
+  } finally {
+    capturing = previous
+  }
+  return undefined
+}
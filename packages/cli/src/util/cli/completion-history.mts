@@ -0,0 +1,75 @@
+/**
+ * Recently seen org slugs, repo names and scan IDs, offered by the shell
+ * completion of `socket completion` for the flags and arguments that take
+ * them.
+ *
+ * Commands that list or create these record them in one small JSON file under
+ * the socket cache directory, newest first and capped per kind. Completion
+ * runs on every tab press, so it only ever reads this file and never calls the
+ * API.
+ */
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { readJson } from '@socketsecurity/lib-stable/fs/read-json'
+import { safeMkdir } from '@socketsecurity/lib-stable/fs/safe'
+import { writeJson } from '@socketsecurity/lib-stable/fs/write-json'
+
+import { getCompletionHistoryPath } from '../../constants/paths.mts'
+
+export const COMPLETION_VALUE_KINDS = ['org', 'repo', 'scan'] as const
+
+export type CompletionValueKind = (typeof COMPLETION_VALUE_KINDS)[number]
+
+export type CompletionHistory = Record<CompletionValueKind, string[]>
+
+export const COMPLETION_HISTORY_MAX = 50
+
+export async function readCompletionHistory(): Promise<CompletionHistory> {
+  const history: CompletionHistory = { org: [], repo: [], scan: [] }
+  let stored: unknown
+  try {
+    stored = await readJson(getCompletionHistoryPath())
+  } catch {
+    return history
+  }
+  if (stored && typeof stored === 'object' && !Array.isArray(stored)) {
+    for (const kind of COMPLETION_VALUE_KINDS) {
+      const values = (stored as Record<string, unknown>)[kind]
+      if (Array.isArray(values)) {
+        history[kind] = values.filter(v => typeof v === 'string')
+      }
+    }
+  }
+  return history
+}
+
+/**
+ * Move `values` to the front of the `kind` history. Failures are logged and
+ * swallowed; they should never fail the command that saw the values.
+ */
+export async function recordCompletionValues(
+  kind: CompletionValueKind,
+  values: ReadonlyArray<string | null | undefined>,
+): Promise<void> {
+  const seen = values.filter((v): v is string => !!v)
+  if (!seen.length) {
+    return
+  }
+  try {
+    const history = await readCompletionHistory()
+    history[kind] = [...new Set([...seen, ...history[kind]])].slice(
+      0,
+      COMPLETION_HISTORY_MAX,
+    )
+    const historyPath = getCompletionHistoryPath()
+    await safeMkdir(path.dirname(historyPath), { recursive: true })
+    // Use atomic write pattern to prevent multi-process race conditions.
+    const tmpPath = `${historyPath}.tmp.${process.pid}`
+    await writeJson(tmpPath, history)
+    await fs.rename(tmpPath, historyPath)
+  } catch (e) {
+    debugDir('error', e)
+  }
+}
@@ -5,10 +5,13 @@
  * 1. Parse Socket CLI flags with meow (mostly to handle `--help`).
  * 2. Filter Socket-only flags out of argv.
  * 3. Optionally render dry-run output and bail.
- * 4. Optionally start a telemetry span for the subprocess.
- * 5. Spawn Socket Firewall (sfw) with the forwarded args.
- * 6. Forward the child's exit code / signal.
- * 7. Optionally end the telemetry span before exiting.
+ * 4. Optionally run a preflight check (e.g. pre-install package scoring).
+ * 5. Optionally prepare the hand-off, rewriting the args and adding a step
+ *    that runs after a successful exit.
+ * 6. Optionally start a telemetry span for the subprocess.
+ * 7. Spawn Socket Firewall (sfw) with the forwarded args.
+ * 8. Forward the child's exit code / signal.
+ * 9. Optionally end the telemetry span before exiting.
  *
  * Defining each wrapper through this helper kills ~100 lines of copy-paste per
  * ecosystem and makes future improvements (signal handling, telemetry, dry-run
@@ -19,11 +22,17 @@
  * examples: ['install ripgrep', 'build', 'add serde'], })
  */
 
+import isInteractive from '@socketregistry/is-interactive/index.cjs'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
 import { defineFlags } from '../../meow.mts'
 import { commonFlags } from '../../flags.mts'
 import { meowOrExit } from './with-subcommands.mts'
 import { spawnSfw, spawnSfwDlx } from '../dlx/spawn.mjs'
 import { outputDryRunExecute } from '../dry-run/output.mts'
+import { getErrorCause } from '../error/errors.mts'
+import { failMsgWithBadge } from '../error/fail-msg-with-badge.mts'
 import { getFlagApiRequirementsOutput } from '../output/formatting.mts'
 import { filterFlags } from '../process/cmd.mts'
 import {
@@ -33,6 +42,21 @@ import {
 
 import type { CliCommandContext } from './with-subcommands.mts'
 import type { CliSubcommand } from './with-subcommands-shared.mts'
+import type { MeowFlags } from '../../flags.mts'
+import type { CResult } from '../../types.mts'
+
+export type HandoffPlan = {
+  // The args to forward instead of the filtered argv.
+  args: readonly string[]
+  // Runs once the child exits with code 0, before the CLI exits.
+  afterExit?: (() => Promise<CResult<unknown>>) | undefined
+}
+
+export type HandoffPreflightContext = {
+  // False without a TTY or with --non-interactive; prompts then take the
+  // socket.yml `wrapper.nonInteractive` answer.
+  interactive: boolean
+}
 
 export interface DefineHandoffCommandOptions {
   /**
@@ -52,9 +76,11 @@ export interface DefineHandoffCommandOptions {
    * Spawn strategy: - 'auto' (= spawnSfw): VFS-extract in SEA mode,
    * dlx-download otherwise. Used by npm/npx because those binaries are bundled
    * in the SEA. - 'dlx' (= spawnSfwDlx): always pnpm-dlx-download. Used by yarn
-   * / pip / cargo / go / etc. where the SEA doesn't bundle the binary.
+   * / pip / cargo / go / etc. where the SEA doesn't bundle the binary. -
+   * 'direct': spawn the binary itself, without sfw. Only for tools sfw cannot
+   * proxy (pipx); those rely on `preflight` for their checks.
    */
-  spawnMode: 'auto' | 'dlx'
+  spawnMode: 'auto' | 'dlx' | 'direct'
   /**
    * Examples to render under "Examples" in the help text. Each line is
    * automatically prefixed with "$ ${command} ". Pass the args portion only.
@@ -68,6 +94,39 @@ export interface DefineHandoffCommandOptions {
   binaryPicker?:
     | ((context: CliCommandContext) => Promise<string> | string)
     | undefined
+  /**
+   * Optional check that runs after flag parsing and before the hand-off, with
+   * the forwarded args and the resolved binary. A failed result is printed and
+   * exits with code 1 without spawning anything. Used by `socket pip` and
+   * `socket pipx` to score the resolved wheels/sdists before installing.
+   * Commands with a preflight take --non-interactive.
+   */
+  preflight?:
+    | ((
+        args: readonly string[],
+        binaryName: string,
+        context: HandoffPreflightContext,
+      ) => Promise<CResult<unknown>>)
+    | undefined
+  /**
+   * Socket-only flags of this command besides the common ones. They are
+   * parsed with meow and filtered out of the forwarded args.
+   */
+  flags?: MeowFlags | undefined
+  /**
+   * Optional step that runs after the preflight and may change the hand-off,
+   * with the forwarded args and the parsed flags. A failed result is printed
+   * and exits with code 1 without spawning anything. A failed `afterExit`
+   * result is printed and exits with code 1. Used by `socket npm
+   * --sandbox-scripts` to install with --ignore-scripts and run the install
+   * scripts in a sandbox afterwards.
+   */
+  prepareHandoff?:
+    | ((
+        args: readonly string[],
+        flags: Record<string, unknown>,
+      ) => Promise<CResult<HandoffPlan>>)
+    | undefined
   /**
    * Extra free-form notes appended after the standard "Note: Everything after X
    * is forwarded…" line. Each entry becomes one indented line.
@@ -99,6 +158,15 @@ const DEFAULT_HIDDEN = false
 const DEFAULT_SUPPORT_DRY_RUN = true
 const DEFAULT_TRACK_TELEMETRY = true
 
+const preflightFlags: MeowFlags = {
+  nonInteractive: {
+    type: 'boolean',
+    default: false,
+    description:
+      'Never prompt; answer with the `wrapper.nonInteractive` setting of socket.yml',
+  },
+}
+
 /**
  * Build the help-text generator function used by meow.
  */
@@ -106,7 +174,15 @@ export function buildHelp(
   config: DefineHandoffCommandOptions,
   parentName: string,
 ): (command: string) => string {
-  const { examples, helpNotes, name, showApiRequirements, wrapperHint } = {
+  const {
+    examples,
+    helpNotes,
+    name,
+    preflight,
+    showApiRequirements,
+    spawnMode,
+    wrapperHint,
+  } = {
     __proto__: null,
     ...config,
   } as typeof config
@@ -123,11 +199,18 @@ export function buildHelp(
       )
     }
 
-    lines.push(
-      '',
-      `    Note: Everything after "${name}" is forwarded to Socket Firewall (sfw).`,
-      `          Socket Firewall provides real-time security scanning for ${name} packages.`,
-    )
+    if (spawnMode === 'direct') {
+      lines.push(
+        '',
+        `    Note: Everything after "${name}" is forwarded to ${name}.`,
+      )
+    } else {
+      lines.push(
+        '',
+        `    Note: Everything after "${name}" is forwarded to Socket Firewall (sfw).`,
+        `          Socket Firewall provides real-time security scanning for ${name} packages.`,
+      )
+    }
 
     if (helpNotes?.length) {
       for (let i = 0, { length } = helpNotes; i < length; i += 1) {
@@ -136,6 +219,14 @@ export function buildHelp(
       }
     }
 
+    if (preflight) {
+      lines.push(
+        '',
+        '    With --non-interactive, or without a TTY, prompts take the answer of',
+        '    `wrapper.nonInteractive` in socket.yml (allow or block, allow by default).',
+      )
+    }
+
     if (wrapperHint) {
       lines.push(
         '',
@@ -168,8 +259,11 @@ export function defineHandoffCommand(
 ): CliSubcommand {
   const {
     description,
+    flags,
     hidden = DEFAULT_HIDDEN,
     name,
+    preflight,
+    prepareHandoff,
     spawnMode,
     supportDryRun = DEFAULT_SUPPORT_DRY_RUN,
     trackTelemetry = DEFAULT_TRACK_TELEMETRY,
@@ -189,7 +283,11 @@ export function defineHandoffCommand(
       commandName: name,
       description,
       hidden,
-      flags: defineFlags({ ...commonFlags }),
+      flags: defineFlags({
+        ...commonFlags,
+        ...(preflight ? preflightFlags : {}),
+        ...flags,
+      }),
       help: buildHelp(config, parentName),
     }
 
@@ -200,11 +298,15 @@ export function defineHandoffCommand(
     const filteredArgv = filterFlags(argv, cliConfig.flags, [])
 
     if (supportDryRun && cli.flags['dryRun']) {
-      outputDryRunExecute(
-        'sfw',
-        [name, ...filteredArgv],
-        `${name} with Socket security scanning`,
-      )
+      if (spawnMode === 'direct') {
+        outputDryRunExecute(name, filteredArgv, `${name} with Socket checks`)
+      } else {
+        outputDryRunExecute(
+          'sfw',
+          [name, ...filteredArgv],
+          `${name} with Socket security scanning`,
+        )
+      }
       return
     }
 
@@ -213,6 +315,34 @@ export function defineHandoffCommand(
       ? await config.binaryPicker(context)
       : name
 
+    if (preflight) {
+      const preflightCResult = await preflight(filteredArgv, binaryName, {
+        interactive: !cli.flags['nonInteractive'] && isInteractive(),
+      })
+      if (!preflightCResult.ok) {
+        getDefaultLogger().fail(
+          failMsgWithBadge(preflightCResult.message, preflightCResult.cause),
+        )
+        process.exitCode = 1
+        return
+      }
+    }
+
+    let handoffArgs: readonly string[] = filteredArgv
+    let afterExit: HandoffPlan['afterExit']
+    if (prepareHandoff) {
+      const planCResult = await prepareHandoff(filteredArgv, cli.flags)
+      if (!planCResult.ok) {
+        getDefaultLogger().fail(
+          failMsgWithBadge(planCResult.message, planCResult.cause),
+        )
+        process.exitCode = 1
+        return
+      }
+      handoffArgs = planCResult.data.args
+      afterExit = planCResult.data.afterExit
+    }
+
     // Default to failure; child's exit listener overwrites on success.
     process.exitCode = 1
 
@@ -221,9 +351,12 @@ export function defineHandoffCommand(
       : undefined
 
     const spawnFn = spawnMode === 'auto' ? spawnSfw : spawnSfwDlx
-    const { spawnPromise } = await spawnFn([binaryName, ...filteredArgv], {
-      stdio: 'inherit',
-    })
+    const { spawnPromise } =
+      spawnMode === 'direct'
+        ? { spawnPromise: spawn(binaryName, handoffArgs, { stdio: 'inherit' }) }
+        : await spawnFn([binaryName, ...handoffArgs], {
+            stdio: 'inherit',
+          })
 
     const { process: childProcess } = spawnPromise as unknown as {
       process: NodeJS.Process & {
@@ -231,6 +364,7 @@ export function defineHandoffCommand(
       }
     }
     wireChildExit(childProcess, {
+      afterExit,
       name,
       subprocessStartTime,
       trackTelemetry,
@@ -243,41 +377,67 @@ export function defineHandoffCommand(
 }
 
 /**
- * Wire the child process's exit/signal back to the parent. Optionally flushes
- * telemetry first. Centralized so all wrappers share the same lifecycle.
+ * Wire the child process's exit/signal back to the parent. Optionally runs
+ * the `afterExit` step of the hand-off plan and flushes telemetry first.
+ * Centralized so all wrappers share the same lifecycle.
  */
 export function wireChildExit(
   childProcess: NodeJS.Process & {
     on: (event: string, listener: (...args: unknown[]) => void) => void
   },
   config: {
+    afterExit?: HandoffPlan['afterExit']
     name: string
     trackTelemetry: boolean
     subprocessStartTime: number | undefined
   },
 ): void {
-  const { name, subprocessStartTime, trackTelemetry } = {
+  const { afterExit, name, subprocessStartTime, trackTelemetry } = {
     __proto__: null,
     ...config,
   } as typeof config
   childProcess.on(
     'exit',
     (code: number | null, signalName: NodeJS.Signals | null) => {
-      const exitProcess = () => {
-        if (signalName) {
-          process.kill(process.pid, signalName)
-        } else if (typeof code === 'number') {
-          process.exit(code)
+      const finish = (exitCode: number | null) => {
+        const exitProcess = () => {
+          if (signalName) {
+            process.kill(process.pid, signalName)
+          } else if (typeof exitCode === 'number') {
+            process.exit(exitCode)
+          }
+        }
+        if (trackTelemetry && subprocessStartTime !== undefined) {
+          // .then/.catch so the exit happens even when telemetry flush fails.
+          void trackSubprocessExit(name, subprocessStartTime, exitCode)
+            .then(exitProcess)
+            .catch(exitProcess)
+        } else {
+          exitProcess()
         }
       }
-      if (trackTelemetry && subprocessStartTime !== undefined) {
-        // .then/.catch so the exit happens even when telemetry flush fails.
-        void trackSubprocessExit(name, subprocessStartTime, code)
-          .then(exitProcess)
-          .catch(exitProcess)
-      } else {
-        exitProcess()
+      if (!afterExit || signalName || code !== 0) {
+        finish(code)
+        return
       }
+      void afterExit()
+        .catch(
+          (e: unknown): CResult<unknown> => ({
+            ok: false,
+            message: `${name} failed after exiting`,
+            cause: getErrorCause(e),
+          }),
+        )
+        .then(afterExitCResult => {
+          if (afterExitCResult.ok) {
+            finish(code)
+            return
+          }
+          getDefaultLogger().fail(
+            failMsgWithBadge(afterExitCResult.message, afterExitCResult.cause),
+          )
+          finish(1)
+        })
     },
   )
 }
@@ -0,0 +1,183 @@
+/**
+ * Git-style external commands: `socket foo` runs a `socket-foo` executable
+ * on PATH when `foo` is not a built-in command, so teams can ship their own
+ * commands without forking the CLI.
+ *
+ * The executable gets the arguments after the command name and the context
+ * of the invocation through environment variables:
+ *
+ * - SOCKET_CLI_API_TOKEN_FILE: A file holding the Socket API token, removed
+ *   when the command exits. Unset without a token.
+ * - SOCKET_CLI_API_AUTH_SCHEME: `bearer` when the Socket API expects the
+ *   token as a Bearer token rather than with Basic auth.
+ * - SOCKET_CLI_API_BASE_URL: The Socket API base URL, when not the default.
+ * - SOCKET_CLI_ORG_SLUG: The --org flag or default org, when there is one.
+ * - SOCKET_CLI_OUTPUT_FORMAT: `json`, `markdown` or `text`.
+ * - SOCKET_CLI_VERSION: The version of the Socket CLI.
+ *
+ * Built-in commands and aliases always win over external commands.
+ */
+
+import { promises as fs } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { whichReal } from '@socketsecurity/lib-stable/bin/which'
+import { WIN32 } from '@socketsecurity/lib-stable/constants/platform'
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+import { isSpawnError } from '@socketsecurity/lib-stable/process/spawn/errors'
+
+import { CONFIG_KEY_DEFAULT_ORG } from '../../constants/config.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { getConfigValueOrUndef } from '../config.mts'
+import { getApiAuthScheme } from '../socket/api-endpoint.mts'
+import { getDefaultApiBaseUrl, getDefaultApiToken } from '../socket/sdk.mts'
+
+import type { OutputKind } from '../../types.mts'
+
+export const EXTERNAL_COMMAND_PREFIX = 'socket-'
+
+// What cmd.exe acts on in a command line, escaped with a caret.
+const CMD_META_CHARS_REGEXP = /([()\][%!^"`<>&|;, *?])/g
+
+export type ExternalCommandContext = {
+  orgSlug?: string | undefined
+  outputKind: OutputKind
+}
+
+/**
+ * Whether a command name can name an external command. Rejects flags, paths
+ * and anything else that is not a plain lowercase name.
+ */
+export function isExternalCommandName(name: string): boolean {
+  return /^[a-z0-9][a-z0-9-]*$/.test(name)
+}
+
+/**
+ * The `socket-<name>` executable on PATH, undefined when there is none.
+ */
+export async function findExternalCommand(
+  name: string,
+): Promise<string | undefined> {
+  if (!isExternalCommandName(name)) {
+    return undefined
+  }
+  const binPath = await whichReal(`${EXTERNAL_COMMAND_PREFIX}${name}`, {
+    nothrow: true,
+  })
+  return (Array.isArray(binPath) ? binPath[0] : binPath) || undefined
+}
+
+/**
+ * The environment variables describing the invocation, see the module doc.
+ */
+export function getExternalCommandEnv(
+  context: ExternalCommandContext,
+  tokenFile: string | undefined,
+): Record<string, string> {
+  const defaultOrg: unknown = getConfigValueOrUndef(CONFIG_KEY_DEFAULT_ORG)
+  const orgSlug =
+    context.orgSlug || (typeof defaultOrg === 'string' ? defaultOrg : '')
+  const apiBaseUrl = getDefaultApiBaseUrl()
+  const apiAuthScheme = getApiAuthScheme()
+  const env: Record<string, string> = {
+    SOCKET_CLI_OUTPUT_FORMAT: context.outputKind,
+    SOCKET_CLI_VERSION: getCliVersion() || '',
+  }
+  if (apiAuthScheme !== 'basic') {
+    env['SOCKET_CLI_API_AUTH_SCHEME'] = apiAuthScheme
+  }
+  if (apiBaseUrl) {
+    env['SOCKET_CLI_API_BASE_URL'] = apiBaseUrl
+  }
+  if (orgSlug) {
+    env['SOCKET_CLI_ORG_SLUG'] = orgSlug
+  }
+  if (tokenFile) {
+    env['SOCKET_CLI_API_TOKEN_FILE'] = tokenFile
+  }
+  return env
+}
+
+/**
+ * The command and arguments to spawn an external command with, without a
+ * shell. A Windows .cmd or .bat shim is run by cmd.exe instead, with its
+ * path and arguments quoted and escaped the way cross-spawn does it, so the
+ * arguments reach the shim as they are.
+ */
+export function getExternalCommandSpawnArgs(
+  binPath: string,
+  argv: string[] | readonly string[],
+): { args: string[]; command: string; windowsVerbatimArguments: boolean } {
+  if (!WIN32 || !/\.(?:bat|cmd)$/i.test(binPath)) {
+    return {
+      args: [...argv],
+      command: binPath,
+      windowsVerbatimArguments: false,
+    }
+  }
+  const commandLine = [
+    binPath.replace(CMD_META_CHARS_REGEXP, '^$1'),
+    ...argv.map(quoteCmdArg),
+  ].join(' ')
+  return {
+    args: ['/d', '/s', '/c', `"${commandLine}"`],
+    command: process.env['ComSpec'] || 'cmd.exe',
+    windowsVerbatimArguments: true,
+  }
+}
+
+/**
+ * Quote an argument of a cmd.exe command line: backslash-escape its quotes
+ * and trailing backslashes, wrap it in quotes and caret-escape the rest.
+ */
+function quoteCmdArg(arg: string): string {
+  const escaped = arg.replace(/(\\*)"/g, '$1$1\\"').replace(/(\\*)$/, '$1$1')
+  return `"${escaped}"`.replace(CMD_META_CHARS_REGEXP, '^$1')
+}
+
+/**
+ * Run an external command with the invocation context and resolve to its
+ * exit code. The API token is handed over in a file only the current user
+ * can read, rather than in the environment the command may pass on.
+ */
+export async function runExternalCommand(
+  binPath: string,
+  argv: string[] | readonly string[],
+  context: ExternalCommandContext,
+): Promise<number> {
+  const token = getDefaultApiToken()
+  const tmpDir = token
+    ? await fs.mkdtemp(path.join(os.tmpdir(), 'socket-cli-command-'))
+    : undefined
+  try {
+    let tokenFile: string | undefined
+    if (tmpDir && token) {
+      tokenFile = path.join(tmpDir, 'api-token')
+      await fs.writeFile(tokenFile, token, { encoding: 'utf8', mode: 0o600 })
+    }
+    try {
+      const { args, command, windowsVerbatimArguments } =
+        getExternalCommandSpawnArgs(binPath, argv)
+      await spawn(command, args, {
+        env: {
+          ...process.env,
+          ...getExternalCommandEnv(context, tokenFile),
+        },
+        stdio: 'inherit',
+        windowsVerbatimArguments,
+      })
+      return 0
+    } catch (e) {
+      if (!isSpawnError(e)) {
+        throw e
+      }
+      return typeof e.code === 'number' ? e.code : 1
+    }
+  } finally {
+    if (tmpDir) {
+      await safeDelete(tmpDir, { force: true })
+    }
+  }
+}
@@ -9,8 +9,15 @@
 
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
+import { setUsageCommand } from '../telemetry/usage.mts'
+
+import {
+  findExternalCommand,
+  runExternalCommand,
+} from './external-command.mts'
 import { findBestCommandMatch } from './with-subcommands-fuzzy-match.mts'
 
+import type { ExternalCommandContext } from './external-command.mts'
 import type { CliAliases, CliSubcommand } from './with-subcommands-shared.mts'
 import type { CliCommandContext } from './with-subcommands.mts'
 
@@ -20,6 +27,8 @@ export interface DispatchSubcommandOptions {
   aliases: CliAliases
   commandOrAliasName: string
   defaultSub: string | undefined
+  // Set for the root router, which falls back to `socket-<name>` executables.
+  externalCommandContext?: ExternalCommandContext | undefined
   importMeta: ImportMeta
   name: string
   rawCommandArgv: string[]
@@ -38,6 +47,7 @@ export async function tryDispatchSubcommand(
     aliases,
     commandOrAliasName,
     defaultSub,
+    externalCommandContext,
     importMeta,
     name,
     rawCommandArgv,
@@ -64,6 +74,7 @@ export async function tryDispatchSubcommand(
     if (alias) {
       context.invokedAs = commandOrAliasName
     }
+    setUsageCommand(`${name} ${commandName}`)
     await commandDefinition.run(commandArgv, importMeta, context)
     return true
   }
@@ -71,6 +82,7 @@ export async function tryDispatchSubcommand(
   // If no command found but defaultSub exists, use it as the command.
   // This treats the first arg as an argument to the default subcommand.
   if (!commandDefinition && defaultSub && subcommands[defaultSub]) {
+    setUsageCommand(`${name} ${defaultSub}`)
     await subcommands[defaultSub].run(
       [commandOrAliasName, ...rawCommandArgv],
       importMeta,
@@ -81,6 +93,19 @@ export async function tryDispatchSubcommand(
     return true
   }
 
+  // Run a `socket-<name>` executable on PATH, like git does.
+  if (commandName && externalCommandContext) {
+    const binPath = await findExternalCommand(commandName)
+    if (binPath) {
+      process.exitCode = await runExternalCommand(
+        binPath,
+        commandArgv,
+        externalCommandContext,
+      )
+      return true
+    }
+  }
+
   // Suggest similar commands for typos.
   if (commandName && !commandDefinition) {
     const suggestion = findBestCommandMatch(commandName, subcommands, aliases)
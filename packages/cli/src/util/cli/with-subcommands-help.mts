@@ -206,23 +206,42 @@ export function pushEnvironmentVariables(
       `                              ${colors.italic('Defaults:')} github-actions[bot]`,
       `  SOCKET_CLI_GITHUB_TOKEN     A classic or fine-grained ${terminalLink('GitHub personal access token', 'https://docs.github.com/en/authentication/keeping-your-account-and-data-secure/managing-your-personal-access-tokens')}`,
       `                              ${colors.italic('Aliases:')} GITHUB_TOKEN`,
+      '  SOCKET_CLI_LOG_FILE         Append the structured log to this file, like --log-file',
+      '  SOCKET_CLI_LOG_FORMAT       Format of the structured log, text or json, like --log-format',
+      '  SOCKET_CLI_LOG_LEVEL        Write a structured log from this level, like --log-level',
       '  SOCKET_CLI_NO_API_TOKEN     Make the default API token `undefined`',
+      '  SOCKET_CLI_NO_KEYCHAIN      Store API tokens in the config file instead of the OS keychain',
+      '  SOCKET_CLI_NO_SELF_UPDATE   Turn off `socket self-update` and update notifications',
       '  SOCKET_CLI_NPM_PATH         The absolute location of the npm directory',
+      '  SOCKET_CLI_OFFLINE          Score packages from the local cache only, like --offline',
       '  SOCKET_CLI_ORG_SLUG         Specify the Socket organization slug',
+      '  SOCKET_CLI_PROFILE          Use this named auth profile instead of the active one',
+      '  SOCKET_CLI_SKIP_HOOKS       Let the git hooks of `socket hooks install` pass without checking',
+      '  SOCKET_CLI_SSO_ISSUER       The OAuth issuer `socket login --sso` authenticates with',
       '',
       '  SOCKET_CLI_ACCEPT_RISKS     Accept risks of a Socket wrapped npm/pnpm exec run',
       '  SOCKET_CLI_VIEW_ALL_RISKS   View all risks of a Socket wrapped npm/pnpm exec run',
+      '  SOCKET_CLI_VERIFY_PROVENANCE',
+      '                              Verify the provenance of packages a Socket wrapped npm installs',
       '',
       'Environment variables for development',
-      '  SOCKET_CLI_API_BASE_URL     Change the base URL for Socket API calls',
+      '  SOCKET_CLI_API_AUTH_SCHEME  Send the API token with basic (default) or bearer auth',
+      '  SOCKET_CLI_API_BASE_URL     Change the base URL for Socket API calls, like --api-base-url',
       `                              ${colors.italic('Defaults:')} The "apiBaseUrl" value of socket/settings local app data`,
       `                              if present, else ${API_V0_URL}`,
+      '  SOCKET_CLI_API_CONCURRENCY  Set how many batched Socket API lookups may run at once (default 4)',
+      '  SOCKET_CLI_API_MAX_RETRIES  Set how often failed Socket API requests are retried, like --max-retries',
       '  SOCKET_CLI_API_PROXY        Set the proxy Socket API requests are routed through, e.g. if set to',
       `                              ${terminalLink('http://127.0.0.1:9090', 'https://docs.proxyman.io/troubleshooting/couldnt-see-any-requests-from-3rd-party-network-libraries')} then all request are passed through that proxy`,
       `                              ${colors.italic('Aliases:')} HTTPS_PROXY, https_proxy, HTTP_PROXY, and http_proxy`,
-      '  SOCKET_CLI_API_TIMEOUT      Set the timeout in milliseconds for Socket API requests',
+      '  SOCKET_CLI_API_RETRY_BACKOFF',
+      '                              Set the milliseconds before the first retry, like --retry-backoff',
+      '  SOCKET_CLI_API_TIMEOUT      Set the timeout in milliseconds for Socket API requests, like --timeout',
       '  SOCKET_CLI_DEBUG            Enable debug logging in Socket CLI',
       `  DEBUG                       Enable debug logging based on the ${socketPackageLink('npm', 'debug', undefined, 'debug')} package`,
+      '  NO_PROXY                    Comma separated hosts reached without the proxy, e.g. .corp.example',
+      `                              ${colors.italic('Aliases:')} no_proxy`,
+      '  SSL_CERT_FILE               Trust the CA certificates in this PEM file, like --cacert',
     )
   } else {
     // Show condensed help with hint about --help-full.
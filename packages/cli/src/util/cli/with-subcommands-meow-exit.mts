@@ -14,10 +14,16 @@ import { trimNewlines } from '@socketsecurity/lib-stable/strings/transform'
 import { VITEST } from '../../env/vitest.mts'
 import { meow } from '../../meow.mts'
 import { isDebug } from '../debug.mts'
+import { logCommandStart } from '../log/structured.mts'
 import {
   resetMachineOutputMode,
   setMachineOutputMode,
 } from '../output/ambient-mode.mts'
+import { setJsonOutputCommand } from '../output/json-envelope.mts'
+import {
+  captureCommandTreeNode,
+  isCapturingCommandTree,
+} from './command-tree.mts'
 import { emitBanner, shouldSuppressBanner } from './with-subcommands-banner.mts'
 
 import type { CliCommandConfig } from './with-subcommands.mts'
@@ -67,6 +73,15 @@ export function meowOrExit<const F extends MeowFlags = MeowFlags>(
   } as MeowOrExitOptions
   const command = `${parentName} ${cliConfig.commandName}`
 
+  // Shell completion only needs to know the flags of this command.
+  if (isCapturingCommandTree()) {
+    captureCommandTreeNode({
+      aliases: {},
+      flags: cliConfig.flags,
+      subcommands: undefined,
+    })
+  }
+
   // This exits if .printHelp() is called either by meow itself or by us.
   const cli = meow({
     argv,
@@ -109,6 +124,8 @@ export function meowOrExit<const F extends MeowFlags = MeowFlags>(
     markdown: markdownFlag,
     quiet: quietFlag,
   })
+  // Name the command in the --json envelope.
+  setJsonOutputCommand(command)
 
   const compactMode = compactHeaderFlag || (getCI() && !VITEST)
   const noSpinner = !spinnerFlag || isDebug()
@@ -179,5 +196,7 @@ export function meowOrExit<const F extends MeowFlags = MeowFlags>(
   // Ok, no help, reset to default.
   process.exitCode = 0
 
+  logCommandStart(command)
+
   return cli
 }
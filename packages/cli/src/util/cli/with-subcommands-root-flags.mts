@@ -12,9 +12,9 @@ import type { MeowFlag, MeowFlags } from '../../flags.mts'
 
 /**
  * Mutate `flags` in place so the root `socket` command shows its
- * root-only flags (compact-header, config, dry-run, help, version, ...) and
- * hides the per-command `json`/`markdown` flags, or the reverse for a
- * sub-command invocation.
+ * root-only flags (api-base-url, cacert, compact-header, config, dry-run,
+ * help, ...) and hides the per-command `json`/`markdown` flags, or the
+ * reverse for a sub-command invocation.
  */
 export function applyRootCommandFlagVisibility(
   flags: MeowFlags,
@@ -24,6 +24,16 @@ export function applyRootCommandFlagVisibility(
   if (isRootCommand) {
     const hiddenDebugFlag = !isDebug()
 
+    flags['apiBaseUrl'] = {
+      ...flags['apiBaseUrl'],
+      hidden: false,
+    } as MeowFlag
+
+    flags['cacert'] = {
+      ...flags['cacert'],
+      hidden: false,
+    } as MeowFlag
+
     flags['compactHeader'] = {
       ...flags['compactHeader'],
       hidden: false,
@@ -49,16 +59,46 @@ export function applyRootCommandFlagVisibility(
       hidden: false,
     } as MeowFlag
 
+    flags['logFile'] = {
+      ...flags['logFile'],
+      hidden: false,
+    } as MeowFlag
+
+    flags['logFormat'] = {
+      ...flags['logFormat'],
+      hidden: false,
+    } as MeowFlag
+
+    flags['logLevel'] = {
+      ...flags['logLevel'],
+      hidden: false,
+    } as MeowFlag
+
     flags['maxOldSpaceSize'] = {
       ...flags['maxOldSpaceSize'],
       hidden: hiddenDebugFlag,
     } as MeowFlag
 
+    flags['maxRetries'] = {
+      ...flags['maxRetries'],
+      hidden: false,
+    } as MeowFlag
+
     flags['maxSemiSpaceSize'] = {
       ...flags['maxSemiSpaceSize'],
       hidden: hiddenDebugFlag,
     } as MeowFlag
 
+    flags['retryBackoff'] = {
+      ...flags['retryBackoff'],
+      hidden: false,
+    } as MeowFlag
+
+    flags['timeout'] = {
+      ...flags['timeout'],
+      hidden: false,
+    } as MeowFlag
+
     flags['version'] = {
       ...flags['version'],
       hidden: false,
@@ -9,16 +9,31 @@ import { getOwn } from '@socketsecurity/lib-stable/objects/inspect'
 import { indentString } from '@socketsecurity/lib-stable/strings/format'
 
 import { DRY_RUN_LABEL } from '../../constants/cli.mts'
+import { CONFIG_KEY_DEFAULT_ORG } from '../../constants/config.mts'
 import { VITEST } from '../../env/vitest.mts'
 import { commonFlags } from '../../flags.mts'
 import { meow } from '../../meow.mts'
-import { overrideCachedConfig, overrideConfigApiToken } from '../config.mts'
+import {
+  overrideCachedConfig,
+  overrideConfigApiToken,
+  setConfigFlagValue,
+} from '../config.mts'
 import { isDebug } from '../debug.mts'
+import { configureLogging } from '../log/structured.mts'
+import { setApiEndpointFlags } from '../socket/api-endpoint.mts'
+import { applyNetworkEnv, setCaCertFlag } from '../socket/network.mts'
+import { setApiRetryFlags } from '../socket/retry.mts'
+import { getDefaultProxyUrl } from '../socket/sdk.mts'
 import {
   resetMachineOutputMode,
   setMachineOutputMode,
 } from '../output/ambient-mode.mts'
+import { getOutputKind } from '../output/mode.mts'
 
+import {
+  captureCommandTreeNode,
+  isCapturingCommandTree,
+} from './command-tree.mts'
 import { buildHelpLines } from './with-subcommands-help.mts'
 import { tryDispatchSubcommand } from './with-subcommands-dispatch.mts'
 import { applyRootCommandFlagVisibility } from './with-subcommands-root-flags.mts'
@@ -141,6 +156,11 @@ export async function meowWithSubcommands(
     ...getOwn(additionalOptions, 'flags'),
   }
 
+  // Shell completion only needs to know what this router offers.
+  if (isCapturingCommandTree()) {
+    captureCommandTreeNode({ aliases, flags, subcommands })
+  }
+
   const [commandOrAliasName_, ...rawCommandArgv] = argv
   let commandOrAliasName = commandOrAliasName_
   if (!commandOrAliasName && defaultSub) {
@@ -201,21 +221,37 @@ export async function meowWithSubcommands(
   })
 
   const {
+    apiBaseUrl: apiBaseUrlFlag,
+    cacert: cacertFlag,
     compactHeader: compactHeaderFlag,
     config: configFlag,
     json: jsonFlag,
+    logFile: logFileFlag,
+    logFormat: logFormatFlag,
+    logLevel: logLevelFlag,
     markdown: markdownFlag,
+    maxRetries: maxRetriesFlag,
     org: orgFlag,
     quiet: quietFlag,
+    retryBackoff: retryBackoffFlag,
     spinner: spinnerFlag,
+    timeout: timeoutFlag,
   } = cli1.flags as {
+    apiBaseUrl: string | undefined
+    cacert: string | undefined
     compactHeader: boolean
     config: string
     json: boolean | undefined
+    logFile: string | undefined
+    logFormat: string | undefined
+    logLevel: string | undefined
     markdown: boolean | undefined
+    maxRetries: number | string | undefined
     org: string
     quiet: boolean | undefined
+    retryBackoff: number | string | undefined
     spinner: boolean
+    timeout: number | string | undefined
   }
 
   // Re-derive from the current argv so ambient mode doesn't leak across
@@ -238,15 +274,33 @@ export async function meowWithSubcommands(
     // Refactoring opportunity: Extract spinner to standalone module.
   }
   // Hard override the config if instructed to do so.
-  // The env var overrides the --flag, which overrides the persisted config
+  // The --flag overrides the env var, which overrides the persisted config
   // Also, when either of these are used, config updates won't persist.
   let configOverrideResult: ReturnType<typeof overrideCachedConfig> | undefined
   const socketCliConfig = getSocketCliConfig()
-  if (socketCliConfig) {
-    configOverrideResult = overrideCachedConfig(socketCliConfig)
-  } else if (configFlag) {
-    configOverrideResult = overrideCachedConfig(configFlag)
+  if (configFlag) {
+    configOverrideResult = overrideCachedConfig(configFlag, '--config')
+  } else if (socketCliConfig) {
+    configOverrideResult = overrideCachedConfig(
+      socketCliConfig,
+      'SOCKET_CLI_CONFIG',
+    )
   }
+  // Reset on every invocation so the flag doesn't leak into the next one.
+  setConfigFlagValue(CONFIG_KEY_DEFAULT_ORG, orgFlag || undefined, '--org')
+  const retryFlagsResult = setApiRetryFlags({
+    maxRetries: maxRetriesFlag,
+    retryBackoff: retryBackoffFlag,
+    timeout: timeoutFlag,
+  })
+  const endpointFlagsResult = setApiEndpointFlags({
+    apiBaseUrl: apiBaseUrlFlag,
+  })
+  const logFlagsResult = configureLogging({
+    logFile: logFileFlag,
+    logFormat: logFormatFlag,
+    logLevel: logLevelFlag,
+  })
 
   if (getSocketCliNoApiToken()) {
     // This overrides the config override and even the explicit token env var.
@@ -272,11 +326,55 @@ export async function meowWithSubcommands(
     return
   }
 
+  if (!retryFlagsResult.ok) {
+    if (!shouldSuppressBanner(cli1.flags)) {
+      emitBanner(name, orgFlag, compactMode, cli1.flags)
+      // Add newline in stderr.
+      logger.error('')
+    }
+    logger.fail(`${retryFlagsResult.message}: ${retryFlagsResult.cause}`)
+    process.exitCode = 2
+    return
+  }
+
+  if (!endpointFlagsResult.ok) {
+    if (!shouldSuppressBanner(cli1.flags)) {
+      emitBanner(name, orgFlag, compactMode, cli1.flags)
+      // Add newline in stderr.
+      logger.error('')
+    }
+    logger.fail(`${endpointFlagsResult.message}: ${endpointFlagsResult.cause}`)
+    process.exitCode = 2
+    return
+  }
+
+  if (!logFlagsResult.ok) {
+    if (!shouldSuppressBanner(cli1.flags)) {
+      emitBanner(name, orgFlag, compactMode, cli1.flags)
+      // Add newline in stderr.
+      logger.error('')
+    }
+    logger.fail(`${logFlagsResult.message}: ${logFlagsResult.cause}`)
+    process.exitCode = 2
+    return
+  }
+
+  // Spawned package managers and tools get the same proxy and CA certificate.
+  setCaCertFlag(cacertFlag)
+  applyNetworkEnv(getDefaultProxyUrl())
+
   // If we have got some args, then lets find out if we can find a command.
   const dispatched = await tryDispatchSubcommand({
     aliases,
     commandOrAliasName: commandOrAliasName || '',
     defaultSub,
+    externalCommandContext:
+      name === 'socket'
+        ? {
+            orgSlug: orgFlag,
+            outputKind: getOutputKind(jsonFlag, markdownFlag),
+          }
+        : undefined,
     importMeta,
     name,
     rawCommandArgv,
@@ -0,0 +1,95 @@
+/**
+ * The user config file: base64 encoded JSON in the Socket app data dir. See
+ * config.mts for the cache in front of it.
+ */
+
+import { statSync, writeFileSync } from 'node:fs'
+import path from 'node:path'
+
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+import { safeMkdirSync } from '@socketsecurity/lib-stable/fs/safe'
+import { getEditableJsonClass } from '@socketsecurity/lib-stable/json/edit'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+
+import { copyStoredConfig } from './config-keys.mts'
+import { debugConfig } from './debug.mts'
+import { getSocketAppDataPath } from '../constants/paths.mts'
+
+import type { LocalConfig } from './config-keys.mts'
+
+const logger = getDefaultLogger()
+
+export function getConfigFilePath(): string | undefined {
+  const socketAppDataPath = getSocketAppDataPath()
+  return socketAppDataPath
+    ? path.join(socketAppDataPath, 'config.json')
+    : undefined
+}
+
+/**
+ * Decode the contents of the config file at configFilePath. Warns and returns
+ * undefined when they don't parse.
+ */
+export function parseConfigFile(
+  raw: string | Buffer,
+  configFilePath: string,
+): LocalConfig | undefined {
+  let config: LocalConfig | undefined
+  try {
+    const rawString = Buffer.isBuffer(raw) ? raw.toString('utf8') : raw
+    const decoded = Buffer.from(rawString, 'base64').toString('utf8')
+    // Check for invalid UTF-8 sequences (replacement character).
+    if (decoded.includes('\ufffd')) {
+      throw new Error(
+        `SOCKET_CLI_CONFIG contains invalid UTF-8 after base64-decode (replacement-character in output); the env var may have been truncated or double-encoded — re-export it with \`echo '{...}' | base64\``,
+      )
+    }
+    const parsed = JSON.parse(decoded)
+    if (parsed && typeof parsed === 'object') {
+      config = copyStoredConfig(parsed)
+    }
+    debugConfig(configFilePath, true)
+    /* c8 ignore start - config parse failure path; tests pass valid JSON or use empty config */
+  } catch (e) {
+    logger.warn(`Failed to parse config at ${configFilePath}`)
+    debugConfig(configFilePath, false, e)
+  }
+  /* c8 ignore stop */
+  return config
+}
+
+/**
+ * Write localConfig to the config file, keeping the formatting and key order
+ * of the file already there. Returns the mtime of the written file, undefined
+ * when it can't be read back.
+ */
+export function writeConfigFile(
+  configFilePath: string,
+  localConfig: LocalConfig,
+): number | undefined {
+  safeMkdirSync(path.dirname(configFilePath), { recursive: true })
+  // Read existing file to preserve formatting, then update with new values.
+  const existingRaw = safeReadFileSync(configFilePath)
+  const EditableJson = getEditableJsonClass<LocalConfig>()
+  const editor = new EditableJson()
+  if (existingRaw !== undefined) {
+    const rawString = Buffer.isBuffer(existingRaw)
+      ? existingRaw.toString('utf8')
+      : existingRaw
+    try {
+      const decoded = Buffer.from(rawString, 'base64').toString('utf8')
+      editor.fromJSON(decoded)
+    } catch {
+      // If decoding fails, start fresh.
+    }
+  }
+  editor.update(localConfig)
+  const jsonContent = JSON.stringify(editor.content)
+  writeFileSync(configFilePath, Buffer.from(jsonContent).toString('base64'))
+  try {
+    return statSync(configFilePath).mtimeMs
+  } catch {
+    // Keep mtime undefined if stat fails.
+    return undefined
+  }
+}
@@ -0,0 +1,254 @@
+/**
+ * The config keys of the Socket CLI: their types, descriptions and which of
+ * them are booleans, sensitive or kept per profile.
+ *
+ * Supported Config Keys:
+ *
+ * - ActiveProfile: Named auth profile to use
+ * - ApiAuthScheme: How requests send the API token, basic or bearer
+ * - ApiBaseUrl: Socket API endpoint URL
+ * - ApiMaxRetries: Retries of failed API requests
+ * - ApiProxy: Proxy for API requests
+ * - ApiRetryBackoff: Milliseconds before the first retry
+ * - ApiTimeout: Milliseconds before an API request times out
+ * - ApiToken: Authentication token for Socket API
+ * - CaCert: Extra CA certificates to trust
+ * - DefaultOrg/org: Default organization slug
+ * - DisableSelfUpdate: Turn off `socket self-update` in managed environments
+ * - EnforcedOrgs: Organizations with enforced security policies
+ * - Telemetry: Opt in to anonymous usage telemetry
+ *
+ * ApiAuthScheme and apiBaseUrl are defined with the Socket API endpoint, see
+ * socket/api-endpoint.mts. They, apiProxy, apiToken, defaultOrg and
+ * enforcedOrgs are kept per profile.
+ */
+
+import { naturalCompare } from '@socketsecurity/lib-stable/sorts/natural'
+
+import { getApiEndpointConfigEntries } from './socket/api-endpoint.mts'
+import {
+  CONFIG_KEY_ACTIVE_PROFILE,
+  CONFIG_KEY_API_MAX_RETRIES,
+  CONFIG_KEY_API_PROXY,
+  CONFIG_KEY_API_RETRY_BACKOFF,
+  CONFIG_KEY_API_TIMEOUT,
+  CONFIG_KEY_API_TOKEN,
+  CONFIG_KEY_CA_CERT,
+  CONFIG_KEY_DEFAULT_ORG,
+  CONFIG_KEY_DISABLE_SELF_UPDATE,
+  CONFIG_KEY_ENFORCED_ORGS,
+  CONFIG_KEY_ORG,
+  CONFIG_KEY_PROFILES,
+  CONFIG_KEY_TELEMETRY,
+} from '../constants/config.mts'
+
+import type { CResult } from '../types.mts'
+import type {
+  ApiEndpointConfig,
+  ApiEndpointConfigKey,
+} from './socket/api-endpoint.mts'
+
+export interface LocalConfig extends ApiEndpointConfig {
+  activeProfile?: string | undefined
+  // Numbers, or numeric strings when set with `socket config set`.
+  apiMaxRetries?: number | string | undefined
+  apiProxy?: string | null | undefined
+  apiRetryBackoff?: number | string | undefined
+  apiTimeout?: number | string | undefined
+  apiToken?: string | null | undefined
+  caCert?: string | null | undefined
+  defaultOrg?: string | undefined
+  disableSelfUpdate?: boolean | undefined
+  enforcedOrgs?: string[] | readonly string[] | null | undefined
+  skipAskToPersistDefaultOrg?: boolean | undefined
+  // Convenience alias for defaultOrg.
+  org?: string | undefined
+  profiles?: Record<string, ProfileConfig> | undefined
+  telemetry?: boolean | undefined
+}
+
+export type ProfileConfigKey =
+  | ApiEndpointConfigKey
+  | 'apiProxy'
+  | 'apiToken'
+  | 'defaultOrg'
+  | 'enforcedOrgs'
+
+export type ProfileConfig = Pick<LocalConfig, ProfileConfigKey>
+
+export type BooleanConfigKey =
+  | 'disableSelfUpdate'
+  | 'skipAskToPersistDefaultOrg'
+  | 'telemetry'
+
+const apiEndpointConfigEntries = getApiEndpointConfigEntries()
+
+const profileConfigKeyLookup: Set<keyof LocalConfig> = new Set([
+  ...apiEndpointConfigEntries.map(p => p[0]),
+  CONFIG_KEY_API_PROXY,
+  CONFIG_KEY_API_TOKEN,
+  CONFIG_KEY_DEFAULT_ORG,
+  CONFIG_KEY_ENFORCED_ORGS,
+])
+
+const PROFILE_NAME_REGEXP = /^[a-zA-Z0-9][\w.-]{0,63}$/
+
+// Keys stored as booleans, set with the strings "true" and "false".
+const booleanConfigKeyLookup: Set<keyof LocalConfig> = new Set([
+  CONFIG_KEY_DISABLE_SELF_UPDATE,
+  'skipAskToPersistDefaultOrg',
+  CONFIG_KEY_TELEMETRY,
+])
+
+const sensitiveConfigKeyLookup: Set<keyof LocalConfig> = new Set([
+  CONFIG_KEY_API_TOKEN,
+])
+
+const supportedConfig: Map<keyof LocalConfig, string> = new Map([
+  [
+    CONFIG_KEY_ACTIVE_PROFILE,
+    'The named auth profile whose API token, proxy, base URL and orgs are used; set it with `socket config use-profile`',
+  ],
+  ...apiEndpointConfigEntries,
+  [
+    CONFIG_KEY_API_MAX_RETRIES,
+    'How often a failed Socket API request is retried, like --max-retries',
+  ],
+  [CONFIG_KEY_API_PROXY, 'A proxy through which to access the Socket API'],
+  [
+    CONFIG_KEY_API_RETRY_BACKOFF,
+    'Milliseconds to wait before the first retry of a failed Socket API request, doubled for each following retry, like --retry-backoff',
+  ],
+  [
+    CONFIG_KEY_API_TIMEOUT,
+    'Milliseconds after which a Socket API request times out, like --timeout',
+  ],
+  [
+    CONFIG_KEY_API_TOKEN,
+    'The Socket API token required to access most Socket API endpoints',
+  ],
+  [
+    CONFIG_KEY_CA_CERT,
+    'Path to a PEM file of extra CA certificates to trust, e.g. of a TLS-inspecting corporate proxy',
+  ],
+  [
+    CONFIG_KEY_DEFAULT_ORG,
+    'The default org slug to use; usually the org your Socket API token has access to. When set, all orgSlug arguments are implied to be this value.',
+  ],
+  [
+    CONFIG_KEY_DISABLE_SELF_UPDATE,
+    'Set to true to turn off `socket self-update` and update notifications, e.g. where an administrator manages the Socket CLI',
+  ],
+  [
+    CONFIG_KEY_ENFORCED_ORGS,
+    'Orgs in this list have their security policies enforced on this machine',
+  ],
+  [
+    'skipAskToPersistDefaultOrg',
+    'This flag prevents the Socket CLI from asking you to persist the org slug when you selected one interactively',
+  ],
+  [CONFIG_KEY_ORG, 'Alias for defaultOrg'],
+  [
+    CONFIG_KEY_TELEMETRY,
+    'Set to true to send anonymous usage telemetry (command names, durations and error classes) to Socket; see `socket telemetry status`',
+  ],
+])
+
+const supportedConfigEntries = [...supportedConfig.entries()].toSorted((a, b) =>
+  naturalCompare(a[0], b[0]),
+)
+const supportedConfigKeys = supportedConfigEntries.map(p => p[0])
+
+/**
+ * Copy the keys of a parsed config object we know about. Only supported keys
+ * and well-formed profiles are copied, to prevent prototype pollution.
+ */
+export function copyStoredConfig(
+  source: Record<string, unknown>,
+): LocalConfig {
+  const config: LocalConfig = {}
+  const keys = Object.keys(source)
+  for (let i = 0, { length } = keys; i < length; i += 1) {
+    const key = keys[i]!
+    if (isSupportedConfigKey(key)) {
+      ;(config as Record<string, unknown>)[key] = source[key]
+    } else if (key === CONFIG_KEY_PROFILES) {
+      const profiles = copyStoredProfiles(source[key])
+      if (profiles) {
+        config.profiles = profiles
+      }
+    }
+  }
+  return config
+}
+
+export function copyStoredProfiles(
+  value: unknown,
+): Record<string, ProfileConfig> | undefined {
+  if (!value || typeof value !== 'object' || Array.isArray(value)) {
+    return undefined
+  }
+  const profiles: Record<string, ProfileConfig> = {}
+  const entries = Object.entries(value as Record<string, unknown>)
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const { 0: name, 1: profile } = entries[i]!
+    if (!isValidProfileName(name) || !profile || typeof profile !== 'object') {
+      continue
+    }
+    const copy: ProfileConfig = {}
+    const keys = Object.keys(profile)
+    for (let j = 0, { length: keyCount } = keys; j < keyCount; j += 1) {
+      const key = keys[j]!
+      if (isProfileConfigKey(key)) {
+        ;(copy as Record<string, unknown>)[key] = (
+          profile as Record<string, unknown>
+        )[key]
+      }
+    }
+    profiles[name] = copy
+  }
+  return profiles
+}
+
+export function getSupportedConfigEntries() {
+  return [...supportedConfigEntries]
+}
+
+export function getSupportedConfigKeys() {
+  return [...supportedConfigKeys]
+}
+
+export function isBooleanConfigKey(key: string): key is BooleanConfigKey {
+  return booleanConfigKeyLookup.has(key as keyof LocalConfig)
+}
+
+export function isProfileConfigKey(key: string): key is ProfileConfigKey {
+  return profileConfigKeyLookup.has(key as keyof LocalConfig)
+}
+
+export function isSensitiveConfigKey(key: string): key is keyof LocalConfig {
+  return sensitiveConfigKeyLookup.has(key as keyof LocalConfig)
+}
+
+export function isSupportedConfigKey(key: string): key is keyof LocalConfig {
+  return supportedConfig.has(key as keyof LocalConfig)
+}
+
+export function isValidProfileName(name: string): boolean {
+  return PROFILE_NAME_REGEXP.test(name)
+}
+
+export function normalizeConfigKey(
+  key: keyof LocalConfig,
+): CResult<keyof LocalConfig> {
+  // Note: `org` is a convenience alias for `defaultOrg`
+  const normalizedKey = key === CONFIG_KEY_ORG ? CONFIG_KEY_DEFAULT_ORG : key
+  if (!isSupportedConfigKey(normalizedKey)) {
+    return {
+      ok: false,
+      message: `Invalid config key: ${String(normalizedKey)}`,
+      data: undefined,
+    }
+  }
+  return { ok: true, data: normalizedKey }
+}
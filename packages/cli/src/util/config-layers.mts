@@ -0,0 +1,326 @@
+/**
+ * The layers a config value is resolved through, highest priority first:
+ *
+ * 1. Flags: command flags such as --org
+ * 2. Env: environment variables such as SOCKET_CLI_ORG_SLUG and
+ *    SOCKET_CLI_API_TOKEN
+ * 3. Repo: the `cli` section of the socket.yml files found walking up from
+ *    the working directory, nested ones winning. A repository can only set
+ *    defaultOrg, never tokens, URLs or certificates.
+ * 4. User: the persisted config file (base64 encoded JSON), or the --config
+ *    flag or SOCKET_CLI_CONFIG standing in for it, read by config.mts
+ * 5. System: plain JSON an administrator provides, see
+ *    getSocketSystemConfigPath()
+ *
+ * Each key is resolved on its own, so a value from a lower layer applies until
+ * a higher layer sets that key. The user layer is passed in by config.mts,
+ * which exposes the resolved values through getConfigValue() and friends.
+ */
+
+import { existsSync } from 'node:fs'
+import path from 'node:path'
+
+import { debugDirNs, debugNs } from '@socketsecurity/lib-stable/debug/output'
+import {
+  getSocketCliApiProxy,
+  getSocketCliOrgSlug,
+} from '@socketsecurity/lib-stable/env/socket-cli'
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { normalizePath } from '@socketsecurity/lib-stable/paths/normalize'
+
+import { copyStoredConfig } from './config-keys.mts'
+import { debugConfig } from './debug.mts'
+import { getErrorCause } from './error/errors.mts'
+import { getApiEndpointConfigEnv } from './socket/api-endpoint.mts'
+import { mergeSocketYml, parseSocketConfig } from './socket-yaml.mts'
+import {
+  CONFIG_KEY_ACTIVE_PROFILE,
+  CONFIG_KEY_API_PROXY,
+  CONFIG_KEY_API_TOKEN,
+  CONFIG_KEY_DEFAULT_ORG,
+  CONFIG_KEY_DISABLE_SELF_UPDATE,
+} from '../constants/config.mts'
+import { getSocketSystemConfigPath } from '../constants/paths.mts'
+import { SOCKET_YAML, SOCKET_YML } from '../constants/socket.mts'
+import { getSocketCliNoSelfUpdate } from '../env/socket-cli-no-self-update.mts'
+import { getSocketCliProfile } from '../env/socket-cli-profile.mts'
+
+import type { CResult } from '../types.mts'
+import type { LocalConfig } from './config-keys.mts'
+import type { SocketYml } from './socket-yaml.mts'
+
+const logger = getDefaultLogger()
+
+export type ConfigSource = 'env' | 'flag' | 'repo' | 'system' | 'user'
+
+export type ConfigValueSource<
+  Key extends keyof LocalConfig = keyof LocalConfig,
+> = {
+  // The flag, env var or file that set the value.
+  origin: string
+  source: ConfigSource
+  value: LocalConfig[Key]
+}
+
+export type FoundSocketYml = {
+  // The nearest socket.yml.
+  path: string
+  // Every socket.yml found walking up, merged.
+  parsed: SocketYml
+  // The merged files, outermost first.
+  paths: string[]
+}
+
+export type LayerConfig = {
+  config: LocalConfig
+  path: string
+}
+
+// Env vars setting a config key. The API token is handed over through
+// setConfigApiTokenOverride() instead, so SOCKET_CLI_NO_API_TOKEN can veto it.
+const envConfigLookup: Map<keyof LocalConfig, [string, () => unknown]> =
+  new Map([
+    [CONFIG_KEY_ACTIVE_PROFILE, ['SOCKET_CLI_PROFILE', getSocketCliProfile]],
+    ...getApiEndpointConfigEnv(),
+    [CONFIG_KEY_API_PROXY, ['SOCKET_CLI_API_PROXY', getSocketCliApiProxy]],
+    [CONFIG_KEY_DEFAULT_ORG, ['SOCKET_CLI_ORG_SLUG', getSocketCliOrgSlug]],
+    [
+      CONFIG_KEY_DISABLE_SELF_UPDATE,
+      [
+        'SOCKET_CLI_NO_SELF_UPDATE',
+        () => getSocketCliNoSelfUpdate() || undefined,
+      ],
+    ],
+  ])
+
+// The API token from the environment, see setConfigApiTokenOverride().
+let apiTokenOverride: ConfigValueSource<'apiToken'> | undefined
+
+// Config keys set by command flags, see setConfigFlagValue().
+const flagConfig: Map<keyof LocalConfig, ConfigValueSource> = new Map()
+
+let cachedRepoConfig:
+  | { cwd: string; found: LayerConfig | undefined }
+  | undefined
+
+// Null when there is no system config.
+let cachedSystemConfig: LayerConfig | null | undefined
+
+/**
+ * Find the socket.yml files that apply to dir and merge them, so a
+ * monorepo package inherits the repo root config and overrides parts of it
+ * (see mergeSocketYml). The walk stops after the repository root, the
+ * directory holding `.git`, or at a file with `inherit: false`.
+ */
+export function findSocketYmlSync(
+  dir = process.cwd(),
+): CResult<FoundSocketYml | undefined> {
+  const found: Array<{ path: string; parsed: SocketYml }> = []
+  let prevDir = undefined
+  while (dir !== prevDir) {
+    let ymlPath = path.join(dir, SOCKET_YML)
+    let yml = safeReadFileSync(ymlPath)
+    if (yml === undefined) {
+      ymlPath = path.join(dir, SOCKET_YAML)
+      yml = safeReadFileSync(ymlPath)
+    }
+    if (yml !== undefined) {
+      try {
+        const ymlString = Buffer.isBuffer(yml) ? yml.toString('utf8') : yml
+        found.push({ path: ymlPath, parsed: parseSocketConfig(ymlString) })
+      } catch (e) {
+        debugNs('error', `Failed to parse config file: ${ymlPath}`)
+        debugDirNs('error', e)
+        return {
+          ok: false,
+          message: `Found file but was unable to parse ${ymlPath}`,
+          cause: getErrorCause(e),
+        }
+      }
+      if (found.at(-1)!.parsed.inherit === false) {
+        break
+      }
+    }
+    if (found.length && existsSync(path.join(dir, '.git'))) {
+      break
+    }
+    prevDir = dir
+    dir = path.join(dir, '..')
+  }
+  if (!found.length) {
+    return { ok: true, data: undefined }
+  }
+  let parsed = found.at(-1)!.parsed
+  for (let i = found.length - 2; i >= 0; i -= 1) {
+    parsed = mergeSocketYml(
+      parsed,
+      found[i]!.parsed,
+      normalizePath(
+        path.relative(
+          path.dirname(found[i + 1]!.path),
+          path.dirname(found[i]!.path),
+        ),
+      ),
+    )
+  }
+  return {
+    ok: true,
+    data: {
+      path: found[0]!.path,
+      parsed,
+      paths: found.map(f => f.path).reverse(),
+    },
+  }
+}
+
+/**
+ * Describe where a value comes from, e.g. `env (SOCKET_CLI_ORG_SLUG)`.
+ */
+export function formatConfigSource(
+  source: ConfigValueSource | undefined,
+): string {
+  return source ? `${source.source} (${source.origin})` : 'none (not set)'
+}
+
+export function getRepoConfig(): LayerConfig | undefined {
+  const cwd = process.cwd()
+  if (cachedRepoConfig?.cwd !== cwd) {
+    const result = findSocketYmlSync(cwd)
+    const found = result.ok ? result.data : undefined
+    cachedRepoConfig = {
+      cwd,
+      found: found?.parsed.cli
+        ? { config: { ...found.parsed.cli }, path: found.path }
+        : undefined,
+    }
+  }
+  return cachedRepoConfig.found
+}
+
+export function getSystemConfig(): LayerConfig | undefined {
+  if (cachedSystemConfig === undefined) {
+    cachedSystemConfig = null
+    const configFilePath = getSocketSystemConfigPath()
+    const raw = safeReadFileSync(configFilePath)
+    if (raw !== undefined) {
+      try {
+        const rawString = Buffer.isBuffer(raw) ? raw.toString('utf8') : raw
+        const parsed = JSON.parse(rawString)
+        if (parsed && typeof parsed === 'object') {
+          const config = copyStoredConfig(parsed)
+          // Profiles are personal, so only the user config has them.
+          delete config.activeProfile
+          delete config.profiles
+          cachedSystemConfig = { config, path: configFilePath }
+        }
+        debugConfig(configFilePath, true)
+      } catch (e) {
+        logger.warn(`Failed to parse config at ${configFilePath}`)
+        debugConfig(configFilePath, false, e)
+      }
+    }
+  }
+  return cachedSystemConfig ?? undefined
+}
+
+/**
+ * The layers setting a normalized key, in the order of the module doc, with
+ * getUserSource reading the user layer. Lazy, so resolving a key stops
+ * reading layers at the first one that sets it.
+ */
+export function* iterateConfigKeySources<Key extends keyof LocalConfig>(
+  key: Key,
+  getUserSource: (key: Key) => ConfigValueSource<Key> | undefined,
+): Generator<ConfigValueSource<Key>> {
+  const fromFlag = flagConfig.get(key)
+  if (fromFlag) {
+    yield fromFlag as ConfigValueSource<Key>
+  }
+  if (key === CONFIG_KEY_API_TOKEN && apiTokenOverride) {
+    yield apiTokenOverride as ConfigValueSource<Key>
+  }
+  const envEntry = envConfigLookup.get(key)
+  if (envEntry) {
+    const { 0: envName, 1: getEnvValue } = envEntry
+    const value = getEnvValue()
+    if (value !== undefined && value !== '') {
+      yield {
+        origin: envName,
+        source: 'env',
+        value: value as LocalConfig[Key],
+      }
+    }
+  }
+  const repoConfig = getRepoConfig()
+  if (repoConfig && repoConfig.config[key] !== undefined) {
+    yield {
+      origin: repoConfig.path,
+      source: 'repo',
+      value: repoConfig.config[key],
+    }
+  }
+  const userSource = getUserSource(key)
+  if (userSource) {
+    yield userSource
+  }
+  const systemConfig = getSystemConfig()
+  if (systemConfig && systemConfig.config[key] !== undefined) {
+    yield {
+      origin: systemConfig.path,
+      source: 'system',
+      value: systemConfig.config[key],
+    }
+  }
+}
+
+/**
+ * Reset the flag, env, repo and system layers for testing purposes.
+ *
+ * @internal
+ */
+export function resetConfigLayersForTesting(): void {
+  apiTokenOverride = undefined
+  flagConfig.clear()
+  cachedRepoConfig = undefined
+  cachedSystemConfig = undefined
+}
+
+/**
+ * Resolve a normalized key through the layers in the order of the module doc.
+ */
+export function resolveConfigKey<Key extends keyof LocalConfig>(
+  key: Key,
+  getUserSource: (key: Key) => ConfigValueSource<Key> | undefined,
+): ConfigValueSource<Key> | undefined {
+  for (const source of iterateConfigKeySources(key, getUserSource)) {
+    return source
+  }
+  return undefined
+}
+
+/**
+ * Use this API token from the environment, or no token at all for an
+ * undefined value of SOCKET_CLI_NO_API_TOKEN, over every config file.
+ */
+export function setConfigApiTokenOverride(
+  source: ConfigValueSource<'apiToken'>,
+): void {
+  apiTokenOverride = source
+}
+
+/**
+ * Let a command flag set a config key for this invocation, or stop doing so
+ * when value is undefined. The flag is not persisted.
+ */
+export function setConfigFlagValue<Key extends keyof LocalConfig>(
+  key: Key,
+  value: LocalConfig[Key],
+  flag: string,
+): void {
+  if (value === undefined) {
+    flagConfig.delete(key)
+  } else {
+    flagConfig.set(key, { origin: flag, source: 'flag', value })
+  }
+}
@@ -1,100 +1,106 @@
 /**
- * Configuration utilities for Socket CLI. Manages CLI configuration including
- * API tokens, org settings, and preferences.
+ * Configuration utilities for Socket CLI. Reads and writes the user config,
+ * holding API tokens, org settings, and preferences.
  *
- * Configuration Hierarchy (highest priority first):
+ * The user config is the persisted config file (base64 encoded JSON), or the
+ * --config flag or SOCKET_CLI_CONFIG standing in for it. It is one layer of
+ * the resolution order in config-layers.mts, so getConfigValue() and friends
+ * return the value of a key resolved through flags, env vars, socket.yml,
+ * the user config and the system config. getConfigValueSource() tells which
+ * layer set the effective value, e.g. for `socket config get --source`.
  *
- * 1. Environment variables (SOCKET_CLI_*)
- * 2. Command-line --config flag
- * 3. Persisted config file (base64 encoded JSON)
+ * The supported keys are listed in config-keys.mts.
  *
- * Supported Config Keys:
+ * Profiles:
  *
- * - ApiBaseUrl: Socket API endpoint URL
- * - ApiProxy: Proxy for API requests
- * - ApiToken: Authentication token for Socket API
- * - DefaultOrg/org: Default organization slug
- * - EnforcedOrgs: Organizations with enforced security policies
+ * Named profiles keep their own API endpoint, proxy, token and orgs under the
+ * `profiles` key. While a profile is active, set through `socket config
+ * use-profile` or SOCKET_CLI_PROFILE, those keys are read from and written to
+ * it instead of the top level of the config.
+ *
+ * An apiToken of KEYCHAIN_API_TOKEN_REF means the token of that profile is
+ * kept in the OS credential manager (see keychain.mts).
  *
  * Key Functions:
  *
- * - FindSocketYmlSync: Locate socket.yml configuration file
  * - GetConfigValue: Retrieve configuration value by key
+ * - GetConfigValueLayers: List every layer that sets a key
+ * - GetConfigValueSource: Tell where the value of a key comes from
  * - OverrideCachedConfig: Apply temporary config overrides
  * - UpdateConfigValue: Persist configuration changes
+ * - UpdateProfileConfigValue: Persist a change to a named profile
  */
 
-import { statSync, writeFileSync } from 'node:fs'
+import { statSync } from 'node:fs'
 import path from 'node:path'
 
-import { debugDirNs, debugNs } from '@socketsecurity/lib-stable/debug/output'
+import { debugNs } from '@socketsecurity/lib-stable/debug/output'
 import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
 import { safeMkdirSync } from '@socketsecurity/lib-stable/fs/safe'
-import { getEditableJsonClass } from '@socketsecurity/lib-stable/json/edit'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 import { naturalCompare } from '@socketsecurity/lib-stable/sorts/natural'
 
-import { debugConfig } from './debug.mts'
-import { parseSocketConfig } from './socket-yaml.mts'
 import {
-  CONFIG_KEY_API_BASE_URL,
-  CONFIG_KEY_API_PROXY,
+  getConfigFilePath,
+  parseConfigFile,
+  writeConfigFile,
+} from './config-file.mts'
+import {
+  copyStoredConfig,
+  isBooleanConfigKey,
+  isProfileConfigKey,
+  isValidProfileName,
+  normalizeConfigKey,
+} from './config-keys.mts'
+import {
+  iterateConfigKeySources,
+  resetConfigLayersForTesting,
+  resolveConfigKey,
+  setConfigApiTokenOverride,
+} from './config-layers.mts'
+import { KEYCHAIN_API_TOKEN_REF, readKeychainToken } from './keychain.mts'
+import {
   CONFIG_KEY_API_TOKEN,
-  CONFIG_KEY_DEFAULT_ORG,
-  CONFIG_KEY_ENFORCED_ORGS,
-  CONFIG_KEY_ORG,
+  DEFAULT_PROFILE_NAME,
 } from '../constants/config.mts'
-import { getSocketAppDataPath } from '../constants/paths.mts'
-import { SOCKET_YAML, SOCKET_YML } from '../constants/socket.mts'
-import { getErrorCause } from './error/errors.mts'
+import { getSocketCliProfile } from '../env/socket-cli-profile.mts'
 
 import type { CResult } from '../types.mts'
-import type { SocketYml } from './socket-yaml.mts'
+import type {
+  BooleanConfigKey,
+  LocalConfig,
+  ProfileConfig,
+  ProfileConfigKey,
+} from './config-keys.mts'
+import type { ConfigValueSource } from './config-layers.mts'
+
+export {
+  getSupportedConfigEntries,
+  getSupportedConfigKeys,
+  isProfileConfigKey,
+  isSensitiveConfigKey,
+  isSupportedConfigKey,
+  isValidProfileName,
+  normalizeConfigKey,
+} from './config-keys.mts'
+export type {
+  LocalConfig,
+  ProfileConfig,
+  ProfileConfigKey,
+} from './config-keys.mts'
+export {
+  findSocketYmlSync,
+  formatConfigSource,
+  setConfigFlagValue,
+} from './config-layers.mts'
+export type {
+  ConfigSource,
+  ConfigValueSource,
+  FoundSocketYml,
+} from './config-layers.mts'
 
 const logger = getDefaultLogger()
 
-export interface LocalConfig {
-  apiBaseUrl?: string | null | undefined
-  apiProxy?: string | null | undefined
-  apiToken?: string | null | undefined
-  defaultOrg?: string | undefined
-  enforcedOrgs?: string[] | readonly string[] | null | undefined
-  skipAskToPersistDefaultOrg?: boolean | undefined
-  // Convenience alias for defaultOrg.
-  org?: string | undefined
-}
-
-const sensitiveConfigKeyLookup: Set<keyof LocalConfig> = new Set([
-  CONFIG_KEY_API_TOKEN,
-])
-
-const supportedConfig: Map<keyof LocalConfig, string> = new Map([
-  [CONFIG_KEY_API_BASE_URL, 'Base URL of the Socket API endpoint'],
-  [CONFIG_KEY_API_PROXY, 'A proxy through which to access the Socket API'],
-  [
-    CONFIG_KEY_API_TOKEN,
-    'The Socket API token required to access most Socket API endpoints',
-  ],
-  [
-    CONFIG_KEY_DEFAULT_ORG,
-    'The default org slug to use; usually the org your Socket API token has access to. When set, all orgSlug arguments are implied to be this value.',
-  ],
-  [
-    CONFIG_KEY_ENFORCED_ORGS,
-    'Orgs in this list have their security policies enforced on this machine',
-  ],
-  [
-    'skipAskToPersistDefaultOrg',
-    'This flag prevents the Socket CLI from asking you to persist the org slug when you selected one interactively',
-  ],
-  [CONFIG_KEY_ORG, 'Alias for defaultOrg'],
-])
-
-const supportedConfigEntries = [...supportedConfig.entries()].toSorted((a, b) =>
-  naturalCompare(a[0], b[0]),
-)
-const supportedConfigKeys = supportedConfigEntries.map(p => p[0])
-
 const MAX_CONFIG_READ_RETRIES = 3
 
 // Ensure export because dist/utils.js is required in src/constants.mts.
@@ -111,59 +117,95 @@ let cachedConfigPath: string | undefined
 // When using --config or SOCKET_CLI_CONFIG, do not persist the config.
 let configFromFlag = false
 
+// The --config flag or SOCKET_CLI_CONFIG, whichever stands in for the file.
+let configOverrideOrigin: string | undefined
+
 let pendingSave = false
 
-export type FoundSocketYml = {
-  path: string
-  parsed: SocketYml
+/**
+ * The user layer of config-layers.mts: the key of the user config, read from
+ * the active profile for profile keys.
+ */
+function getUserConfigSource<Key extends keyof LocalConfig>(
+  key: Key,
+): ConfigValueSource<Key> | undefined {
+  const value = readConfigKey(getConfigValues(), key)
+  return value === undefined
+    ? undefined
+    : {
+        origin: configOverrideOrigin ?? cachedConfigPath ?? 'config',
+        source: 'user',
+        value,
+      }
 }
 
-export function findSocketYmlSync(
-  dir = process.cwd(),
-): CResult<FoundSocketYml | undefined> {
-  let prevDir = undefined
-  while (dir !== prevDir) {
-    let ymlPath = path.join(dir, SOCKET_YML)
-    let yml = safeReadFileSync(ymlPath)
-    if (yml === undefined) {
-      ymlPath = path.join(dir, SOCKET_YAML)
-      yml = safeReadFileSync(ymlPath)
-    }
-    if (yml !== undefined) {
-      try {
-        const ymlString = Buffer.isBuffer(yml) ? yml.toString('utf8') : yml
-        return {
-          ok: true,
-          data: {
-            path: ymlPath,
-            parsed: parseSocketConfig(ymlString),
-          },
-        }
-      } catch (e) {
-        debugNs('error', `Failed to parse config file: ${ymlPath}`)
-        debugDirNs('error', e)
-        return {
-          ok: false,
-          message: `Found file but was unable to parse ${ymlPath}`,
-          cause: getErrorCause(e),
-        }
-      }
-    }
-    prevDir = dir
-    dir = path.join(dir, '..')
+function readConfigKey<Key extends keyof LocalConfig>(
+  localConfig: LocalConfig,
+  key: Key,
+): LocalConfig[Key] {
+  const profileName = isProfileConfigKey(key)
+    ? getActiveProfileName()
+    : undefined
+  const value = profileName
+    ? (getProfileConfig(profileName)?.[
+        key as ProfileConfigKey
+      ] as LocalConfig[Key])
+    : localConfig[key]
+  if (key === CONFIG_KEY_API_TOKEN && value === KEYCHAIN_API_TOKEN_REF) {
+    return readKeychainToken(
+      profileName ?? DEFAULT_PROFILE_NAME,
+    ) as LocalConfig[Key]
   }
-  return { ok: true, data: undefined }
+  return value
+}
+
+/**
+ * Name of the profile in use: SOCKET_CLI_PROFILE, else the `activeProfile`
+ * config value. Undefined when the top-level credentials are in use.
+ */
+export function getActiveProfileName(): string | undefined {
+  const name = getSocketCliProfile() || getConfigValues().activeProfile
+  return name && name !== DEFAULT_PROFILE_NAME ? name : undefined
 }
 
 export function getConfigValue<Key extends keyof LocalConfig>(
   key: Key,
 ): CResult<LocalConfig[Key]> {
-  const localConfig = getConfigValues()
   const keyResult = normalizeConfigKey(key)
   if (!keyResult.ok) {
     return keyResult
   }
-  return { ok: true, data: localConfig[keyResult.data as Key] }
+  return {
+    ok: true,
+    data: resolveConfigKey(keyResult.data as Key, getUserConfigSource)
+      ?.value as LocalConfig[Key],
+  }
+}
+
+/**
+ * Where the effective value of a key comes from, undefined when no layer sets
+ * it or the key is invalid.
+ */
+export function getConfigValueSource<Key extends keyof LocalConfig>(
+  key: Key,
+): ConfigValueSource<Key> | undefined {
+  const keyResult = normalizeConfigKey(key)
+  return keyResult.ok
+    ? resolveConfigKey(keyResult.data as Key, getUserConfigSource)
+    : undefined
+}
+
+/**
+ * Every layer that sets a key, the effective one first. Lets `socket doctor`
+ * point out values that are shadowed by a higher layer.
+ */
+export function getConfigValueLayers<Key extends keyof LocalConfig>(
+  key: Key,
+): Array<ConfigValueSource<Key>> {
+  const keyResult = normalizeConfigKey(key)
+  return keyResult.ok
+    ? [...iterateConfigKeySources(keyResult.data as Key, getUserConfigSource)]
+    : []
 }
 
 // This version squashes errors, returning undefined instead.
@@ -171,25 +213,26 @@ export function getConfigValue<Key extends keyof LocalConfig>(
 export function getConfigValueOrUndef<Key extends keyof LocalConfig>(
   key: Key,
 ): LocalConfig[Key] | undefined {
-  const localConfig = getConfigValues()
   const keyResult = normalizeConfigKey(key)
   if (!keyResult.ok) {
     return undefined
   }
-  return localConfig[keyResult.data as Key]
+  return resolveConfigKey(keyResult.data as Key, getUserConfigSource)?.value
 }
 
+/**
+ * The user config: the persisted file, or the --config flag or
+ * SOCKET_CLI_CONFIG standing in for it. Use getConfigValue() for the value
+ * resolved through all layers.
+ */
 export function getConfigValues(retryCount = 0): LocalConfig {
-  // Order: env var > --config flag > file.
   // If config is from flag/env override, skip file-based caching.
   if (configFromFlag && cachedConfig !== undefined) {
     return cachedConfig
   }
 
-  const socketAppDataPath = getSocketAppDataPath()
-  if (socketAppDataPath) {
-    const configFilePath = path.join(socketAppDataPath, 'config.json')
-
+  const configFilePath = getConfigFilePath()
+  if (configFilePath) {
     try {
       const stats = statSync(configFilePath)
       const currentMtime = stats.mtimeMs
@@ -221,33 +264,7 @@ export function getConfigValues(retryCount = 0): LocalConfig {
         }
 
         if (raw !== undefined) {
-          try {
-            const rawString = Buffer.isBuffer(raw) ? raw.toString('utf8') : raw
-            const decoded = Buffer.from(rawString, 'base64').toString('utf8')
-            // Check for invalid UTF-8 sequences (replacement character).
-            if (decoded.includes('\ufffd')) {
-              throw new Error(
-                `SOCKET_CLI_CONFIG contains invalid UTF-8 after base64-decode (replacement-character in output); the env var may have been truncated or double-encoded — re-export it with \`echo '{...}' | base64\``,
-              )
-            }
-            const parsed = JSON.parse(decoded)
-            // Only copy supported config keys to prevent prototype pollution.
-            if (parsed && typeof parsed === 'object') {
-              const keys = Object.keys(parsed)
-              for (let i = 0, { length } = keys; i < length; i += 1) {
-                const key = keys[i]!
-                if (isSupportedConfigKey(key)) {
-                  ;(cachedConfig as Record<string, unknown>)[key] = parsed[key]
-                }
-              }
-            }
-            debugConfig(configFilePath, true)
-            /* c8 ignore start - config parse failure path; tests pass valid JSON or use empty config */
-          } catch (e) {
-            logger.warn(`Failed to parse config at ${configFilePath}`)
-            debugConfig(configFilePath, false, e)
-          }
-          /* c8 ignore stop */
+          cachedConfig = parseConfigFile(raw, configFilePath) ?? {}
         }
         cachedConfigMtime = currentMtime
         cachedConfigPath = configFilePath
@@ -258,7 +275,7 @@ export function getConfigValues(retryCount = 0): LocalConfig {
         cachedConfig = {}
         cachedConfigMtime = undefined
         cachedConfigPath = configFilePath
-        safeMkdirSync(socketAppDataPath, { recursive: true })
+        safeMkdirSync(path.dirname(configFilePath), { recursive: true })
       }
     }
     /* c8 ignore start - socketAppDataPath undefined fallback; tests always have HOME set so getSocketAppDataPath returns a path */
@@ -269,62 +286,53 @@ export function getConfigValues(retryCount = 0): LocalConfig {
   return cachedConfig
 }
 
-export function getSupportedConfigEntries() {
-  return [...supportedConfigEntries]
-}
-
-export function getSupportedConfigKeys() {
-  return [...supportedConfigKeys]
-}
-
-export function isConfigFromFlag() {
-  return configFromFlag
+export function getProfileConfig(name: string): ProfileConfig | undefined {
+  const { profiles } = getConfigValues()
+  return profiles && Object.hasOwn(profiles, name) ? profiles[name] : undefined
 }
 
-export function isSensitiveConfigKey(key: string): key is keyof LocalConfig {
-  return sensitiveConfigKeyLookup.has(key as keyof LocalConfig)
+export function getProfileNames(): string[] {
+  return Object.keys(getConfigValues().profiles ?? {}).toSorted(naturalCompare)
 }
 
-export function isSupportedConfigKey(key: string): key is keyof LocalConfig {
-  return supportedConfig.has(key as keyof LocalConfig)
+/**
+ * Whether the API token of the profile, DEFAULT_PROFILE_NAME for the top
+ * level, is kept in the OS credential manager.
+ */
+export function isApiTokenInKeychain(profileName: string): boolean {
+  const apiToken =
+    profileName === DEFAULT_PROFILE_NAME
+      ? getConfigValues().apiToken
+      : getProfileConfig(profileName)?.apiToken
+  return apiToken === KEYCHAIN_API_TOKEN_REF
 }
 
-export function normalizeConfigKey(
-  key: keyof LocalConfig,
-): CResult<keyof LocalConfig> {
-  // Note: `org` is a convenience alias for `defaultOrg`
-  const normalizedKey = key === CONFIG_KEY_ORG ? CONFIG_KEY_DEFAULT_ORG : key
-  if (!isSupportedConfigKey(normalizedKey)) {
-    return {
-      ok: false,
-      message: `Invalid config key: ${String(normalizedKey)}`,
-      data: undefined,
-    }
-  }
-  return { ok: true, data: normalizedKey }
+export function isConfigFromFlag() {
+  return configFromFlag
 }
 
-export function overrideCachedConfig(jsonConfig: unknown): CResult<undefined> {
+/**
+ * Use the JSON of the --config flag or SOCKET_CLI_CONFIG, named by origin,
+ * instead of the user config file.
+ */
+export function overrideCachedConfig(
+  jsonConfig: unknown,
+  origin = '--config',
+): CResult<undefined> {
   debugNs('notice', 'override: full config (not stored)')
+  configOverrideOrigin = origin
 
   let config: unknown
   try {
     config = JSON.parse(String(jsonConfig))
-    if (!config || typeof config !== 'object') {
-      // `null` is valid json, so are primitive values.
-      // They're not valid config objects :)
-      return {
-        ok: false,
-        message: 'Could not parse Config as JSON',
-        cause:
-          "Could not JSON parse the config override. Make sure it's a proper JSON object (double-quoted keys and strings, no unquoted `undefined`) and try again.",
-      }
-    }
   } catch {
     // Force set an empty config to prevent accidentally using system settings.
     cachedConfig = {}
     configFromFlag = true
-
+  }
+  // `null` is valid json, so are primitive values.
+  // They're not valid config objects :)
+  if (!config || typeof config !== 'object') {
     return {
       ok: false,
       message: 'Could not parse Config as JSON',
@@ -333,28 +341,27 @@ export function overrideCachedConfig(jsonConfig: unknown): CResult<undefined> {
     }
   }
 
-  // Only copy supported config keys to prevent prototype pollution.
-  cachedConfig = {}
-  const configObj = config as Record<string, unknown>
-  const keys = Object.keys(configObj)
-  for (let i = 0, { length } = keys; i < length; i += 1) {
-    const key = keys[i]!
-    if (isSupportedConfigKey(key)) {
-      ;(cachedConfig as Record<string, unknown>)[key] = configObj[key]
-    }
-  }
+  cachedConfig = copyStoredConfig(config as Record<string, unknown>)
   configFromFlag = true
 
   return { ok: true, data: undefined }
 }
 
+/**
+ * Use the API token of SOCKET_CLI_API_TOKEN, or no token at all for
+ * SOCKET_CLI_NO_API_TOKEN, whatever the config files say. Marks the config
+ * read-only so the token isn't persisted.
+ */
 export function overrideConfigApiToken(apiToken: string | undefined) {
   debugNs('notice', 'override: Socket API token (not stored)')
-  // Set token to the local cached config and mark it read-only so it doesn't persist.
-  cachedConfig = {
-    ...cachedConfig,
-    ...(apiToken === undefined ? {} : { apiToken }),
-  }
+  setConfigApiTokenOverride({
+    origin:
+      apiToken === undefined
+        ? 'SOCKET_CLI_NO_API_TOKEN'
+        : 'SOCKET_CLI_API_TOKEN',
+    source: 'env',
+    value: apiToken,
+  })
   configFromFlag = true
 }
 
@@ -369,25 +376,67 @@ export function resetConfigForTesting(): void {
   cachedConfigMtime = undefined
   cachedConfigPath = undefined
   configFromFlag = false
+  configOverrideOrigin = undefined
+  resetConfigLayersForTesting()
 }
 
 export function updateConfigValue<Key extends keyof LocalConfig>(
   configKey: Key,
   value: LocalConfig[Key],
 ): CResult<undefined | string> {
-  const localConfig = getConfigValues()
   const keyResult = normalizeConfigKey(configKey)
   if (!keyResult.ok) {
     return keyResult
   }
   const key: Key = keyResult.data as Key
+  return writeConfigValue(
+    key,
+    value,
+    isProfileConfigKey(key) ? getActiveProfileName() : undefined,
+  )
+}
+
+/**
+ * Update a key of the named profile, whether or not it is the active one.
+ * Passing DEFAULT_PROFILE_NAME updates the top level of the config.
+ */
+export function updateProfileConfigValue<Key extends ProfileConfigKey>(
+  profileName: string,
+  key: Key,
+  value: LocalConfig[Key],
+): CResult<undefined | string> {
+  if (!isValidProfileName(profileName)) {
+    return {
+      ok: false,
+      message: `Invalid profile name: ${profileName}`,
+      cause:
+        'Profile names start with a letter or digit and may contain letters, digits, `.`, `_` and `-`.',
+    }
+  }
+  return writeConfigValue(
+    key,
+    value,
+    profileName === DEFAULT_PROFILE_NAME ? undefined : profileName,
+  )
+}
+
+function writeConfigValue<Key extends keyof LocalConfig>(
+  key: Key,
+  value: LocalConfig[Key],
+  profileName: string | undefined,
+): CResult<undefined | string> {
+  const localConfig = getConfigValues()
   // Implicitly deleting when serializing.
   let wasDeleted = value === undefined
-  if (key === 'skipAskToPersistDefaultOrg') {
-    if (value === 'false' || value === 'true') {
-      localConfig.skipAskToPersistDefaultOrg = value === 'true'
+  if (isBooleanConfigKey(key)) {
+    const booleanKey: BooleanConfigKey = key
+    // `socket config set` passes the strings, commands the booleans.
+    if (typeof value === 'boolean') {
+      localConfig[booleanKey] = value
+    } else if (value === 'false' || value === 'true') {
+      localConfig[booleanKey] = value === 'true'
     } else {
-      delete localConfig.skipAskToPersistDefaultOrg
+      delete localConfig[booleanKey]
       wasDeleted = true
     }
   } else {
@@ -400,13 +449,27 @@ export function updateConfigValue<Key extends keyof LocalConfig>(
         `Note: The value is set to "${raw}", as a string (!). Use \`socket config unset\` to reset a key.`,
       )
     }
-    localConfig[key] = value
+    if (profileName) {
+      const profiles = { ...localConfig.profiles }
+      const profile: ProfileConfig = {
+        ...(Object.hasOwn(profiles, profileName)
+          ? profiles[profileName]
+          : undefined),
+      }
+      ;(profile as Record<string, unknown>)[key] = value
+      profiles[profileName] = profile
+      localConfig.profiles = profiles
+    } else {
+      localConfig[key] = value
+    }
   }
 
+  const message = `Config key '${key}'${profileName ? ` of profile '${profileName}'` : ''} was ${wasDeleted ? 'deleted' : 'updated'}`
+
   if (configFromFlag) {
     return {
       ok: true,
-      message: `Config key '${key}' was ${wasDeleted ? 'deleted' : 'updated'}`,
+      message,
       data: 'Change applied but not persisted; current config is overridden through env var or flag',
     }
   }
@@ -415,48 +478,18 @@ export function updateConfigValue<Key extends keyof LocalConfig>(
     pendingSave = true
     process.nextTick(() => {
       pendingSave = false
-      const socketAppDataPath = getSocketAppDataPath()
-      if (socketAppDataPath) {
-        safeMkdirSync(socketAppDataPath, { recursive: true })
-        const configFilePath = path.join(socketAppDataPath, 'config.json')
-        // Read existing file to preserve formatting, then update with new values.
-        const existingRaw = safeReadFileSync(configFilePath)
-        const EditableJson = getEditableJsonClass<LocalConfig>()
-        const editor = new EditableJson()
-        if (existingRaw !== undefined) {
-          const rawString = Buffer.isBuffer(existingRaw)
-            ? existingRaw.toString('utf8')
-            : existingRaw
-          try {
-            const decoded = Buffer.from(rawString, 'base64').toString('utf8')
-            editor.fromJSON(decoded)
-          } catch {
-            // If decoding fails, start fresh.
-          }
-        }
-        editor.update(localConfig)
-        const jsonContent = JSON.stringify(editor.content)
-        writeFileSync(
-          configFilePath,
-          Buffer.from(jsonContent).toString('base64'),
-        )
-        // Invalidate mtime cache AFTER write completes to prevent stale reads.
-        cachedConfigMtime = undefined
-        // Update mtime cache with new value.
-        try {
-          const stats = statSync(configFilePath)
-          cachedConfigMtime = stats.mtimeMs
-          cachedConfigPath = configFilePath
-        } catch {
-          // Keep mtime undefined if stat fails.
-        }
+      const configFilePath = getConfigFilePath()
+      if (configFilePath) {
+        // Take the mtime of the write, so the next read doesn't reload it.
+        cachedConfigMtime = writeConfigFile(configFilePath, localConfig)
+        cachedConfigPath = configFilePath
       }
     })
   }
 
   return {
     ok: true,
-    message: `Config key '${key}' was ${wasDeleted ? 'deleted' : 'updated'}`,
+    message,
     data: undefined,
   }
 }
@@ -0,0 +1,148 @@
+/**
+ * Layer flattening for container images.
+ *
+ * Layers are applied in order the way an overlay filesystem would: later
+ * layers replace files, `.wh.<name>` whiteouts delete a path from the lower
+ * layers and `.wh..wh..opq` makes a directory opaque. Only files accepted by
+ * the include filter are kept in memory.
+ */
+
+import path from 'node:path'
+
+import { decompressLayer, readTarStream } from './tar.mts'
+
+import type { ContainerImage } from './image.mts'
+import type { TarEntry, TarStreamEntry } from './tar.mts'
+
+export type ContainerFiles = Map<string, Buffer>
+
+const WHITEOUT_PREFIX = '.wh.'
+
+const OPAQUE_WHITEOUT = '.wh..wh..opq'
+
+// Installed package databases can run to a few megabytes; anything larger is
+// not a manifest.
+const MAX_CONTAINER_FILE_SIZE = 32 * 1024 * 1024
+
+/**
+ * Normalize a layer entry name to a root relative posix path, e.g.
+ * `./usr/lib/os-release` -> `usr/lib/os-release`.
+ */
+export function normalizeLayerPath(name: string): string {
+  return path.posix
+    .normalize(`/${name}`)
+    .replace(/^\/+/, '')
+    .replace(/\/+$/, '')
+}
+
+function deleteTree(files: ContainerFiles, dir: string, keepDir: boolean) {
+  const prefix = dir ? `${dir}/` : ''
+  for (const filepath of [...files.keys()]) {
+    if ((!keepDir && filepath === dir) || filepath.startsWith(prefix)) {
+      files.delete(filepath)
+    }
+  }
+}
+
+/**
+ * Apply one uncompressed layer tar stream on top of files. Only the data of
+ * files accepted by include is read from the stream.
+ */
+export async function applyLayerTar(
+  files: ContainerFiles,
+  layerTar: AsyncIterable<Buffer>,
+  include: (filepath: string) => boolean,
+): Promise<void> {
+  const isKept = (entry: TarEntry) =>
+    entry.type === 'file' &&
+    entry.size <= MAX_CONTAINER_FILE_SIZE &&
+    include(normalizeLayerPath(entry.name))
+  // Whiteouts hide lower layers only, so they go first in case the layer
+  // also adds files to the path it whites out.
+  const rest: TarStreamEntry[] = []
+  for await (const streamEntry of readTarStream(layerTar, isKept)) {
+    const filepath = normalizeLayerPath(streamEntry.entry.name)
+    const basename = path.posix.basename(filepath)
+    if (basename === OPAQUE_WHITEOUT) {
+      deleteTree(files, path.posix.dirname(filepath).replace(/^\.$/, ''), true)
+    } else if (basename.startsWith(WHITEOUT_PREFIX)) {
+      deleteTree(
+        files,
+        path.posix.join(
+          path.posix.dirname(filepath),
+          basename.slice(WHITEOUT_PREFIX.length),
+        ),
+        false,
+      )
+    } else {
+      rest.push(streamEntry)
+    }
+  }
+  for (let i = 0, { length } = rest; i < length; i += 1) {
+    const { data, entry } = rest[i]!
+    const filepath = normalizeLayerPath(entry.name)
+    if (!filepath || entry.type === 'directory') {
+      continue
+    }
+    if (data) {
+      files.set(filepath, Buffer.from(data))
+      continue
+    }
+    if (entry.type === 'hardlink' && include(filepath)) {
+      const target = files.get(normalizeLayerPath(entry.linkname))
+      if (target) {
+        files.set(filepath, target)
+        continue
+      }
+    }
+    // Anything else (symlinks, excluded or oversized files) still replaces a
+    // file from a lower layer.
+    files.delete(filepath)
+  }
+}
+
+/**
+ * Decompress and apply a layer blob. Resolves to false for layers in an
+ * unsupported compression.
+ */
+export async function applyLayer(
+  files: ContainerFiles,
+  blob: Buffer,
+  include: (filepath: string) => boolean,
+): Promise<boolean> {
+  const layerTar = decompressLayer(blob)
+  if (!layerTar) {
+    return false
+  }
+  await applyLayerTar(files, layerTar, include)
+  return true
+}
+
+export type ContainerFilesResult = {
+  files: ContainerFiles
+  // Digests of layers in an unsupported compression.
+  skippedLayers: string[]
+}
+
+/**
+ * Flatten the layers of an image into the files accepted by include.
+ */
+export async function readContainerFiles(
+  image: ContainerImage,
+  include: (filepath: string) => boolean,
+  onLayer?: ((index: number, total: number) => void) | undefined,
+): Promise<ContainerFilesResult> {
+  const files: ContainerFiles = new Map()
+  const skippedLayers: string[] = []
+  const { layers } = image
+  for (let i = 0, { length } = layers; i < length; i += 1) {
+    const layer = layers[i]!
+    onLayer?.(i, length)
+    // Layers must be applied in order.
+    const blob = await layer.read()
+    if (!(await applyLayer(files, blob, include))) {
+      skippedLayers.push(layer.digest)
+    }
+  }
+  return { files, skippedLayers }
+}
@@ -0,0 +1,149 @@
+/**
+ * OCI and Docker image manifests and indexes: the media types a registry is
+ * asked for, and the resolution of a multi-platform index to the image
+ * manifest of one platform, `linux/amd64` unless another platform is
+ * requested. Attestation manifests are never picked.
+ */
+
+import type { CResult } from '../../types.mts'
+
+export type ImagePlatform = {
+  architecture?: string | undefined
+  os?: string | undefined
+  variant?: string | undefined
+}
+
+export type OciDescriptor = {
+  annotations?: Record<string, string> | undefined
+  digest: string
+  mediaType?: string | undefined
+  platform?: ImagePlatform | undefined
+}
+
+export type OciManifest = {
+  config?: OciDescriptor | undefined
+  layers?: OciDescriptor[] | undefined
+  manifests?: OciDescriptor[] | undefined
+  mediaType?: string | undefined
+}
+
+export const DEFAULT_IMAGE_PLATFORM = 'linux/amd64'
+
+export const MANIFEST_ACCEPT = [
+  'application/vnd.oci.image.index.v1+json',
+  'application/vnd.oci.image.manifest.v1+json',
+  'application/vnd.docker.distribution.manifest.list.v2+json',
+  'application/vnd.docker.distribution.manifest.v2+json',
+].join(', ')
+
+function parsePlatform(platform: string): ImagePlatform {
+  const [os, architecture, variant] = platform.split('/')
+  return { os, architecture, variant }
+}
+
+export function formatPlatform(platform: ImagePlatform | undefined): string {
+  return [platform?.os, platform?.architecture, platform?.variant]
+    .filter(Boolean)
+    .join('/')
+}
+
+function isImageManifestDescriptor(descriptor: OciDescriptor): boolean {
+  return (
+    descriptor.platform?.os !== 'unknown' &&
+    descriptor.annotations?.['vnd.docker.reference.type'] !==
+      'attestation-manifest'
+  )
+}
+
+export function listPlatforms(manifests: OciDescriptor[]): string {
+  return (
+    manifests
+      .filter(isImageManifestDescriptor)
+      .map(m => formatPlatform(m.platform))
+      .filter(Boolean)
+      .join(', ') || 'none'
+  )
+}
+
+/**
+ * Pick the manifest for a platform from an image index. Attestation
+ * manifests (`unknown/unknown`) are never picked.
+ */
+export function selectPlatformManifest(
+  manifests: OciDescriptor[],
+  platform: string = DEFAULT_IMAGE_PLATFORM,
+): OciDescriptor | undefined {
+  const wanted = parsePlatform(platform)
+  return manifests.filter(isImageManifestDescriptor).find(
+    m =>
+      m.platform?.os === wanted.os &&
+      m.platform?.architecture === wanted.architecture &&
+      (!wanted.variant || m.platform?.variant === wanted.variant),
+  )
+}
+
+export function isImageIndex(manifest: OciManifest): boolean {
+  return (
+    Array.isArray(manifest.manifests) &&
+    !Array.isArray(manifest.layers) &&
+    !manifest.mediaType?.endsWith('.manifest.v1+json') &&
+    !manifest.mediaType?.endsWith('.manifest.v2+json')
+  )
+}
+
+export type ResolvedImageManifest = {
+  // The index entry the manifest was picked by, if it came from an index.
+  descriptor?: OciDescriptor | undefined
+  manifest: OciManifest
+  // Image name from the index annotations.
+  name?: string | undefined
+}
+
+/**
+ * Resolve an image index to the image manifest for a platform. Indexes may
+ * nest, e.g. an OCI layout whose single entry is itself a multi-platform
+ * index. A manifest that is not an index resolves to itself.
+ */
+export function resolveImageIndex(
+  index: OciManifest,
+  readManifest: (digest: string) => OciManifest | undefined,
+  platform?: string | undefined,
+): CResult<ResolvedImageManifest> {
+  let manifest: OciManifest = index
+  let descriptor: OciDescriptor | undefined
+  let name: string | undefined
+  for (let depth = 0; depth < 4 && isImageIndex(manifest); depth += 1) {
+    const manifests = manifest.manifests ?? []
+    descriptor =
+      manifests.length === 1 && !manifests[0]!.platform
+        ? manifests[0]
+        : selectPlatformManifest(manifests, platform)
+    if (!descriptor) {
+      return {
+        ok: false,
+        message: 'Image platform not found',
+        cause: `no manifest for platform ${platform ?? DEFAULT_IMAGE_PLATFORM}; available: ${listPlatforms(manifests)}`,
+      }
+    }
+    name ??=
+      descriptor.annotations?.['io.containerd.image.name'] ??
+      descriptor.annotations?.['org.opencontainers.image.ref.name']
+    const next = readManifest(descriptor.digest)
+    if (!next) {
+      return {
+        ok: false,
+        message: 'Image manifest not found',
+        cause: `manifest blob ${descriptor.digest} is missing`,
+      }
+    }
+    manifest = next
+  }
+  return {
+    ok: true,
+    data: {
+      ...(descriptor ? { descriptor } : {}),
+      manifest,
+      ...(name ? { name } : {}),
+    },
+  }
+}
@@ -0,0 +1,450 @@
+/**
+ * Container image loading for `socket container scan`.
+ *
+ * Key Functions: - parseImageReference: Split `registry/repository:tag@digest`
+ * with Docker Hub defaults - loadImageArchive: Read a `docker save` tarball or
+ * an OCI image layout tarball - pullRegistryImage: Fetch an image from an OCI
+ * distribution registry, authenticating with anonymous bearer tokens or the
+ * credentials stored by `docker login`.
+ *
+ * Multi-platform images resolve to one platform manifest (see
+ * `./image-manifest.mts`). Layers are returned in application order and
+ * read lazily so only one layer is in memory at a time.
+ */
+
+import { existsSync, readFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import {
+  DEFAULT_IMAGE_PLATFORM,
+  MANIFEST_ACCEPT,
+  formatPlatform,
+  isImageIndex,
+  listPlatforms,
+  resolveImageIndex,
+  selectPlatformManifest,
+} from './image-manifest.mts'
+import { openTarFile } from './tar.mts'
+import { getErrorCause } from '../error/errors.mts'
+import { socketHttpRequest } from '../socket/api-http.mts'
+
+import type {
+  ImagePlatform,
+  OciDescriptor,
+  OciManifest,
+} from './image-manifest.mts'
+import type { TarEntry } from './tar.mts'
+import type { CResult } from '../../types.mts'
+
+export type ImageReference = {
+  registry: string
+  repository: string
+  tag?: string | undefined
+  digest?: string | undefined
+}
+
+export type ContainerImageLayer = {
+  digest: string
+  read: () => Promise<Buffer>
+}
+
+export type ContainerImage = {
+  close: () => void
+  // Config (image id) digest when known.
+  digest?: string | undefined
+  layers: ContainerImageLayer[]
+  // Human readable image name, e.g. `nginx:1.25`.
+  name: string
+  platform?: string | undefined
+}
+
+type DockerSaveManifest = {
+  Config?: string | undefined
+  Layers?: string[] | undefined
+  RepoTags?: string[] | null | undefined
+}
+
+const DOCKER_HUB_REGISTRY = 'docker.io'
+
+const DOCKER_HUB_API_HOST = 'registry-1.docker.io'
+
+/**
+ * Split an image reference, e.g. `nginx`, `ghcr.io/org/app:1.0` or
+ * `localhost:5000/app@sha256:...`, applying the Docker Hub defaults.
+ */
+export function parseImageReference(ref: string): ImageReference | undefined {
+  let rest = ref.trim()
+  let digest: string | undefined
+  const atIndex = rest.indexOf('@')
+  if (atIndex !== -1) {
+    digest = rest.slice(atIndex + 1)
+    rest = rest.slice(0, atIndex)
+  }
+  let tag: string | undefined
+  const colonIndex = rest.lastIndexOf(':')
+  if (colonIndex > rest.lastIndexOf('/')) {
+    tag = rest.slice(colonIndex + 1)
+    rest = rest.slice(0, colonIndex)
+  }
+  const slashIndex = rest.indexOf('/')
+  const firstPart = slashIndex === -1 ? '' : rest.slice(0, slashIndex)
+  const hasRegistry =
+    firstPart.includes('.') ||
+    firstPart.includes(':') ||
+    firstPart === 'localhost'
+  const registry = hasRegistry ? firstPart : DOCKER_HUB_REGISTRY
+  let repository = hasRegistry ? rest.slice(slashIndex + 1) : rest
+  if (
+    !repository ||
+    !/^[a-z0-9]+(?:[._-][a-z0-9]+)*(?:\/[a-z0-9]+(?:[._-][a-z0-9]+)*)*$/.test(
+      repository,
+    ) ||
+    (digest !== undefined && !/^[a-z0-9]+:[a-f0-9]+$/.test(digest))
+  ) {
+    return undefined
+  }
+  if (registry === DOCKER_HUB_REGISTRY && !repository.includes('/')) {
+    repository = `library/${repository}`
+  }
+  return {
+    registry,
+    repository,
+    ...(tag || !digest ? { tag: tag || 'latest' } : {}),
+    ...(digest ? { digest } : {}),
+  }
+}
+
+export function formatImageReference({
+  digest,
+  registry,
+  repository,
+  tag,
+}: ImageReference): string {
+  const name =
+    registry === DOCKER_HUB_REGISTRY
+      ? repository.replace(/^library\//, '')
+      : `${registry}/${repository}`
+  return `${name}${tag ? `:${tag}` : ''}${digest && !tag ? `@${digest}` : ''}`
+}
+
+function getBlobArchivePath(digest: string): string {
+  return `blobs/${digest.replace(':', '/')}`
+}
+
+function normalizeArchivePath(name: string): string {
+  return path.posix.normalize(name).replace(/^(?:\.\/|\/)+/, '')
+}
+
+/**
+ * Load an image from a `docker save` tarball (`manifest.json`) or an OCI
+ * image layout tarball (`index.json` + `blobs/`).
+ */
+export function loadImageArchive(
+  filepath: string,
+  options?: { platform?: string | undefined } | undefined,
+): CResult<ContainerImage> {
+  const { platform } = { __proto__: null, ...options } as {
+    platform?: string | undefined
+  }
+  let tar: ReturnType<typeof openTarFile>
+  try {
+    tar = openTarFile(filepath)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Unable to read image archive',
+      cause: `${filepath}: ${getErrorCause(e)}`,
+    }
+  }
+  const entries = new Map<string, TarEntry>()
+  for (const entry of tar.entries) {
+    if (entry.type === 'file') {
+      entries.set(normalizeArchivePath(entry.name), entry)
+    }
+  }
+  const readJson = (name: string): unknown => {
+    const entry = entries.get(normalizeArchivePath(name))
+    return entry ? JSON.parse(tar.read(entry).toString('utf8')) : undefined
+  }
+  const toLayer = (name: string, digest: string): ContainerImageLayer => ({
+    digest,
+    read: async () => {
+      const entry = entries.get(normalizeArchivePath(name))
+      if (!entry) {
+        throw new Error(`layer ${name} is missing from the archive`)
+      }
+      return tar.read(entry)
+    },
+  })
+  const fail = (cause: string): CResult<ContainerImage> => {
+    tar.close()
+    return { ok: false, message: 'Unsupported image archive', cause }
+  }
+
+  try {
+    const dockerManifests = readJson('manifest.json') as
+      | DockerSaveManifest[]
+      | undefined
+    if (Array.isArray(dockerManifests) && dockerManifests.length) {
+      const manifest = dockerManifests[0]!
+      const config = manifest.Config ? readJson(manifest.Config) : undefined
+      const configPlatform = config as ImagePlatform | undefined
+      return {
+        ok: true,
+        data: {
+          close: tar.close,
+          ...(manifest.Config
+            ? {
+                digest: `sha256:${path.posix.basename(manifest.Config).replace(/\.json$/, '')}`,
+              }
+            : {}),
+          layers: (manifest.Layers ?? []).map(layer =>
+            toLayer(
+              layer,
+              layer.startsWith('blobs/')
+                ? layer.slice('blobs/'.length).replace('/', ':')
+                : layer,
+            ),
+          ),
+          name:
+            manifest.RepoTags?.[0] ||
+            path.basename(filepath).replace(/\.tar(?:\.gz)?$/, ''),
+          ...(configPlatform?.os
+            ? { platform: formatPlatform(configPlatform) }
+            : {}),
+        },
+      }
+    }
+
+    const index = readJson('index.json') as OciManifest | undefined
+    if (!index) {
+      return fail(
+        `${filepath} has neither a manifest.json nor an index.json; expected a \`docker save\` or OCI layout tarball`,
+      )
+    }
+    const resolvedCResult = resolveImageIndex(
+      index,
+      digest =>
+        readJson(getBlobArchivePath(digest)) as OciManifest | undefined,
+      platform,
+    )
+    if (!resolvedCResult.ok) {
+      return fail(resolvedCResult.cause ?? resolvedCResult.message)
+    }
+    const { descriptor, manifest, name } = resolvedCResult.data
+    return {
+      ok: true,
+      data: {
+        close: tar.close,
+        ...(manifest.config?.digest ? { digest: manifest.config.digest } : {}),
+        layers: (manifest.layers ?? []).map(layer =>
+          toLayer(getBlobArchivePath(layer.digest), layer.digest),
+        ),
+        name: name || path.basename(filepath).replace(/\.tar(?:\.gz)?$/, ''),
+        ...(descriptor?.platform
+          ? { platform: formatPlatform(descriptor.platform) }
+          : {}),
+      },
+    }
+  } catch (e) {
+    return fail(getErrorCause(e))
+  }
+}
+
+/**
+ * Basic credentials stored by `docker login` in `~/.docker/config.json`.
+ * Credential helpers are not consulted.
+ */
+export function readDockerCredentials(registry: string): string | undefined {
+  const configDir =
+    process.env['DOCKER_CONFIG'] || path.join(os.homedir(), '.docker')
+  const configPath = path.join(configDir, 'config.json')
+  if (!existsSync(configPath)) {
+    return undefined
+  }
+  try {
+    const config = JSON.parse(readFileSync(configPath, 'utf8')) as {
+      auths?: Record<string, { auth?: string | undefined }> | undefined
+    }
+    const keys =
+      registry === DOCKER_HUB_REGISTRY
+        ? ['https://index.docker.io/v1/', 'index.docker.io', DOCKER_HUB_REGISTRY]
+        : [registry, `https://${registry}`]
+    for (const key of keys) {
+      const auth = config.auths?.[key]?.auth
+      if (auth) {
+        return auth
+      }
+    }
+  } catch {}
+  return undefined
+}
+
+/**
+ * Parse a `WWW-Authenticate: Bearer realm="...",service="...",scope="..."`
+ * challenge.
+ */
+export function parseBearerChallenge(
+  header: string | undefined,
+): Record<string, string> | undefined {
+  if (!header || !/^bearer\s/i.test(header)) {
+    return undefined
+  }
+  const params = { __proto__: null } as unknown as Record<string, string>
+  for (const match of header.slice(7).matchAll(/(\w+)="([^"]*)"/g)) {
+    params[match[1]!] = match[2]!
+  }
+  return params['realm'] ? params : undefined
+}
+
+function getHeader(
+  headers: Record<string, unknown> | undefined,
+  name: string,
+): string | undefined {
+  const value = headers?.[name]
+  return Array.isArray(value) ? String(value[0]) : (value as string | undefined)
+}
+
+class RegistryClient {
+  readonly apiHost: string
+  readonly basicAuth: string | undefined
+  readonly repository: string
+  token: string | undefined
+
+  constructor({ registry, repository }: ImageReference) {
+    this.apiHost =
+      registry === DOCKER_HUB_REGISTRY ? DOCKER_HUB_API_HOST : registry
+    this.basicAuth = readDockerCredentials(registry)
+    this.repository = repository
+  }
+
+  async get(pathname: string, accept?: string | undefined): Promise<Buffer> {
+    const scheme = /^(?:localhost|127\.0\.0\.1)(?::\d+)?$/.test(this.apiHost)
+      ? 'http'
+      : 'https'
+    const url = `${scheme}://${this.apiHost}/v2/${this.repository}/${pathname}`
+    for (let attempt = 0; attempt < 2; attempt += 1) {
+      const authorization = this.token
+        ? `Bearer ${this.token}`
+        : this.basicAuth
+          ? `Basic ${this.basicAuth}`
+          : undefined
+      const response = await socketHttpRequest(url, {
+        headers: {
+          ...(accept ? { accept } : {}),
+          ...(authorization ? { authorization } : {}),
+        },
+        timeout: 300_000,
+      })
+      if (response.ok) {
+        return response.body as Buffer
+      }
+      const challenge = parseBearerChallenge(
+        getHeader(
+          response.headers as Record<string, unknown>,
+          'www-authenticate',
+        ),
+      )
+      if (response.status === 401 && challenge && attempt === 0) {
+        this.token = await this.fetchToken(challenge)
+        continue
+      }
+      throw new Error(
+        `${response.status} ${response.statusText} for ${url}${response.status === 401 ? ' (run `docker login` for private images)' : ''}`,
+      )
+    }
+    /* c8 ignore next - the loop either returns or throws */
+    throw new Error(`authentication failed for ${url}`)
+  }
+
+  async fetchToken(challenge: Record<string, string>): Promise<string> {
+    const url = new URL(challenge['realm']!)
+    if (challenge['service']) {
+      url.searchParams.set('service', challenge['service'])
+    }
+    url.searchParams.set(
+      'scope',
+      challenge['scope'] ?? `repository:${this.repository}:pull`,
+    )
+    const response = await socketHttpRequest(url.href, {
+      headers: this.basicAuth
+        ? { authorization: `Basic ${this.basicAuth}` }
+        : {},
+    })
+    if (!response.ok) {
+      throw new Error(
+        `token request failed with ${response.status} ${response.statusText}`,
+      )
+    }
+    const { access_token, token } = JSON.parse(response.text()) as {
+      access_token?: string | undefined
+      token?: string | undefined
+    }
+    const value = token || access_token
+    if (!value) {
+      throw new Error('token response did not include a token')
+    }
+    return value
+  }
+}
+
+/**
+ * Pull an image manifest from a registry. Layer blobs are downloaded when
+ * read.
+ */
+export async function pullRegistryImage(
+  ref: ImageReference,
+  options?: { platform?: string | undefined } | undefined,
+): Promise<CResult<ContainerImage>> {
+  const { platform } = { __proto__: null, ...options } as {
+    platform?: string | undefined
+  }
+  const client = new RegistryClient(ref)
+  const name = formatImageReference(ref)
+  try {
+    let manifest = JSON.parse(
+      (
+        await client.get(`manifests/${ref.digest ?? ref.tag}`, MANIFEST_ACCEPT)
+      ).toString('utf8'),
+    ) as OciManifest
+    let descriptor: OciDescriptor | undefined
+    if (isImageIndex(manifest)) {
+      const manifests = manifest.manifests ?? []
+      descriptor = selectPlatformManifest(manifests, platform)
+      if (!descriptor) {
+        return {
+          ok: false,
+          message: 'Image platform not found',
+          cause: `${name} has no manifest for ${platform ?? DEFAULT_IMAGE_PLATFORM}; available: ${listPlatforms(manifests)}`,
+        }
+      }
+      manifest = JSON.parse(
+        (
+          await client.get(`manifests/${descriptor.digest}`, MANIFEST_ACCEPT)
+        ).toString('utf8'),
+      ) as OciManifest
+    }
+    return {
+      ok: true,
+      data: {
+        close: () => {},
+        ...(manifest.config?.digest ? { digest: manifest.config.digest } : {}),
+        layers: (manifest.layers ?? []).map(layer => ({
+          digest: layer.digest,
+          read: async () => await client.get(`blobs/${layer.digest}`),
+        })),
+        name,
+        ...(descriptor?.platform
+          ? { platform: formatPlatform(descriptor.platform) }
+          : {}),
+      },
+    }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Unable to pull image',
+      cause: `${name}: ${getErrorCause(e)}`,
+    }
+  }
+}
@@ -0,0 +1,362 @@
+/**
+ * Installed package databases in container images.
+ *
+ * Besides the project manifests an image ships, its filesystem records what
+ * was actually installed:
+ *
+ * - Debian/Ubuntu: `var/lib/dpkg/status` and distroless `status.d/*`
+ * - Alpine: `lib/apk/db/installed`
+ * - npm: every `node_modules/<name>/package.json`
+ * - Python: `*.dist-info/METADATA` and `*.egg-info/PKG-INFO`
+ *
+ * Each database becomes a ParsedLockfile so images reuse the local lockfile
+ * SBOM. OS packages are qualified with the distro from `os-release`.
+ */
+
+import { NODE_MODULES } from '../../constants/packages.mts'
+
+import type { ContainerFiles } from './filesystem.mts'
+import type { PURL_Type } from '../ecosystem/types.mts'
+import type { LockfileDependency, ParsedLockfile } from '../lockfile/types.mts'
+
+export type OsRelease = {
+  id?: string | undefined
+  versionId?: string | undefined
+}
+
+const DPKG_STATUS = 'var/lib/dpkg/status'
+
+const DPKG_STATUS_DIR = 'var/lib/dpkg/status.d/'
+
+const APK_INSTALLED = 'lib/apk/db/installed'
+
+const OS_RELEASE_PATHS = ['etc/os-release', 'usr/lib/os-release']
+
+const NPM_PACKAGE_JSON_REGEXP =
+  /^(.*?\/?node_modules)\/((?:@[^/]+\/)?[^/@.][^/]*)\/package\.json$/
+
+const PYTHON_METADATA_REGEXP =
+  /^(.*)\/[^/]+\.(?:dist-info\/METADATA|egg-info\/PKG-INFO)$/
+
+// Directories holding installed packages or system state rather than
+// project sources.
+const INSTALLED_DIR_SEGMENTS = new Set([
+  NODE_MODULES,
+  'dist-packages',
+  'site-packages',
+  'proc',
+  'sys',
+])
+
+export function isContainerPackageDatabase(filepath: string): boolean {
+  return (
+    filepath === DPKG_STATUS ||
+    (filepath.startsWith(DPKG_STATUS_DIR) &&
+      !filepath.endsWith('.md5sums')) ||
+    filepath === APK_INSTALLED ||
+    OS_RELEASE_PATHS.includes(filepath) ||
+    NPM_PACKAGE_JSON_REGEXP.test(filepath) ||
+    PYTHON_METADATA_REGEXP.test(filepath)
+  )
+}
+
+/**
+ * Whether a path lives inside installed packages, where manifests describe
+ * dependencies rather than the image's own projects.
+ */
+export function isContainerInstalledPath(filepath: string): boolean {
+  return filepath.split('/').some(s => INSTALLED_DIR_SEGMENTS.has(s))
+}
+
+export function parseOsRelease(content: string): OsRelease {
+  const values = { __proto__: null } as unknown as Record<string, string>
+  for (const line of content.split(/\r?\n/)) {
+    const match = /^([A-Z_]+)=(.*)$/.exec(line.trim())
+    if (match) {
+      values[match[1]!] = match[2]!.replace(/^(["'])(.*)\1$/, '$2')
+    }
+  }
+  return {
+    ...(values['ID'] ? { id: values['ID'] } : {}),
+    ...(values['VERSION_ID'] ? { versionId: values['VERSION_ID'] } : {}),
+  }
+}
+
+/**
+ * Split an RFC 822 style database (dpkg status, Python METADATA) into
+ * stanzas of fields. Continuation lines are appended to the previous field.
+ */
+export function parseControlStanzas(
+  content: string,
+): Array<Record<string, string>> {
+  const stanzas: Array<Record<string, string>> = []
+  let current: Record<string, string> | undefined
+  let lastField: string | undefined
+  for (const line of content.split(/\r?\n/)) {
+    if (!line.trim()) {
+      current = undefined
+      lastField = undefined
+      continue
+    }
+    if (/^\s/.test(line)) {
+      if (current && lastField) {
+        current[lastField] += `\n${line.trim()}`
+      }
+      continue
+    }
+    const colonIndex = line.indexOf(':')
+    if (colonIndex === -1) {
+      continue
+    }
+    if (!current) {
+      current = { __proto__: null } as unknown as Record<string, string>
+      stanzas.push(current)
+    }
+    lastField = line.slice(0, colonIndex)
+    current[lastField] = line.slice(colonIndex + 1).trim()
+  }
+  return stanzas
+}
+
+function getDistroQualifier(osRelease: OsRelease): string | undefined {
+  return osRelease.id && osRelease.versionId
+    ? `${osRelease.id}-${osRelease.versionId}`
+    : osRelease.id
+}
+
+function getOsQualifiers(
+  arch: string | undefined,
+  osRelease: OsRelease,
+): Record<string, string> | undefined {
+  const distro = getDistroQualifier(osRelease)
+  const qualifiers = {
+    ...(arch ? { arch } : {}),
+    ...(distro ? { distro } : {}),
+  }
+  return Object.keys(qualifiers).length ? qualifiers : undefined
+}
+
+/**
+ * Resolve dependency names (or virtual packages they provide) to the ids of
+ * installed packages, dropping anything not installed.
+ */
+function linkDependencies(
+  packages: Array<LockfileDependency & { requires: string[] }>,
+  provides: Map<string, string>,
+): LockfileDependency[] {
+  return packages.map(({ requires, ...dep }) => {
+    const childIds = [
+      ...new Set(
+        requires
+          .map(name => provides.get(name))
+          .filter((id): id is string => !!id && id !== dep.id),
+      ),
+    ]
+    return childIds.length ? { ...dep, dependencies: childIds } : dep
+  })
+}
+
+export function parseDpkgStatus(
+  content: string,
+  osRelease: OsRelease,
+): LockfileDependency[] {
+  const namespace = osRelease.id || 'debian'
+  const packages: Array<LockfileDependency & { requires: string[] }> = []
+  const provides = new Map<string, string>()
+  for (const stanza of parseControlStanzas(content)) {
+    const name = stanza['Package']
+    const version = stanza['Version']
+    // Distroless `status.d` entries have no Status field.
+    const status = stanza['Status']
+    if (!name || !version || (status && !/\binstalled$/.test(status))) {
+      continue
+    }
+    const arch = stanza['Architecture']
+    const id = arch && arch !== 'all' ? `${name}:${arch}` : name
+    provides.set(name, id)
+    for (const provided of (stanza['Provides'] ?? '').split(',')) {
+      const providedName = provided.trim().split(/[\s(]/)[0]
+      if (providedName && !provides.has(providedName)) {
+        provides.set(providedName, id)
+      }
+    }
+    const requires: string[] = []
+    for (const field of ['Pre-Depends', 'Depends']) {
+      for (const clause of (stanza[field] ?? '').split(',')) {
+        // Only the first alternative of `a | b` is followed.
+        const depName = clause.split('|')[0]!.trim().split(/[\s(:]/)[0]
+        if (depName) {
+          requires.push(depName)
+        }
+      }
+    }
+    const qualifiers = getOsQualifiers(arch, osRelease)
+    packages.push({
+      id,
+      type: 'deb',
+      namespace,
+      name,
+      version,
+      ...(qualifiers ? { qualifiers } : {}),
+      requires,
+    })
+  }
+  return linkDependencies(packages, provides)
+}
+
+export function parseApkInstalled(
+  content: string,
+  osRelease: OsRelease,
+): LockfileDependency[] {
+  const namespace = osRelease.id || 'alpine'
+  const packages: Array<LockfileDependency & { requires: string[] }> = []
+  const provides = new Map<string, string>()
+  for (const block of content.split(/\r?\n\r?\n/)) {
+    const fields: Array<[string, string]> = []
+    for (const line of block.split(/\r?\n/)) {
+      if (line[1] === ':') {
+        fields.push([line[0]!, line.slice(2)])
+      }
+    }
+    const get = (key: string) => fields.find(f => f[0] === key)?.[1]
+    const name = get('P')
+    const version = get('V')
+    if (!name || !version) {
+      continue
+    }
+    provides.set(name, name)
+    for (const token of (get('p') ?? '').split(/\s+/)) {
+      const providedName = token.split('=')[0]
+      if (providedName && !provides.has(providedName)) {
+        provides.set(providedName, name)
+      }
+    }
+    const requires = (get('D') ?? '')
+      .split(/\s+/)
+      .filter(token => token && !token.startsWith('!'))
+      .map(token => token.split(/[=<>~]/)[0]!)
+    const qualifiers = getOsQualifiers(get('A'), osRelease)
+    packages.push({
+      id: name,
+      type: 'apk',
+      namespace,
+      name,
+      version,
+      ...(qualifiers ? { qualifiers } : {}),
+      requires,
+    })
+  }
+  return linkDependencies(packages, provides)
+}
+
+function pushGrouped(
+  groups: Map<string, LockfileDependency[]>,
+  group: string,
+  dep: LockfileDependency,
+) {
+  const deps = groups.get(group) ?? []
+  if (!deps.some(d => d.id === dep.id)) {
+    deps.push(dep)
+  }
+  groups.set(group, deps)
+}
+
+function groupsToLockfiles(
+  groups: Map<string, LockfileDependency[]>,
+  ecosystem: PURL_Type,
+): ParsedLockfile[] {
+  return [...groups].map(([file, dependencies]) => ({
+    ecosystem,
+    file,
+    dependencies,
+  }))
+}
+
+/**
+ * Collect the installed packages recorded in a flattened image filesystem.
+ * npm and Python packages are grouped by the directory they are installed
+ * into.
+ */
+export function collectInstalledPackages(
+  files: ContainerFiles,
+): ParsedLockfile[] {
+  let osRelease: OsRelease = {}
+  for (const osReleasePath of OS_RELEASE_PATHS) {
+    const content = files.get(osReleasePath)
+    if (content) {
+      osRelease = parseOsRelease(content.toString('utf8'))
+      break
+    }
+  }
+
+  const lockfiles: ParsedLockfile[] = []
+  const dpkg = files.get(DPKG_STATUS)
+  const dpkgDeps = dpkg ? parseDpkgStatus(dpkg.toString('utf8'), osRelease) : []
+  const npmGroups = new Map<string, LockfileDependency[]>()
+  const pypiGroups = new Map<string, LockfileDependency[]>()
+  for (const [filepath, content] of [...files].sort(([a], [b]) =>
+    a.localeCompare(b),
+  )) {
+    if (filepath.startsWith(DPKG_STATUS_DIR)) {
+      dpkgDeps.push(...parseDpkgStatus(content.toString('utf8'), osRelease))
+      continue
+    }
+    let match = NPM_PACKAGE_JSON_REGEXP.exec(filepath)
+    if (match) {
+      let pkg: { name?: unknown; version?: unknown } | undefined
+      try {
+        pkg = JSON.parse(content.toString('utf8'))
+      } catch {}
+      const { name, version } = pkg ?? {}
+      if (typeof name === 'string' && typeof version === 'string') {
+        const slashIndex = name.startsWith('@') ? name.indexOf('/') : -1
+        pushGrouped(npmGroups, match[1]!, {
+          id: `${name}@${version}`,
+          type: 'npm',
+          ...(slashIndex === -1
+            ? { name }
+            : {
+                namespace: name.slice(0, slashIndex),
+                name: name.slice(slashIndex + 1),
+              }),
+          version,
+        })
+      }
+      continue
+    }
+    match = PYTHON_METADATA_REGEXP.exec(filepath)
+    if (match) {
+      const [stanza] = parseControlStanzas(content.toString('utf8'))
+      const name = stanza?.['Name']?.toLowerCase().replace(/[-_.]+/g, '-')
+      const version = stanza?.['Version']
+      if (name && version) {
+        pushGrouped(pypiGroups, match[1]!, {
+          id: `${name}@${version}`,
+          type: 'pypi',
+          name,
+          version,
+        })
+      }
+    }
+  }
+  if (dpkgDeps.length) {
+    lockfiles.push({
+      ecosystem: 'deb',
+      file: dpkg ? DPKG_STATUS : DPKG_STATUS_DIR.slice(0, -1),
+      dependencies: dpkgDeps,
+    })
+  }
+  const apk = files.get(APK_INSTALLED)
+  if (apk) {
+    lockfiles.push({
+      ecosystem: 'apk',
+      file: APK_INSTALLED,
+      dependencies: parseApkInstalled(apk.toString('utf8'), osRelease),
+    })
+  }
+  lockfiles.push(
+    ...groupsToLockfiles(npmGroups, 'npm'),
+    ...groupsToLockfiles(pypiGroups, 'pypi'),
+  )
+  return lockfiles.filter(l => l.dependencies.length)
+}
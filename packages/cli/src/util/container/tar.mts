@@ -0,0 +1,333 @@
+/**
+ * Minimal tar reader for container image archives and layers.
+ *
+ * Handles ustar headers, pax extended headers and GNU long names, which
+ * cover what `docker save`, `skopeo` and registry layers produce. Entries are
+ * listed with the offset of their data so large archives can be read from
+ * disk piecemeal instead of being loaded whole. Layers are read as a stream
+ * so a decompressed layer is never held in memory whole either.
+ */
+
+import { closeSync, fstatSync, openSync, readSync } from 'node:fs'
+import { Readable } from 'node:stream'
+import { createGunzip } from 'node:zlib'
+
+export type TarEntryType =
+  | 'directory'
+  | 'file'
+  | 'hardlink'
+  | 'other'
+  | 'symlink'
+
+export type TarEntry = {
+  name: string
+  type: TarEntryType
+  linkname: string
+  offset: number
+  size: number
+}
+
+export type TarRead = (offset: number, length: number) => Buffer
+
+export type TarStreamEntry = {
+  // Only set for the entries the reader was asked to keep the data of.
+  data: Buffer | undefined
+  entry: TarEntry
+}
+
+// Pax and GNU long name headers describe the entry after them.
+type TarMeta = {
+  longLink?: string | undefined
+  longName?: string | undefined
+  pax?: Record<string, string> | undefined
+}
+
+const BLOCK_SIZE = 512
+
+const TAR_ENTRY_TYPES = {
+  __proto__: null,
+  '': 'file',
+  '0': 'file',
+  '1': 'hardlink',
+  '2': 'symlink',
+  '5': 'directory',
+  '7': 'file',
+} as unknown as Record<string, TarEntryType | undefined>
+
+function readString(block: Buffer, start: number, length: number): string {
+  const field = block.subarray(start, start + length)
+  const nulIndex = field.indexOf(0)
+  return field
+    .subarray(0, nulIndex === -1 ? field.length : nulIndex)
+    .toString('utf8')
+}
+
+function readNumber(block: Buffer, start: number, length: number): number {
+  // GNU base-256 encoding for values that do not fit in octal.
+  if (block[start]! & 0x80) {
+    let value = block[start]! & 0x7f
+    for (let i = start + 1; i < start + length; i += 1) {
+      value = value * 256 + block[i]!
+    }
+    return value
+  }
+  const octal = readString(block, start, length).trim()
+  return octal ? Number.parseInt(octal, 8) : 0
+}
+
+/**
+ * Parse pax extended header records (`<length> <key>=<value>\n`).
+ */
+export function parsePaxHeaders(data: Buffer): Record<string, string> {
+  const headers = { __proto__: null } as unknown as Record<string, string>
+  let offset = 0
+  while (offset < data.length) {
+    const spaceIndex = data.indexOf(0x20, offset)
+    if (spaceIndex === -1) {
+      break
+    }
+    const length = Number.parseInt(
+      data.subarray(offset, spaceIndex).toString('utf8'),
+      10,
+    )
+    if (!length || length < 0) {
+      break
+    }
+    const record = data
+      .subarray(spaceIndex + 1, offset + length - 1)
+      .toString('utf8')
+    const eqIndex = record.indexOf('=')
+    if (eqIndex !== -1) {
+      headers[record.slice(0, eqIndex)] = record.slice(eqIndex + 1)
+    }
+    offset += length
+  }
+  return headers
+}
+
+function isMetaTypeflag(typeflag: string): boolean {
+  return (
+    typeflag === 'L' || typeflag === 'K' || typeflag === 'x' || typeflag === 'g'
+  )
+}
+
+function getDataSize(block: Buffer, typeflag: string, meta: TarMeta): number {
+  return !isMetaTypeflag(typeflag) && meta.pax?.['size']
+    ? Number(meta.pax['size'])
+    : readNumber(block, 124, 12)
+}
+
+// Global pax headers (`g`) are not needed and carry no data worth reading.
+function setMeta(meta: TarMeta, typeflag: string, data: Buffer): void {
+  if (typeflag === 'L') {
+    meta.longName = readString(data, 0, data.length)
+  } else if (typeflag === 'K') {
+    meta.longLink = readString(data, 0, data.length)
+  } else if (typeflag === 'x') {
+    meta.pax = parsePaxHeaders(data)
+  }
+}
+
+/**
+ * The entry of a header block, which uses up the meta headers before it.
+ */
+function takeEntry(
+  block: Buffer,
+  typeflag: string,
+  meta: TarMeta,
+  offset: number,
+  size: number,
+): TarEntry {
+  const { longLink, longName, pax } = meta
+  const prefix =
+    readString(block, 257, 5) === 'ustar' ? readString(block, 345, 155) : ''
+  const headerName = readString(block, 0, 100)
+  const name =
+    pax?.['path'] ??
+    longName ??
+    (prefix ? `${prefix}/${headerName}` : headerName)
+  meta.longLink = undefined
+  meta.longName = undefined
+  meta.pax = undefined
+  return {
+    name,
+    type: TAR_ENTRY_TYPES[typeflag] ?? 'other',
+    linkname: pax?.['linkpath'] ?? longLink ?? readString(block, 157, 100),
+    offset,
+    size,
+  }
+}
+
+/**
+ * List the entries of a tar archive through a random access reader.
+ */
+export function listTarEntries(read: TarRead, totalSize: number): TarEntry[] {
+  const entries: TarEntry[] = []
+  const meta: TarMeta = {}
+  let offset = 0
+  while (offset + BLOCK_SIZE <= totalSize) {
+    const block = read(offset, BLOCK_SIZE)
+    // Two zero blocks mark the end of the archive; one is enough to stop.
+    if (block.every(b => b === 0)) {
+      break
+    }
+    const typeflag = readString(block, 156, 1)
+    const size = getDataSize(block, typeflag, meta)
+    const dataOffset = offset + BLOCK_SIZE
+    offset = dataOffset + Math.ceil(size / BLOCK_SIZE) * BLOCK_SIZE
+    if (isMetaTypeflag(typeflag)) {
+      if (typeflag !== 'g') {
+        setMeta(meta, typeflag, read(dataOffset, size))
+      }
+      continue
+    }
+    entries.push(takeEntry(block, typeflag, meta, dataOffset, size))
+  }
+  return entries
+}
+
+/**
+ * Read the entries of a tar stream in order. Only the data of the entries
+ * wantData accepts is held; the rest is skipped as it streams by.
+ */
+export async function* readTarStream(
+  source: AsyncIterable<Buffer>,
+  wantData: (entry: TarEntry) => boolean,
+): AsyncGenerator<TarStreamEntry> {
+  const iterator = source[Symbol.asyncIterator]()
+  const chunks: Buffer[] = []
+  let buffered = 0
+  // Buffer at least length bytes; false when the stream ends first.
+  const fill = async (length: number): Promise<boolean> => {
+    while (buffered < length) {
+      const result = await iterator.next()
+      if (result.done) {
+        return false
+      }
+      chunks.push(result.value)
+      buffered += result.value.length
+    }
+    return true
+  }
+  const take = (length: number): Buffer => {
+    const all = chunks.length === 1 ? chunks[0]! : Buffer.concat(chunks)
+    chunks.length = 0
+    buffered = all.length - length
+    if (buffered) {
+      chunks.push(all.subarray(length))
+    }
+    return all.subarray(0, length)
+  }
+  // Drop up to length buffered bytes and return how many are left to drop.
+  const drop = (length: number): number => {
+    let left = length
+    while (left && chunks.length) {
+      const chunk = chunks[0]!
+      const dropped = Math.min(chunk.length, left)
+      if (dropped === chunk.length) {
+        chunks.shift()
+      } else {
+        chunks[0] = chunk.subarray(dropped)
+      }
+      buffered -= dropped
+      left -= dropped
+    }
+    return left
+  }
+  try {
+    const meta: TarMeta = {}
+    let offset = 0
+    while (await fill(BLOCK_SIZE)) {
+      const block = take(BLOCK_SIZE)
+      // Two zero blocks mark the end of the archive; one is enough to stop.
+      if (block.every(b => b === 0)) {
+        break
+      }
+      const typeflag = readString(block, 156, 1)
+      const size = getDataSize(block, typeflag, meta)
+      const paddedSize = Math.ceil(size / BLOCK_SIZE) * BLOCK_SIZE
+      const dataOffset = offset + BLOCK_SIZE
+      offset = dataOffset + paddedSize
+      const entry = isMetaTypeflag(typeflag)
+        ? undefined
+        : takeEntry(block, typeflag, meta, dataOffset, size)
+      let data: Buffer | undefined
+      if (entry ? wantData(entry) : typeflag !== 'g') {
+        if (!(await fill(size))) {
+          return
+        }
+        data = take(size)
+      }
+      let left = drop(data ? paddedSize - size : paddedSize)
+      while (left && (await fill(1))) {
+        left = drop(left)
+      }
+      if (entry) {
+        yield { data, entry }
+      } else if (data) {
+        setMeta(meta, typeflag, data)
+      }
+    }
+  } finally {
+    // Stops a decompression stream the archive end left unread.
+    await iterator.return?.()
+  }
+}
+
+export function listTarBufferEntries(data: Buffer): TarEntry[] {
+  return listTarEntries(
+    (offset, length) => data.subarray(offset, offset + length),
+    data.length,
+  )
+}
+
+export type TarFile = {
+  close: () => void
+  entries: TarEntry[]
+  read: (entry: TarEntry) => Buffer
+}
+
+/**
+ * Open a tar archive on disk, reading entry data on demand.
+ */
+export function openTarFile(filepath: string): TarFile {
+  const fd = openSync(filepath, 'r')
+  const readAt: TarRead = (offset, length) => {
+    const buffer = Buffer.alloc(length)
+    readSync(fd, buffer, 0, length, offset)
+    return buffer
+  }
+  try {
+    const entries = listTarEntries(readAt, fstatSync(fd).size)
+    return {
+      close: () => closeSync(fd),
+      entries,
+      read: entry => readAt(entry.offset, entry.size),
+    }
+  } catch (e) {
+    closeSync(fd)
+    throw e
+  }
+}
+
+/**
+ * Decompress a layer blob as a stream of tar data. Layers are either plain
+ * tar or gzip; other compressions (zstd) return undefined.
+ */
+export function decompressLayer(data: Buffer): Readable | undefined {
+  if (data[0] === 0x1f && data[1] === 0x8b) {
+    const gunzip = createGunzip()
+    gunzip.end(data)
+    return gunzip
+  }
+  // A tar archive carries the `ustar` magic at offset 257 of its first
+  // header; an empty layer is all zero blocks.
+  if (
+    data.length >= BLOCK_SIZE &&
+    (data.subarray(257, 262).toString('latin1') === 'ustar' ||
+      data.subarray(0, BLOCK_SIZE).every(b => b === 0))
+  ) {
+    return Readable.from([data])
+  }
+  return undefined
+}
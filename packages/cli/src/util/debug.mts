@@ -169,7 +169,7 @@ export function debugApiResponse(
  * Debug configuration loading.
  */
 // socket-lint: allow boolean-trap -- collapsing into an options object would
-// change call sites in src/util/config.mts and test/unit/util/debug.test.mts,
+// change call sites in src/util/config*.mts and test/unit/util/debug.test.mts,
 // which are out of scope for this pass.
 export function debugConfig(
   source: string,
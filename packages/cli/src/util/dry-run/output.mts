@@ -11,6 +11,9 @@
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
 import { DRY_RUN_LABEL } from '../../constants/cli.mts'
+import { formatUnifiedDiff } from '../output/unified-diff.mts'
+
+import type { UnifiedDiffFile } from '../output/unified-diff.mts'
 
 const logger = getDefaultLogger()
 
@@ -24,17 +27,57 @@ export function out(message: string): void {
 export function outputDryRunDelete(
   resourceType: string,
   identifier: string,
+  request?: DryRunRequest | undefined,
 ): void {
   out('')
   out(`${DRY_RUN_LABEL}: Would delete ${resourceType}`)
   out('')
   out(`  Target: ${identifier}`)
   out('')
+  if (request) {
+    outputDryRunRequest(request)
+  }
   out('  This action cannot be undone.')
   out('  Run without --dry-run to perform this deletion.')
   out('')
 }
 
+/**
+ * Output dry-run for commands that write local files, showing each file as a
+ * unified diff against what is on disk now. File paths are shown as given.
+ */
+export function outputDryRunDiff(
+  description: string,
+  files: UnifiedDiffFile[],
+  changes?: string[] | undefined,
+): void {
+  out('')
+  out(`${DRY_RUN_LABEL}: Would ${description}`)
+  out('')
+  if (changes && changes.length > 0) {
+    out('  Changes:')
+    for (const change of changes) {
+      out(`    - ${change}`)
+    }
+    out('')
+  }
+  for (const file of files) {
+    const diff = formatUnifiedDiff(file)
+    if (diff === '') {
+      out(`  ${file.path}: unchanged`)
+    } else if (diff === undefined) {
+      out(`  ${file.path}: too many changes to show`)
+    } else {
+      for (const line of diff.split('\n')) {
+        out(`  ${line}`)
+      }
+    }
+    out('')
+  }
+  out('  Run without --dry-run to apply these changes.')
+  out('')
+}
+
 /**
  * Output dry-run for commands that execute external tools.
  */
@@ -85,6 +128,38 @@ export function outputDryRunFetch(
   out('')
 }
 
+// An API call a command would make.
+export interface DryRunRequest {
+  method: 'DELETE' | 'GET' | 'PATCH' | 'POST' | 'PUT'
+  // Relative to the API base URL, e.g. `orgs/my-org/repos`.
+  path: string
+  query?: Record<string, string | number | boolean | undefined> | undefined
+  // JSON bodies are shown as JSON, strings describe what else is sent.
+  body?: unknown
+}
+
+function outputDryRunRequest(request: DryRunRequest): void {
+  const query = new URLSearchParams()
+  for (const [key, value] of Object.entries(request.query ?? {})) {
+    if (value !== undefined && value !== '') {
+      query.set(key, String(value))
+    }
+  }
+  const search = `${query}`
+  out(
+    `  API request: ${request.method} ${request.path}${search ? `?${search}` : ''}`,
+  )
+  if (typeof request.body === 'string') {
+    out(`  Request body: ${request.body}`)
+  } else if (request.body !== undefined) {
+    out('  Request body:')
+    for (const line of JSON.stringify(request.body, null, 2).split('\n')) {
+      out(`    ${line}`)
+    }
+  }
+  out('')
+}
+
 export interface DryRunAction {
   type:
     | 'create'
@@ -147,6 +222,7 @@ export function outputDryRunPreview(preview: DryRunPreview): void {
 export function outputDryRunUpload(
   resourceType: string,
   details: Record<string, unknown>,
+  request?: DryRunRequest | undefined,
 ): void {
   out('')
   out(`${DRY_RUN_LABEL}: Would upload ${resourceType}`)
@@ -165,6 +241,9 @@ export function outputDryRunUpload(
     }
   }
   out('')
+  if (request) {
+    outputDryRunRequest(request)
+  }
   out('  Run without --dry-run to perform this upload.')
   out('')
 }
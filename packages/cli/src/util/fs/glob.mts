@@ -5,6 +5,7 @@ import ignore from 'ignore'
 import micromatch from 'micromatch'
 import { parse as yamlParse } from 'yaml'
 
+import { debugDirNs, debugNs } from '@socketsecurity/lib-stable/debug/output'
 import { isDirSync } from '@socketsecurity/lib-stable/fs/inspect'
 import { safeReadFile } from '@socketsecurity/lib-stable/fs/read-file'
 import { defaultIgnore } from '@socketsecurity/lib-stable/globs/defaults'
@@ -14,7 +15,9 @@ import { normalizePath } from '@socketsecurity/lib-stable/paths/normalize'
 import { isNonEmptyString } from '@socketsecurity/lib-stable/strings/predicates'
 
 import { homePath } from '../../constants/paths.mts'
+import { SOCKET_YAML, SOCKET_YML } from '../../constants/socket.mts'
 import { NODE_MODULES, PNPM } from '../../constants.mts'
+import { parseSocketConfig } from '../socket-yaml.mts'
 
 import type { Agent } from '../ecosystem/environment.mts'
 import type { SocketYml } from '../socket-yaml.mts'
@@ -135,20 +138,30 @@ export async function globWithGitIgnore(
     }
   }
 
-  const gitIgnoreStream = fastGlob.globStream(['**/.gitignore'], {
-    absolute: true,
-    cwd,
-    dot: true,
-    ignore: DEFAULT_IGNORE_FOR_GIT_IGNORE,
-  }) as AsyncIterable<string>
+  // The projectIgnorePaths of a nested socket.yml apply to its directory,
+  // like the patterns of a nested .gitignore.
+  const gitIgnoreStream = fastGlob.globStream(
+    ['**/.gitignore', `**/${SOCKET_YML}`, `**/${SOCKET_YAML}`],
+    {
+      absolute: true,
+      cwd,
+      dot: true,
+      ignore: DEFAULT_IGNORE_FOR_GIT_IGNORE,
+    },
+  ) as AsyncIterable<string>
   for await (const ignorePatterns of transform(
     gitIgnoreStream,
-    async (filepath: string) =>
-      ignoreFileToGlobPatterns(
-        (await safeReadFile(filepath, { encoding: 'utf8' })) ?? '',
-        filepath,
-        cwd,
-      ),
+    async (filepath: string) => {
+      const content =
+        (await safeReadFile(filepath, { encoding: 'utf8' })) ?? ''
+      if (path.basename(filepath) === '.gitignore') {
+        return ignoreFileToGlobPatterns(content, filepath, cwd)
+      }
+      // The socket.yml of cwd itself arrives as socketConfig.
+      return path.dirname(filepath) === path.resolve(cwd)
+        ? []
+        : nestedSocketYmlToGlobPatterns(content, filepath, cwd)
+    },
     { concurrency: 8 },
   )) {
     for (let i = 0, { length } = ignorePatterns; i < length; i += 1) {
@@ -302,6 +315,24 @@ export function isReportSupportedFile(
   return micromatch.some(filepath, patterns, { dot: true })
 }
 
+function nestedSocketYmlToGlobPatterns(
+  content: string,
+  filepath: string,
+  cwd: string,
+): string[] {
+  try {
+    return ignoreFileLinesToGlobPatterns(
+      parseSocketConfig(content).projectIgnorePaths,
+      filepath,
+      cwd,
+    )
+  } catch (e) {
+    debugNs('error', `Failed to parse config file: ${filepath}`)
+    debugDirNs('error', e)
+    return []
+  }
+}
+
 export function pathsToGlobPatterns(
   paths: string[] | readonly string[],
   cwd?: string | undefined,
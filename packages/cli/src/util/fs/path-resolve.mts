@@ -7,6 +7,9 @@ import {
   pathsToGlobPatterns,
 } from './glob.mts'
 
+import { isSocketWrapperShim } from '../../commands/wrapper/wrapper-shims.mts'
+import { isLocalLockfilePath } from '../lockfile/parsers.mts'
+
 import type { SupportedFiles } from './glob.mts'
 import type { SocketYml } from '../socket-yaml.mts'
 
@@ -29,6 +32,11 @@ export function findBinPathDetailsSync(binName: string): {
   let theBinPath: string | undefined
   for (let i = 0, { length } = binPaths; i < length; i += 1) {
     const binPath = binPaths[i]!
+    // A `socket wrapper` shim would run Socket CLI again, skip to the real
+    // binary behind it.
+    if (isSocketWrapperShim(binPath)) {
+      continue
+    }
     theBinPath = resolveRealBinSync(binPath)
     break
   }
@@ -38,6 +46,9 @@ export function findBinPathDetailsSync(binName: string): {
 export type PackageFilesForScanOptions = {
   cwd?: string | undefined
   config?: SocketYml | undefined
+  // Also collect lockfiles handled by the local parsers in util/lockfile,
+  // even when the Socket API does not list them as supported.
+  localLockfiles?: boolean | undefined
 }
 
 export async function getPackageFilesForScan(
@@ -45,7 +56,11 @@ export async function getPackageFilesForScan(
   supportedFiles: SupportedFiles,
   options?: PackageFilesForScanOptions | undefined,
 ): Promise<string[]> {
-  const { config: socketConfig, cwd = process.cwd() } = {
+  const {
+    config: socketConfig,
+    cwd = process.cwd(),
+    localLockfiles,
+  } = {
     __proto__: null,
     ...options,
   } as PackageFilesForScanOptions
@@ -53,7 +68,11 @@ export async function getPackageFilesForScan(
   // Apply the supported files filter during streaming to avoid accumulating
   // all files in memory. This is critical for large monorepos with 100k+ files
   // where accumulating all paths before filtering causes OOM errors.
-  const filter = createSupportedFilesFilter(supportedFiles)
+  const supportedFilter = createSupportedFilesFilter(supportedFiles)
+  const filter = localLockfiles
+    ? (filepath: string) =>
+        supportedFilter(filepath) || isLocalLockfilePath(filepath)
+    : supportedFilter
 
   return await globWithGitIgnore(
     pathsToGlobPatterns(inputPaths, options?.cwd),
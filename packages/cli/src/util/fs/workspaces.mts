@@ -0,0 +1,199 @@
+/**
+ * Workspaces of a JavaScript monorepo, for the `--workspace-filter` and
+ * `--changed-since` flags of the scan commands.
+ *
+ * Workspaces come from the `workspaces` field of the root package.json (npm,
+ * yarn, bun) or from pnpm-workspace.yaml. Each is named after the `name` in
+ * its package.json, falling back to its directory. Files outside every
+ * workspace belong to the root workspace, `.`.
+ *
+ * Scanned packages are attributed to workspaces by the manifest files Socket
+ * reports for them, so a package shared by two workspaces counts for both.
+ */
+
+import { existsSync, readdirSync } from 'node:fs'
+import path from 'node:path'
+
+import micromatch from 'micromatch'
+
+import { readPackageJson } from '@socketsecurity/lib-stable/packages/read'
+import { normalizePath } from '@socketsecurity/lib-stable/paths/normalize'
+
+import { globWorkspace } from './glob.mts'
+import { NPM, PNPM } from '../../constants.mts'
+import {
+  PACKAGE_LOCK_JSON,
+  PNPM_LOCK_YAML,
+  YARN_LOCK,
+} from '../../constants/paths.mts'
+import { isLocalLockfilePath } from '../lockfile/parsers.mts'
+
+import type { SocketArtifact } from '../alert/artifact.mts'
+
+export type MonorepoWorkspace = {
+  name: string
+  // Directory relative to the monorepo root, `.` for the root workspace.
+  path: string
+}
+
+export const ROOT_WORKSPACE_PATH = '.'
+
+// Root lockfiles resolve the dependencies of every workspace.
+const ROOT_LOCKFILES = new Set([PACKAGE_LOCK_JSON, PNPM_LOCK_YAML, YARN_LOCK])
+
+/**
+ * The root workspace followed by the declared workspaces, sorted by path.
+ * Without declared workspaces the root is the only one.
+ */
+export async function getMonorepoWorkspaces(
+  cwd: string,
+): Promise<MonorepoWorkspace[]> {
+  const agent = existsSync(path.join(cwd, 'pnpm-workspace.yaml')) ? PNPM : NPM
+  const rootPkgJson = await readPackageJson(cwd, { throws: false })
+  const workspaces: MonorepoWorkspace[] = []
+  const pkgJsonPaths = await globWorkspace(agent, cwd)
+  for (let i = 0, { length } = pkgJsonPaths; i < length; i += 1) {
+    const dir = path.dirname(pkgJsonPaths[i]!)
+    const relPath = normalizePath(path.relative(cwd, dir))
+    if (!relPath || relPath === ROOT_WORKSPACE_PATH) {
+      continue
+    }
+    const pkgJson = await readPackageJson(dir, { throws: false })
+    workspaces.push({ name: pkgJson?.name || relPath, path: relPath })
+  }
+  workspaces.sort((a, b) => a.path.localeCompare(b.path))
+  return [
+    {
+      name: rootPkgJson?.name || ROOT_WORKSPACE_PATH,
+      path: ROOT_WORKSPACE_PATH,
+    },
+    ...workspaces,
+  ]
+}
+
+/**
+ * The workspace a file relative to the monorepo root belongs to: the one with
+ * the longest matching directory, else the root workspace.
+ */
+export function findWorkspaceOfFile(
+  workspaces: MonorepoWorkspace[],
+  relFile: string,
+): MonorepoWorkspace | undefined {
+  const file = normalizePath(relFile)
+  let found: MonorepoWorkspace | undefined
+  for (let i = 0, { length } = workspaces; i < length; i += 1) {
+    const workspace = workspaces[i]!
+    if (workspace.path === ROOT_WORKSPACE_PATH) {
+      found ??= workspace
+      continue
+    }
+    if (
+      file.startsWith(`${workspace.path}/`) &&
+      (!found ||
+        found.path === ROOT_WORKSPACE_PATH ||
+        workspace.path.length > found.path.length)
+    ) {
+      found = workspace
+    }
+  }
+  return found
+}
+
+/**
+ * Workspaces whose name or path matches one of the patterns, e.g. `@acme/api`,
+ * `@acme/*` or `packages/*`.
+ */
+export function filterMonorepoWorkspaces(
+  workspaces: MonorepoWorkspace[],
+  patterns: string[],
+): MonorepoWorkspace[] {
+  return workspaces.filter(
+    w =>
+      micromatch.isMatch(w.name, patterns) ||
+      micromatch.isMatch(w.path, patterns),
+  )
+}
+
+/**
+ * Workspaces with a changed file. A changed root lockfile affects every
+ * workspace.
+ */
+export function getChangedMonorepoWorkspaces(
+  workspaces: MonorepoWorkspace[],
+  changedFiles: string[],
+): MonorepoWorkspace[] {
+  const changed = new Set<MonorepoWorkspace>()
+  for (let i = 0, { length } = changedFiles; i < length; i += 1) {
+    const file = normalizePath(changedFiles[i]!)
+    if (
+      !file.includes('/') &&
+      (ROOT_LOCKFILES.has(file) || isLocalLockfilePath(file))
+    ) {
+      return workspaces
+    }
+    const workspace = findWorkspaceOfFile(workspaces, file)
+    if (workspace) {
+      changed.add(workspace)
+    }
+  }
+  return workspaces.filter(w => changed.has(w))
+}
+
+/**
+ * Scan targets covering the given workspaces: their directories, and the
+ * files directly in the root for the root workspace.
+ */
+export function getMonorepoWorkspaceTargets(
+  cwd: string,
+  workspaces: MonorepoWorkspace[],
+): string[] {
+  const targets: string[] = []
+  for (let i = 0, { length } = workspaces; i < length; i += 1) {
+    const workspace = workspaces[i]!
+    if (workspace.path !== ROOT_WORKSPACE_PATH) {
+      targets.push(path.join(cwd, workspace.path))
+      continue
+    }
+    try {
+      for (const entry of readdirSync(cwd, { withFileTypes: true })) {
+        if (entry.isFile()) {
+          targets.push(path.join(cwd, entry.name))
+        }
+      }
+    } catch {}
+  }
+  return targets
+}
+
+/**
+ * Split scan artifacts by the workspaces of their manifest files. Artifacts
+ * without manifest files go to the root workspace. Pass every workspace, not
+ * a filtered list, so files of the others are not taken for root files.
+ */
+export function groupArtifactsByWorkspace(
+  artifacts: SocketArtifact[],
+  workspaces: MonorepoWorkspace[],
+): Map<MonorepoWorkspace, SocketArtifact[]> {
+  const groups = new Map<MonorepoWorkspace, SocketArtifact[]>(
+    workspaces.map(w => [w, []]),
+  )
+  const root = workspaces.find(w => w.path === ROOT_WORKSPACE_PATH)
+  for (let i = 0, { length } = artifacts; i < length; i += 1) {
+    const artifact = artifacts[i]!
+    const owners = new Set<MonorepoWorkspace>()
+    const manifestFiles = artifact.manifestFiles ?? []
+    for (let j = 0, { length: count } = manifestFiles; j < count; j += 1) {
+      const owner = findWorkspaceOfFile(workspaces, manifestFiles[j]!.file)
+      if (owner) {
+        owners.add(owner)
+      }
+    }
+    if (!owners.size && root) {
+      owners.add(root)
+    }
+    for (const owner of owners) {
+      groups.get(owner)?.push(artifact)
+    }
+  }
+  return groups
+}
@@ -0,0 +1,123 @@
+/**
+ * Git plumbing for `socket hooks`: where a repository keeps its hooks and
+ * which files a commit or push changes.
+ *
+ * Extracted from operations.mts to keep that file under the 1000-line
+ * File size hard cap.
+ */
+
+import path from 'node:path'
+
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { normalizePath } from '@socketsecurity/lib-stable/paths/normalize'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import { getGitPath } from './git-path.mts'
+
+import type { CResult } from '../../types.mjs'
+
+// The object id of the empty tree, a base that every file was added to.
+export const GIT_EMPTY_TREE = '4b825dc642cb6eb9a060e54bf8d69288fbee4904'
+
+async function gitRevParse(
+  args: string[],
+  cwd: string,
+): Promise<string | undefined> {
+  try {
+    const gitBin = await getGitPath()
+    const result = await spawn(gitBin, ['rev-parse', ...args], { cwd })
+    return result.stdout.trim() || undefined
+  } catch (e) {
+    debug(`Failed to run git rev-parse ${args.join(' ')}`)
+    debugDir(e)
+  }
+  return undefined
+}
+
+/**
+ * Absolute path of the hooks directory, honoring core.hooksPath and linked
+ * worktrees. Undefined outside a git repository.
+ */
+export async function gitHooksDir(
+  cwd = process.cwd(),
+): Promise<string | undefined> {
+  const hooksPath = await gitRevParse(['--git-path', 'hooks'], cwd)
+  return hooksPath ? path.resolve(cwd, hooksPath) : undefined
+}
+
+/**
+ * Absolute path of the root of the working tree. Undefined outside a git
+ * repository.
+ */
+export async function gitTopLevel(
+  cwd = process.cwd(),
+): Promise<string | undefined> {
+  return await gitRevParse(['--show-toplevel'], cwd)
+}
+
+/**
+ * The commit id a revision names, or undefined when it does not exist, e.g.
+ * HEAD before the first commit.
+ */
+export async function gitResolveCommit(
+  revision: string,
+  cwd = process.cwd(),
+): Promise<string | undefined> {
+  return await gitRevParse(['--verify', '--quiet', `${revision}^{commit}`], cwd)
+}
+
+/**
+ * The merge base of two revisions, or undefined when they share no history
+ * or either does not exist locally.
+ */
+export async function gitMergeBase(
+  a: string,
+  b: string,
+  cwd = process.cwd(),
+): Promise<string | undefined> {
+  try {
+    const gitBin = await getGitPath()
+    const result = await spawn(gitBin, ['merge-base', a, b], { cwd })
+    return result.stdout.trim() || undefined
+  } catch (e) {
+    debug(`Failed to find the merge base of ${a} and ${b}`)
+    debugDir(e)
+  }
+  return undefined
+}
+
+/**
+ * Files added, copied, modified or renamed between two revisions, or between
+ * HEAD and the index when `to` is undefined. Paths are relative to the root
+ * of the working tree.
+ */
+export async function gitDiffChangedFiles(
+  from: string,
+  to: string | undefined,
+  cwd = process.cwd(),
+): Promise<CResult<string[]>> {
+  const range = to === undefined ? ['--cached', from] : [from, to]
+  try {
+    const gitBin = await getGitPath()
+    const result = await spawn(
+      gitBin,
+      ['diff', '--name-only', '--no-renames', '--diff-filter=ACM', ...range],
+      { cwd },
+    )
+    return {
+      ok: true,
+      data: result.stdout
+        .split(/\r?\n/)
+        .filter(Boolean)
+        .map((p: string) => normalizePath(p)),
+    }
+  } catch (e) {
+    debug(`Failed to list the files changed in ${range.join(' ')}`)
+    debugDir(e)
+    return {
+      ok: false,
+      message: 'Git Error',
+      cause: `Could not list the files changed between \`${from}\` and \`${to ?? 'the index'}\`.`,
+    }
+  }
+}
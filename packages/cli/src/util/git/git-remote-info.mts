@@ -146,6 +146,68 @@ export async function gitUnstagedModifiedFiles(
   }
 }
 
+/**
+ * Files changed since the merge base of `ref` and HEAD, including uncommitted
+ * and untracked files, relative to cwd.
+ */
+export async function gitChangedFilesSince(
+  ref: string,
+  cwd = process.cwd(),
+): Promise<CResult<string[]>> {
+  try {
+    const gitBin = await getGitPath()
+    const diffResult = await spawn(
+      gitBin,
+      ['diff', '--name-only', '--relative', '--merge-base', ref],
+      { cwd },
+    )
+    const untrackedResult = await spawn(
+      gitBin,
+      ['ls-files', '--others', '--exclude-standard'],
+      { cwd },
+    )
+    const relPaths = `${diffResult.stdout}\n${untrackedResult.stdout}`
+      .split('\n')
+      .filter(Boolean)
+    return {
+      ok: true,
+      data: [...new Set(relPaths.map((p: string) => normalizePath(p)))],
+    }
+  } catch (e) {
+    debug(`Failed to list files changed since ${ref}`)
+    debugDir(e)
+    return {
+      ok: false,
+      message: 'Git Error',
+      cause: `Could not list the files changed since \`${ref}\`. Make sure the ref exists locally, e.g. fetch it in shallow CI checkouts.`,
+    }
+  }
+}
+
+/**
+ * Content of a file at a revision, or undefined when it did not exist there.
+ * The path is relative to cwd.
+ */
+export async function gitShowFile(
+  relPath: string,
+  revision = 'HEAD',
+  cwd = process.cwd(),
+): Promise<string | undefined> {
+  try {
+    const gitBin = await getGitPath()
+    const result = await spawn(
+      gitBin,
+      ['show', `${revision}:./${normalizePath(relPath)}`],
+      { cwd },
+    )
+    return result.stdout
+  } catch (e) {
+    debug(`Failed to read ${relPath} at ${revision}`)
+    debugDir(e)
+  }
+  return undefined
+}
+
 export function parseGitRemoteUrl(remoteUrl: string): RepoInfo | undefined {
   let result = parsedGitRemoteUrlCache.get(remoteUrl)
   if (result) {
@@ -0,0 +1,158 @@
+/**
+ * GitHub App authentication for `socket ghapp sync`.
+ *
+ * The app authenticates with a JWT signed by its private key, and exchanges
+ * it for an installation access token, which acts on the repositories the
+ * installation was granted. Installation tokens expire after an hour, so long
+ * runs fetch a new one before it runs out.
+ *
+ * See https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app
+ */
+
+import { createSign } from 'node:crypto'
+
+import { Octokit } from '@octokit/rest'
+
+import { withGitHubRetry } from './github-errors.mts'
+import { GITHUB_API_URL } from '../../env/github-api-url.mts'
+import { getErrorCause } from '../error/errors.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type GitHubAppCredentials = {
+  appId: string
+  // Unset to pick the only installation of the app.
+  installationId?: string | undefined
+  privateKey: string
+}
+
+export type GitHubAppInstallationToken = {
+  account: string
+  expiresAt: number
+  installationId: number
+  token: string
+}
+
+// GitHub rejects app JWTs that expire more than 10 minutes out.
+const JWT_LIFETIME_SECONDS = 9 * 60
+
+// Backdates the JWT against clock drift, as GitHub recommends.
+const JWT_CLOCK_DRIFT_SECONDS = 60
+
+// Fetch a new installation token when the current one has less left.
+const TOKEN_REFRESH_MARGIN_MS = 5 * 60 * 1000
+
+// Apps are installed on users and organizations, which have a login, or on
+// enterprises, which have a slug.
+function getAccountName(account: unknown): string {
+  const { login, slug } = (account ?? {}) as {
+    login?: string | undefined
+    slug?: string | undefined
+  }
+  return login || slug || ''
+}
+
+function base64UrlJson(value: unknown): string {
+  return Buffer.from(JSON.stringify(value)).toString('base64url')
+}
+
+export function createGitHubAppJwt(
+  appId: string,
+  privateKey: string,
+  nowMs = Date.now(),
+): string {
+  const now = Math.floor(nowMs / 1000)
+  const header = base64UrlJson({ alg: 'RS256', typ: 'JWT' })
+  const payload = base64UrlJson({
+    exp: now + JWT_LIFETIME_SECONDS,
+    iat: now - JWT_CLOCK_DRIFT_SECONDS,
+    iss: appId,
+  })
+  const signature = createSign('RSA-SHA256')
+    .update(`${header}.${payload}`)
+    .sign(privateKey, 'base64url')
+  return `${header}.${payload}.${signature}`
+}
+
+export function isGitHubAppTokenExpiring(
+  token: GitHubAppInstallationToken,
+  nowMs = Date.now(),
+): boolean {
+  return token.expiresAt - nowMs < TOKEN_REFRESH_MARGIN_MS
+}
+
+export async function fetchGitHubAppInstallationToken(
+  credentials: GitHubAppCredentials,
+): Promise<CResult<GitHubAppInstallationToken>> {
+  const { appId, installationId, privateKey } = {
+    __proto__: null,
+    ...credentials,
+  } as GitHubAppCredentials
+
+  let jwt: string
+  try {
+    jwt = createGitHubAppJwt(appId, privateKey)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Invalid GitHub App private key',
+      cause: `Signing the app JWT failed: ${getErrorCause(e)}. Expecting the PEM private key downloaded from the settings of the app.`,
+    }
+  }
+  const appOctokit = new Octokit({
+    auth: jwt,
+    ...(GITHUB_API_URL ? { baseUrl: GITHUB_API_URL } : {}),
+  })
+
+  const installationsCResult = await withGitHubRetry(
+    () =>
+      appOctokit.paginate(appOctokit.apps.listInstallations, {
+        per_page: 100,
+      }),
+    `listing the installations of GitHub App ${appId}`,
+  )
+  if (!installationsCResult.ok) {
+    return installationsCResult
+  }
+  const installations = installationsCResult.data
+  const installation = installationId
+    ? installations.find(i => String(i.id) === installationId)
+    : installations.length === 1
+      ? installations[0]
+      : undefined
+  if (!installation) {
+    const known = installations
+      .map(i => `${i.id} (${getAccountName(i.account) || 'unknown'})`)
+      .join(', ')
+    return {
+      ok: false,
+      message: installationId
+        ? 'GitHub App installation not found'
+        : 'GitHub App installation not specified',
+      cause: installations.length
+        ? `Installations of GitHub App ${appId}: ${known}. Pass one with --installation-id.`
+        : `GitHub App ${appId} is not installed on any account.`,
+    }
+  }
+
+  const tokenCResult = await withGitHubRetry(
+    () =>
+      appOctokit.apps.createInstallationAccessToken({
+        installation_id: installation.id,
+      }),
+    `creating an access token for installation ${installation.id}`,
+  )
+  if (!tokenCResult.ok) {
+    return tokenCResult
+  }
+  const { expires_at, token } = tokenCResult.data.data
+  return {
+    ok: true,
+    data: {
+      account: getAccountName(installation.account),
+      expiresAt: Date.parse(expires_at),
+      installationId: installation.id,
+      token,
+    },
+  }
+}
@@ -14,6 +14,7 @@ import { writeJson } from '@socketsecurity/lib-stable/fs/write-json'
 
 import { DISABLE_GITHUB_CACHE } from '../../env/disable-github-cache.mts'
 import { getGithubCachePath } from '../../constants/paths.mts'
+import { getCacheOverrides } from '../cache/overrides.mts'
 
 import type { JsonContent } from '@socketsecurity/lib-stable/fs/types'
 
@@ -36,6 +37,9 @@ export async function cacheFetch<T>(
     return await fetcher()
   }
   /* c8 ignore stop */
+  if (getCacheOverrides().disabled) {
+    return await fetcher()
+  }
 
   // Check if already fetching this key to prevent TOCTOU race.
   const inflight = inflightRequests.get(key)
@@ -82,6 +86,12 @@ export async function readCache(
   // 5 minute in milliseconds time to live (TTL).
   ttlMs = 5 * 60 * 1000,
 ): Promise<JsonContent | undefined> {
+  const { disabled, ttlMs: ttlMsOverride } = getCacheOverrides()
+  if (disabled) {
+    return undefined
+  }
+  // --cache-ttl wins over the TTL of the caller.
+  const effectiveTtlMs = ttlMsOverride ?? ttlMs
   const githubCachePath = getGithubCachePath()
   const cacheJsonPath = path.join(githubCachePath, `${key}.json`)
 
@@ -92,7 +102,7 @@ export async function readCache(
       const { data, timestamp } = entry
       /* c8 ignore start - cache fresh-hit + legacy-format branches; tests pre-populate cache files in only one format */
       if (typeof timestamp === 'number' && data !== undefined) {
-        const isExpired = Date.now() - timestamp > ttlMs
+        const isExpired = Date.now() - timestamp > effectiveTtlMs
         if (!isExpired) {
           return data
         }
@@ -25,6 +25,8 @@ import type {
   PrProvider,
   PrResponse,
   UpdatePrOptions,
+  UpsertCommentOptions,
+  UpsertCommentResult,
 } from './provider.mts'
 
 export type GqlPrNode = {
@@ -327,6 +329,59 @@ export class GitHubProvider implements PrProvider {
     debug(`pr: added comment to PR #${prNumber}`)
   }
 
+  /**
+   * Update the PR comment that contains the marker, or add one when there is
+   * none yet, so repeated runs keep a single comment current.
+   */
+  async upsertComment(
+    config: UpsertCommentOptions,
+  ): Promise<UpsertCommentResult> {
+    const { body, marker, owner, prNumber, repo } = {
+      __proto__: null,
+      ...config,
+    } as typeof config
+    const octokit = getOctokit()
+
+    const listResult = await withGitHubRetry(
+      () =>
+        octokit.paginate(octokit.issues.listComments, {
+          issue_number: prNumber,
+          owner,
+          per_page: 100,
+          repo,
+        }),
+      `listing comments of PR #${prNumber}`,
+    )
+    if (!listResult.ok) {
+      throw new Error(listResult.cause || listResult.message)
+    }
+
+    const existing = listResult.data.find(comment =>
+      comment.body?.includes(marker),
+    )
+    if (!existing) {
+      await this.addComment({ body, owner, prNumber, repo })
+      return 'created'
+    }
+
+    const updateResult = await withGitHubRetry(
+      () =>
+        octokit.issues.updateComment({
+          body,
+          comment_id: existing.id,
+          owner,
+          repo,
+        }),
+      `updating comment ${existing.id} on PR #${prNumber}`,
+    )
+    if (!updateResult.ok) {
+      throw new Error(updateResult.cause || updateResult.message)
+    }
+
+    debug(`pr: updated comment ${existing.id} on PR #${prNumber}`)
+    return 'updated'
+  }
+
   getProviderName(): 'github' {
     return 'github'
   }
@@ -7,6 +7,7 @@
  * - GetGitHubToken: Retrieve GitHub token from env/git config
  * - GetOctokit: Get authenticated Octokit instance
  * - GetOctokitGraphql: Get authenticated GraphQL client
+ * - SetOctokitAuth: Authenticate as a GitHub App installation instead
  *
  * Caching:
  *
@@ -67,6 +68,9 @@ let octokit: Octokit | undefined
 
 let octokitGraphql: typeof OctokitGraphql | undefined
 
+// Replaces SOCKET_CLI_GITHUB_TOKEN, e.g. with a GitHub App installation token.
+let octokitAuth: string | undefined
+
 export type PrAutoMergeState = {
   enabled: boolean
   details?: string[] | undefined
@@ -205,11 +209,12 @@ export async function fetchGhsaDetails(
 
 export function getOctokit(): Octokit {
   if (octokit === undefined) {
-    if (!SOCKET_CLI_GITHUB_TOKEN) {
+    const auth = octokitAuth || SOCKET_CLI_GITHUB_TOKEN
+    if (!auth) {
       debugNs('notice', 'miss: SOCKET_CLI_GITHUB_TOKEN env var')
     }
     const octokitOptions = {
-      ...(SOCKET_CLI_GITHUB_TOKEN ? { auth: SOCKET_CLI_GITHUB_TOKEN } : {}),
+      ...(auth ? { auth } : {}),
       ...(GITHUB_API_URL ? { baseUrl: GITHUB_API_URL } : {}),
     }
     debugDirNs('inspect', { octokitOptions })
@@ -220,18 +225,30 @@ export function getOctokit(): Octokit {
 
 export function getOctokitGraphql(): typeof OctokitGraphql {
   if (!octokitGraphql) {
-    if (!SOCKET_CLI_GITHUB_TOKEN) {
+    const auth = octokitAuth || SOCKET_CLI_GITHUB_TOKEN
+    if (!auth) {
       debugNs('notice', 'miss: SOCKET_CLI_GITHUB_TOKEN env var')
     }
     octokitGraphql = OctokitGraphql.defaults({
       headers: {
-        authorization: `token ${SOCKET_CLI_GITHUB_TOKEN}`,
+        authorization: `token ${auth}`,
       },
     })
   }
   return octokitGraphql
 }
 
+/**
+ * Authenticate the GitHub clients with this token instead of
+ * SOCKET_CLI_GITHUB_TOKEN, or with undefined go back to it. The clients are
+ * created again on their next use.
+ */
+export function setOctokitAuth(token: string | undefined): void {
+  octokitAuth = token
+  octokit = undefined
+  octokitGraphql = undefined
+}
+
 export async function setGitRemoteGithubRepoUrl(
   owner: string,
   repo: string,
@@ -15,6 +15,8 @@ import type {
   PrProvider,
   PrResponse,
   UpdatePrOptions,
+  UpsertCommentOptions,
+  UpsertCommentResult,
 } from './provider.mts'
 import type { MergeRequestSchema } from '@gitbeaker/rest'
 
@@ -29,12 +31,9 @@ export class GitLabProvider implements PrProvider {
   private gitlab: InstanceType<typeof Gitlab>
 
   constructor() {
-    const token = getGitLabToken()
-    const host = process.env['GITLAB_HOST'] || 'https://gitlab.com'
-
     this.gitlab = new Gitlab({
-      host,
-      token,
+      host: getGitLabHost(),
+      token: getGitLabToken(),
     })
   }
 
@@ -266,6 +265,50 @@ export class GitLabProvider implements PrProvider {
     }
   }
 
+  /**
+   * Update the MR note that contains the marker, or add one when there is
+   * none yet, so repeated runs keep a single note current.
+   */
+  async upsertComment(
+    config: UpsertCommentOptions,
+  ): Promise<UpsertCommentResult> {
+    const { body, marker, owner, prNumber, repo } = {
+      __proto__: null,
+      ...config,
+    } as typeof config
+    const projectId = `${owner}/${repo}`
+
+    try {
+      const notes = await this.gitlab.MergeRequestNotes.all(
+        projectId,
+        prNumber,
+      )
+      const existing = notes.find(
+        note => typeof note.body === 'string' && note.body.includes(marker),
+      )
+      if (!existing) {
+        await this.gitlab.MergeRequestNotes.create(projectId, prNumber, body)
+        debug(`mr: added comment to MR !${prNumber}`)
+        return 'created'
+      }
+      await this.gitlab.MergeRequestNotes.edit(
+        projectId,
+        prNumber,
+        existing.id as number,
+        { body },
+      )
+      debug(`mr: updated note ${existing.id} on MR !${prNumber}`)
+      return 'updated'
+    } catch (e) {
+      throw new Error(
+        formatErrorWithDetail(
+          `Failed to update comment on MR !${prNumber}`,
+          e,
+        ),
+      )
+    }
+  }
+
   getProviderName(): 'gitlab' {
     return 'gitlab'
   }
@@ -296,6 +339,18 @@ export function getGitLabToken(): string {
   )
 }
 
+/**
+ * The GitLab instance to talk to. GitLab CI sets CI_SERVER_URL to the instance
+ * running the pipeline.
+ */
+export function getGitLabHost(): string {
+  return (
+    process.env['GITLAB_HOST'] ||
+    process.env['CI_SERVER_URL'] ||
+    'https://gitlab.com'
+  )
+}
+
 /**
  * Maps GitLab merge_status to common merge state status.
  */
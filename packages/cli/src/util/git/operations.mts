@@ -18,7 +18,11 @@
  * (main/master/develop/etc — inclusive-language: external-api) - getBaseBranch:
  * Determine base branch (respects GitHub Actions env) - getRepoInfo: Extract
  * owner/repo from git remote URL - gitBranch: Get current branch or commit
- * hash.
+ * hash - gitChangedFilesSince: Files changed since a ref.
+ *
+ * Hook Plumbing: - gitHooksDir: Hooks directory - gitDiffChangedFiles: Files
+ * a commit or push changes - gitMergeBase / gitResolveCommit: Revisions to
+ * compare.
  */
 
 // Git executable resolution extracted to keep this file under the
@@ -50,6 +54,17 @@ export {
   type GitCreateAndPushBranchOptions,
 } from './git-commit-ops.mts'
 
+// Hook plumbing for `socket hooks` extracted to keep this file under the
+// 1000-line File-size cap. See git-hook-ops.mts.
+export {
+  GIT_EMPTY_TREE,
+  gitDiffChangedFiles,
+  gitHooksDir,
+  gitMergeBase,
+  gitResolveCommit,
+  gitTopLevel,
+} from './git-hook-ops.mts'
+
 // Remote-repository info + URL parsing extracted to keep this file under
 // the 1000-line File-size cap. See git-remote-info.mts.
 export {
@@ -57,6 +72,8 @@ export {
   getRepoName,
   getRepoOwner,
   gitBranch,
+  gitChangedFilesSince,
+  gitShowFile,
   gitUnstagedModifiedFiles,
   parseGitRemoteUrl,
   type RepoInfo,
@@ -12,6 +12,7 @@ export interface PrProvider {
   listPrs(options: ListPrsOptions): Promise<PrMatch[]>
   deleteBranch(branch: string): Promise<boolean>
   addComment(options: AddCommentOptions): Promise<void>
+  upsertComment(options: UpsertCommentOptions): Promise<UpsertCommentResult>
 
   // Metadata.
   getProviderName(): 'github' | 'gitlab'
@@ -43,6 +44,13 @@ export interface AddCommentOptions {
   body: string
 }
 
+export interface UpsertCommentOptions extends AddCommentOptions {
+  // Hidden text, e.g. an HTML comment, that identifies the comment to update.
+  marker: string
+}
+
+export type UpsertCommentResult = 'created' | 'updated'
+
 export interface ListPrsOptions {
   owner: string
   repo: string
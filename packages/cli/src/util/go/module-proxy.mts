@@ -0,0 +1,189 @@
+/**
+ * Resolve `go get`/`go install` queries to module versions through the Go
+ * module proxy protocol (https://go.dev/ref/mod#goproxy-protocol), the same
+ * way the go command does: a package path is tried against each enclosing
+ * module path, longest first, until the proxy knows one.
+ *
+ * The proxy and private-module settings come from `go env`, so GOPROXY and
+ * GONOPROXY/GOPRIVATE set with `go env -w` are honored.
+ */
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import { getErrorCause } from '../error/errors.mts'
+import { socketHttpRequest } from '../socket/api-http.mts'
+
+import type { CResult } from '../../types.mts'
+
+export const DEFAULT_GOPROXY = 'https://proxy.golang.org'
+
+export type GoProxyConfig = {
+  // First proxy URL in GOPROXY, or undefined when only `direct`/`off` remain.
+  proxyUrl: string | undefined
+  // GONOPROXY glob patterns (defaults to GOPRIVATE).
+  noProxy: string[]
+}
+
+export type GoModuleVersion = {
+  path: string
+  version: string
+}
+
+/**
+ * Escape a module path or version for proxy URLs: upper-case letters become
+ * `!` followed by the lower-case letter.
+ */
+export function escapeGoModulePath(modPath: string): string {
+  return modPath.replace(/[A-Z]/g, c => `!${c.toLowerCase()}`)
+}
+
+export function parseGoProxyList(
+  goproxy: string | undefined,
+): string | undefined {
+  const entries = (goproxy || DEFAULT_GOPROXY).split(/[,|]/)
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const entry = entries[i]!.trim()
+    if (entry === 'off') {
+      return undefined
+    }
+    if (entry && entry !== 'direct') {
+      return entry.replace(/\/+$/, '')
+    }
+  }
+  return undefined
+}
+
+// path.Match semantics for a single path element: `*` and `?` never match `/`.
+function goGlobToRegExp(glob: string): RegExp {
+  const source = glob
+    .replace(/[.+^${}()|\\]/g, '\\$&')
+    .replace(/\*/g, '[^/]*')
+    .replace(/\?/g, '[^/]')
+  return new RegExp(`^${source}$`)
+}
+
+/**
+ * Whether any element prefix of modPath matches one of the GONOPROXY glob
+ * patterns, per `go help private`.
+ */
+export function matchesGoPrivate(
+  modPath: string,
+  patterns: readonly string[],
+): boolean {
+  const parts = modPath.split('/')
+  for (let i = 0, { length } = patterns; i < length; i += 1) {
+    const pattern = patterns[i]!
+    const patternParts = pattern.split('/')
+    if (patternParts.length > parts.length) {
+      continue
+    }
+    if (
+      patternParts.every((part, index) =>
+        goGlobToRegExp(part).test(parts[index]!),
+      )
+    ) {
+      return true
+    }
+  }
+  return false
+}
+
+export async function getGoProxyConfig(
+  goBin = 'go',
+): Promise<CResult<GoProxyConfig>> {
+  let goEnv: Record<string, string | undefined>
+  try {
+    const result = await spawn(
+      goBin,
+      ['env', '-json', 'GOPROXY', 'GONOPROXY'],
+      { stdio: 'pipe' },
+    )
+    goEnv = JSON.parse(String(result.stdout ?? '')) as Record<
+      string,
+      string | undefined
+    >
+  } catch (e) {
+    debugDir('error', e)
+    return {
+      ok: false,
+      message: 'Unable to read Go settings',
+      cause: `\`${goBin} env\` failed: ${getErrorCause(e)}`,
+    }
+  }
+  return {
+    ok: true,
+    data: {
+      proxyUrl: parseGoProxyList(goEnv['GOPROXY']),
+      noProxy: (goEnv['GONOPROXY'] ?? '')
+        .split(',')
+        .map(p => p.trim())
+        .filter(Boolean),
+    },
+  }
+}
+
+async function fetchGoProxyInfo(
+  proxyUrl: string,
+  modPath: string,
+  query: string,
+): Promise<CResult<string | undefined>> {
+  const escaped = escapeGoModulePath(modPath)
+  const url =
+    query === 'latest'
+      ? `${proxyUrl}/${escaped}/@latest`
+      : `${proxyUrl}/${escaped}/@v/${escapeGoModulePath(query)}.info`
+  try {
+    const response = await socketHttpRequest(url)
+    // The proxy answers 404/410 for paths that are not modules and for
+    // unknown versions; the caller moves on to the next candidate.
+    if (response.status === 404 || response.status === 410) {
+      return { ok: true, data: undefined }
+    }
+    if (!response.ok) {
+      return {
+        ok: false,
+        message: 'Unable to resolve Go module',
+        cause: `${url} returned HTTP ${response.status}`,
+      }
+    }
+    const info = response.json() as { Version?: unknown }
+    return {
+      ok: true,
+      data: typeof info.Version === 'string' ? info.Version : undefined,
+    }
+  } catch (e) {
+    debugDir('error', e)
+    return {
+      ok: false,
+      message: 'Unable to resolve Go module',
+      cause: `Could not fetch ${url}: ${getErrorCause(e)}`,
+    }
+  }
+}
+
+/**
+ * Resolve `pkgPath@query` to the module that provides it and the version the
+ * query selects. `upgrade` and `patch` are treated as `latest`. Resolves to
+ * undefined when no enclosing module is known to the proxy, which the go
+ * command will report itself.
+ */
+export async function resolveGoModule(
+  proxyUrl: string,
+  pkgPath: string,
+  query = 'latest',
+): Promise<CResult<GoModuleVersion | undefined>> {
+  const proxyQuery = query === 'upgrade' || query === 'patch' ? 'latest' : query
+  const parts = pkgPath.replace(/\/\.\.\.$/, '').split('/')
+  for (let end = parts.length; end > 0; end -= 1) {
+    const modPath = parts.slice(0, end).join('/')
+    const infoCResult = await fetchGoProxyInfo(proxyUrl, modPath, proxyQuery)
+    if (!infoCResult.ok) {
+      return infoCResult
+    }
+    if (infoCResult.data) {
+      return { ok: true, data: { path: modPath, version: infoCResult.data } }
+    }
+  }
+  return { ok: true, data: undefined }
+}
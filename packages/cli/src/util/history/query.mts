@@ -0,0 +1,386 @@
+/**
+ * Queries of the local scan history for `socket history query`, and the
+ * alert diff of two recorded scans for `socket history diff`.
+ *
+ * A query is a few plain words, e.g. "new criticals in the last 30 days":
+ *
+ * - `new`, `fixed` or `open`: alerts first seen, gone or still present
+ * - `critical`, `high`, `medium` or `low`, also in plural: the severities
+ * - `last 30 days`, `past week`, `last 12h` or `since 2026-01-31`: the time
+ * - `repo NAME`, `branch NAME`, `type ALERT_TYPE` and `package NAME`
+ *
+ * Words like "in", "the" and "alerts" are ignored. Scans are grouped into
+ * streams by org, repo and branch, and an alert is tracked along its stream
+ * by its version-less identity, like `socket scan diff` does: it is new when
+ * it first shows up, fixed once a later scan of the stream no longer has it,
+ * and open while the latest scan still does.
+ */
+
+import { joinAnd } from '@socketsecurity/lib-stable/arrays/join'
+
+import { parseCacheDuration } from '../cache/overrides.mts'
+
+import type { HistoryAlert, HistoryData, HistoryScan } from './store.mts'
+import type { CResult } from '../../types.mts'
+
+export const HISTORY_QUERY_STATUSES = ['new', 'fixed', 'open'] as const
+
+export type HistoryQueryStatus = (typeof HISTORY_QUERY_STATUSES)[number]
+
+export type HistoryQuery = {
+  alertType?: string | undefined
+  branch?: string | undefined
+  packageName?: string | undefined
+  repo?: string | undefined
+  severities?: string[] | undefined
+  // Lower bound of the time window, in ms since epoch.
+  since?: number | undefined
+  status?: HistoryQueryStatus | undefined
+}
+
+export type HistoryQueryRow = {
+  branch?: string | undefined
+  firstSeen: number
+  // When the first scan without the alert was recorded.
+  fixedAt?: number | undefined
+  identity: string
+  lastSeen: number
+  orgSlug: string
+  purl: string
+  repo?: string | undefined
+  // The number of scans of the stream that had the alert.
+  scans: number
+  severity: string
+  status: 'fixed' | 'open'
+  type: string
+}
+
+export type HistoryScanDiff = {
+  added: HistoryAlert[]
+  after: HistoryScan
+  before: HistoryScan
+  removed: HistoryAlert[]
+}
+
+const DAY_MS = 24 * 60 * 60 * 1000
+
+const TIME_UNITS_MS: Record<string, number> = {
+  __proto__: null,
+  day: DAY_MS,
+  days: DAY_MS,
+  hour: 60 * 60 * 1000,
+  hours: 60 * 60 * 1000,
+  month: 30 * DAY_MS,
+  months: 30 * DAY_MS,
+  week: 7 * DAY_MS,
+  weeks: 7 * DAY_MS,
+  year: 365 * DAY_MS,
+  years: 365 * DAY_MS,
+} as unknown as Record<string, number>
+
+const SEVERITY_WORDS: Record<string, string> = {
+  __proto__: null,
+  critical: 'critical',
+  criticals: 'critical',
+  high: 'high',
+  highs: 'high',
+  low: 'low',
+  lows: 'low',
+  medium: 'middle',
+  mediums: 'middle',
+  middle: 'middle',
+} as unknown as Record<string, string>
+
+const STATUS_WORDS: Record<string, HistoryQueryStatus> = {
+  __proto__: null,
+  fixed: 'fixed',
+  new: 'new',
+  open: 'open',
+  resolved: 'fixed',
+} as unknown as Record<string, HistoryQueryStatus>
+
+const VALUE_WORDS: Record<string, keyof HistoryQuery> = {
+  __proto__: null,
+  branch: 'branch',
+  package: 'packageName',
+  repo: 'repo',
+  repository: 'repo',
+  type: 'alertType',
+} as unknown as Record<string, keyof HistoryQuery>
+
+const FILLER_WORDS = new Set([
+  'alert',
+  'alerts',
+  'all',
+  'and',
+  'for',
+  'from',
+  'in',
+  'of',
+  'on',
+  'over',
+  'the',
+  'with',
+])
+
+const SEVERITY_RANKS: Record<string, number> = {
+  __proto__: null,
+  critical: 4,
+  high: 3,
+  low: 1,
+  middle: 2,
+} as unknown as Record<string, number>
+
+function getSeverityRank(severity: string): number {
+  return SEVERITY_RANKS[severity] ?? 0
+}
+
+function parseQueryTime(
+  keyword: string,
+  words: string[],
+  now: number,
+): number | undefined {
+  if (keyword === 'since') {
+    const date = Date.parse(words.shift() ?? '')
+    return Number.isNaN(date) ? undefined : date
+  }
+  // "last 30 days", "last week" or "last 30d".
+  let count = 1
+  if (/^\d+$/.test(words[0] ?? '')) {
+    count = Number(words.shift())
+  }
+  const unit = (words.shift() ?? '').toLowerCase()
+  const unitMs = TIME_UNITS_MS[unit]
+  if (unitMs !== undefined) {
+    return now - count * unitMs
+  }
+  const durationMs = count === 1 ? parseCacheDuration(unit) : undefined
+  return durationMs === undefined ? undefined : now - durationMs
+}
+
+export function parseHistoryQuery(
+  text: string,
+  now: number = Date.now(),
+): CResult<HistoryQuery> {
+  const words = text.trim().split(/\s+/).filter(Boolean)
+  const query: HistoryQuery = {}
+  const unknown: string[] = []
+  while (words.length) {
+    const word = words.shift()!
+    const lower = word.toLowerCase()
+    if (FILLER_WORDS.has(lower)) {
+      continue
+    }
+    const status = STATUS_WORDS[lower]
+    if (status) {
+      query.status = status
+      continue
+    }
+    const severity = SEVERITY_WORDS[lower]
+    if (severity) {
+      query.severities = [...new Set([...(query.severities ?? []), severity])]
+      continue
+    }
+    if (lower === 'last' || lower === 'past' || lower === 'since') {
+      const since = parseQueryTime(lower, words, now)
+      if (since === undefined) {
+        return {
+          ok: false,
+          message: 'Invalid time in the query',
+          cause: `Expecting e.g. "last 30 days", "past week" or "since 2026-01-31" after "${word}"`,
+        }
+      }
+      query.since = since
+      continue
+    }
+    const key = VALUE_WORDS[lower]
+    if (key) {
+      const value = words.shift()
+      if (!value) {
+        return {
+          ok: false,
+          message: 'Invalid query',
+          cause: `Expecting a name after "${word}"`,
+        }
+      }
+      ;(query as Record<string, unknown>)[key] = value
+      continue
+    }
+    unknown.push(word)
+  }
+  if (unknown.length) {
+    return {
+      ok: false,
+      message: 'Invalid query',
+      cause: `Unknown ${unknown.length === 1 ? 'word' : 'words'} ${joinAnd(unknown.map(w => `"${w}"`))}. Use e.g. "new criticals in the last 30 days" or "fixed high alerts repo my-app since 2026-01-31"`,
+    }
+  }
+  return { ok: true, data: query }
+}
+
+function getStreamKey(scan: HistoryScan): string {
+  return `${scan.orgSlug}\0${scan.repo ?? ''}\0${scan.branch ?? ''}`
+}
+
+function matchesPackage(identity: string, packageName: string): boolean {
+  // The identity is `type:namespace/name:alertType`.
+  const fullName = identity.split(':')[1] ?? ''
+  return (
+    fullName === packageName ||
+    fullName === `/${packageName}` ||
+    fullName.endsWith(`/${packageName}`)
+  )
+}
+
+type AlertGroup = {
+  alerts: HistoryAlert[]
+  firstSeen: number
+  lastScan: HistoryScan
+  scanIds: Set<string>
+}
+
+/**
+ * Answer a query from the recorded scans. Scans whose results were never
+ * fetched are left out, they have no alerts to go by.
+ */
+export function runHistoryQuery(
+  data: HistoryData,
+  query: HistoryQuery,
+): HistoryQueryRow[] {
+  const { alertType, branch, packageName, repo, severities, since, status } =
+    query
+  const scansById = new Map<string, HistoryScan>()
+  const streams = new Map<string, HistoryScan[]>()
+  for (const scan of data.scans) {
+    if (
+      scan.packages === undefined ||
+      (repo !== undefined && scan.repo !== repo) ||
+      (branch !== undefined && scan.branch !== branch)
+    ) {
+      continue
+    }
+    scansById.set(scan.scanId, scan)
+    const key = getStreamKey(scan)
+    const stream = streams.get(key) ?? []
+    stream.push(scan)
+    streams.set(key, stream)
+  }
+  for (const stream of streams.values()) {
+    stream.sort((a, b) => a.recordedAt - b.recordedAt)
+  }
+
+  const groups = new Map<string, AlertGroup>()
+  for (const alert of data.alerts) {
+    const scan = scansById.get(alert.scanId)
+    if (!scan) {
+      continue
+    }
+    const key = `${getStreamKey(scan)}\0${alert.identity}`
+    const group = groups.get(key)
+    if (!group) {
+      groups.set(key, {
+        alerts: [alert],
+        firstSeen: scan.recordedAt,
+        lastScan: scan,
+        scanIds: new Set([scan.scanId]),
+      })
+      continue
+    }
+    group.scanIds.add(scan.scanId)
+    group.firstSeen = Math.min(group.firstSeen, scan.recordedAt)
+    if (scan.recordedAt > group.lastScan.recordedAt) {
+      group.lastScan = scan
+      group.alerts = [alert]
+    } else if (scan === group.lastScan) {
+      group.alerts.push(alert)
+    }
+  }
+
+  const rows: HistoryQueryRow[] = []
+  for (const group of groups.values()) {
+    const { lastScan } = group
+    // The alerts of the last scan that had it, the most severe first.
+    const alert = group.alerts.toSorted(
+      (a, b) => getSeverityRank(b.severity) - getSeverityRank(a.severity),
+    )[0]!
+    const stream = streams.get(getStreamKey(lastScan))!
+    const fixedBy = stream.find(s => s.recordedAt > lastScan.recordedAt)
+    const row: HistoryQueryRow = {
+      ...(lastScan.branch ? { branch: lastScan.branch } : {}),
+      firstSeen: group.firstSeen,
+      ...(fixedBy ? { fixedAt: fixedBy.recordedAt } : {}),
+      identity: alert.identity,
+      lastSeen: lastScan.recordedAt,
+      orgSlug: lastScan.orgSlug,
+      purl: alert.purl,
+      ...(lastScan.repo ? { repo: lastScan.repo } : {}),
+      scans: group.scanIds.size,
+      severity: alert.severity,
+      status: fixedBy ? 'fixed' : 'open',
+      type: alert.type,
+    }
+    if (
+      (alertType !== undefined && row.type !== alertType) ||
+      (packageName !== undefined &&
+        !matchesPackage(row.identity, packageName)) ||
+      (severities !== undefined && !severities.includes(row.severity))
+    ) {
+      continue
+    }
+    const windowStart = since ?? 0
+    const inWindow =
+      status === 'new'
+        ? row.firstSeen >= windowStart
+        : status === 'fixed'
+          ? row.status === 'fixed' && row.fixedAt! >= windowStart
+          : status === 'open'
+            ? row.status === 'open'
+            : row.lastSeen >= windowStart
+    if (inWindow) {
+      rows.push(row)
+    }
+  }
+  return rows.sort(
+    (a, b) =>
+      getSeverityRank(b.severity) - getSeverityRank(a.severity) ||
+      b.firstSeen - a.firstSeen ||
+      a.purl.localeCompare(b.purl),
+  )
+}
+
+/**
+ * Compare the alerts of two recorded scans by identity, the same way
+ * `socket scan diff` compares fetched ones.
+ */
+export function diffHistoryScans(
+  data: HistoryData,
+  beforeId: string,
+  afterId: string,
+): CResult<HistoryScanDiff> {
+  const before = data.scans.find(s => s.scanId === beforeId)
+  const after = data.scans.find(s => s.scanId === afterId)
+  const missing = [
+    ...(before?.packages === undefined ? [beforeId] : []),
+    ...(after?.packages === undefined ? [afterId] : []),
+  ]
+  if (!before || !after || missing.length) {
+    return {
+      ok: false,
+      message: 'Scan not in the history',
+      cause: `The results of ${joinAnd(missing.map(id => `scan ${id}`))} were not recorded yet. Fetch them once, e.g. with \`socket scan view ${missing[0]}\`, then retry.`,
+    }
+  }
+  const beforeAlerts = data.alerts.filter(a => a.scanId === beforeId)
+  const afterAlerts = data.alerts.filter(a => a.scanId === afterId)
+  const beforeIdentities = new Set(beforeAlerts.map(a => a.identity))
+  const afterIdentities = new Set(afterAlerts.map(a => a.identity))
+  return {
+    ok: true,
+    data: {
+      added: afterAlerts.filter(a => !beforeIdentities.has(a.identity)),
+      after,
+      before,
+      removed: beforeAlerts.filter(a => !afterIdentities.has(a.identity)),
+    },
+  }
+}
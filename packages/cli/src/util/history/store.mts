@@ -0,0 +1,316 @@
+/**
+ * Local history of scan results, queried by `socket history`.
+ *
+ * Every scan the CLI fetches is recorded in a SQLite database in the Socket
+ * app-data directory: one row per scan with its org, repo, branch and package
+ * count, and one row per alert with its fingerprint. So later questions like
+ * "new criticals in the last 30 days", or the alert diff of two scans, are
+ * answered without fetching the scans again.
+ *
+ * Alerts are fingerprinted by their purl and alert key, like the tickets of
+ * `socket report issues`, and also carry the version-less identity of
+ * `socket scan diff` (see diff-scan-alerts.mts), which decides whether an
+ * alert is new. Recording never fails the command that fetched the scan;
+ * errors are only debug logged. The database uses the built-in node:sqlite
+ * module, so on a Node.js build without it the history is unavailable.
+ */
+
+import crypto from 'node:crypto'
+import path from 'node:path'
+
+import { UNKNOWN_VALUE } from '@socketsecurity/lib-stable/constants/sentinels'
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { errorMessage } from '@socketsecurity/lib-stable/errors/message'
+import { safeMkdir } from '@socketsecurity/lib-stable/fs/safe'
+
+import { getAlertIdentity } from '../../commands/scan/diff-scan-alerts.mts'
+import { getSocketHistoryPath } from '../../constants/paths.mts'
+import { VITEST } from '../../env/vitest.mts'
+import { getArtifactPurlString } from '../purl/parse.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../alert/artifact.mts'
+import type { DatabaseSync } from 'node:sqlite'
+
+export type HistoryDb = DatabaseSync
+
+export type HistoryScan = {
+  branch?: string | undefined
+  orgSlug: string
+  // Unset until the results of the scan were fetched.
+  packages?: number | undefined
+  // When the scan was first recorded, in ms since epoch.
+  recordedAt: number
+  repo?: string | undefined
+  scanId: string
+}
+
+export type HistoryAlert = {
+  fingerprint: string
+  // Package and alert type without the version, see getAlertIdentity.
+  identity: string
+  purl: string
+  scanId: string
+  severity: string
+  type: string
+}
+
+export type HistoryData = {
+  alerts: HistoryAlert[]
+  scans: HistoryScan[]
+}
+
+// The `socket history export` file.
+export type HistoryExport = HistoryData & {
+  exportedAt: string
+  version: number
+}
+
+export const HISTORY_EXPORT_VERSION = 1
+
+export type ScanHistoryRecord = {
+  // The results of the scan, when they were fetched.
+  artifacts?: SocketArtifact[] | undefined
+  branch?: string | undefined
+  orgSlug: string
+  repo?: string | undefined
+  scanId: string
+}
+
+// Bump with a migration in migrateHistoryDb when the tables change.
+const HISTORY_SCHEMA_VERSION = 1
+
+const HISTORY_SCHEMA = `
+  CREATE TABLE IF NOT EXISTS scans (
+    scan_id TEXT PRIMARY KEY,
+    org_slug TEXT NOT NULL,
+    repo TEXT,
+    branch TEXT,
+    packages INTEGER,
+    recorded_at INTEGER NOT NULL
+  );
+  CREATE TABLE IF NOT EXISTS alerts (
+    scan_id TEXT NOT NULL REFERENCES scans (scan_id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL,
+    identity TEXT NOT NULL,
+    purl TEXT NOT NULL,
+    type TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    PRIMARY KEY (scan_id, fingerprint)
+  );
+  CREATE INDEX IF NOT EXISTS alerts_identity ON alerts (identity);
+`
+
+// Concurrent CLI runs, e.g. parallel CI steps, wait for each other's writes.
+const HISTORY_BUSY_TIMEOUT_MS = 5000
+
+export function getHistoryAlertFingerprint(
+  purl: string,
+  alertKey: string,
+): string {
+  return crypto
+    .createHash('sha256')
+    .update(`${purl} ${alertKey}`)
+    .digest('hex')
+    .slice(0, 32)
+}
+
+export function getHistoryAlerts(
+  scanId: string,
+  artifacts: SocketArtifact[],
+): HistoryAlert[] {
+  const alerts: HistoryAlert[] = []
+  for (const artifact of artifacts) {
+    if (!artifact.alerts?.length) {
+      continue
+    }
+    const purl = getArtifactPurlString(artifact)
+    for (const alert of artifact.alerts) {
+      alerts.push({
+        fingerprint: getHistoryAlertFingerprint(purl, alert.key),
+        identity: getAlertIdentity(artifact, alert.type),
+        purl,
+        scanId,
+        severity: alert.severity ?? UNKNOWN_VALUE,
+        type: alert.type,
+      })
+    }
+  }
+  return alerts
+}
+
+function migrateHistoryDb(db: HistoryDb): void {
+  const { user_version: version } = db
+    .prepare('PRAGMA user_version')
+    .get() as { user_version: number }
+  if (version < HISTORY_SCHEMA_VERSION) {
+    db.exec(HISTORY_SCHEMA)
+    db.exec(`PRAGMA user_version = ${HISTORY_SCHEMA_VERSION}`)
+  }
+}
+
+export async function openHistoryDb(
+  dbPath: string = getSocketHistoryPath(),
+): Promise<CResult<HistoryDb>> {
+  let sqlite: typeof import('node:sqlite')
+  try {
+    sqlite = await import('node:sqlite')
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'The scan history is unavailable',
+      cause: `This Node.js build has no node:sqlite module: ${errorMessage(e)}`,
+    }
+  }
+  try {
+    await safeMkdir(path.dirname(dbPath), { recursive: true })
+    const db = new sqlite.DatabaseSync(dbPath)
+    db.exec(`PRAGMA busy_timeout = ${HISTORY_BUSY_TIMEOUT_MS}`)
+    db.exec('PRAGMA foreign_keys = ON')
+    migrateHistoryDb(db)
+    return { ok: true, data: db }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Failed to open the scan history',
+      cause: `Could not open ${dbPath}: ${errorMessage(e)}`,
+    }
+  }
+}
+
+/**
+ * Add a scan to the history or fill in what is now known of it. The repo,
+ * branch and package count of an earlier record are kept when not given, the
+ * alerts are replaced when artifacts are.
+ */
+export function writeScanHistory(
+  db: HistoryDb,
+  record: ScanHistoryRecord,
+  recordedAt: number = Date.now(),
+): void {
+  const { artifacts, branch, orgSlug, repo, scanId } = {
+    __proto__: null,
+    ...record,
+  } as ScanHistoryRecord
+  db.exec('BEGIN')
+  try {
+    db.prepare(
+      `INSERT INTO scans (scan_id, org_slug, repo, branch, packages, recorded_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+        ON CONFLICT (scan_id) DO UPDATE SET
+          repo = coalesce(excluded.repo, repo),
+          branch = coalesce(excluded.branch, branch),
+          packages = coalesce(excluded.packages, packages)`,
+    ).run(
+      scanId,
+      orgSlug,
+      repo || null,
+      branch || null,
+      artifacts ? artifacts.length : null,
+      recordedAt,
+    )
+    if (artifacts) {
+      db.prepare('DELETE FROM alerts WHERE scan_id = ?').run(scanId)
+      const insert = db.prepare(
+        `INSERT OR IGNORE INTO alerts
+          (scan_id, fingerprint, identity, purl, type, severity)
+          VALUES (?, ?, ?, ?, ?, ?)`,
+      )
+      for (const alert of getHistoryAlerts(scanId, artifacts)) {
+        insert.run(
+          alert.scanId,
+          alert.fingerprint,
+          alert.identity,
+          alert.purl,
+          alert.type,
+          alert.severity,
+        )
+      }
+    }
+    db.exec('COMMIT')
+  } catch (e) {
+    db.exec('ROLLBACK')
+    throw e
+  }
+}
+
+export function readHistory(db: HistoryDb): HistoryData {
+  const scans = db
+    .prepare(
+      `SELECT scan_id AS scanId, org_slug AS orgSlug, repo, branch, packages,
+        recorded_at AS recordedAt
+        FROM scans ORDER BY recorded_at, scan_id`,
+    )
+    .all() as Array<Record<string, unknown>>
+  const alerts = db
+    .prepare(
+      `SELECT scan_id AS scanId, fingerprint, identity, purl, type, severity
+        FROM alerts ORDER BY scan_id, fingerprint`,
+    )
+    .all() as unknown as HistoryAlert[]
+  return {
+    alerts,
+    // SQLite NULLs come back as null, the types use undefined.
+    scans: scans.map(row => {
+      const scan: HistoryScan = {
+        orgSlug: row['orgSlug'] as string,
+        recordedAt: row['recordedAt'] as number,
+        scanId: row['scanId'] as string,
+      }
+      if (row['repo'] !== null) {
+        scan.repo = row['repo'] as string
+      }
+      if (row['branch'] !== null) {
+        scan.branch = row['branch'] as string
+      }
+      if (row['packages'] !== null) {
+        scan.packages = row['packages'] as number
+      }
+      return scan
+    }),
+  }
+}
+
+export async function readScanHistory(): Promise<CResult<HistoryData>> {
+  const dbCResult = await openHistoryDb()
+  if (!dbCResult.ok) {
+    return dbCResult
+  }
+  const db = dbCResult.data
+  try {
+    return { ok: true, data: readHistory(db) }
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Failed to read the scan history',
+      cause: errorMessage(e),
+    }
+  } finally {
+    db.close()
+  }
+}
+
+/**
+ * Record a scan in the history, if it can be. Tests do not touch the
+ * database of the user.
+ */
+export async function recordScanHistory(
+  record: ScanHistoryRecord,
+): Promise<void> {
+  if (VITEST) {
+    return
+  }
+  const dbCResult = await openHistoryDb()
+  if (!dbCResult.ok) {
+    debugDir('error', dbCResult)
+    return
+  }
+  const db = dbCResult.data
+  try {
+    writeScanHistory(db, record)
+  } catch (e) {
+    debugDir('error', e)
+  } finally {
+    db.close()
+  }
+}
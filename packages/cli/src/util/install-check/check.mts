@@ -0,0 +1,426 @@
+/**
+ * Pre-install package checks for ecosystem wrappers that resolve what they are
+ * about to install before handing off (`socket pip install`, `socket pipx`,
+ * `socket cargo add|install`, `socket pnpm|yarn add|install`).
+ *
+ * The resolved purls are scored with the shallow batch lookup and their
+ * alerts are judged by the policy action the API attached: `error` blocks the
+ * install, `warn` asks for confirmation when interactive (or proceeds when
+ * SOCKET_CLI_ACCEPT_RISKS is set). Malware blocks even without an org policy,
+ * and callers can name alert types that warn when no policy action is set.
+ * A repo `socket.policy.yml` found from the working directory overrides the
+ * org action wherever one of its rules matches. Otherwise the `wrapper.alerts`
+ * modes of the repo socket.yml do: `block`, `prompt`, `warn` (listed, then
+ * installed) or `allow`. Without a TTY, or with --non-interactive, prompts
+ * take the `wrapper.nonInteractive` answer, `allow` unless set. Risks accepted
+ * at the prompt can be recorded as `socket.policy.yml` exceptions, so the team
+ * is not asked again and the decision is reviewed with the file. A policy
+ * `quarantine` window also blocks, or asks about, versions published fewer
+ * than its `days` ago, by their registry publish time. Its `trust` list skips
+ * the prompts for trusted scopes and maintainers, and `distrust` blocks.
+ * SOCKET_CLI_VIEW_ALL_RISKS also lists `monitor` alerts. With
+ * SOCKET_CLI_OFFLINE the packages are scored from the local score cache.
+ * An install with packages missing from it is refused.
+ */
+
+import path from 'node:path'
+
+import isInteractive from '@socketregistry/is-interactive/index.cjs'
+import { SOCKET_PUBLIC_API_TOKEN } from '@socketsecurity/lib-stable/constants/socket'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { confirm, input } from '@socketsecurity/lib-stable/stdio/prompts'
+import { pluralize } from '@socketsecurity/lib-stable/words/pluralize'
+
+import { fetchPublishTimes } from './publish-time.mts'
+import { fetchPurlsShallowScore } from '../../commands/package/fetch-purls-shallow-score.mts'
+import { SOCKET_POLICY_YML } from '../../constants/socket.mts'
+import { SOCKET_CLI_ACCEPT_RISKS } from '../../env/socket-cli-accept-risks.mts'
+import { SOCKET_CLI_OFFLINE } from '../../env/socket-cli-offline.mts'
+import { SOCKET_CLI_VIEW_ALL_RISKS } from '../../env/socket-cli-view-all-risks.mts'
+import { addSocketPolicyException } from '../policy/add-policy-exception.mts'
+import {
+  applyLocalTrust,
+  getLocalLicenseViolation,
+  getLocalPolicyAction,
+  getLocalQuarantineViolation,
+  getLocalTrust,
+  LOCAL_DISTRUST_POLICY_ALERT,
+  LOCAL_LICENSE_POLICY_ALERT,
+  LOCAL_QUARANTINE_POLICY_ALERT,
+} from '../policy/evaluate.mts'
+import { findSocketPolicySync } from '../policy/socket-policy.mts'
+import { getArtifactPurlString } from '../purl/parse.mts'
+import { findSocketYmlSync } from '../config.mts'
+import { getDefaultApiToken } from '../socket/sdk.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../alert/artifact.mts'
+import type { SocketPolicy } from '../policy/socket-policy.mts'
+import type {
+  SocketYmlWrapper,
+  WrapperAlertMode,
+  WrapperPromptDefault,
+} from '../socket-yaml.mts'
+
+const logger = getDefaultLogger()
+
+export type InstallRiskAlert = {
+  action: string
+  severity: string
+  type: string
+}
+
+export type InstallRisk = {
+  alerts: InstallRiskAlert[]
+  purl: string
+}
+
+export type InstallRiskSummary = {
+  blocked: InstallRisk[]
+  monitored: InstallRisk[]
+  // Listed and installed, from socket.yml `warn` modes.
+  noticed: InstallRisk[]
+  warned: InstallRisk[]
+}
+
+export type CheckInstallPackagesOptions = {
+  commandPath: string
+  // Directory to search upward from for a socket.policy.yml.
+  cwd?: string | undefined
+  // Defaults to whether stdin/stdout are a TTY.
+  interactive?: boolean | undefined
+  // Alert types treated as `warn` when the API attached no policy action.
+  warnAlertTypes?: readonly string[] | undefined
+}
+
+// The policy actions of the socket.yml wrapper modes. `notice` lists the risk
+// without asking.
+export const WRAPPER_MODE_ACTIONS: Record<WrapperAlertMode, string> = {
+  __proto__: null,
+  allow: 'ignore',
+  block: 'error',
+  prompt: 'warn',
+  warn: 'notice',
+} as unknown as Record<WrapperAlertMode, string>
+
+function getAlertAction(
+  alert: { action?: string | undefined; type: string },
+  warnAlertTypes: readonly string[],
+  wrapper: SocketYmlWrapper | undefined,
+) {
+  const mode = wrapper?.alerts[alert.type]
+  if (mode) {
+    return WRAPPER_MODE_ACTIONS[mode]
+  }
+  if (alert.action) {
+    return alert.action
+  }
+  // Without an org policy the API leaves action unset; malware is the one
+  // verdict that should never be installed silently.
+  if (alert.type === 'malware') {
+    return 'error'
+  }
+  return warnAlertTypes.includes(alert.type) ? 'warn' : ''
+}
+
+/**
+ * Bucket artifacts by the most severe policy action among their alerts.
+ * `publishTimes`, keyed by artifact purl, feeds the policy quarantine window.
+ */
+export function summarizeInstallRisks(
+  artifacts: SocketArtifact[],
+  warnAlertTypes: readonly string[] = [],
+  localPolicy?: SocketPolicy | undefined,
+  wrapper?: SocketYmlWrapper | undefined,
+  publishTimes?: Map<string, number> | undefined,
+): InstallRiskSummary {
+  const summary: InstallRiskSummary = {
+    blocked: [],
+    monitored: [],
+    noticed: [],
+    warned: [],
+  }
+  for (let i = 0, { length } = artifacts; i < length; i += 1) {
+    const artifact = artifacts[i]!
+    const alerts: InstallRiskAlert[] = []
+    let hasError = false
+    let hasNotice = false
+    let hasWarn = false
+    const trust = localPolicy && getLocalTrust(localPolicy, artifact)
+    for (const alert of artifact.alerts ?? []) {
+      const action = applyLocalTrust(
+        (localPolicy && getLocalPolicyAction(localPolicy, artifact, alert)) ||
+          getAlertAction(alert, warnAlertTypes, wrapper),
+        trust,
+      )
+      if (action === 'error') {
+        hasError = true
+      } else if (action === 'warn') {
+        hasWarn = true
+      } else if (action === 'notice') {
+        hasNotice = true
+      } else if (action !== 'monitor') {
+        continue
+      }
+      alerts.push({
+        action,
+        severity: alert.severity ?? 'unknown',
+        type: alert.type,
+      })
+    }
+    if (localPolicy && getLocalLicenseViolation(localPolicy, artifact)) {
+      hasError = true
+      alerts.push({
+        action: 'error',
+        severity: 'high',
+        type: LOCAL_LICENSE_POLICY_ALERT,
+      })
+    }
+    const purl = getArtifactPurlString(artifact)
+    const quarantineAction =
+      localPolicy?.quarantine &&
+      getLocalQuarantineViolation(
+        localPolicy,
+        artifact,
+        publishTimes?.get(purl),
+      ) !== undefined
+        ? applyLocalTrust(
+            localPolicy.quarantine.action === 'warn' ? 'warn' : 'error',
+            trust,
+          )
+        : 'ignore'
+    if (quarantineAction !== 'ignore') {
+      if (quarantineAction === 'error') {
+        hasError = true
+      } else {
+        hasWarn = true
+      }
+      alerts.push({
+        action: quarantineAction,
+        severity: 'high',
+        type: LOCAL_QUARANTINE_POLICY_ALERT,
+      })
+    }
+    if (trust === 'distrusted') {
+      hasError = true
+      alerts.push({
+        action: 'error',
+        severity: 'critical',
+        type: LOCAL_DISTRUST_POLICY_ALERT,
+      })
+    }
+    if (!alerts.length) {
+      continue
+    }
+    const risk = { alerts, purl }
+    if (hasError) {
+      summary.blocked.push(risk)
+    } else if (hasWarn) {
+      summary.warned.push(risk)
+    } else if (hasNotice) {
+      summary.noticed.push(risk)
+    } else {
+      summary.monitored.push(risk)
+    }
+  }
+  return summary
+}
+
+export function formatInstallRisk(risk: InstallRisk): string {
+  return `${risk.purl}: ${risk.alerts
+    .map(alert => `${alert.type} (${alert.severity}, policy: ${alert.action})`)
+    .join(', ')}`
+}
+
+/**
+ * Ask whether to install despite the risks listed before. Without anyone to
+ * ask the socket.yml `wrapper.nonInteractive` answer applies, `allow` unless
+ * set. SOCKET_CLI_ACCEPT_RISKS accepts without asking.
+ */
+export async function confirmRiskyInstall(
+  interactive: boolean,
+  promptDefault: WrapperPromptDefault = 'allow',
+): Promise<boolean> {
+  if (SOCKET_CLI_ACCEPT_RISKS) {
+    return true
+  }
+  if (!interactive) {
+    return promptDefault === 'allow'
+  }
+  return await confirm({ message: 'Install anyway?', default: false })
+}
+
+/**
+ * After an accepted prompt, offer to record the decision as one
+ * `socket.policy.yml` exception per package version, for the alert types that
+ * asked. Only asks when the install prompt did; failing to write the file only
+ * warns.
+ */
+export async function offerToRecordAcceptedRisks(
+  risks: InstallRisk[],
+  policyPath: string,
+  interactive: boolean,
+): Promise<void> {
+  if (!interactive || SOCKET_CLI_ACCEPT_RISKS || !risks.length) {
+    return
+  }
+  const filename = path.basename(policyPath)
+  const record = await confirm({
+    message: `Record this decision in ${filename} so the team is not asked again?`,
+    default: false,
+  })
+  if (!record) {
+    return
+  }
+  const reason = (
+    await input({
+      message: 'Why are these risks acceptable?',
+      required: true,
+    })
+  )?.trim()
+  if (!reason) {
+    return
+  }
+  for (let i = 0, { length } = risks; i < length; i += 1) {
+    const risk = risks[i]!
+    const alertTypes = new Set(
+      risk.alerts.filter(a => a.action === 'warn').map(a => a.type),
+    )
+    const result = addSocketPolicyException(policyPath, {
+      alerts: [...alertTypes],
+      packages: [risk.purl],
+      paths: [],
+      reason: `Accepted: ${reason}`,
+    })
+    if (!result.ok) {
+      logger.warn(
+        `Could not record the decision: ${result.cause ?? result.message}`,
+      )
+      return
+    }
+  }
+  logger.success(
+    `Added ${risks.length} ${pluralize('exception', { count: risks.length })} to ${policyPath}; commit it so the decision is reviewed`,
+  )
+}
+
+function logInstallRisks(
+  risks: InstallRisk[],
+  log: (message: string) => void,
+): void {
+  for (let i = 0, { length } = risks; i < length; i += 1) {
+    log(`  ${formatInstallRisk(risks[i]!)}`)
+  }
+}
+
+/**
+ * Score the packages an install would add and apply the policy verdict.
+ * Resolves to an ok result when the install may proceed.
+ */
+export async function checkPackagesBeforeInstall(
+  purls: string[],
+  options: CheckInstallPackagesOptions,
+): Promise<CResult<InstallRiskSummary>> {
+  const {
+    commandPath,
+    cwd = process.cwd(),
+    interactive = isInteractive(),
+    warnAlertTypes,
+  } = {
+    __proto__: null,
+    ...options,
+  } as CheckInstallPackagesOptions
+
+  const empty: InstallRiskSummary = {
+    blocked: [],
+    monitored: [],
+    noticed: [],
+    warned: [],
+  }
+  if (!purls.length) {
+    return { ok: true, data: empty }
+  }
+
+  const ymlCResult = findSocketYmlSync(cwd)
+  if (!ymlCResult.ok) {
+    return {
+      ...ymlCResult,
+      cause: `${ymlCResult.cause ?? ymlCResult.message}. Nothing was installed.`,
+    }
+  }
+  const wrapper = ymlCResult.data?.parsed.wrapper
+
+  const policyCResult = findSocketPolicySync(cwd)
+  if (!policyCResult.ok) {
+    return {
+      ...policyCResult,
+      cause: `${policyCResult.cause ?? policyCResult.message}. Nothing was installed; run \`socket policy lint\` for details.`,
+    }
+  }
+
+  const scoreCResult = await fetchPurlsShallowScore(purls, {
+    commandPath,
+    offline: SOCKET_CLI_OFFLINE,
+    // Unauthenticated users still get malware verdicts from the public token.
+    sdkOpts: { apiToken: getDefaultApiToken() || SOCKET_PUBLIC_API_TOKEN },
+  })
+  if (!scoreCResult.ok) {
+    return {
+      ok: false,
+      message: 'Unable to check packages',
+      cause: `${scoreCResult.message}${scoreCResult.cause ? `: ${scoreCResult.cause}` : ''}. Nothing was installed.`,
+    }
+  }
+  const artifacts = scoreCResult.data as unknown as SocketArtifact[]
+  const localPolicy = policyCResult.data?.policy
+  const summary = summarizeInstallRisks(
+    artifacts,
+    warnAlertTypes,
+    localPolicy,
+    wrapper,
+    localPolicy?.quarantine ? await fetchPublishTimes(artifacts) : undefined,
+  )
+
+  if (SOCKET_CLI_VIEW_ALL_RISKS && summary.monitored.length) {
+    logger.info('Socket flagged these packages for monitoring:')
+    logInstallRisks(summary.monitored, msg => logger.info(msg))
+  }
+  if (summary.noticed.length) {
+    logger.warn(
+      `Socket flagged ${summary.noticed.length} ${pluralize('package', { count: summary.noticed.length })}:`,
+    )
+    logInstallRisks(summary.noticed, msg => logger.warn(msg))
+  }
+  if (summary.blocked.length) {
+    logger.fail(
+      `Socket blocked ${summary.blocked.length} ${pluralize('package', { count: summary.blocked.length })}:`,
+    )
+    logInstallRisks(summary.blocked, msg => logger.error(msg))
+    return {
+      ok: false,
+      message: 'Install blocked by Socket security policy',
+      cause: `${summary.blocked.map(r => r.purl).join(', ')} violate the security policy. Nothing was installed.`,
+    }
+  }
+  if (summary.warned.length) {
+    logger.warn(
+      `Socket found risks in ${summary.warned.length} ${pluralize('package', { count: summary.warned.length })}:`,
+    )
+    logInstallRisks(summary.warned, msg => logger.warn(msg))
+    if (!(await confirmRiskyInstall(interactive, wrapper?.nonInteractive))) {
+      return {
+        ok: false,
+        message: 'Install cancelled',
+        cause: interactive
+          ? 'Risky packages were not accepted. Nothing was installed.'
+          : 'Risky packages need confirmation, which socket.yml `wrapper.nonInteractive: block` refuses without a prompt. Nothing was installed.',
+      }
+    }
+    await offerToRecordAcceptedRisks(
+      summary.warned,
+      policyCResult.data?.path ?? path.join(cwd, SOCKET_POLICY_YML),
+      interactive,
+    )
+  }
+  return { ok: true, data: summary }
+}
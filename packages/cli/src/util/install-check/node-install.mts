@@ -0,0 +1,192 @@
+/**
+ * Pre-install checks shared by the pnpm and yarn wrappers (`socket pnpm
+ * add|install`, `socket yarn add|install`).
+ *
+ * Packages named on the command line are resolved against the npm registry;
+ * a bare install checks the direct dependencies pinned in the lockfile of the
+ * package manager instead (`pnpm-lock.yaml`, or `yarn.lock` of Yarn Classic
+ * and Berry). The resulting purls go through `checkPackagesBeforeInstall`, so
+ * pnpm and yarn block and prompt the way the other wrappers do.
+ */
+
+import { existsSync, readFileSync } from 'node:fs'
+import path from 'node:path'
+
+import {
+  PNPM,
+  PNPM_LOCK_YAML,
+  YARN_LOCK,
+} from '@socketsecurity/lib-stable/constants/agents'
+import { readJsonSync } from '@socketsecurity/lib-stable/fs/read-json'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { checkPackagesBeforeInstall } from './check.mts'
+import { findTyposquatTarget } from './typosquat.mts'
+import {
+  escapePackageName,
+  fetchRegistryJson,
+} from '../../commands/verify/fetch-npm-provenance.mts'
+import { parseYarnLockV1 } from '../lockfile/bun.mts'
+import { parseLockfile } from '../lockfile/parsers.mts'
+import { getLockfileDependencyPurl } from '../lockfile/sbom.mts'
+
+import type { CheckInstallPackagesOptions } from './check.mts'
+import type { NpmInstallSpec } from '../../commands/npm/npm-install-specs.mts'
+import type { CResult } from '../../types.mts'
+
+const logger = getDefaultLogger()
+
+export type NodeInstallAgent = 'pnpm' | 'yarn'
+
+export type NodeInstallRequest = {
+  // Directory the package manager runs in, from --dir or --cwd.
+  dir?: string | undefined
+  // A bare install, which checks the direct dependencies of the lockfile.
+  fromLockfile: boolean
+  // Registry packages named on the command line.
+  specs: NpmInstallSpec[]
+}
+
+// Alerts that prompt for npm packages even without an org policy.
+export const NODE_WARN_ALERT_TYPES = ['didYouMean']
+
+/**
+ * Resolve a version, dist-tag or range to the version the npm registry
+ * serves for it. Unknown packages and unsatisfiable ranges read as undefined.
+ */
+export async function resolveNpmVersion(
+  name: string,
+  version: string,
+): Promise<CResult<string | undefined>> {
+  const manifestCResult = await fetchRegistryJson<{
+    version?: string | undefined
+  }>(
+    `/${escapePackageName(name)}/${encodeURIComponent(version)}`,
+    `the ${name}@${version} manifest`,
+  )
+  if (!manifestCResult.ok) {
+    return manifestCResult
+  }
+  return { ok: true, data: manifestCResult.data?.version }
+}
+
+function getDeclaredDependencies(dir: string): Map<string, string> {
+  const pkgJson = readJsonSync(path.join(dir, 'package.json'), {
+    throws: false,
+  }) as Record<string, Record<string, string> | undefined> | undefined
+  const declared = new Map<string, string>()
+  for (const field of [
+    'dependencies',
+    'devDependencies',
+    'optionalDependencies',
+  ]) {
+    for (const { 0: name, 1: range } of Object.entries(
+      pkgJson?.[field] ?? {},
+    )) {
+      declared.set(name, range)
+    }
+  }
+  return declared
+}
+
+/**
+ * The direct dependencies pinned in the lockfile of `agent` in `dir`, as
+ * purls. Yarn Classic lockfiles do not mark direct dependencies, so the
+ * ranges of the package.json pick them out.
+ */
+export function getLockfileDirectPurls(
+  agent: NodeInstallAgent,
+  dir: string,
+): string[] {
+  const lockPath = path.join(dir, agent === PNPM ? PNPM_LOCK_YAML : YARN_LOCK)
+  if (!existsSync(lockPath)) {
+    return []
+  }
+  const parsed = parseLockfile(lockPath, dir)
+  if (parsed) {
+    return parsed.dependencies
+      .filter(dep => dep.direct && dep.type === 'npm')
+      .map(dep => getLockfileDependencyPurl(dep))
+  }
+  if (agent === PNPM) {
+    return []
+  }
+  let entries
+  try {
+    entries = parseYarnLockV1(readFileSync(lockPath, 'utf8'))
+  } catch {
+    return []
+  }
+  const purls = new Set<string>()
+  for (const { 0: name, 1: range } of getDeclaredDependencies(dir)) {
+    const entry = entries.get(`${name}@${range}`)
+    if (entry?.version) {
+      purls.add(`pkg:npm/${entry.name}@${entry.version}`)
+    }
+  }
+  return [...purls]
+}
+
+/**
+ * Preflight for `socket pnpm|yarn` installs: score what the install is about
+ * to add before handing off. Names that look like typosquats warn before the
+ * lookup, the `didYouMean` alert then asks about them. Lookup failures only
+ * warn, Socket Firewall still screens the downloads.
+ */
+export async function checkNodeInstall(
+  agent: NodeInstallAgent,
+  request: NodeInstallRequest | undefined,
+  options?:
+    | Pick<CheckInstallPackagesOptions, 'cwd' | 'interactive'>
+    | undefined,
+): Promise<CResult<unknown>> {
+  if (!request) {
+    return { ok: true, data: undefined }
+  }
+  const { cwd = process.cwd(), interactive } = {
+    __proto__: null,
+    ...options,
+  } as Pick<CheckInstallPackagesOptions, 'cwd' | 'interactive'>
+  const dir = path.resolve(cwd, request.dir ?? '.')
+  let purls: string[]
+  if (request.fromLockfile) {
+    purls = getLockfileDirectPurls(agent, dir)
+  } else {
+    const { specs } = request
+    for (let i = 0, { length } = specs; i < length; i += 1) {
+      const { name } = specs[i]!
+      const match = findTyposquatTarget('npm', name)
+      if (match) {
+        logger.warn(`${name} looks like a typosquat of ${match.target}`)
+      }
+    }
+    const spinner = getDefaultSpinner()
+    spinner.start('Resolving packages to install…')
+    const versionCResults = await Promise.all(
+      specs.map(({ name, version }) => resolveNpmVersion(name, version)),
+    )
+    spinner.stop()
+    purls = []
+    for (let i = 0, { length } = specs; i < length; i += 1) {
+      const versionCResult = versionCResults[i]!
+      if (!versionCResult.ok) {
+        logger.warn(
+          `Skipping the Socket pre-install check: ${versionCResult.cause ?? versionCResult.message}`,
+        )
+        return { ok: true, data: undefined }
+      }
+      // Unknown packages and unsatisfiable ranges are left for the package
+      // manager to report.
+      if (versionCResult.data) {
+        purls.push(`pkg:npm/${specs[i]!.name}@${versionCResult.data}`)
+      }
+    }
+  }
+  return await checkPackagesBeforeInstall(purls, {
+    commandPath: `socket ${agent}`,
+    cwd: dir,
+    interactive,
+    warnAlertTypes: NODE_WARN_ALERT_TYPES,
+  })
+}
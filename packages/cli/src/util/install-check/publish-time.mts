@@ -0,0 +1,112 @@
+/**
+ * Publish times of package versions, for the `quarantine` window of
+ * socket.policy.yml. The Socket score lookup has no publish time, so each
+ * version is looked up in its public registry: npm packuments, the PyPI JSON
+ * API, crates.io and the Go module proxy. Other ecosystems, and versions a
+ * registry does not answer for, get no entry and are not held back.
+ */
+
+import { NPM_REGISTRY_URL } from '@socketsecurity/lib-stable/constants/agents'
+import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { pEach } from '@socketsecurity/lib-stable/promises/iterate'
+
+import { DEFAULT_GOPROXY, escapeGoModulePath } from '../go/module-proxy.mts'
+import { getArtifactPurlString } from '../purl/parse.mts'
+import { socketHttpRequest } from '../socket/api-http.mts'
+
+import type { SocketArtifact } from '../alert/artifact.mts'
+
+const PUBLISH_TIME_CONCURRENCY = 8
+
+type PublishTimeSource = {
+  url: string
+  read: (json: unknown) => unknown
+}
+
+function getPublishTimeSource(
+  artifact: SocketArtifact,
+): PublishTimeSource | undefined {
+  const { name, namespace, type, version } = artifact
+  if (!name || !version) {
+    return undefined
+  }
+  const fullName = namespace ? `${namespace}/${name}` : name
+  switch (type) {
+    case 'npm':
+      return {
+        // Scoped packuments need the `/` encoded.
+        url: `${NPM_REGISTRY_URL}/${fullName.replace('/', '%2f')}`,
+        read: json =>
+          (json as { time?: Record<string, unknown> } | undefined)?.time?.[
+            version
+          ],
+      }
+    case 'pypi':
+      return {
+        url: `https://pypi.org/pypi/${encodeURIComponent(name)}/${encodeURIComponent(version)}/json`,
+        // The earliest file of the release.
+        read: json => {
+          const { urls } = { __proto__: null, ...(json as object) } as {
+            urls?: Array<{ upload_time_iso_8601?: unknown }> | undefined
+          }
+          return (Array.isArray(urls) ? urls : [])
+            .map(file => file?.upload_time_iso_8601)
+            .filter(time => typeof time === 'string')
+            .toSorted()[0]
+        },
+      }
+    case 'cargo':
+      return {
+        url: `https://crates.io/api/v1/crates/${encodeURIComponent(name)}/${encodeURIComponent(version)}`,
+        read: json =>
+          (json as { version?: { created_at?: unknown } } | undefined)?.version
+            ?.created_at,
+      }
+    case 'golang':
+      return {
+        url: `${DEFAULT_GOPROXY}/${escapeGoModulePath(fullName)}/@v/${escapeGoModulePath(version)}.info`,
+        read: json => (json as { Time?: unknown } | undefined)?.Time,
+      }
+    default:
+      return undefined
+  }
+}
+
+async function fetchPublishTime(
+  source: PublishTimeSource,
+): Promise<number | undefined> {
+  try {
+    const response = await socketHttpRequest(source.url)
+    if (!response.ok) {
+      debug(`No publish time: ${source.url} returned HTTP ${response.status}`)
+      return undefined
+    }
+    const time = source.read(response.json())
+    const publishedAt = typeof time === 'string' ? Date.parse(time) : NaN
+    return Number.isNaN(publishedAt) ? undefined : publishedAt
+  } catch (e) {
+    debugDir('error', e)
+    return undefined
+  }
+}
+
+/**
+ * Publish times in epoch milliseconds, keyed by artifact purl.
+ */
+export async function fetchPublishTimes(
+  artifacts: SocketArtifact[],
+): Promise<Map<string, number>> {
+  const publishTimes = new Map<string, number>()
+  await pEach(
+    artifacts,
+    async artifact => {
+      const source = getPublishTimeSource(artifact)
+      const publishedAt = source ? await fetchPublishTime(source) : undefined
+      if (publishedAt !== undefined) {
+        publishTimes.set(getArtifactPurlString(artifact), publishedAt)
+      }
+    },
+    { concurrency: PUBLISH_TIME_CONCURRENCY },
+  )
+  return publishTimes
+}
@@ -0,0 +1,208 @@
+/**
+ * Local typosquat heuristics for the install wrappers. Package names are
+ * compared with a bundled list of popular names per ecosystem
+ * (data/top-packages.json) so a likely typosquat is flagged before, or
+ * without, the Socket API round-trip.
+ *
+ * The distance is a Levenshtein distance with transpositions where the
+ * common slips cost half: neighbouring keys and lookalike characters,
+ * swapped letters, doubled or dropped letters, and separators. Names on the
+ * list are never flagged. The Socket API `didYouMean` alert then confirms or
+ * clears a local verdict, see getTyposquatVerdicts.
+ */
+
+import topPackages from '../../../data/top-packages.json' with { type: 'json' }
+
+import type { SocketArtifact } from '../alert/artifact.mts'
+
+export type TyposquatEcosystem = 'cargo' | 'npm'
+
+export type TyposquatMatch = {
+  distance: number
+  name: string
+  // The popular package the name resembles.
+  target: string
+}
+
+export type TyposquatVerdict = TyposquatMatch & {
+  // The Socket artifact of the package, unless the verdict is `suspected`.
+  artifact?: SocketArtifact | undefined
+  // `confirmed` and `cleared` come from Socket's `didYouMean` alert,
+  // `suspected` is the local verdict alone.
+  status: 'cleared' | 'confirmed' | 'suspected'
+}
+
+const KEYBOARD_ROWS = ['1234567890', 'qwertyuiop', 'asdfghjkl', 'zxcvbnm']
+
+// Horizontal stagger of each keyboard row, in keys.
+const KEYBOARD_ROW_OFFSETS = [-0.5, 0, 0.25, 0.75]
+
+// Characters easily misread for each other, as sorted pairs.
+const LOOKALIKE_PAIRS = new Set(['0o', '1i', '1l', 'il', 'mn', 'uv', 'vw'])
+
+const SEPARATORS = new Set(['-', '.', '_'])
+
+// Names this short are one edit away from too many others.
+const MIN_TARGET_LENGTH = 4
+
+const KEY_POSITIONS = new Map<string, { col: number; row: number }>()
+for (let row = 0, { length } = KEYBOARD_ROWS; row < length; row += 1) {
+  const keys = KEYBOARD_ROWS[row]!
+  for (let col = 0; col < keys.length; col += 1) {
+    KEY_POSITIONS.set(keys[col]!, {
+      col: col + KEYBOARD_ROW_OFFSETS[row]!,
+      row,
+    })
+  }
+}
+
+// Normalized names and their spelling on the list, most popular first.
+const topNames = new Map<TyposquatEcosystem, Array<[string, string]>>()
+
+function isAdjacentKey(a: string, b: string): boolean {
+  const posA = KEY_POSITIONS.get(a)
+  const posB = KEY_POSITIONS.get(b)
+  if (!posA || !posB) {
+    return false
+  }
+  const rowDelta = Math.abs(posA.row - posB.row)
+  const colDelta = Math.abs(posA.col - posB.col)
+  return rowDelta === 0 ? colDelta === 1 : rowDelta === 1 && colDelta < 1
+}
+
+function getSubstitutionCost(a: string, b: string): number {
+  if (a === b) {
+    return 0
+  }
+  if (
+    isAdjacentKey(a, b) ||
+    LOOKALIKE_PAIRS.has(a < b ? `${a}${b}` : `${b}${a}`) ||
+    (SEPARATORS.has(a) && SEPARATORS.has(b))
+  ) {
+    return 0.5
+  }
+  return 1
+}
+
+// Cost of the extra character at `index`: separators and doubled letters
+// are the cheap slips.
+function getIndelCost(value: string, index: number): number {
+  const char = value[index]!
+  return SEPARATORS.has(char) || value[index - 1] === char ? 0.5 : 1
+}
+
+/**
+ * The weighted edit distance between two names, see the module comment.
+ */
+export function getTyposquatDistance(a: string, b: string): number {
+  const rows: number[][] = [Array.from({ length: b.length + 1 }, () => 0)]
+  for (let j = 1; j <= b.length; j += 1) {
+    rows[0]![j] = rows[0]![j - 1]! + getIndelCost(b, j - 1)
+  }
+  for (let i = 1; i <= a.length; i += 1) {
+    const row = [rows[i - 1]![0]! + getIndelCost(a, i - 1)]
+    for (let j = 1; j <= b.length; j += 1) {
+      let cost = Math.min(
+        // An extra character in `a`.
+        rows[i - 1]![j]! + getIndelCost(a, i - 1),
+        // A character of `b` missing from `a`.
+        row[j - 1]! + getIndelCost(b, j - 1),
+        rows[i - 1]![j - 1]! + getSubstitutionCost(a[i - 1]!, b[j - 1]!),
+      )
+      if (
+        i > 1 &&
+        j > 1 &&
+        a[i - 1] === b[j - 2] &&
+        a[i - 2] === b[j - 1] &&
+        a[i - 1] !== a[i - 2]
+      ) {
+        cost = Math.min(cost, rows[i - 2]![j - 2]! + 0.5)
+      }
+      row.push(cost)
+    }
+    rows.push(row)
+  }
+  return rows[a.length]![b.length]!
+}
+
+/**
+ * A name as the registry compares it: crates.io treats `-` and `_` alike.
+ */
+export function normalizeTyposquatName(
+  ecosystem: TyposquatEcosystem,
+  name: string,
+): string {
+  const lowerName = name.toLowerCase()
+  return ecosystem === 'cargo' ? lowerName.replaceAll('_', '-') : lowerName
+}
+
+function getTopNames(ecosystem: TyposquatEcosystem): Array<[string, string]> {
+  let names = topNames.get(ecosystem)
+  if (!names) {
+    names = topPackages[ecosystem].map(name => [
+      normalizeTyposquatName(ecosystem, name),
+      name,
+    ])
+    topNames.set(ecosystem, names)
+  }
+  return names
+}
+
+/**
+ * The popular package a name most likely imitates, or undefined. Longer
+ * names allow a larger distance. Ties go to the more popular package.
+ */
+export function findTyposquatTarget(
+  ecosystem: TyposquatEcosystem,
+  name: string,
+): TyposquatMatch | undefined {
+  const normalized = normalizeTyposquatName(ecosystem, name)
+  const names = getTopNames(ecosystem)
+  if (names.some(({ 0: topName }) => topName === normalized)) {
+    return undefined
+  }
+  let best: TyposquatMatch | undefined
+  for (let i = 0, { length } = names; i < length; i += 1) {
+    const { 0: topName, 1: target } = names[i]!
+    if (
+      topName.length < MIN_TARGET_LENGTH ||
+      Math.abs(topName.length - normalized.length) > 2
+    ) {
+      continue
+    }
+    const maxDistance = topName.length >= 10 ? 1.5 : 1
+    const distance = getTyposquatDistance(normalized, topName)
+    if (distance <= maxDistance && (!best || distance < best.distance)) {
+      best = { distance, name, target }
+    }
+  }
+  return best
+}
+
+/**
+ * Refine local typosquat matches with the Socket artifacts of the same
+ * packages: a `didYouMean` alert confirms a match, an artifact without one
+ * clears it. Matches Socket has no data for stay suspected.
+ */
+export function getTyposquatVerdicts(
+  matches: TyposquatMatch[],
+  artifacts: SocketArtifact[] | undefined,
+): TyposquatVerdict[] {
+  return matches.map(match => {
+    const artifact = artifacts?.find(a => {
+      const name = a.namespace ? `${a.namespace}/${a.name}` : a.name
+      return name === match.name
+    })
+    if (!artifact) {
+      return { ...match, status: 'suspected' as const }
+    }
+    const confirmed = (artifact.alerts ?? []).some(
+      alert => alert.type === 'didYouMean',
+    )
+    return {
+      ...match,
+      artifact,
+      status: confirmed ? ('confirmed' as const) : ('cleared' as const),
+    }
+  })
+}
@@ -0,0 +1,210 @@
+/**
+ * API token storage in the OS credential manager: the macOS Keychain, the
+ * Windows Credential Manager, or a libsecret keyring (through `secret-tool`)
+ * elsewhere. Each auth profile is an account of the `socket-cli` service.
+ *
+ * The platform tools are spawned synchronously because config reads are
+ * synchronous. Tokens are written on stdin so they never show up in the
+ * process list. A config file stores KEYCHAIN_API_TOKEN_REF in place of a
+ * token that lives in the credential manager.
+ */
+
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+import { spawnSync } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import { getSocketCliNoKeychain } from '../env/socket-cli-no-keychain.mts'
+
+import type { CResult } from '../types.mts'
+
+export const KEYCHAIN_API_TOKEN_REF = '<keychain>'
+
+export const KEYCHAIN_SERVICE = 'socket-cli'
+
+// Profile names, see isValidProfileName() in config.mts.
+const ACCOUNT_REGEXP = /^[a-zA-Z0-9][\w.-]{0,63}$/
+
+// Printable ASCII without quotes, backslashes or spaces, so the token can be
+// quoted on the stdin of `security -i`.
+const TOKEN_REGEXP = /^[!#-[\]-~]+$/
+
+const KEYCHAIN_TIMEOUT_MS = 10_000
+
+const WIN32_VAULT = [
+  '$ErrorActionPreference="Stop"',
+  '[void][Windows.Security.Credentials.PasswordVault,Windows.Security.Credentials,ContentType=WindowsRuntime]',
+  '$v=New-Object Windows.Security.Credentials.PasswordVault',
+].join(';')
+
+type KeychainCommand = {
+  args: string[]
+  bin: string
+  input?: string | undefined
+}
+
+const cachedTokens = new Map<string, string | undefined>()
+
+function getKeychainCommand(
+  action: 'delete' | 'read' | 'store',
+  account: string,
+  token?: string | undefined,
+): KeychainCommand | undefined {
+  if (process.platform === 'darwin') {
+    if (action === 'store') {
+      return {
+        args: ['-i'],
+        bin: 'security',
+        input: `add-generic-password -U -s ${KEYCHAIN_SERVICE} -a ${account} -l "Socket CLI API token" -w "${token}"\n`,
+      }
+    }
+    return {
+      args: [
+        action === 'read'
+          ? 'find-generic-password'
+          : 'delete-generic-password',
+        '-s',
+        KEYCHAIN_SERVICE,
+        '-a',
+        account,
+        ...(action === 'read' ? ['-w'] : []),
+      ],
+      bin: 'security',
+    }
+  }
+  if (process.platform === 'win32') {
+    const retrieve = `$v.Retrieve('${KEYCHAIN_SERVICE}','${account}')`
+    const script =
+      action === 'store'
+        ? `$t=[Console]::In.ReadLine();try{$v.Remove(${retrieve})}catch{};$v.Add((New-Object Windows.Security.Credentials.PasswordCredential('${KEYCHAIN_SERVICE}','${account}',$t)))`
+        : action === 'read'
+          ? `$c=${retrieve};$c.RetrievePassword();[Console]::Out.Write($c.Password)`
+          : `$v.Remove(${retrieve})`
+    return {
+      args: [
+        '-NoProfile',
+        '-NonInteractive',
+        '-Command',
+        `${WIN32_VAULT};${script}`,
+      ],
+      bin: 'powershell.exe',
+      input: action === 'store' ? `${token}\n` : undefined,
+    }
+  }
+  if (process.platform === 'linux' || process.platform === 'freebsd') {
+    const attributes = ['service', KEYCHAIN_SERVICE, 'account', account]
+    return {
+      args:
+        action === 'store'
+          ? ['store', '--label=Socket CLI API token', ...attributes]
+          : [action === 'read' ? 'lookup' : 'clear', ...attributes],
+      bin: 'secret-tool',
+      input: action === 'store' ? token : undefined,
+    }
+  }
+  return undefined
+}
+
+function runKeychainCommand(
+  command: KeychainCommand,
+): { ok: boolean; stdout: string } {
+  try {
+    const result = spawnSync(command.bin, command.args, {
+      input: command.input,
+      stdio: ['pipe', 'pipe', 'pipe'],
+      timeout: KEYCHAIN_TIMEOUT_MS,
+    })
+    return {
+      ok: result.status === 0,
+      stdout: String(result.stdout ?? ''),
+    }
+  } catch (e) {
+    debug(`${command.bin} failed: ${(e as Error).message}`)
+    return { ok: false, stdout: '' }
+  }
+}
+
+export function deleteKeychainToken(account: string): boolean {
+  cachedTokens.delete(account)
+  const command = ACCOUNT_REGEXP.test(account)
+    ? getKeychainCommand('delete', account)
+    : undefined
+  return command ? runKeychainCommand(command).ok : false
+}
+
+/**
+ * Whether tokens may go to the credential manager: not turned off through
+ * SOCKET_CLI_NO_KEYCHAIN and on a platform with a known tool.
+ */
+export function isKeychainEnabled(): boolean {
+  return (
+    !getSocketCliNoKeychain() &&
+    getKeychainCommand('read', 'default') !== undefined
+  )
+}
+
+export function readKeychainToken(account: string): string | undefined {
+  if (cachedTokens.has(account)) {
+    return cachedTokens.get(account)
+  }
+  const command = ACCOUNT_REGEXP.test(account)
+    ? getKeychainCommand('read', account)
+    : undefined
+  let token: string | undefined
+  if (command && !getSocketCliNoKeychain()) {
+    const result = runKeychainCommand(command)
+    token = (result.ok && result.stdout.trim()) || undefined
+    if (!token) {
+      debug(`No API token for "${account}" in the OS keychain`)
+    }
+  }
+  cachedTokens.set(account, token)
+  return token
+}
+
+/**
+ * Reset the in-memory token cache for testing purposes.
+ *
+ * @internal
+ */
+export function resetKeychainForTesting(): void {
+  cachedTokens.clear()
+}
+
+export function storeKeychainToken(
+  account: string,
+  token: string,
+): CResult<undefined> {
+  if (!TOKEN_REGEXP.test(token)) {
+    return {
+      ok: false,
+      message: 'Unsupported API token',
+      cause:
+        'The API token contains characters the OS keychain tools cannot take.',
+    }
+  }
+  if (getSocketCliNoKeychain()) {
+    return {
+      ok: false,
+      message: 'OS keychain disabled',
+      cause: 'SOCKET_CLI_NO_KEYCHAIN is set.',
+    }
+  }
+  const command = ACCOUNT_REGEXP.test(account)
+    ? getKeychainCommand('store', account, token)
+    : undefined
+  if (!command) {
+    return {
+      ok: false,
+      message: 'OS keychain unavailable',
+      cause: 'No OS credential manager is supported on this platform.',
+    }
+  }
+  if (!runKeychainCommand(command).ok) {
+    return {
+      ok: false,
+      message: 'OS keychain unavailable',
+      cause: `Could not store the API token with \`${command.bin}\`; the credential manager may be locked or not installed.`,
+    }
+  }
+  cachedTokens.set(account, token)
+  return { ok: true, data: undefined }
+}
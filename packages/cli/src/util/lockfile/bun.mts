@@ -0,0 +1,459 @@
+/**
+ * Bun support: the text `bun.lock` and the binary `bun.lockb`.
+ *
+ * `bun.lock` is JSON with trailing commas. Its `packages` are keyed by install
+ * path (`react-dom/scheduler` is the copy nested under `react-dom`), so
+ * dependencies are resolved the way Node resolves `node_modules`: the nested
+ * copy first, then each parent, then the hoisted one. Every workspace is
+ * walked from its manifest, so each package lists the workspaces that pull it
+ * in. Workspace members are local and left out.
+ *
+ * `bun.lockb` is decoded into the equivalent Yarn v1 lockfile, whose direct
+ * dependencies come from the sibling `package.json`.
+ */
+
+import path from 'node:path'
+
+import { parse as parseBunLockb } from '@socketregistry/hyrious__bun.lockb/index.cjs'
+
+import {
+  BUN_LOCK,
+  BUN_LOCKB,
+} from '@socketsecurity/lib-stable/constants/agents'
+
+import {
+  integrityToLockfileHashes,
+  isNpmRegistryVersion,
+  splitNpmPackageKey,
+  splitNpmPackageName,
+} from './npm.mts'
+
+import type {
+  LockfileDependency,
+  LockfileParseContext,
+  LockfileParser,
+} from './types.mts'
+
+type DependencyMap = Record<string, string>
+
+export type BunLockWorkspace = {
+  name?: string | undefined
+  dependencies?: DependencyMap | undefined
+  devDependencies?: DependencyMap | undefined
+  optionalDependencies?: DependencyMap | undefined
+}
+
+export type BunLockPackageInfo = {
+  dependencies?: DependencyMap | undefined
+  optionalDependencies?: DependencyMap | undefined
+}
+
+// [resolution, registry, info, integrity] for npm packages, shorter tuples for
+// workspace, git and tarball dependencies.
+export type BunLockPackage = [string, ...unknown[]]
+
+export type BunLock = {
+  lockfileVersion?: number | undefined
+  workspaces?: Record<string, BunLockWorkspace> | undefined
+  packages?: Record<string, BunLockPackage> | undefined
+}
+
+const WORKSPACE_GROUPS = [
+  'dependencies',
+  'optionalDependencies',
+  'devDependencies',
+] as const
+
+// Index of the first character at or after `index` that is neither
+// whitespace nor part of a comment.
+function skipJsonTrivia(content: string, index: number): number {
+  let i = index
+  const { length } = content
+  while (i < length) {
+    if (/\s/.test(content[i]!)) {
+      i += 1
+    } else if (content.startsWith('//', i)) {
+      const newline = content.indexOf('\n', i)
+      i = newline === -1 ? length : newline
+    } else if (content.startsWith('/*', i)) {
+      const end = content.indexOf('*/', i + 2)
+      i = end === -1 ? length : end + 2
+    } else {
+      break
+    }
+  }
+  return i
+}
+
+/**
+ * Parse JSON that may contain comments and trailing commas.
+ */
+export function parseJsonWithTrailingCommas(content: string): unknown {
+  let out = ''
+  let i = 0
+  const { length } = content
+  while (i < length) {
+    const ch = content[i]!
+    if (ch === '"') {
+      const start = i
+      i += 1
+      while (i < length && content[i] !== '"') {
+        i += content[i] === '\\' ? 2 : 1
+      }
+      i += 1
+      out += content.slice(start, i)
+      continue
+    }
+    const next = skipJsonTrivia(content, i)
+    if (next !== i) {
+      // Keep a single space so adjacent tokens stay separated.
+      out += ' '
+      i = next
+      continue
+    }
+    if (ch === ',') {
+      const after = content[skipJsonTrivia(content, i + 1)]
+      if (after === '}' || after === ']') {
+        i += 1
+        continue
+      }
+    }
+    out += ch
+    i += 1
+  }
+  return JSON.parse(out)
+}
+
+/**
+ * Split an install path such as `@scope/a/b` into its package names.
+ */
+function splitInstallPath(key: string): string[] {
+  const names: string[] = []
+  const segments = key.split('/')
+  for (let i = 0, { length } = segments; i < length; i += 1) {
+    const segment = segments[i]!
+    if (segment.startsWith('@') && i + 1 < length) {
+      names.push(`${segment}/${segments[i + 1]}`)
+      i += 1
+    } else {
+      names.push(segment)
+    }
+  }
+  return names
+}
+
+function getPackageInfo(entry: BunLockPackage): BunLockPackageInfo {
+  const info = entry.find(
+    (item, index) => index > 0 && item !== null && typeof item === 'object',
+  )
+  return (info ?? {}) as BunLockPackageInfo
+}
+
+function readDirectDependencies(
+  workspace: BunLockWorkspace,
+): Array<{ name: string; range: string; dev: boolean }> {
+  const direct: Array<{ name: string; range: string; dev: boolean }> = []
+  for (const group of WORKSPACE_GROUPS) {
+    for (const [name, range] of Object.entries(workspace[group] ?? {})) {
+      direct.push({ name, range, dev: group === 'devDependencies' })
+    }
+  }
+  return direct
+}
+
+export function parseBunLock(
+  content: string | Buffer,
+): LockfileDependency[] | undefined {
+  let lock: BunLock
+  try {
+    lock = parseJsonWithTrailingCommas(content.toString()) as BunLock
+  } catch {
+    return undefined
+  }
+  const packages = lock.packages ?? {}
+
+  // Node style lookup from the install path `from`.
+  function resolve(from: string[], name: string): string | undefined {
+    for (let depth = from.length; depth >= 0; depth -= 1) {
+      const key = [...from.slice(0, depth), name].join('/')
+      if (Object.hasOwn(packages, key)) {
+        return key
+      }
+    }
+    return undefined
+  }
+
+  function getChildKeys(key: string): string[] {
+    const entry = packages[key]
+    if (!entry) {
+      return []
+    }
+    const info = getPackageInfo(entry)
+    const from = splitInstallPath(key)
+    const childKeys: string[] = []
+    for (const deps of [info.dependencies, info.optionalDependencies]) {
+      for (const name of Object.keys(deps ?? {})) {
+        const childKey = resolve(from, name)
+        if (childKey) {
+          childKeys.push(childKey)
+        }
+      }
+    }
+    return childKeys
+  }
+
+  const directKeys = new Set<string>()
+  const prodKeys = new Set<string>()
+  const workspacesByKey = new Map<string, Set<string>>()
+  for (const [workspacePath, workspace] of Object.entries(
+    lock.workspaces ?? {},
+  )) {
+    const workspaceName = workspace.name || workspacePath || '.'
+    // Overrides nested under a member are keyed by its name.
+    const from = workspacePath ? [workspace.name ?? workspacePath] : []
+    const visited = { dev: new Set<string>(), prod: new Set<string>() }
+    for (const { dev, name } of readDirectDependencies(workspace)) {
+      const key = resolve(from, name)
+      if (!key) {
+        continue
+      }
+      directKeys.add(key)
+      const seen = dev ? visited.dev : visited.prod
+      const queue = [key]
+      while (queue.length) {
+        const current = queue.pop()!
+        if (seen.has(current)) {
+          continue
+        }
+        seen.add(current)
+        if (!dev) {
+          prodKeys.add(current)
+        }
+        const workspaces = workspacesByKey.get(current) ?? new Set()
+        workspaces.add(workspaceName)
+        workspacesByKey.set(current, workspaces)
+        queue.push(...getChildKeys(current))
+      }
+    }
+  }
+
+  // Several install paths can hold the same version; they share one entry.
+  const byId = new Map<string, LockfileDependency>()
+  for (const [key, entry] of Object.entries(packages)) {
+    const resolution = typeof entry?.[0] === 'string' ? entry[0] : ''
+    const parts = splitNpmPackageKey(resolution)
+    if (!parts || !isNpmRegistryVersion(parts.version)) {
+      continue
+    }
+    const id = resolution
+    const dep: LockfileDependency = byId.get(id) ?? {
+      id,
+      type: 'npm',
+      ...splitNpmPackageName(parts.packageName),
+      version: parts.version,
+      direct: false,
+    }
+    dep.direct ||= directKeys.has(key)
+    const workspaces = workspacesByKey.get(key)
+    if (workspaces) {
+      dep.dev = (dep.dev ?? true) && !prodKeys.has(key)
+      dep.workspaces = [...new Set([...(dep.workspaces ?? []), ...workspaces])]
+    }
+    if (!dep.hashes) {
+      const integrity = entry[3]
+      const hashes = integrityToLockfileHashes(
+        typeof integrity === 'string' ? integrity : undefined,
+      )
+      if (hashes.length) {
+        dep.hashes = hashes
+      }
+    }
+    const childIds = getChildKeys(key)
+      .map(childKey => packages[childKey]?.[0])
+      .filter(
+        (childId): childId is string =>
+          typeof childId === 'string' &&
+          isNpmRegistryVersion(splitNpmPackageKey(childId)?.version ?? ''),
+      )
+    if (childIds.length) {
+      dep.dependencies = [
+        ...new Set([...(dep.dependencies ?? []), ...childIds]),
+      ]
+    }
+    byId.set(id, dep)
+  }
+  return [...byId.values()]
+}
+
+export type YarnLockEntry = {
+  name: string
+  version: string
+  integrity?: string | undefined
+  dependencies: DependencyMap
+}
+
+function unquoteYarn(value: string): string {
+  return value.startsWith('"') && value.endsWith('"')
+    ? value.slice(1, -1)
+    : value
+}
+
+function getYarnSpecName(spec: string): string | undefined {
+  const parts = splitNpmPackageKey(spec)
+  if (!parts) {
+    return undefined
+  }
+  // Aliases (`alias@npm:real@^1.0.0`) install the real package.
+  return parts.version.startsWith('npm:')
+    ? splitNpmPackageKey(parts.version.slice('npm:'.length))?.packageName
+    : parts.packageName
+}
+
+/**
+ * Parse a Yarn v1 lockfile into its entries, keyed by every `name@range`
+ * spec that resolves to them.
+ */
+export function parseYarnLockV1(content: string): Map<string, YarnLockEntry> {
+  const bySpec = new Map<string, YarnLockEntry>()
+  let current: YarnLockEntry | undefined
+  let inDependencies = false
+  const lines = content.split(/\r?\n/)
+  for (let i = 0, { length } = lines; i < length; i += 1) {
+    const line = lines[i]!
+    if (!line.trim() || line.trimStart().startsWith('#')) {
+      continue
+    }
+    const indent = line.length - line.trimStart().length
+    const text = line.trim()
+    if (indent === 0) {
+      const specs = text
+        .replace(/:$/, '')
+        .split(/,\s*/)
+        .map(unquoteYarn)
+      const name = getYarnSpecName(specs[0] ?? '')
+      current = name ? { name, version: '', dependencies: {} } : undefined
+      inDependencies = false
+      for (const spec of specs) {
+        if (current) {
+          bySpec.set(spec, current)
+        }
+      }
+      continue
+    }
+    if (!current) {
+      continue
+    }
+    if (indent <= 2) {
+      inDependencies =
+        text === 'dependencies:' || text === 'optionalDependencies:'
+      const match = /^(\S+)\s+(.+)$/.exec(text)
+      if (match?.[1] === 'version') {
+        current.version = unquoteYarn(match[2]!)
+      } else if (match?.[1] === 'integrity') {
+        current.integrity = unquoteYarn(match[2]!)
+      }
+      continue
+    }
+    if (inDependencies) {
+      const match = /^("[^"]+"|\S+)\s+(.+)$/.exec(text)
+      if (match) {
+        current.dependencies[unquoteYarn(match[1]!)] = unquoteYarn(match[2]!)
+      }
+    }
+  }
+  return bySpec
+}
+
+function readRootManifest(
+  context: LockfileParseContext,
+): BunLockWorkspace | undefined {
+  const content = context.readFile(
+    path.join(path.dirname(context.filepath), 'package.json'),
+  )
+  if (content === undefined) {
+    return undefined
+  }
+  try {
+    return JSON.parse(content.toString()) as BunLockWorkspace
+  } catch {}
+  return undefined
+}
+
+export function parseBunLockb(
+  content: string | Buffer,
+  context: LockfileParseContext,
+): LockfileDependency[] | undefined {
+  let yarnLock: string
+  try {
+    yarnLock = parseBunLockb(
+      Buffer.isBuffer(content) ? content : Buffer.from(content),
+    )
+  } catch {
+    return undefined
+  }
+  const bySpec = parseYarnLockV1(yarnLock)
+  const getId = (entry: YarnLockEntry) => `${entry.name}@${entry.version}`
+  const childrenOf = (entry: YarnLockEntry) =>
+    Object.entries(entry.dependencies)
+      .map(([name, range]) => bySpec.get(`${name}@${range}`))
+      .filter((child): child is YarnLockEntry => !!child)
+
+  const manifest = readRootManifest(context)
+  const directIds = new Set<string>()
+  let prodIds: Set<string> | undefined
+  if (manifest) {
+    prodIds = new Set()
+    const queue: YarnLockEntry[] = []
+    for (const { dev, name, range } of readDirectDependencies(manifest)) {
+      const entry = bySpec.get(`${name}@${range}`)
+      if (entry) {
+        directIds.add(getId(entry))
+        if (!dev) {
+          queue.push(entry)
+        }
+      }
+    }
+    while (queue.length) {
+      const entry = queue.pop()!
+      const id = getId(entry)
+      if (prodIds.has(id)) {
+        continue
+      }
+      prodIds.add(id)
+      queue.push(...childrenOf(entry))
+    }
+  }
+
+  const byId = new Map<string, LockfileDependency>()
+  for (const entry of bySpec.values()) {
+    const id = getId(entry)
+    if (byId.has(id) || !isNpmRegistryVersion(entry.version)) {
+      continue
+    }
+    const hashes = integrityToLockfileHashes(entry.integrity)
+    const childIds = childrenOf(entry).map(getId)
+    byId.set(id, {
+      id,
+      type: 'npm',
+      ...splitNpmPackageName(entry.name),
+      version: entry.version,
+      ...(manifest ? { direct: directIds.has(id) } : {}),
+      ...(prodIds ? { dev: !prodIds.has(id) } : {}),
+      ...(hashes.length ? { hashes } : {}),
+      ...(childIds.length ? { dependencies: childIds } : {}),
+    })
+  }
+  return [...byId.values()]
+}
+
+export const bunLockfileParser: LockfileParser = {
+  ecosystem: 'npm',
+  filenames: [BUN_LOCK, BUN_LOCKB],
+  parse: (content, context) => {
+    const dependencies =
+      path.basename(context.filepath) === BUN_LOCKB
+        ? parseBunLockb(content, context)
+        : parseBunLock(content)
+    return dependencies ? { ecosystem: 'npm', dependencies } : undefined
+  },
+}
@@ -0,0 +1,342 @@
+/**
+ * Rust support: `Cargo.lock` (v1 through v4) with its workspace `Cargo.toml`.
+ *
+ * Packages without a `source` are workspace members or path dependencies.
+ * They are not published crates, so they are not reported themselves; their
+ * own dependencies become the direct dependencies of the project. Git
+ * dependencies keep their repository and pinned commit as a `vcs_url`
+ * qualifier. When the workspace manifests are available, crates only reachable
+ * through `[dev-dependencies]` are marked as dev dependencies.
+ */
+
+import path from 'node:path'
+
+import { parseTomlTables } from './toml.mts'
+
+import type { TomlValue } from './toml.mts'
+import type {
+  LockfileDependency,
+  LockfileParseContext,
+  LockfileParser,
+} from './types.mts'
+
+export const CARGO_LOCK = 'Cargo.lock'
+
+export const CARGO_TOML = 'Cargo.toml'
+
+const CRATES_IO_SOURCE = 'registry+https://github.com/rust-lang/crates.io-index'
+
+export type CargoLockPackage = {
+  name: string
+  version: string
+  source?: string | undefined
+  checksum?: string | undefined
+  dependencies: string[]
+}
+
+export function parseCargoLock(content: string): CargoLockPackage[] {
+  const tables = parseTomlTables(content)
+  // v1 lockfiles keep checksums in a `[metadata]` table keyed by
+  // `checksum <name> <version> (<source>)`.
+  const legacyChecksums = new Map<string, string>()
+  const packages: CargoLockPackage[] = []
+  for (let i = 0, { length } = tables; i < length; i += 1) {
+    const { entries, header, isArray } = tables[i]!
+    if (header === 'metadata') {
+      for (const [key, value] of Object.entries(entries)) {
+        if (key.startsWith('checksum ') && typeof value === 'string') {
+          legacyChecksums.set(key.slice('checksum '.length), value)
+        }
+      }
+      continue
+    }
+    if (header !== 'package' || !isArray) {
+      continue
+    }
+    const { checksum, dependencies, name, source, version } = entries
+    if (typeof name !== 'string' || typeof version !== 'string') {
+      continue
+    }
+    packages.push({
+      name,
+      version,
+      ...(typeof source === 'string' ? { source } : {}),
+      ...(typeof checksum === 'string' ? { checksum } : {}),
+      dependencies: Array.isArray(dependencies)
+        ? dependencies.filter((d): d is string => typeof d === 'string')
+        : [],
+    })
+  }
+  for (let i = 0, { length } = packages; i < length; i += 1) {
+    const pkg = packages[i]!
+    if (!pkg.checksum && pkg.source) {
+      const checksum = legacyChecksums.get(
+        `${pkg.name} ${pkg.version} (${pkg.source})`,
+      )
+      if (checksum && checksum !== '<none>') {
+        pkg.checksum = checksum
+      }
+    }
+  }
+  return packages
+}
+
+/**
+ * Purl qualifiers for a lockfile `source`. crates.io needs none.
+ */
+export function getCargoSourceQualifiers(
+  source: string | undefined,
+): Record<string, string> | undefined {
+  if (!source || source === CRATES_IO_SOURCE) {
+    return undefined
+  }
+  if (source.startsWith('git+')) {
+    // git+https://github.com/org/repo?branch=main#<commit>
+    const hashIndex = source.lastIndexOf('#')
+    const url = hashIndex === -1 ? source : source.slice(0, hashIndex)
+    const commit = hashIndex === -1 ? '' : source.slice(hashIndex + 1)
+    const base = url.split('?')[0]!
+    return { vcs_url: commit ? `${base}@${commit}` : base }
+  }
+  const registry = /^(?:sparse|registry)\+(.+)$/.exec(source)
+  return registry ? { repository_url: registry[1]! } : undefined
+}
+
+type CargoManifestDeps = {
+  name: string | undefined
+  normal: Set<string>
+  dev: Set<string>
+}
+
+function getDependencyNames(entries: Record<string, TomlValue>): string[] {
+  return Object.entries(entries).map(([key, value]) =>
+    value &&
+    typeof value === 'object' &&
+    !Array.isArray(value) &&
+    typeof value['package'] === 'string'
+      ? value['package']
+      : key,
+  )
+}
+
+export function parseCargoManifest(content: string): {
+  deps: CargoManifestDeps
+  members: string[]
+} {
+  const tables = parseTomlTables(content)
+  const deps: CargoManifestDeps = {
+    name: undefined,
+    normal: new Set(),
+    dev: new Set(),
+  }
+  let members: string[] = []
+  for (let i = 0, { length } = tables; i < length; i += 1) {
+    const { entries, header } = tables[i]!
+    if (header === 'package' && typeof entries['name'] === 'string') {
+      deps.name = entries['name']
+      continue
+    }
+    if (header === 'workspace' && Array.isArray(entries['members'])) {
+      members = entries['members'].filter(
+        (m): m is string => typeof m === 'string',
+      )
+      continue
+    }
+    // `[dependencies]`, `[target.<cfg>.dev-dependencies]`, and the
+    // `[dependencies.<name>]` table form.
+    const match =
+      /^(?:target\..+\.)?(dependencies|dev-dependencies|build-dependencies)(?:\.(.+))?$/.exec(
+        header,
+      )
+    if (!match) {
+      continue
+    }
+    const bucket = match[1] === 'dev-dependencies' ? deps.dev : deps.normal
+    const names = match[2]
+      ? [
+          typeof entries['package'] === 'string'
+            ? entries['package']
+            : match[2],
+        ]
+      : getDependencyNames(entries)
+    for (const name of names) {
+      bucket.add(name)
+    }
+  }
+  return { deps, members }
+}
+
+function readText(
+  context: LockfileParseContext,
+  filepath: string,
+): string | undefined {
+  const content = context.readFile(filepath)
+  return content === undefined ? undefined : content.toString()
+}
+
+/**
+ * Read the manifests of the workspace rooted next to the lockfile. Only
+ * literal member paths and trailing `*` globs are expanded, which covers the
+ * layouts cargo itself generates.
+ */
+function readWorkspaceManifests(
+  context: LockfileParseContext,
+): CargoManifestDeps[] {
+  const { listDir = () => [] } = context
+  const rootDir = path.dirname(context.filepath)
+  const rootContent = readText(context, path.join(rootDir, CARGO_TOML))
+  if (rootContent === undefined) {
+    return []
+  }
+  const root = parseCargoManifest(rootContent)
+  const manifests = [root.deps]
+  for (const member of root.members) {
+    const dirs = member.endsWith('/*')
+      ? listDir(path.join(rootDir, member.slice(0, -2))).map(d =>
+          path.join(rootDir, member.slice(0, -2), d),
+        )
+      : [path.join(rootDir, member)]
+    for (const dir of dirs) {
+      const content = readText(context, path.join(dir, CARGO_TOML))
+      if (content !== undefined) {
+        manifests.push(parseCargoManifest(content).deps)
+      }
+    }
+  }
+  return manifests
+}
+
+export function parseCargoDependencies(
+  content: string | Buffer,
+  context: LockfileParseContext,
+): LockfileDependency[] {
+  const packages = parseCargoLock(content.toString())
+
+  const byName = new Map<string, CargoLockPackage[]>()
+  for (let i = 0, { length } = packages; i < length; i += 1) {
+    const pkg = packages[i]!
+    const list = byName.get(pkg.name) ?? []
+    list.push(pkg)
+    byName.set(pkg.name, list)
+  }
+  const ids = new Map<CargoLockPackage, string>()
+  for (let i = 0, { length } = packages; i < length; i += 1) {
+    const pkg = packages[i]!
+    ids.set(
+      pkg,
+      `${pkg.name}@${pkg.version}${pkg.source ? ` (${pkg.source})` : ''}`,
+    )
+  }
+
+  // Entries are `name`, `name version` or `name version (source)`, using the
+  // shortest form that is unambiguous within the lockfile.
+  function resolve(spec: string): CargoLockPackage | undefined {
+    const match = /^(\S+)(?: (\S+))?(?: \((.+)\))?$/.exec(spec)
+    if (!match) {
+      return undefined
+    }
+    const [, name, version, source] = match
+    const candidates = byName.get(name!) ?? []
+    return candidates.find(
+      c =>
+        (!version || c.version === version) &&
+        (!source || c.source === source),
+    )
+  }
+
+  const members = packages.filter(pkg => !pkg.source)
+  const directNames = new Map<string, Set<string>>()
+  for (let i = 0, { length } = members; i < length; i += 1) {
+    const member = members[i]!
+    for (const spec of member.dependencies) {
+      const dep = resolve(spec)
+      if (dep?.source) {
+        const owners = directNames.get(dep.name) ?? new Set()
+        owners.add(member.name)
+        directNames.set(dep.name, owners)
+      }
+    }
+  }
+
+  // Work out which crates are only needed by tests/benches/examples. Without
+  // the manifests we cannot tell, so `dev` is left unset.
+  const manifests = readWorkspaceManifests(context)
+  let prodReachable: Set<CargoLockPackage> | undefined
+  if (manifests.length) {
+    const prodRoots: CargoLockPackage[] = []
+    for (let i = 0, { length } = members; i < length; i += 1) {
+      const member = members[i]!
+      const manifest = manifests.find(m => m.name === member.name)
+      for (const spec of member.dependencies) {
+        const dep = resolve(spec)
+        // Members are roots themselves, with their own dev-dependencies.
+        if (!dep?.source) {
+          continue
+        }
+        const devOnly =
+          !!manifest &&
+          manifest.dev.has(dep.name) &&
+          !manifest.normal.has(dep.name)
+        if (!devOnly) {
+          prodRoots.push(dep)
+        }
+      }
+    }
+    prodReachable = new Set()
+    const queue = [...prodRoots]
+    while (queue.length) {
+      const pkg = queue.pop()!
+      if (prodReachable.has(pkg)) {
+        continue
+      }
+      prodReachable.add(pkg)
+      for (const spec of pkg.dependencies) {
+        const dep = resolve(spec)
+        if (dep?.source) {
+          queue.push(dep)
+        }
+      }
+    }
+  }
+
+  const dependencies: LockfileDependency[] = []
+  for (let i = 0, { length } = packages; i < length; i += 1) {
+    const pkg = packages[i]!
+    if (!pkg.source) {
+      continue
+    }
+    const qualifiers = getCargoSourceQualifiers(pkg.source)
+    const owners = directNames.get(pkg.name)
+    const childIds: string[] = []
+    for (const spec of pkg.dependencies) {
+      const dep = resolve(spec)
+      if (dep?.source) {
+        childIds.push(ids.get(dep)!)
+      }
+    }
+    dependencies.push({
+      id: ids.get(pkg)!,
+      type: 'cargo',
+      name: pkg.name,
+      version: pkg.version,
+      ...(qualifiers ? { qualifiers } : {}),
+      direct: !!owners,
+      ...(prodReachable ? { dev: !prodReachable.has(pkg) } : {}),
+      ...(owners ? { workspaces: [...owners] } : {}),
+      ...(pkg.checksum
+        ? { hashes: [{ alg: 'SHA-256', content: pkg.checksum }] }
+        : {}),
+      ...(childIds.length ? { dependencies: childIds } : {}),
+    })
+  }
+  return dependencies
+}
+
+export const cargoLockfileParser: LockfileParser = {
+  ecosystem: 'cargo',
+  filenames: [CARGO_LOCK],
+  parse: (content, context) => ({
+    ecosystem: 'cargo',
+    dependencies: parseCargoDependencies(content, context),
+  }),
+}
@@ -0,0 +1,224 @@
+/**
+ * PHP support: `composer.lock` with its `composer.json`.
+ *
+ * Packages from `packages-dev` are dev dependencies. Direct dependencies are
+ * the `require`/`require-dev` entries of the root `composer.json`, or of the
+ * local path repositories the root pulls in; path packages are not published
+ * themselves and are left out. Dist archives keep their `shasum` as a SHA-1
+ * hash. Branch versions (`dev-main`) float, so those keep the resolved commit
+ * of their source as a `vcs_url` qualifier.
+ */
+
+import path from 'node:path'
+
+import type {
+  LockfileDependency,
+  LockfileHash,
+  LockfileParseContext,
+  LockfileParser,
+} from './types.mts'
+
+export const COMPOSER_JSON = 'composer.json'
+
+export const COMPOSER_LOCK = 'composer.lock'
+
+export type ComposerReference = {
+  type?: string | undefined
+  url?: string | undefined
+  reference?: string | undefined
+  shasum?: string | undefined
+}
+
+export type ComposerLockPackage = {
+  name: string
+  version: string
+  source?: ComposerReference | undefined
+  dist?: ComposerReference | undefined
+  require?: Record<string, string> | undefined
+  'require-dev'?: Record<string, string> | undefined
+  replace?: Record<string, string> | undefined
+  provide?: Record<string, string> | undefined
+}
+
+export type ComposerLock = {
+  packages?: ComposerLockPackage[] | undefined
+  'packages-dev'?: ComposerLockPackage[] | undefined
+}
+
+/**
+ * Platform requirements (`php`, extensions, system libraries and composer
+ * itself) are not installable packages.
+ */
+export function isComposerPlatformPackage(name: string): boolean {
+  return (
+    !name.includes('/') ||
+    /^(?:composer|composer-plugin-api|composer-runtime-api|php.*|ext-.+|lib-.+)$/.test(
+      name,
+    )
+  )
+}
+
+export function getComposerDistHash(
+  pkg: ComposerLockPackage,
+): LockfileHash | undefined {
+  const shasum = pkg.dist?.shasum
+  return shasum && /^[0-9a-f]{40}$/i.test(shasum)
+    ? { alg: 'SHA-1', content: shasum.toLowerCase() }
+    : undefined
+}
+
+export function getComposerQualifiers(
+  pkg: ComposerLockPackage,
+): Record<string, string> | undefined {
+  const { source } = pkg
+  if (
+    !pkg.version.startsWith('dev-') &&
+    !pkg.version.endsWith('-dev') &&
+    !/^[0-9a-f]{40}$/i.test(pkg.version)
+  ) {
+    return undefined
+  }
+  if (!source?.url || !source.reference) {
+    return undefined
+  }
+  const url =
+    source.type === 'git' && !source.url.startsWith('git+')
+      ? `git+${source.url}`
+      : source.url
+  return { vcs_url: `${url}@${source.reference}` }
+}
+
+function readRootRequires(
+  context: LockfileParseContext,
+): string[] | undefined {
+  const content = context.readFile(
+    path.join(path.dirname(context.filepath), COMPOSER_JSON),
+  )
+  if (content === undefined) {
+    return undefined
+  }
+  try {
+    const json = JSON.parse(content.toString()) as {
+      require?: Record<string, string> | undefined
+      'require-dev'?: Record<string, string> | undefined
+    }
+    return [
+      ...Object.keys(json.require ?? {}),
+      ...Object.keys(json['require-dev'] ?? {}),
+    ]
+  } catch {}
+  return undefined
+}
+
+export function parseComposerDependencies(
+  content: string | Buffer,
+  context: LockfileParseContext,
+): LockfileDependency[] | undefined {
+  let lock: ComposerLock
+  try {
+    lock = JSON.parse(content.toString()) as ComposerLock
+  } catch {
+    return undefined
+  }
+  const prod = lock.packages ?? []
+  const dev = lock['packages-dev'] ?? []
+  const all = [...prod, ...dev]
+  const devPackages = new Set(dev)
+
+  // Resolve requirement names, including packages that `replace` or `provide`
+  // another name.
+  const byName = new Map<string, ComposerLockPackage>()
+  for (let i = 0, { length } = all; i < length; i += 1) {
+    const pkg = all[i]!
+    byName.set(pkg.name.toLowerCase(), pkg)
+  }
+  for (let i = 0, { length } = all; i < length; i += 1) {
+    const pkg = all[i]!
+    for (const alias of [
+      ...Object.keys(pkg.replace ?? {}),
+      ...Object.keys(pkg.provide ?? {}),
+    ]) {
+      const key = alias.toLowerCase()
+      if (!byName.has(key)) {
+        byName.set(key, pkg)
+      }
+    }
+  }
+  const isPathPackage = (pkg: ComposerLockPackage) => pkg.dist?.type === 'path'
+  const getId = (pkg: ComposerLockPackage) => `${pkg.name}@${pkg.version}`
+
+  // Direct requirements mapped to the local package that declares them.
+  const owners = new Map<ComposerLockPackage, Set<string>>()
+  function addDirect(requires: string[], owner: string | undefined): void {
+    for (const name of requires) {
+      const pkg = byName.get(name.toLowerCase())
+      if (!pkg || isPathPackage(pkg)) {
+        continue
+      }
+      const set = owners.get(pkg) ?? new Set()
+      if (owner) {
+        set.add(owner)
+      }
+      owners.set(pkg, set)
+    }
+  }
+  const rootRequires = readRootRequires(context)
+  if (rootRequires) {
+    addDirect(rootRequires, undefined)
+  }
+  for (let i = 0, { length } = all; i < length; i += 1) {
+    const pkg = all[i]!
+    if (isPathPackage(pkg)) {
+      addDirect(Object.keys(pkg.require ?? {}), pkg.name)
+    }
+  }
+
+  // Without a manifest or path packages there is nothing to tell direct
+  // dependencies apart, so `direct` is left unset.
+  const knowsDirect = rootRequires !== undefined || owners.size > 0
+
+  const dependencies: LockfileDependency[] = []
+  for (let i = 0, { length } = all; i < length; i += 1) {
+    const pkg = all[i]!
+    const slash = pkg.name.indexOf('/')
+    if (isPathPackage(pkg) || slash === -1) {
+      continue
+    }
+    const qualifiers = getComposerQualifiers(pkg)
+    const hash = getComposerDistHash(pkg)
+    const workspaces = owners.get(pkg)
+    const childIds: string[] = []
+    for (const name of Object.keys(pkg.require ?? {})) {
+      if (isComposerPlatformPackage(name)) {
+        continue
+      }
+      const child = byName.get(name.toLowerCase())
+      if (child && !isPathPackage(child)) {
+        childIds.push(getId(child))
+      }
+    }
+    dependencies.push({
+      id: getId(pkg),
+      type: 'composer',
+      namespace: pkg.name.slice(0, slash),
+      name: pkg.name.slice(slash + 1),
+      version: pkg.version,
+      ...(qualifiers ? { qualifiers } : {}),
+      ...(knowsDirect ? { direct: !!workspaces } : {}),
+      dev: devPackages.has(pkg),
+      ...(workspaces?.size ? { workspaces: [...workspaces] } : {}),
+      ...(hash ? { hashes: [hash] } : {}),
+      ...(childIds.length ? { dependencies: childIds } : {}),
+    })
+  }
+  return dependencies
+}
+
+export const composerLockfileParser: LockfileParser = {
+  ecosystem: 'composer',
+  filenames: [COMPOSER_LOCK],
+  parse: (content, context) => {
+    const dependencies = parseComposerDependencies(content, context)
+    return dependencies ? { ecosystem: 'composer', dependencies } : undefined
+  },
+}
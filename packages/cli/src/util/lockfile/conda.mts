@@ -0,0 +1,328 @@
+/**
+ * Conda support: `environment.yml` and `conda-lock.yml`.
+ *
+ * An environment file declares match specs such as `numpy=1.26.4`,
+ * `conda-forge::pandas` or `scipy==1.11.4=py311h64a7726_0`, and an embedded
+ * `pip:` section of PEP 508 requirements. Every entry is a direct dependency;
+ * only pinned versions are kept, `numpy>=1.26` is reported without one. An
+ * explicit channel and build become `channel` and `build` qualifiers.
+ *
+ * A conda-lock file pins the conda and pip packages of every platform. The
+ * channel, subdir and build of conda packages come from their download URL,
+ * so the purl differs per platform unless the package is `noarch`. Packages
+ * in the `dev` category are dev dependencies. Direct dependencies are those
+ * of the environment files the lock was made from, when they can be read.
+ */
+
+import path from 'node:path'
+
+import { parse as yamlParse } from 'yaml'
+
+import { parsePythonRequirement } from './python.mts'
+import { getLockfileDependencyPurl } from './sbom.mts'
+import { ENVIRONMENT_YAML, ENVIRONMENT_YML } from '../../constants/paths.mts'
+import { normalizePypiName } from '../python/pip-report.mts'
+
+import type {
+  LockfileDependency,
+  LockfileHash,
+  LockfileParseContext,
+  LockfileParser,
+} from './types.mts'
+
+export const CONDA_LOCK_YAML = 'conda-lock.yaml'
+
+export const CONDA_LOCK_YML = 'conda-lock.yml'
+
+// Downloads end in `<name>-<version>-<build>.conda` or `.tar.bz2`.
+const CONDA_PACKAGE_URL_REGEXP =
+  /^https?:\/\/[^/]+\/(.+)\/([^/]+)\/[^/]+-[^-/]+-([^-/]+)\.(?:conda|tar\.bz2)$/
+
+const CONDA_HASH_ALGS = {
+  __proto__: null,
+  md5: 'MD5',
+  sha256: 'SHA-256',
+} as unknown as Record<string, string | undefined>
+
+export type CondaMatchSpec = {
+  build?: string | undefined
+  channel?: string | undefined
+  name: string
+  // Set for `name=1.2`, `name==1.2`, `name=1.2=build` and `name 1.2 build`.
+  version?: string | undefined
+}
+
+/**
+ * The name, pinned version, build and channel of a conda match spec.
+ * Virtual packages (`__glibc`) are not installable and read as undefined.
+ */
+export function parseCondaMatchSpec(spec: string): CondaMatchSpec | undefined {
+  let rest = spec.replace(/\s+#.*$/, '').trim()
+  let channel: string | undefined
+  const channelIndex = rest.indexOf('::')
+  if (channelIndex !== -1) {
+    // `conda-forge::numpy` or `conda-forge/linux-64::numpy`.
+    channel = rest.slice(0, channelIndex).split('/')[0]
+    rest = rest.slice(channelIndex + 2)
+  }
+  const match = /^([A-Za-z0-9_][A-Za-z0-9_.-]*)(?:\[[^\]]*\])?\s*(.*)$/.exec(
+    rest,
+  )
+  if (!match || match[1]!.startsWith('__')) {
+    return undefined
+  }
+  const pin =
+    /^(?:==?\s*([^\s=,|<>!*]+)(?:=(\S+))?|([^\s=,|<>!*]+)(?:\s+(\S+))?)$/.exec(
+      match[2]!,
+    )
+  const version = pin?.[1] ?? pin?.[3]
+  const build = pin?.[2] ?? pin?.[4]
+  return {
+    ...(build ? { build } : {}),
+    ...(channel ? { channel } : {}),
+    name: match[1]!.toLowerCase(),
+    ...(version ? { version } : {}),
+  }
+}
+
+type CondaEnvironment = {
+  conda: CondaMatchSpec[]
+  pip: string[]
+}
+
+export function parseCondaEnvironment(content: string): CondaEnvironment {
+  const env: CondaEnvironment = { conda: [], pip: [] }
+  const doc = yamlParse(content) as { dependencies?: unknown } | null
+  const entries = Array.isArray(doc?.dependencies) ? doc.dependencies : []
+  for (const entry of entries) {
+    if (typeof entry === 'string') {
+      const spec = parseCondaMatchSpec(entry)
+      if (spec) {
+        env.conda.push(spec)
+      }
+    } else if (entry && Array.isArray((entry as { pip?: unknown }).pip)) {
+      for (const requirement of (entry as { pip: unknown[] }).pip) {
+        // Option lines such as `-r` or `--index-url` are not requirements.
+        if (typeof requirement === 'string' && !requirement.startsWith('-')) {
+          env.pip.push(requirement)
+        }
+      }
+    }
+  }
+  return env
+}
+
+export function parseCondaEnvironmentDependencies(
+  content: string | Buffer,
+): LockfileDependency[] {
+  const env = parseCondaEnvironment(content.toString())
+  const dependencies = new Map<string, LockfileDependency>()
+  for (let i = 0, { length } = env.conda; i < length; i += 1) {
+    const { build, channel, name, version = '' } = env.conda[i]!
+    const qualifiers = {
+      ...(build ? { build } : {}),
+      ...(channel ? { channel } : {}),
+    }
+    dependencies.set(`conda:${name}`, {
+      id: `conda:${name}`,
+      type: 'conda',
+      name,
+      version,
+      ...(Object.keys(qualifiers).length ? { qualifiers } : {}),
+      direct: true,
+    })
+  }
+  for (let i = 0, { length } = env.pip; i < length; i += 1) {
+    const requirement = parsePythonRequirement(env.pip[i]!)
+    if (!requirement) {
+      continue
+    }
+    const { marker, name, pinned = '' } = requirement
+    dependencies.set(`pypi:${name}`, {
+      id: `pypi:${name}`,
+      type: 'pypi',
+      name,
+      version: pinned,
+      direct: true,
+      ...(marker ? { markers: [marker] } : {}),
+    })
+  }
+  return [...dependencies.values()]
+}
+
+export type CondaLockPackage = {
+  category?: string | undefined
+  dependencies?: Record<string, string> | undefined
+  hash?: Record<string, string> | undefined
+  manager?: string | undefined
+  name: string
+  platform?: string | undefined
+  url?: string | undefined
+  version: number | string
+}
+
+/**
+ * Channel, subdir and build of a conda package download URL.
+ */
+export function getCondaUrlQualifiers(
+  url: string | undefined,
+): Record<string, string> | undefined {
+  const match = url ? CONDA_PACKAGE_URL_REGEXP.exec(url) : null
+  if (!match) {
+    return undefined
+  }
+  return { build: match[3]!, channel: match[1]!, subdir: match[2]! }
+}
+
+function getCondaLockHashes(
+  hash: CondaLockPackage['hash'],
+): LockfileHash[] | undefined {
+  const hashes: LockfileHash[] = []
+  for (const { 0: key, 1: content } of Object.entries(hash ?? {})) {
+    const alg = CONDA_HASH_ALGS[key]
+    if (alg && typeof content === 'string') {
+      hashes.push({ alg, content })
+    }
+  }
+  return hashes.length ? hashes : undefined
+}
+
+/**
+ * Names declared by the environment files the lock was made from, or
+ * undefined when none of them can be read.
+ */
+function readCondaLockSources(
+  sources: unknown,
+  context: LockfileParseContext,
+): { conda: Set<string>; pip: Set<string> } | undefined {
+  let direct: { conda: Set<string>; pip: Set<string> } | undefined
+  const dir = path.dirname(context.filepath)
+  for (const source of Array.isArray(sources) ? sources : []) {
+    const content =
+      typeof source === 'string'
+        ? context.readFile(path.join(dir, source))
+        : undefined
+    if (content === undefined) {
+      continue
+    }
+    direct ??= { conda: new Set(), pip: new Set() }
+    const env = parseCondaEnvironment(content.toString())
+    for (const spec of env.conda) {
+      direct.conda.add(spec.name)
+    }
+    for (const requirement of env.pip) {
+      const parsed = parsePythonRequirement(requirement)
+      if (parsed) {
+        direct.pip.add(parsed.name)
+      }
+    }
+  }
+  return direct
+}
+
+export function parseCondaLockDependencies(
+  content: string | Buffer,
+  context: LockfileParseContext,
+): LockfileDependency[] {
+  const doc = yamlParse(content.toString()) as {
+    metadata?: { sources?: unknown } | undefined
+    package?: unknown
+  } | null
+  const packages = (Array.isArray(doc?.package) ? doc.package : []).filter(
+    (pkg): pkg is CondaLockPackage =>
+      !!pkg &&
+      typeof pkg.name === 'string' &&
+      (typeof pkg.version === 'string' || typeof pkg.version === 'number'),
+  )
+  const direct = readCondaLockSources(doc?.metadata?.sources, context)
+
+  // Packages are listed once per platform; identical purls are merged.
+  const dependencies = new Map<string, LockfileDependency>()
+  const idByPlatformName = new Map<string, string>()
+  const entries: Array<{
+    id: string
+    manager: string
+    pkg: CondaLockPackage
+  }> = []
+  for (let i = 0, { length } = packages; i < length; i += 1) {
+    const pkg = packages[i]!
+    const manager = pkg.manager === 'pip' ? 'pip' : 'conda'
+    const isPip = manager === 'pip'
+    const name = isPip ? normalizePypiName(pkg.name) : pkg.name
+    const qualifiers = isPip ? undefined : getCondaUrlQualifiers(pkg.url)
+    const dependency: LockfileDependency = {
+      id: '',
+      type: isPip ? 'pypi' : 'conda',
+      name,
+      version: String(pkg.version),
+      ...(qualifiers ? { qualifiers } : {}),
+    }
+    const id = getLockfileDependencyPurl(dependency)
+    const category = pkg.category ?? 'main'
+    const existing = dependencies.get(id)
+    if (existing) {
+      if (!existing.scopes!.includes(category)) {
+        existing.scopes!.push(category)
+      }
+    } else {
+      const hashes = getCondaLockHashes(pkg.hash)
+      const isDirect = direct
+        ? (isPip ? direct.pip : direct.conda).has(name)
+        : undefined
+      dependencies.set(id, {
+        ...dependency,
+        id,
+        ...(isDirect === undefined ? {} : { direct: isDirect }),
+        scopes: [category],
+        ...(hashes ? { hashes } : {}),
+      })
+    }
+    idByPlatformName.set(`${pkg.platform} ${manager} ${name}`, id)
+    entries.push({ id, manager, pkg })
+  }
+
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const { id, manager, pkg } = entries[i]!
+    const dependency = dependencies.get(id)!
+    for (const depName of Object.keys(pkg.dependencies ?? {})) {
+      // pip packages may depend on packages conda installed.
+      const childId =
+        manager === 'pip'
+          ? (idByPlatformName.get(
+              `${pkg.platform} pip ${normalizePypiName(depName)}`,
+            ) ?? idByPlatformName.get(`${pkg.platform} conda ${depName}`))
+          : idByPlatformName.get(`${pkg.platform} conda ${depName}`)
+      if (childId && childId !== id) {
+        dependency.dependencies ??= []
+        if (!dependency.dependencies.includes(childId)) {
+          dependency.dependencies.push(childId)
+        }
+      }
+    }
+  }
+
+  const results = [...dependencies.values()]
+  for (let i = 0, { length } = results; i < length; i += 1) {
+    const dependency = results[i]!
+    dependency.dev = dependency.scopes!.every(scope => scope === 'dev')
+  }
+  return results
+}
+
+export const condaEnvironmentParser: LockfileParser = {
+  ecosystem: 'conda',
+  filenames: [ENVIRONMENT_YML, ENVIRONMENT_YAML],
+  parse: content => ({
+    ecosystem: 'conda',
+    dependencies: parseCondaEnvironmentDependencies(content),
+  }),
+}
+
+export const condaLockParser: LockfileParser = {
+  ecosystem: 'conda',
+  filenames: [CONDA_LOCK_YML, CONDA_LOCK_YAML],
+  parse: (content, context) => ({
+    ecosystem: 'conda',
+    dependencies: parseCondaLockDependencies(content, context),
+  }),
+}
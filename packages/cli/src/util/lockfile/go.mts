@@ -0,0 +1,329 @@
+/**
+ * Go modules support: `go.mod` + `go.sum`.
+ *
+ * The `require` list of `go.mod` is the resolved module set (Go 1.17+ lists
+ * every module in the build list, marking transitive ones `// indirect`).
+ * `replace` directives are applied the way `go build` would: a module
+ * replacement swaps the module path and/or version, and a filesystem
+ * replacement pulls in the requirements of the local module's own `go.mod`.
+ * Excluded versions are dropped. Hashes come from the sibling `go.sum`, whose
+ * extra entries also fill in modules missing from older `go.mod` files.
+ */
+
+import path from 'node:path'
+
+import { compareVersions } from '../semver.mts'
+
+import type {
+  LockfileDependency,
+  LockfileHash,
+  LockfileParseContext,
+  LockfileParser,
+} from './types.mts'
+
+export const GO_MOD = 'go.mod'
+
+export const GO_SUM = 'go.sum'
+
+export type GoModRequire = {
+  path: string
+  version: string
+  indirect: boolean
+}
+
+export type GoModReplace = {
+  oldPath: string
+  oldVersion?: string | undefined
+  newPath: string
+  newVersion?: string | undefined
+}
+
+export type GoMod = {
+  module: string
+  go?: string | undefined
+  requires: GoModRequire[]
+  replaces: GoModReplace[]
+  excludes: Array<{ path: string; version: string }>
+}
+
+function unquote(token: string): string {
+  return token.startsWith('"') || token.startsWith('`')
+    ? token.slice(1, -1)
+    : token
+}
+
+function tokenize(line: string): string[] {
+  return (line.match(/"(?:[^"\\]|\\.)*"|`[^`]*`|\S+/g) ?? []).map(unquote)
+}
+
+function parseReplace(tokens: string[]): GoModReplace | undefined {
+  const arrow = tokens.indexOf('=>')
+  if (arrow < 1 || arrow === tokens.length - 1) {
+    return undefined
+  }
+  const [oldPath, oldVersion] = tokens.slice(0, arrow)
+  const [newPath, newVersion] = tokens.slice(arrow + 1)
+  return {
+    oldPath: oldPath!,
+    ...(oldVersion ? { oldVersion } : {}),
+    newPath: newPath!,
+    ...(newVersion ? { newVersion } : {}),
+  }
+}
+
+export function parseGoMod(content: string): GoMod {
+  const mod: GoMod = { module: '', requires: [], replaces: [], excludes: [] }
+  let block: string | undefined
+  const lines = content.split(/\r?\n/)
+  for (let i = 0, { length } = lines; i < length; i += 1) {
+    const raw = lines[i]!
+    const commentIndex = raw.indexOf('//')
+    const comment = commentIndex === -1 ? '' : raw.slice(commentIndex + 2)
+    const line = (commentIndex === -1 ? raw : raw.slice(0, commentIndex)).trim()
+    if (!line) {
+      continue
+    }
+    if (block && line === ')') {
+      block = undefined
+      continue
+    }
+    let tokens = tokenize(line)
+    let verb = block
+    if (!verb) {
+      verb = tokens[0]
+      tokens = tokens.slice(1)
+      if (tokens[0] === '(') {
+        block = verb
+        continue
+      }
+    }
+    switch (verb) {
+      case 'module':
+        mod.module = tokens[0] ?? ''
+        break
+      case 'go':
+        mod.go = tokens[0]
+        break
+      case 'require':
+        if (tokens.length >= 2) {
+          mod.requires.push({
+            path: tokens[0]!,
+            version: tokens[1]!,
+            indirect: /\bindirect\b/.test(comment),
+          })
+        }
+        break
+      case 'replace': {
+        const replace = parseReplace(tokens)
+        if (replace) {
+          mod.replaces.push(replace)
+        }
+        break
+      }
+      case 'exclude':
+        if (tokens.length >= 2) {
+          mod.excludes.push({ path: tokens[0]!, version: tokens[1]! })
+        }
+        break
+      default:
+        break
+    }
+  }
+  return mod
+}
+
+/**
+ * Map of `<module> <version>` to the `h1:` hash of the module zip. Lines for
+ * `/go.mod` files are skipped since they only hash the manifest.
+ */
+export function parseGoSum(content: string): Map<string, string> {
+  const sums = new Map<string, string>()
+  const lines = content.split(/\r?\n/)
+  for (let i = 0, { length } = lines; i < length; i += 1) {
+    const [modPath, version, hash] = lines[i]!.trim().split(/\s+/)
+    if (!modPath || !version || !hash || version.endsWith('/go.mod')) {
+      continue
+    }
+    sums.set(`${modPath} ${version}`, hash)
+  }
+  return sums
+}
+
+/**
+ * Convert a go.sum `h1:` hash (base64 SHA-256) into a CycloneDX hash.
+ */
+export function goSumHashToLockfileHash(
+  hash: string,
+): LockfileHash | undefined {
+  if (!hash.startsWith('h1:')) {
+    return undefined
+  }
+  const content = Buffer.from(hash.slice(3), 'base64').toString('hex')
+  return content.length === 64 ? { alg: 'SHA-256', content } : undefined
+}
+
+export function isGoLocalPath(modPath: string): boolean {
+  return (
+    modPath.startsWith('./') ||
+    modPath.startsWith('../') ||
+    path.isAbsolute(modPath) ||
+    /^[A-Za-z]:[\\/]/.test(modPath)
+  )
+}
+
+function findReplace(
+  replaces: GoModReplace[],
+  modPath: string,
+  version: string,
+): GoModReplace | undefined {
+  // A versioned replacement takes precedence over a wildcard one.
+  return (
+    replaces.find(r => r.oldPath === modPath && r.oldVersion === version) ??
+    replaces.find(r => r.oldPath === modPath && !r.oldVersion)
+  )
+}
+
+function splitModulePath(modPath: string): {
+  namespace?: string | undefined
+  name: string
+} {
+  const index = modPath.lastIndexOf('/')
+  return index === -1
+    ? { name: modPath }
+    : { namespace: modPath.slice(0, index), name: modPath.slice(index + 1) }
+}
+
+function readText(
+  context: LockfileParseContext,
+  filepath: string,
+): string | undefined {
+  const content = context.readFile(filepath)
+  return content === undefined ? undefined : content.toString()
+}
+
+export function parseGoModules(
+  content: string | Buffer,
+  context: LockfileParseContext,
+): {
+  dependencies: LockfileDependency[]
+  requiresLocalResolution: boolean
+} {
+  const byId = new Map<string, LockfileDependency>()
+  let requiresLocalResolution = false
+  const visitedLocal = new Set<string>()
+
+  function addModules(
+    mod: GoMod,
+    dir: string,
+    sums: Map<string, string>,
+    workspace: string | undefined,
+  ): void {
+    const excluded = new Set(mod.excludes.map(e => `${e.path} ${e.version}`))
+    for (let i = 0, { length } = mod.requires; i < length; i += 1) {
+      const req = mod.requires[i]!
+      if (excluded.has(`${req.path} ${req.version}`)) {
+        continue
+      }
+      const replace = findReplace(mod.replaces, req.path, req.version)
+      if (replace && isGoLocalPath(replace.newPath)) {
+        requiresLocalResolution = true
+        const localDir = path.resolve(dir, replace.newPath)
+        if (visitedLocal.has(localDir)) {
+          continue
+        }
+        visitedLocal.add(localDir)
+        const localMod = readText(context, path.join(localDir, GO_MOD))
+        if (localMod !== undefined) {
+          const localSum = readText(context, path.join(localDir, GO_SUM))
+          addModules(
+            parseGoMod(localMod),
+            localDir,
+            localSum === undefined ? sums : parseGoSum(localSum),
+            req.path,
+          )
+        }
+        continue
+      }
+      const modPath = replace?.newPath ?? req.path
+      const version = replace?.newVersion ?? req.version
+      const id = `${modPath}@${version}`
+      const existing = byId.get(id)
+      if (existing) {
+        if (!req.indirect && !workspace) {
+          existing.direct = true
+        }
+        if (workspace && !existing.workspaces?.includes(workspace)) {
+          existing.workspaces = [...(existing.workspaces ?? []), workspace]
+        }
+        continue
+      }
+      const hash = sums.get(`${modPath} ${version}`)
+      const lockHash = hash ? goSumHashToLockfileHash(hash) : undefined
+      byId.set(id, {
+        id,
+        type: 'golang',
+        ...splitModulePath(modPath),
+        version,
+        direct: !req.indirect && !workspace,
+        ...(workspace ? { workspaces: [workspace] } : {}),
+        ...(lockHash ? { hashes: [lockHash] } : {}),
+      })
+    }
+  }
+
+  const dir = path.dirname(context.filepath)
+  const sumContent = readText(context, path.join(dir, GO_SUM))
+  const sums = sumContent === undefined ? new Map() : parseGoSum(sumContent)
+  const mod = parseGoMod(content.toString())
+  addModules(mod, dir, sums, undefined)
+
+  // Older go.mod files only list direct requirements; the remaining build list
+  // is recoverable from go.sum. Pick the highest recorded version per module,
+  // matching Go's minimal version selection for a consistent go.sum.
+  const known = new Set<string>()
+  for (const dep of byId.values()) {
+    known.add(dep.namespace ? `${dep.namespace}/${dep.name}` : dep.name)
+  }
+  known.add(mod.module)
+  const extra = new Map<string, string>()
+  for (const key of sums.keys()) {
+    const [modPath, version] = key.split(' ') as [string, string]
+    if (known.has(modPath) || isGoLocalPath(modPath)) {
+      continue
+    }
+    const current = extra.get(modPath)
+    if (!current || compareVersions(version, current) > 0) {
+      extra.set(modPath, version)
+    }
+  }
+  for (const [modPath, version] of extra) {
+    const id = `${modPath}@${version}`
+    const lockHash = goSumHashToLockfileHash(sums.get(`${modPath} ${version}`)!)
+    byId.set(id, {
+      id,
+      type: 'golang',
+      ...splitModulePath(modPath),
+      version,
+      direct: false,
+      ...(lockHash ? { hashes: [lockHash] } : {}),
+    })
+  }
+
+  return { dependencies: [...byId.values()], requiresLocalResolution }
+}
+
+export const goLockfileParser: LockfileParser = {
+  ecosystem: 'golang',
+  filenames: [GO_MOD],
+  parse: (content, context) => {
+    const { dependencies, requiresLocalResolution } = parseGoModules(
+      content,
+      context,
+    )
+    return {
+      ecosystem: 'golang',
+      dependencies,
+      ...(requiresLocalResolution ? { requiresLocalResolution } : {}),
+    }
+  },
+}
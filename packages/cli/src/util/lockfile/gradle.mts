@@ -0,0 +1,189 @@
+/**
+ * Gradle support: `gradle.lockfile` with `gradle/verification-metadata.xml`.
+ *
+ * Dependency locking writes one `group:name:version=<configurations>` line per
+ * resolved module, so every entry keeps the configurations that resolve it as
+ * its scopes. Modules only resolved by test, annotation processor or build
+ * script configurations are marked as dev dependencies. Hashes come from the
+ * dependency verification metadata of the build, when it has one. The
+ * metadata also covers plugins and tooling outside of any lockfile, so it is
+ * only used to look up hashes and never adds packages itself.
+ */
+
+import path from 'node:path'
+
+import { findXmlElements, parseXml } from './xml.mts'
+
+import type {
+  LockfileDependency,
+  LockfileHash,
+  LockfileParseContext,
+  LockfileParser,
+} from './types.mts'
+
+export const GRADLE_LOCKFILE = 'gradle.lockfile'
+
+export const GRADLE_BUILDSCRIPT_LOCKFILE = 'buildscript-gradle.lockfile'
+
+export const GRADLE_VERIFICATION_METADATA = 'verification-metadata.xml'
+
+const VERIFICATION_HASH_ALGS = {
+  __proto__: null,
+  md5: 'MD5',
+  sha1: 'SHA-1',
+  sha256: 'SHA-256',
+  sha512: 'SHA-512',
+} as unknown as Record<string, string | undefined>
+
+export type GradleLockEntry = {
+  group: string
+  name: string
+  version: string
+  configurations: string[]
+}
+
+export function parseGradleLockfile(content: string): GradleLockEntry[] {
+  const entries: GradleLockEntry[] = []
+  const lines = content.split(/\r?\n/)
+  for (let i = 0, { length } = lines; i < length; i += 1) {
+    const line = lines[i]!.trim()
+    if (!line || line.startsWith('#')) {
+      continue
+    }
+    const eqIndex = line.indexOf('=')
+    const coordinate = eqIndex === -1 ? line : line.slice(0, eqIndex)
+    // `empty=<configurations>` lists configurations without dependencies.
+    if (coordinate === 'empty') {
+      continue
+    }
+    const [group, name, version] = coordinate.split(':')
+    if (!group || !name || !version) {
+      continue
+    }
+    entries.push({
+      group,
+      name,
+      version,
+      configurations:
+        eqIndex === -1
+          ? []
+          : line
+              .slice(eqIndex + 1)
+              .split(',')
+              .map(c => c.trim())
+              .filter(Boolean),
+    })
+  }
+  return entries
+}
+
+/**
+ * Whether a configuration only serves tests or build tooling, e.g.
+ * `testRuntimeClasspath`, `debugUnitTestCompileClasspath` or `kaptDebug`.
+ */
+export function isGradleDevConfiguration(configuration: string): boolean {
+  return (
+    /(?:^t|T)est(?:[A-Z]|$)/.test(configuration) ||
+    /(?:^a|A)nnotationProcessor/.test(configuration) ||
+    /^(?:kapt|ksp)(?:[A-Z]|$)/.test(configuration)
+  )
+}
+
+/**
+ * Map of `group:name:version` to the hashes of the component's main artifact.
+ */
+export function parseGradleVerificationMetadata(
+  content: string,
+): Map<string, LockfileHash[]> {
+  const hashesByCoordinate = new Map<string, LockfileHash[]>()
+  const components = findXmlElements(parseXml(content), 'component')
+  for (let i = 0, { length } = components; i < length; i += 1) {
+    const { attrs, children } = components[i]!
+    const { group, name, version } = attrs
+    if (!group || !name || !version) {
+      continue
+    }
+    const artifacts = children.filter(c => c.name === 'artifact')
+    // Prefer the binary over the pom/module metadata files.
+    const artifact =
+      artifacts.find(a => /\.(?:aar|jar)$/.test(a.attrs['name'] ?? '')) ??
+      artifacts[0]
+    if (!artifact) {
+      continue
+    }
+    const hashes: LockfileHash[] = []
+    for (const child of artifact.children) {
+      const alg = VERIFICATION_HASH_ALGS[child.name]
+      const value = child.attrs['value']
+      if (alg && value) {
+        hashes.push({ alg, content: value.toLowerCase() })
+      }
+    }
+    if (hashes.length) {
+      hashesByCoordinate.set(`${group}:${name}:${version}`, hashes)
+    }
+  }
+  return hashesByCoordinate
+}
+
+/**
+ * Lockfiles of subprojects share the verification metadata of the root build,
+ * so look for `gradle/verification-metadata.xml` in every parent directory.
+ */
+function readVerificationMetadata(
+  context: LockfileParseContext,
+): Map<string, LockfileHash[]> {
+  let dir = path.dirname(context.filepath)
+  for (;;) {
+    const content = context.readFile(
+      path.join(dir, 'gradle', GRADLE_VERIFICATION_METADATA),
+    )
+    if (content !== undefined) {
+      return parseGradleVerificationMetadata(content.toString())
+    }
+    const parent = path.dirname(dir)
+    if (parent === dir) {
+      return new Map()
+    }
+    dir = parent
+  }
+}
+
+export function parseGradleDependencies(
+  content: string | Buffer,
+  context: LockfileParseContext,
+): LockfileDependency[] {
+  const isBuildscript =
+    path.basename(context.filepath) === GRADLE_BUILDSCRIPT_LOCKFILE
+  const hashesByCoordinate = readVerificationMetadata(context)
+  const entries = parseGradleLockfile(content.toString())
+  const dependencies: LockfileDependency[] = []
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const { configurations, group, name, version } = entries[i]!
+    const id = `${group}:${name}:${version}`
+    const hashes = hashesByCoordinate.get(id)
+    dependencies.push({
+      id,
+      type: 'maven',
+      namespace: group,
+      name,
+      version,
+      dev:
+        isBuildscript ||
+        (configurations.length > 0 &&
+          configurations.every(isGradleDevConfiguration)),
+      ...(configurations.length ? { scopes: configurations } : {}),
+      ...(hashes ? { hashes } : {}),
+    })
+  }
+  return dependencies
+}
+
+export const gradleLockfileParser: LockfileParser = {
+  ecosystem: 'maven',
+  filenames: [GRADLE_LOCKFILE, GRADLE_BUILDSCRIPT_LOCKFILE],
+  parse: (content, context) => ({
+    ecosystem: 'maven',
+    dependencies: parseGradleDependencies(content, context),
+  }),
+}
@@ -0,0 +1,73 @@
+/**
+ * Helpers shared by the npm-ecosystem lockfile parsers (pnpm, bun).
+ */
+
+import type { LockfileHash } from './types.mts'
+
+const INTEGRITY_HASH_ALGS = {
+  __proto__: null,
+  sha1: 'SHA-1',
+  sha256: 'SHA-256',
+  sha384: 'SHA-384',
+  sha512: 'SHA-512',
+} as unknown as Record<string, string | undefined>
+
+/**
+ * Convert a Subresource Integrity string (`sha512-<base64>`, possibly several
+ * separated by whitespace) into CycloneDX hashes.
+ */
+export function integrityToLockfileHashes(
+  integrity: string | undefined,
+): LockfileHash[] {
+  const hashes: LockfileHash[] = []
+  for (const part of integrity?.trim().split(/\s+/) ?? []) {
+    const dashIndex = part.indexOf('-')
+    const alg = INTEGRITY_HASH_ALGS[part.slice(0, dashIndex)]
+    if (dashIndex !== -1 && alg) {
+      hashes.push({
+        alg,
+        content: Buffer.from(part.slice(dashIndex + 1), 'base64').toString(
+          'hex',
+        ),
+      })
+    }
+  }
+  return hashes
+}
+
+export function splitNpmPackageName(packageName: string): {
+  namespace?: string | undefined
+  name: string
+} {
+  const slashIndex = packageName.startsWith('@')
+    ? packageName.indexOf('/')
+    : -1
+  return slashIndex === -1
+    ? { name: packageName }
+    : {
+        namespace: packageName.slice(0, slashIndex),
+        name: packageName.slice(slashIndex + 1),
+      }
+}
+
+/**
+ * Split a `name@version` key, where the name may be scoped, e.g.
+ * `@babel/core@7.23.0`.
+ */
+export function splitNpmPackageKey(
+  key: string,
+): { packageName: string; version: string } | undefined {
+  const atIndex = key.indexOf('@', 1)
+  if (atIndex === -1 || atIndex === key.length - 1) {
+    return undefined
+  }
+  return { packageName: key.slice(0, atIndex), version: key.slice(atIndex + 1) }
+}
+
+/**
+ * Whether a resolved version is a registry version, as opposed to a link,
+ * local file, tarball URL or git reference.
+ */
+export function isNpmRegistryVersion(version: string): boolean {
+  return /^\d+\.\d+\.\d+(?:[-+][0-9A-Za-z.+-]*)?$/.test(version)
+}
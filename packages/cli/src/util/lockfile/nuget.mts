@@ -0,0 +1,302 @@
+/**
+ * .NET support: NuGet `packages.lock.json` and SDK-style project files.
+ *
+ * A lock file lists the resolved packages per target framework, which become
+ * the scopes of each package. `Direct` entries are the project's own
+ * references; `Transitive` and `CentralTransitive` (pinned through central
+ * package management) entries are not direct. Project references are local
+ * and left out. The base64 `contentHash` is the SHA-512 of the package.
+ *
+ * Without a lock file the `PackageReference` items of the project are used
+ * instead, with versions from the nearest `Directory.Packages.props` when
+ * central package management is on. Those only cover direct dependencies, and
+ * references marked `PrivateAssets="all"` (analyzers, build tooling) are dev
+ * dependencies.
+ */
+
+import path from 'node:path'
+
+import { findXmlElements, parseXml } from './xml.mts'
+
+import type {
+  LockfileDependency,
+  LockfileHash,
+  LockfileParseContext,
+  LockfileParser,
+} from './types.mts'
+import type { XmlElement } from './xml.mts'
+
+export const NUGET_LOCKFILE = 'packages.lock.json'
+
+export const DIRECTORY_PACKAGES_PROPS = 'Directory.Packages.props'
+
+export const NUGET_PROJECT_EXTENSIONS = ['.csproj', '.fsproj', '.vbproj']
+
+export type NugetLockEntry = {
+  type?: string | undefined
+  requested?: string | undefined
+  resolved?: string | undefined
+  contentHash?: string | undefined
+  dependencies?: Record<string, string> | undefined
+}
+
+export type NugetLock = {
+  version?: number | undefined
+  dependencies?: Record<string, Record<string, NugetLockEntry>> | undefined
+}
+
+/**
+ * Convert a lock file `contentHash` (base64 SHA-512) into a CycloneDX hash.
+ */
+export function nugetContentHashToLockfileHash(
+  hash: string,
+): LockfileHash | undefined {
+  const content = Buffer.from(hash, 'base64').toString('hex')
+  return content.length === 128 ? { alg: 'SHA-512', content } : undefined
+}
+
+export function parseNugetLockfile(
+  content: string | Buffer,
+): LockfileDependency[] | undefined {
+  let lock: NugetLock
+  try {
+    lock = JSON.parse(content.toString()) as NugetLock
+  } catch {
+    return undefined
+  }
+  const byId = new Map<string, LockfileDependency>()
+  for (const [framework, entries] of Object.entries(lock.dependencies ?? {})) {
+    // Package ids are case-insensitive, so ids use the lowercase name.
+    const resolvedByName = new Map<string, string>()
+    for (const [name, entry] of Object.entries(entries)) {
+      if (entry.type !== 'Project' && entry.resolved) {
+        const lowerName = name.toLowerCase()
+        resolvedByName.set(lowerName, `${lowerName}@${entry.resolved}`)
+      }
+    }
+    for (const [name, entry] of Object.entries(entries)) {
+      const id = resolvedByName.get(name.toLowerCase())
+      if (entry.type === 'Project' || !id) {
+        continue
+      }
+      const childIds: string[] = []
+      for (const depName of Object.keys(entry.dependencies ?? {})) {
+        const childId = resolvedByName.get(depName.toLowerCase())
+        if (childId) {
+          childIds.push(childId)
+        }
+      }
+      const existing = byId.get(id)
+      if (existing) {
+        existing.scopes = [...(existing.scopes ?? []), framework]
+        existing.direct ||= entry.type === 'Direct'
+        for (const childId of childIds) {
+          if (!existing.dependencies?.includes(childId)) {
+            existing.dependencies = [...(existing.dependencies ?? []), childId]
+          }
+        }
+        continue
+      }
+      const hash = entry.contentHash
+        ? nugetContentHashToLockfileHash(entry.contentHash)
+        : undefined
+      byId.set(id, {
+        id,
+        type: 'nuget',
+        name,
+        version: entry.resolved!,
+        direct: entry.type === 'Direct',
+        scopes: [framework],
+        ...(hash ? { hashes: [hash] } : {}),
+        ...(childIds.length ? { dependencies: childIds } : {}),
+      })
+    }
+  }
+  return [...byId.values()]
+}
+
+/**
+ * The version NuGet resolves for a reference: exact versions and minimum
+ * version ranges resolve to their lower bound. Floating versions and ranges
+ * without an inclusive lower bound need a restore and yield undefined.
+ */
+export function getNugetReferenceVersion(
+  spec: string | undefined,
+): string | undefined {
+  const value = spec?.trim()
+  if (!value || value.includes('*')) {
+    return undefined
+  }
+  const range = /^\[\s*([^,\s\]]+)\s*(?:\]|,.*[\])])$/.exec(value)
+  if (range) {
+    return range[1]
+  }
+  return /^[0-9][0-9A-Za-z.+-]*$/.test(value) ? value : undefined
+}
+
+export type MsBuildProject = {
+  properties: Map<string, string>
+  references: XmlElement[]
+}
+
+function readMsBuildProject(content: string): MsBuildProject {
+  const doc = parseXml(content)
+  const properties = new Map<string, string>()
+  const groups = findXmlElements(doc, 'PropertyGroup')
+  for (let i = 0, { length } = groups; i < length; i += 1) {
+    for (const prop of groups[i]!.children) {
+      properties.set(prop.name.toLowerCase(), prop.text.trim())
+    }
+  }
+  return {
+    properties,
+    references: [
+      ...findXmlElements(doc, 'PackageReference'),
+      ...findXmlElements(doc, 'PackageVersion'),
+      ...findXmlElements(doc, 'GlobalPackageReference'),
+    ],
+  }
+}
+
+// Attributes win over the equivalent child element, as in MSBuild.
+function getItemMetadata(item: XmlElement, name: string): string | undefined {
+  return (
+    item.attrs[name] ?? item.children.find(c => c.name === name)?.text.trim()
+  )
+}
+
+function expandProperties(
+  value: string | undefined,
+  properties: Map<string, string>,
+): string | undefined {
+  return value?.replace(
+    /\$\(([\w.-]+)\)/g,
+    (match, name: string) => properties.get(name.toLowerCase()) ?? match,
+  )
+}
+
+/**
+ * Look for the `Directory.Packages.props` that applies to a project, i.e. the
+ * nearest one in the project directory or one of its parents.
+ */
+function readDirectoryPackagesProps(
+  context: LockfileParseContext,
+): MsBuildProject | undefined {
+  let dir = path.dirname(context.filepath)
+  for (;;) {
+    const content = context.readFile(path.join(dir, DIRECTORY_PACKAGES_PROPS))
+    if (content !== undefined) {
+      return readMsBuildProject(content.toString())
+    }
+    const parent = path.dirname(dir)
+    if (parent === dir) {
+      return undefined
+    }
+    dir = parent
+  }
+}
+
+export function parseNugetProject(
+  content: string | Buffer,
+  context: LockfileParseContext,
+): { dependencies: LockfileDependency[]; requiresLocalResolution: boolean } {
+  const project = readMsBuildProject(content.toString())
+  const props = readDirectoryPackagesProps(context)
+  const properties = new Map([
+    ...(props?.properties ?? []),
+    ...project.properties,
+  ])
+  const isCentral =
+    properties.get('managepackageversionscentrally')?.toLowerCase() === 'true'
+  const centralVersions = new Map<string, string>()
+  const globalReferences: XmlElement[] = []
+  for (const item of props?.references ?? []) {
+    const name = item.attrs['Include'] ?? item.attrs['Update']
+    if (item.name === 'PackageVersion' && name) {
+      const version = getItemMetadata(item, 'Version')
+      if (version) {
+        centralVersions.set(name.toLowerCase(), version)
+      }
+    } else if (item.name === 'GlobalPackageReference') {
+      globalReferences.push(item)
+    }
+  }
+
+  const workspace = path.basename(
+    context.filepath,
+    path.extname(context.filepath),
+  )
+  const byId = new Map<string, LockfileDependency>()
+  let requiresLocalResolution = false
+  const references = [
+    ...project.references.filter(r => r.name === 'PackageReference'),
+    ...(isCentral ? globalReferences : []),
+  ]
+  for (let i = 0, { length } = references; i < length; i += 1) {
+    const item = references[i]!
+    const name = item.attrs['Include']
+    if (!name) {
+      continue
+    }
+    let spec =
+      getItemMetadata(item, 'VersionOverride') ??
+      getItemMetadata(item, 'Version')
+    if (spec === undefined && isCentral) {
+      spec = centralVersions.get(name.toLowerCase())
+      // The Socket API only sees the project file, not the central versions.
+      requiresLocalResolution ||= spec !== undefined
+    }
+    const version = getNugetReferenceVersion(
+      expandProperties(spec, properties),
+    )
+    if (!version) {
+      continue
+    }
+    const privateAssets = getItemMetadata(item, 'PrivateAssets')
+    const id = `${name.toLowerCase()}@${version}`
+    byId.set(id, {
+      id,
+      type: 'nuget',
+      name,
+      version,
+      direct: true,
+      // Global references are development dependencies by definition.
+      dev:
+        item.name === 'GlobalPackageReference' ||
+        privateAssets?.toLowerCase() === 'all',
+      workspaces: [workspace],
+    })
+  }
+  return { dependencies: [...byId.values()], requiresLocalResolution }
+}
+
+export const nugetLockfileParser: LockfileParser = {
+  ecosystem: 'nuget',
+  filenames: [NUGET_LOCKFILE],
+  parse: content => {
+    const dependencies = parseNugetLockfile(content)
+    return dependencies ? { ecosystem: 'nuget', dependencies } : undefined
+  },
+}
+
+export const nugetProjectParser: LockfileParser = {
+  ecosystem: 'nuget',
+  filenames: [],
+  extensions: NUGET_PROJECT_EXTENSIONS,
+  parse: (content, context) => {
+    // A lock file next to the project already has the full resolved graph.
+    const lockfile = path.join(path.dirname(context.filepath), NUGET_LOCKFILE)
+    if (context.readFile(lockfile) !== undefined) {
+      return undefined
+    }
+    const { dependencies, requiresLocalResolution } = parseNugetProject(
+      content,
+      context,
+    )
+    return {
+      ecosystem: 'nuget',
+      dependencies,
+      ...(requiresLocalResolution ? { requiresLocalResolution } : {}),
+    }
+  },
+}
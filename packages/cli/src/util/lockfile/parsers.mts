@@ -0,0 +1,115 @@
+/**
+ * Registry of the local lockfile parsers.
+ *
+ * These cover manifests and lockfiles that the Socket API either does not
+ * ingest directly or cannot fully resolve without the rest of the checkout.
+ * `socket scan create` folds their results into a generated CycloneDX SBOM
+ * (see `./upload.mts`).
+ */
+
+import { readdirSync, readFileSync } from 'node:fs'
+import path from 'node:path'
+
+import { bunLockfileParser } from './bun.mts'
+import { cargoLockfileParser } from './cargo.mts'
+import { composerLockfileParser } from './composer.mts'
+import { condaEnvironmentParser, condaLockParser } from './conda.mts'
+import { goLockfileParser } from './go.mts'
+import { gradleLockfileParser } from './gradle.mts'
+import { nugetLockfileParser, nugetProjectParser } from './nuget.mts'
+import { pdmLockfileParser } from './pdm.mts'
+import { pnpmLockfileParser } from './pnpm.mts'
+import { uvLockfileParser } from './uv.mts'
+import { yarnLockfileParser } from './yarn.mts'
+
+import type { LockfileParser, ParsedLockfile } from './types.mts'
+
+export const LOCKFILE_PARSERS: readonly LockfileParser[] = [
+  bunLockfileParser,
+  cargoLockfileParser,
+  composerLockfileParser,
+  condaEnvironmentParser,
+  condaLockParser,
+  goLockfileParser,
+  gradleLockfileParser,
+  nugetLockfileParser,
+  nugetProjectParser,
+  pdmLockfileParser,
+  pnpmLockfileParser,
+  uvLockfileParser,
+  yarnLockfileParser,
+]
+
+const parserByFilename = new Map<string, LockfileParser>()
+const parserByExtension = new Map<string, LockfileParser>()
+for (let i = 0, { length } = LOCKFILE_PARSERS; i < length; i += 1) {
+  const parser = LOCKFILE_PARSERS[i]!
+  for (const filename of parser.filenames) {
+    parserByFilename.set(filename, parser)
+  }
+  for (const extension of parser.extensions ?? []) {
+    parserByExtension.set(extension, parser)
+  }
+}
+
+export function getLockfileParser(
+  filepath: string,
+): LockfileParser | undefined {
+  return (
+    parserByFilename.get(path.basename(filepath)) ??
+    parserByExtension.get(path.extname(filepath).toLowerCase())
+  )
+}
+
+export function isLocalLockfilePath(filepath: string): boolean {
+  return getLockfileParser(filepath) !== undefined
+}
+
+function readFileOrUndefined(filepath: string): Buffer | undefined {
+  try {
+    return readFileSync(filepath)
+  } catch {}
+  return undefined
+}
+
+function listSubdirectories(dir: string): string[] {
+  try {
+    return readdirSync(dir, { withFileTypes: true })
+      .filter(entry => entry.isDirectory())
+      .map(entry => entry.name)
+  } catch {}
+  return []
+}
+
+/**
+ * Parse a lockfile with its registered parser. Returns undefined when no
+ * parser handles the file or it cannot be read or parsed.
+ */
+export function parseLockfile(
+  filepath: string,
+  cwd = process.cwd(),
+): ParsedLockfile | undefined {
+  const parser = getLockfileParser(filepath)
+  if (!parser) {
+    return undefined
+  }
+  const absPath = path.resolve(cwd, filepath)
+  const content = readFileOrUndefined(absPath)
+  if (content === undefined) {
+    return undefined
+  }
+  try {
+    const parsed = parser.parse(content, {
+      filepath: absPath,
+      listDir: listSubdirectories,
+      readFile: readFileOrUndefined,
+    })
+    return parsed
+      ? {
+          ...parsed,
+          file: path.relative(cwd, absPath).replaceAll('\\', '/'),
+        }
+      : undefined
+  } catch {}
+  return undefined
+}
@@ -0,0 +1,188 @@
+/**
+ * `pdm.lock` support, with the `pyproject.toml` next to it. The lockfile
+ * leaves out the project itself, so its requirements come from the
+ * pyproject.toml, and lists the resolution groups of every package.
+ */
+
+import path from 'node:path'
+
+import {
+  PYPROJECT_TOML,
+  PYTHON_DEFAULT_GROUP,
+  getPythonHash,
+  getStrings,
+  getVcsQualifiers,
+  isTomlTable,
+  parsePyprojectRequirements,
+  parsePythonRequirement,
+  walkPythonLock,
+} from './python.mts'
+import { parseTomlTables } from './toml.mts'
+import { normalizePypiName } from '../python/pip-report.mts'
+
+import type { PythonLockEdge, PythonLockPackage } from './python.mts'
+import type { TomlValue } from './toml.mts'
+import type {
+  LockfileDependency,
+  LockfileHash,
+  LockfileParseContext,
+  LockfileParser,
+} from './types.mts'
+
+export const PDM_LOCK = 'pdm.lock'
+
+const SDIST_FILE_REGEXP = /\.(?:tar\.gz|zip)$/
+
+function getPdmQualifiers(
+  entries: Record<string, TomlValue>,
+): Record<string, string> | undefined {
+  const { git, revision, url } = entries
+  if (typeof git === 'string') {
+    return getVcsQualifiers(
+      typeof revision === 'string' ? `${git}#${revision}` : git,
+    )
+  }
+  return typeof url === 'string' ? { download_url: url } : undefined
+}
+
+function getPdmSdistHash(
+  files: TomlValue | undefined,
+): LockfileHash | undefined {
+  for (const file of Array.isArray(files) ? files : []) {
+    if (
+      isTomlTable(file) &&
+      typeof file['file'] === 'string' &&
+      SDIST_FILE_REGEXP.test(file['file'])
+    ) {
+      return getPythonHash(file['hash'])
+    }
+  }
+  return undefined
+}
+
+export function parsePdmLockDependencies(
+  content: string | Buffer,
+  context: LockfileParseContext,
+): LockfileDependency[] {
+  const tables = parseTomlTables(content.toString())
+  const packages: PythonLockPackage[] = []
+  const byName = new Map<string, PythonLockPackage[]>()
+  // Extras are locked as their own `name[extra]` entries, which are folded
+  // into the package.
+  const requirementsByExtra = new Map<
+    PythonLockPackage,
+    Map<string, string[]>
+  >()
+  for (let i = 0, { length } = tables; i < length; i += 1) {
+    const { entries, header, isArray } = tables[i]!
+    const { name, version = '' } = entries
+    if (
+      header !== 'package' ||
+      !isArray ||
+      typeof name !== 'string' ||
+      typeof version !== 'string'
+    ) {
+      continue
+    }
+    const normalized = normalizePypiName(name)
+    const candidates = byName.get(normalized) ?? []
+    let pkg = candidates.find(c => c.version === version)
+    if (!pkg) {
+      const qualifiers = getPdmQualifiers(entries)
+      const hash = getPdmSdistHash(entries['files'])
+      pkg = {
+        edges: new Map(),
+        ...(hash ? { hashes: [hash] } : {}),
+        groups: [],
+        id: `${normalized}@${version}`,
+        local: 'path' in entries || entries['editable'] === true,
+        member: false,
+        name: normalized,
+        ...(qualifiers ? { qualifiers } : {}),
+        version,
+      }
+      packages.push(pkg)
+      candidates.push(pkg)
+      byName.set(normalized, candidates)
+      requirementsByExtra.set(pkg, new Map())
+    }
+    for (const group of getStrings(entries['groups'])) {
+      if (!pkg.groups!.includes(group)) {
+        pkg.groups!.push(group)
+      }
+    }
+    if (typeof entries['marker'] === 'string' && entries['marker']) {
+      pkg.markers = [entries['marker']]
+    }
+    const extras = getStrings(entries['extras'])
+    const requirements = getStrings(entries['dependencies'])
+    const byExtra = requirementsByExtra.get(pkg)!
+    for (const extra of extras.length ? extras : [PYTHON_DEFAULT_GROUP]) {
+      byExtra.set(extra, [...(byExtra.get(extra) ?? []), ...requirements])
+    }
+  }
+
+  const getEdges = (
+    requirements: readonly string[],
+    self?: PythonLockPackage | undefined,
+  ): PythonLockEdge[] => {
+    const edges: PythonLockEdge[] = []
+    for (const spec of requirements) {
+      const requirement = parsePythonRequirement(spec)
+      const candidates = requirement ? byName.get(requirement.name) : undefined
+      for (const target of candidates ?? []) {
+        if (
+          target !== self &&
+          (!requirement!.pinned || requirement!.pinned === target.version)
+        ) {
+          edges.push({
+            extras: requirement!.extras,
+            ...(requirement!.marker ? { marker: requirement!.marker } : {}),
+            target,
+          })
+        }
+      }
+    }
+    return edges
+  }
+
+  for (const { 0: pkg, 1: byExtra } of requirementsByExtra) {
+    pkg.groups!.sort()
+    for (const { 0: extra, 1: requirements } of byExtra) {
+      pkg.edges.set(extra, getEdges(requirements, pkg))
+    }
+  }
+
+  // pdm.lock leaves out the project itself, its requirements come from the
+  // pyproject.toml.
+  const pyprojectContent = context.readFile(
+    path.join(path.dirname(context.filepath), PYPROJECT_TOML),
+  )
+  let devGroups: Set<string> | undefined
+  if (pyprojectContent !== undefined) {
+    const pyproject = parsePyprojectRequirements(pyprojectContent.toString())
+    devGroups = pyproject.devGroups
+    const project: PythonLockPackage = {
+      edges: new Map(),
+      id: `${pyproject.name || PYPROJECT_TOML}@`,
+      local: true,
+      member: true,
+      name: pyproject.name || PYPROJECT_TOML,
+      version: '',
+    }
+    for (const { 0: group, 1: requirements } of pyproject.groups) {
+      project.edges.set(group, getEdges(requirements))
+    }
+    packages.push(project)
+  }
+  return walkPythonLock(packages, devGroups)
+}
+
+export const pdmLockfileParser: LockfileParser = {
+  ecosystem: 'pypi',
+  filenames: [PDM_LOCK],
+  parse: (content, context) => ({
+    ecosystem: 'pypi',
+    dependencies: parsePdmLockDependencies(content, context),
+  }),
+}
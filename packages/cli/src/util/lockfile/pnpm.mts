@@ -0,0 +1,251 @@
+/**
+ * pnpm workspace support: `pnpm-lock.yaml` v9.
+ *
+ * Every importer (workspace package) is walked through the lockfile
+ * `snapshots`, so each package lists the workspace packages that pull it in,
+ * named after their `package.json`. `catalog:` specifiers resolve through the
+ * lockfile `catalogs`, and `workspace:` dependencies (`link:` versions) point
+ * at other importers, which are not packages themselves. Peer dependency
+ * variants of a package are deduped into one entry. Packages only reachable
+ * from `devDependencies` are dev dependencies.
+ *
+ * Single-package lockfiles are left to the Socket API; workspaces and
+ * catalogs need the local resolution to attribute dependencies.
+ */
+
+import path from 'node:path'
+
+import { parse as yamlParse } from 'yaml'
+
+import { PNPM_LOCK_YAML } from '@socketsecurity/lib-stable/constants/agents'
+
+import {
+  integrityToLockfileHashes,
+  isNpmRegistryVersion,
+  splitNpmPackageKey,
+  splitNpmPackageName,
+} from './npm.mts'
+
+import type {
+  LockfileDependency,
+  LockfileParseContext,
+  LockfileParser,
+} from './types.mts'
+
+export type PnpmImporterDependency = {
+  specifier?: string | undefined
+  version?: string | undefined
+}
+
+export type PnpmImporter = {
+  dependencies?: Record<string, PnpmImporterDependency> | undefined
+  devDependencies?: Record<string, PnpmImporterDependency> | undefined
+  optionalDependencies?: Record<string, PnpmImporterDependency> | undefined
+}
+
+export type PnpmSnapshot = {
+  dependencies?: Record<string, string> | undefined
+  optionalDependencies?: Record<string, string> | undefined
+}
+
+export type PnpmLockfile = {
+  lockfileVersion?: string | number | undefined
+  catalogs?:
+    | Record<string, Record<string, PnpmImporterDependency>>
+    | undefined
+  importers?: Record<string, PnpmImporter> | undefined
+  packages?:
+    | Record<string, { resolution?: { integrity?: string | undefined } }>
+    | undefined
+  snapshots?: Record<string, PnpmSnapshot> | undefined
+}
+
+const IMPORTER_GROUPS = [
+  'dependencies',
+  'optionalDependencies',
+  'devDependencies',
+] as const
+
+function stripPeerSuffix(version: string): string {
+  const parenIndex = version.indexOf('(')
+  return parenIndex === -1 ? version : version.slice(0, parenIndex)
+}
+
+/**
+ * The version a `catalog:` or `catalog:<name>` specifier resolves to.
+ */
+export function resolvePnpmCatalogVersion(
+  lockfile: PnpmLockfile,
+  packageName: string,
+  specifier: string | undefined,
+): string | undefined {
+  if (!specifier?.startsWith('catalog:')) {
+    return undefined
+  }
+  const catalog = specifier.slice('catalog:'.length) || 'default'
+  return lockfile.catalogs?.[catalog]?.[packageName]?.version
+}
+
+/**
+ * The `packages` key a dependency reference points at, or undefined for
+ * workspace links and local directories. Aliased dependencies reference the
+ * real package as `<name>@<version>`.
+ */
+export function getPnpmPackageKey(
+  packageName: string,
+  ref: string | undefined,
+): string | undefined {
+  if (!ref || ref.startsWith('link:') || ref.startsWith('file:')) {
+    return undefined
+  }
+  const version = stripPeerSuffix(ref)
+  return /^\d/.test(version) ? `${packageName}@${version}` : version
+}
+
+export function isPnpmLockfileV9(lockfile: PnpmLockfile): boolean {
+  return Number.parseFloat(String(lockfile.lockfileVersion)) >= 9
+}
+
+function readImporterName(
+  context: LockfileParseContext,
+  importer: string,
+): string {
+  const content = context.readFile(
+    path.join(path.dirname(context.filepath), importer, 'package.json'),
+  )
+  if (content !== undefined) {
+    try {
+      const { name } = JSON.parse(content.toString()) as { name?: unknown }
+      if (typeof name === 'string' && name) {
+        return name
+      }
+    } catch {}
+  }
+  return importer
+}
+
+export function parsePnpmDependencies(
+  lockfile: PnpmLockfile,
+  context: LockfileParseContext,
+): LockfileDependency[] {
+  const edges = new Map<string, Set<string>>()
+  for (const [snapshotKey, snapshot] of Object.entries(
+    lockfile.snapshots ?? {},
+  )) {
+    const key = stripPeerSuffix(snapshotKey)
+    const children = edges.get(key) ?? new Set()
+    for (const deps of [
+      snapshot?.dependencies,
+      snapshot?.optionalDependencies,
+    ]) {
+      for (const [name, ref] of Object.entries(deps ?? {})) {
+        const childKey = getPnpmPackageKey(name, ref)
+        if (childKey) {
+          children.add(childKey)
+        }
+      }
+    }
+    edges.set(key, children)
+  }
+
+  const directKeys = new Set<string>()
+  const prodKeys = new Set<string>()
+  const workspacesByKey = new Map<string, Set<string>>()
+  // Importer paths are normalized so `packages/a` and `./packages/a` count
+  // as the same workspace package.
+  const importers = new Map<string, PnpmImporter>()
+  for (const [importerPath, importer] of Object.entries(
+    lockfile.importers ?? {},
+  )) {
+    const normalized = path.posix.normalize(importerPath)
+    if (!importers.has(normalized)) {
+      importers.set(normalized, importer ?? {})
+    }
+  }
+  for (const [importerPath, importer] of importers) {
+    const workspace = readImporterName(context, importerPath)
+    const visited = { dev: new Set<string>(), prod: new Set<string>() }
+    for (const group of IMPORTER_GROUPS) {
+      const isDev = group === 'devDependencies'
+      const seen = isDev ? visited.dev : visited.prod
+      for (const [name, entry] of Object.entries(importer[group] ?? {})) {
+        const key = getPnpmPackageKey(
+          name,
+          entry?.version ??
+            resolvePnpmCatalogVersion(lockfile, name, entry?.specifier),
+        )
+        if (!key) {
+          continue
+        }
+        directKeys.add(key)
+        const queue = [key]
+        while (queue.length) {
+          const current = queue.pop()!
+          if (seen.has(current)) {
+            continue
+          }
+          seen.add(current)
+          if (!isDev) {
+            prodKeys.add(current)
+          }
+          const workspaces = workspacesByKey.get(current) ?? new Set()
+          workspaces.add(workspace)
+          workspacesByKey.set(current, workspaces)
+          queue.push(...(edges.get(current) ?? []))
+        }
+      }
+    }
+  }
+
+  const packages = lockfile.packages ?? {}
+  const packageKeys = new Set(Object.keys(packages))
+  const dependencies: LockfileDependency[] = []
+  for (const [key, info] of Object.entries(packages)) {
+    const parts = splitNpmPackageKey(key)
+    if (!parts || !isNpmRegistryVersion(parts.version)) {
+      continue
+    }
+    const workspaces = workspacesByKey.get(key)
+    const hashes = integrityToLockfileHashes(info?.resolution?.integrity)
+    const childIds = [...(edges.get(key) ?? [])].filter(childKey =>
+      packageKeys.has(childKey),
+    )
+    dependencies.push({
+      id: key,
+      type: 'npm',
+      ...splitNpmPackageName(parts.packageName),
+      version: parts.version,
+      direct: directKeys.has(key),
+      ...(workspaces ? { dev: !prodKeys.has(key) } : {}),
+      ...(workspaces ? { workspaces: [...workspaces] } : {}),
+      ...(hashes.length ? { hashes } : {}),
+      ...(childIds.length ? { dependencies: childIds } : {}),
+    })
+  }
+  return dependencies
+}
+
+export const pnpmLockfileParser: LockfileParser = {
+  ecosystem: 'npm',
+  filenames: [PNPM_LOCK_YAML],
+  parse: (content, context) => {
+    const lockfile = yamlParse(content.toString()) as PnpmLockfile | null
+    // Older lockfile versions have a different layout and are left to the
+    // Socket API.
+    if (
+      !lockfile ||
+      typeof lockfile !== 'object' ||
+      !isPnpmLockfileV9(lockfile)
+    ) {
+      return undefined
+    }
+    const requiresLocalResolution =
+      Object.keys(lockfile.importers ?? {}).length > 1 ||
+      Object.keys(lockfile.catalogs ?? {}).length > 0
+    return {
+      ecosystem: 'npm',
+      dependencies: parsePnpmDependencies(lockfile, context),
+      ...(requiresLocalResolution ? { requiresLocalResolution } : {}),
+    }
+  },
+}
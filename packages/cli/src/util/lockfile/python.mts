@@ -0,0 +1,328 @@
+/**
+ * Python support shared by the `uv.lock` and `pdm.lock` parsers: PEP 508
+ * requirements, `pyproject.toml` requirements and the walk over the locked
+ * packages.
+ *
+ * Both lockfiles pin one environment independent resolution. Every package
+ * keeps the resolution groups that pull it in as its scopes: `default` for
+ * the project dependencies, the name of an extra (`[project.optional-
+ * dependencies]`) or of a dependency group (`dev`, `test`, …). Packages only
+ * pulled in by dependency groups are dev dependencies. Requirements limited
+ * by an environment marker, e.g. `sys_platform == 'win32'`, keep it as a
+ * marker of the package when every requirement of the package has one; the
+ * markers of its dependents are not combined in.
+ *
+ * Local packages (the project, workspace members, path and editable
+ * dependencies) are not published, so they are not reported themselves;
+ * their dependencies become the direct dependencies of the project. Git
+ * dependencies keep their repository and pinned commit as a `vcs_url`
+ * qualifier and direct URL archives their `download_url`.
+ */
+
+import { parseTomlTables } from './toml.mts'
+import { normalizePypiName } from '../python/pip-report.mts'
+
+import type { TomlValue } from './toml.mts'
+import type { LockfileDependency, LockfileHash } from './types.mts'
+
+export const PYPROJECT_TOML = 'pyproject.toml'
+
+// Resolution group of the project dependencies themselves.
+export const PYTHON_DEFAULT_GROUP = 'default'
+
+export type PythonRequirement = {
+  extras: string[]
+  marker?: string | undefined
+  name: string
+  // Exact version of a `name==version` pin.
+  pinned?: string | undefined
+}
+
+/**
+ * The parts of a PEP 508 requirement the lockfiles need: name, extras,
+ * an `==` pin and the environment marker.
+ */
+export function parsePythonRequirement(
+  spec: string,
+): PythonRequirement | undefined {
+  const semicolonIndex = spec.indexOf(';')
+  const requirement = (
+    semicolonIndex === -1 ? spec : spec.slice(0, semicolonIndex)
+  ).trim()
+  const marker =
+    semicolonIndex === -1 ? '' : spec.slice(semicolonIndex + 1).trim()
+  const match =
+    /^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[([^\]]*)\])?\s*(?:===?\s*([^\s,;]+)\s*$)?/.exec(
+      requirement,
+    )
+  if (!match) {
+    return undefined
+  }
+  const extras = (match[2] ?? '')
+    .split(',')
+    .map(extra => extra.trim())
+    .filter(Boolean)
+  return {
+    extras,
+    ...(marker ? { marker } : {}),
+    name: normalizePypiName(match[1]!),
+    ...(match[3] && !/[*<>!~]/.test(match[3]) ? { pinned: match[3] } : {}),
+  }
+}
+
+/**
+ * A `sha256:<hex>` lockfile hash.
+ */
+export function getPythonHash(
+  value: TomlValue | undefined,
+): LockfileHash | undefined {
+  const match =
+    typeof value === 'string' ? /^sha256:([0-9a-f]{64})$/i.exec(value) : null
+  return match
+    ? { alg: 'SHA-256', content: match[1]!.toLowerCase() }
+    : undefined
+}
+
+export function isTomlTable(
+  value: TomlValue | undefined,
+): value is { [key: string]: TomlValue } {
+  return !!value && typeof value === 'object' && !Array.isArray(value)
+}
+
+export function getStrings(value: TomlValue | undefined): string[] {
+  return Array.isArray(value)
+    ? value.filter((v): v is string => typeof v === 'string')
+    : []
+}
+
+export function getVcsQualifiers(url: string): Record<string, string> {
+  // https://github.com/org/repo?rev=main#<commit>
+  const hashIndex = url.lastIndexOf('#')
+  const base = (hashIndex === -1 ? url : url.slice(0, hashIndex)).split('?')[0]!
+  const commit = hashIndex === -1 ? '' : url.slice(hashIndex + 1)
+  return { vcs_url: `git+${base}${commit ? `@${commit}` : ''}` }
+}
+
+export type PythonLockEdge = {
+  extras: string[]
+  marker?: string | undefined
+  target: PythonLockPackage
+}
+
+export type PythonLockPackage = {
+  // Requirements per resolution group, `default` for the unconditional ones.
+  // Published packages only have `default` and their extras.
+  edges: Map<string, PythonLockEdge[]>
+  hashes?: LockfileHash[] | undefined
+  // Resolution groups the lockfile itself lists for the package (pdm).
+  groups?: string[] | undefined
+  id: string
+  local: boolean
+  // Environment markers the lockfile itself lists for the package.
+  markers?: string[] | undefined
+  // Root of the project, i.e. a workspace member rather than a dependency.
+  member: boolean
+  name: string
+  qualifiers?: Record<string, string> | undefined
+  version: string
+}
+
+/**
+ * Walk the requirements of the local packages and report every published
+ * package reached, with the groups, markers and direct dependents that pull
+ * it in. `devGroups` holds the dependency groups; extras are not dev.
+ */
+export function walkPythonLock(
+  packages: readonly PythonLockPackage[],
+  devGroups: ReadonlySet<string> | undefined,
+): LockfileDependency[] {
+  const scopes = new Map<PythonLockPackage, Set<string>>()
+  const activeExtras = new Map<PythonLockPackage, Set<string>>()
+  const owners = new Map<PythonLockPackage, Set<string>>()
+  const edgeMarkers = new Map<PythonLockPackage, Set<string>>()
+  const unconditional = new Set<PythonLockPackage>()
+
+  const noteEdge = (edge: PythonLockEdge) => {
+    if (edge.marker) {
+      const markers = edgeMarkers.get(edge.target) ?? new Set()
+      markers.add(edge.marker)
+      edgeMarkers.set(edge.target, markers)
+    } else {
+      unconditional.add(edge.target)
+    }
+  }
+
+  // Published packages reached through local ones count as direct.
+  const expandLocal = (
+    pkg: PythonLockPackage,
+    groups: readonly string[],
+    seen = new Set<PythonLockPackage>(),
+  ): PythonLockEdge[] => {
+    if (seen.has(pkg)) {
+      return []
+    }
+    seen.add(pkg)
+    const out: PythonLockEdge[] = []
+    for (const group of groups) {
+      for (const edge of pkg.edges.get(group) ?? []) {
+        if (edge.target.local) {
+          out.push(
+            ...expandLocal(
+              edge.target,
+              [PYTHON_DEFAULT_GROUP, ...edge.extras],
+              seen,
+            ),
+          )
+        } else {
+          out.push(edge)
+        }
+      }
+    }
+    return out
+  }
+
+  const members = packages.filter(pkg => pkg.member)
+  for (let i = 0, { length } = members; i < length; i += 1) {
+    const member = members[i]!
+    for (const group of member.edges.keys()) {
+      const queue: PythonLockEdge[] = []
+      for (const edge of expandLocal(member, [group])) {
+        const memberOwners = owners.get(edge.target) ?? new Set()
+        memberOwners.add(member.name)
+        owners.set(edge.target, memberOwners)
+        noteEdge(edge)
+        queue.push(edge)
+      }
+      const visited = new Set<string>()
+      while (queue.length) {
+        const { extras, target } = queue.pop()!
+        const key = `${target.id}[${extras.join(',')}]`
+        if (visited.has(key)) {
+          continue
+        }
+        visited.add(key)
+        const groups = scopes.get(target) ?? new Set()
+        groups.add(group)
+        scopes.set(target, groups)
+        const active = activeExtras.get(target) ?? new Set()
+        for (const extra of extras) {
+          active.add(extra)
+        }
+        activeExtras.set(target, active)
+        for (const next of expandLocal(target, [
+          PYTHON_DEFAULT_GROUP,
+          ...extras,
+        ])) {
+          noteEdge(next)
+          queue.push(next)
+        }
+      }
+    }
+  }
+
+  const dependencies: LockfileDependency[] = []
+  for (let i = 0, { length } = packages; i < length; i += 1) {
+    const pkg = packages[i]!
+    if (pkg.local) {
+      continue
+    }
+    const pkgScopes = pkg.groups?.length
+      ? pkg.groups
+      : [...(scopes.get(pkg) ?? [])].sort()
+    const pkgOwners = owners.get(pkg)
+    // Without local packages to start from, every extra counts.
+    const extras = members.length
+      ? [PYTHON_DEFAULT_GROUP, ...(activeExtras.get(pkg) ?? [])]
+      : [...pkg.edges.keys()]
+    const childIds = new Set<string>()
+    for (const edge of expandLocal(pkg, extras)) {
+      childIds.add(edge.target.id)
+    }
+    childIds.delete(pkg.id)
+    const markers =
+      pkg.markers ??
+      (unconditional.has(pkg) ? undefined : [...(edgeMarkers.get(pkg) ?? [])])
+    dependencies.push({
+      id: pkg.id,
+      type: 'pypi',
+      name: pkg.name,
+      version: pkg.version,
+      ...(pkg.qualifiers ? { qualifiers: pkg.qualifiers } : {}),
+      ...(members.length ? { direct: !!pkgOwners } : {}),
+      ...(devGroups && pkgScopes.length
+        ? { dev: pkgScopes.every(group => devGroups.has(group)) }
+        : {}),
+      ...(pkgScopes.length ? { scopes: pkgScopes } : {}),
+      ...(markers?.length ? { markers } : {}),
+      ...(pkgOwners ? { workspaces: [...pkgOwners] } : {}),
+      ...(pkg.hashes ? { hashes: pkg.hashes } : {}),
+      ...(childIds.size ? { dependencies: [...childIds] } : {}),
+    })
+  }
+  return dependencies
+}
+
+export type PyprojectRequirements = {
+  // Dependency groups, as opposed to the default group and extras.
+  devGroups: Set<string>
+  // Requirements per resolution group.
+  groups: Map<string, string[]>
+  name: string
+}
+
+/**
+ * The requirements of a `pyproject.toml` per resolution group: `default`,
+ * `[project.optional-dependencies]` extras, and the `[dependency-groups]`
+ * and `[tool.pdm.dev-dependencies]` dependency groups.
+ */
+export function parsePyprojectRequirements(
+  content: string,
+): PyprojectRequirements {
+  const groups = new Map<string, string[]>()
+  const devGroups = new Set<string>()
+  const includes = new Map<string, string[]>()
+  let name = ''
+  const tables = parseTomlTables(content)
+  for (let i = 0, { length } = tables; i < length; i += 1) {
+    const { entries, header } = tables[i]!
+    if (header === 'project') {
+      name = typeof entries['name'] === 'string' ? entries['name'] : ''
+      groups.set(PYTHON_DEFAULT_GROUP, getStrings(entries['dependencies']))
+    } else if (header === 'project.optional-dependencies') {
+      for (const { 0: extra, 1: value } of Object.entries(entries)) {
+        groups.set(extra, getStrings(value))
+      }
+    } else if (
+      header === 'dependency-groups' ||
+      header === 'tool.pdm.dev-dependencies'
+    ) {
+      for (const { 0: group, 1: value } of Object.entries(entries)) {
+        devGroups.add(group)
+        groups.set(group, [...(groups.get(group) ?? []), ...getStrings(value)])
+        // PEP 735 `{ include-group = "test" }` entries.
+        for (const item of Array.isArray(value) ? value : []) {
+          if (isTomlTable(item) && typeof item['include-group'] === 'string') {
+            includes.set(group, [
+              ...(includes.get(group) ?? []),
+              item['include-group'],
+            ])
+          }
+        }
+      }
+    }
+  }
+  const expand = (group: string, seen: Set<string>): string[] => {
+    if (seen.has(group)) {
+      return []
+    }
+    seen.add(group)
+    return [
+      ...(groups.get(group) ?? []),
+      ...(includes.get(group) ?? []).flatMap(g => expand(g, seen)),
+    ]
+  }
+  for (const group of includes.keys()) {
+    groups.set(group, expand(group, new Set()))
+  }
+  return { devGroups, groups, name }
+}
@@ -0,0 +1,373 @@
+/**
+ * Import of third-party SBOMs as full scan input.
+ *
+ * Key Functions: - parseSbomDocument: Read the components of a CycloneDX or
+ * SPDX 2 JSON document, with their dependency edges - prepareSbomForUpload:
+ * Write them as a `.socket.sbom.cdx.json` SBOM in cwd for the upload.
+ *
+ * Components are identified by purl. Those without one, or with a purl of an
+ * ecosystem Socket does not know, are counted as skipped. The subject of the
+ * SBOM (CycloneDX `metadata.component`, the SPDX packages the document
+ * describes) is the project itself and not uploaded; the packages it depends
+ * on are marked as direct dependencies.
+ */
+
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import { lockfilesToCycloneDx } from './sbom.mts'
+import { DOT_SOCKET_DOT_SBOM_CDX_JSON } from '../../constants/paths.mts'
+import { ALL_ECOSYSTEMS } from '../ecosystem/types.mts'
+import { getErrorCause } from '../error/errors.mts'
+import { getPurlObject } from '../purl/parse.mts'
+import { isPlainObject } from '../socket-yaml.mts'
+
+import type {
+  LockfileDependency,
+  LockfileHash,
+  ParsedLockfile,
+} from './types.mts'
+import type { CResult } from '../../types.mts'
+import type { PURL_Type } from '../ecosystem/types.mts'
+
+export type SbomFormat = 'cyclonedx' | 'spdx'
+
+export type ImportedSbom = {
+  format: SbomFormat
+  // One entry per ecosystem of the components.
+  lockfiles: ParsedLockfile[]
+  // Components without a purl of a supported ecosystem.
+  skipped: number
+}
+
+export type SbomScanPaths = ImportedSbom & {
+  cleanup: () => Promise<void>
+  components: number
+  path: string
+}
+
+export type PrepareSbomForUploadOptions = {
+  cwd?: string | undefined
+}
+
+type SbomComponent = {
+  hashes: LockfileHash[]
+  id: string
+  purl: string
+}
+
+type SbomGraph = {
+  components: SbomComponent[]
+  // Dependencies of each component id.
+  edges: Map<string, Set<string>>
+  // Ids of the components that make up the subject of the SBOM.
+  rootIds: Set<string>
+  skipped: number
+}
+
+const KNOWN_ECOSYSTEMS: ReadonlySet<string> = new Set(ALL_ECOSYSTEMS)
+
+function getObjects(value: unknown): Array<Record<string, unknown>> {
+  return Array.isArray(value) ? value.filter(isPlainObject) : []
+}
+
+function getString(value: unknown): string {
+  return typeof value === 'string' ? value.trim() : ''
+}
+
+function addEdge(
+  edges: Map<string, Set<string>>,
+  from: string,
+  to: string,
+): void {
+  let dependsOn = edges.get(from)
+  if (!dependsOn) {
+    dependsOn = new Set()
+    edges.set(from, dependsOn)
+  }
+  dependsOn.add(to)
+}
+
+function collectCycloneDxComponents(
+  value: unknown,
+  components: Array<Record<string, unknown>>,
+): void {
+  for (const component of getObjects(value)) {
+    components.push(component)
+    // Assemblies nest the components they are made of.
+    collectCycloneDxComponents(component['components'], components)
+  }
+}
+
+function readCycloneDx(doc: Record<string, unknown>): SbomGraph {
+  const metadata = isPlainObject(doc['metadata']) ? doc['metadata'] : {}
+  const subject = isPlainObject(metadata['component'])
+    ? metadata['component']
+    : undefined
+  const rootIds = new Set<string>()
+  const rootRef = getString(subject?.['bom-ref'])
+  if (rootRef) {
+    rootIds.add(rootRef)
+  }
+
+  const entries: Array<Record<string, unknown>> = []
+  collectCycloneDxComponents(doc['components'], entries)
+  const components: SbomComponent[] = []
+  let skipped = 0
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const entry = entries[i]!
+    const ref = getString(entry['bom-ref'])
+    if (rootIds.has(ref)) {
+      continue
+    }
+    const purl = getString(entry['purl'])
+    if (!purl) {
+      skipped += 1
+      continue
+    }
+    components.push({
+      hashes: getObjects(entry['hashes']).flatMap(hash => {
+        const alg = getString(hash['alg'])
+        const content = getString(hash['content'])
+        return alg && content ? [{ alg, content }] : []
+      }),
+      id: ref || purl,
+      purl,
+    })
+  }
+
+  const edges = new Map<string, Set<string>>()
+  for (const dependency of getObjects(doc['dependencies'])) {
+    const ref = getString(dependency['ref'])
+    const dependsOn = dependency['dependsOn']
+    for (const childRef of Array.isArray(dependsOn) ? dependsOn : []) {
+      if (ref && getString(childRef)) {
+        addEdge(edges, ref, getString(childRef))
+      }
+    }
+  }
+  return { components, edges, rootIds, skipped }
+}
+
+// SPDX names checksum algorithms without the dash CycloneDX uses, e.g.
+// `SHA256` for `SHA-256`.
+function toCycloneDxHashAlg(algorithm: string): string {
+  return algorithm.toUpperCase().replace(/^SHA(\d+)$/, 'SHA-$1')
+}
+
+function readSpdx(doc: Record<string, unknown>): SbomGraph {
+  const rootIds = new Set<string>()
+  const describes = doc['documentDescribes']
+  for (const id of Array.isArray(describes) ? describes : []) {
+    if (getString(id)) {
+      rootIds.add(getString(id))
+    }
+  }
+
+  const edges = new Map<string, Set<string>>()
+  for (const relationship of getObjects(doc['relationships'])) {
+    const element = getString(relationship['spdxElementId'])
+    const related = getString(relationship['relatedSpdxElement'])
+    const type = getString(relationship['relationshipType'])
+    if (!element || !related) {
+      continue
+    }
+    if (type === 'DESCRIBES' && element === 'SPDXRef-DOCUMENT') {
+      rootIds.add(related)
+    } else if (type === 'DEPENDS_ON') {
+      addEdge(edges, element, related)
+    } else if (type === 'DEPENDENCY_OF') {
+      addEdge(edges, related, element)
+    }
+  }
+
+  const components: SbomComponent[] = []
+  let skipped = 0
+  for (const pkg of getObjects(doc['packages'])) {
+    const id = getString(pkg['SPDXID'])
+    if (rootIds.has(id)) {
+      continue
+    }
+    const purlRef = getObjects(pkg['externalRefs']).find(
+      ref => getString(ref['referenceType']) === 'purl',
+    )
+    const purl = getString(purlRef?.['referenceLocator'])
+    if (!purl) {
+      skipped += 1
+      continue
+    }
+    components.push({
+      hashes: getObjects(pkg['checksums']).flatMap(checksum => {
+        const alg = getString(checksum['algorithm'])
+        const content = getString(checksum['checksumValue'])
+        return alg && content
+          ? [{ alg: toCycloneDxHashAlg(alg), content }]
+          : []
+      }),
+      id: id || purl,
+      purl,
+    })
+  }
+  return { components, edges, rootIds, skipped }
+}
+
+function toLockfiles(
+  { components, edges, rootIds }: SbomGraph,
+  file: string,
+): { lockfiles: ParsedLockfile[]; skipped: number } {
+  const directIds = new Set<string>()
+  for (const rootId of rootIds) {
+    for (const id of edges.get(rootId) ?? []) {
+      directIds.add(id)
+    }
+  }
+
+  const byEcosystem = new Map<PURL_Type, LockfileDependency[]>()
+  let skipped = 0
+  for (let i = 0, { length } = components; i < length; i += 1) {
+    const { hashes, id, purl } = components[i]!
+    const purlObj = getPurlObject(purl, { throws: false })
+    const type = purlObj?.type.toLowerCase() as PURL_Type | undefined
+    if (!purlObj?.name || !type || !KNOWN_ECOSYSTEMS.has(type)) {
+      skipped += 1
+      continue
+    }
+    let dependencies = byEcosystem.get(type)
+    if (!dependencies) {
+      dependencies = []
+      byEcosystem.set(type, dependencies)
+    }
+    const qualifiers = purlObj.qualifiers as Record<string, string> | undefined
+    const dependsOn = [...(edges.get(id) ?? [])]
+    dependencies.push({
+      id,
+      type,
+      ...(purlObj.namespace ? { namespace: purlObj.namespace } : {}),
+      name: purlObj.name,
+      version: purlObj.version ?? '',
+      ...(qualifiers && Object.keys(qualifiers).length ? { qualifiers } : {}),
+      // Without edges from the subject the SBOM does not tell.
+      ...(directIds.size ? { direct: directIds.has(id) } : {}),
+      ...(hashes.length ? { hashes } : {}),
+      ...(dependsOn.length ? { dependencies: dependsOn } : {}),
+    })
+  }
+  return {
+    lockfiles: [...byEcosystem].map(({ 0: ecosystem, 1: dependencies }) => ({
+      dependencies,
+      ecosystem,
+      file,
+    })),
+    skipped,
+  }
+}
+
+/**
+ * The components of a CycloneDX or SPDX 2 JSON document. `file` is recorded
+ * as the manifest of every component, so alerts trace back to the SBOM.
+ */
+export function parseSbomDocument(
+  content: string,
+  file: string,
+): CResult<ImportedSbom> {
+  let doc: unknown
+  try {
+    doc = JSON.parse(content)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Invalid SBOM',
+      cause: `Unable to parse ${file} as JSON: ${getErrorCause(e)}`,
+    }
+  }
+  let format: SbomFormat | undefined
+  if (isPlainObject(doc) && doc['bomFormat'] === 'CycloneDX') {
+    format = 'cyclonedx'
+  } else if (
+    isPlainObject(doc) &&
+    getString(doc['spdxVersion']).startsWith('SPDX-2')
+  ) {
+    format = 'spdx'
+  }
+  if (!format) {
+    return {
+      ok: false,
+      message: 'Unsupported SBOM',
+      cause: `${file} is not a CycloneDX or SPDX 2 JSON document`,
+    }
+  }
+  const graph =
+    format === 'cyclonedx'
+      ? readCycloneDx(doc as Record<string, unknown>)
+      : readSpdx(doc as Record<string, unknown>)
+  const { lockfiles, skipped } = toLockfiles(graph, file)
+  return {
+    ok: true,
+    data: { format, lockfiles, skipped: graph.skipped + skipped },
+  }
+}
+
+/**
+ * Read the SBOM at sbomPath and write its components as the CycloneDX SBOM
+ * that `socket scan create --from-sbom` uploads. Fails when no component can
+ * be scanned.
+ */
+export async function prepareSbomForUpload(
+  sbomPath: string,
+  options?: PrepareSbomForUploadOptions | undefined,
+): Promise<CResult<SbomScanPaths>> {
+  const { cwd = process.cwd() } = {
+    __proto__: null,
+    ...options,
+  } as PrepareSbomForUploadOptions
+
+  const resolved = path.resolve(cwd, sbomPath)
+  const content = safeReadFileSync(resolved)
+  if (content === undefined) {
+    return {
+      ok: false,
+      message: 'SBOM not found',
+      cause: `Unable to read ${sbomPath}`,
+    }
+  }
+  const relative = path.relative(cwd, resolved)
+  const file = relative.startsWith('..') ? path.basename(resolved) : relative
+  const importCResult = parseSbomDocument(
+    Buffer.isBuffer(content) ? content.toString('utf8') : content,
+    file,
+  )
+  if (!importCResult.ok) {
+    return importCResult
+  }
+  const { lockfiles } = importCResult.data
+  let components = 0
+  for (const lockfile of lockfiles) {
+    components += lockfile.dependencies.length
+  }
+  if (!components) {
+    return {
+      ok: false,
+      message: 'No packages found',
+      cause: `${sbomPath} lists no components with a purl of a supported ecosystem`,
+    }
+  }
+
+  const uploadPath = path.join(cwd, DOT_SOCKET_DOT_SBOM_CDX_JSON)
+  const bom = lockfilesToCycloneDx(lockfiles)
+  await fs.writeFile(uploadPath, `${JSON.stringify(bom, null, 2)}\n`, 'utf8')
+
+  let cleaned = false
+  const cleanup = async () => {
+    if (!cleaned) {
+      cleaned = true
+      await safeDelete(uploadPath, { force: true })
+    }
+  }
+  return {
+    ok: true,
+    data: { ...importCResult.data, cleanup, components, path: uploadPath },
+  }
+}
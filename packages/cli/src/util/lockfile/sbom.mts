@@ -0,0 +1,234 @@
+/**
+ * CycloneDX rendering of locally parsed lockfiles.
+ *
+ * Packages are keyed by purl and shared across lockfiles; the lockfile(s) that
+ * declared a package are kept as `socket:manifest` properties so alerts can
+ * still be traced back to the file that introduced them. Locally patched
+ * packages carry their patches as CycloneDX `pedigree` and a `socket:patched`
+ * property.
+ */
+
+import crypto from 'node:crypto'
+
+import { CYCLONEDX_SPEC_VERSION } from '../../commands/sbom/generate-cyclonedx.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { createPurlObject } from '../purl/parse.mts'
+
+import type { LockfileDependency, ParsedLockfile } from './types.mts'
+import type {
+  CycloneDxComponent,
+  CycloneDxDependency,
+  CycloneDxProperty,
+} from '../../commands/sbom/generate-cyclonedx.mts'
+
+export type LockfileCycloneDxPatch = {
+  type: 'unofficial'
+  diff: {
+    url: string
+    text?: { contentType: 'text/plain'; content: string } | undefined
+  }
+}
+
+export type LockfileCycloneDxComponent = CycloneDxComponent & {
+  hashes?: Array<{ alg: string; content: string }> | undefined
+  pedigree?: { patches: LockfileCycloneDxPatch[] } | undefined
+}
+
+export type LockfileCycloneDxBom = {
+  bomFormat: 'CycloneDX'
+  specVersion: string
+  serialNumber: string
+  version: number
+  metadata: {
+    timestamp: string
+    tools: {
+      components: Array<{
+        type: 'application'
+        group: string
+        name: string
+        version: string
+      }>
+    }
+  }
+  components: LockfileCycloneDxComponent[]
+  dependencies: CycloneDxDependency[]
+}
+
+export type LockfilesToCycloneDxOptions = {
+  serialNumber?: string | undefined
+  timestamp?: string | undefined
+}
+
+export function getLockfileDependencyPurl(dep: LockfileDependency): string {
+  const { name, namespace, qualifiers, type, version } = dep
+  return (
+    createPurlObject({
+      type,
+      namespace,
+      name,
+      version,
+      qualifiers,
+      throws: false,
+    })?.toString() ??
+    `pkg:${type}/${namespace ? `${namespace}/` : ''}${name}${version ? `@${version}` : ''}`
+  )
+}
+
+function getDependencyProperties(
+  dep: LockfileDependency,
+  file: string,
+): CycloneDxProperty[] {
+  const properties: CycloneDxProperty[] = [
+    { name: 'socket:manifest', value: file },
+  ]
+  if (dep.direct !== undefined) {
+    properties.push({ name: 'socket:direct', value: String(!!dep.direct) })
+  }
+  if (dep.dev !== undefined) {
+    properties.push({ name: 'socket:dev', value: String(!!dep.dev) })
+  }
+  for (const scope of dep.scopes ?? []) {
+    properties.push({ name: 'socket:scope', value: scope })
+  }
+  for (const marker of dep.markers ?? []) {
+    properties.push({ name: 'socket:marker', value: marker })
+  }
+  for (const workspace of dep.workspaces ?? []) {
+    properties.push({ name: 'socket:workspace', value: workspace })
+  }
+  if (dep.patches?.length) {
+    properties.push({ name: 'socket:patched', value: 'true' })
+  }
+  return properties
+}
+
+function getPedigree(
+  dep: LockfileDependency,
+): LockfileCycloneDxComponent['pedigree'] {
+  if (!dep.patches?.length) {
+    return undefined
+  }
+  return {
+    patches: dep.patches.map(patch => ({
+      type: 'unofficial',
+      diff: {
+        url: patch.path,
+        ...(patch.content !== undefined
+          ? { text: { contentType: 'text/plain', content: patch.content } }
+          : {}),
+      },
+    })),
+  }
+}
+
+function mergeProperties(
+  existing: CycloneDxProperty[],
+  incoming: CycloneDxProperty[],
+): CycloneDxProperty[] {
+  const merged = [...existing]
+  for (let i = 0, { length } = incoming; i < length; i += 1) {
+    const property = incoming[i]!
+    const index = merged.findIndex(p => p.name === property.name)
+    if (property.name === 'socket:direct' || property.name === 'socket:dev') {
+      // A package is direct if any lockfile declares it directly, and only a
+      // dev dependency if every lockfile agrees.
+      if (index === -1) {
+        merged.push(property)
+      } else if (
+        (property.name === 'socket:direct') === (property.value === 'true')
+      ) {
+        merged[index] = property
+      }
+    } else if (
+      !merged.some(p => p.name === property.name && p.value === property.value)
+    ) {
+      merged.push(property)
+    }
+  }
+  return merged
+}
+
+export function lockfilesToCycloneDx(
+  lockfiles: ParsedLockfile[],
+  { serialNumber, timestamp }: LockfilesToCycloneDxOptions = {},
+): LockfileCycloneDxBom {
+  const components = new Map<string, LockfileCycloneDxComponent>()
+  const edges = new Map<string, Set<string>>()
+
+  for (let i = 0, { length } = lockfiles; i < length; i += 1) {
+    const { dependencies, file } = lockfiles[i]!
+    const refById = new Map<string, string>()
+    for (let j = 0, { length: depCount } = dependencies; j < depCount; j += 1) {
+      const dep = dependencies[j]!
+      const ref = getLockfileDependencyPurl(dep)
+      refById.set(dep.id, ref)
+      const properties = getDependencyProperties(dep, file)
+      const existing = components.get(ref)
+      const pedigree = getPedigree(dep)
+      if (existing) {
+        existing.properties = mergeProperties(
+          existing.properties ?? [],
+          properties,
+        )
+        if (!existing.hashes && dep.hashes?.length) {
+          existing.hashes = dep.hashes
+        }
+        if (!existing.pedigree && pedigree) {
+          existing.pedigree = pedigree
+        }
+        continue
+      }
+      components.set(ref, {
+        type: 'library',
+        'bom-ref': ref,
+        ...(dep.namespace ? { group: dep.namespace } : {}),
+        name: dep.name,
+        ...(dep.version ? { version: dep.version } : {}),
+        ...(dep.hashes?.length ? { hashes: dep.hashes } : {}),
+        ...(pedigree ? { pedigree } : {}),
+        purl: ref,
+        properties,
+      })
+    }
+    for (let j = 0, { length: depCount } = dependencies; j < depCount; j += 1) {
+      const dep = dependencies[j]!
+      const ref = refById.get(dep.id)!
+      let dependsOn = edges.get(ref)
+      if (!dependsOn) {
+        dependsOn = new Set()
+        edges.set(ref, dependsOn)
+      }
+      for (const childId of dep.dependencies ?? []) {
+        const childRef = refById.get(childId)
+        if (childRef && childRef !== ref) {
+          dependsOn.add(childRef)
+        }
+      }
+    }
+  }
+
+  return {
+    bomFormat: 'CycloneDX',
+    specVersion: CYCLONEDX_SPEC_VERSION,
+    serialNumber: `urn:uuid:${serialNumber ?? crypto.randomUUID()}`,
+    version: 1,
+    metadata: {
+      timestamp: timestamp ?? new Date().toISOString(),
+      tools: {
+        components: [
+          {
+            type: 'application',
+            group: 'socket.dev',
+            name: 'socket',
+            version: getCliVersion() || '0.0.0',
+          },
+        ],
+      },
+    },
+    components: [...components.values()],
+    dependencies: [...edges].map(([ref, dependsOn]) => ({
+      ref,
+      dependsOn: [...dependsOn],
+    })),
+  }
+}
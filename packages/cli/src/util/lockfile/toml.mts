@@ -0,0 +1,219 @@
+/**
+ * Minimal TOML reader for lockfiles and manifests (Cargo.lock, Cargo.toml).
+ *
+ * Only the subset these files use is supported: `[table]` and `[[array]]`
+ * headers, `key = value` pairs with basic/literal strings, numbers, booleans,
+ * (multi-line) arrays of those, and inline tables. Inline tables are returned
+ * as objects of the same scalar values.
+ */
+
+export type TomlValue =
+  | string
+  | number
+  | boolean
+  | TomlValue[]
+  | { [key: string]: TomlValue }
+
+export type TomlTable = {
+  // Dotted header path with quotes removed, e.g. `target.cfg(unix).dependencies`.
+  header: string
+  isArray: boolean
+  entries: Record<string, TomlValue>
+}
+
+const STRING_ESCAPES = {
+  __proto__: null,
+  b: '\b',
+  f: '\f',
+  n: '\n',
+  r: '\r',
+  t: '\t',
+  '"': '"',
+  '\\': '\\',
+} as unknown as Record<string, string | undefined>
+
+class TomlReader {
+  index = 0
+  constructor(readonly text: string) {}
+
+  skipSpace(newlines: boolean): void {
+    const { text } = this
+    while (this.index < text.length) {
+      const ch = text[this.index]!
+      if (
+        ch === ' ' ||
+        ch === '\t' ||
+        (newlines && (ch === '\n' || ch === '\r'))
+      ) {
+        this.index += 1
+      } else if (ch === '#') {
+        while (this.index < text.length && text[this.index] !== '\n') {
+          this.index += 1
+        }
+      } else {
+        break
+      }
+    }
+  }
+
+  readString(): string {
+    const { text } = this
+    const quote = text[this.index]!
+    const triple = text.startsWith(quote.repeat(3), this.index)
+    const delimiter = triple ? quote.repeat(3) : quote
+    this.index += delimiter.length
+    // A newline right after the opening delimiter is not part of the string.
+    if (triple && text.startsWith('\r\n', this.index)) {
+      this.index += 2
+    } else if (triple && text[this.index] === '\n') {
+      this.index += 1
+    }
+    let out = ''
+    while (this.index < text.length) {
+      if (text.startsWith(delimiter, this.index)) {
+        this.index += delimiter.length
+        return out
+      }
+      const ch = text[this.index]!
+      if (ch === '\\' && quote === '"') {
+        const next = text[this.index + 1]!
+        if (next === 'u' || next === 'U') {
+          const size = next === 'u' ? 4 : 8
+          const hex = text.slice(this.index + 2, this.index + 2 + size)
+          out += String.fromCodePoint(Number.parseInt(hex, 16))
+          this.index += 2 + size
+          continue
+        }
+        out += STRING_ESCAPES[next] ?? next
+        this.index += 2
+        continue
+      }
+      out += ch
+      this.index += 1
+    }
+    return out
+  }
+
+  readKey(): string {
+    const { text } = this
+    const parts: string[] = []
+    for (;;) {
+      this.skipSpace(false)
+      const ch = text[this.index]
+      if (ch === '"' || ch === "'") {
+        parts.push(this.readString())
+      } else {
+        const match = /^[A-Za-z0-9_-]+/.exec(text.slice(this.index))
+        if (!match) {
+          break
+        }
+        parts.push(match[0])
+        this.index += match[0].length
+      }
+      this.skipSpace(false)
+      if (text[this.index] === '.') {
+        this.index += 1
+        continue
+      }
+      break
+    }
+    return parts.join('.')
+  }
+
+  readValue(): TomlValue {
+    const { text } = this
+    this.skipSpace(false)
+    const ch = text[this.index]
+    if (ch === '"' || ch === "'") {
+      return this.readString()
+    }
+    if (ch === '[') {
+      this.index += 1
+      const values: TomlValue[] = []
+      for (;;) {
+        this.skipSpace(true)
+        if (text[this.index] === ']') {
+          this.index += 1
+          return values
+        }
+        if (this.index >= text.length) {
+          return values
+        }
+        values.push(this.readValue())
+        this.skipSpace(true)
+        if (text[this.index] === ',') {
+          this.index += 1
+        }
+      }
+    }
+    if (ch === '{') {
+      this.index += 1
+      const table: Record<string, TomlValue> = {}
+      for (;;) {
+        this.skipSpace(false)
+        if (text[this.index] === '}' || this.index >= text.length) {
+          this.index += 1
+          return table
+        }
+        const key = this.readKey()
+        this.skipSpace(false)
+        if (text[this.index] === '=') {
+          this.index += 1
+        }
+        table[key] = this.readValue()
+        this.skipSpace(false)
+        if (text[this.index] === ',') {
+          this.index += 1
+        }
+      }
+    }
+    const match = /^[^\s,\]}#]+/.exec(text.slice(this.index))
+    const raw = match?.[0] ?? ''
+    this.index += raw.length
+    if (raw === 'true' || raw === 'false') {
+      return raw === 'true'
+    }
+    const num = Number(raw.replaceAll('_', ''))
+    return Number.isNaN(num) ? raw : num
+  }
+}
+
+/**
+ * Parse TOML into its tables in document order. Top level keys are returned
+ * under the empty header.
+ */
+export function parseTomlTables(content: string): TomlTable[] {
+  const reader = new TomlReader(content)
+  const tables: TomlTable[] = []
+  let current: TomlTable = { header: '', isArray: false, entries: {} }
+  tables.push(current)
+  const { text } = reader
+  while (reader.index < text.length) {
+    reader.skipSpace(true)
+    if (reader.index >= text.length) {
+      break
+    }
+    if (text[reader.index] === '[') {
+      const isArray = text[reader.index + 1] === '['
+      reader.index += isArray ? 2 : 1
+      const header = reader.readKey()
+      reader.skipSpace(false)
+      reader.index += isArray ? 2 : 1
+      current = { header, isArray, entries: {} }
+      tables.push(current)
+      continue
+    }
+    const start = reader.index
+    const key = reader.readKey()
+    reader.skipSpace(false)
+    if (!key || text[reader.index] !== '=') {
+      // Skip lines we do not understand rather than failing the whole file.
+      const newline = text.indexOf('\n', Math.max(start, reader.index))
+      reader.index = newline === -1 ? text.length : newline + 1
+      continue
+    }
+    reader.index += 1
+    current.entries[key] = reader.readValue()
+  }
+  return tables
+}
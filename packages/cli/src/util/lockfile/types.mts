@@ -0,0 +1,81 @@
+/**
+ * Shared model for the local lockfile parsers.
+ *
+ * Every parser turns one manifest or lockfile into a flat list of resolved
+ * packages. Edges between packages are expressed through `dependencies`,
+ * which reference the `id` of other entries in the same parsed lockfile.
+ */
+
+import type { PURL_Type } from '../ecosystem/types.mts'
+
+export type LockfileHash = {
+  // CycloneDX hash algorithm name, e.g. `SHA-256` or `SHA-512`.
+  alg: string
+  // Hex encoded digest.
+  content: string
+}
+
+export type LockfilePatch = {
+  // Patch file, relative to the lockfile directory.
+  path: string
+  // Contents of the patch file, when it could be read.
+  content?: string | undefined
+}
+
+export type LockfileDependency = {
+  // Unique within the parsed lockfile. Referenced by `dependencies`.
+  id: string
+  type: PURL_Type
+  namespace?: string | undefined
+  name: string
+  version: string
+  qualifiers?: Record<string, string> | undefined
+  // Declared directly by the project (or one of its workspace members).
+  direct?: boolean | undefined
+  // Only needed for development, tests or build tooling.
+  dev?: boolean | undefined
+  // Ecosystem specific scopes, e.g. Gradle configurations.
+  scopes?: string[] | undefined
+  // Environment markers limiting where the package is installed, e.g. the
+  // PEP 508 marker `sys_platform == 'win32'`.
+  markers?: string[] | undefined
+  // Workspace members that pull the package in.
+  workspaces?: string[] | undefined
+  hashes?: LockfileHash[] | undefined
+  // Local patches applied on top of the published version, e.g. Yarn `patch:`
+  // dependencies. The purl stays that of the published package.
+  patches?: LockfilePatch[] | undefined
+  dependencies?: string[] | undefined
+}
+
+export type ParsedLockfile = {
+  ecosystem: PURL_Type
+  // Path of the parsed file, relative to the scan cwd.
+  file: string
+  dependencies: LockfileDependency[]
+  // Set when the result depends on files the Socket API will not see, e.g. a
+  // Go `replace` directive that points at a local directory. Such lockfiles
+  // are uploaded as a generated SBOM rather than as-is.
+  requiresLocalResolution?: boolean | undefined
+}
+
+export type LockfileParseContext = {
+  // Absolute path of the file being parsed.
+  filepath: string
+  // Reads a sibling file, returning undefined when it does not exist.
+  readFile: (filepath: string) => string | Buffer | undefined
+  // Lists the subdirectories of a directory, e.g. to expand workspace globs.
+  listDir?: ((dir: string) => string[]) | undefined
+}
+
+export type LockfileParser = {
+  ecosystem: PURL_Type
+  // Basenames handled by this parser.
+  filenames: readonly string[]
+  // Lowercase file extensions handled by this parser, e.g. `.csproj`.
+  extensions?: readonly string[] | undefined
+  parse: (
+    content: string | Buffer,
+    context: LockfileParseContext,
+  ) => Omit<ParsedLockfile, 'file'> | undefined
+}
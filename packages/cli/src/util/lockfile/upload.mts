@@ -0,0 +1,84 @@
+/**
+ * Prepare locally parsed lockfiles for a full scan upload.
+ *
+ * Key Functions: - prepareLockfilesForUpload: Parse the lockfiles in scanPaths
+ * that need local handling, write them as a single
+ * `.socket.lockfiles.cdx.json` SBOM in cwd and swap it in for the raw files.
+ *
+ * Integration: - Called from handleCreateNewScan right before the upload,
+ * mirroring compressSocketFactsForUpload. The SBOM lives in cwd so the
+ * multipart entry name stays inside the upload root.
+ */
+
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import { isLocalLockfilePath, parseLockfile } from './parsers.mts'
+import { lockfilesToCycloneDx } from './sbom.mts'
+import { DOT_SOCKET_DOT_LOCKFILES_CDX_JSON } from '../../constants/paths.mts'
+
+import type { ParsedLockfile } from './types.mts'
+
+export type LockfileScanPaths = {
+  cleanup: () => Promise<void>
+  lockfiles: ParsedLockfile[]
+  paths: string[]
+}
+
+export type PrepareLockfilesForUploadOptions = {
+  cwd?: string | undefined
+  // Whether the Socket API ingests the file as-is. Files it ingests are only
+  // converted when their parser needs local resolution.
+  isSupportedByApi?: ((filepath: string) => boolean) | undefined
+}
+
+export async function prepareLockfilesForUpload(
+  scanPaths: string[],
+  options?: PrepareLockfilesForUploadOptions | undefined,
+): Promise<LockfileScanPaths> {
+  const { cwd = process.cwd(), isSupportedByApi } = {
+    __proto__: null,
+    ...options,
+  } as PrepareLockfilesForUploadOptions
+
+  const lockfiles: ParsedLockfile[] = []
+  const paths: string[] = []
+  for (let i = 0, { length } = scanPaths; i < length; i += 1) {
+    const p = scanPaths[i]!
+    if (!isLocalLockfilePath(p)) {
+      paths.push(p)
+      continue
+    }
+    const supported = !!isSupportedByApi?.(p)
+    const parsed = parseLockfile(p, cwd)
+    if (parsed && (!supported || parsed.requiresLocalResolution)) {
+      lockfiles.push(parsed)
+    } else if (supported) {
+      paths.push(p)
+    }
+  }
+
+  let sbomPath: string | undefined
+  if (lockfiles.length) {
+    sbomPath = path.join(cwd, DOT_SOCKET_DOT_LOCKFILES_CDX_JSON)
+    const bom = lockfilesToCycloneDx(lockfiles)
+    await fs.writeFile(sbomPath, `${JSON.stringify(bom, null, 2)}\n`, 'utf8')
+    paths.push(sbomPath)
+  }
+
+  const cleanup = async () => {
+    if (sbomPath) {
+      const target = sbomPath
+      sbomPath = undefined
+      await safeDelete(target, { force: true })
+    }
+  }
+  return {
+    __proto__: null,
+    cleanup,
+    lockfiles,
+    paths,
+  } as LockfileScanPaths
+}
@@ -0,0 +1,181 @@
+/**
+ * `uv.lock` support. Packages are identified by name, version and source;
+ * requirements only name the version and source when the name alone is
+ * ambiguous. Editable and virtual sources are the workspace members.
+ */
+
+import {
+  PYTHON_DEFAULT_GROUP,
+  getPythonHash,
+  getStrings,
+  getVcsQualifiers,
+  isTomlTable,
+  walkPythonLock,
+} from './python.mts'
+import { parseTomlTables } from './toml.mts'
+
+import type { PythonLockEdge, PythonLockPackage } from './python.mts'
+import type { TomlValue } from './toml.mts'
+import type { LockfileDependency, LockfileParser } from './types.mts'
+
+export const UV_LOCK = 'uv.lock'
+
+const PYPI_INDEX_URLS = new Set([
+  'https://pypi.org/simple',
+  'https://pypi.org/simple/',
+])
+
+type UvSource = { [key: string]: TomlValue }
+
+function getUvSourceKey(source: TomlValue | undefined): string {
+  return isTomlTable(source)
+    ? Object.keys(source)
+        .sort()
+        .map(key => `${key}=${String(source[key])}`)
+        .join('&')
+    : ''
+}
+
+function isUvLocalSource(source: UvSource | undefined): boolean {
+  return (
+    !source ||
+    'directory' in source ||
+    'editable' in source ||
+    'path' in source ||
+    'virtual' in source
+  )
+}
+
+function getUvQualifiers(
+  source: UvSource | undefined,
+): Record<string, string> | undefined {
+  const { git, registry, url } = { __proto__: null, ...source } as UvSource
+  if (typeof git === 'string') {
+    return getVcsQualifiers(git)
+  }
+  if (typeof url === 'string') {
+    return { download_url: url }
+  }
+  if (typeof registry === 'string' && !PYPI_INDEX_URLS.has(registry)) {
+    return { repository_url: registry }
+  }
+  return undefined
+}
+
+type UvLockEntry = {
+  entries: Record<string, TomlValue>
+  groups: Record<string, TomlValue>
+  optional: Record<string, TomlValue>
+}
+
+export function parseUvLockDependencies(
+  content: string | Buffer,
+): LockfileDependency[] {
+  const tables = parseTomlTables(content.toString())
+  const lockEntries: UvLockEntry[] = []
+  for (let i = 0, { length } = tables; i < length; i += 1) {
+    const { entries, header, isArray } = tables[i]!
+    const current = lockEntries.at(-1)
+    if (header === 'package' && isArray) {
+      lockEntries.push({ entries, groups: {}, optional: {} })
+    } else if (current && header === 'package.optional-dependencies') {
+      current.optional = entries
+    } else if (current && header === 'package.dev-dependencies') {
+      current.groups = entries
+    }
+  }
+
+  const packages: PythonLockPackage[] = []
+  const parsed: Array<{ lockEntry: UvLockEntry; pkg: PythonLockPackage }> =
+    []
+  const byName = new Map<
+    string,
+    Array<{ pkg: PythonLockPackage; sourceKey: string }>
+  >()
+  for (let i = 0, { length } = lockEntries; i < length; i += 1) {
+    const lockEntry = lockEntries[i]!
+    const { entries } = lockEntry
+    const { name, sdist, version = '' } = entries
+    const source = isTomlTable(entries['source'])
+      ? entries['source']
+      : undefined
+    if (typeof name !== 'string' || typeof version !== 'string') {
+      continue
+    }
+    const sourceKey = getUvSourceKey(source)
+    const qualifiers = getUvQualifiers(source)
+    const hash = getPythonHash(isTomlTable(sdist) ? sdist['hash'] : undefined)
+    const resolutionMarkers = getStrings(entries['resolution-markers'])
+    const pkg: PythonLockPackage = {
+      edges: new Map(),
+      ...(hash ? { hashes: [hash] } : {}),
+      id: qualifiers
+        ? `${name}@${version} (${sourceKey})`
+        : `${name}@${version}`,
+      local: isUvLocalSource(source),
+      ...(resolutionMarkers.length ? { markers: resolutionMarkers } : {}),
+      member: !!source && ('editable' in source || 'virtual' in source),
+      name,
+      ...(qualifiers ? { qualifiers } : {}),
+      version,
+    }
+    packages.push(pkg)
+    parsed.push({ lockEntry, pkg })
+    const candidates = byName.get(name) ?? []
+    candidates.push({ pkg, sourceKey })
+    byName.set(name, candidates)
+  }
+
+  // Requirements name the version and source only when the name alone is
+  // ambiguous.
+  const getEdges = (value: TomlValue | undefined): PythonLockEdge[] => {
+    const edges: PythonLockEdge[] = []
+    for (const requirement of Array.isArray(value) ? value : []) {
+      if (!isTomlTable(requirement)) {
+        continue
+      }
+      const { extra, marker, name, source, version } = requirement
+      const target = byName
+        .get(String(name))
+        ?.find(
+          c =>
+            (version === undefined || c.pkg.version === version) &&
+            (source === undefined || c.sourceKey === getUvSourceKey(source)),
+        )?.pkg
+      if (target) {
+        edges.push({
+          extras: getStrings(extra),
+          ...(typeof marker === 'string' && marker ? { marker } : {}),
+          target,
+        })
+      }
+    }
+    return edges
+  }
+
+  const devGroups = new Set<string>()
+  for (let i = 0, { length } = parsed; i < length; i += 1) {
+    const { lockEntry, pkg } = parsed[i]!
+    pkg.edges.set(
+      PYTHON_DEFAULT_GROUP,
+      getEdges(lockEntry.entries['dependencies']),
+    )
+    for (const { 0: extra, 1: value } of Object.entries(lockEntry.optional)) {
+      pkg.edges.set(extra, getEdges(value))
+    }
+    for (const { 0: group, 1: value } of Object.entries(lockEntry.groups)) {
+      devGroups.add(group)
+      pkg.edges.set(group, getEdges(value))
+    }
+  }
+  return walkPythonLock(packages, devGroups)
+}
+
+export const uvLockfileParser: LockfileParser = {
+  ecosystem: 'pypi',
+  filenames: [UV_LOCK],
+  parse: content => ({
+    ecosystem: 'pypi',
+    dependencies: parseUvLockDependencies(content),
+  }),
+}
@@ -0,0 +1,120 @@
+/**
+ * Minimal XML reader for manifests and lockfile metadata (Gradle
+ * verification-metadata.xml, MSBuild project files).
+ *
+ * Elements, attributes, text and CDATA are supported. Comments, processing
+ * instructions and doctypes are skipped, namespace prefixes are kept as part
+ * of the element name, and malformed input is read leniently rather than
+ * rejected.
+ */
+
+export type XmlElement = {
+  name: string
+  attrs: Record<string, string>
+  children: XmlElement[]
+  text: string
+}
+
+const XML_ENTITIES = {
+  __proto__: null,
+  amp: '&',
+  apos: "'",
+  gt: '>',
+  lt: '<',
+  quot: '"',
+} as unknown as Record<string, string | undefined>
+
+const XML_TOKEN_REGEX =
+  /<!--[\s\S]*?-->|<!\[CDATA\[([\s\S]*?)\]\]>|<[?!][^>]*>|<(\/?)([\w.:-]+)((?:\s+[\w.:-]+\s*=\s*(?:"[^"]*"|'[^']*'))*)\s*(\/?)>/g
+
+const XML_ATTR_REGEX = /([\w.:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')/g
+
+export function decodeXmlEntities(value: string): string {
+  return value.replace(
+    /&(#x[0-9a-fA-F]+|#\d+|\w+);/g,
+    (match, entity: string) => {
+      if (entity.startsWith('#')) {
+        const code =
+          entity[1] === 'x'
+            ? Number.parseInt(entity.slice(2), 16)
+            : Number.parseInt(entity.slice(1), 10)
+        return Number.isNaN(code) ? match : String.fromCodePoint(code)
+      }
+      return XML_ENTITIES[entity] ?? match
+    },
+  )
+}
+
+function parseAttrs(raw: string | undefined): Record<string, string> {
+  const attrs: Record<string, string> = {}
+  if (raw) {
+    for (const match of raw.matchAll(XML_ATTR_REGEX)) {
+      attrs[match[1]!] = decodeXmlEntities(match[2] ?? match[3] ?? '')
+    }
+  }
+  return attrs
+}
+
+/**
+ * Parse XML into an element tree. The returned element is a nameless document
+ * node whose children are the top level elements.
+ */
+export function parseXml(content: string): XmlElement {
+  const root: XmlElement = { name: '', attrs: {}, children: [], text: '' }
+  const stack = [root]
+  let lastIndex = 0
+  for (const match of content.matchAll(XML_TOKEN_REGEX)) {
+    const current = stack.at(-1)!
+    current.text += decodeXmlEntities(content.slice(lastIndex, match.index))
+    lastIndex = match.index + match[0].length
+    const { 1: cdata, 2: closing, 3: name, 4: rawAttrs, 5: selfClosing } = match
+    if (cdata !== undefined) {
+      current.text += cdata
+      continue
+    }
+    if (!name) {
+      continue
+    }
+    if (closing) {
+      // Pop back to the matching element so a stray close tag cannot unwind
+      // the whole tree.
+      const index = stack.findLastIndex(el => el.name === name)
+      if (index > 0) {
+        stack.length = index
+      }
+      continue
+    }
+    const element: XmlElement = {
+      name,
+      attrs: parseAttrs(rawAttrs),
+      children: [],
+      text: '',
+    }
+    current.children.push(element)
+    if (!selfClosing) {
+      stack.push(element)
+    }
+  }
+  return root
+}
+
+/**
+ * All descendants of `element` named `name`, in document order.
+ */
+export function findXmlElements(
+  element: XmlElement,
+  name: string,
+): XmlElement[] {
+  const found: XmlElement[] = []
+  const queue = [...element.children].reverse()
+  while (queue.length) {
+    const el = queue.pop()!
+    if (el.name === name) {
+      found.push(el)
+    }
+    for (let i = el.children.length - 1; i >= 0; i -= 1) {
+      queue.push(el.children[i]!)
+    }
+  }
+  return found
+}
@@ -0,0 +1,406 @@
+/**
+ * Yarn Berry (v2 and newer) support: `yarn.lock` with `__metadata`.
+ *
+ * Entries are keyed by the descriptors (`name@npm:^1.0.0`) that resolve to
+ * them and name their `resolution` locator. Every workspace (`workspace:`) is
+ * walked from its entry, so each package lists the workspaces that pull it in;
+ * the `devDependencies` of their `package.json` tell dev dependencies apart.
+ * The `resolutions` of the root `package.json` override descriptors the way
+ * Yarn applies them, as the lockfile keeps the original ranges.
+ *
+ * `patch:` packages keep the purl of the published package they patch, with
+ * the patch files (usually in `.yarn/patches`) attached. Yarn's own `builtin`
+ * compatibility patches do not count. Portals and links point at local
+ * directories; they are left out and their dependencies count as direct.
+ * Workspaces are local too and left out.
+ *
+ * Yarn's `checksum` is that of its cache archive rather than the published
+ * tarball, so no hashes are recorded. Yarn v1 lockfiles are left to the
+ * Socket API, as are Berry lockfiles that need no local resolution.
+ */
+
+import path from 'node:path'
+
+import { parse as yamlParse } from 'yaml'
+
+import { YARN_LOCK } from '@socketsecurity/lib-stable/constants/agents'
+
+import { splitNpmPackageKey, splitNpmPackageName } from './npm.mts'
+
+import type {
+  LockfileDependency,
+  LockfileParseContext,
+  LockfileParser,
+  LockfilePatch,
+} from './types.mts'
+
+export type YarnBerryLockEntry = {
+  resolution?: string | undefined
+  dependencies?: Record<string, string> | undefined
+}
+
+export type YarnBerryLockfile = Record<string, unknown> & {
+  __metadata?: { version?: number | string | undefined } | undefined
+}
+
+export type YarnLocator = {
+  packageName: string
+  // `npm`, `workspace`, `portal`, `link` or another protocol. A `patch:`
+  // locator takes the protocol of the package it patches.
+  protocol: string
+  // Registry version of `npm` packages.
+  version?: string | undefined
+  // Directory of a workspace, portal or link, relative to the lockfile.
+  path?: string | undefined
+  // Patch files, relative to the lockfile.
+  patches?: string[] | undefined
+}
+
+export type YarnResolution = {
+  parentName?: string | undefined
+  name: string
+  range?: string | undefined
+  value: string
+}
+
+type PackageManifest = {
+  dependencies?: Record<string, string> | undefined
+  devDependencies?: Record<string, string> | undefined
+  optionalDependencies?: Record<string, string> | undefined
+  resolutions?: Record<string, string> | undefined
+}
+
+const LOCAL_PROTOCOLS = new Set(['link', 'portal', 'workspace'])
+
+// `optional!builtin<compat/typescript>` (Yarn 4) or `~builtin<compat/fsevents>`
+// (Yarn 3) are patches Yarn ships for Plug'n'Play compatibility.
+const BUILTIN_PATCH_REGEXP = /^(?:optional!)?~?builtin</
+
+// `**/name`, `parent/name`, `parent@range/name`, `name@range`, with scoped
+// names for either part.
+const RESOLUTION_KEY_REGEXP =
+  /^(?:((?:@[^/@]+\/)?[^/@]+)(?:@[^/]+)?\/)?((?:@[^/@]+\/)?[^/@]+)(?:@(.+))?$/
+
+export function isYarnBerryLockfile(content: string | Buffer): boolean {
+  return /^__metadata:/m.test(content.toString())
+}
+
+/**
+ * A descriptor range as it appears in lockfile keys, where the default `npm:`
+ * protocol is spelled out.
+ */
+export function normalizeYarnRange(range: string): string {
+  return /^[a-z][\w+.-]*:/i.test(range) ? range : `npm:${range}`
+}
+
+function stripYarnParams(descriptor: string): string {
+  const index = descriptor.indexOf('::')
+  return index === -1 ? descriptor : descriptor.slice(0, index)
+}
+
+/**
+ * Parse a locator such as `lodash@npm:4.17.21`, `app@workspace:packages/app`
+ * or `lodash@patch:lodash@npm%3A4.17.21#~/.yarn/patches/lodash.patch`.
+ */
+export function parseYarnLocator(locator: string): YarnLocator | undefined {
+  const parts = splitNpmPackageKey(locator)
+  const colonIndex = parts?.version.indexOf(':') ?? -1
+  if (!parts || colonIndex === -1) {
+    return undefined
+  }
+  const { packageName } = parts
+  const protocol = parts.version.slice(0, colonIndex)
+  const reference = parts.version.slice(colonIndex + 1)
+  const paramsIndex = reference.indexOf('::')
+  const source =
+    paramsIndex === -1 ? reference : reference.slice(0, paramsIndex)
+  const params = new URLSearchParams(
+    paramsIndex === -1 ? '' : reference.slice(paramsIndex + 2),
+  )
+  // Relative paths of portals, links and patches start at the workspace that
+  // declared them.
+  const locatorParam = params.get('locator')
+  const baseDir =
+    (locatorParam ? parseYarnLocator(locatorParam)?.path : undefined) ?? '.'
+  if (protocol === 'npm') {
+    return { packageName, protocol, version: source }
+  }
+  if (protocol === 'workspace') {
+    return { packageName, protocol, path: path.posix.normalize(source) }
+  }
+  if (protocol === 'link' || protocol === 'portal') {
+    return {
+      packageName,
+      protocol,
+      path: path.posix.join(baseDir, source),
+    }
+  }
+  if (protocol === 'patch') {
+    const hashIndex = source.indexOf('#')
+    const base = hashIndex === -1 ? source : source.slice(0, hashIndex)
+    const patched = parseYarnLocator(decodeURIComponent(base))
+    if (!patched) {
+      return undefined
+    }
+    const patches = [...(patched.patches ?? [])]
+    const patchPaths = hashIndex === -1 ? '' : source.slice(hashIndex + 1)
+    for (const patchPath of patchPaths.split('&')) {
+      if (patchPath && !BUILTIN_PATCH_REGEXP.test(patchPath)) {
+        patches.push(
+          patchPath.startsWith('~/')
+            ? patchPath.slice(2)
+            : path.posix.isAbsolute(patchPath)
+              ? patchPath
+              : path.posix.join(baseDir, patchPath),
+        )
+      }
+    }
+    return { ...patched, ...(patches.length ? { patches } : {}) }
+  }
+  return { packageName, protocol }
+}
+
+/**
+ * The entries of a `resolutions` field, with ranges normalized.
+ */
+export function parseYarnResolutions(
+  resolutions: Record<string, string> | undefined,
+): YarnResolution[] {
+  const parsed: YarnResolution[] = []
+  for (const { 0: key, 1: value } of Object.entries(resolutions ?? {})) {
+    const match = RESOLUTION_KEY_REGEXP.exec(key.replace(/^\*\*\//, ''))
+    if (match && typeof value === 'string') {
+      parsed.push({
+        ...(match[1] ? { parentName: match[1] } : {}),
+        name: match[2]!,
+        ...(match[3] ? { range: normalizeYarnRange(match[3]) } : {}),
+        value,
+      })
+    }
+  }
+  return parsed
+}
+
+/**
+ * The range a `resolutions` entry replaces a dependency with. Entries scoped
+ * to the parent win over those for a range, which win over name-only ones.
+ */
+export function getYarnResolution(
+  resolutions: YarnResolution[],
+  parentName: string,
+  name: string,
+  range: string,
+): string | undefined {
+  const normalized = normalizeYarnRange(range)
+  const candidates = resolutions.filter(
+    r =>
+      r.name === name &&
+      (r.parentName === undefined || r.parentName === parentName) &&
+      (r.range === undefined || r.range === normalized),
+  )
+  return (
+    candidates.find(r => r.parentName !== undefined) ??
+    candidates.find(r => r.range !== undefined) ??
+    candidates[0]
+  )?.value
+}
+
+function readPackageManifest(
+  context: LockfileParseContext,
+  dir: string,
+): PackageManifest | undefined {
+  const content = context.readFile(
+    path.join(path.dirname(context.filepath), dir, 'package.json'),
+  )
+  if (content !== undefined) {
+    try {
+      return JSON.parse(content.toString()) as PackageManifest
+    } catch {}
+  }
+  return undefined
+}
+
+function readPatches(
+  context: LockfileParseContext,
+  patchPaths: string[],
+): LockfilePatch[] {
+  return patchPaths.map(patchPath => {
+    const content = context.readFile(
+      path.join(path.dirname(context.filepath), patchPath),
+    )
+    return {
+      path: patchPath,
+      ...(content !== undefined ? { content: content.toString() } : {}),
+    }
+  })
+}
+
+export function parseYarnBerryDependencies(
+  lockfile: YarnBerryLockfile,
+  context: LockfileParseContext,
+): LockfileDependency[] {
+  const resolutions = parseYarnResolutions(
+    readPackageManifest(context, '.')?.resolutions,
+  )
+
+  const entries = new Map<string, YarnBerryLockEntry>()
+  const locators = new Map<string, YarnLocator>()
+  const locatorByDescriptor = new Map<string, string>()
+  for (const { 0: key, 1: value } of Object.entries(lockfile)) {
+    const entry = value as YarnBerryLockEntry | null | undefined
+    const resolution = entry?.resolution
+    const locator =
+      typeof resolution === 'string' ? parseYarnLocator(resolution) : undefined
+    if (key === '__metadata' || !locator) {
+      continue
+    }
+    entries.set(resolution!, entry!)
+    locators.set(resolution!, locator)
+    for (const descriptor of key.split(/,\s*/)) {
+      locatorByDescriptor.set(descriptor, resolution!)
+      const stripped = stripYarnParams(descriptor)
+      if (!locatorByDescriptor.has(stripped)) {
+        locatorByDescriptor.set(stripped, resolution!)
+      }
+    }
+  }
+
+  const edges = new Map<string, Array<{ id: string; name: string }>>()
+  for (const { 0: id, 1: entry } of entries) {
+    const { packageName } = locators.get(id)!
+    const children: Array<{ id: string; name: string }> = []
+    for (const { 0: name, 1: value } of Object.entries(
+      entry.dependencies ?? {},
+    )) {
+      // Plain versions may have been read as YAML numbers.
+      const range = String(value)
+      const override = getYarnResolution(resolutions, packageName, name, range)
+      const descriptor = `${name}@${normalizeYarnRange(override ?? range)}`
+      const childId =
+        locatorByDescriptor.get(descriptor) ??
+        locatorByDescriptor.get(stripYarnParams(descriptor))
+      if (childId) {
+        children.push({ id: childId, name })
+      }
+    }
+    edges.set(id, children)
+  }
+
+  const directIds = new Set<string>()
+  const prodIds = new Set<string>()
+  const workspacesById = new Map<string, Set<string>>()
+  for (const { 0: id, 1: locator } of locators) {
+    if (locator.protocol !== 'workspace') {
+      continue
+    }
+    const manifest = readPackageManifest(context, locator.path ?? '.')
+    const visited = { dev: new Set<string>(), prod: new Set<string>() }
+    for (const child of edges.get(id) ?? []) {
+      const isDev =
+        !!manifest?.devDependencies?.[child.name] &&
+        !manifest.dependencies?.[child.name] &&
+        !manifest.optionalDependencies?.[child.name]
+      const seen = isDev ? visited.dev : visited.prod
+      const queue = [{ direct: true, id: child.id }]
+      while (queue.length) {
+        const current = queue.pop()!
+        const { protocol } = locators.get(current.id)!
+        // Other workspaces are walked on their own.
+        if (protocol === 'workspace') {
+          continue
+        }
+        const isLocal = LOCAL_PROTOCOLS.has(protocol)
+        if (current.direct && !isLocal) {
+          directIds.add(current.id)
+        }
+        if (seen.has(current.id)) {
+          continue
+        }
+        seen.add(current.id)
+        if (!isLocal) {
+          if (!isDev) {
+            prodIds.add(current.id)
+          }
+          const workspaces = workspacesById.get(current.id) ?? new Set()
+          workspaces.add(locator.packageName)
+          workspacesById.set(current.id, workspaces)
+        }
+        for (const next of edges.get(current.id) ?? []) {
+          queue.push({ direct: isLocal && current.direct, id: next.id })
+        }
+      }
+    }
+  }
+
+  // Without workspace entries nothing is reachable and every package from
+  // the registry is listed, e.g. the base of a patch is otherwise left out.
+  const walked = workspacesById.size > 0
+  const isListed = (id: string) =>
+    locators.get(id)!.protocol === 'npm' && (!walked || workspacesById.has(id))
+  const dependencies: LockfileDependency[] = []
+  for (const { 0: id, 1: locator } of locators) {
+    if (!isListed(id) || !locator.version) {
+      continue
+    }
+    const workspaces = workspacesById.get(id)
+    const childIds = [
+      ...new Set(
+        (edges.get(id) ?? []).map(child => child.id).filter(isListed),
+      ),
+    ]
+    dependencies.push({
+      id,
+      type: 'npm',
+      ...splitNpmPackageName(locator.packageName),
+      version: locator.version,
+      ...(walked ? { direct: directIds.has(id) } : {}),
+      ...(workspaces ? { dev: !prodIds.has(id) } : {}),
+      ...(workspaces ? { workspaces: [...workspaces] } : {}),
+      ...(locator.patches
+        ? { patches: readPatches(context, locator.patches) }
+        : {}),
+      ...(childIds.length ? { dependencies: childIds } : {}),
+    })
+  }
+  return dependencies
+}
+
+export const yarnLockfileParser: LockfileParser = {
+  ecosystem: 'npm',
+  filenames: [YARN_LOCK],
+  parse: (content, context) => {
+    if (!isYarnBerryLockfile(content)) {
+      return undefined
+    }
+    const lockfile = yamlParse(content.toString()) as YarnBerryLockfile | null
+    if (!lockfile || typeof lockfile !== 'object') {
+      return undefined
+    }
+    const dependencies = parseYarnBerryDependencies(lockfile, context)
+    // Patches and local directories are invisible to the Socket API, and
+    // workspaces need the local walk to attribute dependencies.
+    let workspaceCount = 0
+    let hasLocalPackages = false
+    for (const value of Object.values(lockfile)) {
+      const resolution = (value as YarnBerryLockEntry | null)?.resolution
+      const protocol =
+        typeof resolution === 'string'
+          ? parseYarnLocator(resolution)?.protocol
+          : undefined
+      if (protocol === 'workspace') {
+        workspaceCount += 1
+      } else if (protocol === 'link' || protocol === 'portal') {
+        hasLocalPackages = true
+      }
+    }
+    const requiresLocalResolution =
+      workspaceCount > 1 ||
+      hasLocalPackages ||
+      dependencies.some(dep => dep.patches?.length)
+    return {
+      ecosystem: 'npm',
+      dependencies,
+      ...(requiresLocalResolution ? { requiresLocalResolution } : {}),
+    }
+  },
+}
@@ -0,0 +1,276 @@
+/**
+ * Structured log of a Socket CLI run, for CI debugging and support tickets.
+ *
+ * Off unless --log-level, --log-format or --log-file, or one of the
+ * SOCKET_CLI_LOG_* env vars, is given, so the regular output stays as is.
+ * Records go to stderr, or are appended to the --log-file, one per line:
+ *
+ * - text: `<time> <LEVEL> <message> key=value …`
+ * - json: `{"time":…,"level":…,"msg":…,"traceId":…,…}`
+ *
+ * Every record carries the trace ID of the run, so the records of one run can
+ * be picked out of a shared CI log. API responses also carry the request ID
+ * the Socket API answered with, which support can look up in the backend
+ * logs.
+ */
+
+import { randomUUID } from 'node:crypto'
+import { appendFileSync } from 'node:fs'
+import path from 'node:path'
+import process from 'node:process'
+
+import { errorMessage } from '@socketsecurity/lib-stable/errors/message'
+
+import { getSocketCliLogFile } from '../../env/socket-cli-log-file.mts'
+import { getSocketCliLogFormat } from '../../env/socket-cli-log-format.mts'
+import { getSocketCliLogLevel } from '../../env/socket-cli-log-level.mts'
+
+import type { CResult } from '../../types.mts'
+
+export const LOG_FORMATS = ['json', 'text'] as const
+
+// Ordered from the most to the least severe.
+export const LOG_LEVELS = ['error', 'warn', 'info', 'debug', 'trace'] as const
+
+export type LogFormat = (typeof LOG_FORMATS)[number]
+
+export type LogLevel = (typeof LOG_LEVELS)[number]
+
+export type LogFlags = {
+  logFile?: string | undefined
+  logFormat?: string | undefined
+  logLevel?: string | undefined
+}
+
+export type LogRecord = {
+  level: LogLevel
+  msg: string
+  time: string
+  traceId: string
+  [key: string]: unknown
+}
+
+export type ApiResponseLogInfo = {
+  durationMs?: number | undefined
+  // oxlint-disable-next-line typescript/no-redundant-type-constituents -- fleet optional-explicit-undefined convention: the explicit | undefined on an optional is intentional, not redundant.
+  error?: unknown | undefined
+  // Response headers, the request ID is read from them.
+  headers?: Record<string, unknown> | undefined
+  method?: string | undefined
+  status?: number | undefined
+  url: string
+}
+
+// Response headers naming the request, most specific first.
+const REQUEST_ID_HEADERS = ['x-request-id', 'x-socket-request-id', 'cf-ray']
+
+let logFile: string | undefined
+
+let logFormat: LogFormat = 'text'
+
+// Undefined while the structured log is off.
+let logLevel: LogLevel | undefined
+
+let traceId: string | undefined
+
+let exitListenerAdded = false
+
+function isLogFormat(value: string): value is LogFormat {
+  return (LOG_FORMATS as readonly string[]).includes(value)
+}
+
+function isLogLevel(value: string): value is LogLevel {
+  return (LOG_LEVELS as readonly string[]).includes(value)
+}
+
+function formatTextValue(value: unknown): string {
+  const str = typeof value === 'string' ? value : JSON.stringify(value)
+  return /[\s"=]/.test(str) ? JSON.stringify(str) : str
+}
+
+/**
+ * Take over the log settings of the current invocation, flags first, then
+ * the SOCKET_CLI_LOG_* env vars. Giving only a format or a file logs at the
+ * info level. Fails on an unknown level or format, leaving the log off.
+ */
+export function configureLogging(flags: LogFlags): CResult<undefined> {
+  const opts = { __proto__: null, ...flags } as LogFlags
+  // Reset on every invocation so the settings don't leak into the next one.
+  logFile = undefined
+  logFormat = 'text'
+  logLevel = undefined
+  const level = opts.logLevel || getSocketCliLogLevel()
+  const format = opts.logFormat || getSocketCliLogFormat()
+  const file = opts.logFile || getSocketCliLogFile()
+  if (level && !isLogLevel(level)) {
+    return {
+      ok: false,
+      message: `Invalid ${opts.logLevel ? '--log-level' : 'SOCKET_CLI_LOG_LEVEL'} value`,
+      cause: `Expected one of ${LOG_LEVELS.join(', ')} (saw: ${level})`,
+    }
+  }
+  if (format && !isLogFormat(format)) {
+    return {
+      ok: false,
+      message: `Invalid ${opts.logFormat ? '--log-format' : 'SOCKET_CLI_LOG_FORMAT'} value`,
+      cause: `Expected one of ${LOG_FORMATS.join(', ')} (saw: ${format})`,
+    }
+  }
+  if (!level && !format && !file) {
+    return { ok: true, data: undefined }
+  }
+  logFile = file ? path.resolve(process.cwd(), file) : undefined
+  logFormat = format || 'text'
+  logLevel = level || 'info'
+  return { ok: true, data: undefined }
+}
+
+export function formatLogRecord(record: LogRecord, format: LogFormat): string {
+  if (format === 'json') {
+    return JSON.stringify(record)
+  }
+  const { level, msg, time, ...fields } = record
+  let line = `${time} ${level.toUpperCase().padEnd(5)} ${msg}`
+  for (const { 0: key, 1: value } of Object.entries(fields)) {
+    line += ` ${key}=${formatTextValue(value)}`
+  }
+  return line
+}
+
+/**
+ * The request ID the Socket API, or Cloudflare in front of it, answered with.
+ */
+export function getApiRequestId(
+  headers: Record<string, unknown> | undefined,
+): string | undefined {
+  if (!headers) {
+    return undefined
+  }
+  const byName = new Map(
+    Object.entries(headers).map(({ 0: key, 1: value }) => [
+      key.toLowerCase(),
+      value,
+    ]),
+  )
+  for (const name of REQUEST_ID_HEADERS) {
+    const value = byName.get(name)
+    if (value) {
+      return String(Array.isArray(value) ? value[0] : value)
+    }
+  }
+  return undefined
+}
+
+/**
+ * ID shared by all records of this run.
+ */
+export function getTraceId(): string {
+  traceId ??= randomUUID()
+  return traceId
+}
+
+export function isLogLevelEnabled(level: LogLevel): boolean {
+  return (
+    logLevel !== undefined &&
+    LOG_LEVELS.indexOf(level) <= LOG_LEVELS.indexOf(logLevel)
+  )
+}
+
+/**
+ * Log the response of, or the network error of, a Socket API request.
+ */
+export function logApiResponse(info: ApiResponseLogInfo): void {
+  const { durationMs, error, headers, method, status, url } = {
+    __proto__: null,
+    ...info,
+  } as ApiResponseLogInfo
+  const level: LogLevel =
+    error || (status !== undefined && status >= 500)
+      ? 'error'
+      : status !== undefined && status >= 400
+        ? 'warn'
+        : 'info'
+  logEvent(level, error ? 'api request failed' : 'api response', {
+    method,
+    url,
+    status,
+    durationMs,
+    requestId: getApiRequestId(headers),
+    error: error ? errorMessage(error) : undefined,
+  })
+}
+
+/**
+ * Log the start of a command and, once the process exits, its exit code.
+ */
+export function logCommandStart(command: string): void {
+  if (!isLogLevelEnabled('info')) {
+    return
+  }
+  const startTime = Date.now()
+  logEvent('info', 'command started', {
+    command,
+    nodeVersion: process.version,
+    pid: process.pid,
+  })
+  if (!exitListenerAdded) {
+    exitListenerAdded = true
+    process.once('exit', code => {
+      logEvent(code ? 'error' : 'info', 'command finished', {
+        command,
+        durationMs: Date.now() - startTime,
+        exitCode: code,
+      })
+    })
+  }
+}
+
+/**
+ * Write a record when `level` is enabled. Fields that are undefined are left
+ * out.
+ */
+export function logEvent(
+  level: LogLevel,
+  msg: string,
+  fields?: Record<string, unknown> | undefined,
+): void {
+  if (!isLogLevelEnabled(level)) {
+    return
+  }
+  const record: LogRecord = {
+    time: new Date().toISOString(),
+    level,
+    msg,
+    traceId: getTraceId(),
+  }
+  if (fields) {
+    for (const { 0: key, 1: value } of Object.entries(fields)) {
+      if (value !== undefined) {
+        record[key] = value
+      }
+    }
+  }
+  const line = `${formatLogRecord(record, logFormat)}\n`
+  if (logFile) {
+    try {
+      // Sync, so records written right before process.exit() are kept.
+      appendFileSync(logFile, line)
+      return
+    } catch {
+      // Fall back to stderr rather than losing the record.
+    }
+  }
+  process.stderr.write(line)
+}
+
+/**
+ * Reset the log settings and trace ID for testing purposes.
+ *
+ * @internal
+ */
+export function resetLoggingForTesting(): void {
+  logFile = undefined
+  logFormat = 'text'
+  logLevel = undefined
+  traceId = undefined
+}
@@ -0,0 +1,209 @@
+/**
+ * Signed offline bundles of `socket bundle export` and `socket bundle import`.
+ *
+ * A connected machine packages what the policy checks need, the scan results,
+ * the org security policy and the package score cache, into a gzipped DSSE
+ * envelope signed with a local private key. The air-gapped side verifies it
+ * with the matching public key before importing anything (see ./store.mts).
+ *
+ * Keys are plain PEM files, e.g. made with
+ * `openssl genpkey -algorithm ed25519 -out bundle.key` and
+ * `openssl pkey -in bundle.key -pubout -out bundle.pub`. Ed25519, ECDSA and
+ * RSA keys are accepted.
+ */
+
+import crypto from 'node:crypto'
+import { promises as fs } from 'node:fs'
+import zlib from 'node:zlib'
+
+import { getErrorCause } from '../error/errors.mts'
+import { dssePae } from '../sigstore/dsse.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../alert/artifact.mts'
+import type { DsseEnvelope } from '../sigstore/dsse.mts'
+import type { ScoreCacheFileEntry } from '../socket/score-cache.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+export const OFFLINE_BUNDLE_PAYLOAD_TYPE =
+  'application/vnd.socket.offline-bundle.v1+json'
+
+export const OFFLINE_BUNDLE_VERSION = 1
+
+export const OFFLINE_BUNDLE_DEFAULT_FILENAME = 'socket-bundle.json.gz'
+
+export type SecurityPolicyData =
+  SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+export type OfflineBundleScan = {
+  scan: SocketArtifact[]
+  scanId: string
+}
+
+export type OfflineBundle = {
+  // ISO timestamp of the export.
+  createdAt: string
+  orgSlug: string
+  scans: OfflineBundleScan[]
+  scoreCache: ScoreCacheFileEntry[]
+  securityPolicy: SecurityPolicyData
+  version: typeof OFFLINE_BUNDLE_VERSION
+}
+
+/**
+ * Hex SHA-256 of the DER encoded public key, so `socket bundle import` can
+ * tell which key an envelope was signed with.
+ */
+export function getBundleKeyId(key: crypto.KeyObject): string {
+  const publicKey = key.type === 'private' ? crypto.createPublicKey(key) : key
+  return crypto
+    .createHash('sha256')
+    .update(publicKey.export({ format: 'der', type: 'spki' }))
+    .digest('hex')
+}
+
+// Ed25519 and Ed448 hash internally, the other key types sign a SHA-256.
+function getSignAlgorithm(key: crypto.KeyObject): string | null {
+  const { asymmetricKeyType } = key
+  return asymmetricKeyType === 'ed25519' || asymmetricKeyType === 'ed448'
+    ? null
+    : 'sha256'
+}
+
+export function signOfflineBundle(
+  bundle: OfflineBundle,
+  privateKeyPem: string,
+): CResult<DsseEnvelope> {
+  let key: crypto.KeyObject
+  try {
+    key = crypto.createPrivateKey(privateKeyPem)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Invalid signing key',
+      cause: `The signing key is not a PEM private key: ${getErrorCause(e)}`,
+    }
+  }
+  const payload = Buffer.from(JSON.stringify(bundle), 'utf8')
+  const sig = crypto.sign(
+    getSignAlgorithm(key),
+    dssePae(OFFLINE_BUNDLE_PAYLOAD_TYPE, payload),
+    key,
+  )
+  return {
+    ok: true,
+    data: {
+      payload: payload.toString('base64'),
+      payloadType: OFFLINE_BUNDLE_PAYLOAD_TYPE,
+      signatures: [{ keyid: getBundleKeyId(key), sig: sig.toString('base64') }],
+    },
+  }
+}
+
+/**
+ * Check the signature with the public key and return the bundle inside.
+ */
+export function verifyOfflineBundle(
+  envelope: DsseEnvelope,
+  publicKeyPem: string,
+): CResult<OfflineBundle> {
+  let key: crypto.KeyObject
+  try {
+    key = crypto.createPublicKey(publicKeyPem)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Invalid public key',
+      cause: `The public key is not a PEM public key: ${getErrorCause(e)}`,
+    }
+  }
+  if (envelope.payloadType !== OFFLINE_BUNDLE_PAYLOAD_TYPE) {
+    return {
+      ok: false,
+      message: 'Not an offline bundle',
+      cause: `Expected payload type ${OFFLINE_BUNDLE_PAYLOAD_TYPE} (saw: ${envelope.payloadType})`,
+    }
+  }
+  const keyId = getBundleKeyId(key)
+  const payload = Buffer.from(envelope.payload, 'base64')
+  const pae = dssePae(envelope.payloadType, payload)
+  const verified = envelope.signatures.some(({ keyid, sig }) => {
+    if (keyid && keyid !== keyId) {
+      return false
+    }
+    try {
+      return crypto.verify(
+        getSignAlgorithm(key),
+        pae,
+        key,
+        Buffer.from(sig, 'base64'),
+      )
+    } catch {
+      // Malformed signature for the key type.
+      return false
+    }
+  })
+  if (!verified) {
+    return {
+      ok: false,
+      message: 'Bundle signature mismatch',
+      cause: `No signature of the bundle verifies with the public key ${keyId.slice(0, 16)}. The bundle was changed after export or signed with another key.`,
+    }
+  }
+  let bundle: OfflineBundle
+  try {
+    bundle = JSON.parse(payload.toString('utf8')) as OfflineBundle
+  } catch {
+    return {
+      ok: false,
+      message: 'Invalid offline bundle',
+      cause: 'The signed payload is not valid JSON',
+    }
+  }
+  if (bundle?.version !== OFFLINE_BUNDLE_VERSION) {
+    return {
+      ok: false,
+      message: 'Unsupported offline bundle',
+      cause: `This CLI reads bundle version ${OFFLINE_BUNDLE_VERSION} (saw: ${bundle?.version}). Export the bundle with the same CLI version.`,
+    }
+  }
+  return { ok: true, data: bundle }
+}
+
+export async function writeOfflineBundleArchive(
+  filepath: string,
+  envelope: DsseEnvelope,
+): Promise<void> {
+  await fs.writeFile(
+    filepath,
+    zlib.gzipSync(Buffer.from(JSON.stringify(envelope), 'utf8')),
+  )
+}
+
+export async function readOfflineBundleArchive(
+  filepath: string,
+): Promise<CResult<DsseEnvelope>> {
+  let envelope: DsseEnvelope
+  try {
+    const buffer = await fs.readFile(filepath)
+    envelope = JSON.parse(zlib.gunzipSync(buffer).toString('utf8'))
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Unable to read offline bundle',
+      cause: `${filepath} is not a gzipped offline bundle: ${getErrorCause(e)}`,
+    }
+  }
+  if (
+    typeof envelope?.payload !== 'string' ||
+    typeof envelope.payloadType !== 'string' ||
+    !Array.isArray(envelope.signatures)
+  ) {
+    return {
+      ok: false,
+      message: 'Invalid offline bundle',
+      cause: `${filepath} does not hold a DSSE envelope`,
+    }
+  }
+  return { ok: true, data: envelope }
+}
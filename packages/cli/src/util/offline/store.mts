@@ -0,0 +1,117 @@
+/**
+ * Imported offline bundles, read by `--offline` instead of the Socket API.
+ *
+ * `socket bundle import` writes every scan and the org security policy of a
+ * verified bundle to one JSON file each under `<socket cache>/offline/<org>/`
+ * and the score entries to the score cache (see util/socket/score-cache.mts).
+ * Importing a newer bundle of the same org replaces the files it carries.
+ */
+
+import path from 'node:path'
+
+import { readJson } from '@socketsecurity/lib-stable/fs/read-json'
+import { safeMkdir } from '@socketsecurity/lib-stable/fs/safe'
+import { writeJson } from '@socketsecurity/lib-stable/fs/write-json'
+
+import { getOfflineBundlePath } from '../../constants/paths.mts'
+import { writeScoreCache } from '../socket/score-cache.mts'
+
+import type { OfflineBundle, SecurityPolicyData } from './bundle.mts'
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../alert/artifact.mts'
+import type { JsonContent } from '@socketsecurity/lib-stable/fs/types'
+
+export type OfflineBundleImport = {
+  createdAt: string
+  orgSlug: string
+  scanIds: string[]
+  scoreEntries: number
+}
+
+type OfflineScanFile = {
+  createdAt: string
+  scan: SocketArtifact[]
+}
+
+type OfflinePolicyFile = {
+  createdAt: string
+  securityPolicy: SecurityPolicyData
+}
+
+function getOfflineOrgPath(orgSlug: string): string {
+  return path.join(getOfflineBundlePath(), encodeURIComponent(orgSlug))
+}
+
+export function getOfflineScanPath(orgSlug: string, scanId: string): string {
+  return path.join(
+    getOfflineOrgPath(orgSlug),
+    'scans',
+    `${encodeURIComponent(scanId)}.json`,
+  )
+}
+
+export function getOfflinePolicyPath(orgSlug: string): string {
+  return path.join(getOfflineOrgPath(orgSlug), 'security-policy.json')
+}
+
+export async function importOfflineBundle(
+  bundle: OfflineBundle,
+): Promise<OfflineBundleImport> {
+  const { createdAt, orgSlug, scans, scoreCache, securityPolicy } = bundle
+  await safeMkdir(path.join(getOfflineOrgPath(orgSlug), 'scans'), {
+    recursive: true,
+  })
+  for (let i = 0, { length } = scans; i < length; i += 1) {
+    const { scan, scanId } = scans[i]!
+    await writeJson(getOfflineScanPath(orgSlug, scanId), {
+      createdAt,
+      scan,
+    } satisfies OfflineScanFile as unknown as JsonContent)
+  }
+  await writeJson(getOfflinePolicyPath(orgSlug), {
+    createdAt,
+    securityPolicy,
+  } satisfies OfflinePolicyFile as unknown as JsonContent)
+  for (let i = 0, { length } = scoreCache; i < length; i += 1) {
+    const { data, kind, purl, timestamp } = scoreCache[i]!
+    await writeScoreCache(kind, purl, data, timestamp)
+  }
+  return {
+    createdAt,
+    orgSlug,
+    scanIds: scans.map(s => s.scanId),
+    scoreEntries: scoreCache.length,
+  }
+}
+
+/**
+ * The `fetchScanData` result of an imported scan.
+ */
+export async function readOfflineScanData(
+  orgSlug: string,
+  scanId: string,
+): Promise<
+  CResult<{ scan: SocketArtifact[]; securityPolicy: SecurityPolicyData }>
+> {
+  let scanFile: OfflineScanFile | undefined
+  let policyFile: OfflinePolicyFile | undefined
+  try {
+    scanFile = (await readJson(getOfflineScanPath(orgSlug, scanId))) as
+      | OfflineScanFile
+      | undefined
+    policyFile = (await readJson(getOfflinePolicyPath(orgSlug))) as
+      | OfflinePolicyFile
+      | undefined
+  } catch {}
+  if (!Array.isArray(scanFile?.scan) || !policyFile?.securityPolicy) {
+    return {
+      ok: false,
+      message: 'Scan not in an offline bundle',
+      cause: `Offline mode is on and no imported bundle of org ${orgSlug} holds scan ${scanId}. Export it with \`socket bundle export --scan ${scanId}\` on a connected machine and run \`socket bundle import\` here, or drop --offline.`,
+    }
+  }
+  return {
+    ok: true,
+    data: { scan: scanFile.scan, securityPolicy: policyFile.securityPolicy },
+  }
+}
@@ -9,6 +9,9 @@
  * CLI is a one-shot process with a single root invocation, so module-scoped
  * state is the simplest correct model. Tests that run multiple invocations in
  * sequence should call resetMachineOutputMode() in their setup.
+ *
+ * --quiet is also tracked on its own: it is the one machine mode that asks
+ * for no progress output at all, so long steps print only their result.
  */
 
 import { isMachineOutputMode } from './mode.mts'
@@ -17,14 +20,22 @@ import type { MachineModeFlags } from './mode.mts'
 
 let ambientMode = false
 
+let quietMode = false
+
 export function getMachineOutputMode(): boolean {
   return ambientMode
 }
 
+export function getQuietOutputMode(): boolean {
+  return quietMode
+}
+
 export function resetMachineOutputMode(): void {
   ambientMode = false
+  quietMode = false
 }
 
 export function setMachineOutputMode(flags: MachineModeFlags): void {
   ambientMode = isMachineOutputMode(flags)
+  quietMode = !!flags.quiet
 }
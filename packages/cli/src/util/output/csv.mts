@@ -0,0 +1,61 @@
+/**
+ * Minimal CSV serialization (RFC 4180) for the `--csv` output of commands
+ * that report one row per package or scan.
+ *
+ * Commands with `--columns` keep their rows as records keyed by column name
+ * and pick the requested columns, in order, when serializing.
+ */
+
+import { cmdFlagValueToArray } from '../process/cmd.mts'
+
+export function escapeCsvField(value: string): string {
+  return /[",\r\n]/.test(value) ? `"${value.replace(/"/g, '""')}"` : value
+}
+
+export type FormatCsvOptions = {
+  // Print the column names as the first line, see --no-csv-header.
+  header?: boolean | undefined
+}
+
+export function formatCsv(
+  columns: readonly string[],
+  rows: ReadonlyArray<readonly string[]>,
+  { header = true }: FormatCsvOptions = {},
+): string {
+  return (header ? [columns, ...rows] : rows)
+    .map(cells => cells.map(escapeCsvField).join(','))
+    .join('\n')
+}
+
+export function formatCsvRecords<C extends string>(
+  columns: readonly C[],
+  records: ReadonlyArray<Readonly<Record<C, string>>>,
+  options?: FormatCsvOptions | undefined,
+): string {
+  return formatCsv(
+    columns,
+    records.map(record => columns.map(c => record[c])),
+    options,
+  )
+}
+
+/**
+ * The columns requested with --columns, or the defaults when not given.
+ */
+export function getCsvColumns(
+  flagValue: unknown,
+  defaults: readonly string[],
+): string[] {
+  const columns = cmdFlagValueToArray(flagValue)
+  return columns.length ? columns : [...defaults]
+}
+
+/**
+ * The requested columns that are not available, for input validation.
+ */
+export function getUnknownCsvColumns(
+  columns: readonly string[],
+  available: readonly string[],
+): string[] {
+  return columns.filter(c => !available.includes(c))
+}
@@ -0,0 +1,56 @@
+/**
+ * Helpers for self-contained HTML reports. Reports embed their styles,
+ * scripts and data so they can be archived as CI artifacts and opened
+ * offline.
+ */
+
+export function escapeHtml(value: string | number | boolean): string {
+  return String(value)
+    .replaceAll('&', '&amp;')
+    .replaceAll('<', '&lt;')
+    .replaceAll('>', '&gt;')
+    .replaceAll('"', '&quot;')
+    .replaceAll("'", '&#39;')
+}
+
+/**
+ * Serialize data for an inline `<script type="application/json">` element.
+ * `<` is escaped so no value can close the element early.
+ */
+export function toScriptJson(value: unknown): string {
+  return JSON.stringify(value)
+    .replaceAll('<', '\\u003c')
+    .replaceAll('\u2028', '\\u2028')
+    .replaceAll('\u2029', '\\u2029')
+}
+
+export type HtmlDocument = {
+  body: string
+  script?: string | undefined
+  styles?: string | undefined
+  title: string
+}
+
+export function renderHtmlDocument({
+  body,
+  script,
+  styles,
+  title,
+}: HtmlDocument): string {
+  return [
+    '<!DOCTYPE html>',
+    '<html lang="en">',
+    '<head>',
+    '<meta charset="utf-8">',
+    '<meta name="viewport" content="width=device-width, initial-scale=1">',
+    `<title>${escapeHtml(title)}</title>`,
+    ...(styles ? [`<style>${styles}</style>`] : []),
+    '</head>',
+    '<body>',
+    body,
+    ...(script ? [`<script>${script}</script>`] : []),
+    '</body>',
+    '</html>',
+    '',
+  ].join('\n')
+}
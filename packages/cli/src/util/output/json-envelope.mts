@@ -0,0 +1,109 @@
+/**
+ * Versioned envelope for `--json` output.
+ *
+ * Every command prints its result as
+ * `{ version, command, ok, data }` or `{ version, command, ok, message, cause }`.
+ * The `version` only changes when the envelope or a published `data` schema
+ * changes incompatibly, so tooling can pin it. `socket schema print <command>`
+ * prints the JSON Schema of a command's output.
+ *
+ * MeowOrExit records the running command once at argv-parse time, the same
+ * way it sets the ambient machine-output mode.
+ *
+ * Data schemas live in data/command-json-schemas.json under the keys of
+ * data/command-api-requirements.json. Commands that pass a Socket API
+ * response through have an empty schema: their `data` follows the API.
+ */
+
+import schemas from '../../../data/command-json-schemas.json' with { type: 'json' }
+import { getRequirementsKey } from '../ecosystem/requirements.mts'
+
+export const JSON_OUTPUT_VERSION: string = schemas.version
+
+export const JSON_SCHEMA_DIALECT =
+  'https://json-schema.org/draft/2020-12/schema'
+
+let ambientCommand: string | undefined
+
+export function getJsonOutputCommand(): string | undefined {
+  return ambientCommand
+}
+
+export function resetJsonOutputCommand(): void {
+  ambientCommand = undefined
+}
+
+/**
+ * Record the running command, e.g. `socket scan report`, as `scan report`.
+ */
+export function setJsonOutputCommand(command: string): void {
+  ambientCommand = command.replace(/^socket /, '')
+}
+
+/**
+ * Wrap a serializable result in the envelope of the running command. Results
+ * produced outside a command run (direct calls in tests, library use) are
+ * returned as is.
+ */
+export function toJsonEnvelope<T extends object>(result: T): T {
+  if (ambientCommand === undefined || Array.isArray(result)) {
+    return result
+  }
+  return {
+    version: JSON_OUTPUT_VERSION,
+    command: ambientCommand,
+    ...result,
+  }
+}
+
+/**
+ * Commands with `--json` output, as space separated paths.
+ */
+export function getJsonOutputCommands(): string[] {
+  return Object.keys(schemas.commands).map(key => key.replaceAll(':', ' '))
+}
+
+/**
+ * JSON Schema of the `--json` output of a command, or undefined when the
+ * command has no JSON output. Accepts `scan report`, `scan:report` or
+ * `socket scan report`.
+ */
+export function getJsonOutputSchema(
+  command: string,
+): Record<string, unknown> | undefined {
+  const key = getRequirementsKey(command.trim())
+  const dataSchema = (
+    schemas.commands as Record<string, Record<string, unknown> | undefined>
+  )[key]
+  if (!dataSchema) {
+    return undefined
+  }
+  const commandPath = key.replaceAll(':', ' ')
+  return {
+    $schema: JSON_SCHEMA_DIALECT,
+    title: `socket ${commandPath} --json`,
+    type: 'object',
+    properties: {
+      version: { const: JSON_OUTPUT_VERSION },
+      command: { const: commandPath },
+      ok: { type: 'boolean' },
+      data: Object.keys(dataSchema).length
+        ? dataSchema
+        : { description: 'The Socket API response, unchanged' },
+      message: { type: 'string' },
+      cause: { type: 'string' },
+      code: { type: 'integer' },
+    },
+    required: ['version', 'command', 'ok'],
+    oneOf: [
+      {
+        properties: { ok: { const: true } },
+        required: ['data'],
+      },
+      {
+        properties: { ok: { const: false } },
+        required: ['message'],
+      },
+    ],
+  }
+}
@@ -0,0 +1,83 @@
+/**
+ * JUnit XML rendering for CI test reporters (Jenkins, CircleCI, GitLab,
+ * Azure Pipelines). Findings are reported as test cases: a failing case
+ * carries a `<failure>` with the finding details, a passing case may carry
+ * them as `<system-out>`.
+ *
+ * Reporters reject documents without test cases, so an empty suite gets a
+ * single passing case instead.
+ */
+
+import { renderXmlDocument } from './xml.mts'
+
+import type { XmlNode } from './xml.mts'
+
+export type JunitTestCase = {
+  classname: string
+  failure?:
+    | {
+        details: string
+        message: string
+        type: string
+      }
+    | undefined
+  name: string
+  systemOut?: string | undefined
+}
+
+export type JunitSuite = {
+  // Name of the passing case that stands in for an empty suite.
+  emptyCaseName: string
+  name: string
+  testCases: JunitTestCase[]
+  timestamp?: Date | undefined
+}
+
+function toTestCaseNode(testCase: JunitTestCase): XmlNode {
+  const { classname, failure, name, systemOut } = testCase
+  const children: XmlNode[] = []
+  if (failure) {
+    children.push({
+      name: 'failure',
+      attrs: { message: failure.message, type: failure.type },
+      text: failure.details,
+    })
+  }
+  if (systemOut) {
+    children.push({ name: 'system-out', text: systemOut })
+  }
+  return {
+    name: 'testcase',
+    attrs: { classname, name, time: 0 },
+    children,
+  }
+}
+
+export function renderJunitReport(suite: JunitSuite): string {
+  const { emptyCaseName, name, timestamp = new Date() } = suite
+  const testCases = suite.testCases.length
+    ? suite.testCases
+    : [{ classname: name, name: emptyCaseName }]
+  const tests = testCases.length
+  const failures = testCases.filter(c => c.failure).length
+  return renderXmlDocument({
+    name: 'testsuites',
+    attrs: { name, tests, failures, errors: 0, time: 0 },
+    children: [
+      {
+        name: 'testsuite',
+        attrs: {
+          name,
+          tests,
+          failures,
+          errors: 0,
+          skipped: 0,
+          time: 0,
+          // JUnit timestamps carry no zone suffix.
+          timestamp: timestamp.toISOString().slice(0, 19),
+        },
+        children: testCases.map(toTestCaseNode),
+      },
+    ],
+  })
+}
@@ -0,0 +1,168 @@
+/**
+ * Progress of long steps, like collecting manifest files, uploading a scan
+ * and waiting for its analysis.
+ *
+ * On a terminal a step shows on the spinner with the time it has taken, and
+ * once its size is known with a progress bar and an estimate of the time
+ * left. Without a terminal, e.g. in CI logs, each step prints one line when
+ * it starts instead of redrawing. Under --quiet nothing is printed, so only
+ * the final result is.
+ */
+
+import { getCI } from '@socketsecurity/lib-stable/env/ci'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
+
+import { getQuietOutputMode } from './ambient-mode.mts'
+
+import type { SpinnerInstance } from '@socketsecurity/lib-stable/spinner/types'
+
+export type ProgressMode = 'interactive' | 'plain' | 'silent'
+
+export type ProgressOptions = {
+  // Defaults to the mode of the current output, see getProgressMode.
+  mode?: ProgressMode | undefined
+  spinner?: SpinnerInstance | undefined
+}
+
+export type ProgressState = {
+  detail?: string | undefined
+  done?: number | undefined
+  elapsedMs: number
+  label: string
+  total?: number | undefined
+}
+
+export type Progress = {
+  // Stop and print the outcome of the step.
+  succeed: (message: string) => void
+  stop: () => void
+  update: (done: number, total: number, detail?: string | undefined) => void
+}
+
+const PROGRESS_BAR_WIDTH = 20
+
+// Early rates are too noisy to estimate from.
+const ETA_MIN_ELAPSED_MS = 2000
+
+const REDRAW_INTERVAL_MS = 1000
+
+export function getProgressMode(): ProgressMode {
+  if (getQuietOutputMode()) {
+    return 'silent'
+  }
+  return process.stderr.isTTY && !getCI() ? 'interactive' : 'plain'
+}
+
+export function formatProgressBar(done: number, total: number): string {
+  const ratio = total > 0 ? Math.min(1, done / total) : 1
+  const filled = Math.round(ratio * PROGRESS_BAR_WIDTH)
+  return `[${'#'.repeat(filled)}${'-'.repeat(PROGRESS_BAR_WIDTH - filled)}] ${Math.round(ratio * 100)}%`
+}
+
+export function formatDuration(ms: number): string {
+  const seconds = Math.max(0, Math.round(ms / 1000))
+  if (seconds < 60) {
+    return `${seconds}s`
+  }
+  const minutes = Math.floor(seconds / 60)
+  if (minutes < 60) {
+    return `${minutes}m ${String(seconds % 60).padStart(2, '0')}s`
+  }
+  return `${Math.floor(minutes / 60)}h ${String(minutes % 60).padStart(2, '0')}m`
+}
+
+/**
+ * The time left at the rate so far, undefined until there is a rate to go by.
+ */
+export function estimateRemainingMs(
+  done: number,
+  total: number,
+  elapsedMs: number,
+): number | undefined {
+  if (done <= 0 || done >= total || elapsedMs < ETA_MIN_ELAPSED_MS) {
+    return undefined
+  }
+  return (elapsedMs / done) * (total - done)
+}
+
+export function formatProgressText(state: ProgressState): string {
+  const { detail, done, elapsedMs, label, total } = state
+  const parts = [label]
+  if (done !== undefined && total !== undefined) {
+    parts.push(formatProgressBar(done, total))
+  }
+  if (detail) {
+    parts.push(detail)
+  }
+  const text = parts.join(' ')
+  const remainingMs =
+    done !== undefined && total !== undefined
+      ? estimateRemainingMs(done, total, elapsedMs)
+      : undefined
+  if (remainingMs !== undefined) {
+    return `${text}, ~${formatDuration(remainingMs)} left`
+  }
+  return elapsedMs < REDRAW_INTERVAL_MS
+    ? `${text}…`
+    : `${text}, ${formatDuration(elapsedMs)} elapsed`
+}
+
+/**
+ * Show a step until it is stopped. The elapsed time is redrawn every second,
+ * so a step that takes long does not look hung.
+ */
+export function startProgress(
+  label: string,
+  options?: ProgressOptions | undefined,
+): Progress {
+  const { mode = getProgressMode(), spinner = getDefaultSpinner() } = {
+    __proto__: null,
+    ...options,
+  } as ProgressOptions
+  const logger = getDefaultLogger()
+  const startedAt = Date.now()
+  let state: Omit<ProgressState, 'elapsedMs' | 'label'> = {}
+  let timer: ReturnType<typeof setInterval> | undefined
+
+  const render = () =>
+    spinner.text(
+      formatProgressText({
+        ...state,
+        elapsedMs: Date.now() - startedAt,
+        label,
+      }),
+    )
+
+  if (mode === 'interactive') {
+    spinner.start(formatProgressText({ elapsedMs: 0, label }))
+    timer = setInterval(render, REDRAW_INTERVAL_MS)
+    timer.unref?.()
+  } else if (mode === 'plain') {
+    logger.info(`${label}…`)
+  }
+
+  const stop = () => {
+    if (timer) {
+      clearInterval(timer)
+      timer = undefined
+      spinner.stop()
+    }
+  }
+
+  return {
+    stop,
+    succeed(message) {
+      stop()
+      if (mode !== 'silent') {
+        logger.success(message)
+      }
+    },
+    update(done, total, detail) {
+      state = { detail, done, total }
+      if (timer) {
+        render()
+      }
+    },
+  }
+}
@@ -2,10 +2,14 @@ import { debugDirNs, debugNs } from '@socketsecurity/lib-stable/debug/output'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 import { isObject } from '@socketsecurity/lib-stable/objects/predicates'
 
+import { toJsonEnvelope } from './json-envelope.mts'
+
 import type { CResult } from '../../types.mjs'
 
 // Serialize the final result object before printing it
 // All commands that support the --json flag should call this before printing
+// While a command runs the result is wrapped in the versioned envelope, see
+// json-envelope.mts.
 export function serializeResultJson(data: CResult<unknown>): string {
   if (!isObject(data)) {
     process.exitCode = 1
@@ -14,16 +18,18 @@ export function serializeResultJson(data: CResult<unknown>): string {
 
     // We should not allow the JSON value to be "null", or a boolean/number/string,
     // even if they are valid "json".
-    return `${JSON.stringify({
-      ok: false,
-      message: 'Unable to serialize JSON',
-      cause:
-        'There was a problem converting the data set to JSON. The JSON was not an object. Please try again without --json',
-    }).trim()}\n`
+    return `${JSON.stringify(
+      toJsonEnvelope({
+        ok: false,
+        message: 'Unable to serialize JSON',
+        cause:
+          'There was a problem converting the data set to JSON. The JSON was not an object. Please try again without --json',
+      }),
+    ).trim()}\n`
   }
 
   try {
-    return `${JSON.stringify(data, null, 2).trim()}\n`
+    return `${JSON.stringify(toJsonEnvelope(data), null, 2).trim()}\n`
   } catch (e) {
     process.exitCode = 1
 
@@ -36,10 +42,12 @@ export function serializeResultJson(data: CResult<unknown>): string {
     debugDirNs('error', e)
 
     // This could be caused by circular references, which is an "us" problem.
-    return `${JSON.stringify({
-      ok: false,
-      message: 'Unable to serialize JSON',
-      cause: message,
-    }).trim()}\n`
+    return `${JSON.stringify(
+      toJsonEnvelope({
+        ok: false,
+        message: 'Unable to serialize JSON',
+        cause: message,
+      }),
+    ).trim()}\n`
   }
 }
@@ -0,0 +1,389 @@
+/**
+ * Handlebars-style text templates for `--output-template`, so results can be
+ * rendered as Slack blocks, wiki markup or ticket bodies without post
+ * processing the JSON output.
+ *
+ * Supported syntax:
+ *
+ * - `{{path.to.value}}` prints a value. Objects print as JSON, missing values
+ *   as nothing. Nothing is escaped; use the `json` helper inside JSON.
+ * - `{{helper arg "literal" 1}}` calls a helper: `json`, `upper`, `lower`,
+ *   `length`, `join` (with an optional separator), `default` (first non-empty
+ *   argument) and `eq`.
+ * - `{{#each list}}…{{else}}…{{/each}}` loops over arrays and objects, with
+ *   `this`, `@index`, `@key`, `@first` and `@last`.
+ * - `{{#if expr}}…{{else}}…{{/if}}` and `{{#unless expr}}…{{/unless}}`, where
+ *   empty arrays count as false.
+ * - `{{! comment }}`.
+ *
+ * Names are looked up in the current item first, then in the enclosing ones.
+ * Block tags and comments alone on a line drop the line, so templates can be
+ * indented freely.
+ */
+
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+import { isObject } from '@socketsecurity/lib-stable/objects/predicates'
+
+import type { CResult } from '../../types.mts'
+
+export class TemplateError extends Error {
+  constructor(message: string, line: number) {
+    super(`${message} (line ${line})`)
+    this.name = 'TemplateError'
+  }
+}
+
+type BlockName = 'each' | 'if' | 'unless'
+
+type TemplateNode =
+  | { type: 'text'; value: string }
+  | { type: 'value'; expr: string[]; line: number }
+  | {
+      type: 'block'
+      body: TemplateNode[]
+      expr: string[]
+      inverse: TemplateNode[]
+      line: number
+      name: BlockName
+    }
+
+type Token =
+  | { type: 'text'; value: string }
+  | { type: 'tag'; line: number; value: string }
+
+type Frame = {
+  data: Record<string, unknown>
+  value: unknown
+}
+
+const BLOCK_NAMES = new Set<string>(['each', 'if', 'unless'])
+
+const TAG_REGEX = /\{\{([\s\S]*?)\}\}/g
+
+// An argument is a quoted string or a run of non-space characters.
+const ARG_REGEX = /"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)'|(\S+)/g
+
+const HELPERS: Record<string, (...args: unknown[]) => unknown> = {
+  __proto__: null,
+  default: (...args: unknown[]) =>
+    args.find(a => a !== undefined && a !== null && a !== ''),
+  eq: (a: unknown, b: unknown) => a === b,
+  join: (list: unknown, separator: unknown = ', ') =>
+    Array.isArray(list)
+      ? list.map(v => stringifyValue(v)).join(String(separator))
+      : stringifyValue(list),
+  json: (value: unknown) => JSON.stringify(value) ?? 'null',
+  length: (value: unknown) =>
+    Array.isArray(value) || typeof value === 'string'
+      ? value.length
+      : isObject(value)
+        ? Object.keys(value).length
+        : 0,
+  lower: (value: unknown) => stringifyValue(value).toLowerCase(),
+  upper: (value: unknown) => stringifyValue(value).toUpperCase(),
+} as unknown as Record<string, (...args: unknown[]) => unknown>
+
+function stringifyValue(value: unknown): string {
+  if (value === undefined || value === null) {
+    return ''
+  }
+  if (typeof value === 'object') {
+    return JSON.stringify(value)
+  }
+  return String(value)
+}
+
+function isTruthy(value: unknown): boolean {
+  return Array.isArray(value) ? value.length > 0 : !!value
+}
+
+function isStandaloneTag(value: string): boolean {
+  const tag = value.trim()
+  return (
+    tag.startsWith('#') ||
+    tag.startsWith('/') ||
+    tag.startsWith('!') ||
+    tag === 'else'
+  )
+}
+
+function tokenize(source: string): Token[] {
+  const tokens: Token[] = []
+  let lastIndex = 0
+  let line = 1
+  for (const match of source.matchAll(TAG_REGEX)) {
+    const text = source.slice(lastIndex, match.index)
+    line += text.split('\n').length - 1
+    tokens.push({ type: 'text', value: text })
+    tokens.push({ type: 'tag', line, value: match[1]! })
+    line += match[0].split('\n').length - 1
+    lastIndex = match.index + match[0].length
+  }
+  tokens.push({ type: 'text', value: source.slice(lastIndex) })
+
+  // Drop the line of a block tag or comment that stands alone on it. Text
+  // tokens in lineStarts begin at the start of a line.
+  const lineStarts = new Set<number>([0])
+  for (let i = 1, { length } = tokens; i < length; i += 2) {
+    const tag = tokens[i] as Extract<Token, { type: 'tag' }>
+    if (!isStandaloneTag(tag.value)) {
+      continue
+    }
+    const before = tokens[i - 1] as Extract<Token, { type: 'text' }>
+    const after = tokens[i + 1] as Extract<Token, { type: 'text' }>
+    const lineStart = before.value.lastIndexOf('\n')
+    const leading = before.value.slice(lineStart + 1)
+    if (/\S/.test(leading) || (lineStart === -1 && !lineStarts.has(i - 1))) {
+      continue
+    }
+    const lineEnd = after.value.indexOf('\n')
+    const trailing =
+      lineEnd === -1 ? after.value : after.value.slice(0, lineEnd)
+    // A line only ends without a newline at the end of the template.
+    if (/\S/.test(trailing) || (lineEnd === -1 && i !== length - 2)) {
+      continue
+    }
+    before.value = before.value.slice(0, lineStart + 1)
+    after.value = lineEnd === -1 ? '' : after.value.slice(lineEnd + 1)
+    lineStarts.add(i + 1)
+  }
+  return tokens
+}
+
+function parseExpression(source: string, line: number): string[] {
+  const args: string[] = []
+  for (const match of source.matchAll(ARG_REGEX)) {
+    const quoted = match[1] ?? match[2]
+    // Quoted literals keep their quote so they resolve as strings.
+    args.push(quoted === undefined ? match[3]! : `"${quoted}`)
+  }
+  if (!args.length) {
+    throw new TemplateError('Missing expression in {{ }}', line)
+  }
+  if (args.length > 1 && !HELPERS[args[0]!]) {
+    throw new TemplateError(`Unknown helper "${args[0]}"`, line)
+  }
+  return args
+}
+
+function parse(source: string): TemplateNode[] {
+  const root: TemplateNode[] = []
+  const stack: Array<{
+    block: Extract<TemplateNode, { type: 'block' }>
+    inElse: boolean
+  }> = []
+  const current = (): TemplateNode[] => {
+    const top = stack.at(-1)
+    return top ? (top.inElse ? top.block.inverse : top.block.body) : root
+  }
+  for (const token of tokenize(source)) {
+    if (token.type === 'text') {
+      if (token.value) {
+        current().push(token)
+      }
+      continue
+    }
+    const { line } = token
+    const tag = token.value.trim()
+    if (tag.startsWith('!')) {
+      continue
+    }
+    if (tag.startsWith('#')) {
+      const { 1: name = '', 2: rest = '' } =
+        /^#\s*(\S*)\s*([\s\S]*)$/.exec(tag) ?? []
+      if (!BLOCK_NAMES.has(name)) {
+        throw new TemplateError(`Unknown block "#${name}"`, line)
+      }
+      const block: Extract<TemplateNode, { type: 'block' }> = {
+        type: 'block',
+        body: [],
+        expr: parseExpression(rest, line),
+        inverse: [],
+        line,
+        name: name as BlockName,
+      }
+      current().push(block)
+      stack.push({ block, inElse: false })
+      continue
+    }
+    if (tag.startsWith('/')) {
+      const name = tag.slice(1).trim()
+      const top = stack.pop()
+      if (!top || top.block.name !== name) {
+        throw new TemplateError(
+          top
+            ? `Expected {{/${top.block.name}}} but found {{/${name}}}`
+            : `Unexpected {{/${name}}}`,
+          line,
+        )
+      }
+      continue
+    }
+    if (tag === 'else') {
+      const top = stack.at(-1)
+      if (!top || top.inElse) {
+        throw new TemplateError('Unexpected {{else}}', line)
+      }
+      top.inElse = true
+      continue
+    }
+    current().push({ type: 'value', expr: parseExpression(tag, line), line })
+  }
+  const open = stack.at(-1)
+  if (open) {
+    throw new TemplateError(
+      `Missing {{/${open.block.name}}}`,
+      open.block.line,
+    )
+  }
+  return root
+}
+
+function getPath(value: unknown, segments: string[]): unknown {
+  let result = value
+  for (let i = 0, { length } = segments; i < length; i += 1) {
+    if (!isObject(result)) {
+      return undefined
+    }
+    result = (result as Record<string, unknown>)[segments[i]!]
+  }
+  return result
+}
+
+function resolveArg(arg: string, frames: Frame[]): unknown {
+  if (arg.startsWith('"')) {
+    return arg.slice(1).replace(/\\(.)/g, '$1')
+  }
+  if (/^-?\d+(?:\.\d+)?$/.test(arg)) {
+    return Number(arg)
+  }
+  if (arg === 'true' || arg === 'false') {
+    return arg === 'true'
+  }
+  const frame = frames.at(-1)!
+  if (arg.startsWith('@')) {
+    return frame.data[arg.slice(1)]
+  }
+  if (arg === 'this' || arg === '.') {
+    return frame.value
+  }
+  const segments = arg.split('.')
+  if (segments[0] === 'this') {
+    return getPath(frame.value, segments.slice(1))
+  }
+  const [first = ''] = segments
+  for (let i = frames.length - 1; i >= 0; i -= 1) {
+    const { value } = frames[i]!
+    if (isObject(value) && Object.hasOwn(value, first)) {
+      return getPath(value, segments)
+    }
+  }
+  return undefined
+}
+
+function evaluate(expr: string[], frames: Frame[]): unknown {
+  const [head = '', ...args] = expr
+  const helper = args.length ? HELPERS[head] : undefined
+  if (helper) {
+    return helper(...args.map(a => resolveArg(a, frames)))
+  }
+  return resolveArg(head, frames)
+}
+
+function renderNodes(nodes: TemplateNode[], frames: Frame[]): string {
+  let out = ''
+  for (let i = 0, { length } = nodes; i < length; i += 1) {
+    const node = nodes[i]!
+    if (node.type === 'text') {
+      out += node.value
+    } else if (node.type === 'value') {
+      out += stringifyValue(evaluate(node.expr, frames))
+    } else {
+      out += renderBlock(node, frames)
+    }
+  }
+  return out
+}
+
+function renderBlock(
+  node: Extract<TemplateNode, { type: 'block' }>,
+  frames: Frame[],
+): string {
+  const value = evaluate(node.expr, frames)
+  if (node.name !== 'each') {
+    const truthy = isTruthy(value)
+    return renderNodes(
+      (node.name === 'if' ? truthy : !truthy) ? node.body : node.inverse,
+      frames,
+    )
+  }
+  const entries: Array<[string | number, unknown]> = Array.isArray(value)
+    ? value.map((v, i) => [i, v])
+    : isObject(value)
+      ? Object.entries(value)
+      : []
+  if (!entries.length) {
+    return renderNodes(node.inverse, frames)
+  }
+  let out = ''
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const { 0: key, 1: item } = entries[i]!
+    out += renderNodes(node.body, [
+      ...frames,
+      {
+        data: {
+          first: i === 0,
+          index: i,
+          key,
+          last: i === length - 1,
+        },
+        value: item,
+      },
+    ])
+  }
+  return out
+}
+
+/**
+ * Render a template against a context. Throws a TemplateError for syntax
+ * errors, before anything is rendered.
+ */
+export function renderTemplate(source: string, context: unknown): string {
+  return renderNodes(parse(source), [{ data: {}, value: context }])
+}
+
+/**
+ * Read and render an `--output-template` file. Unreadable files and syntax
+ * errors are returned as failed results.
+ */
+export function renderTemplateFile(
+  filepath: string,
+  context: unknown,
+): CResult<string> {
+  const content = safeReadFileSync(filepath)
+  if (content === undefined) {
+    return {
+      ok: false,
+      message: 'Template file not found',
+      cause: `Unable to read ${filepath}`,
+    }
+  }
+  try {
+    return {
+      ok: true,
+      data: renderTemplate(
+        Buffer.isBuffer(content) ? content.toString('utf8') : content,
+        context,
+      ),
+    }
+  } catch (e) {
+    if (!(e instanceof TemplateError)) {
+      throw e
+    }
+    return {
+      ok: false,
+      message: `Invalid template file ${filepath}`,
+      cause: e.message,
+    }
+  }
+}
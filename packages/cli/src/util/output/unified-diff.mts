@@ -0,0 +1,178 @@
+/**
+ * Line diffs in the unified format of `diff -u` and `git diff`.
+ *
+ * Lines are compared with the Myers algorithm. Its trace grows with the
+ * square of the number of edits, so files that differ in more than
+ * MAX_EDIT_DISTANCE lines are not diffed line by line.
+ */
+
+export type DiffLine = {
+  text: string
+  type: ' ' | '+' | '-'
+}
+
+export type UnifiedDiffOptions = {
+  // Unchanged lines around each change. Defaults to 3.
+  context?: number | undefined
+}
+
+const MAX_EDIT_DISTANCE = 4000
+
+/**
+ * The shortest edit script from `a` to `b`, or undefined when they differ
+ * in more than MAX_EDIT_DISTANCE lines.
+ */
+export function diffLines(a: string[], b: string[]): DiffLine[] | undefined {
+  const n = a.length
+  const m = b.length
+  const maxD = Math.min(n + m, MAX_EDIT_DISTANCE)
+  const offset = maxD + 1
+  const v = new Int32Array(2 * offset + 1)
+  // The furthest x of each diagonal k before step d, for k in [-d, d].
+  const trace: Int32Array[] = []
+  let found = false
+  for (let d = 0; d <= maxD && !found; d += 1) {
+    trace.push(v.slice(offset - d, offset + d + 1))
+    for (let k = -d; k <= d; k += 2) {
+      let x =
+        k === -d || (k !== d && v[offset + k - 1]! < v[offset + k + 1]!)
+          ? v[offset + k + 1]!
+          : v[offset + k - 1]! + 1
+      let y = x - k
+      while (x < n && y < m && a[x] === b[y]) {
+        x += 1
+        y += 1
+      }
+      v[offset + k] = x
+      if (x >= n && y >= m) {
+        found = true
+        break
+      }
+    }
+  }
+  if (!found) {
+    return undefined
+  }
+
+  const lines: DiffLine[] = []
+  let x = n
+  let y = m
+  for (let d = trace.length - 1; d >= 0; d -= 1) {
+    const prev = trace[d]!
+    const at = (k: number) => prev[k + d]!
+    const k = x - y
+    const prevK =
+      k === -d || (k !== d && at(k - 1) < at(k + 1)) ? k + 1 : k - 1
+    const prevX = d ? at(prevK) : 0
+    const prevY = prevX - prevK
+    while (x > prevX && y > prevY) {
+      x -= 1
+      y -= 1
+      lines.push({ text: a[x]!, type: ' ' })
+    }
+    if (d) {
+      if (x === prevX) {
+        y -= 1
+        lines.push({ text: b[y]!, type: '+' })
+      } else {
+        x -= 1
+        lines.push({ text: a[x]!, type: '-' })
+      }
+    }
+  }
+  return lines.reverse()
+}
+
+function splitLines(text: string): string[] {
+  if (!text) {
+    return []
+  }
+  const lines = text.split('\n')
+  if (lines.at(-1) === '') {
+    lines.pop()
+  }
+  return lines
+}
+
+function formatRange(start: number, count: number): string {
+  // An empty range names the line before it, as diff does.
+  return count === 1 ? `${start}` : `${count ? start : start - 1},${count}`
+}
+
+export type UnifiedDiffFile = {
+  // Undefined when the file was removed.
+  after?: string | undefined
+  // Undefined when the file was added.
+  before?: string | undefined
+  path: string
+}
+
+/**
+ * The unified diff of two versions of a file, an empty string when they are
+ * the same, or undefined when they differ too much to diff.
+ */
+export function formatUnifiedDiff(
+  file: UnifiedDiffFile,
+  options?: UnifiedDiffOptions | undefined,
+): string | undefined {
+  const { context = 3 } = {
+    __proto__: null,
+    ...options,
+  } as UnifiedDiffOptions
+  if (file.before === file.after) {
+    return ''
+  }
+  const lines = diffLines(
+    splitLines(file.before ?? ''),
+    splitLines(file.after ?? ''),
+  )
+  if (!lines) {
+    return undefined
+  }
+  // Line numbers in `before` and `after` where each diff line starts.
+  const aLines: number[] = []
+  const bLines: number[] = []
+  const changes: number[] = []
+  for (let i = 0, a = 1, b = 1, { length } = lines; i < length; i += 1) {
+    const { type } = lines[i]!
+    aLines.push(a)
+    bLines.push(b)
+    if (type !== '+') {
+      a += 1
+    }
+    if (type !== '-') {
+      b += 1
+    }
+    if (type !== ' ') {
+      changes.push(i)
+    }
+  }
+  const out = [
+    file.before === undefined ? '--- /dev/null' : `--- a/${file.path}`,
+    file.after === undefined ? '+++ /dev/null' : `+++ b/${file.path}`,
+  ]
+  for (let c = 0, { length } = changes; c < length; ) {
+    const start = Math.max(0, changes[c]! - context)
+    let last = changes[c]!
+    // Changes at most two contexts apart share a hunk.
+    while (c + 1 < length && changes[c + 1]! - last <= 2 * context + 1) {
+      c += 1
+      last = changes[c]!
+    }
+    c += 1
+    const hunk = lines.slice(start, Math.min(lines.length, last + context + 1))
+    const aRange = formatRange(
+      aLines[start]!,
+      hunk.filter(l => l.type !== '+').length,
+    )
+    const bRange = formatRange(
+      bLines[start]!,
+      hunk.filter(l => l.type !== '-').length,
+    )
+    out.push(
+      `@@ -${aRange} +${bRange} @@`,
+      ...hunk.map(l => `${l.type}${l.text}`),
+    )
+  }
+  return out.join('\n')
+}
@@ -0,0 +1,64 @@
+/**
+ * Minimal XML serialization for report formats that require it (CycloneDX XML,
+ * JUnit, GraphML). Only element trees with attributes and text content are
+ * supported — no namespace prefixes, CDATA, or processing instructions beyond
+ * the standard declaration.
+ */
+
+export type XmlNode = {
+  name: string
+  attrs?: Record<string, string | number | boolean | undefined> | undefined
+  children?: XmlNode[] | undefined
+  text?: string | number | undefined
+}
+
+export const XML_DECLARATION = '<?xml version="1.0" encoding="UTF-8"?>'
+
+// Characters that are not allowed anywhere in an XML 1.0 document.
+// oxlint-disable-next-line no-control-regex -- stripping invalid XML chars is the point.
+const INVALID_XML_CHARS_REGEX = /[\u0000-\u0008\u000B\u000C\u000E-\u001F]/g
+
+export function escapeXml(value: string | number | boolean): string {
+  return String(value)
+    .replace(INVALID_XML_CHARS_REGEX, '')
+    .replaceAll('&', '&amp;')
+    .replaceAll('<', '&lt;')
+    .replaceAll('>', '&gt;')
+    .replaceAll('"', '&quot;')
+    .replaceAll("'", '&apos;')
+}
+
+function renderAttrs(attrs: XmlNode['attrs']): string {
+  if (!attrs) {
+    return ''
+  }
+  let out = ''
+  for (const [key, value] of Object.entries(attrs)) {
+    if (value === undefined) {
+      continue
+    }
+    out += ` ${key}="${escapeXml(value)}"`
+  }
+  return out
+}
+
+export function renderXmlNode(node: XmlNode, depth = 0): string {
+  const pad = '  '.repeat(depth)
+  const open = `${pad}<${node.name}${renderAttrs(node.attrs)}`
+  const children = node.children?.filter(Boolean) ?? []
+  if (children.length) {
+    const inner = children.map(c => renderXmlNode(c, depth + 1)).join('\n')
+    return `${open}>\n${inner}\n${pad}</${node.name}>`
+  }
+  if (node.text !== undefined && node.text !== '') {
+    return `${open}>${escapeXml(node.text)}</${node.name}>`
+  }
+  return `${open}/>`
+}
+
+/**
+ * Render a full XML document, including the declaration and trailing newline.
+ */
+export function renderXmlDocument(root: XmlNode): string {
+  return `${XML_DECLARATION}\n${renderXmlNode(root)}\n`
+}
@@ -0,0 +1,81 @@
+/**
+ * @file Append an exception to a `socket.policy.yml`, e.g. when an alert is
+ *   marked as ignored in `socket scan view --interactive`. Uses the `yaml`
+ *   Document API so the comments and layout of the existing file survive. A
+ *   missing file is created with `version: 1`. Files that do not lint are left
+ *   untouched, so a typo is never buried under new entries.
+ */
+
+import { existsSync, writeFileSync } from 'node:fs'
+
+import { isSeq, parseDocument } from 'yaml'
+
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+
+import { parseSocketPolicy } from './socket-policy.mts'
+import { getErrorCause } from '../error/errors.mts'
+
+import type { SocketPolicyException } from './socket-policy.mts'
+import type { CResult } from '../../types.mts'
+
+function toExceptionNode(
+  exception: SocketPolicyException,
+): Record<string, unknown> {
+  // Only set the keys in use, in the order the docs show them.
+  return {
+    ...(exception.paths.length ? { paths: exception.paths } : {}),
+    ...(exception.packages.length ? { packages: exception.packages } : {}),
+    ...(exception.alerts.length ? { alerts: exception.alerts } : {}),
+    reason: exception.reason,
+    ...(exception.expires ? { expires: exception.expires } : {}),
+  }
+}
+
+export function addSocketPolicyException(
+  filepath: string,
+  exception: SocketPolicyException,
+): CResult<undefined> {
+  const content = existsSync(filepath)
+    ? safeReadFileSync(filepath, { encoding: 'utf8' })
+    : ''
+  if (content === undefined) {
+    return {
+      ok: false,
+      message: 'Policy file not readable',
+      cause: `Unable to read ${filepath}`,
+    }
+  }
+  const source = Buffer.isBuffer(content) ? content.toString('utf8') : content
+  if (source.trim()) {
+    const policyCResult = parseSocketPolicy(source)
+    if (!policyCResult.ok) {
+      return { ...policyCResult, message: `Invalid policy file ${filepath}` }
+    }
+  }
+
+  const doc = parseDocument(source, { keepSourceTokens: true })
+  if (!source.trim()) {
+    doc.contents = doc.createNode({ version: 1 })
+  }
+  const exceptions = doc.get('exceptions', true)
+  if (isSeq(exceptions)) {
+    exceptions.add(doc.createNode(toExceptionNode(exception)))
+  } else {
+    doc.set('exceptions', [toExceptionNode(exception)])
+  }
+
+  try {
+    writeFileSync(
+      filepath,
+      doc.toString({ indent: 2, lineWidth: 0, minContentWidth: 0 }),
+      'utf8',
+    )
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Failed to write policy file',
+      cause: getErrorCause(e),
+    }
+  }
+  return { ok: true, data: undefined }
+}
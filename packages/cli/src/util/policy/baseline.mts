@@ -0,0 +1,162 @@
+/**
+ * `socket.baseline.json`: a committed snapshot of the alerts a repository
+ * already has, so gating can be rolled out without fixing everything first.
+ * Reports skip baselined alerts and only fail on new ones.
+ *
+ * An entry matches an alert by its type and the package's purl, including the
+ * version: upgrading a baselined package to a version with the same alert
+ * reports it again, as does the same alert showing up in another package.
+ */
+
+import path from 'node:path'
+
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+
+import { SOCKET_BASELINE_JSON } from '../../constants/socket.mts'
+import { getErrorCause } from '../error/errors.mts'
+import { getArtifactPurlString } from '../purl/parse.mts'
+import { isPlainObject } from '../socket-yaml.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../alert/artifact.mts'
+
+export type SocketBaselineEntry = {
+  purl: string
+  type: string
+}
+
+export type SocketBaseline = {
+  alerts: SocketBaselineEntry[]
+  createdAt: string
+  scanId?: string | undefined
+  version: 1
+}
+
+export type FoundSocketBaseline = {
+  // Lookup set of `${purl} ${type}` keys.
+  keys: Set<string>
+  path: string
+}
+
+function getBaselineKey(purl: string, type: string): string {
+  return `${purl} ${type}`
+}
+
+/**
+ * Snapshot every alert of a scan, whatever its policy action, so a later
+ * policy change does not resurface alerts the repo already had.
+ */
+export function createSocketBaseline(
+  artifacts: SocketArtifact[],
+  scanId?: string | undefined,
+): SocketBaseline {
+  const entries = new Map<string, SocketBaselineEntry>()
+  for (let i = 0, { length } = artifacts; i < length; i += 1) {
+    const artifact = artifacts[i]!
+    const purl = getArtifactPurlString(artifact)
+    const alerts = artifact.alerts ?? []
+    for (let j = 0, { length: alertCount } = alerts; j < alertCount; j += 1) {
+      const { type } = alerts[j]!
+      entries.set(getBaselineKey(purl, type), { purl, type })
+    }
+  }
+  return {
+    version: 1,
+    createdAt: new Date().toISOString(),
+    ...(scanId ? { scanId } : {}),
+    // Sorted so regenerating the file gives a readable diff.
+    alerts: [...entries.values()].sort(
+      (a, b) => a.purl.localeCompare(b.purl) || a.type.localeCompare(b.type),
+    ),
+  }
+}
+
+export function parseSocketBaseline(content: string): CResult<Set<string>> {
+  let parsed: unknown
+  try {
+    parsed = JSON.parse(content)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Invalid baseline file',
+      cause: `Invalid JSON: ${getErrorCause(e)}`,
+    }
+  }
+  if (
+    !isPlainObject(parsed) ||
+    parsed['version'] !== 1 ||
+    !Array.isArray(parsed['alerts'])
+  ) {
+    return {
+      ok: false,
+      message: 'Invalid baseline file',
+      cause: 'Expected a version 1 baseline with an `alerts` list',
+    }
+  }
+  const keys = new Set<string>()
+  const alerts = parsed['alerts'] as unknown[]
+  for (let i = 0, { length } = alerts; i < length; i += 1) {
+    const entry = alerts[i]
+    if (
+      !isPlainObject(entry) ||
+      typeof entry['purl'] !== 'string' ||
+      typeof entry['type'] !== 'string'
+    ) {
+      return {
+        ok: false,
+        message: 'Invalid baseline file',
+        cause: `alerts[${i}] needs a string \`purl\` and \`type\``,
+      }
+    }
+    keys.add(getBaselineKey(entry['purl'], entry['type']))
+  }
+  return { ok: true, data: keys }
+}
+
+export function readSocketBaselineSync(
+  filepath: string,
+): CResult<FoundSocketBaseline> {
+  const content = safeReadFileSync(filepath)
+  if (content === undefined) {
+    return {
+      ok: false,
+      message: 'Baseline file not found',
+      cause: `Unable to read ${filepath}`,
+    }
+  }
+  const keysCResult = parseSocketBaseline(
+    Buffer.isBuffer(content) ? content.toString('utf8') : content,
+  )
+  if (!keysCResult.ok) {
+    return { ...keysCResult, message: `Invalid baseline file ${filepath}` }
+  }
+  return { ok: true, data: { keys: keysCResult.data, path: filepath } }
+}
+
+/**
+ * Find the nearest `socket.baseline.json` walking up from dir.
+ */
+export function findSocketBaselineSync(
+  dir = process.cwd(),
+): CResult<FoundSocketBaseline | undefined> {
+  let prevDir = undefined
+  while (dir !== prevDir) {
+    const filepath = path.join(dir, SOCKET_BASELINE_JSON)
+    if (safeReadFileSync(filepath) !== undefined) {
+      return readSocketBaselineSync(filepath)
+    }
+    prevDir = dir
+    dir = path.join(dir, '..')
+  }
+  return { ok: true, data: undefined }
+}
+
+export function isBaselinedAlert(
+  baseline: FoundSocketBaseline,
+  artifact: SocketArtifact,
+  alertType: string,
+): boolean {
+  return baseline.keys.has(
+    getBaselineKey(getArtifactPurlString(artifact), alertType),
+  )
+}
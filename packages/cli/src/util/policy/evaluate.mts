@@ -0,0 +1,300 @@
+/**
+ * Local evaluation of a `socket.policy.yml` against scored artifacts.
+ *
+ * The local policy only speaks when one of its rules matches: an exception
+ * waives the alert (`ignore`), a block rule makes it `error`, a warn rule
+ * makes it `warn`. Otherwise the org policy action from the API stands.
+ * Exceptions past their `expires` date are skipped.
+ * License allow/deny lists are checked against the artifact's SPDX
+ * expression; an `OR` expression passes when any alternative is acceptable.
+ * The quarantine window is checked against the publish time of the version.
+ * Trusted packages and maintainers skip `warn` prompts; distrusted ones are
+ * blocked outright.
+ */
+
+import micromatch from 'micromatch'
+
+import {
+  isPolicyExceptionExpired,
+  POLICY_SEVERITIES,
+} from './socket-policy.mts'
+import { getArtifactPurlString } from '../purl/parse.mts'
+
+import type {
+  PolicySeverity,
+  SocketPolicy,
+  SocketPolicyException,
+  SocketPolicyRule,
+  SocketPolicyTrustList,
+} from './socket-policy.mts'
+import type { ALERT_ACTION, SocketArtifact } from '../alert/artifact.mts'
+
+export type LocalPolicyAlert = {
+  severity?: string | undefined
+  type: string
+}
+
+export type LocalTrust = 'distrusted' | 'trusted'
+
+// Pseudo alert type used when a license violates the local allow/deny lists.
+export const LOCAL_LICENSE_POLICY_ALERT = 'licensePolicyViolation'
+
+// Pseudo alert type used when a version is younger than the quarantine window.
+export const LOCAL_QUARANTINE_POLICY_ALERT = 'recentlyPublished'
+
+// Pseudo alert type used when a package or maintainer is distrusted.
+export const LOCAL_DISTRUST_POLICY_ALERT = 'distrustedPackage'
+
+const DAY_MS = 24 * 60 * 60 * 1000
+
+function getManifestFiles(artifact: SocketArtifact): string[] {
+  return (
+    artifact.manifestFiles
+      ?.map((o: { file?: string | undefined }) => o.file)
+      .filter((file): file is string => typeof file === 'string') ?? []
+  )
+}
+
+function safeDecode(purl: string): string {
+  try {
+    return decodeURIComponent(purl)
+  } catch {
+    return purl
+  }
+}
+
+function getUnversionedPurl(purl: string): string {
+  const atIndex = purl.lastIndexOf('@')
+  return atIndex > purl.indexOf('/') + 1 ? purl.slice(0, atIndex) : purl
+}
+
+function matchesPackage(artifact: SocketArtifact, patterns: string[]): boolean {
+  const purl = getArtifactPurlString(artifact)
+  // Scoped npm purls encode the `@`, and `pkg:npm/@acme/*` should match
+  // them as well as the encoded purls recorded from install prompts.
+  const decoded = safeDecode(purl)
+  // `pkg:npm/lodash` matches every version, `pkg:npm/lodash@4.*` some.
+  return micromatch.some(
+    [purl, getUnversionedPurl(purl), decoded, getUnversionedPurl(decoded)],
+    patterns,
+  )
+}
+
+function getArtifactMaintainers(artifact: SocketArtifact): string[] {
+  const { author } = artifact as { author?: unknown }
+  return Array.isArray(author)
+    ? author.filter((name): name is string => typeof name === 'string')
+    : []
+}
+
+function matchesTrustList(
+  artifact: SocketArtifact,
+  list: SocketPolicyTrustList | undefined,
+): boolean {
+  if (!list) {
+    return false
+  }
+  if (list.packages.length && matchesPackage(artifact, list.packages)) {
+    return true
+  }
+  const maintainers = list.maintainers.map(name => name.toLowerCase())
+  return getArtifactMaintainers(artifact).some(name =>
+    maintainers.includes(name.toLowerCase()),
+  )
+}
+
+/**
+ * Whether the policy trusts or distrusts the artifact by its purl or one of
+ * its maintainers, or undefined when it does neither. Distrust wins.
+ */
+export function getLocalTrust(
+  policy: SocketPolicy,
+  artifact: SocketArtifact,
+): LocalTrust | undefined {
+  if (matchesTrustList(artifact, policy.distrust)) {
+    return 'distrusted'
+  }
+  if (matchesTrustList(artifact, policy.trust)) {
+    return 'trusted'
+  }
+  return undefined
+}
+
+/**
+ * The action once trust applies: trusted packages are not asked about `warn`
+ * alerts. Every other action stands.
+ */
+export function applyLocalTrust<T extends string>(
+  action: T,
+  trust: LocalTrust | undefined,
+): T | 'ignore' {
+  return trust === 'trusted' && action === 'warn' ? 'ignore' : action
+}
+
+/**
+ * Whether an alert severity is at or above a threshold. Unknown severities
+ * never are.
+ */
+export function isSeverityAtLeast(
+  severity: string | undefined,
+  threshold: PolicySeverity | undefined,
+): boolean {
+  if (!threshold || !severity) {
+    return false
+  }
+  const index = POLICY_SEVERITIES.indexOf(severity as PolicySeverity)
+  return index !== -1 && index >= POLICY_SEVERITIES.indexOf(threshold)
+}
+
+function matchesRule(
+  alert: LocalPolicyAlert,
+  rule: SocketPolicyRule,
+): boolean {
+  return (
+    rule.alerts.includes(alert.type) ||
+    isSeverityAtLeast(alert.severity, rule.severity)
+  )
+}
+
+/**
+ * The first exception in the policy that waives this alert for this artifact.
+ * An exception matches when every criterion it sets matches; `paths` match
+ * when every manifest the artifact was found in is covered. Expired
+ * exceptions never match.
+ */
+export function findPolicyException(
+  policy: SocketPolicy,
+  artifact: SocketArtifact,
+  alertType: string,
+): SocketPolicyException | undefined {
+  const { exceptions } = policy
+  for (let i = 0, { length } = exceptions; i < length; i += 1) {
+    const exception = exceptions[i]!
+    if (isPolicyExceptionExpired(exception)) {
+      continue
+    }
+    if (exception.alerts.length && !exception.alerts.includes(alertType)) {
+      continue
+    }
+    if (
+      exception.packages.length &&
+      !matchesPackage(artifact, exception.packages)
+    ) {
+      continue
+    }
+    if (exception.paths.length) {
+      const manifestFiles = getManifestFiles(artifact)
+      if (
+        !manifestFiles.length ||
+        !manifestFiles.every(file =>
+          micromatch.isMatch(file, exception.paths, { dot: true }),
+        )
+      ) {
+        continue
+      }
+    }
+    return exception
+  }
+  return undefined
+}
+
+/**
+ * Whether an exception in the policy waives this alert for this artifact, see
+ * `findPolicyException`.
+ */
+export function isPolicyException(
+  policy: SocketPolicy,
+  artifact: SocketArtifact,
+  alertType: string,
+): boolean {
+  return !!findPolicyException(policy, artifact, alertType)
+}
+
+/**
+ * The action the local policy assigns to an alert, or undefined when no rule
+ * matches and the org policy should decide.
+ */
+export function getLocalPolicyAction(
+  policy: SocketPolicy,
+  artifact: SocketArtifact,
+  alert: LocalPolicyAlert,
+): ALERT_ACTION | undefined {
+  if (isPolicyException(policy, artifact, alert.type)) {
+    return 'ignore'
+  }
+  if (matchesRule(alert, policy.block)) {
+    return 'error'
+  }
+  if (matchesRule(alert, policy.warn)) {
+    return 'warn'
+  }
+  return undefined
+}
+
+/**
+ * Split an SPDX expression into its `OR` alternatives, each a list of license
+ * ids that must all be acceptable. Parentheses are flattened, which is exact
+ * for the common `A OR B` and `(A AND B)` shapes.
+ */
+export function splitSpdxAlternatives(expression: string): string[][] {
+  return expression
+    .replace(/[()]/g, ' ')
+    .split(/\s+OR\s+/i)
+    .map(alternative =>
+      alternative
+        .split(/\s+AND\s+/i)
+        .map(term => term.split(/\s+WITH\s+/i)[0]!.trim())
+        .filter(Boolean),
+    )
+    .filter(ids => ids.length)
+}
+
+/**
+ * Returns the artifact's license expression when it violates the local
+ * license lists, otherwise undefined. Artifacts without license data are not
+ * judged here; Socket raises its own unidentified-license alerts for them.
+ */
+export function getLocalLicenseViolation(
+  policy: SocketPolicy,
+  artifact: SocketArtifact,
+): string | undefined {
+  const { allow, deny } = policy.licenses
+  const expression = artifact.license
+  if ((!allow.length && !deny.length) || !expression) {
+    return undefined
+  }
+  if (isPolicyException(policy, artifact, LOCAL_LICENSE_POLICY_ALERT)) {
+    return undefined
+  }
+  const acceptable = splitSpdxAlternatives(expression).some(ids =>
+    ids.every(
+      id => !deny.includes(id) && (!allow.length || allow.includes(id)),
+    ),
+  )
+  return acceptable ? undefined : expression
+}
+
+/**
+ * The age in whole days of a version that is still inside the quarantine
+ * window of the policy, otherwise undefined. Versions whose publish time is
+ * unknown are not held back.
+ */
+export function getLocalQuarantineViolation(
+  policy: SocketPolicy,
+  artifact: SocketArtifact,
+  publishedAt: number | undefined,
+  now = Date.now(),
+): number | undefined {
+  const { quarantine } = policy
+  if (!quarantine || publishedAt === undefined) {
+    return undefined
+  }
+  const age = (now - publishedAt) / DAY_MS
+  if (age >= quarantine.days) {
+    return undefined
+  }
+  if (isPolicyException(policy, artifact, LOCAL_QUARANTINE_POLICY_ALERT)) {
+    return undefined
+  }
+  return Math.max(0, Math.floor(age))
+}
@@ -0,0 +1,284 @@
+/**
+ * `socket.org-policy.json`: a committed copy of the org security policy,
+ * written by `socket policy pull`, and the comparison of the local repo
+ * settings against it behind `socket policy diff`.
+ *
+ * A repo relaxes or tightens the org gate in two places: `block`, `warn` and
+ * `exceptions` of its `socket.policy.yml` (scans and install checks), and the
+ * `wrapper.alerts` modes of its socket.yml (install checks only). Each of
+ * these that acts differently on an alert type than the org does is an
+ * override. It is weakened when the local action is milder, e.g. an exception
+ * waiving an alert the org blocks, and strengthened when it is stricter.
+ */
+
+import path from 'node:path'
+
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+
+import { isPolicyExceptionExpired } from './socket-policy.mts'
+import { SOCKET_ORG_POLICY_JSON } from '../../constants/socket.mts'
+import { getErrorCause } from '../error/errors.mts'
+import { WRAPPER_MODE_ACTIONS } from '../install-check/check.mts'
+import { isPlainObject } from '../socket-yaml.mts'
+
+import type { FoundSocketPolicy } from './socket-policy.mts'
+import type { CResult } from '../../types.mts'
+import type { SocketYmlWrapper } from '../socket-yaml.mts'
+
+export type SocketOrgPolicy = {
+  createdAt: string
+  // The security policy preset the org starts from, e.g. `medium`.
+  default: string
+  org: string
+  // Policy action per alert type.
+  rules: Record<string, string>
+  version: 1
+}
+
+export type PolicyOverride = {
+  // Alert type, or `*` for every alert type.
+  alert: string
+  effect: 'strengthened' | 'weakened'
+  local: string
+  // Undefined when the org defers to its default preset.
+  org?: string | undefined
+  // What the override is limited to, e.g. packages, paths or a severity.
+  scope?: string | undefined
+  // Where the override lives, e.g. `socket.policy.yml exceptions[0]`.
+  source: string
+}
+
+// `notice` lists the alert without asking, like `monitor` with
+// SOCKET_CLI_VIEW_ALL_RISKS.
+const ACTION_STRICTNESS: Record<string, number> = {
+  __proto__: null,
+  error: 3,
+  warn: 2,
+  notice: 1,
+  monitor: 1,
+  ignore: 0,
+} as unknown as Record<string, number>
+
+/**
+ * Snapshot the rules of an org security policy, sorted so pulling it again
+ * gives a readable diff. Rules that defer to the default preset are left out.
+ */
+export function createSocketOrgPolicy(
+  orgSlug: string,
+  data: {
+    securityPolicyDefault?: string | undefined
+    securityPolicyRules?:
+      | Record<string, { action?: string | undefined }>
+      | undefined
+  },
+): SocketOrgPolicy {
+  const rules: Record<string, string> = {}
+  const alertTypes = Object.keys(data.securityPolicyRules ?? {}).sort()
+  for (let i = 0, { length } = alertTypes; i < length; i += 1) {
+    const alertType = alertTypes[i]!
+    const action = data.securityPolicyRules![alertType]?.action
+    if (action && action !== 'defer') {
+      rules[alertType] = action
+    }
+  }
+  return {
+    version: 1,
+    createdAt: new Date().toISOString(),
+    org: orgSlug,
+    default: data.securityPolicyDefault ?? '',
+    rules,
+  }
+}
+
+export function parseSocketOrgPolicy(
+  content: string,
+): CResult<SocketOrgPolicy> {
+  let parsed: unknown
+  try {
+    parsed = JSON.parse(content)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Invalid org policy file',
+      cause: `Invalid JSON: ${getErrorCause(e)}`,
+    }
+  }
+  if (
+    !isPlainObject(parsed) ||
+    parsed['version'] !== 1 ||
+    typeof parsed['org'] !== 'string' ||
+    !isPlainObject(parsed['rules'])
+  ) {
+    return {
+      ok: false,
+      message: 'Invalid org policy file',
+      cause: 'Expected a version 1 org policy with an `org` and `rules`',
+    }
+  }
+  const rules = parsed['rules']
+  const alertTypes = Object.keys(rules)
+  for (let i = 0, { length } = alertTypes; i < length; i += 1) {
+    const alertType = alertTypes[i]!
+    if (typeof rules[alertType] !== 'string') {
+      return {
+        ok: false,
+        message: 'Invalid org policy file',
+        cause: `rules.${alertType} needs a string action`,
+      }
+    }
+  }
+  return {
+    ok: true,
+    data: {
+      createdAt: String(parsed['createdAt'] ?? ''),
+      default: String(parsed['default'] ?? ''),
+      org: parsed['org'],
+      rules: rules as Record<string, string>,
+      version: 1,
+    },
+  }
+}
+
+export function readSocketOrgPolicySync(
+  filepath: string,
+): CResult<SocketOrgPolicy> {
+  const content = safeReadFileSync(filepath)
+  if (content === undefined) {
+    return {
+      ok: false,
+      message: 'Org policy file not found',
+      cause: `Unable to read ${filepath}`,
+    }
+  }
+  const orgPolicyCResult = parseSocketOrgPolicy(
+    Buffer.isBuffer(content) ? content.toString('utf8') : content,
+  )
+  if (!orgPolicyCResult.ok) {
+    return {
+      ...orgPolicyCResult,
+      message: `Invalid org policy file ${filepath}`,
+    }
+  }
+  return orgPolicyCResult
+}
+
+/**
+ * Find the nearest `socket.org-policy.json` walking up from dir.
+ */
+export function findSocketOrgPolicyPathSync(
+  dir = process.cwd(),
+): string | undefined {
+  let prevDir = undefined
+  while (dir !== prevDir) {
+    const filepath = path.join(dir, SOCKET_ORG_POLICY_JSON)
+    if (safeReadFileSync(filepath) !== undefined) {
+      return filepath
+    }
+    prevDir = dir
+    dir = path.join(dir, '..')
+  }
+  return undefined
+}
+
+function getOverrideEffect(
+  local: string,
+  org: string | undefined,
+): PolicyOverride['effect'] | undefined {
+  if (local === org) {
+    return undefined
+  }
+  const localStrictness = ACTION_STRICTNESS[local] ?? 0
+  // Without an org rule the default preset decides, which is not known here:
+  // only waiving an alert counts as weakening it.
+  if (org === undefined) {
+    return localStrictness ? 'strengthened' : 'weakened'
+  }
+  const orgStrictness = ACTION_STRICTNESS[org] ?? 0
+  if (localStrictness === orgStrictness) {
+    return undefined
+  }
+  return localStrictness > orgStrictness ? 'strengthened' : 'weakened'
+}
+
+/**
+ * Every local setting that acts on an alert type differently than the org
+ * policy, in the order the files list them. Expired exceptions no longer
+ * apply and are left out.
+ */
+export function diffLocalPolicy(
+  orgPolicy: SocketOrgPolicy,
+  local: {
+    policy?: FoundSocketPolicy | undefined
+    wrapper?: { path: string; wrapper: SocketYmlWrapper } | undefined
+  },
+): PolicyOverride[] {
+  const overrides: PolicyOverride[] = []
+  const add = (
+    alert: string,
+    localAction: string,
+    source: string,
+    scope?: string | undefined,
+  ) => {
+    const org = alert === '*' ? undefined : orgPolicy.rules[alert]
+    const effect = getOverrideEffect(localAction, org)
+    if (effect) {
+      overrides.push({
+        alert,
+        effect,
+        local: localAction,
+        ...(org ? { org } : {}),
+        ...(scope ? { scope } : {}),
+        source,
+      })
+    }
+  }
+  if (local.policy) {
+    const filename = path.basename(local.policy.path)
+    const { block, exceptions, warn } = local.policy.policy
+    for (const { 0: key, 1: rule, 2: action } of [
+      ['block', block, 'error'],
+      ['warn', warn, 'warn'],
+    ] as const) {
+      for (const alertType of rule.alerts) {
+        add(alertType, action, `${filename} ${key}.alerts`)
+      }
+      if (rule.severity) {
+        add(
+          '*',
+          action,
+          `${filename} ${key}.severity`,
+          `${rule.severity} severity and above`,
+        )
+      }
+    }
+    for (let i = 0, { length } = exceptions; i < length; i += 1) {
+      const exception = exceptions[i]!
+      if (isPolicyExceptionExpired(exception)) {
+        continue
+      }
+      const scope = [
+        ...exception.packages,
+        ...exception.paths.map(p => `path ${p}`),
+      ].join(', ')
+      const source = `${filename} exceptions[${i}]`
+      const alertTypes = exception.alerts.length ? exception.alerts : ['*']
+      for (const alertType of alertTypes) {
+        add(alertType, 'ignore', source, scope)
+      }
+    }
+  }
+  if (local.wrapper) {
+    const filename = path.basename(local.wrapper.path)
+    for (const { 0: alertType, 1: mode } of Object.entries(
+      local.wrapper.wrapper.alerts,
+    )) {
+      add(
+        alertType,
+        WRAPPER_MODE_ACTIONS[mode],
+        `${filename} wrapper.alerts`,
+        'install wrappers',
+      )
+    }
+  }
+  return overrides
+}
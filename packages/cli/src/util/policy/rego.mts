@@ -0,0 +1,216 @@
+/**
+ * Evaluate Rego policies against scan results with the Open Policy Agent CLI
+ * (https://www.openpolicyagent.org/docs/cli). The `opa` binary must be on
+ * PATH; the CLI does not bundle it.
+ *
+ * The policy is queried at `data.<package>` (`data.socket` by default) and
+ * may define two rules, in the style of conftest:
+ *
+ *   ```rego
+ *   package socket
+ *
+ *   deny contains msg if {
+ *     some pkg in input.packages
+ *     not pkg.dev
+ *     startswith(pkg.license, "GPL")
+ *     msg := sprintf("%s is GPL licensed", [pkg.purl])
+ *   }
+ *   ```
+ *
+ * `deny` entries fail the check, `warn` entries are reported. Each entry is a
+ * message string or an object with a `msg` field and any extra details.
+ */
+
+import { promises as fs } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { whichReal } from '@socketsecurity/lib-stable/bin/which'
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import { getErrorCause } from '../error/errors.mts'
+import { getArtifactPurlString } from '../purl/parse.mts'
+import { isPlainObject } from '../socket-yaml.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact } from '../alert/artifact.mts'
+
+export const REGO_DEFAULT_PACKAGE = 'socket'
+
+export type RegoViolation = {
+  details?: Record<string, unknown> | undefined
+  msg: string
+}
+
+export type RegoPolicyResult = {
+  deny: RegoViolation[]
+  warn: RegoViolation[]
+}
+
+export type RegoInputOptions = {
+  artifacts: SocketArtifact[]
+  orgSlug: string
+  scanId: string
+  securityPolicyRules?:
+    | Record<string, { action?: string | undefined } | undefined>
+    | undefined
+}
+
+/**
+ * Build the `input` document for a Rego policy: every artifact of the scan as
+ * returned by the API, with its purl and, on each alert, the action the org
+ * security policy assigns to it.
+ */
+export function buildRegoInput({
+  artifacts,
+  orgSlug,
+  scanId,
+  securityPolicyRules = {},
+}: RegoInputOptions): Record<string, unknown> {
+  return {
+    scan: { id: scanId, org: orgSlug },
+    securityPolicy: { rules: securityPolicyRules },
+    packages: artifacts.map(artifact => ({
+      ...artifact,
+      purl: getArtifactPurlString(artifact),
+      alerts: (artifact.alerts ?? []).map(alert => ({
+        ...alert,
+        action: alert.action ?? securityPolicyRules[alert.type]?.action,
+      })),
+    })),
+  }
+}
+
+function toRegoViolations(value: unknown): RegoViolation[] {
+  if (value === undefined || value === false) {
+    return []
+  }
+  // `deny := true` style rules carry no message.
+  if (value === true) {
+    return [{ msg: 'Denied by policy' }]
+  }
+  const entries = Array.isArray(value)
+    ? value
+    : isPlainObject(value)
+      ? Object.values(value)
+      : [value]
+  return entries.map(entry => {
+    if (isPlainObject(entry)) {
+      const { msg, ...details } = entry
+      return {
+        msg: typeof msg === 'string' ? msg : JSON.stringify(entry),
+        ...(Object.keys(details).length ? { details } : {}),
+      }
+    }
+    return { msg: typeof entry === 'string' ? entry : JSON.stringify(entry) }
+  })
+}
+
+/**
+ * Read the `deny` and `warn` rules out of `opa eval --format json` output.
+ * Returns undefined when the queried package is not defined by the policy.
+ */
+export function parseOpaEvalOutput(
+  stdout: string,
+): RegoPolicyResult | undefined {
+  const output = JSON.parse(stdout) as {
+    result?: Array<{ expressions?: Array<{ value?: unknown }> }> | undefined
+  }
+  const value = output.result?.[0]?.expressions?.[0]?.value
+  if (!isPlainObject(value)) {
+    return undefined
+  }
+  return {
+    deny: toRegoViolations(value['deny']),
+    warn: toRegoViolations(value['warn']),
+  }
+}
+
+function getOpaErrorCause(e: unknown): string {
+  const stderr = String(
+    (e as { stderr?: unknown } | undefined)?.stderr ?? '',
+  ).trim()
+  const stdout = String(
+    (e as { stdout?: unknown } | undefined)?.stdout ?? '',
+  ).trim()
+  // With --format json opa reports compile and runtime errors on stdout.
+  try {
+    const { errors } = JSON.parse(stdout) as {
+      errors?: Array<{ message?: string | undefined }> | undefined
+    }
+    if (errors?.length) {
+      return errors.map(error => error.message).join('; ')
+    }
+  } catch {}
+  return stderr || getErrorCause(e)
+}
+
+export async function evaluateRegoPolicy(
+  policyPath: string,
+  input: unknown,
+  regoPackage = REGO_DEFAULT_PACKAGE,
+): Promise<CResult<RegoPolicyResult>> {
+  const opaPath = await whichReal('opa', { nothrow: true })
+  if (!opaPath || Array.isArray(opaPath)) {
+    return {
+      ok: false,
+      message: 'Open Policy Agent not found',
+      cause:
+        'Rego policies are evaluated with the `opa` CLI; install it from https://www.openpolicyagent.org/docs#1-download-opa and make sure it is on PATH',
+    }
+  }
+  const query = `data.${regoPackage}`
+  const tmpDir = await fs.mkdtemp(path.join(os.tmpdir(), 'socket-rego-'))
+  try {
+    const inputPath = path.join(tmpDir, 'input.json')
+    await fs.writeFile(inputPath, JSON.stringify(input), 'utf8')
+    let stdout: string
+    try {
+      const result = await spawn(
+        opaPath,
+        [
+          'eval',
+          '--format',
+          'json',
+          '--data',
+          policyPath,
+          '--input',
+          inputPath,
+          query,
+        ],
+        { stdio: 'pipe' },
+      )
+      stdout = String(result.stdout ?? '')
+    } catch (e) {
+      debugDir('error', e)
+      return {
+        ok: false,
+        message: 'Rego policy evaluation failed',
+        cause: getOpaErrorCause(e),
+      }
+    }
+    let policyResult: RegoPolicyResult | undefined
+    try {
+      policyResult = parseOpaEvalOutput(stdout)
+    } catch (e) {
+      debugDir('error', { error: e, stdout })
+      return {
+        ok: false,
+        message: 'Rego policy evaluation failed',
+        cause: `Unable to parse the \`opa eval\` output: ${getErrorCause(e)}`,
+      }
+    }
+    if (!policyResult) {
+      return {
+        ok: false,
+        message: 'Rego policy evaluation failed',
+        cause: `${policyPath} does not define package ${regoPackage}; set --package to the package that holds your deny and warn rules`,
+      }
+    }
+    return { ok: true, data: policyResult }
+  } finally {
+    await safeDelete(tmpDir, { force: true })
+  }
+}
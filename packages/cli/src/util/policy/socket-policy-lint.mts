@@ -0,0 +1,447 @@
+/**
+ * Linter of `socket.policy.yml`. It keeps every valid field it finds and
+ * reports each problem with its location, which is what `socket policy lint`
+ * prints; unknown keys are warnings, invalid values errors.
+ */
+
+import { parse as yamlParse } from 'yaml'
+
+import {
+  POLICY_SEVERITIES,
+  QUARANTINE_ACTIONS,
+  createEmptySocketPolicy,
+  isPolicyExceptionExpired,
+} from './socket-policy.mts'
+import { getErrorCause } from '../error/errors.mts'
+import { isPlainObject } from '../socket-yaml.mts'
+
+import type {
+  PolicySeverity,
+  QuarantineAction,
+  SocketPolicy,
+  SocketPolicyException,
+  SocketPolicyQuarantine,
+  SocketPolicyRule,
+  SocketPolicyTrustList,
+} from './socket-policy.mts'
+
+export type PolicyLintIssue = {
+  level: 'error' | 'warning'
+  message: string
+  // Dotted location in the file, e.g. `exceptions[0].paths`.
+  path: string
+}
+
+export type PolicyLintResult = {
+  issues: PolicyLintIssue[]
+  policy: SocketPolicy
+}
+
+const TOP_LEVEL_KEYS = new Set([
+  'block',
+  'distrust',
+  'exceptions',
+  'licenses',
+  'quarantine',
+  'trust',
+  'version',
+  'warn',
+])
+
+const RULE_KEYS = new Set(['alerts', 'severity'])
+
+const LICENSE_KEYS = new Set(['allow', 'deny'])
+
+const QUARANTINE_KEYS = new Set(['action', 'days'])
+
+const TRUST_KEYS = new Set(['maintainers', 'packages'])
+
+const EXCEPTION_KEYS = new Set([
+  'alerts',
+  'expires',
+  'packages',
+  'paths',
+  'reason',
+])
+
+const EXPIRES_DATE_REGEXP = /^\d{4}-\d{2}-\d{2}$/
+
+function lintUnknownKeys(
+  value: Record<string, unknown>,
+  known: Set<string>,
+  prefix: string,
+  issues: PolicyLintIssue[],
+): void {
+  for (const key of Object.keys(value)) {
+    if (!known.has(key)) {
+      issues.push({
+        level: 'warning',
+        message: `Unknown key "${key}" is ignored`,
+        path: prefix ? `${prefix}.${key}` : key,
+      })
+    }
+  }
+}
+
+function lintStringList(
+  value: unknown,
+  location: string,
+  issues: PolicyLintIssue[],
+): string[] {
+  if (value === undefined) {
+    return []
+  }
+  if (!Array.isArray(value)) {
+    issues.push({
+      level: 'error',
+      message: 'Expected a list of strings',
+      path: location,
+    })
+    return []
+  }
+  const out: string[] = []
+  for (let i = 0, { length } = value; i < length; i += 1) {
+    const item = value[i]
+    if (typeof item === 'string' && item.trim()) {
+      out.push(item.trim())
+    } else {
+      issues.push({
+        level: 'error',
+        message: 'Expected a non-empty string',
+        path: `${location}[${i}]`,
+      })
+    }
+  }
+  return out
+}
+
+function lintRule(
+  value: unknown,
+  location: string,
+  issues: PolicyLintIssue[],
+): SocketPolicyRule {
+  if (value === undefined) {
+    return { alerts: [] }
+  }
+  if (!isPlainObject(value)) {
+    issues.push({
+      level: 'error',
+      message: 'Expected a mapping with `alerts` and/or `severity`',
+      path: location,
+    })
+    return { alerts: [] }
+  }
+  lintUnknownKeys(value, RULE_KEYS, location, issues)
+  const rule: SocketPolicyRule = {
+    alerts: lintStringList(value['alerts'], `${location}.alerts`, issues),
+  }
+  const severity = value['severity']
+  if (severity !== undefined) {
+    if (POLICY_SEVERITIES.includes(severity as PolicySeverity)) {
+      rule.severity = severity as PolicySeverity
+    } else {
+      issues.push({
+        level: 'error',
+        message: `Expected one of ${POLICY_SEVERITIES.join(', ')}${severity === 'medium' ? ' (Socket calls it "middle")' : ''}`,
+        path: `${location}.severity`,
+      })
+    }
+  }
+  return rule
+}
+
+function lintQuarantine(
+  value: unknown,
+  issues: PolicyLintIssue[],
+): SocketPolicyQuarantine | undefined {
+  if (!isPlainObject(value)) {
+    issues.push({
+      level: 'error',
+      message: 'Expected a mapping with `days` and optionally `action`',
+      path: 'quarantine',
+    })
+    return undefined
+  }
+  lintUnknownKeys(value, QUARANTINE_KEYS, 'quarantine', issues)
+  const action = value['action'] ?? 'block'
+  const days = value['days']
+  let isValid = true
+  if (typeof days !== 'number' || !Number.isFinite(days) || days <= 0) {
+    issues.push({
+      level: 'error',
+      message: 'Expected a positive number of days, e.g. 7',
+      path: 'quarantine.days',
+    })
+    isValid = false
+  }
+  if (!QUARANTINE_ACTIONS.includes(action as QuarantineAction)) {
+    issues.push({
+      level: 'error',
+      message: `Expected one of ${QUARANTINE_ACTIONS.join(', ')}`,
+      path: 'quarantine.action',
+    })
+    isValid = false
+  }
+  return isValid
+    ? { action: action as QuarantineAction, days: days as number }
+    : undefined
+}
+
+function lintTrustList(
+  value: unknown,
+  location: string,
+  issues: PolicyLintIssue[],
+): SocketPolicyTrustList | undefined {
+  if (!isPlainObject(value)) {
+    issues.push({
+      level: 'error',
+      message: 'Expected a mapping with `packages` and/or `maintainers`',
+      path: location,
+    })
+    return undefined
+  }
+  lintUnknownKeys(value, TRUST_KEYS, location, issues)
+  const list: SocketPolicyTrustList = {
+    maintainers: lintStringList(
+      value['maintainers'],
+      `${location}.maintainers`,
+      issues,
+    ),
+    packages: lintStringList(value['packages'], `${location}.packages`, issues),
+  }
+  if (list.packages.some(pattern => /^(?:pkg:)?\**$/.test(pattern))) {
+    issues.push({
+      level: 'error',
+      message: `A \`${location}\` pattern matches every package; name a scope or package`,
+      path: `${location}.packages`,
+    })
+    return undefined
+  }
+  return list
+}
+
+// YAML parses unquoted dates as Date objects; accept both forms.
+function lintExpires(
+  value: unknown,
+  location: string,
+  issues: PolicyLintIssue[],
+): string | undefined {
+  const expires =
+    value instanceof Date && !Number.isNaN(value.getTime())
+      ? value.toISOString().slice(0, 10)
+      : value
+  if (
+    typeof expires === 'string' &&
+    EXPIRES_DATE_REGEXP.test(expires) &&
+    !Number.isNaN(Date.parse(expires))
+  ) {
+    return expires
+  }
+  issues.push({
+    level: 'error',
+    message: 'Expected a date like 2026-12-31',
+    path: location,
+  })
+  return undefined
+}
+
+/**
+ * Validate an already-parsed policy document, collecting every problem rather
+ * than stopping at the first.
+ */
+export function lintSocketPolicyObject(content: unknown): PolicyLintResult {
+  const issues: PolicyLintIssue[] = []
+  const policy = createEmptySocketPolicy()
+  if (!isPlainObject(content)) {
+    issues.push({
+      level: 'error',
+      message: 'Expected the policy file to be a YAML mapping',
+      path: '',
+    })
+    return { issues, policy }
+  }
+  lintUnknownKeys(content, TOP_LEVEL_KEYS, '', issues)
+
+  if (content['version'] === undefined) {
+    issues.push({
+      level: 'warning',
+      message: 'Missing `version`; assuming version 1',
+      path: 'version',
+    })
+  } else if (content['version'] !== 1) {
+    issues.push({
+      level: 'error',
+      message: `Unsupported version ${JSON.stringify(content['version'])}; expected 1`,
+      path: 'version',
+    })
+  }
+
+  policy.block = lintRule(content['block'], 'block', issues)
+  policy.warn = lintRule(content['warn'], 'warn', issues)
+  for (const alert of policy.warn.alerts) {
+    if (policy.block.alerts.includes(alert)) {
+      issues.push({
+        level: 'warning',
+        message: `"${alert}" is also blocked; the block rule wins`,
+        path: 'warn.alerts',
+      })
+    }
+  }
+
+  const licenses = content['licenses']
+  if (licenses !== undefined) {
+    if (isPlainObject(licenses)) {
+      lintUnknownKeys(licenses, LICENSE_KEYS, 'licenses', issues)
+      policy.licenses = {
+        allow: lintStringList(licenses['allow'], 'licenses.allow', issues),
+        deny: lintStringList(licenses['deny'], 'licenses.deny', issues),
+      }
+      for (const license of policy.licenses.deny) {
+        if (policy.licenses.allow.includes(license)) {
+          issues.push({
+            level: 'error',
+            message: `"${license}" is both allowed and denied`,
+            path: 'licenses',
+          })
+        }
+      }
+    } else {
+      issues.push({
+        level: 'error',
+        message: 'Expected a mapping with `allow` and/or `deny`',
+        path: 'licenses',
+      })
+    }
+  }
+
+  if (content['quarantine'] !== undefined) {
+    const quarantine = lintQuarantine(content['quarantine'], issues)
+    if (quarantine) {
+      policy.quarantine = quarantine
+    }
+  }
+
+  for (const key of ['trust', 'distrust'] as const) {
+    if (content[key] !== undefined) {
+      const list = lintTrustList(content[key], key, issues)
+      if (list) {
+        policy[key] = list
+      }
+    }
+  }
+
+  const exceptions = content['exceptions']
+  if (exceptions !== undefined) {
+    if (Array.isArray(exceptions)) {
+      for (let i = 0, { length } = exceptions; i < length; i += 1) {
+        const location = `exceptions[${i}]`
+        const entry = exceptions[i]
+        if (!isPlainObject(entry)) {
+          issues.push({
+            level: 'error',
+            message: 'Expected a mapping',
+            path: location,
+          })
+          continue
+        }
+        lintUnknownKeys(entry, EXCEPTION_KEYS, location, issues)
+        const exception: SocketPolicyException = {
+          alerts: lintStringList(
+            entry['alerts'],
+            `${location}.alerts`,
+            issues,
+          ),
+          packages: lintStringList(
+            entry['packages'],
+            `${location}.packages`,
+            issues,
+          ),
+          paths: lintStringList(entry['paths'], `${location}.paths`, issues),
+          reason:
+            typeof entry['reason'] === 'string' ? entry['reason'].trim() : '',
+        }
+        const hasBadReason =
+          entry['reason'] !== undefined && typeof entry['reason'] !== 'string'
+        if (hasBadReason) {
+          issues.push({
+            level: 'error',
+            message: 'Expected a string',
+            path: `${location}.reason`,
+          })
+        }
+        if (entry['expires'] !== undefined) {
+          const expires = lintExpires(
+            entry['expires'],
+            `${location}.expires`,
+            issues,
+          )
+          if (expires) {
+            exception.expires = expires
+          }
+        }
+        if (
+          !exception.alerts.length &&
+          !exception.packages.length &&
+          !exception.paths.length
+        ) {
+          issues.push({
+            level: 'error',
+            message:
+              'An exception needs at least one of `alerts`, `packages` or `paths`; an empty one would waive everything',
+            path: location,
+          })
+          continue
+        }
+        if (!exception.reason) {
+          if (!hasBadReason) {
+            issues.push({
+              level: 'error',
+              message: 'Missing `reason`; say why this is waived',
+              path: `${location}.reason`,
+            })
+          }
+          continue
+        }
+        if (isPolicyExceptionExpired(exception)) {
+          issues.push({
+            level: 'warning',
+            message: `Expired on ${exception.expires}; the alerts it waived are reported again`,
+            path: `${location}.expires`,
+          })
+        }
+        policy.exceptions.push(exception)
+      }
+    } else {
+      issues.push({
+        level: 'error',
+        message: 'Expected a list of exceptions',
+        path: 'exceptions',
+      })
+    }
+  }
+
+  return { issues, policy }
+}
+
+export function formatPolicyLintIssue(issue: PolicyLintIssue): string {
+  return issue.path ? `${issue.path}: ${issue.message}` : issue.message
+}
+
+export function lintSocketPolicy(content: string): PolicyLintResult {
+  let parsed: unknown
+  try {
+    parsed = yamlParse(content)
+  } catch (e) {
+    return {
+      issues: [
+        {
+          level: 'error',
+          message: `Invalid YAML: ${getErrorCause(e)}`,
+          path: '',
+        },
+      ],
+      policy: createEmptySocketPolicy(),
+    }
+  }
+  return lintSocketPolicyObject(parsed)
+}
@@ -0,0 +1,212 @@
+/**
+ * @file `socket.policy.yml` types and parser. The policy file lets a
+ *   repository tighten or relax gating without org admin changes:
+ *
+ *   ```yaml
+ *   version: 1
+ *   block:
+ *     alerts: [malware, gitDependency]
+ *     severity: critical
+ *   warn:
+ *     alerts: [installScripts]
+ *     severity: high
+ *   licenses:
+ *     allow: [MIT, Apache-2.0, BSD-3-Clause, ISC]
+ *     deny: [AGPL-3.0-only]
+ *   quarantine:
+ *     days: 7
+ *     action: block
+ *   trust:
+ *     packages: ['pkg:npm/@acme/*']
+ *     maintainers: [acme-release-bot]
+ *   distrust:
+ *     packages: ['pkg:npm/@evil-corp/*']
+ *   exceptions:
+ *     - paths: ['tools/**']
+ *       alerts: [installScripts]
+ *       reason: Build tooling, never shipped.
+ *       expires: 2026-12-31
+ *     - packages: ['pkg:npm/@acme/*']
+ *       alerts: [recentlyPublished]
+ *       reason: Our own packages, published by our release pipeline.
+ *   ```
+ *
+ *   `quarantine` holds back versions published fewer than `days` days ago,
+ *   the window in which most malicious releases are found and taken down:
+ *   `block` (the default) refuses them, `warn` asks first. Exceptions for the
+ *   `recentlyPublished` alert exempt packages or whole scopes.
+ *
+ *   `trust` lists packages, or the maintainers (npm publishers) of packages,
+ *   whose `warn` alerts are not asked about; blocking alerts still block.
+ *   `distrust` lists packages and maintainers that are always blocked, even
+ *   without alerts and regardless of exceptions. Distrust wins over trust.
+ *
+ *   `parseSocketPolicy` rejects any file with lint errors (see
+ *   `./socket-policy-lint.mts`). Every exception needs a `reason`; one past
+ *   its `expires` date no longer applies, so the alerts it waived fail again.
+ */
+
+import path from 'node:path'
+
+import { debugDirNs, debugNs } from '@socketsecurity/lib-stable/debug/output'
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+
+import {
+  formatPolicyLintIssue,
+  lintSocketPolicy,
+} from './socket-policy-lint.mts'
+import {
+  SOCKET_POLICY_YAML,
+  SOCKET_POLICY_YML,
+} from '../../constants/socket.mts'
+
+import type { CResult } from '../../types.mts'
+
+export const POLICY_SEVERITIES = ['low', 'middle', 'high', 'critical'] as const
+
+export type PolicySeverity = (typeof POLICY_SEVERITIES)[number]
+
+export type SocketPolicyRule = {
+  alerts: string[]
+  // Alerts at or above this severity match the rule.
+  severity?: PolicySeverity | undefined
+}
+
+export type SocketPolicyException = {
+  alerts: string[]
+  // Last day (YYYY-MM-DD, UTC) the exception applies.
+  expires?: string | undefined
+  packages: string[]
+  paths: string[]
+  reason: string
+}
+
+export const QUARANTINE_ACTIONS = ['block', 'warn'] as const
+
+export type QuarantineAction = (typeof QUARANTINE_ACTIONS)[number]
+
+export type SocketPolicyQuarantine = {
+  action: QuarantineAction
+  // Minimum age of a version, in days since it was published.
+  days: number
+}
+
+export type SocketPolicyTrustList = {
+  // Publishers of the package version, as Socket reports its authors.
+  maintainers: string[]
+  packages: string[]
+}
+
+export type SocketPolicy = {
+  block: SocketPolicyRule
+  distrust?: SocketPolicyTrustList | undefined
+  exceptions: SocketPolicyException[]
+  licenses: {
+    allow: string[]
+    deny: string[]
+  }
+  quarantine?: SocketPolicyQuarantine | undefined
+  trust?: SocketPolicyTrustList | undefined
+  version: 1
+  warn: SocketPolicyRule
+}
+
+export type FoundSocketPolicy = {
+  path: string
+  policy: SocketPolicy
+}
+
+export function createEmptySocketPolicy(): SocketPolicy {
+  return {
+    block: { alerts: [] },
+    exceptions: [],
+    licenses: { allow: [], deny: [] },
+    version: 1,
+    warn: { alerts: [] },
+  }
+}
+
+/**
+ * Whether an exception is past its `expires` date. The date is inclusive: an
+ * exception expiring on 2026-12-31 still applies that whole day (UTC).
+ */
+export function isPolicyExceptionExpired(
+  exception: SocketPolicyException,
+  now = Date.now(),
+): boolean {
+  if (!exception.expires) {
+    return false
+  }
+  return now > Date.parse(`${exception.expires}T23:59:59.999Z`)
+}
+
+/**
+ * Parse a policy file, failing when the linter reports any error so a typo
+ * never silently loosens gating.
+ */
+export function parseSocketPolicy(content: string): CResult<SocketPolicy> {
+  const { issues, policy } = lintSocketPolicy(content)
+  const errors = issues.filter(issue => issue.level === 'error')
+  if (errors.length) {
+    return {
+      ok: false,
+      message: 'Invalid policy file',
+      cause: errors.map(formatPolicyLintIssue).join('; '),
+    }
+  }
+  return { ok: true, data: policy }
+}
+
+export function readSocketPolicySync(filepath: string): CResult<SocketPolicy> {
+  const content = safeReadFileSync(filepath)
+  if (content === undefined) {
+    return {
+      ok: false,
+      message: 'Policy file not found',
+      cause: `Unable to read ${filepath}`,
+    }
+  }
+  const policyCResult = parseSocketPolicy(
+    Buffer.isBuffer(content) ? content.toString('utf8') : content,
+  )
+  if (!policyCResult.ok) {
+    debugNs('error', `Failed to parse policy file: ${filepath}`)
+    debugDirNs('error', policyCResult)
+    return { ...policyCResult, message: `Invalid policy file ${filepath}` }
+  }
+  return policyCResult
+}
+
+/**
+ * Find the nearest `socket.policy.yml` (or `.yaml`) walking up from dir.
+ */
+export function findSocketPolicyPathSync(
+  dir = process.cwd(),
+): string | undefined {
+  let prevDir = undefined
+  while (dir !== prevDir) {
+    for (const name of [SOCKET_POLICY_YML, SOCKET_POLICY_YAML]) {
+      const filepath = path.join(dir, name)
+      if (safeReadFileSync(filepath) !== undefined) {
+        return filepath
+      }
+    }
+    prevDir = dir
+    dir = path.join(dir, '..')
+  }
+  return undefined
+}
+
+export function findSocketPolicySync(
+  dir = process.cwd(),
+): CResult<FoundSocketPolicy | undefined> {
+  const filepath = findSocketPolicyPathSync(dir)
+  if (!filepath) {
+    return { ok: true, data: undefined }
+  }
+  const policyCResult = readSocketPolicySync(filepath)
+  if (!policyCResult.ok) {
+    return policyCResult
+  }
+  return { ok: true, data: { path: filepath, policy: policyCResult.data } }
+}
@@ -0,0 +1,170 @@
+/**
+ * Ecosystem coordinates and their package URLs, behind `socket purl`.
+ *
+ * A coordinate is the ecosystem type, a colon and the package the way its
+ * own tooling writes it:
+ *
+ * - maven:group:artifact[:version]
+ * - pypi:name[==version]
+ * - npm:[@scope/]name[@version], golang:module[@version], and the same
+ *   `[namespace/]name[@version]` form for every other ecosystem.
+ *
+ * `go` is accepted for `golang`. Purls may leave out the `pkg:` prefix.
+ */
+
+import { createPurlObject, getPurlObject, normalizePurl } from './parse.mts'
+import { ALL_ECOSYSTEMS } from '../ecosystem/types.mts'
+import { getErrorCause } from '../error/errors.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type PurlParts = {
+  name: string
+  namespace?: string | undefined
+  // The purl as packageurl-js writes it back, e.g. with lowercased names.
+  purl: string
+  qualifiers?: Record<string, string> | undefined
+  subpath?: string | undefined
+  type: string
+  version?: string | undefined
+}
+
+const COORDINATE_TYPE_ALIASES: Record<string, string> = {
+  __proto__: null,
+  go: 'golang',
+} as unknown as Record<string, string>
+
+const KNOWN_TYPES = new Set<string>(ALL_ECOSYSTEMS)
+
+/**
+ * Coordinates start with `<type>:`, purls with `pkg:` or `<type>/`.
+ */
+export function isCoordinate(value: string): boolean {
+  return !value.startsWith('pkg:') && /^[a-zA-Z]+:/.test(value)
+}
+
+export function parsePurlParts(value: string): CResult<PurlParts> {
+  let purlObj
+  try {
+    purlObj = getPurlObject(normalizePurl(value.trim()))
+  } catch (e) {
+    return {
+      ok: false,
+      message: `Invalid purl ${value}`,
+      cause: getErrorCause(e),
+    }
+  }
+  const { name, namespace, qualifiers, subpath, type, version } = purlObj
+  return {
+    ok: true,
+    data: {
+      purl: purlObj.toString(),
+      type,
+      ...(namespace ? { namespace } : {}),
+      name,
+      ...(version ? { version } : {}),
+      ...(qualifiers && Object.keys(qualifiers).length
+        ? { qualifiers: qualifiers as Record<string, string> }
+        : {}),
+      ...(subpath ? { subpath } : {}),
+    },
+  }
+}
+
+function splitAt(value: string, separator: string): [string, string?] {
+  // A leading `@` is an npm scope, not a version.
+  const index = value.lastIndexOf(separator)
+  return index > 0
+    ? [value.slice(0, index), value.slice(index + separator.length)]
+    : [value]
+}
+
+function splitNamespace(value: string): [string | undefined, string] {
+  const index = value.lastIndexOf('/')
+  return index === -1
+    ? [undefined, value]
+    : [value.slice(0, index), value.slice(index + 1)]
+}
+
+export function coordinateToPurl(coordinate: string): CResult<string> {
+  const colonIndex = coordinate.indexOf(':')
+  const rawType = coordinate.slice(0, colonIndex).toLowerCase()
+  const type = COORDINATE_TYPE_ALIASES[rawType] ?? rawType
+  const rest = coordinate.slice(colonIndex + 1).trim()
+  if (colonIndex === -1 || !KNOWN_TYPES.has(type) || !rest) {
+    return {
+      ok: false,
+      message: `Invalid coordinate ${coordinate}`,
+      cause: 'Expected <ecosystem>:<package>, e.g. npm:lodash@4.17.21',
+    }
+  }
+  let namespace: string | undefined
+  let name: string | undefined
+  let version: string | undefined
+  if (type === 'maven') {
+    const parts = rest.split(':')
+    if (parts.length < 2 || parts.length > 3 || parts.some(p => !p)) {
+      return {
+        ok: false,
+        message: `Invalid coordinate ${coordinate}`,
+        cause: 'Expected maven:<group>:<artifact>[:<version>]',
+      }
+    }
+    namespace = parts[0]
+    name = parts[1]
+    version = parts[2]
+  } else {
+    const { 0: pkg, 1: pkgVersion } = splitAt(
+      rest,
+      type === 'pypi' ? '==' : '@',
+    )
+    const { 0: pkgNamespace, 1: pkgName } = splitNamespace(pkg)
+    namespace = pkgNamespace
+    name = pkgName
+    version = pkgVersion
+  }
+  const purlObj = createPurlObject({
+    type,
+    namespace,
+    name,
+    version: version || undefined,
+    throws: false,
+  })
+  if (!purlObj) {
+    return {
+      ok: false,
+      message: `Invalid coordinate ${coordinate}`,
+      cause: `${rest} is not a valid ${type} package`,
+    }
+  }
+  return { ok: true, data: purlObj.toString() }
+}
+
+/**
+ * The coordinate of a purl. Qualifiers and subpaths have no coordinate
+ * form and are dropped.
+ */
+export function purlToCoordinate(parts: PurlParts): string {
+  const { name, namespace, type, version } = parts
+  if (type === 'maven') {
+    return `maven:${namespace ?? ''}:${name}${version ? `:${version}` : ''}`
+  }
+  const pkg = namespace ? `${namespace}/${name}` : name
+  if (!version) {
+    return `${type}:${pkg}`
+  }
+  return `${type}:${pkg}${type === 'pypi' ? '==' : '@'}${version}`
+}
+
+/**
+ * The canonical purl of a purl or coordinate.
+ */
+export function normalizePurlOrCoordinate(value: string): CResult<string> {
+  if (isCoordinate(value)) {
+    return coordinateToPurl(value)
+  }
+  const partsCResult = parsePurlParts(value)
+  return partsCResult.ok
+    ? { ok: true, data: partsCResult.data.purl }
+    : partsCResult
+}
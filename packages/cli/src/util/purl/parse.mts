@@ -4,7 +4,8 @@
  *
  * PURL Format: pkg:type/namespace/name@version?qualifiers#subpath.
  *
- * Key Functions: - createPurlObject: Create PURL from components - isPurl:
+ * Key Functions: - createPurlObject: Create PURL from components -
+ * getArtifactPurlString: Canonical PURL string for a scan artifact - isPurl:
  * Check if string is valid PURL - normalizePurl: Normalize PURL format -
  * parsePurl: Parse PURL string to object - purlToString: Convert PURL object to
  * string.
@@ -116,6 +117,25 @@ export function createPurlObject(
   return undefined
 }
 
+/**
+ * Canonical purl string for a scan artifact. Falls back to naive string
+ * concatenation when the artifact components do not form a valid purl (e.g.
+ * an ecosystem packageurl-js does not know about).
+ */
+export function getArtifactPurlString(artifact: SocketArtifact): string {
+  const { name = '', namespace, type, version } = artifact
+  return (
+    createPurlObject({
+      type,
+      namespace,
+      name,
+      version,
+      throws: false,
+    })?.toString() ??
+    `pkg:${type}/${namespace ? `${namespace}/` : ''}${name}${version ? `@${version}` : ''}`
+  )
+}
+
 export type PurlObjectOptions = {
   throws?: boolean | undefined
 }
@@ -0,0 +1,103 @@
+/**
+ * Resolve what a pip install would add without installing it.
+ *
+ * Runs the install with `--dry-run --report -` (pip >= 22.2), which prints
+ * pip's JSON installation report to stdout: every distribution the resolver
+ * picked, wheels and sdists alike, with their metadata. The report is
+ * documented at https://pip.pypa.io/en/stable/reference/installation-report/.
+ */
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import { getErrorCause } from '../error/errors.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type ResolvePipInstallOptions = {
+  cwd?: string | undefined
+  // Report every resolved distribution, not just ones missing from the
+  // current environment. Used when the install targets a fresh venv (pipx).
+  ignoreInstalled?: boolean | undefined
+}
+
+/**
+ * PEP 503 name normalization, which is also what the pypi purl type uses.
+ */
+export function normalizePypiName(name: string): string {
+  return name.replace(/[-_.]+/g, '-').toLowerCase()
+}
+
+export function getPipReportPurls(report: unknown): string[] {
+  const install =
+    report && typeof report === 'object'
+      ? (report as { install?: unknown }).install
+      : undefined
+  if (!Array.isArray(install)) {
+    return []
+  }
+  const purls = new Set<string>()
+  for (let i = 0, { length } = install; i < length; i += 1) {
+    const metadata = (
+      install[i] as {
+        metadata?: { name?: unknown; version?: unknown } | undefined
+      }
+    )?.metadata
+    const name = metadata?.name
+    const version = metadata?.version
+    if (typeof name === 'string' && name && typeof version === 'string') {
+      purls.add(`pkg:pypi/${normalizePypiName(name)}@${version}`)
+    }
+  }
+  return [...purls]
+}
+
+/**
+ * Run `<pipCommand> install --dry-run --report - <args>` and return the purls
+ * of the distributions pip would install.
+ */
+export async function resolvePipInstall(
+  pipCommand: readonly string[],
+  args: readonly string[],
+  options?: ResolvePipInstallOptions | undefined,
+): Promise<CResult<string[]>> {
+  const { cwd = process.cwd(), ignoreInstalled } = {
+    __proto__: null,
+    ...options,
+  } as ResolvePipInstallOptions
+  const [bin = 'pip', ...prefixArgs] = pipCommand
+  const pipArgs = [
+    ...prefixArgs,
+    'install',
+    '--dry-run',
+    '--quiet',
+    '--report',
+    '-',
+    ...(ignoreInstalled ? ['--ignore-installed'] : []),
+    ...args,
+  ]
+  let stdout: string
+  try {
+    const result = await spawn(bin, pipArgs, { cwd, stdio: 'pipe' })
+    stdout = String(result.stdout ?? '')
+  } catch (e) {
+    debugDir('error', e)
+    return {
+      ok: false,
+      message: 'Unable to resolve packages',
+      cause: `\`${bin} ${pipArgs.join(' ')}\` failed (pip 22.2 or newer is required): ${getErrorCause(e)}`,
+    }
+  }
+  let report: unknown
+  try {
+    report = JSON.parse(stdout)
+  } catch (e) {
+    debugDir('error', e)
+    return {
+      ok: false,
+      message: 'Unable to resolve packages',
+      cause: `${bin} did not print an installation report; pip 22.2 or newer is required`,
+    }
+  }
+  return { ok: true, data: getPipReportPurls(report) }
+}
@@ -0,0 +1,136 @@
+/**
+ * Package imports of a project's JavaScript and TypeScript sources. A light
+ * static pass: specifiers of `import`, `export … from`, `import()`,
+ * `require()` and `require.resolve()` with string literals are collected
+ * and mapped to package names. Dynamic specifiers are not resolved.
+ *
+ * Key Functions: - findImportedPackages: Package names imported by the
+ * sources under a directory - getImportSpecifiers: Specifiers imported by
+ * one source file - getImportedPackageName: Package name of a bare
+ * specifier.
+ */
+
+import { promises as fs } from 'node:fs'
+import { isBuiltin } from 'node:module'
+
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+
+import { globWithGitIgnore } from '../fs/glob.mts'
+
+import type { SocketYml } from '../socket-yaml.mts'
+
+export const SOURCE_FILE_EXTENSIONS = [
+  'cjs',
+  'cts',
+  'js',
+  'jsx',
+  'mjs',
+  'mts',
+  'ts',
+  'tsx',
+] as const
+
+export type FindImportedPackagesOptions = {
+  socketConfig?: SocketYml | undefined
+}
+
+// `import x from 'a'`, `import 'a'`, `export * from 'a'`. Type only imports
+// are captured too so they can be told apart and skipped.
+const STATIC_IMPORT_REGEXP =
+  /\b(?:import|export)\s+(type\s+)?(?:[\w$*{}\s,]+?\s+from\s*)?(['"])([^'"\n]+)\2/g
+
+// `import('a')`, `require('a')`, `require.resolve('a')`.
+const CALL_IMPORT_REGEXP =
+  /\b(?:import|require(?:\.resolve)?)\s*\(\s*(['"`])([^'"`\n$]+)\1\s*\)/g
+
+const BLOCK_COMMENT_REGEXP = /\/\*[\s\S]*?\*\//g
+
+// Skips `//` preceded by `:` so URLs in strings survive.
+const LINE_COMMENT_REGEXP = /(^|[^:\\])\/\/.*$/gm
+
+// `node:fs`, `https://…`, `data:…` and other URL like specifiers.
+const PROTOCOL_REGEXP = /^[a-z][a-z\d+.-]*:/i
+
+/**
+ * The specifiers one source file imports, in order of appearance.
+ */
+export function getImportSpecifiers(content: string): string[] {
+  const code = content
+    .replace(BLOCK_COMMENT_REGEXP, '')
+    .replace(LINE_COMMENT_REGEXP, '$1')
+  const specifiers: string[] = []
+  for (const match of code.matchAll(STATIC_IMPORT_REGEXP)) {
+    if (!match[1]) {
+      specifiers.push(match[3]!)
+    }
+  }
+  for (const match of code.matchAll(CALL_IMPORT_REGEXP)) {
+    specifiers.push(match[2]!)
+  }
+  return specifiers
+}
+
+/**
+ * The package a bare specifier resolves to, e.g. `@scope/pkg` for
+ * `@scope/pkg/sub/path`. Undefined for relative paths, subpath imports,
+ * Node.js builtins and URLs.
+ */
+export function getImportedPackageName(specifier: string): string | undefined {
+  if (
+    !specifier ||
+    specifier.startsWith('.') ||
+    specifier.startsWith('/') ||
+    specifier.startsWith('#') ||
+    PROTOCOL_REGEXP.test(specifier)
+  ) {
+    return undefined
+  }
+  const parts = specifier.split('/')
+  if (specifier.startsWith('@')) {
+    // Path aliases like `@/components` are not packages.
+    return parts[0]!.length > 1 && parts[1]
+      ? `${parts[0]}/${parts[1]}`
+      : undefined
+  }
+  const name = parts[0]!
+  return isBuiltin(name) ? undefined : name
+}
+
+/**
+ * Names of the packages imported by the JavaScript and TypeScript sources
+ * under cwd. node_modules and git ignored files are skipped.
+ */
+export async function findImportedPackages(
+  cwd: string,
+  options?: FindImportedPackagesOptions | undefined,
+): Promise<Set<string>> {
+  const { socketConfig } = {
+    __proto__: null,
+    ...options,
+  } as FindImportedPackagesOptions
+  const filepaths = await globWithGitIgnore(
+    [`**/*.{${SOURCE_FILE_EXTENSIONS.join(',')}}`],
+    { cwd, socketConfig },
+  )
+  const names = new Set<string>()
+  for (let i = 0, { length } = filepaths; i < length; i += 1) {
+    const filepath = filepaths[i]!
+    let content: string
+    try {
+      content = await fs.readFile(filepath, 'utf8')
+    } catch {
+      debug(`skip: unreadable source file ${filepath}`)
+      continue
+    }
+    for (const specifier of getImportSpecifiers(content)) {
+      const name = getImportedPackageName(specifier)
+      if (name) {
+        names.add(name)
+      }
+    }
+  }
+  debug(
+    `found: ${names.size} imported packages in ${filepaths.length} source files`,
+  )
+  return names
+}
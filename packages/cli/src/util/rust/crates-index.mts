@@ -0,0 +1,131 @@
+/**
+ * Resolve crates.io version requirements the way cargo does, using the sparse
+ * registry index (https://index.crates.io). Each index file is one JSON
+ * record per published version; yanked versions are skipped.
+ *
+ * The index layout is documented at
+ * https://doc.rust-lang.org/cargo/reference/registry-index.html.
+ */
+
+// socket-lint: allow bare-semver -- lib-stable 6.0.9 doesn't publish ./external/semver; semver is bundled at build so no runtime dep leaks.
+import semver from 'semver'
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+
+import { getErrorCause } from '../error/errors.mts'
+import { socketHttpRequest } from '../socket/api-http.mts'
+
+import type { CResult } from '../../types.mts'
+
+export const CRATES_INDEX_URL = 'https://index.crates.io'
+
+type CratesIndexRecord = {
+  name?: unknown
+  vers?: unknown
+  yanked?: unknown
+}
+
+/**
+ * Path of a crate's file in the registry index: `1/a`, `2/ab`, `3/a/abc`,
+ * otherwise `se/rd/serde`. Names are case-insensitive in the index.
+ */
+export function getCratesIndexPath(name: string): string {
+  const lower = name.toLowerCase()
+  switch (lower.length) {
+    case 1:
+      return `1/${lower}`
+    case 2:
+      return `2/${lower}`
+    case 3:
+      return `3/${lower[0]}/${lower}`
+    default:
+      return `${lower.slice(0, 2)}/${lower.slice(2, 4)}/${lower}`
+  }
+}
+
+/**
+ * Translate a cargo version requirement into a node-semver range. A bare
+ * version is a caret requirement in cargo (`1.2` means `^1.2`), and multiple
+ * comparators are comma separated. An empty requirement matches anything.
+ */
+export function cargoReqToSemverRange(req: string | undefined): string {
+  if (!req?.trim()) {
+    return '*'
+  }
+  return req
+    .split(',')
+    .map(part => {
+      const comparator = part.trim()
+      return /^\d/.test(comparator) ? `^${comparator}` : comparator
+    })
+    .filter(Boolean)
+    .join(' ')
+}
+
+export function parseCratesIndexVersions(content: string): string[] {
+  const versions: string[] = []
+  const lines = content.split('\n')
+  for (let i = 0, { length } = lines; i < length; i += 1) {
+    const line = lines[i]!.trim()
+    if (!line) {
+      continue
+    }
+    let record: CratesIndexRecord
+    try {
+      record = JSON.parse(line) as CratesIndexRecord
+    } catch {
+      continue
+    }
+    if (typeof record.vers === 'string' && record.yanked !== true) {
+      versions.push(record.vers)
+    }
+  }
+  return versions
+}
+
+/**
+ * Pick the version cargo would select for `name@req` from crates.io. Resolves
+ * to undefined when the crate does not exist or nothing satisfies the
+ * requirement, which cargo itself will report.
+ */
+export async function resolveCrateVersion(
+  name: string,
+  req?: string | undefined,
+): Promise<CResult<string | undefined>> {
+  const url = `${CRATES_INDEX_URL}/${getCratesIndexPath(name)}`
+  let content: string
+  try {
+    const response = await socketHttpRequest(url)
+    if (response.status === 404) {
+      return { ok: true, data: undefined }
+    }
+    if (!response.ok) {
+      return {
+        ok: false,
+        message: 'Unable to resolve crate',
+        cause: `${url} returned HTTP ${response.status}`,
+      }
+    }
+    content = response.text()
+  } catch (e) {
+    debugDir('error', e)
+    return {
+      ok: false,
+      message: 'Unable to resolve crate',
+      cause: `Could not fetch ${url}: ${getErrorCause(e)}`,
+    }
+  }
+  const range = cargoReqToSemverRange(req)
+  if (!semver.validRange(range)) {
+    return {
+      ok: false,
+      message: 'Unable to resolve crate',
+      cause: `Unsupported version requirement "${req}" for ${name}`,
+    }
+  }
+  const version = semver.maxSatisfying(
+    parseCratesIndexVersions(content),
+    range,
+  )
+  return { ok: true, data: version ?? undefined }
+}
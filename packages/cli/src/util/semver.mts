@@ -16,3 +16,20 @@ export function getMajor(version: unknown): number | undefined {
   } catch {}
   return undefined
 }
+
+/**
+ * Compare two versions, tolerating a leading `v` (Go, git tags). Versions that
+ * are not valid semver sort before valid ones and otherwise compare as equal.
+ */
+export function compareVersions(a: string, b: string): number {
+  const left = semver.valid(a, { loose: true })
+  const right = semver.valid(b, { loose: true })
+  if (left && right) {
+    return semver.compare(left, right)
+  }
+  return left ? 1 : right ? -1 : 0
+}
+
+export function isPrerelease(version: string): boolean {
+  return !!semver.prerelease(version, { loose: true })?.length
+}
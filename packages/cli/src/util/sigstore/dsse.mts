@@ -0,0 +1,77 @@
+/**
+ * in-toto statements and their DSSE envelopes for `socket scan attest`.
+ *
+ * The signature covers the pre-authentication encoding (PAE) of the payload
+ * type and payload, not the payload alone, so a verifier cannot be tricked
+ * into reading the payload as another type.
+ *
+ * See https://github.com/in-toto/attestation/tree/main/spec/v1 and
+ * https://github.com/secure-systems-lab/dsse/blob/master/protocol.md
+ */
+
+export const IN_TOTO_STATEMENT_TYPE = 'https://in-toto.io/Statement/v1'
+
+export const IN_TOTO_PAYLOAD_TYPE = 'application/vnd.in-toto+json'
+
+export type InTotoSubject = {
+  // e.g. `{ gitCommit: '<sha1>' }` or `{ sha256: '<hex>' }`.
+  digest: Record<string, string>
+  name: string
+}
+
+export type InTotoStatement<T = unknown> = {
+  _type: typeof IN_TOTO_STATEMENT_TYPE
+  predicate: T
+  predicateType: string
+  subject: InTotoSubject[]
+}
+
+export type DsseEnvelope = {
+  // Base64 encoded.
+  payload: string
+  payloadType: string
+  signatures: Array<{ keyid: string; sig: string }>
+}
+
+export function createInTotoStatement<T>(
+  subject: InTotoSubject[],
+  predicateType: string,
+  predicate: T,
+): InTotoStatement<T> {
+  return { _type: IN_TOTO_STATEMENT_TYPE, subject, predicateType, predicate }
+}
+
+/**
+ * `DSSEv1 <len(type)> <type> <len(body)> <body>`, lengths in bytes.
+ */
+export function dssePae(payloadType: string, payload: Buffer): Buffer {
+  const type = Buffer.from(payloadType, 'utf8')
+  return Buffer.concat([
+    Buffer.from(`DSSEv1 ${type.length} `, 'utf8'),
+    type,
+    Buffer.from(` ${payload.length} `, 'utf8'),
+    payload,
+  ])
+}
+
+/**
+ * Sign a statement into a DSSE envelope. `sign` gets the PAE bytes and
+ * returns the raw signature.
+ */
+export function createDsseEnvelope(
+  statement: InTotoStatement,
+  sign: (pae: Buffer) => Buffer,
+): DsseEnvelope {
+  const payload = Buffer.from(JSON.stringify(statement), 'utf8')
+  return {
+    payload: payload.toString('base64'),
+    payloadType: IN_TOTO_PAYLOAD_TYPE,
+    signatures: [
+      {
+        // Keyless signatures are identified by the certificate instead.
+        keyid: '',
+        sig: sign(dssePae(IN_TOTO_PAYLOAD_TYPE, payload)).toString('base64'),
+      },
+    ],
+  }
+}
@@ -0,0 +1,335 @@
+/**
+ * Sigstore keyless signing for `socket scan attest`.
+ *
+ * An ephemeral ECDSA P-256 key signs the DSSE envelope. Fulcio binds the key
+ * to the OIDC identity of the signer, e.g. the GitHub Actions workflow, with
+ * a short-lived certificate, and Rekor records the signature in its public
+ * transparency log. The resulting bundle carries the certificate and the log
+ * entry, so it verifies with `cosign verify-blob-attestation` or sigstore-js
+ * long after the key is gone.
+ *
+ * See https://docs.sigstore.dev/about/bundle/
+ */
+
+import { X509Certificate, generateKeyPairSync, sign } from 'node:crypto'
+
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+
+import { createDsseEnvelope } from './dsse.mts'
+import { getNetworkErrorDiagnostics } from '../error/errors-network.mts'
+import { socketHttpRequest, tryReadResponseText } from '../socket/api-http.mts'
+
+import type { DsseEnvelope, InTotoStatement } from './dsse.mts'
+import type { CResult } from '../../types.mts'
+
+export const SIGSTORE_FULCIO_URL = 'https://fulcio.sigstore.dev'
+
+export const SIGSTORE_REKOR_URL = 'https://rekor.sigstore.dev'
+
+// Audience of the OIDC tokens Fulcio accepts.
+export const SIGSTORE_OIDC_AUDIENCE = 'sigstore'
+
+export const SIGSTORE_BUNDLE_MEDIA_TYPE =
+  'application/vnd.dev.sigstore.bundle.v0.3+json'
+
+// Issuers whose tokens Fulcio binds to the email claim instead of `sub`.
+const EMAIL_ISSUERS = new Set([
+  'https://accounts.google.com',
+  'https://oauth2.sigstore.dev/auth',
+])
+
+export type SigstoreTlogEntry = {
+  canonicalizedBody: string
+  inclusionPromise?: { signedEntryTimestamp: string } | undefined
+  inclusionProof?:
+    | {
+        checkpoint: { envelope: string }
+        hashes: string[]
+        logIndex: string
+        rootHash: string
+        treeSize: string
+      }
+    | undefined
+  integratedTime: string
+  kindVersion: { kind: string; version: string }
+  logId: { keyId: string }
+  logIndex: string
+}
+
+export type SigstoreDsseBundle = {
+  dsseEnvelope: DsseEnvelope
+  mediaType: typeof SIGSTORE_BUNDLE_MEDIA_TYPE
+  verificationMaterial: {
+    certificate: { rawBytes: string }
+    tlogEntries: SigstoreTlogEntry[]
+  }
+}
+
+export type KeylessSignOptions = {
+  fulcioUrl?: string | undefined
+  // OIDC token issued for the SIGSTORE_OIDC_AUDIENCE audience.
+  identityToken: string
+  rekorUrl?: string | undefined
+}
+
+export type KeylessSignResult = {
+  bundle: SigstoreDsseBundle
+  // Subject alternative name of the signing certificate, e.g. the workflow
+  // URL of a GitHub Actions run.
+  certificateIdentity: string
+  logIndex: number
+}
+
+type RekorLogEntry = {
+  body: string
+  integratedTime: number
+  logID: string
+  logIndex: number
+  verification?:
+    | {
+        inclusionProof?:
+          | {
+              checkpoint: string
+              hashes: string[]
+              logIndex: number
+              rootHash: string
+              treeSize: number
+            }
+          | undefined
+        signedEntryTimestamp?: string | undefined
+      }
+    | undefined
+}
+
+function hexToBase64(hex: string): string {
+  return Buffer.from(hex, 'hex').toString('base64')
+}
+
+function pemToBase64Der(pem: string): string {
+  return pem
+    .replace(/-----(BEGIN|END) CERTIFICATE-----/g, '')
+    .replace(/\s+/g, '')
+}
+
+export function getJwtClaims(token: string): Record<string, unknown> {
+  try {
+    const claims = JSON.parse(
+      Buffer.from(token.split('.')[1] ?? '', 'base64url').toString('utf8'),
+    )
+    return claims && typeof claims === 'object' ? claims : {}
+  } catch {
+    return {}
+  }
+}
+
+/**
+ * The claim Fulcio expects the proof of possession of the key to sign.
+ */
+export function getIdentityTokenChallenge(token: string): string {
+  const { email, iss, sub } = getJwtClaims(token)
+  const challenge = EMAIL_ISSUERS.has(String(iss)) ? email : sub
+  return typeof challenge === 'string' ? challenge : ''
+}
+
+async function postJson(
+  url: string,
+  body: unknown,
+  service: string,
+): Promise<CResult<unknown>> {
+  const startTime = Date.now()
+  try {
+    const response = await socketHttpRequest(url, {
+      body: JSON.stringify(body),
+      headers: {
+        accept: 'application/json',
+        'content-type': 'application/json',
+      },
+      method: 'POST',
+    })
+    const text = tryReadResponseText(response)
+    if (response.status < 200 || response.status >= 300) {
+      debug(`${service} request failed: ${text}`)
+      let detail = ''
+      try {
+        const { message } = JSON.parse(text ?? '') as { message?: unknown }
+        if (typeof message === 'string' && message) {
+          detail = `: ${message}`
+        }
+      } catch {}
+      return {
+        ok: false,
+        message: `${service} request failed`,
+        cause: `${url} answered with ${response.status} ${response.statusText}${detail}`,
+        data: { code: response.status },
+      }
+    }
+    try {
+      return { ok: true, data: JSON.parse(text ?? '') }
+    } catch {
+      return {
+        ok: false,
+        message: `${service} request failed`,
+        cause: `${url} returned invalid JSON`,
+      }
+    }
+  } catch (e) {
+    return {
+      ok: false,
+      message: `${service} request failed`,
+      cause: `${getNetworkErrorDiagnostics(e, Date.now() - startTime)} (url: ${url})`,
+    }
+  }
+}
+
+function toTlogEntry(entry: RekorLogEntry): SigstoreTlogEntry {
+  const { inclusionProof, signedEntryTimestamp } = entry.verification ?? {}
+  return {
+    canonicalizedBody: entry.body,
+    ...(signedEntryTimestamp
+      ? { inclusionPromise: { signedEntryTimestamp } }
+      : {}),
+    ...(inclusionProof
+      ? {
+          inclusionProof: {
+            checkpoint: { envelope: inclusionProof.checkpoint },
+            hashes: inclusionProof.hashes.map(hexToBase64),
+            logIndex: String(inclusionProof.logIndex),
+            rootHash: hexToBase64(inclusionProof.rootHash),
+            treeSize: String(inclusionProof.treeSize),
+          },
+        }
+      : {}),
+    integratedTime: String(entry.integratedTime),
+    kindVersion: { kind: 'dsse', version: '0.0.1' },
+    logId: { keyId: hexToBase64(entry.logID) },
+    logIndex: String(entry.logIndex),
+  }
+}
+
+function getCertificateIdentity(pem: string): string {
+  try {
+    // e.g. `URI:https://github.com/…` or `email:jdoe@example.com`.
+    const san = new X509Certificate(pem).subjectAltName ?? ''
+    return san.replace(/^[^:]+:/, '')
+  } catch {
+    return ''
+  }
+}
+
+/**
+ * Sign an in-toto statement with a Fulcio certificate for the identity token
+ * and record the signature in Rekor.
+ */
+export async function signStatementKeyless(
+  statement: InTotoStatement,
+  options: KeylessSignOptions,
+): Promise<CResult<KeylessSignResult>> {
+  const {
+    fulcioUrl = SIGSTORE_FULCIO_URL,
+    identityToken,
+    rekorUrl = SIGSTORE_REKOR_URL,
+  } = { __proto__: null, ...options } as KeylessSignOptions
+
+  const challenge = getIdentityTokenChallenge(identityToken)
+  if (!challenge) {
+    return {
+      ok: false,
+      message: 'Invalid OIDC identity token',
+      cause: 'The token is not a JWT with a subject claim',
+    }
+  }
+
+  const { privateKey, publicKey } = generateKeyPairSync('ec', {
+    namedCurve: 'P-256',
+  })
+
+  const certCResult = await postJson(
+    new URL('/api/v2/signingCert', fulcioUrl).href,
+    {
+      credentials: { oidcIdentityToken: identityToken },
+      publicKeyRequest: {
+        publicKey: {
+          algorithm: 'ECDSA',
+          content: publicKey.export({ format: 'pem', type: 'spki' }),
+        },
+        proofOfPossession: sign(
+          'sha256',
+          Buffer.from(challenge, 'utf8'),
+          privateKey,
+        ).toString('base64'),
+      },
+    },
+    'Fulcio',
+  )
+  if (!certCResult.ok) {
+    return certCResult
+  }
+  const { signedCertificateDetachedSct, signedCertificateEmbeddedSct } =
+    certCResult.data as {
+      signedCertificateDetachedSct?:
+        | { chain?: { certificates?: string[] | undefined } | undefined }
+        | undefined
+      signedCertificateEmbeddedSct?:
+        | { chain?: { certificates?: string[] | undefined } | undefined }
+        | undefined
+    }
+  const [certificate] =
+    (signedCertificateEmbeddedSct ?? signedCertificateDetachedSct)?.chain
+      ?.certificates ?? []
+  if (!certificate) {
+    return {
+      ok: false,
+      message: 'Fulcio request failed',
+      cause: 'Fulcio returned no signing certificate',
+    }
+  }
+
+  const envelope = createDsseEnvelope(statement, pae =>
+    sign('sha256', pae, privateKey),
+  )
+
+  const entryCResult = await postJson(
+    new URL('/api/v1/log/entries', rekorUrl).href,
+    {
+      apiVersion: '0.0.1',
+      kind: 'dsse',
+      spec: {
+        proposedContent: {
+          envelope: JSON.stringify(envelope),
+          verifiers: [Buffer.from(certificate, 'utf8').toString('base64')],
+        },
+      },
+    },
+    'Rekor',
+  )
+  if (!entryCResult.ok) {
+    return entryCResult
+  }
+  // Keyed by the entry UUID.
+  const [entry] = Object.values(
+    (entryCResult.data ?? {}) as Record<string, RekorLogEntry>,
+  )
+  if (!entry) {
+    return {
+      ok: false,
+      message: 'Rekor request failed',
+      cause: 'Rekor returned no log entry',
+    }
+  }
+
+  return {
+    ok: true,
+    data: {
+      bundle: {
+        dsseEnvelope: envelope,
+        mediaType: SIGSTORE_BUNDLE_MEDIA_TYPE,
+        verificationMaterial: {
+          certificate: { rawBytes: pemToBase64Der(certificate) },
+          tlogEntries: [toTlogEntry(entry)],
+        },
+      },
+      certificateIdentity: getCertificateIdentity(certificate),
+      logIndex: entry.logIndex,
+    },
+  }
+}
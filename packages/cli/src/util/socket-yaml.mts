@@ -9,6 +9,7 @@
  *   throws `SocketValidationError`.
  */
 
+import micromatch from 'micromatch'
 import { parse as yamlParse } from 'yaml'
 
 export type SocketYmlGitHub = {
@@ -20,11 +21,38 @@ export type SocketYmlGitHub = {
   pullRequestAlertsEnabled?: boolean | undefined
 }
 
+// CLI config keys a repository may set. Repositories are not trusted with
+// tokens, URLs or certificates, see the resolution order in
+// util/config-layers.mts.
+export type SocketYmlCli = {
+  defaultOrg?: string | undefined
+}
+
+export const WRAPPER_ALERT_MODES = ['allow', 'block', 'prompt', 'warn'] as const
+
+export type WrapperAlertMode = (typeof WRAPPER_ALERT_MODES)[number]
+
+export const WRAPPER_PROMPT_DEFAULTS = ['allow', 'block'] as const
+
+export type WrapperPromptDefault = (typeof WRAPPER_PROMPT_DEFAULTS)[number]
+
+// How the install wrappers (`socket npm`, `socket pip`, ...) treat alerts.
+export type SocketYmlWrapper = {
+  // Modes by alert type, e.g. `installScripts: prompt`.
+  alerts: { [alertType: string]: WrapperAlertMode }
+  // The answer to prompts when nobody can be asked, e.g. in CI.
+  nonInteractive?: WrapperPromptDefault | undefined
+}
+
 export type SocketYml = {
+  cli?: SocketYmlCli | undefined
   githubApp: SocketYmlGitHub
+  // `false` stops a nested socket.yml from inheriting its parents.
+  inherit?: boolean | undefined
   issueRules: { [issueName: string]: boolean }
   projectIgnorePaths: string[]
   version: 2
+  wrapper?: SocketYmlWrapper | undefined
 }
 
 export type SocketYmlV1Shape = {
@@ -78,6 +106,17 @@ export function asStringArray(value: unknown): string[] {
   return value.filter((v): v is string => typeof v === 'string')
 }
 
+export function buildCli(value: unknown): SocketYmlCli | undefined {
+  if (!isPlainObject(value)) {
+    return undefined
+  }
+  // `org` is a convenience alias for `defaultOrg`, as in the CLI config.
+  const defaultOrg = value['defaultOrg'] ?? value['org']
+  return typeof defaultOrg === 'string' && defaultOrg
+    ? { defaultOrg }
+    : undefined
+}
+
 export function buildGithub(value: unknown): SocketYmlGitHub {
   if (!isPlainObject(value)) {
     return {}
@@ -110,6 +149,109 @@ export function buildGithub(value: unknown): SocketYmlGitHub {
   return out
 }
 
+export function buildWrapper(value: unknown): SocketYmlWrapper | undefined {
+  if (!isPlainObject(value)) {
+    return undefined
+  }
+  const alerts: SocketYmlWrapper['alerts'] = {}
+  const rawAlerts = isPlainObject(value['alerts']) ? value['alerts'] : {}
+  const alertTypes = Object.keys(rawAlerts)
+  for (let i = 0, { length } = alertTypes; i < length; i += 1) {
+    const alertType = alertTypes[i]!
+    const mode = rawAlerts[alertType]
+    if (WRAPPER_ALERT_MODES.includes(mode as WrapperAlertMode)) {
+      alerts[alertType] = mode as WrapperAlertMode
+    }
+  }
+  const wrapper: SocketYmlWrapper = { alerts }
+  const nonInteractive = value['nonInteractive'] as WrapperPromptDefault
+  if (WRAPPER_PROMPT_DEFAULTS.includes(nonInteractive)) {
+    wrapper.nonInteractive = nonInteractive
+  }
+  return wrapper
+}
+
+/**
+ * Merge a nested socket.yml over the one of a parent directory. Keys of
+ * `cli`, `githubApp`, `issueRules` and `wrapper.alerts` set by the child win.
+ * The parent's projectIgnorePaths are rebased onto the child directory, at
+ * `childDir` relative to the parent, dropping those outside of it, and come
+ * first so a child `!pattern` can re-include a path.
+ */
+export function mergeSocketYml(
+  parent: SocketYml,
+  child: SocketYml,
+  childDir: string,
+): SocketYml {
+  const cli = { ...parent.cli, ...child.cli }
+  let wrapper: SocketYmlWrapper | undefined
+  if (parent.wrapper || child.wrapper) {
+    wrapper = {
+      alerts: { ...parent.wrapper?.alerts, ...child.wrapper?.alerts },
+    }
+    const nonInteractive =
+      child.wrapper?.nonInteractive ?? parent.wrapper?.nonInteractive
+    if (nonInteractive) {
+      wrapper.nonInteractive = nonInteractive
+    }
+  }
+  const inherited: string[] = []
+  for (let i = 0, { length } = parent.projectIgnorePaths; i < length; i += 1) {
+    const pattern = rebaseProjectIgnorePath(
+      parent.projectIgnorePaths[i]!,
+      childDir,
+    )
+    if (pattern !== undefined) {
+      inherited.push(pattern)
+    }
+  }
+  return {
+    ...(Object.keys(cli).length ? { cli } : {}),
+    githubApp: { ...parent.githubApp, ...child.githubApp },
+    issueRules: { ...parent.issueRules, ...child.issueRules },
+    projectIgnorePaths: [...inherited, ...child.projectIgnorePaths],
+    version: 2,
+    ...(wrapper ? { wrapper } : {}),
+  }
+}
+
+/**
+ * Rebase a gitignore-style pattern onto `dir`, a relative POSIX path. A
+ * pattern without an inner slash matches at any depth and is kept as is, an
+ * anchored one is kept when it can match inside `dir`.
+ */
+export function rebaseProjectIgnorePath(
+  pattern: string,
+  dir: string,
+): string | undefined {
+  const negated = pattern.startsWith('!') ? '!' : ''
+  const body = (negated ? pattern.slice(1) : pattern).trim()
+  const dirParts = dir.split('/').filter(p => p && p !== '.')
+  if (!dirParts.length || !body.replace(/\/$/, '').includes('/')) {
+    return pattern
+  }
+  // A trailing slash limits the pattern to directories.
+  const trailing = body.endsWith('/') ? '/' : ''
+  const parts = body.replace(/^\//, '').replace(/\/$/, '').split('/')
+  for (let i = 0, { length } = dirParts; i < length; i += 1) {
+    const part = parts[0]
+    if (part === undefined) {
+      // The pattern covers a parent of `dir`, so everything in it.
+      return `${negated}**`
+    }
+    if (part === '**') {
+      return `${negated}/${parts.join('/')}${trailing}`
+    }
+    if (!micromatch.isMatch(dirParts[i]!, part)) {
+      return undefined
+    }
+    parts.shift()
+  }
+  return parts.length
+    ? `${negated}/${parts.join('/')}${trailing}`
+    : `${negated}**`
+}
+
 export function isPlainObject(
   value: unknown,
 ): value is Record<string, unknown> {
@@ -192,10 +334,16 @@ export function parseSocketConfig(fileContent: string): SocketYml {
       parsed,
     )
   }
+  const cli = buildCli(parsed['cli'])
+  const inherit = asBoolean(parsed['inherit'])
+  const wrapper = buildWrapper(parsed['wrapper'])
   return {
+    ...(cli ? { cli } : {}),
     githubApp: buildGithub(parsed['githubApp']),
+    ...(inherit === false ? { inherit } : {}),
     issueRules: asBooleanRecord(parsed['issueRules']),
     projectIgnorePaths: asStringArray(parsed['projectIgnorePaths']),
     version: 2,
+    ...(wrapper ? { wrapper } : {}),
   }
 }
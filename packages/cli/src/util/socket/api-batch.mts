@@ -0,0 +1,145 @@
+/**
+ * Batched Socket API lookups. Splits a long list of items (usually purls)
+ * into batches and sends them through a pool of concurrent requests.
+ *
+ * A 429 response shrinks the pool to half its size and puts the batch back
+ * in the queue. Every worker then waits for the window the rate-limit
+ * headers ask for (see rate-limit.mts), or for a jittered exponential backoff
+ * when the server sent none. Each successful batch grows the pool back by one, up
+ * to the configured concurrency.
+ */
+
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+import { messageWithCauses } from '@socketsecurity/lib-stable/errors/message'
+
+import {
+  getRateLimitWaitMs,
+  pauseApiRequests,
+  waitForRateLimit,
+} from './rate-limit.mts'
+import {
+  getApiMaxRetries,
+  getApiRetryBackoffMs,
+  getRetryDelayMs,
+} from './retry.mts'
+import { HTTP_STATUS_TOO_MANY_REQUESTS } from '../../constants/http.mts'
+import { getSocketCliApiConcurrency } from '../../env/socket-cli-api-concurrency.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type ApiBatchOptions = {
+  batchSize?: number | undefined
+  // Defaults to SOCKET_CLI_API_CONCURRENCY, else DEFAULT_API_CONCURRENCY.
+  concurrency?: number | undefined
+  // Retries of a rate-limited batch before giving up. Defaults to
+  // --max-retries, else DEFAULT_MAX_RETRIES.
+  maxRetries?: number | undefined
+  // Backoff of the first retry without rate-limit headers, doubled for each
+  // following retry. Defaults to --retry-backoff, else DEFAULT_RETRY_DELAY_MS.
+  retryDelayMs?: number | undefined
+}
+
+export const DEFAULT_API_BATCH_SIZE = 500
+
+export const DEFAULT_API_CONCURRENCY = 4
+
+const DEFAULT_MAX_RETRIES = 5
+
+const DEFAULT_RETRY_DELAY_MS = 1000
+
+export function chunkItems<T>(items: T[], size: number): T[][] {
+  const chunks: T[][] = []
+  for (let i = 0, { length } = items; i < length; i += size) {
+    chunks.push(items.slice(i, i + size))
+  }
+  return chunks
+}
+
+export function isRateLimitedResult(result: CResult<unknown>): boolean {
+  if (result.ok) {
+    return false
+  }
+  const data = result.data as { code?: unknown } | undefined
+  return (
+    result.code === HTTP_STATUS_TOO_MANY_REQUESTS ||
+    data?.code === HTTP_STATUS_TOO_MANY_REQUESTS
+  )
+}
+
+/**
+ * Run `call` over batches of `items` and concatenate the results in the
+ * order of the items. Fails with the first failed batch.
+ */
+export async function runApiBatches<TItem, TData>(
+  items: TItem[],
+  call: (batch: TItem[], offset: number) => Promise<CResult<TData[]>>,
+  options?: ApiBatchOptions | undefined,
+): Promise<CResult<TData[]>> {
+  const {
+    batchSize = DEFAULT_API_BATCH_SIZE,
+    concurrency = getSocketCliApiConcurrency() ?? DEFAULT_API_CONCURRENCY,
+    maxRetries = getApiMaxRetries() ?? DEFAULT_MAX_RETRIES,
+    retryDelayMs = getApiRetryBackoffMs() ?? DEFAULT_RETRY_DELAY_MS,
+  } = { __proto__: null, ...options } as ApiBatchOptions
+
+  const batches = chunkItems(items, Math.max(1, batchSize))
+  const results: TData[][] = []
+  const retries: number[] = batches.map(() => 0)
+  const pending = batches.map((_, index) => index)
+  const maxActive = Math.max(1, concurrency)
+  let limit = maxActive
+  let active = 0
+  let failure: CResult<TData[]> | undefined
+
+  const { promise, resolve } = Promise.withResolvers<void>()
+
+  const runBatch = async (index: number): Promise<void> => {
+    let result: CResult<TData[]>
+    try {
+      await waitForRateLimit()
+      result = await call(batches[index]!, index * batchSize)
+    } catch (e) {
+      result = {
+        ok: false,
+        message: 'Socket API error',
+        cause: messageWithCauses(e as Error),
+      }
+    }
+    active -= 1
+    if (isRateLimitedResult(result) && retries[index]! < maxRetries) {
+      retries[index]! += 1
+      limit = Math.max(1, Math.floor(limit / 2))
+      if (!getRateLimitWaitMs()) {
+        pauseApiRequests(getRetryDelayMs(retries[index]!, retryDelayMs))
+      }
+      debug(
+        `Socket API batch ${index + 1}/${batches.length} rate limited, retry ${retries[index]}/${maxRetries} with ${limit} concurrent requests`,
+      )
+      pending.unshift(index)
+    } else if (!result.ok) {
+      failure ??= result
+    } else {
+      results[index] = result.data
+      limit = Math.min(maxActive, limit + 1)
+    }
+    schedule()
+  }
+
+  const schedule = (): void => {
+    while (!failure && active < limit && pending.length) {
+      active += 1
+      void runBatch(pending.shift()!)
+    }
+    if (!active && (failure || !pending.length)) {
+      resolve()
+    }
+  }
+
+  schedule()
+  await promise
+
+  if (failure) {
+    return failure
+  }
+  return { ok: true, data: ([] as TData[]).concat(...results) }
+}
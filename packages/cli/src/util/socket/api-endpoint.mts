@@ -0,0 +1,258 @@
+/**
+ * The Socket API endpoint: its base URL and how requests send the API token.
+ *
+ * The base URL is read from, highest priority first:
+ *
+ * - The --api-base-url flag
+ * - SOCKET_CLI_API_BASE_URL
+ * - The apiBaseUrl config value, of the active profile if any
+ *
+ * Single-tenant, on-prem and regional Socket deployments serve the API under
+ * their own origin. The last segment of the base URL path is the API
+ * version, e.g. `https://socket.example.com/v0/`. A bare origin passed to
+ * --api-base-url or `socket login` gets the version this CLI supports
+ * appended. The base URL in use, whichever of the three it comes from, is
+ * checked before any request: one that is not an http or https URL, or that
+ * names a version missing from SUPPORTED_API_VERSIONS, is refused up front
+ * rather than failing every request. This is a supported version check, the
+ * server is not asked which versions it serves.
+ *
+ * The API token is sent as the user of Basic auth by default. Deployments
+ * whose gateway expects a Bearer token set the apiAuthScheme config value,
+ * per profile, or SOCKET_CLI_API_AUTH_SCHEME to `bearer`. The SDK always
+ * sends Basic auth, so its requests get the header replaced through their
+ * agent, see withApiAuthorization().
+ *
+ * Both config keys are kept per profile. This module defines them and their
+ * env vars for the config, see getApiEndpointConfigEntries().
+ */
+
+import { Agent as HttpAgent } from 'node:http'
+import { Agent as HttpsAgent } from 'node:https'
+
+import { getSocketCliApiBaseUrl } from '@socketsecurity/lib-stable/env/socket-cli'
+
+import {
+  CONFIG_KEY_API_AUTH_SCHEME,
+  CONFIG_KEY_API_BASE_URL,
+} from '../../constants/config.mts'
+import { API_V0_URL } from '../../constants/socket.mts'
+import { getSocketCliApiAuthScheme } from '../../env/socket-cli-api-auth-scheme.mts'
+import { getConfigValueOrUndef } from '../config.mts'
+
+import type { CResult } from '../../types.mts'
+import type { ClientRequest } from 'node:http'
+
+export const API_AUTH_SCHEMES = ['basic', 'bearer'] as const
+
+export type ApiAuthScheme = (typeof API_AUTH_SCHEMES)[number]
+
+export type ApiEndpointConfig = {
+  apiAuthScheme?: string | null | undefined
+  apiBaseUrl?: string | null | undefined
+}
+
+export type ApiEndpointConfigKey = keyof ApiEndpointConfig
+
+// The API versions this CLI supports, checked against the base URL. Oldest
+// first, the last one is appended to bare origins.
+export const SUPPORTED_API_VERSIONS = ['v0']
+
+export type ApiEndpointFlags = {
+  apiBaseUrl?: string | undefined
+}
+
+const API_VERSION_REGEXP = /^v\d+$/
+
+let apiBaseUrlFlag: string | undefined
+
+function toApiAuthScheme(value: unknown): ApiAuthScheme | undefined {
+  const scheme = typeof value === 'string' ? value.toLowerCase() : undefined
+  return API_AUTH_SCHEMES.find(s => s === scheme)
+}
+
+/**
+ * An agent for requests to baseUrl, trusting the given CA certificates.
+ */
+export function getApiAgent(
+  baseUrl: string,
+  ca?: string[] | undefined,
+): HttpAgent {
+  return baseUrl.startsWith('http:')
+    ? new HttpAgent()
+    : new HttpsAgent(ca ? { ca } : {})
+}
+
+export function getApiAuthScheme(): ApiAuthScheme {
+  return (
+    toApiAuthScheme(getConfigValueOrUndef(CONFIG_KEY_API_AUTH_SCHEME)) ??
+    'basic'
+  )
+}
+
+/**
+ * The Authorization header value sending the API token with the scheme.
+ */
+export function getApiAuthorization(
+  apiToken: string,
+  scheme: ApiAuthScheme = getApiAuthScheme(),
+): string {
+  return scheme === 'bearer'
+    ? `Bearer ${apiToken}`
+    : `Basic ${btoa(`${apiToken}:`)}`
+}
+
+export function getApiBaseUrlFlag(): string | undefined {
+  return apiBaseUrlFlag
+}
+
+/**
+ * The endpoint config keys with their descriptions. A function declaration,
+ * so util/config-keys.mts can read them while this module is still loading.
+ */
+export function getApiEndpointConfigEntries(): Array<
+  [ApiEndpointConfigKey, string]
+> {
+  return [
+    [
+      CONFIG_KEY_API_AUTH_SCHEME,
+      'How Socket API requests send the API token: basic (default) or bearer, for deployments whose gateway expects a Bearer token',
+    ],
+    [
+      CONFIG_KEY_API_BASE_URL,
+      'Base URL of the Socket API endpoint, e.g. of a single-tenant or regional Socket deployment, like --api-base-url',
+    ],
+  ]
+}
+
+/**
+ * The env vars setting the endpoint config keys.
+ */
+export function getApiEndpointConfigEnv(): Array<
+  [ApiEndpointConfigKey, [string, () => unknown]]
+> {
+  return [
+    [
+      CONFIG_KEY_API_AUTH_SCHEME,
+      ['SOCKET_CLI_API_AUTH_SCHEME', getSocketCliApiAuthScheme],
+    ],
+    [
+      CONFIG_KEY_API_BASE_URL,
+      ['SOCKET_CLI_API_BASE_URL', getSocketCliApiBaseUrl],
+    ],
+  ]
+}
+
+/**
+ * Reset the endpoint flags for testing purposes.
+ *
+ * @internal
+ */
+export function resetApiEndpointFlagsForTesting(): void {
+  apiBaseUrlFlag = undefined
+}
+
+/**
+ * Check an API base URL and add what Socket deployments leave implied: the
+ * API version of a bare origin and the trailing slash relative paths are
+ * resolved against.
+ */
+export function resolveApiBaseUrl(value: string): CResult<string> {
+  let url: URL
+  try {
+    url = new URL(value)
+  } catch {
+    return {
+      ok: false,
+      message: 'Invalid Socket API base URL',
+      cause: `Expected an http or https URL (saw: ${value})`,
+    }
+  }
+  if (url.protocol !== 'http:' && url.protocol !== 'https:') {
+    return {
+      ok: false,
+      message: 'Invalid Socket API base URL',
+      cause: `Expected an http or https URL (saw: ${value})`,
+    }
+  }
+  const segments = url.pathname.split('/').filter(Boolean)
+  const version = segments.at(-1)
+  if (!segments.length) {
+    segments.push(SUPPORTED_API_VERSIONS.at(-1)!)
+  } else if (
+    version &&
+    API_VERSION_REGEXP.test(version) &&
+    !SUPPORTED_API_VERSIONS.includes(version)
+  ) {
+    return {
+      ok: false,
+      message: 'Unsupported Socket API version',
+      cause: `${value} serves the API ${version}, this Socket CLI speaks ${SUPPORTED_API_VERSIONS.join(', ')}; update the CLI or use a base URL ending in /${SUPPORTED_API_VERSIONS.at(-1)}/`,
+    }
+  }
+  url.pathname = `/${segments.join('/')}/`
+  return { ok: true, data: url.href }
+}
+
+/**
+ * Take over the flags of the current invocation. Fails on an unknown
+ * apiAuthScheme, or when the base URL in use, from the flag,
+ * SOCKET_CLI_API_BASE_URL or the apiBaseUrl config value, is refused by
+ * resolveApiBaseUrl(). The flag is left unset then.
+ */
+export function setApiEndpointFlags(
+  flags: ApiEndpointFlags,
+): CResult<undefined> {
+  const { apiBaseUrl } = { __proto__: null, ...flags } as ApiEndpointFlags
+  // Reset on every invocation so the flag doesn't leak into the next one.
+  apiBaseUrlFlag = undefined
+  const authScheme = getConfigValueOrUndef(CONFIG_KEY_API_AUTH_SCHEME)
+  if (authScheme && !toApiAuthScheme(authScheme)) {
+    return {
+      ok: false,
+      message: `Invalid ${CONFIG_KEY_API_AUTH_SCHEME} value`,
+      cause: `Expected ${API_AUTH_SCHEMES.join(' or ')} (saw: ${String(authScheme)})`,
+    }
+  }
+  const envBaseUrl = getSocketCliApiBaseUrl()
+  const configBaseUrl = getConfigValueOrUndef(CONFIG_KEY_API_BASE_URL)
+  const baseUrl = apiBaseUrl || envBaseUrl || configBaseUrl
+  if (baseUrl) {
+    const urlCResult = resolveApiBaseUrl(String(baseUrl))
+    if (!urlCResult.ok) {
+      // The check runs for every command, `socket config` included, so a bad
+      // config value needs the flag to be fixed.
+      const source = apiBaseUrl
+        ? '--api-base-url'
+        : envBaseUrl
+          ? 'SOCKET_CLI_API_BASE_URL'
+          : `the ${CONFIG_KEY_API_BASE_URL} config value; fix it with socket config unset ${CONFIG_KEY_API_BASE_URL} --api-base-url ${API_V0_URL}`
+      return { ...urlCResult, cause: `${urlCResult.cause}, from ${source}` }
+    }
+    if (apiBaseUrl) {
+      apiBaseUrlFlag = urlCResult.data
+    }
+  }
+  return { ok: true, data: undefined }
+}
+
+/**
+ * Make every request through agent send the Authorization header, replacing
+ * the one set by the caller. Requests hand themselves to their agent before
+ * their headers are written, so the header can still be changed then.
+ */
+export function withApiAuthorization<T extends HttpAgent>(
+  agent: T,
+  authorization: string,
+): T {
+  const { addRequest } = agent as unknown as {
+    addRequest: (req: ClientRequest, options: unknown) => void
+  }
+  Object.assign(agent, {
+    addRequest(req: ClientRequest, options: unknown) {
+      req.setHeader('Authorization', authorization)
+      return addRequest.call(agent, req, options)
+    },
+  })
+  return agent
+}
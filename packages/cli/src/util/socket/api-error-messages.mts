@@ -21,6 +21,10 @@ import {
   HTTP_STATUS_TOO_MANY_REQUESTS,
   HTTP_STATUS_UNAUTHORIZED,
 } from '../../constants/http.mts'
+import {
+  EXIT_CODE_API_ERROR,
+  EXIT_CODE_AUTH_ERROR,
+} from '../../constants/exit-codes.mts'
 import {
   SOCKET_CLI_ISSUES_URL,
   SOCKET_PRICING_URL,
@@ -123,6 +127,16 @@ export async function getErrorMessageForHttpStatusCode(code: number) {
   )
 }
 
+/**
+ * The exit code for a Socket API error response: auth errors for rejected
+ * tokens, API errors for any other status.
+ */
+export function getExitCodeForHttpStatusCode(code: number): number {
+  return code === HTTP_STATUS_UNAUTHORIZED || code === HTTP_STATUS_FORBIDDEN
+    ? EXIT_CODE_AUTH_ERROR
+    : EXIT_CODE_API_ERROR
+}
+
 /**
  * Log required permissions for a command when encountering 403 errors with
  * actionable guidance.
@@ -1,7 +1,7 @@
 /**
  * Low-level HTTP request helpers for the Socket API. Resolves the configured
- * API base URL and wraps httpRequest with extra CA certificate support and
- * safe response-text reading.
+ * API base URL and wraps httpRequest with extra CA certificate and proxy
+ * support and safe response-text reading.
  */
 
 import { getSocketCliApiBaseUrl } from '@socketsecurity/lib-stable/env/socket-cli'
@@ -12,7 +12,9 @@ import { CONFIG_KEY_API_BASE_URL } from '../../constants/config.mts'
 import { API_V0_URL } from '../../constants/socket.mts'
 import { getConfigValueOrUndef } from '../config.mts'
 
-import { getExtraCaCerts } from './sdk.mts'
+import { getApiBaseUrlFlag } from './api-endpoint.mts'
+import { getProxyAgent, isNoProxyUrl } from './network.mts'
+import { getDefaultProxyUrl, getExtraCaCerts } from './sdk.mts'
 
 import type { HttpRequestOptions } from '@socketsecurity/lib-stable/http-request/request-types'
 import type { HttpResponse } from '@socketsecurity/lib-stable/http-request/response-types'
@@ -20,24 +22,30 @@ import type { HttpResponse } from '@socketsecurity/lib-stable/http-request/respo
 // The Socket API server that should be used for operations.
 export function getDefaultApiBaseUrl(): string | undefined {
   const baseUrl =
-    getSocketCliApiBaseUrl() || getConfigValueOrUndef(CONFIG_KEY_API_BASE_URL)
+    getApiBaseUrlFlag() ||
+    getSocketCliApiBaseUrl() ||
+    getConfigValueOrUndef(CONFIG_KEY_API_BASE_URL)
   if (isNonEmptyString(baseUrl)) {
     return baseUrl
   }
   return API_V0_URL
 }
 
-// Wraps httpRequest with extra CA certificates from SSL_CERT_FILE.
+// Wraps httpRequest with the extra CA certificates and the proxy of the CLI,
+// see network.mts.
 export async function socketHttpRequest(
   url: string,
   options?: HttpRequestOptions | undefined,
 ): Promise<HttpResponse> {
   const ca = getExtraCaCerts()
-  /* c8 ignore start - SSL_CERT_FILE not set in tests; getExtraCaCerts returns undefined */
-  if (ca) {
-    return await httpRequest(url, { ...(options ?? {}), ca })
+  const proxyUrl = isNoProxyUrl(url) ? undefined : getDefaultProxyUrl()
+  if (ca || proxyUrl) {
+    return await httpRequest(url, {
+      ...(options ?? {}),
+      ...(ca ? { ca } : {}),
+      ...(proxyUrl ? { agent: getProxyAgent(url, proxyUrl, ca) } : {}),
+    } as HttpRequestOptions)
   }
-  /* c8 ignore stop */
   return await httpRequest(url, options)
 }
 
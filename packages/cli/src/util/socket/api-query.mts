@@ -8,18 +8,30 @@ import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
 import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
 
 import { CONFIG_KEY_API_BASE_URL } from '../../constants/config.mts'
+import {
+  EXIT_CODE_API_ERROR,
+  EXIT_CODE_AUTH_ERROR,
+} from '../../constants/exit-codes.mts'
 import { debugApiResponse } from '../debug.mts'
 import { ConfigError, getNetworkErrorDiagnostics } from '../error/errors.mts'
+import { logApiResponse } from '../log/structured.mts'
 
 import {
   getErrorMessageForHttpStatusCode,
+  getExitCodeForHttpStatusCode,
   logPermissionsFor403,
 } from './api-error-messages.mts'
+import { getApiAuthorization } from './api-endpoint.mts'
 import {
   getDefaultApiBaseUrl,
   socketHttpRequest,
   tryReadResponseText,
 } from './api-http.mts'
+import {
+  getApiMaxRetries,
+  getApiRetryBackoffMs,
+  getApiTimeoutMs,
+} from './retry.mts'
 import { getDefaultApiToken } from './sdk.mts'
 
 import type { CResult } from '../../types.mts'
@@ -35,14 +47,19 @@ export async function queryApi(path: string, apiToken: string) {
   }
   /* c8 ignore stop */
 
+  // Only reads are retried here, see sendApiRequest() for writes.
+  const retries = getApiMaxRetries()
+  const retryDelay = getApiRetryBackoffMs()
   return await socketHttpRequest(
     `${baseUrl}${baseUrl.endsWith('/') ? '' : '/'}${path}`,
     {
       method: 'GET',
       headers: {
-        Authorization: `Basic ${btoa(`${apiToken}:`)}`,
+        Authorization: getApiAuthorization(apiToken),
       },
-      timeout: 30_000,
+      ...(retries !== undefined ? { retries } : {}),
+      ...(retryDelay !== undefined ? { retryDelay } : {}),
+      timeout: getApiTimeoutMs() ?? 30_000,
     },
   )
 }
@@ -112,6 +129,7 @@ export async function queryApiSafeTextWithStatus(
       message: 'Authentication Error',
       cause:
         'User must be authenticated to run this command. Run `socket login` and enter your Socket API token.',
+      code: EXIT_CODE_AUTH_ERROR,
     }
   }
 
@@ -136,6 +154,13 @@ export async function queryApiSafeTextWithStatus(
         `Received Socket API response (after requesting ${description}).`,
       )
     }
+    logApiResponse({
+      durationMs,
+      headers: result.headers,
+      method: 'GET',
+      status: result.status,
+      url: fullUrl,
+    })
     // Log success for debugging.
     debugApiResponse(description || 'Query API', result.status, undefined, {
       method: 'GET',
@@ -161,6 +186,8 @@ export async function queryApiSafeTextWithStatus(
       headers: { Authorization: '[REDACTED]' },
     })
 
+    logApiResponse({ durationMs, error: e, method: 'GET', url: fullUrl })
+
     // Provide detailed network diagnostics for fetch errors.
     const networkDiagnostics = getNetworkErrorDiagnostics(e, durationMs)
     const message = 'API request failed'
@@ -169,6 +196,7 @@ export async function queryApiSafeTextWithStatus(
       ok: false,
       message,
       cause: `${networkDiagnostics} (path: ${path})`,
+      code: EXIT_CODE_API_ERROR,
     }
   }
 
@@ -195,6 +223,7 @@ export async function queryApiSafeTextWithStatus(
       ok: false,
       message: 'Socket API error',
       cause: `${result.statusText} (reason: ${await getErrorMessageForHttpStatusCode(status)}) (path: ${path})`,
+      code: getExitCodeForHttpStatusCode(status),
       data: {
         code: status,
       },
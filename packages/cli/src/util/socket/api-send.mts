@@ -7,18 +7,26 @@
 import { debug, debugDir } from '@socketsecurity/lib-stable/debug/output'
 import { getDefaultSpinner } from '@socketsecurity/lib-stable/spinner/default'
 
+import {
+  EXIT_CODE_API_ERROR,
+  EXIT_CODE_AUTH_ERROR,
+} from '../../constants/exit-codes.mts'
 import { debugApiResponse } from '../debug.mts'
 import { getNetworkErrorDiagnostics } from '../error/errors.mts'
+import { logApiResponse } from '../log/structured.mts'
 
 import {
   getErrorMessageForHttpStatusCode,
+  getExitCodeForHttpStatusCode,
   logPermissionsFor403,
 } from './api-error-messages.mts'
+import { getApiAuthorization } from './api-endpoint.mts'
 import {
   getDefaultApiBaseUrl,
   socketHttpRequest,
   tryReadResponseText,
 } from './api-http.mts'
+import { getApiTimeoutMs } from './retry.mts'
 import { getDefaultApiToken } from './sdk.mts'
 
 import type { CResult } from '../../types.mts'
@@ -44,6 +52,7 @@ export async function sendApiRequest<T>(
       message: 'Authentication Error',
       cause:
         'User must be authenticated to run this command. To log in, run the command `socket login` and enter your Socket API token.',
+      code: EXIT_CODE_AUTH_ERROR,
     }
   }
 
@@ -79,11 +88,12 @@ export async function sendApiRequest<T>(
     result = await socketHttpRequest(fullUrl, {
       body: body ? JSON.stringify(body) : undefined,
       headers: {
-        Authorization: `Basic ${btoa(`${apiToken}:`)}`,
+        Authorization: getApiAuthorization(apiToken),
         'Content-Type': 'application/json',
       },
       method,
-      timeout: 60_000,
+      // Writes are not retried, a repeated POST may apply twice.
+      timeout: getApiTimeoutMs() ?? 60_000,
     })
     const durationMs = Date.now() - startTime
     if (description) {
@@ -91,6 +101,13 @@ export async function sendApiRequest<T>(
         `Received Socket API response (after requesting ${description}).`,
       )
     }
+    logApiResponse({
+      durationMs,
+      headers: result.headers,
+      method,
+      status: result.status,
+      url: fullUrl,
+    })
     // Log success for debugging.
     debugApiResponse(
       description || 'Send API Request',
@@ -127,6 +144,8 @@ export async function sendApiRequest<T>(
       },
     })
 
+    logApiResponse({ durationMs, error: e, method, url: fullUrl })
+
     // Provide detailed network diagnostics for fetch errors.
     const networkDiagnostics = getNetworkErrorDiagnostics(e, durationMs)
     const message = 'API request failed'
@@ -135,6 +154,7 @@ export async function sendApiRequest<T>(
       ok: false,
       message,
       cause: `${networkDiagnostics} (path: ${path})`,
+      code: EXIT_CODE_API_ERROR,
     }
   }
 
@@ -164,6 +184,7 @@ export async function sendApiRequest<T>(
       ok: false,
       message: 'Socket API error',
       cause: `${result.statusText} (reason: ${await getErrorMessageForHttpStatusCode(status)}) (path: ${path})`,
+      code: getExitCodeForHttpStatusCode(status),
       data: {
         code: status,
       },
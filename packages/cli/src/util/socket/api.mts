@@ -29,10 +29,14 @@ import {
 } from '@socketsecurity/lib-stable/errors/message'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
 
+import { EXIT_CODE_API_ERROR } from '../../constants/exit-codes.mts'
 import { debugApiResponse } from '../debug.mts'
 import { buildErrorCause } from '../error/errors.mts'
 
-import { logPermissionsFor403 } from './api-error-messages.mts'
+import {
+  getExitCodeForHttpStatusCode,
+  logPermissionsFor403,
+} from './api-error-messages.mts'
 
 import type { CResult } from '../../types.mts'
 import type { SpinnerInstance } from '@socketsecurity/lib-stable/spinner/types'
@@ -53,6 +57,7 @@ const NO_ERROR_MESSAGE = 'No error message returned'
 export {
   getCommandRequirements,
   getErrorMessageForHttpStatusCode,
+  getExitCodeForHttpStatusCode,
   logPermissionsFor403,
 } from './api-error-messages.mts'
 export type { CommandRequirements } from './api-error-messages.mts'
@@ -122,6 +127,7 @@ export async function handleApiCall<T extends SocketSdkOperations>(
       ok: false,
       message: 'Socket API error',
       cause: messageWithCauses(e as Error),
+      code: EXIT_CODE_API_ERROR,
     }
     if (description) {
       logger.fail(`An error was thrown while requesting ${description}`)
@@ -158,6 +164,7 @@ export async function handleApiCall<T extends SocketSdkOperations>(
       ok: false,
       message: 'Socket API error',
       cause: causeWithEndpoint,
+      code: getExitCodeForHttpStatusCode(sdkResult.status),
       data: {
         code: sdkResult.status,
       },
@@ -198,6 +205,7 @@ export async function handleApiCallNoSpinner<T extends SocketSdkOperations>(
       ok: false,
       message,
       ...(cause ? { cause } : {}),
+      code: EXIT_CODE_API_ERROR,
     }
   }
 
@@ -225,6 +233,7 @@ export async function handleApiCallNoSpinner<T extends SocketSdkOperations>(
       ok: false,
       message: 'Socket API error',
       cause: causeWithEndpoint,
+      code: getExitCodeForHttpStatusCode(sdkResult.status),
       data: {
         code: sdkResult.status,
       },
@@ -0,0 +1,180 @@
+/**
+ * Proxy and CA certificate settings shared by Socket API requests and the
+ * tools the CLI spawns.
+ *
+ * CA certificates, highest priority first:
+ *
+ * - The --cacert flag
+ * - The caCert config value
+ * - NODE_EXTRA_CA_CERTS, which Node.js loads at process startup
+ * - SSL_CERT_FILE
+ *
+ * The proxy comes from SOCKET_CLI_API_PROXY, its HTTPS_PROXY / HTTP_PROXY
+ * aliases, or the apiProxy config value (see getDefaultProxyUrl() in
+ * sdk.mts). Hosts matching NO_PROXY are reached directly.
+ *
+ * applyNetworkEnv() exports the settings to process.env, so spawned package
+ * managers and dlx tools use the same proxy and trust the same CA.
+ */
+
+import { HttpProxyAgent, HttpsProxyAgent } from 'hpagent'
+
+import { CONFIG_KEY_CA_CERT } from '../../constants/config.mts'
+import { getConfigValueOrUndef } from '../config.mts'
+
+import type { Agent } from 'node:http'
+
+export type CaCertSource =
+  | 'NODE_EXTRA_CA_CERTS'
+  | 'SSL_CERT_FILE'
+  | 'config'
+  | 'flag'
+
+export type CaCertSetting = {
+  path: string
+  source: CaCertSource
+}
+
+const PROXY_ENV_KEYS = [
+  'HTTPS_PROXY',
+  'https_proxy',
+  'HTTP_PROXY',
+  'http_proxy',
+]
+
+// Node.js reads NODE_EXTRA_CA_CERTS once, before any module is loaded, so a
+// value set later by applyNetworkEnv() only reaches child processes.
+const startupNodeExtraCaCerts =
+  process.env['NODE_EXTRA_CA_CERTS'] || undefined
+
+// The proxy variable set by the user, before applyNetworkEnv() adds its own.
+const startupProxyEnvKey = ['SOCKET_CLI_API_PROXY', ...PROXY_ENV_KEYS].find(
+  key => process.env[key],
+)
+
+let caCertFlag: string | undefined
+
+// `[::1]:8080`, `::1`, `example.com:8080` or `.example.com`.
+function splitNoProxyEntry(entry: string): [string, string | undefined] {
+  const bracketed = /^\[([^\]]+)\](?::(\d+))?$/.exec(entry)
+  if (bracketed) {
+    return [bracketed[1]!, bracketed[2]]
+  }
+  const hostPort = /^([^:]+):(\d+)$/.exec(entry)
+  const host = hostPort ? hostPort[1]! : entry
+  return [host.replace(/^\*?\./, ''), hostPort?.[2]]
+}
+
+/**
+ * Export the proxy and CA certificate to process.env for child processes,
+ * without overriding variables the user set.
+ */
+export function applyNetworkEnv(proxyUrl: string | undefined): void {
+  if (proxyUrl && !PROXY_ENV_KEYS.some(key => process.env[key])) {
+    process.env['HTTPS_PROXY'] = proxyUrl
+    process.env['HTTP_PROXY'] = proxyUrl
+  }
+  const caCert = getCaCert()
+  if (caCert && !startupNodeExtraCaCerts) {
+    process.env['NODE_EXTRA_CA_CERTS'] = caCert.path
+  }
+}
+
+export function getCaCert(): CaCertSetting | undefined {
+  if (caCertFlag) {
+    return { path: caCertFlag, source: 'flag' }
+  }
+  const configValue: unknown = getConfigValueOrUndef(CONFIG_KEY_CA_CERT)
+  if (typeof configValue === 'string' && configValue) {
+    return { path: configValue, source: 'config' }
+  }
+  if (startupNodeExtraCaCerts) {
+    return { path: startupNodeExtraCaCerts, source: 'NODE_EXTRA_CA_CERTS' }
+  }
+  const sslCertFile = process.env['SSL_CERT_FILE']
+  if (sslCertFile) {
+    return { path: sslCertFile, source: 'SSL_CERT_FILE' }
+  }
+  return undefined
+}
+
+export function getNoProxy(): string {
+  return process.env['NO_PROXY'] || process.env['no_proxy'] || ''
+}
+
+/**
+ * The environment variable the proxy was configured with, if any.
+ */
+export function getProxyEnvKey(): string | undefined {
+  return startupProxyEnvKey
+}
+
+/**
+ * An agent sending requests for targetUrl through proxyUrl, trusting the
+ * given CA certificates for both the proxy and the target.
+ */
+export function getProxyAgent(
+  targetUrl: string,
+  proxyUrl: string,
+  ca?: string[] | undefined,
+): Agent {
+  // Usage of HttpProxyAgent vs. HttpsProxyAgent based on the chart at:
+  // https://github.com/delvedor/hpagent?tab=readme-ov-file#usage
+  const ProxyAgent = targetUrl.startsWith('http:')
+    ? HttpProxyAgent
+    : HttpsProxyAgent
+  return new ProxyAgent({
+    proxy: proxyUrl,
+    ...(ca ? { ca, proxyConnectOptions: { ca } } : {}),
+  })
+}
+
+/**
+ * Whether Node.js loaded NODE_EXTRA_CA_CERTS at startup.
+ */
+export function isNodeExtraCaCertsLoaded(): boolean {
+  return !!startupNodeExtraCaCerts
+}
+
+/**
+ * Match a URL against a NO_PROXY list: `*`, host names, `.domain` suffixes
+ * and optional `:port`, separated by commas or spaces.
+ */
+export function isNoProxyUrl(url: string, noProxy = getNoProxy()): boolean {
+  let parsed: URL
+  try {
+    parsed = new URL(url)
+  } catch {
+    return false
+  }
+  const host = parsed.hostname.toLowerCase().replace(/^\[|\]$/g, '')
+  const port = parsed.port || (parsed.protocol === 'http:' ? '80' : '443')
+  const entries = noProxy.toLowerCase().split(/[\s,]+/).filter(Boolean)
+  for (let i = 0, { length } = entries; i < length; i += 1) {
+    const entry = entries[i]!
+    if (entry === '*') {
+      return true
+    }
+    const { 0: entryHost, 1: entryPort } = splitNoProxyEntry(entry)
+    if (entryPort && entryPort !== port) {
+      continue
+    }
+    if (host === entryHost || host.endsWith(`.${entryHost}`)) {
+      return true
+    }
+  }
+  return false
+}
+
+/**
+ * Reset the --cacert flag for testing purposes.
+ *
+ * @internal
+ */
+export function resetNetworkForTesting(): void {
+  caCertFlag = undefined
+}
+
+export function setCaCertFlag(certPath: string | undefined): void {
+  caCertFlag = certPath || undefined
+}
@@ -0,0 +1,137 @@
+/**
+ * Process-wide Socket API rate-limit state. The SDK response hook records
+ * the rate-limit headers of every response, and batched lookups wait for the
+ * window to reopen before sending their next request.
+ *
+ * Headers understood:
+ *
+ * - Retry-After: seconds or an HTTP date, sent with 429 responses
+ * - X-RateLimit-Remaining + X-RateLimit-Reset: seconds until the window
+ *   resets, or the reset time in epoch seconds. A remaining count of 0
+ *   pauses requests until the reset even before the server answers 429.
+ */
+
+import { setTimeout as sleep } from 'node:timers/promises'
+
+import { debug } from '@socketsecurity/lib-stable/debug/output'
+
+import { HTTP_STATUS_TOO_MANY_REQUESTS } from '../../constants/http.mts'
+
+export type RateLimitHeaders = Record<
+  string,
+  string | string[] | number | undefined
+>
+
+// Values below this are a duration in seconds, above it epoch seconds.
+const EPOCH_SECONDS_THRESHOLD = 1_000_000_000
+
+// Never pause on a single header for longer than this.
+export const MAX_RATE_LIMIT_WAIT_MS = 5 * 60 * 1000
+
+let pausedUntil = 0
+
+function getHeader(
+  headers: RateLimitHeaders,
+  name: string,
+): string | undefined {
+  for (const key of Object.keys(headers)) {
+    if (key.toLowerCase() === name) {
+      const value = headers[key]
+      return Array.isArray(value) ? value[0] : value?.toString()
+    }
+  }
+  return undefined
+}
+
+function parseResetMs(value: string, now: number): number | undefined {
+  const seconds = Number(value)
+  if (!Number.isFinite(seconds) || seconds < 0) {
+    return undefined
+  }
+  return seconds >= EPOCH_SECONDS_THRESHOLD
+    ? Math.max(0, seconds * 1000 - now)
+    : seconds * 1000
+}
+
+/**
+ * Milliseconds the rate-limit headers ask to wait, or undefined when they
+ * do not ask to wait.
+ */
+export function parseRateLimitWaitMs(
+  status: number,
+  headers: RateLimitHeaders | undefined,
+  now = Date.now(),
+): number | undefined {
+  if (!headers) {
+    return undefined
+  }
+  let waitMs: number | undefined
+  const retryAfter = getHeader(headers, 'retry-after')
+  if (retryAfter && status === HTTP_STATUS_TOO_MANY_REQUESTS) {
+    waitMs = parseResetMs(retryAfter, now)
+    if (waitMs === undefined) {
+      const date = Date.parse(retryAfter)
+      if (!Number.isNaN(date)) {
+        waitMs = Math.max(0, date - now)
+      }
+    }
+  }
+  if (waitMs === undefined) {
+    const remaining = getHeader(headers, 'x-ratelimit-remaining')
+    const reset =
+      getHeader(headers, 'x-ratelimit-reset') ??
+      getHeader(headers, 'ratelimit-reset')
+    if (
+      reset &&
+      (status === HTTP_STATUS_TOO_MANY_REQUESTS || Number(remaining) === 0)
+    ) {
+      waitMs = parseResetMs(reset, now)
+    }
+  }
+  return waitMs === undefined
+    ? undefined
+    : Math.min(waitMs, MAX_RATE_LIMIT_WAIT_MS)
+}
+
+/**
+ * Record the rate-limit headers of a Socket API response.
+ */
+export function noteRateLimitResponse(
+  status: number,
+  headers: RateLimitHeaders | undefined,
+): void {
+  const waitMs = parseRateLimitWaitMs(status, headers)
+  if (waitMs) {
+    pauseApiRequests(waitMs)
+  }
+}
+
+/**
+ * Hold further batched requests for at least `ms` milliseconds.
+ */
+export function pauseApiRequests(ms: number): void {
+  const until = Date.now() + ms
+  if (until > pausedUntil) {
+    debug(`Socket API rate limited, pausing requests for ${ms}ms`)
+    pausedUntil = until
+  }
+}
+
+export function getRateLimitWaitMs(now = Date.now()): number {
+  return Math.max(0, pausedUntil - now)
+}
+
+export async function waitForRateLimit(): Promise<void> {
+  let waitMs = getRateLimitWaitMs()
+  while (waitMs > 0) {
+    await sleep(waitMs)
+    waitMs = getRateLimitWaitMs()
+  }
+}
+
+/**
+ * Forget any recorded rate limit. For tests.
+ */
+export function resetRateLimit(): void {
+  pausedUntil = 0
+}
@@ -0,0 +1,152 @@
+/**
+ * Retry, backoff and timeout settings of Socket API requests.
+ *
+ * Each setting is read from, highest priority first:
+ *
+ * - The --max-retries, --retry-backoff and --timeout flags
+ * - SOCKET_CLI_API_MAX_RETRIES, SOCKET_CLI_API_RETRY_BACKOFF and
+ *   SOCKET_CLI_API_TIMEOUT
+ * - The apiMaxRetries, apiRetryBackoff and apiTimeout config values
+ *
+ * An unset setting leaves the default of the caller in place: the SDK, raw
+ * API queries and batched lookups (api-batch.mts) each have their own.
+ *
+ * Retries wait an exponential backoff with jitter, so CI jobs that failed
+ * together do not retry in lockstep.
+ */
+
+import { getSocketCliApiTimeout } from '@socketsecurity/lib-stable/env/socket-cli'
+
+import {
+  CONFIG_KEY_API_MAX_RETRIES,
+  CONFIG_KEY_API_RETRY_BACKOFF,
+  CONFIG_KEY_API_TIMEOUT,
+} from '../../constants/config.mts'
+import { getSocketCliApiMaxRetries } from '../../env/socket-cli-api-max-retries.mts'
+import { getSocketCliApiRetryBackoff } from '../../env/socket-cli-api-retry-backoff.mts'
+import { getConfigValueOrUndef } from '../config.mts'
+
+import type { CResult } from '../../types.mts'
+
+export type ApiRetryFlags = {
+  maxRetries?: number | string | undefined
+  retryBackoff?: number | string | undefined
+  timeout?: number | string | undefined
+}
+
+export const MAX_API_RETRY_DELAY_MS = 60_000
+
+let maxRetriesFlag: number | undefined
+
+let retryBackoffFlag: number | undefined
+
+let timeoutFlag: number | undefined
+
+// Integers of at least `min`, given as numbers or numeric strings.
+function toInteger(value: unknown, min: number): number | undefined {
+  if (value === undefined || value === null || value === '') {
+    return undefined
+  }
+  const num = Number(value)
+  return Number.isInteger(num) && num >= min ? num : undefined
+}
+
+export function getApiMaxRetries(): number | undefined {
+  return (
+    maxRetriesFlag ??
+    getSocketCliApiMaxRetries() ??
+    toInteger(getConfigValueOrUndef(CONFIG_KEY_API_MAX_RETRIES), 0)
+  )
+}
+
+export function getApiRetryBackoffMs(): number | undefined {
+  return (
+    retryBackoffFlag ??
+    getSocketCliApiRetryBackoff() ??
+    toInteger(getConfigValueOrUndef(CONFIG_KEY_API_RETRY_BACKOFF), 0)
+  )
+}
+
+export function getApiTimeoutMs(): number | undefined {
+  return (
+    timeoutFlag ??
+    (getSocketCliApiTimeout() || undefined) ??
+    toInteger(getConfigValueOrUndef(CONFIG_KEY_API_TIMEOUT), 1)
+  )
+}
+
+/**
+ * Milliseconds to wait before retry number `attempt`, counting from 1: the
+ * backoff doubled for each earlier retry, capped at MAX_API_RETRY_DELAY_MS,
+ * of which a random half is skipped.
+ */
+export function getRetryDelayMs(
+  attempt: number,
+  backoffMs: number,
+  random: () => number = Math.random,
+): number {
+  const delayMs = Math.min(
+    backoffMs * 2 ** Math.max(0, attempt - 1),
+    MAX_API_RETRY_DELAY_MS,
+  )
+  return Math.round(delayMs / 2 + (delayMs / 2) * random())
+}
+
+/**
+ * Reset the retry flags for testing purposes.
+ *
+ * @internal
+ */
+export function resetApiRetryFlagsForTesting(): void {
+  maxRetriesFlag = undefined
+  retryBackoffFlag = undefined
+  timeoutFlag = undefined
+}
+
+/**
+ * Take over the flags of the current invocation. Fails on values that are
+ * not whole numbers, leaving all three unset.
+ */
+export function setApiRetryFlags(flags: ApiRetryFlags): CResult<undefined> {
+  const { maxRetries, retryBackoff, timeout } = {
+    __proto__: null,
+    ...flags,
+  } as ApiRetryFlags
+  // Reset on every invocation so the flags don't leak into the next one.
+  maxRetriesFlag = undefined
+  retryBackoffFlag = undefined
+  timeoutFlag = undefined
+  const checks: Array<[string, unknown, number | undefined, string]> = [
+    [
+      '--max-retries',
+      maxRetries,
+      toInteger(maxRetries, 0),
+      'a whole number of retries, 0 or more',
+    ],
+    [
+      '--retry-backoff',
+      retryBackoff,
+      toInteger(retryBackoff, 0),
+      'a whole number of milliseconds, 0 or more',
+    ],
+    [
+      '--timeout',
+      timeout,
+      toInteger(timeout, 1),
+      'a whole number of milliseconds, 1 or more',
+    ],
+  ]
+  for (const { 0: flag, 1: raw, 2: value, 3: expected } of checks) {
+    if (raw !== undefined && raw !== '' && value === undefined) {
+      return {
+        ok: false,
+        message: `Invalid ${flag} value`,
+        cause: `Expected ${expected} (saw: ${String(raw)})`,
+      }
+    }
+  }
+  maxRetriesFlag = checks[0][2]
+  retryBackoffFlag = checks[1][2]
+  timeoutFlag = checks[2][2]
+  return { ok: true, data: undefined }
+}
@@ -0,0 +1,225 @@
+/**
+ * Persistent cache of package score and alert responses keyed by purl. Lets
+ * `socket package shallow` / `socket package score` skip repeated lookups and
+ * serve results offline (--offline or SOCKET_CLI_OFFLINE) on air-gapped
+ * runners that have a cache directory restored from a connected run.
+ *
+ * Entries live one JSON file per purl under the socket cache directory. Fresh
+ * entries (within the TTL) replace API calls; offline mode also accepts stale
+ * entries. Once the directory grows past the size cap the oldest entries are
+ * evicted first. --no-cache and --cache-ttl override both for one run (see
+ * util/cache/overrides.mts). `socket bundle export` copies the entries
+ * into an offline bundle and `socket bundle import` writes them back with
+ * their original timestamps.
+ */
+import crypto from 'node:crypto'
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { debugDir } from '@socketsecurity/lib-stable/debug/output'
+import { readJson } from '@socketsecurity/lib-stable/fs/read-json'
+import { safeDelete, safeMkdir } from '@socketsecurity/lib-stable/fs/safe'
+import { writeJson } from '@socketsecurity/lib-stable/fs/write-json'
+
+import { getScoreCachePath } from '../../constants/paths.mts'
+import { getCacheOverrides } from '../cache/overrides.mts'
+
+import type { JsonContent } from '@socketsecurity/lib-stable/fs/types'
+
+export type ScoreCacheKind = 'deep' | 'shallow'
+
+export interface ScoreCacheEntry {
+  timestamp: number
+  purl: string
+  data: JsonContent
+}
+
+export type ScoreCacheFileEntry = ScoreCacheEntry & { kind: ScoreCacheKind }
+
+export type ReadScoreCacheOptions = {
+  allowStale?: boolean | undefined
+  ttlMs?: number | undefined
+}
+
+// 24 hours in milliseconds. Alerts change as packages are re-analyzed, so
+// a day keeps repeated CI runs cheap without pinning stale verdicts for long.
+export const SCORE_CACHE_TTL_MS = 24 * 60 * 60 * 1000
+
+// 50 MiB. A shallow entry with alerts is typically a few KiB.
+export const SCORE_CACHE_MAX_BYTES = 50 * 1024 * 1024
+
+export function getScoreCacheFilePath(
+  kind: ScoreCacheKind,
+  purl: string,
+): string {
+  const hash = crypto.createHash('sha256').update(purl).digest('hex')
+  return path.join(getScoreCachePath(), `${kind}-${hash.slice(0, 32)}.json`)
+}
+
+export async function readScoreCache(
+  kind: ScoreCacheKind,
+  purl: string,
+  options?: ReadScoreCacheOptions | undefined,
+): Promise<JsonContent | undefined> {
+  const { allowStale = false, ttlMs: ttlMsOption } = {
+    __proto__: null,
+    ...options,
+  } as ReadScoreCacheOptions
+  const { disabled, ttlMs: ttlMsOverride } = getCacheOverrides()
+  if (disabled) {
+    return undefined
+  }
+  const ttlMs = ttlMsOverride ?? ttlMsOption ?? SCORE_CACHE_TTL_MS
+  try {
+    const entry = await readJson(getScoreCacheFilePath(kind, purl))
+    if (entry && typeof entry === 'object' && !Array.isArray(entry)) {
+      const { data, purl: entryPurl, timestamp } = entry
+      // Guard against hash collisions and hand-edited files.
+      if (
+        entryPurl === purl &&
+        typeof timestamp === 'number' &&
+        data !== undefined &&
+        (allowStale || Date.now() - timestamp <= ttlMs)
+      ) {
+        return data
+      }
+    }
+  } catch {
+    return undefined
+  }
+  return undefined
+}
+
+export async function writeScoreCache(
+  kind: ScoreCacheKind,
+  purl: string,
+  data: JsonContent,
+  timestamp = Date.now(),
+): Promise<void> {
+  const scoreCachePath = getScoreCachePath()
+  const cacheJsonPath = getScoreCacheFilePath(kind, purl)
+  await safeMkdir(scoreCachePath, { recursive: true })
+
+  const entry: ScoreCacheEntry = {
+    timestamp,
+    purl,
+    data,
+  }
+
+  // Use atomic write pattern to prevent multi-process race conditions.
+  const tmpPath = `${cacheJsonPath}.tmp.${process.pid}`
+  await writeJson(tmpPath, entry as unknown as JsonContent)
+  // Eviction and `socket cache` go by mtime, keep it at the entry timestamp.
+  const time = new Date(timestamp)
+  await fs.utimes(tmpPath, time, time)
+  await fs.rename(tmpPath, cacheJsonPath)
+}
+
+/**
+ * Every valid entry of the cache, fresh or stale.
+ */
+export async function readScoreCacheEntries(): Promise<ScoreCacheFileEntry[]> {
+  const scoreCachePath = getScoreCachePath()
+  let names: string[]
+  try {
+    names = await fs.readdir(scoreCachePath)
+  } catch {
+    return []
+  }
+  const entries: ScoreCacheFileEntry[] = []
+  for (let i = 0, { length } = names; i < length; i += 1) {
+    const name = names[i]!
+    const kind = name.slice(0, name.indexOf('-'))
+    if ((kind !== 'deep' && kind !== 'shallow') || !name.endsWith('.json')) {
+      continue
+    }
+    try {
+      const entry = await readJson(path.join(scoreCachePath, name))
+      if (entry && typeof entry === 'object' && !Array.isArray(entry)) {
+        const { data, purl, timestamp } = entry
+        // Guard against hash collisions and hand-edited files.
+        if (
+          typeof purl === 'string' &&
+          typeof timestamp === 'number' &&
+          data !== undefined &&
+          getScoreCacheFilePath(kind, purl) === path.join(scoreCachePath, name)
+        ) {
+          entries.push({ data, kind, purl, timestamp })
+        }
+      }
+    } catch {
+      // Removed by a concurrent prune.
+    }
+  }
+  return entries
+}
+
+/**
+ * Evict the least recently written entries until the cache directory is at or
+ * under `maxBytes`. Returns the number of evicted entries.
+ */
+export async function pruneScoreCache(
+  maxBytes = SCORE_CACHE_MAX_BYTES,
+): Promise<number> {
+  const scoreCachePath = getScoreCachePath()
+  let names: string[]
+  try {
+    names = await fs.readdir(scoreCachePath)
+  } catch {
+    return 0
+  }
+  const files: Array<{ filepath: string; mtimeMs: number; size: number }> = []
+  let totalBytes = 0
+  for (let i = 0, { length } = names; i < length; i += 1) {
+    const name = names[i]!
+    if (!name.endsWith('.json')) {
+      continue
+    }
+    const filepath = path.join(scoreCachePath, name)
+    try {
+      const stats = await fs.stat(filepath)
+      files.push({ filepath, mtimeMs: stats.mtimeMs, size: stats.size })
+      totalBytes += stats.size
+    } catch {
+      // Removed by a concurrent prune.
+    }
+  }
+  if (totalBytes <= maxBytes) {
+    return 0
+  }
+  files.sort((a, b) => a.mtimeMs - b.mtimeMs)
+  let evicted = 0
+  for (let i = 0, { length } = files; i < length; i += 1) {
+    if (totalBytes <= maxBytes) {
+      break
+    }
+    const file = files[i]!
+    await safeDelete(file.filepath, { force: true })
+    totalBytes -= file.size
+    evicted += 1
+  }
+  return evicted
+}
+
+/**
+ * Cache every `[purl, data]` pair then enforce the size cap. Cache failures are
+ * logged and swallowed; they should never fail the lookup that produced the
+ * data.
+ */
+export async function storeScoreCacheEntries(
+  kind: ScoreCacheKind,
+  entries: Array<[purl: string, data: JsonContent]>,
+): Promise<void> {
+  if (!entries.length || getCacheOverrides().disabled) {
+    return
+  }
+  try {
+    for (let i = 0, { length } = entries; i < length; i += 1) {
+      const { 0: purl, 1: data } = entries[i]!
+      await writeScoreCache(kind, purl, data)
+    }
+    await pruneScoreCache()
+  } catch (e) {
+    debugDir('error', e)
+  }
+}
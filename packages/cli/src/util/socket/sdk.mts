@@ -13,6 +13,24 @@
  * - Automatic proxy agent selection
  * - HTTP/HTTPS proxy configuration
  * - Respects SOCKET_CLI_API_PROXY environment variable
+ * - Skips the proxy for NO_PROXY hosts
+ * - Trusts extra CA certificates, see network.mts
+ *
+ * Endpoint:
+ *
+ * - --api-base-url, SOCKET_CLI_API_BASE_URL or the apiBaseUrl config value
+ * - Basic or Bearer auth by the apiAuthScheme config value, see
+ *   api-endpoint.mts
+ *
+ * Retries and Timeout:
+ *
+ * - --max-retries, --retry-backoff and --timeout or their env and config
+ *   equivalents, see retry.mts
+ *
+ * Logging:
+ *
+ * - Requests and responses, with the API request ID, go to the structured
+ *   log of --log-level, see util/log/structured.mts
  *
  * SDK Setup:
  *
@@ -32,15 +50,12 @@ import { readFileSync } from 'node:fs'
 import { Agent as HttpsAgent } from 'node:https'
 import { rootCertificates } from 'node:tls'
 
-import { HttpProxyAgent, HttpsProxyAgent } from 'hpagent'
-
 import { debug as debugLib } from '@socketsecurity/lib-stable/debug/output'
 import isInteractive from '@socketregistry/is-interactive/index.cjs'
 import { getSocketApiToken } from '@socketsecurity/lib-stable/env/socket'
 import {
   getSocketCliApiBaseUrl,
   getSocketCliApiProxy,
-  getSocketCliApiTimeout,
   getSocketCliNoApiToken,
 } from '@socketsecurity/lib-stable/env/socket-cli'
 import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
@@ -62,11 +77,28 @@ import { getCliHomepage } from '../../env/cli-homepage.mts'
 import { getCliName } from '../../env/cli-name.mts'
 import { getCliVersion } from '../../env/cli-version.mts'
 import { SOCKET_CLI_DEBUG } from '../../env/socket-cli-debug.mts'
-import { TOKEN_PREFIX_LENGTH } from '../../constants/socket.mts'
+import { EXIT_CODE_AUTH_ERROR } from '../../constants/exit-codes.mts'
+import { API_V0_URL, TOKEN_PREFIX_LENGTH } from '../../constants/socket.mts'
 import { getConfigValueOrUndef } from '../config.mts'
 import { debugApiRequest, debugApiResponse } from '../debug.mts'
+import { logApiResponse, logEvent } from '../log/structured.mts'
 import { trackCliEvent } from '../telemetry/integration.mts'
+import {
+  getApiAgent,
+  getApiAuthScheme,
+  getApiAuthorization,
+  getApiBaseUrlFlag,
+  withApiAuthorization,
+} from './api-endpoint.mts'
+import { getCaCert, getProxyAgent, isNoProxyUrl } from './network.mts'
+import { noteRateLimitResponse } from './rate-limit.mts'
+import {
+  getApiMaxRetries,
+  getApiRetryBackoffMs,
+  getApiTimeoutMs,
+} from './retry.mts'
 
+import type { RateLimitHeaders } from './rate-limit.mts'
 import type { CResult } from '../../types.mts'
 import type {
   FileValidationResult,
@@ -88,6 +120,7 @@ let defaultToken: string | undefined
 // The Socket API server that should be used for operations.
 export function getDefaultApiBaseUrl(): string | undefined {
   const baseUrl =
+    getApiBaseUrlFlag() ||
     getSocketCliApiBaseUrl() ||
     getConfigValueOrUndef(CONFIG_KEY_API_BASE_URL) ||
     undefined
@@ -118,25 +151,22 @@ export function getDefaultProxyUrl(): string | undefined {
   return isUrl(apiProxy) ? apiProxy : undefined
 }
 
-// Returns combined root and extra CA certificates when SSL_CERT_FILE is set
-// but NODE_EXTRA_CA_CERTS is not. Node.js loads NODE_EXTRA_CA_CERTS at process
-// startup, so setting SSL_CERT_FILE alone does not affect the current process.
-// This function reads the certificate file manually and combines it with the
-// default root certificates for use in HTTPS agents.
+// Returns combined root and extra CA certificates when --cacert, the caCert
+// config value or SSL_CERT_FILE names a certificate file. Node.js only loads
+// NODE_EXTRA_CA_CERTS at process startup, so this function reads the other
+// files manually and combines them with the default root certificates for use
+// in HTTPS agents. See network.mts for the order of the sources.
 export function getExtraCaCerts(): string[] | undefined {
   if (extraCaCertsResolved) {
     return extraCaCerts
   }
   extraCaCertsResolved = true
+  const caCert = getCaCert()
   // Node.js already loaded extra CA certs at startup.
-  if (process.env['NODE_EXTRA_CA_CERTS']) {
+  if (!caCert || caCert.source === 'NODE_EXTRA_CA_CERTS') {
     return undefined
   }
-  const certPath = process.env['SSL_CERT_FILE']
-  if (!certPath) {
-    return undefined
-  }
-  /* c8 ignore start - SSL_CERT_FILE is not set in tests; this entire CA-cert loader is unreachable */
+  const certPath = caCert.path
   try {
     const extraCerts = readFileSync(certPath, 'utf-8')
     // Combine default root certificates with extra certificates. Specifying ca
@@ -144,10 +174,15 @@ export function getExtraCaCerts(): string[] | undefined {
     extraCaCerts = [...rootCertificates, extraCerts]
     return extraCaCerts
   } catch (e) {
-    debugLib(`Failed to read certificate file: ${certPath}`)
+    // A file named with --cacert or the caCert config value is expected to
+    // exist, so a missing one is worth more than a debug note.
+    if (caCert.source === 'SSL_CERT_FILE') {
+      debugLib(`Failed to read certificate file: ${certPath}`)
+    } else {
+      logger.warn(`Failed to read the CA certificate file ${certPath}`)
+    }
     return undefined
   }
-  /* c8 ignore stop */
 }
 
 export function getVisibleTokenPrefix(): string {
@@ -172,6 +207,8 @@ export type SetupSdkOptions = {
   apiBaseUrl?: string | undefined
   apiProxy?: string | undefined
   apiToken?: string | undefined
+  // Retries of failed requests, defaults to getApiMaxRetries().
+  retries?: number | undefined
 }
 
 export async function setupSdk(
@@ -195,40 +232,49 @@ export async function setupSdk(
       ok: false,
       message: 'Auth Error',
       cause: 'You need to provide an API token. Run `socket login` first.',
+      code: EXIT_CODE_AUTH_ERROR,
     }
   }
 
+  const { apiBaseUrl = getDefaultApiBaseUrl() } = opts
+
   let { apiProxy } = opts
   if (!isUrl(apiProxy)) {
     apiProxy = getDefaultProxyUrl()
+    // A proxy passed in wins, the default one skips NO_PROXY hosts.
+    if (apiProxy && isNoProxyUrl(apiBaseUrl || API_V0_URL)) {
+      apiProxy = undefined
+    }
   }
 
-  const { apiBaseUrl = getDefaultApiBaseUrl() } = opts
-
-  // Usage of HttpProxyAgent vs. HttpsProxyAgent based on the chart at:
-  // https://github.com/delvedor/hpagent?tab=readme-ov-file#usage
-  const ProxyAgent = apiBaseUrl?.startsWith('http:')
-    ? HttpProxyAgent
-    : HttpsProxyAgent
-
-  const timeout = getSocketCliApiTimeout() || undefined
+  const { retries = getApiMaxRetries() } = opts
+  const retryDelay = getApiRetryBackoffMs()
+  const timeout = getApiTimeoutMs()
 
   // Load extra CA certificates for SSL_CERT_FILE support when
   // NODE_EXTRA_CA_CERTS was not set at process startup.
   const ca = getExtraCaCerts()
 
+  let agent = apiProxy
+    ? getProxyAgent(apiBaseUrl || API_V0_URL, apiProxy, ca)
+    : ca
+      ? new HttpsAgent({ ca })
+      : undefined
+  // The SDK sends the token with Basic auth, other schemes replace the header
+  // through the agent, see api-endpoint.mts.
+  const authScheme = getApiAuthScheme()
+  if (authScheme !== 'basic') {
+    agent = withApiAuthorization(
+      agent ?? getApiAgent(apiBaseUrl || API_V0_URL, ca),
+      getApiAuthorization(apiToken, authScheme),
+    )
+  }
+
   const sdkOptions = {
-    ...(apiProxy
-      ? {
-          agent: new ProxyAgent({
-            proxy: apiProxy,
-            ...(ca ? { ca, proxyConnectOptions: { ca } } : {}),
-          }),
-        }
-      : ca
-        ? { agent: new HttpsAgent({ ca }) }
-        : {}),
+    ...(agent ? { agent } : {}),
     ...(apiBaseUrl ? { baseUrl: apiBaseUrl } : {}),
+    ...(retries !== undefined ? { retries } : {}),
+    ...(retryDelay !== undefined ? { retryDelay } : {}),
     ...(timeout ? { timeout } : {}),
     // Add HTTP request hooks for telemetry and debugging.
     hooks: {
@@ -242,6 +288,10 @@ export async function setupSdk(
         }
         /* c8 ignore stop */
         if (!isTelemetryEndpoint) {
+          logEvent('debug', 'api request', {
+            method: info.method,
+            url: info.url,
+          })
           // Track API request event.
           void trackCliEvent('api_request', process.argv, {
             method: info.method,
@@ -251,10 +301,24 @@ export async function setupSdk(
         }
       },
       onResponse: (info: ResponseInfo) => {
+        // Let batched lookups slow down before the server starts refusing.
+        noteRateLimitResponse(
+          info.status,
+          info.headers as RateLimitHeaders | undefined,
+        )
+
         // Skip tracking for telemetry submission endpoints to prevent infinite loop.
         const isTelemetryEndpoint = info.url.includes('/telemetry')
 
         if (!isTelemetryEndpoint) {
+          logApiResponse({
+            durationMs: info.duration,
+            error: info.error,
+            headers: info.headers as Record<string, unknown> | undefined,
+            method: info.method,
+            status: info.status,
+            url: info.url,
+          })
           // Track API response event.
           const metadata = {
             duration: info.duration,
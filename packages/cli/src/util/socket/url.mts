@@ -6,11 +6,12 @@
  * - getSocketDevAlertUrl: Generate alert type documentation URL -
  * getSocketDevPackageOverviewUrl: Generate package overview URL -
  * getSocketDevPackageOverviewUrlFromPurl: Generate overview URL from PURL -
+ * getSocketDevPackageFileUrl: Generate URL of a file inside a package -
  * getSocketDevPackageUrl: Generate package detail URL -
  * getSocketDevPackageUrlFromPurl: Generate package URL from PURL -
  * getSocketDevReportUrl: Generate scan report URL.
  *
- * URL Generation: - Package overview and detail pages - Security alert
+ * URL Generation: - Package overview, detail and file pages - Security alert
  * documentation - Scan report links - Ecosystem-specific URL formatting.
  */
 
@@ -53,3 +54,13 @@ export function getSocketDevPackageOverviewUrlFromPurl(
   const fullName = getPkgFullNameFromPurl(purlObj)
   return getSocketDevPackageOverviewUrl(purlObj.type, fullName, purlObj.version)
 }
+
+export function getSocketDevPackageFileUrl(
+  purl: string | PackageURL | SocketArtifact,
+  file: string,
+): string {
+  const purlObj = getPurlObject(purl)
+  const fullName = getPkgFullNameFromPurl(purlObj)
+  const filePath = file.split('/').map(encodeURIComponent).join('/')
+  return `${SOCKET_WEBSITE_URL}/${purlObj.type}/package/${fullName}/files/${purlObj.version}/${filePath}`
+}
@@ -10,6 +10,7 @@ import {
   normalizeExitCode,
   trackEvent,
 } from './integration.mts'
+import { recordUsageEvent } from './usage.mts'
 
 /**
  * Track CLI completion event. Should be called on successful CLI exit. Flushes
@@ -26,6 +27,11 @@ export async function trackCliComplete(
 ): Promise<void> {
   debug('Capture end of command')
 
+  await recordUsageEvent({
+    durationMs: calculateDuration(startTime),
+    exitCode: normalizeExitCode(exitCode, 0),
+  })
+
   await trackEvent(
     'cli_complete',
     buildContext(argv),
@@ -56,6 +62,12 @@ export async function trackCliError(
 ): Promise<void> {
   debug('Capture error and stack trace of command')
 
+  await recordUsageEvent({
+    durationMs: calculateDuration(startTime),
+    error,
+    exitCode: normalizeExitCode(exitCode, 1),
+  })
+
   await trackEvent(
     'cli_error',
     buildContext(argv),
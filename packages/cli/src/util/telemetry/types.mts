@@ -45,3 +45,27 @@ export interface TelemetryEvent {
   metadata?: Record<string, unknown> | undefined
   error?: TelemetryEventError | undefined
 }
+
+/**
+ * Anonymous usage event, see usage.mts. Never holds arguments, paths, org
+ * slugs or tokens.
+ */
+export interface UsageEvent {
+  arch: string
+  ci: boolean
+  /**
+   * Built-in command names only, e.g. `socket scan create`.
+   */
+  command: string
+  created_at: string
+  duration_ms: number
+  error_class?: string | undefined
+  exit_code: number
+  /**
+   * Random ID generated on opt-in, removed by `socket telemetry disable`.
+   */
+  installation_id: string
+  node_version: string
+  platform: string
+  version: string
+}
@@ -0,0 +1,296 @@
+/**
+ * Anonymous usage telemetry for Socket CLI. It is off until the user opts in
+ * with `socket telemetry enable`, i.e. sets the telemetry config key, and a
+ * set DO_NOT_TRACK keeps it off regardless.
+ *
+ * Each invocation appends one event to a local spool: the built-in command
+ * name (never its arguments), the duration, the exit code and the error
+ * class. A later invocation sends the spooled events in the background once
+ * there are enough of them, so the CLI never waits on the network for this.
+ * `socket telemetry status --dry-run` shows the exact payload that is sent.
+ */
+
+import crypto from 'node:crypto'
+import { promises as fs } from 'node:fs'
+import path from 'node:path'
+import process from 'node:process'
+
+import { debugNs } from '@socketsecurity/lib-stable/debug/output'
+import { getCI } from '@socketsecurity/lib-stable/env/ci'
+import { errorMessage } from '@socketsecurity/lib-stable/errors/message'
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import { CONFIG_KEY_TELEMETRY } from '../../constants/config.mts'
+import {
+  EXIT_CODE_API_ERROR,
+  EXIT_CODE_AUTH_ERROR,
+  EXIT_CODE_POLICY_VIOLATION,
+  EXIT_CODE_USAGE,
+} from '../../constants/exit-codes.mts'
+import { getSocketTelemetryPath } from '../../constants/paths.mts'
+import { API_V0_URL } from '../../constants/socket.mts'
+import { getCliVersion } from '../../env/cli-version.mts'
+import { getDoNotTrack } from '../../env/do-not-track.mts'
+import { formatConfigSource, getConfigValueSource } from '../config.mts'
+import { getDefaultApiBaseUrl, socketHttpRequest } from '../socket/api-http.mts'
+
+import type { UsageEvent } from './types.mts'
+
+const INSTALLATION_ID_FILE_NAME = 'installation-id'
+
+const SPOOL_FILE_NAME = 'spool.jsonl'
+
+// Send the spool once it holds this many events.
+const USAGE_BATCH_SIZE = 10
+
+// Stop spooling when the events can not be sent for a long time.
+const MAX_SPOOL_BYTES = 512 * 1024
+
+const FLUSH_TIMEOUT = 2000
+
+// Error classes for failures that exit without throwing.
+const exitCodeErrorClasses: Map<number, string> = new Map([
+  [EXIT_CODE_USAGE, 'InputError'],
+  [EXIT_CODE_POLICY_VIOLATION, 'PolicyViolation'],
+  [EXIT_CODE_API_ERROR, 'ApiError'],
+  [EXIT_CODE_AUTH_ERROR, 'AuthError'],
+])
+
+// The built-in command being run, see setUsageCommand().
+let usageCommand = 'socket'
+
+export type UsagePayload = {
+  events: UsageEvent[]
+}
+
+export type UsageTelemetryState = {
+  enabled: boolean
+  // Why telemetry is on or off.
+  reason: string
+}
+
+function debug(message: string): void {
+  debugNs('socket:telemetry:usage', message)
+}
+
+/**
+ * The random ID of this installation, undefined before the first event.
+ */
+export async function getUsageInstallationId(): Promise<string | undefined> {
+  try {
+    const id = await fs.readFile(
+      path.join(getSocketTelemetryPath(), INSTALLATION_ID_FILE_NAME),
+      'utf8',
+    )
+    return id.trim() || undefined
+  } catch {}
+  return undefined
+}
+
+async function getInstallationId(): Promise<string> {
+  const existingId = await getUsageInstallationId()
+  if (existingId) {
+    return existingId
+  }
+  const id = crypto.randomUUID()
+  const telemetryPath = getSocketTelemetryPath()
+  await fs.mkdir(telemetryPath, { recursive: true })
+  await fs.writeFile(path.join(telemetryPath, INSTALLATION_ID_FILE_NAME), id, {
+    encoding: 'utf8',
+    mode: 0o600,
+  })
+  return id
+}
+
+function serializeEvents(events: UsageEvent[]): string {
+  return events.map(event => `${JSON.stringify(event)}\n`).join('')
+}
+
+/**
+ * Whether usage telemetry is on: the telemetry config key is true, from any
+ * config layer, and DO_NOT_TRACK is not set.
+ */
+export function getUsageTelemetryState(): UsageTelemetryState {
+  if (getDoNotTrack()) {
+    return { enabled: false, reason: 'DO_NOT_TRACK is set' }
+  }
+  const source = getConfigValueSource(CONFIG_KEY_TELEMETRY)
+  if (source?.value === true) {
+    return {
+      enabled: true,
+      reason: `Opted in by the ${formatConfigSource(source)}`,
+    }
+  }
+  if (source) {
+    return {
+      enabled: false,
+      reason: `Opted out by the ${formatConfigSource(source)}`,
+    }
+  }
+  return { enabled: false, reason: 'Not opted in, telemetry is off by default' }
+}
+
+/**
+ * The error class of a finished invocation: the class of the thrown error or
+ * the failure its exit code stands for. Undefined on success.
+ */
+export function getUsageErrorClass(
+  error: unknown,
+  exitCode: number,
+): string | undefined {
+  if (error !== undefined) {
+    return error instanceof Error ? error.constructor.name : 'Error'
+  }
+  if (exitCode === 0) {
+    return undefined
+  }
+  return exitCodeErrorClasses.get(exitCode) ?? 'Error'
+}
+
+export function getUsageEndpoint(): string {
+  const baseUrl = getDefaultApiBaseUrl() || API_V0_URL
+  return `${baseUrl}${baseUrl.endsWith('/') ? '' : '/'}cli/telemetry`
+}
+
+export function getUsageSpoolPath(): string {
+  return path.join(getSocketTelemetryPath(), SPOOL_FILE_NAME)
+}
+
+/**
+ * The events waiting in the spool. Lines that do not parse are skipped.
+ */
+export async function readUsageSpool(
+  spoolPath: string = getUsageSpoolPath(),
+): Promise<UsageEvent[]> {
+  let content = ''
+  try {
+    content = await fs.readFile(spoolPath, 'utf8')
+  } catch {
+    return []
+  }
+  const events: UsageEvent[] = []
+  for (const line of content.split('\n')) {
+    if (!line.trim()) {
+      continue
+    }
+    try {
+      events.push(JSON.parse(line))
+    } catch {
+      debug('Skipping a malformed usage event')
+    }
+  }
+  return events
+}
+
+/**
+ * The payload the next flush sends for the spooled events.
+ */
+export async function getUsagePayload(): Promise<UsagePayload> {
+  return { events: await readUsageSpool() }
+}
+
+/**
+ * Record the command name for the usage event. The router calls this for
+ * every built-in command it dispatches, so only names the CLI defines end up
+ * in the spool.
+ */
+export function setUsageCommand(command: string): void {
+  usageCommand = command
+}
+
+/**
+ * Spool the usage event of this invocation, when telemetry is on. Never
+ * throws: telemetry must not break the CLI.
+ */
+export async function recordUsageEvent({
+  durationMs,
+  error,
+  exitCode,
+}: {
+  durationMs: number
+  error?: unknown
+  exitCode: number
+}): Promise<void> {
+  try {
+    if (!getUsageTelemetryState().enabled) {
+      return
+    }
+    const spoolPath = getUsageSpoolPath()
+    const size = await fs.stat(spoolPath).then(
+      stats => stats.size,
+      () => 0,
+    )
+    if (size >= MAX_SPOOL_BYTES) {
+      debug('Usage spool is full, dropping the event')
+      return
+    }
+    const errorClass = getUsageErrorClass(error, exitCode)
+    const event: UsageEvent = {
+      arch: process.arch,
+      ci: getCI(),
+      command: usageCommand,
+      created_at: new Date().toISOString(),
+      duration_ms: durationMs,
+      ...(errorClass ? { error_class: errorClass } : {}),
+      exit_code: exitCode,
+      installation_id: await getInstallationId(),
+      node_version: process.version,
+      platform: process.platform,
+      version: getCliVersion() || '',
+    }
+    await fs.appendFile(spoolPath, serializeEvents([event]), 'utf8')
+  } catch (e) {
+    debug(`Failed to spool the usage event: ${errorMessage(e)}`)
+  }
+}
+
+async function sendUsageEvents(events: UsageEvent[]): Promise<void> {
+  const response = await socketHttpRequest(getUsageEndpoint(), {
+    body: JSON.stringify({ events } satisfies UsagePayload),
+    headers: { 'Content-Type': 'application/json' },
+    method: 'POST',
+    timeout: FLUSH_TIMEOUT,
+  })
+  if (!response.ok) {
+    throw new Error(`${response.status} ${response.statusText}`)
+  }
+}
+
+/**
+ * Send the spooled events once there are enough of them. The spool is
+ * claimed first, so parallel invocations never send an event twice, and
+ * events that fail to send go back into it. Never throws.
+ */
+export async function flushUsageSpool(): Promise<void> {
+  const spoolPath = getUsageSpoolPath()
+  const claimedPath = `${spoolPath}.${process.pid}.sending`
+  let events: UsageEvent[] = []
+  try {
+    if (
+      !getUsageTelemetryState().enabled ||
+      (await readUsageSpool(spoolPath)).length < USAGE_BATCH_SIZE
+    ) {
+      return
+    }
+    // Throws when another invocation claimed the spool first.
+    await fs.rename(spoolPath, claimedPath)
+    events = await readUsageSpool(claimedPath)
+    await sendUsageEvents(events)
+    debug(`Sent ${events.length} usage events`)
+  } catch (e) {
+    debug(`Failed to send the usage events: ${errorMessage(e)}`)
+    if (events.length) {
+      await fs
+        .appendFile(spoolPath, serializeEvents(events), 'utf8')
+        .catch(() => {})
+    }
+  }
+  await safeDelete(claimedPath, { force: true }).catch(() => {})
+}
+
+/**
+ * Remove the spooled events and the installation ID.
+ */
+export async function clearUsageSpool(): Promise<void> {
+  await safeDelete(getSocketTelemetryPath(), { force: true })
+}
@@ -0,0 +1,147 @@
+/**
+ * Release channels of the Socket CLI for `socket self-update` and
+ * `socket version --check`.
+ *
+ * Each channel is an npm dist-tag of the package the CLI updates from: the
+ * CLI package itself for installs through a package manager, and the
+ * `@socketsecurity/cli.exe.<triplet>` tail of the platform for standalone
+ * executables.
+ *
+ * Key Functions: - getDefaultUpdateChannel: The channel of the running
+ * version - getUpdatePackageName: The package the running CLI updates from -
+ * fetchChannelVersion: The version a channel points to -
+ * getSelfUpdateDisabledReason: Whether updates are turned off for a managed
+ * environment.
+ */
+
+import { getConfigValueOrUndef } from '../config.mts'
+import { CONFIG_KEY_DISABLE_SELF_UPDATE } from '../../constants/config.mts'
+import { SOCKET_CLI_BIN_NAME } from '../../constants/packages.mts'
+import { getCliName } from '../../env/cli-name.mts'
+import { getSocketCliNoSelfUpdate } from '../../env/socket-cli-no-self-update.mts'
+import {
+  escapePackageName,
+  fetchRegistryJson,
+} from '../../commands/verify/fetch-npm-provenance.mts'
+import { isSeaBinary } from '../sea/detect.mts'
+
+import type { CResult } from '../../types.mts'
+
+export const UPDATE_CHANNELS = ['beta', 'stable'] as const
+
+export type UpdateChannel = (typeof UPDATE_CHANNELS)[number]
+
+const CHANNEL_DIST_TAGS: Record<UpdateChannel, string> = {
+  __proto__: null as unknown as string,
+  beta: 'beta',
+  stable: 'latest',
+} as Record<UpdateChannel, string>
+
+// The platforms standalone executables are published for, mirroring
+// CLI_EXE_TRIPLETS of packages/package-builder/scripts/cli-exe-targets.mts.
+const CLI_EXE_TRIPLETS: ReadonlySet<string> = new Set([
+  'darwin-arm64',
+  'darwin-x64',
+  'linux-arm64',
+  'linux-arm64-musl',
+  'linux-x64',
+  'linux-x64-musl',
+  'win32-arm64',
+  'win32-x64',
+])
+
+export function isUpdateChannel(value: unknown): value is UpdateChannel {
+  return (UPDATE_CHANNELS as readonly unknown[]).includes(value)
+}
+
+export function getChannelDistTag(channel: UpdateChannel): string {
+  return CHANNEL_DIST_TAGS[channel]
+}
+
+/**
+ * Prereleases are on the beta channel, other versions on stable.
+ */
+export function getDefaultUpdateChannel(version: string): UpdateChannel {
+  return /^\d+\.\d+\.\d+-/.test(version) ? 'beta' : 'stable'
+}
+
+function getLibc(): 'glibc' | 'musl' | undefined {
+  if (process.platform !== 'linux') {
+    return undefined
+  }
+  const report = process.report?.getReport() as
+    | { header?: { glibcVersionRuntime?: string | undefined } | undefined }
+    | undefined
+  return report?.header?.glibcVersionRuntime ? 'glibc' : 'musl'
+}
+
+/**
+ * The standalone executable triplet of a platform, e.g. `linux-x64-musl`,
+ * undefined when no executable is published for it.
+ */
+export function getCliExeTriplet(
+  platform: string = process.platform,
+  arch: string = process.arch,
+  libc: string | undefined = getLibc(),
+): string | undefined {
+  const muslSuffix = platform === 'linux' && libc === 'musl' ? '-musl' : ''
+  const triplet = `${platform}-${arch}${muslSuffix}`
+  return CLI_EXE_TRIPLETS.has(triplet) ? triplet : undefined
+}
+
+export function getCliExePackageName(triplet: string): string {
+  return `@socketsecurity/cli.exe.${triplet}`
+}
+
+/**
+ * The npm package the running CLI updates from, undefined for standalone
+ * executables of platforms without a published executable.
+ */
+export function getUpdatePackageName(): string | undefined {
+  if (isSeaBinary()) {
+    const triplet = getCliExeTriplet()
+    return triplet ? getCliExePackageName(triplet) : undefined
+  }
+  return getCliName() || SOCKET_CLI_BIN_NAME
+}
+
+/**
+ * The version the dist-tag of a channel points to.
+ */
+export async function fetchChannelVersion(
+  name: string,
+  channel: UpdateChannel,
+): Promise<CResult<string>> {
+  const distTag = getChannelDistTag(channel)
+  const distTagsCResult = await fetchRegistryJson<Record<string, string>>(
+    `/-/package/${escapePackageName(name)}/dist-tags`,
+    `the ${name} dist-tags`,
+  )
+  if (!distTagsCResult.ok) {
+    return distTagsCResult
+  }
+  const version = distTagsCResult.data?.[distTag]
+  if (!version) {
+    return {
+      ok: false,
+      message: 'Release not found',
+      cause: `${name} has no ${distTag} dist-tag on the npm registry, so there is no ${channel} release`,
+    }
+  }
+  return { ok: true, data: version }
+}
+
+/**
+ * Why updates are turned off, undefined when they are not. Managed
+ * environments turn them off with SOCKET_CLI_NO_SELF_UPDATE or the
+ * disableSelfUpdate config value.
+ */
+export function getSelfUpdateDisabledReason(): string | undefined {
+  if (getSocketCliNoSelfUpdate()) {
+    return 'SOCKET_CLI_NO_SELF_UPDATE is set'
+  }
+  if (getConfigValueOrUndef(CONFIG_KEY_DISABLE_SELF_UPDATE) === true) {
+    return 'The disableSelfUpdate config value is true'
+  }
+  return undefined
+}
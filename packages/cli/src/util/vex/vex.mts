@@ -0,0 +1,457 @@
+/**
+ * VEX (Vulnerability Exploitability eXchange) statements read from OpenVEX,
+ * CSAF VEX or CycloneDX documents, and their use to leave out the
+ * vulnerability alerts a statement marks as `not_affected` or `fixed`.
+ *
+ * A statement applies to an alert when one of its vulnerability ids (CVE or
+ * GHSA, aliases included) names the alert's advisory and one of its products
+ * is the alerted package. A product purl without a version covers every
+ * version of the package; qualifiers and subpaths are ignored.
+ */
+
+import { safeReadFileSync } from '@socketsecurity/lib-stable/fs/read-file'
+
+import {
+  ALERT_TYPE_CRITICAL_CVE,
+  ALERT_TYPE_CVE,
+  ALERT_TYPE_MEDIUM_CVE,
+  ALERT_TYPE_MILD_CVE,
+} from '../../constants/alerts.mts'
+import { getErrorCause } from '../error/errors.mts'
+import { getPurlObject } from '../purl/parse.mts'
+import { isPlainObject } from '../socket-yaml.mts'
+
+import type { CResult } from '../../types.mts'
+import type { SocketArtifact, SocketArtifactAlert } from '../alert/artifact.mts'
+
+export type VexStatus =
+  | 'affected'
+  | 'fixed'
+  | 'not_affected'
+  | 'under_investigation'
+
+export type VexStatement = {
+  // Vulnerability id and aliases, e.g. `CVE-2024-1234`.
+  ids: string[]
+  justification?: string | undefined
+  // Purls of the affected packages.
+  products: string[]
+  status: VexStatus
+}
+
+export type VexFormat = 'csaf' | 'cyclonedx' | 'openvex'
+
+export const VULNERABILITY_ALERT_TYPES: ReadonlySet<string> = new Set([
+  ALERT_TYPE_CRITICAL_CVE,
+  ALERT_TYPE_CVE,
+  ALERT_TYPE_MEDIUM_CVE,
+  ALERT_TYPE_MILD_CVE,
+])
+
+const VULNERABILITY_ID_PROPS = ['cveId', 'ghsaId', 'id']
+
+const VULNERABILITY_ID_REGEXP = /^(?:CVE|GHSA)-/i
+
+const SUPPRESSING_STATUSES: ReadonlySet<VexStatus> = new Set([
+  'fixed',
+  'not_affected',
+])
+
+// CSAF sorts products into these lists of its `product_status`.
+const CSAF_PRODUCT_STATUSES: Readonly<Record<string, VexStatus>> = {
+  __proto__: null,
+  first_fixed: 'fixed',
+  fixed: 'fixed',
+  known_affected: 'affected',
+  known_not_affected: 'not_affected',
+  under_investigation: 'under_investigation',
+} as unknown as Record<string, VexStatus>
+
+const CYCLONEDX_ANALYSIS_STATES: Readonly<Record<string, VexStatus>> = {
+  __proto__: null,
+  exploitable: 'affected',
+  false_positive: 'not_affected',
+  in_triage: 'under_investigation',
+  not_affected: 'not_affected',
+  resolved: 'fixed',
+  resolved_with_pedigree: 'fixed',
+} as unknown as Record<string, VexStatus>
+
+const OPENVEX_STATUSES: ReadonlySet<string> = new Set([
+  'affected',
+  'fixed',
+  'not_affected',
+  'under_investigation',
+])
+
+function getStrings(value: unknown): string[] {
+  return Array.isArray(value)
+    ? value.filter((v): v is string => typeof v === 'string' && !!v)
+    : []
+}
+
+function getObjects(value: unknown): Array<Record<string, unknown>> {
+  return Array.isArray(value) ? value.filter(isPlainObject) : []
+}
+
+// Ids compare case-insensitively: GHSA ids are written in lower case after
+// the prefix, but not by every tool.
+function toVulnerabilityIds(values: unknown[]): string[] {
+  const ids = new Map<string, string>()
+  for (const value of values) {
+    if (typeof value === 'string' && VULNERABILITY_ID_REGEXP.test(value)) {
+      const id = value.trim()
+      if (!ids.has(id.toUpperCase())) {
+        ids.set(id.toUpperCase(), id)
+      }
+    }
+  }
+  return [...ids.values()]
+}
+
+/**
+ * The CVE and GHSA ids of a vulnerability alert. Empty for other alerts.
+ */
+export function getAlertVulnerabilityIds(alert: SocketArtifactAlert): string[] {
+  if (!VULNERABILITY_ALERT_TYPES.has(alert.type)) {
+    return []
+  }
+  const props = alert.props ?? {}
+  return toVulnerabilityIds(VULNERABILITY_ID_PROPS.map(key => props[key]))
+}
+
+type PurlKey = {
+  name: string
+  namespace: string
+  type: string
+  version: string
+}
+
+function getPurlKey(purl: string): PurlKey | undefined {
+  const purlObj = getPurlObject(purl, { throws: false })
+  if (!purlObj) {
+    return undefined
+  }
+  return {
+    name: purlObj.name ?? '',
+    namespace: purlObj.namespace ?? '',
+    type: purlObj.type.toLowerCase(),
+    version: purlObj.version ?? '',
+  }
+}
+
+function matchesProduct(artifact: SocketArtifact, product: PurlKey): boolean {
+  return (
+    product.type === (artifact.type ?? '').toLowerCase() &&
+    product.namespace === (artifact.namespace ?? '') &&
+    product.name === (artifact.name ?? '') &&
+    (!product.version || product.version === (artifact.version ?? ''))
+  )
+}
+
+function getOpenVexProductPurls(product: unknown): string[] {
+  if (typeof product === 'string') {
+    return [product]
+  }
+  if (!isPlainObject(product)) {
+    return []
+  }
+  const identifiers = isPlainObject(product['identifiers'])
+    ? product['identifiers']
+    : {}
+  return getStrings([product['@id'], identifiers['purl']])
+}
+
+function parseOpenVex(doc: Record<string, unknown>): VexStatement[] {
+  const statements: VexStatement[] = []
+  for (const statement of getObjects(doc['statements'])) {
+    const status = statement['status']
+    if (typeof status !== 'string' || !OPENVEX_STATUSES.has(status)) {
+      continue
+    }
+    const vulnerability = statement['vulnerability']
+    const ids = isPlainObject(vulnerability)
+      ? toVulnerabilityIds([
+          vulnerability['name'],
+          vulnerability['@id'],
+          ...getStrings(vulnerability['aliases']),
+        ])
+      : toVulnerabilityIds([vulnerability])
+    // The vulnerable packages are often subcomponents of a product that is
+    // the application itself.
+    const statementProducts: unknown[] = Array.isArray(statement['products'])
+      ? statement['products']
+      : []
+    const products: string[] = []
+    for (const product of statementProducts) {
+      products.push(...getOpenVexProductPurls(product))
+      if (isPlainObject(product)) {
+        for (const sub of getObjects(product['subcomponents'])) {
+          products.push(...getOpenVexProductPurls(sub))
+        }
+      }
+    }
+    const justification = statement['justification']
+    statements.push({
+      ids,
+      ...(typeof justification === 'string' ? { justification } : {}),
+      products,
+      status: status as VexStatus,
+    })
+  }
+  return statements
+}
+
+function collectCsafProducts(
+  node: unknown,
+  purlByProductId: Map<string, string>,
+): void {
+  if (Array.isArray(node)) {
+    for (const child of node) {
+      collectCsafProducts(child, purlByProductId)
+    }
+    return
+  }
+  if (!isPlainObject(node)) {
+    return
+  }
+  const productId = node['product_id']
+  const helper = node['product_identification_helper']
+  if (
+    typeof productId === 'string' &&
+    isPlainObject(helper) &&
+    typeof helper['purl'] === 'string'
+  ) {
+    purlByProductId.set(productId, helper['purl'])
+  }
+  for (const key of [
+    'branches',
+    'full_product_name',
+    'full_product_names',
+    'product',
+    'relationships',
+  ]) {
+    collectCsafProducts(node[key], purlByProductId)
+  }
+}
+
+function parseCsafVex(doc: Record<string, unknown>): VexStatement[] {
+  const purlByProductId = new Map<string, string>()
+  collectCsafProducts(doc['product_tree'], purlByProductId)
+  const statements: VexStatement[] = []
+  for (const vulnerability of getObjects(doc['vulnerabilities'])) {
+    const ids = toVulnerabilityIds([
+      vulnerability['cve'],
+      ...getObjects(vulnerability['ids']).map(id => id['text']),
+    ])
+    const justificationByProductId = new Map<string, string>()
+    for (const flag of getObjects(vulnerability['flags'])) {
+      if (typeof flag['label'] === 'string') {
+        for (const productId of getStrings(flag['product_ids'])) {
+          justificationByProductId.set(productId, flag['label'])
+        }
+      }
+    }
+    const productStatus = isPlainObject(vulnerability['product_status'])
+      ? vulnerability['product_status']
+      : {}
+    for (const { 0: key, 1: productIds } of Object.entries(productStatus)) {
+      const status = CSAF_PRODUCT_STATUSES[key]
+      if (!status) {
+        continue
+      }
+      for (const productId of getStrings(productIds)) {
+        const justification = justificationByProductId.get(productId)
+        statements.push({
+          ids,
+          ...(justification ? { justification } : {}),
+          products: [purlByProductId.get(productId) ?? productId],
+          status,
+        })
+      }
+    }
+  }
+  return statements
+}
+
+function collectCycloneDxComponents(
+  components: unknown,
+  purlByRef: Map<string, string>,
+): void {
+  for (const component of getObjects(components)) {
+    const ref = component['bom-ref']
+    const { purl } = component
+    if (typeof ref === 'string' && typeof purl === 'string') {
+      purlByRef.set(ref, purl)
+    }
+    collectCycloneDxComponents(component['components'], purlByRef)
+  }
+}
+
+function parseCycloneDxVex(doc: Record<string, unknown>): VexStatement[] {
+  const purlByRef = new Map<string, string>()
+  collectCycloneDxComponents(doc['components'], purlByRef)
+  const statements: VexStatement[] = []
+  for (const vulnerability of getObjects(doc['vulnerabilities'])) {
+    const analysis = isPlainObject(vulnerability['analysis'])
+      ? vulnerability['analysis']
+      : {}
+    const status = CYCLONEDX_ANALYSIS_STATES[String(analysis['state'])]
+    if (!status) {
+      continue
+    }
+    const { justification } = analysis
+    statements.push({
+      ids: toVulnerabilityIds([
+        vulnerability['id'],
+        ...getObjects(vulnerability['references']).map(r => r['id']),
+      ]),
+      ...(typeof justification === 'string' ? { justification } : {}),
+      // Refs are bom-refs of the document's components, or purls.
+      products: getObjects(vulnerability['affects'])
+        .map(affect => affect['ref'])
+        .filter((ref): ref is string => typeof ref === 'string')
+        .map(ref => purlByRef.get(ref) ?? ref),
+      status,
+    })
+  }
+  return statements
+}
+
+export function getVexFormat(doc: unknown): VexFormat | undefined {
+  if (!isPlainObject(doc)) {
+    return undefined
+  }
+  if (
+    typeof doc['@context'] === 'string' &&
+    doc['@context'].includes('openvex')
+  ) {
+    return 'openvex'
+  }
+  const document = doc['document']
+  if (isPlainObject(document) && document['category'] === 'csaf_vex') {
+    return 'csaf'
+  }
+  if (doc['bomFormat'] === 'CycloneDX') {
+    return 'cyclonedx'
+  }
+  return undefined
+}
+
+export function parseVexDocument(content: string): CResult<VexStatement[]> {
+  let doc: unknown
+  try {
+    doc = JSON.parse(content)
+  } catch (e) {
+    return {
+      ok: false,
+      message: 'Invalid VEX document',
+      cause: `Invalid JSON: ${getErrorCause(e)}`,
+    }
+  }
+  const format = getVexFormat(doc)
+  if (!format) {
+    return {
+      ok: false,
+      message: 'Invalid VEX document',
+      cause: 'Expected an OpenVEX, CSAF VEX or CycloneDX JSON document',
+    }
+  }
+  const record = doc as Record<string, unknown>
+  return {
+    ok: true,
+    data:
+      format === 'openvex'
+        ? parseOpenVex(record)
+        : format === 'csaf'
+          ? parseCsafVex(record)
+          : parseCycloneDxVex(record),
+  }
+}
+
+/**
+ * Read the statements of every VEX document, in order.
+ */
+export function readVexFilesSync(
+  filepaths: string[],
+): CResult<VexStatement[]> {
+  const statements: VexStatement[] = []
+  for (const filepath of filepaths) {
+    const content = safeReadFileSync(filepath)
+    if (content === undefined) {
+      return {
+        ok: false,
+        message: 'VEX document not found',
+        cause: `Unable to read ${filepath}`,
+      }
+    }
+    const statementsCResult = parseVexDocument(
+      Buffer.isBuffer(content) ? content.toString('utf8') : content,
+    )
+    if (!statementsCResult.ok) {
+      return {
+        ...statementsCResult,
+        message: `Invalid VEX document ${filepath}`,
+      }
+    }
+    statements.push(...statementsCResult.data)
+  }
+  return { ok: true, data: statements }
+}
+
+type SuppressingStatement = {
+  ids: Set<string>
+  products: PurlKey[]
+}
+
+/**
+ * The scan without the vulnerability alerts that a statement marks as
+ * `not_affected` or `fixed`, and the number of alerts left out. Statements
+ * that an alert is affected do not override others.
+ */
+export function applyVexStatements(
+  scan: SocketArtifact[],
+  statements: VexStatement[],
+): { scan: SocketArtifact[]; suppressed: number } {
+  const suppressing: SuppressingStatement[] = []
+  for (const statement of statements) {
+    if (SUPPRESSING_STATUSES.has(statement.status) && statement.ids.length) {
+      const products = statement.products
+        .map(getPurlKey)
+        .filter((key): key is PurlKey => !!key)
+      if (products.length) {
+        suppressing.push({
+          ids: new Set(statement.ids.map(id => id.toUpperCase())),
+          products,
+        })
+      }
+    }
+  }
+  let suppressed = 0
+  if (!suppressing.length) {
+    return { scan, suppressed }
+  }
+  const filtered = scan.map(artifact => {
+    const alerts = artifact.alerts ?? []
+    const statementsForArtifact = suppressing.filter(s =>
+      s.products.some(product => matchesProduct(artifact, product)),
+    )
+    if (!alerts.length || !statementsForArtifact.length) {
+      return artifact
+    }
+    const kept = alerts.filter(alert => {
+      const ids = getAlertVulnerabilityIds(alert)
+      const isSuppressed = statementsForArtifact.some(s =>
+        ids.some(id => s.ids.has(id.toUpperCase())),
+      )
+      if (isSuppressed) {
+        suppressed += 1
+      }
+      return !isSuppressed
+    })
+    return kept.length === alerts.length
+      ? artifact
+      : { ...artifact, alerts: kept }
+  })
+  return { scan: filtered, suppressed }
+}
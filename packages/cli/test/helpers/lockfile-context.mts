@@ -0,0 +1,27 @@
+/**
+ * @file Parse context for lockfile parser tests. Sibling files and directory
+ *   listings are served from in-memory maps keyed by absolute path.
+ */
+
+import path from 'node:path'
+
+import type { LockfileParseContext } from '../../src/util/lockfile/types.mts'
+
+/**
+ * Build the context a lockfile parser gets for the file at `filepath`. Pass
+ * `dirs` to give the parser a `listDir`, e.g. to expand workspace globs.
+ */
+export function makeContext(
+  filepath: string,
+  files: Record<string, string> = {},
+  dirs?: Record<string, string[]> | undefined,
+): LockfileParseContext {
+  const context: LockfileParseContext = {
+    filepath: path.resolve(filepath),
+    readFile: (file: string) => files[path.resolve(file)],
+  }
+  if (dirs) {
+    context.listDir = (dir: string) => dirs[path.resolve(dir)] ?? []
+  }
+  return context
+}
@@ -32,6 +32,28 @@ export function createErrorResult(
   }
 }
 
+/**
+ * Creates an httpRequest response, with synchronous .text() and .json().
+ */
+export function createHttpResponse(opts: {
+  body?: string | undefined
+  ok?: boolean | undefined
+  status?: number | undefined
+  statusText?: string | undefined
+}) {
+  const bodyStr = opts.body ?? ''
+  const bodyBuffer = Buffer.from(bodyStr)
+  return {
+    body: bodyBuffer,
+    headers: {},
+    json: () => JSON.parse(bodyStr),
+    ok: opts.ok ?? true,
+    status: opts.status ?? 200,
+    statusText: opts.statusText ?? 'OK',
+    text: () => bodyStr,
+  }
+}
+
 /**
  * Creates mock logger functions.
  */
@@ -93,3 +115,21 @@ export async function setupSdkSetupFailure(
   const options: ErrorOptions = cause !== undefined ? { cause } : {}
   vi.mocked(setupSdk).mockResolvedValue(createErrorResult(message, options))
 }
+
+/**
+ * Creates the module of a pre-install check that always passes. The wrapper
+ * command tests mock their check with it; the checks have their own tests.
+ *
+ * @example
+ *   vi.mock(import('.../check-pip-install.mts'), async () => {
+ *     const { passingInstallCheck } = await import('.../helpers/mocks.mts')
+ *     return passingInstallCheck('checkPipInstall')
+ *   })
+ */
+export function passingInstallCheck<T extends string>(
+  exportName: T,
+): Record<T, ReturnType<typeof vi.fn>> {
+  return {
+    [exportName]: vi.fn(async () => createSuccessResult(undefined)),
+  } as Record<T, ReturnType<typeof vi.fn>>
+}
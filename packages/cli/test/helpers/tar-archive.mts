@@ -0,0 +1,68 @@
+/**
+ * @file Tar archive builder for container image tests. Produces ustar
+ *   archives with optional pax headers, enough for the container tar reader.
+ */
+
+export type TarArchiveEntry = {
+  content?: string | Buffer | undefined
+  linkname?: string | undefined
+  name: string
+  // Tar typeflag: '0' file, '1' hardlink, '2' symlink, '5' directory.
+  type?: string | undefined
+}
+
+function tarHeader(
+  name: string,
+  size: number,
+  type: string,
+  linkname: string,
+): Buffer {
+  const header = Buffer.alloc(512)
+  header.write(name.slice(0, 100), 0, 'utf8')
+  header.write('0000644\0', 100)
+  header.write(`${size.toString(8).padStart(11, '0')}\0`, 124)
+  header.write(type, 156)
+  header.write(linkname, 157)
+  header.write('ustar\0', 257)
+  header.write('00', 263)
+  return header
+}
+
+function pushEntry(
+  chunks: Buffer[],
+  name: string,
+  data: Buffer,
+  type: string,
+  linkname: string,
+) {
+  chunks.push(
+    tarHeader(name, data.length, type, linkname),
+    data,
+    Buffer.alloc((512 - (data.length % 512)) % 512),
+  )
+}
+
+/**
+ * Build a tar archive. Names longer than 100 characters get a pax `path`
+ * header.
+ */
+export function makeTarArchive(entries: TarArchiveEntry[]): Buffer {
+  const chunks: Buffer[] = []
+  for (const { content = '', linkname = '', name, type = '0' } of entries) {
+    if (name.length > 100) {
+      const record = ` path=${name}\n`
+      let length = record.length
+      length += String(length + String(length).length).length
+      pushEntry(
+        chunks,
+        'PaxHeader',
+        Buffer.from(`${length}${record}`),
+        'x',
+        '',
+      )
+    }
+    pushEntry(chunks, name, Buffer.from(content), type, linkname)
+  }
+  chunks.push(Buffer.alloc(1024))
+  return Buffer.concat(chunks)
+}
@@ -4,6 +4,21 @@ import path from 'node:path'
 
 import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
 
+import type {
+  SocketArtifact,
+  SocketArtifactAlert,
+} from '../../src/util/alert/artifact.mts'
+
+// Alert types and severities are plain strings so tests can use made up ones.
+type AlertFixture = Partial<Omit<SocketArtifactAlert, 'severity' | 'type'>> & {
+  severity?: string | undefined
+  type: string
+}
+
+export type ArtifactFixture = Omit<Partial<SocketArtifact>, 'alerts'> & {
+  alerts?: AlertFixture[] | undefined
+}
+
 /**
  * Creates a temporary copy of a fixture directory for testing. The temporary
  * directory is automatically cleaned up when tests complete.
@@ -67,3 +82,24 @@ export async function withTempFixture(fixturePath: string): Promise<{
 
   return { tempDir, cleanup }
 }
+
+/**
+ * Builds a scanned package for tests: an npm package at version 1.0.0 unless
+ * the overrides say otherwise. Alerts only need a type.
+ *
+ * @param name - Package name.
+ * @param overrides - Artifact fields to set or replace.
+ *
+ * @returns The artifact.
+ */
+export function artifact(
+  name: string,
+  overrides: ArtifactFixture = {},
+): SocketArtifact {
+  return {
+    type: 'npm',
+    name,
+    version: '1.0.0',
+    ...overrides,
+  } as unknown as SocketArtifact
+}
@@ -9,11 +9,12 @@
  * package specs - Exit codes for valid invocations.
  *
  * Command Categories Validated: - Main commands (login, scan, fix, optimize,
- * cdxgen, ci) - Socket API commands (analytics, audit-log, organization,
- * package, repository, scan, threat-feed) - Local tools (manifest, npm, npx,
- * raw-npm, raw-npx) - CLI configuration (config, install, login, logout,
- * uninstall, whoami, wrapper) - Global flags (--compact-header, --config,
- * --dry-run, --help, --version, etc.)
+ * cdxgen, ci) - Socket API commands (analytics, audit-log, bundle, explain,
+ * ghapp, license, organization, package, report, repository, scan, threat-feed, verify, why) - Local tools
+ * (audit-installed, hooks, manifest, npm, npx, raw-npm, raw-npx, registry) - CLI configuration
+ * (cache, completion, config, diagnose, doctor, install, login, logout, self, self-update,
+ * telemetry, uninstall, version, whoami, wrapper) - Global flags (--cacert, --compact-header, --config, --dry-run,
+ * --help, --log-level, --max-retries, --timeout, --version, etc.)
  *
  * Related Files: - src/cli.mts - Main CLI entry point - src/constants/cli.mts -
  * CLI flag constants - test/utils.mts - Test utilities (cmdit, spawnSocketCli)
@@ -57,27 +58,54 @@ describe('socket root command', async () => {
             Socket API
               analytics                   Look up analytics data
               audit-log                   Look up the audit log for an organization
+              bundle                      Move scan data and policy to air-gapped machines
+              compare                     Compare the security posture across an organization
+              container                   Scan container images for vulnerable and malicious packages
+              explain                     Explain an alert of a scan in detail
+              ghapp                       Scan repositories as a GitHub App installation
+              graph                       Export the dependency graph of Socket scans
+              license                     Report and check the licenses of scanned packages
               organization                Manage Socket organization account details
               package                     Look up published package details
+              report                      Report the results of a scan to other tools
               repository                  Manage registered repositories
+              sbom                        Export Socket scans as Software Bill of Materials documents
               scan                        Manage Socket scans
               threat-feed                 [Beta] View the threat-feed
+              verify                      Verify the provenance and signatures of a published package
+              why                         Show which direct dependencies pull a package into a scan
           
             Local tools
+              audit-installed             Verify the installed node_modules against the lockfile and registry
+              history                     Query the local history of scan results
+              hooks                       Check new dependencies in git hooks before commit and push
               manifest                    Generate a dependency manifest for certain ecosystems
               npm                         Run npm with Socket Firewall security
               npx                         Run pnpm exec with Socket Firewall security
+              policy                      Work with the repository socket.policy.yml
+              purl                        Parse, normalize and convert package URLs
               pycli                       Run Socket Python CLI (socketsecurity) directly
               raw-npm                     Run npm without the Socket wrapper
               raw-npx                     Run pnpm exec without the Socket wrapper
+              registry                    Protect package registry traffic with Socket
+              schema                      Print the JSON Schemas of the --json output
               sfw                         Run Socket Firewall directly (alias: firewall)
+              suppressions                Review the alert exceptions in socket.policy.yml
           
             CLI configuration
+              cache                       Inspect and clear the local response caches
+              completion                  Print a tab completion script for bash, zsh, fish or PowerShell
               config                      Manage Socket CLI configuration
+              diagnose                    Diagnose problems running Socket CLI
+              doctor                      Check the Socket CLI setup and suggest fixes
               install                     Install Socket CLI tab completion
               login                       Setup Socket CLI with an API token and defaults
               logout                      Socket API logout
+              self                        Install the standalone Socket CLI executable
+              self-update                 Update the Socket CLI executable to the latest release
+              telemetry                   Opt in to or out of anonymous usage telemetry
               uninstall                   Uninstall Socket CLI tab completion
+              version                     Show the Socket CLI version and check for updates
               whoami                      Check Socket CLI authentication status
               wrapper                     Enable or disable the Socket npm/pnpm exec wrapper
           
@@ -85,14 +113,22 @@ describe('socket root command', async () => {
             Options
               Note: All commands have these flags even when not displayed in their help
           
+              --api-base-url              Base URL of the Socket API, e.g. of a single-tenant or regional deployment (config: apiBaseUrl)
+              --cacert                    Trust the CA certificates in this PEM file, e.g. of a TLS-inspecting corporate proxy
               --compact-header            Use compact single-line header format (auto-enabled in CI)
               --config                    Override the local config with this JSON
               --dry-run                   Run without uploading
               --help                      Show help
               --help-full                 Show full help including environment variables
+              --log-file                  Append the structured log to this file instead of writing it to stderr
+              --log-format                Format of the structured log: text (default) or json
+              --log-level                 Write a structured log with trace and API request IDs, from this level: error, warn, info, debug or trace
+              --max-retries               Retry failed Socket API requests this many times (config: apiMaxRetries)
               --no-banner                 Hide the Socket banner
               --no-spinner                Hide the console spinner
               --quiet                     Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
+              --retry-backoff             Milliseconds before the first retry, doubled with jitter for each next one (config: apiRetryBackoff)
+              --timeout                   Milliseconds before a Socket API request times out (config: apiTimeout)
               --version                   Print the app version
           
             Environment variables [more\\u2026]
@@ -69,6 +69,8 @@ describe('socket audit-log', async () => {
           
               The page arg should be a positive integer, offset 1. Defaults to 1.
           
+              To export the whole log for a SIEM, see \`socket organization audit-log export\`.
+          
               Options
                 --interactive       Allow for interactive elements, asking for input.
                                     Use --no-interactive to prevent any input questions, defaulting them to cancel/no.
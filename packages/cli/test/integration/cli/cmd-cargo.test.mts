@@ -41,6 +41,12 @@ describe('socket cargo', async () => {
           
               Note: Everything after "cargo" is forwarded to Socket Firewall (sfw).
                     Socket Firewall provides real-time security scanning for cargo packages.
+                    Crates named by \`cargo add\` and \`cargo install\` are checked against Socket
+                    before cargo runs. Build scripts, network access and likely typosquats
+                    prompt for confirmation unless your organization policy says otherwise.
+          
+              With --non-interactive, or without a TTY, prompts take the answer of
+              \`wrapper.nonInteractive\` in socket.yml (allow or block, allow by default).
           
               Examples
                 $ socket cargo install ripgrep
@@ -47,21 +47,51 @@ describe('socket ci', async () => {
           
               Options
                 --auto-manifest     Auto generate manifest files where detected? See autoManifest flag in \`socket scan create\`
+                --fail-on           Fail on alerts of at least this severity instead of on the policy error level: 'critical', 'high', 'any', 'none'. 'any' fails on every alert the policy does not ignore, 'none' never fails
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
+                --smart             Only scan the manifests the pull request changed and compare with the latest scan of the base branch
           
               This command is intended to use in CI runs to allow automated systems to
               accept or reject a current build. It will use the default org of the
               Socket API token. The exit code will be non-zero when the scan does not pass
               your security policy.
           
+              Exit codes: 0 when the scan passes, 3 when it violates the policy, 4 on
+              Socket API errors, 5 on auth errors and 2 on invalid usage. Other
+              failures exit with 1, so a pipeline can tell "found issues" from "the
+              tool broke". Pass --fail-on to gate on alert severity instead of on the
+              "error" policy level, e.g. --fail-on=critical, or --fail-on=none to only
+              fail when the scan itself fails.
+          
               The --auto-manifest flag does the same as the one from \`socket scan create\`
               but is not enabled by default since the CI is less likely to be set up with
               all the necessary dev tooling. Enable it if you want the scan to include
               locally generated manifests like for gradle and sbt.
           
+              With --smart, only the manifests and lockfiles in dirs the pull request
+              changed since the base branch are uploaded, as a scan hidden from the
+              dashboard, and the build only fails on alerts that scan adds compared to
+              the latest scan of the base branch. The base branch must be fetched, at
+              \`origin/<base>\`, and needs an earlier \`socket ci\` scan.
+          
+              Run \`socket ci report\` to summarize the alert changes of a pull request,
+              or \`socket ci report --github\` to post that summary as a PR comment
+              (\`--gitlab\` for a merge request note).
+          
+              Run \`socket ci enrich-bot-pr --github\` on Renovate and Dependabot pull
+              requests to comment the score and alert changes of the bumped package.
+          
+              Run \`socket ci token\` to mint a short-lived API token for the CI job,
+              from the org API token or the OIDC identity of the job.
+          
               Examples
                 $ socket ci
-                $ socket ci --auto-manifest"
+                $ socket ci --auto-manifest
+                $ socket ci --fail-on=high
+                $ socket ci --smart
+                $ socket ci report --github
+                $ socket ci enrich-bot-pr --github
+                $ socket ci token --oidc --org my-org"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
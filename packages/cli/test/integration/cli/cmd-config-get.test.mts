@@ -19,10 +19,12 @@
  *
  * Configuration Precedence (highest to lowest):
  *
- * 1. Environment variables (SOCKET_CLI_API_TOKEN, SOCKET_CLI_API_KEY)
- * 2. Legacy environment variables (SOCKET_SECURITY_API_KEY)
- * 3. Command-line flag (--config)
- * 4. Local config file
+ * 1. Command flags (--org)
+ * 2. Environment variables (SOCKET_CLI_API_TOKEN, SOCKET_CLI_API_KEY), then
+ *    legacy environment variables (SOCKET_SECURITY_API_KEY)
+ * 3. The `cli` section of the repo socket.yml
+ * 4. Command-line flag (--config), standing in for the local config file
+ * 5. System config file
  *
  * Available Config Keys:
  *
@@ -77,24 +79,36 @@ describe('socket config get', async () => {
                 --json              Output as JSON
                 --markdown          Output as Markdown
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
+                --source            Show where the effective value comes from: a flag, an env var, the repo socket.yml, the user config or the system config
           
-              Retrieve the value for given KEY at this time. If you have overridden the
-              config then the value will come from that override.
+              Retrieve the effective value for given KEY at this time. The first of
+              these that sets the KEY wins: a flag, an env var, the \`cli\` section of
+              the repo socket.yml, the user config (or the --config flag standing in
+              for it) and the system config. Use --source to see which one it was.
           
               KEY is an enum. Valid keys:
           
               Keys:
           
-               - apiBaseUrl -- Base URL of the Socket API endpoint
+               - activeProfile -- The named auth profile whose API token, proxy, base URL and orgs are used; set it with \`socket config use-profile\`
+               - apiAuthScheme -- How Socket API requests send the API token: basic (default) or bearer, for deployments whose gateway expects a Bearer token
+               - apiBaseUrl -- Base URL of the Socket API endpoint, e.g. of a single-tenant or regional Socket deployment, like --api-base-url
+               - apiMaxRetries -- How often a failed Socket API request is retried, like --max-retries
                - apiProxy -- A proxy through which to access the Socket API
+               - apiRetryBackoff -- Milliseconds to wait before the first retry of a failed Socket API request, doubled for each following retry, like --retry-backoff
+               - apiTimeout -- Milliseconds after which a Socket API request times out, like --timeout
                - apiToken -- The Socket API token required to access most Socket API endpoints
+               - caCert -- Path to a PEM file of extra CA certificates to trust, e.g. of a TLS-inspecting corporate proxy
                - defaultOrg -- The default org slug to use; usually the org your Socket API token has access to. When set, all orgSlug arguments are implied to be this value.
+               - disableSelfUpdate -- Set to true to turn off \`socket self-update\` and update notifications, e.g. where an administrator manages the Socket CLI
                - enforcedOrgs -- Orgs in this list have their security policies enforced on this machine
                - org -- Alias for defaultOrg
                - skipAskToPersistDefaultOrg -- This flag prevents the Socket CLI from asking you to persist the org slug when you selected one interactively
+               - telemetry -- Set to true to send anonymous usage telemetry (command names, durations and error classes) to Socket; see \`socket telemetry status\`
           
               Examples
-                $ socket config get defaultOrg"
+                $ socket config get defaultOrg
+                $ socket config get defaultOrg --source"
       `)
       // Node 24 on Windows currently fails this test with added stderr:
       // Assertion failed: !(handle->flags & UV_HANDLE_CLOSING), file src\win\async.c, line 76
@@ -371,4 +385,54 @@ describe('socket config get', async () => {
       )
     })
   })
+
+  describe('--source', () => {
+    cmdit(
+      [
+        'config',
+        'get',
+        'defaultOrg',
+        '--source',
+        FLAG_CONFIG,
+        '{"defaultOrg":"config-org"}',
+      ],
+      'should show the config override as the source',
+      async cmd => {
+        const { code, stdout } = await spawnSocketCli(binCliPath, cmd, {
+          env: { SOCKET_CLI_ORG_SLUG: '' },
+        })
+        expect(stdout).toMatchInlineSnapshot(`
+          "defaultOrg: config-org
+          Source: user (--config)
+
+          Note: the config is in read-only mode, meaning at least one key was temporarily overridden from an env var or command flag."
+        `)
+        expect(code).toBe(0)
+      },
+    )
+
+    cmdit(
+      [
+        'config',
+        'get',
+        'defaultOrg',
+        '--source',
+        FLAG_CONFIG,
+        '{"defaultOrg":"config-org"}',
+      ],
+      'should prefer the env var over the config',
+      async cmd => {
+        const { code, stdout } = await spawnSocketCli(binCliPath, cmd, {
+          env: { SOCKET_CLI_ORG_SLUG: 'env-org' },
+        })
+        expect(stdout).toMatchInlineSnapshot(`
+          "defaultOrg: env-org
+          Source: env (SOCKET_CLI_ORG_SLUG)
+
+          Note: the config is in read-only mode, meaning at least one key was temporarily overridden from an env var or command flag."
+        `)
+        expect(code).toBe(0)
+      },
+    )
+  })
 })
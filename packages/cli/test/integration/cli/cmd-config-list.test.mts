@@ -47,9 +47,15 @@ describe('socket config get', async () => {
                 --json              Output as JSON
                 --markdown          Output as Markdown
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
+                --source            Show where the effective value comes from: a flag, an env var, the repo socket.yml, the user config or the system config
+          
+              Shows the effective value of each key. With --source, also shows
+              whether it comes from a flag, an env var, the repo socket.yml, the user
+              config or the system config.
           
               Examples
-                $ socket config list"
+                $ socket config list
+                $ socket config list --source"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
@@ -57,13 +57,21 @@ describe('socket config unset', async () => {
           
               Keys:
           
-               - apiBaseUrl -- Base URL of the Socket API endpoint
+               - activeProfile -- The named auth profile whose API token, proxy, base URL and orgs are used; set it with \`socket config use-profile\`
+               - apiAuthScheme -- How Socket API requests send the API token: basic (default) or bearer, for deployments whose gateway expects a Bearer token
+               - apiBaseUrl -- Base URL of the Socket API endpoint, e.g. of a single-tenant or regional Socket deployment, like --api-base-url
+               - apiMaxRetries -- How often a failed Socket API request is retried, like --max-retries
                - apiProxy -- A proxy through which to access the Socket API
+               - apiRetryBackoff -- Milliseconds to wait before the first retry of a failed Socket API request, doubled for each following retry, like --retry-backoff
+               - apiTimeout -- Milliseconds after which a Socket API request times out, like --timeout
                - apiToken -- The Socket API token required to access most Socket API endpoints
+               - caCert -- Path to a PEM file of extra CA certificates to trust, e.g. of a TLS-inspecting corporate proxy
                - defaultOrg -- The default org slug to use; usually the org your Socket API token has access to. When set, all orgSlug arguments are implied to be this value.
+               - disableSelfUpdate -- Set to true to turn off \`socket self-update\` and update notifications, e.g. where an administrator manages the Socket CLI
                - enforcedOrgs -- Orgs in this list have their security policies enforced on this machine
                - org -- Alias for defaultOrg
                - skipAskToPersistDefaultOrg -- This flag prevents the Socket CLI from asking you to persist the org slug when you selected one interactively
+               - telemetry -- Set to true to send anonymous usage telemetry (command names, durations and error classes) to Socket; see \`socket telemetry status\`
           
               Examples
                 $ socket config unset defaultOrg"
@@ -61,10 +61,13 @@ describe('socket config', async () => {
           
             Commands
               auto                        Automatically discover and set the correct value config item
+              effective                   Show the merged socket.yml that applies to a directory
               get                         Get the value of a local CLI config item
               list                        Show all local CLI config items and their values
+              migrate-token               Move API tokens from the config file to the OS keychain
               set                         Update the value of a local CLI config item
               unset                       Clear the value of a local CLI config item
+              use-profile                 Switch the active auth profile
           
             Options
           
@@ -217,6 +217,8 @@ describe('socket fix', async () => {
                 --all               Process all discovered vulnerabilities in local mode. Cannot be used with --id.
                 --autopilot         Enable auto-merge for pull requests that Socket opens.
                                     See GitHub documentation (https://docs.github.com/en/repositories/configuring-branches-and-merges-in-your-repository/configuring-pull-request-merges/managing-auto-merge-for-pull-requests-in-your-repository) for managing auto-merge for pull requests in your repository.
+                --cache             Read and write the local response cache. Use --no-cache to bypass it for this run.
+                --cache-ttl         Treat cached responses older than this (e.g. 30m, 12h, 7d) as stale for this run
                 --debug             Enable debug logging in the Coana-based Socket Fix CLI invocation.
                 --ecosystems        Limit fix analysis to specific ecosystems. Can be provided as comma separated values or as multiple flags. Defaults to all ecosystems.
                 --exclude           Exclude workspaces matching these glob patterns. Can be provided as comma separated values or as multiple flags
@@ -241,6 +243,9 @@ describe('socket fix', async () => {
                                       * preserve - Retain the existing version range style as-is
                 --show-affected-direct-dependencies  List the direct dependencies responsible for introducing transitive vulnerabilities and list the updates required to resolve the vulnerabilities
                 --silence           Silence all output except the final result
+                --test              Verify the fix by running the test script after installing. Implies --verify.
+                --test-script       The test script to run for fix attempts (default 'test')
+                --verify            Verify fixes by running the package manager install after applying them. In CI mode, fixes that fail verification are not opened as pull requests.
           
               Environment Variables (for CI/PR mode)
                 CI                          Set to enable CI mode
@@ -287,7 +292,7 @@ describe('socket fix', async () => {
         [DryRun]: Analyze and compute and apply fixes for discovered vulnerabilities
 
           Actions that would be performed:
-            - [fetch] Scan project dependencies for vulnerabilities \\u2192 [PROJECT]
+            - [upload] Upload manifest files to scan for vulnerabilities \\u2192 [PROJECT]
                 organization: "fake-org"
                 ecosystems: "all"
             - [fetch] Analyze vulnerability fix options
@@ -357,7 +362,7 @@ describe('socket fix', async () => {
       FLAG_CONFIG,
       '{"apiToken":"fakeToken","defaultOrg":"fake-org"}',
     ],
-    'should ignore --test flag',
+    'should accept --test flag',
     async cmd => {
       const { code, stderr, stdout } = await spawnSocketCli(binCliPath, cmd)
 
@@ -378,7 +383,7 @@ describe('socket fix', async () => {
       FLAG_CONFIG,
       '{"apiToken":"fakeToken","defaultOrg":"fake-org"}',
     ],
-    'should ignore --test-script flag',
+    'should accept --test-script flag',
     async cmd => {
       const { code, stderr, stdout } = await spawnSocketCli(binCliPath, cmd)
 
@@ -37,8 +37,13 @@ describe('socket go', async () => {
           
               Note: Everything after "go" is forwarded to Socket Firewall (sfw).
                     Socket Firewall provides real-time security scanning for go packages.
+                    Modules requested by \`go get\` and \`go install\` are checked against Socket
+                    before the go command fetches them.
                     Wrapper mode works best on Linux (macOS may have keychain issues).
           
+              With --non-interactive, or without a TTY, prompts take the answer of
+              \`wrapper.nonInteractive\` in socket.yml (allow or block, allow by default).
+          
               Examples
                 $ socket go get github.com/gin-gonic/gin
                 $ socket go install golang.org/x/tools/cmd/goimports
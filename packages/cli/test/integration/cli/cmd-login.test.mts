@@ -53,14 +53,40 @@ describe('socket login', async () => {
           
               Logs into the Socket API by prompting for an API token
           
+              With --profile the token and orgs are stored in a named profile, so you
+              can keep a login per Socket org and switch between them with
+              \`socket config use-profile\`.
+          
+              With --api-base-url you log into a single-tenant, on-prem or regional
+              Socket deployment; the URL is stored with the credentials. A bare origin
+              gets the API version appended, e.g. https://socket.example.com/v0/. Set
+              the apiAuthScheme config value to bearer when its gateway expects a
+              Bearer token.
+          
+              The API token is kept in the OS keychain (macOS Keychain, Windows
+              Credential Manager or libsecret) when one is available, and in the
+              config file otherwise.
+          
+              With --sso you log in through the browser: open the URL shown, enter
+              the code and sign in with your SSO provider. Use it when your org
+              enforces SSO. The issuer is the Socket API unless SOCKET_CLI_SSO_ISSUER
+              is set.
+          
               Options
-                --api-base-url      API server to connect to for login
+                --api-base-url      API server to connect to for login, stored with the credentials
                 --api-proxy         Proxy to use when making connection to API server
+                --no-keychain       Store the API token in the config file instead of the OS keychain, e.g. in headless CI
+                --profile           Store the API token and orgs in this named profile and make it the active one
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
+                --sso               Log in through your SSO provider in the browser instead of entering an API token
           
               Examples
                 $ socket login
-                $ socket login --api-proxy=http://localhost:1234"
+                $ socket login --api-proxy=http://localhost:1234
+                $ socket login --api-base-url=https://socket.example.com --profile onprem
+                $ socket login --profile work
+                $ socket login --no-keychain
+                $ socket login --sso"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
@@ -105,7 +131,7 @@ describe('socket login', async () => {
           Changes:
             - Prompt for Socket API token
             - Verify token with Socket API
-            - Save API token to config
+            - Save API token to the OS keychain, or to config if unavailable
             - Optionally set default organization
             - Optionally install bash completion
 
@@ -47,6 +47,7 @@ describe('socket logout', async () => {
                 $ socket logout [options]
           
               Logs out of the Socket API and clears all Socket credentials from disk
+              and the OS keychain
           
               Examples
                 $ socket logout"
@@ -159,6 +159,8 @@ describe('socket manifest conda', async () => {
         })
         expect(cleanOutput(stdout)).toMatchInlineSnapshot(`
           "{
+            "version": "1",
+            "command": "manifest conda",
             "ok": true,
             "data": {
               "content": "name: my_stuff\\n\\nchannels:\\n  - conda-thing\\n  - defaults\\ndependencies:\\n  - python=3.8\\n  - pandas=1.3.4\\n  - numpy=1.19.0\\n  - scipy\\n  - mkl-service\\n  - libpython\\n  - m2w64-toolchain\\n  - pytest\\n  - requests\\n  - pip\\n  - pip:\\n      - qgrid==1.3.0\\n      - mplstereonet\\n      - pyqt5\\n      - gempy==2.1.0\\n",
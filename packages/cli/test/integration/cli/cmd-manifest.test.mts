@@ -14,6 +14,7 @@
  *
  * - Auto: Auto-detect and generate manifests
  * - Conda: Generate conda environment manifests
+ * - Generate: Resolve loose requirements into a pinned SBOM
  * - Gradle: Generate Gradle project manifests
  * - Kotlin: Generate Kotlin project manifests
  * - Scala: Generate Scala/SBT project manifests
@@ -53,6 +54,7 @@ describe('socket manifest', async () => {
               auto                        Auto-detect build and attempt to generate manifest file
               cdxgen                      Run cdxgen for SBOM generation
               conda                       [beta] Convert a Conda environment.yml file to a python requirements.txt
+              generate                    Resolve loose requirements locally into a pinned manifest for scanning
               gradle                      [beta] Use Gradle to generate a manifest file (\`pom.xml\`) for a Gradle/Java/Kotlin/etc project
               kotlin                      [beta] Use Gradle to generate a manifest file (\`pom.xml\`) for a Kotlin project
               scala                       [beta] Generate a manifest file (\`pom.xml\`) from Scala's \`build.sbt\` file
@@ -54,13 +54,25 @@ describe('socket npm', async () => {
           
               Note: Everything after "npm" is forwarded to Socket Firewall (sfw).
                     Socket Firewall provides real-time security scanning for npm packages.
+                    Packages named by \`npm install\` are checked for likely typosquats before installing.
+                    Set SOCKET_CLI_VERIFY_PROVENANCE=1 to verify package provenance before installing.
+                    With SOCKET_CLI_OFFLINE=1 these checks use the local score cache. Socket Firewall
+                    itself still checks the packages with the Socket API.
+                    With --sandbox-scripts, install scripts run without network and with a read-only
+                    filesystem, and .socket.install-scripts.json records the programs, network endpoints
+                    and files they tried to use. \`socket scan create\` uploads it with the scan.
+                    Needs Linux with bubblewrap (bwrap) and strace.
+          
+              With --non-interactive, or without a TTY, prompts take the answer of
+              \`wrapper.nonInteractive\` in socket.yml (allow or block, allow by default).
           
               Use \`socket wrapper on\` to alias this command as \`npm\`.
           
               Examples
                 $ socket npm
                 $ socket npm install cowsay
-                $ socket npm install -g cowsay"
+                $ socket npm install -g cowsay
+                $ socket npm install --sandbox-scripts"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
@@ -151,10 +151,19 @@ describe('socket optimize', async () => {
                 --pin               Pin overrides to latest version
                 --prod              Add overrides for production dependencies only
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
+                --safe-versions     Pin alerted transitive dependencies to patched versions instead of adding @socketregistry overrides
+          
+              With --safe-versions the transitive dependencies with vulnerability
+              alerts are pinned to their first patched version with \`overrides\`
+              (npm, pnpm) or \`resolutions\` (yarn, bun). Use this when the direct
+              dependency that pulls them in can't be bumped yet. The command reinstalls
+              and checks that the lockfile no longer resolves the vulnerable versions;
+              a failed install reverts package.json and the lockfile.
           
               Examples
                 $ socket optimize
-                $ socket optimize ./path/to/project --pin"
+                $ socket optimize ./path/to/project --pin
+                $ socket optimize --safe-versions --prod"
       `,
       )
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
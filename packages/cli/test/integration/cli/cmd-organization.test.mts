@@ -12,10 +12,13 @@
  *
  * Available Subcommands:
  *
+ * - Audit-log: Export the organization audit log
  * - Dependencies: View organization dependencies
  * - List: List available organizations
+ * - Members: Manage organization members
  * - Policy: Manage security policies
  * - Quota: View API quota usage
+ * - Tokens: Manage organization API tokens
  *
  * Related Files:
  *
@@ -49,10 +52,13 @@ describe('socket organization', async () => {
               $ socket organization <command>
           
             Commands
+              audit-log                   Export the audit log of an organization
               dependencies                Search for any dependency that is being used in your organization
               list                        List organizations associated with the Socket API token
+              members                     Manage who has access to an organization
               policy                      Organization policy details
               quota                       Show remaining Socket API quota for the current token, plus refresh window
+              tokens                      Manage the API tokens of an organization
           
             Options
           
@@ -36,14 +36,21 @@ describe('socket package score', async () => {
 
           Usage
                 $ socket package score [options] <<ECOSYSTEM> <NAME> | <PURL>>
+                $ socket package score [options] --input <FILE|->
           
               API Token Requirements
                 - Quota: 100 units
                 - Permissions: packages:list
           
               Options
+                --cache             Read and write the local response cache. Use --no-cache to bypass it for this run.
+                --cache-ttl         Treat cached responses older than this (e.g. 30m, 12h, 7d) as stale for this run
+                --csv               Output the scores read with --input as CSV
+                --input             Score the purls in this file, one per line, or - to read them from stdin
                 --json              Output as JSON
                 --markdown          Output as Markdown
+                --offline           Serve results from the local score cache without calling the Socket API
+                --output-template   Render the result with a Handlebars-style template file instead of the default output
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
           
               Show deep scoring details for one package. The score will reflect the package
@@ -51,6 +58,23 @@ describe('socket package score', async () => {
           
               When you want to know whether to trust a package, this is the command to run.
           
+              Results are cached on disk for a day. With --offline (or SOCKET_CLI_OFFLINE=1)
+              the score is served from that cache, however old, and the command fails
+              when the package was never looked up on this machine. Use --no-cache to
+              skip the cache for one run, --cache-ttl to change how long entries stay
+              fresh, and \`socket cache\` to inspect or clear it.
+          
+              With --input the command scores a whole list of packages instead, like a
+              vendored dependency list without a manifest. The list holds one purl per
+              line; blank lines and # comments are skipped. The packages are looked up
+              in batches and reported one row each, with --json, --csv, --markdown or
+              as a table. In this mode the scores are those of each package itself,
+              like \`socket package shallow\` reports them, not of its dependencies.
+          
+              Use --output-template=<file> to render the score with your own Handlebars-
+              style template instead; the context is the \`data\` of the --json output.
+              See \`socket scan report --help\` for the template syntax.
+          
               See also the \`socket package shallow\` command, which returns the shallow
               score for any number of packages. That will not reflect the dependency scores.
           
@@ -69,7 +93,10 @@ describe('socket package score', async () => {
                 $ socket package score npm babel-cli
                 $ socket package score npm eslint@1.0.0 --json
                 $ socket package score pkg:golang/github.com/steelpoor/tlsproxy@v0.0.0-20250304082521-29051ed19c60
-                $ socket package score nuget/needpluscommonlibrary@1.0.0 --markdown"
+                $ socket package score nuget/needpluscommonlibrary@1.0.0 --markdown
+                $ socket package score npm eslint --output-template=score.hbs
+                $ socket package score --input purls.txt --csv > scores.csv
+                $ cat purls.txt | socket package score --input - --json"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
@@ -43,8 +43,11 @@ describe('socket package shallow', async () => {
                 - Permissions: packages:list
           
               Options
+                --cache             Read and write the local response cache. Use --no-cache to bypass it for this run.
+                --cache-ttl         Treat cached responses older than this (e.g. 30m, 12h, 7d) as stale for this run
                 --json              Output as JSON
                 --markdown          Output as Markdown
+                --offline           Serve results from the local score cache without calling the Socket API
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
           
               Show scoring details for one or more packages purely based on their own package.
@@ -59,6 +62,12 @@ describe('socket package shallow', async () => {
               If the first arg is an ecosystem, remaining args that are not a purl are
               assumed to be scoped to that ecosystem. The \`pkg:\` prefix is optional.
           
+              Results are cached on disk for a day. With --offline (or SOCKET_CLI_OFFLINE=1)
+              packages are served from that cache, however old, and the command fails
+              listing any package that was never looked up on this machine. Use
+              --no-cache to skip the cache for one run, --cache-ttl to change how long
+              entries stay fresh, and \`socket cache\` to inspect or clear it.
+          
               Note: if a package cannot be found, it may be too old or perhaps was removed
                     before we had the opportunity to process it.
           
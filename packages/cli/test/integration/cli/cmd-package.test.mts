@@ -48,6 +48,8 @@ describe('socket package', async () => {
               $ socket package <command>
           
             Commands
+              diff                        Compare two published versions of a package: files, install scripts, capabilities, dependencies and scores
+              files                       List the files of a published package and compare them with its source repository
               score                       Look up score for one package which reflects all of its transitive dependencies as well
               shallow                     Look up info regarding one or more packages but not their transitives
           
@@ -42,6 +42,10 @@ describe('socket pip', async () => {
           
               Note: Everything after "pip" is forwarded to Socket Firewall (sfw).
                     Socket Firewall provides real-time security scanning for pip packages.
+                    Packages resolved by \`pip install\` are checked against Socket before installing.
+          
+              With --non-interactive, or without a TTY, prompts take the answer of
+              \`wrapper.nonInteractive\` in socket.yml (allow or block, allow by default).
           
               Examples
                 $ socket pip install flask
@@ -76,6 +80,10 @@ describe('socket pip3', async () => {
           
               Note: Everything after "pip" is forwarded to Socket Firewall (sfw).
                     Socket Firewall provides real-time security scanning for pip packages.
+                    Packages resolved by \`pip install\` are checked against Socket before installing.
+          
+              With --non-interactive, or without a TTY, prompts take the answer of
+              \`wrapper.nonInteractive\` in socket.yml (allow or block, allow by default).
           
               Examples
                 $ socket pip install flask
@@ -63,6 +63,12 @@ describe('socket pnpm', async () => {
           
               Note: Everything after "pnpm" is forwarded to Socket Firewall (sfw).
                     Socket Firewall provides real-time security scanning for pnpm packages.
+                    Packages named by \`pnpm add\`, and the direct dependencies locked for a bare
+                    \`pnpm install\`, are checked with Socket first. Alerts the org policy blocks
+                    stop the install and alerts it warns about ask before installing.
+          
+              With --non-interactive, or without a TTY, prompts take the answer of
+              \`wrapper.nonInteractive\` in socket.yml (allow or block, allow by default).
           
               Use \`socket wrapper on\` to alias this command as \`pnpm\`.
           
@@ -83,6 +89,12 @@ describe('socket pnpm', async () => {
           
               Note: Everything after "pnpm" is forwarded to Socket Firewall (sfw).
                     Socket Firewall provides real-time security scanning for pnpm packages.
+                    Packages named by \`pnpm add\`, and the direct dependencies locked for a bare
+                    \`pnpm install\`, are checked with Socket first. Alerts the org policy blocks
+                    stop the install and alerts it warns about ask before installing.
+          
+              With --non-interactive, or without a TTY, prompts take the answer of
+              \`wrapper.nonInteractive\` in socket.yml (allow or block, allow by default).
           
               Use \`socket wrapper on\` to alias this command as \`pnpm\`.
           
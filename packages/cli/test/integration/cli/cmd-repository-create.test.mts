@@ -135,6 +135,16 @@ describe('socket repository create', async () => {
             organization: "fakeOrg"
             repository: "a"
 
+          API request: POST orgs/fakeOrg/repos
+          Request body:
+            {
+              "name": "a",
+              "default_branch": "main",
+              "description": "",
+              "homepage": "",
+              "visibility": "private"
+            }
+
           Run without --dry-run to perform this upload."
       `)
 
@@ -265,6 +275,16 @@ describe('socket repository create', async () => {
             organization: "fakeOrg"
             repository: "fakerepo"
 
+          API request: POST orgs/fakeOrg/repos
+          Request body:
+            {
+              "name": "fakerepo",
+              "default_branch": "main",
+              "description": "",
+              "homepage": "",
+              "visibility": "private"
+            }
+
           Run without --dry-run to perform this upload."
       `)
 
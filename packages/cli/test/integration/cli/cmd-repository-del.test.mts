@@ -126,6 +126,8 @@ describe('socket repository del', async () => {
 
           Target: xyz/a
 
+          API request: DELETE orgs/xyz/repos/a
+
           This action cannot be undone.
           Run without --dry-run to perform this deletion."
       `)
@@ -255,6 +257,8 @@ describe('socket repository del', async () => {
 
           Target: fakeOrg/fakerepo
 
+          API request: DELETE orgs/fakeOrg/repos/fakerepo
+
           This action cannot be undone.
           Run without --dry-run to perform this deletion."
       `)
@@ -223,6 +223,17 @@ describe('socket repository update', async () => {
             organization: "fakeOrg"
             repository: "fakerepo"
 
+          API request: POST orgs/fakeOrg/repos/fakerepo
+          Request body:
+            {
+              "default_branch": "main",
+              "description": "",
+              "homepage": "",
+              "name": "fakerepo",
+              "orgSlug": "fakeOrg",
+              "visibility": "private"
+            }
+
           Run without --dry-run to perform this upload."
       `)
 
@@ -14,6 +14,7 @@
  * - Create: Register new repository
  * - Del: Unregister repository
  * - List: List registered repositories
+ * - Trend: Score and alert trends over time
  * - Update: Update repository settings
  * - View: View repository details
  *
@@ -52,6 +53,7 @@ describe('socket repository', async () => {
               create                      Create a repository in an organization
               del                         Delete a repository in an organization
               list                        List repositories in an organization
+              trend                       Show score and alert trends of a repository over time
               update                      Update a repository in an organization
               view                        View repositories in an organization
           
@@ -113,6 +115,7 @@ describe('socket repository', async () => {
               create                      Create a repository in an organization
               del                         Delete a repository in an organization
               list                        List repositories in an organization
+              trend                       Show score and alert trends of a repository over time
               update                      Update a repository in an organization
               view                        View repositories in an organization
           
@@ -32,29 +32,45 @@ describe('socket scan create', async () => {
                 - Permissions: full-scans:create
           
               Options
+                --all-repos         Scan every repository of --github-org or --gitlab-group, or every git clone in the TARGET dir, and report them ranked by new critical alerts
                 --auto-manifest     Run \`socket manifest auto\` before collecting manifest files. This is necessary for languages like Scala, Gradle, and Kotlin, See \`socket manifest auto --help\`.
                 --basics            Run comprehensive security scanning (SAST, secrets, containers) via socket-basics. Requires Python, Trivy, TruffleHog, and OpenGrep to be available.
                 --branch            Branch name
+                --callback-url      URL to POST a JSON summary of the scan to once its analysis is done, requires --wait
+                --changed-since     Only include the monorepo workspaces with files changed since this git ref, e.g. \`origin/main\`. A changed root lockfile selects every workspace.
                 --commit-hash       Commit hash
                 --commit-message    Commit message
                 --committers        Committers
                 --cwd               working directory, defaults to process.cwd()
                 --exclude-paths     List of glob patterns to exclude from the scan, including SCA/SBOM manifest discovery and (when --reach is enabled) Tier 1 reachability analysis. Patterns are matched relative to the project root. Bare directory names are auto-extended to recursive globs (e.g. \`tests\` becomes \`tests/**\`). Trailing slashes are stripped. Negation patterns (\`!path\`) are not supported. Accepts a comma-separated value or multiple flags.
+                --fail-on           Fail on alerts of at least this severity instead of on the policy error level: 'critical', 'high', 'any', 'none'. 'any' fails on every alert the policy does not ignore, 'none' never fails
+                --format            Render the --report output in an alternative format. Supported: 'sarif', 'gitlab-code-quality', 'gitlab-sast', 'junit', 'html', 'csv'
+                --from-sbom         Scan the components of this CycloneDX or SPDX JSON document instead of the manifest files of the TARGETs
+                --github-org        With --all-repos, the GitHub organization whose repositories to scan. Uses SOCKET_CLI_GITHUB_TOKEN
+                --gitlab-group      With --all-repos, the GitLab group whose repositories, subgroups included, to scan. Uses GITLAB_TOKEN and GITLAB_HOST
                 --interactive       Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.
                 --json              Output as JSON
                 --make-default-branch  Reassign the repo's default-branch pointer at Socket to the branch of this scan. The previous default-branch designation is replaced. Mirrors the \`make_default_branch\` API field.
                 --markdown          Output as Markdown
+                --notify            Post a summary of the scan to a chat webhook when it is created, "slack" or "teams"
+                --only-reachable    Leave out alerts of packages the project sources do not import, directly or through other packages, from the --report output
                 --org               Force override the organization slug, overrides the default org from config
+                --per-workspace     Report each monorepo workspace separately, with its own pass/fail result. Implied by --workspace-filter and --changed-since.
                 --pull-request      Pull request number
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
                 --reach             Run tier 1 full application reachability analysis
-                --read-only         Similar to --dry-run except it can read from remote, stops before it would create an actual report
+                --read-only         Similar to --dry-run except it can read from remote, lists the files it would upload and stops before it would create an actual report
                 --repo              Repository name
+                --repo-concurrency  With --all-repos, the number of repositories to scan at once
                 --report            Wait for the scan creation to complete, then basically run \`socket scan report\` on it
                 --report-level      Which policy level alerts should be reported (default 'error')
                 --set-as-alerts-page  When true and if this is the "default branch" then this Scan will be the one reflected on your alerts page. See help for details. Defaults to true.
                 --tmp               Set the visibility (true/false) of the scan in your dashboard.
+                --wait              Wait for the analysis of the scan to finish and fail when it does not within --wait-timeout. Defaults to --no-wait, which returns once the scan is created
+                --wait-timeout      How long --wait waits for the analysis, e.g. "90s", "30m" or "1h" (default 10m)
+                --webhook-url       Incoming webhook URL for --notify, defaults to the SOCKET_CLI_NOTIFY_WEBHOOK_URL environment variable
                 --workspace         The workspace in the Socket Organization that the repository is in to associate with the full scan.
+                --workspace-filter  Only include the monorepo workspaces whose package name or path matches, e.g. \`@acme/api\`, \`@acme/*\` or \`packages/*\`. Accepts a comma-separated value or multiple flags.
           
               Reachability Options (when --reach is used)
                 --reach-analysis-memory-limit  The maximum memory in MB to use for the reachability analysis. The default is 8192MB.
@@ -101,12 +117,69 @@ describe('socket scan create', async () => {
               this by using --no-set-as-alerts-page. This flag is ignored for any branch that
               is not designated as the "default branch". It is disabled when using --tmp.
           
+              With --report, the command exits with code 3 when the scan violates the
+              policy, like \`socket scan report\`. Pass --fail-on to fail on alert
+              severity instead of on the "error" policy level, e.g. --fail-on=critical.
+              Socket API errors exit with 4, auth errors with 5.
+          
+              With --reach, the --report output also tags each alert with whether the
+              project's JavaScript and TypeScript sources import its package, directly
+              or through other packages. Pass --only-reachable to leave out the alerts
+              of packages they cannot reach.
+          
+              In a monorepo, --workspace-filter and --changed-since scan only some of
+              the npm, yarn, pnpm or bun workspaces under the current dir instead of
+              the TARGETs. With --report, each workspace then passes or fails on its
+              own. Pass --per-workspace to report every workspace separately.
+          
+              With --from-sbom, the scan is made of the components of an existing
+              CycloneDX or SPDX JSON document, e.g. one produced by a container image
+              build, instead of the manifest files of the TARGETs. Components are
+              identified by their purl; those without one are skipped.
+          
+              With --notify slack or --notify teams, a summary of the new scan is posted
+              to the chat incoming webhook of --webhook-url, or of the
+              SOCKET_CLI_NOTIFY_WEBHOOK_URL environment variable: the new critical
+              alerts and the alert diff compared with the latest scan of the repo's
+              default branch, and a link to the report. A failed notification only
+              warns, the scan is still created.
+          
+              By default the command returns as soon as the scan is created, while
+              Socket analyses it in the background; \`socket scan await <ID>\` waits
+              for that analysis later. With --wait it waits for the analysis itself,
+              and fails when it is not done within --wait-timeout, so CI can gate on
+              it. --callback-url is then POSTed a JSON summary of the analysis: the
+              scan ID, its package count and its alert count of each severity.
+          
+              The manifest files are uploaded in a single request. A failed upload is
+              retried as a whole, see --max-retries and --timeout. The Socket API has
+              no chunked or resumable upload yet, so each retry of a large upload
+              starts over.
+          
+              With --all-repos, every repository of the GitHub organization of
+              --github-org or the GitLab group of --gitlab-group is shallow-cloned at
+              its default branch and scanned, or every git clone directly inside the
+              TARGET dir. Up to --repo-concurrency repositories are scanned at once.
+              The report ranks the repositories by the new critical alerts compared
+              with the previous scan of their default branch. Archived and empty
+              repositories are skipped. The command exits with code 1 when any
+              repository failed to scan.
+
               You can use \`socket scan setup\` to configure certain repo flag defaults.
           
               Examples
                 $ socket scan create
                 $ socket scan create ./proj --json
-                $ socket scan create --repo=test-repo --branch=main ./package.json"
+                $ socket scan create --repo=test-repo --branch=main ./package.json
+                $ socket scan create --report --format=sarif > socket.sarif
+                $ socket scan create --report --fail-on=high .
+                $ socket scan create --reach --report --only-reachable .
+                $ socket scan create --report --changed-since=origin/main
+                $ socket scan create --from-sbom=sbom.cdx.json --repo=my-image --report
+                $ socket scan create --notify=slack --webhook-url=https://hooks.slack.com/services/...
+                $ socket scan create --wait --wait-timeout=20m --callback-url=https://ci.example.com/hook
+                $ socket scan create --all-repos --github-org=acme --repo-concurrency=8
+                $ socket scan create --all-repos ./clones --markdown"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
@@ -158,6 +231,9 @@ describe('socket scan create', async () => {
             repository: "xyz"
             branch: "abc"
 
+          API request: POST orgs/fakeOrg/full-scans?branch=abc&make_default_branch=false&repo=xyz&scan_type=socket&set_as_pending_head=true&tmp=false
+          Request body: the supported manifest files of the targets, listed by --read-only
+
           Run without --dry-run to perform this upload."
       `)
 
@@ -39,28 +39,46 @@ describe('socket scan diff', async () => {
           
               API Token Requirements
                 - Quota: 1 unit
-                - Permissions: full-scans:list
+                - Permissions: full-scans:list and security-policy:read
           
               This command displays the package changes between two scans. The full output
               can be pretty large depending on the size of your repo and time range. It is
               best stored to disk (with --json) to be further analyzed by other tools.
           
+              New and resolved alerts are listed alongside the package changes. The
+              command exits with code 3 only when newly introduced alerts have an
+              "error" action in the organization security policy, which makes it
+              suitable as a pull request gate. Alerts are matched by package and alert
+              type, so upgrading a package that keeps an existing alert does not fail.
+          
+              Use --severity, --alert-type, --ecosystem and --direct-only to only show
+              some of the packages and alerts. Only the alerts they include are compared,
+              so they also narrow down what fails the command.
+          
               Note: While it will work in any order, the first Scan ID is assumed to be the
                     older ID, even if it is a newer Scan. This is only relevant for the
                     added/removed list (similar to diffing two files with git).
           
               Options
+                --alert-category    Only include alerts of these categories, e.g. \`secrets\` for credentials embedded in packages or \`supplyChainRisk\`. Accepts a comma-separated value or multiple flags.
+                --alerts            Compare alerts of both scans against the security policy and exit with code 3 when new alerts violate it. Use --no-alerts to only show package changes.
+                --alert-type        Only include alerts of these types, e.g. \`malware\` or \`criticalCVE\`. Accepts a comma-separated value or multiple flags.
                 --depth             Max depth of JSON to display before truncating, use zero for no limit (without --json/--file)
+                --direct-only       Only include direct dependencies
+                --ecosystem         Only include packages of these ecosystems, e.g. \`npm\` or \`pypi\`. Accepts a comma-separated value or multiple flags.
                 --file              Path to a local file where the output should be saved. Use \`-\` to force stdout.
                 --interactive       Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.
                 --json              Output as JSON
                 --markdown          Output as Markdown
                 --org               Force override the organization slug, overrides the default org from config
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
+                --severity          Only include alerts of at least this severity: 'low', 'middle', 'high', 'critical'
           
               Examples
                 $ socket scan diff [UUID] [UUID]
-                $ socket scan diff [UUID] [UUID] --json"
+                $ socket scan diff [UUID] [UUID] --json
+                $ socket scan diff [UUID] [UUID] --no-alerts
+                $ socket scan diff [UUID] [UUID] --severity=high --ecosystem=npm"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
@@ -138,6 +156,7 @@ describe('socket scan diff', async () => {
             scanId1: x
             scanId2: y
             depth: 2
+            alerts: true
 
           This is a read-only operation that does not modify any data.
           Run without --dry-run to fetch and display the data."
@@ -43,17 +43,35 @@ describe('socket scan report', async () => {
                 - Permissions: full-scans:list and security-policy:read
           
               Options
+                --alert-category    Only include alerts of these categories, e.g. \`secrets\` for credentials embedded in packages or \`supplyChainRisk\`. Accepts a comma-separated value or multiple flags.
+                --alert-type        Only include alerts of these types, e.g. \`malware\` or \`criticalCVE\`. Accepts a comma-separated value or multiple flags.
+                --baseline          Baseline file of known alerts to leave out of the report (default: the nearest socket.baseline.json)
+                --changed-since     Only include the monorepo workspaces with files changed since this git ref, e.g. \`origin/main\`. A changed root lockfile selects every workspace.
+                --columns           Comma-separated columns of the CSV output, in order. See the help text for the available columns
+                --csv-header        Start the CSV output with a header row. Use --no-csv-header to leave it out.
+                --direct-only       Only include direct dependencies
+                --ecosystem         Only include packages of these ecosystems, e.g. \`npm\` or \`pypi\`. Accepts a comma-separated value or multiple flags.
+                --fail-on           Fail on alerts of at least this severity instead of on the policy error level: 'critical', 'high', 'any', 'none'. 'any' fails on every alert the policy does not ignore, 'none' never fails
                 --fold              Fold reported alerts to some degree (default 'none')
+                --format            Render the report in an alternative format instead of the default json/markdown/text. Supported: 'sarif', 'gitlab-code-quality', 'gitlab-sast', 'junit', 'html', 'csv'
                 --interactive       Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.
                 --json              Output as JSON
                 --license           Also report the license policy status. Default: false
                 --markdown          Output as Markdown
+                --offline           Read the scan and security policy from an imported offline bundle without calling the Socket API
                 --org               Force override the organization slug, overrides the default org from config
+                --output            Write the report to this file instead of stdout, like the OUTPUT_PATH argument
+                --output-template   Render the result with a Handlebars-style template file instead of the default output
+                --per-workspace     Report each monorepo workspace separately, with its own pass/fail result. Implied by --workspace-filter and --changed-since.
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
                 --report-level      Which policy level alerts should be reported (default 'warn')
+                --severity          Only include alerts of at least this severity: 'low', 'middle', 'high', 'critical'
                 --short             Report only the healthy status
+                --show-evidence     List the file and a redacted snippet of each secret alert, for credentials embedded in dependencies
+                --vex               OpenVEX, CSAF or CycloneDX VEX document whose not_affected and fixed statements leave out matching vulnerability alerts. Accepts multiple flags
+                --workspace-filter  Only include the monorepo workspaces whose package name or path matches, e.g. \`@acme/api\`, \`@acme/*\` or \`packages/*\`. Accepts a comma-separated value or multiple flags.
           
-              When no output path is given the contents is sent to stdout.
+              When no output path (or --output) is given the contents is sent to stdout.
           
               By default the result is a nested object that looks like this:
                 \`{
@@ -73,6 +91,77 @@ describe('socket scan report', async () => {
               By default only the warn and error policy level alerts are reported. You can
               override this and request more ('defer' < 'ignore' < 'monitor' < 'warn' < 'error')
           
+              Use --format=sarif to emit a SARIF 2.1.0 log that can be uploaded to
+              GitHub Code Scanning. Results point at the manifest file (and line, when the
+              manifest exists in the current dir) that introduced the offending package.
+              The --fold and --short flags do not apply to SARIF output.
+          
+              Use --format=gitlab-code-quality or --format=gitlab-sast to emit the same
+              results as a GitLab Code Quality or SAST report. Declare the file under
+              \`artifacts:reports:codequality\` or \`artifacts:reports:sast\` in
+              .gitlab-ci.yml and the alerts show up in the merge request.
+          
+              Use --format=junit to emit JUnit XML for CI test reporters, with one
+              test case per alert. Alerts that make the report unhealthy are failures;
+              the others pass and carry their details as output.
+          
+              Use --format=html to emit a self-contained HTML page with sortable alert and
+              package tables, a score breakdown and a dependency graph. Selecting an
+              alert highlights the paths from direct dependencies that introduce the
+              alerted package.
+          
+              Use --format=csv to emit one row per alert for spreadsheets. Pick the
+              columns, in order, with --columns: package, version, ecosystem, purl,
+              severity, alertType, policy, directDep, filePath and url. The default is
+              package,version,severity,alertType,directDep,filePath. Use
+              --no-csv-header to leave out the header row.
+          
+              Use --output-template=<file> to render the report with your own template,
+              e.g. as Slack blocks, Confluence markup or a ticket body. Templates use a
+              Handlebars-style syntax: \`{{orgSlug}}\`, \`{{#each alerts}}...{{/each}}\`,
+              \`{{#if healthy}}...{{else}}...{{/if}}\` and the helpers \`json\`, \`upper\`,
+              \`lower\`, \`length\`, \`join\`, \`default\` and \`eq\`. The context has
+              \`orgSlug\`, \`scanId\`, \`healthy\`, \`reportLevel\`, \`counts\` (per policy
+              level and \`total\`) and \`alerts\`, each with \`type\`, \`title\`,
+              \`description\`, \`fix\`, \`message\`, \`level\`, \`policy\`, \`severity\`,
+              \`purl\`, \`manifest\`, \`line\` and \`url\`.
+          
+              The report is unhealthy when an alert has the "error" policy level. Use
+              --fail-on to fail on alert severity instead, e.g. --fail-on=critical to
+              only fail on critical alerts or --fail-on=none to never fail. It decides
+              the exit code for every output format.
+          
+              Exit codes: 0 when the report is healthy, 3 when it violates the policy
+              or --fail-on, 4 on Socket API errors, 5 on auth errors and 2 on invalid
+              usage. Other failures exit with 1.
+          
+              Alerts recorded in a baseline file (see \`socket scan baseline write\`)
+              are left out, so only new alerts make the report unhealthy.
+          
+              With --vex, vulnerability alerts that a VEX document marks as
+              not_affected or fixed are left out as well, matched by CVE or GHSA id
+              and package purl. See \`socket sbom vex\` to export your own triage.
+          
+              On an air-gapped machine, import a bundle made with \`socket bundle export\`
+              and report with --offline (or SOCKET_CLI_OFFLINE=1). The scan and the org
+              security policy are then read from the bundle and no API token is needed.
+          
+              Use --severity, --alert-type, --ecosystem and --direct-only to only
+              report some of the alerts, e.g. --severity=high --ecosystem=npm. Alerts
+              they leave out do not make the report unhealthy either.
+          
+              Credentials and keys embedded in the files of dependencies are secret
+              alerts, in a category of their own: --alert-category=secrets only reports
+              them. With --show-evidence each lists the file it was found in and the
+              line, with the secret redacted. The line is read from the package
+              installed under the current dir when its version matches the scan.
+          
+              In a monorepo, --per-workspace reports each npm, yarn, pnpm or bun
+              workspace under the current dir on its own, attributing packages by the
+              manifest files that pull them in. Every workspace passes or fails
+              independently and the command exits with code 3 when any fails. Use
+              --workspace-filter and --changed-since to only report some of them.
+          
               Short responses look like this:
                 --json:     \`{healthy:bool}\`
                 --markdown: \`healthy = bool\`
@@ -80,7 +169,18 @@ describe('socket scan report', async () => {
           
               Examples
                 $ socket scan report [UUID] --json --fold=version
-                $ socket scan report [UUID] --license --markdown --short"
+                $ socket scan report [UUID] --license --markdown --short
+                $ socket scan report [UUID] --fail-on=high
+                $ socket scan report [UUID] --format=sarif socket.sarif
+                $ socket scan report [UUID] --format=gitlab-code-quality gl-code-quality-report.json
+                $ socket scan report [UUID] --format=junit socket-junit.xml
+                $ socket scan report [UUID] --format=html --output report.html
+                $ socket scan report [UUID] --format=csv --columns=package,version,severity alerts.csv
+                $ socket scan report [UUID] --output-template=slack.hbs
+                $ socket scan report [UUID] --vex vendor.openvex.json
+                $ socket scan report [UUID] --changed-since=origin/main
+                $ socket scan report [UUID] --alert-type=malware,criticalCVE --direct-only
+                $ socket scan report [UUID] --alert-category=secrets --show-evidence --markdown"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
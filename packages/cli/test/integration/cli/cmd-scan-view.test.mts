@@ -44,17 +44,34 @@ describe('socket scan view', async () => {
           
               When no output path is given the contents is sent to stdout.
           
+              With --interactive in a terminal the alerts are listed grouped by package
+              or severity. Expand one to see the dependency path that brings it in, and
+              press "i" to ignore or "a" to accept it with a reason. Decisions are added
+              as exceptions to the nearest socket.policy.yml, or saved to the org
+              triage settings with --triage-target=org.
+          
+              Use --severity, --alert-type, --ecosystem and --direct-only to only show
+              some of the packages and alerts, e.g. --severity=high --ecosystem=npm.
+          
               Options
-                --interactive       Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.
+                --alert-category    Only include alerts of these categories, e.g. \`secrets\` for credentials embedded in packages or \`supplyChainRisk\`. Accepts a comma-separated value or multiple flags.
+                --alert-type        Only include alerts of these types, e.g. \`malware\` or \`criticalCVE\`. Accepts a comma-separated value or multiple flags.
+                --direct-only       Only include direct dependencies
+                --ecosystem         Only include packages of these ecosystems, e.g. \`npm\` or \`pypi\`. Accepts a comma-separated value or multiple flags.
+                --interactive       Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no. Passed explicitly, opens a terminal UI to triage the alerts.
                 --json              Output as JSON
                 --markdown          Output as Markdown
                 --org               Force override the organization slug, overrides the default org from config
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
+                --severity          Only include alerts of at least this severity: 'low', 'middle', 'high', 'critical'
                 --stream            Only valid with --json. Streams the response as "ndjson" (chunks of valid json blobs).
+                --triage-target     Where --interactive saves ignored and accepted alerts: "local" (socket.policy.yml exceptions) or "org" (the org triage settings)
           
               Examples
                 $ socket scan view [UUID]
-                $ socket scan view [UUID] ./stream.txt"
+                $ socket scan view [UUID] ./stream.txt
+                $ socket scan view [UUID] --interactive
+                $ socket scan view [UUID] --severity=critical --direct-only"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
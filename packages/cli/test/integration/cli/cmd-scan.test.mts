@@ -22,6 +22,7 @@
  * - Report: Generate scan reports
  * - Setup: Setup scan configuration
  * - View: View scan details
+ * - Watch: Rescan manifests on change
  *
  * Related Files:
  *
@@ -55,6 +56,10 @@ describe('socket scan', async () => {
               $ socket scan <command>
           
             Commands
+              attest                      Sign an attestation of the policy result of a scan
+              await                       Wait for the analysis of a scan to finish
+              baseline                    Manage the baseline of known alerts that reports leave out
+              check                       Check a scan result against a Rego policy
               create                      Create a new Socket scan and report
               del                         Delete a scan
               diff                        See what changed between two Scans
@@ -63,6 +68,7 @@ describe('socket scan', async () => {
               report                      Check whether a scan result passes the organizational policies (security, license)
               setup                       Start interactive configurator to customize default flag values for \`socket scan\` in this dir
               view                        View the raw results of a scan
+              watch                       Rescan manifests on change and show new alerts as they appear
           
             Options
           
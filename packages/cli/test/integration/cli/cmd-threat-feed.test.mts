@@ -50,11 +50,15 @@ describe('socket threat-feed', async () => {
           
               Options
                 --direction         Order asc or desc by the createdAt attribute
-                --eco               Only show threats for a particular ecosystem
+                --eco               Only show threats for these ecosystems, a comma-separated list
                 --filter            Filter what type of threats to return
+                --follow            Keep polling and print new threats as they are published, until interrupted
                 --interactive       Allow for interactive elements, asking for input. Use --no-interactive to prevent any input questions, defaulting them to cancel/no.
+                --interval          Seconds between polls with --follow, at least 10
                 --json              Output as JSON
+                --jsonl             Output one JSON object per threat per line, for piping into other tools
                 --markdown          Output as Markdown
+                --namespace         Only show threats in this namespace, e.g. @acme or org.example (repeatable)
                 --org               Force override the organization slug, overrides the default org from config
                 --page              Page token
                 --per-page          Number of items per page
@@ -99,11 +103,18 @@ describe('socket threat-feed', async () => {
           
               Note: The version filter is a prefix search, pkg name is a substring search.
           
+              To watch for new threats to the ecosystems and namespaces you use, add
+              --follow. It prints the latest page, then polls every --interval seconds
+              and prints threats as they are published, oldest first, until interrupted.
+              With --jsonl every threat is one JSON object per line, ready to pipe into
+              a SIEM or chat alerting. --page and --direction do not apply to --follow.
+          
               Examples
                 $ socket threat-feed
                 $ socket threat-feed maven --json
                 $ socket threat-feed typo
-                $ socket threat-feed npm joke 1.0.0 --per-page=5 --page=2 --direction=asc"
+                $ socket threat-feed npm joke 1.0.0 --per-page=5 --page=2 --direction=asc
+                $ socket threat-feed --eco npm,pypi --namespace @acme --follow --jsonl"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
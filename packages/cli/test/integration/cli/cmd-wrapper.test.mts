@@ -4,12 +4,12 @@
  * Tests enabling/disabling Socket npm/npx wrappers globally.
  *
  * Test Coverage: - Help text display and usage examples - Dry-run behavior
- * validation - Wrapper activation (on) - Wrapper deactivation (off) - Status
- * checking.
+ * validation - Wrapper activation (enable) - Wrapper deactivation (disable) -
+ * Status checking.
  *
- * Wrapper Feature: When enabled, aliases `npm` and `npx` commands to `socket
- * npm` and `socket npx` in the shell, applying security scanning to all package
- * operations.
+ * Wrapper Feature: When enabled, PATH shims run `npm`, `npx`, `pnpm` and `yarn`
+ * as `socket npm`, `socket npx` and so on, applying security scanning to all
+ * package operations.
  *
  * Related Files: - src/commands/wrapper/cmd-wrapper.mts - Command definition -
  * src/commands/wrapper/handle-wrapper.mts - Wrapper management logic.
@@ -40,7 +40,13 @@ const fixtureHome = path.join(
   import.meta.dirname,
   '../../fixtures/commands/wrapper/rc-home',
 )
-const fixtureHomeEnv = { HOME: fixtureHome, USERPROFILE: fixtureHome }
+// The user shims live in the app data directory, pin it inside the fixture
+// too.
+const fixtureHomeEnv = {
+  HOME: fixtureHome,
+  USERPROFILE: fixtureHome,
+  XDG_DATA_HOME: path.join(fixtureHome, '.local', 'share'),
+}
 
 describe('socket wrapper', async () => {
   cmdit(
@@ -52,18 +58,38 @@ describe('socket wrapper', async () => {
         "Enable or disable the Socket npm/pnpm exec wrapper
 
           Usage
-                $ socket wrapper <"on" | "off">
+                $ socket wrapper <"enable" | "disable" | "status"> [package-manager ...]
           
               Options
+                --json              Output as JSON
+                --markdown          Output as Markdown
+                --project           Put the shims in .socket/shims of the current directory instead of the user shim directory
                 --quiet             Route non-essential output (status, progress, warnings) to stderr so stdout carries only the payload. Implied by --json and --markdown.
           
               While enabled, the wrapper makes it so that when you call npm/npx on your
               machine, it will automatically actually run \`socket npm\` / \`socket npx\`
               instead.
           
+              The wrapper is a set of shims on PATH, one per package manager
+              (npm, npx, pnpm, yarn). Without a package manager, enable wraps
+              npm and npx and disable unwraps everything. "on" and "off" still
+              work as enable and disable.
+          
+              User shims go in a Socket directory that ~/.bashrc and ~/.zshrc put on
+              PATH. With --project they go in .socket/shims of the current directory,
+              for a tool like direnv to put on PATH. Shims are outside every package
+              manager install and find the real binary when they run, so package
+              manager upgrades do not break or remove them.
+          
+              Status shows what each scope wraps, whether the shell reaches the shims
+              and the binary each shim ends up running.
+          
               Examples
-                $ socket wrapper on
-                $ socket wrapper off"
+                $ socket wrapper enable
+                $ socket wrapper enable pnpm yarn
+                $ socket wrapper enable --project npm
+                $ socket wrapper disable
+                $ socket wrapper status --json"
       `)
       expect(`\n   ${stderr}`).toMatchInlineSnapshot(`
         "
@@ -95,7 +121,7 @@ describe('socket wrapper', async () => {
 
         \\xd7  Input error:  Please review the input requirements and try again
 
-          \\xd7 Must specify "on" or "off" argument (missing)"
+          \\xd7 Must specify "enable", "disable" or "status" argument (missing)"
       `)
 
       expect(code, 'dry-run should exit with code 2 if missing input').toBe(2)
@@ -123,10 +149,53 @@ describe('socket wrapper', async () => {
 
         [DryRun]: Would enable Socket npm/pnpm exec wrapper
 
-          Target file: [PROJECT]/packages/cli/test/fixtures/commands/wrapper/rc-home/.bashrc, [PROJECT]/packages/cli/test/fixtures/commands/wrapper/rc-home/.zshrc
-          Changes:
-            - Add shell aliases/functions to wrap npm/pnpm exec commands
-            - Redirect npm/pnpm exec calls to socket npm/socket npx
+          --- /dev/null
+          +++ b/~/.local/share/socket/shims/npm
+          @@ -0,0 +1,14 @@
+          +#!/bin/sh
+          +# Managed by \`socket wrapper\`, do not edit.
+          +shim_dir='[PROJECT]/packages/cli/test/fixtures/commands/wrapper/rc-home/.local/share/socket/shims'
+          +path=
+          +set -f
+          +IFS=:
+          +for dir in $PATH; do
+          +  [ "$dir" = "$shim_dir" ] || path="\${path:+$path:}$dir"
+          +done
+          +unset IFS
+          +set +f
+          +PATH=$path
+          +export PATH
+          +exec socket npm "$@"
+
+          --- /dev/null
+          +++ b/~/.local/share/socket/shims/npx
+          @@ -0,0 +1,14 @@
+          +#!/bin/sh
+          +# Managed by \`socket wrapper\`, do not edit.
+          +shim_dir='[PROJECT]/packages/cli/test/fixtures/commands/wrapper/rc-home/.local/share/socket/shims'
+          +path=
+          +set -f
+          +IFS=:
+          +for dir in $PATH; do
+          +  [ "$dir" = "$shim_dir" ] || path="\${path:+$path:}$dir"
+          +done
+          +unset IFS
+          +set +f
+          +PATH=$path
+          +export PATH
+          +exec socket npx "$@"
+
+          --- a/~/.bashrc
+          +++ b/~/.bashrc
+          @@ -1 +1,2 @@
+           # wrapper test fixture bashrc
+          +export PATH="[PROJECT]/packages/cli/test/fixtures/commands/wrapper/rc-home/.local/share/socket/shims:$PATH" # Managed by \`socket wrapper\`, do not edit.
+
+          --- a/~/.zshrc
+          +++ b/~/.zshrc
+          @@ -1 +1,2 @@
+           # wrapper test fixture zshrc
+          +export PATH="[PROJECT]/packages/cli/test/fixtures/commands/wrapper/rc-home/.local/share/socket/shims:$PATH" # Managed by \`socket wrapper\`, do not edit.
 
           Run without --dry-run to apply these changes."
       `)
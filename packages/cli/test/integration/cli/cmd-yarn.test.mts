@@ -51,6 +51,12 @@ describe('socket yarn', async () => {
           
               Note: Everything after "yarn" is forwarded to Socket Firewall (sfw).
                     Socket Firewall provides real-time security scanning for yarn packages.
+                    Packages named by \`yarn add\`, and the direct dependencies locked for a bare
+                    \`yarn install\`, are checked with Socket first. Alerts the org policy blocks
+                    stop the install and alerts it warns about ask before installing.
+          
+              With --non-interactive, or without a TTY, prompts take the answer of
+              \`wrapper.nonInteractive\` in socket.yml (allow or block, allow by default).
           
               Use \`socket wrapper on\` to alias this command as \`yarn\`.
           
@@ -0,0 +1,274 @@
+/**
+ * Unit tests for `socket audit-installed`.
+ *
+ * Tests comparing installed packages with their registry tarballs and the
+ * npm lockfile, with the npm registry mocked: tampered files, versions and
+ * integrities that differ from the lockfile, packages missing from the
+ * lockfile, extraneous packages and packages that cannot be verified.
+ */
+
+import crypto from 'node:crypto'
+import { gzipSync } from 'node:zlib'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  auditInstalledPackages,
+  integritiesConflict,
+} from '../../../../src/commands/audit-installed/audit-installed.mts'
+import { matchesIntegrity } from '../../../../src/commands/audit-installed/package-contents.mts'
+import { makeTarArchive } from '../../../helpers/tar-archive.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  socketHttpRequest: mockSocketHttpRequest,
+}))
+
+type Published = {
+  files: Record<string, string>
+  name: string
+  version: string
+}
+
+function pkgJson(name: string, version: string) {
+  return JSON.stringify({ name, version })
+}
+
+function pack({ files, name, version }: Published) {
+  const tgz = gzipSync(
+    makeTarArchive(
+      Object.entries({
+        'package.json': pkgJson(name, version),
+        ...files,
+      }).map(({ 0: file, 1: content }) => ({
+        name: `package/${file}`,
+        content,
+      })),
+    ),
+  )
+  return {
+    integrity: `sha512-${crypto.createHash('sha512').update(tgz).digest('base64')}`,
+    tarball: `https://registry.npmjs.org/${name}/-/${name}-${version}.tgz`,
+    tgz,
+  }
+}
+
+const PUBLISHED: Published[] = [
+  { name: 'left-pad', version: '1.3.0', files: { 'index.js': 'pad()' } },
+  { name: 'lodash', version: '4.17.21', files: { 'lodash.js': '_' } },
+  { name: 'stray', version: '1.0.0', files: { 'index.js': 'stray' } },
+]
+
+function mockRegistry() {
+  const responses = new Map<string, object>()
+  for (const published of PUBLISHED) {
+    const { integrity, tarball, tgz } = pack(published)
+    const manifest = {
+      name: published.name,
+      version: published.version,
+      dist: { integrity, tarball },
+    }
+    responses.set(
+      `https://registry.npmjs.org/${published.name}/${published.version}`,
+      { ok: true, status: 200, text: () => JSON.stringify(manifest) },
+    )
+    responses.set(tarball, { ok: true, status: 200, body: tgz })
+  }
+  mockSocketHttpRequest.mockImplementation(
+    async (url: string) =>
+      responses.get(url) ?? { ok: false, status: 404, statusText: 'Not Found' },
+  )
+}
+
+function lockEntry(name: string, version: string) {
+  const published = PUBLISHED.find(p => p.name === name)!
+  const { integrity, tarball } = pack(published)
+  return { version, resolved: tarball, integrity }
+}
+
+describe('matchesIntegrity', () => {
+  it('checks the strongest algorithm listed', () => {
+    const data = Buffer.from('data')
+    const sha512 = crypto.createHash('sha512').update(data).digest('base64')
+    const sha1 = crypto.createHash('sha1').update(data).digest('base64')
+
+    expect(matchesIntegrity(data, `sha1-${sha1} sha512-${sha512}`)).toBe(true)
+    expect(matchesIntegrity(data, `sha1-${sha1} sha512-AAAA`)).toBe(false)
+    expect(matchesIntegrity(data, 'md5-AAAA')).toBe(false)
+  })
+})
+
+describe('integritiesConflict', () => {
+  it('only compares the algorithms both list', () => {
+    expect(integritiesConflict('sha512-a', 'sha512-b')).toBe(true)
+    expect(integritiesConflict('sha1-a', 'sha512-b')).toBe(false)
+    expect(integritiesConflict('sha1-a sha512-b', 'sha512-b')).toBe(false)
+  })
+})
+
+describe('auditInstalledPackages', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockRegistry()
+  })
+
+  it('reports tampered, extraneous and unlocked packages', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'package.json',
+          content: JSON.stringify({
+            name: 'app',
+            dependencies: { 'left-pad': '^1.3.0', lodash: '^4.17.21' },
+          }),
+        },
+        {
+          path: 'package-lock.json',
+          content: JSON.stringify({
+            lockfileVersion: 3,
+            packages: {
+              '': { name: 'app' },
+              'node_modules/left-pad': lockEntry('left-pad', '1.3.0'),
+              'node_modules/lodash': lockEntry('lodash', '4.17.21'),
+            },
+          }),
+        },
+        {
+          path: 'node_modules/left-pad/package.json',
+          content: pkgJson('left-pad', '1.3.0'),
+        },
+        {
+          path: 'node_modules/left-pad/index.js',
+          content: 'pad(); steal()',
+        },
+        {
+          path: 'node_modules/lodash/package.json',
+          // Installers may add `_` fields.
+          content: JSON.stringify({
+            name: 'lodash',
+            version: '4.17.21',
+            _resolved: 'x',
+          }),
+        },
+        { path: 'node_modules/lodash/lodash.js', content: '_' },
+        // Build output of install scripts is not compared.
+        { path: 'node_modules/lodash/build/out.node', content: 'bin' },
+        {
+          path: 'node_modules/stray/package.json',
+          content: pkgJson('stray', '1.0.0'),
+        },
+        { path: 'node_modules/stray/index.js', content: 'stray' },
+      ],
+    })
+    try {
+      const result = await auditInstalledPackages(workspace.path)
+
+      expect(result).toEqual({
+        ok: true,
+        data: {
+          lockfile: 'package-lock.json',
+          packages: 3,
+          verified: 2,
+          unverified: [],
+          issues: [
+            {
+              kind: 'tampered',
+              location: 'node_modules/left-pad',
+              name: 'left-pad',
+              version: '1.3.0',
+              message: '1 modified file compared to the registry tarball',
+              files: ['index.js'],
+            },
+            {
+              kind: 'extraneous',
+              location: 'node_modules/stray',
+              name: 'stray',
+              version: '1.0.0',
+              message: 'Not a dependency of the project or of its dependencies',
+            },
+            {
+              kind: 'missing-from-lockfile',
+              location: 'node_modules/stray',
+              name: 'stray',
+              version: '1.0.0',
+              message: 'Not in package-lock.json',
+            },
+          ],
+        },
+      })
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('reports lockfile mismatches and unverified packages', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'package.json',
+          content: JSON.stringify({
+            dependencies: { 'left-pad': '^1.3.0', linked: 'file:../linked' },
+          }),
+        },
+        {
+          path: 'package-lock.json',
+          content: JSON.stringify({
+            lockfileVersion: 3,
+            packages: {
+              'node_modules/left-pad': {
+                ...lockEntry('left-pad', '1.2.0'),
+                integrity: 'sha512-AAAA',
+              },
+              'node_modules/linked': {
+                version: '1.0.0',
+                resolved: 'git+ssh://git@github.com/acme/linked.git#abc',
+              },
+            },
+          }),
+        },
+        {
+          path: 'node_modules/left-pad/package.json',
+          content: pkgJson('left-pad', '1.3.0'),
+        },
+        { path: 'node_modules/left-pad/index.js', content: 'pad()' },
+        {
+          path: 'node_modules/linked/package.json',
+          content: pkgJson('linked', '1.0.0'),
+        },
+      ],
+    })
+    try {
+      const result = await auditInstalledPackages(workspace.path)
+
+      expect(result.ok && result.data.verified).toBe(1)
+      expect(result.ok && result.data.unverified).toEqual([
+        {
+          location: 'node_modules/linked',
+          name: 'linked',
+          version: '1.0.0',
+          reason: 'installed from git+ssh://git@github.com/acme/linked.git#abc',
+        },
+      ])
+      expect(result.ok && result.data.issues.map(i => i.message)).toEqual([
+        'Installed 1.3.0, package-lock.json has 1.2.0',
+        'The integrity in package-lock.json differs from the npm registry',
+      ])
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('fails without installed packages', async () => {
+    const workspace = await createTestWorkspace({ files: [] })
+    try {
+      expect(await auditInstalledPackages(workspace.path)).toMatchObject({
+        ok: false,
+        message: 'No installed packages',
+      })
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+})
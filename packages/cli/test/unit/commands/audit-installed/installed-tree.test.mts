@@ -0,0 +1,162 @@
+/**
+ * Unit tests for the installed node_modules tree of
+ * `socket audit-installed`.
+ *
+ * Tests walking nested and scoped packages, reading npm lockfiles, Node.js
+ * style resolution of installed locations and finding extraneous packages.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  findExtraneousPackages,
+  listInstalledPackages,
+  readNpmLockfile,
+  resolveInstalledLocation,
+} from '../../../../src/commands/audit-installed/installed-tree.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+function pkgJson(
+  name: string,
+  version: string,
+  dependencies?: Record<string, string> | undefined,
+) {
+  return JSON.stringify({ name, version, dependencies })
+}
+
+function getFiles() {
+  return [
+    {
+      path: 'package.json',
+      content: JSON.stringify({
+        name: 'app',
+        dependencies: { express: '^4.0.0' },
+        devDependencies: { '@types/node': '^20.0.0' },
+      }),
+    },
+    {
+      path: 'node_modules/express/package.json',
+      content: pkgJson('express', '4.18.2', { debug: '2.6.9' }),
+    },
+    {
+      path: 'node_modules/express/node_modules/debug/package.json',
+      content: pkgJson('debug', '2.6.9', { ms: '2.0.0' }),
+    },
+    { path: 'node_modules/ms/package.json', content: pkgJson('ms', '2.0.0') },
+    {
+      path: 'node_modules/debug/package.json',
+      content: pkgJson('debug', '4.3.4'),
+    },
+    {
+      path: 'node_modules/@types/node/package.json',
+      content: pkgJson('@types/node', '20.11.0'),
+    },
+    { path: 'node_modules/.bin/README', content: '' },
+  ]
+}
+
+describe('listInstalledPackages', () => {
+  it('lists nested and scoped packages by location', async () => {
+    const workspace = await createTestWorkspace({ files: getFiles() })
+    try {
+      const installed = await listInstalledPackages(workspace.path)
+
+      expect(installed.map(p => `${p.location}@${p.version}`)).toEqual([
+        'node_modules/@types/node@20.11.0',
+        'node_modules/debug@4.3.4',
+        'node_modules/express@4.18.2',
+        'node_modules/express/node_modules/debug@2.6.9',
+        'node_modules/ms@2.0.0',
+      ])
+      expect(installed[3]!.dependencies).toEqual(['ms'])
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+})
+
+describe('readNpmLockfile', () => {
+  it('prefers npm-shrinkwrap.json', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'npm-shrinkwrap.json',
+          content: JSON.stringify({ lockfileVersion: 3, packages: {} }),
+        },
+        {
+          path: 'package-lock.json',
+          content: JSON.stringify({ lockfileVersion: 3, packages: {} }),
+        },
+      ],
+    })
+    try {
+      expect(await readNpmLockfile(workspace.path)).toEqual({
+        ok: true,
+        data: { file: 'npm-shrinkwrap.json', packages: {} },
+      })
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('fails for lockfileVersion 1 and passes without a lockfile', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'package-lock.json',
+          content: JSON.stringify({ lockfileVersion: 1, dependencies: {} }),
+        },
+      ],
+    })
+    try {
+      expect(await readNpmLockfile(workspace.path)).toMatchObject({
+        ok: false,
+        message: 'Unsupported lockfile',
+      })
+    } finally {
+      await workspace.cleanup()
+    }
+    const empty = await createTestWorkspace({ files: [] })
+    try {
+      expect(await readNpmLockfile(empty.path)).toEqual({
+        ok: true,
+        data: undefined,
+      })
+    } finally {
+      await empty.cleanup()
+    }
+  })
+})
+
+describe('resolveInstalledLocation', () => {
+  it('walks up the node_modules folders', () => {
+    const locations = new Set([
+      'node_modules/a',
+      'node_modules/a/node_modules/b',
+      'node_modules/c',
+    ])
+
+    expect(resolveInstalledLocation('node_modules/a', 'b', locations)).toBe(
+      'node_modules/a/node_modules/b',
+    )
+    expect(
+      resolveInstalledLocation('node_modules/a/node_modules/b', 'c', locations),
+    ).toBe('node_modules/c')
+    expect(resolveInstalledLocation('', 'd', locations)).toBeUndefined()
+  })
+})
+
+describe('findExtraneousPackages', () => {
+  it('finds packages no dependency leads to', async () => {
+    const workspace = await createTestWorkspace({ files: getFiles() })
+    try {
+      const installed = await listInstalledPackages(workspace.path)
+
+      expect(await findExtraneousPackages(workspace.path, installed)).toEqual(
+        new Set(['node_modules/debug']),
+      )
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+})
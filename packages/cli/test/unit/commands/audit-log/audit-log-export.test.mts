@@ -0,0 +1,131 @@
+/**
+ * Unit tests for the audit log export.
+ *
+ * Purpose: Tests reading --since, paging through the audit log until the
+ * cutoff, and the JSON Lines and CSV formats.
+ *
+ * Related Files: - src/commands/audit-log/audit-log-export.mts
+ * (implementation) - src/commands/audit-log/fetch-audit-log.mts (API fetcher)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  exportAuditLog,
+  formatAuditLogCsv,
+  formatAuditLogJsonl,
+  parseAuditLogSince,
+} from '../../../../src/commands/audit-log/audit-log-export.mts'
+import {
+  createErrorResult,
+  createSuccessResult,
+} from '../../../helpers/mocks.mts'
+
+import type { AuditLogEvent } from '../../../../src/commands/audit-log/output-audit-log.mts'
+
+const mockFetchAuditLog = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/audit-log/fetch-audit-log.mts'),
+  () => ({
+    fetchAuditLog: mockFetchAuditLog,
+  }),
+)
+
+function event(eventId: string, createdAt: string): AuditLogEvent {
+  return {
+    event_id: eventId,
+    created_at: createdAt,
+    type: 'updateAlertTriage',
+    user_email: 'person@socket.dev',
+    payload: { note: 'Not used, "safe"' },
+  } as unknown as AuditLogEvent
+}
+
+describe('parseAuditLogSince', () => {
+  const now = Date.parse('2025-04-10T00:00:00Z')
+
+  it('reads dates and times back from now', () => {
+    expect(parseAuditLogSince('2025-04-01', now)).toBe(
+      Date.parse('2025-04-01'),
+    )
+    expect(parseAuditLogSince('7d', now)).toBe(
+      Date.parse('2025-04-03T00:00:00Z'),
+    )
+    expect(parseAuditLogSince('12h', now)).toBe(
+      Date.parse('2025-04-09T12:00:00Z'),
+    )
+    expect(parseAuditLogSince('last week', now)).toBeUndefined()
+  })
+})
+
+describe('exportAuditLog', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFetchAuditLog
+      .mockResolvedValueOnce(
+        createSuccessResult({
+          results: [
+            event('3', '2025-04-03T00:00:00Z'),
+            event('2', '2025-04-02T00:00:00Z'),
+          ],
+          nextPage: '2',
+        }),
+      )
+      .mockResolvedValueOnce(
+        createSuccessResult({
+          results: [
+            event('1', '2025-04-01T00:00:00Z'),
+            event('0', '2025-03-01T00:00:00Z'),
+          ],
+          nextPage: '3',
+        }),
+      )
+  })
+
+  it('stops paging at the cutoff and exports oldest first', async () => {
+    const result = await exportAuditLog({
+      logType: '',
+      orgSlug: 'test-org',
+      since: Date.parse('2025-03-15'),
+    })
+
+    expect(mockFetchAuditLog).toHaveBeenCalledTimes(2)
+    expect(mockFetchAuditLog.mock.calls[1]![0]).toMatchObject({
+      page: 2,
+      perPage: 100,
+    })
+    expect(result.ok && result.data.map(e => e.event_id)).toEqual([
+      '1',
+      '2',
+      '3',
+    ])
+  })
+
+  it('passes fetch failures through', async () => {
+    const error = createErrorResult('Socket API error', { code: 403 })
+    mockFetchAuditLog.mockReset().mockResolvedValue(error)
+
+    const result = await exportAuditLog({ logType: '', orgSlug: 'test-org' })
+
+    expect(result).toBe(error)
+  })
+})
+
+describe('formatAuditLog', () => {
+  const events = [event('1', '2025-04-01T00:00:00Z')]
+
+  it('writes one JSON object per line', () => {
+    expect(formatAuditLogJsonl([...events, ...events]).split('\n')).toEqual([
+      JSON.stringify(events[0]),
+      JSON.stringify(events[0]),
+    ])
+  })
+
+  it('writes the payload as JSON in CSV', () => {
+    expect(formatAuditLogCsv(events).split('\n')).toEqual([
+      'event_id,created_at,type,user_id,user_email,ip_address,country_code,user_agent,status_code,payload',
+      '1,2025-04-01T00:00:00Z,updateAlertTriage,,person@socket.dev,,,,,"{""note"":""Not used, \\""safe\\""""}"',
+    ])
+  })
+})
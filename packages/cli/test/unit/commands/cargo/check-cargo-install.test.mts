@@ -0,0 +1,139 @@
+/**
+ * Unit tests for the socket cargo pre-install check.
+ *
+ * Purpose: Tests which crates `cargo add` and `cargo install` invocations name
+ * and how they are resolved and scored before handing off to Socket Firewall.
+ *
+ * Test Coverage: - Crate specs with `@req` and --version - Global options and
+ * toolchain overrides before the subcommand - git, path and alternate
+ * registry sources are skipped - Likely typosquats warn before the lookup -
+ * Lookup failure falls through with a warning - Resolved purls are passed to
+ * the install check.
+ *
+ * Related Files: - src/commands/cargo/check-cargo-install.mts
+ * (implementation) - src/util/rust/crates-index.mts (version resolution)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  CARGO_WARN_ALERT_TYPES,
+  checkCargoInstall,
+  getCargoInstallCrates,
+} from '../../../../src/commands/cargo/check-cargo-install.mts'
+
+const mockResolveCrateVersion = vi.hoisted(() => vi.fn())
+const mockCheckPackagesBeforeInstall = vi.hoisted(() => vi.fn())
+const mockWarn = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/rust/crates-index.mts'), () => ({
+  resolveCrateVersion: mockResolveCrateVersion,
+}))
+
+vi.mock(import('../../../../src/util/install-check/check.mts'), () => ({
+  checkPackagesBeforeInstall: mockCheckPackagesBeforeInstall,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => ({ warn: mockWarn }),
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/spinner/default'), () => ({
+  getDefaultSpinner: () => ({ start: vi.fn(), stop: vi.fn() }),
+}))
+
+describe('getCargoInstallCrates', () => {
+  it('collects crates for cargo add', () => {
+    expect(
+      getCargoInstallCrates(['add', 'serde@1.0', '-F', 'derive', 'tokio']),
+    ).toEqual([
+      { name: 'serde', req: '1.0' },
+      { name: 'tokio', req: undefined },
+    ])
+  })
+
+  it('applies --version to cargo install', () => {
+    expect(
+      getCargoInstallCrates([
+        '+nightly',
+        '--config',
+        'net.retry=3',
+        'install',
+        '--locked',
+        '--version',
+        '14.1.0',
+        'ripgrep',
+      ]),
+    ).toEqual([{ name: 'ripgrep', req: '14.1.0' }])
+  })
+
+  it('skips other subcommands, sources and dry runs', () => {
+    expect(getCargoInstallCrates(['build', '--release'])).toBeUndefined()
+    expect(getCargoInstallCrates(['install', '--path', '.'])).toBeUndefined()
+    expect(
+      getCargoInstallCrates([
+        'install',
+        '--git',
+        'https://example.test/x',
+        'x',
+      ]),
+    ).toBeUndefined()
+    expect(
+      getCargoInstallCrates(['add', '--registry', 'corp', 'x']),
+    ).toBeUndefined()
+    expect(getCargoInstallCrates(['add', '--dry-run', 'x'])).toBeUndefined()
+    expect(getCargoInstallCrates(['install'])).toBeUndefined()
+  })
+})
+
+describe('checkCargoInstall', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('does nothing for non-install commands', async () => {
+    const result = await checkCargoInstall(['test'])
+
+    expect(result.ok).toBe(true)
+    expect(mockResolveCrateVersion).not.toHaveBeenCalled()
+  })
+
+  it('warns and proceeds when the index lookup fails', async () => {
+    mockResolveCrateVersion.mockResolvedValue({
+      ok: false,
+      message: 'Unable to resolve crate',
+      cause: 'https://index.crates.io/se/rd/serde returned HTTP 503',
+    })
+
+    const result = await checkCargoInstall(['add', 'serde'])
+
+    expect(result.ok).toBe(true)
+    expect(mockWarn).toHaveBeenCalledWith(
+      expect.stringContaining('Skipping the Socket pre-install check'),
+    )
+    expect(mockCheckPackagesBeforeInstall).not.toHaveBeenCalled()
+  })
+
+  it('checks resolved crates and leaves unknown ones to cargo', async () => {
+    mockResolveCrateVersion
+      .mockResolvedValueOnce({ ok: true, data: '1.0.203' })
+      .mockResolvedValueOnce({ ok: true, data: undefined })
+    mockCheckPackagesBeforeInstall.mockResolvedValue({
+      ok: false,
+      message: 'Install blocked by Socket security policy',
+    })
+
+    const result = await checkCargoInstall(['add', 'serde@1', 'srede'])
+
+    expect(result.ok).toBe(false)
+    expect(mockWarn).toHaveBeenCalledTimes(1)
+    expect(mockWarn).toHaveBeenCalledWith(
+      'srede looks like a typosquat of serde',
+    )
+    expect(mockResolveCrateVersion).toHaveBeenCalledWith('serde', '1')
+    expect(mockCheckPackagesBeforeInstall).toHaveBeenCalledWith(
+      ['pkg:cargo/serde@1.0.203'],
+      { commandPath: 'socket cargo', warnAlertTypes: CARGO_WARN_ALERT_TYPES },
+    )
+  })
+})
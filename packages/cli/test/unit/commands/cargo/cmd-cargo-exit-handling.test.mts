@@ -25,6 +25,14 @@ vi.mock(import('../../../../src/util/cli/with-subcommands.mjs'), () => ({
   meowOrExit: mockMeowOrExit,
 }))
 
+vi.mock(
+  import('../../../../src/commands/cargo/check-cargo-install.mts'),
+  async () => {
+    const { passingInstallCheck } = await import('../../../helpers/mocks.mts')
+    return passingInstallCheck('checkCargoInstall')
+  },
+)
+
 vi.mock(import('../../../../src/util/process/cmd.mts'), () => ({
   filterFlags: mockFilterFlags,
 }))
@@ -0,0 +1,144 @@
+/**
+ * Unit tests for the Renovate and Dependabot pull requests of `socket ci
+ * enrich-bot-pr`.
+ *
+ * Purpose: Tests telling bot pull requests apart, finding the bumped package
+ * and rendering the comparison comment.
+ *
+ * Test Coverage: - Branch and author detection - Dependabot and Renovate
+ * titles - Picking the bump among lockfile changes - Score and alert changes.
+ *
+ * Related Files: - src/commands/ci/bot-pr.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  BOT_PR_COMMENT_MARKER,
+  compareBotPrBump,
+  detectDependencyBot,
+  generateBotPrComment,
+  parseBotPrTitle,
+  selectBotPrBump,
+} from '../../../../src/commands/ci/bot-pr.mts'
+
+import type { BotPrVersionChange } from '../../../../src/commands/ci/bot-pr.mts'
+import type { BulkScoreRow } from '../../../../src/commands/package/bulk-score.mts'
+
+const LODASH: BotPrVersionChange = {
+  ecosystem: 'npm',
+  file: 'package-lock.json',
+  from: ['4.17.20'],
+  name: 'lodash',
+  to: ['4.17.21'],
+}
+
+function getRow(purl: string, overrides?: Partial<BulkScoreRow>): BulkScoreRow {
+  return {
+    alerts: { critical: 0, high: 0, low: 0, middle: 0 },
+    alertTypes: [],
+    found: true,
+    purl,
+    score: {
+      license: 100,
+      maintenance: 80,
+      quality: 90,
+      supplyChain: 70,
+      vulnerability: 100,
+    },
+    ...overrides,
+  }
+}
+
+describe('detectDependencyBot', () => {
+  it('detects bots by their branch or author', () => {
+    expect(
+      detectDependencyBot({
+        author: '',
+        branch: 'dependabot/npm_and_yarn/lodash-4.17.21',
+      }),
+    ).toBe('dependabot')
+    expect(
+      detectDependencyBot({ author: 'renovate[bot]', branch: 'deps/lodash' }),
+    ).toBe('renovate')
+    expect(
+      detectDependencyBot({ author: 'octocat', branch: 'fix-lodash' }),
+    ).toBeUndefined()
+  })
+})
+
+describe('parseBotPrTitle', () => {
+  it('reads the package of Dependabot and Renovate titles', () => {
+    expect(parseBotPrTitle('Bump lodash from 4.17.20 to 4.17.21')).toBe(
+      'lodash',
+    )
+    expect(
+      parseBotPrTitle(
+        'chore(deps): bump @types/node from 20.1.0 to 20.2.0 in /web',
+      ),
+    ).toBe('@types/node')
+    expect(
+      parseBotPrTitle('fix(deps): update dependency lodash to v4.17.21'),
+    ).toBe('lodash')
+    expect(parseBotPrTitle('Update all non-major dependencies')).toBeUndefined()
+  })
+})
+
+describe('selectBotPrBump', () => {
+  it('picks the only bumped package and skips added ones', () => {
+    const added = { ...LODASH, from: [], name: 'left-pad', to: ['1.0.0'] }
+    expect(selectBotPrBump([LODASH, added])).toEqual({
+      ok: true,
+      data: {
+        ecosystem: 'npm',
+        files: ['package-lock.json'],
+        from: '4.17.20',
+        name: 'lodash',
+        to: '4.17.21',
+      },
+    })
+  })
+
+  it('picks the package the title names among several', () => {
+    const transitive = { ...LODASH, from: ['1.0.0'], name: 'ms', to: ['1.0.1'] }
+    const result = selectBotPrBump([transitive, LODASH], 'lodash')
+    expect(result.ok && result.data.name).toBe('lodash')
+  })
+
+  it('fails on grouped updates', () => {
+    const other = { ...LODASH, from: ['1.0.0'], name: 'ms', to: ['1.0.1'] }
+    expect(selectBotPrBump([other, LODASH])).toMatchObject({
+      ok: false,
+      message: 'Not a single package bump',
+    })
+  })
+})
+
+describe('generateBotPrComment', () => {
+  it('renders the score and alert changes', () => {
+    const bump = {
+      ecosystem: 'npm' as const,
+      files: ['package-lock.json'],
+      from: '4.17.20',
+      name: 'lodash',
+      to: '4.17.21',
+    }
+    const comparison = compareBotPrBump(
+      bump,
+      getRow('pkg:npm/lodash@4.17.20', { alertTypes: ['cve'] }),
+      getRow('pkg:npm/lodash@4.17.21', { alertTypes: ['installScripts'] }),
+    )
+    expect(comparison.addedAlerts).toEqual(['installScripts'])
+    expect(comparison.removedAlerts).toEqual(['cve'])
+
+    const body = generateBotPrComment(comparison)
+    expect(body.startsWith(BOT_PR_COMMENT_MARKER)).toBe(true)
+    expect(body).toContain('## Socket: `lodash` 4.17.20 → 4.17.21')
+    expect(body).toContain('**The upgrade adds 1 alert type.**')
+    expect(body).toContain('| Supply chain | 70 | 70 | 0 |')
+    expect(body).toContain(
+      '- [`installScripts`](https://socket.dev/alerts/installScripts)',
+    )
+    expect(body).toContain('### Resolved alerts')
+  })
+})
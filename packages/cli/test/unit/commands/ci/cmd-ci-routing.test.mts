@@ -0,0 +1,251 @@
+/**
+ * Unit tests for ci command routing.
+ *
+ * Tests where `socket ci` sends its arguments besides the full scan: the
+ * report and token subcommands, the diff-aware scan of --smart, and the
+ * --fail-on threshold passed on to the handlers.
+ *
+ * Testing Approach: - Mock the subcommands and both handlers - Mock
+ * meowOrExit to control flag values - Mock git operations and
+ * getDefaultOrgSlug for the dry-run.
+ *
+ * Related Files: - src/commands/ci/cmd-ci.mts - Implementation -
+ * test/unit/commands/ci/cmd-ci.test.mts - Full scan and dry-run.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdCI } from '../../../../src/commands/ci/cmd-ci.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+import type * as WithSubcommandsModule from '../../../../src/util/cli/with-subcommands.mjs'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock handler.
+const mockHandleCi = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/commands/ci/handle-ci.mts'), () => ({
+  handleCi: mockHandleCi,
+}))
+
+const mockHandleCiSmart = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/commands/ci/handle-ci-smart.mts'), () => ({
+  handleCiSmart: mockHandleCiSmart,
+}))
+
+// Mock the report subcommand.
+const mockCiReportRun = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/commands/ci/cmd-ci-report.mts'), () => ({
+  cmdCiReport: {
+    description: 'Summarize the alert changes of a pull request',
+    hidden: false,
+    run: mockCiReportRun,
+  },
+}))
+
+// Mock the token subcommand.
+const mockCiTokenRun = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/commands/ci/cmd-ci-token.mts'), () => ({
+  cmdCiToken: {
+    description: 'Mint a short-lived API token',
+    hidden: false,
+    run: mockCiTokenRun,
+  },
+}))
+
+// Mock git operations.
+const mockGitBranch = vi.hoisted(() => vi.fn(() => Promise.resolve('main')))
+const mockDetectDefaultBranch = vi.hoisted(() =>
+  vi.fn(() => Promise.resolve('main')),
+)
+const mockGetRepoName = vi.hoisted(() =>
+  vi.fn(() => Promise.resolve('my-repo')),
+)
+
+vi.mock(import('../../../../src/util/git/operations.mjs'), () => ({
+  detectDefaultBranch: mockDetectDefaultBranch,
+  getRepoName: mockGetRepoName,
+  gitBranch: mockGitBranch,
+}))
+
+// Mock organization slug fetching.
+const mockGetDefaultOrgSlug = vi.hoisted(() =>
+  vi.fn(() => Promise.resolve({ ok: true, data: 'my-org' })),
+)
+
+vi.mock(
+  import('../../../../src/commands/ci/fetch-default-org-slug.mts'),
+  () => ({
+    getDefaultOrgSlug: mockGetDefaultOrgSlug,
+  }),
+)
+
+// Mock dry-run output.
+const mockOutputDryRunUpload = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/dry-run/output.mts'), () => ({
+  outputDryRunUpload: mockOutputDryRunUpload,
+}))
+
+// Mock meowOrExit to prevent actual CLI parsing. Also invoke the
+// help() callback so its template-string body is recorded as covered;
+// production meowOrExit only invokes it on --help, which the test
+// suite never exercises.
+const mockMeowOrExit = vi.hoisted(() =>
+  vi.fn((options: unknown) => {
+    const argv = options.argv as string[] | readonly string[]
+    const flags: Record<string, unknown> = {}
+
+    // Real meow seeds every declared flag with its `default` before argv
+    // overrides — mirror that from the command's own config so the mock
+    // never delivers `undefined` where production delivers `false`.
+    const declared = (options.config?.flags ?? {}) as Record<
+      string,
+      { default?: unknown | undefined }
+    >
+    for (const [name, def] of Object.entries(declared)) {
+      if (def && 'default' in def) {
+        flags[name] = def.default
+      }
+    }
+
+    // Parse flags from argv.
+    if (argv.includes('--dry-run')) {
+      flags['dryRun'] = true
+    }
+    if (argv.includes('--auto-manifest')) {
+      flags['autoManifest'] = true
+    }
+    if (argv.includes('--smart')) {
+      flags['smart'] = true
+    }
+    const failOnIndex = argv.indexOf('--fail-on')
+    if (failOnIndex !== -1) {
+      flags['failOn'] = argv[failOnIndex + 1]
+    }
+
+    const help = options.config?.help ? options.config.help('socket ci') : ''
+
+    return {
+      flags,
+      help,
+      input: [],
+      pkg: {},
+    }
+  }),
+)
+
+vi.mock(
+  import('../../../../src/util/cli/with-subcommands.mjs'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof WithSubcommandsModule>()
+    return {
+      ...actual,
+      meowOrExit: mockMeowOrExit,
+    }
+  },
+)
+
+describe('cmd-ci', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+    mockGetDefaultOrgSlug.mockResolvedValue({ ok: true, data: 'my-org' })
+    mockGitBranch.mockResolvedValue('main')
+    mockDetectDefaultBranch.mockResolvedValue('main')
+    mockGetRepoName.mockResolvedValue('my-repo')
+  })
+
+  describe('run', () => {
+    const importMeta = { url: 'file:///test/cmd-ci.mts' }
+    const context = { parentName: 'socket' }
+
+    describe('report subcommand', () => {
+      it('should route `socket ci report` to the report command', async () => {
+        await cmdCI.run(['report', '--github'], importMeta, context)
+
+        expect(mockCiReportRun).toHaveBeenCalledWith(['--github'], importMeta, {
+          parentName: 'socket ci',
+        })
+        expect(mockMeowOrExit).not.toHaveBeenCalled()
+        expect(mockHandleCi).not.toHaveBeenCalled()
+      })
+    })
+
+    describe('token subcommand', () => {
+      it('should route `socket ci token` to the token command', async () => {
+        await cmdCI.run(['token', '--oidc'], importMeta, context)
+
+        expect(mockCiTokenRun).toHaveBeenCalledWith(['--oidc'], importMeta, {
+          parentName: 'socket ci',
+        })
+        expect(mockMeowOrExit).not.toHaveBeenCalled()
+        expect(mockHandleCi).not.toHaveBeenCalled()
+      })
+    })
+
+    describe('--fail-on flag', () => {
+      it('should pass the threshold to the handler', async () => {
+        await cmdCI.run(['--fail-on', 'critical'], importMeta, context)
+
+        expect(mockHandleCi).toHaveBeenCalledWith(false, 'critical')
+      })
+
+      it('should fail on an unsupported threshold', async () => {
+        await cmdCI.run(['--fail-on', 'medium'], importMeta, context)
+
+        expect(process.exitCode).toBe(2)
+        expect(mockHandleCi).not.toHaveBeenCalled()
+      })
+    })
+
+    describe('--smart flag', () => {
+      it('should run the smart scan instead of the full one', async () => {
+        await cmdCI.run(['--smart'], importMeta, context)
+
+        expect(mockHandleCiSmart).toHaveBeenCalledWith(undefined)
+        expect(mockHandleCi).not.toHaveBeenCalled()
+      })
+
+      it('should fail when combined with --auto-manifest', async () => {
+        await cmdCI.run(['--smart', '--auto-manifest'], importMeta, context)
+
+        expect(process.exitCode).toBe(2)
+        expect(mockHandleCiSmart).not.toHaveBeenCalled()
+      })
+
+      it('should include smart in dry-run', async () => {
+        await cmdCI.run(['--smart', '--dry-run'], importMeta, context)
+
+        expect(mockOutputDryRunUpload).toHaveBeenCalledWith(
+          'CI scan',
+          expect.objectContaining({ smart: true }),
+        )
+        expect(mockHandleCiSmart).not.toHaveBeenCalled()
+      })
+    })
+  })
+})
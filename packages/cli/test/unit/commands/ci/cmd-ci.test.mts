@@ -15,7 +15,8 @@
  * dry-run testing.
  *
  * Related Files: - src/commands/ci/cmd-ci.mts - Implementation -
- * src/commands/ci/handle-ci.mts - Handler.
+ * src/commands/ci/handle-ci.mts - Handler - cmd-ci-routing.test.mts - The
+ * report and token subcommands, --smart and --fail-on.
  */
 
 import { beforeEach, describe, expect, it, vi } from 'vitest'
@@ -53,6 +54,34 @@ vi.mock(import('../../../../src/commands/ci/handle-ci.mts'), () => ({
   handleCi: mockHandleCi,
 }))
 
+const mockHandleCiSmart = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/commands/ci/handle-ci-smart.mts'), () => ({
+  handleCiSmart: mockHandleCiSmart,
+}))
+
+// Mock the report subcommand.
+const mockCiReportRun = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/commands/ci/cmd-ci-report.mts'), () => ({
+  cmdCiReport: {
+    description: 'Summarize the alert changes of a pull request',
+    hidden: false,
+    run: mockCiReportRun,
+  },
+}))
+
+// Mock the token subcommand.
+const mockCiTokenRun = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/commands/ci/cmd-ci-token.mts'), () => ({
+  cmdCiToken: {
+    description: 'Mint a short-lived API token',
+    hidden: false,
+    run: mockCiTokenRun,
+  },
+}))
+
 // Mock git operations.
 const mockGitBranch = vi.hoisted(() => vi.fn(() => Promise.resolve('main')))
 const mockDetectDefaultBranch = vi.hoisted(() =>
@@ -169,13 +198,13 @@ describe('cmd-ci', () => {
       it('should call handler with autoManifest false by default', async () => {
         await cmdCI.run([], importMeta, context)
 
-        expect(mockHandleCi).toHaveBeenCalledWith(false)
+        expect(mockHandleCi).toHaveBeenCalledWith(false, undefined)
       })
 
       it('should call handler with autoManifest true when flag provided', async () => {
         await cmdCI.run(['--auto-manifest'], importMeta, context)
 
-        expect(mockHandleCi).toHaveBeenCalledWith(true)
+        expect(mockHandleCi).toHaveBeenCalledWith(true, undefined)
       })
 
       it('should call handler exactly once', async () => {
@@ -293,13 +322,13 @@ describe('cmd-ci', () => {
       it('should default to false', async () => {
         await cmdCI.run([], importMeta, context)
 
-        expect(mockHandleCi).toHaveBeenCalledWith(false)
+        expect(mockHandleCi).toHaveBeenCalledWith(false, undefined)
       })
 
       it('should pass true when flag provided', async () => {
         await cmdCI.run(['--auto-manifest'], importMeta, context)
 
-        expect(mockHandleCi).toHaveBeenCalledWith(true)
+        expect(mockHandleCi).toHaveBeenCalledWith(true, undefined)
       })
 
       it('should handle boolean conversion correctly', async () => {
@@ -394,7 +423,7 @@ describe('cmd-ci', () => {
 
         await cmdCI.run(readonlyArgv, importMeta, context)
 
-        expect(mockHandleCi).toHaveBeenCalledWith(false)
+        expect(mockHandleCi).toHaveBeenCalledWith(false, undefined)
       })
 
       it('should handle all git operations returning null', async () => {
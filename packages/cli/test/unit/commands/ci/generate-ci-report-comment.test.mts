@@ -0,0 +1,113 @@
+/**
+ * Unit tests for the `socket ci report` pull request comment.
+ *
+ * Purpose: Tests the markdown rendered for an alert diff.
+ *
+ * Test Coverage: - Marker and verdict - Count table - Collapsed per-alert
+ * sections with blocking alerts first - Resolved alerts - Size limit.
+ *
+ * Related Files: - src/commands/ci/generate-ci-report-comment.mts
+ * (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  CI_REPORT_COMMENT_MARKER,
+  formatAlertDetails,
+  generateCiReportComment,
+} from '../../../../src/commands/ci/generate-ci-report-comment.mts'
+
+import type { ScanDiffAlert } from '../../../../src/commands/scan/diff-scan-alerts.mts'
+
+const OPTIONS = { afterScanId: 'scan-2', beforeScanId: 'scan-1' }
+
+const MALWARE: ScanDiffAlert = {
+  action: 'error',
+  file: 'package.json',
+  purl: 'pkg:npm/evil@1.0.0',
+  severity: 'critical',
+  type: 'malware',
+}
+
+const INSTALL_SCRIPTS: ScanDiffAlert = {
+  action: 'warn',
+  purl: 'pkg:npm/esbuild@0.21.0',
+  severity: 'middle',
+  type: 'installScripts',
+}
+
+describe('formatAlertDetails', () => {
+  it('renders a collapsed section per alert', () => {
+    const details = formatAlertDetails(MALWARE)
+
+    expect(details).toMatch(/^<details>\n<summary>🚫 <code>malware<\/code>/)
+    expect(details).toContain('- Severity: critical')
+    expect(details).toContain('- Policy action: error')
+    expect(details).toContain('- Manifest: `package.json`')
+    expect(details).toContain('https://socket.dev/alerts/malware')
+    expect(details).toContain(
+      'https://socket.dev/npm/package/evil/overview/1.0.0',
+    )
+    expect(details.endsWith('</details>')).toBe(true)
+  })
+})
+
+describe('generateCiReportComment', () => {
+  it('starts with the marker so later runs find the comment', () => {
+    const body = generateCiReportComment(
+      { added: [], blocking: [], removed: [] },
+      OPTIONS,
+    )
+
+    expect(body.startsWith(CI_REPORT_COMMENT_MARKER)).toBe(true)
+    expect(body).toContain('✅ No new alerts.')
+    expect(body).not.toContain('### New alerts')
+    expect(body).toContain('Compared scan `scan-1` with `scan-2`.')
+  })
+
+  it('lists blocking alerts first', () => {
+    const body = generateCiReportComment(
+      {
+        added: [INSTALL_SCRIPTS, MALWARE],
+        blocking: [MALWARE],
+        removed: [],
+      },
+      OPTIONS,
+    )
+
+    expect(body).toContain('🚫 **1 new alert violates the security policy.**')
+    expect(body).toContain('| New | 2 |')
+    expect(body).toContain('| Blocking | 1 |')
+    expect(body.indexOf('<code>malware</code>')).toBeLessThan(
+      body.indexOf('<code>installScripts</code>'),
+    )
+  })
+
+  it('collapses resolved alerts and links the report', () => {
+    const body = generateCiReportComment(
+      { added: [INSTALL_SCRIPTS], blocking: [], removed: [MALWARE] },
+      { ...OPTIONS, reportUrl: 'https://socket.dev/report/1' },
+    )
+
+    expect(body).toContain('✅ No new alerts violate the security policy.')
+    expect(body).toContain('<summary>✨ 1 resolved alert</summary>')
+    expect(body).toContain('- `malware` in `pkg:npm/evil@1.0.0`')
+    expect(body).toContain('[View the scan](https://socket.dev/report/1)')
+  })
+
+  it('summarizes alerts that do not fit in a comment', () => {
+    const added = Array.from({ length: 1000 }, (_, i) => ({
+      ...INSTALL_SCRIPTS,
+      purl: `pkg:npm/package-${i}@1.0.0`,
+    }))
+
+    const body = generateCiReportComment(
+      { added, blocking: [], removed: [] },
+      OPTIONS,
+    )
+
+    expect(body.length).toBeLessThan(65_536)
+    expect(body).toMatch(/… and \d+ more alerts\./)
+  })
+})
@@ -0,0 +1,429 @@
+/**
+ * Unit tests for the `socket ci report` handler.
+ *
+ * Purpose: Tests how the scans to compare are picked and how the report is
+ * posted to the pull request or merge request.
+ *
+ * Test Coverage: - Scan IDs from flags - Latest scans of the base and head
+ * branches - GitLab merge request branches - Missing pull request context and
+ * token - Creating vs updating the comment - GitLab merge request notes -
+ * Errors from the comment API - handleCiReport output.
+ *
+ * Related Files: - src/commands/ci/handle-ci-report.mts (implementation) -
+ * src/commands/ci/generate-ci-report-comment.mts - Comment body.
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockEnv = vi.hoisted(() => ({ baseRef: '', token: 'gh-token' }))
+vi.mock(import('../../../../src/env/github-base-ref.mts'), () => ({
+  get GITHUB_BASE_REF() {
+    return mockEnv.baseRef
+  },
+}))
+vi.mock(import('../../../../src/env/socket-cli-github-token.mts'), () => ({
+  getGithubToken: () => mockEnv.token,
+  get SOCKET_CLI_GITHUB_TOKEN() {
+    return mockEnv.token
+  },
+}))
+
+const mockGetDefaultOrgSlug = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/ci/fetch-default-org-slug.mts'),
+  () => ({
+    getDefaultOrgSlug: mockGetDefaultOrgSlug,
+  }),
+)
+
+const mockDetectCiPullRequestNumber = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/ci/handle-ci.mts'), () => ({
+  detectCiPullRequestNumber: mockDetectCiPullRequestNumber,
+}))
+
+const mockCiRepoInfo = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/fix/env-helpers.mts'), () => ({
+  ciRepoInfo: mockCiRepoInfo,
+}))
+
+const mockUpsertComment = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/git/github-provider.mts'), () => ({
+  GitHubProvider: vi.fn(function MockGitHubProvider() {
+    return { upsertComment: mockUpsertComment }
+  }),
+}))
+vi.mock(import('../../../../src/util/git/gitlab-provider.mts'), () => ({
+  GitLabProvider: vi.fn(function MockGitLabProvider() {
+    return { upsertComment: mockUpsertComment }
+  }),
+}))
+
+const mockGetRepoName = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/git/operations.mjs'), () => ({
+  getRepoName: mockGetRepoName,
+}))
+
+const mockFetchOrgFullScanList = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/scan/fetch-list-scans.mts'), () => ({
+  fetchOrgFullScanList: mockFetchOrgFullScanList,
+}))
+
+const mockFetchScanAlertDiff = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/scan/fetch-scan-alert-diff.mts'),
+  () => ({
+    fetchScanAlertDiff: mockFetchScanAlertDiff,
+  }),
+)
+
+const mockOutputCiReport = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/ci/output-ci-report.mts'), () => ({
+  outputCiReport: mockOutputCiReport,
+}))
+
+import { CI_REPORT_COMMENT_MARKER } from '../../../../src/commands/ci/generate-ci-report-comment.mts'
+import {
+  getCiReport,
+  handleCiReport,
+  postCiReportComment,
+  resolveCiReportScans,
+} from '../../../../src/commands/ci/handle-ci-report.mts'
+
+const BLOCKING_ALERT = {
+  action: 'error',
+  purl: 'pkg:npm/evil@1.0.0',
+  severity: 'critical',
+  type: 'malware',
+}
+
+const GITLAB_ENV_NAMES = [
+  'CI_MERGE_REQUEST_IID',
+  'CI_MERGE_REQUEST_SOURCE_BRANCH_NAME',
+  'CI_MERGE_REQUEST_TARGET_BRANCH_NAME',
+  'CI_PROJECT_PATH',
+  'GITLAB_TOKEN',
+]
+
+describe('handle-ci-report', () => {
+  const originalEnv: Record<string, string | undefined> = {}
+  for (const name of ['GITHUB_HEAD_REF', ...GITLAB_ENV_NAMES]) {
+    originalEnv[name] = process.env[name]
+  }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockEnv.baseRef = 'main'
+    mockEnv.token = 'gh-token'
+    process.env['GITHUB_HEAD_REF'] = 'feature'
+    for (const name of GITLAB_ENV_NAMES) {
+      delete process.env[name]
+    }
+    mockGetDefaultOrgSlug.mockResolvedValue({ ok: true, data: 'acme' })
+    mockGetRepoName.mockResolvedValue('widgets')
+    mockCiRepoInfo.mockReturnValue({ owner: 'acme', repo: 'widgets' })
+    mockDetectCiPullRequestNumber.mockReturnValue(42)
+    mockFetchOrgFullScanList.mockImplementation(async ({ branch }) => ({
+      ok: true,
+      data: {
+        results: [
+          {
+            id: `${branch}-scan`,
+            html_report_url: `https://socket.dev/${branch}`,
+          },
+        ],
+      },
+    }))
+    mockFetchScanAlertDiff.mockResolvedValue({
+      ok: true,
+      data: {
+        added: [BLOCKING_ALERT],
+        blocking: [BLOCKING_ALERT],
+        removed: [],
+      },
+    })
+  })
+
+  afterEach(() => {
+    for (const [name, value] of Object.entries(originalEnv)) {
+      if (value === undefined) {
+        delete process.env[name]
+      } else {
+        process.env[name] = value
+      }
+    }
+  })
+
+  describe('resolveCiReportScans', () => {
+    it('uses the scan IDs given as flags', async () => {
+      const result = await resolveCiReportScans({
+        afterScanId: 'after',
+        beforeScanId: 'before',
+        orgSlug: 'acme',
+        repoName: 'widgets',
+      })
+
+      expect(result).toEqual({
+        ok: true,
+        data: { after: { id: 'after' }, before: { id: 'before' } },
+      })
+      expect(mockFetchOrgFullScanList).not.toHaveBeenCalled()
+    })
+
+    it('falls back to the latest scans of the base and head branches', async () => {
+      const result = await resolveCiReportScans({
+        afterScanId: '',
+        beforeScanId: '',
+        orgSlug: 'acme',
+        repoName: 'widgets',
+      })
+
+      expect(result).toEqual({
+        ok: true,
+        data: {
+          after: {
+            id: 'feature-scan',
+            reportUrl: 'https://socket.dev/feature',
+          },
+          before: { id: 'main-scan', reportUrl: 'https://socket.dev/main' },
+        },
+      })
+      expect(mockFetchOrgFullScanList).toHaveBeenCalledWith(
+        expect.objectContaining({
+          branch: 'main',
+          direction: 'desc',
+          orgSlug: 'acme',
+          perPage: 1,
+          repo: 'widgets',
+          sort: 'created_at',
+        }),
+        { commandPath: 'socket ci report' },
+      )
+    })
+
+    it('uses the merge request branches in GitLab CI', async () => {
+      mockEnv.baseRef = ''
+      delete process.env['GITHUB_HEAD_REF']
+      process.env['CI_MERGE_REQUEST_TARGET_BRANCH_NAME'] = 'develop'
+      process.env['CI_MERGE_REQUEST_SOURCE_BRANCH_NAME'] = 'topic'
+
+      const result = await resolveCiReportScans({
+        afterScanId: '',
+        beforeScanId: '',
+        orgSlug: 'acme',
+        repoName: 'widgets',
+      })
+
+      expect(result.ok && result.data.before.id).toBe('develop-scan')
+      expect(result.ok && result.data.after.id).toBe('topic-scan')
+    })
+
+    it('fails outside a pull request build without flags', async () => {
+      mockEnv.baseRef = ''
+
+      const result = await resolveCiReportScans({
+        afterScanId: 'after',
+        beforeScanId: '',
+        orgSlug: 'acme',
+        repoName: 'widgets',
+      })
+
+      expect(result).toMatchObject({
+        ok: false,
+        message: 'Missing scan to compare',
+      })
+      expect(!result.ok && result.cause).toContain('--before')
+    })
+
+    it('fails when a branch has no scan', async () => {
+      mockFetchOrgFullScanList.mockResolvedValue({
+        ok: true,
+        data: { results: [] },
+      })
+
+      const result = await resolveCiReportScans({
+        afterScanId: 'after',
+        beforeScanId: '',
+        orgSlug: 'acme',
+        repoName: 'widgets',
+      })
+
+      expect(result).toMatchObject({ ok: false, message: 'No scan found' })
+    })
+  })
+
+  describe('postCiReportComment', () => {
+    it('upserts the marked comment on the pull request', async () => {
+      mockUpsertComment.mockResolvedValue('updated')
+
+      const result = await postCiReportComment('body', 'github')
+
+      expect(mockUpsertComment).toHaveBeenCalledWith({
+        body: 'body',
+        marker: CI_REPORT_COMMENT_MARKER,
+        owner: 'acme',
+        prNumber: 42,
+        repo: 'widgets',
+      })
+      expect(result).toEqual({
+        ok: true,
+        data: {
+          platform: 'github',
+          prNumber: 42,
+          repo: 'acme/widgets',
+          status: 'updated',
+        },
+      })
+    })
+
+    it('requires a GitHub token', async () => {
+      mockEnv.token = ''
+
+      const result = await postCiReportComment('body', 'github')
+
+      expect(result).toMatchObject({
+        ok: false,
+        message: 'Missing GitHub token',
+      })
+      expect(mockUpsertComment).not.toHaveBeenCalled()
+    })
+
+    it('requires a pull request', async () => {
+      mockDetectCiPullRequestNumber.mockReturnValue(0)
+
+      const result = await postCiReportComment('body', 'github')
+
+      expect(result).toMatchObject({
+        ok: false,
+        message: 'Missing pull request context',
+      })
+    })
+
+    it('reports API failures', async () => {
+      mockUpsertComment.mockRejectedValue(new Error('Resource not accessible'))
+
+      const result = await postCiReportComment('body', 'github')
+
+      expect(result).toMatchObject({
+        ok: false,
+        message: 'Failed to post the report comment',
+      })
+      expect(!result.ok && result.cause).toContain('Resource not accessible')
+    })
+
+    it('upserts the marked note on a GitLab merge request', async () => {
+      process.env['GITLAB_TOKEN'] = 'gl-token'
+      process.env['CI_PROJECT_PATH'] = 'acme/platform/widgets'
+      process.env['CI_MERGE_REQUEST_IID'] = '7'
+      mockUpsertComment.mockResolvedValue('created')
+
+      const result = await postCiReportComment('body', 'gitlab')
+
+      expect(mockUpsertComment).toHaveBeenCalledWith({
+        body: 'body',
+        marker: CI_REPORT_COMMENT_MARKER,
+        owner: 'acme/platform',
+        prNumber: 7,
+        repo: 'widgets',
+      })
+      expect(result).toEqual({
+        ok: true,
+        data: {
+          platform: 'gitlab',
+          prNumber: 7,
+          repo: 'acme/platform/widgets',
+          status: 'created',
+        },
+      })
+    })
+
+    it('requires a GitLab token', async () => {
+      const result = await postCiReportComment('body', 'gitlab')
+
+      expect(result).toMatchObject({
+        ok: false,
+        message: 'Missing GitLab token',
+      })
+      expect(mockUpsertComment).not.toHaveBeenCalled()
+    })
+
+    it('requires a merge request pipeline', async () => {
+      process.env['GITLAB_TOKEN'] = 'gl-token'
+      process.env['CI_PROJECT_PATH'] = 'acme/widgets'
+
+      const result = await postCiReportComment('body', 'gitlab')
+
+      expect(result).toMatchObject({
+        ok: false,
+        message: 'Missing merge request context',
+      })
+    })
+  })
+
+  describe('getCiReport', () => {
+    it('renders the report without posting it', async () => {
+      const result = await getCiReport({
+        afterScanId: '',
+        beforeScanId: '',
+      })
+
+      expect(mockFetchScanAlertDiff).toHaveBeenCalledWith({
+        id1: 'main-scan',
+        id2: 'feature-scan',
+        orgSlug: 'acme',
+      })
+      expect(result.ok).toBe(true)
+      expect(result.ok && result.data.blocking).toBe(1)
+      expect(result.ok && result.data.body).toContain('<code>malware</code>')
+      expect(result.ok && result.data.comment).toBeUndefined()
+      expect(mockUpsertComment).not.toHaveBeenCalled()
+    })
+
+    it('posts the report to github', async () => {
+      mockUpsertComment.mockResolvedValue('created')
+
+      const result = await getCiReport({
+        afterScanId: '',
+        beforeScanId: '',
+        platform: 'github',
+      })
+
+      expect(result.ok && result.data.comment).toEqual({
+        platform: 'github',
+        prNumber: 42,
+        repo: 'acme/widgets',
+        status: 'created',
+      })
+    })
+
+    it('passes on scan diff errors', async () => {
+      mockFetchScanAlertDiff.mockResolvedValue({
+        ok: false,
+        message: 'Scan not found',
+      })
+
+      const result = await getCiReport({
+        afterScanId: 'a',
+        beforeScanId: 'b',
+        platform: 'github',
+      })
+
+      expect(result).toEqual({ ok: false, message: 'Scan not found' })
+      expect(mockUpsertComment).not.toHaveBeenCalled()
+    })
+  })
+
+  describe('handleCiReport', () => {
+    it('passes the report to the output', async () => {
+      await handleCiReport({
+        afterScanId: 'a',
+        beforeScanId: 'b',
+        outputKind: 'markdown',
+      })
+
+      expect(mockOutputCiReport).toHaveBeenCalledWith(
+        expect.objectContaining({ ok: true }),
+        'markdown',
+      )
+    })
+  })
+})
@@ -0,0 +1,273 @@
+/**
+ * Unit tests for the `socket ci --smart` handler.
+ *
+ * Purpose: Tests which package files a pull request scan uploads and how the
+ * partial scan is compared with the latest scan of the base branch.
+ *
+ * Test Coverage: - Changed manifests bring their lockfiles along - Unchanged
+ * dirs are left out - GitLab merge request target branch - Nothing to scan -
+ * Upload as a hidden scan - Blocking alerts with and without --fail-on -
+ * Missing base branch scan.
+ *
+ * Related Files: - src/commands/ci/handle-ci-smart.mts (implementation) -
+ * src/commands/ci/handle-ci-report.mts - Latest scan of a branch.
+ */
+
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const {
+  mockFetchCreateOrgFullScan,
+  mockFetchLatestScan,
+  mockFetchScanAlertDiff,
+  mockFetchSupportedScanFileNames,
+  mockGetBaseBranch,
+  mockGetPackageFilesForScan,
+  mockGitChangedFilesSince,
+} = vi.hoisted(() => ({
+  mockFetchCreateOrgFullScan: vi.fn(),
+  mockFetchLatestScan: vi.fn(),
+  mockFetchScanAlertDiff: vi.fn(),
+  mockFetchSupportedScanFileNames: vi.fn(),
+  mockGetBaseBranch: vi.fn(),
+  mockGetPackageFilesForScan: vi.fn(),
+  mockGitChangedFilesSince: vi.fn(),
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => ({ info: vi.fn(), log: vi.fn() }),
+}))
+vi.mock(
+  import('../../../../src/commands/ci/fetch-default-org-slug.mts'),
+  () => ({
+    getDefaultOrgSlug: vi.fn(async () => ({ ok: true, data: 'acme' })),
+  }),
+)
+vi.mock(import('../../../../src/commands/ci/handle-ci.mts'), () => ({
+  detectCiPullRequestNumber: () => 42,
+}))
+vi.mock(import('../../../../src/commands/ci/handle-ci-report.mts'), () => ({
+  fetchLatestScan: mockFetchLatestScan,
+}))
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  findSocketYmlSync: () => ({ ok: true, data: undefined }),
+}))
+vi.mock(import('../../../../src/util/fs/path-resolve.mts'), () => ({
+  getPackageFilesForScan: mockGetPackageFilesForScan,
+}))
+vi.mock(import('../../../../src/util/git/operations.mjs'), () => ({
+  detectDefaultBranch: vi.fn(async () => 'main'),
+  getBaseBranch: mockGetBaseBranch,
+  getRepoName: vi.fn(async () => 'monorepo'),
+  gitBranch: vi.fn(async () => 'feature'),
+  gitChangedFilesSince: mockGitChangedFilesSince,
+}))
+vi.mock(
+  import('../../../../src/commands/scan/fetch-create-org-full-scan.mts'),
+  () => ({
+    fetchCreateOrgFullScan: mockFetchCreateOrgFullScan,
+  }),
+)
+vi.mock(
+  import('../../../../src/commands/scan/fetch-scan-alert-diff.mts'),
+  () => ({
+    fetchScanAlertDiff: mockFetchScanAlertDiff,
+  }),
+)
+vi.mock(
+  import('../../../../src/commands/scan/fetch-supported-scan-file-names.mts'),
+  () => ({
+    fetchSupportedScanFileNames: mockFetchSupportedScanFileNames,
+  }),
+)
+
+const { getCiSmartScan, resolveCiBaseBranch, selectChangedManifests } =
+  await import('../../../../src/commands/ci/handle-ci-smart.mts')
+
+const cwd = path.resolve('/repo')
+
+const packagePaths = [
+  path.join(cwd, 'package.json'),
+  path.join(cwd, 'pnpm-lock.yaml'),
+  path.join(cwd, 'packages/api/package.json'),
+  path.join(cwd, 'packages/web/package.json'),
+  path.join(cwd, 'packages/web/yarn.lock'),
+]
+
+describe('selectChangedManifests', () => {
+  it('adds the lockfile of a changed manifest', () => {
+    expect(
+      selectChangedManifests(
+        packagePaths,
+        ['packages/web/package.json', 'packages/web/src/index.ts'],
+        cwd,
+      ),
+    ).toEqual([
+      path.join(cwd, 'packages/web/package.json'),
+      path.join(cwd, 'packages/web/yarn.lock'),
+    ])
+  })
+
+  it('adds the manifest of a changed lockfile', () => {
+    expect(
+      selectChangedManifests(packagePaths, ['pnpm-lock.yaml'], cwd),
+    ).toEqual([
+      path.join(cwd, 'package.json'),
+      path.join(cwd, 'pnpm-lock.yaml'),
+    ])
+  })
+
+  it('selects nothing when only source files changed', () => {
+    expect(
+      selectChangedManifests(packagePaths, ['packages/api/src/main.ts'], cwd),
+    ).toEqual([])
+  })
+})
+
+describe('resolveCiBaseBranch', () => {
+  afterEach(() => {
+    vi.unstubAllEnvs()
+  })
+
+  it('prefers the GitLab merge request target branch', async () => {
+    vi.stubEnv('CI_MERGE_REQUEST_TARGET_BRANCH_NAME', 'develop')
+
+    expect(await resolveCiBaseBranch(cwd)).toBe('develop')
+    expect(mockGetBaseBranch).not.toHaveBeenCalled()
+  })
+
+  it('falls back to the base branch from git', async () => {
+    vi.stubEnv('CI_MERGE_REQUEST_TARGET_BRANCH_NAME', '')
+    mockGetBaseBranch.mockResolvedValueOnce('main')
+
+    expect(await resolveCiBaseBranch(cwd)).toBe('main')
+  })
+})
+
+describe('getCiSmartScan', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    vi.spyOn(process, 'cwd').mockReturnValue(cwd)
+    vi.stubEnv('CI_MERGE_REQUEST_TARGET_BRANCH_NAME', '')
+    mockGetBaseBranch.mockResolvedValue('main')
+    mockFetchSupportedScanFileNames.mockResolvedValue({ ok: true, data: {} })
+    mockGetPackageFilesForScan.mockResolvedValue(packagePaths)
+    mockFetchLatestScan.mockResolvedValue({ ok: true, data: { id: 'base' } })
+    mockFetchCreateOrgFullScan.mockResolvedValue({
+      ok: true,
+      data: { html_report_url: 'https://socket.dev/r/new', id: 'new' },
+    })
+  })
+
+  afterEach(() => {
+    vi.restoreAllMocks()
+    vi.unstubAllEnvs()
+  })
+
+  it('does not scan when no manifest changed', async () => {
+    mockGitChangedFilesSince.mockResolvedValue({
+      ok: true,
+      data: ['README.md'],
+    })
+
+    const result = await getCiSmartScan()
+
+    expect(mockGitChangedFilesSince).toHaveBeenCalledWith('origin/main', cwd)
+    expect(result).toEqual({
+      ok: true,
+      data: { added: 0, baseBranch: 'main', blocking: [], manifests: [] },
+    })
+    expect(mockFetchCreateOrgFullScan).not.toHaveBeenCalled()
+  })
+
+  it('uploads the changed dirs as a hidden scan', async () => {
+    mockGitChangedFilesSince.mockResolvedValue({
+      ok: true,
+      data: ['packages/api/package.json'],
+    })
+    const blocking = {
+      action: 'error',
+      purl: 'pkg:npm/evil@1.0.0',
+      severity: 'critical',
+      type: 'malware',
+    }
+    const warning = {
+      action: 'warn',
+      purl: 'pkg:npm/old@1.0.0',
+      severity: 'low',
+      type: 'deprecated',
+    }
+    mockFetchScanAlertDiff.mockResolvedValue({
+      ok: true,
+      data: { added: [blocking, warning], blocking: [blocking], removed: [] },
+    })
+
+    const result = await getCiSmartScan()
+
+    expect(mockFetchCreateOrgFullScan).toHaveBeenCalledWith(
+      [path.join(cwd, 'packages/api/package.json')],
+      'acme',
+      expect.objectContaining({
+        branchName: 'feature',
+        pullRequest: 42,
+        repoName: 'monorepo',
+      }),
+      expect.objectContaining({ pendingHead: false, tmp: true }),
+    )
+    expect(mockFetchScanAlertDiff).toHaveBeenCalledWith({
+      id1: 'base',
+      id2: 'new',
+      orgSlug: 'acme',
+    })
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        added: 2,
+        baseBranch: 'main',
+        baseScanId: 'base',
+        blocking: [blocking],
+        manifests: [path.join('packages', 'api', 'package.json')],
+        reportUrl: 'https://socket.dev/r/new',
+        scanId: 'new',
+      },
+    })
+  })
+
+  it('gates on severity with --fail-on', async () => {
+    mockGitChangedFilesSince.mockResolvedValue({
+      ok: true,
+      data: ['package.json'],
+    })
+    const high = {
+      action: 'warn',
+      purl: 'pkg:npm/risky@1.0.0',
+      severity: 'high',
+      type: 'networkAccess',
+    }
+    mockFetchScanAlertDiff.mockResolvedValue({
+      ok: true,
+      data: { added: [high], blocking: [], removed: [] },
+    })
+
+    const result = await getCiSmartScan('high')
+
+    expect(result.ok && result.data.blocking).toEqual([high])
+  })
+
+  it('fails without a scan of the base branch', async () => {
+    mockGitChangedFilesSince.mockResolvedValue({
+      ok: true,
+      data: ['package.json'],
+    })
+    mockFetchLatestScan.mockResolvedValue({
+      ok: false,
+      message: 'No scan found',
+    })
+
+    const result = await getCiSmartScan()
+
+    expect(result).toEqual({ ok: false, message: 'No scan found' })
+    expect(mockFetchCreateOrgFullScan).not.toHaveBeenCalled()
+  })
+})
@@ -0,0 +1,226 @@
+/**
+ * Unit tests for the `socket ci token` handler.
+ *
+ * Purpose: Tests which credential is exchanged for the short-lived token and
+ * how the org is picked.
+ *
+ * Test Coverage: - API token exchange with the default org - OIDC exchange
+ * without an API token or with --oidc - Missing org for OIDC - Missing OIDC
+ * identity - GitHub and GitLab OIDC identities.
+ *
+ * Related Files: - src/commands/ci/handle-ci-token.mts (implementation) -
+ * src/commands/ci/ci-oidc.mts - CI OIDC identity.
+ * - src/commands/ci/fetch-ci-token.mts - Token API call.
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockEnv = vi.hoisted(() => ({ orgSlug: '' }))
+vi.mock(import('../../../../src/env/socket-cli-org-slug.mts'), () => ({
+  get SOCKET_CLI_ORG_SLUG() {
+    return mockEnv.orgSlug
+  },
+}))
+
+const mockHasDefaultApiToken = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), () => ({
+  hasDefaultApiToken: mockHasDefaultApiToken,
+}))
+
+const mockGetDefaultOrgSlug = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/ci/fetch-default-org-slug.mts'),
+  () => ({
+    getDefaultOrgSlug: mockGetDefaultOrgSlug,
+  }),
+)
+
+const mockFetchCiToken = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/ci/fetch-ci-token.mts'), () => ({
+  fetchCiToken: mockFetchCiToken,
+}))
+
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  socketHttpRequest: mockSocketHttpRequest,
+  tryReadResponseText: (response: { body?: string }) => response.body,
+}))
+
+const mockOutputCiToken = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/ci/output-ci-token.mts'), () => ({
+  outputCiToken: mockOutputCiToken,
+}))
+
+import { detectCiOidcProvider } from '../../../../src/commands/ci/ci-oidc.mts'
+import { handleCiToken } from '../../../../src/commands/ci/handle-ci-token.mts'
+
+const minted = {
+  ok: true,
+  data: {
+    expires_at: '2026-01-01T01:00:00Z',
+    scopes: ['full-scans:create'],
+    token: 'sktsec_short',
+  },
+}
+
+const OIDC_ENV_KEYS = [
+  'ACTIONS_ID_TOKEN_REQUEST_TOKEN',
+  'ACTIONS_ID_TOKEN_REQUEST_URL',
+  'GITLAB_CI',
+  'SOCKET_ID_TOKEN',
+]
+
+function lastOutput() {
+  return mockOutputCiToken.mock.calls.at(-1)?.[0]
+}
+
+describe('handleCiToken', () => {
+  const savedEnv = { ...process.env }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockEnv.orgSlug = ''
+    mockFetchCiToken.mockResolvedValue(minted)
+    for (const key of OIDC_ENV_KEYS) {
+      delete process.env[key]
+    }
+  })
+
+  afterEach(() => {
+    for (const key of OIDC_ENV_KEYS) {
+      if (savedEnv[key] === undefined) {
+        delete process.env[key]
+      } else {
+        process.env[key] = savedEnv[key]
+      }
+    }
+  })
+
+  it('exchanges the API token for the default org', async () => {
+    mockHasDefaultApiToken.mockReturnValue(true)
+    mockGetDefaultOrgSlug.mockResolvedValue({ ok: true, data: 'acme' })
+
+    await handleCiToken({
+      oidc: false,
+      orgSlug: '',
+      outputKind: 'text',
+      ttlMinutes: 30,
+    })
+
+    expect(mockFetchCiToken).toHaveBeenCalledWith({
+      identity: undefined,
+      orgSlug: 'acme',
+      ttlMinutes: 30,
+    })
+    expect(lastOutput()).toEqual({
+      ok: true,
+      data: {
+        expiresAt: '2026-01-01T01:00:00Z',
+        orgSlug: 'acme',
+        scopes: ['full-scans:create'],
+        source: 'api-token',
+        token: 'sktsec_short',
+      },
+    })
+  })
+
+  it('exchanges the GitHub OIDC identity without an API token', async () => {
+    mockHasDefaultApiToken.mockReturnValue(false)
+    mockEnv.orgSlug = 'acme'
+    process.env['ACTIONS_ID_TOKEN_REQUEST_URL'] =
+      'https://token.actions.example/request?x=1'
+    process.env['ACTIONS_ID_TOKEN_REQUEST_TOKEN'] = 'request-token'
+    mockSocketHttpRequest.mockResolvedValue({
+      body: JSON.stringify({ value: 'github-id-token' }),
+      status: 200,
+    })
+
+    await handleCiToken({
+      oidc: false,
+      orgSlug: '',
+      outputKind: 'json',
+      ttlMinutes: 60,
+    })
+
+    expect(mockSocketHttpRequest).toHaveBeenCalledWith(
+      'https://token.actions.example/request?x=1&audience=https%3A%2F%2Fsocket.dev',
+      expect.objectContaining({
+        headers: expect.objectContaining({
+          authorization: 'bearer request-token',
+        }),
+      }),
+    )
+    expect(mockFetchCiToken).toHaveBeenCalledWith({
+      identity: { idToken: 'github-id-token', provider: 'github' },
+      orgSlug: 'acme',
+      ttlMinutes: 60,
+    })
+    expect(lastOutput()).toMatchObject({ ok: true, data: { source: 'github' } })
+  })
+
+  it('uses the GitLab ID token with --oidc and needs an org', async () => {
+    mockHasDefaultApiToken.mockReturnValue(true)
+    process.env['GITLAB_CI'] = 'true'
+    process.env['SOCKET_ID_TOKEN'] = 'gitlab-id-token'
+
+    expect(detectCiOidcProvider()).toBe('gitlab')
+
+    await handleCiToken({
+      oidc: true,
+      orgSlug: '',
+      outputKind: 'text',
+      ttlMinutes: 60,
+    })
+    expect(lastOutput()).toMatchObject({ ok: false, message: 'Missing org' })
+    expect(mockFetchCiToken).not.toHaveBeenCalled()
+
+    await handleCiToken({
+      oidc: true,
+      orgSlug: 'acme',
+      outputKind: 'text',
+      ttlMinutes: 60,
+    })
+    expect(mockFetchCiToken).toHaveBeenCalledWith({
+      identity: { idToken: 'gitlab-id-token', provider: 'gitlab' },
+      orgSlug: 'acme',
+      ttlMinutes: 60,
+    })
+  })
+
+  it('fails outside of a CI job with an OIDC identity', async () => {
+    mockHasDefaultApiToken.mockReturnValue(false)
+
+    await handleCiToken({
+      oidc: false,
+      orgSlug: 'acme',
+      outputKind: 'text',
+      ttlMinutes: 60,
+    })
+
+    expect(lastOutput()).toMatchObject({
+      ok: false,
+      message: 'No CI OIDC identity',
+    })
+    expect(mockFetchCiToken).not.toHaveBeenCalled()
+  })
+
+  it('explains a missing id-token permission on GitHub', async () => {
+    mockHasDefaultApiToken.mockReturnValue(false)
+    process.env['ACTIONS_ID_TOKEN_REQUEST_URL'] =
+      'https://token.actions.example/request'
+    process.env['ACTIONS_ID_TOKEN_REQUEST_TOKEN'] = 'request-token'
+    mockSocketHttpRequest.mockResolvedValue({ body: '{}', status: 403 })
+
+    await handleCiToken({
+      oidc: false,
+      orgSlug: 'acme',
+      outputKind: 'text',
+      ttlMinutes: 60,
+    })
+
+    expect(lastOutput()).toMatchObject({
+      ok: false,
+      cause: expect.stringContaining('id-token: write'),
+    })
+  })
+})
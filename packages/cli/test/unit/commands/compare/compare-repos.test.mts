@@ -0,0 +1,138 @@
+/**
+ * Unit tests for the repository comparison data.
+ *
+ * Tests how repositories are selected by slug or glob, how the alerts of a
+ * scan are checked against the security policy, and the table rows.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getPolicyCompliance,
+  selectRepos,
+  summarizeRepoPosture,
+} from '../../../../src/commands/compare/compare-repos.mts'
+import { getRepoPostureRow } from '../../../../src/commands/compare/output-compare-repos.mts'
+
+import type { SecurityPolicyData } from '../../../../src/commands/compare/compare-repos.mts'
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+const REPOS = [
+  { default_branch: 'main', name: 'web-shop' },
+  { default_branch: 'trunk', name: 'api' },
+  { archived: true, default_branch: 'main', name: 'web-legacy' },
+  { default_branch: 'main', name: 'web-admin' },
+]
+
+const POLICY = {
+  securityPolicyRules: {
+    criticalCVE: { action: 'error' },
+    didYouMean: { action: 'warn' },
+    unmaintained: { action: 'monitor' },
+  },
+} as unknown as SecurityPolicyData
+
+const ARTIFACTS = [
+  {
+    alerts: [
+      { key: 'a', severity: 'critical', type: 'criticalCVE' },
+      { key: 'b', severity: 'low', type: 'unmaintained' },
+    ],
+    score: {
+      license: 1,
+      maintenance: 0.5,
+      quality: 0.8,
+      supplyChain: 0.9,
+      vulnerability: 0.2,
+    },
+  },
+  {
+    alerts: [{ key: 'c', severity: 'high', type: 'didYouMean' }],
+  },
+] as unknown as SocketArtifact[]
+
+describe('selectRepos', () => {
+  it('selects repositories by slug and glob, sorted by name', () => {
+    const { selected, unmatched } = selectRepos(REPOS, ['web-*', 'api'])
+
+    expect(selected.map(r => r.name)).toEqual(['api', 'web-admin', 'web-shop'])
+    expect(unmatched).toEqual([])
+  })
+
+  it('only selects archived repositories named exactly', () => {
+    expect(
+      selectRepos(REPOS, ['web-*', 'web-legacy']).selected.map(r => r.name),
+    ).toEqual(['web-admin', 'web-legacy', 'web-shop'])
+  })
+
+  it('reports patterns matching no repository', () => {
+    expect(selectRepos(REPOS, ['api', 'mobile-*']).unmatched).toEqual([
+      'mobile-*',
+    ])
+  })
+})
+
+describe('getPolicyCompliance', () => {
+  it('counts the alerts the policy errors and warns on', () => {
+    expect(getPolicyCompliance(ARTIFACTS, POLICY)).toEqual({
+      compliant: false,
+      errors: 1,
+      warnings: 1,
+    })
+  })
+
+  it('complies without error alerts or policy rules', () => {
+    expect(getPolicyCompliance(ARTIFACTS.slice(1), POLICY)).toEqual({
+      compliant: true,
+      errors: 0,
+      warnings: 1,
+    })
+    expect(
+      getPolicyCompliance(ARTIFACTS, {} as SecurityPolicyData).compliant,
+    ).toBe(true)
+  })
+})
+
+describe('summarizeRepoPosture', () => {
+  it('summarizes the scores, alerts and policy of a scan', () => {
+    const posture = summarizeRepoPosture(
+      'api',
+      'trunk',
+      { created_at: '2025-03-01T12:00:00.000Z', id: 'scan-1' },
+      ARTIFACTS,
+      POLICY,
+    )
+
+    expect(posture).toEqual({
+      alerts: { critical: 1, high: 1, low: 1, middle: 0 },
+      branch: 'trunk',
+      createdAt: '2025-03-01T12:00:00.000Z',
+      packages: 2,
+      policy: { compliant: false, errors: 1, warnings: 1 },
+      repo: 'api',
+      scanId: 'scan-1',
+      score: {
+        license: 100,
+        maintenance: 50,
+        quality: 80,
+        supplyChain: 90,
+        vulnerability: 20,
+      },
+    })
+    expect(getRepoPostureRow(posture)).toEqual({
+      critical: '1',
+      date: '2025-03-01',
+      high: '1',
+      license: '100',
+      low: '1',
+      maintenance: '50',
+      middle: '0',
+      packages: '2',
+      policy: 'fail, 1 error, 1 warning',
+      quality: '80',
+      repo: 'api',
+      supplyChain: '90',
+      vulnerability: '20',
+    })
+  })
+})
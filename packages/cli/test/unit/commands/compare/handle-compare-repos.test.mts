@@ -0,0 +1,159 @@
+/**
+ * Unit tests for handleCompareRepos.
+ *
+ * Purpose: Tests the handler that selects repositories of the org, fetches
+ * the latest scan of each default branch and summarizes it with the
+ * security policy.
+ *
+ * Testing Approach: Mocks the fetch and output functions to isolate the
+ * handler orchestration logic.
+ *
+ * Related Files: - src/commands/compare/handle-compare-repos.mts
+ * (implementation) - src/commands/compare/compare-repos.mts (summaries)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { createSuccessResult } from '../../../helpers/index.mts'
+import { handleCompareRepos } from '../../../../src/commands/compare/handle-compare-repos.mts'
+
+const mockFetchListAllRepos = vi.hoisted(() => vi.fn())
+const mockFetchOrgFullScanList = vi.hoisted(() => vi.fn())
+const mockFetchScan = vi.hoisted(() => vi.fn())
+const mockFetchSecurityPolicy = vi.hoisted(() => vi.fn())
+const mockOutputCompareRepos = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/compare/output-compare-repos.mts'),
+  () => ({
+    outputCompareRepos: mockOutputCompareRepos,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/organization/fetch-security-policy.mts'),
+  () => ({
+    fetchSecurityPolicy: mockFetchSecurityPolicy,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/repository/fetch-list-all-repos.mts'),
+  () => ({
+    fetchListAllRepos: mockFetchListAllRepos,
+  }),
+)
+
+vi.mock(import('../../../../src/commands/scan/fetch-list-scans.mts'), () => ({
+  fetchOrgFullScanList: mockFetchOrgFullScanList,
+}))
+
+vi.mock(import('../../../../src/commands/scan/fetch-scan.mts'), () => ({
+  fetchScan: mockFetchScan,
+}))
+
+describe('handleCompareRepos', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFetchListAllRepos.mockResolvedValue(
+      createSuccessResult({
+        nextPage: null,
+        results: [
+          { default_branch: 'trunk', name: 'api' },
+          { default_branch: 'main', name: 'web' },
+          { default_branch: '', name: 'docs' },
+        ],
+      }),
+    )
+    mockFetchSecurityPolicy.mockResolvedValue(
+      createSuccessResult({
+        securityPolicyRules: { malware: { action: 'error' } },
+      }),
+    )
+  })
+
+  it('compares the latest default branch scans', async () => {
+    mockFetchOrgFullScanList.mockImplementation(async ({ repo }) =>
+      createSuccessResult({
+        results:
+          repo === 'docs'
+            ? []
+            : [{ created_at: '2025-03-01T00:00:00.000Z', id: `${repo}-1` }],
+      }),
+    )
+    mockFetchScan.mockImplementation(async (_org: string, id: string) =>
+      createSuccessResult(
+        id === 'web-1'
+          ? [{ alerts: [{ key: 'a', severity: 'critical', type: 'malware' }] }]
+          : [],
+      ),
+    )
+
+    await handleCompareRepos({
+      orgSlug: 'test-org',
+      outputKind: 'json',
+      patterns: ['*'],
+    })
+
+    expect(mockFetchOrgFullScanList).toHaveBeenCalledWith(
+      expect.objectContaining({
+        branch: 'trunk',
+        direction: 'desc',
+        perPage: 1,
+        repo: 'api',
+        sort: 'created_at',
+      }),
+      { commandPath: 'socket compare repos' },
+    )
+    const [result, outputKind] = mockOutputCompareRepos.mock.calls[0]!
+    expect(outputKind).toBe('json')
+    expect(result.ok).toBe(true)
+    const repos = result.data.repos.map(
+      (r: { policy: { compliant: boolean }; repo: string }) => [
+        r.repo,
+        r.policy.compliant,
+      ],
+    )
+    expect(repos).toEqual([
+      ['api', true],
+      ['web', false],
+    ])
+    expect(result.data.unscanned).toEqual([{ branch: 'main', repo: 'docs' }])
+  })
+
+  it('fails on a pattern matching no repository', async () => {
+    await handleCompareRepos({
+      orgSlug: 'test-org',
+      outputKind: 'text',
+      patterns: ['api', 'mobile'],
+    })
+
+    expect(mockFetchSecurityPolicy).not.toHaveBeenCalled()
+    expect(mockOutputCompareRepos).toHaveBeenCalledWith(
+      expect.objectContaining({
+        ok: false,
+        message: 'Repository not found',
+        cause: 'No repository of test-org matches mobile',
+      }),
+      'text',
+    )
+  })
+
+  it('passes on a failed scan fetch', async () => {
+    const failure = { ok: false, message: 'Socket API error', code: 500 }
+    mockFetchOrgFullScanList.mockResolvedValue(
+      createSuccessResult({
+        results: [{ created_at: '2025-03-01T00:00:00.000Z', id: 'scan-1' }],
+      }),
+    )
+    mockFetchScan.mockResolvedValue(failure)
+
+    await handleCompareRepos({
+      orgSlug: 'test-org',
+      outputKind: 'markdown',
+      patterns: ['api'],
+    })
+
+    expect(mockOutputCompareRepos).toHaveBeenCalledWith(failure, 'markdown')
+  })
+})
@@ -0,0 +1,148 @@
+/**
+ * Unit tests for what `socket completion` offers on a tab press.
+ *
+ * Purpose: Tests walking the words typed so far to the command they name and
+ * listing what can follow.
+ *
+ * Test Coverage: - Subcommands and aliases, hiding hidden ones - Flags, and
+ * skipping flag values while walking - Org, repo and scan values from the
+ * completion history - Static positional values - Unknown commands.
+ *
+ * Testing Approach: Builds a small command tree whose commands hand their
+ * node to the capture, and stubs the completion history.
+ *
+ * Related Files: - src/commands/completion/completion-candidates.mts
+ * (implementation) - src/util/cli/command-tree.mts - Introspection.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockReadCompletionHistory = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/cli/completion-history.mts'), () => ({
+  readCompletionHistory: mockReadCompletionHistory,
+}))
+
+import { getCompletionCandidates } from '../../../../src/commands/completion/completion-candidates.mts'
+import {
+  captureCommandTreeNode,
+  isCapturingCommandTree,
+} from '../../../../src/util/cli/command-tree.mts'
+
+import type { MeowFlags } from '../../../../src/flags.mts'
+import type { CommandTreeNode } from '../../../../src/util/cli/command-tree.mts'
+import type { CliSubcommand } from '../../../../src/util/cli/with-subcommands.mts'
+
+const orgFlags: MeowFlags = {
+  json: { type: 'boolean', description: 'Output as JSON' },
+  org: { type: 'string', description: 'Organization slug' },
+  secret: { type: 'boolean', description: 'Hidden', hidden: true },
+}
+
+function command(
+  description: string,
+  node: Partial<CommandTreeNode>,
+): CliSubcommand {
+  return {
+    description,
+    run() {
+      if (isCapturingCommandTree()) {
+        captureCommandTreeNode({
+          aliases: {},
+          flags: orgFlags,
+          subcommands: undefined,
+          ...node,
+        })
+      }
+      throw new Error('should not run')
+    },
+  }
+}
+
+const root: CommandTreeNode = {
+  aliases: {
+    repo: { argv: ['repository'], description: 'Alias', hidden: true },
+  },
+  flags: {
+    config: { type: 'string', description: 'Config JSON' },
+    help: { type: 'boolean', description: 'Show help' },
+  },
+  subcommands: {
+    repository: command('Manage repositories', {
+      subcommands: {
+        create: command('Create a repository', {}),
+        view: command('View a repository', {}),
+      },
+    }),
+    scan: command('Manage scans', {
+      subcommands: {
+        diff: command('Diff two scans', {}),
+      },
+    }),
+    secret: { ...command('Hidden', {}), hidden: true },
+  },
+}
+
+function complete(words: string[], current = '') {
+  return getCompletionCandidates({
+    current,
+    importMeta: { url: import.meta.url } as ImportMeta,
+    name: 'socket',
+    root,
+    words,
+  })
+}
+
+describe('getCompletionCandidates', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockReadCompletionHistory.mockResolvedValue({
+      org: ['acme', 'beta'],
+      repo: ['api', 'web'],
+      scan: ['scan-1', 'scan-2'],
+    })
+  })
+
+  it('lists the visible subcommands', async () => {
+    expect(await complete([])).toEqual([
+      { description: 'Manage repositories', value: 'repository' },
+      { description: 'Manage scans', value: 'scan' },
+    ])
+    expect(await complete([], 'sc')).toEqual([
+      { description: 'Manage scans', value: 'scan' },
+    ])
+  })
+
+  it('follows aliases and skips flag values', async () => {
+    expect(
+      (await complete(['--config', 'view', 'repo'])).map(c => c.value),
+    ).toEqual(['create', 'view'])
+  })
+
+  it('lists the flags of the command', async () => {
+    expect(await complete(['repository', 'view'], '--')).toEqual([
+      { description: 'Output as JSON', value: '--json' },
+      { description: 'Organization slug', value: '--org' },
+    ])
+  })
+
+  it('offers recorded values for flags and arguments', async () => {
+    expect(await complete(['repository', 'view', '--org'], 'a')).toEqual([
+      { value: 'acme' },
+    ])
+    expect(await complete(['repository', 'view'], '--org=b')).toEqual([
+      { value: '--org=beta' },
+    ])
+    expect(await complete(['repository', 'view', '--json'])).toEqual([
+      { value: 'api' },
+      { value: 'web' },
+    ])
+    expect(
+      (await complete(['scan', 'diff', 'scan-1'])).map(c => c.value),
+    ).toEqual(['scan-1', 'scan-2'])
+    expect(await complete(['repository', 'create'])).toEqual([])
+  })
+
+  it('offers nothing for unknown commands', async () => {
+    expect(await complete(['nope'])).toEqual([])
+  })
+})
@@ -0,0 +1,48 @@
+/**
+ * Unit tests for the scripts `socket completion <shell>` prints.
+ *
+ * Purpose: Tests that each shell calls back into the CLI for candidates.
+ *
+ * Test Coverage: - The callback and its arguments per shell - Registration
+ * for another command name.
+ *
+ * Related Files: - src/commands/completion/completion-scripts.mts
+ * (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  COMPLETION_SHELLS,
+  getCompletionScript,
+} from '../../../../src/commands/completion/completion-scripts.mts'
+
+describe('getCompletionScript', () => {
+  it.each(COMPLETION_SHELLS)('calls back into socket from %s', shell => {
+    const script = getCompletionScript(shell)
+
+    expect(script).toContain('completion __complete')
+    expect(script).toContain('--current=')
+    expect(script).toContain('SOCKET_CLI_SKIP_UPDATE_CHECK')
+  })
+
+  it('registers each shell for the command', () => {
+    expect(getCompletionScript('bash')).toContain(
+      'complete -o default -F _socket_complete socket\n',
+    )
+    expect(getCompletionScript('zsh')).toMatch(/^#compdef socket\n/)
+    expect(getCompletionScript('fish')).toContain(
+      "complete -c socket -f -a '(__socket_complete)'\n",
+    )
+    expect(getCompletionScript('powershell')).toContain(
+      "Register-ArgumentCompleter -Native -CommandName 'socket'",
+    )
+  })
+
+  it('completes another command name', () => {
+    const script = getCompletionScript('bash', 'sd')
+
+    expect(script).toContain('sd completion __complete')
+    expect(script).toContain('complete -o default -F _sd_complete sd\n')
+  })
+})
@@ -5,13 +5,13 @@
  * dispatcher.
  *
  * Test Coverage: - Unknown key → "Requested key is not a valid config key" -
- * apiBaseUrl / apiProxy / apiToken → returns hand-written advisory without an
- * auto-discovery attempt - defaultOrg without an API token → "No API token set"
- * error - defaultOrg with token + single org → returns that slug - defaultOrg
- * with token + multiple orgs → returns array - defaultOrg with token + zero
- * orgs → "Was unable to determine" - defaultOrg with token + fetch failure →
- * same error message - enforcedOrgs same matrix - test sentinel key →
- * "congrats, you found the test key"
+ * apiAuthScheme / apiBaseUrl / apiProxy / apiToken / caCert / retry settings → returns
+ * hand-written advisory without an auto-discovery attempt - defaultOrg without
+ * an API token → "No API token set" error - defaultOrg with token + single
+ * org → returns that slug - defaultOrg with token + multiple orgs → returns
+ * array - defaultOrg with token + zero orgs → "Was unable to determine" -
+ * defaultOrg with token + fetch failure → same error message - enforcedOrgs
+ * same matrix - test sentinel key → "congrats, you found the test key"
  *
  * Related Files: - src/commands/config/discover-config-value.mts -
  * Implementation - src/util/config.mts - isSupportedConfigKey (mocked) -
@@ -77,6 +77,14 @@ describe('discoverConfigValue', () => {
     }
   })
 
+  it('returns advisory for apiAuthScheme', async () => {
+    const result = await discoverConfigValue('apiAuthScheme')
+    expect(result.ok).toBe(false)
+    if (!result.ok) {
+      expect(result.cause).toContain('bearer')
+    }
+  })
+
   it('returns advisory for apiBaseUrl', async () => {
     const result = await discoverConfigValue('apiBaseUrl')
     expect(result.ok).toBe(false)
@@ -101,6 +109,25 @@ describe('discoverConfigValue', () => {
     }
   })
 
+  it('returns advisory for caCert', async () => {
+    const result = await discoverConfigValue('caCert')
+    expect(result.ok).toBe(false)
+    if (!result.ok) {
+      expect(result.cause).toContain('CA certificate of the proxy')
+    }
+  })
+
+  it.each(['apiMaxRetries', 'apiRetryBackoff', 'apiTimeout'])(
+    'returns advisory for %s',
+    async key => {
+      const result = await discoverConfigValue(key)
+      expect(result.ok).toBe(false)
+      if (!result.ok) {
+        expect(result.cause).toContain('flaky networks')
+      }
+    },
+  )
+
   describe('defaultOrg', () => {
     it('errors when no API token is set', async () => {
       mockHasDefaultApiToken.mockReturnValue(false)
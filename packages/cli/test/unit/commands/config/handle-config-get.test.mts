@@ -37,6 +37,7 @@ const mockLogger = vi.hoisted(() => ({
 // Mock the dependencies.
 const mockOutputConfigGet = vi.hoisted(() => vi.fn())
 const mockGetConfigValue = vi.hoisted(() => vi.fn())
+const mockGetConfigValueSource = vi.hoisted(() => vi.fn())
 
 vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
   getDefaultLogger: () => mockLogger,
@@ -51,6 +52,7 @@ vi.mock(
 )
 vi.mock(import('../../../../src/util/config.mts'), () => ({
   getConfigValue: mockGetConfigValue,
+  getConfigValueSource: mockGetConfigValueSource,
 }))
 
 describe('handleConfigGet', () => {
@@ -92,6 +94,31 @@ describe('handleConfigGet', () => {
     expect(outputConfigGet).toHaveBeenCalledWith('org', mockResult, 'text')
   })
 
+  it('passes the source of the value with showSource', async () => {
+    const mockResult = createSuccessResult('env-org')
+    const source = {
+      origin: 'SOCKET_CLI_ORG_SLUG',
+      source: 'env',
+      value: 'env-org',
+    }
+    mockGetConfigValue.mockReturnValue(mockResult)
+    mockGetConfigValueSource.mockReturnValue(source)
+
+    await handleConfigGet({
+      key: 'defaultOrg',
+      outputKind: 'text',
+      showSource: true,
+    })
+
+    expect(mockGetConfigValueSource).toHaveBeenCalledWith('defaultOrg')
+    expect(mockOutputConfigGet).toHaveBeenCalledWith(
+      'defaultOrg',
+      mockResult,
+      'text',
+      { source },
+    )
+  })
+
   it('handles markdown output', async () => {
     const { getConfigValue } = await import('../../../../src/util/config.mts')
     const { outputConfigGet } =
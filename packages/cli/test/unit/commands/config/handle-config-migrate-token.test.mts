@@ -0,0 +1,131 @@
+/**
+ * Unit tests for config migrate-token handler.
+ *
+ * Tests the handler that moves plaintext API tokens from the config file to
+ * the OS keychain.
+ *
+ * Test Coverage: - Moving the tokens of every profile - Skipping profiles
+ * without a token or already in the keychain - Keeping tokens that could not
+ * be stored - Read-only config - Missing keychain.
+ *
+ * Related Files: - src/commands/config/handle-config-migrate-token.mts -
+ * Implementation - src/util/keychain.mts - OS keychain storage.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockOutputConfigMigrateToken = vi.hoisted(() => vi.fn())
+const mockGetConfigValues = vi.hoisted(() => vi.fn())
+const mockGetProfileConfig = vi.hoisted(() => vi.fn())
+const mockGetProfileNames = vi.hoisted(() => vi.fn())
+const mockIsConfigFromFlag = vi.hoisted(() => vi.fn(() => false))
+const mockUpdateProfileConfigValue = vi.hoisted(() => vi.fn())
+const mockIsKeychainEnabled = vi.hoisted(() => vi.fn(() => true))
+const mockStoreKeychainToken = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/config/output-config-migrate-token.mts'),
+  () => ({
+    outputConfigMigrateToken: mockOutputConfigMigrateToken,
+  }),
+)
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  getConfigValues: mockGetConfigValues,
+  getProfileConfig: mockGetProfileConfig,
+  getProfileNames: mockGetProfileNames,
+  isConfigFromFlag: mockIsConfigFromFlag,
+  updateProfileConfigValue: mockUpdateProfileConfigValue,
+}))
+vi.mock(import('../../../../src/util/keychain.mts'), () => ({
+  isKeychainEnabled: mockIsKeychainEnabled,
+  KEYCHAIN_API_TOKEN_REF: '<keychain>',
+  storeKeychainToken: mockStoreKeychainToken,
+}))
+
+import { handleConfigMigrateToken } from '../../../../src/commands/config/handle-config-migrate-token.mts'
+
+const profiles: Record<string, { apiToken?: string }> = {
+  ci: {},
+  oss: { apiToken: '<keychain>' },
+  work: { apiToken: 'work-token' },
+}
+
+describe('handleConfigMigrateToken', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockGetConfigValues.mockReturnValue({ apiToken: 'top-token' })
+    mockGetProfileNames.mockReturnValue(['ci', 'oss', 'work'])
+    mockGetProfileConfig.mockImplementation(name => profiles[name])
+    mockStoreKeychainToken.mockReturnValue({ ok: true, data: undefined })
+  })
+
+  it('moves plaintext tokens of every profile', async () => {
+    await handleConfigMigrateToken({ outputKind: 'text' })
+
+    expect(mockStoreKeychainToken).toHaveBeenCalledWith('default', 'top-token')
+    expect(mockStoreKeychainToken).toHaveBeenCalledWith('work', 'work-token')
+    expect(mockUpdateProfileConfigValue).toHaveBeenCalledWith(
+      'work',
+      'apiToken',
+      '<keychain>',
+    )
+    expect(mockOutputConfigMigrateToken).toHaveBeenCalledWith(
+      {
+        ok: true,
+        data: [
+          { profile: 'default', status: 'moved' },
+          { profile: 'ci', status: 'no-token' },
+          { profile: 'oss', status: 'in-keychain' },
+          { profile: 'work', status: 'moved' },
+        ],
+      },
+      'text',
+    )
+  })
+
+  it('keeps tokens that could not be stored', async () => {
+    mockStoreKeychainToken.mockReturnValueOnce({
+      ok: false,
+      message: 'OS keychain unavailable',
+      cause: 'locked',
+    })
+
+    await handleConfigMigrateToken({ outputKind: 'json' })
+
+    const [result] = mockOutputConfigMigrateToken.mock.calls[0]!
+    expect(result.ok).toBe(false)
+    expect(result.data[0]).toEqual({
+      profile: 'default',
+      reason: 'locked',
+      status: 'failed',
+    })
+    expect(mockUpdateProfileConfigValue).not.toHaveBeenCalledWith(
+      'default',
+      expect.anything(),
+      expect.anything(),
+    )
+  })
+
+  it('fails without a keychain', async () => {
+    mockIsKeychainEnabled.mockReturnValueOnce(false)
+
+    await handleConfigMigrateToken({ outputKind: 'text' })
+
+    expect(mockStoreKeychainToken).not.toHaveBeenCalled()
+    expect(mockOutputConfigMigrateToken.mock.calls[0]![0]).toMatchObject({
+      ok: false,
+      message: 'OS keychain unavailable',
+    })
+  })
+
+  it('fails for a read-only config', async () => {
+    mockIsConfigFromFlag.mockReturnValueOnce(true)
+
+    await handleConfigMigrateToken({ outputKind: 'text' })
+
+    expect(mockOutputConfigMigrateToken.mock.calls[0]![0]).toMatchObject({
+      ok: false,
+      message: 'Config is read-only',
+    })
+  })
+})
@@ -0,0 +1,109 @@
+/**
+ * Unit tests for config use-profile handler.
+ *
+ * Tests the handler that switches the active auth profile.
+ *
+ * Test Coverage: - Switching to a known profile - Switching back to the
+ * default profile - Unknown profiles - SOCKET_CLI_PROFILE taking precedence.
+ *
+ * Related Files: - src/commands/config/handle-config-use-profile.mts -
+ * Implementation - src/util/config.mts - Config file utilities.
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockOutputConfigUseProfile = vi.hoisted(() => vi.fn())
+const mockGetConfigValueOrUndef = vi.hoisted(() => vi.fn())
+const mockGetProfileConfig = vi.hoisted(() => vi.fn())
+const mockGetProfileNames = vi.hoisted(() => vi.fn())
+const mockUpdateConfigValue = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/config/output-config-use-profile.mts'),
+  () => ({
+    outputConfigUseProfile: mockOutputConfigUseProfile,
+  }),
+)
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  getConfigValueOrUndef: mockGetConfigValueOrUndef,
+  getProfileConfig: mockGetProfileConfig,
+  getProfileNames: mockGetProfileNames,
+  updateConfigValue: mockUpdateConfigValue,
+}))
+
+import { handleConfigUseProfile } from '../../../../src/commands/config/handle-config-use-profile.mts'
+
+describe('handleConfigUseProfile', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockUpdateConfigValue.mockReturnValue({
+      ok: true,
+      message: "Config key 'activeProfile' was updated",
+      data: undefined,
+    })
+  })
+
+  afterEach(() => {
+    delete process.env['SOCKET_CLI_PROFILE']
+  })
+
+  it('switches to a known profile', async () => {
+    mockGetProfileConfig.mockReturnValue({ defaultOrg: 'work-org' })
+
+    await handleConfigUseProfile({ outputKind: 'text', profile: 'work' })
+
+    expect(mockUpdateConfigValue).toHaveBeenCalledWith('activeProfile', 'work')
+    expect(mockOutputConfigUseProfile).toHaveBeenCalledWith(
+      {
+        ok: true,
+        message: "Now using profile 'work'",
+        data: { defaultOrg: 'work-org', note: undefined, profile: 'work' },
+      },
+      'text',
+    )
+  })
+
+  it('switches back to the default profile', async () => {
+    mockGetConfigValueOrUndef.mockReturnValue('top-org')
+
+    await handleConfigUseProfile({ outputKind: 'json', profile: 'default' })
+
+    expect(mockGetProfileConfig).not.toHaveBeenCalled()
+    expect(mockUpdateConfigValue).toHaveBeenCalledWith(
+      'activeProfile',
+      undefined,
+    )
+    expect(mockOutputConfigUseProfile.mock.calls[0]![0].data).toMatchObject({
+      defaultOrg: 'top-org',
+      profile: 'default',
+    })
+  })
+
+  it('fails for unknown profiles and lists the known ones', async () => {
+    mockGetProfileConfig.mockReturnValue(undefined)
+    mockGetProfileNames.mockReturnValue(['oss', 'work'])
+
+    await handleConfigUseProfile({ outputKind: 'text', profile: 'nope' })
+
+    expect(mockUpdateConfigValue).not.toHaveBeenCalled()
+    expect(mockOutputConfigUseProfile).toHaveBeenCalledWith(
+      {
+        ok: false,
+        message: 'Unknown profile',
+        cause: expect.stringContaining('default, oss, and work'),
+      },
+      'text',
+    )
+  })
+
+  it('notes when SOCKET_CLI_PROFILE takes precedence', async () => {
+    process.env['SOCKET_CLI_PROFILE'] = 'oss'
+    mockGetProfileConfig.mockReturnValue({})
+
+    await handleConfigUseProfile({ outputKind: 'text', profile: 'work' })
+
+    expect(mockOutputConfigUseProfile.mock.calls[0]![0].data.note).toContain(
+      'SOCKET_CLI_PROFILE',
+    )
+  })
+})
@@ -27,6 +27,8 @@ vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
 // Mock utilities.
 const mockIsConfigFromFlag = vi.hoisted(() => vi.fn(() => false))
 vi.mock(import('../../../../src/util/config.mts'), () => ({
+  formatConfigSource: (source: { origin: string; source: string }) =>
+    `${source.source} (${source.origin})`,
   isConfigFromFlag: mockIsConfigFromFlag,
 }))
 
@@ -123,6 +125,26 @@ describe('output-config-get', () => {
         expect(process.exitCode).toBe(1)
       })
 
+      it('shows the source with --source', async () => {
+        const result: CResult<string> = {
+          ok: true,
+          data: 'env-org',
+        }
+
+        await outputConfigGet('defaultOrg', result, 'text', {
+          source: {
+            origin: 'SOCKET_CLI_ORG_SLUG',
+            source: 'env',
+            value: 'env-org',
+          },
+        })
+
+        expect(mockLogger.log).toHaveBeenCalledWith('defaultOrg: env-org')
+        expect(mockLogger.log).toHaveBeenCalledWith(
+          'Source: env (SOCKET_CLI_ORG_SLUG)',
+        )
+      })
+
       it('shows read-only note when config from flag', async () => {
         mockIsConfigFromFlag.mockReturnValue(true)
         const result: CResult<string> = {
@@ -0,0 +1,170 @@
+/**
+ * Unit tests for container scan command.
+ *
+ * Tests the command that creates a Socket scan from an OCI container image.
+ */
+
+import { mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { safeDeleteSync } from '@socketsecurity/lib-stable/fs/safe'
+
+import { cmdContainerScan } from '../../../../src/commands/container/cmd-container-scan.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+import type * as SdkModule from '../../../../src/util/socket/sdk.mts'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockHandleContainerScan = vi.hoisted(() => vi.fn())
+const mockDetermineOrgSlug = vi.hoisted(() =>
+  vi.fn().mockResolvedValue(['test-org', 'test-org']),
+)
+const mockHasDefaultApiToken = vi.hoisted(() => vi.fn().mockReturnValue(true))
+
+vi.mock(
+  import('../../../../src/commands/container/handle-container-scan.mts'),
+  () => ({
+    handleContainerScan: mockHandleContainerScan,
+  }),
+)
+
+vi.mock(import('../../../../src/util/socket/org-slug.mts'), () => ({
+  determineOrgSlug: mockDetermineOrgSlug,
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mts'), async importOriginal => {
+  const actual = await importOriginal<typeof SdkModule>()
+  return {
+    ...actual,
+    hasDefaultApiToken: mockHasDefaultApiToken,
+  }
+})
+
+describe('cmd-container-scan', () => {
+  const importMeta = { url: 'file:///test/cmd-container-scan.mts' }
+  const context = { parentName: 'socket container' }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  it('should not be hidden', () => {
+    expect(cmdContainerScan.hidden).toBe(false)
+  })
+
+  it('should scan a registry image', async () => {
+    await cmdContainerScan.run(
+      ['ghcr.io/acme/api:1.4.2', '--platform', 'linux/arm64'],
+      importMeta,
+      context,
+    )
+
+    expect(mockHandleContainerScan).toHaveBeenCalledWith({
+      branchName: '',
+      image: 'ghcr.io/acme/api:1.4.2',
+      imageRef: {
+        registry: 'ghcr.io',
+        repository: 'acme/api',
+        tag: '1.4.2',
+      },
+      interactive: true,
+      orgSlug: 'test-org',
+      outputKind: 'text',
+      platform: 'linux/arm64',
+      repoName: undefined,
+      tmp: false,
+    })
+  })
+
+  it('should treat existing paths as image tarballs', async () => {
+    const dir = mkdtempSync(path.join(os.tmpdir(), 'socket-container-cmd-'))
+    const tarball = path.join(dir, 'image.tar')
+    writeFileSync(tarball, '')
+
+    try {
+      await cmdContainerScan.run(
+        [tarball, '--repo', 'api', '--branch', 'main', '--json'],
+        importMeta,
+        context,
+      )
+    } finally {
+      safeDeleteSync(dir, { force: true })
+    }
+
+    expect(mockHandleContainerScan).toHaveBeenCalledWith(
+      expect.objectContaining({
+        branchName: 'main',
+        image: tarball,
+        imageRef: undefined,
+        outputKind: 'json',
+        platform: undefined,
+        repoName: 'api',
+      }),
+    )
+  })
+
+  it('should fail without an image', async () => {
+    await cmdContainerScan.run([], importMeta, context)
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleContainerScan).not.toHaveBeenCalled()
+  })
+
+  it('should fail on an invalid image reference', async () => {
+    await cmdContainerScan.run(['Not An Image'], importMeta, context)
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleContainerScan).not.toHaveBeenCalled()
+  })
+
+  it('should fail on an invalid platform', async () => {
+    await cmdContainerScan.run(
+      ['nginx', '--platform', 'amd64'],
+      importMeta,
+      context,
+    )
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleContainerScan).not.toHaveBeenCalled()
+  })
+
+  it('should fail without Socket API token', async () => {
+    mockHasDefaultApiToken.mockReturnValueOnce(false)
+
+    await cmdContainerScan.run(['nginx'], importMeta, context)
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleContainerScan).not.toHaveBeenCalled()
+  })
+
+  it('should not scan in dry-run mode', async () => {
+    await cmdContainerScan.run(['nginx', '--dry-run'], importMeta, context)
+
+    expect(mockHandleContainerScan).not.toHaveBeenCalled()
+  })
+})
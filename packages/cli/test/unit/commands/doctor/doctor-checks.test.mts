@@ -0,0 +1,214 @@
+/**
+ * Unit tests for the checks of `socket doctor`.
+ *
+ * Purpose: Tests the findings and fixes of the auth, scope and PATH checks.
+ *
+ * Test Coverage: - Missing and rejected API tokens - Default org access -
+ * Token scopes missing for some commands - Binaries shadowed on the PATH -
+ * Wrapper aliases or shims without a socket binary.
+ *
+ * Related Files: - src/commands/doctor/doctor-checks.mts (implementation)
+ * - src/commands/doctor/handle-doctor.mts - Runs the checks.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockWhich = vi.hoisted(() => ({
+  whichRealSync: vi.fn(),
+}))
+vi.mock(import('@socketsecurity/lib-stable/bin/which'), () => mockWhich)
+
+const mockSdk = vi.hoisted(() => ({
+  getDefaultApiToken: vi.fn(),
+}))
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), () => mockSdk)
+
+const mockOrgs = vi.hoisted(() => ({
+  fetchOrganization: vi.fn(),
+}))
+vi.mock(
+  import('../../../../src/commands/organization/fetch-organization-list.mts'),
+  () => mockOrgs,
+)
+
+const mockTokens = vi.hoisted(() => ({
+  fetchOrgApiTokens: vi.fn(),
+}))
+vi.mock(
+  import('../../../../src/commands/organization/fetch-org-tokens.mts'),
+  () => mockTokens,
+)
+
+const mockWrapper = vi.hoisted(() => ({
+  hasSocketWrapperAlias: vi.fn(() => false),
+}))
+vi.mock(
+  import('../../../../src/commands/wrapper/check-socket-wrapper-setup.mts'),
+  () => mockWrapper,
+)
+
+const mockConfig = vi.hoisted(() => ({
+  getConfigValueOrUndef: vi.fn(),
+  getConfigValueSource: vi.fn(),
+}))
+vi.mock(import('../../../../src/util/config.mts'), async importOriginal => ({
+  ...(await importOriginal()),
+  ...mockConfig,
+}))
+
+import {
+  checkAuth,
+  checkShadowedBinaries,
+  getMissingScopes,
+} from '../../../../src/commands/doctor/doctor-checks.mts'
+
+describe('checkAuth', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockSdk.getDefaultApiToken.mockReturnValue('sktsec_abcdef123')
+    mockConfig.getConfigValueOrUndef.mockReturnValue(undefined)
+    mockConfig.getConfigValueSource.mockReturnValue({
+      origin: 'SOCKET_CLI_API_TOKEN',
+      source: 'env',
+      value: 'sktsec_abcdef123',
+    })
+    mockOrgs.fetchOrganization.mockResolvedValue({
+      ok: true,
+      data: { organizations: [{ slug: 'acme' }] },
+    })
+    mockTokens.fetchOrgApiTokens.mockResolvedValue({
+      ok: true,
+      data: [{ scopes: ['packages:list'], token_prefix: 'sktsec_abc' }],
+    })
+  })
+
+  it('fails without a token', async () => {
+    mockSdk.getDefaultApiToken.mockReturnValue(undefined)
+
+    expect(await checkAuth(true)).toEqual([
+      expect.objectContaining({
+        fix: 'Run `socket login`, or set SOCKET_CLI_API_TOKEN in CI',
+        name: 'api-token',
+        status: 'fail',
+      }),
+    ])
+  })
+
+  it('skips the API checks when the API is not reachable', async () => {
+    const checks = await checkAuth(false)
+
+    expect(checks.map(c => [c.name, c.status])).toEqual([
+      ['api-token', 'ok'],
+      ['api-token-valid', 'skip'],
+    ])
+    expect(checks[0]?.message).toBe(
+      'API token from env (SOCKET_CLI_API_TOKEN)',
+    )
+    expect(mockOrgs.fetchOrganization).not.toHaveBeenCalled()
+  })
+
+  it('fails when the API rejects the token', async () => {
+    mockOrgs.fetchOrganization.mockResolvedValue({
+      ok: false,
+      code: 5,
+      message: 'Socket API error',
+      data: { code: 401 },
+    })
+
+    const checks = await checkAuth(true)
+
+    expect(checks[1]).toMatchObject({
+      message: 'The Socket API rejected the token, it may be revoked or expired',
+      status: 'fail',
+    })
+  })
+
+  it('fails when the default org is not accessible', async () => {
+    mockConfig.getConfigValueOrUndef.mockReturnValue('other')
+
+    const checks = await checkAuth(true)
+
+    expect(checks.at(-1)).toMatchObject({
+      fix: 'Run `socket config set defaultOrg acme`, or use a token of other',
+      name: 'org-access',
+      status: 'fail',
+    })
+    expect(mockTokens.fetchOrgApiTokens).not.toHaveBeenCalled()
+  })
+
+  it('warns about scopes the token lacks', async () => {
+    const checks = await checkAuth(true)
+
+    expect(mockTokens.fetchOrgApiTokens).toHaveBeenCalledWith('acme')
+    expect(checks.at(-1)).toMatchObject({
+      name: 'token-scopes',
+      status: 'warn',
+    })
+    expect(checks.at(-1)?.message).toContain('full-scans:list')
+    expect(checks.at(-1)?.message).not.toContain('packages:list')
+  })
+
+  it('skips the scopes when the token is not listed', async () => {
+    mockTokens.fetchOrgApiTokens.mockResolvedValue({
+      ok: false,
+      message: 'Socket API error',
+    })
+
+    const checks = await checkAuth(true)
+
+    expect(checks.at(-1)).toMatchObject({
+      name: 'token-scopes',
+      status: 'skip',
+    })
+  })
+})
+
+describe('getMissingScopes', () => {
+  it('lists the scopes commands need that are not granted', () => {
+    const missing = getMissingScopes(['full-scans:list'])
+
+    expect(missing).toContain('api-tokens:create')
+    expect(missing).not.toContain('full-scans:list')
+    expect(missing).toEqual([...missing].sort())
+  })
+})
+
+describe('checkShadowedBinaries', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockWhich.whichRealSync.mockReturnValue(null)
+    mockWrapper.hasSocketWrapperAlias.mockReturnValue(false)
+  })
+
+  it('is ok when nothing is shadowed', () => {
+    expect(checkShadowedBinaries()).toEqual([
+      expect.objectContaining({ name: 'shadowed', status: 'ok' }),
+    ])
+  })
+
+  it('warns about binaries on the PATH more than once', () => {
+    mockWhich.whichRealSync.mockImplementation((bin: string) =>
+      bin === 'npm'
+        ? ['/usr/local/bin/npm', '/usr/bin/npm', '/usr/local/bin/npm']
+        : null,
+    )
+
+    expect(checkShadowedBinaries()).toEqual([
+      expect.objectContaining({
+        message: '/usr/local/bin/npm shadows /usr/bin/npm',
+        name: 'npm',
+        status: 'warn',
+      }),
+    ])
+  })
+
+  it('fails when the wrapper aliases call a missing socket', () => {
+    mockWrapper.hasSocketWrapperAlias.mockReturnValue(true)
+
+    const checks = checkShadowedBinaries()
+
+    expect(checks).toHaveLength(1)
+    expect(checks[0]).toMatchObject({ name: 'wrapper', status: 'fail' })
+    expect(checks[0]?.fix).toContain('socket wrapper disable')
+  })
+})
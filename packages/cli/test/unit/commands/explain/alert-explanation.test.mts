@@ -0,0 +1,193 @@
+/**
+ * Unit tests for alert explanations.
+ *
+ * Tests looking up an alert of a scan by its ID, resolving its location to a
+ * line of the locally installed package, and the remediation and links of
+ * the explanation.
+ */
+
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  explainAlert,
+  findScanAlert,
+} from '../../../../src/commands/explain/alert-explanation.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+const SOURCE = [
+  "'use strict'",
+  "const token = process.env['NPM_TOKEN']",
+  'module.exports = token',
+  '',
+].join('\n')
+
+function getScan(): SocketArtifact[] {
+  return [
+    {
+      id: '1',
+      type: 'npm',
+      name: 'left-pad',
+      version: '1.3.0',
+      direct: false,
+      alerts: [
+        {
+          key: 'QmEnv1',
+          type: 'envVars',
+          severity: 'low',
+          category: 'supplyChainRisk',
+          file: 'package/index.js',
+          start: SOURCE.indexOf('process.env'),
+          end: SOURCE.indexOf(']'),
+          props: { envVars: ['NPM_TOKEN'] },
+        },
+      ],
+      manifestFiles: [{ file: 'package-lock.json' }],
+    },
+    {
+      id: '2',
+      type: 'npm',
+      name: 'lodash',
+      version: '4.17.20',
+      direct: true,
+      alerts: [
+        {
+          key: 'QmCve1',
+          type: 'cve',
+          severity: 'high',
+          props: {
+            cveId: 'CVE-2021-23337',
+            firstPatchedVersionIdentifier: '4.17.21',
+            title: 'Command Injection in lodash',
+            url: 'https://github.com/advisories/GHSA-35jh-r3h4-6jhm',
+            vulnerableVersionRange: '<4.17.21',
+          },
+        },
+      ],
+      manifestFiles: [{ file: 'package.json' }],
+    },
+  ] as SocketArtifact[]
+}
+
+describe('findScanAlert', () => {
+  it('finds alerts by ID or a unique prefix', () => {
+    const scan = getScan()
+    const exact = findScanAlert(scan, 'QmCve1')
+    expect(exact.ok && exact.data.artifact.name).toBe('lodash')
+    const prefix = findScanAlert(scan, 'QmE')
+    expect(prefix.ok && prefix.data.alert.type).toBe('envVars')
+  })
+
+  it('fails on unknown and ambiguous IDs', () => {
+    const scan = getScan()
+    expect(findScanAlert(scan, 'QmNope')).toMatchObject({
+      ok: false,
+      message: 'Alert not found',
+    })
+    expect(findScanAlert(scan, 'Qm')).toMatchObject({
+      ok: false,
+      message: 'Ambiguous alert ID',
+    })
+  })
+})
+
+describe('explainAlert', () => {
+  it('resolves the line in the installed package', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'node_modules/left-pad/package.json',
+          content: JSON.stringify({ name: 'left-pad', version: '1.3.0' }),
+        },
+        { path: 'node_modules/left-pad/index.js', content: SOURCE },
+      ],
+    })
+    try {
+      const [artifact] = getScan()
+      const explanation = explainAlert(artifact!, artifact!.alerts![0]!, {
+        cwd: workspace.path,
+        scanId: 'scan-ai-dee',
+      })
+
+      expect(explanation.location).toEqual({
+        file: 'package/index.js',
+        start: 27,
+        end: 50,
+        line: 2,
+        column: 15,
+        snippet: "const token = process.env['NPM_TOKEN']",
+        localPath: path.join(workspace.path, 'node_modules/left-pad/index.js'),
+      })
+      expect(explanation.details).toEqual([
+        { name: 'envVars', value: 'NPM_TOKEN' },
+      ])
+      expect(explanation.remediation).toContain(
+        'left-pad is a transitive dependency; upgrading or replacing the direct dependency that pulls it in also removes it, see `socket why left-pad@1.3.0`',
+      )
+      expect(explanation.links.map(l => l.label)).toEqual([
+        'Alert type',
+        'Package',
+        'File',
+      ])
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('keeps the offsets when the installed version differs', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'node_modules/left-pad/package.json',
+          content: JSON.stringify({ name: 'left-pad', version: '1.1.0' }),
+        },
+        { path: 'node_modules/left-pad/index.js', content: SOURCE },
+      ],
+    })
+    try {
+      const [artifact] = getScan()
+      const { location } = explainAlert(artifact!, artifact!.alerts![0]!, {
+        cwd: workspace.path,
+      })
+
+      expect(location).toEqual({
+        file: 'package/index.js',
+        start: 27,
+        end: 50,
+      })
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('explains CVEs with the advisory and patched version', () => {
+    const artifact = getScan()[1]!
+    const explanation = explainAlert(artifact, artifact.alerts![0]!)
+
+    expect(explanation).toMatchObject({
+      alertId: 'QmCve1',
+      title: 'Command Injection in lodash',
+      severity: 'high',
+      purl: 'pkg:npm/lodash@4.17.20',
+      manifest: ['package.json'],
+    })
+    expect(explanation.location).toBeUndefined()
+    expect(explanation.details).toEqual([
+      { name: 'cveId', value: 'CVE-2021-23337' },
+      { name: 'vulnerableVersionRange', value: '<4.17.21' },
+    ])
+    expect(explanation.remediation[0]).toBe(
+      'Upgrade lodash to 4.17.21 or later, `socket fix` can open the upgrade for you',
+    )
+    expect(explanation.remediation.at(-1)).toContain(
+      'socket scan view <SCAN_ID> --interactive',
+    )
+    expect(explanation.links.at(-1)).toEqual({
+      label: 'Advisory',
+      url: 'https://github.com/advisories/GHSA-35jh-r3h4-6jhm',
+    })
+  })
+})
@@ -0,0 +1,94 @@
+/**
+ * Unit tests for fix command.
+ *
+ * Tests that `socket fix --dry-run` only shows its plan. Nothing is uploaded
+ * and coana isn't run, so the real handler runs here with the SDK mocked.
+ * Command metadata and flag parsing cases live in cmd-fix.test.mts.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdFix } from '../../../../src/commands/fix/cmd-fix.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockUploadManifestFiles = vi.hoisted(() => vi.fn())
+const mockSetupSdk = vi.hoisted(() =>
+  vi.fn().mockResolvedValue({
+    ok: true,
+    data: { uploadManifestFiles: mockUploadManifestFiles },
+  }),
+)
+const mockSpawnCoanaDlx = vi.hoisted(() => vi.fn())
+const mockGetDefaultOrgSlug = vi.hoisted(() =>
+  vi.fn().mockResolvedValue({ ok: true, data: 'test-org' }),
+)
+
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), () => ({
+  setupSdk: mockSetupSdk,
+}))
+
+vi.mock(import('../../../../src/util/dlx/spawn.mjs'), () => ({
+  spawnCoanaDlx: mockSpawnCoanaDlx,
+}))
+
+vi.mock(
+  import('../../../../src/commands/ci/fetch-default-org-slug.mts'),
+  () => ({
+    getDefaultOrgSlug: mockGetDefaultOrgSlug,
+  }),
+)
+
+describe('cmd-fix --dry-run', () => {
+  const importMeta = { url: 'file:///test/cmd-fix.mts' }
+  const context = { parentName: 'socket' }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  it('does not upload the manifest files', async () => {
+    await cmdFix.run(['--dry-run'], importMeta, context)
+
+    expect(mockLogger.error).toHaveBeenCalledWith(
+      expect.stringContaining('DryRun'),
+    )
+    expect(mockLogger.error).toHaveBeenCalledWith(
+      expect.stringContaining(
+        '[upload] Upload manifest files to scan for vulnerabilities',
+      ),
+    )
+    expect(mockUploadManifestFiles).not.toHaveBeenCalled()
+    expect(mockSpawnCoanaDlx).not.toHaveBeenCalled()
+  })
+
+  it('does not upload the manifest files with --no-apply-fixes', async () => {
+    await cmdFix.run(['--dry-run', '--no-apply-fixes'], importMeta, context)
+
+    expect(mockUploadManifestFiles).not.toHaveBeenCalled()
+    expect(mockSpawnCoanaDlx).not.toHaveBeenCalled()
+  })
+})
@@ -119,6 +119,9 @@ describe('cmd-fix', () => {
           rangeStyle: 'preserve',
           showAffectedDirectDependencies: false,
           silence: false,
+          test: false,
+          testScript: 'test',
+          verify: false,
         }),
       )
     })
@@ -319,6 +322,33 @@ describe('cmd-fix', () => {
       )
     })
 
+    it('should pass --verify flag to handleFix', async () => {
+      await cmdFix.run(['--verify'], importMeta, context)
+
+      expect(mockHandleFix).toHaveBeenCalledWith(
+        expect.objectContaining({
+          test: false,
+          verify: true,
+        }),
+      )
+    })
+
+    it('should verify when --test is passed', async () => {
+      await cmdFix.run(
+        ['--test', '--test-script', 'test:unit'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleFix).toHaveBeenCalledWith(
+        expect.objectContaining({
+          test: true,
+          testScript: 'test:unit',
+          verify: true,
+        }),
+      )
+    })
+
     it('should pass --include flag to handleFix', async () => {
       await cmdFix.run(['--include', 'packages/*'], importMeta, context)
 
@@ -65,6 +65,7 @@ const mockSafeDelete = vi.hoisted(() => vi.fn())
 const mockReadFile = vi.hoisted(() => vi.fn())
 const mockWriteFile = vi.hoisted(() => vi.fn())
 const mockLogPrEvent = vi.hoisted(() => vi.fn())
+const mockVerifyFix = vi.hoisted(() => vi.fn())
 
 vi.mock(import('../../../../src/util/dlx/spawn.mjs'), () => ({
   spawnCoanaDlx: mockSpawnCoanaDlx,
@@ -115,6 +116,7 @@ vi.mock(import('../../../../src/util/git/operations.mjs'), () => ({
   gitPushBranch: mockGitPushBranch,
   gitRemoteBranchExists: mockGitRemoteBranchExists,
   gitResetAndClean: mockGitResetAndClean,
+  gitShowFile: vi.fn(),
   gitUnstagedModifiedFiles: mockGitUnstagedModifiedFiles,
 }))
 
@@ -134,6 +136,10 @@ vi.mock(import('../../../../src/commands/fix/pr-lifecycle-logger.mts'), () => ({
   logPrEvent: mockLogPrEvent,
 }))
 
+vi.mock(import('../../../../src/commands/fix/verify-fix.mts'), () => ({
+  verifyFix: mockVerifyFix,
+}))
+
 vi.mock(import('@socketsecurity/lib-stable/fs/read-json'), () => ({
   readJsonSync: mockReadJsonSync,
 }))
@@ -323,6 +329,35 @@ describe('coanaFix (coverage)', () => {
       }
     })
 
+    it('skips the PR and resets when verification fails', async () => {
+      mockSpawnCoanaDlx.mockResolvedValueOnce({ ok: true, data: 'applied' })
+      mockVerifyFix.mockResolvedValueOnce({
+        ok: false,
+        message: 'Install verification failed',
+        cause: 'npm install failed after applying the fix.',
+      })
+
+      const result = await coanaFix({
+        ...baseConfig,
+        ghsas: ['all'],
+        prLimit: 1,
+        test: true,
+        testScript: 'test',
+        verify: true,
+      })
+      expect(mockVerifyFix).toHaveBeenCalledWith(
+        '/test/cwd',
+        expect.objectContaining({ test: true, testScript: 'test' }),
+      )
+      expect(mockGitResetAndClean).toHaveBeenCalledWith('main', '/test/cwd')
+      expect(mockGitCreateBranch).not.toHaveBeenCalled()
+      expect(mockOpenSocketFixPr).not.toHaveBeenCalled()
+      expect(result.ok).toBe(true)
+      if (result.ok) {
+        expect(result.data.fixedAll).toBe(false)
+      }
+    })
+
     it('continues with empty modified files when gitUnstagedModifiedFiles returns ok:false', async () => {
       mockSpawnCoanaDlx.mockResolvedValueOnce({ ok: true, data: 'applied' })
       mockGitUnstagedModifiedFiles.mockResolvedValue({
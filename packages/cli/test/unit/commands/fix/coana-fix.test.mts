@@ -116,6 +116,7 @@ vi.mock(import('../../../../src/util/git/operations.mjs'), () => ({
   gitPushBranch: mockGitPushBranch,
   gitRemoteBranchExists: mockGitRemoteBranchExists,
   gitResetAndClean: mockGitResetAndClean,
+  gitShowFile: vi.fn(),
   gitUnstagedModifiedFiles: mockGitUnstagedModifiedFiles,
 }))
 
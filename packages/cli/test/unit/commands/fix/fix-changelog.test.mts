@@ -0,0 +1,193 @@
+/**
+ * Unit tests for the changelog of `socket fix`.
+ *
+ * Purpose: Tests that the dependency versions changed by a fix are read from
+ * manifests and lockfiles and listed for the terminal and pull requests.
+ *
+ * Test Coverage: - package.json ranges and overrides - package-lock.json and
+ * yarn.lock versions - Diffing added, removed and changed packages - Reading
+ * the previous content from git - Terminal and Markdown formatting.
+ *
+ * Related Files: - src/commands/fix/fix-changelog.mts (implementation) -
+ * src/commands/fix/git.mts - Pull request body.
+ */
+
+import { mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  diffDependencyVersions,
+  formatFixChangelog,
+  formatFixChangelogMarkdown,
+  getDependencyVersions,
+  getFixChangelog,
+} from '../../../../src/commands/fix/fix-changelog.mts'
+
+const mockGitShowFile = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/util/git/operations.mjs'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    gitShowFile: mockGitShowFile,
+  }),
+)
+
+function versionsOf(filepath: string, content: string) {
+  const versions = getDependencyVersions(filepath, content)
+  return versions
+    ? Object.fromEntries([...versions].map(({ 0: k, 1: v }) => [k, [...v]]))
+    : undefined
+}
+
+describe('getDependencyVersions', () => {
+  it('reads the ranges and overrides of a package.json', () => {
+    const content = JSON.stringify({
+      dependencies: { lodash: '^4.17.20' },
+      devDependencies: { vitest: '^1.0.0' },
+      overrides: { foo: { bar: '1.2.3' } },
+      pnpm: { overrides: { qs: '6.11.0' } },
+    })
+
+    expect(versionsOf('packages/a/package.json', content)).toEqual({
+      'foo>bar': ['1.2.3'],
+      lodash: ['^4.17.20'],
+      qs: ['6.11.0'],
+      vitest: ['^1.0.0'],
+    })
+  })
+
+  it('reads the resolved versions of a package-lock.json', () => {
+    const content = JSON.stringify({
+      lockfileVersion: 3,
+      packages: {
+        '': { name: 'app' },
+        'node_modules/lodash': { version: '4.17.20' },
+        'node_modules/a/node_modules/lodash': { version: '3.10.1' },
+        'node_modules/@scope/pkg': { version: '1.0.0' },
+      },
+    })
+
+    expect(versionsOf('package-lock.json', content)).toEqual({
+      '@scope/pkg': ['1.0.0'],
+      lodash: ['4.17.20', '3.10.1'],
+    })
+  })
+
+  it('reads the resolved versions of a yarn.lock', () => {
+    const content = `# yarn lockfile v1
+
+lodash@^4.17.20, lodash@^4.17.0:
+  version "4.17.21"
+  resolved "https://registry.yarnpkg.com/lodash/-/lodash-4.17.21.tgz"
+`
+
+    expect(versionsOf('yarn.lock', content)).toEqual({
+      lodash: ['4.17.21'],
+    })
+  })
+
+  it('returns undefined for files it does not understand', () => {
+    expect(getDependencyVersions('package.json', '{')).toBeUndefined()
+    expect(getDependencyVersions('README.md', '# app')).toBeUndefined()
+  })
+})
+
+describe('diffDependencyVersions', () => {
+  it('lists changed, added and removed packages by name', () => {
+    const before = new Map([
+      ['lodash', new Set(['4.17.20'])],
+      ['left-pad', new Set(['1.0.0'])],
+      ['qs', new Set(['6.11.0'])],
+    ])
+    const after = new Map([
+      ['lodash', new Set(['4.17.21'])],
+      ['minimist', new Set(['1.2.8'])],
+      ['qs', new Set(['6.11.0'])],
+    ])
+
+    const file = 'package-lock.json'
+
+    expect(diffDependencyVersions(file, before, after)).toEqual([
+      { file, from: ['1.0.0'], name: 'left-pad', to: [] },
+      { file, from: ['4.17.20'], name: 'lodash', to: ['4.17.21'] },
+      { file, from: [], name: 'minimist', to: ['1.2.8'] },
+    ])
+  })
+})
+
+describe('getFixChangelog', () => {
+  let tmpDir: string
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    tmpDir = path.resolve(mkdtempSync(path.join(os.tmpdir(), 'socket-test-')))
+  })
+
+  afterEach(async () => {
+    await safeDelete(tmpDir, { recursive: true })
+  })
+
+  it('compares the working tree to HEAD', async () => {
+    writeFileSync(
+      path.join(tmpDir, 'package.json'),
+      JSON.stringify({ dependencies: { lodash: '^4.17.21' } }),
+      'utf8',
+    )
+    mockGitShowFile.mockResolvedValue(
+      JSON.stringify({ dependencies: { lodash: '^4.17.20' } }),
+    )
+
+    const changes = await getFixChangelog(tmpDir, ['package.json', ''])
+
+    expect(mockGitShowFile).toHaveBeenCalledWith('package.json', 'HEAD', tmpDir)
+    expect(changes).toEqual([
+      {
+        file: 'package.json',
+        from: ['^4.17.20'],
+        name: 'lodash',
+        to: ['^4.17.21'],
+      },
+    ])
+  })
+
+  it('skips files that are missing at HEAD', async () => {
+    writeFileSync(path.join(tmpDir, 'package.json'), '{}', 'utf8')
+    mockGitShowFile.mockResolvedValue(undefined)
+
+    expect(await getFixChangelog(tmpDir, ['package.json'])).toEqual([])
+  })
+})
+
+describe('formatFixChangelog', () => {
+  const changes = [
+    {
+      file: 'package.json',
+      from: ['^1.0.0 || ^2.0.0'],
+      name: 'a',
+      to: ['^2.1.0'],
+    },
+    { file: 'yarn.lock', from: [], name: 'b', to: ['1.0.0'] },
+  ]
+
+  it('formats a line per change', () => {
+    expect(formatFixChangelog(changes)).toEqual([
+      'a: ^1.0.0 || ^2.0.0 → ^2.1.0 (package.json)',
+      'b: (none) → 1.0.0 (yarn.lock)',
+    ])
+  })
+
+  it('formats a Markdown table with escaped pipes', () => {
+    expect(formatFixChangelogMarkdown(changes).split('\n')).toEqual([
+      '| Package | From | To | File |',
+      '| --- | --- | --- | --- |',
+      '| `a` | ^1.0.0 \\|\\| ^2.0.0 | ^2.1.0 | package.json |',
+      '| `b` | (none) | 1.0.0 | yarn.lock |',
+    ])
+  })
+})
@@ -328,5 +328,28 @@ describe('fix/git utilities', () => {
       const matches = body.match(/pkg \(NPM\)/g)
       expect(matches).toHaveLength(1)
     })
+
+    it('appends a table of the dependency changes', () => {
+      const body = getSocketFixPullRequestBody(
+        ['GHSA-1234-5678-9abc'],
+        undefined,
+        [
+          {
+            file: 'package.json',
+            from: ['^4.17.20'],
+            name: 'lodash',
+            to: ['^4.17.21'],
+          },
+        ],
+      )
+      expect(body.split('\n').slice(1)).toEqual([
+        '',
+        '**Changes:**',
+        '',
+        '| Package | From | To | File |',
+        '| --- | --- | --- | --- |',
+        '| `lodash` | ^4.17.20 | ^4.17.21 | package.json |',
+      ])
+    })
   })
 })
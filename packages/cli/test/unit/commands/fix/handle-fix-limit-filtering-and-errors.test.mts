@@ -84,6 +84,7 @@ vi.mock(import('../../../../src/util/git/operations.mjs'), () => ({
   gitPushBranch: vi.fn(() => Promise.resolve(true)),
   gitRemoteBranchExists: vi.fn(() => Promise.resolve(false)),
   gitResetAndClean: vi.fn(() => Promise.resolve(true)),
+  gitShowFile: vi.fn(),
   gitUnstagedModifiedFiles: mockGitUnstagedModifiedFiles,
 }))
 
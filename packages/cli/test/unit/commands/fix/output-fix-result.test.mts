@@ -137,6 +137,30 @@ describe('output-fix-result', () => {
         expect(process.exitCode).toBeUndefined()
       })
 
+      it('lists the dependency changes', async () => {
+        const result: CResult<unknown> = {
+          ok: true,
+          data: {
+            changes: [
+              {
+                file: 'package.json',
+                from: ['^4.17.20'],
+                name: 'lodash',
+                to: ['^4.17.21'],
+              },
+            ],
+          },
+        }
+
+        await outputFixResult(result, 'text')
+
+        expect(mockLogger.log).toHaveBeenCalledWith('Dependency changes:')
+        expect(mockLogger.log).toHaveBeenCalledWith(
+          '  lodash: ^4.17.20 → ^4.17.21 (package.json)',
+        )
+        expect(mockLogger.success).toHaveBeenCalledWith('Finished!')
+      })
+
       it('outputs error with fail message', async () => {
         const result: CResult<unknown> = {
           ok: false,
@@ -492,6 +492,7 @@ describe('pull-request', () => {
       expect(mockGetSocketFixPullRequestBody).toHaveBeenCalledWith(
         ['GHSA-details-test'],
         mockGhsaDetails,
+        undefined,
       )
     })
   })
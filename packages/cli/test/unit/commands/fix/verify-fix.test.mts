@@ -0,0 +1,131 @@
+/**
+ * Unit tests for verifying applied fixes.
+ *
+ * Purpose: Tests that `socket fix --verify` installs the fixed dependencies
+ * and, with --test, runs the test script, failing with a clear message when
+ * either step fails.
+ *
+ * Test Coverage: - Skipping projects without a package.json - Package
+ * environment errors - Install failures - Test script runs and failures.
+ *
+ * Related Files: - src/commands/fix/verify-fix.mts (implementation) -
+ * src/commands/optimize/agent-installer.mts - Install command.
+ */
+
+import { mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import { verifyFix } from '../../../../src/commands/fix/verify-fix.mts'
+
+const mockDetectAndValidatePackageEnvironment = vi.hoisted(() => vi.fn())
+const mockRunAgentInstall = vi.hoisted(() => vi.fn())
+const mockSpawn = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/ecosystem/environment.mjs'), () => ({
+  detectAndValidatePackageEnvironment: mockDetectAndValidatePackageEnvironment,
+}))
+
+vi.mock(import('../../../../src/commands/optimize/agent-installer.mts'), () => ({
+  runAgentInstall: mockRunAgentInstall,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/process/spawn/child'), () => ({
+  spawn: mockSpawn,
+}))
+
+describe('verifyFix', () => {
+  let tmpDir: string
+  let pkgEnvDetails: Record<string, unknown>
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    tmpDir = path.resolve(mkdtempSync(path.join(os.tmpdir(), 'socket-test-')))
+    pkgEnvDetails = {
+      agent: 'npm',
+      agentExecPath: '/usr/bin/npm',
+      pkgPath: tmpDir,
+    }
+    mockDetectAndValidatePackageEnvironment.mockResolvedValue({
+      ok: true,
+      data: pkgEnvDetails,
+    })
+    mockRunAgentInstall.mockResolvedValue(undefined)
+    mockSpawn.mockResolvedValue(undefined)
+  })
+
+  afterEach(async () => {
+    await safeDelete(tmpDir, { recursive: true })
+  })
+
+  it('skips projects without a package.json', async () => {
+    const result = await verifyFix(tmpDir)
+
+    expect(result).toEqual({ ok: true, data: false })
+    expect(mockDetectAndValidatePackageEnvironment).not.toHaveBeenCalled()
+  })
+
+  describe('with a package.json', () => {
+    beforeEach(() => {
+      writeFileSync(path.join(tmpDir, 'package.json'), '{}', 'utf8')
+    })
+
+    it('runs the package manager install', async () => {
+      const result = await verifyFix(tmpDir, { stdio: 'inherit' })
+
+      expect(result).toEqual({ ok: true, data: true })
+      expect(mockRunAgentInstall).toHaveBeenCalledWith(
+        pkgEnvDetails,
+        expect.objectContaining({ stdio: 'inherit' }),
+      )
+      expect(mockSpawn).not.toHaveBeenCalled()
+    })
+
+    it('returns package environment errors', async () => {
+      const error = { ok: false, message: 'Missing lockfile' }
+      mockDetectAndValidatePackageEnvironment.mockResolvedValue(error)
+
+      expect(await verifyFix(tmpDir)).toBe(error)
+      expect(mockRunAgentInstall).not.toHaveBeenCalled()
+    })
+
+    it('fails when the install fails', async () => {
+      mockRunAgentInstall.mockRejectedValue(new Error('ERESOLVE'))
+
+      const result = await verifyFix(tmpDir, { test: true })
+
+      expect(result.ok).toBe(false)
+      expect(!result.ok && result.message).toBe('Install verification failed')
+      expect(!result.ok && result.cause).toContain('npm install failed')
+      expect(mockSpawn).not.toHaveBeenCalled()
+    })
+
+    it('runs the test script with --test', async () => {
+      const result = await verifyFix(tmpDir, {
+        test: true,
+        testScript: 'test:unit',
+      })
+
+      expect(result).toEqual({ ok: true, data: true })
+      expect(mockSpawn).toHaveBeenCalledWith(
+        '/usr/bin/npm',
+        ['run', 'test:unit'],
+        expect.objectContaining({ cwd: tmpDir }),
+      )
+    })
+
+    it('fails when the test script fails', async () => {
+      mockSpawn.mockRejectedValue(new Error('exit code 1'))
+
+      const result = await verifyFix(tmpDir, { test: true })
+
+      expect(result.ok).toBe(false)
+      expect(!result.ok && result.message).toBe('Test verification failed')
+      expect(!result.ok && result.cause).toContain('npm run test failed')
+    })
+  })
+})
@@ -0,0 +1,252 @@
+/**
+ * Unit tests for the repository sync of `socket ghapp sync`.
+ *
+ * Tests scanning the installation repositories through the GitHub API,
+ * leaving repositories alone whose latest scan is of the current commit,
+ * skipping archived ones, and stopping when GitHub rejects the installation.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import type { Octokit } from '@octokit/rest'
+
+const mockPaginate = vi.hoisted(() => vi.fn())
+const mockSetOctokitAuth = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/git/github.mts'), async importOriginal => {
+  const actual = await importOriginal()
+  return {
+    ...actual,
+    getOctokit: () =>
+      ({
+        apps: { listReposAccessibleToInstallation: vi.fn() },
+        paginate: mockPaginate,
+      }) as unknown as Octokit,
+    setOctokitAuth: mockSetOctokitAuth,
+  }
+})
+
+const mockFetchInstallationToken = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/util/git/github-app.mts'),
+  async importOriginal => {
+    const actual = await importOriginal()
+    return {
+      ...actual,
+      fetchGitHubAppInstallationToken: mockFetchInstallationToken,
+    }
+  },
+)
+
+const mockGetLastCommitDetails = vi.hoisted(() => vi.fn())
+const mockGetRepoBranchTree = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/scan/create-scan-from-github-api.mts'),
+  () => ({
+    getLastCommitDetails: mockGetLastCommitDetails,
+    getRepoBranchTree: mockGetRepoBranchTree,
+  }),
+)
+
+const mockFetchOrgFullScanList = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/scan/fetch-list-scans.mts'), () => ({
+  fetchOrgFullScanList: mockFetchOrgFullScanList,
+}))
+
+vi.mock(
+  import('../../../../src/commands/scan/fetch-supported-scan-file-names.mts'),
+  () => ({
+    fetchSupportedScanFileNames: async () => ({ ok: true, data: {} }),
+  }),
+)
+
+const mockTestAndDownloadManifestFiles = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/scan/github-scan-manifest.mts'),
+  () => ({
+    testAndDownloadManifestFiles: mockTestAndDownloadManifestFiles,
+  }),
+)
+
+const mockFetchCreateOrgFullScan = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/scan/fetch-create-org-full-scan.mts'),
+  () => ({
+    fetchCreateOrgFullScan: mockFetchCreateOrgFullScan,
+  }),
+)
+
+vi.mock(import('../../../../src/util/fs/path-resolve.mts'), () => ({
+  getPackageFilesForScan: async () => ['package.json'],
+}))
+
+import { syncGitHubAppRepos } from '../../../../src/commands/ghapp/ghapp-sync.mts'
+
+const config = {
+  credentials: { appId: '12345', privateKey: 'pem' },
+  force: false,
+  orgSlug: 'acme',
+  repoNames: [] as string[],
+}
+
+function installationRepo(name: string, extra: Record<string, unknown> = {}) {
+  return {
+    default_branch: 'main',
+    full_name: `acme/${name}`,
+    name,
+    owner: { login: 'acme' },
+    size: 1,
+    ...extra,
+  }
+}
+
+describe('syncGitHubAppRepos', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFetchInstallationToken.mockResolvedValue({
+      ok: true,
+      data: {
+        account: 'acme',
+        expiresAt: Date.now() + 60 * 60 * 1000,
+        installationId: 7,
+        token: 'ghs_token',
+      },
+    })
+    mockPaginate.mockResolvedValue([installationRepo('api')])
+    mockGetLastCommitDetails.mockResolvedValue({
+      ok: true,
+      data: {
+        lastCommitMessage: 'Fix it',
+        lastCommitSha: 'abc123',
+        lastCommitter: 'jdoe',
+      },
+    })
+    mockFetchOrgFullScanList.mockResolvedValue({
+      ok: true,
+      data: { results: [{ commit_hash: 'old456' }] },
+    })
+    mockGetRepoBranchTree.mockResolvedValue({
+      ok: true,
+      data: ['package.json'],
+    })
+    mockTestAndDownloadManifestFiles.mockResolvedValue({
+      ok: true,
+      data: undefined,
+    })
+    mockFetchCreateOrgFullScan.mockResolvedValue({
+      ok: true,
+      data: { html_report_url: 'https://socket.dev/report', id: 'scan-1' },
+    })
+  })
+
+  it('scans the default branch of the installation repositories', async () => {
+    const result = await syncGitHubAppRepos(config)
+
+    expect(mockSetOctokitAuth).toHaveBeenCalledWith('ghs_token')
+    expect(mockFetchCreateOrgFullScan).toHaveBeenCalledWith(
+      ['package.json'],
+      'acme',
+      expect.objectContaining({
+        branchName: 'main',
+        commitHash: 'abc123',
+        commitMessage: 'Fix it',
+        committers: 'jdoe',
+        repoName: 'api',
+      }),
+      expect.objectContaining({ defaultBranch: true, pendingHead: true }),
+    )
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        account: 'acme',
+        installationId: 7,
+        repos: [
+          {
+            branch: 'main',
+            commitHash: 'abc123',
+            repo: 'acme/api',
+            reportUrl: 'https://socket.dev/report',
+            scanId: 'scan-1',
+            status: 'created',
+          },
+        ],
+      },
+    })
+    // Later GitHub calls must not go out as the installation.
+    expect(mockSetOctokitAuth).toHaveBeenLastCalledWith(undefined)
+  })
+
+  it('leaves repositories scanned at the current commit', async () => {
+    mockFetchOrgFullScanList.mockResolvedValue({
+      ok: true,
+      data: { results: [{ commit_hash: 'abc123' }] },
+    })
+
+    const result = await syncGitHubAppRepos(config)
+
+    expect(mockFetchCreateOrgFullScan).not.toHaveBeenCalled()
+    expect(result.ok && result.data.repos[0]!.status).toBe('current')
+  })
+
+  it('scans again with force', async () => {
+    mockFetchOrgFullScanList.mockResolvedValue({
+      ok: true,
+      data: { results: [{ commit_hash: 'abc123' }] },
+    })
+
+    const result = await syncGitHubAppRepos({ ...config, force: true })
+
+    expect(mockFetchOrgFullScanList).not.toHaveBeenCalled()
+    expect(result.ok && result.data.repos[0]!.status).toBe('created')
+  })
+
+  it('skips archived repositories and filters by name', async () => {
+    mockPaginate.mockResolvedValue([
+      installationRepo('api', { archived: true }),
+      installationRepo('web'),
+    ])
+
+    const result = await syncGitHubAppRepos({ ...config, repoNames: ['api'] })
+
+    expect(mockGetLastCommitDetails).not.toHaveBeenCalled()
+    expect(result.ok && result.data.repos).toEqual([
+      {
+        branch: 'main',
+        reason: 'Archived or empty',
+        repo: 'acme/api',
+        status: 'skipped',
+      },
+    ])
+  })
+
+  it('stops when GitHub rejects the installation token', async () => {
+    mockPaginate.mockResolvedValue([
+      installationRepo('api'),
+      installationRepo('web'),
+    ])
+    mockGetLastCommitDetails.mockResolvedValue({
+      ok: false,
+      message: 'GitHub authentication failed',
+      cause: 'Bad credentials',
+    })
+
+    const result = await syncGitHubAppRepos(config)
+
+    expect(mockGetLastCommitDetails).toHaveBeenCalledTimes(1)
+    expect(result).toEqual({
+      ok: false,
+      message: 'GitHub authentication failed',
+      cause:
+        'Stopped at acme/api after 0 of 2 repositories: GitHub authentication failed: Bad credentials',
+    })
+  })
+
+  it('passes on failing to fetch the installation token', async () => {
+    const failure = { ok: false, message: 'Invalid GitHub App private key' }
+    mockFetchInstallationToken.mockResolvedValue(failure)
+
+    const result = await syncGitHubAppRepos(config)
+
+    expect(result).toBe(failure)
+    expect(mockPaginate).not.toHaveBeenCalled()
+  })
+})
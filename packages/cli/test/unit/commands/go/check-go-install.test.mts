@@ -0,0 +1,172 @@
+/**
+ * Unit tests for the socket go pre-install check.
+ *
+ * Purpose: Tests which module queries `go get` and `go install` invocations
+ * make and how they are resolved and scored before handing off to Socket
+ * Firewall.
+ *
+ * Test Coverage: - Queries with and without versions - Build flags before
+ * packages - Local, standard library, toolchain and removal arguments are
+ * skipped - GOPROXY=direct and private modules skip the lookup - Lookup
+ * failure falls through with a warning - Resolved purls are passed to the
+ * install check.
+ *
+ * Related Files: - src/commands/go/check-go-install.mts (implementation) -
+ * src/util/go/module-proxy.mts (module resolution)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  checkGoInstall,
+  getGoInstallQueries,
+} from '../../../../src/commands/go/check-go-install.mts'
+
+const mockGetGoProxyConfig = vi.hoisted(() => vi.fn())
+const mockResolveGoModule = vi.hoisted(() => vi.fn())
+const mockCheckPackagesBeforeInstall = vi.hoisted(() => vi.fn())
+const mockWarn = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/util/go/module-proxy.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    getGoProxyConfig: mockGetGoProxyConfig,
+    resolveGoModule: mockResolveGoModule,
+  }),
+)
+
+vi.mock(import('../../../../src/util/install-check/check.mts'), () => ({
+  checkPackagesBeforeInstall: mockCheckPackagesBeforeInstall,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => ({ warn: mockWarn }),
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/spinner/default'), () => ({
+  getDefaultSpinner: () => ({ start: vi.fn(), stop: vi.fn() }),
+}))
+
+const PROXY = 'https://proxy.golang.org'
+
+describe('getGoInstallQueries', () => {
+  it('collects module queries for go get', () => {
+    expect(
+      getGoInstallQueries([
+        'get',
+        '-u',
+        '-tags',
+        'netgo',
+        'github.com/gin-gonic/gin@v1.9.1',
+        'golang.org/x/sync',
+      ]),
+    ).toEqual([
+      { path: 'github.com/gin-gonic/gin', query: 'v1.9.1' },
+      { path: 'golang.org/x/sync', query: 'upgrade' },
+    ])
+  })
+
+  it('requires a version for go install', () => {
+    expect(
+      getGoInstallQueries([
+        'install',
+        'golang.org/x/tools/cmd/goimports@latest',
+        'github.com/acme/tool',
+      ]),
+    ).toEqual([{ path: 'golang.org/x/tools/cmd/goimports', query: 'latest' }])
+  })
+
+  it('skips arguments that do not name a remote module', () => {
+    expect(
+      getGoInstallQueries([
+        'get',
+        './...',
+        'fmt',
+        'go@1.22.0',
+        'toolchain@none',
+        'github.com/old/dep@none',
+        'github.com/x/y@<v2',
+      ]),
+    ).toBeUndefined()
+    expect(getGoInstallQueries(['mod', 'download'])).toBeUndefined()
+    expect(getGoInstallQueries(['get', '-h'])).toBeUndefined()
+  })
+})
+
+describe('checkGoInstall', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('does nothing for other go commands', async () => {
+    const result = await checkGoInstall(['build', './...'], 'go')
+
+    expect(result.ok).toBe(true)
+    expect(mockGetGoProxyConfig).not.toHaveBeenCalled()
+  })
+
+  it('warns and proceeds when no proxy is configured', async () => {
+    mockGetGoProxyConfig.mockResolvedValue({
+      ok: true,
+      data: { noProxy: [], proxyUrl: undefined },
+    })
+
+    const result = await checkGoInstall(['get', 'example.com/x'], 'go')
+
+    expect(result.ok).toBe(true)
+    expect(mockWarn).toHaveBeenCalledWith(
+      expect.stringContaining('GOPROXY does not name a module proxy'),
+    )
+    expect(mockResolveGoModule).not.toHaveBeenCalled()
+  })
+
+  it('warns and proceeds when the proxy lookup fails', async () => {
+    mockGetGoProxyConfig.mockResolvedValue({
+      ok: true,
+      data: { noProxy: [], proxyUrl: PROXY },
+    })
+    mockResolveGoModule.mockResolvedValue({
+      ok: false,
+      message: 'Unable to resolve Go module',
+    })
+
+    const result = await checkGoInstall(['get', 'example.com/x'], 'go')
+
+    expect(result.ok).toBe(true)
+    expect(mockCheckPackagesBeforeInstall).not.toHaveBeenCalled()
+  })
+
+  it('checks resolved public modules', async () => {
+    mockGetGoProxyConfig.mockResolvedValue({
+      ok: true,
+      data: { noProxy: ['github.com/acme'], proxyUrl: PROXY },
+    })
+    mockResolveGoModule.mockResolvedValue({
+      ok: true,
+      data: { path: 'golang.org/x/tools', version: 'v0.22.0' },
+    })
+    mockCheckPackagesBeforeInstall.mockResolvedValue({ ok: true, data: {} })
+
+    const result = await checkGoInstall(
+      [
+        'install',
+        'golang.org/x/tools/cmd/goimports@latest',
+        'github.com/acme/tool@v1.0.0',
+      ],
+      'go',
+    )
+
+    expect(result.ok).toBe(true)
+    expect(mockResolveGoModule).toHaveBeenCalledTimes(1)
+    expect(mockResolveGoModule).toHaveBeenCalledWith(
+      PROXY,
+      'golang.org/x/tools/cmd/goimports',
+      'latest',
+    )
+    expect(mockCheckPackagesBeforeInstall).toHaveBeenCalledWith(
+      ['pkg:golang/golang.org/x/tools@v0.22.0'],
+      { commandPath: 'socket go' },
+    )
+  })
+})
@@ -39,6 +39,14 @@ vi.mock(import('../../../../src/util/cli/with-subcommands.mjs'), () => ({
   meowOrExit: mockMeowOrExit,
 }))
 
+vi.mock(
+  import('../../../../src/commands/go/check-go-install.mts'),
+  async () => {
+    const { passingInstallCheck } = await import('../../../helpers/mocks.mts')
+    return passingInstallCheck('checkGoInstall')
+  },
+)
+
 vi.mock(import('../../../../src/util/process/cmd.mts'), () => ({
   filterFlags: mockFilterFlags,
 }))
@@ -0,0 +1,150 @@
+/**
+ * Unit tests for graph export command.
+ *
+ * Tests the command that exports the dependency graph of a completed scan.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdGraphExport } from '../../../../src/commands/graph/cmd-graph-export.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+import type * as SdkModule from '../../../../src/util/socket/sdk.mts'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockHandleGraphExport = vi.hoisted(() => vi.fn())
+const mockDetermineOrgSlug = vi.hoisted(() =>
+  vi.fn().mockResolvedValue(['test-org', 'test-org']),
+)
+const mockHasDefaultApiToken = vi.hoisted(() => vi.fn().mockReturnValue(true))
+
+vi.mock(
+  import('../../../../src/commands/graph/handle-graph-export.mts'),
+  () => ({
+    handleGraphExport: mockHandleGraphExport,
+  }),
+)
+
+vi.mock(import('../../../../src/util/socket/org-slug.mts'), () => ({
+  determineOrgSlug: mockDetermineOrgSlug,
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mts'), async importOriginal => {
+  const actual = await importOriginal<typeof SdkModule>()
+  return {
+    ...actual,
+    hasDefaultApiToken: mockHasDefaultApiToken,
+  }
+})
+
+describe('cmd-graph-export', () => {
+  const importMeta = { url: 'file:///test/cmd-graph-export.mts' }
+  const importMeta = { url: 'file:///test/cmd-graph-export.mts' }
+  const context = { parentName: 'socket graph' }
+  const testScanId = '000aaaa1-0000-0a0a-00a0-00a0000000a0'
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  it('should not be hidden', () => {
+    expect(cmdGraphExport.hidden).toBe(false)
+  })
+
+  it('should export dot to stdout by default', async () => {
+    await cmdGraphExport.run([testScanId], importMeta, context)
+
+    expect(mockHandleGraphExport).toHaveBeenCalledWith({
+      filepath: '',
+      format: 'dot',
+      orgSlug: 'test-org',
+      outputKind: 'text',
+      scanId: testScanId,
+    })
+  })
+
+  it('should infer graphml from the output file', async () => {
+    await cmdGraphExport.run(
+      [testScanId, './deps.graphml'],
+      importMeta,
+      context,
+    )
+
+    expect(mockHandleGraphExport).toHaveBeenCalledWith(
+      expect.objectContaining({
+        filepath: './deps.graphml',
+        format: 'graphml',
+      }),
+    )
+  })
+
+  it('should prefer --format over the output file extension', async () => {
+    await cmdGraphExport.run(
+      [testScanId, '--format', 'graphml', './deps.xml'],
+      importMeta,
+      context,
+    )
+
+    expect(mockHandleGraphExport).toHaveBeenCalledWith(
+      expect.objectContaining({ filepath: './deps.xml', format: 'graphml' }),
+    )
+  })
+
+  it('should fail without scan ID', async () => {
+    await cmdGraphExport.run([], importMeta, context)
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleGraphExport).not.toHaveBeenCalled()
+  })
+
+  it('should fail on an unsupported format', async () => {
+    await cmdGraphExport.run(
+      [testScanId, '--format', 'gexf'],
+      importMeta,
+      context,
+    )
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleGraphExport).not.toHaveBeenCalled()
+  })
+
+  it('should fail without Socket API token', async () => {
+    mockHasDefaultApiToken.mockReturnValueOnce(false)
+
+    await cmdGraphExport.run([testScanId], importMeta, context)
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleGraphExport).not.toHaveBeenCalled()
+  })
+
+  it('should support --dry-run flag', async () => {
+    await cmdGraphExport.run(['--dry-run', testScanId], importMeta, context)
+
+    expect(mockHandleGraphExport).not.toHaveBeenCalled()
+    expect(mockLogger.error).toHaveBeenCalledWith(
+      expect.stringContaining('DryRun'),
+    )
+  })
+})
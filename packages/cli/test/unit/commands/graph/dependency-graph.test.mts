@@ -0,0 +1,133 @@
+/**
+ * Unit tests for the scan dependency graph.
+ *
+ * Tests building nodes and edges from scan artifacts and rendering them as
+ * DOT and GraphML.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  buildDependencyGraph,
+  renderDot,
+  renderGraphMl,
+} from '../../../../src/commands/graph/dependency-graph.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+describe('buildDependencyGraph', () => {
+  it('links the scan to direct dependencies and follows dependencies', () => {
+    const graph = buildDependencyGraph(
+      [
+        artifact('app-lib', { id: 'a', dependencies: ['b'], direct: true }),
+        artifact('left-pad', { id: 'b' }),
+      ],
+      'scan-1',
+    )
+
+    expect(graph.hasGraph).toBe(true)
+    expect(graph.root).toMatchObject({
+      id: 'scan:scan-1',
+      label: 'scan scan-1',
+    })
+    expect(graph.nodes.map(n => n.id)).toEqual([
+      'pkg:npm/app-lib@1.0.0',
+      'pkg:npm/left-pad@1.0.0',
+    ])
+    expect(graph.edges).toEqual([
+      ['pkg:npm/app-lib@1.0.0', 'pkg:npm/left-pad@1.0.0'],
+      ['scan:scan-1', 'pkg:npm/app-lib@1.0.0'],
+    ])
+  })
+
+  it('falls back to top level ancestors without dependency edges', () => {
+    const graph = buildDependencyGraph(
+      [
+        artifact('app-lib', { id: 'a', direct: true }),
+        artifact('left-pad', { id: 'b', topLevelAncestors: ['a'] }),
+      ],
+      'scan-1',
+    )
+
+    expect(graph.hasGraph).toBe(false)
+    expect(graph.edges).toEqual([
+      ['pkg:npm/app-lib@1.0.0', 'pkg:npm/left-pad@1.0.0'],
+      ['scan:scan-1', 'pkg:npm/app-lib@1.0.0'],
+    ])
+  })
+
+  it('merges alerts of a repeated package and keeps the worst severity', () => {
+    const graph = buildDependencyGraph(
+      [
+        artifact('evil', {
+          id: 'a',
+          alerts: [{ severity: 'low', type: 'unmaintained' }],
+        }),
+        artifact('evil', {
+          id: 'b',
+          alerts: [
+            { severity: 'critical', type: 'malware' },
+            { severity: 'low', type: 'unmaintained' },
+          ],
+        }),
+      ],
+      'scan-1',
+    )
+
+    expect(graph.nodes).toEqual([
+      {
+        alerts: ['malware', 'unmaintained'],
+        direct: false,
+        id: 'pkg:npm/evil@1.0.0',
+        label: 'pkg:npm/evil@1.0.0',
+        severity: 'critical',
+      },
+    ])
+  })
+})
+
+describe('renderDot', () => {
+  it('annotates alerted nodes and escapes quotes', () => {
+    const dot = renderDot(
+      buildDependencyGraph(
+        [
+          artifact('evil', {
+            id: 'a',
+            alerts: [{ severity: 'high', type: 'malware' }],
+            direct: true,
+          }),
+        ],
+        'say "hi"',
+      ),
+    )
+
+    expect(dot).toContain('digraph "scan say \\"hi\\"" {')
+    expect(dot).toContain(
+      '  "pkg:npm/evil@1.0.0" [label="pkg:npm/evil@1.0.0", penwidth=2, alerts="malware", tooltip="malware", severity="high", style=filled, fillcolor="#ff7f0e"];',
+    )
+    expect(dot).toContain('  "scan:say \\"hi\\"" -> "pkg:npm/evil@1.0.0";')
+  })
+})
+
+describe('renderGraphMl', () => {
+  it('writes the node data keys and directed edges', () => {
+    const xml = renderGraphMl(
+      buildDependencyGraph(
+        [
+          artifact('evil', {
+            id: 'a',
+            alerts: [{ severity: 'high', type: 'malware' }],
+            direct: true,
+          }),
+        ],
+        'scan-1',
+      ),
+    )
+
+    expect(xml).toContain('<graph id="scan:scan-1" edgedefault="directed">')
+    expect(xml).toContain('<data key="alerts">malware</data>')
+    expect(xml).toContain('<data key="severity">high</data>')
+    expect(xml).toContain(
+      '<edge source="scan:scan-1" target="pkg:npm/evil@1.0.0"/>',
+    )
+  })
+})
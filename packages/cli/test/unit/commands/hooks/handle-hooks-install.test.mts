@@ -0,0 +1,241 @@
+/**
+ * Unit tests for `socket hooks install` and `socket hooks uninstall`.
+ *
+ * Purpose: Tests which files the hooks are written to and how existing hooks
+ * are treated.
+ *
+ * Test Coverage: - Executable hook scripts in the git hooks directory -
+ * Refusing foreign hooks, and moving them aside with --force - The
+ * pre-commit framework config picked by --framework auto - Diffs of the
+ * files --dry-run would write - Uninstall restoring moved hooks -
+ * Directories outside a git repository.
+ *
+ * Testing Approach: Points the git helpers at a temporary directory and
+ * captures the result handed to the output functions.
+ *
+ * Related Files: - src/commands/hooks/handle-hooks-install.mts
+ * (implementation) - src/commands/hooks/handle-hooks-uninstall.mts -
+ * src/commands/hooks/hook-scripts.mts - Hook contents.
+ */
+
+import {
+  existsSync,
+  mkdirSync,
+  mkdtempSync,
+  readFileSync,
+  rmSync,
+  statSync,
+  writeFileSync,
+} from 'node:fs'
+import { tmpdir } from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockGit = vi.hoisted(() => ({
+  gitHooksDir: vi.fn(),
+  gitTopLevel: vi.fn(),
+}))
+vi.mock(import('../../../../src/util/git/operations.mjs'), () => mockGit)
+
+const mockOutputInstall = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/hooks/output-hooks-install.mts'),
+  () => ({
+    outputHooksInstall: mockOutputInstall,
+  }),
+)
+
+const mockOutputDryRunDiff = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/dry-run/output.mts'), () => ({
+  outputDryRunDiff: mockOutputDryRunDiff,
+}))
+
+const mockOutputUninstall = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/hooks/output-hooks-uninstall.mts'),
+  () => ({
+    outputHooksUninstall: mockOutputUninstall,
+  }),
+)
+
+import { handleHooksInstall } from '../../../../src/commands/hooks/handle-hooks-install.mts'
+import { handleHooksUninstall } from '../../../../src/commands/hooks/handle-hooks-uninstall.mts'
+
+import type { HandleHooksInstallConfig } from '../../../../src/commands/hooks/handle-hooks-install.mts'
+
+const LINT_STAGED_HOOK = '#!/bin/sh\nnpx lint-staged\n'
+
+describe('socket hooks install', () => {
+  let repoDir: string
+  let hooksDir: string
+
+  function install(config?: Partial<HandleHooksInstallConfig>) {
+    return handleHooksInstall({
+      cwd: repoDir,
+      failOn: 'error',
+      force: false,
+      framework: 'auto',
+      hooks: ['pre-commit', 'pre-push'],
+      outputKind: 'text',
+      ...config,
+    })
+  }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    repoDir = mkdtempSync(path.join(tmpdir(), 'socket-hooks-'))
+    hooksDir = path.join(repoDir, '.git', 'hooks')
+    mkdirSync(hooksDir, { recursive: true })
+    mockGit.gitTopLevel.mockResolvedValue(repoDir)
+    mockGit.gitHooksDir.mockResolvedValue(hooksDir)
+  })
+
+  afterEach(() => {
+    rmSync(repoDir, { force: true, recursive: true })
+  })
+
+  it('writes executable hook scripts', async () => {
+    await install()
+
+    const preCommit = path.join(hooksDir, 'pre-commit')
+    expect(mockOutputInstall).toHaveBeenCalledWith(
+      {
+        ok: true,
+        data: {
+          backups: [],
+          failOn: 'error',
+          files: [preCommit, path.join(hooksDir, 'pre-push')],
+          framework: 'git',
+          hooks: ['pre-commit', 'pre-push'],
+        },
+      },
+      'text',
+    )
+    expect(readFileSync(preCommit, 'utf8')).toContain(
+      'exec socket hooks run pre-commit --fail-on error "$@"',
+    )
+    if (process.platform !== 'win32') {
+      expect(statSync(preCommit).mode & 0o111).toBe(0o111)
+    }
+  })
+
+  it('keeps hooks it did not write unless forced', async () => {
+    const preCommit = path.join(hooksDir, 'pre-commit')
+    writeFileSync(preCommit, LINT_STAGED_HOOK)
+
+    await install()
+
+    expect(mockOutputInstall.mock.calls[0]![0]).toMatchObject({
+      ok: false,
+      message: 'Hook already exists',
+    })
+    expect(readFileSync(preCommit, 'utf8')).toBe(LINT_STAGED_HOOK)
+    expect(existsSync(path.join(hooksDir, 'pre-push'))).toBe(false)
+
+    await install({ force: true })
+
+    expect(mockOutputInstall.mock.calls[1]![0]).toMatchObject({
+      ok: true,
+      data: { backups: [`${preCommit}.socket-backup`] },
+    })
+    expect(readFileSync(`${preCommit}.socket-backup`, 'utf8')).toBe(
+      LINT_STAGED_HOOK,
+    )
+
+    // Reinstalling over its own hooks needs no --force.
+    await install({ failOn: 'warn' })
+
+    expect(mockOutputInstall.mock.calls[2]![0]).toMatchObject({ ok: true })
+    expect(readFileSync(preCommit, 'utf8')).toContain('--fail-on warn')
+  })
+
+  it('uses the pre-commit framework when the repo has a config', async () => {
+    const configPath = path.join(repoDir, '.pre-commit-config.yaml')
+    writeFileSync(configPath, 'repos: []\n')
+
+    await install({ hooks: ['pre-commit'] })
+
+    expect(mockOutputInstall.mock.calls[0]![0]).toMatchObject({
+      ok: true,
+      data: { files: [configPath], framework: 'pre-commit' },
+    })
+    expect(readFileSync(configPath, 'utf8')).toContain('id: socket-pre-commit')
+    expect(existsSync(path.join(hooksDir, 'pre-commit'))).toBe(false)
+  })
+
+  it('shows the hooks it would write with --dry-run', async () => {
+    const preCommit = path.join(hooksDir, 'pre-commit')
+    writeFileSync(preCommit, LINT_STAGED_HOOK)
+
+    await install({ dryRun: true, force: true, hooks: ['pre-commit'] })
+
+    expect(mockOutputInstall).not.toHaveBeenCalled()
+    expect(mockOutputDryRunDiff).toHaveBeenCalledWith(
+      'install git hooks, failing on error',
+      [
+        {
+          after: expect.stringContaining(
+            'exec socket hooks run pre-commit --fail-on error',
+          ),
+          before: undefined,
+          path: '.git/hooks/pre-commit',
+        },
+      ],
+      [
+        'Move .git/hooks/pre-commit aside to .git/hooks/pre-commit.socket-backup',
+      ],
+    )
+    expect(readFileSync(preCommit, 'utf8')).toBe(LINT_STAGED_HOOK)
+    expect(existsSync(`${preCommit}.socket-backup`)).toBe(false)
+  })
+
+  it('diffs the pre-commit framework config with --dry-run', async () => {
+    const configPath = path.join(repoDir, '.pre-commit-config.yaml')
+    writeFileSync(configPath, 'repos: []\n')
+
+    await install({ dryRun: true, hooks: ['pre-commit'] })
+
+    const [, files] = mockOutputDryRunDiff.mock.calls[0]!
+    expect(files).toEqual([
+      {
+        after: expect.stringContaining('id: socket-pre-commit'),
+        before: 'repos: []\n',
+        path: '.pre-commit-config.yaml',
+      },
+    ])
+    expect(readFileSync(configPath, 'utf8')).toBe('repos: []\n')
+  })
+
+  it('fails outside a git repository', async () => {
+    mockGit.gitTopLevel.mockResolvedValue(undefined)
+
+    await install()
+
+    expect(mockOutputInstall.mock.calls[0]![0]).toMatchObject({
+      ok: false,
+      message: 'Not a git repository',
+    })
+  })
+
+  it('uninstalls the hooks and restores the ones moved aside', async () => {
+    const preCommit = path.join(hooksDir, 'pre-commit')
+    writeFileSync(preCommit, LINT_STAGED_HOOK)
+    await install({ force: true })
+
+    await handleHooksUninstall({ cwd: repoDir, outputKind: 'text' })
+
+    expect(mockOutputUninstall).toHaveBeenCalledWith(
+      {
+        ok: true,
+        data: {
+          removed: [preCommit, path.join(hooksDir, 'pre-push')],
+          restored: [preCommit],
+        },
+      },
+      'text',
+    )
+    expect(readFileSync(preCommit, 'utf8')).toBe(LINT_STAGED_HOOK)
+    expect(existsSync(path.join(hooksDir, 'pre-push'))).toBe(false)
+  })
+})
@@ -0,0 +1,272 @@
+/**
+ * Unit tests for `socket hooks run`.
+ *
+ * Purpose: Tests which package versions a commit or push is checked for and
+ * when the hook fails.
+ *
+ * Test Coverage: - Only versions the staged lockfiles gain are looked up -
+ * Other changed files are ignored - --fail-on error, warn and never -
+ * Packages that could not be checked - Pushed ref ranges, new branches and
+ * deletions - SOCKET_CLI_SKIP_HOOKS.
+ *
+ * Testing Approach: Stubs the git helpers and the score lookup and captures
+ * the result handed to the output function.
+ *
+ * Related Files: - src/commands/hooks/handle-hooks-run.mts (implementation)
+ * - src/commands/fix/fix-changelog.mts - Lockfile version diffs.
+ */
+
+import { mkdtempSync, rmSync } from 'node:fs'
+import { tmpdir } from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockGit = vi.hoisted(() => ({
+  GIT_EMPTY_TREE: 'empty-tree',
+  gitDiffChangedFiles: vi.fn(),
+  gitMergeBase: vi.fn(),
+  gitResolveCommit: vi.fn(),
+  gitShowFile: vi.fn(),
+  gitTopLevel: vi.fn(),
+}))
+vi.mock(import('../../../../src/util/git/operations.mjs'), () => mockGit)
+
+const mockFetchPurlsShallowScore = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/package/fetch-purls-shallow-score.mts'),
+  () => ({
+    fetchPurlsShallowScore: mockFetchPurlsShallowScore,
+  }),
+)
+
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), () => ({
+  getDefaultApiToken: () => 'sktsec_test',
+}))
+
+const mockOutput = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/hooks/output-hooks-run.mts'),
+  () => ({
+    outputHooksRun: mockOutput,
+  }),
+)
+
+import { handleHooksRun } from '../../../../src/commands/hooks/handle-hooks-run.mts'
+
+import type { HandleHooksRunConfig } from '../../../../src/commands/hooks/handle-hooks-run.mts'
+
+function packageLock(versions: Record<string, string>): string {
+  const packages: Record<string, unknown> = { '': { name: 'app' } }
+  for (const { 0: name, 1: version } of Object.entries(versions)) {
+    packages[`node_modules/${name}`] = { version }
+  }
+  return JSON.stringify({ lockfileVersion: 3, packages })
+}
+
+describe('handleHooksRun', () => {
+  let repoDir: string
+
+  async function run(config?: Partial<HandleHooksRunConfig>) {
+    await handleHooksRun({
+      cwd: repoDir,
+      failOn: 'error',
+      hook: 'pre-commit',
+      outputKind: 'text',
+      ...config,
+    })
+    return mockOutput.mock.calls.at(-1)![0]
+  }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    delete process.env['SOCKET_CLI_SKIP_HOOKS']
+    repoDir = mkdtempSync(path.join(tmpdir(), 'socket-hooks-run-'))
+    mockGit.gitTopLevel.mockResolvedValue(repoDir)
+    mockGit.gitResolveCommit.mockResolvedValue('head-sha')
+    mockGit.gitDiffChangedFiles.mockResolvedValue({
+      ok: true,
+      data: ['README.md', 'package-lock.json'],
+    })
+    mockGit.gitShowFile.mockImplementation(
+      async (_file: string, revision: string) =>
+        revision === ''
+          ? packageLock({
+              '@scope/pkg': '2.0.0',
+              'is-odd': '3.0.1',
+              'left-pad': '1.1.0',
+            })
+          : packageLock({ 'is-odd': '3.0.1', 'left-pad': '1.0.0' }),
+    )
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        {
+          alerts: [{ severity: 'critical', type: 'malware' }],
+          name: 'left-pad',
+          type: 'npm',
+          version: '1.1.0',
+        },
+      ],
+    })
+  })
+
+  afterEach(() => {
+    delete process.env['SOCKET_CLI_SKIP_HOOKS']
+    rmSync(repoDir, { force: true, recursive: true })
+  })
+
+  it('checks the versions the staged lockfiles add', async () => {
+    const result = await run()
+
+    expect(mockGit.gitDiffChangedFiles).toHaveBeenCalledWith(
+      'head-sha',
+      undefined,
+      repoDir,
+    )
+    expect(mockFetchPurlsShallowScore).toHaveBeenCalledWith(
+      ['pkg:npm/%40scope/pkg@2.0.0', 'pkg:npm/left-pad@1.1.0'],
+      expect.objectContaining({ sdkOpts: { apiToken: 'sktsec_test' } }),
+    )
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        blocked: [
+          {
+            alerts: [
+              { action: 'error', severity: 'critical', type: 'malware' },
+            ],
+            purl: 'pkg:npm/left-pad@1.1.0',
+          },
+        ],
+        checked: ['pkg:npm/%40scope/pkg@2.0.0', 'pkg:npm/left-pad@1.1.0'],
+        hook: 'pre-commit',
+        passed: false,
+        skipped: false,
+        warned: [],
+      },
+    })
+  })
+
+  it('compares with the empty tree before the first commit', async () => {
+    mockGit.gitResolveCommit.mockResolvedValue(undefined)
+
+    await run()
+
+    expect(mockGit.gitDiffChangedFiles).toHaveBeenCalledWith(
+      'empty-tree',
+      undefined,
+      repoDir,
+    )
+  })
+
+  it('skips the lookup when no lockfile changed', async () => {
+    mockGit.gitDiffChangedFiles.mockResolvedValue({
+      ok: true,
+      data: ['package.json', 'src/index.js'],
+    })
+
+    const result = await run()
+
+    expect(mockFetchPurlsShallowScore).not.toHaveBeenCalled()
+    expect(result).toMatchObject({
+      ok: true,
+      data: { checked: [], passed: true },
+    })
+  })
+
+  it('applies the --fail-on strictness', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        {
+          alerts: [
+            { action: 'warn', severity: 'middle', type: 'networkAccess' },
+          ],
+          name: 'left-pad',
+          type: 'npm',
+          version: '1.1.0',
+        },
+      ],
+    })
+
+    expect(await run()).toMatchObject({ data: { passed: true } })
+    expect(await run({ failOn: 'warn' })).toMatchObject({
+      data: { passed: false },
+    })
+
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        {
+          alerts: [{ severity: 'critical', type: 'malware' }],
+          name: 'left-pad',
+          type: 'npm',
+          version: '1.1.0',
+        },
+      ],
+    })
+
+    expect(await run({ failOn: 'never' })).toMatchObject({
+      data: { passed: true },
+    })
+  })
+
+  it('only refuses unchecked packages with --fail-on warn', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: false,
+      message: 'Socket API error',
+      cause: 'API unreachable',
+    })
+
+    expect(await run()).toMatchObject({
+      ok: true,
+      data: { error: 'API unreachable', passed: true },
+    })
+    expect(await run({ failOn: 'warn' })).toMatchObject({
+      ok: true,
+      data: { error: 'API unreachable', passed: false },
+    })
+  })
+
+  it('checks each pushed ref against its remote tip', async () => {
+    mockGit.gitResolveCommit.mockImplementation(async (revision: string) =>
+      revision === 'remote-sha' ? 'remote-sha' : undefined,
+    )
+    mockGit.gitMergeBase.mockResolvedValue('base-sha')
+    const zero = '0'.repeat(40)
+
+    await run({
+      hook: 'pre-push',
+      pushRefs: [
+        'refs/heads/main local-sha refs/heads/main remote-sha',
+        `refs/heads/feature feature-sha refs/heads/feature ${zero}`,
+        `(delete) ${zero} refs/heads/old old-sha`,
+        '',
+      ].join('\n'),
+      remote: 'origin',
+    })
+
+    expect(mockGit.gitMergeBase).toHaveBeenCalledWith(
+      'feature-sha',
+      'refs/remotes/origin/HEAD',
+      repoDir,
+    )
+    expect(mockGit.gitDiffChangedFiles.mock.calls).toEqual([
+      ['remote-sha', 'local-sha', repoDir],
+      ['base-sha', 'feature-sha', repoDir],
+    ])
+  })
+
+  it('passes without looking when SOCKET_CLI_SKIP_HOOKS is set', async () => {
+    process.env['SOCKET_CLI_SKIP_HOOKS'] = '1'
+
+    const result = await run()
+
+    expect(mockGit.gitDiffChangedFiles).not.toHaveBeenCalled()
+    expect(result).toMatchObject({
+      ok: true,
+      data: { passed: true, skipped: true },
+    })
+  })
+})
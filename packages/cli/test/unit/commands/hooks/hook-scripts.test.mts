@@ -0,0 +1,141 @@
+/**
+ * Unit tests for the hooks `socket hooks install` writes.
+ *
+ * Purpose: Tests the hook script and the edits to a pre-commit framework
+ * config.
+ *
+ * Test Coverage: - Script marker, skip variable and missing-socket fallback -
+ * Adding hooks to empty and existing configs - Replacing hooks on reinstall -
+ * Removing hooks while keeping other repos - Invalid YAML.
+ *
+ * Related Files: - src/commands/hooks/hook-scripts.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  addPreCommitFrameworkHooks,
+  getGitHookScript,
+  isSocketHookScript,
+  removePreCommitFrameworkHooks,
+} from '../../../../src/commands/hooks/hook-scripts.mts'
+
+describe('getGitHookScript', () => {
+  it('runs socket with the hook arguments unless skipped', () => {
+    const script = getGitHookScript('pre-push', 'warn')
+
+    expect(script.startsWith('#!/bin/sh\n')).toBe(true)
+    expect(isSocketHookScript(script)).toBe(true)
+    expect(script).toContain('case "$SOCKET_CLI_SKIP_HOOKS" in')
+    expect(script).toContain('git push --no-verify')
+    expect(script).toContain('command -v socket')
+    expect(script).toContain(
+      'exec socket hooks run pre-push --fail-on warn "$@"\n',
+    )
+  })
+
+  it('recognizes its hooks with CRLF line endings', () => {
+    const script = getGitHookScript('pre-commit', 'error')
+
+    expect(isSocketHookScript(script.replaceAll('\n', '\r\n'))).toBe(true)
+  })
+
+  it('does not claim hooks written by others', () => {
+    expect(isSocketHookScript('#!/bin/sh\nnpx lint-staged\n')).toBe(false)
+  })
+})
+
+describe('addPreCommitFrameworkHooks', () => {
+  it('creates a config with a local repo', () => {
+    const result = addPreCommitFrameworkHooks('', ['pre-commit'], 'error')
+
+    expect(result).toEqual({
+      ok: true,
+      data: `repos:
+  - repo: local
+    hooks:
+      - id: socket-pre-commit
+        name: Socket pre-commit dependency check
+        entry: socket hooks run pre-commit --fail-on error
+        language: system
+        pass_filenames: false
+        always_run: true
+        stages:
+          - pre-commit
+`,
+    })
+  })
+
+  it('keeps other repos and comments and replaces an earlier install', () => {
+    const source = `# Lint before commit.
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v4.6.0
+    hooks:
+      - id: trailing-whitespace
+`
+    const first = addPreCommitFrameworkHooks(
+      source,
+      ['pre-commit', 'pre-push'],
+      'error',
+    )
+    const second = addPreCommitFrameworkHooks(
+      first.ok ? first.data : '',
+      ['pre-push'],
+      'never',
+    )
+
+    expect(second.ok).toBe(true)
+    const data = second.ok ? second.data : ''
+    expect(data.startsWith('# Lint before commit.\n')).toBe(true)
+    expect(data).toContain('id: trailing-whitespace')
+    expect(data).not.toContain('socket-pre-commit')
+    expect(data.match(/repo: local/g)).toHaveLength(1)
+    expect(data).toContain('entry: socket hooks run pre-push --fail-on never')
+  })
+
+  it('refuses invalid YAML', () => {
+    expect(
+      addPreCommitFrameworkHooks('repos: [\n', ['pre-commit'], 'error'),
+    ).toMatchObject({ ok: false, message: 'Invalid .pre-commit-config.yaml' })
+  })
+})
+
+describe('removePreCommitFrameworkHooks', () => {
+  it('removes only the socket hooks', () => {
+    const source = `repos:
+  - repo: local
+    hooks:
+      - id: eslint
+        name: eslint
+        entry: npx eslint
+        language: system
+      - id: socket-pre-commit
+        name: Socket pre-commit dependency check
+        entry: socket hooks run pre-commit --fail-on error
+        language: system
+  - repo: local
+    hooks:
+      - id: socket-pre-push
+        entry: socket hooks run pre-push --fail-on error
+        language: system
+`
+
+    const updated = removePreCommitFrameworkHooks(source)
+
+    expect(updated).toBe(`repos:
+  - repo: local
+    hooks:
+      - id: eslint
+        name: eslint
+        entry: npx eslint
+        language: system
+`)
+  })
+
+  it('returns undefined when there is nothing to remove', () => {
+    expect(
+      removePreCommitFrameworkHooks('repos:\n  - repo: local\n    hooks: []\n'),
+    ).toBeUndefined()
+  })
+})
@@ -0,0 +1,129 @@
+/**
+ * Unit tests for handleLicenseCheck.
+ *
+ * Purpose: Tests that the handler finds the policy file, checks the packages
+ * of the scan against it and hands the verdict to the output.
+ *
+ * Testing Approach: Policy files live in a temp dir; fetchScan and the output
+ * function are mocked.
+ *
+ * Related Files: - src/commands/license/handle-license-check.mts
+ * (implementation) - src/commands/license/license-inventory.mts (check)
+ */
+
+import { mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import { createSuccessResult } from '../../../helpers/index.mts'
+import { handleLicenseCheck } from '../../../../src/commands/license/handle-license-check.mts'
+
+const mockFetchScan = vi.hoisted(() => vi.fn())
+const mockOutputLicenseCheck = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/license/output-license-check.mts'),
+  () => ({
+    outputLicenseCheck: mockOutputLicenseCheck,
+  }),
+)
+
+vi.mock(import('../../../../src/commands/scan/fetch-scan.mts'), () => ({
+  fetchScan: mockFetchScan,
+}))
+
+const DENY_GPL_POLICY = `version: 1
+licenses:
+  deny: [GPL-3.0-only]
+`
+
+describe('handleLicenseCheck', () => {
+  let tmpDir: string
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    tmpDir = path.resolve(mkdtempSync(path.join(os.tmpdir(), 'socket-test-')))
+  })
+
+  afterEach(async () => {
+    await safeDelete(tmpDir, { recursive: true })
+  })
+
+  it('checks the scan against the nearest policy file', async () => {
+    const policyPath = path.join(tmpDir, 'socket.policy.yml')
+    writeFileSync(policyPath, DENY_GPL_POLICY, 'utf8')
+    mockFetchScan.mockResolvedValue(
+      createSuccessResult([
+        { license: 'GPL-3.0-only', name: 'a', type: 'npm', version: '1.0.0' },
+        { license: 'MIT', name: 'b', type: 'npm', version: '1.0.0' },
+      ]),
+    )
+
+    await handleLicenseCheck({
+      allowUnknown: false,
+      cwd: tmpDir,
+      orgSlug: 'test-org',
+      outputKind: 'json',
+      policy: '',
+      scanId: 'scan-1',
+    })
+
+    expect(mockFetchScan).toHaveBeenCalledWith('test-org', 'scan-1')
+    expect(mockOutputLicenseCheck).toHaveBeenCalledWith(
+      {
+        ok: true,
+        data: {
+          allowUnknown: false,
+          denied: [{ license: 'GPL-3.0-only', purl: 'pkg:npm/a@1.0.0' }],
+          passed: false,
+          policyPath,
+          scanId: 'scan-1',
+          unknown: [],
+        },
+      },
+      'json',
+      { hasLicenseLists: true },
+    )
+  })
+
+  it('reads the policy given with --policy', async () => {
+    const policyPath = path.join(tmpDir, 'legal.policy.yml')
+    writeFileSync(policyPath, 'version: 1\n', 'utf8')
+    mockFetchScan.mockResolvedValue(createSuccessResult([]))
+
+    await handleLicenseCheck({
+      allowUnknown: false,
+      cwd: tmpDir,
+      orgSlug: 'test-org',
+      outputKind: 'text',
+      policy: 'legal.policy.yml',
+      scanId: 'scan-1',
+    })
+
+    const [result, , options] = mockOutputLicenseCheck.mock.calls[0]!
+    expect(result.data.policyPath).toBe(policyPath)
+    expect(result.data.passed).toBe(true)
+    expect(options).toEqual({ hasLicenseLists: false })
+  })
+
+  it('fails without a policy file and skips the scan fetch', async () => {
+    await handleLicenseCheck({
+      allowUnknown: false,
+      cwd: tmpDir,
+      orgSlug: 'test-org',
+      outputKind: 'text',
+      policy: '',
+      scanId: 'scan-1',
+    })
+
+    expect(mockFetchScan).not.toHaveBeenCalled()
+    expect(mockOutputLicenseCheck).toHaveBeenCalledWith(
+      expect.objectContaining({ ok: false, message: 'No license policy' }),
+      'text',
+    )
+  })
+})
@@ -0,0 +1,143 @@
+/**
+ * Unit tests for the license inventory of a scan.
+ *
+ * Tests the per-license roll-up of `socket license list` and the check of a
+ * scan against the license lists of a policy for `socket license check`.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  checkLicenses,
+  getLicenseInventory,
+  isUnknownLicense,
+} from '../../../../src/commands/license/license-inventory.mts'
+import { createEmptySocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+function pkg(name: string, license?: string, direct = false): SocketArtifact {
+  return {
+    direct,
+    license,
+    name,
+    type: 'npm',
+    version: '1.0.0',
+  } as SocketArtifact
+}
+
+function policyWith(licenses: { allow?: string[]; deny?: string[] }) {
+  const policy = createEmptySocketPolicy()
+  policy.licenses = { allow: [], deny: [], ...licenses }
+  return policy
+}
+
+describe('isUnknownLicense', () => {
+  it('treats missing and NOASSERTION licenses as unknown', () => {
+    expect(isUnknownLicense(undefined)).toBe(true)
+    expect(isUnknownLicense(' ')).toBe(true)
+    expect(isUnknownLicense('NOASSERTION')).toBe(true)
+    expect(isUnknownLicense('unknown')).toBe(true)
+    expect(isUnknownLicense('MIT')).toBe(false)
+  })
+})
+
+describe('getLicenseInventory', () => {
+  it('counts the packages per license id', () => {
+    const inventory = getLicenseInventory('scan-1', [
+      pkg('b', 'MIT OR Apache-2.0', true),
+      pkg('a', 'MIT'),
+      pkg('c'),
+    ])
+    expect(inventory).toEqual({
+      licenses: [
+        { id: 'MIT', packages: 2 },
+        { id: 'Apache-2.0', packages: 1 },
+        { id: 'NOASSERTION', packages: 1 },
+      ],
+      packages: [
+        {
+          direct: false,
+          ids: ['MIT'],
+          license: 'MIT',
+          purl: 'pkg:npm/a@1.0.0',
+        },
+        {
+          direct: true,
+          ids: ['MIT', 'Apache-2.0'],
+          license: 'MIT OR Apache-2.0',
+          purl: 'pkg:npm/b@1.0.0',
+        },
+        {
+          direct: false,
+          ids: ['NOASSERTION'],
+          license: 'NOASSERTION',
+          purl: 'pkg:npm/c@1.0.0',
+        },
+      ],
+      scanId: 'scan-1',
+      unknown: 1,
+    })
+  })
+
+  it('lists a package found in several manifests once', () => {
+    const inventory = getLicenseInventory('scan-1', [pkg('a'), pkg('a', 'ISC')])
+    expect(inventory.packages).toHaveLength(1)
+    expect(inventory.packages[0]!.license).toBe('ISC')
+    expect(inventory.unknown).toBe(0)
+  })
+})
+
+describe('checkLicenses', () => {
+  const options = {
+    allowUnknown: false,
+    policyPath: '/repo/socket.policy.yml',
+    scanId: 'scan-1',
+  }
+
+  it('passes when one alternative of each expression is acceptable', () => {
+    const result = checkLicenses(
+      [pkg('a', 'MIT'), pkg('b', 'GPL-3.0-only OR MIT')],
+      policyWith({ deny: ['GPL-3.0-only'] }),
+      options,
+    )
+    expect(result.passed).toBe(true)
+    expect(result.denied).toEqual([])
+  })
+
+  it('reports denied and unknown licenses', () => {
+    const result = checkLicenses(
+      [pkg('a', 'GPL-3.0-only'), pkg('b')],
+      policyWith({ deny: ['GPL-3.0-only'] }),
+      options,
+    )
+    expect(result).toEqual({
+      ...options,
+      denied: [{ license: 'GPL-3.0-only', purl: 'pkg:npm/a@1.0.0' }],
+      passed: false,
+      unknown: ['pkg:npm/b@1.0.0'],
+    })
+  })
+
+  it('lets unknown licenses pass with allowUnknown', () => {
+    const result = checkLicenses([pkg('b')], policyWith({ allow: ['MIT'] }), {
+      ...options,
+      allowUnknown: true,
+    })
+    expect(result.passed).toBe(true)
+    expect(result.unknown).toEqual(['pkg:npm/b@1.0.0'])
+  })
+
+  it('waives unknown licenses covered by a policy exception', () => {
+    const policy = policyWith({ allow: ['MIT'] })
+    policy.exceptions.push({
+      alerts: ['licensePolicyViolation'],
+      packages: ['pkg:npm/b'],
+      paths: [],
+      reason: 'Vendored with a license file',
+    })
+    const result = checkLicenses([pkg('b')], policy, options)
+    expect(result.passed).toBe(true)
+    expect(result.unknown).toEqual([])
+  })
+})
@@ -0,0 +1,41 @@
+/**
+ * Unit tests for the CSV output of `socket license list`.
+ *
+ * Related Files: - src/commands/license/output-license-list.mts
+ * (implementation) - src/util/output/csv.mts - CSV serialization.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { formatLicenseCsv } from '../../../../src/commands/license/output-license-list.mts'
+
+const inventory = {
+  licenses: [{ id: 'MIT', packages: 1 }],
+  packages: [
+    {
+      direct: true,
+      ids: ['MIT', 'Apache-2.0'],
+      license: 'MIT OR Apache-2.0',
+      purl: 'pkg:pypi/requests@2.31.0',
+    },
+  ],
+  scanId: 'scan-1',
+  unknown: 0,
+}
+
+describe('formatLicenseCsv', () => {
+  it('prints the purl, license and direct columns by default', () => {
+    expect(formatLicenseCsv(inventory)).toBe(
+      'purl,license,direct\npkg:pypi/requests@2.31.0,MIT OR Apache-2.0,true',
+    )
+  })
+
+  it('prints the selected columns without a header', () => {
+    expect(
+      formatLicenseCsv(inventory, {
+        columns: ['ecosystem', 'package', 'version', 'license'],
+        header: false,
+      }),
+    ).toBe('pypi,requests,2.31.0,MIT OR Apache-2.0')
+  })
+})
@@ -4,21 +4,42 @@
  * Purpose: Tests the applyLogin function that updates CLI configuration.
  *
  * Test Coverage: - Config value updates - Token storage - Enforced orgs
- * storage.
+ * storage - Named profile storage - OS keychain storage and fallback.
  *
  * Related Files: - commands/login/apply-login.mts (implementation)
+ * - util/keychain.mts (OS keychain)
  */
 
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 // Mock dependencies.
 const mockUpdateConfigValue = vi.hoisted(() => vi.fn())
+const mockUpdateProfileConfigValue = vi.hoisted(() => vi.fn())
+const mockGetActiveProfileName = vi.hoisted(() => vi.fn())
+const mockIsApiTokenInKeychain = vi.hoisted(() => vi.fn(() => false))
+const mockIsConfigFromFlag = vi.hoisted(() => vi.fn(() => false))
+const mockDeleteKeychainToken = vi.hoisted(() => vi.fn())
+const mockIsKeychainEnabled = vi.hoisted(() => vi.fn(() => false))
+const mockStoreKeychainToken = vi.hoisted(() => vi.fn())
 
 vi.mock(import('../../../../src/util/config.mts'), () => ({
+  getActiveProfileName: mockGetActiveProfileName,
+  isApiTokenInKeychain: mockIsApiTokenInKeychain,
+  isConfigFromFlag: mockIsConfigFromFlag,
   updateConfigValue: mockUpdateConfigValue,
+  updateProfileConfigValue: mockUpdateProfileConfigValue,
+}))
+vi.mock(import('../../../../src/util/keychain.mts'), () => ({
+  deleteKeychainToken: mockDeleteKeychainToken,
+  isKeychainEnabled: mockIsKeychainEnabled,
+  KEYCHAIN_API_TOKEN_REF: '<keychain>',
+  storeKeychainToken: mockStoreKeychainToken,
 }))
 
-import { applyLogin } from '../../../../src/commands/login/apply-login.mts'
+import {
+  applyLogin,
+  saveApiToken,
+} from '../../../../src/commands/login/apply-login.mts'
 
 describe('apply-login', () => {
   beforeEach(() => {
@@ -68,5 +89,96 @@ describe('apply-login', () => {
 
       expect(mockUpdateConfigValue).toHaveBeenCalledWith('enforcedOrgs', [])
     })
+
+    it('stores credentials in a named profile and activates it', () => {
+      applyLogin('work-token', ['org1'], undefined, undefined, 'work')
+
+      expect(mockUpdateProfileConfigValue).toHaveBeenCalledTimes(4)
+      expect(mockUpdateProfileConfigValue).toHaveBeenCalledWith(
+        'work',
+        'apiToken',
+        'work-token',
+      )
+      expect(mockUpdateProfileConfigValue).toHaveBeenCalledWith(
+        'work',
+        'enforcedOrgs',
+        ['org1'],
+      )
+      expect(mockUpdateConfigValue).toHaveBeenCalledTimes(1)
+      expect(mockUpdateConfigValue).toHaveBeenCalledWith(
+        'activeProfile',
+        'work',
+      )
+    })
+  })
+
+  describe('saveApiToken', () => {
+    it('stores the token in the keychain when available', () => {
+      mockIsKeychainEnabled.mockReturnValueOnce(true)
+      mockStoreKeychainToken.mockReturnValueOnce({ ok: true, data: undefined })
+
+      const result = saveApiToken('token', 'work')
+
+      expect(result).toEqual({ ok: true, data: 'keychain' })
+      expect(mockStoreKeychainToken).toHaveBeenCalledWith('work', 'token')
+      expect(mockUpdateProfileConfigValue).toHaveBeenCalledWith(
+        'work',
+        'apiToken',
+        '<keychain>',
+      )
+    })
+
+    it('uses the active profile as keychain account', () => {
+      mockIsKeychainEnabled.mockReturnValueOnce(true)
+      mockGetActiveProfileName.mockReturnValueOnce('oss')
+      mockStoreKeychainToken.mockReturnValueOnce({ ok: true, data: undefined })
+
+      saveApiToken('token', undefined)
+
+      expect(mockStoreKeychainToken).toHaveBeenCalledWith('oss', 'token')
+      expect(mockUpdateConfigValue).toHaveBeenCalledWith(
+        'apiToken',
+        '<keychain>',
+      )
+    })
+
+    it('falls back to the config file when the keychain fails', () => {
+      mockIsKeychainEnabled.mockReturnValueOnce(true)
+      mockStoreKeychainToken.mockReturnValueOnce({
+        ok: false,
+        message: 'OS keychain unavailable',
+        cause: 'locked',
+      })
+
+      const result = saveApiToken('token', undefined)
+
+      expect(result).toMatchObject({
+        ok: true,
+        data: 'config',
+        message: expect.stringContaining('locked'),
+      })
+      expect(mockUpdateConfigValue).toHaveBeenCalledWith('apiToken', 'token')
+    })
+
+    it('skips the keychain with --no-keychain and drops the old entry', () => {
+      mockIsKeychainEnabled.mockReturnValueOnce(true)
+      mockIsApiTokenInKeychain.mockReturnValueOnce(true)
+
+      const result = saveApiToken('token', undefined, false)
+
+      expect(result).toEqual({ ok: true, data: 'config' })
+      expect(mockStoreKeychainToken).not.toHaveBeenCalled()
+      expect(mockDeleteKeychainToken).toHaveBeenCalledWith('default')
+      expect(mockUpdateConfigValue).toHaveBeenCalledWith('apiToken', 'token')
+    })
+
+    it('skips the keychain when the config is read-only', () => {
+      mockIsKeychainEnabled.mockReturnValueOnce(true)
+      mockIsConfigFromFlag.mockReturnValueOnce(true)
+
+      saveApiToken('token', undefined)
+
+      expect(mockStoreKeychainToken).not.toHaveBeenCalled()
+    })
   })
 })
@@ -199,6 +199,8 @@ describe('attemptLogin', () => {
       ['a'],
       undefined,
       undefined,
+      undefined,
+      true,
     )
   })
 
@@ -227,6 +229,8 @@ describe('attemptLogin', () => {
       ['a'],
       undefined,
       undefined,
+      undefined,
+      true,
     )
   })
 
@@ -345,6 +349,8 @@ describe('attemptLogin', () => {
       [],
       undefined,
       undefined,
+      undefined,
+      true,
     )
   })
 
@@ -361,6 +367,8 @@ describe('attemptLogin', () => {
       ['a'],
       undefined,
       undefined,
+      undefined,
+      true,
     )
   })
 
@@ -376,6 +384,8 @@ describe('attemptLogin', () => {
       [],
       undefined,
       undefined,
+      undefined,
+      true,
     )
   })
 })
@@ -96,7 +96,7 @@ describe('cmd-login', () => {
           expect.arrayContaining([
             'Prompt for Socket API token',
             'Verify token with Socket API',
-            'Save API token to config',
+            'Save API token to the OS keychain, or to config if unavailable',
             'Optionally set default organization',
             'Optionally install bash completion',
           ]),
@@ -138,7 +138,7 @@ describe('cmd-login', () => {
       it('should call attemptLogin with empty strings by default', async () => {
         await cmdLogin.run([], importMeta, context)
 
-        expect(mockAttemptLogin).toHaveBeenCalledWith('', '')
+        expect(mockAttemptLogin).toHaveBeenCalledWith('', '', '', true, false)
       })
 
       it('should pass API base URL when provided', async () => {
@@ -149,11 +149,41 @@ describe('cmd-login', () => {
         )
 
         expect(mockAttemptLogin).toHaveBeenCalledWith(
-          'https://api.example.com',
+          'https://api.example.com/v0/',
           '',
+          '',
+          true,
+          false,
+        )
+      })
+
+      it('should keep the API version of the base URL', async () => {
+        await cmdLogin.run(
+          ['--api-base-url=https://socket.example.com/api/v0'],
+          importMeta,
+          context,
+        )
+
+        expect(mockAttemptLogin).toHaveBeenCalledWith(
+          'https://socket.example.com/api/v0/',
+          '',
+          '',
+          true,
+          false,
         )
       })
 
+      it('should throw InputError for an unsupported API version', async () => {
+        await expect(
+          cmdLogin.run(
+            ['--api-base-url=https://socket.example.com/v1/'],
+            importMeta,
+            context,
+          ),
+        ).rejects.toThrow(/Unsupported Socket API version/)
+        expect(mockAttemptLogin).not.toHaveBeenCalled()
+      })
+
       it('should pass API proxy when provided', async () => {
         await cmdLogin.run(
           ['--api-proxy=http://localhost:8080'],
@@ -164,6 +194,9 @@ describe('cmd-login', () => {
         expect(mockAttemptLogin).toHaveBeenCalledWith(
           '',
           'http://localhost:8080',
+          '',
+          true,
+          false,
         )
       })
 
@@ -178,21 +211,46 @@ describe('cmd-login', () => {
         )
 
         expect(mockAttemptLogin).toHaveBeenCalledWith(
-          'https://api.example.com',
+          'https://api.example.com/v0/',
           'http://localhost:8080',
+          '',
+          true,
+          false,
         )
       })
 
       it('should handle empty string API base URL', async () => {
         await cmdLogin.run(['--api-base-url='], importMeta, context)
 
-        expect(mockAttemptLogin).toHaveBeenCalledWith('', '')
+        expect(mockAttemptLogin).toHaveBeenCalledWith('', '', '', true, false)
       })
 
       it('should handle empty string API proxy', async () => {
         await cmdLogin.run(['--api-proxy='], importMeta, context)
 
-        expect(mockAttemptLogin).toHaveBeenCalledWith('', '')
+        expect(mockAttemptLogin).toHaveBeenCalledWith('', '', '', true, false)
+      })
+
+      it('should pass the profile and --no-keychain', async () => {
+        await cmdLogin.run(
+          ['--profile', 'work', '--no-keychain'],
+          importMeta,
+          context,
+        )
+
+        expect(mockAttemptLogin).toHaveBeenCalledWith(
+          '',
+          '',
+          'work',
+          false,
+          false,
+        )
+      })
+
+      it('should pass --sso', async () => {
+        await cmdLogin.run(['--sso'], importMeta, context)
+
+        expect(mockAttemptLogin).toHaveBeenCalledWith('', '', '', true, true)
       })
     })
 
@@ -208,7 +266,13 @@ describe('cmd-login', () => {
           const url = validUrls[i]
           mockAttemptLogin.mockClear()
           await cmdLogin.run([`--api-base-url=${url}`], importMeta, context)
-          expect(mockAttemptLogin).toHaveBeenCalledWith(url, '')
+          expect(mockAttemptLogin).toHaveBeenCalledWith(
+            url,
+            '',
+            '',
+            true,
+            false,
+          )
         }
       })
 
@@ -223,7 +287,13 @@ describe('cmd-login', () => {
           const proxy = validProxies[i]
           mockAttemptLogin.mockClear()
           await cmdLogin.run([`--api-proxy=${proxy}`], importMeta, context)
-          expect(mockAttemptLogin).toHaveBeenCalledWith('', proxy)
+          expect(mockAttemptLogin).toHaveBeenCalledWith(
+            '',
+            proxy,
+            '',
+            true,
+            false,
+          )
         }
       })
     })
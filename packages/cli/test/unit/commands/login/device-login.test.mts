@@ -0,0 +1,172 @@
+/**
+ * Unit tests for the OAuth device authorization flow of `socket login --sso`.
+ *
+ * Purpose: Tests endpoint discovery on the issuer, the device authorization
+ * request and polling the token endpoint until the login finished.
+ *
+ * Test Coverage: - Issuer from SOCKET_CLI_SSO_ISSUER or the API base URL -
+ * Missing device grant metadata - Pending and slow_down polling - Denied and
+ * expired codes - End-to-end login.
+ *
+ * Related Files: - src/commands/login/device-login.mts (implementation)
+ * - src/commands/login/attempt-login.mts (caller)
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  getDefaultApiBaseUrl: () => 'https://api.socket.dev/v0/',
+  socketHttpRequest: mockSocketHttpRequest,
+  tryReadResponseText: (response: { body?: string }) => response.body,
+}))
+
+import {
+  deviceLogin,
+  fetchDeviceLoginEndpoints,
+  getSsoIssuer,
+  pollDeviceToken,
+} from '../../../../src/commands/login/device-login.mts'
+
+import type { DeviceAuthorization } from '../../../../src/commands/login/device-login.mts'
+
+function jsonResponse(status: number, json: unknown) {
+  return { body: JSON.stringify(json), status }
+}
+
+const authorization: DeviceAuthorization = {
+  deviceCode: 'device-1',
+  expiresIn: 600,
+  interval: 5,
+  userCode: 'ABCD-EFGH',
+  verificationUri: 'https://socket.dev/device',
+  verificationUriComplete: 'https://socket.dev/device?user_code=ABCD-EFGH',
+}
+
+const metadata = {
+  device_authorization_endpoint: 'https://api.socket.dev/oauth/device',
+  token_endpoint: 'https://api.socket.dev/oauth/token',
+}
+
+describe('device login', () => {
+  const wait = vi.fn(async () => {})
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  afterEach(() => {
+    delete process.env['SOCKET_CLI_SSO_ISSUER']
+  })
+
+  it('uses the API origin as issuer unless SOCKET_CLI_SSO_ISSUER is set', () => {
+    expect(getSsoIssuer(undefined)).toBe('https://api.socket.dev')
+    expect(getSsoIssuer('https://socket.example.com/api/v0/')).toBe(
+      'https://socket.example.com',
+    )
+    process.env['SOCKET_CLI_SSO_ISSUER'] = 'https://sso.example.com'
+    expect(getSsoIssuer(undefined)).toBe('https://sso.example.com')
+  })
+
+  it('fails when the issuer has no device authorization grant', async () => {
+    mockSocketHttpRequest.mockResolvedValue(
+      jsonResponse(200, { token_endpoint: metadata.token_endpoint }),
+    )
+
+    const result = await fetchDeviceLoginEndpoints('https://api.socket.dev')
+
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'SSO login unavailable',
+    })
+    expect(mockSocketHttpRequest).toHaveBeenCalledWith(
+      'https://api.socket.dev/.well-known/oauth-authorization-server',
+      expect.objectContaining({ method: 'GET' }),
+    )
+  })
+
+  it('polls while pending and slows down when asked', async () => {
+    mockSocketHttpRequest
+      .mockResolvedValueOnce(
+        jsonResponse(400, { error: 'authorization_pending' }),
+      )
+      .mockResolvedValueOnce(jsonResponse(400, { error: 'slow_down' }))
+      .mockResolvedValueOnce(jsonResponse(200, { access_token: 'sktsec_x' }))
+
+    const result = await pollDeviceToken(
+      metadata.token_endpoint,
+      authorization,
+      wait,
+    )
+
+    expect(result).toEqual({ ok: true, data: 'sktsec_x' })
+    expect(wait.mock.calls).toEqual([[5000], [5000], [10_000]])
+    expect(mockSocketHttpRequest).toHaveBeenCalledWith(
+      metadata.token_endpoint,
+      expect.objectContaining({
+        body: 'client_id=socket-cli&device_code=device-1&grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Adevice_code',
+        method: 'POST',
+      }),
+    )
+  })
+
+  it('stops when the login was denied or the code expired', async () => {
+    mockSocketHttpRequest.mockResolvedValueOnce(
+      jsonResponse(400, { error: 'access_denied' }),
+    )
+    expect(
+      await pollDeviceToken(metadata.token_endpoint, authorization, wait),
+    ).toMatchObject({ ok: false, message: 'SSO login denied' })
+
+    mockSocketHttpRequest.mockResolvedValueOnce(
+      jsonResponse(400, { error: 'expired_token' }),
+    )
+    expect(
+      await pollDeviceToken(metadata.token_endpoint, authorization, wait),
+    ).toMatchObject({ ok: false, message: 'SSO login expired' })
+  })
+
+  it('passes on unexpected token endpoint errors', async () => {
+    mockSocketHttpRequest.mockResolvedValueOnce(
+      jsonResponse(400, {
+        error: 'invalid_client',
+        error_description: 'Unknown client',
+      }),
+    )
+
+    const result = await pollDeviceToken(
+      metadata.token_endpoint,
+      authorization,
+      wait,
+    )
+
+    expect(result).toMatchObject({
+      ok: false,
+      cause: 'invalid_client: Unknown client',
+    })
+  })
+
+  it('logs in end to end', async () => {
+    mockSocketHttpRequest
+      .mockResolvedValueOnce(jsonResponse(200, metadata))
+      .mockResolvedValueOnce(
+        jsonResponse(200, {
+          device_code: 'device-1',
+          expires_in: 600,
+          user_code: 'ABCD-EFGH',
+          verification_uri: 'https://socket.dev/device',
+        }),
+      )
+      .mockResolvedValueOnce(jsonResponse(200, { access_token: 'sktsec_x' }))
+    const onAuthorization = vi.fn()
+
+    const result = await deviceLogin({ onAuthorization, wait })
+
+    expect(result).toEqual({ ok: true, data: 'sktsec_x' })
+    expect(onAuthorization).toHaveBeenCalledWith({
+      ...authorization,
+      verificationUriComplete: undefined,
+    })
+  })
+})
@@ -61,6 +61,16 @@ vi.mock(
   }),
 )
 
+vi.mock(
+  import('../../../../src/commands/manifest/cmd-manifest-generate.mts'),
+  () => ({
+    cmdManifestGenerate: {
+      description: 'Resolve loose requirements',
+      hidden: false,
+    },
+  }),
+)
+
 vi.mock(
   import('../../../../src/commands/manifest/cmd-manifest-gradle.mts'),
   () => ({
@@ -138,6 +148,7 @@ describe('cmd-manifest', () => {
       expect(subcommands).toHaveProperty('auto')
       expect(subcommands).toHaveProperty('cdxgen')
       expect(subcommands).toHaveProperty('conda')
+      expect(subcommands).toHaveProperty('generate')
       expect(subcommands).toHaveProperty('gradle')
       expect(subcommands).toHaveProperty('kotlin')
       expect(subcommands).toHaveProperty('scala')
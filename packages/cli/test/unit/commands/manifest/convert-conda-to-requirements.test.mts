@@ -55,6 +55,19 @@ dependencies:
     `)
   })
 
+  it('should convert a file with CRLF line endings', () => {
+    const input = `name: myenv
+dependencies:
+  - pip:
+    - pandas
+    - numpy==1.21.0
+`
+
+    expect(
+      convertCondaToRequirementsFromInput(input.replaceAll('\n', '\r\n')),
+    ).toBe(convertCondaToRequirementsFromInput(input))
+  })
+
   it('should support arbitrary indent block', () => {
     const output = convertCondaToRequirementsFromInput(`
 name: myenv
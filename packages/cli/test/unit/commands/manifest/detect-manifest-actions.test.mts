@@ -7,6 +7,7 @@
  *
  * Test Coverage: - Empty directory → no detections, count 0 - build.sbt present
  * → sbt=true, count 1 - gradlew present → gradle=true, count 1 -
+ * gradle.lockfile present → gradle=false (scanned directly) -
  * environment.yml present → conda=true, count 1 - environment.yaml present
  * (when no .yml) → conda=true - Both .yml and .yaml present → only counts once
  * (yml wins) - All three present → all true, count 3 - sockJson disables sbt →
@@ -80,6 +81,14 @@ describe('detectManifestActions', () => {
     expect(result.count).toBe(1)
   })
 
+  it('skips gradle conversion when a gradle.lockfile is present', async () => {
+    touch('gradlew')
+    touch('gradle.lockfile')
+    const result = await detectManifestActions(undefined, cwd)
+    expect(result.gradle).toBe(false)
+    expect(result.count).toBe(0)
+  })
+
   it('detects environment.yml as Conda project', async () => {
     touch('environment.yml')
     const result = await detectManifestActions(undefined, cwd)
@@ -0,0 +1,140 @@
+/**
+ * Unit tests for resolving loose manifests.
+ *
+ * Tests which requirements files and Gradle builds count as unpinned, and how
+ * the Gradle dependency tree is read into resolved packages with edges.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  findLooseManifests,
+  isPinnedRequirements,
+  parseGradleDependencyTree,
+} from '../../../../src/commands/manifest/resolve-loose-manifests.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+describe('isPinnedRequirements', () => {
+  it('accepts exact pins with hashes, markers and options', () => {
+    expect(
+      isPinnedRequirements(
+        [
+          '# generated by pip-compile',
+          '--index-url https://pypi.org/simple',
+          '-r base.txt',
+          'requests[socks]==2.31.0 \\',
+          '    --hash=sha256:abc',
+          'tomli===2.0.1 ; python_version < "3.11"',
+          '',
+        ].join('\n'),
+      ),
+    ).toBe(true)
+  })
+
+  it('rejects ranges and bare names', () => {
+    expect(isPinnedRequirements('flask>=2.0\n')).toBe(false)
+    expect(isPinnedRequirements('requests==2.31.0\nurllib3\n')).toBe(false)
+    expect(isPinnedRequirements('django==4.*\n')).toBe(false)
+  })
+})
+
+describe('parseGradleDependencyTree', () => {
+  it('reads resolved versions, edges and direct dependencies', () => {
+    const output = [
+      '',
+      'runtimeClasspath - Runtime classpath of source set main.',
+      '+--- com.google.guava:guava:31.0-jre -> 32.1.2-jre',
+      '|    +--- com.google.guava:failureaccess:1.0.1',
+      '|    \\--- org.checkerframework:checker-qual:3.37.0 (c)',
+      '+--- project :lib',
+      '|    \\--- org.slf4j:slf4j-api:2.0.9',
+      '+--- org.slf4j:slf4j-api:2.0.9 (*)',
+      '+--- commons-logging:commons-logging:1.2 -> org.slf4j:jcl-over-slf4j:2.0.9',
+      '\\--- com.example:missing:1.0 FAILED',
+    ].join('\n')
+
+    expect(parseGradleDependencyTree(output)).toEqual([
+      {
+        id: 'com.google.guava:guava:32.1.2-jre',
+        type: 'maven',
+        namespace: 'com.google.guava',
+        name: 'guava',
+        version: '32.1.2-jre',
+        direct: true,
+        dependencies: ['com.google.guava:failureaccess:1.0.1'],
+      },
+      {
+        id: 'com.google.guava:failureaccess:1.0.1',
+        type: 'maven',
+        namespace: 'com.google.guava',
+        name: 'failureaccess',
+        version: '1.0.1',
+        direct: false,
+      },
+      {
+        id: 'org.slf4j:slf4j-api:2.0.9',
+        type: 'maven',
+        namespace: 'org.slf4j',
+        name: 'slf4j-api',
+        version: '2.0.9',
+        direct: true,
+      },
+      {
+        id: 'org.slf4j:jcl-over-slf4j:2.0.9',
+        type: 'maven',
+        namespace: 'org.slf4j',
+        name: 'jcl-over-slf4j',
+        version: '2.0.9',
+        direct: true,
+      },
+    ])
+  })
+})
+
+describe('findLooseManifests', () => {
+  it('lists unpinned requirements and unlocked Gradle builds', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        { path: 'requirements.txt', content: 'flask>=2.0\n' },
+        { path: 'requirements-dev.txt', content: 'pytest==8.0.0\n' },
+        { path: 'build.gradle.kts', content: 'plugins { java }\n' },
+      ],
+    })
+    try {
+      expect(findLooseManifests(workspace.path)).toEqual([
+        { ecosystem: 'pypi', file: 'requirements.txt' },
+        { ecosystem: 'maven', file: 'build.gradle.kts' },
+      ])
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('skips requirements files of projects with a uv.lock', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        { path: 'requirements.txt', content: 'flask>=2.0\n' },
+        { path: 'uv.lock', content: 'version = 1\n' },
+      ],
+    })
+    try {
+      expect(findLooseManifests(workspace.path)).toEqual([])
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('skips Gradle builds with dependency locking', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        { path: 'build.gradle', content: '' },
+        { path: 'gradle.lockfile', content: 'empty=\n' },
+      ],
+    })
+    try {
+      expect(findLooseManifests(workspace.path)).toEqual([])
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+})
@@ -0,0 +1,132 @@
+/**
+ * Unit tests for the socket npm provenance preflight.
+ *
+ * Purpose: Tests which npm invocations have their packages verified before
+ * handing off to Socket Firewall when SOCKET_CLI_VERIFY_PROVENANCE is set.
+ *
+ * Test Coverage: - Opt-in through the environment variable - Lookup failures
+ * fall through with a warning - Failed checks block the install - Offline
+ * Socket lookups.
+ *
+ * Related Files: - src/commands/npm/check-npm-provenance.mts (implementation)
+ * - src/commands/verify/verify-provenance.mts (checks)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { checkNpmProvenance } from '../../../../src/commands/npm/check-npm-provenance.mts'
+
+const mockEnv = vi.hoisted(() => ({
+  SOCKET_CLI_OFFLINE: false,
+  SOCKET_CLI_VERIFY_PROVENANCE: true,
+}))
+const mockVerifyNpmPackage = vi.hoisted(() => vi.fn())
+const mockWarn = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/env/socket-cli-offline.mts'), () => ({
+  get SOCKET_CLI_OFFLINE() {
+    return mockEnv.SOCKET_CLI_OFFLINE
+  },
+}))
+
+vi.mock(import('../../../../src/env/socket-cli-verify-provenance.mts'), () => ({
+  get SOCKET_CLI_VERIFY_PROVENANCE() {
+    return mockEnv.SOCKET_CLI_VERIFY_PROVENANCE
+  },
+}))
+
+vi.mock(
+  import('../../../../src/commands/verify/verify-provenance.mts'),
+  () => ({
+    verifyNpmPackage: mockVerifyNpmPackage,
+  }),
+)
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => ({ success: vi.fn(), warn: mockWarn }),
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/spinner/default'), () => ({
+  getDefaultSpinner: () => ({ start: vi.fn(), stop: vi.fn() }),
+}))
+
+describe('checkNpmProvenance', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockEnv.SOCKET_CLI_OFFLINE = false
+    mockEnv.SOCKET_CLI_VERIFY_PROVENANCE = true
+  })
+
+  it('does nothing unless SOCKET_CLI_VERIFY_PROVENANCE is set', async () => {
+    mockEnv.SOCKET_CLI_VERIFY_PROVENANCE = false
+
+    const result = await checkNpmProvenance(['install', 'lodash'])
+
+    expect(result.ok).toBe(true)
+    expect(mockVerifyNpmPackage).not.toHaveBeenCalled()
+  })
+
+  it('warns and proceeds when a package cannot be looked up', async () => {
+    mockVerifyNpmPackage.mockResolvedValue({
+      ok: false,
+      message: 'Package not found',
+    })
+
+    const result = await checkNpmProvenance(['install', 'missing'])
+
+    expect(result.ok).toBe(true)
+    expect(mockWarn).toHaveBeenCalledWith(
+      expect.stringContaining('Package not found'),
+    )
+  })
+
+  it('fails when a check fails', async () => {
+    mockVerifyNpmPackage.mockResolvedValue({
+      ok: true,
+      data: {
+        checks: [
+          { check: 'provenance', message: 'Missing', status: 'warn' },
+          {
+            check: 'source-repository',
+            message: 'Mismatch',
+            status: 'fail',
+          },
+        ],
+        purl: 'pkg:npm/lodash@4.17.21',
+        verified: false,
+      },
+    })
+
+    const result = await checkNpmProvenance(['install', 'lodash@4.17.21'])
+
+    expect(mockVerifyNpmPackage).toHaveBeenCalledWith('lodash', '4.17.21', {
+      commandPath: 'socket npm',
+      offline: false,
+      requireProvenance: false,
+    })
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Provenance verification failed',
+      cause: expect.stringContaining('source-repository: Mismatch'),
+    })
+    expect(mockWarn).toHaveBeenCalledWith(
+      'pkg:npm/lodash@4.17.21 provenance: Missing',
+    )
+  })
+
+  it('takes the Socket data from the cache with SOCKET_CLI_OFFLINE', async () => {
+    mockEnv.SOCKET_CLI_OFFLINE = true
+    mockVerifyNpmPackage.mockResolvedValue({
+      ok: true,
+      data: { checks: [], purl: 'pkg:npm/lodash@4.17.21', verified: true },
+    })
+
+    await checkNpmProvenance(['install', 'lodash@4.17.21'])
+
+    expect(mockVerifyNpmPackage).toHaveBeenCalledWith(
+      'lodash',
+      '4.17.21',
+      expect.objectContaining({ offline: true }),
+    )
+  })
+})
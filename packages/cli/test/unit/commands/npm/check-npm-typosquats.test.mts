@@ -0,0 +1,227 @@
+/**
+ * Unit tests for the socket npm typosquat preflight.
+ *
+ * Purpose: Tests that likely typosquats named by `npm install` warn locally
+ * and that Socket's verdict confirms or clears them.
+ *
+ * Test Coverage: - Popular names skip the lookup - Local warnings without
+ * the API - Confirmed typosquats prompt when interactive - Cleared matches -
+ * SOCKET_CLI_ACCEPT_RISKS - socket.policy.yml exceptions, read and recorded.
+ *
+ * Related Files: - src/commands/npm/check-npm-typosquats.mts (implementation)
+ * - src/util/install-check/typosquat.mts (heuristics)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { checkNpmTyposquats } from '../../../../src/commands/npm/check-npm-typosquats.mts'
+import { createEmptySocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+
+const mockEnv = vi.hoisted(() => ({ SOCKET_CLI_ACCEPT_RISKS: false }))
+const mockFetchPurlsShallowScore = vi.hoisted(() => vi.fn())
+const mockAddSocketPolicyException = vi.hoisted(() => vi.fn())
+const mockConfirm = vi.hoisted(() => vi.fn())
+const mockFindSocketPolicySync = vi.hoisted(() => vi.fn())
+const mockInput = vi.hoisted(() => vi.fn())
+const mockLogger = vi.hoisted(() => ({
+  fail: vi.fn(),
+  info: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(import('../../../../src/env/socket-cli-accept-risks.mts'), () => ({
+  get SOCKET_CLI_ACCEPT_RISKS() {
+    return mockEnv.SOCKET_CLI_ACCEPT_RISKS
+  },
+}))
+
+vi.mock(
+  import('../../../../src/commands/package/fetch-purls-shallow-score.mts'),
+  () => ({
+    fetchPurlsShallowScore: mockFetchPurlsShallowScore,
+  }),
+)
+
+vi.mock(import('../../../../src/util/socket/sdk.mts'), () => ({
+  getDefaultApiToken: () => 'test-token',
+}))
+
+vi.mock(
+  import('../../../../src/util/policy/socket-policy.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    findSocketPolicySync: mockFindSocketPolicySync,
+  }),
+)
+
+vi.mock(import('../../../../src/util/policy/add-policy-exception.mts'), () => ({
+  addSocketPolicyException: mockAddSocketPolicyException,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/stdio/prompts'), () => ({
+  confirm: mockConfirm,
+  input: mockInput,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/spinner/default'), () => ({
+  getDefaultSpinner: () => ({ start: vi.fn(), stop: vi.fn() }),
+}))
+
+describe('checkNpmTyposquats', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockEnv.SOCKET_CLI_ACCEPT_RISKS = false
+    mockFindSocketPolicySync.mockReturnValue({ ok: true, data: undefined })
+  })
+
+  it('skips the lookup for popular names', async () => {
+    const result = await checkNpmTyposquats(['install', 'lodash', 'react'])
+
+    expect(result.ok).toBe(true)
+    expect(mockFetchPurlsShallowScore).not.toHaveBeenCalled()
+    expect(mockLogger.warn).not.toHaveBeenCalled()
+  })
+
+  it('warns locally and proceeds when Socket cannot be reached', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: false,
+      message: 'Package data not cached',
+    })
+
+    const result = await checkNpmTyposquats(['install', 'lodahs'], {
+      interactive: true,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockLogger.warn).toHaveBeenCalledWith(
+      'lodahs looks like a typosquat of lodash',
+    )
+    expect(mockFetchPurlsShallowScore).toHaveBeenCalledWith(
+      ['pkg:npm/lodahs'],
+      expect.objectContaining({ commandPath: 'socket npm' }),
+    )
+    expect(mockConfirm).not.toHaveBeenCalled()
+  })
+
+  it('asks before installing confirmed typosquats', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [{ name: 'lodahs', alerts: [{ type: 'didYouMean' }] }],
+    })
+    mockConfirm.mockResolvedValue(false)
+
+    const result = await checkNpmTyposquats(['i', 'lodahs'], {
+      interactive: true,
+    })
+
+    expect(mockLogger.fail).toHaveBeenCalledWith(
+      expect.stringContaining('lodahs'),
+    )
+    expect(result).toMatchObject({ ok: false, message: 'Install cancelled' })
+  })
+
+  it('proceeds with SOCKET_CLI_ACCEPT_RISKS', async () => {
+    mockEnv.SOCKET_CLI_ACCEPT_RISKS = true
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [{ name: 'lodahs', alerts: [{ type: 'didYouMean' }] }],
+    })
+
+    const result = await checkNpmTyposquats(['i', 'lodahs'], {
+      interactive: true,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockConfirm).not.toHaveBeenCalled()
+  })
+
+  it('reports matches Socket clears', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [{ name: 'expresss', alerts: [] }],
+    })
+
+    const result = await checkNpmTyposquats(['add', 'expresss'], {
+      interactive: true,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockLogger.info).toHaveBeenCalledWith(
+      'Socket does not flag expresss as a typosquat',
+    )
+    expect(mockConfirm).not.toHaveBeenCalled()
+  })
+
+  it('skips typosquats a policy exception accepts', async () => {
+    const policy = createEmptySocketPolicy()
+    policy.exceptions.push({
+      alerts: ['didYouMean'],
+      packages: ['pkg:npm/lodahs'],
+      paths: [],
+      reason: 'Internal fork',
+    })
+    mockFindSocketPolicySync.mockReturnValue({
+      ok: true,
+      data: { path: '/repo/socket.policy.yml', policy },
+    })
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        {
+          alerts: [{ type: 'didYouMean' }],
+          name: 'lodahs',
+          type: 'npm',
+          version: '1.0.0',
+        },
+      ],
+    })
+
+    const result = await checkNpmTyposquats(['i', 'lodahs'], {
+      interactive: true,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockConfirm).not.toHaveBeenCalled()
+    expect(mockLogger.info).toHaveBeenCalledWith(
+      expect.stringContaining('accepted by socket.policy.yml'),
+    )
+  })
+
+  it('records accepted typosquats for the exact version', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        {
+          alerts: [{ type: 'didYouMean' }],
+          name: 'lodahs',
+          type: 'npm',
+          version: '1.0.0',
+        },
+      ],
+    })
+    mockConfirm.mockResolvedValue(true)
+    mockInput.mockResolvedValue('Our own package')
+    mockAddSocketPolicyException.mockReturnValue({ ok: true, data: undefined })
+
+    const result = await checkNpmTyposquats(['i', 'lodahs'], {
+      interactive: true,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockConfirm).toHaveBeenCalledTimes(2)
+    expect(mockAddSocketPolicyException).toHaveBeenCalledWith(
+      expect.stringMatching(/socket\.policy\.yml$/),
+      {
+        alerts: ['didYouMean'],
+        packages: ['pkg:npm/lodahs@1.0.0'],
+        paths: [],
+        reason: 'Accepted: Our own package',
+      },
+    )
+  })
+})
@@ -0,0 +1,48 @@
+/**
+ * Unit tests for npm install spec extraction.
+ *
+ * Purpose: Tests which packages of an npm invocation the `socket npm`
+ * preflight checks look up on the registry.
+ *
+ * Test Coverage: - install, i and add detection - Registry, range and alias
+ * specs - Local, git and url specs are skipped.
+ *
+ * Related Files: - src/commands/npm/npm-install-specs.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { getNpmInstallSpecs } from '../../../../src/commands/npm/npm-install-specs.mts'
+
+describe('getNpmInstallSpecs', () => {
+  it('returns the registry packages of an install', () => {
+    expect(
+      getNpmInstallSpecs(['install', '-D', 'lodash@^4', '@scope/pkg']),
+    ).toEqual([
+      { name: 'lodash', spec: 'lodash@^4', version: '^4' },
+      { name: '@scope/pkg', spec: '@scope/pkg', version: 'latest' },
+    ])
+    expect(getNpmInstallSpecs(['--no-audit', 'add', 'chalk@latest'])).toEqual([
+      { name: 'chalk', spec: 'chalk@latest', version: 'latest' },
+    ])
+    expect(getNpmInstallSpecs(['i', 'alias@npm:real@1.0.0'])).toEqual([
+      { name: 'real', spec: 'alias@npm:real@1.0.0', version: '1.0.0' },
+    ])
+  })
+
+  it('skips local, git and url specs', () => {
+    expect(
+      getNpmInstallSpecs([
+        'add',
+        './local',
+        'github:owner/repo',
+        'https://example.com/pkg.tgz',
+      ]),
+    ).toEqual([])
+  })
+
+  it('skips other commands', () => {
+    expect(getNpmInstallSpecs(['run', 'build'])).toBeUndefined()
+    expect(getNpmInstallSpecs(['--version'])).toBeUndefined()
+  })
+})
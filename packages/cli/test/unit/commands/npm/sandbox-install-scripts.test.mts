@@ -0,0 +1,263 @@
+/**
+ * Unit tests for `socket npm --sandbox-scripts`.
+ *
+ * Tests which npm commands are sandboxed, the install scripts of installed
+ * packages, and running the scripts of new packages after the install with
+ * the sandbox mocked: the order, the transcript written to the project and
+ * failing scripts.
+ */
+
+import { existsSync, promises as fs } from 'node:fs'
+import path from 'node:path'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  getInstallScripts,
+  isNpmScriptsInstall,
+  prepareSandboxedInstall,
+} from '../../../../src/commands/npm/sandbox-install-scripts.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+const mockFindScriptSandboxTools = vi.hoisted(() => vi.fn())
+const mockRunSandboxedScript = vi.hoisted(() => vi.fn())
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(import('../../../../src/commands/npm/script-sandbox.mts'), () => ({
+  findScriptSandboxTools: mockFindScriptSandboxTools,
+  runSandboxedScript: mockRunSandboxedScript,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+const TOOLS = { bwrap: '/usr/bin/bwrap', strace: '/usr/bin/strace' }
+
+const EMPTY_SUMMARY = {
+  commands: [],
+  network: [],
+  fileWrites: [],
+  homeReads: [],
+}
+
+function pkgJson(name: string, scripts?: Record<string, string> | undefined) {
+  return JSON.stringify({ name, version: '1.0.0', scripts })
+}
+
+function getFiles() {
+  return [
+    {
+      path: 'package.json',
+      content: JSON.stringify({ name: 'app', scripts: { prepare: 'tsc' } }),
+    },
+    {
+      path: 'node_modules/esbuild/package.json',
+      content: pkgJson('esbuild', { postinstall: 'node install.js' }),
+    },
+    {
+      path: 'node_modules/esbuild/node_modules/native/package.json',
+      content: pkgJson('native'),
+    },
+    {
+      path: 'node_modules/esbuild/node_modules/native/binding.gyp',
+      content: '{}',
+    },
+    { path: 'node_modules/lodash/package.json', content: pkgJson('lodash') },
+  ]
+}
+
+describe('isNpmScriptsInstall', () => {
+  it('matches the commands that run install scripts', () => {
+    expect(isNpmScriptsInstall(['install'])).toBe(true)
+    expect(isNpmScriptsInstall(['--save-dev', 'i', 'esbuild'])).toBe(true)
+    expect(isNpmScriptsInstall(['ci'])).toBe(true)
+    expect(isNpmScriptsInstall(['run', 'build'])).toBe(false)
+    expect(isNpmScriptsInstall([])).toBe(false)
+  })
+})
+
+describe('getInstallScripts', () => {
+  it('adds node-gyp rebuild for packages with a binding.gyp', async () => {
+    const workspace = await createTestWorkspace({ files: getFiles() })
+    try {
+      const dir = path.join(workspace.path, 'node_modules/esbuild')
+      const nativeDir = path.join(dir, 'node_modules/native')
+
+      expect(
+        await getInstallScripts({
+          location: 'node_modules/esbuild',
+          name: 'esbuild',
+          version: '1.0.0',
+          dir,
+          dependencies: [],
+        }),
+      ).toEqual([{ event: 'postinstall', script: 'node install.js' }])
+      expect(
+        await getInstallScripts({
+          location: 'node_modules/esbuild/node_modules/native',
+          name: 'native',
+          version: '1.0.0',
+          dir: nativeDir,
+          dependencies: [],
+        }),
+      ).toEqual([{ event: 'install', script: 'node-gyp rebuild' }])
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+})
+
+describe('prepareSandboxedInstall', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFindScriptSandboxTools.mockResolvedValue({ ok: true, data: TOOLS })
+  })
+
+  it('forwards the args unchanged without --sandbox-scripts', async () => {
+    expect(
+      await prepareSandboxedInstall(['install'], { sandboxScripts: false }),
+    ).toEqual({ ok: true, data: { args: ['install'] } })
+    expect(
+      await prepareSandboxedInstall(['run', 'build'], { sandboxScripts: true }),
+    ).toEqual({ ok: true, data: { args: ['run', 'build'] } })
+    expect(mockFindScriptSandboxTools).not.toHaveBeenCalled()
+  })
+
+  it('fails for global installs and without the sandbox tools', async () => {
+    expect(
+      await prepareSandboxedInstall(['install', '-g', 'cowsay'], {
+        sandboxScripts: true,
+      }),
+    ).toMatchObject({
+      ok: false,
+      message: 'Global installs cannot be sandboxed',
+    })
+
+    mockFindScriptSandboxTools.mockResolvedValue({
+      ok: false,
+      message: 'Sandbox tools not found',
+    })
+    expect(
+      await prepareSandboxedInstall(['install'], { sandboxScripts: true }),
+    ).toMatchObject({ ok: false, message: 'Sandbox tools not found' })
+  })
+
+  it('runs the scripts of new packages deepest first', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'node_modules/lodash/package.json',
+          content: pkgJson('lodash'),
+        },
+      ],
+    })
+    try {
+      const planCResult = await prepareSandboxedInstall(
+        ['install', 'esbuild'],
+        { sandboxScripts: true },
+        workspace.path,
+      )
+      expect(planCResult.ok && planCResult.data.args).toEqual([
+        'install',
+        'esbuild',
+        '--ignore-scripts',
+      ])
+
+      // The install adds esbuild and its native dependency.
+      for (const { content, path: file } of getFiles()) {
+        await workspace.writeFile(file, content)
+      }
+      mockRunSandboxedScript.mockResolvedValue({
+        exitCode: 0,
+        output: '',
+        summary: {
+          ...EMPTY_SUMMARY,
+          network: [
+            {
+              address: '203.0.113.7',
+              port: 443,
+              syscall: 'connect',
+              denied: true,
+            },
+          ],
+        },
+      })
+      const afterExitCResult = planCResult.ok
+        ? await planCResult.data.afterExit!()
+        : planCResult
+
+      expect(afterExitCResult).toMatchObject({ ok: true })
+      expect(mockRunSandboxedScript.mock.calls.map(c => c[1].script)).toEqual([
+        'node-gyp rebuild',
+        'node install.js',
+      ])
+      expect(mockRunSandboxedScript.mock.calls[1]![1]).toMatchObject({
+        cwd: workspace.path,
+        pkgDir: path.join(workspace.path, 'node_modules/esbuild'),
+        env: {
+          npm_lifecycle_event: 'postinstall',
+          npm_package_name: 'esbuild',
+        },
+      })
+      expect(mockLogger.warn).toHaveBeenCalledWith(
+        'esbuild@1.0.0 postinstall tried to reach 203.0.113.7:443',
+      )
+      expect(mockLogger.warn).toHaveBeenCalledWith(
+        "The project's own prepare script was not run, run it with `npm run`",
+      )
+
+      const reportPath = path.join(
+        workspace.path,
+        '.socket.install-scripts.json',
+      )
+      expect(existsSync(reportPath)).toBe(true)
+      const report = JSON.parse(await fs.readFile(reportPath, 'utf8'))
+      expect(
+        report.transcripts.map(
+          (t: { event: string; location: string }) =>
+            `${t.location} ${t.event}`,
+        ),
+      ).toEqual([
+        'node_modules/esbuild postinstall',
+        'node_modules/esbuild/node_modules/native install',
+      ])
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('fails when a script fails in the sandbox', async () => {
+    const workspace = await createTestWorkspace({ files: [] })
+    try {
+      const planCResult = await prepareSandboxedInstall(
+        ['ci'],
+        { sandboxScripts: true },
+        workspace.path,
+      )
+      for (const { content, path: file } of getFiles()) {
+        await workspace.writeFile(file, content)
+      }
+      mockRunSandboxedScript.mockResolvedValue({
+        exitCode: 1,
+        output: 'gyp ERR! network',
+        summary: EMPTY_SUMMARY,
+      })
+      const afterExitCResult = planCResult.ok
+        ? await planCResult.data.afterExit!()
+        : planCResult
+
+      expect(afterExitCResult).toMatchObject({
+        ok: false,
+        message: 'Install scripts failed in the sandbox',
+      })
+      expect(mockLogger.error).toHaveBeenCalledWith('gyp ERR! network')
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+})
@@ -0,0 +1,82 @@
+/**
+ * Unit tests for the strace summaries of `socket npm --sandbox-scripts`.
+ *
+ * Tests joining calls strace splits across lines and summarizing the
+ * programs run, network endpoints, writes outside of the package and reads
+ * in the home folder.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { WIN32 } from '@socketsecurity/lib-stable/constants/platform'
+
+import {
+  readStraceCalls,
+  summarizeStrace,
+} from '../../../../src/commands/npm/strace-transcript.mts'
+
+const PKG_DIR = '/home/dev/app/node_modules/evil'
+
+const TRACE = [
+  '101 execve("/bin/sh", ["/bin/sh", "-c", "node install.js"], 0x7ffd /* 12 vars */) = 0',
+  '102 execve("/usr/bin/node", ["node", "install.js"], 0x7ffd /* 12 vars */) = 0',
+  '102 openat(AT_FDCWD, "/home/dev/.npmrc", O_RDONLY|O_CLOEXEC) = -1 ENOENT (No such file or directory)',
+  '102 openat(AT_FDCWD, "/home/dev/app/package.json", O_RDONLY|O_CLOEXEC) = 17',
+  '102 openat(AT_FDCWD, "build/out.js", O_WRONLY|O_CREAT|O_TRUNC|O_CLOEXEC, 0666) = 18',
+  '102 openat(AT_FDCWD, "/usr/lib/node/evil.js", O_WRONLY|O_CREAT|O_CLOEXEC, 0666) = -1 EROFS (Read-only file system)',
+  '102 connect(19, {sa_family=AF_INET, sin_port=htons(443), sin_addr=inet_addr("203.0.113.7")}, 16 <unfinished ...>',
+  '103 unlink("/home/dev/app/package-lock.json") = -1 EROFS (Read-only file system)',
+  '102 <... connect resumed>) = -1 ENETUNREACH (Network is unreachable)',
+  '102 sendto(20, "\\x12\\x34", 2, MSG_NOSIGNAL, {sa_family=AF_INET6, sin6_port=htons(53), sin6_flowinfo=htonl(0), inet_pton(AF_INET6, "2001:db8::1", &sin6_addr), sin6_scope_id=0}, 28) = -1 ENETUNREACH (Network is unreachable)',
+  '102 +++ exited with 1 +++',
+  '',
+].join('\n')
+
+describe('readStraceCalls', () => {
+  it('joins unfinished and resumed calls', () => {
+    const calls = readStraceCalls(TRACE)
+    const connect = calls.find(c => c.syscall === 'connect')
+
+    expect(calls).toHaveLength(9)
+    expect(connect).toEqual({
+      syscall: 'connect',
+      args: '19, {sa_family=AF_INET, sin_port=htons(443), sin_addr=inet_addr("203.0.113.7")}, 16',
+      result: '-1 ENETUNREACH (Network is unreachable)',
+    })
+  })
+})
+
+// The sandbox is Linux only, strace reports POSIX paths.
+describe.skipIf(WIN32)('summarizeStrace', () => {
+  it('summarizes what the script attempted', () => {
+    expect(
+      summarizeStrace(TRACE, {
+        cwd: PKG_DIR,
+        home: '/home/dev',
+        readablePaths: ['/home/dev/app'],
+        writablePaths: [PKG_DIR, '/tmp'],
+      }),
+    ).toEqual({
+      commands: ['/bin/sh -c node install.js', 'node install.js'],
+      network: [
+        {
+          address: '203.0.113.7',
+          port: 443,
+          syscall: 'connect',
+          denied: true,
+        },
+        {
+          address: '2001:db8::1',
+          port: 53,
+          syscall: 'sendto',
+          denied: true,
+        },
+      ],
+      fileWrites: [
+        { path: '/usr/lib/node/evil.js', denied: true },
+        { path: '/home/dev/app/package-lock.json', denied: true },
+      ],
+      homeReads: ['/home/dev/.npmrc'],
+    })
+  })
+})
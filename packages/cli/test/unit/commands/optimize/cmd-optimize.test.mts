@@ -1,7 +1,8 @@
 /**
  * Unit tests for optimize command.
  *
- * Tests the command that optimizes dependencies with @socketregistry overrides.
+ * Tests the command that optimizes dependencies with @socketregistry overrides,
+ * including the manifest diffs of --dry-run.
  */
 
 // socket-lint: allow bare-semver -- the mock must mirror EnvDetails.agentVersion, a semver SemVer instance; the lib versions helpers are string-based.
@@ -36,6 +37,16 @@ vi.mock(
 // Mock dependencies.
 const mockHandleOptimize = vi.hoisted(() => vi.fn())
 const mockDetectAndValidatePackageEnvironment = vi.hoisted(() => vi.fn())
+const mockHasDefaultApiToken = vi.hoisted(() => vi.fn(() => true))
+const mockPreviewOptimization = vi.hoisted(() =>
+  vi.fn().mockResolvedValue({ added: [], files: [], updated: [] }),
+)
+const mockPreviewSafeOverrides = vi.hoisted(() =>
+  vi.fn().mockResolvedValue({
+    ok: true,
+    data: { files: [], overrides: [], unfixable: [] },
+  }),
+)
 // The default resolved value is installed outside vi.hoisted because the real
 // EnvDetails.agentVersion is a SemVer instance and the semver import is not
 // initialized yet inside hoisted callbacks.
@@ -44,6 +55,7 @@ mockDetectAndValidatePackageEnvironment.mockResolvedValue({
   data: {
     agent: 'npm',
     agentVersion: new SemVer('10.0.0'),
+    lockName: 'package-lock.json',
     pkgPath: '/test/path',
   },
 })
@@ -55,10 +67,22 @@ vi.mock(
   }),
 )
 
+vi.mock(
+  import('../../../../src/commands/optimize/preview-optimization.mts'),
+  () => ({
+    previewOptimization: mockPreviewOptimization,
+    previewSafeOverrides: mockPreviewSafeOverrides,
+  }),
+)
+
 vi.mock(import('../../../../src/util/ecosystem/environment.mjs'), () => ({
   detectAndValidatePackageEnvironment: mockDetectAndValidatePackageEnvironment,
 }))
 
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), () => ({
+  hasDefaultApiToken: mockHasDefaultApiToken,
+}))
+
 describe('cmd-optimize', () => {
   beforeEach(() => {
     vi.clearAllMocks()
@@ -209,5 +233,118 @@ describe('cmd-optimize', () => {
         expect.stringContaining('pnpm v9.0.0'),
       )
     })
+
+    it('should pass --safe-versions flag to handleOptimize', async () => {
+      await cmdOptimize.run(['--safe-versions'], importMeta, context)
+
+      expect(mockHandleOptimize).toHaveBeenCalledWith(
+        expect.objectContaining({
+          safeVersions: true,
+        }),
+      )
+    })
+
+    it('should require an API token with --safe-versions', async () => {
+      mockHasDefaultApiToken.mockReturnValueOnce(false)
+
+      await cmdOptimize.run(['--safe-versions'], importMeta, context)
+
+      expect(mockHandleOptimize).not.toHaveBeenCalled()
+      expect(process.exitCode).toBe(2)
+    })
+
+    it('should show the package.json diff in dry-run mode', async () => {
+      mockPreviewOptimization.mockResolvedValueOnce({
+        added: ['@socketregistry/is-regex'],
+        files: [
+          {
+            after: '{\n  "overrides": {\n    "is-regex": "npm:@socketregistry/is-regex@^1"\n  }\n}\n',
+            before: '{}\n',
+            path: '/test/path/package.json',
+          },
+        ],
+        updated: [],
+      })
+
+      await cmdOptimize.run(['--dry-run'], importMeta, context)
+
+      expect(mockHandleOptimize).not.toHaveBeenCalled()
+      expect(mockPreviewOptimization).toHaveBeenCalledWith(
+        expect.objectContaining({ pkgPath: '/test/path' }),
+        { pin: false, prod: false },
+      )
+      const output = mockLogger.error.mock.calls.map(c => c[0]).join('\n')
+      expect(output).toContain('--- a/package.json')
+      expect(output).toContain('+++ b/package.json')
+      expect(output).toContain(
+        '+    "is-regex": "npm:@socketregistry/is-regex@^1"',
+      )
+      expect(output).toContain(
+        'Add an override with @socketregistry/is-regex',
+      )
+      expect(output).toContain('Run npm install to update package-lock.json')
+    })
+
+    it('should say so in dry-run mode when there is nothing to override', async () => {
+      await cmdOptimize.run(['--dry-run'], importMeta, context)
+
+      expect(mockLogger.error).toHaveBeenCalledWith(
+        expect.stringContaining('No overrides to add or update'),
+      )
+    })
+
+    it('should show the safe version diff in dry-run mode', async () => {
+      mockPreviewSafeOverrides.mockResolvedValueOnce({
+        ok: true,
+        data: {
+          files: [
+            {
+              after: '{\n  "overrides": {\n    "minimist@1.2.5": "^1.2.6"\n  }\n}\n',
+              before: '{}\n',
+              path: '/test/path/package.json',
+            },
+          ],
+          overrides: [
+            {
+              alertTypes: ['criticalCVE'],
+              name: 'minimist',
+              safeVersion: '1.2.6',
+              version: '1.2.5',
+            },
+          ],
+          unfixable: [],
+        },
+      })
+
+      await cmdOptimize.run(
+        ['--dry-run', '--safe-versions', '--pin'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleOptimize).not.toHaveBeenCalled()
+      expect(mockPreviewSafeOverrides).toHaveBeenCalledWith(
+        expect.objectContaining({ pkgPath: '/test/path' }),
+        { pin: true, prod: false },
+      )
+      const output = mockLogger.error.mock.calls.map(c => c[0]).join('\n')
+      expect(output).toContain('+    "minimist@1.2.5": "^1.2.6"')
+      expect(output).toContain('Pin minimist@1.2.5 to 1.2.6')
+    })
+
+    it('should show the safe version plan in dry-run mode without a token', async () => {
+      mockHasDefaultApiToken.mockReturnValueOnce(false)
+
+      await cmdOptimize.run(
+        ['--dry-run', '--safe-versions'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleOptimize).not.toHaveBeenCalled()
+      expect(mockLogger.error).toHaveBeenCalledWith(
+        expect.stringContaining('Pin alerted transitive dependencies'),
+      )
+    })
   })
 })
@@ -0,0 +1,160 @@
+/**
+ * Unit tests for the safe version overrides of `socket optimize
+ * --safe-versions`.
+ *
+ * Test Coverage: - package-lock.json v3 and v1 parsing - Transitive purl
+ * selection - Safe version selection across alerts - Override keys by agent.
+ *
+ * Related Files: - src/commands/optimize/safe-overrides.mts (implementation) -
+ * src/commands/optimize/apply-safe-overrides.mts - Install and verification.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getSafeOverrideKey,
+  getSafeOverrides,
+  getTransitivePurls,
+  parseNpmLockPackages,
+} from '../../../../src/commands/optimize/safe-overrides.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+describe('parseNpmLockPackages', () => {
+  it('marks the packages declared by the root and workspaces as direct', () => {
+    const lockSrc = JSON.stringify({
+      lockfileVersion: 3,
+      packages: {
+        '': { dependencies: { mkdirp: '^0.5.1' } },
+        'packages/web': { devDependencies: { '@scope/tool': '^1.0.0' } },
+        'node_modules/mkdirp': { version: '0.5.5' },
+        'node_modules/minimist': { version: '1.2.5' },
+        'node_modules/@scope/tool': { dev: true, version: '1.0.0' },
+        'node_modules/web': { link: true },
+      },
+    })
+
+    expect(parseNpmLockPackages(lockSrc)).toEqual([
+      { dev: false, direct: true, name: 'mkdirp', version: '0.5.5' },
+      { dev: false, direct: false, name: 'minimist', version: '1.2.5' },
+      { dev: true, direct: true, name: '@scope/tool', version: '1.0.0' },
+    ])
+  })
+
+  it('walks the nested dependencies of lockfile v1', () => {
+    const lockSrc = JSON.stringify({
+      lockfileVersion: 1,
+      dependencies: {
+        mkdirp: {
+          version: '0.5.5',
+          dependencies: { minimist: { version: '1.2.5' } },
+        },
+      },
+    })
+
+    expect(parseNpmLockPackages(lockSrc)).toEqual([
+      { dev: false, direct: true, name: 'mkdirp', version: '0.5.5' },
+      { dev: false, direct: false, name: 'minimist', version: '1.2.5' },
+    ])
+  })
+
+  it('returns no packages for invalid JSON', () => {
+    expect(parseNpmLockPackages('{')).toEqual([])
+  })
+})
+
+describe('getTransitivePurls', () => {
+  const packages = [
+    { dev: false, direct: true, name: 'mkdirp', version: '0.5.5' },
+    { dev: false, direct: false, name: 'minimist', version: '1.2.5' },
+    { dev: true, direct: false, name: '@scope/tool', version: '1.0.0' },
+    // A direct dependency stays one when installed again deeper down.
+    { dev: false, direct: false, name: 'mkdirp', version: '0.5.1' },
+  ]
+
+  it('skips every version of a direct dependency', () => {
+    expect(getTransitivePurls(packages)).toEqual([
+      'pkg:npm/%40scope/tool@1.0.0',
+      'pkg:npm/minimist@1.2.5',
+    ])
+  })
+
+  it('skips dev dependencies with prod', () => {
+    expect(getTransitivePurls(packages, { prod: true })).toEqual([
+      'pkg:npm/minimist@1.2.5',
+    ])
+  })
+})
+
+describe('getSafeOverrides', () => {
+  it('picks the highest first patched version of the alerts', () => {
+    const artifacts = [
+      {
+        alerts: [
+          {
+            props: { firstPatchedVersionIdentifier: '1.2.6' },
+            type: 'mediumCVE',
+          },
+          {
+            props: { firstPatchedVersionIdentifier: '1.2.8' },
+            type: 'criticalCVE',
+          },
+          { type: 'unmaintained' },
+        ],
+        name: 'minimist',
+        type: 'npm',
+        version: '1.2.5',
+      },
+    ] as unknown as SocketArtifact[]
+
+    expect(getSafeOverrides(artifacts)).toEqual({
+      overrides: [
+        {
+          alertTypes: ['criticalCVE', 'mediumCVE'],
+          name: 'minimist',
+          safeVersion: '1.2.8',
+          version: '1.2.5',
+        },
+      ],
+      unfixable: [],
+    })
+  })
+
+  it('lists vulnerabilities without a patched version as unfixable', () => {
+    const artifacts = [
+      {
+        alerts: [
+          {
+            props: { vulnerableVersionRange: '>=0.0.0' },
+            type: 'highCVE',
+          },
+        ],
+        name: 'tool',
+        namespace: '@scope',
+        type: 'npm',
+        version: '1.0.0',
+      },
+    ] as unknown as SocketArtifact[]
+
+    expect(getSafeOverrides(artifacts)).toEqual({
+      overrides: [],
+      unfixable: [
+        { alertTypes: ['highCVE'], name: '@scope/tool', version: '1.0.0' },
+      ],
+    })
+  })
+})
+
+describe('getSafeOverrideKey', () => {
+  const override = { name: 'minimist', version: '1.2.5' }
+
+  it('names the vulnerable version for npm and pnpm', () => {
+    expect(getSafeOverrideKey('npm', override)).toBe('minimist@1.2.5')
+    expect(getSafeOverrideKey('pnpm', override)).toBe('minimist@1.2.5')
+  })
+
+  it('names only the package for yarn and bun', () => {
+    expect(getSafeOverrideKey('yarn/berry', override)).toBe('minimist')
+    expect(getSafeOverrideKey('bun', override)).toBe('minimist')
+  })
+})
@@ -6,20 +6,28 @@
  * pnpm-workspace.yaml) depending on the package manager and version.
  *
  * Test Coverage: - updateManifest (per-agent routing + pnpm 11+
- * pnpm-workspace.yaml integration) - usesPnpmWorkspaceOverrides -
- * getEntryIndexes / getLowestEntryIndex / getHighestEntryIndex.
+ * pnpm-workspace.yaml integration and dry runs) - getPkgJsonChange -
+ * usesPnpmWorkspaceOverrides - getEntryIndexes / getLowestEntryIndex /
+ * getHighestEntryIndex.
  *
  * Related Files: - commands/optimize/update-manifest-by-agent.mts
  * (implementation)
  */
 
-import { mkdtempSync, readFileSync, rmSync } from 'node:fs'
+import {
+  existsSync,
+  mkdtempSync,
+  readFileSync,
+  rmSync,
+  writeFileSync,
+} from 'node:fs'
 import os from 'node:os'
 import path from 'node:path'
 
 import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
 
 import {
+  getPkgJsonChange,
   updateManifest,
   usesPnpmWorkspaceOverrides,
 } from '../../../../src/commands/optimize/update-manifest-by-agent.mts'
@@ -300,6 +308,28 @@ minimumReleaseAge: 10080
         expect(yamlContent).toContain('lodash: 4.17.21')
       })
 
+      it('returns the pnpm-workspace.yaml change without writing it on a dry run', async () => {
+        pkgJson = createEditablePkgJson({ name: 'test' })
+        const file = await updateManifest(
+          'pnpm',
+          makeEnv({
+            agent: 'pnpm',
+            agentVersion: { major: 11, minor: 0, patch: 8 },
+            editablePkgJson: pkgJson,
+            pkgPath: tmpDir,
+          }),
+          { lodash: '4.17.21' },
+          { dryRun: true },
+        )
+
+        expect(file?.path).toBe(path.join(tmpDir, 'pnpm-workspace.yaml'))
+        expect(file?.before).toBeUndefined()
+        expect(file?.after).toContain('lodash: 4.17.21')
+        expect(existsSync(path.join(tmpDir, 'pnpm-workspace.yaml'))).toBe(
+          false,
+        )
+      })
+
       it('routes to package.json when pnpm < 11 (legacy path)', async () => {
         pkgJson = createEditablePkgJson({ name: 'test' })
         await updateManifest(
@@ -325,6 +355,37 @@ minimumReleaseAge: 10080
     })
   })
 
+  describe('getPkgJsonChange', () => {
+    let tmpDir: string
+
+    beforeEach(() => {
+      tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-cli-pkg-json-test-'))
+    })
+
+    afterEach(async () => {
+      await safeDelete(tmpDir)
+    })
+
+    it('serializes the unsaved content with the indentation on disk', () => {
+      const pkgJsonPath = path.join(tmpDir, 'package.json')
+      writeFileSync(pkgJsonPath, '{\n\t"name": "test"\n}\n', 'utf8')
+      const pkgJson = createEditablePkgJson({
+        name: 'test',
+        overrides: { lodash: '4.17.21' },
+      })
+
+      expect(
+        getPkgJsonChange({ editablePkgJson: pkgJson, pkgPath: tmpDir }),
+      ).toEqual({
+        after:
+          '{\n\t"name": "test",\n\t"overrides": {\n\t\t"lodash": "4.17.21"\n\t}\n}\n',
+        before: '{\n\t"name": "test"\n}\n',
+        path: pkgJsonPath,
+      })
+      expect(pkgJson.save).not.toHaveBeenCalled()
+    })
+  })
+
   describe('usesPnpmWorkspaceOverrides', () => {
     it('returns true for pnpm 11+', () => {
       expect(
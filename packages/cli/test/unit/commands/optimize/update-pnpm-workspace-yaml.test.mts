@@ -5,7 +5,8 @@
  * in its `packageManager` field. Comments and non-overrides keys must be
  * preserved across edits.
  *
- * Test Coverage: - updatePnpmWorkspaceYamlOverrides.
+ * Test Coverage: - updatePnpmWorkspaceYamlOverrides, with and without
+ * dryRun.
  *
  * Related Files: - commands/optimize/update-pnpm-workspace-yaml.mts
  * (implementation)
@@ -63,6 +64,23 @@ minimumReleaseAge: 10080
     expect(content).toContain('lodash: 4.17.21')
   })
 
+  it('returns the merged file without writing it on a dry run', async () => {
+    const existing = `overrides:
+  lodash: 4.17.20
+`
+    const yamlPath = path.join(tmpDir, 'pnpm-workspace.yaml')
+    writeFileSync(yamlPath, existing, 'utf8')
+    const file = await updatePnpmWorkspaceYamlOverrides(
+      tmpDir,
+      { lodash: '4.17.21' },
+      { dryRun: true },
+    )
+    expect(file.path).toBe(yamlPath)
+    expect(file.before).toBe(existing)
+    expect(file.after).toContain('lodash: 4.17.21')
+    expect(readFileSync(yamlPath, 'utf8')).toBe(existing)
+  })
+
   it('merges new entries into an existing overrides block', async () => {
     const existing = `# Header comment
 overrides:
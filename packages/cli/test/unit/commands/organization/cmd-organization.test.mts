@@ -7,12 +7,15 @@
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 import { cmdOrganization } from '../../../../src/commands/organization/cmd-organization.mts'
+import { cmdOrganizationAuditLog } from '../../../../src/commands/organization/cmd-organization-audit-log.mts'
 import { cmdOrganizationDependencies } from '../../../../src/commands/organization/cmd-organization-dependencies.mts'
 import { cmdOrganizationList } from '../../../../src/commands/organization/cmd-organization-list.mts'
+import { cmdOrganizationMembers } from '../../../../src/commands/organization/cmd-organization-members.mts'
 import { cmdOrganizationPolicy } from '../../../../src/commands/organization/cmd-organization-policy.mts'
 import { cmdOrganizationPolicyLicense } from '../../../../src/commands/organization/cmd-organization-policy-license.mts'
 import { cmdOrganizationPolicySecurity } from '../../../../src/commands/organization/cmd-organization-policy-security.mts'
 import { cmdOrganizationQuota } from '../../../../src/commands/organization/cmd-organization-quota.mts'
+import { cmdOrganizationTokens } from '../../../../src/commands/organization/cmd-organization-tokens.mts'
 
 const mockLogger = vi.hoisted(() => ({
   error: vi.fn(),
@@ -73,10 +76,13 @@ describe('cmd-organization', () => {
       // Subcommand identity (each entry IS the imported src module instance)
       // is asserted in the "subcommand validation" block below.
       expect(Object.keys(config.subcommands).toSorted()).toEqual([
+        'audit-log',
         'dependencies',
         'list',
+        'members',
         'policy',
         'quota',
+        'tokens',
       ])
       expect(callOptions.description).toBe(
         'Manage Socket organization account details',
@@ -137,6 +143,9 @@ describe('cmd-organization', () => {
         'list',
         'quota',
         'policy',
+        'members',
+        'tokens',
+        'audit-log',
       ])
     })
 
@@ -185,12 +194,15 @@ describe('cmd-organization', () => {
       // Reference-identity checks (=== inside the expect(actual) call): the
       // routed subcommand must BE the imported src module instance, so the
       // -stable alias (a different module instance) can't stand in here.
+      expect(subcommands['audit-log'] === cmdOrganizationAuditLog).toBe(true)
       expect(subcommands.dependencies === cmdOrganizationDependencies).toBe(
         true,
       )
       expect(subcommands.list === cmdOrganizationList).toBe(true)
+      expect(subcommands.members === cmdOrganizationMembers).toBe(true)
       expect(subcommands.policy === cmdOrganizationPolicy).toBe(true)
       expect(subcommands.quota === cmdOrganizationQuota).toBe(true)
+      expect(subcommands.tokens === cmdOrganizationTokens).toBe(true)
     })
   })
 
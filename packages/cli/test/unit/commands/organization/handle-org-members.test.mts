@@ -0,0 +1,92 @@
+/**
+ * Unit tests for organization member administration.
+ *
+ * Purpose: Tests looking up members by ID or email and removing them.
+ * Validates that the member is resolved before the removal is sent and that
+ * fetch failures are passed through.
+ *
+ * Related Files: - src/commands/organization/handle-org-members.mts
+ * (implementation) - src/commands/organization/fetch-org-members.mts (API
+ * fetchers)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  findOrgMember,
+  removeOrgMember,
+} from '../../../../src/commands/organization/handle-org-members.mts'
+import {
+  createErrorResult,
+  createSuccessResult,
+} from '../../../helpers/mocks.mts'
+
+const mockFetchOrgMembers = vi.hoisted(() => vi.fn())
+const mockFetchRemoveOrgMember = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/organization/fetch-org-members.mts'),
+  () => ({
+    ORG_MEMBER_ROLES: ['admin', 'member'],
+    fetchInviteOrgMember: vi.fn(),
+    fetchOrgMembers: mockFetchOrgMembers,
+    fetchRemoveOrgMember: mockFetchRemoveOrgMember,
+  }),
+)
+
+const MEMBERS = [
+  { id: 'member-1', email: 'Jane@Example.com', role: 'admin' },
+  { id: 'member-2', email: 'joe@example.com', role: 'member' },
+]
+
+describe('findOrgMember', () => {
+  it('finds members by ID or email', () => {
+    expect(findOrgMember(MEMBERS, 'member-2')).toEqual({
+      ok: true,
+      data: MEMBERS[1],
+    })
+    expect(findOrgMember(MEMBERS, 'jane@example.com')).toEqual({
+      ok: true,
+      data: MEMBERS[0],
+    })
+  })
+
+  it('fails for unknown members', () => {
+    expect(findOrgMember(MEMBERS, 'sam@example.com')).toMatchObject({
+      ok: false,
+      message: 'Member not found',
+    })
+  })
+})
+
+describe('removeOrgMember', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFetchOrgMembers.mockResolvedValue(createSuccessResult(MEMBERS))
+    mockFetchRemoveOrgMember.mockResolvedValue(createSuccessResult({}))
+  })
+
+  it('removes the member by ID', async () => {
+    const result = await removeOrgMember('test-org', 'joe@example.com')
+
+    expect(mockFetchRemoveOrgMember).toHaveBeenCalledWith(
+      'test-org',
+      'member-2',
+    )
+    expect(result).toEqual({ ok: true, data: MEMBERS[1] })
+  })
+
+  it('does not send a removal for unknown members', async () => {
+    const result = await removeOrgMember('test-org', 'sam@example.com')
+
+    expect(result.ok).toBe(false)
+    expect(mockFetchRemoveOrgMember).not.toHaveBeenCalled()
+  })
+
+  it('passes fetch failures through', async () => {
+    const error = createErrorResult('Socket API error', { code: 403 })
+    mockFetchRemoveOrgMember.mockResolvedValue(error)
+
+    expect(await removeOrgMember('test-org', 'member-1')).toBe(error)
+  })
+})
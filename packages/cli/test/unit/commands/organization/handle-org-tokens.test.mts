@@ -0,0 +1,96 @@
+/**
+ * Unit tests for organization API token administration.
+ *
+ * Purpose: Tests validating token scopes and the create request, which only
+ * asks for an expiry when a lifetime is given.
+ *
+ * Related Files: - src/commands/organization/handle-org-tokens.mts
+ * (implementation) - src/commands/organization/fetch-org-tokens.mts (API
+ * fetchers)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  getInvalidTokenScopes,
+  handleCreateOrgToken,
+} from '../../../../src/commands/organization/handle-org-tokens.mts'
+import { createSuccessResult } from '../../../helpers/mocks.mts'
+
+const mockFetchCreateOrgApiToken = vi.hoisted(() => vi.fn())
+const mockOutputCreateOrgToken = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/organization/fetch-org-tokens.mts'),
+  () => ({
+    fetchCreateOrgApiToken: mockFetchCreateOrgApiToken,
+    fetchOrgApiTokens: vi.fn(),
+    fetchRevokeOrgApiToken: vi.fn(),
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/organization/output-org-tokens.mts'),
+  () => ({
+    outputCreateOrgToken: mockOutputCreateOrgToken,
+    outputOrgTokens: vi.fn(),
+    outputRevokeOrgToken: vi.fn(),
+  }),
+)
+
+describe('getInvalidTokenScopes', () => {
+  it('keeps scopes that are not API permissions', () => {
+    expect(
+      getInvalidTokenScopes([
+        'full-scans:create',
+        'repo:list',
+        'scans',
+        'Repo:List',
+        'repo:',
+      ]),
+    ).toEqual(['scans', 'Repo:List', 'repo:'])
+  })
+})
+
+describe('handleCreateOrgToken', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFetchCreateOrgApiToken.mockResolvedValue(
+      createSuccessResult({ id: 'token-1', token: 'sktsec_secret' }),
+    )
+  })
+
+  it('asks for an expiry when a lifetime is given', async () => {
+    await handleCreateOrgToken(
+      {
+        name: 'ci',
+        orgSlug: 'test-org',
+        scopes: ['full-scans:create'],
+        ttlDays: 90,
+      },
+      'json',
+    )
+
+    expect(mockFetchCreateOrgApiToken).toHaveBeenCalledWith('test-org', {
+      name: 'ci',
+      scopes: ['full-scans:create'],
+      ttl_days: 90,
+    })
+    expect(mockOutputCreateOrgToken).toHaveBeenCalledWith(
+      { ok: true, data: { id: 'token-1', token: 'sktsec_secret' } },
+      'json',
+    )
+  })
+
+  it('creates tokens that never expire without a lifetime', async () => {
+    await handleCreateOrgToken(
+      { name: '', orgSlug: 'test-org', scopes: ['repo:list'], ttlDays: 0 },
+      'text',
+    )
+
+    expect(mockFetchCreateOrgApiToken).toHaveBeenCalledWith('test-org', {
+      name: '',
+      scopes: ['repo:list'],
+    })
+  })
+})
@@ -0,0 +1,180 @@
+/**
+ * Unit tests for the bulk mode of package score.
+ *
+ * Tests reading the purl list, matching the looked up artifacts back to the
+ * requested purls, and the CSV output.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  formatBulkScoreCsv,
+  getBulkScoreData,
+  parsePurlList,
+} from '../../../../src/commands/package/bulk-score.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+const SCORE = {
+  license: 1,
+  maintenance: 0.8,
+  overall: 0.8,
+  quality: 0.9,
+  supplyChain: 0.95,
+  vulnerability: 1,
+}
+
+describe('parsePurlList', () => {
+  it('skips blank lines and comments', () => {
+    const { invalid, purls } = parsePurlList(
+      '# vendored\n\npkg:npm/express@4.18.2\npkg:pypi/requests@2.31.0  # http\n',
+    )
+    expect(invalid).toEqual([])
+    expect(purls).toEqual([
+      'pkg:npm/express@4.18.2',
+      'pkg:pypi/requests@2.31.0',
+    ])
+  })
+
+  it('adds a missing pkg: prefix and dedupes', () => {
+    const { purls } = parsePurlList(
+      'npm/express@4.18.2\r\npkg:npm/express@4.18.2\n',
+    )
+    expect(purls).toEqual(['pkg:npm/express@4.18.2'])
+  })
+
+  it('reports invalid lines with their line number', () => {
+    const { invalid, purls } = parsePurlList(
+      'pkg:npm/express\nexpress\nnpm/a b\n',
+    )
+    expect(purls).toEqual(['pkg:npm/express'])
+    expect(invalid).toEqual([
+      { line: 2, text: 'express' },
+      { line: 3, text: 'npm/a b' },
+    ])
+  })
+})
+
+describe('getBulkScoreData', () => {
+  it('matches artifacts by their input purl', () => {
+    const data = getBulkScoreData(
+      ['pkg:npm/express@4.18.2'],
+      [
+        artifact('express', {
+          inputPurl: 'pkg:npm/express@4.18.2',
+          score: SCORE,
+          version: '4.18.2',
+        }),
+      ],
+    )
+    expect(data.missing).toEqual([])
+    expect(data.packages).toEqual([
+      {
+        alerts: { critical: 0, high: 0, low: 0, middle: 0 },
+        alertTypes: [],
+        found: true,
+        purl: 'pkg:npm/express@4.18.2',
+        score: {
+          license: 100,
+          maintenance: 80,
+          quality: 90,
+          supplyChain: 95,
+          vulnerability: 100,
+        },
+      },
+    ])
+  })
+
+  it('matches artifacts by name when the version was left out or latest', () => {
+    const data = getBulkScoreData(
+      ['pkg:npm/express', 'pkg:npm/%40babel/core@latest'],
+      [
+        artifact('express', { score: SCORE, version: '4.18.2' }),
+        artifact('core', {
+          namespace: '@babel',
+          score: SCORE,
+          version: '7.0.0',
+        }),
+      ],
+    )
+    expect(data.missing).toEqual([])
+    expect(data.packages.map(p => p.found)).toEqual([true, true])
+  })
+
+  it('lists purls without an artifact as missing', () => {
+    const data = getBulkScoreData(['pkg:npm/nope@1.0.0'], [])
+    expect(data.missing).toEqual(['pkg:npm/nope@1.0.0'])
+    expect(data.packages[0]).toMatchObject({ found: false, score: undefined })
+  })
+
+  it('merges duplicate artifacts into the lowest scores and all alerts', () => {
+    const data = getBulkScoreData(
+      ['pkg:pypi/numpy@2.0.0'],
+      [
+        artifact('numpy', {
+          alerts: [{ key: 'a', severity: 'high', type: 'networkAccess' }],
+          score: SCORE,
+          type: 'pypi',
+          version: '2.0.0',
+        }),
+        artifact('numpy', {
+          alerts: [
+            { key: 'b', severity: 'high', type: 'networkAccess' },
+            { key: 'c', severity: 'critical', type: 'malware' },
+          ],
+          score: {
+            license: 1,
+            maintenance: 0.5,
+            overall: 0.5,
+            quality: 0.9,
+            supplyChain: 0.95,
+            vulnerability: 1,
+          },
+          type: 'pypi',
+          version: '2.0.0',
+        }),
+      ],
+    )
+    const row = data.packages[0]!
+    expect(row.alerts).toEqual({ critical: 1, high: 1, low: 0, middle: 0 })
+    expect(row.alertTypes).toEqual(['malware', 'networkAccess'])
+    expect(row.score?.maintenance).toBe(50)
+  })
+})
+
+describe('formatBulkScoreCsv', () => {
+  it('prints a header and one row per package', () => {
+    const data = getBulkScoreData(
+      ['pkg:npm/express@4.18.2', 'pkg:npm/nope@1.0.0'],
+      [
+        artifact('express', {
+          alerts: [{ key: 'a', severity: 'low', type: 'unmaintained' }],
+          score: SCORE,
+          version: '4.18.2',
+        }),
+      ],
+    )
+    expect(formatBulkScoreCsv(data)).toBe(
+      [
+        'purl,found,supplyChain,maintenance,quality,vulnerability,license,critical,high,middle,low,alertTypes',
+        'pkg:npm/express@4.18.2,true,95,80,90,100,100,0,0,0,1,unmaintained',
+        'pkg:npm/nope@1.0.0,false,,,,,,0,0,0,0,',
+      ].join('\n'),
+    )
+  })
+
+  it('quotes fields that hold commas or quotes', () => {
+    const csv = formatBulkScoreCsv({
+      missing: ['pkg:maven/a/b@1,2'],
+      packages: [
+        {
+          alerts: { critical: 0, high: 0, low: 0, middle: 0 },
+          alertTypes: [],
+          found: false,
+          purl: 'pkg:maven/a/b@1,2',
+          score: undefined,
+        },
+      ],
+    })
+    expect(csv.split('\n')[1]).toBe('"pkg:maven/a/b@1,2",false,,,,,,0,0,0,0,')
+  })
+})
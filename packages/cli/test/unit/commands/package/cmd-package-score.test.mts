@@ -117,6 +117,8 @@ describe('cmd-package-score', () => {
       expect(mockHandlePurlDeepScore).toHaveBeenCalledWith(
         'pkg:npm/babel-cli',
         'text',
+        false,
+        '',
       )
     })
 
@@ -132,6 +134,8 @@ describe('cmd-package-score', () => {
       expect(mockHandlePurlDeepScore).toHaveBeenCalledWith(
         'pkg:npm/babel-cli@1.0.0',
         'text',
+        false,
+        '',
       )
     })
 
@@ -143,6 +147,8 @@ describe('cmd-package-score', () => {
       expect(mockHandlePurlDeepScore).toHaveBeenCalledWith(
         'pkg:npm/babel-cli@1.0.0',
         'text',
+        false,
+        '',
       )
     })
 
@@ -158,6 +164,8 @@ describe('cmd-package-score', () => {
       expect(mockHandlePurlDeepScore).toHaveBeenCalledWith(
         'pkg:npm/babel-cli',
         'json',
+        false,
+        '',
       )
     })
 
@@ -173,6 +181,8 @@ describe('cmd-package-score', () => {
       expect(mockHandlePurlDeepScore).toHaveBeenCalledWith(
         'pkg:npm/babel-cli',
         'markdown',
+        false,
+        '',
       )
     })
 
@@ -198,6 +208,8 @@ describe('cmd-package-score', () => {
       expect(mockHandlePurlDeepScore).toHaveBeenCalledWith(
         'pkg:npm/eslint@1.0.0',
         'text',
+        false,
+        '',
       )
     })
 
@@ -209,6 +221,8 @@ describe('cmd-package-score', () => {
       expect(mockHandlePurlDeepScore).toHaveBeenCalledWith(
         'pkg:pypi/requests',
         'text',
+        false,
+        '',
       )
     })
 
@@ -243,6 +257,8 @@ describe('cmd-package-score', () => {
       expect(mockHandlePurlDeepScore).toHaveBeenCalledWith(
         'pkg:golang/github.com/steelpoor/tlsproxy@v0.0.0-20250304082521-29051ed19c60',
         'text',
+        false,
+        '',
       )
     })
 
@@ -258,6 +274,8 @@ describe('cmd-package-score', () => {
       expect(mockHandlePurlDeepScore).toHaveBeenCalledWith(
         'pkg:nuget/needpluscommonlibrary@1.0.0',
         'markdown',
+        false,
+        '',
       )
     })
   })
@@ -8,6 +8,7 @@
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 import { cmdPackageShallow } from '../../../../src/commands/package/cmd-package-shallow.mts'
+import { getCacheOverrides } from '../../../../src/util/cache/overrides.mts'
 
 import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
 
@@ -95,6 +96,7 @@ describe('cmd-package-shallow', () => {
       await cmdPackageShallow.run(['npm', 'webtorrent'], importMeta, context)
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'text',
         purls: ['pkg:npm/webtorrent'],
       })
@@ -108,6 +110,7 @@ describe('cmd-package-shallow', () => {
       )
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'text',
         purls: ['pkg:npm/webtorrent', 'pkg:npm/babel'],
       })
@@ -121,6 +124,7 @@ describe('cmd-package-shallow', () => {
       )
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'text',
         purls: ['pkg:npm/webtorrent@1.9.1'],
       })
@@ -130,6 +134,7 @@ describe('cmd-package-shallow', () => {
       await cmdPackageShallow.run(['npm/webtorrent@1.9.1'], importMeta, context)
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'text',
         purls: ['pkg:npm/webtorrent@1.9.1'],
       })
@@ -143,6 +148,7 @@ describe('cmd-package-shallow', () => {
       )
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'text',
         purls: ['pkg:npm/webtorrent@1.0.1', 'pkg:npm/babel'],
       })
@@ -156,6 +162,7 @@ describe('cmd-package-shallow', () => {
       )
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'text',
         purls: ['pkg:npm/webtorrent', 'pkg:golang/babel'],
       })
@@ -169,6 +176,7 @@ describe('cmd-package-shallow', () => {
       )
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'json',
         purls: ['pkg:npm/webtorrent'],
       })
@@ -182,6 +190,7 @@ describe('cmd-package-shallow', () => {
       )
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'markdown',
         purls: ['pkg:npm/webtorrent'],
       })
@@ -207,6 +216,7 @@ describe('cmd-package-shallow', () => {
       )
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'text',
         purls: ['pkg:npm/webtorrent@1.9.1'],
       })
@@ -220,6 +230,7 @@ describe('cmd-package-shallow', () => {
       )
 
       expect(mockHandlePurlsShallowScore).toHaveBeenCalledWith({
+        offline: false,
         outputKind: 'text',
         purls: ['pkg:maven/webtorrent', 'pkg:maven/babel'],
       })
@@ -254,5 +265,51 @@ describe('cmd-package-shallow', () => {
       expect(process.exitCode).toBe(2)
       expect(mockHandlePurlsShallowScore).not.toHaveBeenCalled()
     })
+
+    it('should bypass the cache with --no-cache', async () => {
+      await cmdPackageShallow.run(
+        ['--no-cache', 'npm', 'webtorrent'],
+        importMeta,
+        context,
+      )
+
+      expect(getCacheOverrides()).toEqual({ disabled: true, ttlMs: undefined })
+      expect(mockHandlePurlsShallowScore).toHaveBeenCalled()
+    })
+
+    it('should apply --cache-ttl', async () => {
+      await cmdPackageShallow.run(
+        ['--cache-ttl', '12h', 'npm', 'webtorrent'],
+        importMeta,
+        context,
+      )
+
+      expect(getCacheOverrides()).toEqual({
+        disabled: false,
+        ttlMs: 12 * 60 * 60 * 1000,
+      })
+    })
+
+    it('should fail with an invalid --cache-ttl', async () => {
+      await cmdPackageShallow.run(
+        ['--cache-ttl', 'soon', 'npm', 'webtorrent'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandlePurlsShallowScore).not.toHaveBeenCalled()
+    })
+
+    it('should fail when --offline is combined with --no-cache', async () => {
+      await cmdPackageShallow.run(
+        ['--offline', '--no-cache', 'npm', 'webtorrent'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandlePurlsShallowScore).not.toHaveBeenCalled()
+    })
   })
 })
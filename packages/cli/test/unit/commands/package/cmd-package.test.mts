@@ -111,7 +111,12 @@ describe('cmd-package', () => {
       const call = mockMeowWithSubcommands.mock.calls[0]
       const subcommands = call[0].subcommands
 
-      expect(Object.keys(subcommands)).toEqual(['score', 'shallow'])
+      expect(Object.keys(subcommands)).toEqual([
+        'diff',
+        'files',
+        'score',
+        'shallow',
+      ])
     })
 
     it('should pass through argv unchanged', async () => {
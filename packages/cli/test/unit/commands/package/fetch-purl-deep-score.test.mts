@@ -2,7 +2,8 @@
  * Unit tests for fetchPurlDeepScore.
  *
  * Thin wrapper around queryApiSafeJson — verifies URL encoding, info logging,
- * and that the query result is returned unchanged.
+ * that the query result is returned unchanged, and the score cache lookups
+ * that short-circuit it.
  *
  * Related Files: - src/commands/package/fetch-purl-deep-score.mts.
  */
@@ -10,6 +11,8 @@
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 const mockQueryApiSafeJson = vi.hoisted(() => vi.fn())
+const mockReadScoreCache = vi.hoisted(() => vi.fn())
+const mockStoreScoreCacheEntries = vi.hoisted(() => vi.fn())
 const mockLogger = vi.hoisted(() => ({
   info: vi.fn(),
 }))
@@ -20,12 +23,17 @@ vi.mock(import('../../../../src/util/socket/api.mts'), () => ({
 vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
   getDefaultLogger: () => mockLogger,
 }))
+vi.mock(import('../../../../src/util/socket/score-cache.mts'), () => ({
+  readScoreCache: mockReadScoreCache,
+  storeScoreCacheEntries: mockStoreScoreCacheEntries,
+}))
 
 import { fetchPurlDeepScore } from '../../../../src/commands/package/fetch-purl-deep-score.mts'
 
 describe('fetchPurlDeepScore', () => {
   beforeEach(() => {
     vi.clearAllMocks()
+    mockReadScoreCache.mockResolvedValue(undefined)
   })
 
   it('encodes the PURL into the request path', async () => {
@@ -74,4 +82,44 @@ describe('fetchPurlDeepScore', () => {
 
     expect(result).toBe(response)
   })
+
+  it('caches successful responses', async () => {
+    const data = { purl: 'pkg:npm/foo@1.0.0' }
+    mockQueryApiSafeJson.mockResolvedValueOnce({ ok: true, data })
+
+    await fetchPurlDeepScore('pkg:npm/foo@1.0.0')
+
+    expect(mockStoreScoreCacheEntries).toHaveBeenCalledWith('deep', [
+      ['pkg:npm/foo@1.0.0', data],
+    ])
+  })
+
+  it('returns cached data without querying the API', async () => {
+    const data = { purl: 'pkg:npm/foo@1.0.0' }
+    mockReadScoreCache.mockResolvedValueOnce(data)
+
+    const result = await fetchPurlDeepScore('pkg:npm/foo@1.0.0', {
+      offline: true,
+    })
+
+    expect(mockReadScoreCache).toHaveBeenCalledWith(
+      'deep',
+      'pkg:npm/foo@1.0.0',
+      { allowStale: true },
+    )
+    expect(mockQueryApiSafeJson).not.toHaveBeenCalled()
+    expect(result).toEqual({ ok: true, data })
+  })
+
+  it('fails offline on a cache miss', async () => {
+    const result = await fetchPurlDeepScore('pkg:npm/foo@1.0.0', {
+      offline: true,
+    })
+
+    expect(mockQueryApiSafeJson).not.toHaveBeenCalled()
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Package data not cached',
+    })
+  })
 })
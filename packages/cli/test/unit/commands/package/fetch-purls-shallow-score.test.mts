@@ -9,7 +9,8 @@
  * handling - API call error scenarios (rate limits, large batches) - Custom SDK
  * options (API tokens, base URLs) - Empty PURL array handling - Mixed ecosystem
  * PURL types (npm, pypi, maven, gem) - Large batch processing (100+ packages) -
- * Null prototype usage for security.
+ * Splitting lookups into API batches - Null prototype usage for security - Score cache hits, writes and offline
+ * misses.
  *
  * Testing Approach: Uses SDK test helpers to mock Socket API interactions.
  * Tests various batch sizes and PURL formats to ensure robust multi-package
@@ -21,7 +22,7 @@
  * (SDK setup)
  */
 
-import { describe, expect, it, vi } from 'vitest'
+import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 import { fetchPurlsShallowScore } from '../../../../src/commands/package/fetch-purls-shallow-score.mts'
 import {
@@ -33,6 +34,8 @@ import {
 // Mock the dependencies.
 const mockHandleApiCall = vi.hoisted(() => vi.fn())
 const mockSetupSdk = vi.hoisted(() => vi.fn())
+const mockReadScoreCache = vi.hoisted(() => vi.fn())
+const mockStoreScoreCacheEntries = vi.hoisted(() => vi.fn())
 const mockInfo = vi.hoisted(() => vi.fn())
 const mockGetDefaultLogger = vi.hoisted(() =>
   vi.fn(() => ({
@@ -52,7 +55,18 @@ vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
   getDefaultLogger: mockGetDefaultLogger,
 }))
 
+vi.mock(import('../../../../src/util/socket/score-cache.mts'), () => ({
+  readScoreCache: mockReadScoreCache,
+  storeScoreCacheEntries: mockStoreScoreCacheEntries,
+}))
+
 describe('fetchPurlsShallowScore', () => {
+  beforeEach(() => {
+    mockReadScoreCache.mockReset().mockResolvedValue(undefined)
+    mockStoreScoreCacheEntries.mockReset()
+    mockSetupSdk.mockClear()
+  })
+
   it('fetches purls shallow scores successfully', async () => {
     const { mockHandleApi, mockSdk } = await setupSdkMockSuccess(
       'batchPackageFetch',
@@ -130,10 +144,8 @@ describe('fetchPurlsShallowScore', () => {
 
     const result = await fetchPurlsShallowScore([])
 
-    expect(mockSdk.batchPackageFetch).toHaveBeenCalledWith(
-      { components: [] },
-      { alerts: 'true' },
-    )
+    // There is no batch to send.
+    expect(mockSdk.batchPackageFetch).not.toHaveBeenCalled()
     expect(result.ok).toBe(true)
     expect(result.data).toEqual([])
   })
@@ -170,6 +182,36 @@ describe('fetchPurlsShallowScore', () => {
     expect(result.data).toHaveLength(100)
   })
 
+  it('splits large lookups into batches', async () => {
+    const purls = Array(1200)
+      .fill(0)
+      .map((_, i) => `pkg:npm/package-${i}@1.0.0`)
+    const { mockHandleApi, mockSdk } = await setupSdkMockSuccess(
+      'batchPackageFetch',
+      [],
+    )
+    mockHandleApi.mockImplementation(async () => ({
+      ok: true,
+      data: [{ type: 'npm', name: 'package', batchIndex: 1 }],
+    }))
+
+    const result = await fetchPurlsShallowScore(purls)
+
+    expect(mockSdk.batchPackageFetch).toHaveBeenCalledTimes(3)
+    expect(mockSdk.batchPackageFetch).toHaveBeenNthCalledWith(
+      3,
+      { components: purls.slice(1000).map(purl => ({ purl })) },
+      { alerts: 'true' },
+    )
+    // Batch indexes point into the whole lookup, cache keys into the batch.
+    expect(
+      (result.data as Array<{ batchIndex?: number }>).map(a => a.batchIndex),
+    ).toEqual([1, 501, 1001])
+    expect(mockStoreScoreCacheEntries).toHaveBeenCalledWith('shallow', [
+      [purls[1001], { type: 'npm', name: 'package', batchIndex: 1 }],
+    ])
+  })
+
   it('uses null prototype for options', async () => {
     const { mockSdk } = await setupSdkMockSuccess('batchPackageFetch', [])
 
@@ -179,4 +221,83 @@ describe('fetchPurlsShallowScore', () => {
     // The function should work without prototype pollution issues.
     expect(mockSdk.batchPackageFetch).toHaveBeenCalled()
   })
+
+  it('caches fetched artifacts under the requested purl', async () => {
+    const artifact = {
+      type: 'npm',
+      name: 'lodash',
+      version: '4.17.21',
+      inputPurl: 'pkg:npm/lodash',
+    }
+    await setupSdkMockSuccess('batchPackageFetch', [artifact])
+
+    await fetchPurlsShallowScore(['pkg:npm/lodash'])
+
+    expect(mockStoreScoreCacheEntries).toHaveBeenCalledWith('shallow', [
+      ['pkg:npm/lodash', artifact],
+    ])
+  })
+
+  it('only requests purls missing from the cache', async () => {
+    const cachedArtifact = { type: 'npm', name: 'lodash', version: '4.17.21' }
+    mockReadScoreCache.mockImplementation(async (_kind, purl) =>
+      purl === 'pkg:npm/lodash@4.17.21' ? cachedArtifact : undefined,
+    )
+    const fetchedArtifact = { type: 'npm', name: 'express', version: '4.18.2' }
+    const { mockSdk } = await setupSdkMockSuccess('batchPackageFetch', [
+      fetchedArtifact,
+    ])
+
+    const result = await fetchPurlsShallowScore([
+      'pkg:npm/lodash@4.17.21',
+      'pkg:npm/express@4.18.2',
+    ])
+
+    expect(mockSdk.batchPackageFetch).toHaveBeenCalledWith(
+      { components: [{ purl: 'pkg:npm/express@4.18.2' }] },
+      { alerts: 'true' },
+    )
+    expect(result).toEqual({
+      ok: true,
+      data: [cachedArtifact, fetchedArtifact],
+    })
+  })
+
+  it('serves fully cached lookups without the API', async () => {
+    mockReadScoreCache.mockResolvedValue({ type: 'npm', name: 'lodash' })
+
+    const result = await fetchPurlsShallowScore(['pkg:npm/lodash'], {
+      offline: true,
+    })
+
+    expect(mockReadScoreCache).toHaveBeenCalledWith(
+      'shallow',
+      'pkg:npm/lodash',
+      { allowStale: true },
+    )
+    expect(mockSetupSdk).not.toHaveBeenCalled()
+    expect(result).toEqual({
+      ok: true,
+      data: [{ type: 'npm', name: 'lodash' }],
+    })
+  })
+
+  it('fails offline when a purl is not cached', async () => {
+    mockReadScoreCache.mockImplementation(async (_kind, purl) =>
+      purl === 'pkg:npm/lodash' ? { type: 'npm', name: 'lodash' } : undefined,
+    )
+
+    const result = await fetchPurlsShallowScore(
+      ['pkg:npm/lodash', 'pkg:npm/left-pad'],
+      { offline: true },
+    )
+
+    expect(mockSetupSdk).not.toHaveBeenCalled()
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Package data not cached',
+    })
+    expect(result.ok ? '' : result.cause).toContain('pkg:npm/left-pad')
+    expect(result.ok ? '' : result.cause).not.toContain('pkg:npm/lodash')
+  })
 })
@@ -71,11 +71,14 @@ describe('handlePurlDeepScore', () => {
     const purl = 'pkg:npm/package1@1.0.0'
     await handlePurlDeepScore(purl, 'json')
 
-    expect(mockFetchPurlDeepScore).toHaveBeenCalledWith(purl)
+    expect(mockFetchPurlDeepScore).toHaveBeenCalledWith(purl, {
+      offline: false,
+    })
     expect(mockOutputPurlsDeepScore).toHaveBeenCalledWith(
       purl,
       mockData,
       'json',
+      '',
     )
   })
 
@@ -89,11 +92,14 @@ describe('handlePurlDeepScore', () => {
     const purl = 'pkg:npm/package1@1.0.0'
     await handlePurlDeepScore(purl, 'text')
 
-    expect(mockFetchPurlDeepScore).toHaveBeenCalledWith(purl)
+    expect(mockFetchPurlDeepScore).toHaveBeenCalledWith(purl, {
+      offline: false,
+    })
     expect(mockOutputPurlsDeepScore).toHaveBeenCalledWith(
       purl,
       mockError,
       'text',
+      '',
     )
   })
 
@@ -115,6 +121,7 @@ describe('handlePurlDeepScore', () => {
       purl,
       mockData,
       'markdown',
+      '',
     )
   })
 
@@ -167,7 +174,9 @@ describe('handlePurlDeepScore', () => {
 
       await handlePurlDeepScore(purl, 'json')
 
-      expect(mockFetchPurlDeepScore).toHaveBeenCalledWith(purl)
+      expect(mockFetchPurlDeepScore).toHaveBeenCalledWith(purl, {
+        offline: false,
+      })
     }
   })
 
@@ -189,6 +198,7 @@ describe('handlePurlDeepScore', () => {
       purl,
       mockData,
       'text',
+      '',
     )
   })
 })
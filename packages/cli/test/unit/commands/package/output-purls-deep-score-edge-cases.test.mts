@@ -2,7 +2,7 @@
  * Unit tests for output-purls-deep-score edge cases.
  *
  * Purpose: Tests the no-dependencies markdown-report path and the
- * outputPurlsDeepScore exit-code / output-kind branches.
+ * outputPurlsDeepScore exit-code / output-kind / --output-template branches.
  *
  * Related Files:
  *
@@ -10,7 +10,12 @@
  * - Src/commands/package/fixtures/*.json (test fixtures)
  */
 
-import { describe, expect, it } from 'vitest'
+import { mkdtempSync, rmSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { describe, expect, it, vi } from 'vitest'
 
 import nugetDeep from '../../../../src/commands/package/fixtures/nuget_deep.json' with { type: 'json' }
 import {
@@ -223,5 +228,57 @@ describe('package score output', async () => {
         outputPurlsDeepScore('pkg:nuget/test', result as unknown, 'text'),
       ).resolves.toBeUndefined()
     })
+
+    describe('with --output-template', () => {
+      const result = {
+        ok: true as const,
+        data: nugetDeep.data,
+      }
+
+      function writeTemplate(content: string): string {
+        const tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-score-'))
+        const filepath = path.join(tmpDir, 'score.hbs')
+        writeFileSync(filepath, content)
+        return filepath
+      }
+
+      it('renders the score data with the template', async () => {
+        const filepath = writeTemplate(
+          '{{purl}} scored {{self.score.overall}}',
+        )
+        const logSpy = vi.spyOn(getDefaultLogger(), 'log')
+        try {
+          await outputPurlsDeepScore(
+            'pkg:nuget/test',
+            result as unknown,
+            'text',
+            filepath,
+          )
+          expect(logSpy).toHaveBeenCalledWith(
+            `${nugetDeep.data.purl} scored ${nugetDeep.data.self.score.overall}`,
+          )
+        } finally {
+          logSpy.mockRestore()
+          rmSync(path.dirname(filepath), { force: true, recursive: true })
+        }
+      })
+
+      it('fails for an invalid template', async () => {
+        const filepath = writeTemplate('{{#each self.alerts}}')
+        process.exitCode = undefined
+        try {
+          await outputPurlsDeepScore(
+            'pkg:nuget/test',
+            result as unknown,
+            'text',
+            filepath,
+          )
+          expect(process.exitCode).toBe(1)
+        } finally {
+          process.exitCode = undefined
+          rmSync(path.dirname(filepath), { force: true, recursive: true })
+        }
+      })
+    })
   })
 })
@@ -0,0 +1,130 @@
+/**
+ * Unit tests for the version comparison of `socket package diff`.
+ *
+ * Purpose: Tests how two tarballs of a package are compared and which
+ * changes are pulled out for review.
+ *
+ * Test Coverage: - Version specs - Added, removed, modified and binary files
+ * - New capabilities - Install script and dependency changes.
+ *
+ * Related Files: - src/commands/package/package-diff.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  detectCapabilities,
+  diffDependencies,
+  diffInstallScripts,
+  diffPackageFiles,
+  getNewCapabilities,
+  parsePackageDiffSpec,
+} from '../../../../src/commands/package/package-diff.mts'
+
+function toFiles(files: Record<string, string | Buffer>): Map<string, Buffer> {
+  return new Map(
+    Object.entries(files).map(({ 0: path, 1: data }) => [
+      path,
+      Buffer.isBuffer(data) ? data : Buffer.from(data),
+    ]),
+  )
+}
+
+describe('parsePackageDiffSpec', () => {
+  it('reads names and purls', () => {
+    expect(parsePackageDiffSpec('lodash@4.17.21')).toEqual({
+      name: 'lodash',
+      version: '4.17.21',
+    })
+    expect(parsePackageDiffSpec('pkg:npm/%40sigstore/cli@0.8.0')).toEqual({
+      name: '@sigstore/cli',
+      version: '0.8.0',
+    })
+  })
+
+  it('rejects specs without a version and other ecosystems', () => {
+    expect(parsePackageDiffSpec('@sigstore/cli')).toBeUndefined()
+    expect(parsePackageDiffSpec('lodash@')).toBeUndefined()
+    expect(parsePackageDiffSpec('pkg:pypi/requests@2.0.0')).toBeUndefined()
+  })
+})
+
+describe('diffPackageFiles', () => {
+  it('lists changed files with their diffs', () => {
+    const files = diffPackageFiles(
+      toFiles({
+        'index.js': 'a\n',
+        'logo.png': Buffer.from([0x89, 0, 1]),
+        'old.js': 'x\n',
+        'same.js': 's\n',
+      }),
+      toFiles({
+        'index.js': 'b\n',
+        'logo.png': Buffer.from([0x89, 0, 2]),
+        'new.js': 'y\n',
+        'same.js': 's\n',
+      }),
+    )
+    expect(files.map(f => [f.path, f.status, f.skipped])).toEqual([
+      ['index.js', 'modified', undefined],
+      ['logo.png', 'modified', 'binary'],
+      ['new.js', 'added', undefined],
+      ['old.js', 'removed', undefined],
+    ])
+    expect(files[0]!.diff).toBe(
+      '--- a/index.js\n+++ b/index.js\n@@ -1 +1 @@\n-a\n+b',
+    )
+    expect(files[2]!.diff).toContain('--- /dev/null')
+  })
+})
+
+describe('getNewCapabilities', () => {
+  it('reports capabilities only the new version uses', () => {
+    const before = detectCapabilities(
+      toFiles({ 'index.js': "const fs = require('fs')\n" }),
+    )
+    const after = detectCapabilities(
+      toFiles({
+        'index.d.ts': "import { request } from 'node:https'\n",
+        'index.js': "const fs = require('node:fs/promises')\n",
+        'lib/run.mjs': "import { exec } from 'child_process'\n",
+        'lib/send.cjs': "fetch('https://example.com')\n",
+      }),
+    )
+    expect(getNewCapabilities(before, after)).toEqual([
+      { capability: 'childProcess', files: ['lib/run.mjs'] },
+      { capability: 'network', files: ['lib/send.cjs'] },
+    ])
+  })
+})
+
+describe('diffInstallScripts', () => {
+  it('reports added, changed and removed install scripts', () => {
+    expect(
+      diffInstallScripts(
+        { scripts: { install: 'node-gyp rebuild', test: 'vitest' } },
+        { scripts: { install: 'node build.js', postinstall: 'node x.js' } },
+      ),
+    ).toEqual([
+      { after: 'node build.js', before: 'node-gyp rebuild', name: 'install' },
+      { after: 'node x.js', name: 'postinstall' },
+    ])
+  })
+})
+
+describe('diffDependencies', () => {
+  it('compares each dependency field', () => {
+    expect(
+      diffDependencies(
+        { dependencies: { ms: '^2.0.0', debug: '^4.0.0' } },
+        {
+          dependencies: { debug: '^4.0.0', ms: '^2.1.0' },
+          optionalDependencies: { fsevents: '^2.3.0' },
+        },
+      ),
+    ).toEqual([
+      { after: '^2.1.0', before: '^2.0.0', field: 'dependencies', name: 'ms' },
+      { after: '^2.3.0', field: 'optionalDependencies', name: 'fsevents' },
+    ])
+  })
+})
@@ -0,0 +1,131 @@
+/**
+ * Unit tests for the static inspection of `socket package files`.
+ *
+ * Purpose: Tests how the source commit of a version is found and how its
+ * tarball files are compared with the source tree.
+ *
+ * Test Coverage: - Git blob hashes - Provenance, gitHead and monorepo
+ * directories - Added, modified and unchanged files - Unsupported git
+ * providers.
+ *
+ * Related Files: - src/commands/package/package-files.mts (implementation)
+ */
+
+import { describe, expect, it, vi } from 'vitest'
+
+import {
+  comparePackageFiles,
+  fetchSourceTree,
+  getGitBlobSha,
+  getPackageSource,
+} from '../../../../src/commands/package/package-files.mts'
+
+import type { NpmVersionManifest } from '../../../../src/commands/verify/fetch-npm-provenance.mts'
+
+const mockGetOctokit = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/util/git/github.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    getOctokit: mockGetOctokit,
+  }),
+)
+
+const MANIFEST: NpmVersionManifest = {
+  gitHead: 'aaaa1111',
+  name: '@acme/utils',
+  repository: {
+    directory: 'packages/utils/',
+    type: 'git',
+    url: 'git+https://github.com/acme/monorepo.git',
+  },
+  version: '1.0.0',
+}
+
+describe('getGitBlobSha', () => {
+  it('hashes contents the way git does', () => {
+    expect(getGitBlobSha(Buffer.from('hello\n'))).toBe(
+      'ce013625030ba8dba906f756967f9e9ca394464a',
+    )
+  })
+})
+
+describe('getPackageSource', () => {
+  it('prefers the attested commit over the gitHead', () => {
+    expect(
+      getPackageSource(MANIFEST, {
+        commit: 'bbbb2222',
+        predicateType: 'https://slsa.dev/provenance/v1',
+        ref: 'refs/tags/v1.0.0',
+        repository: 'https://github.com/acme/monorepo',
+      }),
+    ).toEqual({
+      commit: 'bbbb2222',
+      directory: 'packages/utils',
+      origin: 'provenance',
+      ref: 'refs/tags/v1.0.0',
+      repository: 'github.com/acme/monorepo',
+    })
+  })
+
+  it('falls back to the gitHead of the manifest', () => {
+    expect(getPackageSource(MANIFEST, undefined)).toMatchObject({
+      commit: 'aaaa1111',
+      origin: 'gitHead',
+      repository: 'github.com/acme/monorepo',
+    })
+    expect(
+      getPackageSource({ ...MANIFEST, gitHead: undefined }, undefined),
+    ).toBeUndefined()
+  })
+})
+
+describe('comparePackageFiles', () => {
+  const files = new Map([
+    ['package.json', Buffer.from('{}\n')],
+    ['index.js', Buffer.from('hello\n')],
+    ['dist/extra.js', Buffer.from('console.log(1)\n')],
+  ])
+
+  it('marks files by how they compare with the source tree', () => {
+    const tree = new Map([
+      ['packages/utils/index.js', 'ce013625030ba8dba906f756967f9e9ca394464a'],
+      [
+        'packages/utils/package.json',
+        '0000000000000000000000000000000000000000',
+      ],
+    ])
+
+    expect(comparePackageFiles(files, tree, 'packages/utils')).toEqual([
+      { path: 'dist/extra.js', size: 15, status: 'added' },
+      { path: 'index.js', size: 6, status: 'same' },
+      { path: 'package.json', size: 3, status: 'modified' },
+    ])
+  })
+
+  it('only lists the files without a source tree', () => {
+    expect(comparePackageFiles(files).map(f => f.status)).toEqual([
+      undefined,
+      undefined,
+      undefined,
+    ])
+  })
+})
+
+describe('fetchSourceTree', () => {
+  it('fails for repositories outside of GitHub', async () => {
+    const result = await fetchSourceTree({
+      commit: 'aaaa1111',
+      origin: 'gitHead',
+      repository: 'gitlab.com/acme/utils',
+    })
+
+    expect(result).toEqual({
+      ok: false,
+      message: 'Unsupported provider',
+      cause:
+        'Only GitHub repositories can be compared, gitlab.com/acme/utils is hosted on gitlab.com',
+    })
+    expect(mockGetOctokit).not.toHaveBeenCalled()
+  })
+})
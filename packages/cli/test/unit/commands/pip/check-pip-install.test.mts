@@ -0,0 +1,112 @@
+/**
+ * Unit tests for the socket pip pre-install check.
+ *
+ * Purpose: Tests which pip invocations are resolved and scored before handing
+ * off to Socket Firewall.
+ *
+ * Test Coverage: - install detection after global options - Opt-outs for
+ * --dry-run, --report and help - Resolution failure falls through with a
+ * warning - Resolved purls are passed to the install check.
+ *
+ * Related Files: - src/commands/pip/check-pip-install.mts (implementation) -
+ * src/util/install-check/check.mts (policy check)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  checkPipInstall,
+  getPipInstallArgs,
+} from '../../../../src/commands/pip/check-pip-install.mts'
+
+const mockResolvePipInstall = vi.hoisted(() => vi.fn())
+const mockCheckPackagesBeforeInstall = vi.hoisted(() => vi.fn())
+const mockWarn = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/python/pip-report.mts'), () => ({
+  resolvePipInstall: mockResolvePipInstall,
+}))
+
+vi.mock(import('../../../../src/util/install-check/check.mts'), () => ({
+  checkPackagesBeforeInstall: mockCheckPackagesBeforeInstall,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => ({ warn: mockWarn }),
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/spinner/default'), () => ({
+  getDefaultSpinner: () => ({ start: vi.fn(), stop: vi.fn() }),
+}))
+
+describe('getPipInstallArgs', () => {
+  it('returns the arguments after install', () => {
+    expect(getPipInstallArgs(['install', 'requests'])).toEqual(['requests'])
+    expect(getPipInstallArgs(['-q', 'install', '-r', 'req.txt'])).toEqual([
+      '-r',
+      'req.txt',
+    ])
+  })
+
+  it('skips other subcommands', () => {
+    expect(getPipInstallArgs(['list'])).toBeUndefined()
+    expect(getPipInstallArgs(['--version'])).toBeUndefined()
+  })
+
+  it('skips installs that already resolve without installing', () => {
+    expect(getPipInstallArgs(['install', '--dry-run', 'x'])).toBeUndefined()
+    expect(
+      getPipInstallArgs(['install', '--report=out.json', 'x']),
+    ).toBeUndefined()
+    expect(getPipInstallArgs(['install', '--help'])).toBeUndefined()
+  })
+})
+
+describe('checkPipInstall', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('does nothing for non-install commands', async () => {
+    const result = await checkPipInstall(['freeze'], 'pip')
+
+    expect(result.ok).toBe(true)
+    expect(mockResolvePipInstall).not.toHaveBeenCalled()
+  })
+
+  it('warns and proceeds when resolution fails', async () => {
+    mockResolvePipInstall.mockResolvedValue({
+      ok: false,
+      message: 'Unable to resolve packages',
+      cause: 'pip 22.2 or newer is required',
+    })
+
+    const result = await checkPipInstall(['install', 'x'], 'pip3')
+
+    expect(result.ok).toBe(true)
+    expect(mockResolvePipInstall).toHaveBeenCalledWith(['pip3'], ['x'])
+    expect(mockWarn).toHaveBeenCalledWith(
+      expect.stringContaining('Skipping the Socket pre-install check'),
+    )
+    expect(mockCheckPackagesBeforeInstall).not.toHaveBeenCalled()
+  })
+
+  it('checks the resolved packages', async () => {
+    mockResolvePipInstall.mockResolvedValue({
+      ok: true,
+      data: ['pkg:pypi/x@1.0.0'],
+    })
+    mockCheckPackagesBeforeInstall.mockResolvedValue({
+      ok: false,
+      message: 'Install blocked by Socket security policy',
+    })
+
+    const result = await checkPipInstall(['install', 'x'], 'pip')
+
+    expect(result.ok).toBe(false)
+    expect(mockCheckPackagesBeforeInstall).toHaveBeenCalledWith(
+      ['pkg:pypi/x@1.0.0'],
+      { commandPath: 'socket pip' },
+    )
+  })
+})
@@ -25,6 +25,14 @@ vi.mock(import('../../../../src/util/cli/with-subcommands.mjs'), () => ({
   meowOrExit: vi.fn(),
 }))
 
+vi.mock(
+  import('../../../../src/commands/pip/check-pip-install.mts'),
+  async () => {
+    const { passingInstallCheck } = await import('../../../helpers/mocks.mts')
+    return passingInstallCheck('checkPipInstall')
+  },
+)
+
 const mockWhichReal = vi.mocked(binModule.whichReal)
 const mockSpawnSfwDlx = vi.mocked(spawnModule.spawnSfwDlx)
 const mockFilterFlags = vi.mocked(cmdModule.filterFlags)
@@ -408,87 +416,25 @@ describe('cmd-pip', () => {
   })
 
   describe('common pip operations', () => {
-    it('should handle pip install', async () => {
-      const argv = ['install', 'requests']
-      const importMeta = { url: import.meta.url } as ImportMeta
-      const context: CliCommandContext = {
-        parentName: 'socket',
-      }
-
-      mockFilterFlags.mockReturnValue(['install', 'requests'])
-
-      await cmdPip.run(argv, importMeta, context)
-
-      expect(mockSpawnSfwDlx).toHaveBeenCalledWith(
-        ['pip', 'install', 'requests'],
-        expect.any(Object),
-      )
-    })
-
-    it('should handle pip list', async () => {
-      const argv = ['list']
-      const importMeta = { url: import.meta.url } as ImportMeta
-      const context: CliCommandContext = {
-        parentName: 'socket',
-      }
-
-      mockFilterFlags.mockReturnValue(['list'])
-
-      await cmdPip.run(argv, importMeta, context)
-
-      expect(mockSpawnSfwDlx).toHaveBeenCalledWith(
-        ['pip', 'list'],
-        expect.any(Object),
-      )
-    })
-
-    it('should handle pip freeze', async () => {
-      const argv = ['freeze']
-      const importMeta = { url: import.meta.url } as ImportMeta
-      const context: CliCommandContext = {
-        parentName: 'socket',
-      }
-
-      mockFilterFlags.mockReturnValue(['freeze'])
-
-      await cmdPip.run(argv, importMeta, context)
-
-      expect(mockSpawnSfwDlx).toHaveBeenCalledWith(
-        ['pip', 'freeze'],
-        expect.any(Object),
-      )
-    })
-
-    it('should handle pip uninstall', async () => {
-      const argv = ['uninstall', 'flask', '-y']
-      const importMeta = { url: import.meta.url } as ImportMeta
-      const context: CliCommandContext = {
-        parentName: 'socket',
-      }
-
-      mockFilterFlags.mockReturnValue(['uninstall', 'flask', '-y'])
-
-      await cmdPip.run(argv, importMeta, context)
-
-      expect(mockSpawnSfwDlx).toHaveBeenCalledWith(
-        ['pip', 'uninstall', 'flask', '-y'],
-        expect.any(Object),
-      )
-    })
-
-    it('should handle pip install with requirements.txt', async () => {
-      const argv = ['install', '-r', 'requirements.txt']
+    it.each([
+      'install requests',
+      'list',
+      'freeze',
+      'uninstall flask -y',
+      'install -r requirements.txt',
+    ])('should handle pip %s', async command => {
+      const argv = command.split(' ')
       const importMeta = { url: import.meta.url } as ImportMeta
       const context: CliCommandContext = {
         parentName: 'socket',
       }
 
-      mockFilterFlags.mockReturnValue(['install', '-r', 'requirements.txt'])
+      mockFilterFlags.mockReturnValue(argv)
 
       await cmdPip.run(argv, importMeta, context)
 
       expect(mockSpawnSfwDlx).toHaveBeenCalledWith(
-        ['pip', 'install', '-r', 'requirements.txt'],
+        ['pip', ...argv],
         expect.any(Object),
       )
     })
@@ -25,6 +25,14 @@ vi.mock(import('../../../../src/util/cli/with-subcommands.mjs'), () => ({
   meowOrExit: vi.fn(),
 }))
 
+vi.mock(
+  import('../../../../src/commands/pip/check-pip-install.mts'),
+  async () => {
+    const { passingInstallCheck } = await import('../../../helpers/mocks.mts')
+    return passingInstallCheck('checkPipInstall')
+  },
+)
+
 const mockWhichReal = vi.mocked(binModule.whichReal)
 const mockSpawnSfwDlx = vi.mocked(spawnModule.spawnSfwDlx)
 const mockFilterFlags = vi.mocked(cmdModule.filterFlags)
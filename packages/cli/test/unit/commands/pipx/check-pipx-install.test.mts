@@ -0,0 +1,129 @@
+/**
+ * Unit tests for the socket pipx pre-install check.
+ *
+ * Purpose: Tests how pipx install, inject and run invocations map to the
+ * distributions pip would resolve, and that resolution failures block.
+ *
+ * Test Coverage: - install specs and --python/--index-url/--pip-args - inject
+ * skips the venv name - run uses --spec or the app name, ignoring app args -
+ * Local scripts, URLs and other subcommands are skipped - Resolution failure
+ * blocks the install.
+ *
+ * Related Files: - src/commands/pipx/check-pipx-install.mts (implementation) -
+ * src/commands/pipx/cmd-pipx.mts (command)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  checkPipxInstall,
+  getPipxInstallTarget,
+} from '../../../../src/commands/pipx/check-pipx-install.mts'
+
+const mockResolvePipInstall = vi.hoisted(() => vi.fn())
+const mockCheckPackagesBeforeInstall = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/python/pip-report.mts'), () => ({
+  resolvePipInstall: mockResolvePipInstall,
+}))
+
+vi.mock(import('../../../../src/util/install-check/check.mts'), () => ({
+  checkPackagesBeforeInstall: mockCheckPackagesBeforeInstall,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/spinner/default'), () => ({
+  getDefaultSpinner: () => ({ start: vi.fn(), stop: vi.fn() }),
+}))
+
+describe('getPipxInstallTarget', () => {
+  it('collects install specs and pip options', () => {
+    expect(
+      getPipxInstallTarget([
+        'install',
+        '--python',
+        'python3.12',
+        '-i',
+        'https://example.test/simple',
+        '--pip-args=--pre --no-cache-dir',
+        'black',
+        'ruff==0.5.0',
+      ]),
+    ).toEqual({
+      pipArgs: [
+        '--index-url',
+        'https://example.test/simple',
+        '--pre',
+        '--no-cache-dir',
+      ],
+      python: 'python3.12',
+      specs: ['black', 'ruff==0.5.0'],
+    })
+  })
+
+  it('skips the venv name for inject', () => {
+    expect(getPipxInstallTarget(['inject', 'black', 'flake8'])).toEqual({
+      pipArgs: [],
+      python: undefined,
+      specs: ['flake8'],
+    })
+  })
+
+  it('uses --spec or the app name for run', () => {
+    expect(
+      getPipxInstallTarget(['run', 'cowsay', '-t', 'moo', 'extra'])?.specs,
+    ).toEqual(['cowsay'])
+    expect(
+      getPipxInstallTarget(['run', '--spec', 'httpie==3.2.2', 'http', 'x'])
+        ?.specs,
+    ).toEqual(['httpie==3.2.2'])
+  })
+
+  it('skips local scripts, help and other subcommands', () => {
+    expect(getPipxInstallTarget(['run', './tool.py'])).toBeUndefined()
+    expect(
+      getPipxInstallTarget(['run', 'https://example.test/tool.py']),
+    ).toBeUndefined()
+    expect(getPipxInstallTarget(['install', '--help'])).toBeUndefined()
+    expect(getPipxInstallTarget(['list'])).toBeUndefined()
+    expect(getPipxInstallTarget([])).toBeUndefined()
+  })
+})
+
+describe('checkPipxInstall', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('blocks when resolution fails', async () => {
+    mockResolvePipInstall.mockResolvedValue({
+      ok: false,
+      message: 'Unable to resolve packages',
+    })
+
+    const result = await checkPipxInstall(['install', 'black'])
+
+    expect(result.ok).toBe(false)
+    expect(mockResolvePipInstall).toHaveBeenCalledWith(
+      ['python3', '-m', 'pip'],
+      ['black'],
+      { ignoreInstalled: true },
+    )
+    expect(mockCheckPackagesBeforeInstall).not.toHaveBeenCalled()
+  })
+
+  it('checks the resolved packages', async () => {
+    mockResolvePipInstall.mockResolvedValue({
+      ok: true,
+      data: ['pkg:pypi/black@24.4.2'],
+    })
+    mockCheckPackagesBeforeInstall.mockResolvedValue({ ok: true, data: {} })
+
+    const result = await checkPipxInstall(['install', 'black'])
+
+    expect(result.ok).toBe(true)
+    expect(mockCheckPackagesBeforeInstall).toHaveBeenCalledWith(
+      ['pkg:pypi/black@24.4.2'],
+      { commandPath: 'socket pipx' },
+    )
+  })
+})
@@ -0,0 +1,65 @@
+/**
+ * Unit tests for the socket pnpm pre-install check.
+ *
+ * Purpose: Tests what `pnpm add` and `pnpm install` invocations install, as
+ * checked before handing off to Socket Firewall.
+ *
+ * Test Coverage: - Package specs of add and install - Options that take a
+ * value, and --dir - Bare installs check the lockfile - Other commands and
+ * alternate registries are skipped.
+ *
+ * Related Files: - src/commands/pnpm/check-pnpm-install.mts (implementation)
+ * - src/util/install-check/node-install.mts (resolution and scoring)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { getPnpmInstallRequest } from '../../../../src/commands/pnpm/check-pnpm-install.mts'
+
+describe('getPnpmInstallRequest', () => {
+  it('collects the registry packages of pnpm add', () => {
+    expect(
+      getPnpmInstallRequest([
+        '--filter',
+        'web',
+        'add',
+        '-D',
+        'lodash@^4',
+        './local',
+        '@scope/pkg',
+      ]),
+    ).toEqual({
+      dir: undefined,
+      fromLockfile: false,
+      specs: [
+        { name: 'lodash', spec: 'lodash@^4', version: '^4' },
+        { name: '@scope/pkg', spec: '@scope/pkg', version: 'latest' },
+      ],
+    })
+  })
+
+  it('treats pnpm install with packages as pnpm add', () => {
+    expect(getPnpmInstallRequest(['-C', 'app', 'i', 'chalk@5.3.0'])).toEqual({
+      dir: 'app',
+      fromLockfile: false,
+      specs: [{ name: 'chalk', spec: 'chalk@5.3.0', version: '5.3.0' }],
+    })
+  })
+
+  it('checks the lockfile of a bare install', () => {
+    expect(
+      getPnpmInstallRequest(['install', '--frozen-lockfile', '--dir=pkg']),
+    ).toEqual({ dir: 'pkg', fromLockfile: true, specs: [] })
+  })
+
+  it('skips other commands and registries', () => {
+    expect(getPnpmInstallRequest([])).toBeUndefined()
+    expect(getPnpmInstallRequest(['dlx', 'cowsay'])).toBeUndefined()
+    expect(getPnpmInstallRequest(['update', 'lodash'])).toBeUndefined()
+    expect(getPnpmInstallRequest(['add'])).toBeUndefined()
+    expect(getPnpmInstallRequest(['add', 'github:x/y'])).toBeUndefined()
+    expect(
+      getPnpmInstallRequest(['add', '--registry', 'https://corp.test', 'x']),
+    ).toBeUndefined()
+  })
+})
@@ -48,6 +48,14 @@ vi.mock(import('../../../../src/util/telemetry/integration.mts'), () => ({
   trackSubprocessStart: mockTrackSubprocessStart,
 }))
 
+vi.mock(
+  import('../../../../src/commands/pnpm/check-pnpm-install.mts'),
+  async () => {
+    const { passingInstallCheck } = await import('../../../helpers/mocks.mts')
+    return passingInstallCheck('checkPnpmInstall')
+  },
+)
+
 describe('cmd-pnpm', () => {
   interface MockChildProcess extends Partial<EventEmitter> {
     pid: number
@@ -0,0 +1,156 @@
+/**
+ * Unit tests for policy diff handling.
+ *
+ * Purpose: Tests where `socket policy diff` gets the org policy from and how
+ * it reports the local overrides.
+ *
+ * Test Coverage: - getPolicyDiff with a pulled file and with the API - Fetch
+ * and local policy failures - The weakened count.
+ *
+ * Related Files: - src/commands/policy/handle-policy-diff.mts (implementation)
+ * - src/util/policy/org-policy.mts (comparison)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  createErrorResult,
+  createSuccessResult,
+} from '../../../../test/helpers/index.mts'
+
+const mockFetchSecurityPolicy = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/organization/fetch-security-policy.mts'),
+  () => ({
+    fetchSecurityPolicy: mockFetchSecurityPolicy,
+  }),
+)
+
+const mockReadSocketOrgPolicySync = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/util/policy/org-policy.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    readSocketOrgPolicySync: mockReadSocketOrgPolicySync,
+  }),
+)
+
+const mockFindSocketPolicySync = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/util/policy/socket-policy.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    findSocketPolicySync: mockFindSocketPolicySync,
+  }),
+)
+
+const mockFindSocketYmlSync = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/config.mts'), async importOriginal => ({
+  ...(await importOriginal()),
+  findSocketYmlSync: mockFindSocketYmlSync,
+}))
+
+import { getPolicyDiff } from '../../../../src/commands/policy/handle-policy-diff.mts'
+
+describe('getPolicyDiff', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFindSocketPolicySync.mockReturnValue({ ok: true, data: undefined })
+    mockFindSocketYmlSync.mockReturnValue({
+      ok: true,
+      data: {
+        path: '/repo/socket.yml',
+        parsed: { wrapper: { alerts: { installScripts: 'allow' } } },
+      },
+    })
+  })
+
+  it('compares against a pulled org policy', async () => {
+    mockReadSocketOrgPolicySync.mockReturnValue({
+      ok: true,
+      data: {
+        createdAt: '',
+        default: 'medium',
+        org: 'acme',
+        rules: { installScripts: 'warn' },
+        version: 1,
+      },
+    })
+
+    const result = await getPolicyDiff({
+      cwd: '/repo',
+      orgPolicyPath: '/repo/socket.org-policy.json',
+      orgSlug: '',
+    })
+
+    expect(mockFetchSecurityPolicy).not.toHaveBeenCalled()
+    expect(mockFindSocketYmlSync).toHaveBeenCalledWith('/repo')
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        org: 'acme',
+        orgPolicyPath: '/repo/socket.org-policy.json',
+        overrides: [
+          expect.objectContaining({
+            alert: 'installScripts',
+            effect: 'weakened',
+          }),
+        ],
+        weakened: 1,
+      },
+    })
+  })
+
+  it('fetches the org policy without a pulled file', async () => {
+    mockFetchSecurityPolicy.mockResolvedValue(
+      createSuccessResult({
+        securityPolicyDefault: 'medium',
+        securityPolicyRules: { installScripts: { action: 'ignore' } },
+      }),
+    )
+
+    const result = await getPolicyDiff({
+      cwd: '/repo',
+      orgPolicyPath: undefined,
+      orgSlug: 'acme',
+    })
+
+    expect(mockFetchSecurityPolicy).toHaveBeenCalledWith('acme', {
+      commandPath: 'socket policy diff',
+    })
+    expect(result).toEqual({
+      ok: true,
+      data: { org: 'acme', overrides: [], weakened: 0 },
+    })
+  })
+
+  it('returns fetch failures', async () => {
+    const error = createErrorResult('Socket API error')
+    mockFetchSecurityPolicy.mockResolvedValue(error)
+
+    const result = await getPolicyDiff({
+      cwd: '/repo',
+      orgPolicyPath: undefined,
+      orgSlug: 'acme',
+    })
+
+    expect(result).toBe(error)
+    expect(mockFindSocketPolicySync).not.toHaveBeenCalled()
+  })
+
+  it('returns invalid local policy files', async () => {
+    mockFetchSecurityPolicy.mockResolvedValue(createSuccessResult({}))
+    mockFindSocketPolicySync.mockReturnValue({
+      ok: false,
+      message: 'Invalid policy file /repo/socket.policy.yml',
+    })
+
+    const result = await getPolicyDiff({
+      cwd: '/repo',
+      orgPolicyPath: undefined,
+      orgSlug: 'acme',
+    })
+
+    expect(result).toMatchObject({ ok: false })
+  })
+})
@@ -0,0 +1,129 @@
+/**
+ * Unit tests for policy lint handling.
+ *
+ * Purpose: Tests how `socket policy lint` locates and lints the policy file.
+ *
+ * Test Coverage: - lintPolicyFile with an explicit path - Discovery of the
+ * nearest policy file - Missing and unreadable files - Errors vs warnings -
+ * handlePolicyLint passes the result to the output.
+ *
+ * Related Files: - src/commands/policy/handle-policy-lint.mts (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockSafeReadFileSync = vi.hoisted(() => vi.fn())
+vi.mock(import('@socketsecurity/lib-stable/fs/read-file'), () => ({
+  safeReadFileSync: mockSafeReadFileSync,
+}))
+
+const mockFindSocketPolicyPathSync = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/util/policy/socket-policy.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    findSocketPolicyPathSync: mockFindSocketPolicyPathSync,
+  }),
+)
+
+const mockOutputPolicyLint = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/policy/output-policy-lint.mts'), () => ({
+  outputPolicyLint: mockOutputPolicyLint,
+}))
+
+import {
+  handlePolicyLint,
+  lintPolicyFile,
+} from '../../../../src/commands/policy/handle-policy-lint.mts'
+
+describe('lintPolicyFile', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('lints the given file', () => {
+    mockSafeReadFileSync.mockReturnValue('version: 1\n')
+
+    const result = lintPolicyFile('/repo/custom.yml', '/repo')
+
+    expect(mockFindSocketPolicyPathSync).not.toHaveBeenCalled()
+    expect(mockSafeReadFileSync).toHaveBeenCalledWith('/repo/custom.yml')
+    expect(result).toEqual({
+      ok: true,
+      data: { issues: [], path: '/repo/custom.yml' },
+    })
+  })
+
+  it('finds the nearest policy file when none is given', () => {
+    mockFindSocketPolicyPathSync.mockReturnValue('/repo/socket.policy.yml')
+    mockSafeReadFileSync.mockReturnValue('version: 1\n')
+
+    const result = lintPolicyFile(undefined, '/repo/packages/app')
+
+    expect(mockFindSocketPolicyPathSync).toHaveBeenCalledWith(
+      '/repo/packages/app',
+    )
+    expect(result.ok && result.data.path).toBe('/repo/socket.policy.yml')
+  })
+
+  it('fails when there is no policy file', () => {
+    mockFindSocketPolicyPathSync.mockReturnValue(undefined)
+
+    const result = lintPolicyFile(undefined, '/repo')
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.message).toBe('No policy file found')
+  })
+
+  it('fails when the file cannot be read', () => {
+    mockSafeReadFileSync.mockReturnValue(undefined)
+
+    const result = lintPolicyFile('/repo/missing.yml', '/repo')
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.cause).toBe('Unable to read /repo/missing.yml')
+  })
+
+  it('keeps warnings in a passing result', () => {
+    mockSafeReadFileSync.mockReturnValue('warn:\n  alerts: [installScripts]\n')
+
+    const result = lintPolicyFile('/repo/socket.policy.yml', '/repo')
+
+    expect(result.ok).toBe(true)
+    expect(result.ok && result.data.issues).toEqual([
+      expect.objectContaining({ level: 'warning', path: 'version' }),
+    ])
+  })
+
+  it('fails with the report when there are errors', () => {
+    mockSafeReadFileSync.mockReturnValue('version: 2\n')
+
+    const result = lintPolicyFile('/repo/socket.policy.yml', '/repo')
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.cause).toBe(
+      '/repo/socket.policy.yml has 1 error',
+    )
+    expect(!result.ok && result.data).toEqual({
+      issues: [expect.objectContaining({ level: 'error', path: 'version' })],
+      path: '/repo/socket.policy.yml',
+    })
+  })
+})
+
+describe('handlePolicyLint', () => {
+  it('outputs the lint result', async () => {
+    mockSafeReadFileSync.mockReturnValue('version: 1\n')
+
+    await handlePolicyLint({
+      cwd: '/repo',
+      filepath: '/repo/socket.policy.yml',
+      outputKind: 'json',
+    })
+
+    expect(mockOutputPolicyLint).toHaveBeenCalledWith(
+      { ok: true, data: { issues: [], path: '/repo/socket.policy.yml' } },
+      'json',
+    )
+  })
+})
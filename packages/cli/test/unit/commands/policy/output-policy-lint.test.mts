@@ -0,0 +1,122 @@
+/**
+ * Unit tests for policy lint output formatting.
+ *
+ * Purpose: Tests how `socket policy lint` reports its findings.
+ *
+ * Test Coverage: - outputPolicyLint function - JSON output format - Text
+ * output format - Markdown output format - Exit codes.
+ *
+ * Related Files: - src/commands/policy/output-policy-lint.mts (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockLogger = vi.hoisted(() => ({
+  log: vi.fn(),
+  error: vi.fn(),
+  warn: vi.fn(),
+  fail: vi.fn(),
+  success: vi.fn(),
+  info: vi.fn(),
+}))
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+vi.mock(import('../../../../src/util/error/fail-msg-with-badge.mts'), () => ({
+  failMsgWithBadge: (msg: string, cause?: string | undefined) =>
+    cause ? `${msg}: ${cause}` : msg,
+}))
+
+import { outputPolicyLint } from '../../../../src/commands/policy/output-policy-lint.mts'
+
+import type { PolicyLintReport } from '../../../../src/commands/policy/handle-policy-lint.mts'
+import type { CResult } from '../../../../src/types.mts'
+
+const WARNING_REPORT: PolicyLintReport = {
+  issues: [
+    {
+      level: 'warning',
+      message: 'Missing `version`; assuming version 1',
+      path: 'version',
+    },
+  ],
+  path: '/repo/socket.policy.yml',
+}
+
+const ERROR_RESULT: CResult<PolicyLintReport> = {
+  ok: false,
+  message: 'Policy file has errors',
+  cause: '/repo/socket.policy.yml has 1 error',
+  data: {
+    issues: [
+      {
+        level: 'error',
+        message: '"MIT" is both allowed and denied',
+        path: 'licenses',
+      },
+    ],
+    path: '/repo/socket.policy.yml',
+  },
+}
+
+describe('outputPolicyLint', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  it('outputs the result as JSON', async () => {
+    await outputPolicyLint(ERROR_RESULT, 'json')
+
+    const output = JSON.parse(mockLogger.log.mock.calls[0]![0] as string)
+    expect(output.ok).toBe(false)
+    expect(output.data.issues).toHaveLength(1)
+    expect(process.exitCode).toBe(1)
+  })
+
+  it('prints warnings and succeeds in text mode', async () => {
+    await outputPolicyLint({ ok: true, data: WARNING_REPORT }, 'text')
+
+    expect(mockLogger.warn).toHaveBeenCalledWith(
+      'version: Missing `version`; assuming version 1',
+    )
+    expect(mockLogger.success).toHaveBeenCalledWith(
+      '/repo/socket.policy.yml is valid, with warnings',
+    )
+    expect(process.exitCode).toBeUndefined()
+  })
+
+  it('prints errors and fails in text mode', async () => {
+    await outputPolicyLint(ERROR_RESULT, 'text')
+
+    expect(mockLogger.error).toHaveBeenCalledWith(
+      'licenses: "MIT" is both allowed and denied',
+    )
+    expect(mockLogger.fail).toHaveBeenCalledWith(
+      'Policy file has errors: /repo/socket.policy.yml has 1 error',
+    )
+    expect(mockLogger.success).not.toHaveBeenCalled()
+    expect(process.exitCode).toBe(1)
+  })
+
+  it('fails when no policy file was found', async () => {
+    await outputPolicyLint(
+      { ok: false, message: 'No policy file found' },
+      'markdown',
+    )
+
+    expect(mockLogger.fail).toHaveBeenCalledWith('No policy file found')
+    expect(process.exitCode).toBe(1)
+  })
+
+  it('lists issues in markdown', async () => {
+    await outputPolicyLint({ ok: true, data: WARNING_REPORT }, 'markdown')
+
+    const output = mockLogger.log.mock.calls.map(call => call[0]).join('\n')
+    expect(output).toContain('# Policy Lint')
+    expect(output).toContain(
+      '- **warning**: version: Missing `version`; assuming version 1',
+    )
+  })
+})
@@ -0,0 +1,43 @@
+/**
+ * Unit tests for purl list normalization.
+ *
+ * Tests the report behind `socket purl normalize`.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { getPurlNormalizeReport } from '../../../../src/commands/purl/handle-purl-normalize.mts'
+
+describe('getPurlNormalizeReport', () => {
+  it('normalizes purls and coordinates once each, in input order', () => {
+    expect(
+      getPurlNormalizeReport([
+        { text: 'maven:org.example:lib:1.0.0' },
+        { text: 'npm/lodash@4.17.21' },
+        { text: 'npm:lodash@4.17.21' },
+      ]),
+    ).toEqual({
+      ok: true,
+      data: {
+        invalid: [],
+        purls: ['pkg:maven/org.example/lib@1.0.0', 'pkg:npm/lodash@4.17.21'],
+      },
+    })
+  })
+
+  it('fails with the entries that are not valid', () => {
+    const result = getPurlNormalizeReport([
+      { line: 1, text: 'npm/lodash@4.17.21' },
+      { line: 2, text: 'nope:thing' },
+    ])
+
+    expect(result).toMatchObject({
+      ok: false,
+      cause: '1 of 2 entries are not valid purls or coordinates',
+      data: {
+        invalid: [expect.objectContaining({ line: 2, text: 'nope:thing' })],
+        purls: ['pkg:npm/lodash@4.17.21'],
+      },
+    })
+  })
+})
@@ -0,0 +1,104 @@
+/**
+ * Unit tests for the `socket registry proxy` package checker.
+ *
+ * Purpose: Tests how registry packages are scored and judged.
+ *
+ * Test Coverage: - Scoped and unscoped npm purls - Blocked, warned and
+ * clean verdicts in request order - Lookup failures.
+ *
+ * Related Files: - src/commands/registry/handle-registry-proxy.mts
+ * (implementation) - src/util/install-check/check.mts - Policy verdicts.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockFetchPurlsShallowScore = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/package/fetch-purls-shallow-score.mts'),
+  () => ({
+    fetchPurlsShallowScore: mockFetchPurlsShallowScore,
+  }),
+)
+
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), () => ({
+  getDefaultApiToken: () => 'sktsec_test',
+  getDefaultProxyUrl: () => undefined,
+  getExtraCaCerts: () => undefined,
+}))
+
+import { createRegistryPackageChecker } from '../../../../src/commands/registry/handle-registry-proxy.mts'
+
+describe('createRegistryPackageChecker', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('judges packages by the policy action of their alerts', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        {
+          alerts: [
+            { action: 'warn', severity: 'middle', type: 'networkAccess' },
+          ],
+          name: 'pkg',
+          namespace: '@scope',
+          type: 'npm',
+          version: '2.0.0',
+        },
+        {
+          alerts: [{ severity: 'critical', type: 'malware' }],
+          name: 'left-pad',
+          type: 'npm',
+          version: '1.1.0',
+        },
+      ],
+    })
+
+    const result = await createRegistryPackageChecker(undefined)([
+      { name: 'left-pad', version: '1.1.0' },
+      { name: 'is-odd', version: '3.0.1' },
+      { name: '@scope/pkg', version: '2.0.0' },
+    ])
+
+    expect(mockFetchPurlsShallowScore).toHaveBeenCalledWith(
+      [
+        'pkg:npm/left-pad@1.1.0',
+        'pkg:npm/is-odd@3.0.1',
+        'pkg:npm/%40scope/pkg@2.0.0',
+      ],
+      expect.objectContaining({ sdkOpts: { apiToken: 'sktsec_test' } }),
+    )
+    expect(result).toEqual({
+      ok: true,
+      data: [
+        {
+          action: 'block',
+          alerts: [{ action: 'error', severity: 'critical', type: 'malware' }],
+          purl: 'pkg:npm/left-pad@1.1.0',
+        },
+        { action: 'allow', alerts: [], purl: 'pkg:npm/is-odd@3.0.1' },
+        {
+          action: 'warn',
+          alerts: [
+            { action: 'warn', severity: 'middle', type: 'networkAccess' },
+          ],
+          purl: 'pkg:npm/%40scope/pkg@2.0.0',
+        },
+      ],
+    })
+  })
+
+  it('passes lookup failures on', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: false,
+      message: 'Socket API error',
+    })
+
+    const result = await createRegistryPackageChecker(undefined)([
+      { name: 'left-pad', version: '1.1.0' },
+    ])
+
+    expect(result).toEqual({ ok: false, message: 'Socket API error' })
+  })
+})
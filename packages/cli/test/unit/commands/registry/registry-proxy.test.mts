@@ -0,0 +1,278 @@
+/**
+ * Unit tests for the `socket registry proxy` server.
+ *
+ * Purpose: Tests how registry paths are recognized, how package metadata is
+ * rewritten and which tarballs the proxy refuses.
+ *
+ * Test Coverage: - Packument and tarball paths, scoped and unscoped -
+ * Tarball URLs pointed at the proxy - Quarantined versions and moved
+ * dist-tags - 403 for blocked tarballs - 503 and a retry when a package
+ * could not be checked - Pass-through of other registry requests.
+ *
+ * Testing Approach: Runs the proxy against a local upstream registry with a
+ * stubbed package checker.
+ *
+ * Related Files: - src/commands/registry/registry-proxy.mts (implementation)
+ * - src/commands/registry/handle-registry-proxy.mts - Policy checker.
+ */
+
+import { createServer } from 'node:http'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), () => ({
+  getDefaultProxyUrl: () => undefined,
+  getExtraCaCerts: () => undefined,
+}))
+
+import {
+  createRegistryProxy,
+  parseNpmRegistryPath,
+  rewritePackument,
+} from '../../../../src/commands/registry/registry-proxy.mts'
+
+import type {
+  RegistryPackage,
+  RegistryProxyEvent,
+} from '../../../../src/commands/registry/registry-proxy.mts'
+import type { AddressInfo } from 'node:net'
+import type { Server } from 'node:http'
+
+function listen(server: Server): Promise<string> {
+  return new Promise(resolve => {
+    server.listen(0, '127.0.0.1', () => {
+      resolve(`http://127.0.0.1:${(server.address() as AddressInfo).port}/`)
+    })
+  })
+}
+
+function close(server: Server): Promise<void> {
+  return new Promise(resolve => {
+    server.closeAllConnections()
+    server.close(() => resolve())
+  })
+}
+
+describe('parseNpmRegistryPath', () => {
+  it('recognizes packuments and tarballs', () => {
+    expect(parseNpmRegistryPath('/left-pad')).toEqual({
+      kind: 'packument',
+      name: 'left-pad',
+    })
+    expect(parseNpmRegistryPath('/@scope%2fpkg')).toEqual({
+      kind: 'packument',
+      name: '@scope/pkg',
+    })
+    expect(parseNpmRegistryPath('/@scope/pkg/-/pkg-1.0.0-beta.1.tgz')).toEqual({
+      kind: 'tarball',
+      name: '@scope/pkg',
+      version: '1.0.0-beta.1',
+    })
+    expect(parseNpmRegistryPath('/left-pad/-/left-pad-1.3.0.tgz')).toEqual({
+      kind: 'tarball',
+      name: 'left-pad',
+      version: '1.3.0',
+    })
+  })
+
+  it('ignores registry API paths and version documents', () => {
+    expect(parseNpmRegistryPath('/-/npm/v1/security/audits')).toBeUndefined()
+    expect(parseNpmRegistryPath('/left-pad/1.3.0')).toBeUndefined()
+    expect(parseNpmRegistryPath('/left-pad/-/other-1.0.0.tgz')).toBeUndefined()
+    expect(parseNpmRegistryPath('/')).toBeUndefined()
+  })
+})
+
+describe('rewritePackument', () => {
+  it('points tarballs at the proxy and moves tags off quarantined versions', () => {
+    const packument = {
+      'dist-tags': { latest: '2.0.0', next: '2.1.0-rc.1' },
+      versions: {
+        '1.0.0': { dist: { tarball: 'https://up.example/a/-/a-1.0.0.tgz' } },
+        '1.1.0-rc.1': {
+          dist: { tarball: 'https://up.example/a/-/a-1.1.0-rc.1.tgz' },
+        },
+        '2.0.0': { dist: { tarball: 'https://up.example/a/-/a-2.0.0.tgz' } },
+        '2.1.0-rc.1': {
+          dist: { tarball: 'https://cdn.example/a-2.1.0-rc.1.tgz' },
+        },
+      },
+    }
+
+    const removed = rewritePackument(packument, {
+      proxyBaseUrl: 'http://127.0.0.1:8765/',
+      quarantined: new Set(['2.0.0']),
+      upstreamBaseUrl: 'https://up.example/',
+    })
+
+    expect(removed).toEqual(['2.0.0'])
+    expect(packument['dist-tags']).toEqual({
+      latest: '1.0.0',
+      next: '2.1.0-rc.1',
+    })
+    expect(Object.keys(packument.versions)).toEqual([
+      '1.0.0',
+      '1.1.0-rc.1',
+      '2.1.0-rc.1',
+    ])
+    expect(packument.versions['1.0.0'].dist.tarball).toBe(
+      'http://127.0.0.1:8765/a/-/a-1.0.0.tgz',
+    )
+    // Tarballs hosted elsewhere are left alone.
+    expect(packument.versions['2.1.0-rc.1'].dist.tarball).toBe(
+      'https://cdn.example/a-2.1.0-rc.1.tgz',
+    )
+  })
+})
+
+describe('createRegistryProxy', () => {
+  let upstream: Server
+  let upstreamUrl: string
+  let proxy: Server | undefined
+  const upstreamRequests: string[] = []
+  const events: RegistryProxyEvent[] = []
+  const checkPackages = vi.fn()
+
+  beforeEach(async () => {
+    upstreamRequests.length = 0
+    events.length = 0
+    checkPackages.mockReset()
+    checkPackages.mockImplementation(async (packages: RegistryPackage[]) => ({
+      ok: true,
+      data: packages.map(({ name, version }) => ({
+        action: version === '1.1.0' ? 'block' : 'allow',
+        alerts:
+          version === '1.1.0'
+            ? [{ action: 'error', severity: 'critical', type: 'malware' }]
+            : [],
+        purl: `pkg:npm/${name}@${version}`,
+      })),
+    }))
+    upstream = createServer((req, res) => {
+      upstreamRequests.push(`${req.method} ${req.url}`)
+      if (req.url === '/left-pad') {
+        res.writeHead(200, { 'content-type': 'application/json', etag: 'x' })
+        res.end(
+          JSON.stringify({
+            'dist-tags': { latest: '1.1.0' },
+            name: 'left-pad',
+            versions: {
+              '1.0.0': {
+                dist: {
+                  tarball: `${upstreamUrl}left-pad/-/left-pad-1.0.0.tgz`,
+                },
+              },
+              '1.1.0': {
+                dist: {
+                  tarball: `${upstreamUrl}left-pad/-/left-pad-1.1.0.tgz`,
+                },
+              },
+            },
+          }),
+        )
+        return
+      }
+      if (req.url?.endsWith('.tgz')) {
+        res.writeHead(200, { 'content-type': 'application/octet-stream' })
+        res.end(`tarball ${req.url}`)
+        return
+      }
+      res.writeHead(404, { 'content-type': 'application/json' })
+      res.end('{"error":"Not found"}')
+    })
+    upstreamUrl = await listen(upstream)
+  })
+
+  afterEach(async () => {
+    if (proxy) {
+      await close(proxy)
+      proxy = undefined
+    }
+    await close(upstream)
+  })
+
+  async function startProxy(quarantine: boolean): Promise<string> {
+    proxy = createRegistryProxy({
+      checkPackages,
+      onEvent: event => events.push(event),
+      quarantine,
+      upstreamUrl,
+    })
+    return await listen(proxy)
+  }
+
+  it('serves metadata through the proxy and refuses blocked tarballs', async () => {
+    const proxyUrl = await startProxy(false)
+
+    const packument = await (await fetch(`${proxyUrl}left-pad`)).json()
+    expect(packument.versions['1.1.0'].dist.tarball).toBe(
+      `${proxyUrl}left-pad/-/left-pad-1.1.0.tgz`,
+    )
+    expect(packument['dist-tags'].latest).toBe('1.1.0')
+
+    const allowed = await fetch(`${proxyUrl}left-pad/-/left-pad-1.0.0.tgz`)
+    expect(allowed.status).toBe(200)
+    expect(await allowed.text()).toBe('tarball /left-pad/-/left-pad-1.0.0.tgz')
+
+    const blocked = await fetch(`${proxyUrl}left-pad/-/left-pad-1.1.0.tgz`)
+    expect(blocked.status).toBe(403)
+    expect((await blocked.json()).error).toBe(
+      'Socket blocked pkg:npm/left-pad@1.1.0: malware (critical)',
+    )
+    expect(upstreamRequests).not.toContain('GET /left-pad/-/left-pad-1.1.0.tgz')
+    expect(events.map(event => event.action)).toEqual(['allow', 'block'])
+  })
+
+  it('hides quarantined versions from metadata', async () => {
+    const proxyUrl = await startProxy(true)
+
+    const packument = await (await fetch(`${proxyUrl}left-pad`)).json()
+
+    expect(packument['dist-tags'].latest).toBe('1.0.0')
+    expect(Object.keys(packument.versions)).toEqual(['1.0.0'])
+    expect(events).toEqual([
+      {
+        action: 'quarantine',
+        alerts: [{ action: 'error', severity: 'critical', type: 'malware' }],
+        purl: 'pkg:npm/left-pad@1.1.0',
+      },
+    ])
+    // The verdict is reused for the tarball.
+    const blocked = await fetch(`${proxyUrl}left-pad/-/left-pad-1.1.0.tgz`)
+    expect(blocked.status).toBe(403)
+    expect(checkPackages).toHaveBeenCalledTimes(1)
+  })
+
+  it('refuses packages it could not check and retries later', async () => {
+    checkPackages.mockResolvedValueOnce({
+      ok: false,
+      message: 'Unable to check packages',
+      cause: 'API unreachable',
+    })
+    const proxyUrl = await startProxy(false)
+    const tarballUrl = `${proxyUrl}left-pad/-/left-pad-1.0.0.tgz`
+
+    const refused = await fetch(tarballUrl)
+    expect(refused.status).toBe(503)
+    expect(events[0]).toMatchObject({
+      action: 'error',
+      reason: 'API unreachable',
+    })
+
+    expect((await fetch(tarballUrl)).status).toBe(200)
+    expect(checkPackages).toHaveBeenCalledTimes(2)
+  })
+
+  it('passes other requests through', async () => {
+    const proxyUrl = await startProxy(false)
+
+    const res = await fetch(`${proxyUrl}-/npm/v1/security/audits/quick`, {
+      body: '{}',
+      method: 'POST',
+    })
+
+    expect(res.status).toBe(404)
+    expect(upstreamRequests).toEqual(['POST /-/npm/v1/security/audits/quick'])
+    expect(checkPackages).not.toHaveBeenCalled()
+  })
+})
@@ -0,0 +1,57 @@
+/**
+ * Unit tests for the links of `socket report open`.
+ *
+ * Tests the order of the scan, package and alert links, the link that is
+ * opened, and rejecting packages that are not purls.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { getReportLinks } from '../../../../src/commands/report/handle-report-open.mts'
+
+const REPORT_URL = 'https://socket.dev/dashboard/org/acme/sbom/scan-1'
+
+describe('getReportLinks', () => {
+  it('should link to the scan report', () => {
+    expect(getReportLinks({ reportUrl: REPORT_URL })).toEqual({
+      ok: true,
+      data: {
+        links: [{ kind: 'scan', url: REPORT_URL }],
+        url: REPORT_URL,
+      },
+    })
+  })
+
+  it('should put the most specific link first', () => {
+    const result = getReportLinks({
+      alertType: 'installScripts',
+      purl: 'pkg:npm/esbuild@0.20.0',
+      reportUrl: REPORT_URL,
+    })
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        links: [
+          { kind: 'alert', url: 'https://socket.dev/alerts/installScripts' },
+          {
+            kind: 'package',
+            url: 'https://socket.dev/npm/package/esbuild/overview/0.20.0',
+          },
+          { kind: 'scan', url: REPORT_URL },
+        ],
+        url: 'https://socket.dev/alerts/installScripts',
+      },
+    })
+  })
+
+  it('should reject a package that is not a purl', () => {
+    expect(getReportLinks({ purl: 'esbuild' })).toMatchObject({
+      ok: false,
+      message: 'Invalid package',
+    })
+  })
+
+  it('should fail without any link', () => {
+    expect(getReportLinks({})).toMatchObject({ ok: false })
+  })
+})
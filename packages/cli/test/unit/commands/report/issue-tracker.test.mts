@@ -0,0 +1,96 @@
+/**
+ * Unit tests for the issue tracker tickets of `socket report issues`.
+ *
+ * Tests picking the alerts at or above the severity, the fingerprints that
+ * dedupe tickets across scans, and the order of the tickets.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getIssueFingerprint,
+  getIssueTickets,
+} from '../../../../src/commands/report/issue-tracker.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+function getScan(): SocketArtifact[] {
+  return [
+    {
+      id: '1',
+      type: 'npm',
+      name: 'lodash',
+      version: '4.17.20',
+      direct: true,
+      alerts: [
+        { key: 'QmCve1', type: 'cve', severity: 'high' },
+        { key: 'QmLow1', type: 'unmaintained', severity: 'low' },
+      ],
+      manifestFiles: [{ file: 'package-lock.json' }],
+    },
+    {
+      id: '2',
+      type: 'npm',
+      name: 'evil',
+      version: '1.0.0',
+      direct: false,
+      alerts: [{ key: 'QmMal1', type: 'malware', severity: 'critical' }],
+      manifestFiles: [{ file: 'package-lock.json' }],
+    },
+    {
+      id: '3',
+      type: 'npm',
+      name: 'evil',
+      version: '1.0.0',
+      direct: false,
+      alerts: [{ key: 'QmMal1', type: 'malware', severity: 'critical' }],
+      manifestFiles: [{ file: 'packages/api/package-lock.json' }],
+    },
+  ] as SocketArtifact[]
+}
+
+describe('getIssueFingerprint', () => {
+  it('is a stable label per package and alert', () => {
+    const fingerprint = getIssueFingerprint('pkg:npm/evil@1.0.0', 'QmMal1')
+
+    expect(fingerprint).toMatch(/^socket-[\da-f]{16}$/)
+    expect(getIssueFingerprint('pkg:npm/evil@1.0.0', 'QmMal1')).toBe(
+      fingerprint,
+    )
+    expect(getIssueFingerprint('pkg:npm/evil@1.0.1', 'QmMal1')).not.toBe(
+      fingerprint,
+    )
+  })
+})
+
+describe('getIssueTickets', () => {
+  it('files one ticket per high or critical alert, most severe first', () => {
+    const tickets = getIssueTickets(getScan(), {
+      minSeverity: 'high',
+      reportUrl: 'https://socket.dev/dashboard/org/acme/sbom/scan-1',
+      scanId: 'scan-1',
+    })
+
+    expect(tickets.map(t => t.explanation.alertId)).toEqual([
+      'QmMal1',
+      'QmCve1',
+    ])
+    expect(tickets[0]).toMatchObject({
+      fingerprint: getIssueFingerprint('pkg:npm/evil@1.0.0', 'QmMal1'),
+      reportUrl: 'https://socket.dev/dashboard/org/acme/sbom/scan-1',
+      scanId: 'scan-1',
+    })
+    expect(tickets[0]!.summary).toMatch(
+      /^\[Socket\] critical: .+ in evil@1\.0\.0$/,
+    )
+  })
+
+  it('only files critical alerts with the critical severity', () => {
+    const tickets = getIssueTickets(getScan(), {
+      minSeverity: 'critical',
+      scanId: 'scan-1',
+    })
+
+    expect(tickets.map(t => t.explanation.type)).toEqual(['malware'])
+  })
+})
@@ -0,0 +1,170 @@
+/**
+ * Unit tests for the Jira issue tracker of `socket report issues`.
+ *
+ * Tests the wiki markup description, paging through the labelled tickets of
+ * a project, and creating tickets and surfacing Jira's field errors.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  socketHttpRequest: mockSocketHttpRequest,
+  tryReadResponseText: (response: { text?: () => string }) =>
+    response.text?.(),
+}))
+
+import {
+  JiraTracker,
+  formatJiraDescription,
+} from '../../../../src/commands/report/jira-tracker.mts'
+
+import type { IssueTicket } from '../../../../src/commands/report/issue-tracker.mts'
+
+function jsonResponse(status: number, body: unknown) {
+  return {
+    status,
+    statusText: status === 200 || status === 201 ? 'OK' : 'Bad Request',
+    text: () => JSON.stringify(body),
+  }
+}
+
+function getTicket(): IssueTicket {
+  return {
+    explanation: {
+      alertId: 'QmMal1',
+      type: 'malware',
+      title: 'Known malware',
+      severity: 'critical',
+      purl: 'pkg:npm/evil@1.0.0',
+      manifest: ['package-lock.json'],
+      description: 'This package is malware.',
+      details: [],
+      location: { file: 'package/install.js', line: 3 },
+      remediation: ['Remove evil, `socket fix` can not upgrade it'],
+      links: [
+        { label: 'Alert type', url: 'https://socket.dev/alerts/malware' },
+      ],
+    },
+    fingerprint: 'socket-0123456789abcdef',
+    reportUrl: 'https://socket.dev/dashboard/org/acme/sbom/scan-1',
+    scanId: 'scan-1',
+    summary: '[Socket] critical: Known malware in evil@1.0.0',
+  }
+}
+
+function getTracker(): JiraTracker {
+  return new JiraTracker({
+    apiToken: 'secret',
+    baseUrl: 'https://acme.atlassian.net/',
+    email: 'bot@acme.com',
+    issueType: 'Bug',
+  })
+}
+
+describe('formatJiraDescription', () => {
+  it('lists the package, path, remediation and report link', () => {
+    const description = formatJiraDescription(getTicket())
+
+    expect(description).toContain('*Package:* {{pkg:npm/evil@1.0.0}}')
+    expect(description).toContain('*Path:* {{package/install.js:3}}')
+    expect(description).toContain('*Manifest:* {{package-lock.json}}')
+    expect(description).toContain(
+      '* Remove evil, {{socket fix}} can not upgrade it',
+    )
+    expect(description).toContain(
+      '* [Scan report|https://socket.dev/dashboard/org/acme/sbom/scan-1]',
+    )
+  })
+})
+
+describe('JiraTracker', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('collects the fingerprints of every page', async () => {
+    mockSocketHttpRequest
+      .mockResolvedValueOnce(
+        jsonResponse(200, {
+          issues: [
+            {
+              key: 'SEC-1',
+              fields: { labels: ['socket-alert', 'socket-aaaa'] },
+            },
+          ],
+          nextPageToken: 'next',
+        }),
+      )
+      .mockResolvedValueOnce(
+        jsonResponse(200, {
+          issues: [
+            {
+              key: 'SEC-2',
+              fields: { labels: ['socket-alert', 'socket-bbbb'] },
+            },
+          ],
+        }),
+      )
+
+    const result = await getTracker().listFingerprints('SEC')
+
+    expect(result).toEqual({
+      ok: true,
+      data: new Set(['socket-aaaa', 'socket-bbbb']),
+    })
+    const [firstUrl, options] = mockSocketHttpRequest.mock.calls[0]!
+    expect(firstUrl).toContain(
+      'https://acme.atlassian.net/rest/api/2/search/jql?',
+    )
+    expect(new URL(firstUrl).searchParams.get('jql')).toBe(
+      'project = "SEC" AND labels = "socket-alert"',
+    )
+    expect(options.headers.Authorization).toBe(
+      `Basic ${Buffer.from('bot@acme.com:secret').toString('base64')}`,
+    )
+    expect(
+      new URL(mockSocketHttpRequest.mock.calls[1]![0]).searchParams.get(
+        'nextPageToken',
+      ),
+    ).toBe('next')
+  })
+
+  it('creates labelled tickets', async () => {
+    mockSocketHttpRequest.mockResolvedValueOnce(
+      jsonResponse(201, { id: '10001', key: 'SEC-3' }),
+    )
+
+    const result = await getTracker().createIssue('SEC', getTicket())
+
+    expect(result).toEqual({
+      ok: true,
+      data: { key: 'SEC-3', url: 'https://acme.atlassian.net/browse/SEC-3' },
+    })
+    const { fields } = JSON.parse(mockSocketHttpRequest.mock.calls[0]![1].body)
+    expect(fields).toMatchObject({
+      issuetype: { name: 'Bug' },
+      labels: ['socket-alert', 'socket-0123456789abcdef'],
+      project: { key: 'SEC' },
+      summary: '[Socket] critical: Known malware in evil@1.0.0',
+    })
+  })
+
+  it('fails with the field errors of Jira', async () => {
+    mockSocketHttpRequest.mockResolvedValueOnce(
+      jsonResponse(400, {
+        errors: { issuetype: 'Specify a valid issue type' },
+      }),
+    )
+
+    const result = await getTracker().createIssue('SEC', getTicket())
+
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Jira API error',
+      cause:
+        '400 Bad Request: issuetype: Specify a valid issue type (path: /rest/api/2/issue)',
+      data: { code: 400 },
+    })
+  })
+})
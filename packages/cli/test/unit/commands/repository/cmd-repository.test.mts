@@ -7,9 +7,13 @@
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 import { cmdRepository } from '../../../../src/commands/repository/cmd-repository.mts'
+import { cmdRepositoryArchive } from '../../../../src/commands/repository/cmd-repository-archive.mts'
 import { cmdRepositoryCreate } from '../../../../src/commands/repository/cmd-repository-create.mts'
 import { cmdRepositoryDel } from '../../../../src/commands/repository/cmd-repository-del.mts'
+import { cmdRepositoryLabel } from '../../../../src/commands/repository/cmd-repository-label.mts'
 import { cmdRepositoryList } from '../../../../src/commands/repository/cmd-repository-list.mts'
+import { cmdRepositorySetDefaultBranch } from '../../../../src/commands/repository/cmd-repository-set-default-branch.mts'
+import { cmdRepositoryTrend } from '../../../../src/commands/repository/cmd-repository-trend.mts'
 import { cmdRepositoryUpdate } from '../../../../src/commands/repository/cmd-repository-update.mts'
 import { cmdRepositoryView } from '../../../../src/commands/repository/cmd-repository-view.mts'
 
@@ -70,9 +74,13 @@ describe('cmd-repository', () => {
       // Subcommand identity (each entry IS the imported src module instance)
       // is asserted in the "include all subcommands" test below.
       expect(Object.keys(config.subcommands).toSorted()).toEqual([
+        'archive',
         'create',
         'del',
+        'label',
         'list',
+        'set-default-branch',
+        'trend',
         'update',
         'view',
       ])
@@ -109,7 +117,11 @@ describe('cmd-repository', () => {
         'view',
         'list',
         'del',
+        'trend',
         'update',
+        'archive',
+        'set-default-branch',
+        'label',
       ])
     })
 
@@ -162,7 +174,13 @@ describe('cmd-repository', () => {
       expect(subcommands.view === cmdRepositoryView).toBe(true)
       expect(subcommands.list === cmdRepositoryList).toBe(true)
       expect(subcommands.del === cmdRepositoryDel).toBe(true)
+      expect(subcommands.trend === cmdRepositoryTrend).toBe(true)
       expect(subcommands.update === cmdRepositoryUpdate).toBe(true)
+      expect(subcommands.archive === cmdRepositoryArchive).toBe(true)
+      expect(
+        subcommands['set-default-branch'] === cmdRepositorySetDefaultBranch,
+      ).toBe(true)
+      expect(subcommands.label === cmdRepositoryLabel).toBe(true)
     })
   })
 
@@ -184,6 +202,7 @@ describe('cmd-repository', () => {
         'view',
         'list',
         'del',
+        'trend',
         'update',
       ])
     })
@@ -0,0 +1,75 @@
+/**
+ * Unit tests for handleArchiveRepo.
+ *
+ * Purpose: Tests that archiving and unarchiving only send the `archived`
+ * field, so the other repository fields keep their value.
+ *
+ * Related Files: - src/commands/repository/handle-archive-repo.mts
+ * (implementation) - src/commands/repository/fetch-patch-repo.mts (API
+ * fetcher) - src/commands/repository/output-archive-repo.mts (formatter)
+ */
+
+import { describe, expect, it, vi } from 'vitest'
+
+import { handleArchiveRepo } from '../../../../src/commands/repository/handle-archive-repo.mts'
+import { createSuccessResult } from '../../../helpers/mocks.mts'
+
+const mockFetchPatchRepo = vi.hoisted(() => vi.fn())
+const mockOutputArchiveRepo = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/repository/fetch-patch-repo.mts'),
+  () => ({
+    fetchPatchRepo: mockFetchPatchRepo,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/repository/output-archive-repo.mts'),
+  () => ({
+    outputArchiveRepo: mockOutputArchiveRepo,
+  }),
+)
+
+describe('handleArchiveRepo', () => {
+  it('archives the repository', async () => {
+    const mockResult = createSuccessResult({ id: 'repo-1', archived: true })
+    mockFetchPatchRepo.mockResolvedValue(mockResult)
+
+    await handleArchiveRepo(
+      { archived: true, orgSlug: 'test-org', repoName: 'test-repo' },
+      'text',
+    )
+
+    expect(mockFetchPatchRepo).toHaveBeenCalledWith(
+      'test-org',
+      'test-repo',
+      { archived: true },
+      {
+        commandPath: 'socket repository archive',
+        description: 'to archive a repository',
+      },
+    )
+    expect(mockOutputArchiveRepo).toHaveBeenCalledWith(
+      mockResult,
+      { archived: true, repoName: 'test-repo' },
+      'text',
+    )
+  })
+
+  it('unarchives the repository', async () => {
+    mockFetchPatchRepo.mockResolvedValue(createSuccessResult({}))
+
+    await handleArchiveRepo(
+      { archived: false, orgSlug: 'test-org', repoName: 'test-repo' },
+      'json',
+    )
+
+    expect(mockFetchPatchRepo).toHaveBeenCalledWith(
+      'test-org',
+      'test-repo',
+      { archived: false },
+      expect.objectContaining({ description: 'to unarchive a repository' }),
+    )
+  })
+})
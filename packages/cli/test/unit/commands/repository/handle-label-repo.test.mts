@@ -0,0 +1,120 @@
+/**
+ * Unit tests for labelRepo.
+ *
+ * Purpose: Tests adding and removing repository labels. Validates that
+ * missing labels are created, labels already in the requested state are left
+ * alone, and fetch failures are passed through.
+ *
+ * Related Files: - src/commands/repository/handle-label-repo.mts
+ * (implementation) - src/commands/repository/fetch-repo-labels.mts (API
+ * fetchers)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { labelRepo } from '../../../../src/commands/repository/handle-label-repo.mts'
+import { createSuccessResult } from '../../../helpers/mocks.mts'
+
+const mockFetchViewRepo = vi.hoisted(() => vi.fn())
+const mockFetchRepoLabels = vi.hoisted(() => vi.fn())
+const mockFetchCreateRepoLabel = vi.hoisted(() => vi.fn())
+const mockFetchAssignRepoLabel = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/repository/fetch-view-repo.mts'),
+  () => ({
+    fetchViewRepo: mockFetchViewRepo,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/repository/fetch-repo-labels.mts'),
+  () => ({
+    fetchAssignRepoLabel: mockFetchAssignRepoLabel,
+    fetchCreateRepoLabel: mockFetchCreateRepoLabel,
+    fetchRepoLabels: mockFetchRepoLabels,
+  }),
+)
+
+describe('labelRepo', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFetchViewRepo.mockResolvedValue(
+      createSuccessResult({ id: 'repo-1', name: 'test-repo' }),
+    )
+    mockFetchRepoLabels.mockResolvedValue(
+      createSuccessResult([
+        { id: 'label-1', name: 'legacy', repository_ids: ['repo-1'] },
+        { id: 'label-2', name: 'team-web', repository_ids: [] },
+      ]),
+    )
+    mockFetchAssignRepoLabel.mockResolvedValue(createSuccessResult({}))
+  })
+
+  it('creates missing labels and skips assigned ones', async () => {
+    mockFetchCreateRepoLabel.mockResolvedValue(
+      createSuccessResult({ id: 'label-3', name: 'team-payments' }),
+    )
+
+    const result = await labelRepo({
+      labels: ['legacy', 'team-web', 'team-payments'],
+      orgSlug: 'test-org',
+      remove: false,
+      repoName: 'test-repo',
+    })
+
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        action: 'added',
+        changed: ['team-web', 'team-payments'],
+        repository: 'test-repo',
+        unchanged: ['legacy'],
+      },
+    })
+    expect(mockFetchCreateRepoLabel).toHaveBeenCalledWith(
+      'test-org',
+      'team-payments',
+    )
+    expect(mockFetchAssignRepoLabel).toHaveBeenCalledWith(
+      'test-org',
+      'label-3',
+      'repo-1',
+      'associate',
+    )
+  })
+
+  it('removes only assigned labels', async () => {
+    const result = await labelRepo({
+      labels: ['legacy', 'team-web', 'unknown'],
+      orgSlug: 'test-org',
+      remove: true,
+      repoName: 'test-repo',
+    })
+
+    expect(result.ok && result.data.changed).toEqual(['legacy'])
+    expect(result.ok && result.data.unchanged).toEqual(['team-web', 'unknown'])
+    expect(mockFetchCreateRepoLabel).not.toHaveBeenCalled()
+    expect(mockFetchAssignRepoLabel).toHaveBeenCalledWith(
+      'test-org',
+      'label-1',
+      'repo-1',
+      'disassociate',
+    )
+  })
+
+  it('passes through fetch failures', async () => {
+    const failure = { ok: false, message: 'Not found', code: 404 }
+    mockFetchViewRepo.mockResolvedValue(failure)
+
+    const result = await labelRepo({
+      labels: ['legacy'],
+      orgSlug: 'test-org',
+      remove: false,
+      repoName: 'missing-repo',
+    })
+
+    expect(result).toBe(failure)
+    expect(mockFetchRepoLabels).not.toHaveBeenCalled()
+  })
+})
@@ -0,0 +1,144 @@
+/**
+ * Unit tests for handleRepoTrend.
+ *
+ * Purpose: Tests the handler that looks up the scan history of a repository
+ * branch, fetches a sample of the scans and summarizes them into trend
+ * points.
+ *
+ * Testing Approach: Mocks the fetch and output functions to isolate the
+ * handler orchestration logic.
+ *
+ * Related Files: - src/commands/repository/handle-repo-trend.mts
+ * (implementation) - src/commands/repository/repo-trend.mts (summaries)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { createSuccessResult } from '../../../helpers/index.mts'
+import { handleRepoTrend } from '../../../../src/commands/repository/handle-repo-trend.mts'
+
+const mockFetchRepoScanHistory = vi.hoisted(() => vi.fn())
+const mockFetchScan = vi.hoisted(() => vi.fn())
+const mockFetchViewRepo = vi.hoisted(() => vi.fn())
+const mockOutputRepoTrend = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/repository/fetch-repo-scan-history.mts'),
+  () => ({
+    fetchRepoScanHistory: mockFetchRepoScanHistory,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/repository/fetch-view-repo.mts'),
+  () => ({
+    fetchViewRepo: mockFetchViewRepo,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/repository/output-repo-trend.mts'),
+  () => ({
+    outputRepoTrend: mockOutputRepoTrend,
+  }),
+)
+
+vi.mock(import('../../../../src/commands/scan/fetch-scan.mts'), () => ({
+  fetchScan: mockFetchScan,
+}))
+
+describe('handleRepoTrend', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('summarizes the scans of the default branch', async () => {
+    mockFetchViewRepo.mockResolvedValue(
+      createSuccessResult({ default_branch: 'trunk' }),
+    )
+    mockFetchRepoScanHistory.mockResolvedValue(
+      createSuccessResult([
+        { created_at: '2025-01-01T00:00:00.000Z', id: 'scan-1' },
+        { created_at: '2025-02-01T00:00:00.000Z', id: 'scan-2' },
+      ]),
+    )
+    mockFetchScan.mockImplementation(async (_org: string, id: string) =>
+      createSuccessResult(
+        id === 'scan-1'
+          ? [{ alerts: [{ key: 'a', severity: 'high', type: 'malware' }] }]
+          : [],
+      ),
+    )
+
+    await handleRepoTrend({
+      branch: '',
+      days: 365,
+      orgSlug: 'test-org',
+      outputKind: 'json',
+      points: 12,
+      repoName: 'test-repo',
+    })
+
+    expect(mockFetchViewRepo).toHaveBeenCalledWith('test-org', 'test-repo', {
+      commandPath: 'socket repository trend',
+    })
+    expect(mockFetchRepoScanHistory).toHaveBeenCalledWith(
+      expect.objectContaining({
+        branch: 'trunk',
+        orgSlug: 'test-org',
+        repoName: 'test-repo',
+      }),
+      { commandPath: 'socket repository trend' },
+    )
+    const [result, outputKind] = mockOutputRepoTrend.mock.calls[0]!
+    expect(outputKind).toBe('json')
+    expect(result.ok).toBe(true)
+    expect(result.data.branch).toBe('trunk')
+    const scanIds = result.data.points.map((p: { scanId: string }) => p.scanId)
+    expect(scanIds).toEqual(['scan-1', 'scan-2'])
+    expect(result.data.points[0].alerts.high).toBe(1)
+  })
+
+  it('skips the repository lookup when a branch is given', async () => {
+    mockFetchRepoScanHistory.mockResolvedValue(createSuccessResult([]))
+
+    await handleRepoTrend({
+      branch: 'release',
+      days: 30,
+      orgSlug: 'test-org',
+      outputKind: 'text',
+      points: 12,
+      repoName: 'test-repo',
+    })
+
+    expect(mockFetchViewRepo).not.toHaveBeenCalled()
+    expect(mockOutputRepoTrend).toHaveBeenCalledWith(
+      expect.objectContaining({
+        ok: false,
+        message: 'No scans found',
+      }),
+      'text',
+    )
+  })
+
+  it('passes on a failed scan fetch', async () => {
+    const failure = { ok: false, message: 'Socket API error', code: 500 }
+    mockFetchRepoScanHistory.mockResolvedValue(
+      createSuccessResult([
+        { created_at: '2025-01-01T00:00:00.000Z', id: 'scan-1' },
+      ]),
+    )
+    mockFetchScan.mockResolvedValue(failure)
+
+    await handleRepoTrend({
+      branch: 'main',
+      days: 365,
+      orgSlug: 'test-org',
+      outputKind: 'csv',
+      points: 12,
+      repoName: 'test-repo',
+    })
+
+    expect(mockOutputRepoTrend).toHaveBeenCalledWith(failure, 'csv')
+  })
+})
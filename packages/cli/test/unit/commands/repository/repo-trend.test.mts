@@ -0,0 +1,140 @@
+/**
+ * Unit tests for the repository trend data.
+ *
+ * Tests how scans are sampled over time, how a scan is summarized into
+ * average scores and alert counts, and the sparkline and CSV rendering.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  formatRepoTrendCsv,
+  getSparkline,
+  selectTrendScans,
+  summarizeScanArtifacts,
+} from '../../../../src/commands/repository/repo-trend.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+function scanOn(day: number) {
+  return {
+    created_at: new Date(Date.UTC(2025, 0, day)).toISOString(),
+    id: `scan-${day}`,
+  }
+}
+
+describe('selectTrendScans', () => {
+  it('keeps all scans when there are no more than the points', () => {
+    const scans = [scanOn(3), scanOn(1), scanOn(2)]
+    expect(selectTrendScans(scans, 5).map(s => s.id)).toEqual([
+      'scan-1',
+      'scan-2',
+      'scan-3',
+    ])
+  })
+
+  it('keeps the last scan of each period', () => {
+    const scans = [1, 2, 3, 4, 5, 10, 11, 31].map(scanOn)
+    // Three periods of 10 days from Jan 1 to Jan 31.
+    expect(selectTrendScans(scans, 3).map(s => s.id)).toEqual([
+      'scan-10',
+      'scan-11',
+      'scan-31',
+    ])
+  })
+
+  it('leaves out periods without a scan and undated scans', () => {
+    const scans = [
+      scanOn(1),
+      scanOn(2),
+      scanOn(31),
+      { created_at: undefined, id: 'undated' },
+    ]
+    expect(selectTrendScans(scans, 2).map(s => s.id)).toEqual([
+      'scan-2',
+      'scan-31',
+    ])
+  })
+})
+
+describe('summarizeScanArtifacts', () => {
+  it('averages the scores and counts the alerts', () => {
+    const point = summarizeScanArtifacts(scanOn(1), [
+      {
+        alerts: [
+          { key: 'a', severity: 'high', type: 'networkAccess' },
+          { key: 'b', severity: 'low', type: 'envVars' },
+        ],
+        score: {
+          license: 1,
+          maintenance: 0.5,
+          overall: 0.5,
+          quality: 0.8,
+          supplyChain: 0.9,
+          vulnerability: 1,
+        },
+      },
+      {
+        alerts: [{ key: 'c', severity: 'critical', type: 'malware' }],
+        score: {
+          license: 0.5,
+          maintenance: 1,
+          overall: 0.5,
+          quality: 0.6,
+          supplyChain: 0.7,
+          vulnerability: 0.25,
+        },
+      },
+    ] as SocketArtifact[])
+    expect(point).toEqual({
+      alerts: { critical: 1, high: 1, low: 1, middle: 0 },
+      createdAt: '2025-01-01T00:00:00.000Z',
+      packages: 2,
+      scanId: 'scan-1',
+      score: {
+        license: 75,
+        maintenance: 75,
+        quality: 70,
+        supplyChain: 80,
+        vulnerability: 63,
+      },
+    })
+  })
+
+  it('leaves the scores undefined when no package has one', () => {
+    const point = summarizeScanArtifacts(scanOn(1), [
+      { alerts: [] },
+    ] as unknown as SocketArtifact[])
+    expect(point.packages).toBe(1)
+    expect(point.score.supplyChain).toBeUndefined()
+  })
+})
+
+describe('getSparkline', () => {
+  it('scales values between the lowest and highest', () => {
+    expect(getSparkline([0, 50, 100])).toBe('▁▅█')
+  })
+
+  it('draws flat values and blanks for missing ones', () => {
+    expect(getSparkline([7, undefined, 7])).toBe('▁ ▁')
+  })
+})
+
+describe('formatRepoTrendCsv', () => {
+  it('prints one row per point', () => {
+    const point = summarizeScanArtifacts(scanOn(2), [])
+    expect(
+      formatRepoTrendCsv({
+        branch: 'main',
+        points: [point],
+        repo: 'test-repo',
+        since: '2024-01-02T00:00:00.000Z',
+      }),
+    ).toBe(
+      [
+        'date,scanId,packages,supplyChain,maintenance,quality,vulnerability,license,critical,high,middle,low',
+        '2025-01-02,scan-2,0,,,,,,0,0,0,0',
+      ].join('\n'),
+    )
+  })
+})
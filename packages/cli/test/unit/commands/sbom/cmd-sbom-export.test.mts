@@ -0,0 +1,166 @@
+/**
+ * Unit tests for sbom export command.
+ *
+ * Tests the command that converts a completed scan into an SBOM document.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdSbomExport } from '../../../../src/commands/sbom/cmd-sbom-export.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+import type * as SdkModule from '../../../../src/util/socket/sdk.mts'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockHandleSbomExport = vi.hoisted(() => vi.fn())
+const mockDetermineOrgSlug = vi.hoisted(() =>
+  vi.fn().mockResolvedValue(['test-org', 'test-org']),
+)
+const mockHasDefaultApiToken = vi.hoisted(() => vi.fn().mockReturnValue(true))
+
+vi.mock(import('../../../../src/commands/sbom/handle-sbom-export.mts'), () => ({
+  handleSbomExport: mockHandleSbomExport,
+}))
+
+vi.mock(import('../../../../src/util/socket/org-slug.mts'), () => ({
+  determineOrgSlug: mockDetermineOrgSlug,
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mts'), async importOriginal => {
+  const actual = await importOriginal<typeof SdkModule>()
+  return {
+    ...actual,
+    hasDefaultApiToken: mockHasDefaultApiToken,
+  }
+})
+
+describe('cmd-sbom-export', () => {
+  const importMeta = { url: 'file:///test/cmd-sbom-export.mts' }
+  const context = { parentName: 'socket sbom' }
+  const testScanId = '000aaaa1-0000-0a0a-00a0-00a0000000a0'
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  it('should not be hidden', () => {
+    expect(cmdSbomExport.hidden).toBe(false)
+  })
+
+  it('should export cyclonedx json by default', async () => {
+    await cmdSbomExport.run([testScanId], importMeta, context)
+
+    expect(mockHandleSbomExport).toHaveBeenCalledWith({
+      encoding: 'json',
+      filepath: '',
+      format: 'cyclonedx',
+      orgSlug: 'test-org',
+      outputKind: 'text',
+      scanId: testScanId,
+    })
+  })
+
+  it('should infer xml encoding from the output file', async () => {
+    await cmdSbomExport.run([testScanId, './bom.xml'], importMeta, context)
+
+    expect(mockHandleSbomExport).toHaveBeenCalledWith(
+      expect.objectContaining({ encoding: 'xml', filepath: './bom.xml' }),
+    )
+  })
+
+  it('should export spdx tag-value for .spdx output files', async () => {
+    await cmdSbomExport.run(
+      [testScanId, '--format', 'spdx', './bom.spdx'],
+      importMeta,
+      context,
+    )
+
+    expect(mockHandleSbomExport).toHaveBeenCalledWith(
+      expect.objectContaining({
+        encoding: 'tag-value',
+        filepath: './bom.spdx',
+        format: 'spdx',
+      }),
+    )
+  })
+
+  it('should reject encodings the format does not support', async () => {
+    await cmdSbomExport.run(
+      [testScanId, '--format', 'spdx', '--encoding', 'xml'],
+      importMeta,
+      context,
+    )
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleSbomExport).not.toHaveBeenCalled()
+  })
+
+  it('should fail without scan ID', async () => {
+    await cmdSbomExport.run([], importMeta, context)
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleSbomExport).not.toHaveBeenCalled()
+  })
+
+  it('should fail on an unsupported format', async () => {
+    await cmdSbomExport.run(
+      [testScanId, '--format', 'foo'],
+      importMeta,
+      context,
+    )
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleSbomExport).not.toHaveBeenCalled()
+  })
+
+  it('should fail on an unsupported encoding', async () => {
+    await cmdSbomExport.run(
+      [testScanId, '--encoding', 'yaml'],
+      importMeta,
+      context,
+    )
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleSbomExport).not.toHaveBeenCalled()
+  })
+
+  it('should fail without Socket API token', async () => {
+    mockHasDefaultApiToken.mockReturnValueOnce(false)
+
+    await cmdSbomExport.run([testScanId], importMeta, context)
+
+    expect(process.exitCode).toBe(2)
+    expect(mockHandleSbomExport).not.toHaveBeenCalled()
+  })
+
+  it('should support --dry-run flag', async () => {
+    await cmdSbomExport.run(['--dry-run', testScanId], importMeta, context)
+
+    expect(mockHandleSbomExport).not.toHaveBeenCalled()
+    expect(mockLogger.error).toHaveBeenCalledWith(
+      expect.stringContaining('DryRun'),
+    )
+  })
+})
@@ -0,0 +1,148 @@
+/**
+ * Unit tests for CycloneDX SBOM generation.
+ *
+ * Purpose: Tests conversion of Socket scan artifacts into CycloneDX 1.5 JSON
+ * and XML documents.
+ *
+ * Test Coverage: - Component mapping (purl, license, properties) - Dependency
+ * graph - Metadata - XML serialization.
+ *
+ * Related Files: - src/commands/sbom/generate-cyclonedx.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  cycloneDxToXml,
+  generateCycloneDx,
+  getArtifactRefs,
+} from '../../../../src/commands/sbom/generate-cyclonedx.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+function getScan(): SocketArtifact[] {
+  return [
+    {
+      id: '1',
+      type: 'npm',
+      name: 'express',
+      version: '4.18.2',
+      license: 'MIT',
+      author: ['dougwilson'],
+      direct: true,
+      dependencies: ['2'],
+      score: { overall: 0.9, license: 1 },
+      alerts: [{ type: 'envVars', key: 'k1', severity: 'low' }],
+      manifestFiles: [{ file: 'package-lock.json' }],
+    },
+    {
+      id: '2',
+      type: 'npm',
+      namespace: '@types',
+      name: 'node',
+      version: '20.0.0',
+      license: 'MIT OR Apache-2.0',
+      direct: false,
+    },
+  ] as unknown as SocketArtifact[]
+}
+
+const options = {
+  orgSlug: 'acme',
+  scanId: 'scan-1',
+  serialNumber: '00000000-0000-0000-0000-000000000000',
+  timestamp: '2026-01-01T00:00:00.000Z',
+}
+
+describe('generate-cyclonedx', () => {
+  it('should produce a CycloneDX 1.5 envelope', () => {
+    const bom = generateCycloneDx([], options)
+
+    expect(bom.bomFormat).toBe('CycloneDX')
+    expect(bom.specVersion).toBe('1.5')
+    expect(bom.serialNumber).toBe(
+      'urn:uuid:00000000-0000-0000-0000-000000000000',
+    )
+    expect(bom.metadata.timestamp).toBe(options.timestamp)
+    expect(bom.metadata.component).toEqual({
+      type: 'application',
+      'bom-ref': 'socket-scan:scan-1',
+      name: 'scan-1',
+    })
+    expect(bom.metadata.properties).toEqual([
+      { name: 'socket:org', value: 'acme' },
+      { name: 'socket:scan_id', value: 'scan-1' },
+    ])
+    expect(bom.components).toEqual([])
+  })
+
+  it('should map artifacts to library components', () => {
+    const bom = generateCycloneDx(getScan(), options)
+
+    expect(bom.components[0]).toEqual({
+      type: 'library',
+      'bom-ref': 'pkg:npm/express@4.18.2',
+      name: 'express',
+      version: '4.18.2',
+      purl: 'pkg:npm/express@4.18.2',
+      author: 'dougwilson',
+      licenses: [{ expression: 'MIT' }],
+      properties: [
+        { name: 'socket:score:license', value: '1' },
+        { name: 'socket:score:overall', value: '0.9' },
+        { name: 'socket:direct', value: 'true' },
+        { name: 'socket:alert:envVars', value: 'low' },
+        { name: 'socket:manifest', value: 'package-lock.json' },
+      ],
+    })
+    expect(bom.components[1]).toMatchObject({
+      group: '@types',
+      name: 'node',
+      purl: 'pkg:npm/%40types/node@20.0.0',
+      licenses: [{ expression: 'MIT OR Apache-2.0' }],
+    })
+  })
+
+  it('should build the dependency graph from artifact ids', () => {
+    const bom = generateCycloneDx(getScan(), options)
+
+    expect(bom.dependencies).toEqual([
+      { ref: 'socket-scan:scan-1', dependsOn: ['pkg:npm/express@4.18.2'] },
+      {
+        ref: 'pkg:npm/express@4.18.2',
+        dependsOn: ['pkg:npm/%40types/node@20.0.0'],
+      },
+      { ref: 'pkg:npm/%40types/node@20.0.0', dependsOn: [] },
+    ])
+  })
+
+  it('should disambiguate duplicate purls', () => {
+    const scan = getScan()
+    const refs = getArtifactRefs([scan[0]!, { ...scan[0]!, id: '9' }])
+
+    expect([...refs.values()]).toEqual([
+      'pkg:npm/express@4.18.2',
+      'pkg:npm/express@4.18.2#9',
+    ])
+  })
+
+  describe('cycloneDxToXml', () => {
+    it('should serialize to the CycloneDX XML schema', () => {
+      const xml = cycloneDxToXml(generateCycloneDx(getScan(), options))
+
+      expect(xml).toContain(
+        '<bom xmlns="http://cyclonedx.org/schema/bom/1.5" serialNumber="urn:uuid:00000000-0000-0000-0000-000000000000" version="1">',
+      )
+      expect(xml).toContain(
+        '<component type="library" bom-ref="pkg:npm/express@4.18.2">',
+      )
+      expect(xml).toContain('<expression>MIT</expression>')
+      expect(xml).toContain(
+        '<property name="socket:alert:envVars">low</property>',
+      )
+      expect(xml).toContain(
+        '<dependency ref="pkg:npm/%40types/node@20.0.0"/>',
+      )
+    })
+  })
+})
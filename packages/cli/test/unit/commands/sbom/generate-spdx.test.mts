@@ -0,0 +1,143 @@
+/**
+ * Unit tests for SPDX SBOM generation.
+ *
+ * Purpose: Tests conversion of Socket scan artifacts into SPDX 2.3 JSON and
+ * tag-value documents.
+ *
+ * Test Coverage: - Package mapping (purl, license, supplier) - DEPENDS_ON
+ * relationships - Document metadata - Tag-value serialization.
+ *
+ * Related Files: - src/commands/sbom/generate-spdx.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  generateSpdx,
+  spdxToTagValue,
+  toSpdxId,
+} from '../../../../src/commands/sbom/generate-spdx.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+function getScan(): SocketArtifact[] {
+  return [
+    {
+      id: '1',
+      type: 'npm',
+      name: 'express',
+      version: '4.18.2',
+      license: 'MIT',
+      author: ['dougwilson'],
+      direct: true,
+      dependencies: ['2', '2', 'missing'],
+      alerts: [
+        { type: 'envVars', key: 'k1', severity: 'low' },
+        { type: 'envVars', key: 'k2', severity: 'low' },
+      ],
+    },
+    {
+      id: '2',
+      type: 'npm',
+      namespace: '@types',
+      name: 'node',
+      version: '20.0.0',
+      direct: false,
+    },
+  ] as unknown as SocketArtifact[]
+}
+
+const options = {
+  orgSlug: 'acme',
+  scanId: 'scan-1',
+  timestamp: '2026-01-01T00:00:00Z',
+  uuid: '00000000-0000-0000-0000-000000000000',
+}
+
+describe('generate-spdx', () => {
+  it('should produce an SPDX 2.3 document describing the scan', () => {
+    const doc = generateSpdx([], options)
+
+    expect(doc).toMatchObject({
+      spdxVersion: 'SPDX-2.3',
+      dataLicense: 'CC0-1.0',
+      SPDXID: 'SPDXRef-DOCUMENT',
+      name: 'socket-scan-scan-1',
+      documentNamespace:
+        'https://socket.dev/spdx/acme/scan-1-00000000-0000-0000-0000-000000000000',
+      creationInfo: { created: '2026-01-01T00:00:00Z' },
+    })
+    expect(doc.creationInfo.creators[0]).toMatch(/^Tool: socket-/)
+    expect(doc.packages).toHaveLength(1)
+    expect(doc.relationships).toEqual([
+      {
+        spdxElementId: 'SPDXRef-DOCUMENT',
+        relationshipType: 'DESCRIBES',
+        relatedSpdxElement: 'SPDXRef-Scan',
+      },
+    ])
+  })
+
+  it('should map artifacts to packages', () => {
+    const doc = generateSpdx(getScan(), options)
+
+    expect(doc.packages[1]).toMatchObject({
+      SPDXID: 'SPDXRef-Package-npm-express-4.18.2',
+      name: 'express',
+      versionInfo: '4.18.2',
+      supplier: 'Person: dougwilson',
+      licenseDeclared: 'MIT',
+      licenseConcluded: 'NOASSERTION',
+      externalRefs: [
+        {
+          referenceCategory: 'PACKAGE-MANAGER',
+          referenceType: 'purl',
+          referenceLocator: 'pkg:npm/express@4.18.2',
+        },
+      ],
+      comment: 'Socket alerts: envVars',
+    })
+    expect(doc.packages[2]).toMatchObject({
+      name: '@types/node',
+      licenseDeclared: 'NOASSERTION',
+    })
+    expect(doc.packages[2]!.supplier).toBeUndefined()
+  })
+
+  it('should derive DEPENDS_ON relationships from the dependency tree', () => {
+    const doc = generateSpdx(getScan(), options)
+    const dependsOn = doc.relationships
+      .filter(r => r.relationshipType === 'DEPENDS_ON')
+      .map(r => [r.spdxElementId, r.relatedSpdxElement])
+
+    expect(dependsOn).toEqual([
+      ['SPDXRef-Scan', 'SPDXRef-Package-npm-express-4.18.2'],
+      ['SPDXRef-Package-npm-express-4.18.2', doc.packages[2]!.SPDXID],
+    ])
+  })
+
+  it('should sanitize SPDX identifiers', () => {
+    expect(toSpdxId('npm/@types/node@20.0.0')).toBe(
+      'SPDXRef-Package-npm-types-node-20.0.0',
+    )
+  })
+
+  it('should serialize to tag-value', () => {
+    const tv = spdxToTagValue(generateSpdx(getScan(), options))
+
+    expect(tv).toContain('SPDXVersion: SPDX-2.3\n')
+    expect(tv).toContain('DataLicense: CC0-1.0\n')
+    expect(tv).toContain('Created: 2026-01-01T00:00:00Z\n')
+    expect(tv).toContain('PackageName: express\n')
+    expect(tv).toContain(
+      'ExternalRef: PACKAGE-MANAGER purl pkg:npm/express@4.18.2\n',
+    )
+    expect(tv).toContain(
+      'Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Scan\n',
+    )
+    expect(tv).toContain(
+      'Relationship: SPDXRef-Scan DEPENDS_ON SPDXRef-Package-npm-express-4.18.2\n',
+    )
+    expect(tv.endsWith('\n')).toBe(true)
+  })
+})
@@ -0,0 +1,182 @@
+/**
+ * Unit tests for VEX generation.
+ *
+ * Tests how the vulnerability alerts of a scan are triaged into VEX findings
+ * and rendered as OpenVEX and CSAF VEX documents.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  generateCsafVex,
+  generateOpenVex,
+  getVexFindings,
+} from '../../../../src/commands/sbom/generate-vex.mts'
+import { createEmptySocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+function pkg(
+  name: string,
+  cveId: string,
+  action?: string | undefined,
+): SocketArtifact {
+  return {
+    alerts: [
+      {
+        action,
+        key: `${name}-${cveId}`,
+        props: { cveId, title: `${name} advisory` },
+        severity: 'high',
+        type: 'cve',
+      },
+      { key: `${name}-env`, severity: 'low', type: 'envVars' },
+    ],
+    id: name,
+    name,
+    type: 'npm',
+    version: '1.0.0',
+  } as SocketArtifact
+}
+
+const OPTIONS = {
+  author: 'acme',
+  orgSlug: 'acme',
+  scanId: 'scan-1',
+  timestamp: '2025-01-02T03:04:05Z',
+  uuid: 'uuid-1',
+}
+
+describe('getVexFindings', () => {
+  it('triages by policy exception, org triage and reachability', () => {
+    const policy = createEmptySocketPolicy()
+    policy.exceptions.push({
+      alerts: ['cve'],
+      packages: ['pkg:npm/a'],
+      paths: [],
+      reason: 'Only used in tests',
+    })
+    const findings = getVexFindings(
+      [
+        pkg('a', 'CVE-2024-0001'),
+        pkg('b', 'CVE-2024-0002', 'ignore'),
+        pkg('c', 'CVE-2024-0003'),
+        pkg('d', 'CVE-2024-0004'),
+      ],
+      { policy, reachability: new Map([['c', 'unreachable' as const]]) },
+    )
+    expect(findings.map(f => [f.purl, f.status, f.justification])).toEqual([
+      ['pkg:npm/a@1.0.0', 'not_affected', undefined],
+      ['pkg:npm/b@1.0.0', 'not_affected', undefined],
+      [
+        'pkg:npm/c@1.0.0',
+        'not_affected',
+        'vulnerable_code_not_in_execute_path',
+      ],
+      ['pkg:npm/d@1.0.0', 'under_investigation', undefined],
+    ])
+    expect(findings[0]!.impactStatement).toBe('Only used in tests')
+    expect(findings[0]!.title).toBe('a advisory')
+  })
+
+  it('lists an alert found in several files once', () => {
+    const artifact = pkg('a', 'CVE-2024-0001')
+    artifact.alerts = [artifact.alerts![0]!, artifact.alerts![0]!]
+    expect(getVexFindings([artifact])).toHaveLength(1)
+  })
+})
+
+describe('generateOpenVex', () => {
+  it('groups the packages of a vulnerability with the same triage', () => {
+    const doc = generateOpenVex(
+      [
+        {
+          ids: ['CVE-2024-0001', 'GHSA-aaaa-bbbb-cccc'],
+          purl: 'pkg:npm/a@1.0.0',
+          status: 'under_investigation',
+        },
+        {
+          ids: ['CVE-2024-0001', 'GHSA-aaaa-bbbb-cccc'],
+          purl: 'pkg:npm/a@2.0.0',
+          status: 'under_investigation',
+        },
+      ],
+      OPTIONS,
+    )
+    expect(doc).toMatchObject({
+      '@context': 'https://openvex.dev/ns/v0.2.0',
+      '@id': 'https://socket.dev/vex/acme/scan-1-uuid-1',
+      author: 'acme',
+      timestamp: '2025-01-02T03:04:05Z',
+      version: 1,
+      statements: [
+        {
+          vulnerability: {
+            name: 'CVE-2024-0001',
+            aliases: ['GHSA-aaaa-bbbb-cccc'],
+          },
+          products: [
+            { '@id': 'pkg:npm/a@1.0.0' },
+            { '@id': 'pkg:npm/a@2.0.0' },
+          ],
+          status: 'under_investigation',
+        },
+      ],
+    })
+  })
+})
+
+describe('generateCsafVex', () => {
+  it('sorts products into the product status lists', () => {
+    const doc = generateCsafVex(
+      [
+        {
+          ids: ['CVE-2024-0001'],
+          impactStatement: 'Only used in tests',
+          justification: 'vulnerable_code_not_in_execute_path',
+          purl: 'pkg:npm/a@1.0.0',
+          status: 'not_affected',
+          title: 'a advisory',
+        },
+        {
+          ids: ['CVE-2024-0001'],
+          purl: 'pkg:npm/b@1.0.0',
+          status: 'under_investigation',
+        },
+      ],
+      OPTIONS,
+    )
+    expect(doc.document).toMatchObject({
+      category: 'csaf_vex',
+      csaf_version: '2.0',
+      tracking: { id: 'socket-vex-scan-1', status: 'final' },
+    })
+    expect(doc.product_tree.full_product_names.map(p => p.name)).toEqual([
+      'pkg:npm/a@1.0.0',
+      'pkg:npm/b@1.0.0',
+    ])
+    expect(doc.vulnerabilities).toEqual([
+      {
+        cve: 'CVE-2024-0001',
+        notes: [{ category: 'summary', text: 'a advisory' }],
+        product_status: {
+          known_not_affected: ['CSAFPID-0001'],
+          under_investigation: ['CSAFPID-0002'],
+        },
+        flags: [
+          {
+            label: 'vulnerable_code_not_in_execute_path',
+            product_ids: ['CSAFPID-0001'],
+          },
+        ],
+        threats: [
+          {
+            category: 'impact',
+            details: 'Only used in tests',
+            product_ids: ['CSAFPID-0001'],
+          },
+        ],
+      },
+    ])
+  })
+})
@@ -0,0 +1,160 @@
+/**
+ * Unit tests for waiting on the analysis of a scan.
+ *
+ * Purpose: Tests the summary of a finished analysis and the callback POSTed
+ * once it is done.
+ *
+ * Test Coverage: - Package and alert counts by severity - The timeout is
+ * passed on to fetchScan - Failures keep the scan ID - Callback payload - A
+ * failed callback only warns.
+ *
+ * Related Files: - src/commands/scan/await-scan.mts (implementation)
+ * - src/commands/scan/fetch-scan.mts (polling)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+const mockFetchScan = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/scan/fetch-scan.mts'), () => ({
+  fetchScan: mockFetchScan,
+}))
+
+const mockPostScanNotification = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/scan/scan-notify.mts'), () => ({
+  postScanNotification: mockPostScanNotification,
+}))
+
+import {
+  awaitScanAnalysis,
+  summarizeScanArtifacts,
+} from '../../../../src/commands/scan/await-scan.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+const artifacts = [
+  {
+    alerts: [
+      { key: 'a', severity: 'critical', type: 'malware' },
+      { key: 'b', severity: 'low', type: 'unmaintained' },
+    ],
+    name: 'evil',
+    type: 'npm',
+    version: '1.0.0',
+  },
+  {
+    alerts: [{ key: 'c', severity: 'high', type: 'cve' }],
+    name: 'old',
+    type: 'npm',
+    version: '2.0.0',
+  },
+  { name: 'clean', type: 'npm', version: '3.0.0' },
+] as unknown as SocketArtifact[]
+
+describe('summarizeScanArtifacts', () => {
+  it('counts the packages and their alerts by severity', () => {
+    expect(summarizeScanArtifacts(artifacts)).toEqual({
+      alerts: { critical: 1, high: 1, low: 1, middle: 0 },
+      packages: 3,
+    })
+  })
+})
+
+describe('awaitScanAnalysis', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('waits for the scan with the timeout and summarizes it', async () => {
+    mockFetchScan.mockResolvedValue({ ok: true, data: artifacts })
+
+    const result = await awaitScanAnalysis('acme', 'scan-1', {
+      timeoutMs: 30_000,
+    })
+
+    expect(mockFetchScan).toHaveBeenCalledWith('acme', 'scan-1', {
+      timeoutMs: 30_000,
+    })
+    expect(result.ok).toBe(true)
+    expect(result.data).toMatchObject({
+      alerts: { critical: 1, high: 1, low: 1, middle: 0 },
+      orgSlug: 'acme',
+      packages: 3,
+      scanId: 'scan-1',
+    })
+    expect(mockPostScanNotification).not.toHaveBeenCalled()
+  })
+
+  it('keeps the scan ID when the analysis is not done in time', async () => {
+    mockFetchScan.mockResolvedValue({
+      ok: false,
+      message: 'Scan results not ready',
+      cause: 'Still computing',
+    })
+
+    const result = await awaitScanAnalysis('acme', 'scan-1', { timeoutMs: 0 })
+
+    expect(result).toEqual({
+      ok: false,
+      message: 'Scan results not ready',
+      cause: 'Still computing',
+      data: { scanId: 'scan-1' },
+    })
+  })
+
+  it('posts the summary to the callback URL', async () => {
+    mockFetchScan.mockResolvedValue({ ok: true, data: artifacts })
+    mockPostScanNotification.mockResolvedValue({ ok: true, data: undefined })
+
+    await awaitScanAnalysis('acme', 'scan-1', {
+      callbackUrl: 'https://ci.example.com/hook',
+      timeoutMs: 30_000,
+    })
+
+    expect(mockPostScanNotification).toHaveBeenCalledWith(
+      'https://ci.example.com/hook',
+      expect.objectContaining({
+        alerts: { critical: 1, high: 1, low: 1, middle: 0 },
+        event: 'scan.analyzed',
+        orgSlug: 'acme',
+        packages: 3,
+        scanId: 'scan-1',
+      }),
+    )
+  })
+
+  it('only warns when the callback fails', async () => {
+    mockFetchScan.mockResolvedValue({ ok: true, data: artifacts })
+    mockPostScanNotification.mockResolvedValue({
+      ok: false,
+      message: 'Failed to post the notification',
+      cause: 'The webhook responded with 500 Internal Server Error',
+    })
+
+    const result = await awaitScanAnalysis('acme', 'scan-1', {
+      callbackUrl: 'https://ci.example.com/hook',
+      timeoutMs: 30_000,
+    })
+
+    expect(result).toMatchObject({
+      ok: true,
+      data: {
+        callbackError: 'The webhook responded with 500 Internal Server Error',
+      },
+    })
+    expect(mockLogger.warn).toHaveBeenCalledWith(
+      'Failed to post the notification: The webhook responded with 500 Internal Server Error',
+    )
+  })
+})
@@ -35,6 +35,7 @@ vi.mock(
 
 // Mock dependencies.
 const mockHandleCreateNewScan = vi.hoisted(() => vi.fn())
+const mockHandleCreateSbomScan = vi.hoisted(() => vi.fn())
 const mockOutputCreateNewScan = vi.hoisted(() => vi.fn())
 const mockSuggestOrgSlug = vi.hoisted(() =>
   vi.fn().mockResolvedValue('test-org'),
@@ -71,6 +72,13 @@ vi.mock(
   }),
 )
 
+vi.mock(
+  import('../../../../src/commands/scan/handle-create-sbom-scan.mts'),
+  () => ({
+    handleCreateSbomScan: mockHandleCreateSbomScan,
+  }),
+)
+
 vi.mock(
   import('../../../../src/commands/scan/output-create-new-scan.mts'),
   () => ({
@@ -851,6 +859,52 @@ describe('cmd-scan-create', () => {
       )
     })
 
+    it('should scan the components of --from-sbom instead of targets', async () => {
+      mockHasDefaultApiToken.mockReturnValueOnce(true)
+
+      await cmdScanCreate.run(
+        [
+          '--org',
+          'test-org',
+          '--from-sbom',
+          'sbom.cdx.json',
+          '--no-interactive',
+        ],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleCreateNewScan).not.toHaveBeenCalled()
+      expect(mockHandleCreateSbomScan).toHaveBeenCalledWith(
+        expect.objectContaining({
+          orgSlug: 'test-org',
+          repoName: 'test-repo',
+          sbomPath: 'sbom.cdx.json',
+        }),
+      )
+    })
+
+    it('should fail when --from-sbom is combined with targets or --reach', async () => {
+      mockHasDefaultApiToken.mockReturnValue(true)
+
+      await cmdScanCreate.run(
+        ['--org', 'test-org', '--from-sbom', 'sbom.cdx.json', '.'],
+        importMeta,
+        context,
+      )
+      expect(process.exitCode).toBe(2)
+
+      process.exitCode = undefined
+      await cmdScanCreate.run(
+        ['--org', 'test-org', '--from-sbom', 'sbom.cdx.json', '--reach'],
+        importMeta,
+        context,
+      )
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleCreateSbomScan).not.toHaveBeenCalled()
+      mockHasDefaultApiToken.mockReturnValue(false)
+    })
+
     it('should default to current directory if no target specified', async () => {
       mockHasDefaultApiToken.mockReturnValueOnce(true)
 
@@ -1063,6 +1117,102 @@ describe('cmd-scan-create', () => {
       })
     })
 
+    describe('--only-reachable flag', () => {
+      it('should pass onlyReachable flag to handler', async () => {
+        mockHasDefaultApiToken.mockReturnValueOnce(true)
+
+        await cmdScanCreate.run(
+          [
+            '--org',
+            'test-org',
+            '--report',
+            '--only-reachable',
+            '.',
+            '--no-interactive',
+          ],
+          importMeta,
+          context,
+        )
+
+        expect(mockHandleCreateNewScan).toHaveBeenCalledWith(
+          expect.objectContaining({
+            onlyReachable: true,
+            report: true,
+          }),
+        )
+      })
+
+      it('should fail when --only-reachable is set without --report', async () => {
+        mockHasDefaultApiToken.mockReturnValueOnce(true)
+
+        await cmdScanCreate.run(
+          ['--org', 'test-org', '--only-reachable', '.', '--no-interactive'],
+          importMeta,
+          context,
+        )
+
+        expect(process.exitCode).toBe(2)
+        expect(mockHandleCreateNewScan).not.toHaveBeenCalled()
+      })
+    })
+
+    describe('--notify flag', () => {
+      it('should pass the notify channel and webhook URL to handler', async () => {
+        mockHasDefaultApiToken.mockReturnValueOnce(true)
+
+        await cmdScanCreate.run(
+          [
+            '--org',
+            'test-org',
+            '--notify',
+            'teams',
+            '--webhook-url',
+            'https://example.webhook.office.com/webhookb2/abc',
+            '.',
+            '--no-interactive',
+          ],
+          importMeta,
+          context,
+        )
+
+        expect(mockHandleCreateNewScan).toHaveBeenCalledWith(
+          expect.objectContaining({
+            notify: {
+              channel: 'teams',
+              webhookUrl: 'https://example.webhook.office.com/webhookb2/abc',
+            },
+          }),
+        )
+      })
+
+      it('should fail on an unknown channel or without a webhook URL', async () => {
+        mockHasDefaultApiToken.mockReturnValue(true)
+
+        await cmdScanCreate.run(
+          [
+            '--org',
+            'test-org',
+            '--notify',
+            'discord',
+            '--webhook-url',
+            'https://example.com/hook',
+            '.',
+            '--no-interactive',
+          ],
+          importMeta,
+          context,
+        )
+        await cmdScanCreate.run(
+          ['--org', 'test-org', '--notify', 'slack', '.', '--no-interactive'],
+          importMeta,
+          context,
+        )
+
+        expect(process.exitCode).toBe(2)
+        expect(mockHandleCreateNewScan).not.toHaveBeenCalled()
+      })
+    })
+
     describe('--committers flag', () => {
       it('should pass committers flag to handler', async () => {
         mockHasDefaultApiToken.mockReturnValueOnce(true)
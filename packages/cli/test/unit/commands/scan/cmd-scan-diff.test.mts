@@ -128,6 +128,20 @@ describe('cmd-scan-diff', () => {
       )
     })
 
+    it('should pass --no-alerts flag to handleDiffScan', async () => {
+      await cmdScanDiff.run(
+        [testScanId1, testScanId2, '--no-alerts'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleDiffScan).toHaveBeenCalledWith(
+        expect.objectContaining({
+          alerts: false,
+        }),
+      )
+    })
+
     it('should pass --depth flag to handleDiffScan', async () => {
       await cmdScanDiff.run(
         [testScanId1, testScanId2, '--depth', '5'],
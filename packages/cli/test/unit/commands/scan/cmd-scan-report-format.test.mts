@@ -0,0 +1,230 @@
+/**
+ * Unit tests for scan report command output formats.
+ *
+ * Tests that --format, the CSV --columns and --output-template are checked
+ * and passed on to handleScanReport. The other flags are covered in
+ * cmd-scan-report.test.mts.
+ */
+
+import { mkdtempSync, rmSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdScanReport } from '../../../../src/commands/scan/cmd-scan-report.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+import type * as SdkModule from '../../../../src/util/socket/sdk.mjs'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockHandleScanReport = vi.hoisted(() => vi.fn())
+const mockDetermineOrgSlug = vi.hoisted(() =>
+  vi.fn().mockResolvedValue(['test-org', 'test-org']),
+)
+const mockHasDefaultApiToken = vi.hoisted(() => vi.fn().mockReturnValue(true))
+
+vi.mock(import('../../../../src/commands/scan/handle-scan-report.mts'), () => ({
+  handleScanReport: mockHandleScanReport,
+}))
+
+vi.mock(import('../../../../src/util/socket/org-slug.mjs'), () => ({
+  determineOrgSlug: mockDetermineOrgSlug,
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), async importOriginal => {
+  const actual = await importOriginal<typeof SdkModule>()
+  return {
+    ...actual,
+    hasDefaultApiToken: mockHasDefaultApiToken,
+  }
+})
+
+describe('cmd-scan-report', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  describe('run', () => {
+    const importMeta = { url: 'file:///test/cmd-scan-report.mts' }
+    const context = { parentName: 'socket scan' }
+    const testScanId = '000aaaa1-0000-0a0a-00a0-00a0000000a0'
+
+    it('should pass --format sarif to handleScanReport', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--format', 'sarif'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleScanReport).toHaveBeenCalledWith(
+        expect.objectContaining({
+          format: 'sarif',
+        }),
+      )
+    })
+
+    it('should pass --format gitlab-code-quality to handleScanReport', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--format', 'gitlab-code-quality'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleScanReport).toHaveBeenCalledWith(
+        expect.objectContaining({
+          format: 'gitlab-code-quality',
+        }),
+      )
+    })
+
+    it('should fail on an unsupported --format', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--format', 'xml'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanReport).not.toHaveBeenCalled()
+    })
+
+    it('should pass --format csv columns to handleScanReport', async () => {
+      await cmdScanReport.run(
+        [
+          testScanId,
+          '--format',
+          'csv',
+          '--columns',
+          'package,severity',
+          '--no-csv-header',
+        ],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleScanReport).toHaveBeenCalledWith(
+        expect.objectContaining({
+          columns: ['package', 'severity'],
+          csvHeader: false,
+          format: 'csv',
+        }),
+      )
+    })
+
+    it('should default the --format csv columns', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--format', 'csv'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleScanReport).toHaveBeenCalledWith(
+        expect.objectContaining({
+          columns: [
+            'package',
+            'version',
+            'severity',
+            'alertType',
+            'directDep',
+            'filePath',
+          ],
+          csvHeader: true,
+        }),
+      )
+    })
+
+    it('should fail on an unknown --columns name', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--format', 'csv', '--columns', 'package,score'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanReport).not.toHaveBeenCalled()
+    })
+
+    it('should fail when --columns is given without --format csv', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--columns', 'package'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanReport).not.toHaveBeenCalled()
+    })
+
+    it('should pass the resolved --output-template path to handleScanReport', async () => {
+      const templatePath = path.join(
+        mkdtempSync(path.join(os.tmpdir(), 'socket-template-')),
+        'slack.hbs',
+      )
+      writeFileSync(templatePath, '{{orgSlug}}')
+
+      await cmdScanReport.run(
+        [testScanId, '--output-template', templatePath],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleScanReport).toHaveBeenCalledWith(
+        expect.objectContaining({
+          outputTemplate: templatePath,
+        }),
+      )
+      rmSync(path.dirname(templatePath), { force: true, recursive: true })
+    })
+
+    it('should fail when the --output-template file does not exist', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--output-template', '/nonexistent/slack.hbs'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanReport).not.toHaveBeenCalled()
+    })
+
+    it('should fail when --output-template is combined with --format', async () => {
+      await cmdScanReport.run(
+        [
+          testScanId,
+          '--output-template',
+          '/nonexistent/slack.hbs',
+          '--format',
+          'sarif',
+        ],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanReport).not.toHaveBeenCalled()
+    })
+  })
+})
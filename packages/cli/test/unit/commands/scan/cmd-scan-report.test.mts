@@ -2,6 +2,8 @@
  * Unit tests for scan report command.
  *
  * Tests the command that checks scan results against organizational policies.
+ * The --format, --columns and --output-template cases live in
+ * cmd-scan-report-format.test.mts.
  */
 
 import { beforeEach, describe, expect, it, vi } from 'vitest'
@@ -158,6 +160,34 @@ describe('cmd-scan-report', () => {
       )
     })
 
+    it('should pass --show-evidence and the secrets category to handleScanReport', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--alert-category', 'secrets', '--show-evidence'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleScanReport).toHaveBeenCalledWith(
+        expect.objectContaining({
+          alertFilter: expect.objectContaining({
+            alertCategories: ['secrets'],
+          }),
+          showEvidence: true,
+        }),
+      )
+    })
+
+    it('should fail on --show-evidence with --format', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--show-evidence', '--format', 'sarif'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanReport).not.toHaveBeenCalled()
+    })
+
     it('should pass --license flag to handleScanReport', async () => {
       await cmdScanReport.run([testScanId, '--license'], importMeta, context)
 
@@ -182,6 +212,32 @@ describe('cmd-scan-report', () => {
       )
     })
 
+    it('should pass --output as the output file path', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--format', 'html', '--output', 'report.html'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleScanReport).toHaveBeenCalledWith(
+        expect.objectContaining({
+          filepath: 'report.html',
+          format: 'html',
+        }),
+      )
+    })
+
+    it('should fail when both --output and an output path are given', async () => {
+      await cmdScanReport.run(
+        [testScanId, 'a.html', '--output', 'b.html'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanReport).not.toHaveBeenCalled()
+    })
+
     it('should pass --org flag to determineOrgSlug', async () => {
       mockDetermineOrgSlug.mockResolvedValueOnce(['custom-org', 'custom-org'])
 
@@ -234,6 +290,31 @@ describe('cmd-scan-report', () => {
       expect(mockHandleScanReport).not.toHaveBeenCalled()
     })
 
+    it('should pass --fail-on to handleScanReport', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--fail-on', 'critical'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleScanReport).toHaveBeenCalledWith(
+        expect.objectContaining({
+          failOn: 'critical',
+        }),
+      )
+    })
+
+    it('should fail on an unsupported --fail-on', async () => {
+      await cmdScanReport.run(
+        [testScanId, '--fail-on', 'medium'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanReport).not.toHaveBeenCalled()
+    })
+
     it('should pass --no-interactive to determineOrgSlug', async () => {
       await cmdScanReport.run(
         [testScanId, '--no-interactive'],
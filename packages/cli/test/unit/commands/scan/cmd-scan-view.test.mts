@@ -4,7 +4,7 @@
  * Tests the command that views raw scan results.
  */
 
-import { beforeEach, describe, expect, it, vi } from 'vitest'
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
 
 import { cmdScanView } from '../../../../src/commands/scan/cmd-scan-view.mts'
 
@@ -34,6 +34,7 @@ vi.mock(
 
 // Mock dependencies.
 const mockHandleScanView = vi.hoisted(() => vi.fn())
+const mockHandleScanViewInteractive = vi.hoisted(() => vi.fn())
 const mockStreamScan = vi.hoisted(() => vi.fn())
 const mockDetermineOrgSlug = vi.hoisted(() =>
   vi.fn().mockResolvedValue(['test-org', 'test-org']),
@@ -42,6 +43,7 @@ const mockHasDefaultApiToken = vi.hoisted(() => vi.fn().mockReturnValue(true))
 
 vi.mock(import('../../../../src/commands/scan/handle-scan-view.mts'), () => ({
   handleScanView: mockHandleScanView,
+  handleScanViewInteractive: mockHandleScanViewInteractive,
 }))
 
 vi.mock(import('../../../../src/commands/scan/stream-scan.mts'), () => ({
@@ -116,6 +118,7 @@ describe('cmd-scan-view', () => {
         testScanId,
         '',
         'text',
+        undefined,
       )
     })
 
@@ -127,6 +130,7 @@ describe('cmd-scan-view', () => {
         testScanId,
         './output.json',
         'text',
+        undefined,
       )
     })
 
@@ -149,6 +153,7 @@ describe('cmd-scan-view', () => {
         testScanId,
         '',
         'text',
+        undefined,
       )
     })
 
@@ -160,6 +165,7 @@ describe('cmd-scan-view', () => {
         testScanId,
         '',
         'json',
+        undefined,
       )
     })
 
@@ -171,6 +177,7 @@ describe('cmd-scan-view', () => {
         testScanId,
         '',
         'markdown',
+        undefined,
       )
     })
 
@@ -239,6 +246,89 @@ describe('cmd-scan-view', () => {
       expect(mockHandleScanView).not.toHaveBeenCalled()
     })
 
+    describe('--interactive terminal UI', () => {
+      let stdinIsTTY: boolean | undefined
+      let stdoutIsTTY: boolean | undefined
+
+      beforeEach(() => {
+        stdinIsTTY = process.stdin.isTTY
+        stdoutIsTTY = process.stdout.isTTY
+        process.stdin.isTTY = true
+        process.stdout.isTTY = true
+      })
+
+      afterEach(() => {
+        process.stdin.isTTY = stdinIsTTY as boolean
+        process.stdout.isTTY = stdoutIsTTY as boolean
+      })
+
+      it('should open the terminal UI on --interactive', async () => {
+        await cmdScanView.run(
+          [testScanId, '--interactive'],
+          importMeta,
+          context,
+        )
+
+        expect(mockHandleScanViewInteractive).toHaveBeenCalledWith(
+          'test-org',
+          testScanId,
+          'local',
+          undefined,
+        )
+        expect(mockHandleScanView).not.toHaveBeenCalled()
+      })
+
+      it('should pass --triage-target=org', async () => {
+        await cmdScanView.run(
+          [testScanId, '--interactive', '--triage-target', 'org'],
+          importMeta,
+          context,
+        )
+
+        expect(mockHandleScanViewInteractive).toHaveBeenCalledWith(
+          'test-org',
+          testScanId,
+          'org',
+          undefined,
+        )
+      })
+
+      it('should fail on an unknown --triage-target', async () => {
+        await cmdScanView.run(
+          [testScanId, '--interactive', '--triage-target', 'remote'],
+          importMeta,
+          context,
+        )
+
+        expect(process.exitCode).toBe(2)
+        expect(mockHandleScanViewInteractive).not.toHaveBeenCalled()
+      })
+
+      it('should fail without a terminal', async () => {
+        process.stdout.isTTY = false
+
+        await cmdScanView.run(
+          [testScanId, '--interactive'],
+          importMeta,
+          context,
+        )
+
+        expect(process.exitCode).toBe(2)
+        expect(mockHandleScanViewInteractive).not.toHaveBeenCalled()
+      })
+
+      it('should fail when combined with --json', async () => {
+        await cmdScanView.run(
+          [testScanId, '--interactive', '--json'],
+          importMeta,
+          context,
+        )
+
+        expect(process.exitCode).toBe(2)
+        expect(mockHandleScanViewInteractive).not.toHaveBeenCalled()
+      })
+    })
+
     it('should show scan ID in dry-run', async () => {
       await cmdScanView.run(['--dry-run', testScanId], importMeta, context)
 
@@ -0,0 +1,144 @@
+/**
+ * Unit tests for scan watch command.
+ *
+ * Tests the command that rescans manifests whenever they change.
+ */
+
+import path from 'node:path'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdScanWatch } from '../../../../src/commands/scan/cmd-scan-watch.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+import type * as SdkModule from '../../../../src/util/socket/sdk.mjs'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockHandleScanWatch = vi.hoisted(() => vi.fn())
+const mockDetermineOrgSlug = vi.hoisted(() =>
+  vi.fn().mockResolvedValue(['test-org', 'test-org']),
+)
+const mockHasDefaultApiToken = vi.hoisted(() => vi.fn().mockReturnValue(true))
+
+vi.mock(import('../../../../src/commands/scan/handle-scan-watch.mts'), () => ({
+  handleScanWatch: mockHandleScanWatch,
+}))
+
+vi.mock(
+  import('../../../../src/commands/scan/cmd-scan-create-defaults.mts'),
+  () => ({
+    applyScanCreateDefaults: vi.fn().mockResolvedValue({
+      autoManifest: false,
+      branchName: 'main',
+      repoName: 'test-repo',
+      report: false,
+      workspace: '',
+    }),
+  }),
+)
+
+vi.mock(import('../../../../src/util/socket/org-slug.mjs'), () => ({
+  determineOrgSlug: mockDetermineOrgSlug,
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), async importOriginal => {
+  const actual = await importOriginal<typeof SdkModule>()
+  return {
+    ...actual,
+    hasDefaultApiToken: mockHasDefaultApiToken,
+  }
+})
+
+describe('cmd-scan-watch', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  describe('command metadata', () => {
+    it('should have correct description', () => {
+      expect(cmdScanWatch.description).toBe(
+        'Rescan manifests on change and show new alerts as they appear',
+      )
+    })
+
+    it('should not be hidden', () => {
+      expect(cmdScanWatch.hidden).toBe(false)
+    })
+  })
+
+  describe('run', () => {
+    const importMeta = { url: 'file:///test/cmd-scan-watch.mts' }
+    const context = { parentName: 'socket scan' }
+
+    it('should support --dry-run flag', async () => {
+      await cmdScanWatch.run(['--dry-run'], importMeta, context)
+
+      expect(mockHandleScanWatch).not.toHaveBeenCalled()
+      expect(mockLogger.error).toHaveBeenCalledWith(
+        expect.stringContaining('DryRun'),
+      )
+    })
+
+    it('should fail without an API token', async () => {
+      mockHasDefaultApiToken.mockReturnValueOnce(false)
+
+      await cmdScanWatch.run([], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanWatch).not.toHaveBeenCalled()
+    })
+
+    it('should reject more than one directory', async () => {
+      await cmdScanWatch.run(['a', 'b'], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanWatch).not.toHaveBeenCalled()
+    })
+
+    it('should reject a negative --debounce', async () => {
+      await cmdScanWatch.run(['--debounce', '-1'], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleScanWatch).not.toHaveBeenCalled()
+    })
+
+    it('should watch the given directory', async () => {
+      await cmdScanWatch.run(
+        ['./proj', '--debounce', '250', '--json'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleScanWatch).toHaveBeenCalledWith({
+        branchName: 'main',
+        cwd: path.resolve(process.cwd(), 'proj'),
+        debounceMs: 250,
+        orgSlug: 'test-org',
+        outputKind: 'json',
+        repoName: 'test-repo',
+      })
+    })
+  })
+})
@@ -7,6 +7,8 @@
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 import { cmdScan } from '../../../../src/commands/scan/cmd-scan.mts'
+import { cmdScanBaseline } from '../../../../src/commands/scan/cmd-scan-baseline.mts'
+import { cmdScanCheck } from '../../../../src/commands/scan/cmd-scan-check.mts'
 import { cmdScanCreate } from '../../../../src/commands/scan/cmd-scan-create.mts'
 import { cmdScanDel } from '../../../../src/commands/scan/cmd-scan-del.mts'
 import { cmdScanDiff } from '../../../../src/commands/scan/cmd-scan-diff.mts'
@@ -72,6 +74,10 @@ describe('cmd-scan', () => {
       // Subcommand identity (each entry IS the imported src module instance)
       // is asserted in the "subcommand validation" block below.
       expect(Object.keys(config.subcommands).toSorted()).toEqual([
+        'attest',
+        'await',
+        'baseline',
+        'check',
         'create',
         'del',
         'diff',
@@ -82,6 +88,7 @@ describe('cmd-scan', () => {
         'report',
         'setup',
         'view',
+        'watch',
       ])
       expect(callOptions.description).toBe('Manage Socket scans')
       expect(callOptions.aliases.meta).toMatchObject({
@@ -127,6 +134,10 @@ describe('cmd-scan', () => {
       const subcommands = call[0].subcommands
 
       expect(Object.keys(subcommands)).toEqual([
+        'attest',
+        'await',
+        'baseline',
+        'check',
         'create',
         'del',
         'diff',
@@ -137,6 +148,7 @@ describe('cmd-scan', () => {
         'report',
         'setup',
         'view',
+        'watch',
       ])
     })
 
@@ -181,6 +193,8 @@ describe('cmd-scan', () => {
       // Reference-identity checks (=== inside the expect(actual) call): the
       // routed subcommand must BE the imported src module instance, so the
       // -stable alias (a different module instance) can't stand in here.
+      expect(subcommands.baseline === cmdScanBaseline).toBe(true)
+      expect(subcommands.check === cmdScanCheck).toBe(true)
       expect(subcommands.create === cmdScanCreate).toBe(true)
       expect(subcommands.del === cmdScanDel).toBe(true)
       expect(subcommands.diff === cmdScanDiff).toBe(true)
@@ -256,6 +270,10 @@ describe('cmd-scan', () => {
       const subcommandKeys = Object.keys(call[0].subcommands)
 
       expect(subcommandKeys).toEqual([
+        'attest',
+        'await',
+        'baseline',
+        'check',
         'create',
         'del',
         'diff',
@@ -266,6 +284,7 @@ describe('cmd-scan', () => {
         'report',
         'setup',
         'view',
+        'watch',
       ])
     })
   })
@@ -0,0 +1,100 @@
+/**
+ * Unit tests for getScanAlertDiff.
+ *
+ * Purpose: Tests the alert-level comparison of two full scans used by
+ * `socket scan diff` to decide its exit code.
+ *
+ * Test Coverage: - New and resolved alerts - Version-insensitive matching -
+ * Policy-based blocking alerts.
+ *
+ * Related Files: - src/commands/scan/diff-scan-alerts.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { getScanAlertDiff } from '../../../../src/commands/scan/diff-scan-alerts.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+type SecurityPolicyData = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+const policy = {
+  securityPolicyRules: {
+    envVars: { action: 'warn' },
+    malware: { action: 'error' },
+    protestware: { action: 'error' },
+  },
+  securityPolicyDefault: 'medium',
+} as unknown as SecurityPolicyData
+
+const envVars = { type: 'envVars', severity: 'high' }
+const malware = { type: 'malware', severity: 'high' }
+const protestware = { type: 'protestware', severity: 'high' }
+const unknownAlert = { type: 'unknownAlert', severity: 'high' }
+
+describe('getScanAlertDiff', () => {
+  it('should report nothing for identical scans', () => {
+    const scan = [artifact('lodash', { version: '4.17.20', alerts: [malware] })]
+
+    expect(getScanAlertDiff(scan, scan, policy)).toEqual({
+      added: [],
+      blocking: [],
+      removed: [],
+    })
+  })
+
+  it('should list new and resolved alerts', () => {
+    const diff = getScanAlertDiff(
+      [artifact('left-pad', { alerts: [envVars] })],
+      [artifact('colors', { version: '1.4.1', alerts: [protestware] })],
+      policy,
+    )
+
+    expect(diff.added).toEqual([
+      {
+        action: 'error',
+        purl: 'pkg:npm/colors@1.4.1',
+        severity: 'high',
+        type: 'protestware',
+      },
+    ])
+    expect(diff.removed).toEqual([
+      {
+        action: 'warn',
+        purl: 'pkg:npm/left-pad@1.0.0',
+        severity: 'high',
+        type: 'envVars',
+      },
+    ])
+    expect(diff.blocking).toEqual(diff.added)
+  })
+
+  it('should not report existing alerts again after a version bump', () => {
+    const diff = getScanAlertDiff(
+      [artifact('lodash', { version: '4.17.20', alerts: [malware] })],
+      [
+        artifact('lodash', {
+          version: '4.17.21',
+          alerts: [malware, envVars],
+        }),
+      ],
+      policy,
+    )
+
+    expect(diff.added.map(a => a.type)).toEqual(['envVars'])
+    expect(diff.removed).toEqual([])
+    expect(diff.blocking).toEqual([])
+  })
+
+  it('should only block on alerts with an error policy action', () => {
+    const diff = getScanAlertDiff(
+      [],
+      [artifact('a', { alerts: [envVars, unknownAlert, malware] })],
+      policy,
+    )
+
+    expect(diff.added).toHaveLength(3)
+    expect(diff.blocking.map(a => a.type)).toEqual(['malware'])
+  })
+})
@@ -0,0 +1,141 @@
+/**
+ * Unit tests for the repository discovery of `socket scan create --all-repos`.
+ *
+ * Tests finding the git clones of a local dir and listing the repositories
+ * of a GitHub organization without the archived and empty ones.
+ */
+
+import { mkdirSync, mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+const mockPaginate = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/git/github.mts'), () => ({
+  getOctokit: () => ({
+    paginate: mockPaginate,
+    repos: { listForOrg: vi.fn() },
+  }),
+}))
+
+vi.mock(
+  import('../../../../src/util/git/operations.mts'),
+  async importOriginal => {
+    const actual = await importOriginal()
+    return {
+      ...actual,
+      getRepoInfo: async (cwd: string) =>
+        path.basename(cwd) === 'checkout'
+          ? { owner: 'acme', repo: 'website' }
+          : undefined,
+      gitBranch: async () => 'main',
+    }
+  },
+)
+
+import { discoverRepos } from '../../../../src/commands/scan/discover-repos.mts'
+
+describe('discoverRepos', () => {
+  let tmpDir: string
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    tmpDir = path.resolve(mkdtempSync(path.join(os.tmpdir(), 'socket-repos-')))
+  })
+
+  afterEach(async () => {
+    await safeDelete(tmpDir, { force: true })
+  })
+
+  it('finds the git clones of a local dir', async () => {
+    mkdirSync(path.join(tmpDir, 'api', '.git'), { recursive: true })
+    mkdirSync(path.join(tmpDir, 'checkout'))
+    // Worktrees have a `.git` file.
+    writeFileSync(path.join(tmpDir, 'checkout', '.git'), 'gitdir: ../x')
+    mkdirSync(path.join(tmpDir, 'notes'))
+    writeFileSync(path.join(tmpDir, 'README.md'), '')
+
+    const result = await discoverRepos({ kind: 'local', dir: tmpDir })
+
+    expect(result).toEqual({
+      ok: true,
+      data: [
+        {
+          branch: 'main',
+          dir: path.join(tmpDir, 'api'),
+          isDefaultBranch: false,
+          name: 'api',
+        },
+        {
+          branch: 'main',
+          dir: path.join(tmpDir, 'checkout'),
+          isDefaultBranch: false,
+          name: 'website',
+        },
+      ],
+    })
+  })
+
+  it('fails when the local dir cannot be read', async () => {
+    const result = await discoverRepos({
+      kind: 'local',
+      dir: path.join(tmpDir, 'missing'),
+    })
+
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Failed to read the directory of clones',
+    })
+  })
+
+  it('skips archived and empty GitHub repositories', async () => {
+    mockPaginate.mockResolvedValue([
+      {
+        archived: false,
+        clone_url: 'https://github.com/acme/api.git',
+        default_branch: 'main',
+        full_name: 'acme/api',
+        name: 'api',
+        size: 120,
+      },
+      {
+        archived: true,
+        clone_url: 'https://github.com/acme/old.git',
+        default_branch: 'master',
+        full_name: 'acme/old',
+        name: 'old',
+        size: 50,
+      },
+      {
+        archived: false,
+        clone_url: 'https://github.com/acme/new.git',
+        default_branch: 'main',
+        full_name: 'acme/new',
+        name: 'new',
+        size: 0,
+      },
+    ])
+
+    const result = await discoverRepos({ kind: 'github', org: 'acme' })
+
+    expect(mockPaginate).toHaveBeenCalledWith(expect.anything(), {
+      org: 'acme',
+      per_page: 100,
+      type: 'all',
+    })
+    expect(result).toEqual({
+      ok: true,
+      data: [
+        {
+          branch: 'main',
+          cloneUrl: 'https://github.com/acme/api.git',
+          isDefaultBranch: true,
+          name: 'api',
+        },
+      ],
+    })
+  })
+})
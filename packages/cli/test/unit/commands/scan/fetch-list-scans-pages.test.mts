@@ -0,0 +1,290 @@
+/**
+ * Unit tests for fetchOrgFullScanPages.
+ *
+ * Purpose: Tests fetching pages of scans for the committer and status filters
+ * the list endpoint does not support.
+ *
+ * Test Coverage: - Paging until the limit or a page of matches - Paging with
+ * all - The next page when the limit cuts a page - Long and looping page
+ * chains.
+ *
+ * Testing Approach: Uses SDK test helpers to mock Socket API interactions.
+ *
+ * Related Files: - src/commands/scan/fetch-list-scans.mts (implementation)
+ */
+
+import { describe, expect, it, vi } from 'vitest'
+
+import { setupSdkMockSuccess } from '../../../helpers/sdk-test-helpers.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock the dependencies.
+vi.mock(import('../../../../src/util/socket/api.mts'), () => ({
+  handleApiCall: vi.fn(),
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mts'), () => ({
+  setupSdk: vi.fn(),
+}))
+
+describe('fetchOrgFullScanPages', () => {
+  const config = {
+    branch: '',
+    direction: 'desc',
+    from_time: '',
+    orgSlug: 'test-org',
+    page: 1,
+    perPage: 2,
+    repo: '',
+    sort: 'created_at',
+  }
+
+  async function mockPages(
+    pages: Array<{ committers: string[]; id: string; scan_state?: string }[]>,
+  ) {
+    const { mockHandleApi } = await setupSdkMockSuccess('listFullScans', {})
+    mockHandleApi.mockReset()
+    for (let i = 0; i < pages.length; i += 1) {
+      mockHandleApi.mockResolvedValueOnce({
+        ok: true,
+        data: {
+          nextPage: i + 1 < pages.length ? i + 2 : null,
+          results: pages[i],
+        },
+      })
+    }
+    return mockHandleApi
+  }
+
+  it('fetches pages until the limit of matching scans', async () => {
+    const { fetchOrgFullScanPages } =
+      await import('../../../../src/commands/scan/fetch-list-scans.mts')
+
+    const mockHandleApi = await mockPages([
+      [
+        { committers: ['JDoe'], id: 'a' },
+        { committers: ['other'], id: 'b' },
+      ],
+      [
+        { committers: ['jdoe'], id: 'c' },
+        { committers: ['jdoe'], id: 'd' },
+      ],
+      [{ committers: ['jdoe'], id: 'e' }],
+    ])
+
+    const result = await fetchOrgFullScanPages(config, {
+      committer: 'jdoe',
+      limit: 2,
+    })
+
+    expect(mockHandleApi).toHaveBeenCalledTimes(2)
+    expect(result.ok && result.data.results.map(scan => scan.id)).toEqual([
+      'a',
+      'c',
+    ])
+    // 'd' is left out, so the next page is the one it is on.
+    expect(result.ok && result.data.nextPage).toBe(2)
+  })
+
+  it('fetches every page with all and filters by status', async () => {
+    const { fetchOrgFullScanPages } =
+      await import('../../../../src/commands/scan/fetch-list-scans.mts')
+
+    const mockHandleApi = await mockPages([
+      [
+        { committers: [], id: 'a', scan_state: 'pending' },
+        { committers: [], id: 'b' },
+      ],
+      [{ committers: [], id: 'c', scan_state: 'pending' }],
+    ])
+
+    const result = await fetchOrgFullScanPages(config, {
+      all: true,
+      status: 'pending',
+    })
+
+    expect(mockHandleApi).toHaveBeenCalledTimes(2)
+    expect(result.ok && result.data.results.map(scan => scan.id)).toEqual([
+      'a',
+      'c',
+    ])
+  })
+
+  it('fetches pages until a page of scans matches without a limit', async () => {
+    const { fetchOrgFullScanPages } =
+      await import('../../../../src/commands/scan/fetch-list-scans.mts')
+
+    const mockHandleApi = await mockPages([
+      [
+        { committers: ['other'], id: 'a' },
+        { committers: ['other'], id: 'b' },
+      ],
+      [
+        { committers: ['jdoe'], id: 'c' },
+        { committers: ['other'], id: 'd' },
+      ],
+      [
+        { committers: ['jdoe'], id: 'e' },
+        { committers: ['jdoe'], id: 'f' },
+      ],
+      [{ committers: ['jdoe'], id: 'g' }],
+    ])
+
+    const result = await fetchOrgFullScanPages(config, { committer: 'jdoe' })
+
+    expect(mockHandleApi).toHaveBeenCalledTimes(3)
+    expect(result.ok && result.data).toEqual({
+      nextPage: 3,
+      results: [
+        { committers: ['jdoe'], id: 'c' },
+        { committers: ['jdoe'], id: 'e' },
+      ],
+    })
+  })
+
+  it('keeps the next page when the limit ends with a page', async () => {
+    const { fetchOrgFullScanPages } =
+      await import('../../../../src/commands/scan/fetch-list-scans.mts')
+
+    await mockPages([
+      [
+        { committers: ['jdoe'], id: 'a' },
+        { committers: ['jdoe'], id: 'b' },
+      ],
+      [{ committers: ['jdoe'], id: 'c' }],
+    ])
+
+    const result = await fetchOrgFullScanPages(config, {
+      committer: 'jdoe',
+      limit: 2,
+    })
+
+    expect(result.ok && result.data.nextPage).toBe(2)
+  })
+
+  it('fetches over 100 pages with all', async () => {
+    const { fetchOrgFullScanPages } =
+      await import('../../../../src/commands/scan/fetch-list-scans.mts')
+
+    const mockHandleApi = await mockPages(
+      Array.from({ length: 150 }, (_, i) => [
+        { committers: [], id: `scan-${i}` },
+      ]),
+    )
+
+    const result = await fetchOrgFullScanPages(config, { all: true })
+
+    expect(mockHandleApi).toHaveBeenCalledTimes(150)
+    expect(result.ok && result.data.results).toHaveLength(150)
+  })
+
+  it('warns and stops when the next page was fetched before', async () => {
+    const { fetchOrgFullScanPages } =
+      await import('../../../../src/commands/scan/fetch-list-scans.mts')
+
+    const { mockHandleApi } = await setupSdkMockSuccess('listFullScans', {})
+    mockHandleApi.mockReset()
+    mockHandleApi
+      .mockResolvedValueOnce({
+        ok: true,
+        data: { nextPage: 2, results: [{ committers: [], id: 'a' }] },
+      })
+      .mockResolvedValueOnce({
+        ok: true,
+        data: { nextPage: 1, results: [{ committers: [], id: 'b' }] },
+      })
+
+    const result = await fetchOrgFullScanPages(config, { all: true })
+
+    expect(mockHandleApi).toHaveBeenCalledTimes(2)
+    expect(mockLogger.warn).toHaveBeenCalledWith(
+      expect.stringContaining('points back to page 1'),
+    )
+    expect(result.ok && result.data.results.map(scan => scan.id)).toEqual([
+      'a',
+      'b',
+    ])
+  })
+
+  it('requests the pages in turn from config.page on', async () => {
+    const { fetchOrgFullScanPages } =
+      await import('../../../../src/commands/scan/fetch-list-scans.mts')
+
+    const { mockHandleApi, mockSdk } = await setupSdkMockSuccess(
+      'listFullScans',
+      {},
+    )
+    mockHandleApi.mockReset()
+    mockHandleApi
+      .mockResolvedValueOnce({
+        ok: true,
+        data: { nextPage: 3, results: [{ committers: [], id: 'a' }] },
+      })
+      .mockResolvedValueOnce({
+        ok: true,
+        data: {
+          nextPage: null,
+          results: [{ committers: [], id: 'b', scan_state: 'failed' }],
+        },
+      })
+
+    const result = await fetchOrgFullScanPages(
+      { ...config, page: 2 },
+      { status: 'failed' },
+    )
+
+    expect(mockSdk.listFullScans).toHaveBeenNthCalledWith(
+      1,
+      'test-org',
+      expect.objectContaining({ page: 2 }),
+    )
+    expect(mockSdk.listFullScans).toHaveBeenNthCalledWith(
+      2,
+      'test-org',
+      expect.objectContaining({ page: 3 }),
+    )
+    expect(result.ok && result.data.results.map(scan => scan.id)).toEqual([
+      'b',
+    ])
+  })
+
+  it('fetches one page without filters, all or limit', async () => {
+    const { fetchOrgFullScanPages } =
+      await import('../../../../src/commands/scan/fetch-list-scans.mts')
+
+    const mockHandleApi = await mockPages([
+      [{ committers: ['jdoe'], id: 'a' }],
+      [{ committers: ['jdoe'], id: 'b' }],
+    ])
+
+    const result = await fetchOrgFullScanPages(config, {})
+
+    expect(mockHandleApi).toHaveBeenCalledTimes(1)
+    expect(result.ok && result.data).toEqual({
+      nextPage: 2,
+      results: [{ committers: ['jdoe'], id: 'a' }],
+    })
+  })
+})
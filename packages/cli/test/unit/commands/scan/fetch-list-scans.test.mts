@@ -7,6 +7,8 @@
  * Test Coverage: - Successful API operation - SDK setup failure handling - API
  * call error scenarios - Custom SDK options (API tokens, base URLs) - Scan
  * filtering - Pagination - Sort options - Null prototype usage for security.
+ * Fetching several pages for the filters lives in
+ * fetch-list-scans-pages.test.mts.
  *
  * Testing Approach: Uses SDK test helpers to mock Socket API interactions.
  * Validates comprehensive error handling and API integration.
@@ -294,3 +296,27 @@ describe('fetchOrgFullScanList', () => {
     expect(mockSdk.listFullScans).toHaveBeenCalled()
   })
 })
+
+describe('sortScanList', () => {
+  it('sorts by a field the API cannot sort by', async () => {
+    const { sortScanList } =
+      await import('../../../../src/commands/scan/fetch-list-scans.mts')
+
+    const scans = [
+      { branch: 'main', id: 'a' },
+      { branch: 'feature-10', id: 'b' },
+      { branch: 'feature-9', id: 'c' },
+    ] as unknown as Parameters<typeof sortScanList>[0]
+
+    expect(sortScanList(scans, 'branch', 'asc').map(s => s.id)).toEqual([
+      'c',
+      'b',
+      'a',
+    ])
+    expect(sortScanList(scans, 'branch', 'desc').map(s => s.id)).toEqual([
+      'a',
+      'b',
+      'c',
+    ])
+  })
+})
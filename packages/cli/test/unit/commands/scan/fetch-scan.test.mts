@@ -114,6 +114,17 @@ describe('fetchScan', () => {
     expect(result.data).toEqual([{ type: 'package', name: 'ready' }])
   })
 
+  it('gives up once the timeout of the options has passed', async () => {
+    mockQueryApiSafeTextWithStatus.mockResolvedValue(processingResponse())
+
+    const result = await fetchScan('test-org', 'scan-123', { timeoutMs: 0 })
+
+    expect(mockQueryApiSafeTextWithStatus).toHaveBeenCalledTimes(1)
+    expect(mockSleep).not.toHaveBeenCalled()
+    expect(result.ok).toBe(false)
+    expect(result.message).toBe('Scan results not ready')
+  })
+
   it('handles API call failure', async () => {
     const error = {
       ok: false,
@@ -0,0 +1,200 @@
+/**
+ * Unit tests for the client-side alert filters of the scan commands.
+ *
+ * Purpose: Tests how --severity, --alert-category, --alert-type, --ecosystem
+ * and --direct-only are parsed and applied to scan artifacts.
+ *
+ * Test Coverage: - No filter set - Comma-separated and repeated values -
+ * Severity threshold - Alert categories, secrets included - Alert types -
+ * Ecosystems - Direct dependencies - Keeping left out packages for reports -
+ * Dry-run details.
+ *
+ * Related Files: - src/commands/scan/filter-scan-alerts.mts (implementation) -
+ * src/commands/scan/alert-filter-flags.mts - Flag definitions.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  filterScanArtifacts,
+  getScanAlertFilter,
+  getScanAlertFilterDetails,
+} from '../../../../src/commands/scan/filter-scan-alerts.mts'
+
+const artifacts = [
+  {
+    alerts: [
+      { severity: 'critical', type: 'malware' },
+      { severity: 'low', type: 'unmaintained' },
+    ],
+    direct: true,
+    name: 'left-pad',
+    type: 'npm',
+  },
+  {
+    alerts: [{ severity: 'high', type: 'criticalCVE' }],
+    direct: false,
+    name: 'minimist',
+    type: 'npm',
+  },
+  {
+    alerts: [
+      {
+        category: 'supplyChainRisk',
+        severity: 'middle',
+        type: 'networkAccess',
+      },
+      {
+        category: 'supplyChainRisk',
+        severity: 'high',
+        type: 'exposedSecret',
+      },
+    ],
+    direct: true,
+    name: 'requests',
+    type: 'pypi',
+  },
+  { alerts: [], direct: true, name: 'clean', type: 'npm' },
+]
+
+describe('getScanAlertFilter', () => {
+  it('returns undefined without filter flags', () => {
+    expect(
+      getScanAlertFilter({ alertType: [], directOnly: false, severity: '' }),
+    ).toBeUndefined()
+  })
+
+  it('splits comma-separated and repeated values', () => {
+    expect(
+      getScanAlertFilter({
+        alertType: ['malware,criticalCVE', 'typosquat'],
+        directOnly: true,
+        ecosystem: ['NPM'],
+        severity: 'high',
+      }),
+    ).toEqual({
+      alertCategories: [],
+      alertTypes: ['malware', 'criticalCVE', 'typosquat'],
+      directOnly: true,
+      ecosystems: ['npm'],
+      severity: 'high',
+    })
+  })
+})
+
+describe('filterScanArtifacts', () => {
+  const noFilter = {
+    alertCategories: [],
+    alertTypes: [],
+    directOnly: false,
+    ecosystems: [],
+  }
+
+  it('returns the artifacts as is without a filter', () => {
+    expect(filterScanArtifacts(artifacts, undefined)).toBe(artifacts)
+  })
+
+  it('keeps alerts of at least the severity', () => {
+    expect(
+      filterScanArtifacts(artifacts, { ...noFilter, severity: 'high' }),
+    ).toEqual([
+      { ...artifacts[0], alerts: [{ severity: 'critical', type: 'malware' }] },
+      artifacts[1],
+      {
+        ...artifacts[2],
+        alerts: [
+          {
+            category: 'supplyChainRisk',
+            severity: 'high',
+            type: 'exposedSecret',
+          },
+        ],
+      },
+    ])
+  })
+
+  it('keeps secret alerts under their own category', () => {
+    const result = filterScanArtifacts(artifacts, {
+      ...noFilter,
+      alertCategories: ['secrets'],
+    })
+
+    expect(
+      result.map(a => [a.name, a.alerts.map(alert => alert.type)]),
+    ).toEqual([['requests', ['exposedSecret']]])
+  })
+
+  it('keeps alerts of the other categories the Socket API gives them', () => {
+    const result = filterScanArtifacts(artifacts, {
+      ...noFilter,
+      alertCategories: ['supplyChainRisk'],
+    })
+
+    expect(
+      result.map(a => [a.name, a.alerts.map(alert => alert.type)]),
+    ).toEqual([['requests', ['networkAccess']]])
+  })
+
+  it('keeps alerts of the given types', () => {
+    expect(
+      filterScanArtifacts(artifacts, {
+        ...noFilter,
+        alertTypes: ['networkAccess'],
+      }).map(a => a.name),
+    ).toEqual(['requests'])
+  })
+
+  it('keeps packages of the given ecosystems, with or without alerts', () => {
+    expect(
+      filterScanArtifacts(artifacts, { ...noFilter, ecosystems: ['npm'] }).map(
+        a => a.name,
+      ),
+    ).toEqual(['left-pad', 'minimist', 'clean'])
+  })
+
+  it('keeps direct dependencies', () => {
+    expect(
+      filterScanArtifacts(artifacts, { ...noFilter, directOnly: true }).map(
+        a => a.name,
+      ),
+    ).toEqual(['left-pad', 'requests', 'clean'])
+  })
+
+  it('clears the alerts of left out packages with keepPackages', () => {
+    const result = filterScanArtifacts(
+      artifacts,
+      { ...noFilter, directOnly: true, severity: 'critical' },
+      { keepPackages: true },
+    )
+
+    expect(result.map(a => [a.name, a.alerts.length])).toEqual([
+      ['left-pad', 1],
+      ['minimist', 0],
+      ['requests', 0],
+      ['clean', 0],
+    ])
+  })
+})
+
+describe('getScanAlertFilterDetails', () => {
+  it('lists only the filters that are set', () => {
+    expect(
+      getScanAlertFilterDetails({
+        alertCategories: [],
+        alertTypes: ['malware', 'criticalCVE'],
+        directOnly: false,
+        ecosystems: [],
+        severity: 'high',
+      }),
+    ).toEqual({ alertType: 'malware, criticalCVE', severity: 'high' })
+    expect(
+      getScanAlertFilterDetails({
+        alertCategories: ['secrets'],
+        alertTypes: [],
+        directOnly: false,
+        ecosystems: [],
+      }),
+    ).toEqual({ alertCategory: 'secrets' })
+    expect(getScanAlertFilterDetails(undefined)).toEqual({})
+  })
+})
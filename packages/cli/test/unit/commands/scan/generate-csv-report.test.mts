@@ -0,0 +1,83 @@
+/**
+ * Unit tests for the CSV report format of `socket scan report`.
+ *
+ * Purpose: Tests the conversion of SARIF results to one CSV row per alert.
+ *
+ * Test Coverage: - Default columns - Selected columns in order - Header row
+ * toggle - Direct dependencies across manifests - Quoting of fields.
+ *
+ * Related Files: - src/commands/scan/generate-csv-report.mts (implementation)
+ * - src/util/output/csv.mts - CSV serialization.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { generateCsvReport } from '../../../../src/commands/scan/generate-csv-report.mts'
+import { generateSarifReport } from '../../../../src/commands/scan/generate-sarif-report.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+type SecurityPolicyData = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+const scan = [
+  {
+    alerts: [{ key: 'a1', severity: 'critical', type: 'malware' }],
+    direct: false,
+    manifestFiles: [{ file: 'packages/web/package-lock.json' }],
+    name: 'core',
+    namespace: '@evil',
+    type: 'npm',
+    version: '1.0.0',
+  },
+  {
+    alerts: [{ key: 'a1', severity: 'critical', type: 'malware' }],
+    direct: true,
+    manifestFiles: [{ file: 'package-lock.json' }],
+    name: 'core',
+    namespace: '@evil',
+    type: 'npm',
+    version: '1.0.0',
+  },
+] as unknown as SocketArtifact[]
+
+function getCsv(options?: Parameters<typeof generateCsvReport>[2]): string {
+  const sarif = generateSarifReport(
+    scan.slice(1),
+    {
+      securityPolicyRules: { malware: { action: 'error' } },
+    } as unknown as SecurityPolicyData,
+    { reportLevel: 'warn' },
+  )
+  return generateCsvReport(scan, sarif, options)
+}
+
+describe('generateCsvReport', () => {
+  it('prints the default columns', () => {
+    expect(getCsv()).toBe(
+      'package,version,severity,alertType,directDep,filePath\n' +
+        '@evil/core,1.0.0,critical,malware,true,package-lock.json\n',
+    )
+  })
+
+  it('prints the selected columns in order without a header', () => {
+    expect(
+      getCsv({ columns: ['policy', 'ecosystem', 'version'], header: false }),
+    ).toBe('error,npm,1.0.0\n')
+  })
+
+  it('quotes fields with commas', () => {
+    const sarif = generateSarifReport(
+      scan,
+      {
+        securityPolicyRules: { malware: { action: 'error' } },
+      } as unknown as SecurityPolicyData,
+      { reportLevel: 'warn' },
+    )
+    sarif.runs[0]!.results[0]!.properties.severity = 'high, maybe'
+
+    expect(
+      generateCsvReport(scan, sarif, { columns: ['severity'], header: false }),
+    ).toMatch(/^"high, maybe"\n/)
+  })
+})
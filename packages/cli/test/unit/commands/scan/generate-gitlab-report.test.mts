@@ -0,0 +1,135 @@
+/**
+ * Unit tests for the GitLab report formats of `socket scan report`.
+ *
+ * Purpose: Tests the conversion of a Socket SARIF log to GitLab Code Quality
+ * and SAST report artifacts.
+ *
+ * Test Coverage: - Code Quality issues and severities - Stable fingerprints -
+ * SAST scan metadata and vulnerabilities - Purl fallback without a manifest.
+ *
+ * Related Files: - src/commands/scan/generate-gitlab-report.mts
+ * (implementation) - src/commands/scan/generate-sarif-report.mts - Source log.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  GITLAB_SAST_SCHEMA_VERSION,
+  generateGitlabCodeQualityReport,
+  generateGitlabSastReport,
+} from '../../../../src/commands/scan/generate-gitlab-report.mts'
+import { generateSarifReport } from '../../../../src/commands/scan/generate-sarif-report.mts'
+import { getScanWithEnvVars } from '../../../helpers/generate-report-test-helpers.mts'
+
+import type { SarifLog } from '../../../../src/commands/scan/generate-sarif-report.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+type SecurityPolicyData = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+function getSarif(action: string): SarifLog {
+  return generateSarifReport(
+    getScanWithEnvVars(),
+    {
+      securityPolicyRules: { envVars: { action } },
+      securityPolicyDefault: 'medium',
+    } as SecurityPolicyData,
+    { reportLevel: 'warn' },
+  )
+}
+
+describe('generate-gitlab-report', () => {
+  describe('generateGitlabCodeQualityReport', () => {
+    it('should emit one issue per result', () => {
+      const sarif = getSarif('error')
+      const issues = generateGitlabCodeQualityReport(sarif)
+
+      expect(issues).toHaveLength(sarif.runs[0]!.results.length)
+      expect(issues[0]).toMatchObject({
+        categories: ['Security'],
+        check_name: 'envVars',
+        location: { lines: { begin: 1 }, path: 'package-lock.json' },
+        severity: 'blocker',
+        type: 'issue',
+      })
+      expect(issues[0]!.fingerprint).toMatch(/^[0-9a-f]{32}$/)
+    })
+
+    it('should grade alerts the policy does not block by severity', () => {
+      const sarif = getSarif('warn')
+      sarif.runs[0]!.results[0]!.properties.severity = 'high'
+
+      const issues = generateGitlabCodeQualityReport(sarif)
+
+      // The fixture alerts have no severity of their own.
+      expect(issues.map(i => i.severity)).toEqual(['major', 'info'])
+    })
+
+    it('should keep fingerprints stable across runs', () => {
+      const first = generateGitlabCodeQualityReport(getSarif('error'))
+      const second = generateGitlabCodeQualityReport(getSarif('error'))
+
+      expect(first.map(i => i.fingerprint)).toEqual(
+        second.map(i => i.fingerprint),
+      )
+    })
+
+    it('should fall back to the purl without a manifest location', () => {
+      const sarif = getSarif('error')
+      sarif.runs[0]!.results[0]!.locations = []
+
+      const issues = generateGitlabCodeQualityReport(sarif)
+
+      expect(issues[0]!.location.path).toBe('pkg:npm/tslib@1.14.1')
+    })
+
+    it('should emit an empty array for an empty log', () => {
+      const sarif = generateSarifReport([], {} as SecurityPolicyData, {
+        reportLevel: 'warn',
+      })
+
+      expect(generateGitlabCodeQualityReport(sarif)).toEqual([])
+    })
+  })
+
+  describe('generateGitlabSastReport', () => {
+    it('should describe the scan', () => {
+      const report = generateGitlabSastReport(getSarif('error'), {
+        startTime: new Date('2024-01-02T03:04:05.678Z'),
+      })
+
+      expect(report.version).toBe(GITLAB_SAST_SCHEMA_VERSION)
+      expect(report.scan).toMatchObject({
+        analyzer: { id: 'socket', vendor: { name: 'Socket' } },
+        scanner: { id: 'socket', name: 'Socket' },
+        start_time: '2024-01-02T03:04:05',
+        status: 'success',
+        type: 'sast',
+      })
+      expect(report.scan.end_time).toMatch(
+        /^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d$/,
+      )
+    })
+
+    it('should map results to vulnerabilities', () => {
+      const report = generateGitlabSastReport(getSarif('error'))
+
+      expect(report.vulnerabilities).toHaveLength(2)
+      expect(report.vulnerabilities[0]).toMatchObject({
+        identifiers: [
+          {
+            name: 'Environment variable access',
+            type: 'socket_alert',
+            url: 'https://socket.dev/alerts/envVars',
+            value: 'envVars',
+          },
+        ],
+        links: [{ url: 'https://socket.dev/npm/package/tslib/overview/1.14.1' }],
+        location: { file: 'package-lock.json' },
+        severity: 'Unknown',
+      })
+      expect(report.vulnerabilities[0]!.id).toMatch(
+        /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/,
+      )
+    })
+  })
+})
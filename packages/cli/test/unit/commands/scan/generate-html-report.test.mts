@@ -0,0 +1,233 @@
+/**
+ * Unit tests for the HTML format of `socket scan report`.
+ *
+ * Purpose: Tests the report data and markup of the self-contained HTML
+ * report.
+ *
+ * Test Coverage: - Alerts with the direct dependencies that introduce them -
+ * Dependency graph limited to paths to alerted packages - Score
+ * percentages - Health status - Escaping of package data - Empty reports.
+ *
+ * Related Files: - src/commands/scan/generate-html-report.mts
+ * (implementation) - src/util/output/html.mts - Escaping and document shell.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  generateHtmlReport,
+  getAncestorIds,
+  getHtmlReportData,
+} from '../../../../src/commands/scan/generate-html-report.mts'
+import { generateSarifReport } from '../../../../src/commands/scan/generate-sarif-report.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+type SecurityPolicyData = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+const GENERATED_AT = new Date('2024-05-06T07:08:09.000Z')
+
+const score = {
+  license: 1,
+  maintenance: 0.8,
+  overall: 0.5,
+  quality: 0.9,
+  supplyChain: 0.5,
+  vulnerability: 1,
+}
+
+// app -> lib -> evil, other -> evil, and an unrelated direct dependency.
+function getScan(): SocketArtifact[] {
+  return [
+    artifact('app', { id: '1', dependencies: ['2'], direct: true, score }),
+    artifact('lib', { id: '2', dependencies: ['3'], score }),
+    artifact('evil', {
+      id: '3',
+      alerts: [{ key: 'k1', type: 'malware', severity: 'critical' }],
+      manifestFiles: [{ file: 'package-lock.json', start: 0, end: 1 }],
+      score,
+    }),
+    artifact('other', { id: '4', dependencies: ['3'], direct: true, score }),
+    artifact('unrelated', { id: '5', direct: true, score }),
+  ]
+}
+
+function getSarif(scan: SocketArtifact[], action = 'error') {
+  return generateSarifReport(
+    scan,
+    {
+      securityPolicyRules: { malware: { action } },
+      securityPolicyDefault: 'medium',
+    } as SecurityPolicyData,
+    { reportLevel: 'warn' },
+  )
+}
+
+describe('generate-html-report', () => {
+  describe('getAncestorIds', () => {
+    it('should collect every package depending on the target', () => {
+      const parents = new Map([
+        ['c', ['b', 'd']],
+        ['b', ['a']],
+        // A cycle back to the target.
+        ['a', ['c']],
+      ])
+
+      expect([...getAncestorIds('c', parents)].sort()).toEqual([
+        'a',
+        'b',
+        'd',
+      ])
+    })
+  })
+
+  describe('getHtmlReportData', () => {
+    it('should list the direct dependencies that introduce an alert', () => {
+      const scan = getScan()
+      const data = getHtmlReportData(scan, getSarif(scan), {
+        generatedAt: GENERATED_AT,
+        orgSlug: 'fakeOrg',
+        scanId: 'scan-ai-dee',
+      })
+
+      expect(data.alerts).toEqual([
+        expect.objectContaining({
+          introducedBy: ['pkg:npm/app@1.0.0', 'pkg:npm/other@1.0.0'],
+          level: 'error',
+          manifest: 'package-lock.json',
+          packageId: '3',
+          policy: 'error',
+          purl: 'pkg:npm/evil@1.0.0',
+          severity: 'critical',
+          type: 'malware',
+        }),
+      ])
+      expect(data.healthy).toBe(false)
+      expect(data.generatedAt).toBe('2024-05-06T07:08:09.000Z')
+    })
+
+    it('should graph only the paths to alerted packages', () => {
+      const scan = getScan()
+      const data = getHtmlReportData(scan, getSarif(scan), {
+        orgSlug: 'fakeOrg',
+        scanId: 'scan-ai-dee',
+      })
+
+      expect(data.graph.nodes).toEqual(['1', '2', '3', '4'])
+      expect(data.graph.edges).toEqual([
+        ['1', '2'],
+        ['2', '3'],
+        ['4', '3'],
+      ])
+    })
+
+    it('should report scores as percentages and count alerts', () => {
+      const scan = getScan()
+      const data = getHtmlReportData(scan, getSarif(scan), {
+        orgSlug: 'fakeOrg',
+        scanId: 'scan-ai-dee',
+      })
+
+      expect(data.packages).toHaveLength(5)
+      expect(data.packages[2]).toEqual({
+        alerts: 1,
+        direct: false,
+        id: '3',
+        purl: 'pkg:npm/evil@1.0.0',
+        score: {
+          license: 100,
+          maintenance: 80,
+          overall: 50,
+          quality: 90,
+          supplyChain: 50,
+          vulnerability: 100,
+        },
+      })
+      expect(data.packages[0]!.alerts).toBe(0)
+    })
+
+    it('should fall back to the top level ancestors without a graph', () => {
+      const scan = [
+        artifact('app', { id: '1', direct: true, score }),
+        artifact('evil', {
+          id: '3',
+          alerts: [{ key: 'k1', type: 'malware' }],
+          score,
+          topLevelAncestors: ['1'],
+        }),
+      ]
+      const data = getHtmlReportData(scan, getSarif(scan), {
+        orgSlug: 'fakeOrg',
+        scanId: 'scan-ai-dee',
+      })
+
+      expect(data.alerts[0]!.introducedBy).toEqual(['pkg:npm/app@1.0.0'])
+    })
+
+    it('should be healthy when no alert is blocked', () => {
+      const scan = getScan()
+      const data = getHtmlReportData(scan, getSarif(scan, 'warn'), {
+        orgSlug: 'fakeOrg',
+        scanId: 'scan-ai-dee',
+      })
+
+      expect(data.healthy).toBe(true)
+      expect(data.alerts).toHaveLength(1)
+    })
+  })
+
+  describe('generateHtmlReport', () => {
+    it('should render a self-contained document', () => {
+      const scan = getScan()
+      const html = generateHtmlReport(scan, getSarif(scan), {
+        generatedAt: GENERATED_AT,
+        orgSlug: 'fakeOrg',
+        scanId: 'scan-ai-dee',
+      })
+
+      expect(html).toMatch(/^<!DOCTYPE html>/)
+      expect(html).toContain('<title>Socket scan report scan-ai-dee</title>')
+      expect(html).toContain('class="status fail"')
+      expect(html).toContain('<table class="sortable" id="alerts">')
+      expect(html).toContain('<table class="sortable" id="packages">')
+      expect(html).toContain('<tr class="level-error" data-package="3">')
+      expect(html).toContain('<svg id="graph"')
+      expect(html).toContain('<script type="application/json" id="report-data">')
+      // No external resources.
+      expect(html).not.toMatch(/<(?:link|script) [^>]*(?:href|src)=/)
+    })
+
+    it('should escape package data', () => {
+      const scan = [
+        artifact('<img src=x onerror=alert(1)>', {
+          id: '1',
+          alerts: [{ key: 'k1', type: 'malware' }],
+          direct: true,
+          score,
+        }),
+      ]
+      const html = generateHtmlReport(scan, getSarif(scan), {
+        orgSlug: 'fakeOrg',
+        scanId: 'scan-ai-dee',
+      })
+
+      expect(html).not.toContain('<img src=x')
+    })
+
+    it('should render an empty report', () => {
+      const html = generateHtmlReport([], getSarif([]), {
+        orgSlug: 'fakeOrg',
+        scanId: 'scan-ai-dee',
+      })
+
+      expect(html).toContain('class="status pass"')
+      expect(html).toContain(
+        'The scan has no alerts at the reported policy levels.',
+      )
+      expect(html).toContain('No alerted packages to show.')
+      expect(html).not.toContain('<svg id="graph"')
+    })
+  })
+})
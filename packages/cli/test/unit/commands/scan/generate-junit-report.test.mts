@@ -0,0 +1,102 @@
+/**
+ * Unit tests for the JUnit report formats of `socket scan report` and
+ * `socket scan check`.
+ *
+ * Purpose: Tests the conversion of scan results and policy check results to
+ * JUnit test cases.
+ *
+ * Test Coverage: - One case per alert - Failures only for error results -
+ * Alert details - Empty reports - Deny and warn policy entries.
+ *
+ * Related Files: - src/commands/scan/generate-junit-report.mts
+ * (implementation) - src/util/output/junit.mts - XML rendering.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  generateJunitReport,
+  generatePolicyCheckJunitReport,
+} from '../../../../src/commands/scan/generate-junit-report.mts'
+import { generateSarifReport } from '../../../../src/commands/scan/generate-sarif-report.mts'
+import { getScanWithEnvVars } from '../../../helpers/generate-report-test-helpers.mts'
+
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+type SecurityPolicyData = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+function getJunit(action: string): string {
+  const sarif = generateSarifReport(
+    getScanWithEnvVars(),
+    {
+      securityPolicyRules: { envVars: { action } },
+      securityPolicyDefault: 'medium',
+    } as SecurityPolicyData,
+    { reportLevel: 'warn' },
+  )
+  return generateJunitReport(sarif)
+}
+
+describe('generate-junit-report', () => {
+  describe('generateJunitReport', () => {
+    it('should fail one case per blocking alert', () => {
+      const xml = getJunit('error')
+
+      expect(xml).toContain('<testsuite name="Socket" tests="2" failures="2"')
+      expect(xml).toContain(
+        '<testcase classname="package-lock.json" name="envVars in pkg:npm/tslib@1.14.1" time="0">',
+      )
+      expect(xml).toContain('type="envVars"')
+      expect(xml).toContain('Policy action: error')
+      expect(xml).toContain(
+        'More info: https://socket.dev/npm/package/tslib/overview/1.14.1',
+      )
+    })
+
+    it('should pass alerts the policy does not block', () => {
+      const xml = getJunit('warn')
+
+      expect(xml).toContain('tests="2" failures="0"')
+      expect(xml).not.toContain('<failure')
+      expect(xml).toContain('<system-out>')
+    })
+
+    it('should emit a passing case for an empty report', () => {
+      const sarif = generateSarifReport([], {} as SecurityPolicyData, {
+        reportLevel: 'warn',
+      })
+
+      const xml = generateJunitReport(sarif)
+
+      expect(xml).toContain('tests="1" failures="0"')
+      expect(xml).toContain('name="No alerts reported"')
+    })
+  })
+
+  describe('generatePolicyCheckJunitReport', () => {
+    it('should fail deny entries and pass warn entries', () => {
+      const xml = generatePolicyCheckJunitReport(
+        {
+          deny: [{ details: { purl: 'pkg:npm/a@1.0.0' }, msg: 'GPL' }],
+          warn: [{ msg: 'install scripts' }],
+        },
+        { regoPackage: 'socket' },
+      )
+
+      expect(xml).toContain('tests="2" failures="1"')
+      expect(xml).toContain('<failure message="GPL" type="deny">GPL')
+      expect(xml).toContain('&quot;purl&quot;: &quot;pkg:npm/a@1.0.0&quot;')
+      expect(xml).toContain('<system-out>install scripts</system-out>')
+    })
+
+    it('should emit a passing case when the policy passes', () => {
+      const xml = generatePolicyCheckJunitReport(
+        { deny: [], warn: [] },
+        { regoPackage: 'socket' },
+      )
+
+      expect(xml).toContain('name="Policy passed"')
+      expect(xml).toContain('failures="0"')
+    })
+  })
+})
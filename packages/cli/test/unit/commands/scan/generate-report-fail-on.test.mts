@@ -0,0 +1,153 @@
+/**
+ * Unit tests for failing scan reports.
+ *
+ * Purpose: Tests when generateReport marks a report unhealthy beyond the
+ * "error" policy action of its alerts.
+ *
+ * Test Coverage: - Baselined alerts left out - --fail-on severity
+ * thresholds, any and none.
+ *
+ * Related Files: - src/commands/scan/generate-report.mts (implementation)
+ * - test/unit/commands/scan/generate-report.test.mts (policy actions)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { generateReport } from '../../../../src/commands/scan/generate-report.mts'
+import {
+  FOLD_SETTING_NONE,
+  FOLD_SETTING_PKG,
+} from '../../../../src/constants/cli.mts'
+import {
+  REPORT_LEVEL_ERROR,
+  REPORT_LEVEL_WARN,
+} from '../../../../src/constants/reporting.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+const defaultOptions = {
+  fold: FOLD_SETTING_NONE,
+  orgSlug: 'my-org',
+  reportLevel: REPORT_LEVEL_ERROR,
+  scanId: 'scan-123',
+}
+
+describe('generateReport', () => {
+  it('leaves baselined alerts out so only new ones fail', () => {
+    const scan = [
+      artifact('test-pkg', {
+        alerts: [
+          { type: 'badAlert', file: 'index.js', start: 0, end: 10 },
+          { type: 'newAlert', file: 'index.js', start: 0, end: 10 },
+        ],
+      }),
+    ]
+    const policy = {
+      securityPolicyRules: {
+        badAlert: { action: 'error' },
+        newAlert: { action: 'warn' },
+      },
+    }
+    const baseline = {
+      keys: new Set(['pkg:npm/test-pkg@1.0.0 badAlert']),
+      path: '/repo/socket.baseline.json',
+    }
+
+    const result = generateReport(scan, policy, {
+      ...defaultOptions,
+      baseline,
+      fold: FOLD_SETTING_PKG,
+      reportLevel: REPORT_LEVEL_WARN,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(result.data).toEqual(expect.objectContaining({ healthy: true }))
+    const data = result.data as { alerts: Map<string, unknown> }
+    const leaf = data.alerts.get('npm').get('test-pkg')
+    expect(leaf).toHaveProperty('type', 'newAlert')
+  })
+
+  describe('failOn', () => {
+    const scan = [
+      artifact('test-pkg', {
+        alerts: [
+          {
+            type: 'highAlert',
+            file: 'index.js',
+            severity: 'high',
+            start: 0,
+            end: 10,
+          },
+          {
+            type: 'lowAlert',
+            file: 'index.js',
+            severity: 'low',
+            start: 20,
+            end: 30,
+          },
+        ],
+      }),
+    ]
+
+    it('fails on alerts at or above the severity, whatever the action', () => {
+      const policy = {
+        securityPolicyRules: {
+          highAlert: { action: 'warn' },
+          lowAlert: { action: 'error' },
+        },
+      }
+
+      const high = generateReport(scan, policy, {
+        ...defaultOptions,
+        failOn: 'high',
+      })
+      const critical = generateReport(scan, policy, {
+        ...defaultOptions,
+        failOn: 'critical',
+      })
+
+      expect(high.data).toEqual(expect.objectContaining({ healthy: false }))
+      expect(high.message).toContain('--fail-on high')
+      expect(critical.data).toEqual(
+        expect.objectContaining({
+          healthy: true,
+          options: expect.objectContaining({ failOn: 'critical' }),
+        }),
+      )
+    })
+
+    it('fails on any alert the policy does not ignore with any', () => {
+      const policy = {
+        securityPolicyRules: {
+          highAlert: { action: 'ignore' },
+          lowAlert: { action: 'monitor' },
+        },
+      }
+
+      const result = generateReport(scan, policy, {
+        ...defaultOptions,
+        failOn: 'any',
+        short: true,
+      })
+
+      expect(result.data).toEqual({ healthy: false })
+    })
+
+    it('never fails with none', () => {
+      const policy = {
+        securityPolicyRules: {
+          highAlert: { action: 'error' },
+          lowAlert: { action: 'error' },
+        },
+      }
+
+      const result = generateReport(scan, policy, {
+        ...defaultOptions,
+        failOn: 'none',
+      })
+
+      expect(result.data).toEqual(expect.objectContaining({ healthy: true }))
+      const data = result.data as { alerts: Map<string, unknown> }
+      expect(data.alerts.size).toBe(1)
+    })
+  })
+})
@@ -0,0 +1,89 @@
+/**
+ * Unit tests for the tags of scan report alerts.
+ *
+ * Purpose: Tests what generateReport adds to the alerts it reports besides
+ * their policy action.
+ *
+ * Test Coverage: - Import reachability of the package - Secret alert
+ * categories and evidence.
+ *
+ * Related Files: - src/commands/scan/generate-report.mts (implementation)
+ * - test/unit/commands/scan/generate-report.test.mts (policy actions)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { generateReport } from '../../../../src/commands/scan/generate-report.mts'
+import { FOLD_SETTING_PKG } from '../../../../src/constants/cli.mts'
+import { REPORT_LEVEL_ERROR } from '../../../../src/constants/reporting.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+const defaultOptions = {
+  fold: FOLD_SETTING_PKG,
+  orgSlug: 'my-org',
+  reportLevel: REPORT_LEVEL_ERROR,
+  scanId: 'scan-123',
+}
+
+describe('generateReport', () => {
+  it('tags alerts with the import reachability of their package', () => {
+    const scan = [
+      artifact('test-pkg', {
+        id: 'a',
+        alerts: [{ type: 'badAlert', file: 'index.js', start: 0, end: 10 }],
+      }),
+      artifact('other-pkg', {
+        id: 'b',
+        alerts: [{ type: 'badAlert', file: 'index.js', start: 0, end: 10 }],
+      }),
+    ]
+    const policy = { securityPolicyRules: { badAlert: { action: 'error' } } }
+
+    const result = generateReport(scan, policy, {
+      ...defaultOptions,
+      reachability: new Map([['a', 'reachable']]),
+    })
+
+    const data = result.data as { alerts: Map<string, unknown> }
+    const npmAlerts = data.alerts.get('npm')
+    expect(npmAlerts.get('test-pkg')).toHaveProperty(
+      'reachability',
+      'reachable',
+    )
+    expect(npmAlerts.get('other-pkg')).not.toHaveProperty('reachability')
+  })
+
+  it('puts secret alerts in their category, with evidence when asked', () => {
+    const scan = [
+      artifact('test-pkg', {
+        alerts: [
+          {
+            type: 'exposedSecret',
+            file: 'package/config.js',
+            props: { snippet: "token = 'sk_live_0123456789abcdef'" },
+          },
+        ],
+      }),
+    ]
+    const policy = {
+      securityPolicyRules: { exposedSecret: { action: 'error' } },
+    }
+
+    const withoutEvidence = generateReport(scan, policy, defaultOptions)
+    const withEvidence = generateReport(scan, policy, {
+      ...defaultOptions,
+      evidenceCwd: '/nonexistent',
+    })
+
+    const getLeaf = (result: typeof withEvidence) =>
+      (result.data as { alerts: Map<string, Map<string, unknown>> }).alerts
+        .get('npm')!
+        .get('test-pkg')
+    expect(getLeaf(withoutEvidence)).toHaveProperty('category', 'secrets')
+    expect(getLeaf(withoutEvidence)).not.toHaveProperty('evidence')
+    expect(getLeaf(withEvidence)).toHaveProperty('evidence', {
+      file: 'package/config.js',
+      snippet: "token = 'sk_l****'",
+    })
+  })
+})
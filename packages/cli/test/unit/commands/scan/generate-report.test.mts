@@ -5,7 +5,9 @@
  *
  * Test Coverage: - generateReport function - Policy action handling (error,
  * warn, monitor, ignore, defer) - Fold settings (pkg, version, file) - Report
- * level filtering - Health status determination.
+ * level filtering - Health status determination. Failing the report lives in
+ * generate-report-fail-on.test.mts, the tags of alerts in
+ * generate-report-tags.test.mts.
  *
  * Related Files: - src/commands/scan/generate-report.mts (implementation)
  */
@@ -13,10 +15,17 @@
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 // Mock socket URL utility.
-vi.mock(import('../../../../src/util/socket/url.mts'), () => ({
-  getSocketDevPackageOverviewUrlFromPurl: (art: { name: string }) =>
-    `https://socket.dev/pkg/${art.name}`,
-}))
+vi.mock(
+  import('../../../../src/util/socket/url.mts'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof UrlModule>()
+    return {
+      ...actual,
+      getSocketDevPackageOverviewUrlFromPurl: (art: { name: string }) =>
+        `https://socket.dev/pkg/${art.name}`,
+    }
+  },
+)
 
 import { generateReport } from '../../../../src/commands/scan/generate-report.mts'
 import {
@@ -34,6 +43,7 @@ import {
 } from '../../../../src/constants/reporting.mts'
 
 import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+import type * as UrlModule from '../../../../src/util/socket/url.mts'
 
 describe('generate-report', () => {
   beforeEach(() => {
@@ -0,0 +1,220 @@
+/**
+ * Unit tests for generateSarifReport.
+ *
+ * Purpose: Tests the mapping from Socket scan artifacts and org security
+ * policy to a SARIF 2.1.0 log suitable for GitHub Code Scanning.
+ *
+ * Test Coverage: - Rule/result mapping - Policy level filtering - SARIF level
+ * mapping - Manifest line resolution - Locations of packages without a
+ * manifest.
+ *
+ * Related Files: - src/commands/scan/generate-sarif-report.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  SARIF_ROOT_URI,
+  findDependencyRegion,
+  generateSarifReport,
+  isReportedPolicyAction,
+  offsetToRegion,
+  policyActionToSarifLevel,
+} from '../../../../src/commands/scan/generate-sarif-report.mts'
+import { getScanWithEnvVars } from '../../../helpers/generate-report-test-helpers.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+type SecurityPolicyData = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+function getPolicy(action: string): SecurityPolicyData {
+  return {
+    securityPolicyRules: {
+      envVars: {
+        action,
+      },
+    },
+    securityPolicyDefault: 'medium',
+  } as SecurityPolicyData
+}
+
+describe('generate-sarif-report', () => {
+  it('should emit an empty run for an empty scan', () => {
+    const sarif = generateSarifReport([], getPolicy('error'), {
+      reportLevel: 'warn',
+    })
+
+    expect(sarif.version).toBe('2.1.0')
+    expect(sarif.$schema).toContain('sarif-2.1.0')
+    expect(sarif.runs).toHaveLength(1)
+    expect(sarif.runs[0]!.tool.driver.name).toBe('Socket')
+    expect(sarif.runs[0]!.tool.driver.rules).toEqual([])
+    expect(sarif.runs[0]!.results).toEqual([])
+  })
+
+  it('should map alerts to one rule per type and one result per alert', () => {
+    const sarif = generateSarifReport(getScanWithEnvVars(), getPolicy('error'), {
+      reportLevel: 'warn',
+    })
+
+    const { results, tool } = sarif.runs[0]!
+    expect(tool.driver.rules).toHaveLength(1)
+    expect(tool.driver.rules[0]).toMatchObject({
+      id: 'envVars',
+      name: 'Environment variable access',
+      helpUri: 'https://socket.dev/alerts/envVars',
+    })
+    expect(results).toHaveLength(2)
+    expect(results[0]).toMatchObject({
+      ruleId: 'envVars',
+      ruleIndex: 0,
+      level: 'error',
+      locations: [
+        {
+          physicalLocation: {
+            artifactLocation: { uri: 'package-lock.json' },
+          },
+        },
+      ],
+      properties: {
+        policy: 'error',
+        purl: 'pkg:npm/tslib@1.14.1',
+        url: 'https://socket.dev/npm/package/tslib/overview/1.14.1',
+      },
+    })
+  })
+
+  it('should drop alerts below the report level', () => {
+    const sarif = generateSarifReport(
+      getScanWithEnvVars(),
+      getPolicy('monitor'),
+      { reportLevel: 'warn' },
+    )
+
+    expect(sarif.runs[0]!.results).toEqual([])
+    expect(sarif.runs[0]!.tool.driver.rules).toEqual([])
+  })
+
+  it('should map warn policy to SARIF warning', () => {
+    const sarif = generateSarifReport(getScanWithEnvVars(), getPolicy('warn'), {
+      reportLevel: 'warn',
+    })
+
+    expect(sarif.runs[0]!.results.map(r => r.level)).toEqual([
+      'warning',
+      'warning',
+    ])
+  })
+
+  it('should resolve manifest regions when the manifest is readable', () => {
+    const scan = getScanWithEnvVars()
+    scan[0]!.manifestFiles = [{ file: 'package.json' }]
+    const sarif = generateSarifReport(scan, getPolicy('error'), {
+      readManifest: () => '{\n  "dependencies": {\n    "tslib": "^1.14.1"\n  }\n}',
+      reportLevel: 'warn',
+    })
+
+    expect(
+      sarif.runs[0]!.results[0]!.locations[0]!.physicalLocation.region,
+    ).toEqual({ startLine: 3, startColumn: 5 })
+  })
+
+  it('should point packages without a manifest at the scanned manifest', () => {
+    const scan = [
+      artifact('app', {
+        manifestFiles: [{ file: 'packages\\app\\package.json' }],
+      }),
+      artifact('evil', { alerts: [{ type: 'envVars', severity: 'high' }] }),
+    ]
+    const sarif = generateSarifReport(scan, getPolicy('error'), {
+      reportLevel: 'warn',
+    })
+
+    expect(sarif.runs[0]!.results[0]!.locations).toEqual([
+      {
+        physicalLocation: {
+          artifactLocation: { uri: 'packages/app/package.json' },
+        },
+      },
+    ])
+  })
+
+  it('should point at the repository root without a manifest', () => {
+    const scan = [
+      artifact('evil', { alerts: [{ type: 'envVars', severity: 'high' }] }),
+    ]
+    const sarif = generateSarifReport(scan, getPolicy('error'), {
+      reportLevel: 'warn',
+    })
+
+    expect(sarif.runs[0]!.results[0]!.locations).toEqual([
+      { physicalLocation: { artifactLocation: { uri: SARIF_ROOT_URI } } },
+    ])
+  })
+
+  describe('isReportedPolicyAction', () => {
+    it('should always report error', () => {
+      expect(isReportedPolicyAction('error', 'error')).toBe(true)
+      expect(isReportedPolicyAction('error', 'defer')).toBe(true)
+    })
+
+    it('should report lower levels only when requested', () => {
+      expect(isReportedPolicyAction('warn', 'error')).toBe(false)
+      expect(isReportedPolicyAction('warn', 'warn')).toBe(true)
+      expect(isReportedPolicyAction('monitor', 'warn')).toBe(false)
+      expect(isReportedPolicyAction('monitor', 'monitor')).toBe(true)
+      expect(isReportedPolicyAction('defer', 'ignore')).toBe(false)
+      expect(isReportedPolicyAction('defer', 'defer')).toBe(true)
+    })
+
+    it('should ignore unknown actions', () => {
+      expect(isReportedPolicyAction('', 'defer')).toBe(false)
+      expect(isReportedPolicyAction('constructor', 'defer')).toBe(false)
+    })
+  })
+
+  describe('policyActionToSarifLevel', () => {
+    it('should map policy actions to SARIF levels', () => {
+      expect(policyActionToSarifLevel('error')).toBe('error')
+      expect(policyActionToSarifLevel('warn')).toBe('warning')
+      expect(policyActionToSarifLevel('monitor')).toBe('note')
+      expect(policyActionToSarifLevel('ignore')).toBe('note')
+      expect(policyActionToSarifLevel('defer')).toBe('none')
+    })
+  })
+
+  describe('offsetToRegion', () => {
+    it('should convert offsets to one-based line and column', () => {
+      expect(offsetToRegion('abc', 0)).toEqual({ startLine: 1, startColumn: 1 })
+      expect(offsetToRegion('ab\ncd', 4)).toEqual({
+        startLine: 2,
+        startColumn: 2,
+      })
+    })
+
+    it('should clamp out of range offsets', () => {
+      expect(offsetToRegion('ab', 99)).toEqual({ startLine: 1, startColumn: 3 })
+    })
+  })
+
+  describe('findDependencyRegion', () => {
+    it('should prefer the API offset', () => {
+      expect(findDependencyRegion('a\nb\nc', 'c', 2)).toEqual({
+        startLine: 2,
+        startColumn: 1,
+      })
+    })
+
+    it('should fall back to an unquoted mention', () => {
+      expect(findDependencyRegion('x\nrequests==2.0', 'requests')).toEqual({
+        startLine: 2,
+        startColumn: 1,
+      })
+    })
+
+    it('should return undefined when the package is not mentioned', () => {
+      expect(findDependencyRegion('{}', 'tslib')).toBeUndefined()
+    })
+  })
+})
@@ -0,0 +1,85 @@
+/**
+ * Unit tests for the `--output-template` context of `socket scan report`.
+ *
+ * Purpose: Tests the conversion of SARIF results to the template context.
+ *
+ * Test Coverage: - Alert fields - Counts per policy level - Healthy status -
+ * Empty reports.
+ *
+ * Related Files: - src/commands/scan/generate-template-report.mts
+ * (implementation) - src/commands/scan/generate-sarif-report.mts - Alert
+ * source.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { generateSarifReport } from '../../../../src/commands/scan/generate-sarif-report.mts'
+import { generateScanReportTemplateContext } from '../../../../src/commands/scan/generate-template-report.mts'
+import { getScanWithEnvVars } from '../../../helpers/generate-report-test-helpers.mts'
+
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+type SecurityPolicyData = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+function getContext(action: string) {
+  const sarif = generateSarifReport(
+    getScanWithEnvVars(),
+    {
+      securityPolicyRules: { envVars: { action } },
+      securityPolicyDefault: 'medium',
+    } as SecurityPolicyData,
+    { reportLevel: 'warn' },
+  )
+  return generateScanReportTemplateContext(sarif, {
+    orgSlug: 'fakeOrg',
+    reportLevel: 'warn',
+    scanId: 'scan-ai-dee',
+  })
+}
+
+describe('generateScanReportTemplateContext', () => {
+  it('should describe each alert', () => {
+    const context = getContext('error')
+
+    expect(context.alerts).toHaveLength(2)
+    expect(context.alerts[0]).toMatchObject({
+      level: 'error',
+      manifest: 'package-lock.json',
+      policy: 'error',
+      purl: 'pkg:npm/tslib@1.14.1',
+      type: 'envVars',
+      url: 'https://socket.dev/npm/package/tslib/overview/1.14.1',
+    })
+    expect(context.alerts[0]!.title).toBeTruthy()
+    expect(context.alerts[0]!.message).toBeTruthy()
+  })
+
+  it('should be unhealthy when an alert is blocked', () => {
+    const context = getContext('error')
+
+    expect(context).toMatchObject({
+      counts: { error: 2, total: 2, warn: 0 },
+      healthy: false,
+      orgSlug: 'fakeOrg',
+      reportLevel: 'warn',
+      scanId: 'scan-ai-dee',
+    })
+  })
+
+  it('should be healthy when alerts only warn', () => {
+    const context = getContext('warn')
+
+    expect(context.healthy).toBe(true)
+    expect(context.counts).toMatchObject({ error: 0, total: 2, warn: 2 })
+  })
+
+  it('should handle a report without alerts', () => {
+    const context = getContext('ignore')
+
+    expect(context).toMatchObject({
+      alerts: [],
+      counts: { total: 0 },
+      healthy: true,
+    })
+  })
+})
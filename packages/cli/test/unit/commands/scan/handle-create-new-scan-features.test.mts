@@ -286,8 +286,10 @@ describe('handleCreateNewScan', () => {
     await handleCreateNewScan({ ...mockConfig, report: true })
 
     expect(handleScanReport).toHaveBeenCalledWith({
+      cwd: '/test/project',
       filepath: '-',
       fold: 'version',
+      importReachability: false,
       includeLicensePolicy: true,
       orgSlug: 'test-org',
       outputKind: 'json',
@@ -187,7 +187,7 @@ describe('handleCreateNewScan', () => {
     expect(getPackageFilesForScan).toHaveBeenCalledWith(
       ['.'],
       new Set(['package.json', 'yarn.lock']),
-      { cwd: '/test/project' },
+      { cwd: '/test/project', localLockfiles: true },
     )
     expect(fetchCreateOrgFullScan).toHaveBeenCalledWith(
       ['/test/project/package.json', '/test/project/yarn.lock'],
@@ -34,6 +34,7 @@ const mockLogger = vi.hoisted(() => ({
 
 const mockFetchDiffScan = vi.hoisted(() => vi.fn())
 const mockOutputDiffScan = vi.hoisted(() => vi.fn())
+const mockFetchScanAlertDiff = vi.hoisted(() => vi.fn())
 
 vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
   getDefaultLogger: () => mockLogger,
@@ -44,6 +45,13 @@ vi.mock(import('../../../../src/commands/scan/fetch-diff-scan.mts'), () => ({
   fetchDiffScan: mockFetchDiffScan,
 }))
 
+vi.mock(
+  import('../../../../src/commands/scan/fetch-scan-alert-diff.mts'),
+  () => ({
+    fetchScanAlertDiff: mockFetchScanAlertDiff,
+  }),
+)
+
 vi.mock(import('../../../../src/commands/scan/output-diff-scan.mts'), () => ({
   outputDiffScan: mockOutputDiffScan,
 }))
@@ -199,4 +207,51 @@ describe('handleDiffScan', () => {
       }),
     )
   })
+
+  it('compares alerts when requested', async () => {
+    const mockDiff = createSuccessResult({})
+    const mockAlertDiff = createSuccessResult({
+      added: [],
+      blocking: [],
+      removed: [],
+    })
+    mockFetchDiffScan.mockResolvedValue(mockDiff)
+    mockFetchScanAlertDiff.mockResolvedValue(mockAlertDiff)
+
+    await handleDiffScan({
+      alerts: true,
+      depth: 2,
+      file: '',
+      id1: 'scan-1',
+      id2: 'scan-2',
+      orgSlug: 'test-org',
+      outputKind: 'text',
+    })
+
+    expect(mockFetchScanAlertDiff).toHaveBeenCalledWith({
+      id1: 'scan-1',
+      id2: 'scan-2',
+      orgSlug: 'test-org',
+    })
+    expect(mockOutputDiffScan).toHaveBeenCalledWith(
+      mockDiff,
+      expect.objectContaining({ alertDiff: mockAlertDiff }),
+    )
+  })
+
+  it('skips the alert comparison when the diff fails', async () => {
+    mockFetchDiffScan.mockResolvedValue(createErrorResult('Scans not found'))
+
+    await handleDiffScan({
+      alerts: true,
+      depth: 2,
+      file: '',
+      id1: 'scan-1',
+      id2: 'scan-2',
+      orgSlug: 'test-org',
+      outputKind: 'text',
+    })
+
+    expect(mockFetchScanAlertDiff).not.toHaveBeenCalled()
+  })
 })
@@ -0,0 +1,110 @@
+/**
+ * Unit tests for handleScanBaselineWrite.
+ *
+ * Purpose: Tests that `socket scan baseline write` snapshots a scan's alerts
+ * into the baseline file.
+ *
+ * Test Coverage: - Fetch failure handling - Baseline file contents - Write
+ * failure handling.
+ *
+ * Related Files: - src/commands/scan/handle-scan-baseline-write.mts
+ * (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  createErrorResult,
+  createSuccessResult,
+} from '../../../../test/helpers/index.mts'
+
+const mockWriteFile = vi.hoisted(() => vi.fn())
+vi.mock(import('node:fs'), async importOriginal => {
+  const actual = await importOriginal()
+  return {
+    ...actual,
+    promises: { ...actual.promises, writeFile: mockWriteFile },
+  }
+})
+
+const mockFetchScan = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/scan/fetch-scan.mts'), () => ({
+  fetchScan: mockFetchScan,
+}))
+
+const mockOutputScanBaselineWrite = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/scan/output-scan-baseline-write.mts'),
+  () => ({
+    outputScanBaselineWrite: mockOutputScanBaselineWrite,
+  }),
+)
+
+import { handleScanBaselineWrite } from '../../../../src/commands/scan/handle-scan-baseline-write.mts'
+
+const config = {
+  filepath: '/repo/socket.baseline.json',
+  orgSlug: 'acme',
+  outputKind: 'text' as const,
+  scanId: 'scan-1',
+}
+
+describe('handleScanBaselineWrite', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockWriteFile.mockResolvedValue(undefined)
+  })
+
+  it('outputs fetch failures without writing', async () => {
+    const error = createErrorResult('Socket API error')
+    mockFetchScan.mockResolvedValue(error)
+
+    await handleScanBaselineWrite(config)
+
+    expect(mockWriteFile).not.toHaveBeenCalled()
+    expect(mockOutputScanBaselineWrite).toHaveBeenCalledWith(error, 'text')
+  })
+
+  it('writes the alerts of the scan', async () => {
+    mockFetchScan.mockResolvedValue(
+      createSuccessResult([
+        {
+          type: 'npm',
+          name: 'esbuild',
+          version: '0.19.0',
+          alerts: [{ type: 'installScripts' }],
+        },
+      ]),
+    )
+
+    await handleScanBaselineWrite(config)
+
+    expect(mockFetchScan).toHaveBeenCalledWith('acme', 'scan-1')
+    const [filepath, content] = mockWriteFile.mock.calls[0]!
+    expect(filepath).toBe('/repo/socket.baseline.json')
+    expect(JSON.parse(content as string)).toMatchObject({
+      version: 1,
+      scanId: 'scan-1',
+      alerts: [{ purl: 'pkg:npm/esbuild@0.19.0', type: 'installScripts' }],
+    })
+    expect(mockOutputScanBaselineWrite).toHaveBeenCalledWith(
+      { ok: true, data: { alerts: 1, path: '/repo/socket.baseline.json' } },
+      'text',
+    )
+  })
+
+  it('reports write failures', async () => {
+    mockFetchScan.mockResolvedValue(createSuccessResult([]))
+    mockWriteFile.mockRejectedValue(new Error('EACCES'))
+
+    await handleScanBaselineWrite(config)
+
+    expect(mockOutputScanBaselineWrite).toHaveBeenCalledWith(
+      expect.objectContaining({
+        ok: false,
+        message: 'Failed to write baseline file',
+      }),
+      'text',
+    )
+  })
+})
@@ -0,0 +1,113 @@
+/**
+ * Unit tests for handleScanCheck.
+ *
+ * Purpose: Tests the handler behind `socket scan check`, which evaluates a
+ * Rego policy against a scan.
+ *
+ * Test Coverage: - Missing policy file - Fetch failure handling - Rego input
+ * and evaluation delegation.
+ *
+ * Related Files: - src/commands/scan/handle-scan-check.mts (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  createErrorResult,
+  createSuccessResult,
+} from '../../../../test/helpers/index.mts'
+
+const mockExistsSync = vi.hoisted(() => vi.fn(() => true))
+vi.mock(import('node:fs'), async importOriginal => ({
+  ...(await importOriginal()),
+  existsSync: mockExistsSync,
+}))
+
+const mockFetchScanData = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/scan/fetch-report-data.mts'), () => ({
+  fetchScanData: mockFetchScanData,
+}))
+
+const mockOutputScanCheck = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/scan/output-scan-check.mts'), () => ({
+  outputScanCheck: mockOutputScanCheck,
+}))
+
+const mockEvaluateRegoPolicy = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/util/policy/rego.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    evaluateRegoPolicy: mockEvaluateRegoPolicy,
+  }),
+)
+
+import { handleScanCheck } from '../../../../src/commands/scan/handle-scan-check.mts'
+
+const config = {
+  orgSlug: 'acme',
+  outputKind: 'text' as const,
+  policyPath: '/repo/policy.rego',
+  regoPackage: 'socket',
+  scanId: 'scan-1',
+}
+
+describe('handleScanCheck', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockExistsSync.mockReturnValue(true)
+  })
+
+  it('fails without fetching when the policy file is missing', async () => {
+    mockExistsSync.mockReturnValue(false)
+
+    await handleScanCheck(config)
+
+    expect(mockFetchScanData).not.toHaveBeenCalled()
+    expect(mockOutputScanCheck).toHaveBeenCalledWith(
+      expect.objectContaining({ ok: false, message: 'Policy file not found' }),
+      'text',
+    )
+  })
+
+  it('outputs fetch failures', async () => {
+    const error = createErrorResult('Socket API error')
+    mockFetchScanData.mockResolvedValue(error)
+
+    await handleScanCheck(config)
+
+    expect(mockEvaluateRegoPolicy).not.toHaveBeenCalled()
+    expect(mockOutputScanCheck).toHaveBeenCalledWith(error, 'text')
+  })
+
+  it('evaluates the policy against the scan', async () => {
+    mockFetchScanData.mockResolvedValue(
+      createSuccessResult({
+        scan: [{ type: 'npm', name: 'lodash', version: '4.17.21' }],
+        securityPolicy: { securityPolicyRules: {} },
+      }),
+    )
+    const evaluation = createSuccessResult({ deny: [], warn: [] })
+    mockEvaluateRegoPolicy.mockResolvedValue(evaluation)
+
+    await handleScanCheck(config)
+
+    expect(mockFetchScanData).toHaveBeenCalledWith('acme', 'scan-1', {
+      offline: false,
+    })
+    expect(mockEvaluateRegoPolicy).toHaveBeenCalledWith(
+      '/repo/policy.rego',
+      expect.objectContaining({
+        scan: { id: 'scan-1', org: 'acme' },
+        packages: [
+          expect.objectContaining({ purl: 'pkg:npm/lodash@4.17.21' }),
+        ],
+      }),
+      'socket',
+    )
+    expect(mockOutputScanCheck).toHaveBeenCalledWith(evaluation, 'text', {
+      format: undefined,
+      regoPackage: 'socket',
+    })
+  })
+})
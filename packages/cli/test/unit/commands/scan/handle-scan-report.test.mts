@@ -5,7 +5,10 @@
  * comprehensive report generation and formatting.
  *
  * Test Coverage: - Successful operation flow - Fetch failure handling - Input
- * validation - Output formatting delegation - Error propagation.
+ * validation - Output formatting delegation - Error propagation - Local
+ * socket.policy.yml lookup and invalid policy files - Baseline lookup and
+ * explicit baseline files - Import reachability tagging and --only-reachable
+ * filtering.
  *
  * Testing Approach: Mocks fetch and output functions to isolate handler
  * orchestration logic. Validates proper data flow through the handler
@@ -25,6 +28,9 @@ import {
 const mockFetchScanData = vi.hoisted(() => vi.fn())
 const mockOutputScanReport = vi.hoisted(() => vi.fn())
 const mockSetupSdk = vi.hoisted(() => vi.fn())
+const mockFindSocketPolicySync = vi.hoisted(() =>
+  vi.fn(() => ({ ok: true, data: undefined })),
+)
 
 vi.mock(import('../../../../src/commands/scan/fetch-report-data.mts'), () => ({
   fetchScanData: mockFetchScanData,
@@ -38,6 +44,34 @@ vi.mock(import('../../../../src/util/socket/sdk.mts'), () => ({
   setupSdk: mockSetupSdk,
 }))
 
+vi.mock(import('../../../../src/util/policy/socket-policy.mts'), () => ({
+  findSocketPolicySync: mockFindSocketPolicySync,
+}))
+
+const mockFindSocketBaselineSync = vi.hoisted(() =>
+  vi.fn(() => ({ ok: true, data: undefined })),
+)
+const mockReadSocketBaselineSync = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/policy/baseline.mts'), () => ({
+  findSocketBaselineSync: mockFindSocketBaselineSync,
+  readSocketBaselineSync: mockReadSocketBaselineSync,
+}))
+
+const mockFindImportedPackages = vi.hoisted(() => vi.fn())
+const mockFindSocketYmlSync = vi.hoisted(() =>
+  vi.fn(() => ({ ok: true, data: undefined })),
+)
+
+vi.mock(import('../../../../src/util/reachability/source-imports.mts'), () => ({
+  findImportedPackages: mockFindImportedPackages,
+}))
+
+vi.mock(import('../../../../src/util/config.mts'), async importOriginal => ({
+  ...(await importOriginal()),
+  findSocketYmlSync: mockFindSocketYmlSync,
+}))
+
 describe('handleScanReport', () => {
   let handleScanReport: unknown
 
@@ -207,4 +241,196 @@ describe('handleScanReport', () => {
       }),
     )
   })
+
+  it('passes a local socket.policy.yml to the output', async () => {
+    const policy = { block: { alerts: ['malware'] } }
+    mockFindSocketPolicySync.mockReturnValueOnce({
+      ok: true,
+      data: { path: '/repo/socket.policy.yml', policy },
+    })
+    mockFetchScanData.mockResolvedValue(createSuccessResult({}))
+
+    await handleScanReport({
+      cwd: '/repo',
+      orgSlug: 'test-org',
+      scanId: 'scan-123',
+      includeLicensePolicy: false,
+      outputKind: 'text',
+      filepath: '-',
+      fold: 'none',
+      reportLevel: 'warn',
+      short: false,
+    })
+
+    expect(mockFindSocketPolicySync).toHaveBeenCalledWith('/repo')
+    expect(mockOutputScanReport).toHaveBeenCalledWith(
+      expect.any(Object),
+      expect.objectContaining({ localPolicy: policy }),
+    )
+  })
+
+  it('fails without fetching when the local policy is invalid', async () => {
+    const policyError = createErrorResult('Invalid policy file')
+    mockFindSocketPolicySync.mockReturnValueOnce(policyError)
+
+    await handleScanReport({
+      orgSlug: 'test-org',
+      scanId: 'scan-123',
+      includeLicensePolicy: false,
+      outputKind: 'text',
+      filepath: '-',
+      fold: 'none',
+      reportLevel: 'warn',
+      short: false,
+    })
+
+    expect(mockFetchScanData).not.toHaveBeenCalled()
+    expect(mockOutputScanReport).toHaveBeenCalledWith(
+      policyError,
+      expect.any(Object),
+    )
+  })
+
+  it('passes the nearest baseline to the output', async () => {
+    const baseline = { keys: new Set(), path: '/repo/socket.baseline.json' }
+    mockFindSocketBaselineSync.mockReturnValueOnce({
+      ok: true,
+      data: baseline,
+    })
+    mockFetchScanData.mockResolvedValue(createSuccessResult({}))
+
+    await handleScanReport({
+      cwd: '/repo',
+      orgSlug: 'test-org',
+      scanId: 'scan-123',
+      includeLicensePolicy: false,
+      outputKind: 'text',
+      filepath: '-',
+      fold: 'none',
+      reportLevel: 'warn',
+      short: false,
+    })
+
+    expect(mockFindSocketBaselineSync).toHaveBeenCalledWith('/repo')
+    expect(mockOutputScanReport).toHaveBeenCalledWith(
+      expect.any(Object),
+      expect.objectContaining({ baseline }),
+    )
+  })
+
+  it('reads an explicit baseline and fails when it is invalid', async () => {
+    const baselineError = createErrorResult('Invalid baseline file')
+    mockReadSocketBaselineSync.mockReturnValueOnce(baselineError)
+
+    await handleScanReport({
+      baselinePath: '/repo/ci/baseline.json',
+      orgSlug: 'test-org',
+      scanId: 'scan-123',
+      includeLicensePolicy: false,
+      outputKind: 'text',
+      filepath: '-',
+      fold: 'none',
+      reportLevel: 'warn',
+      short: false,
+    })
+
+    expect(mockReadSocketBaselineSync).toHaveBeenCalledWith(
+      '/repo/ci/baseline.json',
+    )
+    expect(mockFindSocketBaselineSync).not.toHaveBeenCalled()
+    expect(mockFetchScanData).not.toHaveBeenCalled()
+    expect(mockOutputScanReport).toHaveBeenCalledWith(
+      baselineError,
+      expect.any(Object),
+    )
+  })
+
+  describe('import reachability', () => {
+    const scan = [
+      {
+        id: 'a',
+        type: 'npm',
+        name: 'express',
+        dependencies: ['b'],
+        alerts: [{ type: 'envVars' }],
+      },
+      { id: 'b', type: 'npm', name: 'qs', alerts: [{ type: 'envVars' }] },
+      { id: 'c', type: 'npm', name: 'unused', alerts: [{ type: 'envVars' }] },
+    ]
+
+    beforeEach(() => {
+      mockFetchScanData.mockResolvedValue(
+        createSuccessResult({ scan, securityPolicy: {} }),
+      )
+      mockFindImportedPackages.mockResolvedValue(new Set(['express']))
+    })
+
+    it('does not read sources by default', async () => {
+      await handleScanReport({
+        orgSlug: 'test-org',
+        scanId: 'scan-123',
+        includeLicensePolicy: false,
+        outputKind: 'text',
+        filepath: '-',
+        fold: 'none',
+        reportLevel: 'warn',
+        short: false,
+      })
+
+      expect(mockFindImportedPackages).not.toHaveBeenCalled()
+      expect(mockOutputScanReport).toHaveBeenCalledWith(
+        expect.any(Object),
+        expect.objectContaining({ reachability: undefined }),
+      )
+    })
+
+    it('tags the report with the reachability of each package', async () => {
+      await handleScanReport({
+        cwd: '/repo',
+        importReachability: true,
+        orgSlug: 'test-org',
+        scanId: 'scan-123',
+        includeLicensePolicy: false,
+        outputKind: 'text',
+        filepath: '-',
+        fold: 'none',
+        reportLevel: 'warn',
+        short: false,
+      })
+
+      expect(mockFindImportedPackages).toHaveBeenCalledWith('/repo', {
+        socketConfig: undefined,
+      })
+      const { 0: scanData, 1: config } = mockOutputScanReport.mock.calls[0]!
+      expect(scanData.data.scan).toBe(scan)
+      expect([...config.reachability]).toEqual([
+        ['a', 'imported'],
+        ['b', 'reachable'],
+        ['c', 'unreachable'],
+      ])
+    })
+
+    it('leaves out unreachable alerts with onlyReachable', async () => {
+      await handleScanReport({
+        cwd: '/repo',
+        onlyReachable: true,
+        orgSlug: 'test-org',
+        scanId: 'scan-123',
+        includeLicensePolicy: false,
+        outputKind: 'text',
+        filepath: '-',
+        fold: 'none',
+        reportLevel: 'warn',
+        short: false,
+      })
+
+      const { 0: scanData } = mockOutputScanReport.mock.calls[0]!
+      expect(
+        scanData.data.scan.map(
+          (a: { alerts: unknown[]; id: string }) =>
+            `${a.id}:${a.alerts.length}`,
+        ),
+      ).toEqual(['a:1', 'b:1', 'c:0'])
+    })
+  })
 })
@@ -5,7 +5,8 @@
  * presentation and formatting.
  *
  * Test Coverage: - Successful operation flow - Fetch failure handling - Input
- * validation - Output formatting delegation - Error propagation.
+ * validation - Output formatting delegation - Error propagation - Triage
+ * writes to the policy file or the org triage API - Interactive view flow.
  *
  * Testing Approach: Mocks fetch and output functions to isolate handler
  * orchestration logic. Validates proper data flow through the handler
@@ -16,17 +17,48 @@
 
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
-import { handleScanView } from '../../../../src/commands/scan/handle-scan-view.mts'
+import {
+  handleScanView,
+  handleScanViewInteractive,
+  triageScanAlert,
+} from '../../../../src/commands/scan/handle-scan-view.mts'
+
+import type { TriageItem } from '../../../../src/commands/scan/scan-view-triage.mts'
 
 // Mock the dependencies.
 const mockFetchScan = vi.hoisted(() => vi.fn())
 const mockOutputScanView = vi.hoisted(() => vi.fn())
+const mockOutputScanViewTriage = vi.hoisted(() => vi.fn())
+const mockFetchUpdateAlertTriage = vi.hoisted(() => vi.fn())
+const mockAddSocketPolicyException = vi.hoisted(() => vi.fn())
+const mockFindSocketPolicyPathSync = vi.hoisted(() => vi.fn())
+const mockRunScanViewTui = vi.hoisted(() => vi.fn())
 
 vi.mock(import('../../../../src/commands/scan/fetch-scan.mts'), () => ({
   fetchScan: mockFetchScan,
 }))
 vi.mock(import('../../../../src/commands/scan/output-scan-view.mts'), () => ({
   outputScanView: mockOutputScanView,
+  outputScanViewTriage: mockOutputScanViewTriage,
+}))
+vi.mock(
+  import('../../../../src/commands/scan/fetch-update-alert-triage.mts'),
+  () => ({
+    fetchUpdateAlertTriage: mockFetchUpdateAlertTriage,
+  }),
+)
+vi.mock(import('../../../../src/util/policy/add-policy-exception.mts'), () => ({
+  addSocketPolicyException: mockAddSocketPolicyException,
+}))
+vi.mock(
+  import('../../../../src/util/policy/socket-policy.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    findSocketPolicyPathSync: mockFindSocketPolicyPathSync,
+  }),
+)
+vi.mock(import('../../../../src/commands/scan/scan-view-tui.mts'), () => ({
+  runScanViewTui: mockRunScanViewTui,
 }))
 
 describe('handleScanView', () => {
@@ -207,3 +239,142 @@ describe('handleScanView', () => {
     ).rejects.toThrow('Network error')
   })
 })
+
+describe('triageScanAlert', () => {
+  const item: TriageItem = {
+    action: 'error',
+    alertKey: 'alert-key-1',
+    dependencyPath: ['pkg:npm/app@1.0.0', 'pkg:npm/evil@1.0.0'],
+    purl: 'pkg:npm/evil@1.0.0',
+    severity: 'critical',
+    status: 'open',
+    title: 'Known malware',
+    type: 'malware',
+  }
+  const options = {
+    orgSlug: 'test-org',
+    policyPath: '/repo/socket.policy.yml',
+  }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('adds a policy exception for the package version', async () => {
+    mockAddSocketPolicyException.mockReturnValue({ ok: true, data: undefined })
+
+    const result = await triageScanAlert(item, 'accepted', 'Sandboxed', {
+      ...options,
+      target: 'local',
+    })
+
+    expect(mockAddSocketPolicyException).toHaveBeenCalledWith(
+      '/repo/socket.policy.yml',
+      {
+        alerts: ['malware'],
+        packages: ['pkg:npm/evil@1.0.0'],
+        paths: [],
+        reason: 'Accepted: Sandboxed',
+      },
+    )
+    expect(result).toEqual({
+      ok: true,
+      data: 'Accepted malware; added an exception to socket.policy.yml',
+    })
+    expect(mockFetchUpdateAlertTriage).not.toHaveBeenCalled()
+  })
+
+  it('returns policy write failures', async () => {
+    const failure = { ok: false, message: 'Invalid policy file', cause: 'x' }
+    mockAddSocketPolicyException.mockReturnValue(failure)
+
+    const result = await triageScanAlert(item, 'ignored', 'False positive', {
+      ...options,
+      target: 'local',
+    })
+
+    expect(result).toEqual(failure)
+  })
+
+  it('sets the org triage state of the alert key', async () => {
+    mockFetchUpdateAlertTriage.mockResolvedValue({ ok: true, data: {} })
+
+    const ignored = await triageScanAlert(item, 'ignored', 'False positive', {
+      ...options,
+      target: 'org',
+    })
+    await triageScanAlert(item, 'accepted', 'Sandboxed', {
+      ...options,
+      target: 'org',
+    })
+
+    expect(mockFetchUpdateAlertTriage).toHaveBeenNthCalledWith(1, 'test-org', [
+      { alertKey: 'alert-key-1', note: 'False positive', state: 'ignore' },
+    ])
+    expect(mockFetchUpdateAlertTriage).toHaveBeenNthCalledWith(2, 'test-org', [
+      { alertKey: 'alert-key-1', note: 'Sandboxed', state: 'monitor' },
+    ])
+    expect(ignored).toEqual({ ok: true, data: 'Ignored malware for test-org' })
+    expect(mockAddSocketPolicyException).not.toHaveBeenCalled()
+  })
+
+  it('refuses org triage of alerts without a key', async () => {
+    const result = await triageScanAlert(
+      { ...item, alertKey: '' },
+      'ignored',
+      'False positive',
+      { ...options, target: 'org' },
+    )
+
+    expect(result.ok).toBe(false)
+    expect(mockFetchUpdateAlertTriage).not.toHaveBeenCalled()
+  })
+})
+
+describe('handleScanViewInteractive', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('runs the terminal UI and prints the summary', async () => {
+    const scan = [
+      {
+        id: '1',
+        type: 'npm',
+        name: 'evil',
+        version: '1.0.0',
+        alerts: [{ key: 'k1', type: 'malware', severity: 'critical' }],
+      },
+    ]
+    mockFetchScan.mockResolvedValue({ ok: true, data: scan })
+    mockFindSocketPolicyPathSync.mockReturnValue('/repo/socket.policy.yml')
+    mockRunScanViewTui.mockImplementation(async items => items)
+
+    await handleScanViewInteractive('test-org', 'scan-123', 'local')
+
+    expect(mockRunScanViewTui).toHaveBeenCalledWith(
+      [expect.objectContaining({ alertKey: 'k1', type: 'malware' })],
+      { onTriage: expect.any(Function) },
+    )
+    expect(mockOutputScanViewTriage).toHaveBeenCalledWith(
+      [expect.objectContaining({ alertKey: 'k1' })],
+      '/repo/socket.policy.yml',
+    )
+  })
+
+  it('reports fetch failures without opening the UI', async () => {
+    const failure = { ok: false, message: 'Not found' }
+    mockFetchScan.mockResolvedValue(failure)
+
+    await handleScanViewInteractive('test-org', 'scan-123', 'org')
+
+    expect(mockOutputScanView).toHaveBeenCalledWith(
+      failure,
+      'test-org',
+      'scan-123',
+      '',
+      'text',
+    )
+    expect(mockRunScanViewTui).not.toHaveBeenCalled()
+  })
+})
@@ -0,0 +1,77 @@
+/**
+ * Unit tests for the scan watch helpers.
+ *
+ * Purpose: Tests which file events trigger a rescan and how the manifests
+ * that changed between two rounds are found.
+ *
+ * Test Coverage: - File events under .git and node_modules are ignored -
+ * Supported manifests and local lockfiles are watched - Added, changed and
+ * removed manifests are reported.
+ *
+ * Related Files: - src/commands/scan/handle-scan-watch.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getChangedScanFiles,
+  isWatchedScanFile,
+} from '../../../../src/commands/scan/handle-scan-watch.mts'
+
+const isSupportedFile = (filepath: string) =>
+  filepath === 'package.json' || filepath.endsWith('/package.json')
+
+describe('isWatchedScanFile', () => {
+  it('watches supported manifests', () => {
+    expect(isWatchedScanFile('package.json', isSupportedFile)).toBe(true)
+    expect(isWatchedScanFile('packages/a/package.json', isSupportedFile)).toBe(
+      true,
+    )
+  })
+
+  it('watches lockfiles parsed locally', () => {
+    expect(isWatchedScanFile('Cargo.lock', isSupportedFile)).toBe(true)
+  })
+
+  it('ignores other files', () => {
+    expect(isWatchedScanFile('src/index.ts', isSupportedFile)).toBe(false)
+  })
+
+  it('ignores node_modules and .git', () => {
+    expect(
+      isWatchedScanFile('node_modules/lodash/package.json', isSupportedFile),
+    ).toBe(false)
+    expect(isWatchedScanFile('.git/package.json', isSupportedFile)).toBe(false)
+  })
+
+  it('normalizes Windows separators', () => {
+    expect(isWatchedScanFile('packages\\a\\package.json', isSupportedFile)).toBe(
+      true,
+    )
+  })
+})
+
+describe('getChangedScanFiles', () => {
+  it('returns nothing when every hash matches', () => {
+    const hashes = new Map([['package.json', 'a']])
+
+    expect(getChangedScanFiles(hashes, new Map(hashes))).toEqual([])
+  })
+
+  it('reports added, changed and removed files sorted', () => {
+    const before = new Map([
+      ['package.json', 'a'],
+      ['packages/old/package.json', 'b'],
+    ])
+    const after = new Map([
+      ['package.json', 'changed'],
+      ['Cargo.lock', 'c'],
+    ])
+
+    expect(getChangedScanFiles(before, after)).toEqual([
+      'Cargo.lock',
+      'package.json',
+      'packages/old/package.json',
+    ])
+  })
+})
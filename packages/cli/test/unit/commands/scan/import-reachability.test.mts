@@ -0,0 +1,88 @@
+/**
+ * Unit tests for the import reachability of scan packages.
+ *
+ * Purpose: Tests that scan packages are tagged as imported, reachable or
+ * unreachable from the packages the project sources import, and that
+ * --only-reachable leaves out the alerts of unreachable packages.
+ *
+ * Test Coverage: - Imported packages by name and scope - Transitive
+ * dependencies - Top level ancestors when there is no dependency graph -
+ * Packages of other ecosystems - Filtering alerts.
+ *
+ * Related Files: - src/commands/scan/import-reachability.mts (implementation)
+ * - src/util/reachability/source-imports.mts - Source imports.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  filterReachableAlerts,
+  getImportReachability,
+} from '../../../../src/commands/scan/import-reachability.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+const scan = [
+  { id: 'express', type: 'npm', name: 'express', dependencies: ['qs'] },
+  { id: 'qs', type: 'npm', name: 'qs', dependencies: ['side'] },
+  { id: 'side', type: 'npm', name: 'side-channel' },
+  { id: 'types', type: 'npm', namespace: '@types', name: 'node' },
+  { id: 'unused', type: 'npm', name: 'left-pad' },
+  { id: 'requests', type: 'pypi', name: 'requests' },
+] as SocketArtifact[]
+
+describe('getImportReachability', () => {
+  it('walks the dependencies of imported packages', () => {
+    const reachability = getImportReachability(
+      scan,
+      new Set(['express', '@types/node']),
+    )
+
+    expect(Object.fromEntries(reachability)).toEqual({
+      express: 'imported',
+      qs: 'reachable',
+      requests: 'unknown',
+      side: 'reachable',
+      types: 'imported',
+      unused: 'unreachable',
+    })
+  })
+
+  it('falls back to top level ancestors without a dependency graph', () => {
+    const flatScan = [
+      { id: 'a', type: 'npm', name: 'a' },
+      { id: 'b', type: 'npm', name: 'b', topLevelAncestors: ['a'] },
+      { id: 'c', type: 'npm', name: 'c', topLevelAncestors: ['d'] },
+      { id: 'd', type: 'npm', name: 'd' },
+    ] as SocketArtifact[]
+
+    expect(
+      Object.fromEntries(getImportReachability(flatScan, new Set(['a']))),
+    ).toEqual({
+      a: 'imported',
+      b: 'reachable',
+      c: 'unreachable',
+      d: 'unreachable',
+    })
+  })
+})
+
+describe('filterReachableAlerts', () => {
+  it('empties the alerts of unreachable packages only', () => {
+    const alerts = [{ type: 'envVars' }] as SocketArtifact['alerts']
+    const alertedScan = scan.map(artifact => ({ ...artifact, alerts }))
+    const reachability = getImportReachability(alertedScan, new Set(['qs']))
+
+    const filtered = filterReachableAlerts(alertedScan, reachability)
+
+    expect(filtered.map(({ alerts, id }) => [id, alerts?.length])).toEqual([
+      ['express', 0],
+      ['qs', 1],
+      ['side', 1],
+      ['types', 0],
+      ['unused', 0],
+      ['requests', 1],
+    ])
+    expect(filtered[1]).toBe(alertedScan[1])
+  })
+})
@@ -317,4 +317,84 @@ describe('outputDiffScan', () => {
       expect(mockLogger.log).toHaveBeenCalled()
     })
   })
+
+  describe('alert policy gate', () => {
+    const blockingAlert = {
+      action: 'error',
+      purl: 'pkg:npm/colors@1.4.1',
+      severity: 'high',
+      type: 'protestware',
+    }
+
+    it('exits with code 3 when new alerts violate the policy', async () => {
+      const result = createSuccessResult(createMockDiffData())
+
+      await outputDiffScan(result as unknown, {
+        alertDiff: createSuccessResult({
+          added: [blockingAlert],
+          blocking: [blockingAlert],
+          removed: [],
+        }),
+        depth: 5,
+        file: '',
+        outputKind: 'text',
+      })
+
+      expect(process.exitCode).toBe(3)
+      expect(mockLogger.fail).toHaveBeenCalledWith(
+        expect.stringContaining('violating the security policy'),
+      )
+    })
+
+    it('keeps exit code 0 for non-blocking alerts', async () => {
+      const result = createSuccessResult(createMockDiffData())
+      const warnAlert = { ...blockingAlert, action: 'warn' }
+
+      await outputDiffScan(result as unknown, {
+        alertDiff: createSuccessResult({
+          added: [warnAlert],
+          blocking: [],
+          removed: [],
+        }),
+        depth: 5,
+        file: '',
+        outputKind: 'text',
+      })
+
+      expect(process.exitCode).toBeUndefined()
+      expect(mockLogger.success).toHaveBeenCalled()
+    })
+
+    it('includes the alert diff in JSON output', async () => {
+      const result = createSuccessResult(createMockDiffData())
+      const alertDiff = { added: [], blocking: [], removed: [blockingAlert] }
+
+      await outputDiffScan(result as unknown, {
+        alertDiff: createSuccessResult(alertDiff),
+        depth: 5,
+        file: '',
+        outputKind: 'json',
+      })
+
+      expect(mockSerializeResultJson).toHaveBeenCalledWith(
+        expect.objectContaining({
+          data: expect.objectContaining({ alerts: alertDiff }),
+        }),
+      )
+    })
+
+    it('fails when the alert comparison could not be computed', async () => {
+      const result = createSuccessResult(createMockDiffData())
+
+      await outputDiffScan(result as unknown, {
+        alertDiff: createErrorResult('Policy unavailable', { code: 3 }),
+        depth: 5,
+        file: '',
+        outputKind: 'text',
+      })
+
+      expect(process.exitCode).toBe(3)
+      expect(mockLogger.fail).toHaveBeenCalled()
+    })
+  })
 })
@@ -0,0 +1,131 @@
+/**
+ * Unit tests for scan check output formatting.
+ *
+ * Purpose: Tests how `socket scan check` reports Rego policy results.
+ *
+ * Test Coverage: - JSON output with the passed flag - Text output for deny
+ * and warn entries - Markdown output - JUnit output - Exit codes.
+ *
+ * Related Files: - src/commands/scan/output-scan-check.mts (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockLogger = vi.hoisted(() => ({
+  log: vi.fn(),
+  error: vi.fn(),
+  warn: vi.fn(),
+  fail: vi.fn(),
+  success: vi.fn(),
+  info: vi.fn(),
+}))
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+vi.mock(import('../../../../src/util/error/fail-msg-with-badge.mts'), () => ({
+  failMsgWithBadge: (msg: string, cause?: string | undefined) =>
+    cause ? `${msg}: ${cause}` : msg,
+}))
+
+import { outputScanCheck } from '../../../../src/commands/scan/output-scan-check.mts'
+
+const DENIED = {
+  ok: true as const,
+  data: {
+    deny: [{ msg: 'pkg:npm/a@1.0.0 is GPL licensed' }],
+    warn: [{ msg: 'pkg:npm/b@2.0.0 has install scripts' }],
+  },
+}
+
+describe('outputScanCheck', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  it('outputs JSON with the passed flag', async () => {
+    await outputScanCheck(DENIED, 'json')
+
+    const output = JSON.parse(mockLogger.log.mock.calls[0]![0] as string)
+    expect(output.ok).toBe(true)
+    expect(output.data.passed).toBe(false)
+    expect(output.data.deny).toHaveLength(1)
+    expect(process.exitCode).toBe(3)
+  })
+
+  it('prints violations and fails in text mode', async () => {
+    await outputScanCheck(DENIED, 'text')
+
+    expect(mockLogger.warn).toHaveBeenCalledWith(
+      'pkg:npm/b@2.0.0 has install scripts',
+    )
+    expect(mockLogger.error).toHaveBeenCalledWith(
+      'pkg:npm/a@1.0.0 is GPL licensed',
+    )
+    expect(mockLogger.fail).toHaveBeenCalledWith(
+      'The scan failed the policy with 1 violation',
+    )
+    expect(process.exitCode).toBe(3)
+  })
+
+  it('succeeds when nothing is denied', async () => {
+    await outputScanCheck({ ok: true, data: { deny: [], warn: [] } }, 'text')
+
+    expect(mockLogger.success).toHaveBeenCalledWith(
+      'The scan passed the policy',
+    )
+    expect(process.exitCode).toBeUndefined()
+  })
+
+  it('reports evaluation errors', async () => {
+    await outputScanCheck(
+      {
+        ok: false,
+        message: 'Open Policy Agent not found',
+        cause: 'install opa',
+      },
+      'text',
+    )
+
+    expect(mockLogger.fail).toHaveBeenCalledWith(
+      'Open Policy Agent not found: install opa',
+    )
+    expect(process.exitCode).toBe(1)
+  })
+
+  it('lists violations in markdown', async () => {
+    await outputScanCheck(DENIED, 'markdown')
+
+    const output = mockLogger.log.mock.calls.map(call => call[0]).join('\n')
+    expect(output).toContain('Result: failed')
+    expect(output).toContain('- pkg:npm/a@1.0.0 is GPL licensed')
+  })
+
+  it('outputs JUnit XML with --format junit', async () => {
+    await outputScanCheck(DENIED, 'text', {
+      format: 'junit',
+      regoPackage: 'acme.policy',
+    })
+
+    const output = mockLogger.log.mock.calls[0]![0] as string
+    expect(output).toContain('<testsuite name="Socket policy check" tests="2"')
+    expect(output).toContain('failures="1"')
+    expect(output).toContain('classname="acme.policy"')
+    expect(output).toContain(
+      '<failure message="pkg:npm/a@1.0.0 is GPL licensed" type="deny">',
+    )
+    expect(process.exitCode).toBe(3)
+  })
+
+  it('reports errors as text with --format junit', async () => {
+    await outputScanCheck(
+      { ok: false, message: 'Open Policy Agent not found' },
+      'text',
+      { format: 'junit' },
+    )
+
+    expect(mockLogger.log).not.toHaveBeenCalled()
+    expect(mockLogger.fail).toHaveBeenCalledWith('Open Policy Agent not found')
+  })
+})
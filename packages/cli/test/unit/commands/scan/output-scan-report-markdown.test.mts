@@ -8,12 +8,13 @@
  * Testing Approach: Uses result helpers and fixtures to create test data.
  * Validates formatted markdown output strings.
  *
- * Related Files: - src/commands/outputScanReport.mts (implementation)
+ * Related Files: - src/commands/scan/output-scan-report-markdown.mts
+ * (implementation)
  */
 
 import { describe, expect, it } from 'vitest'
 
-import { toMarkdownReport } from '../../../../src/commands/scan/output-scan-report.mts'
+import { toMarkdownReport } from '../../../../src/commands/scan/output-scan-report-markdown.mts'
 import { SOCKET_WEBSITE_URL } from '../../../../src/constants/socket.mts'
 
 import type { ScanReport } from '../../../../src/commands/scan/generate-report.mts'
@@ -109,6 +110,97 @@ describe('output-scan-report', () => {
         "
       `)
     })
+
+    it('should add an Alert ID column when the alerts have keys', () => {
+      const report: ScanReport = {
+        ...getUnhealthyReport(),
+        alerts: new Map([
+          [
+            'npm',
+            new Map([
+              [
+                'tslib',
+                {
+                  alertId: 'QmAlert1',
+                  manifest: ['package-lock.json'],
+                  policy: 'error' as const,
+                  type: 'envVars',
+                  url: `${SOCKET_WEBSITE_URL}/npm/package/tslib/1.14.1`,
+                },
+              ],
+            ]),
+          ],
+        ]),
+      }
+      const result = toMarkdownReport(report)
+      expect(result).toContain('| Policy | Alert ID | Alert Type |')
+      expect(result).toContain('| error  | QmAlert1 | envVars    |')
+      expect(toMarkdownReport(getUnhealthyReport())).not.toContain('Alert ID')
+    })
+
+    it('should list secret alerts with their evidence', () => {
+      const report: ScanReport = {
+        ...getUnhealthyReport(),
+        alerts: new Map([
+          [
+            'npm',
+            new Map([
+              [
+                'leaky-sdk',
+                {
+                  category: 'secrets',
+                  evidence: {
+                    file: 'package/lib/client.js',
+                    line: 2,
+                    snippet: "key: 'AKIA****' || fallback",
+                  },
+                  manifest: ['package-lock.json'],
+                  policy: 'error' as const,
+                  type: 'exposedSecret',
+                  url: `${SOCKET_WEBSITE_URL}/npm/package/leaky-sdk/2.0.1`,
+                },
+              ],
+            ]),
+          ],
+        ]),
+      }
+      const result = toMarkdownReport(report)
+      expect(result).toContain('## Secrets in dependencies')
+      expect(result).toContain(
+        '1 alert for credentials or keys embedded in the files of dependencies.',
+      )
+      expect(result).toContain('| Package   | Introduced by | File')
+      expect(result).toContain(
+        "| package/lib/client.js | 2    | key: 'AKIA****' \\|\\| fallback |",
+      )
+    })
+
+    it('should point secret alerts without evidence to --show-evidence', () => {
+      const report: ScanReport = {
+        ...getUnhealthyReport(),
+        alerts: new Map([
+          [
+            'npm',
+            new Map([
+              [
+                'leaky-sdk',
+                {
+                  category: 'secrets',
+                  manifest: ['package-lock.json'],
+                  policy: 'error' as const,
+                  type: 'exposedSecret',
+                  url: `${SOCKET_WEBSITE_URL}/npm/package/leaky-sdk/2.0.1`,
+                },
+              ],
+            ]),
+          ],
+        ]),
+      }
+      const result = toMarkdownReport(report)
+      expect(result).toContain('Pass --show-evidence')
+      expect(result).toContain('| leaky-sdk | <unknown>     | exposedSecret |')
+      expect(toMarkdownReport(getUnhealthyReport())).not.toContain('Secrets')
+    })
   })
 })
 
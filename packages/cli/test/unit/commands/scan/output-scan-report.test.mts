@@ -5,8 +5,8 @@
  * alert display, score formatting, and recommendation presentation.
  *
  * Test Coverage: - Successful operation output formatting - Error message
- * formatting - Multiple output formats (text, json, markdown) - Edge case
- * handling.
+ * formatting - Multiple output formats (text, json, markdown) - Output
+ * templates - Edge case handling.
  *
  * Testing Approach: Uses result helpers and fixtures to create test data.
  * Validates formatted output strings across different output modes.
@@ -14,9 +14,14 @@
  * Related Files: - src/commands/outputScanReport.mts (implementation)
  */
 
+import { mkdtempSync, rmSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 import { outputScanReport } from '../../../../src/commands/scan/output-scan-report.mts'
+import { getScanWithEnvVars } from '../../../helpers/generate-report-test-helpers.mts'
 
 import type * as GenerateReportModule from '../../../../src/commands/scan/generate-report.mts'
 
@@ -141,7 +146,7 @@ describe('output-scan-report', () => {
       expect(mockLogger.dir).toHaveBeenCalled()
     })
 
-    it('should set exit code 1 for unhealthy report', async () => {
+    it('should set exit code 3 for unhealthy report', async () => {
       const successResult = {
         ok: true as const,
         data: {
@@ -163,7 +168,7 @@ describe('output-scan-report', () => {
 
       await outputScanReport(successResult, baseConfig)
 
-      expect(process.exitCode).toBe(1)
+      expect(process.exitCode).toBe(3)
     })
 
     it('should handle report generation failure', async () => {
@@ -400,4 +405,84 @@ describe('output-scan-report', () => {
       )
     })
   })
+
+  describe('outputScanReport - output template', () => {
+    const baseConfig = {
+      filepath: '',
+      fold: 'none' as const,
+      includeLicensePolicy: false,
+      orgSlug: 'test-org',
+      outputKind: 'text' as const,
+      reportLevel: 'warn' as const,
+      scanId: 'scan-123',
+      short: false,
+    }
+
+    function getResult(action: string) {
+      return {
+        ok: true as const,
+        data: {
+          scan: getScanWithEnvVars(),
+          securityPolicy: {
+            securityPolicyRules: { envVars: { action } },
+            securityPolicyDefault: 'medium',
+          },
+        },
+      } as Parameters<typeof outputScanReport>[0]
+    }
+
+    function withTemplate(
+      content: string,
+      fn: (templatePath: string) => Promise<void>,
+    ): Promise<void> {
+      const tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-template-'))
+      const templatePath = path.join(tmpDir, 'report.hbs')
+      writeFileSync(templatePath, content)
+      return fn(templatePath).finally(() =>
+        rmSync(tmpDir, { force: true, recursive: true }),
+      )
+    }
+
+    it('should render the report with the template', async () => {
+      await withTemplate(
+        '{{orgSlug}} {{#if healthy}}OK{{else}}FAIL{{/if}} {{counts.total}}',
+        async outputTemplate => {
+          await outputScanReport(getResult('error'), {
+            ...baseConfig,
+            outputTemplate,
+          })
+        },
+      )
+
+      expect(mockGenerateReport).not.toHaveBeenCalled()
+      expect(mockLogger.log).toHaveBeenCalledWith('test-org FAIL 2')
+      expect(process.exitCode).toBe(3)
+    })
+
+    it('should write the rendered template to the output path', async () => {
+      await withTemplate('{{scanId}}', async outputTemplate => {
+        await outputScanReport(getResult('warn'), {
+          ...baseConfig,
+          filepath: '/tmp/report.txt',
+          outputTemplate,
+        })
+      })
+
+      expect(mockWriteFile).toHaveBeenCalledWith('/tmp/report.txt', 'scan-123')
+      expect(process.exitCode).toBeUndefined()
+    })
+
+    it('should fail for an invalid template', async () => {
+      await withTemplate('{{#each alerts}}', async outputTemplate => {
+        await outputScanReport(getResult('warn'), {
+          ...baseConfig,
+          outputTemplate,
+        })
+      })
+
+      expect(mockLogger.fail).toHaveBeenCalled()
+      expect(mockLogger.log).not.toHaveBeenCalled()
+      expect(process.exitCode).toBe(1)
+    })
+  })
 })
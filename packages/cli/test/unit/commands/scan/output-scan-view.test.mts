@@ -4,7 +4,8 @@
  * Purpose: Tests the output formatting for scan view results.
  *
  * Test Coverage: - outputScanView function - JSON output format - Markdown/Text
- * output format - File writing - Error handling.
+ * output format - File writing - Error handling - outputScanViewTriage
+ * summary.
  *
  * Related Files: - src/commands/scan/output-scan-view.mts (implementation)
  */
@@ -51,8 +52,12 @@ vi.mock(import('../../../../src/util/terminal/link.mts'), () => ({
   fileLink: (path: string) => path,
 }))
 
-import { outputScanView } from '../../../../src/commands/scan/output-scan-view.mts'
+import {
+  outputScanView,
+  outputScanViewTriage,
+} from '../../../../src/commands/scan/output-scan-view.mts'
 
+import type { TriageItem } from '../../../../src/commands/scan/scan-view-triage.mts'
 import type { CResult } from '../../../../src/types.mts'
 import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
 
@@ -262,4 +267,47 @@ describe('output-scan-view', () => {
       })
     })
   })
+
+  describe('outputScanViewTriage', () => {
+    const item = (status: TriageItem['status']): TriageItem => ({
+      action: '',
+      alertKey: 'k1',
+      dependencyPath: ['pkg:npm/evil@1.0.0'],
+      purl: 'pkg:npm/evil@1.0.0',
+      severity: 'critical',
+      status,
+      title: 'Known malware',
+      type: 'malware',
+    })
+
+    beforeEach(() => {
+      vi.clearAllMocks()
+    })
+
+    it('summarizes local decisions with the policy file', () => {
+      outputScanViewTriage(
+        [item('ignored'), item('accepted'), item('open')],
+        '/repo/socket.policy.yml',
+      )
+
+      expect(mockLogger.success).toHaveBeenCalledWith(
+        'Ignored 1 and accepted 1 of 3 alerts; saved to exceptions in /repo/socket.policy.yml',
+      )
+    })
+
+    it('summarizes org decisions', () => {
+      outputScanViewTriage([item('ignored')], '')
+
+      expect(mockLogger.success).toHaveBeenCalledWith(
+        'Ignored 1 and accepted 0 of 1 alerts; saved to the org triage settings',
+      )
+    })
+
+    it('says when nothing was triaged', () => {
+      outputScanViewTriage([item('open')], '/repo/socket.policy.yml')
+
+      expect(mockLogger.info).toHaveBeenCalledWith('No alerts were triaged')
+      expect(mockLogger.success).not.toHaveBeenCalled()
+    })
+  })
 })
@@ -0,0 +1,172 @@
+/**
+ * Unit tests for scan watch output formatting.
+ *
+ * Purpose: Tests the compact delta printed after each scan watch round.
+ *
+ * Test Coverage: - Initial and follow-up summaries - Blocking alerts turn the
+ * summary into a failure - One line of JSON per round - Error handling.
+ *
+ * Related Files: - src/commands/scan/output-scan-watch.mts (implementation)
+ * - src/commands/scan/handle-scan-watch.mts (rounds)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+// Mock logger.
+const mockLogger = vi.hoisted(() => ({
+  log: vi.fn(),
+  error: vi.fn(),
+  warn: vi.fn(),
+  fail: vi.fn(),
+  success: vi.fn(),
+  info: vi.fn(),
+}))
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+vi.mock(import('../../../../src/util/error/fail-msg-with-badge.mts'), () => ({
+  failMsgWithBadge: (msg: string, cause?: string | undefined) =>
+    cause ? `${msg}: ${cause}` : msg,
+}))
+
+import {
+  formatScanWatchSummary,
+  outputScanWatch,
+} from '../../../../src/commands/scan/output-scan-watch.mts'
+
+import type { ScanDiffAlert } from '../../../../src/commands/scan/diff-scan-alerts.mts'
+import type { ScanWatchEvent } from '../../../../src/commands/scan/handle-scan-watch.mts'
+
+const now = new Date(2026, 0, 1, 12, 30, 5)
+
+const malware: ScanDiffAlert = {
+  action: 'error',
+  purl: 'pkg:npm/evil@1.0.0',
+  severity: 'critical',
+  type: 'malware',
+}
+
+const unmaintained: ScanDiffAlert = {
+  action: 'warn',
+  purl: 'pkg:npm/old@1.0.0',
+  severity: 'low',
+  type: 'unmaintained',
+}
+
+function makeEvent(overrides: Partial<ScanWatchEvent>): ScanWatchEvent {
+  return {
+    alerts: { added: [], blocking: [], removed: [] },
+    changedFiles: ['package.json'],
+    fileCount: 2,
+    initial: false,
+    scanId: 'scan-1',
+    totalAlerts: 3,
+    totalBlocking: 1,
+    ...overrides,
+  }
+}
+
+describe('formatScanWatchSummary', () => {
+  it('summarizes the initial scan', () => {
+    expect(
+      formatScanWatchSummary(
+        makeEvent({ changedFiles: [], initial: true }),
+        now,
+      ),
+    ).toBe('[12:30:05] Watching 2 files: 3 alerts (1 blocking)')
+  })
+
+  it('summarizes new and resolved alerts', () => {
+    expect(
+      formatScanWatchSummary(
+        makeEvent({
+          alerts: {
+            added: [malware],
+            blocking: [malware],
+            removed: [unmaintained],
+          },
+        }),
+        now,
+      ),
+    ).toBe('[12:30:05] package.json: +1 alert (1 blocking), -1 resolved')
+  })
+
+  it('says when the alerts did not change', () => {
+    expect(formatScanWatchSummary(makeEvent({}), now)).toBe(
+      '[12:30:05] package.json: no alert changes',
+    )
+  })
+})
+
+describe('outputScanWatch', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  it('fails the summary and marks blocking alerts', () => {
+    outputScanWatch(
+      {
+        ok: true,
+        data: makeEvent({
+          alerts: {
+            added: [malware, unmaintained],
+            blocking: [malware],
+            removed: [],
+          },
+        }),
+      },
+      'text',
+    )
+
+    expect(mockLogger.fail).toHaveBeenCalledWith(
+      expect.stringContaining('+2 alerts (1 blocking)'),
+    )
+    expect(mockLogger.log).toHaveBeenCalledWith(
+      '  ! malware in pkg:npm/evil@1.0.0 [critical, policy: error]',
+    )
+    expect(mockLogger.log).toHaveBeenCalledWith(
+      '  + unmaintained in pkg:npm/old@1.0.0 [low, policy: warn]',
+    )
+    expect(process.exitCode).toBeUndefined()
+  })
+
+  it('lists resolved alerts', () => {
+    outputScanWatch(
+      {
+        ok: true,
+        data: makeEvent({
+          alerts: { added: [], blocking: [], removed: [unmaintained] },
+        }),
+      },
+      'text',
+    )
+
+    expect(mockLogger.success).toHaveBeenCalled()
+    expect(mockLogger.log).toHaveBeenCalledWith(
+      '  - unmaintained in pkg:npm/old@1.0.0 [low, policy: warn]',
+    )
+  })
+
+  it('prints each round as one line of JSON', () => {
+    const event = makeEvent({})
+
+    outputScanWatch({ ok: true, data: event }, 'json')
+
+    expect(mockLogger.log).toHaveBeenCalledTimes(1)
+    const line = mockLogger.log.mock.calls[0]![0] as string
+    expect(line).not.toContain('\n')
+    expect(JSON.parse(line)).toEqual({ ok: true, data: event })
+  })
+
+  it('reports errors and sets the exit code', () => {
+    outputScanWatch(
+      { ok: false, message: 'Scan failed', cause: 'Server error' },
+      'text',
+    )
+
+    expect(mockLogger.fail).toHaveBeenCalledWith('Scan failed: Server error')
+    expect(process.exitCode).toBe(1)
+  })
+})
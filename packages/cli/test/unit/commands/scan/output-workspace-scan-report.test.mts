@@ -0,0 +1,195 @@
+/**
+ * Unit tests for per-workspace scan report output.
+ *
+ * Purpose: Tests that every monorepo workspace gets its own report and
+ * pass/fail result.
+ *
+ * Test Coverage: - Text summary per workspace - Exit code when any workspace
+ * fails - JSON and markdown formats - Empty selection - Error handling.
+ *
+ * Related Files: - src/commands/scan/output-workspace-scan-report.mts
+ * (implementation) - src/commands/scan/scan-workspaces.mts (reports)
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+// Mock logger.
+const mockLogger = vi.hoisted(() => ({
+  log: vi.fn(),
+  error: vi.fn(),
+  warn: vi.fn(),
+  fail: vi.fn(),
+  success: vi.fn(),
+  info: vi.fn(),
+}))
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+vi.mock(import('../../../../src/util/error/fail-msg-with-badge.mts'), () => ({
+  failMsgWithBadge: (msg: string, cause?: string | undefined) =>
+    cause ? `${msg}: ${cause}` : msg,
+}))
+
+vi.mock(import('../../../../src/util/socket/url.mts'), () => ({
+  getSocketDevPackageOverviewUrlFromPurl: (art: { name: string }) =>
+    `https://socket.dev/pkg/${art.name}`,
+}))
+
+import { outputWorkspaceScanReport } from '../../../../src/commands/scan/output-workspace-scan-report.mts'
+import {
+  FOLD_SETTING_NONE,
+  OUTPUT_JSON,
+  OUTPUT_MARKDOWN,
+  OUTPUT_TEXT,
+} from '../../../../src/constants/cli.mts'
+import { REPORT_LEVEL_ERROR } from '../../../../src/constants/reporting.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+import type { WorkspaceSelection } from '../../../../src/commands/scan/scan-workspaces.mts'
+import type { OutputKind } from '../../../../src/types.mts'
+import type { MonorepoWorkspace } from '../../../../src/util/fs/workspaces.mts'
+import type { SocketSdkSuccessResult } from '@socketsecurity/sdk-stable'
+
+type SecurityPolicyData = SocketSdkSuccessResult<'getOrgSecurityPolicy'>['data']
+
+const root: MonorepoWorkspace = { name: 'acme', path: '.' }
+const api: MonorepoWorkspace = { name: '@acme/api', path: 'packages/api' }
+const web: MonorepoWorkspace = { name: '@acme/web', path: 'apps/web' }
+
+const selection: WorkspaceSelection = {
+  all: [root, web, api],
+  selected: [web, api],
+}
+
+const scan = [
+  artifact('evil', {
+    alerts: [{ type: 'malware', file: 'index.js', start: 0, end: 10 }],
+    manifestFiles: [{ file: 'packages/api/package.json' }],
+  }),
+  artifact('fine', {
+    version: '2.0.0',
+    alerts: [],
+    manifestFiles: [{ file: 'apps/web/package.json' }],
+  }),
+]
+
+const securityPolicy = {
+  securityPolicyRules: { malware: { action: 'error' } },
+} as unknown as SecurityPolicyData
+
+function config(outputKind: OutputKind, filepath = '') {
+  return {
+    filepath,
+    fold: FOLD_SETTING_NONE,
+    orgSlug: 'acme-org',
+    outputKind,
+    reportLevel: REPORT_LEVEL_ERROR,
+    scanId: 'scan-1',
+    short: false,
+  } as const
+}
+
+describe('outputWorkspaceScanReport', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  afterEach(() => {
+    process.exitCode = undefined
+  })
+
+  it('reports every selected workspace on its own', async () => {
+    await outputWorkspaceScanReport(
+      { ok: true, data: { scan, securityPolicy } },
+      selection,
+      config(OUTPUT_TEXT),
+    )
+
+    expect(mockLogger.success).toHaveBeenCalledWith(
+      '@acme/web (apps/web): OK, 0 alerts',
+    )
+    expect(mockLogger.fail).toHaveBeenCalledWith(
+      '@acme/api (packages/api): ERR, 1 alert',
+    )
+    expect(mockLogger.log).toHaveBeenCalledWith(
+      '  error: malware in evil@1.0.0',
+    )
+    expect(mockLogger.fail).toHaveBeenLastCalledWith(
+      '1 of 2 workspaces violate the policies',
+    )
+    expect(process.exitCode).toBe(3)
+  })
+
+  it('passes when every selected workspace is healthy', async () => {
+    await outputWorkspaceScanReport(
+      { ok: true, data: { scan, securityPolicy } },
+      { ...selection, selected: [web] },
+      config(OUTPUT_TEXT),
+    )
+
+    expect(mockLogger.success).toHaveBeenLastCalledWith(
+      'All 1 workspace pass the policies',
+    )
+    expect(process.exitCode).toBeUndefined()
+  })
+
+  it('says so when no workspace is selected', async () => {
+    await outputWorkspaceScanReport(
+      { ok: true, data: { scan, securityPolicy } },
+      { ...selection, selected: [] },
+      config(OUTPUT_TEXT),
+    )
+
+    expect(mockLogger.info).toHaveBeenCalledWith(
+      'No workspace selected, nothing to report',
+    )
+    expect(process.exitCode).toBeUndefined()
+  })
+
+  it('outputs JSON with a result per workspace', async () => {
+    await outputWorkspaceScanReport(
+      { ok: true, data: { scan, securityPolicy } },
+      selection,
+      config(OUTPUT_JSON),
+    )
+
+    const json = JSON.parse(mockLogger.log.mock.calls[0]![0])
+    expect(json.ok).toBe(true)
+    expect(json.data.healthy).toBe(false)
+    expect(
+      json.data.workspaces.map((w: { name: string; healthy: boolean }) => [
+        w.name,
+        w.healthy,
+      ]),
+    ).toEqual([
+      ['@acme/web', true],
+      ['@acme/api', false],
+    ])
+  })
+
+  it('outputs a markdown table of workspaces', async () => {
+    await outputWorkspaceScanReport(
+      { ok: true, data: { scan, securityPolicy } },
+      selection,
+      config(OUTPUT_MARKDOWN),
+    )
+
+    const markdown = mockLogger.log.mock.calls[0]![0] as string
+    expect(markdown).toContain('Scan Policy Report per Workspace')
+    expect(markdown).toMatch(/@acme\/api.*FAIL/)
+    expect(markdown).toMatch(/@acme\/web.*PASS/)
+  })
+
+  it('outputs errors', async () => {
+    await outputWorkspaceScanReport(
+      { ok: false, message: 'No matching workspace', cause: 'nope', code: 2 },
+      selection,
+      config(OUTPUT_TEXT),
+    )
+
+    expect(mockLogger.fail).toHaveBeenCalledWith('No matching workspace: nope')
+    expect(process.exitCode).toBe(2)
+  })
+})
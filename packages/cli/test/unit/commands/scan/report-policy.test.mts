@@ -0,0 +1,159 @@
+/**
+ * Unit tests for the policy side of scan reports.
+ *
+ * Purpose: Tests the action an alert gets from the org policy, a local
+ * socket.policy.yml and a socket.baseline.json, and how that action decides
+ * the listing and health of the report.
+ *
+ * Test Coverage: - isFailOnViolation thresholds - isReportedAction report
+ * levels - getAlertAction precedence and baselines - getLocalPolicyAlerts
+ * license and distrust alerts.
+ *
+ * Related Files: - src/commands/scan/report-policy.mts (implementation) -
+ * src/commands/scan/generate-report.mts (consumer)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getAlertAction,
+  getLocalPolicyAlerts,
+  isFailOnViolation,
+  isReportedAction,
+} from '../../../../src/commands/scan/report-policy.mts'
+import { createEmptySocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+
+import type {
+  ArtifactAlert,
+  ReportPolicy,
+} from '../../../../src/commands/scan/report-policy.mts'
+import type { REPORT_LEVEL } from '../../../../src/commands/scan/types.mts'
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+const artifact = {
+  type: 'npm',
+  name: 'test-pkg',
+  version: '1.0.0',
+  license: 'GPL-3.0',
+} as SocketArtifact
+
+const alert = { type: 'badAlert', severity: 'high' } as ArtifactAlert
+
+const baseline = {
+  keys: new Set([
+    'pkg:npm/test-pkg@1.0.0 badAlert',
+    'pkg:npm/test-pkg@1.0.0 licensePolicyViolation',
+  ]),
+  path: '/repo/socket.baseline.json',
+}
+
+describe('report-policy', () => {
+  describe('isFailOnViolation', () => {
+    it('uses the error action without a threshold', () => {
+      expect(isFailOnViolation(undefined, 'error', 'low')).toBe(true)
+      expect(isFailOnViolation(undefined, 'warn', 'critical')).toBe(false)
+    })
+
+    it('compares severities with a threshold', () => {
+      expect(isFailOnViolation('high', 'warn', 'critical')).toBe(true)
+      expect(isFailOnViolation('high', 'error', 'middle')).toBe(false)
+      expect(isFailOnViolation('critical', 'error', 'high')).toBe(false)
+    })
+
+    it('never counts ignored alerts', () => {
+      expect(isFailOnViolation('any', 'ignore', 'critical')).toBe(false)
+      expect(isFailOnViolation('high', 'ignore', 'critical')).toBe(false)
+    })
+  })
+
+  describe('isReportedAction', () => {
+    it('always lists errors', () => {
+      expect(isReportedAction('error', 'error')).toBe(true)
+      expect(isReportedAction('error', 'defer')).toBe(true)
+    })
+
+    it('lists the actions at or above the report level', () => {
+      expect(isReportedAction('warn', 'error')).toBe(false)
+      expect(isReportedAction('warn', 'warn')).toBe(true)
+      expect(isReportedAction('monitor', 'warn')).toBe(false)
+      expect(isReportedAction('monitor', 'monitor')).toBe(true)
+      expect(isReportedAction('ignore', 'monitor')).toBe(false)
+      expect(isReportedAction('ignore', 'ignore')).toBe(true)
+    })
+
+    it('lists deferred alerts only at the defer level', () => {
+      expect(isReportedAction('defer', 'ignore')).toBe(false)
+      expect(isReportedAction('defer', 'defer')).toBe(true)
+    })
+
+    it('never lists unknown actions', () => {
+      expect(isReportedAction('unknown' as REPORT_LEVEL, 'defer')).toBe(false)
+    })
+  })
+
+  describe('getAlertAction', () => {
+    const securityRules = {
+      badAlert: { action: 'warn' },
+    } as unknown as ReportPolicy['securityRules']
+
+    it('uses the org policy action', () => {
+      expect(
+        getAlertAction({ securityRules }, artifact, alert, undefined),
+      ).toBe('warn')
+    })
+
+    it('prefers a matching local rule', () => {
+      const localPolicy = {
+        ...createEmptySocketPolicy(),
+        block: { alerts: ['badAlert'] },
+      }
+
+      expect(
+        getAlertAction(
+          { localPolicy, securityRules },
+          artifact,
+          alert,
+          undefined,
+        ),
+      ).toBe('error')
+    })
+
+    it('ignores the warn alerts of trusted packages', () => {
+      expect(
+        getAlertAction({ securityRules }, artifact, alert, 'trusted'),
+      ).toBe('ignore')
+    })
+
+    it('returns undefined for baselined alerts', () => {
+      expect(
+        getAlertAction({ baseline, securityRules }, artifact, alert, undefined),
+      ).toBeUndefined()
+    })
+  })
+
+  describe('getLocalPolicyAlerts', () => {
+    const localPolicy = {
+      ...createEmptySocketPolicy(),
+      licenses: { allow: ['MIT'], deny: [] },
+    }
+
+    it('raises license and distrust alerts', () => {
+      expect(
+        getLocalPolicyAlerts({ localPolicy }, artifact, 'distrusted'),
+      ).toEqual([
+        { type: 'licensePolicyViolation', severity: 'high' },
+        { type: 'distrustedPackage', severity: 'critical' },
+      ])
+    })
+
+    it('leaves out baselined alerts', () => {
+      expect(
+        getLocalPolicyAlerts({ baseline, localPolicy }, artifact, 'distrusted'),
+      ).toEqual([{ type: 'distrustedPackage', severity: 'critical' }])
+    })
+
+    it('raises nothing without a local policy', () => {
+      expect(getLocalPolicyAlerts({}, artifact, undefined)).toEqual([])
+    })
+  })
+})
@@ -0,0 +1,211 @@
+/**
+ * Unit tests for the worker pool of `socket scan create --all-repos`.
+ *
+ * Tests ranking the repositories by new critical alerts, scanning local
+ * clones, skipping repositories without manifests, and recording failures
+ * without stopping the other repositories.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockFetchSupportedScanFileNames = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/scan/fetch-supported-scan-file-names.mts'),
+  () => ({
+    fetchSupportedScanFileNames: mockFetchSupportedScanFileNames,
+  }),
+)
+
+const mockGetScanNotifySummary = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/scan/scan-notify.mts'), () => ({
+  getScanNotifySummary: mockGetScanNotifySummary,
+}))
+
+const mockFetchCreateOrgFullScan = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/scan/fetch-create-org-full-scan.mts'),
+  () => ({
+    fetchCreateOrgFullScan: mockFetchCreateOrgFullScan,
+  }),
+)
+
+const mockGetPackageFilesForScan = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/fs/path-resolve.mts'), () => ({
+  getPackageFilesForScan: mockGetPackageFilesForScan,
+}))
+
+vi.mock(import('../../../../src/util/config.mts'), async importOriginal => {
+  const actual = await importOriginal()
+  return {
+    ...actual,
+    findSocketYmlSync: () => ({ ok: true, data: undefined }),
+  }
+})
+
+vi.mock(
+  import('../../../../src/util/git/operations.mts'),
+  async importOriginal => {
+    const actual = await importOriginal()
+    return {
+      ...actual,
+      gitResolveCommit: async () => 'abc123',
+    }
+  },
+)
+
+import {
+  rankRepoScanResults,
+  scanAllRepos,
+} from '../../../../src/commands/scan/scan-all-repos.mts'
+
+import type { DiscoveredRepo } from '../../../../src/commands/scan/discover-repos.mts'
+
+function localRepo(name: string): DiscoveredRepo {
+  return {
+    branch: 'main',
+    dir: `/clones/${name}`,
+    isDefaultBranch: false,
+    name,
+  }
+}
+
+describe('rankRepoScanResults', () => {
+  it('ranks by new criticals, then name, with failures last', () => {
+    const ranked = rankRepoScanResults([
+      { branch: 'main', repo: 'broken', status: 'failed' },
+      { branch: 'main', newCriticals: 0, repo: 'alpha', status: 'scanned' },
+      { branch: 'main', newCriticals: 3, repo: 'zeta', status: 'scanned' },
+      { branch: 'main', repo: 'empty', status: 'skipped' },
+      { branch: 'main', newCriticals: 3, repo: 'beta', status: 'scanned' },
+    ])
+
+    expect(ranked.map(repo => repo.repo)).toEqual([
+      'beta',
+      'zeta',
+      'alpha',
+      'empty',
+      'broken',
+    ])
+  })
+})
+
+describe('scanAllRepos', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFetchSupportedScanFileNames.mockResolvedValue({ ok: true, data: {} })
+    mockGetPackageFilesForScan.mockResolvedValue(['package.json'])
+    mockFetchCreateOrgFullScan.mockResolvedValue({
+      ok: true,
+      data: { html_report_url: 'https://socket.dev/report', id: 'scan-1' },
+    })
+    mockGetScanNotifySummary.mockResolvedValue({
+      ok: true,
+      data: { baseScanId: 'scan-0', newCriticals: [{}, {}] },
+    })
+  })
+
+  it('scans local clones in place', async () => {
+    const result = await scanAllRepos({
+      concurrency: 2,
+      orgSlug: 'acme',
+      repos: [localRepo('api')],
+      source: { kind: 'local', dir: '/clones' },
+    })
+
+    expect(mockFetchCreateOrgFullScan).toHaveBeenCalledWith(
+      ['package.json'],
+      'acme',
+      expect.objectContaining({
+        branchName: 'main',
+        commitHash: 'abc123',
+        repoName: 'api',
+      }),
+      { cwd: '/clones/api' },
+    )
+    expect(result).toEqual({
+      ok: true,
+      data: [
+        {
+          branch: 'main',
+          newCriticals: 2,
+          repo: 'api',
+          reportUrl: 'https://socket.dev/report',
+          scanId: 'scan-1',
+          status: 'scanned',
+        },
+      ],
+    })
+  })
+
+  it('skips repositories without manifests', async () => {
+    mockGetPackageFilesForScan.mockResolvedValue([])
+
+    const result = await scanAllRepos({
+      concurrency: 1,
+      orgSlug: 'acme',
+      repos: [localRepo('docs')],
+      source: { kind: 'local', dir: '/clones' },
+    })
+
+    expect(mockFetchCreateOrgFullScan).not.toHaveBeenCalled()
+    expect(result.ok && result.data[0]!.status).toBe('skipped')
+  })
+
+  it('leaves new criticals unset without an earlier scan', async () => {
+    mockGetScanNotifySummary.mockResolvedValue({
+      ok: true,
+      data: { newCriticals: [] },
+    })
+
+    const result = await scanAllRepos({
+      concurrency: 1,
+      orgSlug: 'acme',
+      repos: [localRepo('api')],
+      source: { kind: 'local', dir: '/clones' },
+    })
+
+    expect(result.ok && result.data[0]!.newCriticals).toBeUndefined()
+  })
+
+  it('records failures and keeps scanning the others', async () => {
+    mockFetchCreateOrgFullScan
+      .mockResolvedValueOnce({
+        ok: false,
+        message: 'Socket API error',
+        cause: 'Bad request',
+      })
+      .mockResolvedValue({ ok: true, data: { id: 'scan-2' } })
+
+    const result = await scanAllRepos({
+      concurrency: 1,
+      orgSlug: 'acme',
+      repos: [localRepo('api'), localRepo('web')],
+      source: { kind: 'local', dir: '/clones' },
+    })
+
+    expect(result.ok && result.data).toEqual([
+      expect.objectContaining({ repo: 'web', status: 'scanned' }),
+      {
+        branch: 'main',
+        reason: 'Socket API error: Bad request',
+        repo: 'api',
+        status: 'failed',
+      },
+    ])
+  })
+
+  it('passes on failing to fetch the supported files', async () => {
+    const failure = { ok: false, message: 'Unauthorized' }
+    mockFetchSupportedScanFileNames.mockResolvedValue(failure)
+
+    const result = await scanAllRepos({
+      concurrency: 1,
+      orgSlug: 'acme',
+      repos: [localRepo('api')],
+      source: { kind: 'local', dir: '/clones' },
+    })
+
+    expect(result).toBe(failure)
+    expect(mockFetchCreateOrgFullScan).not.toHaveBeenCalled()
+  })
+})
@@ -0,0 +1,203 @@
+/**
+ * Unit tests for the in-toto statement of `socket scan attest`.
+ *
+ * Tests the subject, the policy digests and the result recorded in the
+ * predicate.
+ */
+
+import { createHash } from 'node:crypto'
+import { mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  SOCKET_SCAN_PREDICATE_TYPE,
+  buildScanAttestation,
+  countReportAlerts,
+  getFileSubject,
+} from '../../../../src/commands/scan/scan-attestation.mts'
+import { createEmptySocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+
+import type {
+  ReportLeafNode,
+  ScanReport,
+} from '../../../../src/commands/scan/generate-report.mts'
+import type { BuildScanAttestationOptions } from '../../../../src/commands/scan/scan-attestation.mts'
+
+type SecurityPolicy = BuildScanAttestationOptions['securityPolicy']
+
+function leaf(policy: ReportLeafNode['policy']): ReportLeafNode {
+  return { manifest: ['package.json'], policy, type: 'malware', url: '' }
+}
+
+function report(healthy: boolean, leaves: ReportLeafNode[]): ScanReport {
+  return {
+    alerts: new Map([
+      [
+        'npm',
+        new Map([
+          [
+            'lodash',
+            new Map([
+              [
+                '4.17.20',
+                new Map([
+                  [
+                    'package.json',
+                    new Map(leaves.map((l, i) => [`alert-${i}`, l])),
+                  ],
+                ]),
+              ],
+            ]),
+          ],
+        ]),
+      ],
+    ]) as ScanReport['alerts'],
+    healthy,
+    options: { fold: 'none', reportLevel: 'monitor' },
+    orgSlug: 'acme',
+    scanId: 'scan-1',
+  }
+}
+
+const metadata = {
+  branch: 'main',
+  commit_hash: 'abc123',
+  created_at: '2026-01-01T00:00:00Z',
+  html_report_url: 'https://socket.dev/report/scan-1',
+  repo: 'api',
+} as unknown as BuildScanAttestationOptions['metadata']
+
+const securityPolicy = {
+  securityPolicyDefault: 'medium',
+  securityPolicyRules: { malware: { action: 'error' } },
+} as unknown as SecurityPolicy
+
+describe('countReportAlerts', () => {
+  it('counts the alerts by policy action', () => {
+    expect(
+      countReportAlerts(
+        report(false, [leaf('error'), leaf('warn'), leaf('warn')]),
+      ),
+    ).toEqual({ error: 1, monitor: 0, warn: 2 })
+  })
+})
+
+describe('buildScanAttestation', () => {
+  let tmpDir: string
+
+  beforeEach(() => {
+    tmpDir = path.resolve(mkdtempSync(path.join(os.tmpdir(), 'socket-attest-')))
+  })
+
+  afterEach(async () => {
+    await safeDelete(tmpDir, { force: true })
+  })
+
+  it('attests the scanned commit', () => {
+    const statement = buildScanAttestation({
+      cwd: tmpDir,
+      metadata,
+      now: new Date('2026-01-02T00:00:00Z'),
+      orgSlug: 'acme',
+      report: report(true, [leaf('warn')]),
+      scanId: 'scan-1',
+      securityPolicy,
+    })
+
+    expect(statement.subject).toEqual([
+      { digest: { gitCommit: 'abc123' }, name: 'api' },
+    ])
+    expect(statement.predicateType).toBe(SOCKET_SCAN_PREDICATE_TYPE)
+    expect(statement.predicate).toEqual(
+      expect.objectContaining({
+        evaluatedAt: '2026-01-02T00:00:00.000Z',
+        result: { alerts: { error: 0, monitor: 0, warn: 1 }, passed: true },
+        scan: {
+          branch: 'main',
+          commit: 'abc123',
+          createdAt: '2026-01-01T00:00:00Z',
+          id: 'scan-1',
+          orgSlug: 'acme',
+          reportUrl: 'https://socket.dev/report/scan-1',
+          repo: 'api',
+        },
+      }),
+    )
+  })
+
+  it('records the policies by digest', () => {
+    const policyPath = path.join(tmpDir, 'socket.policy.yml')
+    writeFileSync(policyPath, 'version: 1\n')
+    const reordered = {
+      securityPolicyDefault: 'medium',
+      securityPolicyRules: {
+        unmaintained: { action: 'warn' },
+        malware: { action: 'error' },
+      },
+    } as unknown as SecurityPolicy
+
+    const build = (rules: SecurityPolicy) =>
+      buildScanAttestation({
+        cwd: tmpDir,
+        failOn: 'high',
+        localPolicy: { path: policyPath, policy: createEmptySocketPolicy() },
+        metadata,
+        orgSlug: 'acme',
+        report: report(false, [leaf('error')]),
+        scanId: 'scan-1',
+        securityPolicy: rules,
+      }).predicate.policy
+
+    const policy = build(reordered)
+    expect(policy.failOn).toBe('high')
+    expect(policy.localPolicy).toEqual({
+      digest: {
+        sha256: createHash('sha256').update('version: 1\n').digest('hex'),
+      },
+      path: 'socket.policy.yml',
+    })
+    // The digest does not depend on the order of the rules.
+    expect(
+      build({
+        ...reordered,
+        securityPolicyRules: {
+          malware: { action: 'error' },
+          unmaintained: { action: 'warn' },
+        },
+      }).securityPolicy,
+    ).toEqual(policy.securityPolicy)
+    expect(policy.securityPolicy).not.toEqual(
+      build(securityPolicy).securityPolicy,
+    )
+  })
+
+  it('attests the given artifacts instead of the commit', () => {
+    const artifactPath = path.join(tmpDir, 'app.tar.gz')
+    writeFileSync(artifactPath, 'artifact')
+
+    const statement = buildScanAttestation({
+      cwd: tmpDir,
+      metadata,
+      orgSlug: 'acme',
+      report: report(true, []),
+      scanId: 'scan-1',
+      securityPolicy,
+      subjects: [getFileSubject(artifactPath)],
+    })
+
+    expect(statement.subject).toEqual([
+      {
+        digest: {
+          sha256: createHash('sha256').update('artifact').digest('hex'),
+        },
+        name: 'app.tar.gz',
+      },
+    ])
+    expect(statement.predicate.scan.commit).toBe('abc123')
+  })
+})
@@ -0,0 +1,232 @@
+/**
+ * Unit tests for the chat notifications of `socket scan create --notify`.
+ *
+ * Tests comparing the new scan with the latest scan of the default branch,
+ * the Slack and Microsoft Teams payloads, and the failures of the webhook.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockFetchViewRepo = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/repository/fetch-view-repo.mts'),
+  () => ({
+    fetchViewRepo: mockFetchViewRepo,
+  }),
+)
+
+const mockFetchOrgFullScanList = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/scan/fetch-list-scans.mts'), () => ({
+  fetchOrgFullScanList: mockFetchOrgFullScanList,
+}))
+
+const mockFetchScanAlertDiff = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/scan/fetch-scan-alert-diff.mts'),
+  () => ({
+    fetchScanAlertDiff: mockFetchScanAlertDiff,
+  }),
+)
+
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  socketHttpRequest: mockSocketHttpRequest,
+  tryReadResponseText: () => '',
+}))
+
+import {
+  formatSlackPayload,
+  formatTeamsPayload,
+  getScanNotifySummary,
+  notifyScan,
+} from '../../../../src/commands/scan/scan-notify.mts'
+
+import type { ScanNotifySummary } from '../../../../src/commands/scan/scan-notify.mts'
+
+const critical = {
+  action: 'error',
+  purl: 'pkg:npm/evil@1.0.0',
+  severity: 'critical',
+  type: 'malware',
+}
+
+function getSummary(
+  overrides?: Partial<ScanNotifySummary> | undefined,
+): ScanNotifySummary {
+  return {
+    baseBranch: 'main',
+    baseScanId: 'scan-base',
+    branchName: 'feature',
+    counts: { added: 2, blocking: 1, removed: 3 },
+    newCriticals: [critical],
+    orgSlug: 'test-org',
+    repoName: 'test-repo',
+    reportUrl: 'https://socket.dev/dashboard/org/test-org/sbom/scan-new',
+    scanId: 'scan-new',
+    ...overrides,
+  }
+}
+
+describe('scan-notify', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFetchViewRepo.mockResolvedValue({
+      ok: true,
+      data: { default_branch: 'main' },
+    })
+    mockFetchOrgFullScanList.mockResolvedValue({
+      ok: true,
+      data: { results: [{ id: 'scan-new' }, { id: 'scan-base' }] },
+    })
+    mockFetchScanAlertDiff.mockResolvedValue({
+      ok: true,
+      data: {
+        added: [critical, { ...critical, severity: 'high', type: 'cve' }],
+        blocking: [critical],
+        removed: [],
+      },
+    })
+  })
+
+  describe('getScanNotifySummary', () => {
+    it('compares with the latest other scan of the default branch', async () => {
+      const result = await getScanNotifySummary({
+        branchName: 'feature',
+        orgSlug: 'test-org',
+        repoName: 'test-repo',
+        scanId: 'scan-new',
+      })
+
+      expect(mockFetchOrgFullScanList).toHaveBeenCalledWith(
+        expect.objectContaining({ branch: 'main', repo: 'test-repo' }),
+        expect.anything(),
+      )
+      expect(mockFetchScanAlertDiff).toHaveBeenCalledWith({
+        id1: 'scan-base',
+        id2: 'scan-new',
+        orgSlug: 'test-org',
+      })
+      expect(result).toMatchObject({
+        ok: true,
+        data: {
+          baseScanId: 'scan-base',
+          counts: { added: 2, blocking: 1, removed: 0 },
+          newCriticals: [critical],
+        },
+      })
+    })
+
+    it('skips the diff without an earlier scan', async () => {
+      mockFetchOrgFullScanList.mockResolvedValueOnce({
+        ok: true,
+        data: { results: [{ id: 'scan-new' }] },
+      })
+
+      const result = await getScanNotifySummary({
+        branchName: 'main',
+        orgSlug: 'test-org',
+        repoName: 'test-repo',
+        scanId: 'scan-new',
+      })
+
+      expect(mockFetchScanAlertDiff).not.toHaveBeenCalled()
+      expect(result.ok && result.data.baseScanId).toBeUndefined()
+    })
+  })
+
+  describe('formatSlackPayload', () => {
+    it('lists the new criticals and links the report', () => {
+      const payload = formatSlackPayload(getSummary())
+      const text = JSON.stringify(payload)
+
+      expect(payload['text']).toBe(
+        'Socket scan of test-repo (feature): 1 new critical alert compared with main',
+      )
+      expect(text).toContain('`malware` in `pkg:npm/evil@1.0.0`')
+      expect(text).toContain(
+        '"url":"https://socket.dev/dashboard/org/test-org/sbom/scan-new"',
+      )
+    })
+
+    it('says when there is nothing to compare with', () => {
+      const payload = formatSlackPayload(
+        getSummary({
+          baseScanId: undefined,
+          counts: undefined,
+          newCriticals: [],
+        }),
+      )
+
+      expect(payload['text']).toBe(
+        'Socket scan of test-repo (feature): No earlier scan of main to compare with',
+      )
+    })
+  })
+
+  describe('formatTeamsPayload', () => {
+    it('wraps an Adaptive Card in a message', () => {
+      const payload = formatTeamsPayload(getSummary({ newCriticals: [] }))
+      const [attachment] = payload['attachments'] as Array<Record<string, any>>
+
+      expect(payload['type']).toBe('message')
+      expect(attachment!['contentType']).toBe(
+        'application/vnd.microsoft.card.adaptive',
+      )
+      expect(attachment!['content'].body[1]).toMatchObject({
+        color: 'Good',
+        text: 'No new critical alerts compared with main',
+      })
+      expect(attachment!['content'].body[2].facts).toContainEqual({
+        title: 'Resolved',
+        value: '3',
+      })
+    })
+  })
+
+  describe('notifyScan', () => {
+    it('posts the payload of the channel as JSON', async () => {
+      mockSocketHttpRequest.mockResolvedValueOnce({ status: 200 })
+
+      const result = await notifyScan({
+        branchName: 'feature',
+        channel: 'slack',
+        orgSlug: 'test-org',
+        repoName: 'test-repo',
+        scanId: 'scan-new',
+        webhookUrl: 'https://hooks.slack.com/services/T/B/X',
+      })
+
+      expect(result.ok).toBe(true)
+      expect(mockSocketHttpRequest).toHaveBeenCalledWith(
+        'https://hooks.slack.com/services/T/B/X',
+        expect.objectContaining({
+          headers: { 'Content-Type': 'application/json' },
+          method: 'POST',
+        }),
+      )
+    })
+
+    it('fails when the webhook rejects the message', async () => {
+      mockSocketHttpRequest.mockResolvedValueOnce({
+        status: 404,
+        statusText: 'Not Found',
+      })
+
+      const result = await notifyScan({
+        branchName: 'feature',
+        channel: 'teams',
+        orgSlug: 'test-org',
+        repoName: 'test-repo',
+        scanId: 'scan-new',
+        webhookUrl: 'https://example.webhook.office.com/webhookb2/abc',
+      })
+
+      expect(result).toEqual({
+        ok: false,
+        message: 'Failed to post the notification',
+        cause: 'The webhook responded with 404 Not Found',
+        data: { code: 404 },
+      })
+    })
+  })
+})
@@ -0,0 +1,280 @@
+/**
+ * Unit tests for the triage view of `socket scan view --interactive`.
+ *
+ * Purpose: Tests the terminal-independent part of the triage UI: the items
+ * built from a scan, grouping, key handling and the rendered screen.
+ *
+ * Test Coverage: - Dependency paths from the nearest direct dependency -
+ * Grouping by package and severity - Cursor movement over item rows -
+ * Reason prompt for ignore and accept - Status updates - Rendering and
+ * scrolling.
+ *
+ * Related Files: - src/commands/scan/scan-view-triage.mts (implementation) -
+ * src/commands/scan/scan-view-triage-state.mts - View state and keys -
+ * src/commands/scan/scan-view-tui.mts - Key loop.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { stripAnsi } from '@socketsecurity/lib-stable/ansi/strip'
+
+import {
+  createTriageViewState,
+  getSelectedTriageIndex,
+  getTriageRows,
+  reduceTriageView,
+  setTriageStatus,
+} from '../../../../src/commands/scan/scan-view-triage-state.mts'
+import {
+  getTriageItems,
+  renderTriageView,
+} from '../../../../src/commands/scan/scan-view-triage.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+import type {
+  TriageKey,
+  TriageViewState,
+} from '../../../../src/commands/scan/scan-view-triage-state.mts'
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+// app -> lib -> evil, other -> evil.
+function getScan(): SocketArtifact[] {
+  return [
+    artifact('app', { id: '1', dependencies: ['2'], direct: true }),
+    artifact('lib', {
+      id: '2',
+      alerts: [{ key: 'k2', type: 'installScripts', severity: 'middle' }],
+      dependencies: ['3'],
+    }),
+    artifact('evil', {
+      id: '3',
+      alerts: [
+        { key: 'k1', type: 'malware', severity: 'critical', action: 'error' },
+        { key: 'k3', type: 'gitDependency', severity: 'middle' },
+      ],
+    }),
+    artifact('other', { id: '4', dependencies: ['3'], direct: true }),
+  ]
+}
+
+function press(
+  state: TriageViewState,
+  ...keys: Array<string | TriageKey>
+): TriageViewState {
+  let current = state
+  for (const key of keys) {
+    current = reduceTriageView(
+      current,
+      typeof key === 'string' ? { name: key, sequence: key } : key,
+    ).state
+  }
+  return current
+}
+
+function getScreen(state: TriageViewState, rows = 24): string[] {
+  return renderTriageView(state, { columns: 100, rows }).lines.map(line =>
+    stripAnsi(line),
+  )
+}
+
+describe('getTriageItems', () => {
+  it('creates an item per alert with its shortest dependency path', () => {
+    const items = getTriageItems(getScan())
+
+    expect(items).toEqual([
+      {
+        action: '',
+        alertKey: 'k2',
+        dependencyPath: ['pkg:npm/app@1.0.0', 'pkg:npm/lib@1.0.0'],
+        purl: 'pkg:npm/lib@1.0.0',
+        severity: 'middle',
+        status: 'open',
+        title: 'Install scripts',
+        type: 'installScripts',
+      },
+      expect.objectContaining({
+        action: 'error',
+        alertKey: 'k1',
+        dependencyPath: ['pkg:npm/other@1.0.0', 'pkg:npm/evil@1.0.0'],
+        title: 'Known malware',
+      }),
+      expect.objectContaining({
+        alertKey: 'k3',
+        dependencyPath: ['pkg:npm/other@1.0.0', 'pkg:npm/evil@1.0.0'],
+      }),
+    ])
+  })
+
+  it('falls back to the top level ancestors without a dependency graph', () => {
+    const items = getTriageItems([
+      artifact('app', { id: '1', direct: true }),
+      artifact('evil', {
+        id: '2',
+        alerts: [{ type: 'malware', severity: 'critical' }],
+        topLevelAncestors: ['1'],
+      }),
+    ])
+
+    expect(items[0]!.dependencyPath).toEqual([
+      'pkg:npm/app@1.0.0',
+      'pkg:npm/evil@1.0.0',
+    ])
+    expect(items[0]!.alertKey).toBe('')
+  })
+})
+
+describe('getTriageRows', () => {
+  it('groups by package, worst alert first', () => {
+    const rows = getTriageRows(getTriageItems(getScan()), 'package')
+
+    expect(rows).toEqual([
+      { type: 'group', count: 2, label: 'pkg:npm/evil@1.0.0' },
+      { type: 'item', index: 1 },
+      { type: 'item', index: 2 },
+      { type: 'group', count: 1, label: 'pkg:npm/lib@1.0.0' },
+      { type: 'item', index: 0 },
+    ])
+  })
+
+  it('groups by severity from critical down', () => {
+    const rows = getTriageRows(getTriageItems(getScan()), 'severity')
+
+    expect(rows).toEqual([
+      { type: 'group', count: 1, label: 'critical' },
+      { type: 'item', index: 1 },
+      { type: 'group', count: 2, label: 'middle' },
+      { type: 'item', index: 2 },
+      { type: 'item', index: 0 },
+    ])
+  })
+})
+
+describe('reduceTriageView', () => {
+  it('moves over item rows and skips group headers', () => {
+    const state = createTriageViewState(getTriageItems(getScan()))
+
+    expect(getSelectedTriageIndex(state)).toBe(1)
+    expect(getSelectedTriageIndex(press(state, 'down'))).toBe(2)
+    expect(getSelectedTriageIndex(press(state, 'down', 'j'))).toBe(0)
+    expect(getSelectedTriageIndex(press(state, 'down', 'j', 'down'))).toBe(0)
+    expect(getSelectedTriageIndex(press(state, 'end', 'up', 'k'))).toBe(1)
+  })
+
+  it('keeps the selected item when switching the grouping', () => {
+    const state = press(
+      createTriageViewState(getTriageItems(getScan())),
+      'end',
+      'g',
+    )
+
+    expect(state.groupBy).toBe('severity')
+    expect(getSelectedTriageIndex(state)).toBe(0)
+    expect(state.cursor).toBe(4)
+  })
+
+  it('toggles the dependency path details', () => {
+    const state = createTriageViewState(getTriageItems(getScan()))
+
+    expect(press(state, 'return').expanded).toBe(true)
+    expect(press(state, 'return', 'return').expanded).toBe(false)
+    expect(press(state, 'right', 'left').expanded).toBe(false)
+  })
+
+  it('asks for a reason before triaging', () => {
+    let state = press(
+      createTriageViewState(getTriageItems(getScan())),
+      'i',
+      'o',
+      'k',
+      { name: 'space', sequence: ' ' },
+      'x',
+      'backspace',
+    )
+
+    expect(state.prompt).toEqual({ status: 'ignored', value: 'ok ' })
+
+    const result = reduceTriageView(state, { name: 'return', sequence: '\r' })
+    state = result.state
+
+    expect(result.command).toEqual({
+      type: 'triage',
+      index: 1,
+      reason: 'ok',
+      status: 'ignored',
+    })
+    expect(state.prompt).toBeUndefined()
+  })
+
+  it('requires a non-empty reason and cancels on escape', () => {
+    const state = press(createTriageViewState(getTriageItems(getScan())), 'a')
+    const empty = reduceTriageView(state, { name: 'return' })
+
+    expect(empty.command).toEqual({ type: 'none' })
+    expect(empty.state.message).toBe('A reason is required')
+    expect(press(state, 'escape').prompt).toBeUndefined()
+  })
+
+  it('quits on q and ctrl+c', () => {
+    const state = createTriageViewState([])
+
+    expect(reduceTriageView(state, { name: 'q' }).command.type).toBe('quit')
+    expect(
+      reduceTriageView(state, { ctrl: true, name: 'c' }).command.type,
+    ).toBe('quit')
+  })
+})
+
+describe('renderTriageView', () => {
+  it('renders groups, items and statuses', () => {
+    const state = setTriageStatus(
+      createTriageViewState(getTriageItems(getScan())),
+      2,
+      'accepted',
+      'Accepted gitDependency',
+    )
+
+    expect(getScreen(state)).toEqual([
+      'Scan alerts: 3 (2 open) · grouped by package',
+      '',
+      'pkg:npm/evil@1.0.0 (2)',
+      '  › Known malware · critical',
+      '    Git dependency · middle [accepted]',
+      'pkg:npm/lib@1.0.0 (1)',
+      '    Install scripts · middle',
+      'Accepted gitDependency',
+    ])
+  })
+
+  it('shows the dependency path of the selected item', () => {
+    const screen = getScreen(
+      press(createTriageViewState(getTriageItems(getScan())), 'return'),
+    )
+
+    expect(screen.slice(-6)).toEqual([
+      'Known malware (malware)',
+      'severity critical · policy error',
+      'Dependency path:',
+      '  pkg:npm/other@1.0.0',
+      '    └─ pkg:npm/evil@1.0.0',
+      '↑/↓ move  enter path  g group  i ignore  a accept  q quit',
+    ])
+  })
+
+  it('scrolls to keep the cursor visible', () => {
+    const state = press(
+      createTriageViewState(getTriageItems(getScan())),
+      'end',
+    )
+    const rendered = renderTriageView(state, { columns: 100, rows: 5 })
+
+    expect(rendered.lines).toHaveLength(5)
+    expect(rendered.state.scrollTop).toBe(3)
+    expect(stripAnsi(rendered.lines[2]!)).toBe('pkg:npm/lib@1.0.0 (1)')
+  })
+
+  it('says when the scan has no alerts', () => {
+    expect(getScreen(createTriageViewState([]))).toContain(
+      'No alerts in this scan.',
+    )
+  })
+})
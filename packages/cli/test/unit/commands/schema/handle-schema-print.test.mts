@@ -0,0 +1,70 @@
+/**
+ * Unit tests for `socket schema print`.
+ *
+ * Purpose: Tests the schema lookup and how it is printed.
+ *
+ * Test Coverage: - Known commands - Unknown commands - Output and exit codes.
+ *
+ * Related Files: - src/commands/schema/handle-schema-print.mts
+ * (implementation) - src/commands/schema/output-schema-print.mts - Output.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockLogger = vi.hoisted(() => ({
+  fail: vi.fn(),
+  log: vi.fn(),
+}))
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+vi.mock(import('../../../../src/util/error/fail-msg-with-badge.mts'), () => ({
+  failMsgWithBadge: (msg: string, cause?: string | undefined) =>
+    cause ? `${msg}: ${cause}` : msg,
+}))
+
+import {
+  getSchemaForCommand,
+  handleSchemaPrint,
+} from '../../../../src/commands/schema/handle-schema-print.mts'
+
+describe('handle-schema-print', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  it('finds the schema of a command', () => {
+    const result = getSchemaForCommand('ci report')
+
+    expect(result.ok).toBe(true)
+    expect(result.ok && result.data['title']).toBe('socket ci report --json')
+  })
+
+  it('fails for a command without JSON output', () => {
+    const result = getSchemaForCommand('login')
+
+    expect(result).toMatchObject({ ok: false, message: 'Unknown command' })
+    expect(!result.ok && result.cause).toContain('`socket login`')
+  })
+
+  it('prints the schema as JSON', async () => {
+    await handleSchemaPrint('scan check')
+
+    const schema = JSON.parse(mockLogger.log.mock.calls[0]![0] as string)
+    expect(schema.properties.command).toEqual({ const: 'scan check' })
+    expect(schema.properties.data.required).toEqual(['deny', 'passed', 'warn'])
+    expect(process.exitCode).toBeUndefined()
+  })
+
+  it('fails with exit code 1 for unknown commands', async () => {
+    await handleSchemaPrint('nope')
+
+    expect(mockLogger.log).not.toHaveBeenCalled()
+    expect(mockLogger.fail).toHaveBeenCalledWith(
+      expect.stringContaining('Unknown command'),
+    )
+    expect(process.exitCode).toBe(1)
+  })
+})
@@ -0,0 +1,127 @@
+/**
+ * Unit tests for the self-update command.
+ *
+ * Tests the command that replaces the standalone executable with the latest
+ * release of a channel.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdSelfUpdate } from '../../../../src/commands/self-update/cmd-self-update.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockGetCliVersion = vi.hoisted(() => vi.fn())
+const mockHandleSelfUpdate = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/env/cli-version.mts'), () => ({
+  getCliVersion: mockGetCliVersion,
+}))
+
+vi.mock(
+  import('../../../../src/commands/self-update/handle-self-update.mts'),
+  () => ({
+    handleSelfUpdate: mockHandleSelfUpdate,
+  }),
+)
+
+describe('cmd-self-update', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+    mockGetCliVersion.mockReturnValue('2.0.0')
+  })
+
+  describe('command metadata', () => {
+    it('should have correct description', () => {
+      expect(cmdSelfUpdate.description).toBe(
+        'Update the Socket CLI executable to the latest release',
+      )
+    })
+
+    it('should not be hidden', () => {
+      expect(cmdSelfUpdate.hidden).toBe(false)
+    })
+  })
+
+  describe('run', () => {
+    const importMeta = { url: 'file:///test/cmd-self-update.mts' }
+    const context = { parentName: 'socket' }
+
+    it('should support --dry-run flag', async () => {
+      await cmdSelfUpdate.run(['--dry-run'], importMeta, context)
+
+      expect(mockHandleSelfUpdate).not.toHaveBeenCalled()
+      expect(mockLogger.error).toHaveBeenCalledWith(
+        expect.stringContaining('DryRun'),
+      )
+    })
+
+    it('should default to the stable channel', async () => {
+      await cmdSelfUpdate.run([], importMeta, context)
+
+      expect(mockHandleSelfUpdate).toHaveBeenCalledWith({
+        channel: 'stable',
+        force: false,
+        outputKind: 'text',
+      })
+    })
+
+    it('should default prereleases to the beta channel', async () => {
+      mockGetCliVersion.mockReturnValue('2.1.0-beta.2')
+
+      await cmdSelfUpdate.run(['--json'], importMeta, context)
+
+      expect(mockHandleSelfUpdate).toHaveBeenCalledWith({
+        channel: 'beta',
+        force: false,
+        outputKind: 'json',
+      })
+    })
+
+    it('should pass --channel and --force', async () => {
+      mockGetCliVersion.mockReturnValue('2.1.0-beta.2')
+
+      await cmdSelfUpdate.run(
+        ['--channel', 'stable', '--force'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleSelfUpdate).toHaveBeenCalledWith({
+        channel: 'stable',
+        force: true,
+        outputKind: 'text',
+      })
+    })
+
+    it('should reject unknown channels', async () => {
+      await cmdSelfUpdate.run(['--channel', 'nightly'], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleSelfUpdate).not.toHaveBeenCalled()
+    })
+  })
+})
@@ -0,0 +1,243 @@
+/**
+ * Unit tests for `socket self-update`.
+ *
+ * Purpose: Tests replacing the standalone executable with a signed release.
+ *
+ * Test Coverage: - The managed environment opt-out - Package manager and
+ * unmanaged installs - Registry signature failures - Up to date and forced
+ * updates - Replacing the executable - Releases without an executable.
+ *
+ * Related Files: - src/commands/self-update/self-update.mts (implementation)
+ * - src/util/update/channel.mts (channels)
+ */
+
+import { promises as fs } from 'node:fs'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { selfUpdate } from '../../../../src/commands/self-update/self-update.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+import type { Workspace } from '../../../helpers/workspace-helper.mts'
+import type * as ChannelModule from '../../../../src/util/update/channel.mts'
+
+const mockCanSelfUpdate = vi.hoisted(() => vi.fn())
+const mockFetchNpmProvenanceData = vi.hoisted(() => vi.fn())
+const mockFetchPackageTarball = vi.hoisted(() => vi.fn())
+const mockGetCliExeTriplet = vi.hoisted(() => vi.fn())
+const mockGetCliVersion = vi.hoisted(() => vi.fn())
+const mockGetRegistrySignatureCheck = vi.hoisted(() => vi.fn())
+const mockGetSeaBinaryPath = vi.hoisted(() => vi.fn())
+const mockGetSelfUpdateDisabledReason = vi.hoisted(() => vi.fn())
+const mockIsSeaBinary = vi.hoisted(() => vi.fn())
+const mockReadTarballFiles = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/env/cli-version.mts'), () => ({
+  getCliVersion: mockGetCliVersion,
+}))
+
+vi.mock(import('../../../../src/util/sea/detect.mts'), () => ({
+  canSelfUpdate: mockCanSelfUpdate,
+  getSeaBinaryPath: mockGetSeaBinaryPath,
+  isSeaBinary: mockIsSeaBinary,
+}))
+
+vi.mock(
+  import('../../../../src/util/update/channel.mts'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof ChannelModule>()
+    return {
+      ...actual,
+      getCliExeTriplet: mockGetCliExeTriplet,
+      getSelfUpdateDisabledReason: mockGetSelfUpdateDisabledReason,
+    }
+  },
+)
+
+vi.mock(
+  import('../../../../src/commands/verify/fetch-npm-provenance.mts'),
+  () => ({
+    fetchNpmProvenanceData: mockFetchNpmProvenanceData,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/verify/registry-signature.mts'),
+  () => ({
+    getRegistrySignatureCheck: mockGetRegistrySignatureCheck,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/audit-installed/package-contents.mts'),
+  () => ({
+    fetchPackageTarball: mockFetchPackageTarball,
+    readTarballFiles: mockReadTarballFiles,
+  }),
+)
+
+const INTEGRITY = `sha512-${Buffer.alloc(64, 1).toString('base64')}`
+
+describe('selfUpdate', () => {
+  let workspace: Workspace
+  let binPath: string
+
+  beforeEach(async () => {
+    vi.clearAllMocks()
+    workspace = await createTestWorkspace({
+      files: [{ path: 'socket', content: 'old' }],
+    })
+    binPath = workspace.resolve('socket')
+    mockCanSelfUpdate.mockReturnValue(true)
+    mockGetCliExeTriplet.mockReturnValue('linux-x64')
+    mockGetCliVersion.mockReturnValue('2.0.0')
+    mockGetSeaBinaryPath.mockReturnValue(binPath)
+    mockGetSelfUpdateDisabledReason.mockReturnValue(undefined)
+    mockIsSeaBinary.mockReturnValue(true)
+    mockFetchNpmProvenanceData.mockResolvedValue({
+      ok: true,
+      data: {
+        attestations: [],
+        keys: [],
+        manifest: {
+          dist: { integrity: INTEGRITY, tarball: 'https://example.test/t.tgz' },
+          name: '@socketsecurity/cli.exe.linux-x64',
+          version: '2.1.0',
+        },
+      },
+    })
+    mockGetRegistrySignatureCheck.mockReturnValue({
+      check: 'registry signature',
+      message: 'Signed by npm registry key SHA256:abc',
+      status: 'pass',
+    })
+    mockFetchPackageTarball.mockResolvedValue({
+      ok: true,
+      data: Buffer.from('tgz'),
+    })
+    mockReadTarballFiles.mockReturnValue(
+      new Map([['bin/socket', Buffer.from('new')]]),
+    )
+  })
+
+  afterEach(async () => {
+    await workspace.cleanup()
+  })
+
+  it('should replace the executable with a newer signed release', async () => {
+    const result = await selfUpdate({ channel: 'stable' })
+
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        channel: 'stable',
+        current: '2.0.0',
+        latest: '2.1.0',
+        path: binPath,
+        signature: 'Signed by npm registry key SHA256:abc',
+        updated: true,
+      },
+    })
+    expect(mockFetchNpmProvenanceData).toHaveBeenCalledWith(
+      '@socketsecurity/cli.exe.linux-x64',
+      'latest',
+    )
+    expect(await fs.readFile(binPath, 'utf8')).toBe('new')
+  })
+
+  it('should leave an up to date executable alone', async () => {
+    mockGetCliVersion.mockReturnValue('2.1.0')
+
+    const result = await selfUpdate({ channel: 'stable' })
+
+    expect(result.ok && result.data.updated).toBe(false)
+    expect(mockFetchPackageTarball).not.toHaveBeenCalled()
+    expect(await fs.readFile(binPath, 'utf8')).toBe('old')
+  })
+
+  it('should install an older release with force', async () => {
+    mockGetCliVersion.mockReturnValue('2.2.0-beta.1')
+
+    const result = await selfUpdate({ channel: 'stable', force: true })
+
+    expect(result.ok && result.data.updated).toBe(true)
+    expect(await fs.readFile(binPath, 'utf8')).toBe('new')
+  })
+
+  it('should not install a release without a valid signature', async () => {
+    mockGetRegistrySignatureCheck.mockReturnValue({
+      check: 'registry signature',
+      message: 'No signature verifies with a current npm registry key',
+      status: 'fail',
+    })
+
+    const result = await selfUpdate({ channel: 'beta' })
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.message).toBe('Signature verification failed')
+    expect(mockFetchPackageTarball).not.toHaveBeenCalled()
+    expect(await fs.readFile(binPath, 'utf8')).toBe('old')
+  })
+
+  it('should fail when the release has no executable', async () => {
+    mockReadTarballFiles.mockReturnValue(new Map())
+
+    const result = await selfUpdate({ channel: 'stable' })
+
+    expect(!result.ok && result.message).toBe(
+      'Executable not found in release',
+    )
+    expect(await fs.readFile(binPath, 'utf8')).toBe('old')
+  })
+
+  it('should pass tarball failures through', async () => {
+    const failure = { ok: false, message: 'Integrity mismatch' }
+    mockFetchPackageTarball.mockResolvedValue(failure)
+
+    const result = await selfUpdate({ channel: 'stable' })
+
+    expect(result).toBe(failure)
+  })
+
+  it('should fail when self-update is disabled', async () => {
+    mockGetSelfUpdateDisabledReason.mockReturnValue(
+      'SOCKET_CLI_NO_SELF_UPDATE is set',
+    )
+
+    const result = await selfUpdate({ channel: 'stable' })
+
+    expect(!result.ok && result.message).toBe('Self-update is disabled')
+    expect(mockFetchNpmProvenanceData).not.toHaveBeenCalled()
+  })
+
+  it('should point package manager installs to npm', async () => {
+    mockIsSeaBinary.mockReturnValue(false)
+    mockGetSeaBinaryPath.mockReturnValue(undefined)
+
+    const result = await selfUpdate({ channel: 'beta' })
+
+    expect(!result.ok && result.message).toBe(
+      'Self-update is only available for the standalone executable',
+    )
+    expect(!result.ok && result.cause).toContain('npm install -g')
+    expect(!result.ok && result.cause).toContain('@beta')
+  })
+
+  it('should not replace executables Socket does not manage', async () => {
+    mockCanSelfUpdate.mockReturnValue(false)
+
+    const result = await selfUpdate({ channel: 'stable' })
+
+    expect(!result.ok && result.message).toBe(
+      'This executable is not managed by Socket',
+    )
+  })
+
+  it('should fail on platforms without an executable', async () => {
+    mockGetCliExeTriplet.mockReturnValue(undefined)
+
+    const result = await selfUpdate({ channel: 'stable' })
+
+    expect(!result.ok && result.message).toBe('Unsupported platform')
+  })
+})
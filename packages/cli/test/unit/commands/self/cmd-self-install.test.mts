@@ -0,0 +1,141 @@
+/**
+ * Unit tests for the self install command.
+ *
+ * Tests the command that installs the standalone executable and puts it on
+ * PATH.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdSelfInstall } from '../../../../src/commands/self/cmd-self-install.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockGetCliVersion = vi.hoisted(() => vi.fn())
+const mockHandleSelfInstall = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/env/cli-version.mts'), () => ({
+  getCliVersion: mockGetCliVersion,
+}))
+
+vi.mock(
+  import('../../../../src/commands/self/handle-self-install.mts'),
+  () => ({
+    handleSelfInstall: mockHandleSelfInstall,
+  }),
+)
+
+describe('cmd-self-install', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+    mockGetCliVersion.mockReturnValue('2.0.0')
+  })
+
+  describe('command metadata', () => {
+    it('should have correct description', () => {
+      expect(cmdSelfInstall.description).toBe(
+        'Install the standalone Socket CLI executable and put it on PATH',
+      )
+    })
+
+    it('should not be hidden', () => {
+      expect(cmdSelfInstall.hidden).toBe(false)
+    })
+  })
+
+  describe('run', () => {
+    const importMeta = { url: 'file:///test/cmd-self-install.mts' }
+    const context = { parentName: 'socket self' }
+
+    it('should support --dry-run flag', async () => {
+      await cmdSelfInstall.run(['--dry-run'], importMeta, context)
+
+      expect(mockHandleSelfInstall).not.toHaveBeenCalled()
+      expect(mockLogger.error).toHaveBeenCalledWith(
+        expect.stringContaining('DryRun'),
+      )
+    })
+
+    it('should install the latest stable release by default', async () => {
+      await cmdSelfInstall.run([], importMeta, context)
+
+      expect(mockHandleSelfInstall).toHaveBeenCalledWith({
+        binDir: '',
+        channel: 'stable',
+        from: '',
+        outputKind: 'text',
+        release: '',
+        skipPath: false,
+      })
+    })
+
+    it('should default the channel of a prerelease to beta', async () => {
+      await cmdSelfInstall.run(
+        ['--release', '2.1.0-beta.2', '--from', 'package.tgz', '--json'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleSelfInstall).toHaveBeenCalledWith({
+        binDir: '',
+        channel: 'beta',
+        from: 'package.tgz',
+        outputKind: 'json',
+        release: '2.1.0-beta.2',
+        skipPath: false,
+      })
+    })
+
+    it('should pass --bin-dir and --skip-path', async () => {
+      await cmdSelfInstall.run(
+        ['--bin-dir', '/opt/socket/bin', '--skip-path'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleSelfInstall).toHaveBeenCalledWith(
+        expect.objectContaining({
+          binDir: '/opt/socket/bin',
+          skipPath: true,
+        }),
+      )
+    })
+
+    it('should reject a release that is not a version', async () => {
+      await cmdSelfInstall.run(['--release', 'latest'], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleSelfInstall).not.toHaveBeenCalled()
+    })
+
+    it('should reject unknown channels', async () => {
+      await cmdSelfInstall.run(['--channel', 'nightly'], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleSelfInstall).not.toHaveBeenCalled()
+    })
+  })
+})
@@ -0,0 +1,375 @@
+/**
+ * Unit tests for `socket self install`.
+ *
+ * Purpose: Tests installing the standalone executable of a signed release
+ * and putting its command on PATH.
+ *
+ * Test Coverage: - Installing a signed release - Pinned versions - Release
+ * tarballs downloaded already - Registry signature and integrity failures -
+ * Shell profile and GITHUB_PATH updates - Reinstalling to another bin
+ * directory - Unsupported platforms.
+ *
+ * Related Files: - src/commands/self/self-install.mts (implementation) -
+ * src/commands/self-update/self-update.mts (release checks)
+ */
+
+import crypto from 'node:crypto'
+import { promises as fs } from 'node:fs'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  getSelfInstallPath,
+  renderCmdLauncher,
+  selfInstall,
+} from '../../../../src/commands/self/self-install.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+import type { Workspace } from '../../../helpers/workspace-helper.mts'
+import type * as DlxBinaryModule from '@socketsecurity/lib-stable/dlx/binary'
+import type * as PackageContentsModule from '../../../../src/commands/audit-installed/package-contents.mts'
+import type * as ShellProfilesModule from '../../../../src/commands/wrapper/shell-profiles.mts'
+import type * as ChannelModule from '../../../../src/util/update/channel.mts'
+
+const mockDlxDir = vi.hoisted(() => ({ value: '' }))
+const mockFetchNpmProvenanceData = vi.hoisted(() => vi.fn())
+const mockFetchPackageTarball = vi.hoisted(() => vi.fn())
+const mockGetCliExeTriplet = vi.hoisted(() => vi.fn())
+const mockGetGithubPath = vi.hoisted(() => vi.fn())
+const mockGetRegistrySignatureCheck = vi.hoisted(() => vi.fn())
+const mockGetShellRcPaths = vi.hoisted(() => vi.fn())
+const mockReadTarballFiles = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('@socketsecurity/lib-stable/dlx/binary'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof DlxBinaryModule>()
+    return {
+      ...actual,
+      getDlxCachePath: () => mockDlxDir.value,
+    }
+  },
+)
+
+vi.mock(import('../../../../src/env/github-path.mts'), () => ({
+  getGithubPath: mockGetGithubPath,
+}))
+
+vi.mock(
+  import('../../../../src/util/update/channel.mts'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof ChannelModule>()
+    return {
+      ...actual,
+      getCliExeTriplet: mockGetCliExeTriplet,
+    }
+  },
+)
+
+vi.mock(
+  import('../../../../src/commands/verify/fetch-npm-provenance.mts'),
+  () => ({
+    fetchNpmProvenanceData: mockFetchNpmProvenanceData,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/verify/registry-signature.mts'),
+  () => ({
+    getRegistrySignatureCheck: mockGetRegistrySignatureCheck,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/audit-installed/package-contents.mts'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof PackageContentsModule>()
+    return {
+      ...actual,
+      fetchPackageTarball: mockFetchPackageTarball,
+      readTarballFiles: mockReadTarballFiles,
+    }
+  },
+)
+
+vi.mock(
+  import('../../../../src/commands/wrapper/shell-profiles.mts'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof ShellProfilesModule>()
+    return {
+      ...actual,
+      getShellRcPaths: mockGetShellRcPaths,
+    }
+  },
+)
+
+const TGZ = Buffer.from('tgz')
+
+const INTEGRITY = `sha512-${crypto.createHash('sha512').update(TGZ).digest('base64')}`
+
+const RELEASE = {
+  name: '@socketsecurity/cli.exe.linux-x64',
+  version: '2.1.0',
+}
+
+describe('selfInstall', () => {
+  let workspace: Workspace
+  let binDir: string
+  let rcPath: string
+  const originalPath = process.env['PATH']
+
+  beforeEach(async () => {
+    vi.clearAllMocks()
+    workspace = await createTestWorkspace({
+      files: [
+        { path: '.bashrc', content: 'alias ll="ls -l"\n' },
+        { path: 'package.tgz', content: 'tgz' },
+      ],
+    })
+    binDir = workspace.resolve('bin')
+    rcPath = workspace.resolve('.bashrc')
+    mockDlxDir.value = workspace.resolve('dlx')
+    mockGetCliExeTriplet.mockReturnValue('linux-x64')
+    mockGetGithubPath.mockReturnValue(undefined)
+    mockGetShellRcPaths.mockReturnValue([rcPath])
+    mockFetchNpmProvenanceData.mockResolvedValue({
+      ok: true,
+      data: {
+        attestations: [],
+        keys: [],
+        manifest: {
+          dist: {
+            integrity: INTEGRITY,
+            tarball: 'https://example.test/t.tgz',
+          },
+          ...RELEASE,
+        },
+      },
+    })
+    mockGetRegistrySignatureCheck.mockReturnValue({
+      check: 'registry signature',
+      message: 'Signed by npm registry key SHA256:abc',
+      status: 'pass',
+    })
+    mockFetchPackageTarball.mockResolvedValue({ ok: true, data: TGZ })
+    mockReadTarballFiles.mockReturnValue(
+      new Map([['bin/socket', Buffer.from('exe')]]),
+    )
+  })
+
+  afterEach(async () => {
+    process.env['PATH'] = originalPath
+    await workspace.cleanup()
+  })
+
+  it('should install a signed release and put its command on PATH', async () => {
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      updatePath: true,
+    })
+
+    const executable = getSelfInstallPath(RELEASE, 'linux-x64')
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        binDir,
+        channel: 'stable',
+        executable,
+        launcher: workspace.resolve('bin', 'socket'),
+        modifiedFiles: [rcPath],
+        onPath: false,
+        signature: 'Signed by npm registry key SHA256:abc',
+        userPathUpdated: false,
+        version: '2.1.0',
+        workflowPathUpdated: false,
+      },
+    })
+    expect(mockFetchNpmProvenanceData).toHaveBeenCalledWith(
+      '@socketsecurity/cli.exe.linux-x64',
+      'latest',
+    )
+    expect(executable.startsWith(mockDlxDir.value)).toBe(true)
+    expect(await fs.readFile(executable, 'utf8')).toBe('exe')
+    expect(await fs.readlink(workspace.resolve('bin', 'socket'))).toBe(
+      executable,
+    )
+    expect(await workspace.readFile('.bashrc')).toBe(
+      `alias ll="ls -l"\nexport PATH="${binDir}:$PATH" # Added by \`socket self install\`.\n`,
+    )
+  })
+
+  it('should name the install directory like install.sh', () => {
+    const hash = crypto
+      .createHash('sha256')
+      .update('@socketsecurity/cli.exe.linux-x64@2.1.0')
+      .digest('hex')
+
+    expect(getSelfInstallPath(RELEASE, 'linux-x64')).toBe(
+      workspace.resolve('dlx', hash, 'package', 'bin', 'socket'),
+    )
+    expect(
+      getSelfInstallPath(RELEASE, 'win32-x64').endsWith('socket.exe'),
+    ).toBe(true)
+  })
+
+  it('should install a pinned version', async () => {
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      updatePath: false,
+      version: '2.1.0',
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockFetchNpmProvenanceData).toHaveBeenCalledWith(
+      '@socketsecurity/cli.exe.linux-x64',
+      '2.1.0',
+    )
+  })
+
+  it('should install from a downloaded tarball that matches the signed integrity', async () => {
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      from: workspace.resolve('package.tgz'),
+      updatePath: false,
+      version: '2.1.0',
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockFetchPackageTarball).not.toHaveBeenCalled()
+    expect(mockReadTarballFiles).toHaveBeenCalledWith(TGZ)
+  })
+
+  it('should not install a downloaded tarball with another integrity', async () => {
+    await workspace.writeFile('package.tgz', 'tampered')
+
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      from: workspace.resolve('package.tgz'),
+      updatePath: true,
+    })
+
+    expect(!result.ok && result.message).toBe('Integrity check failed')
+    expect(mockReadTarballFiles).not.toHaveBeenCalled()
+    expect(workspace.fileExists('bin/socket')).toBe(false)
+  })
+
+  it('should not install a release without a valid signature', async () => {
+    mockGetRegistrySignatureCheck.mockReturnValue({
+      check: 'registry signature',
+      message: 'No signature verifies with a current npm registry key',
+      status: 'fail',
+    })
+
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      updatePath: true,
+    })
+
+    expect(!result.ok && result.message).toBe('Signature verification failed')
+    expect(mockFetchPackageTarball).not.toHaveBeenCalled()
+    expect(workspace.fileExists('bin/socket')).toBe(false)
+    expect(await workspace.readFile('.bashrc')).toBe('alias ll="ls -l"\n')
+  })
+
+  it('should leave the shell profiles alone without updatePath', async () => {
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      updatePath: false,
+    })
+
+    expect(result.ok && result.data.modifiedFiles).toEqual([])
+    expect(await workspace.readFile('.bashrc')).toBe('alias ll="ls -l"\n')
+  })
+
+  it('should add the command directory to GITHUB_PATH', async () => {
+    const githubPath = workspace.resolve('github-path')
+    await fs.writeFile(githubPath, '')
+    mockGetGithubPath.mockReturnValue(githubPath)
+
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      updatePath: true,
+    })
+
+    expect(result.ok && result.data.workflowPathUpdated).toBe(true)
+    expect(await fs.readFile(githubPath, 'utf8')).toBe(`${binDir}\n`)
+  })
+
+  it('should not add a command directory on PATH to GITHUB_PATH', async () => {
+    const githubPath = workspace.resolve('github-path')
+    mockGetGithubPath.mockReturnValue(githubPath)
+    process.env['PATH'] = `${binDir}:${originalPath ?? ''}`
+
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      updatePath: true,
+    })
+
+    expect(result.ok && result.data.onPath).toBe(true)
+    expect(result.ok && result.data.workflowPathUpdated).toBe(false)
+    expect(workspace.fileExists('github-path')).toBe(false)
+  })
+
+  it('should replace the launcher and PATH line of an earlier install', async () => {
+    const otherBinDir = workspace.resolve('other-bin')
+    await selfInstall({
+      binDir: otherBinDir,
+      channel: 'stable',
+      updatePath: true,
+    })
+    await workspace.writeFile('bin/socket', 'stale')
+
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      updatePath: true,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(await fs.readlink(workspace.resolve('bin', 'socket'))).toBe(
+      getSelfInstallPath(RELEASE, 'linux-x64'),
+    )
+    expect(await workspace.readFile('.bashrc')).toBe(
+      `alias ll="ls -l"\nexport PATH="${binDir}:$PATH" # Added by \`socket self install\`.\n`,
+    )
+  })
+
+  it('should fail on platforms without an executable', async () => {
+    mockGetCliExeTriplet.mockReturnValue(undefined)
+
+    const result = await selfInstall({
+      binDir,
+      channel: 'stable',
+      updatePath: true,
+    })
+
+    expect(!result.ok && result.message).toBe('Unsupported platform')
+    expect(mockFetchNpmProvenanceData).not.toHaveBeenCalled()
+  })
+})
+
+describe('renderCmdLauncher', () => {
+  it('should run the executable with all arguments', () => {
+    expect(
+      renderCmdLauncher(
+        'C:\\Users\\jo\\.socket\\_dlx\\abc\\package\\bin\\socket.exe',
+      ),
+    ).toBe(
+      '@echo off\r\nrem Added by `socket self install`.\r\n"C:\\Users\\jo\\.socket\\_dlx\\abc\\package\\bin\\socket.exe" %*\r\n',
+    )
+  })
+
+  it('should escape percent signs', () => {
+    expect(renderCmdLauncher('C:\\100%\\socket.exe')).toContain(
+      '"C:\\100%%\\socket.exe" %*',
+    )
+  })
+})
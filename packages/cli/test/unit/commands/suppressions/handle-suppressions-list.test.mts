@@ -0,0 +1,174 @@
+/**
+ * Unit tests for suppressions list handling.
+ *
+ * Purpose: Tests how `socket suppressions list` reads the policy exceptions
+ * and works out which are expired or expiring.
+ *
+ * Test Coverage: - getSuppressionEntry statuses and days left - Missing and
+ * invalid policy files - The --expiring filter - handleSuppressionsList passes
+ * the result to the output.
+ *
+ * Related Files: - src/commands/suppressions/handle-suppressions-list.mts
+ * (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockSafeReadFileSync = vi.hoisted(() => vi.fn())
+vi.mock(import('@socketsecurity/lib-stable/fs/read-file'), () => ({
+  safeReadFileSync: mockSafeReadFileSync,
+}))
+
+const mockFindSocketPolicyPathSync = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/util/policy/socket-policy.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    findSocketPolicyPathSync: mockFindSocketPolicyPathSync,
+  }),
+)
+
+const mockOutputSuppressionsList = vi.hoisted(() => vi.fn())
+vi.mock(
+  import('../../../../src/commands/suppressions/output-suppressions-list.mts'),
+  () => ({
+    outputSuppressionsList: mockOutputSuppressionsList,
+  }),
+)
+
+import {
+  getSuppressionEntry,
+  handleSuppressionsList,
+  listSuppressions,
+} from '../../../../src/commands/suppressions/handle-suppressions-list.mts'
+
+// 2026-06-15 at noon UTC.
+const NOW = Date.parse('2026-06-15T12:00:00Z')
+
+const POLICY_YAML = `version: 1
+exceptions:
+  - packages: ['pkg:npm/lodash@4.17.20']
+    alerts: [criticalCVE]
+    reason: No fixed release yet
+    expires: 2026-06-20
+  - paths: ['tools/**']
+    reason: Build tooling
+  - packages: ['pkg:npm/left-pad']
+    reason: Removed next sprint
+    expires: 2026-06-01
+`
+
+describe('getSuppressionEntry', () => {
+  const base = { alerts: [], packages: [], paths: [], reason: 'Because' }
+
+  it('treats exceptions without a date as active', () => {
+    expect(getSuppressionEntry(base, 30, NOW)).toEqual({
+      ...base,
+      status: 'active',
+    })
+  })
+
+  it('counts whole days until the expiry', () => {
+    expect(
+      getSuppressionEntry({ ...base, expires: '2026-08-01' }, 30, NOW),
+    ).toMatchObject({ daysLeft: 47, status: 'active' })
+    expect(
+      getSuppressionEntry({ ...base, expires: '2026-07-15' }, 30, NOW),
+    ).toMatchObject({ daysLeft: 30, status: 'expiring' })
+  })
+
+  it('still applies on the expiry day', () => {
+    expect(
+      getSuppressionEntry({ ...base, expires: '2026-06-15' }, 30, NOW),
+    ).toMatchObject({ daysLeft: 0, status: 'expiring' })
+  })
+
+  it('marks past dates as expired', () => {
+    expect(
+      getSuppressionEntry({ ...base, expires: '2026-06-14' }, 30, NOW),
+    ).toMatchObject({ daysLeft: -1, status: 'expired' })
+  })
+})
+
+describe('listSuppressions', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFindSocketPolicyPathSync.mockReturnValue('/repo/socket.policy.yml')
+  })
+
+  it('lists every exception', () => {
+    mockSafeReadFileSync.mockReturnValue(POLICY_YAML)
+
+    const result = listSuppressions('/repo', { now: NOW })
+
+    expect(mockFindSocketPolicyPathSync).toHaveBeenCalledWith('/repo')
+    expect(result.ok).toBe(true)
+    expect(result.ok && result.data.path).toBe('/repo/socket.policy.yml')
+    expect(
+      result.ok && result.data.suppressions.map(entry => entry.status),
+    ).toEqual(['expiring', 'active', 'expired'])
+  })
+
+  it('keeps only expired and expiring exceptions with onlyExpiring', () => {
+    mockSafeReadFileSync.mockReturnValue(POLICY_YAML)
+
+    const result = listSuppressions('/repo', {
+      expiringWithinDays: 3,
+      now: NOW,
+      onlyExpiring: true,
+    })
+
+    expect(
+      result.ok && result.data.suppressions.map(entry => entry.reason),
+    ).toEqual(['Removed next sprint'])
+  })
+
+  it('fails when there is no policy file', () => {
+    mockFindSocketPolicyPathSync.mockReturnValue(undefined)
+
+    const result = listSuppressions('/repo', { now: NOW })
+
+    expect(result).toMatchObject({ ok: false, message: 'No policy file found' })
+    expect(mockSafeReadFileSync).not.toHaveBeenCalled()
+  })
+
+  it('fails when the policy file has errors', () => {
+    mockSafeReadFileSync.mockReturnValue(
+      "exceptions:\n  - packages: ['pkg:npm/lodash']\n",
+    )
+
+    const result = listSuppressions('/repo', { now: NOW })
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.message).toBe(
+      'Invalid policy file /repo/socket.policy.yml',
+    )
+    expect(!result.ok && result.cause).toContain('exceptions[0].reason')
+  })
+})
+
+describe('handleSuppressionsList', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('passes the list to the output', async () => {
+    mockFindSocketPolicyPathSync.mockReturnValue('/repo/socket.policy.yml')
+    mockSafeReadFileSync.mockReturnValue('version: 1\n')
+
+    await handleSuppressionsList({
+      cwd: '/repo',
+      expiringWithinDays: 30,
+      onlyExpiring: false,
+      outputKind: 'json',
+    })
+
+    expect(mockOutputSuppressionsList).toHaveBeenCalledWith(
+      {
+        ok: true,
+        data: { path: '/repo/socket.policy.yml', suppressions: [] },
+      },
+      'json',
+    )
+  })
+})
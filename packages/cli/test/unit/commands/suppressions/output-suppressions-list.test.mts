@@ -0,0 +1,153 @@
+/**
+ * Unit tests for suppressions list output formatting.
+ *
+ * Purpose: Tests how `socket suppressions list` reports policy exceptions.
+ *
+ * Test Coverage: - outputSuppressionsList function - JSON output format - Text
+ * output format - Markdown output format - Exit codes - Expiry formatting.
+ *
+ * Related Files: - src/commands/suppressions/output-suppressions-list.mts
+ * (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockLogger = vi.hoisted(() => ({
+  log: vi.fn(),
+  error: vi.fn(),
+  warn: vi.fn(),
+  fail: vi.fn(),
+  success: vi.fn(),
+  info: vi.fn(),
+}))
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+vi.mock(import('../../../../src/util/error/fail-msg-with-badge.mts'), () => ({
+  failMsgWithBadge: (msg: string, cause?: string | undefined) =>
+    cause ? `${msg}: ${cause}` : msg,
+}))
+
+import {
+  formatSuppressionExpiry,
+  formatSuppressionTarget,
+  outputSuppressionsList,
+} from '../../../../src/commands/suppressions/output-suppressions-list.mts'
+
+import type { SuppressionsList } from '../../../../src/commands/suppressions/handle-suppressions-list.mts'
+import type { CResult } from '../../../../src/types.mts'
+
+const LIST: SuppressionsList = {
+  path: '/repo/socket.policy.yml',
+  suppressions: [
+    {
+      alerts: ['criticalCVE'],
+      daysLeft: 5,
+      expires: '2026-06-20',
+      packages: ['pkg:npm/lodash@4.17.20'],
+      paths: [],
+      reason: 'No fixed release yet',
+      status: 'expiring',
+    },
+    {
+      alerts: [],
+      packages: [],
+      paths: ['tools/**'],
+      reason: 'Build tooling',
+      status: 'active',
+    },
+  ],
+}
+
+describe('formatSuppressionExpiry', () => {
+  const base = {
+    alerts: [],
+    packages: [],
+    paths: [],
+    reason: 'r',
+    status: 'active' as const,
+  }
+
+  it('describes each kind of expiry', () => {
+    expect(formatSuppressionExpiry(base)).toBe('never')
+    expect(
+      formatSuppressionExpiry({ ...base, daysLeft: 0, expires: '2026-06-15' }),
+    ).toBe('2026-06-15 (today)')
+    expect(
+      formatSuppressionExpiry({ ...base, daysLeft: 1, expires: '2026-06-16' }),
+    ).toBe('2026-06-16 (in 1 day)')
+    expect(
+      formatSuppressionExpiry({ ...base, daysLeft: -3, expires: '2026-06-12' }),
+    ).toBe('2026-06-12 (expired 3 days ago)')
+  })
+
+  it('joins the exception criteria into a target', () => {
+    expect(formatSuppressionTarget(LIST.suppressions[0]!)).toBe(
+      'pkg:npm/lodash@4.17.20 / criticalCVE',
+    )
+    expect(formatSuppressionTarget(base)).toBe('everything')
+  })
+})
+
+describe('outputSuppressionsList', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  it('outputs JSON', async () => {
+    await outputSuppressionsList({ ok: true, data: LIST }, 'json')
+
+    const output = JSON.parse(mockLogger.log.mock.calls[0]![0])
+    expect(output.ok).toBe(true)
+    expect(output.data.suppressions).toHaveLength(2)
+    expect(process.exitCode).toBeUndefined()
+  })
+
+  it('warns about expiring exceptions in text output', async () => {
+    await outputSuppressionsList({ ok: true, data: LIST }, 'text')
+
+    expect(mockLogger.warn).toHaveBeenCalledWith(
+      'pkg:npm/lodash@4.17.20 / criticalCVE: No fixed release yet; expires 2026-06-20 (in 5 days)',
+    )
+    expect(mockLogger.log).toHaveBeenCalledWith(
+      '  tools/**: Build tooling; expires never',
+    )
+  })
+
+  it('reports an empty list', async () => {
+    await outputSuppressionsList(
+      { ok: true, data: { ...LIST, suppressions: [] } },
+      'text',
+    )
+
+    expect(mockLogger.log).toHaveBeenCalledWith(
+      'No matching exceptions in /repo/socket.policy.yml',
+    )
+  })
+
+  it('outputs a markdown table', async () => {
+    await outputSuppressionsList({ ok: true, data: LIST }, 'markdown')
+
+    const output = mockLogger.log.mock.calls.map(call => call[0]).join('\n')
+    expect(output).toContain('# Suppressions')
+    expect(output).toContain('| Exception')
+    expect(output).toContain('No fixed release yet')
+  })
+
+  it('sets the exit code on failure', async () => {
+    const result: CResult<SuppressionsList> = {
+      ok: false,
+      message: 'No policy file found',
+      cause: 'There is no socket.policy.yml in /repo',
+    }
+
+    await outputSuppressionsList(result, 'text')
+
+    expect(mockLogger.fail).toHaveBeenCalledWith(
+      'No policy file found: There is no socket.policy.yml in /repo',
+    )
+    expect(process.exitCode).toBe(1)
+  })
+})
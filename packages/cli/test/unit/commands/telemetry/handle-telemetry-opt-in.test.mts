@@ -0,0 +1,102 @@
+/**
+ * Unit tests for the telemetry enable and disable handler.
+ *
+ * Test Coverage: - Opting in and out through the telemetry config key -
+ * Clearing the spool on opt-out - The note when DO_NOT_TRACK or another config
+ * layer decides - Config write failures.
+ *
+ * Related Files: - src/commands/telemetry/handle-telemetry-opt-in.mts -
+ * src/util/telemetry/usage.mts - Usage telemetry.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { handleTelemetryOptIn } from '../../../../src/commands/telemetry/handle-telemetry-opt-in.mts'
+
+const mockClearUsageSpool = vi.hoisted(() => vi.fn())
+const mockGetUsageTelemetryState = vi.hoisted(() => vi.fn())
+const mockOutputTelemetryOptIn = vi.hoisted(() => vi.fn())
+const mockUpdateConfigValue = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/telemetry/output-telemetry-opt-in.mts'),
+  () => ({
+    outputTelemetryOptIn: mockOutputTelemetryOptIn,
+  }),
+)
+
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  updateConfigValue: mockUpdateConfigValue,
+}))
+
+vi.mock(import('../../../../src/util/telemetry/usage.mts'), () => ({
+  clearUsageSpool: mockClearUsageSpool,
+  getUsageTelemetryState: mockGetUsageTelemetryState,
+}))
+
+describe('handleTelemetryOptIn', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockClearUsageSpool.mockResolvedValue(undefined)
+    mockUpdateConfigValue.mockReturnValue({ ok: true, data: undefined })
+  })
+
+  it('opts in through the telemetry config key', async () => {
+    mockGetUsageTelemetryState.mockReturnValue({ enabled: true, reason: '' })
+
+    await handleTelemetryOptIn({ enabled: true, outputKind: 'text' })
+
+    expect(mockUpdateConfigValue).toHaveBeenCalledWith('telemetry', true)
+    expect(mockClearUsageSpool).not.toHaveBeenCalled()
+    expect(mockOutputTelemetryOptIn).toHaveBeenCalledWith(
+      {
+        ok: true,
+        message: 'Opted in to anonymous usage telemetry',
+        data: { enabled: true, note: undefined },
+      },
+      'text',
+    )
+  })
+
+  it('clears the spool when opting out', async () => {
+    mockGetUsageTelemetryState.mockReturnValue({ enabled: false, reason: '' })
+
+    await handleTelemetryOptIn({ enabled: false, outputKind: 'json' })
+
+    expect(mockUpdateConfigValue).toHaveBeenCalledWith('telemetry', false)
+    expect(mockClearUsageSpool).toHaveBeenCalled()
+    expect(mockOutputTelemetryOptIn).toHaveBeenCalledWith(
+      expect.objectContaining({ data: { enabled: false, note: undefined } }),
+      'json',
+    )
+  })
+
+  it('notes when DO_NOT_TRACK keeps telemetry off', async () => {
+    mockGetUsageTelemetryState.mockReturnValue({
+      enabled: false,
+      reason: 'DO_NOT_TRACK is set',
+    })
+
+    await handleTelemetryOptIn({ enabled: true, outputKind: 'text' })
+
+    expect(mockOutputTelemetryOptIn).toHaveBeenCalledWith(
+      expect.objectContaining({
+        data: {
+          enabled: false,
+          note: 'DO_NOT_TRACK is set, so telemetry is still off',
+        },
+      }),
+      'text',
+    )
+  })
+
+  it('passes config write failures on', async () => {
+    const failure = { ok: false, message: 'Config is read-only' }
+    mockUpdateConfigValue.mockReturnValue(failure)
+
+    await handleTelemetryOptIn({ enabled: false, outputKind: 'text' })
+
+    expect(mockClearUsageSpool).not.toHaveBeenCalled()
+    expect(mockOutputTelemetryOptIn).toHaveBeenCalledWith(failure, 'text')
+  })
+})
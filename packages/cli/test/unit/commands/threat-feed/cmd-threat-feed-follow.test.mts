@@ -0,0 +1,141 @@
+/**
+ * Unit tests for threat-feed command streaming.
+ *
+ * Tests the flags that tail the threat feed: --follow and its --interval,
+ * --jsonl, and the --eco and --namespace scope.
+ *
+ * Testing Approach: - Mock handleThreatFeed to verify handler invocation -
+ * Mock determineOrgSlug and hasDefaultApiToken.
+ *
+ * Related Files: - src/commands/threat-feed/cmd-threat-feed.mts -
+ * Implementation - src/commands/threat-feed/follow-threat-feed.mts - Polling -
+ * cmd-threat-feed.test.mts - The other flags.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdThreatFeed } from '../../../../src/commands/threat-feed/cmd-threat-feed.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+import type * as SdkModule from '../../../../src/util/socket/sdk.mjs'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockHandleThreatFeed = vi.hoisted(() => vi.fn())
+const mockDetermineOrgSlug = vi.hoisted(() =>
+  vi.fn().mockResolvedValue(['test-org', 'test-org']),
+)
+const mockHasDefaultApiToken = vi.hoisted(() => vi.fn().mockReturnValue(true))
+
+vi.mock(
+  import('../../../../src/commands/threat-feed/handle-threat-feed.mts'),
+  () => ({
+    handleThreatFeed: mockHandleThreatFeed,
+  }),
+)
+
+vi.mock(import('../../../../src/util/socket/org-slug.mjs'), () => ({
+  determineOrgSlug: mockDetermineOrgSlug,
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mjs'), async importOriginal => {
+  const actual = await importOriginal<typeof SdkModule>()
+  return {
+    ...actual,
+    hasDefaultApiToken: mockHasDefaultApiToken,
+  }
+})
+
+describe('cmd-threat-feed', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  describe('run', () => {
+    const importMeta = { url: 'file:///test/cmd-threat-feed.mts' }
+    const context = { parentName: 'socket' }
+
+    it('should pass --follow, --jsonl and the scope to handleThreatFeed', async () => {
+      mockHasDefaultApiToken.mockReturnValueOnce(true)
+
+      await cmdThreatFeed.run(
+        [
+          '--eco',
+          'npm,pypi',
+          '--namespace',
+          '@acme',
+          '--namespace',
+          'acme-labs',
+          '--follow',
+          '--interval',
+          '30',
+          '--jsonl',
+        ],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleThreatFeed).toHaveBeenCalledWith(
+        expect.objectContaining({
+          ecosystem: 'npm,pypi',
+          follow: true,
+          interval: 30,
+          jsonl: true,
+          namespaces: ['@acme', 'acme-labs'],
+        }),
+      )
+    })
+
+    it('should fail if --follow is combined with --json', async () => {
+      mockHasDefaultApiToken.mockReturnValueOnce(true)
+
+      await cmdThreatFeed.run(['--follow', '--json'], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleThreatFeed).not.toHaveBeenCalled()
+    })
+
+    it('should fail if --interval is too short for --follow', async () => {
+      mockHasDefaultApiToken.mockReturnValueOnce(true)
+
+      await cmdThreatFeed.run(
+        ['--follow', '--interval', '1'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleThreatFeed).not.toHaveBeenCalled()
+    })
+
+    it('should fail for unknown ecosystems in --eco', async () => {
+      mockHasDefaultApiToken.mockReturnValueOnce(true)
+
+      await cmdThreatFeed.run(['--eco', 'npm,cobol'], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleThreatFeed).not.toHaveBeenCalled()
+    })
+  })
+})
@@ -15,7 +15,8 @@
  * and defaults.
  *
  * Related Files: - src/commands/threat-feed/cmd-threat-feed.mts -
- * Implementation - src/commands/threat-feed/handle-threat-feed.mts - Handler.
+ * Implementation - src/commands/threat-feed/handle-threat-feed.mts - Handler -
+ * cmd-threat-feed-follow.test.mts - --follow, --jsonl and the scope flags.
  */
 
 import { beforeEach, describe, expect, it, vi } from 'vitest'
@@ -122,6 +123,10 @@ describe('cmd-threat-feed', () => {
         direction: 'desc',
         ecosystem: '',
         filter: '',
+        follow: false,
+        interval: 60,
+        jsonl: false,
+        namespaces: [],
         orgSlug: 'test-org',
         outputKind: 'text',
         page: '1',
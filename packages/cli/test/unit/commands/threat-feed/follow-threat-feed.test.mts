@@ -0,0 +1,152 @@
+/**
+ * Unit tests for `socket threat-feed --follow`.
+ *
+ * Test Coverage: - Printing only threats not printed before, oldest first -
+ * Catching up over several pages - JSONL output - A failing first poll ends
+ * the stream, later failures are retried.
+ *
+ * Related Files: - src/commands/threat-feed/follow-threat-feed.mts.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { followThreatFeed } from '../../../../src/commands/threat-feed/follow-threat-feed.mts'
+
+const mockFetchThreatFeed = vi.hoisted(() => vi.fn())
+const mockLogger = vi.hoisted(() => ({
+  fail: vi.fn(),
+  log: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('../../../../src/commands/threat-feed/fetch-threat-feed.mts'),
+  () => ({
+    fetchThreatFeed: mockFetchThreatFeed,
+  }),
+)
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+function threat(id: number, name = `pkg-${id}`) {
+  return {
+    createdAt: `2026-10-0${id}T00:00:00Z`,
+    description: '',
+    id,
+    locationHtmlUrl: '',
+    packageHtmlUrl: `https://socket.dev/npm/package/${name}`,
+    purl: `pkg:npm/${name}@1.0.0`,
+    removedAt: null,
+    threatType: 'malware',
+  }
+}
+
+function page(ids: number[], nextPage = '') {
+  return { ok: true, data: { results: ids.map(id => threat(id)), nextPage } }
+}
+
+// Resolve the fetches in order, aborting once they run out.
+function mockPolls(controller: AbortController, responses: unknown[]) {
+  for (const response of responses) {
+    mockFetchThreatFeed.mockResolvedValueOnce(response)
+  }
+  mockFetchThreatFeed.mockImplementation(async () => {
+    controller.abort()
+    return page([])
+  })
+}
+
+const OPTIONS = {
+  filter: 'mal',
+  interval: 0,
+  jsonl: false,
+  orgSlug: 'test-org',
+  perPage: 3,
+  pkg: '',
+  scope: { ecosystems: ['npm'], namespaces: [] },
+  version: '',
+}
+
+describe('followThreatFeed', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('prints new threats oldest first and catches up over pages', async () => {
+    const controller = new AbortController()
+    mockPolls(controller, [
+      page([2, 1], 'p2'),
+      // Five threats were published since, the second page reaches a seen one.
+      page([7, 6, 5], 'p2'),
+      page([4, 3, 2], 'p3'),
+    ])
+
+    const result = await followThreatFeed({
+      ...OPTIONS,
+      signal: controller.signal,
+    })
+
+    expect(result).toEqual({ ok: true, data: undefined })
+    expect(mockFetchThreatFeed.mock.calls.map(c => c[0].page)).toEqual([
+      '1',
+      '1',
+      'p2',
+      '1',
+    ])
+    expect(mockFetchThreatFeed).toHaveBeenCalledWith(
+      expect.objectContaining({ direction: 'desc', ecosystem: 'npm' }),
+    )
+    const printed = mockLogger.log.mock.calls.flatMap(c =>
+      String(c[0]).split('\n'),
+    )
+    expect(printed.map(line => line.split('  ')[2])).toEqual(
+      [1, 2, 3, 4, 5, 6, 7].map(id => `pkg:npm/pkg-${id}@1.0.0`),
+    )
+  })
+
+  it('prints JSONL narrowed to the scope', async () => {
+    const controller = new AbortController()
+    mockPolls(controller, [
+      {
+        ok: true,
+        data: {
+          results: [threat(2, '%40acme/ui'), threat(1, 'left-pad')],
+          nextPage: '',
+        },
+      },
+    ])
+
+    await followThreatFeed({
+      ...OPTIONS,
+      jsonl: true,
+      scope: { ecosystems: [], namespaces: ['@acme'] },
+      signal: controller.signal,
+    })
+
+    expect(mockLogger.log).toHaveBeenCalledOnce()
+    expect(JSON.parse(mockLogger.log.mock.calls[0]![0])).toMatchObject({
+      id: 2,
+      purl: 'pkg:npm/%40acme/ui@1.0.0',
+    })
+  })
+
+  it('returns a failing first poll and retries later ones', async () => {
+    const failure = { ok: false, message: 'Socket API error', code: 401 }
+    mockFetchThreatFeed.mockResolvedValueOnce(failure)
+
+    expect(await followThreatFeed(OPTIONS)).toEqual(failure)
+
+    const controller = new AbortController()
+    mockPolls(controller, [page([1]), failure, page([2, 1])])
+
+    expect(
+      await followThreatFeed({ ...OPTIONS, signal: controller.signal }),
+    ).toEqual({ ok: true, data: undefined })
+    expect(mockLogger.warn).toHaveBeenCalledWith(
+      expect.stringContaining('Polling the threat feed failed'),
+    )
+    expect(mockLogger.log).toHaveBeenCalledTimes(2)
+  })
+})
@@ -87,7 +87,7 @@ describe('handleThreatFeed', () => {
       pkg: '',
       version: '',
     })
-    expect(outputThreatFeed).toHaveBeenCalledWith(mockData, 'json')
+    expect(outputThreatFeed).toHaveBeenCalledWith(mockData, 'json', false)
   })
 
   it('handles fetch failure', async () => {
@@ -114,7 +114,7 @@ describe('handleThreatFeed', () => {
       version: '',
     })
 
-    expect(outputThreatFeed).toHaveBeenCalledWith(mockError, 'text')
+    expect(outputThreatFeed).toHaveBeenCalledWith(mockError, 'text', false)
   })
 
   it('handles specific package and version filter', async () => {
@@ -180,7 +180,7 @@ describe('handleThreatFeed', () => {
       version: '',
     })
 
-    expect(outputThreatFeed).toHaveBeenCalledWith(mockData, 'markdown')
+    expect(outputThreatFeed).toHaveBeenCalledWith(mockData, 'markdown', false)
   })
 
   it('handles different ecosystems', async () => {
@@ -302,6 +302,6 @@ describe('handleThreatFeed', () => {
       version: '',
     })
 
-    expect(outputThreatFeed).toHaveBeenCalledWith(mockData, 'text')
+    expect(outputThreatFeed).toHaveBeenCalledWith(mockData, 'text', false)
   })
 })
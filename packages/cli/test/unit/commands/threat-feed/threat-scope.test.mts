@@ -0,0 +1,93 @@
+/**
+ * Unit tests for narrowing the threat feed to ecosystems and namespaces.
+ *
+ * Test Coverage: - Parsing the --eco list - The ecosystem sent to the API -
+ * Namespace matching, nested namespaces and npm scopes without `@`.
+ *
+ * Related Files: - src/commands/threat-feed/threat-scope.mts.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  filterThreatResults,
+  getApiEcosystem,
+  matchesThreatScope,
+  parseEcosystems,
+} from '../../../../src/commands/threat-feed/threat-scope.mts'
+
+function threat(id: number, purl: string) {
+  return {
+    createdAt: '2026-10-01T00:00:00Z',
+    description: '',
+    id,
+    locationHtmlUrl: '',
+    packageHtmlUrl: '',
+    purl,
+    removedAt: null,
+    threatType: 'malware',
+  }
+}
+
+describe('threat scope', () => {
+  it('parses a comma-separated list of ecosystems', () => {
+    expect(parseEcosystems('')).toEqual([])
+    expect(parseEcosystems('npm, pypi,')).toEqual(['npm', 'pypi'])
+  })
+
+  it('asks the API for the only ecosystem in scope', () => {
+    expect(getApiEcosystem({ ecosystems: ['npm'], namespaces: [] })).toBe(
+      'npm',
+    )
+    expect(
+      getApiEcosystem({ ecosystems: ['npm', 'pypi'], namespaces: [] }),
+    ).toBe('')
+  })
+
+  it('matches namespaces and the namespaces nested in them', () => {
+    const scope = {
+      ecosystems: [],
+      namespaces: ['acme', 'org.example', 'github.com/acme'],
+    }
+
+    expect(
+      matchesThreatScope(threat(1, 'pkg:npm/%40acme/ui@1.0.0'), scope),
+    ).toBe(true)
+    expect(
+      matchesThreatScope(
+        threat(2, 'pkg:maven/org.example.util/core@2.0.0'),
+        scope,
+      ),
+    ).toBe(true)
+    expect(
+      matchesThreatScope(
+        threat(3, 'pkg:golang/github.com/acme/tools/cli@v1.0.0'),
+        scope,
+      ),
+    ).toBe(true)
+    expect(
+      matchesThreatScope(threat(4, 'pkg:maven/org.examples/core@1.0.0'), scope),
+    ).toBe(false)
+    expect(matchesThreatScope(threat(5, 'pkg:npm/acme@1.0.0'), scope)).toBe(
+      false,
+    )
+  })
+
+  it('filters by several ecosystems and leaves unnarrowed results alone', () => {
+    const results = [
+      threat(1, 'pkg:npm/left-pad@1.0.0'),
+      threat(2, 'pkg:pypi/requests@2.0.0'),
+      threat(3, 'pkg:gem/rails@7.0.0'),
+    ]
+
+    expect(
+      filterThreatResults(results, {
+        ecosystems: ['npm', 'pypi'],
+        namespaces: [],
+      }).map(r => r.id),
+    ).toEqual([1, 2])
+    expect(
+      filterThreatResults(results, { ecosystems: ['gem'], namespaces: [] }),
+    ).toBe(results)
+  })
+})
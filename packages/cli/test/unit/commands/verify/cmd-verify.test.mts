@@ -0,0 +1,128 @@
+/**
+ * Unit tests for the verify command.
+ *
+ * Tests the command that checks the provenance attestation and signatures of
+ * a published npm package version.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdVerify } from '../../../../src/commands/verify/cmd-verify.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockHandleVerify = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/commands/verify/handle-verify.mts'), () => ({
+  handleVerify: mockHandleVerify,
+}))
+
+describe('cmd-verify', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+  })
+
+  describe('command metadata', () => {
+    it('should have correct description', () => {
+      expect(cmdVerify.description).toBe(
+        'Verify the provenance and signatures of a published package',
+      )
+    })
+
+    it('should not be hidden', () => {
+      expect(cmdVerify.hidden).toBe(false)
+    })
+  })
+
+  describe('run', () => {
+    const importMeta = { url: 'file:///test/cmd-verify.mts' }
+    const context = { parentName: 'socket' }
+
+    it('should support --dry-run flag', async () => {
+      await cmdVerify.run(
+        ['--dry-run', 'pkg:npm/sigstore@3.0.0'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleVerify).not.toHaveBeenCalled()
+      expect(mockLogger.error).toHaveBeenCalledWith(
+        expect.stringContaining('DryRun'),
+      )
+    })
+
+    it('should fail without a purl', async () => {
+      await cmdVerify.run([], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleVerify).not.toHaveBeenCalled()
+    })
+
+    it('should reject other ecosystems', async () => {
+      await cmdVerify.run(['pkg:pypi/requests@2.31.0'], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleVerify).not.toHaveBeenCalled()
+    })
+
+    it('should reject more than one purl', async () => {
+      await cmdVerify.run(
+        ['pkg:npm/a@1.0.0', 'pkg:npm/b@1.0.0'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleVerify).not.toHaveBeenCalled()
+    })
+
+    it('should call handleVerify with the package version', async () => {
+      await cmdVerify.run(['pkg:npm/sigstore@3.0.0'], importMeta, context)
+
+      expect(mockHandleVerify).toHaveBeenCalledWith({
+        name: 'sigstore',
+        outputKind: 'text',
+        requireProvenance: false,
+        version: '3.0.0',
+      })
+    })
+
+    it('should pass scoped names and --require-provenance', async () => {
+      await cmdVerify.run(
+        ['npm/%40sigstore/cli', '--require-provenance', '--json'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleVerify).toHaveBeenCalledWith({
+        name: '@sigstore/cli',
+        outputKind: 'json',
+        requireProvenance: true,
+        version: undefined,
+      })
+    })
+  })
+})
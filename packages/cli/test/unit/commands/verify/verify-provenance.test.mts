@@ -0,0 +1,323 @@
+/**
+ * Unit tests for npm provenance verification.
+ *
+ * Purpose: Tests the checks `socket verify` runs on a package version's
+ * registry data and the Socket cross-reference.
+ *
+ * Test Coverage: - Registry signature verification - Expired registry keys
+ * - Missing and unreadable provenance - Subject, workflow and repository
+ * mismatches - Socket alert cross-reference - Socket lookup failures.
+ *
+ * Related Files: - src/commands/verify/verify-provenance.mts (implementation)
+ * - src/commands/verify/provenance-attestation.mts (attestation checks)
+ * - src/commands/verify/registry-signature.mts (registry signatures)
+ * - src/commands/verify/fetch-npm-provenance.mts (registry data)
+ */
+
+import { generateKeyPairSync, sign } from 'node:crypto'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  getProvenanceReport,
+  getSocketMetadataCheck,
+  verifyNpmPackage,
+} from '../../../../src/commands/verify/verify-provenance.mts'
+
+import type {
+  NpmAttestation,
+  NpmProvenanceData,
+} from '../../../../src/commands/verify/fetch-npm-provenance.mts'
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+const mockFetchNpmProvenanceData = vi.hoisted(() => vi.fn())
+const mockFetchPurlsShallowScore = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/verify/fetch-npm-provenance.mts'),
+  () => ({
+    fetchNpmProvenanceData: mockFetchNpmProvenanceData,
+  }),
+)
+
+vi.mock(
+  import('../../../../src/commands/package/fetch-purls-shallow-score.mts'),
+  () => ({
+    fetchPurlsShallowScore: mockFetchPurlsShallowScore,
+  }),
+)
+
+const INTEGRITY = `sha512-${Buffer.alloc(64, 1).toString('base64')}`
+
+function getData(
+  overrides?: Partial<NpmProvenanceData> | undefined,
+): NpmProvenanceData {
+  return {
+    attestations: [],
+    keys: [],
+    manifest: {
+      dist: { integrity: INTEGRITY },
+      name: 'pkg',
+      repository: { type: 'git', url: 'git+https://github.com/owner/pkg.git' },
+      version: '1.0.0',
+    },
+    ...overrides,
+  }
+}
+
+function getAttestation(statement: unknown): NpmAttestation {
+  return {
+    bundle: {
+      dsseEnvelope: {
+        payload: Buffer.from(JSON.stringify(statement)).toString('base64'),
+        payloadType: 'application/vnd.in-toto+json',
+        signatures: [{ sig: 'c2ln' }],
+      },
+    },
+    predicateType: 'https://slsa.dev/provenance/v1',
+  }
+}
+
+function getCheck(
+  report: ReturnType<typeof getProvenanceReport>,
+  check: string,
+) {
+  return report.checks.find(c => c.check === check)
+}
+
+describe('getProvenanceReport', () => {
+  it('warns about missing provenance by default', () => {
+    const report = getProvenanceReport(getData())
+
+    expect(getCheck(report, 'provenance')).toMatchObject({ status: 'warn' })
+    expect(report.repository).toBe('github.com/owner/pkg')
+    expect(report.purl).toBe('pkg:npm/pkg@1.0.0')
+    expect(report.verified).toBe(true)
+  })
+
+  it('fails missing provenance with requireProvenance', () => {
+    const report = getProvenanceReport(getData(), { requireProvenance: true })
+
+    expect(getCheck(report, 'provenance')).toMatchObject({ status: 'fail' })
+    expect(report.verified).toBe(false)
+  })
+
+  it('verifies registry signatures against the registry keys', () => {
+    const { privateKey, publicKey } = generateKeyPairSync('ec', {
+      namedCurve: 'P-256',
+    })
+    const data = getData({
+      keys: [
+        {
+          expires: null,
+          key: publicKey
+            .export({ format: 'der', type: 'spki' })
+            .toString('base64'),
+          keyid: 'SHA256:test',
+          keytype: 'ecdsa-sha2-nistp256',
+          scheme: 'ecdsa-sha2-nistp256',
+        },
+      ],
+    })
+    const sig = sign(
+      'sha256',
+      Buffer.from(`pkg@1.0.0:${INTEGRITY}`),
+      privateKey,
+    ).toString('base64')
+
+    data.manifest.dist!.signatures = [{ keyid: 'SHA256:test', sig }]
+    expect(
+      getCheck(getProvenanceReport(data), 'registry-signature'),
+    ).toMatchObject({ status: 'pass' })
+
+    data.manifest.dist!.integrity = `sha512-${Buffer.alloc(64, 2).toString(
+      'base64',
+    )}`
+    expect(
+      getCheck(getProvenanceReport(data), 'registry-signature'),
+    ).toMatchObject({ status: 'fail' })
+  })
+
+  it('fails signatures of expired registry keys', () => {
+    const { privateKey, publicKey } = generateKeyPairSync('ec', {
+      namedCurve: 'P-256',
+    })
+    const data = getData({
+      keys: [
+        {
+          expires: '2025-01-29T00:00:00.000Z',
+          key: publicKey
+            .export({ format: 'der', type: 'spki' })
+            .toString('base64'),
+          keyid: 'SHA256:expired',
+          keytype: 'ecdsa-sha2-nistp256',
+          scheme: 'ecdsa-sha2-nistp256',
+        },
+      ],
+    })
+    const sig = sign(
+      'sha256',
+      Buffer.from(`pkg@1.0.0:${INTEGRITY}`),
+      privateKey,
+    ).toString('base64')
+    data.manifest.dist!.signatures = [{ keyid: 'SHA256:expired', sig }]
+
+    expect(
+      getCheck(getProvenanceReport(data), 'registry-signature'),
+    ).toMatchObject({
+      message:
+        'Signed by the npm registry key SHA256:expired, which expired on 2025-01-29T00:00:00.000Z',
+      status: 'fail',
+    })
+  })
+
+  it('warns about signatures from unknown registry keys', () => {
+    const data = getData()
+    data.manifest.dist!.signatures = [{ keyid: 'SHA256:old', sig: 'c2ln' }]
+
+    expect(
+      getCheck(getProvenanceReport(data), 'registry-signature'),
+    ).toMatchObject({ status: 'warn' })
+  })
+
+  it('checks the attestation against the manifest', () => {
+    const report = getProvenanceReport(
+      getData({
+        attestations: [
+          getAttestation({
+            predicate: {
+              buildDefinition: {
+                externalParameters: {
+                  workflow: {
+                    path: '.github/workflows/release.yml',
+                    ref: 'refs/heads/main',
+                    repository: 'https://github.com/attacker/pkg',
+                  },
+                },
+              },
+            },
+            predicateType: 'https://slsa.dev/provenance/v1',
+            subject: [
+              {
+                digest: { sha512: Buffer.alloc(64, 2).toString('hex') },
+                name: 'pkg:npm/pkg@1.0.0',
+              },
+            ],
+          }),
+        ],
+      }),
+    )
+
+    expect(report.provenance).toMatchObject({
+      repository: 'https://github.com/attacker/pkg',
+    })
+    expect(getCheck(report, 'provenance')).toMatchObject({ status: 'pass' })
+    expect(getCheck(report, 'provenance-signature')).toMatchObject({
+      status: 'fail',
+    })
+    expect(getCheck(report, 'provenance-subject')).toMatchObject({
+      message: 'The attested digest does not match the package integrity',
+      status: 'fail',
+    })
+    expect(getCheck(report, 'source-repository')).toMatchObject({
+      message: expect.stringContaining('github.com/attacker/pkg'),
+      status: 'fail',
+    })
+    expect(report.verified).toBe(false)
+  })
+
+  it('fails attestations that cannot be read', () => {
+    const attestation = getAttestation({})
+    attestation.bundle.dsseEnvelope!.payload = 'bm90IGpzb24='
+
+    const report = getProvenanceReport(
+      getData({ attestations: [attestation] }),
+    )
+
+    expect(getCheck(report, 'provenance')).toMatchObject({
+      message: 'The provenance attestation could not be read',
+      status: 'fail',
+    })
+  })
+})
+
+describe('getSocketMetadataCheck', () => {
+  it('fails on manifest confusion', () => {
+    expect(
+      getSocketMetadataCheck({
+        alerts: [{ type: 'manifestConfusion' }],
+      } as SocketArtifact),
+    ).toMatchObject({ status: 'fail' })
+  })
+
+  it('warns on ownership changes', () => {
+    expect(
+      getSocketMetadataCheck({
+        alerts: [{ type: 'newAuthor' }, { type: 'newAuthor' }],
+      } as SocketArtifact),
+    ).toMatchObject({ message: 'Socket reports newAuthor', status: 'warn' })
+  })
+
+  it('passes without ownership or manifest alerts', () => {
+    expect(
+      getSocketMetadataCheck({
+        alerts: [{ type: 'envVars' }],
+      } as SocketArtifact),
+    ).toMatchObject({ status: 'pass' })
+    expect(getSocketMetadataCheck(undefined)).toMatchObject({ status: 'warn' })
+  })
+})
+
+describe('verifyNpmPackage', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('returns registry lookup failures', async () => {
+    mockFetchNpmProvenanceData.mockResolvedValue({
+      ok: false,
+      message: 'Package not found',
+    })
+
+    const result = await verifyNpmPackage('pkg', '9.9.9')
+
+    expect(result.ok).toBe(false)
+    expect(mockFetchPurlsShallowScore).not.toHaveBeenCalled()
+  })
+
+  it('adds the Socket cross-reference', async () => {
+    mockFetchNpmProvenanceData.mockResolvedValue({ ok: true, data: getData() })
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [{ alerts: [{ type: 'manifestConfusion' }] }],
+    })
+
+    const result = await verifyNpmPackage('pkg', '1.0.0', {
+      commandPath: 'socket verify',
+    })
+
+    expect(mockFetchPurlsShallowScore).toHaveBeenCalledWith(
+      ['pkg:npm/pkg@1.0.0'],
+      { commandPath: 'socket verify' },
+    )
+    expect(result.ok && result.data.verified).toBe(false)
+  })
+
+  it('only warns when Socket cannot be reached', async () => {
+    mockFetchNpmProvenanceData.mockResolvedValue({ ok: true, data: getData() })
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: false,
+      message: 'Socket API error',
+      cause: 'Unauthorized',
+    })
+
+    const result = await verifyNpmPackage('pkg')
+
+    expect(result.ok && result.data.checks.at(-1)).toEqual({
+      check: 'socket-metadata',
+      message: 'Skipped: Unauthorized',
+      status: 'warn',
+    })
+    expect(result.ok && result.data.verified).toBe(true)
+  })
+})
@@ -0,0 +1,121 @@
+/**
+ * Unit tests for the version command.
+ *
+ * Tests the command that shows the Socket CLI version and checks the release
+ * channels for a newer one.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { cmdVersion } from '../../../../src/commands/version/cmd-version.mts'
+
+import type * as LoggerModule from '@socketsecurity/lib-stable/logger/default'
+
+// Mock the logger.
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/logger/default'),
+  async importOriginal => {
+    const actual = await importOriginal<typeof LoggerModule>()
+    return {
+      ...actual,
+      getDefaultLogger: () => mockLogger,
+    }
+  },
+)
+
+// Mock dependencies.
+const mockGetCliVersion = vi.hoisted(() => vi.fn())
+const mockHandleVersion = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/env/cli-version.mts'), () => ({
+  getCliVersion: mockGetCliVersion,
+}))
+
+vi.mock(import('../../../../src/commands/version/handle-version.mts'), () => ({
+  handleVersion: mockHandleVersion,
+}))
+
+describe('cmd-version', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.exitCode = undefined
+    mockGetCliVersion.mockReturnValue('2.0.0')
+  })
+
+  describe('command metadata', () => {
+    it('should have correct description', () => {
+      expect(cmdVersion.description).toBe(
+        'Show the Socket CLI version and check for updates',
+      )
+    })
+
+    it('should not be hidden', () => {
+      expect(cmdVersion.hidden).toBe(false)
+    })
+  })
+
+  describe('run', () => {
+    const importMeta = { url: 'file:///test/cmd-version.mts' }
+    const context = { parentName: 'socket' }
+
+    it('should show the version without checking', async () => {
+      await cmdVersion.run([], importMeta, context)
+
+      expect(mockHandleVersion).toHaveBeenCalledWith({
+        channel: 'stable',
+        check: false,
+        outputKind: 'text',
+      })
+    })
+
+    it('should support --dry-run flag with --check', async () => {
+      await cmdVersion.run(['--check', '--dry-run'], importMeta, context)
+
+      expect(mockHandleVersion).not.toHaveBeenCalled()
+      expect(mockLogger.error).toHaveBeenCalledWith(
+        expect.stringContaining('DryRun'),
+      )
+    })
+
+    it('should pass --check and --channel', async () => {
+      await cmdVersion.run(
+        ['--check', '--channel', 'beta', '--json'],
+        importMeta,
+        context,
+      )
+
+      expect(mockHandleVersion).toHaveBeenCalledWith({
+        channel: 'beta',
+        check: true,
+        outputKind: 'json',
+      })
+    })
+
+    it('should reject --channel without --check', async () => {
+      await cmdVersion.run(['--channel', 'beta'], importMeta, context)
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleVersion).not.toHaveBeenCalled()
+    })
+
+    it('should reject unknown channels', async () => {
+      await cmdVersion.run(
+        ['--check', '--channel', 'nightly'],
+        importMeta,
+        context,
+      )
+
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleVersion).not.toHaveBeenCalled()
+    })
+  })
+})
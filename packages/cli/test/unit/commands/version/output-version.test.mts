@@ -0,0 +1,53 @@
+/**
+ * Unit tests for the version command output.
+ *
+ * Tests the update hint `socket version --check` prints for standalone
+ * executables and package manager installs.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { getUpdateHint } from '../../../../src/commands/version/output-version.mts'
+
+import type { VersionInfo } from '../../../../src/commands/version/handle-version.mts'
+
+function getInfo(overrides?: Partial<VersionInfo> | undefined): VersionInfo {
+  return {
+    channel: 'stable',
+    install: 'standalone',
+    latest: '2.1.0',
+    updateAvailable: true,
+    version: '2.0.0',
+    ...overrides,
+  }
+}
+
+describe('getUpdateHint', () => {
+  it('should suggest self-update for standalone executables', () => {
+    expect(getUpdateHint(getInfo())).toBe('socket self-update')
+    expect(getUpdateHint(getInfo({ channel: 'beta' }))).toBe(
+      'socket self-update --channel beta',
+    )
+  })
+
+  it('should suggest npm for package manager installs', () => {
+    expect(getUpdateHint(getInfo({ install: 'npm' }))).toMatch(
+      /^npm install -g \S+@latest$/,
+    )
+    expect(getUpdateHint(getInfo({ channel: 'beta', install: 'npm' }))).toMatch(
+      /@beta$/,
+    )
+  })
+
+  it('should not suggest anything without an update', () => {
+    expect(getUpdateHint(getInfo({ updateAvailable: false }))).toBeUndefined()
+  })
+
+  it('should not suggest anything when updates are turned off', () => {
+    expect(
+      getUpdateHint(
+        getInfo({ disabledReason: 'SOCKET_CLI_NO_SELF_UPDATE is set' }),
+      ),
+    ).toBeUndefined()
+  })
+})
@@ -199,6 +199,8 @@ describe('cmd-whoami', () => {
         const result = JSON.parse(jsonOutput)
 
         expect(result).toEqual({
+          version: '1',
+          command: 'whoami',
           ok: true,
           data: {
             authenticated: true,
@@ -240,6 +242,8 @@ describe('cmd-whoami', () => {
         const result = JSON.parse(jsonOutput)
 
         expect(result).toEqual({
+          version: '1',
+          command: 'whoami',
           ok: true,
           data: {
             authenticated: true,
@@ -283,6 +287,8 @@ describe('cmd-whoami', () => {
         const result = JSON.parse(jsonOutput)
 
         expect(result).toEqual({
+          version: '1',
+          command: 'whoami',
           ok: true,
           data: {
             authenticated: false,
@@ -0,0 +1,130 @@
+/**
+ * Unit tests for the dependency chains of `socket why`.
+ *
+ * Tests reading package queries, walking the scanned dependency graph from
+ * the queried package up to the direct dependencies, and the fallback to top
+ * level ancestors for scans without a graph.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getWhyResult,
+  parsePackageQuery,
+} from '../../../../src/commands/why/dependency-chains.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+function getScan(): SocketArtifact[] {
+  return [
+    artifact('express', {
+      id: '1',
+      version: '4.18.2',
+      direct: true,
+      dependencies: ['2', '3'],
+    }),
+    artifact('body-parser', {
+      id: '2',
+      version: '1.20.1',
+      dependencies: ['4'],
+    }),
+    artifact('send', { id: '3', version: '0.18.0', dependencies: ['4', '5'] }),
+    artifact('lodash', { id: '4', version: '4.17.20', dependencies: ['2'] }),
+    artifact('lodash', { id: '5', version: '4.17.21' }),
+    artifact('webpack', {
+      id: '6',
+      version: '5.89.0',
+      direct: true,
+      dependencies: ['4'],
+    }),
+  ]
+}
+
+describe('parsePackageQuery', () => {
+  it('reads names, versions and purls', () => {
+    expect(parsePackageQuery('lodash@4.17.20')).toEqual({
+      name: 'lodash',
+      version: '4.17.20',
+    })
+    expect(parsePackageQuery('@babel/core')).toEqual({ name: '@babel/core' })
+    expect(parsePackageQuery('pkg:npm/%40babel/core@7.0.0')).toEqual({
+      name: '@babel/core',
+      type: 'npm',
+      version: '7.0.0',
+    })
+    expect(parsePackageQuery('')).toBeUndefined()
+  })
+})
+
+describe('getWhyResult', () => {
+  it('lists every chain from a direct dependency', () => {
+    const result = getWhyResult(getScan(), 'lodash@4.17.20', 'scan-ai-dee')
+
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        scanId: 'scan-ai-dee',
+        query: 'lodash@4.17.20',
+        hasGraph: true,
+        packages: [
+          {
+            purl: 'pkg:npm/lodash@4.17.20',
+            direct: false,
+            chains: [
+              [
+                'pkg:npm/express@4.18.2',
+                'pkg:npm/body-parser@1.20.1',
+                'pkg:npm/lodash@4.17.20',
+              ],
+              [
+                'pkg:npm/express@4.18.2',
+                'pkg:npm/send@0.18.0',
+                'pkg:npm/lodash@4.17.20',
+              ],
+              ['pkg:npm/webpack@5.89.0', 'pkg:npm/lodash@4.17.20'],
+            ],
+            truncated: false,
+          },
+        ],
+        directDependencies: [
+          'pkg:npm/express@4.18.2',
+          'pkg:npm/webpack@5.89.0',
+        ],
+      },
+    })
+  })
+
+  it('traces every version without a version in the query', () => {
+    const result = getWhyResult(getScan(), 'lodash', 'scan-ai-dee')
+
+    expect(result.ok && result.data.packages.map(p => p.purl)).toEqual([
+      'pkg:npm/lodash@4.17.20',
+      'pkg:npm/lodash@4.17.21',
+    ])
+  })
+
+  it('falls back to the top level ancestors without a graph', () => {
+    const scan = [
+      artifact('express', { id: '1', version: '4.18.2', direct: true }),
+      artifact('lodash', {
+        id: '4',
+        version: '4.17.20',
+        topLevelAncestors: ['1'],
+      }),
+    ]
+    const result = getWhyResult(scan, 'lodash', 'scan-ai-dee')
+
+    expect(result.ok && result.data.hasGraph).toBe(false)
+    expect(result.ok && result.data.packages[0]!.chains).toEqual([
+      ['pkg:npm/express@4.18.2', 'pkg:npm/lodash@4.17.20'],
+    ])
+  })
+
+  it('fails for packages that are not in the scan', () => {
+    expect(getWhyResult(getScan(), 'left-pad', 'scan-ai-dee')).toMatchObject({
+      ok: false,
+      message: 'Package not found',
+    })
+  })
+})
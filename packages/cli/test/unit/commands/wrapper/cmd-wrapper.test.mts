@@ -1,16 +1,14 @@
 /**
  * Unit tests for wrapper command.
  *
- * Tests the command that enables/disables the Socket npm/npx wrapper.
+ * Tests the command that enables/disables the Socket npm/npx wrapper shims
+ * and shows their status.
  */
 
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 import { cmdWrapper } from '../../../../src/commands/wrapper/cmd-wrapper.mts'
 
-import type * as FsModule from 'node:fs'
-import type * as PathsModule from '../../../../src/constants/paths.mts'
-
 // Mock the logger.
 const mockLogger = vi.hoisted(() => ({
   error: vi.fn(),
@@ -30,48 +28,14 @@ vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
 }))
 
 // Mock dependencies.
-const mockAddSocketWrapper = vi.hoisted(() => vi.fn())
-const mockRemoveSocketWrapper = vi.hoisted(() => vi.fn())
-const mockCheckSocketWrapperSetup = vi.hoisted(() =>
-  vi.fn().mockReturnValue(false),
-)
+const mockHandleWrapperChange = vi.hoisted(() => vi.fn())
+const mockHandleWrapperStatus = vi.hoisted(() => vi.fn())
 const mockPostinstallWrapper = vi.hoisted(() => vi.fn())
-const mockExistsSync = vi.hoisted(() => vi.fn().mockReturnValue(true))
-const mockGetBashRcPath = vi.hoisted(() =>
-  vi.fn().mockReturnValue('/home/user/.bashrc'),
-)
-const mockGetZshRcPath = vi.hoisted(() =>
-  vi.fn().mockReturnValue('/home/user/.zshrc'),
-)
-
-vi.mock(import('node:fs'), async importOriginal => {
-  const actual = await importOriginal<typeof FsModule>()
-  return {
-    ...actual,
-    existsSync: mockExistsSync,
-  }
-})
 
-vi.mock(
-  import('../../../../src/commands/wrapper/add-socket-wrapper.mts'),
-  () => ({
-    addSocketWrapper: mockAddSocketWrapper,
-  }),
-)
-
-vi.mock(
-  import('../../../../src/commands/wrapper/remove-socket-wrapper.mts'),
-  () => ({
-    removeSocketWrapper: mockRemoveSocketWrapper,
-  }),
-)
-
-vi.mock(
-  import('../../../../src/commands/wrapper/check-socket-wrapper-setup.mts'),
-  () => ({
-    checkSocketWrapperSetup: mockCheckSocketWrapperSetup,
-  }),
-)
+vi.mock(import('../../../../src/commands/wrapper/handle-wrapper.mts'), () => ({
+  handleWrapperChange: mockHandleWrapperChange,
+  handleWrapperStatus: mockHandleWrapperStatus,
+}))
 
 vi.mock(
   import('../../../../src/commands/wrapper/postinstall-wrapper.mts'),
@@ -80,21 +44,10 @@ vi.mock(
   }),
 )
 
-vi.mock(import('../../../../src/constants/paths.mts'), async importOriginal => {
-  const actual = await importOriginal<typeof PathsModule>()
-  return {
-    ...actual,
-    getBashRcPath: mockGetBashRcPath,
-    getZshRcPath: mockGetZshRcPath,
-  }
-})
-
 describe('cmd-wrapper', () => {
   beforeEach(() => {
     vi.clearAllMocks()
     process.exitCode = undefined
-    mockExistsSync.mockReturnValue(true)
-    mockCheckSocketWrapperSetup.mockReturnValue(false)
   })
 
   describe('command metadata', () => {
@@ -116,8 +69,7 @@ describe('cmd-wrapper', () => {
     it('should support --dry-run flag with enable', async () => {
       await cmdWrapper.run(['on', '--dry-run'], importMeta, context)
 
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-      expect(mockRemoveSocketWrapper).not.toHaveBeenCalled()
+      expect(mockHandleWrapperChange).not.toHaveBeenCalled()
       expect(mockLogger.error).toHaveBeenCalledWith(
         expect.stringContaining('DryRun'),
       )
@@ -126,168 +78,101 @@ describe('cmd-wrapper', () => {
     it('should support --dry-run flag with disable', async () => {
       await cmdWrapper.run(['off', '--dry-run'], importMeta, context)
 
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-      expect(mockRemoveSocketWrapper).not.toHaveBeenCalled()
+      expect(mockHandleWrapperChange).not.toHaveBeenCalled()
       expect(mockLogger.error).toHaveBeenCalledWith(
         expect.stringContaining('DryRun'),
       )
     })
 
-    it('should fail without on/off argument', async () => {
+    it('should fail without an action', async () => {
       await cmdWrapper.run([], importMeta, context)
 
       expect(process.exitCode).toBe(2)
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-      expect(mockRemoveSocketWrapper).not.toHaveBeenCalled()
+      expect(mockHandleWrapperChange).not.toHaveBeenCalled()
     })
 
     it('should fail with invalid argument', async () => {
       await cmdWrapper.run(['invalid'], importMeta, context)
 
       expect(process.exitCode).toBe(2)
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-      expect(mockRemoveSocketWrapper).not.toHaveBeenCalled()
+      expect(mockHandleWrapperChange).not.toHaveBeenCalled()
     })
 
-    it('should fail with multiple arguments', async () => {
-      await cmdWrapper.run(['on', 'off'], importMeta, context)
+    it('should fail with an unknown package manager', async () => {
+      await cmdWrapper.run(['enable', 'npm', 'pip'], importMeta, context)
 
       expect(process.exitCode).toBe(2)
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-      expect(mockRemoveSocketWrapper).not.toHaveBeenCalled()
-    })
-
-    it('should enable wrapper with "on" argument', async () => {
-      await cmdWrapper.run(['on'], importMeta, context)
-
-      expect(mockAddSocketWrapper).toHaveBeenCalledTimes(2)
-      expect(mockRemoveSocketWrapper).not.toHaveBeenCalled()
-    })
-
-    it('should enable wrapper with "enable" argument', async () => {
-      await cmdWrapper.run(['enable'], importMeta, context)
-
-      expect(mockAddSocketWrapper).toHaveBeenCalledTimes(2)
-      expect(mockRemoveSocketWrapper).not.toHaveBeenCalled()
+      expect(mockHandleWrapperChange).not.toHaveBeenCalled()
     })
 
-    it('should enable wrapper with "enabled" argument', async () => {
-      await cmdWrapper.run(['enabled'], importMeta, context)
-
-      expect(mockAddSocketWrapper).toHaveBeenCalledTimes(2)
-      expect(mockRemoveSocketWrapper).not.toHaveBeenCalled()
-    })
-
-    it('should disable wrapper with "off" argument', async () => {
-      await cmdWrapper.run(['off'], importMeta, context)
-
-      expect(mockRemoveSocketWrapper).toHaveBeenCalledTimes(2)
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-    })
-
-    it('should disable wrapper with "disable" argument', async () => {
-      await cmdWrapper.run(['disable'], importMeta, context)
-
-      expect(mockRemoveSocketWrapper).toHaveBeenCalledTimes(2)
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-    })
+    it('should fail when status gets package managers', async () => {
+      await cmdWrapper.run(['status', 'npm'], importMeta, context)
 
-    it('should disable wrapper with "disabled" argument', async () => {
-      await cmdWrapper.run(['disabled'], importMeta, context)
-
-      expect(mockRemoveSocketWrapper).toHaveBeenCalledTimes(2)
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-    })
-
-    it('should skip already configured files when enabling', async () => {
-      mockCheckSocketWrapperSetup.mockReturnValue(true)
-
-      await cmdWrapper.run(['on'], importMeta, context)
-
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-    })
-
-    it('should add wrapper to bashrc when it exists', async () => {
-      mockExistsSync.mockImplementation(
-        (path: string) => path === '/home/user/.bashrc',
+      expect(process.exitCode).toBe(2)
+      expect(mockHandleWrapperStatus).not.toHaveBeenCalled()
+    })
+
+    it.each(['on', 'enable', 'enabled'])(
+      'should wrap npm and npx for the user with "%s"',
+      async arg => {
+        await cmdWrapper.run([arg], importMeta, context)
+
+        expect(mockHandleWrapperChange).toHaveBeenCalledWith(
+          'enable',
+          'user',
+          ['npm', 'npx'],
+          process.cwd(),
+          'text',
+        )
+      },
+    )
+
+    it.each(['off', 'disable', 'disabled'])(
+      'should unwrap every package manager with "%s"',
+      async arg => {
+        await cmdWrapper.run([arg], importMeta, context)
+
+        expect(mockHandleWrapperChange).toHaveBeenCalledWith(
+          'disable',
+          'user',
+          ['npm', 'npx', 'pnpm', 'yarn'],
+          process.cwd(),
+          'text',
+        )
+      },
+    )
+
+    it('should wrap the named package managers in the project', async () => {
+      await cmdWrapper.run(
+        ['enable', 'pnpm', 'yarn', '--project', '--json'],
+        importMeta,
+        context,
       )
 
-      await cmdWrapper.run(['on'], importMeta, context)
-
-      expect(mockAddSocketWrapper).toHaveBeenCalledWith('/home/user/.bashrc')
-    })
-
-    it('should add wrapper to zshrc when it exists', async () => {
-      mockExistsSync.mockImplementation(
-        (path: string) => path === '/home/user/.zshrc',
+      expect(mockHandleWrapperChange).toHaveBeenCalledWith(
+        'enable',
+        'project',
+        ['pnpm', 'yarn'],
+        process.cwd(),
+        'json',
       )
-
-      await cmdWrapper.run(['on'], importMeta, context)
-
-      expect(mockAddSocketWrapper).toHaveBeenCalledWith('/home/user/.zshrc')
     })
 
-    it('should fail when no shell config files exist', async () => {
-      mockExistsSync.mockReturnValue(false)
-
-      await cmdWrapper.run(['on'], importMeta, context)
+    it('should show the status', async () => {
+      await cmdWrapper.run(['status', '--markdown'], importMeta, context)
 
-      expect(mockLogger.fail).toHaveBeenCalledWith(
-        expect.stringContaining('bash profile'),
+      expect(mockHandleWrapperStatus).toHaveBeenCalledWith(
+        process.cwd(),
+        'markdown',
       )
+      expect(mockHandleWrapperChange).not.toHaveBeenCalled()
     })
 
     it('should handle --postinstall argument', async () => {
       await cmdWrapper.run(['--postinstall'], importMeta, context)
 
       expect(mockPostinstallWrapper).toHaveBeenCalled()
-      expect(mockAddSocketWrapper).not.toHaveBeenCalled()
-      expect(mockRemoveSocketWrapper).not.toHaveBeenCalled()
-    })
-
-    it('should output JSON when --json flag is set', async () => {
-      await cmdWrapper.run(['on', '--json'], importMeta, context)
-
-      expect(mockLogger.log).toHaveBeenCalledWith(
-        expect.stringContaining('"action"'),
-      )
-      expect(mockLogger.log).toHaveBeenCalledWith(
-        expect.stringContaining('"enabled"'),
-      )
-    })
-
-    it('should output markdown when --markdown flag is set', async () => {
-      await cmdWrapper.run(['on', '--markdown'], importMeta, context)
-
-      expect(mockLogger.log).toHaveBeenCalledWith(
-        expect.stringContaining('# Socket Wrapper Enabled'),
-      )
-    })
-
-    it('should show disabled status in JSON output', async () => {
-      await cmdWrapper.run(['off', '--json'], importMeta, context)
-
-      expect(mockLogger.log).toHaveBeenCalledWith(
-        expect.stringContaining('"disabled"'),
-      )
-    })
-
-    it('should show disabled status in markdown output', async () => {
-      await cmdWrapper.run(['off', '--markdown'], importMeta, context)
-
-      expect(mockLogger.log).toHaveBeenCalledWith(
-        expect.stringContaining('# Socket Wrapper Disabled'),
-      )
-    })
-
-    it('shows skipped-files section in markdown when files are already configured', async () => {
-      mockCheckSocketWrapperSetup.mockReturnValue(true)
-
-      await cmdWrapper.run(['on', '--markdown'], importMeta, context)
-
-      expect(mockLogger.log).toHaveBeenCalledWith(
-        expect.stringContaining('## Skipped Files'),
-      )
+      expect(mockHandleWrapperChange).not.toHaveBeenCalled()
     })
   })
 })
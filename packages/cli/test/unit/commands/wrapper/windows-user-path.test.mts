@@ -0,0 +1,49 @@
+/**
+ * Unit tests for editing the Windows user PATH of `socket wrapper`.
+ *
+ * Test Coverage: - Finding an entry regardless of case and trailing
+ * separators - Putting the shim directory first - Removing it again while
+ * keeping unexpanded entries.
+ *
+ * Related Files: - src/commands/wrapper/windows-user-path.mts
+ * (implementation) - src/commands/wrapper/wrapper-shims.mts - The shims.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  addWindowsPathEntry,
+  hasWindowsPathEntry,
+  removeWindowsPathEntry,
+} from '../../../../src/commands/wrapper/windows-user-path.mts'
+
+const SHIM_DIR = 'C:\\Users\\jo\\AppData\\Local\\socket\\shims'
+
+describe('Windows user PATH entries', () => {
+  it('finds an entry regardless of case and trailing separators', () => {
+    const value = `C:\\Windows;c:\\users\\JO\\AppData\\Local\\socket\\shims\\`
+
+    expect(hasWindowsPathEntry(value, SHIM_DIR)).toBe(true)
+    expect(hasWindowsPathEntry('C:\\Windows', SHIM_DIR)).toBe(false)
+    expect(hasWindowsPathEntry('', SHIM_DIR)).toBe(false)
+  })
+
+  it('puts the entry first without duplicates', () => {
+    expect(
+      addWindowsPathEntry('C:\\Windows;%USERPROFILE%\\bin', SHIM_DIR),
+    ).toBe(`${SHIM_DIR};C:\\Windows;%USERPROFILE%\\bin`)
+    expect(
+      addWindowsPathEntry(`C:\\Windows;${SHIM_DIR.toLowerCase()};`, SHIM_DIR),
+    ).toBe(`${SHIM_DIR};C:\\Windows`)
+    expect(addWindowsPathEntry('', SHIM_DIR)).toBe(SHIM_DIR)
+  })
+
+  it('removes the entry and keeps the others as they are', () => {
+    expect(
+      removeWindowsPathEntry(
+        `${SHIM_DIR}\\;C:\\Windows;;%USERPROFILE%\\bin`,
+        SHIM_DIR,
+      ),
+    ).toBe('C:\\Windows;%USERPROFILE%\\bin')
+  })
+})
@@ -0,0 +1,332 @@
+/**
+ * Unit tests for the PATH shims of `socket wrapper`.
+ *
+ * Purpose: Tests writing and removing shims, the shell profile PATH line and
+ * the status report.
+ *
+ * Test Coverage: - Shims that run socket <package-manager> without their own
+ * directory on PATH - Replacing the legacy npm/npx aliases - Keeping the PATH
+ * line until the last shim is gone - Refusing to overwrite foreign files -
+ * The files --dry-run would change - Project shims - CRLF shell profiles - Windows .cmd shims, Git Bash paths
+ * and the Windows user PATH.
+ *
+ * Related Files: - src/commands/wrapper/wrapper-shims.mts (implementation)
+ * - src/commands/wrapper/wrapper-shim-sh.mts,
+ * src/commands/wrapper/wrapper-shim-cmd.mts - Shim rendering.
+ * - src/commands/wrapper/shell-profiles.mts - Shell profile PATH line.
+ * - src/commands/wrapper/cmd-wrapper.mts - Command definition.
+ */
+
+import {
+  existsSync,
+  mkdirSync,
+  mkdtempSync,
+  readFileSync,
+  rmSync,
+  statSync,
+  writeFileSync,
+} from 'node:fs'
+import { tmpdir } from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import type * as WindowsUserPathModule from '../../../../src/commands/wrapper/windows-user-path.mts'
+import type * as PathsModule from '../../../../src/constants/paths.mts'
+
+const mockPaths = vi.hoisted(() => ({
+  getBashRcPath: vi.fn(),
+  getSocketShimsPath: vi.fn(),
+  getZshRcPath: vi.fn(),
+}))
+vi.mock(import('../../../../src/constants/paths.mts'), async importOriginal => ({
+  ...(await importOriginal<typeof PathsModule>()),
+  ...mockPaths,
+}))
+
+const mockUserPath = vi.hoisted(() => ({ value: '' }))
+vi.mock(
+  import('../../../../src/commands/wrapper/windows-user-path.mts'),
+  async importOriginal => ({
+    ...(await importOriginal<typeof WindowsUserPathModule>()),
+    updateWindowsUserPath: vi.fn(async (update: (value: string) => string) => {
+      const updated = update(mockUserPath.value)
+      const changed = updated !== mockUserPath.value
+      mockUserPath.value = updated
+      return { ok: true as const, data: changed }
+    }),
+  }),
+)
+
+import { toGitBashPath } from '../../../../src/commands/wrapper/wrapper-shim-sh.mts'
+import {
+  disableWrapperShims,
+  enableWrapperShims,
+  getWrapperFileChanges,
+  getWrapperStatus,
+  renderShim,
+} from '../../../../src/commands/wrapper/wrapper-shims.mts'
+
+function setPlatform(platform: NodeJS.Platform): void {
+  Object.defineProperty(process, 'platform', {
+    value: platform,
+    configurable: true,
+  })
+}
+
+describe.skipIf(process.platform === 'win32')('wrapper shims', () => {
+  let tmpDir: string
+  let bashRc: string
+  let shimDir: string
+
+  beforeEach(() => {
+    tmpDir = mkdtempSync(path.join(tmpdir(), 'socket-wrapper-'))
+    bashRc = path.join(tmpDir, '.bashrc')
+    shimDir = path.join(tmpDir, 'shims')
+    writeFileSync(bashRc, 'export EDITOR=vim\nalias npm="socket npm"\n')
+    mockPaths.getBashRcPath.mockReturnValue(bashRc)
+    mockPaths.getZshRcPath.mockReturnValue(path.join(tmpDir, '.zshrc'))
+    mockPaths.getSocketShimsPath.mockReturnValue(shimDir)
+  })
+
+  afterEach(() => {
+    rmSync(tmpDir, { force: true, recursive: true })
+  })
+
+  it('renders a shim that drops its directory from PATH', () => {
+    const shim = renderShim('pnpm', "/home/o'neil/shims")['pnpm']
+
+    expect(shim).toContain("shim_dir='/home/o'\\''neil/shims'")
+    expect(shim).toContain('exec socket pnpm "$@"')
+  })
+
+  it('writes executable shims and replaces the legacy aliases', async () => {
+    const result = await enableWrapperShims('user', ['npm', 'npx'], tmpDir)
+
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        action: 'enabled',
+        bins: ['npm', 'npx'],
+        modifiedFiles: [bashRc],
+        scope: 'user',
+        shimDir,
+        shimDirOnPath: false,
+        userPathUpdated: false,
+      },
+    })
+    expect(statSync(path.join(shimDir, 'npm')).mode & 0o111).not.toBe(0)
+    expect(readFileSync(bashRc, 'utf8')).toBe(
+      `export EDITOR=vim\nexport PATH="${shimDir}:$PATH" # Managed by \`socket wrapper\`, do not edit.\n`,
+    )
+
+    // Enabling again leaves the profile alone.
+    const again = await enableWrapperShims('user', ['npm'], tmpDir)
+    expect(again.ok && again.data.modifiedFiles).toEqual([])
+  })
+
+  it('keeps the CRLF line endings of a profile', async () => {
+    writeFileSync(bashRc, 'export EDITOR=vim\r\nalias npm="socket npm"\r\n')
+
+    await enableWrapperShims('user', ['npm'], tmpDir)
+    expect(readFileSync(bashRc, 'utf8')).toBe(
+      `export EDITOR=vim\r\nexport PATH="${shimDir}:$PATH" # Managed by \`socket wrapper\`, do not edit.\r\n`,
+    )
+
+    // The PATH line is found again despite the CR.
+    const again = await enableWrapperShims('user', ['npx'], tmpDir)
+    expect(again.ok && again.data.modifiedFiles).toEqual([])
+
+    await disableWrapperShims('user', ['npm', 'npx'], tmpDir)
+    expect(readFileSync(bashRc, 'utf8')).toBe('export EDITOR=vim\r\n')
+  })
+
+  it('keeps the PATH line until the last shim is gone', async () => {
+    await enableWrapperShims('user', ['npm', 'pnpm'], tmpDir)
+
+    const first = await disableWrapperShims('user', ['npm', 'yarn'], tmpDir)
+    expect(first.ok && first.data).toMatchObject({
+      bins: ['npm'],
+      modifiedFiles: [],
+    })
+    expect(existsSync(path.join(shimDir, 'npm'))).toBe(false)
+
+    const second = await disableWrapperShims('user', ['pnpm'], tmpDir)
+    expect(second.ok && second.data.modifiedFiles).toEqual([bashRc])
+    expect(readFileSync(bashRc, 'utf8')).toBe('export EDITOR=vim\n')
+  })
+
+  it('does not overwrite files it did not write', async () => {
+    mkdirSync(shimDir)
+    writeFileSync(path.join(shimDir, 'npm'), '#!/bin/sh\necho mine\n')
+
+    const result = await enableWrapperShims('user', ['npm'], tmpDir)
+
+    expect(result.ok).toBe(false)
+    expect(readFileSync(path.join(shimDir, 'npm'), 'utf8')).toContain('mine')
+  })
+
+  it('lists the files a change would write without writing them', () => {
+    const enable = getWrapperFileChanges('enable', 'user', ['npm'], tmpDir)
+
+    expect(enable).toEqual({
+      ok: true,
+      data: [
+        {
+          after: renderShim('npm', shimDir)['npm'],
+          before: undefined,
+          path: path.join(shimDir, 'npm'),
+        },
+        {
+          after: `export EDITOR=vim\nexport PATH="${shimDir}:$PATH" # Managed by \`socket wrapper\`, do not edit.\n`,
+          before: 'export EDITOR=vim\nalias npm="socket npm"\n',
+          path: bashRc,
+        },
+      ],
+    })
+    expect(existsSync(shimDir)).toBe(false)
+    expect(readFileSync(bashRc, 'utf8')).toContain('alias npm="socket npm"')
+  })
+
+  it('lists the shims and profiles disabling would change', async () => {
+    await enableWrapperShims('user', ['npm', 'pnpm'], tmpDir)
+    const npm = path.join(shimDir, 'npm')
+
+    const first = getWrapperFileChanges('disable', 'user', ['npm'], tmpDir)
+    expect(first.ok && first.data).toEqual([
+      { before: readFileSync(npm, 'utf8'), path: npm },
+    ])
+
+    const all = getWrapperFileChanges(
+      'disable',
+      'user',
+      ['npm', 'pnpm'],
+      tmpDir,
+    )
+    expect(all.ok && all.data.map(f => [f.path, f.after])).toEqual([
+      [npm, undefined],
+      [path.join(shimDir, 'pnpm'), undefined],
+      [bashRc, 'export EDITOR=vim\n'],
+    ])
+    expect(existsSync(npm)).toBe(true)
+  })
+
+  it('puts project shims in .socket/shims without touching profiles', async () => {
+    const result = await enableWrapperShims('project', ['yarn'], tmpDir)
+
+    expect(result.ok && result.data).toMatchObject({
+      modifiedFiles: [],
+      shimDir: path.join(tmpDir, '.socket', 'shims'),
+    })
+    expect(readFileSync(bashRc, 'utf8')).toContain('alias npm="socket npm"')
+  })
+
+  it('reports what each scope wraps', async () => {
+    await enableWrapperShims('project', ['yarn'], tmpDir)
+
+    const status = getWrapperStatus(tmpDir)
+
+    expect(status.aliasFiles).toEqual([bashRc])
+    expect(status.pathFiles).toEqual([])
+    const project = status.scopes.find(s => s.scope === 'project')
+    expect(
+      project?.bins.filter(b => b.shimmed).map(b => [b.name, b.active]),
+    ).toEqual([['yarn', false]])
+  })
+})
+
+describe('wrapper shims on Windows', () => {
+  const realPlatform = process.platform
+  let tmpDir: string
+  let shimDir: string
+
+  beforeEach(() => {
+    tmpDir = mkdtempSync(path.join(tmpdir(), 'socket-wrapper-win-'))
+    shimDir = path.join(tmpDir, 'shims')
+    mockPaths.getBashRcPath.mockReturnValue(path.join(tmpDir, '.bashrc'))
+    mockPaths.getZshRcPath.mockReturnValue(path.join(tmpDir, '.zshrc'))
+    mockPaths.getSocketShimsPath.mockReturnValue(shimDir)
+    mockUserPath.value = 'C:\\Windows\\system32;%USERPROFILE%\\bin'
+    setPlatform('win32')
+  })
+
+  afterEach(() => {
+    setPlatform(realPlatform)
+    rmSync(tmpDir, { force: true, recursive: true })
+  })
+
+  it('spells drive paths the Git Bash way', () => {
+    expect(toGitBashPath('C:\\Users\\jo\\shims')).toBe('/c/Users/jo/shims')
+    expect(toGitBashPath('D:/tools')).toBe('/d/tools')
+    expect(toGitBashPath('C:\\')).toBe('/c')
+    expect(toGitBashPath('/home/jo')).toBe('/home/jo')
+  })
+
+  it('renders a .cmd shim with CRLF line endings', () => {
+    const shims = renderShim(
+      'npm',
+      'C:\\Users\\jo\\AppData\\Local\\socket\\100%\\shims',
+    )
+
+    expect(Object.keys(shims)).toEqual(['npm', 'npm.cmd'])
+    const cmd = shims['npm.cmd']!
+    expect(cmd.split('\r\n').slice(0, 3)).toEqual([
+      '@echo off',
+      'rem Managed by `socket wrapper`, do not edit.',
+      'setlocal',
+    ])
+    expect(cmd).not.toMatch(/[^\r]\n/)
+    // With and without a trailing backslash, % escaped for cmd.exe.
+    expect(cmd).toContain(
+      'set "PATH=%PATH:;C:\\Users\\jo\\AppData\\Local\\socket\\100%%\\shims;=;%"',
+    )
+    expect(cmd).toContain(
+      'set "PATH=%PATH:;C:\\Users\\jo\\AppData\\Local\\socket\\100%%\\shims\\;=;%"',
+    )
+    expect(cmd).toContain('socket npm %*')
+    // The sh shim is for Git Bash and keeps LF line endings.
+    expect(shims['npm']).toContain(
+      "shim_dir='/c/Users/jo/AppData/Local/socket/100%/shims'",
+    )
+    expect(shims['npm']).not.toContain('\r')
+  })
+
+  it('puts the user shims first on the Windows user PATH', async () => {
+    const result = await enableWrapperShims('user', ['npm'], tmpDir)
+
+    expect(result.ok && result.data).toMatchObject({
+      modifiedFiles: [],
+      userPathUpdated: true,
+    })
+    expect(existsSync(path.join(shimDir, 'npm.cmd'))).toBe(true)
+    expect(mockUserPath.value).toBe(
+      `${shimDir};C:\\Windows\\system32;%USERPROFILE%\\bin`,
+    )
+
+    // Enabling again leaves the user PATH alone.
+    const again = await enableWrapperShims('user', ['npx'], tmpDir)
+    expect(again.ok && again.data.userPathUpdated).toBe(false)
+  })
+
+  it('takes the shims off the user PATH once the last one is gone', async () => {
+    await enableWrapperShims('user', ['npm', 'npx'], tmpDir)
+
+    const first = await disableWrapperShims('user', ['npm'], tmpDir)
+    expect(first.ok && first.data.userPathUpdated).toBe(false)
+    expect(existsSync(path.join(shimDir, 'npm.cmd'))).toBe(false)
+
+    const second = await disableWrapperShims('user', ['npx'], tmpDir)
+    expect(second.ok && second.data.userPathUpdated).toBe(true)
+    expect(mockUserPath.value).toBe(
+      'C:\\Windows\\system32;%USERPROFILE%\\bin',
+    )
+  })
+
+  it('leaves the user PATH alone for project shims', async () => {
+    await enableWrapperShims('project', ['yarn'], tmpDir)
+
+    expect(mockUserPath.value).toBe(
+      'C:\\Windows\\system32;%USERPROFILE%\\bin',
+    )
+  })
+})
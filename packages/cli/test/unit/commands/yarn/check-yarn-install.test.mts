@@ -0,0 +1,59 @@
+/**
+ * Unit tests for the socket yarn pre-install check.
+ *
+ * Purpose: Tests what `yarn add` and `yarn install` invocations install, as
+ * checked before handing off to Socket Firewall.
+ *
+ * Test Coverage: - Package specs of add, global add and workspace add -
+ * Options that take a value, and --cwd - Bare installs check the lockfile -
+ * Scripts, other commands and alternate registries are skipped.
+ *
+ * Related Files: - src/commands/yarn/check-yarn-install.mts (implementation)
+ * - src/util/install-check/node-install.mts (resolution and scoring)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { getYarnInstallRequest } from '../../../../src/commands/yarn/check-yarn-install.mts'
+
+describe('getYarnInstallRequest', () => {
+  it('collects the registry packages of yarn add', () => {
+    expect(
+      getYarnInstallRequest(['add', '--dev', 'lodash@^4', 'file:../x']),
+    ).toEqual({
+      dir: undefined,
+      fromLockfile: false,
+      specs: [{ name: 'lodash', spec: 'lodash@^4', version: '^4' }],
+    })
+  })
+
+  it('handles global and workspace adds', () => {
+    expect(getYarnInstallRequest(['global', 'add', 'cowsay'])).toMatchObject({
+      specs: [{ name: 'cowsay', version: 'latest' }],
+    })
+    expect(
+      getYarnInstallRequest(['workspace', 'web', 'add', 'react@18']),
+    ).toMatchObject({ specs: [{ name: 'react', version: '18' }] })
+  })
+
+  it('checks the lockfile of a bare install', () => {
+    expect(getYarnInstallRequest([])).toEqual({
+      dir: undefined,
+      fromLockfile: true,
+      specs: [],
+    })
+    expect(
+      getYarnInstallRequest(['--cwd', 'app', 'install', '--immutable']),
+    ).toEqual({ dir: 'app', fromLockfile: true, specs: [] })
+  })
+
+  it('skips scripts, other commands and registries', () => {
+    expect(getYarnInstallRequest(['build'])).toBeUndefined()
+    expect(getYarnInstallRequest(['remove', 'lodash'])).toBeUndefined()
+    expect(getYarnInstallRequest(['workspace', 'web', 'build'])).toBeUndefined()
+    expect(getYarnInstallRequest(['global', 'list'])).toBeUndefined()
+    expect(
+      getYarnInstallRequest(['add', '--registry=https://corp.test', 'x']),
+    ).toBeUndefined()
+  })
+})
@@ -47,6 +47,14 @@ vi.mock(import('../../../../src/util/telemetry/integration.mts'), () => ({
   trackSubprocessStart: mockTrackSubprocessStart,
 }))
 
+vi.mock(
+  import('../../../../src/commands/yarn/check-yarn-install.mts'),
+  async () => {
+    const { passingInstallCheck } = await import('../../../helpers/mocks.mts')
+    return passingInstallCheck('checkYarnInstall')
+  },
+)
+
 describe('cmd-yarn', () => {
   interface MockChildProcess extends Partial<EventEmitter> {
     pid: number
@@ -36,6 +36,7 @@ import {
   getDistPath,
   getGithubCachePath,
   getPackageJsonPath,
+  getScoreCachePath,
   getSocketCachePath,
   getZshRcPath,
   homePath,
@@ -191,6 +192,12 @@ describe('paths constants', () => {
       expect(result).toContain('socket')
       expect(result).toContain('github')
     })
+
+    it('getScoreCachePath returns path in socket cache', () => {
+      const result = getScoreCachePath()
+      expect(result).toContain('socket')
+      expect(result).toContain('scores')
+    })
   })
 
   describe('getBlessedOptions', () => {
@@ -16,10 +16,13 @@ import {
   NPM_REGISTRY_URL,
   SCAN_TYPE_SOCKET,
   SCAN_TYPE_SOCKET_TIER1,
+  SOCKET_BASELINE_JSON,
   SOCKET_CLI_ISSUES_URL,
   SOCKET_DEFAULT_BRANCH,
   SOCKET_DEFAULT_REPOSITORY,
   SOCKET_JSON,
+  SOCKET_POLICY_YAML,
+  SOCKET_POLICY_YML,
   SOCKET_WEBSITE_URL,
   SOCKET_YAML,
   SOCKET_YML,
@@ -56,6 +59,15 @@ describe('socket constants', () => {
     it('has SOCKET_YML constant', () => {
       expect(SOCKET_YML).toBe('socket.yml')
     })
+
+    it('has SOCKET_POLICY_YML and SOCKET_POLICY_YAML constants', () => {
+      expect(SOCKET_POLICY_YML).toBe('socket.policy.yml')
+      expect(SOCKET_POLICY_YAML).toBe('socket.policy.yaml')
+    })
+
+    it('has SOCKET_BASELINE_JSON constant', () => {
+      expect(SOCKET_BASELINE_JSON).toBe('socket.baseline.json')
+    })
   })
 
   describe('repository metadata constants', () => {
@@ -0,0 +1,139 @@
+/**
+ * Unit tests for the local response caches behind `socket cache`.
+ *
+ * Test Coverage: - Entry counts, sizes and ages per cache - Clearing all or
+ * some caches - Pruning by age - Duration parsing for --older-than and
+ * --cache-ttl.
+ *
+ * Related Files: - src/util/cache/local-caches.mts (implementation) -
+ * src/util/cache/overrides.mts - Duration parsing and per-run overrides.
+ */
+
+import { mkdtempSync, mkdirSync, utimesSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterAll, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { safeDeleteSync } from '@socketsecurity/lib-stable/fs/safe'
+
+const mockCacheDir = vi.hoisted(() => ({ path: '' }))
+
+vi.mock(import('../../../../src/constants/paths.mts'), () => ({
+  getGithubCachePath: () => path.join(mockCacheDir.path, 'github'),
+  getScoreCachePath: () => path.join(mockCacheDir.path, 'scores'),
+}))
+
+import {
+  getLocalCacheStats,
+  isLocalCacheName,
+  removeLocalCacheEntries,
+} from '../../../../src/util/cache/local-caches.mts'
+import { parseCacheDuration } from '../../../../src/util/cache/overrides.mts'
+
+const tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-local-caches-test-'))
+
+const DAY_MS = 24 * 60 * 60 * 1000
+
+function writeEntry(cache: string, name: string, ageMs: number): void {
+  const dir = path.join(mockCacheDir.path, cache)
+  mkdirSync(dir, { recursive: true })
+  const filepath = path.join(dir, name)
+  writeFileSync(filepath, '{"timestamp":0,"data":{}}')
+  const seconds = (Date.now() - ageMs) / 1000
+  utimesSync(filepath, seconds, seconds)
+}
+
+describe('local caches', () => {
+  beforeEach(() => {
+    mockCacheDir.path = mkdtempSync(path.join(tmpDir, 'cache-'))
+  })
+
+  afterAll(() => {
+    safeDeleteSync(tmpDir, { force: true })
+  })
+
+  it('should know the cache names', () => {
+    expect(isLocalCacheName('github')).toBe(true)
+    expect(isLocalCacheName('scores')).toBe(true)
+    expect(isLocalCacheName('dlx')).toBe(false)
+  })
+
+  it('should report entries, size and ages per cache', async () => {
+    writeEntry('github', 'a.json', 3 * DAY_MS)
+    writeEntry('github', 'b.json', DAY_MS)
+    writeEntry('github', 'notes.txt', 0)
+
+    const [github, scores] = await getLocalCacheStats()
+
+    expect(github).toMatchObject({
+      name: 'github',
+      path: path.join(mockCacheDir.path, 'github'),
+      entries: 2,
+      bytes: 50,
+    })
+    expect(github!.newest! - github!.oldest!).toBeCloseTo(2 * DAY_MS, -3)
+    expect(scores).toEqual({
+      name: 'scores',
+      path: path.join(mockCacheDir.path, 'scores'),
+      entries: 0,
+      bytes: 0,
+      oldest: undefined,
+      newest: undefined,
+    })
+  })
+
+  it('should clear only the named caches', async () => {
+    writeEntry('github', 'a.json', 0)
+    writeEntry('scores', 'shallow-1.json', 0)
+    writeEntry('scores', 'deep-2.json.tmp.123', 0)
+
+    const removals = await removeLocalCacheEntries(['scores'])
+
+    expect(removals).toEqual([
+      {
+        name: 'scores',
+        path: path.join(mockCacheDir.path, 'scores'),
+        removedEntries: 2,
+        removedBytes: 50,
+      },
+    ])
+    const [github, scores] = await getLocalCacheStats()
+    expect(github!.entries).toBe(1)
+    expect(scores!.entries).toBe(0)
+  })
+
+  it('should prune only the entries older than the given age', async () => {
+    writeEntry('scores', 'old.json', 8 * DAY_MS)
+    writeEntry('scores', 'new.json', DAY_MS)
+
+    const [removal] = await removeLocalCacheEntries(['scores'], 7 * DAY_MS)
+
+    expect(removal!.removedEntries).toBe(1)
+    const [scores] = await getLocalCacheStats(['scores'])
+    expect(scores!.entries).toBe(1)
+  })
+})
+
+describe('parseCacheDuration', () => {
+  it('should parse durations with units', () => {
+    expect(parseCacheDuration('90s')).toBe(90_000)
+    expect(parseCacheDuration('30m')).toBe(30 * 60 * 1000)
+    expect(parseCacheDuration('12h')).toBe(12 * 60 * 60 * 1000)
+    expect(parseCacheDuration('7d')).toBe(7 * DAY_MS)
+    expect(parseCacheDuration('2w')).toBe(14 * DAY_MS)
+    expect(parseCacheDuration('1.5h')).toBe(90 * 60 * 1000)
+  })
+
+  it('should take bare numbers as seconds', () => {
+    expect(parseCacheDuration('0')).toBe(0)
+    expect(parseCacheDuration('60')).toBe(60_000)
+  })
+
+  it('should reject anything else', () => {
+    expect(parseCacheDuration('')).toBe(undefined)
+    expect(parseCacheDuration('7 days')).toBe(undefined)
+    expect(parseCacheDuration('-1d')).toBe(undefined)
+    expect(parseCacheDuration('1y')).toBe(undefined)
+  })
+})
@@ -0,0 +1,66 @@
+/**
+ * Unit tests for command tree introspection.
+ *
+ * Purpose: Tests reading the subcommands and flags of a command without
+ * running it.
+ *
+ * Test Coverage: - Routers built on meowWithSubcommands - Leaf commands
+ * built on meowOrExit - Commands that never reach the router.
+ *
+ * Related Files: - src/util/cli/command-tree.mts (implementation) -
+ * src/commands/completion/completion-candidates.mts - The main consumer.
+ */
+
+import { describe, expect, it, vi } from 'vitest'
+
+const mockHandleHooksRun = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/commands/hooks/handle-hooks-run.mts'), () => ({
+  handleHooksRun: mockHandleHooksRun,
+}))
+
+import { cmdHooks } from '../../../../src/commands/hooks/cmd-hooks.mts'
+import { cmdHooksRun } from '../../../../src/commands/hooks/cmd-hooks-run.mts'
+import {
+  inspectCommand,
+  isCapturingCommandTree,
+} from '../../../../src/util/cli/command-tree.mts'
+
+const importMeta = { url: import.meta.url } as ImportMeta
+
+describe('inspectCommand', () => {
+  it('reads the subcommands of a router', async () => {
+    const node = await inspectCommand(cmdHooks, 'socket', importMeta)
+
+    expect(Object.keys(node?.subcommands ?? {})).toEqual([
+      'install',
+      'run',
+      'uninstall',
+    ])
+    expect(node?.flags).toHaveProperty('help')
+  })
+
+  it('reads the flags of a leaf command without running it', async () => {
+    const node = await inspectCommand(cmdHooksRun, 'socket hooks', importMeta)
+
+    expect(node?.subcommands).toBeUndefined()
+    expect(node?.flags).toHaveProperty('failOn')
+    expect(mockHandleHooksRun).not.toHaveBeenCalled()
+    expect(isCapturingCommandTree()).toBe(false)
+  })
+
+  it('returns undefined for commands that skip the router', async () => {
+    const node = await inspectCommand(
+      {
+        description: 'Fails early',
+        run() {
+          throw new Error('boom')
+        },
+      },
+      'socket',
+      importMeta,
+    )
+
+    expect(node).toBeUndefined()
+    expect(isCapturingCommandTree()).toBe(false)
+  })
+})
@@ -0,0 +1,93 @@
+/**
+ * Unit tests for the shell completion history.
+ *
+ * Purpose: Tests how seen org slugs, repo names and scan IDs are stored.
+ *
+ * Test Coverage: - Newest values first without duplicates - The per-kind
+ * cap - Missing and malformed history files.
+ *
+ * Related Files: - src/util/cli/completion-history.mts (implementation)
+ */
+
+import { mkdtempSync, rmSync, writeFileSync } from 'node:fs'
+import { tmpdir } from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockGetCompletionHistoryPath = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/constants/paths.mts'), async importOriginal => {
+  const actual = await importOriginal()
+  return {
+    ...actual,
+    getCompletionHistoryPath: mockGetCompletionHistoryPath,
+  }
+})
+
+import {
+  COMPLETION_HISTORY_MAX,
+  readCompletionHistory,
+  recordCompletionValues,
+} from '../../../../src/util/cli/completion-history.mts'
+
+describe('completion history', () => {
+  let cacheDir: string
+  let historyPath: string
+
+  beforeEach(() => {
+    cacheDir = mkdtempSync(path.join(tmpdir(), 'socket-completion-'))
+    historyPath = path.join(cacheDir, 'socket', 'completion-history.json')
+    mockGetCompletionHistoryPath.mockReturnValue(historyPath)
+  })
+
+  afterEach(() => {
+    rmSync(cacheDir, { force: true, recursive: true })
+  })
+
+  it('is empty before anything was recorded', async () => {
+    expect(await readCompletionHistory()).toEqual({
+      org: [],
+      repo: [],
+      scan: [],
+    })
+  })
+
+  it('keeps the newest values first without duplicates', async () => {
+    await recordCompletionValues('repo', ['api', 'web'])
+    await recordCompletionValues('repo', ['cli', undefined, 'api'])
+    await recordCompletionValues('org', ['acme'])
+
+    expect(await readCompletionHistory()).toEqual({
+      org: ['acme'],
+      repo: ['cli', 'api', 'web'],
+      scan: [],
+    })
+  })
+
+  it('caps each kind', async () => {
+    const ids = Array.from(
+      { length: COMPLETION_HISTORY_MAX + 5 },
+      (_, i) => `scan-${i}`,
+    )
+    await recordCompletionValues('scan', ids)
+
+    const { scan } = await readCompletionHistory()
+    expect(scan).toHaveLength(COMPLETION_HISTORY_MAX)
+    expect(scan[0]).toBe('scan-0')
+  })
+
+  it('ignores malformed history files', async () => {
+    await recordCompletionValues('org', ['acme'])
+    writeFileSync(historyPath, '{"org": [1, "acme"], "repo": "web"}')
+
+    expect(await readCompletionHistory()).toEqual({
+      org: ['acme'],
+      repo: [],
+      scan: [],
+    })
+
+    writeFileSync(historyPath, 'not json')
+
+    expect((await readCompletionHistory()).org).toEqual([])
+  })
+})
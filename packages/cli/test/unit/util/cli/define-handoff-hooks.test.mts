@@ -0,0 +1,300 @@
+/**
+ * Unit tests for the hooks `defineHandoffCommand` runs before spawning.
+ *
+ * Covers the preflight check (a failing one aborts, `--non-interactive` is
+ * passed on, direct mode spawns the tool itself) and the `prepareHandoff`
+ * hook `socket npm --sandbox-scripts` uses: a failing hook aborts before
+ * spawning, a passing one rewrites the forwarded args, and its `afterExit`
+ * step runs after a successful child and decides the exit code. Everything
+ * else about the factory is covered in define-handoff.test.mts.
+ */
+
+import EventEmitter from 'node:events'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockMeowOrExit = vi.hoisted(() => vi.fn())
+const mockFilterFlags = vi.hoisted(() => vi.fn())
+const mockSpawnSfw = vi.hoisted(() => vi.fn())
+const mockSpawnSfwDlx = vi.hoisted(() => vi.fn())
+const mockOutputDryRunExecute = vi.hoisted(() => vi.fn())
+const mockTrackSubprocessStart = vi.hoisted(() => vi.fn())
+const mockTrackSubprocessExit = vi.hoisted(() => vi.fn())
+const mockSpawn = vi.hoisted(() => vi.fn())
+const mockLogger = vi.hoisted(() => ({ fail: vi.fn() }))
+
+vi.mock(import('../../../../src/util/cli/with-subcommands.mts'), () => ({
+  meowOrExit: mockMeowOrExit,
+}))
+
+vi.mock(import('../../../../src/util/dlx/spawn.mts'), () => ({
+  spawnSfw: mockSpawnSfw,
+  spawnSfwDlx: mockSpawnSfwDlx,
+}))
+
+vi.mock(import('../../../../src/util/dry-run/output.mts'), () => ({
+  outputDryRunExecute: mockOutputDryRunExecute,
+}))
+
+vi.mock(import('../../../../src/util/process/cmd.mts'), () => ({
+  filterFlags: mockFilterFlags,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/process/spawn/child'), () => ({
+  spawn: mockSpawn,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), async importOriginal => ({
+  ...(await importOriginal()),
+  getDefaultLogger: () => mockLogger,
+}))
+
+vi.mock(import('../../../../src/util/telemetry/integration.mts'), () => ({
+  trackSubprocessStart: mockTrackSubprocessStart,
+  trackSubprocessExit: mockTrackSubprocessExit,
+}))
+
+import { defineHandoffCommand } from '../../../../src/util/cli/define-handoff.mts'
+
+function makeChildProcess() {
+  const child = new EventEmitter()
+  const spawnPromise: unknown = Promise.resolve({
+    code: 0,
+    signal: undefined,
+    stderr: Buffer.from(''),
+    stdout: Buffer.from(''),
+  })
+  spawnPromise.process = child
+  return { child, spawnPromise }
+}
+
+describe('defineHandoffCommand', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockFilterFlags.mockReturnValue([])
+    mockMeowOrExit.mockReturnValue({
+      flags: {},
+      input: [],
+      pkg: {},
+      showHelp: vi.fn(),
+      showVersion: vi.fn(),
+      unknownFlags: [],
+    })
+    mockTrackSubprocessStart.mockResolvedValue(123)
+    mockTrackSubprocessExit.mockResolvedValue(undefined)
+  })
+
+  describe('preflight and direct spawn', () => {
+    it('aborts with exit code 1 when the preflight fails', async () => {
+      mockFilterFlags.mockReturnValue(['install', 'evil'])
+      const preflight = vi.fn(async () => ({
+        ok: false as const,
+        message: 'Install blocked by Socket security policy',
+        cause: 'pkg:pypi/evil@1.0.0 violate the security policy.',
+      }))
+
+      const cmd = defineHandoffCommand({
+        name: 'pip',
+        description: 'Run pip',
+        spawnMode: 'dlx',
+        examples: [],
+        preflight,
+        trackTelemetry: false,
+      })
+
+      await cmd.run(
+        ['install', 'evil'],
+        { url: import.meta.url } as ImportMeta,
+        { parentName: 'socket' },
+      )
+
+      expect(preflight).toHaveBeenCalledWith(['install', 'evil'], 'pip', {
+        interactive: expect.any(Boolean),
+      })
+      expect(mockLogger.fail).toHaveBeenCalledWith(
+        expect.stringContaining('Install blocked by Socket security policy'),
+      )
+      expect(process.exitCode).toBe(1)
+      expect(mockSpawnSfwDlx).not.toHaveBeenCalled()
+      process.exitCode = undefined
+    })
+
+    it('tells the preflight not to prompt with --non-interactive', async () => {
+      mockFilterFlags.mockReturnValue(['install', 'evil'])
+      mockMeowOrExit.mockReturnValue({
+        flags: { nonInteractive: true },
+        input: [],
+      })
+      const preflight = vi.fn(async () => ({
+        ok: false as const,
+        message: 'Install cancelled',
+      }))
+
+      const cmd = defineHandoffCommand({
+        name: 'pip',
+        description: 'Run pip',
+        spawnMode: 'dlx',
+        examples: [],
+        preflight,
+        trackTelemetry: false,
+      })
+
+      await cmd.run(
+        ['install', 'evil', '--non-interactive'],
+        { url: import.meta.url } as ImportMeta,
+        { parentName: 'socket' },
+      )
+
+      expect(preflight).toHaveBeenCalledWith(['install', 'evil'], 'pip', {
+        interactive: false,
+      })
+      expect(mockFilterFlags.mock.calls[0]![1]).toHaveProperty('nonInteractive')
+      process.exitCode = undefined
+    })
+
+    it('spawns the tool itself in direct mode after a passing preflight', async () => {
+      const { child, spawnPromise } = makeChildProcess()
+      mockSpawn.mockReturnValue(spawnPromise)
+      mockFilterFlags.mockReturnValue(['install', 'black'])
+
+      const cmd = defineHandoffCommand({
+        name: 'pipx',
+        description: 'Run pipx',
+        spawnMode: 'direct',
+        examples: [],
+        preflight: async () => ({ ok: true, data: undefined }),
+        trackTelemetry: false,
+      })
+
+      const runPromise = cmd.run(
+        ['install', 'black'],
+        { url: import.meta.url } as ImportMeta,
+        { parentName: 'socket' },
+      )
+      setImmediate(() => child.emit('exit', 0, undefined))
+      const mockExit = vi
+        .spyOn(process, 'exit')
+        .mockImplementation((() => {}) as unknown)
+      try {
+        await runPromise
+        expect(mockSpawn).toHaveBeenCalledWith('pipx', ['install', 'black'], {
+          stdio: 'inherit',
+        })
+        expect(mockSpawnSfw).not.toHaveBeenCalled()
+        expect(mockSpawnSfwDlx).not.toHaveBeenCalled()
+      } finally {
+        mockExit.mockRestore()
+      }
+    })
+  })
+
+  describe('prepareHandoff', () => {
+    it('aborts with exit code 1 when preparing the hand-off fails', async () => {
+      mockFilterFlags.mockReturnValue(['install'])
+
+      const cmd = defineHandoffCommand({
+        name: 'npm',
+        description: 'Run npm',
+        spawnMode: 'auto',
+        examples: [],
+        prepareHandoff: async () => ({
+          ok: false as const,
+          message: 'Sandbox tools not found',
+        }),
+        trackTelemetry: false,
+      })
+
+      await cmd.run(['install'], { url: import.meta.url } as ImportMeta, {
+        parentName: 'socket',
+      })
+
+      expect(mockLogger.fail).toHaveBeenCalledWith(
+        expect.stringContaining('Sandbox tools not found'),
+      )
+      expect(process.exitCode).toBe(1)
+      expect(mockSpawnSfw).not.toHaveBeenCalled()
+      process.exitCode = undefined
+    })
+
+    it('spawns the planned args and runs afterExit on success', async () => {
+      const { child, spawnPromise } = makeChildProcess()
+      mockSpawnSfw.mockResolvedValue({ spawnPromise })
+      mockFilterFlags.mockReturnValue(['install'])
+      const afterExit = vi.fn(async () => ({ ok: true as const, data: 1 }))
+
+      const cmd = defineHandoffCommand({
+        name: 'npm',
+        description: 'Run npm',
+        spawnMode: 'auto',
+        examples: [],
+        prepareHandoff: async args => ({
+          ok: true,
+          data: { args: [...args, '--ignore-scripts'], afterExit },
+        }),
+        trackTelemetry: false,
+      })
+
+      const mockExit = vi
+        .spyOn(process, 'exit')
+        .mockImplementation((() => {}) as unknown)
+      try {
+        void cmd.run(['install'], { url: import.meta.url } as ImportMeta, {
+          parentName: 'socket',
+        })
+        await new Promise(resolve => setImmediate(resolve))
+        child.emit('exit', 0, undefined)
+        await new Promise(resolve => setImmediate(resolve))
+        expect(mockSpawnSfw).toHaveBeenCalledWith(
+          ['npm', 'install', '--ignore-scripts'],
+          expect.anything(),
+        )
+        expect(afterExit).toHaveBeenCalledOnce()
+        expect(mockExit).toHaveBeenCalledWith(0)
+      } finally {
+        mockExit.mockRestore()
+      }
+    })
+
+    it('exits with code 1 when afterExit fails', async () => {
+      const { child, spawnPromise } = makeChildProcess()
+      mockSpawnSfw.mockResolvedValue({ spawnPromise })
+      mockFilterFlags.mockReturnValue(['install'])
+
+      const cmd = defineHandoffCommand({
+        name: 'npm',
+        description: 'Run npm',
+        spawnMode: 'auto',
+        examples: [],
+        prepareHandoff: async args => ({
+          ok: true,
+          data: {
+            args,
+            afterExit: async () => ({
+              ok: false as const,
+              message: 'Install scripts failed in the sandbox',
+            }),
+          },
+        }),
+        trackTelemetry: false,
+      })
+
+      const mockExit = vi
+        .spyOn(process, 'exit')
+        .mockImplementation((() => {}) as unknown)
+      try {
+        void cmd.run(['install'], { url: import.meta.url } as ImportMeta, {
+          parentName: 'socket',
+        })
+        await new Promise(resolve => setImmediate(resolve))
+        child.emit('exit', 0, undefined)
+        await new Promise(resolve => setImmediate(resolve))
+        expect(mockLogger.fail).toHaveBeenCalledWith(
+          expect.stringContaining('Install scripts failed in the sandbox'),
+        )
+        expect(mockExit).toHaveBeenCalledWith(1)
+      } finally {
+        mockExit.mockRestore()
+      }
+    })
+  })
+})
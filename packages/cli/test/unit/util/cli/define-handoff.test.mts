@@ -4,8 +4,12 @@
  * Locks in the contract that the factory builds the same shape every existing
  * hand-off wrapper used to build by hand: a CliSubcommand with `description`,
  * `hidden`, and `run` — where `run` parses flags, filters Socket-only flags,
- * picks the binary, optionally renders dry-run, optionally tracks telemetry,
- * spawns sfw, and forwards the child's exit code or signal.
+ * picks the binary, optionally renders dry-run, optionally runs a preflight
+ * check, optionally tracks telemetry, spawns sfw (or the tool itself in direct
+ * mode), and forwards the child's exit code or signal.
+ *
+ * The preflight and `prepareHandoff` hooks are covered in
+ * define-handoff-hooks.test.mts.
  */
 
 import EventEmitter from 'node:events'
@@ -19,6 +23,8 @@ const mockSpawnSfwDlx = vi.hoisted(() => vi.fn())
 const mockOutputDryRunExecute = vi.hoisted(() => vi.fn())
 const mockTrackSubprocessStart = vi.hoisted(() => vi.fn())
 const mockTrackSubprocessExit = vi.hoisted(() => vi.fn())
+const mockSpawn = vi.hoisted(() => vi.fn())
+const mockLogger = vi.hoisted(() => ({ fail: vi.fn() }))
 
 vi.mock(import('../../../../src/util/cli/with-subcommands.mts'), () => ({
   meowOrExit: mockMeowOrExit,
@@ -37,6 +43,15 @@ vi.mock(import('../../../../src/util/process/cmd.mts'), () => ({
   filterFlags: mockFilterFlags,
 }))
 
+vi.mock(import('@socketsecurity/lib-stable/process/spawn/child'), () => ({
+  spawn: mockSpawn,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), async importOriginal => ({
+  ...(await importOriginal()),
+  getDefaultLogger: () => mockLogger,
+}))
+
 vi.mock(import('../../../../src/util/telemetry/integration.mts'), () => ({
   trackSubprocessStart: mockTrackSubprocessStart,
   trackSubprocessExit: mockTrackSubprocessExit,
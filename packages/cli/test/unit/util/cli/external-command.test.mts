@@ -0,0 +1,247 @@
+/**
+ * Unit tests for external commands.
+ *
+ * Purpose: Tests running `socket-<name>` executables for commands that are
+ * not built in.
+ *
+ * Test Coverage: - Command name validation - PATH lookup - The context
+ * environment variables - The API token file and its removal - Exit codes -
+ * Spawning Windows .cmd shims without a shell.
+ *
+ * Related Files: - src/util/cli/external-command.mts (implementation) -
+ * src/util/cli/with-subcommands-dispatch.mts (dispatch)
+ */
+
+import { existsSync, readFileSync, statSync } from 'node:fs'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  findExternalCommand,
+  getExternalCommandEnv,
+  getExternalCommandSpawnArgs,
+  isExternalCommandName,
+  runExternalCommand,
+} from '../../../../src/util/cli/external-command.mts'
+
+const mockGetConfigValueOrUndef = vi.hoisted(() => vi.fn())
+const mockGetDefaultApiBaseUrl = vi.hoisted(() => vi.fn())
+const mockGetDefaultApiToken = vi.hoisted(() => vi.fn())
+const mockSpawn = vi.hoisted(() => vi.fn())
+const mockWhichReal = vi.hoisted(() => vi.fn())
+const mockWin32 = vi.hoisted(() => ({ WIN32: false }))
+
+vi.mock(import('@socketsecurity/lib-stable/bin/which'), () => ({
+  whichReal: mockWhichReal,
+}))
+
+vi.mock(
+  import('@socketsecurity/lib-stable/constants/platform'),
+  () => mockWin32,
+)
+
+vi.mock(import('@socketsecurity/lib-stable/process/spawn/child'), () => ({
+  spawn: mockSpawn,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/process/spawn/errors'), () => ({
+  isSpawnError: vi.fn(e => e?.isSpawnError),
+}))
+
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  getConfigValueOrUndef: mockGetConfigValueOrUndef,
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mts'), () => ({
+  getDefaultApiBaseUrl: mockGetDefaultApiBaseUrl,
+  getDefaultApiToken: mockGetDefaultApiToken,
+}))
+
+describe('external commands', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockWin32.WIN32 = false
+    mockGetConfigValueOrUndef.mockReturnValue(undefined)
+    mockGetDefaultApiBaseUrl.mockReturnValue(undefined)
+    mockGetDefaultApiToken.mockReturnValue(undefined)
+    mockSpawn.mockResolvedValue({ code: 0, stderr: '', stdout: '' })
+  })
+
+  describe('isExternalCommandName', () => {
+    it('should accept plain lowercase names', () => {
+      expect(isExternalCommandName('deploy')).toBe(true)
+      expect(isExternalCommandName('sbom-sync2')).toBe(true)
+    })
+
+    it('should reject flags, paths and other names', () => {
+      expect(isExternalCommandName('--json')).toBe(false)
+      expect(isExternalCommandName('../bin/sh')).toBe(false)
+      expect(isExternalCommandName('Deploy')).toBe(false)
+      expect(isExternalCommandName('')).toBe(false)
+    })
+  })
+
+  describe('findExternalCommand', () => {
+    it('should look up socket-<name> on PATH', async () => {
+      mockWhichReal.mockResolvedValue('/usr/local/bin/socket-deploy')
+
+      expect(await findExternalCommand('deploy')).toBe(
+        '/usr/local/bin/socket-deploy',
+      )
+      expect(mockWhichReal).toHaveBeenCalledWith('socket-deploy', {
+        nothrow: true,
+      })
+    })
+
+    it('should use the first of several matches', async () => {
+      mockWhichReal.mockResolvedValue(['/a/socket-deploy', '/b/socket-deploy'])
+
+      expect(await findExternalCommand('deploy')).toBe('/a/socket-deploy')
+    })
+
+    it('should return undefined when there is none', async () => {
+      mockWhichReal.mockResolvedValue(null)
+
+      expect(await findExternalCommand('deploy')).toBeUndefined()
+    })
+
+    it('should not look up invalid names', async () => {
+      expect(await findExternalCommand('../sh')).toBeUndefined()
+      expect(mockWhichReal).not.toHaveBeenCalled()
+    })
+  })
+
+  describe('getExternalCommandEnv', () => {
+    it('should describe the invocation', () => {
+      mockGetDefaultApiBaseUrl.mockReturnValue('https://api.example.test/v0/')
+
+      expect(
+        getExternalCommandEnv(
+          { orgSlug: 'acme', outputKind: 'json' },
+          '/tmp/token',
+        ),
+      ).toEqual({
+        SOCKET_CLI_API_BASE_URL: 'https://api.example.test/v0/',
+        SOCKET_CLI_API_TOKEN_FILE: '/tmp/token',
+        SOCKET_CLI_ORG_SLUG: 'acme',
+        SOCKET_CLI_OUTPUT_FORMAT: 'json',
+        SOCKET_CLI_VERSION: expect.any(String),
+      })
+    })
+
+    it('should fall back to the default org', () => {
+      mockGetConfigValueOrUndef.mockReturnValue('default-org')
+
+      const env = getExternalCommandEnv({ outputKind: 'text' }, undefined)
+
+      expect(env['SOCKET_CLI_ORG_SLUG']).toBe('default-org')
+      expect(env).not.toHaveProperty('SOCKET_CLI_API_TOKEN_FILE')
+      expect(env).not.toHaveProperty('SOCKET_CLI_API_BASE_URL')
+    })
+  })
+
+  describe('getExternalCommandSpawnArgs', () => {
+    it('should spawn the executable itself outside Windows', () => {
+      expect(
+        getExternalCommandSpawnArgs('/usr/local/bin/socket-deploy.cmd', [
+          'a&b',
+        ]),
+      ).toEqual({
+        args: ['a&b'],
+        command: '/usr/local/bin/socket-deploy.cmd',
+        windowsVerbatimArguments: false,
+      })
+    })
+
+    it('should spawn Windows executables without cmd.exe', () => {
+      mockWin32.WIN32 = true
+
+      expect(
+        getExternalCommandSpawnArgs('C:\\bin\\socket-deploy.exe', ['a b']),
+      ).toEqual({
+        args: ['a b'],
+        command: 'C:\\bin\\socket-deploy.exe',
+        windowsVerbatimArguments: false,
+      })
+    })
+
+    it('should quote the path and arguments of a .cmd shim for cmd.exe', () => {
+      mockWin32.WIN32 = true
+      vi.stubEnv('ComSpec', 'C:\\Windows\\system32\\cmd.exe')
+      try {
+        const { args, command, windowsVerbatimArguments } =
+          getExternalCommandSpawnArgs('C:\\Program Files\\socket-deploy.CMD', [
+            'a b',
+            'x&whoami',
+            'say "hi"',
+          ])
+
+        expect(command).toBe('C:\\Windows\\system32\\cmd.exe')
+        expect(windowsVerbatimArguments).toBe(true)
+        expect(args).toEqual([
+          '/d',
+          '/s',
+          '/c',
+          '"C:\\Program^ Files\\socket-deploy.CMD ^"a^ b^" ^"x^&whoami^" ^"say^ \\^"hi\\^"^""',
+        ])
+      } finally {
+        vi.unstubAllEnvs()
+      }
+    })
+  })
+
+  describe('runExternalCommand', () => {
+    it('should hand the token over in a private file', async () => {
+      mockGetDefaultApiToken.mockReturnValue('sktsec_test_token')
+      let tokenFile = ''
+      mockSpawn.mockImplementation(async (_bin, _argv, options) => {
+        tokenFile = options.env.SOCKET_CLI_API_TOKEN_FILE
+        expect(readFileSync(tokenFile, 'utf8')).toBe('sktsec_test_token')
+        if (process.platform !== 'win32') {
+          expect(statSync(tokenFile).mode & 0o077).toBe(0)
+        }
+        return { code: 0, stderr: '', stdout: '' }
+      })
+
+      const code = await runExternalCommand(
+        '/usr/local/bin/socket-deploy',
+        ['--env', 'prod'],
+        { orgSlug: 'acme', outputKind: 'text' },
+      )
+
+      expect(code).toBe(0)
+      expect(mockSpawn).toHaveBeenCalledWith(
+        '/usr/local/bin/socket-deploy',
+        ['--env', 'prod'],
+        expect.objectContaining({ stdio: 'inherit' }),
+      )
+      expect(mockSpawn.mock.calls[0]![2]).not.toHaveProperty('shell')
+      expect(tokenFile).not.toBe('')
+      expect(existsSync(tokenFile)).toBe(false)
+    })
+
+    it('should not pass a token file without a token', async () => {
+      await runExternalCommand('/usr/local/bin/socket-deploy', [], {
+        outputKind: 'json',
+      })
+
+      const { env } = mockSpawn.mock.calls[0]![2]
+      expect(env).not.toHaveProperty('SOCKET_CLI_API_TOKEN_FILE')
+      expect(env['SOCKET_CLI_OUTPUT_FORMAT']).toBe('json')
+    })
+
+    it('should resolve to the exit code of a failing command', async () => {
+      const error = Object.assign(new Error('command failed'), {
+        code: 4,
+        isSpawnError: true,
+      })
+      mockSpawn.mockRejectedValue(error)
+
+      expect(
+        await runExternalCommand('/usr/local/bin/socket-deploy', [], {
+          outputKind: 'text',
+        }),
+      ).toBe(4)
+    })
+  })
+})
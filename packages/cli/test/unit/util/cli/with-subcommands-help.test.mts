@@ -364,14 +364,22 @@ describe('buildHelpLines', () => {
       expect(blob).toContain('SOCKET_CLI_GIT_USER_EMAIL')
       expect(blob).toContain('SOCKET_CLI_GIT_USER_NAME')
       expect(blob).toContain('SOCKET_CLI_GITHUB_TOKEN')
+      expect(blob).toContain('SOCKET_CLI_NO_KEYCHAIN')
+      expect(blob).toContain('SOCKET_CLI_NO_SELF_UPDATE')
       expect(blob).toContain('SOCKET_CLI_NPM_PATH')
       expect(blob).toContain('SOCKET_CLI_ORG_SLUG')
+      expect(blob).toContain('SOCKET_CLI_PROFILE')
+      expect(blob).toContain('SOCKET_CLI_SKIP_HOOKS')
+      expect(blob).toContain('SOCKET_CLI_SSO_ISSUER')
       expect(blob).toContain('SOCKET_CLI_ACCEPT_RISKS')
       expect(blob).toContain('SOCKET_CLI_VIEW_ALL_RISKS')
       expect(blob).toContain('Environment variables for development')
       expect(blob).toContain('SOCKET_CLI_API_BASE_URL')
       expect(blob).toContain('SOCKET_CLI_API_PROXY')
+      expect(blob).toContain('NO_PROXY')
+      expect(blob).toContain('SSL_CERT_FILE')
       expect(blob).toContain('SOCKET_CLI_API_TIMEOUT')
+      expect(blob).toContain('SOCKET_CLI_API_CONCURRENCY')
       expect(blob).toContain('SOCKET_CLI_DEBUG')
       expect(blob).toContain('DEBUG')
     })
@@ -36,6 +36,7 @@ const mockGetConfigValueOrUndef = vi.hoisted(() => vi.fn())
 const mockIsConfigFromFlag = vi.hoisted(() => vi.fn(() => false))
 const mockOverrideCachedConfig = vi.hoisted(() => vi.fn())
 const mockOverrideConfigApiToken = vi.hoisted(() => vi.fn())
+const mockSetConfigFlagValue = vi.hoisted(() => vi.fn())
 const mockIsDebug = vi.hoisted(() => vi.fn(() => false))
 const mockGetVisibleTokenPrefix = vi.hoisted(() => vi.fn(() => 'test'))
 const mockSocketPackageLink = vi.hoisted(() => vi.fn(pkg => pkg))
@@ -101,6 +102,7 @@ vi.mock(import('../../../../src/util/config.mts'), () => ({
   isConfigFromFlag: mockIsConfigFromFlag,
   overrideCachedConfig: mockOverrideCachedConfig,
   overrideConfigApiToken: mockOverrideConfigApiToken,
+  setConfigFlagValue: mockSetConfigFlagValue,
 }))
 
 // Mock debug utility.
@@ -110,6 +112,7 @@ vi.mock(import('../../../../src/util/debug.mts'), () => ({
 
 // Mock SDK utility.
 vi.mock(import('../../../../src/util/socket/sdk.mts'), () => ({
+  getDefaultProxyUrl: () => undefined,
   getVisibleTokenPrefix: mockGetVisibleTokenPrefix,
 }))
 
@@ -118,6 +121,24 @@ vi.mock(import('../../../../src/util/terminal/link.mts'), () => ({
   socketPackageLink: mockSocketPackageLink,
 }))
 
+// Mock external command lookup.
+const mockFindExternalCommand = vi.hoisted(() =>
+  vi.fn(async () => undefined as string | undefined),
+)
+const mockRunExternalCommand = vi.hoisted(() => vi.fn(async () => 0))
+
+vi.mock(import('../../../../src/util/cli/external-command.mts'), () => ({
+  findExternalCommand: mockFindExternalCommand,
+  runExternalCommand: mockRunExternalCommand,
+}))
+
+// Mock usage telemetry.
+const mockSetUsageCommand = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/telemetry/usage.mts'), () => ({
+  setUsageCommand: mockSetUsageCommand,
+}))
+
 // Mock process.exit.
 vi.spyOn(process, 'exit').mockImplementation(() => {
   throw new Error('process.exit called')
@@ -662,6 +683,91 @@ describe('meow-with-subcommands', () => {
       process.exitCode = undefined
     })
 
+    it('runs a socket-<name> executable for unknown root commands', async () => {
+      mockFindExternalCommand.mockResolvedValueOnce('/usr/local/bin/socket-foo')
+      mockRunExternalCommand.mockResolvedValueOnce(3)
+      process.exitCode = undefined
+      await meowWithSubcommands({
+        name: 'socket',
+        argv: ['foo', 'bar', '--baz'],
+        importMeta: import.meta,
+        subcommands: {
+          scan: { description: 'scan', run: vi.fn() },
+        },
+      })
+      expect(mockFindExternalCommand).toHaveBeenCalledWith('foo')
+      expect(mockRunExternalCommand).toHaveBeenCalledWith(
+        '/usr/local/bin/socket-foo',
+        ['bar', '--baz'],
+        expect.objectContaining({ outputKind: 'text' }),
+      )
+      expect(process.exitCode).toBe(3)
+      expect(mockLogger.fail).not.toHaveBeenCalled()
+      process.exitCode = undefined
+    })
+
+    it('prefers built-in commands over socket-<name> executables', async () => {
+      const runSpy = vi.fn(async () => undefined)
+      await meowWithSubcommands({
+        name: 'socket',
+        argv: ['scan'],
+        importMeta: import.meta,
+        subcommands: {
+          scan: { description: 'scan', run: runSpy },
+        },
+      })
+      expect(runSpy).toHaveBeenCalled()
+      expect(mockFindExternalCommand).not.toHaveBeenCalled()
+    })
+
+    it('records built-in command names for usage telemetry', async () => {
+      await meowWithSubcommands(
+        {
+          name: 'socket',
+          argv: ['sc', 'some-arg'],
+          importMeta: import.meta,
+          subcommands: {
+            scan: { description: 'scan', run: vi.fn(async () => undefined) },
+          },
+        },
+        {
+          aliases: {
+            sc: { description: 'scan', hidden: false, argv: ['scan'] },
+          },
+        },
+      )
+      expect(mockSetUsageCommand).toHaveBeenCalledWith('socket scan')
+    })
+
+    it('does not record the names of socket-<name> executables', async () => {
+      mockFindExternalCommand.mockResolvedValueOnce('/usr/local/bin/socket-foo')
+      await meowWithSubcommands({
+        name: 'socket',
+        argv: ['foo'],
+        importMeta: import.meta,
+        subcommands: {
+          scan: { description: 'scan', run: vi.fn() },
+        },
+      })
+      expect(mockSetUsageCommand).not.toHaveBeenCalled()
+      process.exitCode = undefined
+    })
+
+    it('only looks for socket-<name> executables at the root', async () => {
+      process.exitCode = undefined
+      await meowWithSubcommands({
+        name: 'app',
+        argv: ['totally-different'],
+        importMeta: import.meta,
+        subcommands: {
+          scan: { description: 'scan', run: vi.fn() },
+        },
+      })
+      expect(mockFindExternalCommand).not.toHaveBeenCalled()
+      expect(process.exitCode).toBe(2)
+      process.exitCode = undefined
+    })
+
     it('forwards purl-like arguments via package score shortcut', async () => {
       // socket pkg:npm/lodash → calls itself recursively with [package, deep, ...]
       const packageRun = vi.fn(async () => undefined)
@@ -1035,6 +1141,72 @@ describe('meow-with-subcommands', () => {
       }
     })
 
+    it('prefers the --config flag over SOCKET_CLI_CONFIG', async () => {
+      const originalConfig = process.env['SOCKET_CLI_CONFIG']
+      process.env['SOCKET_CLI_CONFIG'] = '{"defaultOrg":"env-org"}'
+      mockOverrideCachedConfig.mockReturnValue({ ok: true })
+      const subcommands = {
+        scan: {
+          description: 'scan',
+          run: vi.fn(async () => undefined),
+        },
+      }
+      try {
+        await meowWithSubcommands({
+          name: 'socket',
+          argv: ['scan', '--config', '{"defaultOrg":"flag-org"}'],
+          importMeta: import.meta,
+          subcommands,
+        })
+        expect(mockOverrideCachedConfig).toHaveBeenCalledWith(
+          '{"defaultOrg":"flag-org"}',
+          '--config',
+        )
+        expect(mockOverrideCachedConfig).not.toHaveBeenCalledWith(
+          expect.anything(),
+          'SOCKET_CLI_CONFIG',
+        )
+      } finally {
+        if (originalConfig === undefined) {
+          delete process.env['SOCKET_CLI_CONFIG']
+        } else {
+          process.env['SOCKET_CLI_CONFIG'] = originalConfig
+        }
+      }
+    })
+
+    it('sets the --org flag as the defaultOrg of this invocation', async () => {
+      const subcommands = {
+        scan: {
+          description: 'scan',
+          run: vi.fn(async () => undefined),
+        },
+      }
+      await meowWithSubcommands({
+        name: 'socket',
+        argv: ['scan', '--org', 'acme'],
+        importMeta: import.meta,
+        subcommands,
+      })
+      expect(mockSetConfigFlagValue).toHaveBeenCalledWith(
+        'defaultOrg',
+        'acme',
+        '--org',
+      )
+
+      await meowWithSubcommands({
+        name: 'socket',
+        argv: ['scan'],
+        importMeta: import.meta,
+        subcommands,
+      })
+      expect(mockSetConfigFlagValue).toHaveBeenLastCalledWith(
+        'defaultOrg',
+        undefined,
+        '--org',
+      )
+    })
+
     it('applies SOCKET_API_TOKEN override (line 362)', async () => {
       const originalNoToken = process.env['SOCKET_CLI_NO_API_TOKEN']
       const originalToken = process.env['SOCKET_API_TOKEN'] // socket-api-token-getter: allow direct-env -- test bootstrap; saves the ambient token to restore after simulating the override.
@@ -0,0 +1,85 @@
+/**
+ * Unit tests for the user config file.
+ *
+ * Purpose: Tests decoding the base64 encoded JSON of the config file and
+ * writing it back without losing the keys already in it.
+ *
+ * Test Coverage: - Decoding and copying supported keys - Unparsable files -
+ * Preserving existing keys on write.
+ *
+ * Testing Approach: Uses a temporary config file.
+ *
+ * Related Files: - src/util/config-file.mts (implementation)
+ * - src/util/config.mts (config cache)
+ */
+
+import { mkdtempSync, readFileSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  parseConfigFile,
+  writeConfigFile,
+} from '../../../src/util/config-file.mts'
+
+function encode(config: object): string {
+  return Buffer.from(JSON.stringify(config)).toString('base64')
+}
+
+describe('util/config-file', () => {
+  let tmpDir: string
+  let configFilePath: string
+
+  beforeEach(() => {
+    tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-config-file-'))
+    configFilePath = path.join(tmpDir, 'config.json')
+  })
+
+  afterEach(async () => {
+    await safeDelete(tmpDir, { recursive: true })
+  })
+
+  describe('parseConfigFile', () => {
+    it('decodes the supported keys', () => {
+      expect(
+        parseConfigFile(
+          encode({ bogus: 'x', defaultOrg: 'my-org' }),
+          configFilePath,
+        ),
+      ).toEqual({ defaultOrg: 'my-org' })
+    })
+
+    it('returns undefined for a file that does not parse', () => {
+      expect(
+        parseConfigFile(
+          Buffer.from('not json').toString('base64'),
+          configFilePath,
+        ),
+      ).toBeUndefined()
+    })
+  })
+
+  describe('writeConfigFile', () => {
+    it('keeps the keys already in the file', () => {
+      writeFileSync(configFilePath, encode({ apiToken: 'tok', extra: 1 }))
+
+      const mtime = writeConfigFile(configFilePath, { defaultOrg: 'my-org' })
+
+      expect(typeof mtime).toBe('number')
+      const written = JSON.parse(
+        Buffer.from(readFileSync(configFilePath, 'utf8'), 'base64').toString(
+          'utf8',
+        ),
+      )
+      expect(written).toEqual({
+        apiToken: 'tok',
+        defaultOrg: 'my-org',
+        extra: 1,
+      })
+    })
+  })
+})
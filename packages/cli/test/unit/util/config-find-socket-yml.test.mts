@@ -6,7 +6,7 @@
  *
  * Testing Approach: Uses temporary config files.
  *
- * Related Files: - util/config.mts (implementation)
+ * Related Files: - util/config-layers.mts (implementation)
  */
 
 import { mkdtempSync, writeFileSync } from 'node:fs'
@@ -17,7 +17,7 @@ import { describe, expect, it } from 'vitest'
 
 import { safeDelete, safeMkdirSync } from '@socketsecurity/lib-stable/fs/safe'
 
-import { findSocketYmlSync } from '../../../src/util/config.mts'
+import { findSocketYmlSync } from '../../../src/util/config-layers.mts'
 
 describe('util/config', () => {
   describe('findSocketYmlSync', () => {
@@ -77,6 +77,121 @@ describe('util/config', () => {
       }
     })
 
+    it('should read the defaultOrg of the cli section', async () => {
+      const tmpDir = path.resolve(
+        mkdtempSync(path.join(os.tmpdir(), 'socket-test-')),
+      )
+
+      try {
+        writeFileSync(
+          path.join(tmpDir, 'socket.yml'),
+          'version: 2\n\ncli:\n  org: acme\n  apiToken: ignored\n',
+          'utf8',
+        )
+
+        const result = findSocketYmlSync(tmpDir)
+
+        expect(result.ok && result.data?.parsed.cli).toEqual({
+          defaultOrg: 'acme',
+        })
+      } finally {
+        await safeDelete(tmpDir, { recursive: true })
+      }
+    })
+
+    it('should read the alert modes of the wrapper section', async () => {
+      const tmpDir = path.resolve(
+        mkdtempSync(path.join(os.tmpdir(), 'socket-test-')),
+      )
+
+      try {
+        writeFileSync(
+          path.join(tmpDir, 'socket.yml'),
+          'version: 2\n\nwrapper:\n  nonInteractive: block\n  alerts:\n    installScripts: prompt\n    telemetry: warn\n    malware: maybe\n',
+          'utf8',
+        )
+
+        const result = findSocketYmlSync(tmpDir)
+
+        expect(result.ok && result.data?.parsed.wrapper).toEqual({
+          alerts: { installScripts: 'prompt', telemetry: 'warn' },
+          nonInteractive: 'block',
+        })
+      } finally {
+        await safeDelete(tmpDir, { recursive: true })
+      }
+    })
+
+    it('should merge nested socket.yml files over the repo root', async () => {
+      const tmpDir = path.resolve(
+        mkdtempSync(path.join(os.tmpdir(), 'socket-test-')),
+      )
+      const pkgDir = path.join(tmpDir, 'packages', 'foo')
+
+      try {
+        safeMkdirSync(pkgDir, { recursive: true })
+        safeMkdirSync(path.join(tmpDir, '.git'))
+        writeFileSync(
+          path.join(tmpDir, 'socket.yml'),
+          'version: 2\nprojectIgnorePaths:\n  - packages/foo/fixtures\n  - packages/bar\n  - dist\nissueRules:\n  unmaintained: false\n  gitDependency: true\n',
+          'utf8',
+        )
+        writeFileSync(
+          path.join(pkgDir, 'socket.yml'),
+          'version: 2\nprojectIgnorePaths:\n  - \'!dist\'\nissueRules:\n  gitDependency: false\n',
+          'utf8',
+        )
+
+        const result = findSocketYmlSync(pkgDir)
+
+        expect(result.ok && result.data).toEqual({
+          path: path.join(pkgDir, 'socket.yml'),
+          parsed: {
+            githubApp: {},
+            issueRules: { gitDependency: false, unmaintained: false },
+            projectIgnorePaths: ['/fixtures', 'dist', '!dist'],
+            version: 2,
+          },
+          paths: [
+            path.join(tmpDir, 'socket.yml'),
+            path.join(pkgDir, 'socket.yml'),
+          ],
+        })
+      } finally {
+        await safeDelete(tmpDir, { recursive: true })
+      }
+    })
+
+    it('should stop at a socket.yml with inherit: false', async () => {
+      const tmpDir = path.resolve(
+        mkdtempSync(path.join(os.tmpdir(), 'socket-test-')),
+      )
+      const pkgDir = path.join(tmpDir, 'pkg')
+
+      try {
+        safeMkdirSync(pkgDir, { recursive: true })
+        writeFileSync(
+          path.join(tmpDir, 'socket.yml'),
+          'version: 2\nissueRules:\n  unmaintained: false\n',
+          'utf8',
+        )
+        writeFileSync(
+          path.join(pkgDir, 'socket.yml'),
+          'version: 2\ninherit: false\n',
+          'utf8',
+        )
+
+        const result = findSocketYmlSync(pkgDir)
+
+        expect(result.ok && result.data?.paths).toEqual([
+          path.join(pkgDir, 'socket.yml'),
+        ])
+        expect(result.ok && result.data?.parsed.issueRules).toEqual({})
+      } finally {
+        await safeDelete(tmpDir, { recursive: true })
+      }
+    })
+
     it('returns parse error when socket.yml has invalid YAML (lines 222-228)', async () => {
       // Write a socket.yml with garbage YAML content that fails to parse.
       const tmpDir = path.resolve(
@@ -0,0 +1,212 @@
+/**
+ * Unit tests for the config resolution order.
+ *
+ * Purpose: Tests that every config key is resolved through flags, env vars,
+ * the repo socket.yml, the user config and the system config, in that order,
+ * and that the source of each value is reported.
+ *
+ * Testing Approach: Uses temporary socket.yml and system config files,
+ * environment variable mocking and a mocked working directory.
+ *
+ * Related Files: - util/config-layers.mts (implementation)
+ * - util/config.mts (user layer)
+ */
+
+import { mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  formatConfigSource,
+  getConfigValueLayers,
+  getConfigValueOrUndef,
+  getConfigValueSource,
+  overrideCachedConfig,
+  overrideConfigApiToken,
+  resetConfigForTesting,
+  setConfigFlagValue,
+} from '../../../src/util/config.mts'
+import { iterateConfigKeySources } from '../../../src/util/config-layers.mts'
+
+const mockGetSocketSystemConfigPath = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../src/constants/paths.mts'), async importOriginal => {
+  const actual = await importOriginal()
+  return {
+    ...actual,
+    getSocketSystemConfigPath: mockGetSocketSystemConfigPath,
+  }
+})
+
+describe('util/config resolution order', () => {
+  let tmpDir: string
+  let originalOrgSlug: string | undefined
+
+  beforeEach(() => {
+    tmpDir = path.resolve(mkdtempSync(path.join(os.tmpdir(), 'socket-cfg-')))
+    originalOrgSlug = process.env['SOCKET_CLI_ORG_SLUG']
+    delete process.env['SOCKET_CLI_ORG_SLUG']
+    vi.spyOn(process, 'cwd').mockReturnValue(tmpDir)
+    mockGetSocketSystemConfigPath.mockReturnValue(
+      path.join(tmpDir, 'system-config.json'),
+    )
+    resetConfigForTesting()
+    overrideCachedConfig('{}')
+  })
+
+  afterEach(async () => {
+    vi.restoreAllMocks()
+    if (originalOrgSlug === undefined) {
+      delete process.env['SOCKET_CLI_ORG_SLUG']
+    } else {
+      process.env['SOCKET_CLI_ORG_SLUG'] = originalOrgSlug
+    }
+    resetConfigForTesting()
+    await safeDelete(tmpDir, { recursive: true })
+  })
+
+  function writeSystemConfig(config: object) {
+    writeFileSync(
+      path.join(tmpDir, 'system-config.json'),
+      JSON.stringify(config),
+    )
+  }
+
+  function writeSocketYml(content: string) {
+    writeFileSync(path.join(tmpDir, 'socket.yml'), content)
+  }
+
+  it('reports no source for a key nobody sets', () => {
+    expect(getConfigValueSource('defaultOrg')).toBeUndefined()
+    expect(formatConfigSource(undefined)).toBe('none (not set)')
+  })
+
+  it('falls back to the system config', () => {
+    writeSystemConfig({ apiProxy: 'http://proxy.example', defaultOrg: 'sys' })
+
+    expect(getConfigValueSource('apiProxy')).toEqual({
+      origin: path.join(tmpDir, 'system-config.json'),
+      source: 'system',
+      value: 'http://proxy.example',
+    })
+  })
+
+  it('prefers the user config over the system config', () => {
+    writeSystemConfig({ defaultOrg: 'sys-org' })
+    overrideCachedConfig('{"defaultOrg":"user-org"}')
+
+    expect(getConfigValueSource('defaultOrg')).toEqual({
+      origin: '--config',
+      source: 'user',
+      value: 'user-org',
+    })
+  })
+
+  it('prefers the repo socket.yml over the user config', () => {
+    overrideCachedConfig('{"defaultOrg":"user-org"}')
+    writeSocketYml('version: 2\ncli:\n  defaultOrg: repo-org\n')
+
+    expect(getConfigValueOrUndef('defaultOrg')).toBe('repo-org')
+    expect(getConfigValueSource('org')).toMatchObject({
+      origin: path.join(tmpDir, 'socket.yml'),
+      source: 'repo',
+    })
+  })
+
+  it('does not let the repo socket.yml set other keys', () => {
+    writeSocketYml(
+      'version: 2\ncli:\n  apiBaseUrl: https://evil.example\n  apiToken: x\n',
+    )
+
+    expect(getConfigValueOrUndef('apiBaseUrl')).toBeUndefined()
+    expect(getConfigValueOrUndef('apiToken')).toBeUndefined()
+  })
+
+  it('prefers env vars over the repo socket.yml', () => {
+    writeSocketYml('version: 2\ncli:\n  defaultOrg: repo-org\n')
+    process.env['SOCKET_CLI_ORG_SLUG'] = 'env-org'
+
+    expect(getConfigValueSource('defaultOrg')).toEqual({
+      origin: 'SOCKET_CLI_ORG_SLUG',
+      source: 'env',
+      value: 'env-org',
+    })
+  })
+
+  it('prefers flags over env vars', () => {
+    process.env['SOCKET_CLI_ORG_SLUG'] = 'env-org'
+    setConfigFlagValue('defaultOrg', 'flag-org', '--org')
+
+    expect(getConfigValueSource('defaultOrg')).toEqual({
+      origin: '--org',
+      source: 'flag',
+      value: 'flag-org',
+    })
+    expect(formatConfigSource(getConfigValueSource('defaultOrg'))).toBe(
+      'flag (--org)',
+    )
+
+    setConfigFlagValue('defaultOrg', undefined, '--org')
+    expect(getConfigValueOrUndef('defaultOrg')).toBe('env-org')
+  })
+
+  it('lists every layer that sets a key, the effective one first', () => {
+    writeSystemConfig({ defaultOrg: 'sys-org' })
+    writeSocketYml('version: 2\ncli:\n  defaultOrg: repo-org\n')
+    process.env['SOCKET_CLI_ORG_SLUG'] = 'env-org'
+
+    expect(
+      getConfigValueLayers('org').map(({ source, value }) => [source, value]),
+    ).toEqual([
+      ['env', 'env-org'],
+      ['repo', 'repo-org'],
+      ['system', 'sys-org'],
+    ])
+    expect(getConfigValueLayers('apiProxy')).toEqual([])
+  })
+
+  it('reads the user layer through the given reader', () => {
+    writeSystemConfig({ defaultOrg: 'sys-org' })
+    const getUserSource = vi.fn(() => ({
+      origin: 'test',
+      source: 'user' as const,
+      value: 'user-org',
+    }))
+
+    expect([...iterateConfigKeySources('defaultOrg', getUserSource)]).toEqual([
+      { origin: 'test', source: 'user', value: 'user-org' },
+      {
+        origin: path.join(tmpDir, 'system-config.json'),
+        source: 'system',
+        value: 'sys-org',
+      },
+    ])
+    expect(getUserSource).toHaveBeenCalledWith('defaultOrg')
+  })
+
+  it('lets SOCKET_CLI_NO_API_TOKEN veto every token', () => {
+    writeSystemConfig({ apiToken: 'sys-token' })
+    overrideCachedConfig('{"apiToken":"user-token"}')
+    overrideConfigApiToken(undefined)
+
+    expect(getConfigValueOrUndef('apiToken')).toBeUndefined()
+    expect(getConfigValueSource('apiToken')).toMatchObject({
+      origin: 'SOCKET_CLI_NO_API_TOKEN',
+      source: 'env',
+    })
+  })
+
+  it('keeps profiles out of the system config', () => {
+    writeSystemConfig({
+      activeProfile: 'work',
+      profiles: { work: { defaultOrg: 'work-org' } },
+    })
+
+    expect(getConfigValueOrUndef('activeProfile')).toBeUndefined()
+    expect(getConfigValueOrUndef('defaultOrg')).toBeUndefined()
+  })
+})
@@ -0,0 +1,162 @@
+/**
+ * Unit tests for config profiles.
+ *
+ * Purpose: Tests that the profile keys of the config are read from the
+ * active profile, and that API tokens kept in the keychain are resolved
+ * through it.
+ *
+ * Test Coverage: - Active profile from activeProfile and SOCKET_CLI_PROFILE
+ * - The default profile as the top level - Malformed profiles dropped
+ * - Updating a named profile - Keychain references.
+ *
+ * Testing Approach: Overrides the cached config and sets environment
+ * variables.
+ *
+ * Related Files: - util/config.mts (implementation)
+ * - test/unit/util/config.test.mts (config values)
+ */
+
+import { afterEach, describe, expect, it } from 'vitest'
+
+import {
+  getActiveProfileName,
+  getConfigValueOrUndef,
+  getProfileConfig,
+  getProfileNames,
+  isApiTokenInKeychain,
+  overrideCachedConfig,
+  overrideConfigApiToken,
+  resetConfigForTesting,
+  updateProfileConfigValue,
+} from '../../../src/util/config.mts'
+
+describe('util/config profiles', () => {
+  const profileConfig = {
+    apiToken: 'top-token',
+    defaultOrg: 'top-org',
+    profiles: {
+      oss: { apiToken: 'oss-token', defaultOrg: 'oss-org' },
+      work: { apiToken: 'work-token', defaultOrg: 'work-org' },
+    },
+  }
+
+  afterEach(() => {
+    delete process.env['SOCKET_CLI_NO_KEYCHAIN']
+    delete process.env['SOCKET_CLI_PROFILE']
+    resetConfigForTesting()
+  })
+
+  it('uses the top-level keys without an active profile', () => {
+    overrideCachedConfig(JSON.stringify(profileConfig))
+    expect(getActiveProfileName()).toBeUndefined()
+    expect(getConfigValueOrUndef('apiToken')).toBe('top-token')
+    expect(getProfileNames()).toEqual(['oss', 'work'])
+  })
+
+  it('reads profile keys from the active profile', () => {
+    overrideCachedConfig(
+      JSON.stringify({ ...profileConfig, activeProfile: 'work' }),
+    )
+    expect(getActiveProfileName()).toBe('work')
+    expect(getConfigValueOrUndef('apiToken')).toBe('work-token')
+    expect(getConfigValueOrUndef('org')).toBe('work-org')
+    expect(getConfigValueOrUndef('apiProxy')).toBeUndefined()
+  })
+
+  it('reads the API endpoint keys from the active profile', () => {
+    overrideCachedConfig(
+      JSON.stringify({
+        activeProfile: 'eu',
+        apiBaseUrl: 'https://api.socket.dev/v0/',
+        profiles: {
+          eu: {
+            apiAuthScheme: 'bearer',
+            apiBaseUrl: 'https://eu.socket.example/v0/',
+          },
+        },
+      }),
+    )
+    expect(getConfigValueOrUndef('apiAuthScheme')).toBe('bearer')
+    expect(getConfigValueOrUndef('apiBaseUrl')).toBe(
+      'https://eu.socket.example/v0/',
+    )
+    expect(getProfileConfig('eu')).toEqual({
+      apiAuthScheme: 'bearer',
+      apiBaseUrl: 'https://eu.socket.example/v0/',
+    })
+  })
+
+  it('prefers SOCKET_CLI_PROFILE over activeProfile', () => {
+    process.env['SOCKET_CLI_PROFILE'] = 'oss'
+    overrideCachedConfig(
+      JSON.stringify({ ...profileConfig, activeProfile: 'work' }),
+    )
+    expect(getActiveProfileName()).toBe('oss')
+    expect(getConfigValueOrUndef('defaultOrg')).toBe('oss-org')
+  })
+
+  it('treats the default profile as the top level', () => {
+    overrideCachedConfig(
+      JSON.stringify({ ...profileConfig, activeProfile: 'default' }),
+    )
+    expect(getActiveProfileName()).toBeUndefined()
+    expect(getConfigValueOrUndef('defaultOrg')).toBe('top-org')
+  })
+
+  it('prefers an API token override over the profile token', () => {
+    overrideCachedConfig(
+      JSON.stringify({ ...profileConfig, activeProfile: 'work' }),
+    )
+    overrideConfigApiToken('flag-token')
+    expect(getConfigValueOrUndef('apiToken')).toBe('flag-token')
+    expect(getConfigValueOrUndef('defaultOrg')).toBe('work-org')
+  })
+
+  it('drops malformed profiles and unknown keys', () => {
+    overrideCachedConfig(
+      JSON.stringify({
+        profiles: {
+          '-bad': { apiToken: 'x' },
+          good: { apiToken: 'good-token', bogus: 'x' },
+          nope: 'string',
+        },
+      }),
+    )
+    expect(getProfileNames()).toEqual(['good'])
+    expect(getProfileConfig('good')).toEqual({ apiToken: 'good-token' })
+    expect(getProfileConfig('constructor')).toBeUndefined()
+  })
+
+  it('updates a named profile', () => {
+    overrideCachedConfig(JSON.stringify(profileConfig))
+    const result = updateProfileConfigValue('oss', 'apiToken', 'new-token')
+    expect(result).toMatchObject({
+      ok: true,
+      message: "Config key 'apiToken' of profile 'oss' was updated",
+    })
+    expect(getProfileConfig('oss')?.apiToken).toBe('new-token')
+    // The top level is left alone.
+    expect(getConfigValueOrUndef('apiToken')).toBe('top-token')
+  })
+
+  it('resolves keychain references through the keychain', () => {
+    process.env['SOCKET_CLI_NO_KEYCHAIN'] = '1'
+    overrideCachedConfig(
+      JSON.stringify({
+        apiToken: '<keychain>',
+        profiles: { work: { apiToken: 'work-token' } },
+      }),
+    )
+    expect(isApiTokenInKeychain('default')).toBe(true)
+    expect(isApiTokenInKeychain('work')).toBe(false)
+    // The keychain is disabled, so there is no token to read.
+    expect(getConfigValueOrUndef('apiToken')).toBeUndefined()
+  })
+
+  it('rejects invalid profile names', () => {
+    overrideCachedConfig('{}')
+    expect(
+      updateProfileConfigValue('../x', 'apiToken', 'token'),
+    ).toMatchObject({ ok: false, message: 'Invalid profile name: ../x' })
+  })
+})
@@ -12,6 +12,7 @@
  * mocking.
  *
  * Related Files: - util/config.mts (implementation)
+ * - test/unit/util/config-profiles.test.mts (profiles and keychain)
  */
 
 import { mkdtempSync, readFileSync, writeFileSync } from 'node:fs'
@@ -107,6 +108,18 @@ describe('util/config', () => {
       )
       expect(result.ok).toBe(true)
     })
+
+    it('stores disableSelfUpdate=true as a boolean', () => {
+      const result = updateConfigValue('disableSelfUpdate', 'true')
+      expect(result.ok).toBe(true)
+      expect(getConfigValueOrUndef('disableSelfUpdate')).toBe(true)
+    })
+
+    it('stores boolean values of boolean keys as they are', () => {
+      const result = updateConfigValue('telemetry', false)
+      expect(result.ok).toBe(true)
+      expect(getConfigValueOrUndef('telemetry')).toBe(false)
+    })
   })
 
   describe('overrideConfigApiToken', () => {
@@ -265,6 +278,32 @@ describe('util/config', () => {
       expect(result.data).toBe('test-org')
     })
 
+    it('should write profile keys into the active profile', async () => {
+      const settingsDir = path.join(tmpDir, 'socket', 'settings')
+      safeMkdirSync(settingsDir)
+      const configFilePath = path.join(settingsDir, 'config.json')
+      const initialJson = JSON.stringify({
+        activeProfile: 'work',
+        apiToken: 'top-token',
+        profiles: { work: { apiToken: 'work-token' } },
+      })
+      writeFileSync(configFilePath, Buffer.from(initialJson).toString('base64'))
+      resetConfigForTesting()
+
+      updateConfigValue('defaultOrg', 'work-org')
+      await new Promise(resolve => process.nextTick(resolve))
+
+      const finalRaw = readFileSync(configFilePath, 'utf8')
+      const finalConfig = JSON.parse(
+        Buffer.from(finalRaw, 'base64').toString('utf8'),
+      )
+      expect(finalConfig.defaultOrg).toBeUndefined()
+      expect(finalConfig.apiToken).toBe('top-token')
+      expect(finalConfig.profiles).toEqual({
+        work: { apiToken: 'work-token', defaultOrg: 'work-org' },
+      })
+    })
+
     it('handles skipAskToPersistDefaultOrg with string "true"', () => {
       resetConfigForTesting()
       const r = updateConfigValue('skipAskToPersistDefaultOrg', 'true')
@@ -342,6 +381,11 @@ describe('util/config', () => {
       expect(isSupportedConfigKey('apiToken')).toBe(true)
     })
 
+    it('returns true for the API endpoint keys', () => {
+      expect(isSupportedConfigKey('apiAuthScheme')).toBe(true)
+      expect(isSupportedConfigKey('apiBaseUrl')).toBe(true)
+    })
+
     it('returns false for unknown keys', () => {
       expect(isSupportedConfigKey('totally-bogus')).toBe(false)
     })
@@ -0,0 +1,227 @@
+/**
+ * Unit tests for container layer flattening.
+ *
+ * Purpose: Tests the tar reader and the overlay semantics used to flatten
+ * image layers into the files a container scan needs.
+ *
+ * Test Coverage: - ustar and pax long names - Reading tar streams in chunks
+ * - Gzip and unsupported layer compression - Later layers replacing files -
+ * `.wh.` whiteouts and opaque directories - Hardlinks - Include filtering.
+ *
+ * Related Files: - src/util/container/filesystem.mts (implementation) -
+ * src/util/container/tar.mts (tar reader)
+ */
+
+import { Readable } from 'node:stream'
+import { buffer } from 'node:stream/consumers'
+import { gzipSync } from 'node:zlib'
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  applyLayer,
+  normalizeLayerPath,
+  readContainerFiles,
+} from '../../../../src/util/container/filesystem.mts'
+import {
+  decompressLayer,
+  listTarBufferEntries,
+  parsePaxHeaders,
+  readTarStream,
+} from '../../../../src/util/container/tar.mts'
+import { makeTarArchive } from '../../../helpers/tar-archive.mts'
+
+const includeAll = () => true
+
+describe('container filesystem', () => {
+  describe('tar reader', () => {
+    it('should list entries with pax long names', () => {
+      const longName = `app/${'nested/'.repeat(20)}package.json`
+      const entries = listTarBufferEntries(
+        makeTarArchive([
+          { name: 'app/', type: '5' },
+          { name: longName, content: '{}' },
+          { name: 'app/link', type: '2', linkname: 'package.json' },
+        ]),
+      )
+
+      expect(
+        entries.map(({ linkname, name, size, type }) => ({
+          linkname,
+          name,
+          size,
+          type,
+        })),
+      ).toEqual([
+        { linkname: '', name: 'app/', size: 0, type: 'directory' },
+        { linkname: '', name: longName, size: 2, type: 'file' },
+        {
+          linkname: 'package.json',
+          name: 'app/link',
+          size: 0,
+          type: 'symlink',
+        },
+      ])
+    })
+
+    it('should parse pax records', () => {
+      expect(
+        parsePaxHeaders(Buffer.from('16 path=a/b/c.d\n9 size=7\n')),
+      ).toEqual({ path: 'a/b/c.d', size: '7' })
+    })
+
+    it('should read a tar stream in chunks, keeping only wanted data', async () => {
+      const tar = makeTarArchive([
+        { name: `app/${'nested/'.repeat(20)}package.json`, content: '{}' },
+        { name: 'app/big.bin', content: 'x'.repeat(5000) },
+        { name: 'app/go.mod', content: 'module a' },
+      ])
+      const chunks: Buffer[] = []
+      for (let i = 0; i < tar.length; i += 100) {
+        chunks.push(tar.subarray(i, i + 100))
+      }
+
+      const read: Array<[string, string | undefined]> = []
+      for await (const { data, entry } of readTarStream(
+        Readable.from(chunks),
+        entry => !entry.name.endsWith('.bin'),
+      )) {
+        read.push([entry.name, data?.toString()])
+      }
+
+      expect(read).toEqual([
+        [`app/${'nested/'.repeat(20)}package.json`, '{}'],
+        ['app/big.bin', undefined],
+        ['app/go.mod', 'module a'],
+      ])
+    })
+
+    it('should detect the layer compression', async () => {
+      const tar = makeTarArchive([{ name: 'a', content: 'x' }])
+
+      expect(await buffer(decompressLayer(tar)!)).toEqual(tar)
+      expect(await buffer(decompressLayer(gzipSync(tar))!)).toEqual(tar)
+      // zstd magic.
+      expect(
+        decompressLayer(Buffer.from([0x28, 0xb5, 0x2f, 0xfd, 0, 0])),
+      ).toBeUndefined()
+    })
+  })
+
+  describe('normalizeLayerPath', () => {
+    it('should make paths root relative', () => {
+      expect(normalizeLayerPath('./usr/lib/os-release')).toBe(
+        'usr/lib/os-release',
+      )
+      expect(normalizeLayerPath('/app/../etc/')).toBe('etc')
+    })
+  })
+
+  describe('applyLayer', () => {
+    it('should apply whiteouts from later layers', async () => {
+      const files = new Map<string, Buffer>()
+      await applyLayer(
+        files,
+        makeTarArchive([
+          { name: 'app/package.json', content: 'v1' },
+          { name: 'app/old/requirements.txt', content: 'flask' },
+          { name: 'srv/go.mod', content: 'module a' },
+          { name: 'srv/keep/go.mod', content: 'module b' },
+        ]),
+        includeAll,
+      )
+      await applyLayer(
+        files,
+        gzipSync(
+          makeTarArchive([
+            { name: 'app/package.json', content: 'v2' },
+            { name: 'app/.wh.old', content: '' },
+            { name: 'srv/keep/go.mod', content: 'module c' },
+            { name: 'srv/.wh..wh..opq', content: '' },
+          ]),
+        ),
+        includeAll,
+      )
+
+      expect(
+        Object.fromEntries([...files].map(([k, v]) => [k, v.toString()])),
+      ).toEqual({
+        'app/package.json': 'v2',
+        // Re-added in the same layer as the opaque marker.
+        'srv/keep/go.mod': 'module c',
+      })
+    })
+
+    it('should drop files replaced by excluded entries', async () => {
+      const files = new Map([['app/package.json', Buffer.from('{}')]])
+      await applyLayer(
+        files,
+        makeTarArchive([
+          { name: 'app/package.json', type: '2', linkname: '/dev/null' },
+          { name: 'app/README.md', content: 'hi' },
+        ]),
+        filepath => filepath.endsWith('.json'),
+      )
+
+      expect(files.size).toBe(0)
+    })
+
+    it('should resolve hardlinks to kept files', async () => {
+      const files = new Map<string, Buffer>()
+      await applyLayer(
+        files,
+        makeTarArchive([
+          { name: 'a/package.json', content: '{"name":"a"}' },
+          { name: 'b/package.json', type: '1', linkname: 'a/package.json' },
+        ]),
+        includeAll,
+      )
+
+      expect(files.get('b/package.json')?.toString()).toBe('{"name":"a"}')
+    })
+
+    it('should report unsupported layers', async () => {
+      expect(
+        await applyLayer(
+          new Map(),
+          Buffer.from([0x28, 0xb5, 0x2f, 0xfd]),
+          includeAll,
+        ),
+      ).toBe(false)
+    })
+  })
+
+  describe('readContainerFiles', () => {
+    it('should flatten layers in order', async () => {
+      const progress: number[] = []
+      const { files, skippedLayers } = await readContainerFiles(
+        {
+          close: () => {},
+          layers: [
+            {
+              digest: 'sha256:1',
+              read: async () =>
+                makeTarArchive([{ name: 'etc/os-release', content: 'ID=a' }]),
+            },
+            {
+              digest: 'sha256:2',
+              read: async () => Buffer.from([0x28, 0xb5, 0x2f, 0xfd]),
+            },
+            {
+              digest: 'sha256:3',
+              read: async () =>
+                makeTarArchive([{ name: 'etc/os-release', content: 'ID=b' }]),
+            },
+          ],
+          name: 'test',
+        },
+        includeAll,
+        index => progress.push(index),
+      )
+
+      expect(files.get('etc/os-release')?.toString()).toBe('ID=b')
+      expect(skippedLayers).toEqual(['sha256:2'])
+      expect(progress).toEqual([0, 1, 2])
+    })
+  })
+})
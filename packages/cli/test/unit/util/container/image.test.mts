@@ -0,0 +1,309 @@
+/**
+ * Unit tests for container image loading.
+ *
+ * Purpose: Tests resolving image references and reading image manifests from
+ * `docker save` tarballs, OCI layout tarballs and registries.
+ *
+ * Test Coverage: - Reference parsing with Docker Hub defaults - Platform
+ * selection from image indexes - docker save and OCI layout archives -
+ * Registry pulls with bearer token challenges.
+ *
+ * Related Files: - src/util/container/image.mts (implementation)
+ * - src/util/container/image-manifest.mts (index resolution)
+ */
+
+import { mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterAll, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { safeDeleteSync } from '@socketsecurity/lib-stable/fs/safe'
+
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  socketHttpRequest: mockSocketHttpRequest,
+}))
+
+import {
+  formatImageReference,
+  loadImageArchive,
+  parseBearerChallenge,
+  parseImageReference,
+  pullRegistryImage,
+} from '../../../../src/util/container/image.mts'
+import { selectPlatformManifest } from '../../../../src/util/container/image-manifest.mts'
+import { makeTarArchive } from '../../../helpers/tar-archive.mts'
+
+const tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-image-test-'))
+
+const INDEX_MANIFESTS = [
+  {
+    digest: 'sha256:aaa',
+    platform: { os: 'linux', architecture: 'amd64' },
+  },
+  {
+    digest: 'sha256:bbb',
+    platform: { os: 'linux', architecture: 'arm64', variant: 'v8' },
+  },
+  {
+    digest: 'sha256:ccc',
+    platform: { os: 'unknown', architecture: 'unknown' },
+    annotations: { 'vnd.docker.reference.type': 'attestation-manifest' },
+  },
+]
+
+function writeArchive(name: string, files: Record<string, unknown>): string {
+  const filepath = path.join(tmpDir, name)
+  writeFileSync(
+    filepath,
+    makeTarArchive(
+      Object.entries(files).map(([entryName, content]) => ({
+        name: entryName,
+        content: Buffer.isBuffer(content) ? content : JSON.stringify(content),
+      })),
+    ),
+  )
+  return filepath
+}
+
+function jsonResponse(body: unknown, init: Record<string, unknown> = {}) {
+  const text = JSON.stringify(body)
+  return {
+    ok: true,
+    status: 200,
+    statusText: 'OK',
+    headers: {},
+    body: Buffer.from(text),
+    text: () => text,
+    ...init,
+  }
+}
+
+describe('container image', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    process.env['DOCKER_CONFIG'] = path.join(tmpDir, 'no-docker-config')
+  })
+
+  afterAll(() => {
+    delete process.env['DOCKER_CONFIG']
+    safeDeleteSync(tmpDir, { force: true })
+  })
+
+  describe('parseImageReference', () => {
+    it('should apply Docker Hub defaults', () => {
+      expect(parseImageReference('nginx')).toEqual({
+        registry: 'docker.io',
+        repository: 'library/nginx',
+        tag: 'latest',
+      })
+      expect(parseImageReference('bitnami/redis:7.2')).toEqual({
+        registry: 'docker.io',
+        repository: 'bitnami/redis',
+        tag: '7.2',
+      })
+    })
+
+    it('should detect registries, ports and digests', () => {
+      expect(parseImageReference('localhost:5000/app@sha256:abc123')).toEqual({
+        registry: 'localhost:5000',
+        repository: 'app',
+        digest: 'sha256:abc123',
+      })
+      expect(parseImageReference('ghcr.io/acme/api:1.4.2')).toEqual({
+        registry: 'ghcr.io',
+        repository: 'acme/api',
+        tag: '1.4.2',
+      })
+    })
+
+    it('should reject invalid references', () => {
+      expect(parseImageReference('Not An Image')).toBeUndefined()
+      expect(parseImageReference('app@latest')).toBeUndefined()
+    })
+
+    it('should format references back to their short form', () => {
+      expect(formatImageReference(parseImageReference('nginx:1.25')!)).toBe(
+        'nginx:1.25',
+      )
+      expect(
+        formatImageReference(parseImageReference('ghcr.io/acme/api@sha256:ab')!),
+      ).toBe('ghcr.io/acme/api@sha256:ab')
+    })
+  })
+
+  describe('selectPlatformManifest', () => {
+    it('should pick the requested platform and skip attestations', () => {
+      expect(selectPlatformManifest(INDEX_MANIFESTS)?.digest).toBe('sha256:aaa')
+      expect(
+        selectPlatformManifest(INDEX_MANIFESTS, 'linux/arm64')?.digest,
+      ).toBe('sha256:bbb')
+      expect(
+        selectPlatformManifest(INDEX_MANIFESTS, 'unknown/unknown'),
+      ).toBeUndefined()
+    })
+  })
+
+  describe('parseBearerChallenge', () => {
+    it('should parse the challenge parameters', () => {
+      expect(
+        parseBearerChallenge(
+          'Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"',
+        ),
+      ).toEqual({
+        realm: 'https://auth.docker.io/token',
+        service: 'registry.docker.io',
+        scope: 'repository:library/nginx:pull',
+      })
+      expect(parseBearerChallenge('Basic realm="x"')).toBeUndefined()
+    })
+  })
+
+  describe('loadImageArchive', () => {
+    it('should read docker save tarballs', async () => {
+      const filepath = writeArchive('docker-save.tar', {
+        'manifest.json': [
+          {
+            Config: 'blobs/sha256/cfg',
+            RepoTags: ['acme/api:1.0'],
+            Layers: ['blobs/sha256/l1', 'blobs/sha256/l2'],
+          },
+        ],
+        'blobs/sha256/cfg': { os: 'linux', architecture: 'amd64' },
+        'blobs/sha256/l1': Buffer.from('layer one'),
+        'blobs/sha256/l2': Buffer.from('layer two'),
+      })
+
+      const result = loadImageArchive(filepath)
+
+      expect(result.ok).toBe(true)
+      const image = result.ok ? result.data : undefined
+      expect(image).toMatchObject({
+        digest: 'sha256:cfg',
+        name: 'acme/api:1.0',
+        platform: 'linux/amd64',
+      })
+      expect(image!.layers.map(l => l.digest)).toEqual([
+        'sha256:l1',
+        'sha256:l2',
+      ])
+      expect((await image!.layers[1]!.read()).toString()).toBe('layer two')
+      image!.close()
+    })
+
+    it('should resolve nested indexes in OCI layouts', async () => {
+      const filepath = writeArchive('oci.tar', {
+        'oci-layout': { imageLayoutVersion: '1.0.0' },
+        'index.json': {
+          manifests: [
+            {
+              digest: 'sha256:idx',
+              annotations: { 'io.containerd.image.name': 'app:2' },
+            },
+          ],
+        },
+        'blobs/sha256/idx': { manifests: INDEX_MANIFESTS },
+        'blobs/sha256/bbb': {
+          config: { digest: 'sha256:cfg' },
+          layers: [{ digest: 'sha256:l1' }],
+        },
+        'blobs/sha256/l1': Buffer.from('arm layer'),
+      })
+
+      const result = loadImageArchive(filepath, { platform: 'linux/arm64' })
+
+      expect(result.ok).toBe(true)
+      const image = result.ok ? result.data : undefined
+      expect(image).toMatchObject({
+        digest: 'sha256:cfg',
+        name: 'app:2',
+        platform: 'linux/arm64/v8',
+      })
+      expect((await image!.layers[0]!.read()).toString()).toBe('arm layer')
+      image!.close()
+    })
+
+    it('should fail on archives that are not images', () => {
+      const filepath = writeArchive('other.tar', { 'README.md': 'hi' })
+
+      expect(loadImageArchive(filepath)).toMatchObject({
+        ok: false,
+        message: 'Unsupported image archive',
+      })
+    })
+  })
+
+  describe('pullRegistryImage', () => {
+    it('should authenticate and resolve the platform manifest', async () => {
+      mockSocketHttpRequest
+        .mockResolvedValueOnce(
+          jsonResponse(
+            {},
+            {
+              ok: false,
+              status: 401,
+              statusText: 'Unauthorized',
+              headers: {
+                'www-authenticate':
+                  'Bearer realm="https://auth.docker.io/token",service="registry.docker.io"',
+              },
+            },
+          ),
+        )
+        .mockResolvedValueOnce(jsonResponse({ token: 'tok' }))
+        .mockResolvedValueOnce(
+          jsonResponse({
+            mediaType: 'application/vnd.oci.image.index.v1+json',
+            manifests: INDEX_MANIFESTS,
+          }),
+        )
+        .mockResolvedValueOnce(
+          jsonResponse({
+            mediaType: 'application/vnd.oci.image.manifest.v1+json',
+            config: { digest: 'sha256:cfg' },
+            layers: [{ digest: 'sha256:l1' }],
+          }),
+        )
+
+      const result = await pullRegistryImage(parseImageReference('nginx')!)
+
+      expect(result).toMatchObject({
+        ok: true,
+        data: {
+          digest: 'sha256:cfg',
+          name: 'nginx:latest',
+          platform: 'linux/amd64',
+        },
+      })
+      expect(mockSocketHttpRequest.mock.calls.map(c => c[0])).toEqual([
+        'https://registry-1.docker.io/v2/library/nginx/manifests/latest',
+        'https://auth.docker.io/token?service=registry.docker.io&scope=repository%3Alibrary%2Fnginx%3Apull',
+        'https://registry-1.docker.io/v2/library/nginx/manifests/latest',
+        'https://registry-1.docker.io/v2/library/nginx/manifests/sha256:aaa',
+      ])
+      expect(mockSocketHttpRequest.mock.calls[3]![1].headers).toMatchObject({
+        authorization: 'Bearer tok',
+      })
+    })
+
+    it('should fail when the platform is missing', async () => {
+      mockSocketHttpRequest.mockResolvedValueOnce(
+        jsonResponse({ manifests: INDEX_MANIFESTS }),
+      )
+
+      const result = await pullRegistryImage(parseImageReference('nginx')!, {
+        platform: 'linux/s390x',
+      })
+
+      expect(result).toMatchObject({
+        ok: false,
+        message: 'Image platform not found',
+      })
+      expect(result.ok ? '' : result.cause).toContain(
+        'available: linux/amd64, linux/arm64/v8',
+      )
+    })
+  })
+})
@@ -0,0 +1,168 @@
+/**
+ * Unit tests for container installed package databases.
+ *
+ * Purpose: Tests reading the packages an image has installed from its OS and
+ * language package databases.
+ *
+ * Test Coverage: - os-release parsing - dpkg status with Provides and
+ * Depends - Distroless status.d entries - apk installed database - npm
+ * node_modules and Python dist-info grouping - Installed path detection.
+ *
+ * Related Files: - src/util/container/packages.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  collectInstalledPackages,
+  isContainerInstalledPath,
+  isContainerPackageDatabase,
+  parseApkInstalled,
+  parseDpkgStatus,
+  parseOsRelease,
+} from '../../../../src/util/container/packages.mts'
+
+const DPKG_STATUS = `Package: libc6
+Status: install ok installed
+Architecture: amd64
+Version: 2.36-9+deb12u4
+Provides: libc6-x32
+
+Package: curl
+Status: install ok installed
+Architecture: amd64
+Version: 7.88.1-10+deb12u5
+Depends: libc6 (>= 2.34), libcurl4 (= 7.88.1-10+deb12u5) | libcurl3
+Description: command line tool for transferring data with URL syntax
+ curl is a command line tool for transferring data with URL syntax.
+
+Package: removed-pkg
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 1.0
+`
+
+const APK_INSTALLED = `C:Q1abc=
+P:musl
+V:1.2.4_git20230717-r4
+A:x86_64
+p:so:libc.musl-x86_64.so.1=1
+
+C:Q1def=
+P:busybox
+V:1.36.1-r15
+A:x86_64
+D:so:libc.musl-x86_64.so.1 !busybox-extras
+`
+
+describe('container installed packages', () => {
+  it('should parse os-release', () => {
+    expect(
+      parseOsRelease('NAME="Debian GNU/Linux"\nID=debian\nVERSION_ID="12"\n'),
+    ).toEqual({ id: 'debian', versionId: '12' })
+  })
+
+  it('should parse installed dpkg packages', () => {
+    const deps = parseDpkgStatus(DPKG_STATUS, { id: 'debian', versionId: '12' })
+
+    expect(deps).toEqual([
+      {
+        id: 'libc6:amd64',
+        type: 'deb',
+        namespace: 'debian',
+        name: 'libc6',
+        version: '2.36-9+deb12u4',
+        qualifiers: { arch: 'amd64', distro: 'debian-12' },
+      },
+      {
+        id: 'curl:amd64',
+        type: 'deb',
+        namespace: 'debian',
+        name: 'curl',
+        version: '7.88.1-10+deb12u5',
+        qualifiers: { arch: 'amd64', distro: 'debian-12' },
+        dependencies: ['libc6:amd64'],
+      },
+    ])
+  })
+
+  it('should parse the apk installed database', () => {
+    const deps = parseApkInstalled(APK_INSTALLED, {
+      id: 'alpine',
+      versionId: '3.19.1',
+    })
+
+    expect(deps[1]).toEqual({
+      id: 'busybox',
+      type: 'apk',
+      namespace: 'alpine',
+      name: 'busybox',
+      version: '1.36.1-r15',
+      qualifiers: { arch: 'x86_64', distro: 'alpine-3.19.1' },
+      dependencies: ['musl'],
+    })
+  })
+
+  it('should collect packages grouped by install location', () => {
+    const files = new Map(
+      Object.entries({
+        'etc/os-release': 'ID=ubuntu\nVERSION_ID="22.04"\n',
+        'var/lib/dpkg/status.d/tzdata':
+          'Package: tzdata\nArchitecture: all\nVersion: 2024a-0ubuntu0.22.04\n',
+        'app/node_modules/express/package.json':
+          '{"name":"express","version":"4.18.2"}',
+        'app/node_modules/@types/node/package.json':
+          '{"name":"@types/node","version":"20.11.0"}',
+        'usr/lib/python3/dist-packages/requests-2.31.0.dist-info/METADATA':
+          'Metadata-Version: 2.1\nName: Requests\nVersion: 2.31.0\n\nbody',
+      }).map(([k, v]) => [k, Buffer.from(v)]),
+    )
+
+    const lockfiles = collectInstalledPackages(files)
+
+    expect(
+      lockfiles.map(({ dependencies, ecosystem, file }) => ({
+        ecosystem,
+        file,
+        ids: dependencies.map(d => d.id),
+      })),
+    ).toEqual([
+      { ecosystem: 'deb', file: 'var/lib/dpkg/status.d', ids: ['tzdata'] },
+      {
+        ecosystem: 'npm',
+        file: 'app/node_modules',
+        ids: ['@types/node@20.11.0', 'express@4.18.2'],
+      },
+      {
+        ecosystem: 'pypi',
+        file: 'usr/lib/python3/dist-packages',
+        ids: ['requests@2.31.0'],
+      },
+    ])
+    expect(lockfiles[0]!.dependencies[0]!.qualifiers).toEqual({
+      arch: 'all',
+      distro: 'ubuntu-22.04',
+    })
+    expect(lockfiles[1]!.dependencies[0]).toMatchObject({
+      namespace: '@types',
+      name: 'node',
+    })
+  })
+
+  it('should tell package databases from project manifests', () => {
+    expect(isContainerPackageDatabase('var/lib/dpkg/status')).toBe(true)
+    expect(
+      isContainerPackageDatabase('var/lib/dpkg/status.d/tzdata.md5sums'),
+    ).toBe(false)
+    expect(
+      isContainerPackageDatabase('app/node_modules/a/node_modules/b/package.json'),
+    ).toBe(true)
+    expect(
+      isContainerPackageDatabase('app/node_modules/a/lib/package.json'),
+    ).toBe(false)
+    expect(isContainerInstalledPath('app/node_modules/a/package.json')).toBe(
+      true,
+    )
+    expect(isContainerInstalledPath('app/package.json')).toBe(false)
+  })
+})
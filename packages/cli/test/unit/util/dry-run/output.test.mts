@@ -2,6 +2,7 @@ import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
 
 import {
   outputDryRunDelete,
+  outputDryRunDiff,
   outputDryRunExecute,
   outputDryRunFetch,
   outputDryRunPreview,
@@ -213,7 +214,77 @@ describe('dry-run output utilities', () => {
     })
   })
 
+  describe('outputDryRunDiff', () => {
+    it('renders each file as a unified diff', () => {
+      outputDryRunDiff(
+        'install git hooks',
+        [
+          { after: 'a\nc\n', before: 'a\nb\n', path: 'config.yaml' },
+          { after: '#!/bin/sh\n', path: '.git/hooks/pre-push' },
+        ],
+        ['Move .git/hooks/pre-push aside'],
+      )
+
+      const output = mockStderrLog.mock.calls.map(c => c[0]).join('\n')
+      expect(output).toContain('[DryRun]: Would install git hooks')
+      expect(output).toContain('- Move .git/hooks/pre-push aside')
+      expect(output).toContain(
+        [
+          '  --- a/config.yaml',
+          '  +++ b/config.yaml',
+          '  @@ -1,2 +1,2 @@',
+          '   a',
+          '  -b',
+          '  +c',
+        ].join('\n'),
+      )
+      expect(output).toContain('  --- /dev/null\n  +++ b/.git/hooks/pre-push')
+      expect(mockStdoutLog).not.toHaveBeenCalled()
+    })
+
+    it('marks files that stay the same', () => {
+      outputDryRunDiff('update config', [
+        { after: 'x\n', before: 'x\n', path: 'same.txt' },
+      ])
+
+      const output = mockStderrLog.mock.calls.map(c => c[0]).join('\n')
+      expect(output).toContain('same.txt: unchanged')
+      expect(output).not.toContain('Changes:')
+    })
+  })
+
   describe('outputDryRunUpload', () => {
+    it('renders the API request with its query and JSON body', () => {
+      outputDryRunUpload(
+        'repository',
+        { organization: 'my-org' },
+        {
+          method: 'POST',
+          path: 'orgs/my-org/repos',
+          query: { empty: '', skipped: undefined, tmp: false },
+          body: { name: 'my-repo' },
+        },
+      )
+
+      const output = mockStderrLog.mock.calls.map(c => c[0]).join('\n')
+      expect(output).toContain('API request: POST orgs/my-org/repos?tmp=false')
+      expect(output).toContain(
+        'Request body:\n    {\n      "name": "my-repo"\n    }',
+      )
+    })
+
+    it('describes a non-JSON request body', () => {
+      outputDryRunUpload(
+        'scan',
+        {},
+        { method: 'POST', path: 'orgs/o/full-scans', body: 'the files' },
+      )
+
+      const output = mockStderrLog.mock.calls.map(c => c[0]).join('\n')
+      expect(output).toContain('API request: POST orgs/o/full-scans\n')
+      expect(output).toContain('Request body: the files')
+    })
+
     it('renders nested object details', () => {
       outputDryRunUpload('scan', {
         orgSlug: 'my-org',
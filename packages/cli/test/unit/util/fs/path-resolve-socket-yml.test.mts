@@ -0,0 +1,71 @@
+/**
+ * Unit tests for the socket.yml ignores of scanned package files.
+ *
+ * Purpose: Tests that a scan from the repo root applies the
+ * projectIgnorePaths of nested socket.yml files to their own directories.
+ *
+ * Testing Approach: Scans temporary workspaces with getPackageFilesForScan.
+ *
+ * Related Files: - util/fs/path-resolve.mts (implementation) -
+ * util/config.mts (findSocketYmlSync) - path-resolve.test.mts
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { normalizePath } from '@socketsecurity/lib-stable/paths/normalize'
+import { naturalCompare } from '@socketsecurity/lib-stable/sorts/natural'
+
+import {
+  PACKAGE_JSON,
+  PACKAGE_LOCK_JSON,
+} from '../../../../src/constants/packages.mts'
+import { getPackageFilesForScan } from '../../../../src/util/fs/path-resolve.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+const globPatterns = {
+  npm: {
+    packagejson: {
+      pattern: PACKAGE_JSON,
+    },
+    packagelockjson: {
+      pattern: PACKAGE_LOCK_JSON,
+    },
+  },
+}
+
+async function getSortedPackageFiles(
+  ...args: Parameters<typeof getPackageFilesForScan>
+): Promise<string[]> {
+  const result = await getPackageFilesForScan(...args)
+  return result.toSorted(naturalCompare)
+}
+
+describe('getPackageFilesForScan() with nested socket.yml files', () => {
+  it('should respect ignores from a nested socket.yml', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        { path: 'bar/package-lock.json', content: '{}' },
+        { path: 'bar/package.json', content: '{}' },
+        {
+          path: 'bar/socket.yml',
+          content: 'version: 2\nprojectIgnorePaths:\n  - package-lock.json\n',
+        },
+        { path: 'foo/package-lock.json', content: '{}' },
+        { path: 'foo/package.json', content: '{}' },
+      ],
+    })
+
+    try {
+      const actual = await getSortedPackageFiles(['**/*'], globPatterns, {
+        cwd: workspace.path,
+      })
+      expect(actual.map(normalizePath)).toEqual([
+        normalizePath(workspace.resolve('bar/package.json')),
+        normalizePath(workspace.resolve('foo/package-lock.json')),
+        normalizePath(workspace.resolve('foo/package.json')),
+      ])
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+})
@@ -9,9 +9,14 @@
  *
  * Testing Approach: Tests path utilities with various input formats.
  *
- * Related Files: - util/fs/path-resolve.mts (implementation)
+ * Related Files: - util/fs/path-resolve.mts (implementation) -
+ * path-resolve-socket-yml.test.mts (nested socket.yml ignores)
  */
 
+import { mkdtempSync, rmSync, writeFileSync } from 'node:fs'
+import { tmpdir } from 'node:os'
+import path from 'node:path'
+
 import { beforeEach, describe, expect, it, vi } from 'vitest'
 
 import { WIN32 } from '@socketsecurity/lib-stable/constants/platform'
@@ -23,6 +28,7 @@ import {
   PNPM_LOCK_YAML,
   YARN_LOCK,
 } from '../../../../src/constants/packages.mts'
+import { renderShim } from '../../../../src/commands/wrapper/wrapper-shims.mts'
 import {
   findBinPathDetailsSync,
   getPackageFilesForScan,
@@ -452,5 +458,18 @@ describe('Path Resolve', () => {
         path: '/usr/local/bin/npm',
       })
     })
+
+    it('skips socket wrapper shims', () => {
+      const shimDir = mkdtempSync(path.join(tmpdir(), 'socket-shims-'))
+      try {
+        const shimPath = path.join(shimDir, 'npm')
+        writeFileSync(shimPath, renderShim('npm', shimDir)['npm']!)
+        mockWhichRealSync.mockReturnValue([shimPath, '/usr/local/bin/npm'])
+
+        expect(findBinPathDetailsSync('npm').path).toBe('/usr/local/bin/npm')
+      } finally {
+        rmSync(shimDir, { force: true, recursive: true })
+      }
+    })
   })
 })
@@ -0,0 +1,157 @@
+/**
+ * Unit tests for monorepo workspace helpers.
+ *
+ * Purpose: Tests how files and scanned packages are attributed to the
+ * workspaces of a monorepo.
+ *
+ * Test Coverage: - Longest matching workspace wins - Name and path filters -
+ * Changed workspaces, including root lockfiles - Scan targets - Grouping
+ * artifacts by manifest file.
+ *
+ * Related Files: - src/util/fs/workspaces.mts (implementation)
+ */
+
+import { mkdirSync, mkdtempSync, rmSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  filterMonorepoWorkspaces,
+  findWorkspaceOfFile,
+  getChangedMonorepoWorkspaces,
+  getMonorepoWorkspaceTargets,
+  groupArtifactsByWorkspace,
+} from '../../../../src/util/fs/workspaces.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+import type { MonorepoWorkspace } from '../../../../src/util/fs/workspaces.mts'
+
+const root: MonorepoWorkspace = { name: 'acme', path: '.' }
+const api: MonorepoWorkspace = { name: '@acme/api', path: 'packages/api' }
+const apiPlugin: MonorepoWorkspace = {
+  name: '@acme/api-plugin',
+  path: 'packages/api/plugin',
+}
+const web: MonorepoWorkspace = { name: 'apps/web', path: 'apps/web' }
+
+const workspaces = [root, web, api, apiPlugin]
+
+describe('workspaces', () => {
+  describe('findWorkspaceOfFile', () => {
+    it('picks the longest matching workspace', () => {
+      expect(findWorkspaceOfFile(workspaces, 'packages/api/index.js')).toBe(
+        api,
+      )
+      expect(
+        findWorkspaceOfFile(workspaces, 'packages/api/plugin/package.json'),
+      ).toBe(apiPlugin)
+    })
+
+    it('falls back to the root workspace', () => {
+      expect(findWorkspaceOfFile(workspaces, 'README.md')).toBe(root)
+      expect(findWorkspaceOfFile(workspaces, 'packages/apiary/x.js')).toBe(
+        root,
+      )
+    })
+
+    it('returns undefined without a root workspace', () => {
+      expect(findWorkspaceOfFile([api], 'README.md')).toBeUndefined()
+    })
+  })
+
+  describe('filterMonorepoWorkspaces', () => {
+    it('matches names and paths', () => {
+      expect(filterMonorepoWorkspaces(workspaces, ['@acme/*'])).toEqual([
+        api,
+        apiPlugin,
+      ])
+      expect(filterMonorepoWorkspaces(workspaces, ['apps/*'])).toEqual([web])
+      expect(
+        filterMonorepoWorkspaces(workspaces, ['packages/api', 'acme']),
+      ).toEqual([root, api])
+    })
+
+    it('returns nothing when no pattern matches', () => {
+      expect(filterMonorepoWorkspaces(workspaces, ['@other/*'])).toEqual([])
+    })
+  })
+
+  describe('getChangedMonorepoWorkspaces', () => {
+    it('returns the workspaces with changed files, in order', () => {
+      expect(
+        getChangedMonorepoWorkspaces(workspaces, [
+          'packages/api/plugin/src/a.ts',
+          'apps/web/package.json',
+        ]),
+      ).toEqual([web, apiPlugin])
+    })
+
+    it('selects every workspace when a root lockfile changed', () => {
+      expect(
+        getChangedMonorepoWorkspaces(workspaces, ['pnpm-lock.yaml']),
+      ).toBe(workspaces)
+    })
+
+    it('does not treat nested lockfiles as root lockfiles', () => {
+      expect(
+        getChangedMonorepoWorkspaces(workspaces, [
+          'packages/api/package-lock.json',
+        ]),
+      ).toEqual([api])
+    })
+
+    it('returns nothing without changes', () => {
+      expect(getChangedMonorepoWorkspaces(workspaces, [])).toEqual([])
+    })
+  })
+
+  describe('getMonorepoWorkspaceTargets', () => {
+    it('returns the workspace directories', () => {
+      const cwd = path.join(path.sep, 'repo')
+      expect(getMonorepoWorkspaceTargets(cwd, [api, web])).toEqual([
+        path.join(cwd, 'packages/api'),
+        path.join(cwd, 'apps/web'),
+      ])
+    })
+
+    it('returns only the direct files of the root workspace', () => {
+      const cwd = mkdtempSync(path.join(os.tmpdir(), 'socket-workspaces-'))
+      try {
+        writeFileSync(path.join(cwd, 'package.json'), '{}')
+        mkdirSync(path.join(cwd, 'packages'))
+        expect(getMonorepoWorkspaceTargets(cwd, [root])).toEqual([
+          path.join(cwd, 'package.json'),
+        ])
+      } finally {
+        rmSync(cwd, { force: true, recursive: true })
+      }
+    })
+  })
+
+  describe('groupArtifactsByWorkspace', () => {
+    it('groups artifacts by the workspaces of their manifest files', () => {
+      const shared = artifact('shared', {
+        manifestFiles: [
+          { file: 'packages/api/package.json' },
+          { file: 'apps/web/package.json' },
+        ],
+      })
+      const onlyApi = artifact('only-api', {
+        manifestFiles: [{ file: 'packages/api/package.json' }],
+      })
+      const unattributed = artifact('unattributed', { manifestFiles: [] })
+
+      const groups = groupArtifactsByWorkspace(
+        [shared, onlyApi, unattributed],
+        workspaces,
+      )
+
+      expect(groups.get(api)).toEqual([shared, onlyApi])
+      expect(groups.get(web)).toEqual([shared])
+      expect(groups.get(apiPlugin)).toEqual([])
+      expect(groups.get(root)).toEqual([unattributed])
+    })
+  })
+})
@@ -0,0 +1,165 @@
+/**
+ * Unit tests for the GitHub App authentication of `socket ghapp sync`.
+ *
+ * Tests signing the app JWT, the expiry check of installation tokens, and
+ * picking the installation to exchange the JWT with.
+ *
+ * Related Files: - src/util/git/github-app.mts (implementation) -
+ * src/commands/ghapp/ghapp-sync.mts (consumer)
+ */
+
+import { createVerify, generateKeyPairSync } from 'node:crypto'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockCreateToken = vi.hoisted(() => vi.fn())
+const mockPaginate = vi.hoisted(() => vi.fn())
+vi.mock(import('@octokit/rest'), () => ({
+  Octokit: vi.fn(function MockOctokit() {
+    return {
+      apps: {
+        createInstallationAccessToken: mockCreateToken,
+        listInstallations: vi.fn(),
+      },
+      paginate: mockPaginate,
+    }
+  }),
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/debug/output'), () => ({
+  debug: vi.fn(),
+  debugDir: vi.fn(),
+  debugDirNs: vi.fn(),
+  debugNs: vi.fn(),
+}))
+
+import {
+  createGitHubAppJwt,
+  fetchGitHubAppInstallationToken,
+  isGitHubAppTokenExpiring,
+} from '../../../../src/util/git/github-app.mts'
+
+const { privateKey, publicKey } = generateKeyPairSync('rsa', {
+  modulusLength: 2048,
+  privateKeyEncoding: { format: 'pem', type: 'pkcs8' },
+  publicKeyEncoding: { format: 'pem', type: 'spki' },
+})
+
+function decodePart(part: string): Record<string, unknown> {
+  return JSON.parse(Buffer.from(part, 'base64url').toString('utf8'))
+}
+
+describe('createGitHubAppJwt', () => {
+  it('signs an RS256 JWT issued by the app', () => {
+    const nowMs = 1_700_000_000_000
+    const jwt = createGitHubAppJwt('12345', privateKey, nowMs)
+    const [header, payload, signature] = jwt.split('.')
+    expect(decodePart(header!)).toEqual({ alg: 'RS256', typ: 'JWT' })
+    expect(decodePart(payload!)).toEqual({
+      exp: 1_700_000_540,
+      iat: 1_699_999_940,
+      iss: '12345',
+    })
+    const verified = createVerify('RSA-SHA256')
+      .update(`${header}.${payload}`)
+      .verify(publicKey, signature!, 'base64url')
+    expect(verified).toBe(true)
+  })
+
+  it('throws for a key that is not a PEM private key', () => {
+    expect(() => createGitHubAppJwt('12345', 'not a key')).toThrow()
+  })
+})
+
+describe('isGitHubAppTokenExpiring', () => {
+  const token = {
+    account: 'acme',
+    expiresAt: 60 * 60 * 1000,
+    installationId: 1,
+    token: 'ghs_x',
+  }
+
+  it('is false with more than 5 minutes left', () => {
+    expect(isGitHubAppTokenExpiring(token, 10 * 60 * 1000)).toBe(false)
+  })
+
+  it('is true with less than 5 minutes left', () => {
+    expect(isGitHubAppTokenExpiring(token, 56 * 60 * 1000)).toBe(true)
+  })
+})
+
+describe('fetchGitHubAppInstallationToken', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockCreateToken.mockResolvedValue({
+      data: { expires_at: '2026-01-01T01:00:00Z', token: 'ghs_token' },
+    })
+  })
+
+  it('uses the only installation of the app', async () => {
+    mockPaginate.mockResolvedValue([{ account: { login: 'acme' }, id: 7 }])
+
+    const result = await fetchGitHubAppInstallationToken({
+      appId: '12345',
+      privateKey,
+    })
+
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        account: 'acme',
+        expiresAt: Date.parse('2026-01-01T01:00:00Z'),
+        installationId: 7,
+        token: 'ghs_token',
+      },
+    })
+    expect(mockCreateToken).toHaveBeenCalledWith({ installation_id: 7 })
+  })
+
+  it('uses the given installation', async () => {
+    mockPaginate.mockResolvedValue([
+      { account: { login: 'acme' }, id: 7 },
+      { account: { slug: 'acme-enterprise' }, id: 8 },
+    ])
+
+    const result = await fetchGitHubAppInstallationToken({
+      appId: '12345',
+      installationId: '8',
+      privateKey,
+    })
+
+    expect(result.ok && result.data.account).toBe('acme-enterprise')
+    expect(mockCreateToken).toHaveBeenCalledWith({ installation_id: 8 })
+  })
+
+  it('lists the installations when it cannot pick one', async () => {
+    mockPaginate.mockResolvedValue([
+      { account: { login: 'acme' }, id: 7 },
+      { account: { login: 'other' }, id: 8 },
+    ])
+
+    const result = await fetchGitHubAppInstallationToken({
+      appId: '12345',
+      privateKey,
+    })
+
+    expect(result).toEqual({
+      ok: false,
+      message: 'GitHub App installation not specified',
+      cause:
+        'Installations of GitHub App 12345: 7 (acme), 8 (other). Pass one with --installation-id.',
+    })
+    expect(mockCreateToken).not.toHaveBeenCalled()
+  })
+
+  it('reports an invalid private key without calling GitHub', async () => {
+    const result = await fetchGitHubAppInstallationToken({
+      appId: '12345',
+      privateKey: 'not a key',
+    })
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.message).toBe('Invalid GitHub App private key')
+    expect(mockPaginate).not.toHaveBeenCalled()
+  })
+})
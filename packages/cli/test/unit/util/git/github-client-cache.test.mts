@@ -16,6 +16,7 @@ import {
   cacheFetch,
   getOctokit,
   getOctokitGraphql,
+  setOctokitAuth,
   writeCache,
 } from '../../../../src/util/git/github.mts'
 
@@ -187,3 +188,17 @@ describe('setGitRemoteGithubRepoUrl', () => {
     expect(typeof result).toBe('boolean')
   })
 })
+
+describe('setOctokitAuth', () => {
+  afterEach(() => {
+    setOctokitAuth(undefined)
+  })
+
+  it('replaces the cached clients', () => {
+    const octokit = getOctokit()
+    const graphql = getOctokitGraphql()
+    setOctokitAuth('ghs_installation')
+    expect(getOctokit()).not.toBe(octokit)
+    expect(getOctokitGraphql()).not.toBe(graphql)
+  })
+})
@@ -19,12 +19,16 @@ const mockRebase = vi.hoisted(() => vi.fn())
 const mockShow = vi.hoisted(() => vi.fn())
 const mockAll = vi.hoisted(() => vi.fn())
 const mockNotesCreate = vi.hoisted(() => vi.fn())
+const mockNotesAll = vi.hoisted(() => vi.fn())
+const mockNotesEdit = vi.hoisted(() => vi.fn())
 
 vi.mock(import('@gitbeaker/rest'), () => {
   return {
     Gitlab: class MockGitlab {
       MergeRequestNotes = {
+        all: mockNotesAll,
         create: mockNotesCreate,
+        edit: mockNotesEdit,
       }
       MergeRequests = {
         all: mockAll,
@@ -300,4 +304,63 @@ describe('git/gitlab-provider', () => {
       ).rejects.toThrow('Failed to add comment to MR !123')
     })
   })
+
+  describe('upsertComment', () => {
+    it('adds a note when none has the marker', async () => {
+      mockNotesAll.mockResolvedValueOnce([{ body: 'LGTM', id: 1 }])
+      mockNotesCreate.mockResolvedValueOnce({})
+
+      const result = await provider.upsertComment({
+        body: '<!-- marker -->\nReport',
+        marker: '<!-- marker -->',
+        owner: 'owner',
+        prNumber: 123,
+        repo: 'repo',
+      })
+
+      expect(result).toBe('created')
+      expect(mockNotesCreate).toHaveBeenCalledWith(
+        'owner/repo',
+        123,
+        '<!-- marker -->\nReport',
+      )
+      expect(mockNotesEdit).not.toHaveBeenCalled()
+    })
+
+    it('edits the note that has the marker', async () => {
+      mockNotesAll.mockResolvedValueOnce([
+        { body: 'LGTM', id: 1 },
+        { body: '<!-- marker -->\nOld report', id: 2 },
+      ])
+      mockNotesEdit.mockResolvedValueOnce({})
+
+      const result = await provider.upsertComment({
+        body: '<!-- marker -->\nNew report',
+        marker: '<!-- marker -->',
+        owner: 'owner',
+        prNumber: 123,
+        repo: 'repo',
+      })
+
+      expect(result).toBe('updated')
+      expect(mockNotesEdit).toHaveBeenCalledWith('owner/repo', 123, 2, {
+        body: '<!-- marker -->\nNew report',
+      })
+      expect(mockNotesCreate).not.toHaveBeenCalled()
+    })
+
+    it('throws on failure', async () => {
+      mockNotesAll.mockRejectedValueOnce(new Error('API error'))
+
+      await expect(
+        provider.upsertComment({
+          body: 'Test',
+          marker: '<!-- marker -->',
+          owner: 'owner',
+          prNumber: 123,
+          repo: 'repo',
+        }),
+      ).rejects.toThrow('Failed to update comment on MR !123')
+    })
+  })
 })
@@ -136,7 +136,10 @@ describe('GitHubProvider', () => {
       },
       issues: {
         createComment: vi.fn(),
+        listComments: vi.fn(),
+        updateComment: vi.fn(),
       },
+      paginate: vi.fn(),
     }
 
     mockOctokitGraphql = vi.fn()
@@ -225,6 +228,61 @@ describe('GitHubProvider', () => {
     })
   })
 
+  describe('upsertComment', () => {
+    it('adds a comment when none has the marker', async () => {
+      mockOctokit.paginate.mockResolvedValue([{ body: 'LGTM', id: 1 }])
+      mockOctokit.issues.createComment.mockResolvedValue({})
+
+      const provider = new GitHubProvider()
+      const result = await provider.upsertComment({
+        owner: 'owner',
+        repo: 'repo',
+        prNumber: 123,
+        body: '<!-- marker -->\nReport',
+        marker: '<!-- marker -->',
+      })
+
+      expect(result).toBe('created')
+      expect(mockOctokit.paginate).toHaveBeenCalledWith(
+        mockOctokit.issues.listComments,
+        { owner: 'owner', repo: 'repo', issue_number: 123, per_page: 100 },
+      )
+      expect(mockOctokit.issues.createComment).toHaveBeenCalledWith({
+        owner: 'owner',
+        repo: 'repo',
+        issue_number: 123,
+        body: '<!-- marker -->\nReport',
+      })
+      expect(mockOctokit.issues.updateComment).not.toHaveBeenCalled()
+    })
+
+    it('updates the comment that has the marker', async () => {
+      mockOctokit.paginate.mockResolvedValue([
+        { body: 'LGTM', id: 1 },
+        { body: '<!-- marker -->\nOld report', id: 2 },
+      ])
+      mockOctokit.issues.updateComment.mockResolvedValue({})
+
+      const provider = new GitHubProvider()
+      const result = await provider.upsertComment({
+        owner: 'owner',
+        repo: 'repo',
+        prNumber: 123,
+        body: '<!-- marker -->\nNew report',
+        marker: '<!-- marker -->',
+      })
+
+      expect(result).toBe('updated')
+      expect(mockOctokit.issues.updateComment).toHaveBeenCalledWith({
+        owner: 'owner',
+        repo: 'repo',
+        comment_id: 2,
+        body: '<!-- marker -->\nNew report',
+      })
+      expect(mockOctokit.issues.createComment).not.toHaveBeenCalled()
+    })
+  })
+
   describe('listPrs', () => {
     it('lists PRs with pagination', async () => {
       const mockResponse = {
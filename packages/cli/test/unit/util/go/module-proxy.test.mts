@@ -0,0 +1,165 @@
+/**
+ * Unit tests for Go module proxy resolution.
+ *
+ * Purpose: Tests GOPROXY/GONOPROXY handling and resolving package queries to
+ * the enclosing module and version through the proxy protocol.
+ *
+ * Test Coverage: - Case escaping - GOPROXY list parsing with direct/off -
+ * GONOPROXY glob matching on path prefixes - `go env` reading and failure -
+ * Longest-prefix module lookup - Exact and latest queries - Proxy errors.
+ *
+ * Related Files: - src/util/go/module-proxy.mts (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  escapeGoModulePath,
+  getGoProxyConfig,
+  matchesGoPrivate,
+  parseGoProxyList,
+  resolveGoModule,
+} from '../../../../src/util/go/module-proxy.mts'
+
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+const mockSpawn = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  socketHttpRequest: mockSocketHttpRequest,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/process/spawn/child'), () => ({
+  spawn: mockSpawn,
+}))
+
+const PROXY = 'https://proxy.golang.org'
+
+describe('escapeGoModulePath', () => {
+  it('escapes upper-case letters', () => {
+    expect(escapeGoModulePath('github.com/BurntSushi/toml')).toBe(
+      'github.com/!burnt!sushi/toml',
+    )
+  })
+})
+
+describe('parseGoProxyList', () => {
+  it('returns the first proxy URL', () => {
+    expect(parseGoProxyList(undefined)).toBe(PROXY)
+    expect(parseGoProxyList('https://goproxy.example/,direct')).toBe(
+      'https://goproxy.example',
+    )
+    expect(parseGoProxyList('direct|https://b.example')).toBe(
+      'https://b.example',
+    )
+  })
+
+  it('returns undefined when nothing is proxied', () => {
+    expect(parseGoProxyList('direct')).toBeUndefined()
+    expect(parseGoProxyList('off')).toBeUndefined()
+  })
+})
+
+describe('matchesGoPrivate', () => {
+  it('matches globs against leading path elements', () => {
+    const patterns = ['*.corp.example', 'github.com/acme']
+    expect(matchesGoPrivate('git.corp.example/x/y', patterns)).toBe(true)
+    expect(matchesGoPrivate('github.com/acme/tool', patterns)).toBe(true)
+    expect(matchesGoPrivate('github.com/acmeinc/tool', patterns)).toBe(false)
+    expect(matchesGoPrivate('github.com', patterns)).toBe(false)
+  })
+})
+
+describe('getGoProxyConfig', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('reads GOPROXY and GONOPROXY from go env', async () => {
+    mockSpawn.mockResolvedValue({
+      stdout: JSON.stringify({
+        GONOPROXY: 'github.com/acme, *.corp.example',
+        GOPROXY: 'https://proxy.golang.org,direct',
+      }),
+    })
+
+    expect(await getGoProxyConfig()).toEqual({
+      ok: true,
+      data: {
+        noProxy: ['github.com/acme', '*.corp.example'],
+        proxyUrl: PROXY,
+      },
+    })
+    expect(mockSpawn).toHaveBeenCalledWith(
+      'go',
+      ['env', '-json', 'GOPROXY', 'GONOPROXY'],
+      { stdio: 'pipe' },
+    )
+  })
+
+  it('fails when go env fails', async () => {
+    mockSpawn.mockRejectedValue(new Error('spawn go ENOENT'))
+
+    expect(await getGoProxyConfig()).toMatchObject({
+      ok: false,
+      message: 'Unable to read Go settings',
+    })
+  })
+})
+
+describe('resolveGoModule', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('walks up to the enclosing module', async () => {
+    mockSocketHttpRequest.mockImplementation(async (url: string) =>
+      url === `${PROXY}/golang.org/x/tools/@latest`
+        ? { ok: true, status: 200, json: () => ({ Version: 'v0.22.0' }) }
+        : { ok: false, status: 404 },
+    )
+
+    expect(
+      await resolveGoModule(PROXY, 'golang.org/x/tools/cmd/goimports'),
+    ).toEqual({
+      ok: true,
+      data: { path: 'golang.org/x/tools', version: 'v0.22.0' },
+    })
+    expect(mockSocketHttpRequest).toHaveBeenCalledTimes(3)
+  })
+
+  it('looks up exact versions via .info', async () => {
+    mockSocketHttpRequest.mockResolvedValue({
+      ok: true,
+      status: 200,
+      json: () => ({ Version: 'v1.9.1' }),
+    })
+
+    expect(
+      await resolveGoModule(PROXY, 'github.com/gin-gonic/gin', 'v1.9.1'),
+    ).toEqual({
+      ok: true,
+      data: { path: 'github.com/gin-gonic/gin', version: 'v1.9.1' },
+    })
+    expect(mockSocketHttpRequest).toHaveBeenCalledWith(
+      `${PROXY}/github.com/gin-gonic/gin/@v/v1.9.1.info`,
+    )
+  })
+
+  it('resolves to undefined when no module matches', async () => {
+    mockSocketHttpRequest.mockResolvedValue({ ok: false, status: 410 })
+
+    expect(await resolveGoModule(PROXY, 'example.com/nope')).toEqual({
+      ok: true,
+      data: undefined,
+    })
+  })
+
+  it('fails on other proxy errors', async () => {
+    mockSocketHttpRequest.mockResolvedValue({ ok: false, status: 500 })
+
+    expect(await resolveGoModule(PROXY, 'example.com/x')).toMatchObject({
+      ok: false,
+      message: 'Unable to resolve Go module',
+    })
+  })
+})
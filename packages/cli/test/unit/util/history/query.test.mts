@@ -0,0 +1,218 @@
+/**
+ * Unit tests for the queries of the local scan history.
+ *
+ * Test Coverage: - Parsing plain word queries - New, fixed and open alerts
+ * along a stream of scans - Severity, package and time filters - Diffing two
+ * recorded scans.
+ *
+ * Related Files: - src/util/history/query.mts (implementation) -
+ * src/util/history/store.mts - The recorded scans and alerts.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  diffHistoryScans,
+  parseHistoryQuery,
+  runHistoryQuery,
+} from '../../../../src/util/history/query.mts'
+
+import type {
+  HistoryAlert,
+  HistoryData,
+  HistoryScan,
+} from '../../../../src/util/history/store.mts'
+
+const DAY_MS = 24 * 60 * 60 * 1000
+
+const NOW = Date.UTC(2026, 9, 14)
+
+function scan(scanId: string, daysAgo: number, repo = 'app'): HistoryScan {
+  return {
+    branch: 'main',
+    orgSlug: 'acme',
+    packages: 10,
+    recordedAt: NOW - daysAgo * DAY_MS,
+    repo,
+    scanId,
+  }
+}
+
+function alert(
+  scanId: string,
+  name: string,
+  severity: string,
+  type = 'malware',
+  version = '1.0.0',
+): HistoryAlert {
+  return {
+    fingerprint: `${scanId}-${name}-${type}`,
+    identity: `npm:/${name}:${type}`,
+    purl: `pkg:npm/${name}@${version}`,
+    scanId,
+    severity,
+    type,
+  }
+}
+
+describe('parseHistoryQuery', () => {
+  it('parses status, severity and time', () => {
+    expect(parseHistoryQuery('new criticals in last 30 days', NOW)).toEqual({
+      ok: true,
+      data: {
+        severities: ['critical'],
+        since: NOW - 30 * DAY_MS,
+        status: 'new',
+      },
+    })
+  })
+
+  it('parses named values and dates', () => {
+    const result = parseHistoryQuery(
+      'fixed high and medium alerts repo my-app branch main since 2026-01-31',
+      NOW,
+    )
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        branch: 'main',
+        repo: 'my-app',
+        severities: ['high', 'middle'],
+        since: Date.parse('2026-01-31'),
+        status: 'fixed',
+      },
+    })
+  })
+
+  it('accepts short durations and single units', () => {
+    expect(parseHistoryQuery('past week', NOW)).toMatchObject({
+      data: { since: NOW - 7 * DAY_MS },
+    })
+    expect(parseHistoryQuery('last 12h', NOW)).toMatchObject({
+      data: { since: NOW - 12 * 60 * 60 * 1000 },
+    })
+  })
+
+  it('rejects unknown words', () => {
+    expect(parseHistoryQuery('new bananas', NOW)).toMatchObject({
+      ok: false,
+      message: 'Invalid query',
+      cause: expect.stringContaining('"bananas"'),
+    })
+  })
+
+  it('rejects an invalid time', () => {
+    expect(parseHistoryQuery('since yesterday-ish', NOW)).toMatchObject({
+      ok: false,
+      message: 'Invalid time in the query',
+    })
+  })
+})
+
+describe('runHistoryQuery', () => {
+  const data: HistoryData = {
+    scans: [scan('s1', 40), scan('s2', 20), scan('s3', 5)],
+    alerts: [
+      alert('s1', 'old-bad', 'critical'),
+      alert('s2', 'old-bad', 'critical'),
+      alert('s3', 'old-bad', 'critical', 'malware', '1.0.1'),
+      alert('s1', 'gone', 'high'),
+      alert('s2', 'recent', 'critical'),
+      alert('s3', 'recent', 'critical'),
+      alert('s3', 'newest', 'low'),
+    ],
+  }
+
+  it('finds alerts first seen in the window', () => {
+    const query = parseHistoryQuery('new criticals in last 30 days', NOW)
+    expect(query.ok).toBe(true)
+    const rows = runHistoryQuery(data, query.ok ? query.data : {})
+    expect(rows.map(r => r.purl)).toEqual(['pkg:npm/recent@1.0.0'])
+    expect(rows[0]).toMatchObject({
+      firstSeen: NOW - 20 * DAY_MS,
+      lastSeen: NOW - 5 * DAY_MS,
+      scans: 2,
+      status: 'open',
+    })
+  })
+
+  it('follows an alert across versions', () => {
+    const rows = runHistoryQuery(data, { packageName: 'old-bad' })
+    expect(rows).toHaveLength(1)
+    expect(rows[0]).toMatchObject({
+      firstSeen: NOW - 40 * DAY_MS,
+      purl: 'pkg:npm/old-bad@1.0.1',
+      scans: 3,
+    })
+  })
+
+  it('finds fixed alerts by the scan that no longer had them', () => {
+    const rows = runHistoryQuery(data, { status: 'fixed' })
+    expect(rows).toEqual([
+      expect.objectContaining({
+        fixedAt: NOW - 20 * DAY_MS,
+        purl: 'pkg:npm/gone@1.0.0',
+        status: 'fixed',
+      }),
+    ])
+  })
+
+  it('sorts by severity', () => {
+    const rows = runHistoryQuery(data, { status: 'open' })
+    expect(rows.map(r => r.severity)).toEqual(['critical', 'critical', 'low'])
+  })
+
+  it('keeps streams of other repos apart', () => {
+    const rows = runHistoryQuery(
+      {
+        scans: [...data.scans, scan('o1', 1, 'other')],
+        alerts: [...data.alerts, alert('o1', 'gone', 'high')],
+      },
+      { packageName: 'gone' },
+    )
+    expect(rows.map(r => [r.repo, r.status])).toEqual([
+      ['other', 'open'],
+      ['app', 'fixed'],
+    ])
+  })
+
+  it('skips scans whose results were not recorded', () => {
+    const { packages: _packages, ...pending } = scan('s4', 1)
+    const rows = runHistoryQuery(
+      { scans: [...data.scans, pending], alerts: data.alerts },
+      { status: 'fixed' },
+    )
+    expect(rows).toHaveLength(1)
+  })
+})
+
+describe('diffHistoryScans', () => {
+  const data: HistoryData = {
+    scans: [scan('s1', 2), scan('s2', 1)],
+    alerts: [
+      alert('s1', 'kept', 'high'),
+      alert('s2', 'kept', 'high', 'malware', '2.0.0'),
+      alert('s1', 'gone', 'low'),
+      alert('s2', 'added', 'critical'),
+    ],
+  }
+
+  it('lists added and removed alerts by identity', () => {
+    const result = diffHistoryScans(data, 's1', 's2')
+    expect(result).toMatchObject({
+      ok: true,
+      data: {
+        added: [expect.objectContaining({ purl: 'pkg:npm/added@1.0.0' })],
+        removed: [expect.objectContaining({ purl: 'pkg:npm/gone@1.0.0' })],
+      },
+    })
+  })
+
+  it('fails for scans not in the history', () => {
+    expect(diffHistoryScans(data, 's1', 'nope')).toMatchObject({
+      ok: false,
+      message: 'Scan not in the history',
+      cause: expect.stringContaining('scan nope'),
+    })
+  })
+})
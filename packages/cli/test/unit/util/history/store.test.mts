@@ -0,0 +1,156 @@
+/**
+ * Unit tests for the local scan history database.
+ *
+ * Test Coverage: - Recording scans and their alerts - Filling in a scan
+ * recorded before its results were fetched - Replacing the alerts of a
+ * scan - Alert fingerprints.
+ *
+ * Related Files: - src/util/history/store.mts (implementation) -
+ * src/commands/scan/diff-scan-alerts.mts - The alert identities.
+ */
+
+import { mkdtempSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterAll, describe, expect, it } from 'vitest'
+
+import { safeDeleteSync } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  getHistoryAlertFingerprint,
+  openHistoryDb,
+  readHistory,
+  writeScanHistory,
+} from '../../../../src/util/history/store.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+const tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-history-test-'))
+
+afterAll(() => {
+  safeDeleteSync(tmpDir, { force: true })
+})
+
+const malware = {
+  key: 'left-pad-malware',
+  type: 'malware',
+  severity: 'critical',
+}
+const envVars = { key: 'left-pad-envVars', type: 'envVars', severity: 'low' }
+
+async function openTestDb(name: string) {
+  const dbCResult = await openHistoryDb(path.join(tmpDir, name, 'history.db'))
+  if (!dbCResult.ok) {
+    throw new Error(dbCResult.cause)
+  }
+  return dbCResult.data
+}
+
+describe('scan history store', () => {
+  it('records scans and their alerts', async () => {
+    const db = await openTestDb('record')
+    writeScanHistory(
+      db,
+      {
+        artifacts: [
+          artifact('left-pad', { alerts: [malware] }),
+          artifact('clean'),
+        ],
+        branch: 'main',
+        orgSlug: 'acme',
+        repo: 'app',
+        scanId: 's1',
+      },
+      1000,
+    )
+    expect(readHistory(db)).toEqual({
+      alerts: [
+        {
+          fingerprint: getHistoryAlertFingerprint(
+            'pkg:npm/left-pad@1.0.0',
+            'left-pad-malware',
+          ),
+          identity: expect.stringContaining('left-pad'),
+          purl: 'pkg:npm/left-pad@1.0.0',
+          scanId: 's1',
+          severity: 'critical',
+          type: 'malware',
+        },
+      ],
+      scans: [
+        {
+          branch: 'main',
+          orgSlug: 'acme',
+          packages: 2,
+          recordedAt: 1000,
+          repo: 'app',
+          scanId: 's1',
+        },
+      ],
+    })
+    db.close()
+  })
+
+  it('fills in a scan recorded before its results', async () => {
+    const db = await openTestDb('fill')
+    writeScanHistory(
+      db,
+      { branch: 'main', orgSlug: 'acme', repo: 'app', scanId: 's1' },
+      1000,
+    )
+    expect(readHistory(db).scans).toEqual([
+      {
+        branch: 'main',
+        orgSlug: 'acme',
+        recordedAt: 1000,
+        repo: 'app',
+        scanId: 's1',
+      },
+    ])
+    writeScanHistory(
+      db,
+      {
+        artifacts: [artifact('left-pad', { alerts: [malware] })],
+        orgSlug: 'acme',
+        scanId: 's1',
+      },
+      2000,
+    )
+    expect(readHistory(db).scans).toEqual([
+      {
+        branch: 'main',
+        orgSlug: 'acme',
+        packages: 1,
+        recordedAt: 1000,
+        repo: 'app',
+        scanId: 's1',
+      },
+    ])
+    db.close()
+  })
+
+  it('replaces the alerts of a scan fetched again', async () => {
+    const db = await openTestDb('replace')
+    writeScanHistory(db, {
+      artifacts: [artifact('left-pad', { alerts: [malware] })],
+      orgSlug: 'acme',
+      scanId: 's1',
+    })
+    writeScanHistory(db, {
+      artifacts: [artifact('left-pad', { alerts: [envVars] })],
+      orgSlug: 'acme',
+      scanId: 's1',
+    })
+    expect(readHistory(db).alerts.map(a => a.type)).toEqual(['envVars'])
+    db.close()
+  })
+
+  it('keeps the history across opens', async () => {
+    const db = await openTestDb('reopen')
+    writeScanHistory(db, { orgSlug: 'acme', scanId: 's1' })
+    db.close()
+    const reopened = await openTestDb('reopen')
+    expect(readHistory(reopened).scans.map(s => s.scanId)).toEqual(['s1'])
+    reopened.close()
+  })
+})
@@ -0,0 +1,254 @@
+/**
+ * Unit tests for the pre-install package check.
+ *
+ * Purpose: Tests how scored artifacts are bucketed by policy action. How
+ * checkPackagesBeforeInstall acts on the buckets lives in check.test.mts.
+ *
+ * Test Coverage: - Bucketing by error/warn/monitor actions - Malware without an
+ * org policy treated as blocking - Caller-named warn alert types - Local
+ * socket.policy.yml rules, quarantine window and trust lists - socket.yml
+ * wrapper modes.
+ *
+ * Related Files: - src/util/install-check/check.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { summarizeInstallRisks } from '../../../../src/util/install-check/check.mts'
+import { createEmptySocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+describe('summarizeInstallRisks', () => {
+  it('buckets artifacts by their most severe action', () => {
+    const summary = summarizeInstallRisks([
+      artifact('bad', {
+        type: 'pypi',
+        alerts: [
+          { action: 'warn', severity: 'middle', type: 'networkAccess' },
+          { action: 'error', severity: 'critical', type: 'malware' },
+        ],
+      }),
+      artifact('risky', {
+        type: 'pypi',
+        alerts: [{ action: 'warn', severity: 'high', type: 'installScripts' }],
+      }),
+      artifact('watched', {
+        type: 'pypi',
+        alerts: [{ action: 'monitor', severity: 'low', type: 'unmaintained' }],
+      }),
+      artifact('fine', {
+        type: 'pypi',
+        alerts: [{ action: 'ignore', type: 'envVars' }],
+      }),
+    ])
+
+    expect(summary.blocked.map(r => r.purl)).toEqual(['pkg:pypi/bad@1.0.0'])
+    expect(summary.blocked[0]!.alerts).toHaveLength(2)
+    expect(summary.warned.map(r => r.purl)).toEqual(['pkg:pypi/risky@1.0.0'])
+    expect(summary.monitored.map(r => r.purl)).toEqual([
+      'pkg:pypi/watched@1.0.0',
+    ])
+  })
+
+  it('blocks malware when no policy action is set', () => {
+    const summary = summarizeInstallRisks([
+      artifact('evil', {
+        type: 'pypi',
+        alerts: [{ severity: 'critical', type: 'malware' }],
+      }),
+      artifact('other', {
+        type: 'pypi',
+        alerts: [{ severity: 'low', type: 'unmaintained' }],
+      }),
+    ])
+
+    expect(summary.blocked.map(r => r.purl)).toEqual(['pkg:pypi/evil@1.0.0'])
+    expect(summary.warned).toEqual([])
+    expect(summary.monitored).toEqual([])
+  })
+
+  it('warns on caller-named alert types when no policy action is set', () => {
+    const summary = summarizeInstallRisks(
+      [
+        artifact('build', {
+          type: 'pypi',
+          alerts: [{ severity: 'middle', type: 'installScripts' }],
+        }),
+        artifact('policy', {
+          type: 'pypi',
+          alerts: [{ action: 'ignore', type: 'installScripts' }],
+        }),
+      ],
+      ['installScripts'],
+    )
+
+    expect(summary.warned.map(r => r.purl)).toEqual(['pkg:pypi/build@1.0.0'])
+    expect(summary.blocked).toEqual([])
+  })
+
+  it('applies a local socket.policy.yml', () => {
+    const policy = createEmptySocketPolicy()
+    policy.block.alerts = ['networkAccess']
+    policy.licenses.deny = ['GPL-3.0-only']
+    policy.exceptions = [
+      {
+        alerts: ['malware'],
+        packages: ['pkg:pypi/waived'],
+        paths: [],
+        reason: 'Vetted internal mirror',
+      },
+    ]
+
+    const summary = summarizeInstallRisks(
+      [
+        artifact('net', {
+          type: 'pypi',
+          alerts: [{ action: 'monitor', type: 'networkAccess' }],
+        }),
+        artifact('waived', { type: 'pypi', alerts: [{ type: 'malware' }] }),
+        artifact('gpl', { type: 'pypi', license: 'GPL-3.0-only' }),
+        artifact('dual', { type: 'pypi', license: 'GPL-3.0-only OR MIT' }),
+      ],
+      [],
+      policy,
+    )
+
+    expect(summary.blocked.map(r => r.purl)).toEqual([
+      'pkg:pypi/net@1.0.0',
+      'pkg:pypi/gpl@1.0.0',
+    ])
+    expect(summary.blocked[1]!.alerts[0]!.type).toBe('licensePolicyViolation')
+    expect(summary.warned).toEqual([])
+  })
+
+  it('holds back versions inside the local quarantine window', () => {
+    const policy = createEmptySocketPolicy()
+    policy.quarantine = { action: 'warn', days: 7 }
+    const publishTimes = new Map([
+      ['pkg:pypi/fresh@1.0.0', Date.now()],
+      ['pkg:pypi/aged@1.0.0', Date.now() - 30 * 24 * 60 * 60 * 1000],
+    ])
+
+    const summary = summarizeInstallRisks(
+      [
+        artifact('fresh', { type: 'pypi' }),
+        artifact('aged', { type: 'pypi' }),
+        artifact('unknown', { type: 'pypi' }),
+      ],
+      [],
+      policy,
+      undefined,
+      publishTimes,
+    )
+
+    expect(summary.warned).toEqual([
+      {
+        alerts: [
+          { action: 'warn', severity: 'high', type: 'recentlyPublished' },
+        ],
+        purl: 'pkg:pypi/fresh@1.0.0',
+      },
+    ])
+    expect(summary.blocked).toEqual([])
+  })
+
+  it('skips the prompts of trusted packages and blocks distrusted ones', () => {
+    const policy = createEmptySocketPolicy()
+    policy.trust = { maintainers: [], packages: ['pkg:pypi/internal-*'] }
+    policy.distrust = { maintainers: [], packages: ['pkg:pypi/evil'] }
+
+    const summary = summarizeInstallRisks(
+      [
+        artifact('internal-tools', {
+          type: 'pypi',
+          alerts: [
+            { action: 'warn', type: 'networkAccess' },
+            { action: 'error', type: 'malware' },
+          ],
+        }),
+        artifact('internal-lib', {
+          type: 'pypi',
+          alerts: [{ action: 'warn', type: 'shellAccess' }],
+        }),
+        artifact('evil', { type: 'pypi' }),
+      ],
+      [],
+      policy,
+    )
+
+    expect(summary.blocked).toEqual([
+      {
+        alerts: [{ action: 'error', severity: 'unknown', type: 'malware' }],
+        purl: 'pkg:pypi/internal-tools@1.0.0',
+      },
+      {
+        alerts: [
+          { action: 'error', severity: 'critical', type: 'distrustedPackage' },
+        ],
+        purl: 'pkg:pypi/evil@1.0.0',
+      },
+    ])
+    expect(summary.warned).toEqual([])
+  })
+
+  it('applies the socket.yml wrapper modes over the org policy', () => {
+    const policy = createEmptySocketPolicy()
+    policy.block.alerts = ['telemetry']
+
+    const summary = summarizeInstallRisks(
+      [
+        artifact('build', {
+          type: 'pypi',
+          alerts: [{ action: 'ignore', type: 'installScripts' }],
+        }),
+        artifact('evil', { type: 'pypi', alerts: [{ type: 'malware' }] }),
+        artifact('phones-home', {
+          type: 'pypi',
+          alerts: [{ action: 'warn', type: 'telemetry' }],
+        }),
+        artifact('stale', {
+          type: 'pypi',
+          alerts: [{ action: 'warn', type: 'unmaintained' }],
+        }),
+        artifact('net', {
+          type: 'pypi',
+          alerts: [{ action: 'warn', type: 'networkAccess' }],
+        }),
+      ],
+      [],
+      undefined,
+      {
+        alerts: {
+          installScripts: 'prompt',
+          malware: 'block',
+          networkAccess: 'allow',
+          telemetry: 'warn',
+        },
+      },
+    )
+
+    expect(summary.blocked.map(r => r.purl)).toEqual(['pkg:pypi/evil@1.0.0'])
+    expect(summary.warned.map(r => r.purl)).toEqual([
+      'pkg:pypi/build@1.0.0',
+      'pkg:pypi/stale@1.0.0',
+    ])
+    expect(summary.noticed.map(r => r.purl)).toEqual([
+      'pkg:pypi/phones-home@1.0.0',
+    ])
+    expect(summary.noticed[0]!.alerts[0]!.action).toBe('notice')
+
+    // A matching socket.policy.yml rule still wins.
+    const withPolicy = summarizeInstallRisks(
+      [
+        artifact('phones-home', {
+          type: 'pypi',
+          alerts: [{ type: 'telemetry' }],
+        }),
+      ],
+      [],
+      policy,
+      { alerts: { telemetry: 'warn' } },
+    )
+    expect(withPolicy.blocked).toHaveLength(1)
+  })
+})
@@ -0,0 +1,338 @@
+/**
+ * Unit tests for the pre-install package check.
+ *
+ * Purpose: Tests how checkPackagesBeforeInstall blocks, prompts or proceeds.
+ * How the scored artifacts are bucketed lives in check-risks.test.mts.
+ *
+ * Test Coverage: - Fetch failures - Offline lookups - Blocked installs -
+ * Warned installs with confirm accepted, declined and non-interactive -
+ * Recording accepted risks - The socket.yml non-interactive answer and warn
+ * mode - Invalid policy files - Empty purl list short circuit.
+ *
+ * Related Files: - src/util/install-check/check.mts (implementation) -
+ * src/commands/package/fetch-purls-shallow-score.mts (batch lookup)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { checkPackagesBeforeInstall } from '../../../../src/util/install-check/check.mts'
+import { createEmptySocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+const mockEnv = vi.hoisted(() => ({ SOCKET_CLI_OFFLINE: false }))
+const mockFetchPurlsShallowScore = vi.hoisted(() => vi.fn())
+const mockAddSocketPolicyException = vi.hoisted(() => vi.fn())
+const mockConfirm = vi.hoisted(() => vi.fn())
+const mockFindSocketPolicySync = vi.hoisted(() => vi.fn())
+const mockFindSocketYmlSync = vi.hoisted(() => vi.fn())
+const mockInput = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/package/fetch-purls-shallow-score.mts'),
+  () => ({
+    fetchPurlsShallowScore: mockFetchPurlsShallowScore,
+  }),
+)
+
+vi.mock(import('../../../../src/env/socket-cli-offline.mts'), () => ({
+  get SOCKET_CLI_OFFLINE() {
+    return mockEnv.SOCKET_CLI_OFFLINE
+  },
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mts'), () => ({
+  getDefaultApiToken: () => 'test-token',
+}))
+
+vi.mock(
+  import('../../../../src/util/policy/socket-policy.mts'),
+  async importOriginal => ({
+    ...(await importOriginal()),
+    findSocketPolicySync: mockFindSocketPolicySync,
+  }),
+)
+
+vi.mock(import('../../../../src/util/policy/add-policy-exception.mts'), () => ({
+  addSocketPolicyException: mockAddSocketPolicyException,
+}))
+
+vi.mock(import('../../../../src/util/config.mts'), async importOriginal => ({
+  ...(await importOriginal()),
+  findSocketYmlSync: mockFindSocketYmlSync,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/stdio/prompts'), () => ({
+  confirm: mockConfirm,
+  input: mockInput,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => ({
+    error: vi.fn(),
+    fail: vi.fn(),
+    info: vi.fn(),
+    success: vi.fn(),
+    warn: vi.fn(),
+  }),
+}))
+
+describe('checkPackagesBeforeInstall', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockEnv.SOCKET_CLI_OFFLINE = false
+    mockFindSocketPolicySync.mockReturnValue({ ok: true, data: undefined })
+    mockFindSocketYmlSync.mockReturnValue({ ok: true, data: undefined })
+  })
+
+  it('skips the lookup when nothing would be installed', async () => {
+    const result = await checkPackagesBeforeInstall([], {
+      commandPath: 'socket pip',
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockFetchPurlsShallowScore).not.toHaveBeenCalled()
+  })
+
+  it('fails when the lookup fails', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: false,
+      message: 'Socket API error',
+      cause: 'Rate limited',
+    })
+
+    const result = await checkPackagesBeforeInstall(['pkg:pypi/a@1.0.0'], {
+      commandPath: 'socket pip',
+    })
+
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Unable to check packages',
+    })
+    expect(mockFetchPurlsShallowScore).toHaveBeenCalledWith(
+      ['pkg:pypi/a@1.0.0'],
+      {
+        commandPath: 'socket pip',
+        offline: false,
+        sdkOpts: { apiToken: 'test-token' },
+      },
+    )
+  })
+
+  it('scores from the local cache with SOCKET_CLI_OFFLINE', async () => {
+    mockEnv.SOCKET_CLI_OFFLINE = true
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: false,
+      message: 'Offline cache miss',
+      cause: 'The local score cache has no entry for pkg:pypi/a@1.0.0',
+    })
+
+    const result = await checkPackagesBeforeInstall(['pkg:pypi/a@1.0.0'], {
+      commandPath: 'socket pip',
+    })
+
+    expect(mockFetchPurlsShallowScore).toHaveBeenCalledWith(
+      ['pkg:pypi/a@1.0.0'],
+      expect.objectContaining({ offline: true }),
+    )
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Unable to check packages',
+      cause: expect.stringContaining('Nothing was installed.'),
+    })
+  })
+
+  it('blocks packages that violate policy', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        artifact('evil', {
+          type: 'pypi',
+          alerts: [{ action: 'error', type: 'malware' }],
+        }),
+      ],
+    })
+
+    const result = await checkPackagesBeforeInstall(['pkg:pypi/evil@1.0.0'], {
+      commandPath: 'socket pip',
+      interactive: true,
+    })
+
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Install blocked by Socket security policy',
+    })
+    expect(mockConfirm).not.toHaveBeenCalled()
+  })
+
+  it('asks before installing warned packages', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        artifact('risky', {
+          type: 'pypi',
+          alerts: [{ action: 'warn', type: 'installScripts' }],
+        }),
+      ],
+    })
+    // Accept, do not record the decision, then decline.
+    mockConfirm
+      .mockResolvedValueOnce(true)
+      .mockResolvedValueOnce(false)
+      .mockResolvedValueOnce(false)
+
+    const accepted = await checkPackagesBeforeInstall(
+      ['pkg:pypi/risky@1.0.0'],
+      { commandPath: 'socket pip', interactive: true },
+    )
+    const declined = await checkPackagesBeforeInstall(
+      ['pkg:pypi/risky@1.0.0'],
+      { commandPath: 'socket pip', interactive: true },
+    )
+
+    expect(accepted.ok).toBe(true)
+    expect(declined).toMatchObject({ ok: false, message: 'Install cancelled' })
+    expect(mockConfirm).toHaveBeenCalledTimes(3)
+    expect(mockAddSocketPolicyException).not.toHaveBeenCalled()
+  })
+
+  it('records accepted risks as policy exceptions', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        artifact('risky', {
+          type: 'pypi',
+          alerts: [
+            { action: 'warn', type: 'installScripts' },
+            { action: 'monitor', type: 'unmaintained' },
+          ],
+        }),
+      ],
+    })
+    mockFindSocketPolicySync.mockReturnValue({
+      ok: true,
+      data: {
+        path: '/repo/socket.policy.yml',
+        policy: createEmptySocketPolicy(),
+      },
+    })
+    mockConfirm.mockResolvedValue(true)
+    mockInput.mockResolvedValue(' Vetted the build script ')
+    mockAddSocketPolicyException.mockReturnValue({ ok: true, data: undefined })
+
+    const result = await checkPackagesBeforeInstall(['pkg:pypi/risky@1.0.0'], {
+      commandPath: 'socket pip',
+      interactive: true,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockAddSocketPolicyException).toHaveBeenCalledWith(
+      '/repo/socket.policy.yml',
+      {
+        alerts: ['installScripts'],
+        packages: ['pkg:pypi/risky@1.0.0'],
+        paths: [],
+        reason: 'Accepted: Vetted the build script',
+      },
+    )
+  })
+
+  it('proceeds with warned packages when not interactive', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        artifact('risky', {
+          type: 'pypi',
+          alerts: [{ action: 'warn', type: 'installScripts' }],
+        }),
+      ],
+    })
+
+    const result = await checkPackagesBeforeInstall(['pkg:pypi/risky@1.0.0'], {
+      commandPath: 'socket pip',
+      interactive: false,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockConfirm).not.toHaveBeenCalled()
+  })
+
+  it('answers prompts with the socket.yml non-interactive default', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        artifact('risky', {
+          type: 'pypi',
+          alerts: [{ action: 'warn', type: 'installScripts' }],
+        }),
+      ],
+    })
+    mockFindSocketYmlSync.mockReturnValue({
+      ok: true,
+      data: {
+        path: '/repo/socket.yml',
+        parsed: { wrapper: { alerts: {}, nonInteractive: 'block' } },
+      },
+    })
+
+    const result = await checkPackagesBeforeInstall(['pkg:pypi/risky@1.0.0'], {
+      commandPath: 'socket pip',
+      cwd: '/repo',
+      interactive: false,
+    })
+
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Install cancelled',
+      cause: expect.stringContaining('wrapper.nonInteractive'),
+    })
+    expect(mockFindSocketYmlSync).toHaveBeenCalledWith('/repo')
+    expect(mockConfirm).not.toHaveBeenCalled()
+  })
+
+  it('installs packages the socket.yml only warns about', async () => {
+    mockFetchPurlsShallowScore.mockResolvedValue({
+      ok: true,
+      data: [
+        artifact('phones-home', {
+          type: 'pypi',
+          alerts: [{ action: 'warn', type: 'telemetry' }],
+        }),
+      ],
+    })
+    mockFindSocketYmlSync.mockReturnValue({
+      ok: true,
+      data: {
+        path: '/repo/socket.yml',
+        parsed: { wrapper: { alerts: { telemetry: 'warn' } } },
+      },
+    })
+
+    const result = await checkPackagesBeforeInstall(
+      ['pkg:pypi/phones-home@1.0.0'],
+      { commandPath: 'socket pip', interactive: true },
+    )
+
+    expect(result.ok && result.data.noticed).toHaveLength(1)
+    expect(mockConfirm).not.toHaveBeenCalled()
+  })
+
+  it('fails when the local policy file is invalid', async () => {
+    mockFindSocketPolicySync.mockReturnValue({
+      ok: false,
+      message: 'Invalid policy file /repo/socket.policy.yml',
+      cause: 'block.severity: Expected one of low, middle, high, critical',
+    })
+
+    const result = await checkPackagesBeforeInstall(['pkg:pypi/a@1.0.0'], {
+      commandPath: 'socket pip',
+      cwd: '/repo',
+    })
+
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Invalid policy file /repo/socket.policy.yml',
+    })
+    expect(mockFindSocketPolicySync).toHaveBeenCalledWith('/repo')
+    expect(mockFetchPurlsShallowScore).not.toHaveBeenCalled()
+  })
+})
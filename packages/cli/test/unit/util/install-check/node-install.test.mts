@@ -0,0 +1,189 @@
+/**
+ * Unit tests for the pnpm and yarn pre-install checks.
+ *
+ * Purpose: Tests how the packages of `socket pnpm|yarn add|install` are
+ * resolved and scored before handing off to Socket Firewall.
+ *
+ * Test Coverage: - Registry resolution of named packages - Likely typosquats
+ * warn before the lookup - Lookup failure falls through with a warning -
+ * Direct dependencies of pnpm-lock.yaml and Yarn Classic yarn.lock files.
+ *
+ * Related Files: - src/util/install-check/node-install.mts (implementation)
+ * - src/commands/pnpm/check-pnpm-install.mts -
+ * src/commands/yarn/check-yarn-install.mts
+ */
+
+import { mkdtempSync, rmSync, writeFileSync } from 'node:fs'
+import { tmpdir } from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  NODE_WARN_ALERT_TYPES,
+  checkNodeInstall,
+  getLockfileDirectPurls,
+} from '../../../../src/util/install-check/node-install.mts'
+
+const mockFetchRegistryJson = vi.hoisted(() => vi.fn())
+const mockCheckPackagesBeforeInstall = vi.hoisted(() => vi.fn())
+const mockWarn = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/verify/fetch-npm-provenance.mts'),
+  () => ({
+    escapePackageName: (name: string) => name.replace('/', '%2f'),
+    fetchRegistryJson: mockFetchRegistryJson,
+  }),
+)
+
+vi.mock(import('../../../../src/util/install-check/check.mts'), () => ({
+  checkPackagesBeforeInstall: mockCheckPackagesBeforeInstall,
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => ({ warn: mockWarn }),
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/spinner/default'), () => ({
+  getDefaultSpinner: () => ({ start: vi.fn(), stop: vi.fn() }),
+}))
+
+describe('checkNodeInstall', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockCheckPackagesBeforeInstall.mockResolvedValue({ ok: true, data: {} })
+  })
+
+  it('does nothing without an install', async () => {
+    const result = await checkNodeInstall('pnpm', undefined)
+
+    expect(result.ok).toBe(true)
+    expect(mockCheckPackagesBeforeInstall).not.toHaveBeenCalled()
+  })
+
+  it('checks resolved packages and leaves unknown ones to the package manager', async () => {
+    mockFetchRegistryJson
+      .mockResolvedValueOnce({ ok: true, data: { version: '4.17.21' } })
+      .mockResolvedValueOnce({ ok: true, data: undefined })
+
+    const result = await checkNodeInstall(
+      'yarn',
+      {
+        fromLockfile: false,
+        specs: [
+          { name: 'lodash', spec: 'lodash@^4', version: '^4' },
+          { name: 'lodahs', spec: 'lodahs', version: 'latest' },
+        ],
+      },
+      { cwd: '/repo' },
+    )
+
+    expect(result.ok).toBe(true)
+    expect(mockWarn).toHaveBeenCalledWith(
+      'lodahs looks like a typosquat of lodash',
+    )
+    expect(mockFetchRegistryJson).toHaveBeenCalledWith(
+      '/lodash/%5E4',
+      'the lodash@^4 manifest',
+    )
+    expect(mockCheckPackagesBeforeInstall).toHaveBeenCalledWith(
+      ['pkg:npm/lodash@4.17.21'],
+      {
+        commandPath: 'socket yarn',
+        cwd: '/repo',
+        warnAlertTypes: NODE_WARN_ALERT_TYPES,
+      },
+    )
+  })
+
+  it('warns and proceeds when the registry lookup fails', async () => {
+    mockFetchRegistryJson.mockResolvedValue({
+      ok: false,
+      message: 'npm registry request failed',
+      cause: 'Fetching the chalk@latest manifest failed with 503',
+    })
+
+    const result = await checkNodeInstall('pnpm', {
+      fromLockfile: false,
+      specs: [{ name: 'chalk', spec: 'chalk', version: 'latest' }],
+    })
+
+    expect(result.ok).toBe(true)
+    expect(mockWarn).toHaveBeenCalledWith(
+      expect.stringContaining('Skipping the Socket pre-install check'),
+    )
+    expect(mockCheckPackagesBeforeInstall).not.toHaveBeenCalled()
+  })
+})
+
+describe('getLockfileDirectPurls', () => {
+  let tmpDir: string
+
+  beforeEach(() => {
+    tmpDir = mkdtempSync(path.join(tmpdir(), 'socket-node-install-'))
+  })
+
+  afterEach(() => {
+    rmSync(tmpDir, { force: true, recursive: true })
+  })
+
+  it('reads the direct dependencies of pnpm-lock.yaml', () => {
+    writeFileSync(
+      path.join(tmpDir, 'pnpm-lock.yaml'),
+      `lockfileVersion: '9.0'
+importers:
+  .:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.0
+packages:
+  loose-envify@1.4.0: {}
+  react@18.2.0: {}
+snapshots:
+  loose-envify@1.4.0: {}
+  react@18.2.0:
+    dependencies:
+      loose-envify: 1.4.0
+`,
+    )
+
+    expect(getLockfileDirectPurls('pnpm', tmpDir)).toEqual([
+      'pkg:npm/react@18.2.0',
+    ])
+  })
+
+  it('matches Yarn Classic entries to the package.json ranges', () => {
+    writeFileSync(
+      path.join(tmpDir, 'package.json'),
+      JSON.stringify({
+        dependencies: { '@babel/core': '^7.0.0' },
+        devDependencies: { lodash: '^4.17.0' },
+      }),
+    )
+    writeFileSync(
+      path.join(tmpDir, 'yarn.lock'),
+      `# yarn lockfile v1
+
+"@babel/core@^7.0.0":
+  version "7.24.0"
+
+lodash@^4.17.0, lodash@^4.17.21:
+  version "4.17.21"
+
+ms@^2.1.0:
+  version "2.1.3"
+`,
+    )
+
+    expect(getLockfileDirectPurls('yarn', tmpDir)).toEqual([
+      'pkg:npm/@babel/core@7.24.0',
+      'pkg:npm/lodash@4.17.21',
+    ])
+  })
+
+  it('is empty without a lockfile', () => {
+    expect(getLockfileDirectPurls('yarn', tmpDir)).toEqual([])
+  })
+})
@@ -0,0 +1,103 @@
+/**
+ * Unit tests for the local typosquat heuristics.
+ *
+ * Purpose: Tests the keyboard-weighted edit distance and which names are
+ * flagged against the bundled popular package lists.
+ *
+ * Test Coverage: - Cheap slips (adjacent keys, swaps, doubled letters,
+ * separators) - Registry name normalization - Popular and unrelated names
+ * are not flagged - Socket didYouMean confirmation and clearing.
+ *
+ * Related Files: - src/util/install-check/typosquat.mts (implementation) -
+ * data/top-packages.json (popular names)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  findTyposquatTarget,
+  getTyposquatDistance,
+  getTyposquatVerdicts,
+  normalizeTyposquatName,
+} from '../../../../src/util/install-check/typosquat.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+describe('getTyposquatDistance', () => {
+  it('charges half for common slips', () => {
+    // Adjacent keys.
+    expect(getTyposquatDistance('lodasj', 'lodash')).toBe(0.5)
+    // Swapped letters.
+    expect(getTyposquatDistance('lodahs', 'lodash')).toBe(0.5)
+    // Doubled and dropped letters.
+    expect(getTyposquatDistance('expresss', 'express')).toBe(0.5)
+    expect(getTyposquatDistance('expres', 'express')).toBe(0.5)
+    // Separators and lookalikes.
+    expect(getTyposquatDistance('crossenv', 'cross-env')).toBe(0.5)
+    expect(getTyposquatDistance('1odash', 'lodash')).toBe(0.5)
+  })
+
+  it('charges full edits otherwise', () => {
+    expect(getTyposquatDistance('reakt', 'react')).toBe(1)
+    expect(getTyposquatDistance('chalks', 'chalk')).toBe(1)
+    expect(getTyposquatDistance('same', 'same')).toBe(0)
+  })
+})
+
+describe('normalizeTyposquatName', () => {
+  it('normalizes names the way each registry compares them', () => {
+    expect(normalizeTyposquatName('cargo', 'serde_json')).toBe('serde-json')
+    expect(normalizeTyposquatName('npm', 'Lodash')).toBe('lodash')
+  })
+})
+
+describe('findTyposquatTarget', () => {
+  it('flags names close to popular packages', () => {
+    expect(findTyposquatTarget('npm', 'lodahs')).toEqual({
+      distance: 0.5,
+      name: 'lodahs',
+      target: 'lodash',
+    })
+    expect(findTyposquatTarget('npm', 'typescirpt')?.target).toBe(
+      'typescript',
+    )
+    expect(findTyposquatTarget('cargo', 'serde_jsno')?.target).toBe(
+      'serde_json',
+    )
+  })
+
+  it('does not flag popular names or their registry spellings', () => {
+    expect(findTyposquatTarget('npm', 'cross-env')).toBeUndefined()
+    expect(findTyposquatTarget('cargo', 'serde-json')).toBeUndefined()
+  })
+
+  it('does not flag unrelated names', () => {
+    expect(findTyposquatTarget('npm', 'left-pad')).toBeUndefined()
+    expect(findTyposquatTarget('npm', 'my-app')).toBeUndefined()
+  })
+})
+
+describe('getTyposquatVerdicts', () => {
+  const matches = [
+    { distance: 0.5, name: 'lodahs', target: 'lodash' },
+    { distance: 1, name: 'reakt', target: 'react' },
+    { distance: 0.5, name: 'expresss', target: 'express' },
+  ]
+
+  it('refines local matches with Socket alerts', () => {
+    const artifacts = [
+      { name: 'lodahs', alerts: [{ type: 'didYouMean' }] },
+      { name: 'reakt', alerts: [{ type: 'envVars' }] },
+    ] as SocketArtifact[]
+
+    expect(
+      getTyposquatVerdicts(matches, artifacts).map(v => v.status),
+    ).toEqual(['confirmed', 'cleared', 'suspected'])
+  })
+
+  it('keeps every match suspected without Socket data', () => {
+    expect(
+      getTyposquatVerdicts(matches, undefined).map(v => v.status),
+    ).toEqual(['suspected', 'suspected', 'suspected'])
+  })
+})
@@ -0,0 +1,156 @@
+/**
+ * Unit tests for OS keychain token storage.
+ *
+ * Purpose: Tests the platform commands that store, read and delete API tokens
+ * in the OS credential manager.
+ *
+ * Test Coverage: - macOS `security`, libsecret `secret-tool` and Windows
+ * PowerShell commands - Tokens passed on stdin - Failed and missing tools -
+ * SOCKET_CLI_NO_KEYCHAIN - Rejected accounts and tokens - Read cache.
+ *
+ * Related Files: - src/util/keychain.mts (implementation)
+ * - src/util/config.mts (keychain references)
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockSpawnSync = vi.hoisted(() => vi.fn())
+
+vi.mock(import('@socketsecurity/lib-stable/process/spawn/child'), () => ({
+  spawnSync: mockSpawnSync,
+}))
+
+import {
+  deleteKeychainToken,
+  isKeychainEnabled,
+  readKeychainToken,
+  resetKeychainForTesting,
+  storeKeychainToken,
+} from '../../../src/util/keychain.mts'
+
+const originalPlatform = process.platform
+
+function setPlatform(platform: NodeJS.Platform) {
+  Object.defineProperty(process, 'platform', {
+    configurable: true,
+    value: platform,
+  })
+}
+
+describe('util/keychain', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    resetKeychainForTesting()
+    mockSpawnSync.mockReturnValue({ status: 0, stdout: 'secret-token\n' })
+  })
+
+  afterEach(() => {
+    setPlatform(originalPlatform)
+    delete process.env['SOCKET_CLI_NO_KEYCHAIN']
+  })
+
+  it('uses secret-tool on Linux', () => {
+    setPlatform('linux')
+
+    expect(storeKeychainToken('work', 'sktsec_abc')).toEqual({
+      ok: true,
+      data: undefined,
+    })
+    expect(mockSpawnSync).toHaveBeenCalledWith(
+      'secret-tool',
+      [
+        'store',
+        '--label=Socket CLI API token',
+        'service',
+        'socket-cli',
+        'account',
+        'work',
+      ],
+      expect.objectContaining({ input: 'sktsec_abc' }),
+    )
+
+    resetKeychainForTesting()
+    expect(readKeychainToken('work')).toBe('secret-token')
+    expect(mockSpawnSync).toHaveBeenLastCalledWith(
+      'secret-tool',
+      ['lookup', 'service', 'socket-cli', 'account', 'work'],
+      expect.anything(),
+    )
+
+    expect(deleteKeychainToken('work')).toBe(true)
+    expect(mockSpawnSync.mock.lastCall?.[1][0]).toBe('clear')
+  })
+
+  it('passes macOS tokens on the stdin of security', () => {
+    setPlatform('darwin')
+
+    storeKeychainToken('default', 'sktsec_abc')
+
+    const [bin, args, options] = mockSpawnSync.mock.calls[0]!
+    expect(bin).toBe('security')
+    expect(args).toEqual(['-i'])
+    expect(options.input).toContain('-a default')
+    expect(options.input).toContain('-w "sktsec_abc"')
+  })
+
+  it('keeps Windows tokens out of the command line', () => {
+    setPlatform('win32')
+
+    storeKeychainToken('default', 'sktsec_abc')
+
+    const [bin, args, options] = mockSpawnSync.mock.calls[0]!
+    expect(bin).toBe('powershell.exe')
+    expect(args.join(' ')).not.toContain('sktsec_abc')
+    expect(options.input).toBe('sktsec_abc\n')
+  })
+
+  it('caches tokens it has read', () => {
+    setPlatform('linux')
+
+    readKeychainToken('default')
+    readKeychainToken('default')
+
+    expect(mockSpawnSync).toHaveBeenCalledTimes(1)
+  })
+
+  it('treats failed lookups as no token', () => {
+    setPlatform('linux')
+    mockSpawnSync.mockReturnValue({ status: 1, stdout: '' })
+
+    expect(readKeychainToken('default')).toBeUndefined()
+  })
+
+  it('reports a tool that could not be spawned', () => {
+    setPlatform('linux')
+    mockSpawnSync.mockImplementation(() => {
+      throw new Error('ENOENT')
+    })
+
+    expect(storeKeychainToken('default', 'sktsec_abc')).toMatchObject({
+      ok: false,
+      message: 'OS keychain unavailable',
+    })
+  })
+
+  it('is disabled through SOCKET_CLI_NO_KEYCHAIN', () => {
+    setPlatform('linux')
+    process.env['SOCKET_CLI_NO_KEYCHAIN'] = '1'
+
+    expect(isKeychainEnabled()).toBe(false)
+    expect(storeKeychainToken('default', 'sktsec_abc')).toMatchObject({
+      ok: false,
+      message: 'OS keychain disabled',
+    })
+    expect(readKeychainToken('default')).toBeUndefined()
+    expect(mockSpawnSync).not.toHaveBeenCalled()
+  })
+
+  it('rejects accounts and tokens that cannot be passed safely', () => {
+    setPlatform('darwin')
+
+    expect(storeKeychainToken('default', 'has "quotes"').ok).toBe(false)
+    expect(storeKeychainToken("x';rm", 'sktsec_abc').ok).toBe(false)
+    expect(readKeychainToken('../x')).toBeUndefined()
+    expect(mockSpawnSync).not.toHaveBeenCalled()
+  })
+})
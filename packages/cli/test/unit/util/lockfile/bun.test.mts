@@ -0,0 +1,209 @@
+/**
+ * Unit tests for the Bun lockfile parser.
+ *
+ * Purpose: Tests parsing of the text bun.lock and the binary bun.lockb into
+ * npm dependencies.
+ *
+ * Test Coverage: - JSON with trailing commas - Nested install path resolution
+ * - Workspace attribution and dev detection - Integrity hashes - bun.lockb
+ * decoding via its Yarn v1 form - Direct dependencies from package.json.
+ *
+ * Related Files: - src/util/lockfile/bun.mts (implementation)
+ */
+
+import path from 'node:path'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const mockParseBunLockb = vi.hoisted(() => vi.fn())
+
+vi.mock(import('@socketregistry/hyrious__bun.lockb/index.cjs'), () => ({
+  parse: mockParseBunLockb,
+}))
+
+import {
+  bunLockfileParser,
+  parseBunLock,
+  parseJsonWithTrailingCommas,
+  parseYarnLockV1,
+} from '../../../../src/util/lockfile/bun.mts'
+
+const SHA512_HEX = 'ef'.repeat(64)
+
+const INTEGRITY = `sha512-${Buffer.from(SHA512_HEX, 'hex').toString('base64')}`
+
+const BUN_LOCK = `{
+  "lockfileVersion": 1,
+  "workspaces": {
+    "": {
+      "name": "monorepo",
+      "devDependencies": {
+        "typescript": "^5.3.0",
+      },
+    },
+    "packages/ui": {
+      "name": "@acme/ui",
+      "dependencies": {
+        "react-dom": "^18.2.0",
+        "scheduler": "^0.22.0",
+      },
+    },
+  },
+  "packages": {
+    "@acme/ui": ["@acme/ui@workspace:packages/ui"],
+    "loose-envify": ["loose-envify@1.4.0", "", {}, "${INTEGRITY}"],
+    "react": ["react@18.2.0", "", { "dependencies": { "loose-envify": "^1.1.0" } }, "${INTEGRITY}"],
+    "react-dom": ["react-dom@18.2.0", "", { "dependencies": { "loose-envify": "^1.1.0", "scheduler": "^0.23.0" }, "peerDependencies": { "react": "^18.2.0" } }, "${INTEGRITY}"],
+    "react-dom/scheduler": ["scheduler@0.23.0", "", { "dependencies": { "loose-envify": "^1.1.0" } }, "${INTEGRITY}"],
+    "scheduler": ["scheduler@0.22.0", "", {}, "${INTEGRITY}"],
+    "typescript": ["typescript@5.3.3", "", {}, "${INTEGRITY}"],
+  }
+}
+`
+
+const YARN_LOCK = `# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+# bun ./bun.lockb --hash: 0000
+
+"loose-envify@^1.1.0":
+  version "1.4.0"
+  resolved "https://registry.npmjs.org/loose-envify/-/loose-envify-1.4.0.tgz"
+  integrity ${INTEGRITY}
+
+"react@^18.2.0", react@^18.0.0:
+  version "18.2.0"
+  resolved "https://registry.npmjs.org/react/-/react-18.2.0.tgz"
+  integrity ${INTEGRITY}
+  dependencies:
+    loose-envify "^1.1.0"
+
+"typescript@^5.3.0":
+  version "5.3.3"
+  resolved "https://registry.npmjs.org/typescript/-/typescript-5.3.3.tgz"
+`
+
+describe('bun lockfile parser', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  describe('parseJsonWithTrailingCommas', () => {
+    it('should drop trailing commas and comments outside strings', () => {
+      expect(
+        parseJsonWithTrailingCommas(
+          '{ "a": [1, 2,], // note\n "b": "x,}", /* c */ }',
+        ),
+      ).toEqual({ a: [1, 2], b: 'x,}' })
+    })
+  })
+
+  describe('parseBunLock', () => {
+    it('should resolve nested install paths and attribute workspaces', () => {
+      const deps = parseBunLock(BUN_LOCK)!
+      const byId = Object.fromEntries(deps.map(d => [d.id, d]))
+
+      expect(Object.keys(byId).sort()).toEqual([
+        'loose-envify@1.4.0',
+        'react-dom@18.2.0',
+        'react@18.2.0',
+        'scheduler@0.22.0',
+        'scheduler@0.23.0',
+        'typescript@5.3.3',
+      ])
+      expect(byId['react-dom@18.2.0']).toEqual({
+        id: 'react-dom@18.2.0',
+        type: 'npm',
+        name: 'react-dom',
+        version: '18.2.0',
+        direct: true,
+        dev: false,
+        workspaces: ['@acme/ui'],
+        hashes: [{ alg: 'SHA-512', content: SHA512_HEX }],
+        dependencies: ['loose-envify@1.4.0', 'scheduler@0.23.0'],
+      })
+      expect(byId['scheduler@0.23.0']).toMatchObject({
+        direct: false,
+        workspaces: ['@acme/ui'],
+      })
+      expect(byId['scheduler@0.22.0']!.direct).toBe(true)
+      expect(byId['typescript@5.3.3']).toMatchObject({
+        direct: true,
+        dev: true,
+        workspaces: ['monorepo'],
+      })
+      // Not reachable from any workspace.
+      expect(byId['react@18.2.0']!.workspaces).toBeUndefined()
+    })
+
+    it('should return undefined for invalid input', () => {
+      expect(parseBunLock('{')).toBeUndefined()
+    })
+  })
+
+  describe('parseYarnLockV1', () => {
+    it('should key entries by every spec', () => {
+      const bySpec = parseYarnLockV1(YARN_LOCK)
+
+      expect(bySpec.get('react@^18.0.0')).toBe(bySpec.get('react@^18.2.0'))
+      expect(bySpec.get('react@^18.2.0')).toEqual({
+        name: 'react',
+        version: '18.2.0',
+        integrity: INTEGRITY,
+        dependencies: { 'loose-envify': '^1.1.0' },
+      })
+    })
+
+    it('should install the real package of aliases', () => {
+      const bySpec = parseYarnLockV1(`"strip@npm:strip-ansi@^6.0.1":
+  version "6.0.1"
+`)
+
+      expect(bySpec.get('strip@npm:strip-ansi@^6.0.1')?.name).toBe(
+        'strip-ansi',
+      )
+    })
+  })
+
+  describe('bunLockfileParser', () => {
+    it('should decode bun.lockb with direct dependencies from package.json', () => {
+      mockParseBunLockb.mockReturnValue(YARN_LOCK)
+
+      const parsed = bunLockfileParser.parse(
+        Buffer.from('binary'),
+        makeContext('/repo/bun.lockb', {
+          [path.resolve('/repo/package.json')]: JSON.stringify({
+            dependencies: { react: '^18.2.0' },
+            devDependencies: { typescript: '^5.3.0' },
+          }),
+        }),
+      )!
+
+      expect(mockParseBunLockb).toHaveBeenCalledWith(Buffer.from('binary'))
+      expect(parsed.ecosystem).toBe('npm')
+      expect(
+        parsed.dependencies.map(({ dev, direct, id }) => ({ dev, direct, id })),
+      ).toEqual([
+        { dev: false, direct: false, id: 'loose-envify@1.4.0' },
+        { dev: false, direct: true, id: 'react@18.2.0' },
+        { dev: true, direct: true, id: 'typescript@5.3.3' },
+      ])
+      expect(parsed.dependencies[1]!.dependencies).toEqual([
+        'loose-envify@1.4.0',
+      ])
+    })
+
+    it('should return undefined when bun.lockb cannot be decoded', () => {
+      mockParseBunLockb.mockImplementation(() => {
+        throw new Error('bad lockfile')
+      })
+
+      expect(
+        bunLockfileParser.parse(
+          Buffer.from('binary'),
+          makeContext('/repo/bun.lockb'),
+        ),
+      ).toBeUndefined()
+    })
+  })
+})
@@ -0,0 +1,290 @@
+/**
+ * Unit tests for the Cargo lockfile parser.
+ *
+ * Purpose: Tests parsing of Cargo.lock (with the workspace Cargo.toml files)
+ * into resolved crate dependencies.
+ *
+ * Test Coverage: - v1 and v3 lockfile packages and checksums - Workspace
+ * members and direct dependencies - Git and alternate registry sources - Dev
+ * dependency detection from Cargo.toml - TOML table parsing.
+ *
+ * Related Files: - src/util/lockfile/cargo.mts (implementation) -
+ * src/util/lockfile/toml.mts (TOML reader)
+ */
+
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getCargoSourceQualifiers,
+  parseCargoDependencies,
+  parseCargoLock,
+  parseCargoManifest,
+} from '../../../../src/util/lockfile/cargo.mts'
+import { parseTomlTables } from '../../../../src/util/lockfile/toml.mts'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const CRATES_IO = 'registry+https://github.com/rust-lang/crates.io-index'
+
+const SERDE_SUM =
+  '3fb1c873e1b9b056a4dc4c0c198b24c3ffa059243875552b2bd0933b1aee4ce2'
+
+const CARGO_LOCK = `# This file is automatically @generated by Cargo.
+# It is not intended for manual editing.
+version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "serde",
+ "tempfile",
+ "tokio",
+]
+
+[[package]]
+name = "cli"
+version = "0.1.0"
+dependencies = [
+ "app",
+ "serde",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.190"
+source = "${CRATES_IO}"
+checksum = "${SERDE_SUM}"
+
+[[package]]
+name = "tempfile"
+version = "3.8.1"
+source = "${CRATES_IO}"
+checksum = "7ef1adac450ad7f4b3c28589471ade84f25f731a7a0fe30d71dfa9f60fd808e5"
+dependencies = [
+ "fastrand",
+]
+
+[[package]]
+name = "fastrand"
+version = "2.0.1"
+source = "${CRATES_IO}"
+checksum = "25cbce373ec4653f1a01a31e8a5e5ec0c622dc27ff9c4e6606eefef5cbbed4a5"
+
+[[package]]
+name = "tokio"
+version = "1.34.0"
+source = "git+https://github.com/tokio-rs/tokio?branch=master#a1b2c3d"
+`
+
+const ROOT_MANIFEST = `[workspace]
+members = ["crates/*"]
+`
+
+const APP_MANIFEST = `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = { version = "1", features = ["derive"] }
+
+[dependencies.tokio]
+git = "https://github.com/tokio-rs/tokio"
+
+[dev-dependencies]
+tempfile = "3"
+`
+
+const CLI_MANIFEST = `[package]
+name = "cli"
+version = "0.1.0"
+
+[dependencies]
+app = { path = "../app" }
+serde = "1"
+`
+
+describe('cargo lockfile parser', () => {
+  describe('parseTomlTables', () => {
+    it('should parse tables, array tables and inline values', () => {
+      const tables = parseTomlTables(`title = "x" # comment
+[target.'cfg(unix)'.dependencies]
+libc = { version = "0.2", default-features = false }
+
+[[bin]]
+name = 'tool'
+list = [
+  1,
+  "two",
+]
+`)
+
+      expect(tables).toEqual([
+        { header: '', isArray: false, entries: { title: 'x' } },
+        {
+          header: 'target.cfg(unix).dependencies',
+          isArray: false,
+          entries: { libc: { version: '0.2', 'default-features': false } },
+        },
+        {
+          header: 'bin',
+          isArray: true,
+          entries: { name: 'tool', list: [1, 'two'] },
+        },
+      ])
+    })
+
+    it('should read CRLF files like LF ones', () => {
+      const source = `# comment
+[package]
+name = "app"
+description = """
+Multi-line."""
+list = [
+  "a",
+]
+`
+      expect(parseTomlTables(source.replaceAll('\n', '\r\n'))).toEqual(
+        parseTomlTables(source),
+      )
+      expect(parseCargoLock(CARGO_LOCK.replaceAll('\n', '\r\n'))).toEqual(
+        parseCargoLock(CARGO_LOCK),
+      )
+    })
+  })
+
+  describe('parseCargoLock', () => {
+    it('should parse v3 packages with checksums and dependencies', () => {
+      const packages = parseCargoLock(CARGO_LOCK)
+
+      expect(packages).toHaveLength(6)
+      expect(packages[0]).toEqual({
+        name: 'app',
+        version: '0.1.0',
+        dependencies: ['serde', 'tempfile', 'tokio'],
+      })
+      expect(packages[2]).toEqual({
+        name: 'serde',
+        version: '1.0.190',
+        source: CRATES_IO,
+        checksum: SERDE_SUM,
+        dependencies: [],
+      })
+    })
+
+    it('should read v1 checksums from the metadata table', () => {
+      const packages = parseCargoLock(`[[package]]
+name = "serde"
+version = "1.0.190"
+source = "${CRATES_IO}"
+
+[metadata]
+"checksum serde 1.0.190 (${CRATES_IO})" = "${SERDE_SUM}"
+`)
+
+      expect(packages[0]!.checksum).toBe(SERDE_SUM)
+    })
+  })
+
+  describe('getCargoSourceQualifiers', () => {
+    it('should not qualify crates.io packages', () => {
+      expect(getCargoSourceQualifiers(CRATES_IO)).toBeUndefined()
+      expect(getCargoSourceQualifiers(undefined)).toBeUndefined()
+    })
+
+    it('should pin git sources to their commit', () => {
+      expect(
+        getCargoSourceQualifiers(
+          'git+https://github.com/tokio-rs/tokio?branch=master#a1b2c3d',
+        ),
+      ).toEqual({ vcs_url: 'git+https://github.com/tokio-rs/tokio@a1b2c3d' })
+    })
+
+    it('should keep alternate registries', () => {
+      expect(
+        getCargoSourceQualifiers('sparse+https://cargo.example.com/index/'),
+      ).toEqual({ repository_url: 'https://cargo.example.com/index/' })
+    })
+  })
+
+  describe('parseCargoManifest', () => {
+    it('should collect normal and dev dependency names', () => {
+      const { deps, members } = parseCargoManifest(`[package]
+name = "app"
+
+[dependencies]
+json = { package = "serde_json", version = "1" }
+
+[target.'cfg(windows)'.dev-dependencies]
+winapi = "0.3"
+
+[workspace]
+members = ["a", "b"]
+`)
+
+      expect(deps.name).toBe('app')
+      expect([...deps.normal]).toEqual(['serde_json'])
+      expect([...deps.dev]).toEqual(['winapi'])
+      expect(members).toEqual(['a', 'b'])
+    })
+  })
+
+  describe('parseCargoDependencies', () => {
+    it('should skip workspace members and mark their dependencies direct', () => {
+      const deps = parseCargoDependencies(
+        CARGO_LOCK,
+        makeContext('/repo/Cargo.lock'),
+      )
+      const byName = Object.fromEntries(deps.map(d => [d.name, d]))
+
+      expect(Object.keys(byName).sort()).toEqual([
+        'fastrand',
+        'serde',
+        'tempfile',
+        'tokio',
+      ])
+      expect(byName['serde']).toEqual({
+        id: `serde@1.0.190 (${CRATES_IO})`,
+        type: 'cargo',
+        name: 'serde',
+        version: '1.0.190',
+        direct: true,
+        workspaces: ['app', 'cli'],
+        hashes: [{ alg: 'SHA-256', content: SERDE_SUM }],
+      })
+      expect(byName['fastrand']!.direct).toBe(false)
+      expect(byName['tempfile']!.dependencies).toEqual([
+        `fastrand@2.0.1 (${CRATES_IO})`,
+      ])
+      expect(byName['tokio']!.qualifiers).toEqual({
+        vcs_url: 'git+https://github.com/tokio-rs/tokio@a1b2c3d',
+      })
+      // Without manifests dev-only crates cannot be told apart.
+      expect(byName['tempfile']!.dev).toBeUndefined()
+    })
+
+    it('should mark crates only reachable from dev-dependencies as dev', () => {
+      const deps = parseCargoDependencies(
+        CARGO_LOCK,
+        makeContext(
+          '/repo/Cargo.lock',
+          {
+            [path.resolve('/repo/Cargo.toml')]: ROOT_MANIFEST,
+            [path.resolve('/repo/crates/app/Cargo.toml')]: APP_MANIFEST,
+            [path.resolve('/repo/crates/cli/Cargo.toml')]: CLI_MANIFEST,
+          },
+          { [path.resolve('/repo/crates')]: ['app', 'cli'] },
+        ),
+      )
+      const dev = Object.fromEntries(deps.map(d => [d.name, d.dev]))
+
+      expect(dev).toEqual({
+        fastrand: true,
+        serde: false,
+        tempfile: true,
+        tokio: false,
+      })
+    })
+  })
+})
@@ -0,0 +1,176 @@
+/**
+ * Unit tests for the Composer lockfile parser.
+ *
+ * Purpose: Tests parsing of composer.lock (with composer.json) into resolved
+ * PHP package dependencies.
+ *
+ * Test Coverage: - Prod and dev packages - Direct dependencies from
+ * composer.json - Dist shasum hashes - Source commits for branch versions -
+ * Path repositories - Platform requirements.
+ *
+ * Related Files: - src/util/lockfile/composer.mts (implementation)
+ */
+
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getComposerQualifiers,
+  isComposerPlatformPackage,
+  parseComposerDependencies,
+} from '../../../../src/util/lockfile/composer.mts'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const SHASUM = 'a3e1e6d2f2b1c9bb3d0f95a8d7f7c2a4d9d6c8e1'
+
+const COMMIT = '0b3f1c4f5e9c4d2f6a7b8c9d0e1f2a3b4c5d6e7f'
+
+const COMPOSER_LOCK = JSON.stringify({
+  packages: [
+    {
+      name: 'monolog/monolog',
+      version: '3.5.0',
+      source: {
+        type: 'git',
+        url: 'https://github.com/Seldaek/monolog.git',
+        reference: COMMIT,
+      },
+      dist: {
+        type: 'zip',
+        url: 'https://api.github.com/repos/Seldaek/monolog/zipball/0b3f1c4',
+        reference: COMMIT,
+        shasum: SHASUM.toUpperCase(),
+      },
+      require: { php: '>=8.1', 'psr/log': '^2.0 || ^3.0', 'ext-json': '*' },
+    },
+    {
+      name: 'psr/log',
+      version: '3.0.0',
+      dist: { type: 'zip', url: 'https://example.com/log.zip', shasum: '' },
+    },
+    {
+      name: 'acme/internal',
+      version: 'dev-main',
+      source: {
+        type: 'git',
+        url: 'https://git.example.com/acme/internal.git',
+        reference: COMMIT,
+      },
+    },
+    {
+      name: 'acme/local',
+      version: 'dev-main',
+      dist: { type: 'path', url: '../local', reference: 'abc' },
+      require: { 'acme/internal': 'dev-main' },
+    },
+  ],
+  'packages-dev': [
+    {
+      name: 'phpunit/phpunit',
+      version: '10.5.0',
+      require: { 'psr/log': '^3.0' },
+    },
+  ],
+})
+
+const COMPOSER_JSON = JSON.stringify({
+  require: { php: '^8.1', 'monolog/monolog': '^3.5', 'acme/local': '*' },
+  'require-dev': { 'phpunit/phpunit': '^10.5' },
+})
+
+describe('composer lockfile parser', () => {
+  describe('isComposerPlatformPackage', () => {
+    it.each(['php', 'php-64bit', 'ext-json', 'lib-icu', 'composer-plugin-api'])(
+      'should treat %s as a platform package',
+      name => {
+        expect(isComposerPlatformPackage(name)).toBe(true)
+      },
+    )
+
+    it('should not treat vendor packages as platform packages', () => {
+      expect(isComposerPlatformPackage('phpunit/phpunit')).toBe(false)
+    })
+  })
+
+  describe('getComposerQualifiers', () => {
+    it('should only pin branch versions to their source commit', () => {
+      expect(
+        getComposerQualifiers({
+          name: 'acme/internal',
+          version: 'dev-main',
+          source: {
+            type: 'git',
+            url: 'https://git.example.com/acme/internal.git',
+            reference: COMMIT,
+          },
+        }),
+      ).toEqual({
+        vcs_url: `git+https://git.example.com/acme/internal.git@${COMMIT}`,
+      })
+      expect(
+        getComposerQualifiers({
+          name: 'psr/log',
+          version: '3.0.0',
+          source: { type: 'git', url: 'https://x', reference: COMMIT },
+        }),
+      ).toBeUndefined()
+    })
+  })
+
+  describe('parseComposerDependencies', () => {
+    it('should return undefined for invalid JSON', () => {
+      expect(
+        parseComposerDependencies('{', makeContext('/repo/composer.lock')),
+      ).toBeUndefined()
+    })
+
+    it('should map packages with hashes, edges and dev flags', () => {
+      const deps = parseComposerDependencies(
+        COMPOSER_LOCK,
+        makeContext('/repo/composer.lock', {
+          [path.resolve('/repo/composer.json')]: COMPOSER_JSON,
+        }),
+      )!
+      const byName = Object.fromEntries(deps.map(d => [d.name, d]))
+
+      expect(Object.keys(byName)).toEqual([
+        'monolog',
+        'log',
+        'internal',
+        'phpunit',
+      ])
+      expect(byName['monolog']).toEqual({
+        id: 'monolog/monolog@3.5.0',
+        type: 'composer',
+        namespace: 'monolog',
+        name: 'monolog',
+        version: '3.5.0',
+        direct: true,
+        dev: false,
+        hashes: [{ alg: 'SHA-1', content: SHASUM }],
+        dependencies: ['psr/log@3.0.0'],
+      })
+      expect(byName['log']!.direct).toBe(false)
+      expect(byName['log']!.hashes).toBeUndefined()
+      expect(byName['phpunit']).toMatchObject({ direct: true, dev: true })
+      // Pulled in by the local path package rather than the root.
+      expect(byName['internal']).toMatchObject({
+        direct: true,
+        workspaces: ['acme/local'],
+        qualifiers: {
+          vcs_url: `git+https://git.example.com/acme/internal.git@${COMMIT}`,
+        },
+      })
+    })
+
+    it('should leave direct unset without a composer.json', () => {
+      const deps = parseComposerDependencies(
+        JSON.stringify({ packages: [{ name: 'psr/log', version: '3.0.0' }] }),
+        makeContext('/repo/composer.lock'),
+      )!
+
+      expect(deps[0]!.direct).toBeUndefined()
+    })
+  })
+})
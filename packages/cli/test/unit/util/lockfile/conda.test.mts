@@ -0,0 +1,268 @@
+/**
+ * Unit tests for the conda parsers.
+ *
+ * Purpose: Tests parsing of environment.yml and conda-lock.yml into conda
+ * and PyPI dependencies.
+ *
+ * Test Coverage: - Match spec pins, builds and channels - Embedded pip
+ * sections - Per-platform packages and noarch merging - Dev categories and
+ * direct dependencies from the lock sources.
+ *
+ * Related Files: - src/util/lockfile/conda.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getCondaUrlQualifiers,
+  parseCondaEnvironmentDependencies,
+  parseCondaLockDependencies,
+  parseCondaMatchSpec,
+} from '../../../../src/util/lockfile/conda.mts'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const ENVIRONMENT_YML = `name: analysis
+channels:
+  - conda-forge
+dependencies:
+  - python=3.11
+  - numpy==1.26.4=py311h64a7726_0
+  - conda-forge::pandas>=2.1
+  - __glibc>=2.17
+  - pip
+  - pip:
+      - --index-url https://pypi.example/simple
+      - requests==2.31.0
+      - Rich>=13; python_version >= "3.11"
+`
+
+const CONDA = 'https://conda.anaconda.org/conda-forge'
+
+const CONDA_LOCK_YML = `version: 1
+metadata:
+  platforms:
+  - linux-64
+  - osx-arm64
+  sources:
+  - environment.yml
+package:
+- name: numpy
+  version: 1.26.4
+  manager: conda
+  platform: linux-64
+  dependencies:
+    python: '>=3.11,<3.12.0a0'
+  url: ${CONDA}/linux-64/numpy-1.26.4-py311h64a7726_0.conda
+  hash:
+    md5: a502d7aad449a1206efb366d6a12c52d
+    sha256: ${'c'.repeat(64)}
+  category: main
+  optional: false
+- name: numpy
+  version: 1.26.4
+  manager: conda
+  platform: osx-arm64
+  dependencies:
+    python: '>=3.11,<3.12.0a0'
+  url: ${CONDA}/osx-arm64/numpy-1.26.4-py311h7125741_0.conda
+  category: main
+  optional: false
+- name: python
+  version: 3.11.8
+  manager: conda
+  platform: linux-64
+  dependencies: {}
+  url: ${CONDA}/linux-64/python-3.11.8-hab00c5b_0_cpython.conda
+  category: main
+  optional: false
+- name: python
+  version: 3.11.8
+  manager: conda
+  platform: osx-arm64
+  dependencies: {}
+  url: ${CONDA}/osx-arm64/python-3.11.8-hdf0ec26_0_cpython.tar.bz2
+  category: main
+  optional: false
+- name: pytest
+  version: 8.0.2
+  manager: conda
+  platform: linux-64
+  dependencies:
+    python: '>=3.8'
+  url: ${CONDA}/noarch/pytest-8.0.2-pyhd8ed1ab_0.conda
+  category: dev
+  optional: true
+- name: pytest
+  version: 8.0.2
+  manager: conda
+  platform: osx-arm64
+  dependencies:
+    python: '>=3.8'
+  url: ${CONDA}/noarch/pytest-8.0.2-pyhd8ed1ab_0.conda
+  category: dev
+  optional: true
+- name: Requests
+  version: 2.31.0
+  manager: pip
+  platform: linux-64
+  dependencies:
+    numpy: '*'
+  url: https://files.pythonhosted.org/packages/requests-2.31.0-py3-none-any.whl
+  hash:
+    sha256: ${'d'.repeat(64)}
+  category: main
+  optional: false
+`
+
+describe('conda parsers', () => {
+  describe('parseCondaMatchSpec', () => {
+    it('should read pins, builds and channels', () => {
+      expect(parseCondaMatchSpec('numpy=1.26.4=py311_0')).toEqual({
+        build: 'py311_0',
+        name: 'numpy',
+        version: '1.26.4',
+      })
+      expect(parseCondaMatchSpec('conda-forge::SciPy 1.11.4')).toEqual({
+        channel: 'conda-forge',
+        name: 'scipy',
+        version: '1.11.4',
+      })
+      expect(parseCondaMatchSpec('pandas>=2.1,<3')).toEqual({ name: 'pandas' })
+      expect(parseCondaMatchSpec('pandas=2.*')).toEqual({ name: 'pandas' })
+    })
+
+    it('should skip virtual packages', () => {
+      expect(parseCondaMatchSpec('__cuda>=12')).toBeUndefined()
+    })
+  })
+
+  describe('getCondaUrlQualifiers', () => {
+    it('should read the channel, subdir and build', () => {
+      expect(
+        getCondaUrlQualifiers(`${CONDA}/noarch/pytest-8.0.2-pyhd8ed1ab_0.conda`),
+      ).toEqual({
+        build: 'pyhd8ed1ab_0',
+        channel: 'conda-forge',
+        subdir: 'noarch',
+      })
+      expect(
+        getCondaUrlQualifiers('https://files.example/requests-2.31.0.whl'),
+      ).toBeUndefined()
+    })
+  })
+
+  describe('parseCondaEnvironmentDependencies', () => {
+    it('should map conda and pip entries as direct dependencies', () => {
+      expect(parseCondaEnvironmentDependencies(ENVIRONMENT_YML)).toEqual([
+        {
+          id: 'conda:python',
+          type: 'conda',
+          name: 'python',
+          version: '3.11',
+          direct: true,
+        },
+        {
+          id: 'conda:numpy',
+          type: 'conda',
+          name: 'numpy',
+          version: '1.26.4',
+          qualifiers: { build: 'py311h64a7726_0' },
+          direct: true,
+        },
+        {
+          id: 'conda:pandas',
+          type: 'conda',
+          name: 'pandas',
+          version: '',
+          qualifiers: { channel: 'conda-forge' },
+          direct: true,
+        },
+        {
+          id: 'conda:pip',
+          type: 'conda',
+          name: 'pip',
+          version: '',
+          direct: true,
+        },
+        {
+          id: 'pypi:requests',
+          type: 'pypi',
+          name: 'requests',
+          version: '2.31.0',
+          direct: true,
+        },
+        {
+          id: 'pypi:rich',
+          type: 'pypi',
+          name: 'rich',
+          version: '',
+          direct: true,
+          markers: ['python_version >= "3.11"'],
+        },
+      ])
+    })
+
+    it('should read an environment without dependencies', () => {
+      expect(parseCondaEnvironmentDependencies('name: empty\n')).toEqual([])
+    })
+  })
+
+  describe('parseCondaLockDependencies', () => {
+    const deps = parseCondaLockDependencies(
+      CONDA_LOCK_YML,
+      makeContext('/repo/conda-lock.yml', {
+        '/repo/environment.yml': ENVIRONMENT_YML,
+      }),
+    )
+    const byBuild = (build: string) =>
+      deps.find(dep => dep.qualifiers?.['build'] === build)
+
+    it('should keep one package per platform build', () => {
+      expect(deps).toHaveLength(6)
+      expect(byBuild('py311h64a7726_0')).toMatchObject({
+        type: 'conda',
+        name: 'numpy',
+        version: '1.26.4',
+        qualifiers: { channel: 'conda-forge', subdir: 'linux-64' },
+        direct: true,
+        dev: false,
+        hashes: [
+          { alg: 'MD5', content: 'a502d7aad449a1206efb366d6a12c52d' },
+          { alg: 'SHA-256', content: 'c'.repeat(64) },
+        ],
+        dependencies: [byBuild('hab00c5b_0_cpython')!.id],
+      })
+      expect(byBuild('py311h7125741_0')!.dependencies).toEqual([
+        byBuild('hdf0ec26_0_cpython')!.id,
+      ])
+    })
+
+    it('should merge noarch packages and mark dev categories', () => {
+      const pytest = deps.filter(dep => dep.name === 'pytest')
+      expect(pytest).toHaveLength(1)
+      expect(pytest[0]).toMatchObject({
+        direct: false,
+        dev: true,
+        scopes: ['dev'],
+      })
+      expect(pytest[0]!.dependencies).toHaveLength(2)
+    })
+
+    it('should link pip packages to conda packages', () => {
+      expect(deps.find(dep => dep.type === 'pypi')).toMatchObject({
+        name: 'requests',
+        version: '2.31.0',
+        direct: true,
+        dependencies: [byBuild('py311h64a7726_0')!.id],
+      })
+    })
+
+    it('should leave direct unset without the lock sources', () => {
+      const deps = parseCondaLockDependencies(
+        CONDA_LOCK_YML,
+        makeContext('/repo/conda-lock.yml'),
+      )
+      expect(deps.every(dep => dep.direct === undefined)).toBe(true)
+    })
+  })
+})
@@ -0,0 +1,152 @@
+/**
+ * Unit tests for the Go modules lockfile parser.
+ *
+ * Purpose: Tests parsing of go.mod and go.sum into resolved Go module
+ * dependencies.
+ *
+ * Test Coverage: - go.mod require/replace/exclude blocks - go.sum hashes -
+ * Module and filesystem replace directives - go.sum fallback for older go.mod
+ * files.
+ *
+ * Related Files: - src/util/lockfile/go.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  goSumHashToLockfileHash,
+  parseGoMod,
+  parseGoModules,
+  parseGoSum,
+} from '../../../../src/util/lockfile/go.mts'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const GO_MOD = `module example.com/app
+
+go 1.21
+
+require (
+\tgithub.com/gin-gonic/gin v1.9.1
+\tgolang.org/x/net v0.17.0 // indirect
+\texample.com/old v1.0.0
+\texample.com/lib v0.0.0
+)
+
+require github.com/pkg/errors v0.9.1
+
+exclude golang.org/x/net v0.16.0
+
+replace example.com/old => example.com/new v1.2.0
+
+replace example.com/lib => ../lib
+`
+
+const H1 = 'h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU='
+
+describe('go lockfile parser', () => {
+  describe('parseGoMod', () => {
+    it('should parse module, requires, replaces and excludes', () => {
+      const mod = parseGoMod(GO_MOD)
+
+      expect(mod.module).toBe('example.com/app')
+      expect(mod.go).toBe('1.21')
+      expect(mod.requires).toEqual([
+        { path: 'github.com/gin-gonic/gin', version: 'v1.9.1', indirect: false },
+        { path: 'golang.org/x/net', version: 'v0.17.0', indirect: true },
+        { path: 'example.com/old', version: 'v1.0.0', indirect: false },
+        { path: 'example.com/lib', version: 'v0.0.0', indirect: false },
+        { path: 'github.com/pkg/errors', version: 'v0.9.1', indirect: false },
+      ])
+      expect(mod.excludes).toEqual([
+        { path: 'golang.org/x/net', version: 'v0.16.0' },
+      ])
+      expect(mod.replaces).toEqual([
+        {
+          oldPath: 'example.com/old',
+          newPath: 'example.com/new',
+          newVersion: 'v1.2.0',
+        },
+        { oldPath: 'example.com/lib', newPath: '../lib' },
+      ])
+    })
+  })
+
+  describe('parseGoSum', () => {
+    it('should skip go.mod hash lines', () => {
+      const sums = parseGoSum(
+        `github.com/pkg/errors v0.9.1 ${H1}\ngithub.com/pkg/errors v0.9.1/go.mod h1:other=\n`,
+      )
+
+      expect([...sums]).toEqual([['github.com/pkg/errors v0.9.1', H1]])
+    })
+
+    it('should convert h1 hashes to hex SHA-256', () => {
+      expect(goSumHashToLockfileHash(H1)).toEqual({
+        alg: 'SHA-256',
+        content:
+          'e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855',
+      })
+      expect(goSumHashToLockfileHash('h2:abc')).toBeUndefined()
+    })
+  })
+
+  describe('parseGoModules', () => {
+    it('should apply module and filesystem replace directives', () => {
+      const context = makeContext('/repo/app/go.mod', {
+        '/repo/app/go.sum': `github.com/pkg/errors v0.9.1 ${H1}\n`,
+        '/repo/lib/go.mod':
+          'module example.com/lib\n\nrequire github.com/google/uuid v1.3.0\n',
+      })
+      const { dependencies, requiresLocalResolution } = parseGoModules(
+        GO_MOD,
+        context,
+      )
+
+      expect(requiresLocalResolution).toBe(true)
+      expect(dependencies.map(d => d.id)).toEqual([
+        'github.com/gin-gonic/gin@v1.9.1',
+        'golang.org/x/net@v0.17.0',
+        'example.com/new@v1.2.0',
+        'github.com/google/uuid@v1.3.0',
+        'github.com/pkg/errors@v0.9.1',
+      ])
+      expect(dependencies[0]).toMatchObject({
+        type: 'golang',
+        namespace: 'github.com/gin-gonic',
+        name: 'gin',
+        version: 'v1.9.1',
+        direct: true,
+      })
+      expect(dependencies[1]!.direct).toBe(false)
+      expect(dependencies[3]).toMatchObject({
+        direct: false,
+        workspaces: ['example.com/lib'],
+      })
+      expect(dependencies[4]!.hashes).toEqual([
+        goSumHashToLockfileHash(H1),
+      ])
+    })
+
+    it('should recover the build list from go.sum for older go.mod files', () => {
+      const context = makeContext('/repo/app/go.mod', {
+        '/repo/app/go.sum': [
+          `golang.org/x/text v0.3.0 ${H1}`,
+          `golang.org/x/text v0.3.7 ${H1}`,
+          'golang.org/x/text v0.3.8/go.mod h1:other=',
+        ].join('\n'),
+      })
+      const { dependencies, requiresLocalResolution } = parseGoModules(
+        'module example.com/app\n',
+        context,
+      )
+
+      expect(requiresLocalResolution).toBe(false)
+      expect(dependencies).toEqual([
+        expect.objectContaining({
+          id: 'golang.org/x/text@v0.3.7',
+          direct: false,
+        }),
+      ])
+    })
+  })
+})
@@ -0,0 +1,179 @@
+/**
+ * Unit tests for the Gradle lockfile parser.
+ *
+ * Purpose: Tests parsing of gradle.lockfile and verification-metadata.xml into
+ * resolved Maven dependencies scoped by Gradle configuration.
+ *
+ * Test Coverage: - Lockfile entries and configurations - Dev configuration
+ * detection - Verification metadata hashes - Metadata lookup from
+ * subprojects - XML reading.
+ *
+ * Related Files: - src/util/lockfile/gradle.mts (implementation) -
+ * src/util/lockfile/xml.mts (XML reader)
+ */
+
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  isGradleDevConfiguration,
+  parseGradleDependencies,
+  parseGradleLockfile,
+  parseGradleVerificationMetadata,
+} from '../../../../src/util/lockfile/gradle.mts'
+import { findXmlElements, parseXml } from '../../../../src/util/lockfile/xml.mts'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const GRADLE_LOCKFILE = `# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+com.google.guava:guava:32.1.3-jre=compileClasspath,runtimeClasspath,testCompileClasspath,testRuntimeClasspath
+junit:junit:4.13.2=testCompileClasspath,testRuntimeClasspath
+com.google.dagger:dagger-compiler:2.48=kapt
+empty=annotationProcessor
+`
+
+const GUAVA_SHA256 =
+  '6d4e2b5a118aab62e6e5e29d185a0224eed82c85c40ac3d33cf04a270c3b3744'
+
+const VERIFICATION_METADATA = `<?xml version="1.0" encoding="UTF-8"?>
+<verification-metadata xmlns="https://schema.gradle.org/dependency-verification">
+   <configuration>
+      <verify-metadata>true</verify-metadata>
+   </configuration>
+   <components>
+      <!-- Generated by Gradle -->
+      <component group="com.google.guava" name="guava" version="32.1.3-jre">
+         <artifact name="guava-32.1.3-jre.pom">
+            <sha256 value="0000000000000000000000000000000000000000000000000000000000000000" origin="Generated by Gradle"/>
+         </artifact>
+         <artifact name="guava-32.1.3-jre.jar">
+            <sha1 value="1A2B3C" origin="Generated by Gradle"/>
+            <sha256 value="${GUAVA_SHA256}" origin="Generated by Gradle"/>
+         </artifact>
+      </component>
+   </components>
+</verification-metadata>
+`
+
+describe('gradle lockfile parser', () => {
+  describe('parseXml', () => {
+    it('should read elements, attributes and text', () => {
+      const doc = parseXml(
+        '<?xml version="1.0"?><a x="1 &amp; 2"><!-- c --><b>t&lt;</b><b/></a>',
+      )
+
+      expect(doc.children).toHaveLength(1)
+      expect(doc.children[0]!.attrs).toEqual({ x: '1 & 2' })
+      expect(findXmlElements(doc, 'b').map(b => b.text)).toEqual(['t<', ''])
+    })
+  })
+
+  describe('parseGradleLockfile', () => {
+    it('should parse coordinates and configurations', () => {
+      const entries = parseGradleLockfile(GRADLE_LOCKFILE)
+
+      expect(entries).toEqual([
+        {
+          group: 'com.google.guava',
+          name: 'guava',
+          version: '32.1.3-jre',
+          configurations: [
+            'compileClasspath',
+            'runtimeClasspath',
+            'testCompileClasspath',
+            'testRuntimeClasspath',
+          ],
+        },
+        {
+          group: 'junit',
+          name: 'junit',
+          version: '4.13.2',
+          configurations: ['testCompileClasspath', 'testRuntimeClasspath'],
+        },
+        {
+          group: 'com.google.dagger',
+          name: 'dagger-compiler',
+          version: '2.48',
+          configurations: ['kapt'],
+        },
+      ])
+    })
+  })
+
+  describe('isGradleDevConfiguration', () => {
+    it.each([
+      'testRuntimeClasspath',
+      'androidTestImplementation',
+      'debugUnitTestCompileClasspath',
+      'annotationProcessor',
+      'testAnnotationProcessor',
+      'kaptDebug',
+    ])('should treat %s as dev', configuration => {
+      expect(isGradleDevConfiguration(configuration)).toBe(true)
+    })
+
+    it.each(['compileClasspath', 'releaseRuntimeClasspath', 'latestClasspath'])(
+      'should not treat %s as dev',
+      configuration => {
+        expect(isGradleDevConfiguration(configuration)).toBe(false)
+      },
+    )
+  })
+
+  describe('parseGradleVerificationMetadata', () => {
+    it('should prefer the jar artifact hashes', () => {
+      const hashes = parseGradleVerificationMetadata(VERIFICATION_METADATA)
+
+      expect(hashes.get('com.google.guava:guava:32.1.3-jre')).toEqual([
+        { alg: 'SHA-1', content: '1a2b3c' },
+        { alg: 'SHA-256', content: GUAVA_SHA256 },
+      ])
+    })
+  })
+
+  describe('parseGradleDependencies', () => {
+    it('should scope dependencies and attach hashes from the root build', () => {
+      const deps = parseGradleDependencies(
+        GRADLE_LOCKFILE,
+        makeContext('/repo/app/gradle.lockfile', {
+          [path.resolve('/repo/gradle/verification-metadata.xml')]:
+            VERIFICATION_METADATA,
+        }),
+      )
+
+      expect(deps[0]).toEqual({
+        id: 'com.google.guava:guava:32.1.3-jre',
+        type: 'maven',
+        namespace: 'com.google.guava',
+        name: 'guava',
+        version: '32.1.3-jre',
+        dev: false,
+        scopes: [
+          'compileClasspath',
+          'runtimeClasspath',
+          'testCompileClasspath',
+          'testRuntimeClasspath',
+        ],
+        hashes: [
+          { alg: 'SHA-1', content: '1a2b3c' },
+          { alg: 'SHA-256', content: GUAVA_SHA256 },
+        ],
+      })
+      expect(deps.map(d => d.dev)).toEqual([false, true, true])
+      expect(deps[1]!.hashes).toBeUndefined()
+    })
+
+    it('should mark buildscript dependencies as dev', () => {
+      const deps = parseGradleDependencies(
+        'com.android.tools.build:gradle:8.1.0=classpath\n',
+        makeContext('/repo/buildscript-gradle.lockfile'),
+      )
+
+      expect(deps).toEqual([
+        expect.objectContaining({ name: 'gradle', dev: true }),
+      ])
+    })
+  })
+})
@@ -0,0 +1,187 @@
+/**
+ * Unit tests for the NuGet lockfile parsers.
+ *
+ * Purpose: Tests parsing of packages.lock.json and SDK-style project files
+ * (with Directory.Packages.props) into resolved NuGet dependencies.
+ *
+ * Test Coverage: - Lock file packages per target framework - Direct,
+ * transitive and centrally pinned entries - Content hashes - PackageReference
+ * versions and ranges - Central package management - Lock file precedence.
+ *
+ * Related Files: - src/util/lockfile/nuget.mts (implementation)
+ */
+
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  getNugetReferenceVersion,
+  nugetProjectParser,
+  parseNugetLockfile,
+  parseNugetProject,
+} from '../../../../src/util/lockfile/nuget.mts'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const SHA512_HEX = 'ab'.repeat(64)
+
+const CONTENT_HASH = Buffer.from(SHA512_HEX, 'hex').toString('base64')
+
+const PACKAGES_LOCK = JSON.stringify({
+  version: 2,
+  dependencies: {
+    'net8.0': {
+      'Serilog.Sinks.Console': {
+        type: 'Direct',
+        requested: '[5.0.1, )',
+        resolved: '5.0.1',
+        contentHash: CONTENT_HASH,
+        dependencies: { Serilog: '3.1.1' },
+      },
+      Serilog: {
+        type: 'CentralTransitive',
+        requested: '[3.1.1, )',
+        resolved: '3.1.1',
+        contentHash: CONTENT_HASH,
+      },
+      'Shared.Lib': {
+        type: 'Project',
+        dependencies: { Serilog: '[3.1.1, )' },
+      },
+    },
+    net48: {
+      serilog: { type: 'Direct', requested: '[3.1.1, )', resolved: '3.1.1' },
+    },
+  },
+})
+
+const PROJECT = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+    <XunitVersion>2.6.2</XunitVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Serilog" />
+    <PackageReference Include="Polly" VersionOverride="8.2.0" />
+    <PackageReference Include="xunit" Version="$(XunitVersion)" />
+    <PackageReference Include="StyleCop.Analyzers">
+      <PrivateAssets>all</PrivateAssets>
+    </PackageReference>
+    <PackageReference Include="Floating" Version="1.*" />
+  </ItemGroup>
+</Project>
+`
+
+const PACKAGES_PROPS = `<Project>
+  <PropertyGroup>
+    <ManagePackageVersionsCentrally>true</ManagePackageVersionsCentrally>
+    <CentralPackageTransitivePinningEnabled>true</CentralPackageTransitivePinningEnabled>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageVersion Include="Serilog" Version="[3.1.1]" />
+    <PackageVersion Include="Polly" Version="8.0.0" />
+    <PackageVersion Include="StyleCop.Analyzers" Version="1.1.118" />
+    <GlobalPackageReference Include="Nerdbank.GitVersioning" Version="3.6.133" />
+  </ItemGroup>
+</Project>
+`
+
+describe('nuget lockfile parsers', () => {
+  describe('parseNugetLockfile', () => {
+    it('should merge target frameworks and skip project references', () => {
+      const deps = parseNugetLockfile(PACKAGES_LOCK)!
+
+      expect(deps).toEqual([
+        {
+          id: 'serilog.sinks.console@5.0.1',
+          type: 'nuget',
+          name: 'Serilog.Sinks.Console',
+          version: '5.0.1',
+          direct: true,
+          scopes: ['net8.0'],
+          hashes: [{ alg: 'SHA-512', content: SHA512_HEX }],
+          dependencies: ['serilog@3.1.1'],
+        },
+        {
+          id: 'serilog@3.1.1',
+          type: 'nuget',
+          name: 'Serilog',
+          version: '3.1.1',
+          // Centrally pinned for net8.0, referenced directly by net48.
+          direct: true,
+          scopes: ['net8.0', 'net48'],
+          hashes: [{ alg: 'SHA-512', content: SHA512_HEX }],
+        },
+      ])
+    })
+
+    it('should return undefined for invalid JSON', () => {
+      expect(parseNugetLockfile('{')).toBeUndefined()
+    })
+  })
+
+  describe('getNugetReferenceVersion', () => {
+    it.each([
+      ['1.2.3', '1.2.3'],
+      ['[1.2.3]', '1.2.3'],
+      ['[1.2.3, )', '1.2.3'],
+      ['[1.0.0, 2.0.0)', '1.0.0'],
+      ['1.0.0-beta.1', '1.0.0-beta.1'],
+      ['(1.0.0, 2.0.0]', undefined],
+      ['1.*', undefined],
+      ['$(Missing)', undefined],
+      [undefined, undefined],
+    ])('should resolve %s to %s', (spec, expected) => {
+      expect(getNugetReferenceVersion(spec)).toBe(expected)
+    })
+  })
+
+  describe('parseNugetProject', () => {
+    it('should resolve references with central package management', () => {
+      const { dependencies, requiresLocalResolution } = parseNugetProject(
+        PROJECT,
+        makeContext('/repo/src/App/App.csproj', {
+          [path.resolve('/repo/Directory.Packages.props')]: PACKAGES_PROPS,
+        }),
+      )
+
+      expect(requiresLocalResolution).toBe(true)
+      expect(
+        dependencies.map(({ dev, name, version }) => ({ dev, name, version })),
+      ).toEqual([
+        { dev: false, name: 'Serilog', version: '3.1.1' },
+        { dev: false, name: 'Polly', version: '8.2.0' },
+        { dev: false, name: 'xunit', version: '2.6.2' },
+        { dev: true, name: 'StyleCop.Analyzers', version: '1.1.118' },
+        { dev: true, name: 'Nerdbank.GitVersioning', version: '3.6.133' },
+      ])
+      expect(dependencies[0]).toMatchObject({
+        direct: true,
+        workspaces: ['App'],
+      })
+    })
+
+    it('should not need local resolution without central versions', () => {
+      const { dependencies, requiresLocalResolution } = parseNugetProject(
+        '<Project><ItemGroup><PackageReference Include="Polly" Version="8.2.0" /></ItemGroup></Project>',
+        makeContext('/repo/App.csproj'),
+      )
+
+      expect(requiresLocalResolution).toBe(false)
+      expect(dependencies.map(d => d.id)).toEqual(['polly@8.2.0'])
+    })
+  })
+
+  describe('nugetProjectParser', () => {
+    it('should defer to a sibling packages.lock.json', () => {
+      const result = nugetProjectParser.parse(
+        PROJECT,
+        makeContext('/repo/App.csproj', {
+          [path.resolve('/repo/packages.lock.json')]: PACKAGES_LOCK,
+        }),
+      )
+
+      expect(result).toBeUndefined()
+    })
+  })
+})
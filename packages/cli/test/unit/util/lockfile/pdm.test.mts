@@ -0,0 +1,161 @@
+/**
+ * Unit tests for the pdm.lock parser.
+ *
+ * Purpose: Tests parsing of pdm.lock, with its pyproject.toml, into
+ * resolved PyPI dependencies.
+ *
+ * Test Coverage: - Folding locked extras into their package - The project
+ * requirements from pyproject.toml - Dev detection from the locked groups -
+ * Markers and sdist hashes.
+ *
+ * Related Files: - src/util/lockfile/pdm.mts (implementation)
+ * - src/util/lockfile/python.mts (shared walk)
+ */
+
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import { parsePdmLockDependencies } from '../../../../src/util/lockfile/pdm.mts'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const COLORAMA_SUM = 'b'.repeat(64)
+
+const PDM_LOCK = `# This file is @generated by PDM.
+[metadata]
+groups = ["default", "socks", "test"]
+lock_version = "4.4"
+
+[[package]]
+name = "colorama"
+version = "0.4.6"
+groups = ["test"]
+marker = "sys_platform == \\"win32\\""
+files = [
+    {file = "colorama-0.4.6-py2.py3-none-any.whl", hash = "sha256:${'a'.repeat(64)}"},
+    {file = "colorama-0.4.6.tar.gz", hash = "sha256:${COLORAMA_SUM}"},
+]
+
+[[package]]
+name = "PySocks"
+version = "1.7.1"
+groups = ["socks"]
+
+[[package]]
+name = "pytest"
+version = "8.2.0"
+groups = ["test"]
+dependencies = [
+    "colorama; sys_platform == \\"win32\\"",
+]
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+groups = ["default", "socks"]
+dependencies = [
+    "urllib3<3,>=1.21.1",
+]
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+extras = ["socks"]
+groups = ["socks"]
+dependencies = [
+    "PySocks!=1.5.7,>=1.5.6",
+    "requests==2.31.0",
+]
+
+[[package]]
+name = "urllib3"
+version = "2.2.1"
+groups = ["default", "socks"]
+`
+
+const PYPROJECT_TOML = `[project]
+name = "app"
+dependencies = ["requests>=2.31"]
+
+[project.optional-dependencies]
+socks = ["requests[socks]"]
+
+[tool.pdm.dev-dependencies]
+test = ["pytest>=8"]
+`
+
+describe('parsePdmLockDependencies', () => {
+  it('should fold extras and read the project from pyproject.toml', () => {
+    const dependencies = parsePdmLockDependencies(
+      PDM_LOCK,
+      makeContext('/repo/pdm.lock', {
+        [path.resolve('/repo/pyproject.toml')]: PYPROJECT_TOML,
+      }),
+    )
+
+    expect(dependencies).toEqual([
+      {
+        id: 'colorama@0.4.6',
+        type: 'pypi',
+        name: 'colorama',
+        version: '0.4.6',
+        direct: false,
+        dev: true,
+        scopes: ['test'],
+        markers: ['sys_platform == "win32"'],
+        hashes: [{ alg: 'SHA-256', content: COLORAMA_SUM }],
+      },
+      {
+        id: 'pysocks@1.7.1',
+        type: 'pypi',
+        name: 'pysocks',
+        version: '1.7.1',
+        direct: false,
+        dev: false,
+        scopes: ['socks'],
+      },
+      {
+        id: 'pytest@8.2.0',
+        type: 'pypi',
+        name: 'pytest',
+        version: '8.2.0',
+        direct: true,
+        dev: true,
+        scopes: ['test'],
+        workspaces: ['app'],
+        dependencies: ['colorama@0.4.6'],
+      },
+      {
+        id: 'requests@2.31.0',
+        type: 'pypi',
+        name: 'requests',
+        version: '2.31.0',
+        direct: true,
+        dev: false,
+        scopes: ['default', 'socks'],
+        workspaces: ['app'],
+        dependencies: ['urllib3@2.2.1', 'pysocks@1.7.1'],
+      },
+      {
+        id: 'urllib3@2.2.1',
+        type: 'pypi',
+        name: 'urllib3',
+        version: '2.2.1',
+        direct: false,
+        dev: false,
+        scopes: ['default', 'socks'],
+      },
+    ])
+  })
+
+  it('should leave direct and dev unset without a pyproject.toml', () => {
+    const [colorama] = parsePdmLockDependencies(
+      PDM_LOCK,
+      makeContext('/repo/pdm.lock'),
+    )
+
+    expect(colorama).not.toHaveProperty('direct')
+    expect(colorama).not.toHaveProperty('dev')
+    expect(colorama!.scopes).toEqual(['test'])
+  })
+})
@@ -0,0 +1,258 @@
+/**
+ * Unit tests for the pnpm lockfile parser.
+ *
+ * Purpose: Tests parsing of pnpm-lock.yaml v9 workspaces into npm
+ * dependencies attributed to the workspace packages that pull them in.
+ *
+ * Test Coverage: - Importer walking through snapshots - catalog: and
+ * workspace: protocols - Peer dependency variants - Aliased dependencies - Dev
+ * detection - Integrity hashes - Lockfile version and local resolution
+ * detection.
+ *
+ * Related Files: - src/util/lockfile/pnpm.mts (implementation) -
+ * src/util/lockfile/npm.mts (shared npm helpers)
+ */
+
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  integrityToLockfileHashes,
+  splitNpmPackageKey,
+} from '../../../../src/util/lockfile/npm.mts'
+import {
+  getPnpmPackageKey,
+  pnpmLockfileParser,
+  resolvePnpmCatalogVersion,
+} from '../../../../src/util/lockfile/pnpm.mts'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const SHA512_HEX = 'cd'.repeat(64)
+
+const INTEGRITY = `sha512-${Buffer.from(SHA512_HEX, 'hex').toString('base64')}`
+
+const PNPM_LOCK = `lockfileVersion: '9.0'
+
+settings:
+  autoInstallPeers: true
+  excludeLinksFromLockfile: false
+
+catalogs:
+  default:
+    react:
+      specifier: ^18.2.0
+      version: 18.2.0
+
+importers:
+
+  .:
+    devDependencies:
+      typescript:
+        specifier: ^5.3.0
+        version: 5.3.3
+
+  packages/app:
+    dependencies:
+      '@acme/ui':
+        specifier: workspace:*
+        version: link:../ui
+      react:
+        specifier: 'catalog:'
+        version: 18.2.0
+      strip:
+        specifier: npm:strip-ansi@^6.0.1
+        version: strip-ansi@6.0.1
+
+  packages/ui:
+    dependencies:
+      react-dom:
+        specifier: ^18.2.0
+        version: 18.2.0(react@18.2.0)
+    devDependencies:
+      react:
+        specifier: 'catalog:'
+        version: 18.2.0
+
+packages:
+
+  js-tokens@4.0.0:
+    resolution: {integrity: ${INTEGRITY}}
+
+  loose-envify@1.4.0:
+    resolution: {integrity: ${INTEGRITY}}
+
+  react-dom@18.2.0:
+    resolution: {integrity: ${INTEGRITY}}
+    peerDependencies:
+      react: ^18.2.0
+
+  react@18.2.0:
+    resolution: {integrity: ${INTEGRITY}}
+
+  strip-ansi@6.0.1:
+    resolution: {integrity: ${INTEGRITY}}
+
+  typescript@5.3.3:
+    resolution: {integrity: ${INTEGRITY}}
+
+snapshots:
+
+  js-tokens@4.0.0: {}
+
+  loose-envify@1.4.0:
+    dependencies:
+      js-tokens: 4.0.0
+
+  react-dom@18.2.0(react@18.2.0):
+    dependencies:
+      loose-envify: 1.4.0
+      react: 18.2.0
+
+  react@18.2.0:
+    dependencies:
+      loose-envify: 1.4.0
+
+  strip-ansi@6.0.1: {}
+
+  typescript@5.3.3: {}
+`
+
+describe('pnpm lockfile parser', () => {
+  describe('npm helpers', () => {
+    it('should split scoped and unscoped package keys', () => {
+      expect(splitNpmPackageKey('@babel/core@7.23.0')).toEqual({
+        packageName: '@babel/core',
+        version: '7.23.0',
+      })
+      expect(splitNpmPackageKey('react@18.2.0')).toEqual({
+        packageName: 'react',
+        version: '18.2.0',
+      })
+      expect(splitNpmPackageKey('react')).toBeUndefined()
+    })
+
+    it('should convert integrity strings to hashes', () => {
+      expect(integrityToLockfileHashes(INTEGRITY)).toEqual([
+        { alg: 'SHA-512', content: SHA512_HEX },
+      ])
+      expect(integrityToLockfileHashes(undefined)).toEqual([])
+    })
+  })
+
+  describe('getPnpmPackageKey', () => {
+    it('should strip peer suffixes and follow aliases', () => {
+      expect(getPnpmPackageKey('react-dom', '18.2.0(react@18.2.0)')).toBe(
+        'react-dom@18.2.0',
+      )
+      expect(getPnpmPackageKey('strip', 'strip-ansi@6.0.1')).toBe(
+        'strip-ansi@6.0.1',
+      )
+      expect(getPnpmPackageKey('@acme/ui', 'link:../ui')).toBeUndefined()
+    })
+  })
+
+  describe('resolvePnpmCatalogVersion', () => {
+    it('should resolve default and named catalogs', () => {
+      const lockfile = {
+        catalogs: {
+          default: { react: { version: '18.2.0' } },
+          legacy: { react: { version: '17.0.2' } },
+        },
+      }
+
+      expect(resolvePnpmCatalogVersion(lockfile, 'react', 'catalog:')).toBe(
+        '18.2.0',
+      )
+      expect(
+        resolvePnpmCatalogVersion(lockfile, 'react', 'catalog:legacy'),
+      ).toBe('17.0.2')
+      expect(
+        resolvePnpmCatalogVersion(lockfile, 'react', '^18.2.0'),
+      ).toBeUndefined()
+    })
+  })
+
+  describe('pnpmLockfileParser', () => {
+    it('should attribute dependencies to the workspace packages', () => {
+      const parsed = pnpmLockfileParser.parse(
+        PNPM_LOCK,
+        makeContext('/repo/pnpm-lock.yaml', {
+          [path.resolve('/repo/package.json')]: '{"name":"monorepo"}',
+          [path.resolve('/repo/packages/app/package.json')]:
+            '{"name":"@acme/app"}',
+          [path.resolve('/repo/packages/ui/package.json')]:
+            '{"name":"@acme/ui"}',
+        }),
+      )!
+      const byName = Object.fromEntries(
+        parsed.dependencies.map(d => [d.name, d]),
+      )
+
+      expect(parsed.requiresLocalResolution).toBe(true)
+      expect(byName['react']).toEqual({
+        id: 'react@18.2.0',
+        type: 'npm',
+        name: 'react',
+        version: '18.2.0',
+        direct: true,
+        dev: false,
+        workspaces: ['@acme/app', '@acme/ui'],
+        hashes: [{ alg: 'SHA-512', content: SHA512_HEX }],
+        dependencies: ['loose-envify@1.4.0'],
+      })
+      expect(byName['react-dom']!.dependencies).toEqual([
+        'loose-envify@1.4.0',
+        'react@18.2.0',
+      ])
+      expect(byName['js-tokens']).toMatchObject({
+        direct: false,
+        dev: false,
+        workspaces: ['@acme/app', '@acme/ui'],
+      })
+      expect(byName['strip-ansi']).toMatchObject({
+        direct: true,
+        workspaces: ['@acme/app'],
+      })
+      expect(byName['typescript']).toMatchObject({
+        direct: true,
+        dev: true,
+        workspaces: ['monorepo'],
+      })
+    })
+
+    it('should leave single package lockfiles to the API', () => {
+      const parsed = pnpmLockfileParser.parse(
+        `lockfileVersion: '9.0'
+importers:
+  .:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.0
+packages:
+  react@18.2.0:
+    resolution: {integrity: ${INTEGRITY}}
+snapshots:
+  react@18.2.0: {}
+`,
+        makeContext('/repo/pnpm-lock.yaml'),
+      )!
+
+      expect(parsed.requiresLocalResolution).toBeUndefined()
+      expect(parsed.dependencies[0]).toMatchObject({
+        id: 'react@18.2.0',
+        workspaces: ['.'],
+      })
+    })
+
+    it('should skip lockfiles older than v9', () => {
+      expect(
+        pnpmLockfileParser.parse(
+          "lockfileVersion: '6.0'\npackages: {}\n",
+          makeContext('/repo/pnpm-lock.yaml'),
+        ),
+      ).toBeUndefined()
+    })
+  })
+})
@@ -0,0 +1,58 @@
+/**
+ * Unit tests for the shared Python lockfile support.
+ *
+ * Purpose: Tests parsing of PEP 508 requirements and of the requirements of
+ * a pyproject.toml.
+ *
+ * Test Coverage: - Names, extras, pins and markers of requirements -
+ * Requirements per resolution group and included dependency groups.
+ *
+ * Related Files: - src/util/lockfile/python.mts (implementation)
+ * - src/util/lockfile/pdm.mts, src/util/lockfile/uv.mts (parsers)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  parsePyprojectRequirements,
+  parsePythonRequirement,
+} from '../../../../src/util/lockfile/python.mts'
+
+describe('python lockfile support', () => {
+  describe('parsePythonRequirement', () => {
+    it('should read the name, extras, pin and marker', () => {
+      expect(
+        parsePythonRequirement(
+          'Requests[socks, security]==2.31.0; os_name == "nt"',
+        ),
+      ).toEqual({
+        extras: ['socks', 'security'],
+        marker: 'os_name == "nt"',
+        name: 'requests',
+        pinned: '2.31.0',
+      })
+      expect(parsePythonRequirement('urllib3<3,>=1.21.1')).toEqual({
+        extras: [],
+        name: 'urllib3',
+      })
+    })
+  })
+
+  describe('parsePyprojectRequirements', () => {
+    it('should collect requirements per group and included groups', () => {
+      const { devGroups, groups, name } = parsePyprojectRequirements(`[project]
+name = "app"
+dependencies = ["flask"]
+
+[dependency-groups]
+test = ["pytest"]
+dev = [{ include-group = "test" }, "ruff"]
+`)
+
+      expect(name).toBe('app')
+      expect([...devGroups]).toEqual(['test', 'dev'])
+      expect(groups.get('default')).toEqual(['flask'])
+      expect(groups.get('dev')).toEqual(['ruff', 'pytest'])
+    })
+  })
+})
@@ -0,0 +1,208 @@
+/**
+ * Unit tests for importing third-party SBOMs.
+ *
+ * Purpose: Tests that the components of CycloneDX and SPDX documents are read
+ * with their edges and rewritten as the CycloneDX SBOM of a full scan upload.
+ *
+ * Test Coverage: - CycloneDX nested components and subject - SPDX packages,
+ * relationships and checksums - Unsupported documents - Upload file and
+ * cleanup.
+ *
+ * Related Files: - src/util/lockfile/sbom-import.mts (implementation) -
+ * src/util/lockfile/sbom.mts (SBOM rendering)
+ */
+
+import { existsSync, readFileSync } from 'node:fs'
+
+import { describe, expect, it } from 'vitest'
+
+import { DOT_SOCKET_DOT_SBOM_CDX_JSON } from '../../../../src/constants/paths.mts'
+import {
+  parseSbomDocument,
+  prepareSbomForUpload,
+} from '../../../../src/util/lockfile/sbom-import.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+const CYCLONEDX_SBOM = JSON.stringify({
+  bomFormat: 'CycloneDX',
+  specVersion: '1.5',
+  metadata: {
+    component: { 'bom-ref': 'app', name: 'app', type: 'container' },
+  },
+  components: [
+    {
+      'bom-ref': 'express',
+      name: 'express',
+      purl: 'pkg:npm/express@4.18.2',
+      hashes: [{ alg: 'SHA-1', content: 'abc' }],
+      components: [
+        { 'bom-ref': 'qs', name: 'qs', purl: 'pkg:npm/qs@6.11.0' },
+      ],
+    },
+    { name: 'libc6', purl: 'pkg:deb/debian/libc6@2.36?arch=amd64' },
+    { name: 'busybox', type: 'file' },
+  ],
+  dependencies: [
+    { ref: 'app', dependsOn: ['express'] },
+    { ref: 'express', dependsOn: ['qs'] },
+  ],
+})
+
+describe('parseSbomDocument', () => {
+  it('reads nested CycloneDX components without the subject', () => {
+    const result = parseSbomDocument(CYCLONEDX_SBOM, 'sbom.cdx.json')
+
+    expect(result.ok).toBe(true)
+    const { format, lockfiles, skipped } = result.ok
+      ? result.data
+      : ({} as never)
+    expect(format).toBe('cyclonedx')
+    expect(skipped).toBe(1)
+    expect(lockfiles.map(l => [l.ecosystem, l.file])).toEqual([
+      ['npm', 'sbom.cdx.json'],
+      ['deb', 'sbom.cdx.json'],
+    ])
+    expect(lockfiles[0]!.dependencies).toEqual([
+      {
+        id: 'express',
+        type: 'npm',
+        name: 'express',
+        version: '4.18.2',
+        direct: true,
+        hashes: [{ alg: 'SHA-1', content: 'abc' }],
+        dependencies: ['qs'],
+      },
+      { id: 'qs', type: 'npm', name: 'qs', version: '6.11.0', direct: false },
+    ])
+    expect(lockfiles[1]!.dependencies[0]).toMatchObject({
+      namespace: 'debian',
+      qualifiers: { arch: 'amd64' },
+    })
+  })
+
+  it('reads SPDX packages through their purl external refs', () => {
+    const result = parseSbomDocument(
+      JSON.stringify({
+        spdxVersion: 'SPDX-2.3',
+        documentDescribes: ['SPDXRef-Root'],
+        packages: [
+          { SPDXID: 'SPDXRef-Root', name: 'app' },
+          {
+            SPDXID: 'SPDXRef-Requests',
+            name: 'requests',
+            checksums: [{ algorithm: 'SHA256', checksumValue: 'def' }],
+            externalRefs: [
+              {
+                referenceCategory: 'PACKAGE-MANAGER',
+                referenceType: 'purl',
+                referenceLocator: 'pkg:pypi/requests@2.31.0',
+              },
+            ],
+          },
+          { SPDXID: 'SPDXRef-File', name: 'README' },
+        ],
+        relationships: [
+          {
+            spdxElementId: 'SPDXRef-Requests',
+            relationshipType: 'DEPENDENCY_OF',
+            relatedSpdxElement: 'SPDXRef-Root',
+          },
+        ],
+      }),
+      'sbom.spdx.json',
+    )
+
+    expect(result).toEqual({
+      ok: true,
+      data: {
+        format: 'spdx',
+        lockfiles: [
+          {
+            ecosystem: 'pypi',
+            file: 'sbom.spdx.json',
+            dependencies: [
+              {
+                id: 'SPDXRef-Requests',
+                type: 'pypi',
+                name: 'requests',
+                version: '2.31.0',
+                direct: true,
+                hashes: [{ alg: 'SHA-256', content: 'def' }],
+              },
+            ],
+          },
+        ],
+        skipped: 1,
+      },
+    })
+  })
+
+  it('rejects documents that are not CycloneDX or SPDX JSON', () => {
+    expect(parseSbomDocument('{"openvex":true}', 'x.json')).toMatchObject({
+      ok: false,
+      message: 'Unsupported SBOM',
+    })
+    expect(parseSbomDocument('<bom/>', 'bom.xml')).toMatchObject({
+      ok: false,
+      message: 'Invalid SBOM',
+    })
+  })
+})
+
+describe('prepareSbomForUpload', () => {
+  it('writes the components as the upload SBOM and cleans it up', async () => {
+    const workspace = await createTestWorkspace({
+      files: [{ path: 'out/sbom.cdx.json', content: CYCLONEDX_SBOM }],
+    })
+    try {
+      const result = await prepareSbomForUpload('out/sbom.cdx.json', {
+        cwd: workspace.path,
+      })
+      const uploadPath = workspace.resolve(DOT_SOCKET_DOT_SBOM_CDX_JSON)
+
+      expect(result.ok).toBe(true)
+      const sbomScan = result.ok ? result.data : ({} as never)
+      expect(sbomScan.path).toBe(uploadPath)
+      expect(sbomScan.components).toBe(3)
+      const bom = JSON.parse(readFileSync(uploadPath, 'utf8'))
+      expect(bom.components.map((c: { purl: string }) => c.purl)).toEqual([
+        'pkg:npm/express@4.18.2',
+        'pkg:npm/qs@6.11.0',
+        'pkg:deb/debian/libc6@2.36?arch=amd64',
+      ])
+      expect(bom.components[0].properties).toContainEqual({
+        name: 'socket:manifest',
+        value: 'out/sbom.cdx.json',
+      })
+
+      await sbomScan.cleanup()
+      expect(existsSync(uploadPath)).toBe(false)
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('fails for a missing SBOM or one without scannable components', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'empty.cdx.json',
+          content: '{"bomFormat":"CycloneDX","components":[]}',
+        },
+      ],
+    })
+    try {
+      expect(
+        await prepareSbomForUpload('missing.json', { cwd: workspace.path }),
+      ).toMatchObject({ ok: false, message: 'SBOM not found' })
+      expect(
+        await prepareSbomForUpload('empty.cdx.json', { cwd: workspace.path }),
+      ).toMatchObject({ ok: false, message: 'No packages found' })
+      expect(existsSync(workspace.resolve(DOT_SOCKET_DOT_SBOM_CDX_JSON))).toBe(
+        false,
+      )
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+})
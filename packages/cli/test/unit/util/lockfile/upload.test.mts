@@ -0,0 +1,101 @@
+/**
+ * Unit tests for prepareLockfilesForUpload.
+ *
+ * Purpose: Tests that locally parsed lockfiles are swapped for a generated
+ * CycloneDX SBOM before a full scan upload.
+ *
+ * Test Coverage: - Pass-through of unrelated files - Conversion of lockfiles
+ * the API cannot ingest - Local resolution override - Cleanup.
+ *
+ * Related Files: - src/util/lockfile/upload.mts (implementation) -
+ * src/util/lockfile/sbom.mts (SBOM rendering)
+ */
+
+import { existsSync, readFileSync } from 'node:fs'
+
+import { describe, expect, it } from 'vitest'
+
+import { DOT_SOCKET_DOT_LOCKFILES_CDX_JSON } from '../../../../src/constants/paths.mts'
+import { prepareLockfilesForUpload } from '../../../../src/util/lockfile/upload.mts'
+import { createTestWorkspace } from '../../../helpers/workspace-helper.mts'
+
+describe('prepareLockfilesForUpload', () => {
+  it('should pass through files without a local parser', async () => {
+    const result = await prepareLockfilesForUpload(['/x/package.json'])
+
+    expect(result.paths).toEqual(['/x/package.json'])
+    expect(result.lockfiles).toEqual([])
+    await result.cleanup()
+  })
+
+  it('should keep API supported lockfiles that resolve remotely', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'go.mod',
+          content: 'module example.com/app\n\nrequire golang.org/x/net v0.17.0\n',
+        },
+      ],
+    })
+    try {
+      const goMod = workspace.resolve('go.mod')
+      const result = await prepareLockfilesForUpload([goMod], {
+        cwd: workspace.path,
+        isSupportedByApi: () => true,
+      })
+
+      expect(result.paths).toEqual([goMod])
+      expect(result.lockfiles).toEqual([])
+      await result.cleanup()
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+
+  it('should upload a generated SBOM for locally resolved lockfiles', async () => {
+    const workspace = await createTestWorkspace({
+      files: [
+        {
+          path: 'app/go.mod',
+          content:
+            'module example.com/app\n\nrequire example.com/lib v0.0.0\n\nreplace example.com/lib => ../lib\n',
+        },
+        {
+          path: 'lib/go.mod',
+          content:
+            'module example.com/lib\n\nrequire github.com/google/uuid v1.3.0\n',
+        },
+      ],
+    })
+    try {
+      const goMod = workspace.resolve('app/go.mod')
+      const result = await prepareLockfilesForUpload(
+        [workspace.resolve('package.json'), goMod],
+        { cwd: workspace.path, isSupportedByApi: () => true },
+      )
+      const sbomPath = workspace.resolve(DOT_SOCKET_DOT_LOCKFILES_CDX_JSON)
+
+      expect(result.paths).toEqual([
+        workspace.resolve('package.json'),
+        sbomPath,
+      ])
+      expect(result.lockfiles.map(l => l.file)).toEqual(['app/go.mod'])
+      const bom = JSON.parse(readFileSync(sbomPath, 'utf8'))
+      expect(bom.bomFormat).toBe('CycloneDX')
+      expect(bom.components).toEqual([
+        expect.objectContaining({
+          purl: 'pkg:golang/github.com/google/uuid@v1.3.0',
+          properties: expect.arrayContaining([
+            { name: 'socket:manifest', value: 'app/go.mod' },
+            { name: 'socket:workspace', value: 'example.com/lib' },
+          ]),
+        }),
+      ])
+
+      await result.cleanup()
+      expect(existsSync(sbomPath)).toBe(false)
+    } finally {
+      await workspace.cleanup()
+    }
+  })
+})
@@ -0,0 +1,166 @@
+/**
+ * Unit tests for the uv.lock parser.
+ *
+ * Purpose: Tests parsing of uv.lock into resolved PyPI dependencies.
+ *
+ * Test Coverage: - Direct dependencies of the project - Extras, dependency
+ * groups and dev detection - Environment and resolution markers - Git
+ * sources and sdist hashes.
+ *
+ * Related Files: - src/util/lockfile/uv.mts (implementation)
+ * - src/util/lockfile/python.mts (shared walk)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { parseUvLockDependencies } from '../../../../src/util/lockfile/uv.mts'
+
+const COLORAMA_SUM = 'b'.repeat(64)
+
+const UV_LOCK = `version = 1
+requires-python = ">=3.11"
+
+[[package]]
+name = "app"
+version = "0.1.0"
+source = { editable = "." }
+dependencies = [
+    { name = "colorama", marker = "sys_platform == 'win32'" },
+    { name = "pydantic", extra = ["email"] },
+]
+
+[package.optional-dependencies]
+cli = [
+    { name = "rich" },
+]
+
+[package.dev-dependencies]
+test = [
+    { name = "pytest" },
+]
+
+[[package]]
+name = "colorama"
+version = "0.4.6"
+source = { registry = "https://pypi.org/simple" }
+sdist = { url = "https://files.example/colorama-0.4.6.tar.gz", hash = "sha256:${COLORAMA_SUM}", size = 27697 }
+
+[[package]]
+name = "email-validator"
+version = "2.1.1"
+source = { registry = "https://pypi.org/simple" }
+
+[[package]]
+name = "pydantic"
+version = "2.7.0"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [
+    { name = "typing-extensions" },
+]
+
+[package.optional-dependencies]
+email = [
+    { name = "email-validator" },
+]
+
+[[package]]
+name = "pytest"
+version = "8.2.0"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [
+    { name = "colorama", marker = "sys_platform == 'win32'" },
+]
+
+[[package]]
+name = "rich"
+version = "13.7.1"
+source = { git = "https://github.com/Textualize/rich?rev=master#abc123" }
+
+[[package]]
+name = "typing-extensions"
+version = "4.11.0"
+source = { registry = "https://pypi.org/simple" }
+`
+
+describe('parseUvLockDependencies', () => {
+  it('should resolve groups, extras, markers and sources', () => {
+    expect(parseUvLockDependencies(UV_LOCK)).toEqual([
+      {
+        id: 'colorama@0.4.6',
+        type: 'pypi',
+        name: 'colorama',
+        version: '0.4.6',
+        direct: true,
+        dev: false,
+        scopes: ['default', 'test'],
+        markers: ["sys_platform == 'win32'"],
+        workspaces: ['app'],
+        hashes: [{ alg: 'SHA-256', content: COLORAMA_SUM }],
+      },
+      {
+        id: 'email-validator@2.1.1',
+        type: 'pypi',
+        name: 'email-validator',
+        version: '2.1.1',
+        direct: false,
+        dev: false,
+        scopes: ['default'],
+      },
+      {
+        id: 'pydantic@2.7.0',
+        type: 'pypi',
+        name: 'pydantic',
+        version: '2.7.0',
+        direct: true,
+        dev: false,
+        scopes: ['default'],
+        workspaces: ['app'],
+        dependencies: ['typing-extensions@4.11.0', 'email-validator@2.1.1'],
+      },
+      {
+        id: 'pytest@8.2.0',
+        type: 'pypi',
+        name: 'pytest',
+        version: '8.2.0',
+        direct: true,
+        dev: true,
+        scopes: ['test'],
+        workspaces: ['app'],
+        dependencies: ['colorama@0.4.6'],
+      },
+      {
+        id: 'rich@13.7.1 (git=https://github.com/Textualize/rich?rev=master#abc123)',
+        type: 'pypi',
+        name: 'rich',
+        version: '13.7.1',
+        qualifiers: {
+          vcs_url: 'git+https://github.com/Textualize/rich@abc123',
+        },
+        direct: true,
+        dev: false,
+        scopes: ['cli'],
+        workspaces: ['app'],
+      },
+      {
+        id: 'typing-extensions@4.11.0',
+        type: 'pypi',
+        name: 'typing-extensions',
+        version: '4.11.0',
+        direct: false,
+        dev: false,
+        scopes: ['default'],
+      },
+    ])
+  })
+
+  it('should keep the resolution markers of forked packages', () => {
+    const [numpy] = parseUvLockDependencies(`[[package]]
+name = "numpy"
+version = "1.26.4"
+source = { registry = "https://pypi.org/simple" }
+resolution-markers = ["python_full_version < '3.12'"]
+`)
+
+    expect(numpy!.markers).toEqual(["python_full_version < '3.12'"])
+  })
+})
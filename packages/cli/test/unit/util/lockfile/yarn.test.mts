@@ -0,0 +1,300 @@
+/**
+ * Unit tests for the Yarn Berry lockfile parser.
+ *
+ * Purpose: Tests parsing of Yarn Berry yarn.lock files into npm dependencies
+ * attributed to the workspaces that pull them in.
+ *
+ * Test Coverage: - Locators of the npm, patch, portal and workspace protocols
+ * - Root package.json resolutions - Patch files and their CycloneDX pedigree
+ * - Dev detection - Yarn v1 and local resolution detection.
+ *
+ * Related Files: - src/util/lockfile/yarn.mts (implementation) -
+ * src/util/lockfile/sbom.mts (SBOM rendering)
+ */
+
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import { lockfilesToCycloneDx } from '../../../../src/util/lockfile/sbom.mts'
+import {
+  getYarnResolution,
+  parseYarnLocator,
+  parseYarnResolutions,
+  yarnLockfileParser,
+} from '../../../../src/util/lockfile/yarn.mts'
+import { makeContext } from '../../../helpers/lockfile-context.mts'
+
+const PATCH_PATH = '.yarn/patches/lodash-npm-4.17.21-6382451519.patch'
+
+const PATCH_CONTENT = 'diff --git a/template.js b/template.js\n'
+
+const LODASH_PATCH_LOCATOR = `lodash@patch:lodash@npm%3A4.17.21#~/${PATCH_PATH}::version=4.17.21&hash=5d6ef0&locator=monorepo%40workspace%3A.`
+
+const PORTAL_LOCATOR =
+  'local-utils@portal:../local-utils::locator=%40acme%2Fui%40workspace%3Apackages%2Fui'
+
+const YARN_LOCK = `# This file is generated by running "yarn install" inside your project.
+# Manual changes might be lost - proceed with caution!
+
+__metadata:
+  version: 8
+  cacheKey: 10c0
+
+"@acme/app@workspace:packages/app":
+  version: 0.0.0-use.local
+  resolution: "@acme/app@workspace:packages/app"
+  dependencies:
+    "@acme/ui": "workspace:^"
+    left-pad: "npm:^1.3.0"
+    lodash: "npm:^4.17.21"
+    typescript: "npm:^5.3.0"
+  languageName: unknown
+  linkType: soft
+
+"@acme/ui@workspace:^, @acme/ui@workspace:packages/ui":
+  version: 0.0.0-use.local
+  resolution: "@acme/ui@workspace:packages/ui"
+  dependencies:
+    local-utils: "portal:../local-utils"
+    ms: "npm:^2.0.0"
+  languageName: unknown
+  linkType: soft
+
+"left-pad@npm:1.2.0":
+  version: 1.2.0
+  resolution: "left-pad@npm:1.2.0"
+  checksum: 10c0/aaaa
+  languageName: node
+  linkType: hard
+
+"local-utils@portal:../local-utils::locator=%40acme%2Fui%40workspace%3Apackages%2Fui":
+  version: 0.0.0-use.local
+  resolution: "${PORTAL_LOCATOR}"
+  dependencies:
+    ms: "npm:^2.1.0"
+  languageName: node
+  linkType: soft
+
+"lodash@npm:4.17.21":
+  version: 4.17.21
+  resolution: "lodash@npm:4.17.21"
+  checksum: 10c0/bbbb
+  languageName: node
+  linkType: hard
+
+"lodash@patch:lodash@npm%3A4.17.21#~/${PATCH_PATH}::locator=monorepo%40workspace%3A.":
+  version: 4.17.21
+  resolution: "${LODASH_PATCH_LOCATOR}"
+  checksum: 10c0/cccc
+  languageName: node
+  linkType: hard
+
+"monorepo@workspace:.":
+  version: 0.0.0-use.local
+  resolution: "monorepo@workspace:."
+  languageName: unknown
+  linkType: soft
+
+"ms@npm:^2.0.0, ms@npm:^2.1.0":
+  version: 2.1.3
+  resolution: "ms@npm:2.1.3"
+  checksum: 10c0/dddd
+  languageName: node
+  linkType: hard
+
+"typescript@npm:^5.3.0":
+  version: 5.3.3
+  resolution: "typescript@npm:5.3.3"
+  checksum: 10c0/eeee
+  languageName: node
+  linkType: hard
+`
+
+const FILES = {
+  [path.resolve('/repo/package.json')]: JSON.stringify({
+    name: 'monorepo',
+    workspaces: ['packages/*'],
+    resolutions: {
+      'left-pad': '1.2.0',
+      'lodash@npm:^4.17.21': `patch:lodash@npm%3A4.17.21#~/${PATCH_PATH}`,
+    },
+  }),
+  [path.resolve('/repo/packages/app/package.json')]: JSON.stringify({
+    name: '@acme/app',
+    dependencies: {
+      '@acme/ui': 'workspace:^',
+      'left-pad': '^1.3.0',
+      lodash: '^4.17.21',
+    },
+    devDependencies: { typescript: '^5.3.0' },
+  }),
+  [path.resolve('/repo', PATCH_PATH)]: PATCH_CONTENT,
+}
+
+describe('yarn lockfile parser', () => {
+  describe('parseYarnLocator', () => {
+    it('should read patch locators as the package they patch', () => {
+      expect(parseYarnLocator(LODASH_PATCH_LOCATOR)).toEqual({
+        packageName: 'lodash',
+        protocol: 'npm',
+        version: '4.17.21',
+        patches: [PATCH_PATH],
+      })
+      expect(
+        parseYarnLocator(
+          'typescript@patch:typescript@npm%3A5.3.3#optional!builtin<compat/typescript>::version=5.3.3&hash=e012d7',
+        ),
+      ).toEqual({
+        packageName: 'typescript',
+        protocol: 'npm',
+        version: '5.3.3',
+      })
+    })
+
+    it('should resolve local paths from the declaring workspace', () => {
+      expect(parseYarnLocator(PORTAL_LOCATOR)).toEqual({
+        packageName: 'local-utils',
+        protocol: 'portal',
+        path: 'local-utils',
+      })
+      expect(parseYarnLocator('@acme/ui@workspace:packages/ui')).toEqual({
+        packageName: '@acme/ui',
+        protocol: 'workspace',
+        path: 'packages/ui',
+      })
+      expect(parseYarnLocator('lodash')).toBeUndefined()
+    })
+  })
+
+  describe('getYarnResolution', () => {
+    it('should prefer parent scoped over ranged over name-only entries', () => {
+      const resolutions = parseYarnResolutions({
+        '**/debug': '4.3.4',
+        'debug@^2.6.9': '2.6.9',
+        'express/debug': 'npm:4.3.1',
+        'react-dom/@types/react': '18.2.0',
+      })
+
+      expect(resolutions[3]).toEqual({
+        parentName: 'react-dom',
+        name: '@types/react',
+        value: '18.2.0',
+      })
+      expect(getYarnResolution(resolutions, 'express', 'debug', '^2.6.9')).toBe(
+        'npm:4.3.1',
+      )
+      expect(getYarnResolution(resolutions, 'send', 'debug', '^2.6.9')).toBe(
+        '2.6.9',
+      )
+      expect(getYarnResolution(resolutions, 'send', 'debug', '^3.0.0')).toBe(
+        '4.3.4',
+      )
+      expect(getYarnResolution(resolutions, 'send', 'ms', '^2.0.0')).toBe(
+        undefined,
+      )
+    })
+  })
+
+  describe('yarnLockfileParser', () => {
+    it('should attribute dependencies to workspaces via resolutions', () => {
+      const parsed = yarnLockfileParser.parse(
+        YARN_LOCK,
+        makeContext('/repo/yarn.lock', FILES),
+      )!
+
+      expect(parsed.requiresLocalResolution).toBe(true)
+      expect(parsed.dependencies).toEqual([
+        {
+          id: 'left-pad@npm:1.2.0',
+          type: 'npm',
+          name: 'left-pad',
+          version: '1.2.0',
+          direct: true,
+          dev: false,
+          workspaces: ['@acme/app'],
+        },
+        {
+          id: LODASH_PATCH_LOCATOR,
+          type: 'npm',
+          name: 'lodash',
+          version: '4.17.21',
+          direct: true,
+          dev: false,
+          workspaces: ['@acme/app'],
+          patches: [{ path: PATCH_PATH, content: PATCH_CONTENT }],
+        },
+        {
+          id: 'ms@npm:2.1.3',
+          type: 'npm',
+          name: 'ms',
+          version: '2.1.3',
+          direct: true,
+          dev: false,
+          workspaces: ['@acme/ui'],
+        },
+        {
+          id: 'typescript@npm:5.3.3',
+          type: 'npm',
+          name: 'typescript',
+          version: '5.3.3',
+          direct: true,
+          dev: true,
+          workspaces: ['@acme/app'],
+        },
+      ])
+    })
+
+    it('should attach patches to the CycloneDX component', () => {
+      const parsed = yarnLockfileParser.parse(
+        YARN_LOCK,
+        makeContext('/repo/yarn.lock', FILES),
+      )!
+      const bom = lockfilesToCycloneDx([{ ...parsed, file: 'yarn.lock' }])
+      const lodash = bom.components.find(c => c.name === 'lodash')!
+
+      expect(lodash.purl).toBe('pkg:npm/lodash@4.17.21')
+      expect(lodash.properties).toContainEqual({
+        name: 'socket:patched',
+        value: 'true',
+      })
+      expect(lodash.pedigree).toEqual({
+        patches: [
+          {
+            type: 'unofficial',
+            diff: {
+              url: PATCH_PATH,
+              text: { contentType: 'text/plain', content: PATCH_CONTENT },
+            },
+          },
+        ],
+      })
+    })
+
+    it('should leave plain and Yarn v1 lockfiles to the Socket API', () => {
+      const parsed = yarnLockfileParser.parse(
+        `__metadata:
+  version: 8
+
+"app@workspace:.":
+  resolution: "app@workspace:."
+  dependencies:
+    ms: "npm:^2.0.0"
+
+"ms@npm:^2.0.0":
+  resolution: "ms@npm:2.1.3"
+`,
+        makeContext('/repo/yarn.lock'),
+      )!
+
+      expect(parsed.requiresLocalResolution).toBeUndefined()
+      expect(parsed.dependencies.map(d => d.id)).toEqual(['ms@npm:2.1.3'])
+      expect(
+        yarnLockfileParser.parse(
+          'ms@^2.0.0:\n  version "2.1.3"\n',
+          makeContext('/repo/yarn.lock'),
+        ),
+      ).toBeUndefined()
+    })
+  })
+})
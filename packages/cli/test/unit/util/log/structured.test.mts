@@ -0,0 +1,237 @@
+/**
+ * Unit tests for the structured log of --log-level, --log-format and
+ * --log-file.
+ *
+ * Purpose: Tests the log settings, the record formats and where records are
+ * written.
+ *
+ * Test Coverage: - Flags over SOCKET_CLI_LOG_* env vars - Rejecting unknown
+ * levels and formats - Level filtering - Text and JSON records with the
+ * trace ID - API request IDs from response headers.
+ *
+ * Related Files: - src/util/log/structured.mts (implementation)
+ * - src/util/socket/sdk.mts (API response records)
+ */
+
+import { mkdtempSync, readFileSync, rmSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  configureLogging,
+  formatLogRecord,
+  getApiRequestId,
+  getTraceId,
+  isLogLevelEnabled,
+  logApiResponse,
+  logEvent,
+  resetLoggingForTesting,
+} from '../../../../src/util/log/structured.mts'
+
+const ENV_KEYS = [
+  'SOCKET_CLI_LOG_FILE',
+  'SOCKET_CLI_LOG_FORMAT',
+  'SOCKET_CLI_LOG_LEVEL',
+]
+
+describe('structured log', () => {
+  const savedEnv = { ...process.env }
+  let stderrWrite: ReturnType<typeof vi.spyOn>
+
+  beforeEach(() => {
+    resetLoggingForTesting()
+    for (const key of ENV_KEYS) {
+      delete process.env[key]
+    }
+    stderrWrite = vi
+      .spyOn(process.stderr, 'write')
+      .mockImplementation(() => true)
+  })
+
+  afterEach(() => {
+    stderrWrite.mockRestore()
+    for (const key of ENV_KEYS) {
+      if (savedEnv[key] === undefined) {
+        delete process.env[key]
+      } else {
+        process.env[key] = savedEnv[key]
+      }
+    }
+  })
+
+  function writtenRecords(): unknown[] {
+    return stderrWrite.mock.calls.map(call => JSON.parse(String(call[0])))
+  }
+
+  describe('configureLogging', () => {
+    it('leaves the log off without settings', () => {
+      expect(configureLogging({})).toEqual({ ok: true, data: undefined })
+      expect(isLogLevelEnabled('error')).toBe(false)
+
+      logEvent('error', 'dropped')
+
+      expect(stderrWrite).not.toHaveBeenCalled()
+    })
+
+    it('logs at the info level when only a format is given', () => {
+      configureLogging({ logFormat: 'json' })
+
+      expect(isLogLevelEnabled('info')).toBe(true)
+      expect(isLogLevelEnabled('debug')).toBe(false)
+    })
+
+    it('prefers flags over env vars', () => {
+      process.env['SOCKET_CLI_LOG_LEVEL'] = 'error'
+
+      configureLogging({ logLevel: 'trace' })
+
+      expect(isLogLevelEnabled('trace')).toBe(true)
+    })
+
+    it('reads env vars', () => {
+      process.env['SOCKET_CLI_LOG_LEVEL'] = 'warn'
+
+      configureLogging({})
+
+      expect(isLogLevelEnabled('warn')).toBe(true)
+      expect(isLogLevelEnabled('info')).toBe(false)
+    })
+
+    it('rejects unknown levels', () => {
+      configureLogging({ logLevel: 'debug' })
+
+      expect(configureLogging({ logLevel: 'loud' })).toEqual({
+        ok: false,
+        message: 'Invalid --log-level value',
+        cause: 'Expected one of error, warn, info, debug, trace (saw: loud)',
+      })
+      expect(isLogLevelEnabled('error')).toBe(false)
+    })
+
+    it('names the env var of an unknown format', () => {
+      process.env['SOCKET_CLI_LOG_FORMAT'] = 'xml'
+
+      const result = configureLogging({})
+
+      expect(!result.ok && result.message).toBe(
+        'Invalid SOCKET_CLI_LOG_FORMAT value',
+      )
+    })
+  })
+
+  describe('logEvent', () => {
+    it('writes JSON records with the trace ID', () => {
+      configureLogging({ logFormat: 'json', logLevel: 'debug' })
+
+      logEvent('debug', 'api request', { method: 'GET', url: undefined })
+      logEvent('trace', 'dropped')
+
+      expect(writtenRecords()).toEqual([
+        {
+          time: expect.any(String),
+          level: 'debug',
+          msg: 'api request',
+          traceId: getTraceId(),
+          method: 'GET',
+        },
+      ])
+    })
+
+    it('appends to the log file', () => {
+      const tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-log-'))
+      const logFile = path.join(tmpDir, 'socket.log')
+      try {
+        configureLogging({ logFile })
+
+        logEvent('info', 'first')
+        logEvent('warn', 'second')
+
+        const lines = readFileSync(logFile, 'utf8').trimEnd().split('\n')
+        expect(lines).toHaveLength(2)
+        expect(lines[1]).toContain(' WARN  second traceId=')
+        expect(stderrWrite).not.toHaveBeenCalled()
+      } finally {
+        rmSync(tmpDir, { force: true, recursive: true })
+      }
+    })
+  })
+
+  describe('logApiResponse', () => {
+    it('logs failed responses as warnings with the request ID', () => {
+      configureLogging({ logFormat: 'json', logLevel: 'warn' })
+
+      logApiResponse({
+        durationMs: 12,
+        headers: { 'X-Request-Id': 'req-1' },
+        method: 'GET',
+        status: 404,
+        url: 'https://api.socket.dev/v0/orgs',
+      })
+      logApiResponse({ method: 'GET', status: 200, url: 'https://a.b/' })
+
+      expect(writtenRecords()).toEqual([
+        expect.objectContaining({
+          durationMs: 12,
+          level: 'warn',
+          msg: 'api response',
+          requestId: 'req-1',
+          status: 404,
+        }),
+      ])
+    })
+
+    it('logs network errors as errors', () => {
+      configureLogging({ logFormat: 'json', logLevel: 'error' })
+
+      logApiResponse({
+        error: new Error('socket hang up'),
+        url: 'https://a.b/',
+      })
+
+      expect(writtenRecords()).toEqual([
+        expect.objectContaining({
+          error: 'socket hang up',
+          level: 'error',
+          msg: 'api request failed',
+        }),
+      ])
+    })
+  })
+})
+
+describe('formatLogRecord', () => {
+  const record = {
+    time: '2026-01-01T00:00:00.000Z',
+    level: 'info' as const,
+    msg: 'api response',
+    traceId: 'trace-1',
+    status: 200,
+    url: 'https://api.socket.dev/v0/a b',
+  }
+
+  it('formats text records as key=value pairs', () => {
+    expect(formatLogRecord(record, 'text')).toBe(
+      '2026-01-01T00:00:00.000Z INFO  api response traceId=trace-1 status=200 url="https://api.socket.dev/v0/a b"',
+    )
+  })
+
+  it('formats JSON records on one line', () => {
+    expect(JSON.parse(formatLogRecord(record, 'json'))).toEqual(record)
+  })
+})
+
+describe('getApiRequestId', () => {
+  it('prefers the request ID over the Cloudflare ray ID', () => {
+    expect(
+      getApiRequestId({ 'cf-ray': 'ray-1', 'x-request-id': 'req-1' }),
+    ).toBe('req-1')
+    expect(getApiRequestId({ 'CF-Ray': 'ray-1' })).toBe('ray-1')
+  })
+
+  it('is undefined without headers', () => {
+    expect(getApiRequestId(undefined)).toBeUndefined()
+    expect(getApiRequestId({ 'content-type': 'text/plain' })).toBeUndefined()
+  })
+})
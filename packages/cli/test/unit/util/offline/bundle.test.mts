@@ -0,0 +1,200 @@
+/**
+ * Unit tests for the signed offline bundles of `socket bundle export` and
+ * `socket bundle import`.
+ *
+ * Related Files: - src/util/offline/bundle.mts (implementation) -
+ * src/util/offline/store.mts (consumer)
+ */
+
+import { createPublicKey, generateKeyPairSync } from 'node:crypto'
+import { mkdtempSync, rmSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it } from 'vitest'
+
+import {
+  OFFLINE_BUNDLE_PAYLOAD_TYPE,
+  OFFLINE_BUNDLE_VERSION,
+  getBundleKeyId,
+  readOfflineBundleArchive,
+  signOfflineBundle,
+  verifyOfflineBundle,
+  writeOfflineBundleArchive,
+} from '../../../../src/util/offline/bundle.mts'
+
+import type { OfflineBundle } from '../../../../src/util/offline/bundle.mts'
+
+function generatePemKeyPair(type: 'ec' | 'ed25519' = 'ed25519') {
+  const { privateKey, publicKey } =
+    type === 'ec'
+      ? generateKeyPairSync('ec', { namedCurve: 'P-256' })
+      : generateKeyPairSync('ed25519')
+  return {
+    privateKey: privateKey.export({ format: 'pem', type: 'pkcs8' }).toString(),
+    publicKey: publicKey.export({ format: 'pem', type: 'spki' }).toString(),
+  }
+}
+
+const bundle: OfflineBundle = {
+  createdAt: '2026-01-01T00:00:00.000Z',
+  orgSlug: 'acme',
+  scans: [{ scan: [], scanId: 'scan-1' }],
+  scoreCache: [],
+  securityPolicy: {
+    securityPolicyDefault: 'medium',
+    securityPolicyRules: {},
+  } as unknown as OfflineBundle['securityPolicy'],
+  version: OFFLINE_BUNDLE_VERSION,
+}
+
+describe('signOfflineBundle', () => {
+  it('signs a bundle that verifies with the public key', () => {
+    const { privateKey, publicKey } = generatePemKeyPair()
+
+    const signed = signOfflineBundle(bundle, privateKey)
+    expect(signed.ok).toBe(true)
+    if (!signed.ok) {
+      return
+    }
+    expect(signed.data.payloadType).toBe(OFFLINE_BUNDLE_PAYLOAD_TYPE)
+
+    expect(verifyOfflineBundle(signed.data, publicKey)).toEqual({
+      ok: true,
+      data: bundle,
+    })
+  })
+
+  it('signs with ECDSA keys', () => {
+    const { privateKey, publicKey } = generatePemKeyPair('ec')
+
+    const signed = signOfflineBundle(bundle, privateKey)
+    expect(signed.ok).toBe(true)
+    if (!signed.ok) {
+      return
+    }
+    expect(verifyOfflineBundle(signed.data, publicKey).ok).toBe(true)
+  })
+
+  it('records the key id of the public key', () => {
+    const { privateKey, publicKey } = generatePemKeyPair()
+
+    const signed = signOfflineBundle(bundle, privateKey)
+
+    expect(signed.ok && signed.data.signatures[0]?.keyid).toBe(
+      getBundleKeyId(createPublicKey(publicKey)),
+    )
+  })
+
+  it('rejects a key that is not PEM', () => {
+    const signed = signOfflineBundle(bundle, 'not a key')
+
+    expect(signed.ok).toBe(false)
+    expect(!signed.ok && signed.message).toBe('Invalid signing key')
+  })
+})
+
+describe('verifyOfflineBundle', () => {
+  it('rejects a changed payload', () => {
+    const { privateKey, publicKey } = generatePemKeyPair()
+    const signed = signOfflineBundle(bundle, privateKey)
+    if (!signed.ok) {
+      throw new Error('signing failed')
+    }
+    const tampered = {
+      ...signed.data,
+      payload: Buffer.from(
+        JSON.stringify({ ...bundle, orgSlug: 'evil' }),
+      ).toString('base64'),
+    }
+
+    const verified = verifyOfflineBundle(tampered, publicKey)
+
+    expect(verified.ok).toBe(false)
+    expect(!verified.ok && verified.message).toBe('Bundle signature mismatch')
+  })
+
+  it('rejects a bundle signed with another key', () => {
+    const { privateKey } = generatePemKeyPair()
+    const { publicKey } = generatePemKeyPair()
+    const signed = signOfflineBundle(bundle, privateKey)
+    if (!signed.ok) {
+      throw new Error('signing failed')
+    }
+
+    const verified = verifyOfflineBundle(signed.data, publicKey)
+
+    expect(verified.ok).toBe(false)
+    expect(!verified.ok && verified.message).toBe('Bundle signature mismatch')
+  })
+
+  it('rejects other payload types', () => {
+    const { privateKey, publicKey } = generatePemKeyPair()
+    const signed = signOfflineBundle(bundle, privateKey)
+    if (!signed.ok) {
+      throw new Error('signing failed')
+    }
+
+    const verified = verifyOfflineBundle(
+      { ...signed.data, payloadType: 'application/vnd.in-toto+json' },
+      publicKey,
+    )
+
+    expect(verified.ok).toBe(false)
+    expect(!verified.ok && verified.message).toBe('Not an offline bundle')
+  })
+
+  it('rejects bundles of another version', () => {
+    const { privateKey, publicKey } = generatePemKeyPair()
+    const signed = signOfflineBundle(
+      { ...bundle, version: 2 as typeof OFFLINE_BUNDLE_VERSION },
+      privateKey,
+    )
+    if (!signed.ok) {
+      throw new Error('signing failed')
+    }
+
+    const verified = verifyOfflineBundle(signed.data, publicKey)
+
+    expect(verified.ok).toBe(false)
+    expect(!verified.ok && verified.message).toBe('Unsupported offline bundle')
+  })
+})
+
+describe('offline bundle archives', () => {
+  let tmpDir: string
+
+  beforeEach(() => {
+    tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-offline-bundle-'))
+  })
+
+  afterEach(() => {
+    rmSync(tmpDir, { force: true, recursive: true })
+  })
+
+  it('reads back the envelope it wrote', async () => {
+    const { privateKey } = generatePemKeyPair()
+    const signed = signOfflineBundle(bundle, privateKey)
+    if (!signed.ok) {
+      throw new Error('signing failed')
+    }
+    const filepath = path.join(tmpDir, 'socket-bundle.json.gz')
+
+    await writeOfflineBundleArchive(filepath, signed.data)
+
+    expect(await readOfflineBundleArchive(filepath)).toEqual({
+      ok: true,
+      data: signed.data,
+    })
+  })
+
+  it('fails on a file that is not gzipped', async () => {
+    const filepath = path.join(tmpDir, 'socket-bundle.json.gz')
+    writeFileSync(filepath, '{}')
+
+    const result = await readOfflineBundleArchive(filepath)
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.message).toBe('Unable to read offline bundle')
+  })
+})
@@ -2,7 +2,8 @@
  * Unit tests for ambient machine-output mode tracking.
  *
  * Module-scoped let updated by meow at argv-parse time. Tests verify
- * set/get/reset and the delegation to isMachineOutputMode.
+ * set/get/reset, the delegation to isMachineOutputMode and the separate
+ * --quiet state.
  *
  * Related Files: - src/util/output/ambient-mode.mts.
  */
@@ -11,6 +12,7 @@ import { afterEach, beforeEach, describe, expect, it } from 'vitest'
 
 import {
   getMachineOutputMode,
+  getQuietOutputMode,
   resetMachineOutputMode,
   setMachineOutputMode,
 } from '../../../../src/util/output/ambient-mode.mts'
@@ -61,4 +63,13 @@ describe('ambient-mode', () => {
     setMachineOutputMode({})
     expect(getMachineOutputMode()).toBe(false)
   })
+
+  it('tracks --quiet apart from the other machine modes', () => {
+    setMachineOutputMode({ json: true })
+    expect(getQuietOutputMode()).toBe(false)
+    setMachineOutputMode({ quiet: true })
+    expect(getQuietOutputMode()).toBe(true)
+    resetMachineOutputMode()
+    expect(getQuietOutputMode()).toBe(false)
+  })
 })
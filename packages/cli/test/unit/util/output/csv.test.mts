@@ -0,0 +1,76 @@
+/**
+ * Unit tests for CSV serialization.
+ *
+ * Related Files: - src/util/output/csv.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  escapeCsvField,
+  formatCsv,
+  formatCsvRecords,
+  getCsvColumns,
+  getUnknownCsvColumns,
+} from '../../../../src/util/output/csv.mts'
+
+describe('escapeCsvField', () => {
+  it('leaves plain values alone', () => {
+    expect(escapeCsvField('MIT OR Apache-2.0')).toBe('MIT OR Apache-2.0')
+  })
+
+  it('quotes values with commas, quotes or line breaks', () => {
+    expect(escapeCsvField('a,b')).toBe('"a,b"')
+    expect(escapeCsvField('say "hi"')).toBe('"say ""hi"""')
+    expect(escapeCsvField('a\nb')).toBe('"a\nb"')
+  })
+})
+
+describe('formatCsv', () => {
+  it('prints the header and one line per row', () => {
+    expect(
+      formatCsv(
+        ['purl', 'license'],
+        [
+          ['pkg:npm/a@1.0.0', 'MIT'],
+          ['pkg:npm/b@1.0.0', ''],
+        ],
+      ),
+    ).toBe('purl,license\npkg:npm/a@1.0.0,MIT\npkg:npm/b@1.0.0,')
+  })
+  it('leaves out the header row', () => {
+    expect(formatCsv(['purl'], [['pkg:npm/a@1.0.0']], { header: false })).toBe(
+      'pkg:npm/a@1.0.0',
+    )
+  })
+})
+
+describe('formatCsvRecords', () => {
+  it('prints the columns of each record in order', () => {
+    expect(
+      formatCsvRecords(
+        ['license', 'purl'],
+        [{ license: 'MIT', purl: 'pkg:npm/a@1.0.0' }],
+      ),
+    ).toBe('license,purl\nMIT,pkg:npm/a@1.0.0')
+  })
+})
+
+describe('getCsvColumns', () => {
+  it('splits the --columns value', () => {
+    expect(getCsvColumns('package, version', ['purl'])).toEqual([
+      'package',
+      'version',
+    ])
+  })
+
+  it('falls back to the default columns', () => {
+    expect(getCsvColumns('', ['purl', 'license'])).toEqual(['purl', 'license'])
+  })
+
+  it('lists the columns that are not available', () => {
+    expect(getUnknownCsvColumns(['purl', 'score'], ['purl'])).toEqual([
+      'score',
+    ])
+  })
+})
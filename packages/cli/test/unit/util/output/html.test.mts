@@ -0,0 +1,75 @@
+/**
+ * Unit tests for HTML report helpers.
+ *
+ * Purpose: Tests escaping and the document shell of self-contained HTML
+ * reports.
+ *
+ * Test Coverage: - Text and attribute escaping - Inline JSON that cannot
+ * close its script element - Document structure.
+ *
+ * Related Files: - util/output/html.mts (implementation).
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  escapeHtml,
+  renderHtmlDocument,
+  toScriptJson,
+} from '../../../../src/util/output/html.mts'
+
+describe('escapeHtml', () => {
+  it('escapes markup characters', () => {
+    expect(escapeHtml(`<a href="x">Tom & Jerry's</a>`)).toBe(
+      '&lt;a href=&quot;x&quot;&gt;Tom &amp; Jerry&#39;s&lt;/a&gt;',
+    )
+  })
+
+  it('stringifies numbers and booleans', () => {
+    expect(escapeHtml(42)).toBe('42')
+    expect(escapeHtml(false)).toBe('false')
+  })
+})
+
+describe('toScriptJson', () => {
+  it('cannot close the script element', () => {
+    const json = toScriptJson({ name: '</script><script>alert(1)</script>' })
+
+    expect(json).not.toContain('</script>')
+    expect(JSON.parse(json)).toEqual({
+      name: '</script><script>alert(1)</script>',
+    })
+  })
+
+  it('escapes line and paragraph separators', () => {
+    const json = toScriptJson('a\u2028b\u2029c')
+
+    expect(json).toBe('"a\\u2028b\\u2029c"')
+    expect(JSON.parse(json)).toBe('a\u2028b\u2029c')
+  })
+})
+
+describe('renderHtmlDocument', () => {
+  it('renders a complete document', () => {
+    const html = renderHtmlDocument({
+      body: '<p>Hi</p>',
+      script: 'void 0',
+      styles: 'p{}',
+      title: 'A & B',
+    })
+
+    expect(html).toMatch(/^<!DOCTYPE html>\n<html lang="en">/)
+    expect(html).toContain('<meta charset="utf-8">')
+    expect(html).toContain('<title>A &amp; B</title>')
+    expect(html).toContain('<style>p{}</style>')
+    expect(html).toContain('<body>\n<p>Hi</p>\n<script>void 0</script>')
+    expect(html.endsWith('</html>\n')).toBe(true)
+  })
+
+  it('leaves out empty styles and scripts', () => {
+    const html = renderHtmlDocument({ body: '', title: 'T' })
+
+    expect(html).not.toContain('<style>')
+    expect(html).not.toContain('<script>')
+  })
+})
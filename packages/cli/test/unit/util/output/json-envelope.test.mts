@@ -0,0 +1,110 @@
+/**
+ * Unit tests for the versioned --json envelope.
+ *
+ * Purpose: Tests the envelope wrapping and the published JSON Schemas.
+ *
+ * Test Coverage: - Envelope only while a command runs - Command naming -
+ * Schema lookup by path or key - Published data schemas - Unconstrained data
+ * for API passthrough commands - Unknown commands.
+ *
+ * Related Files: - util/output/json-envelope.mts (implementation) -
+ * data/command-json-schemas.json - Data schemas.
+ */
+
+import { afterEach, describe, expect, it } from 'vitest'
+
+import {
+  JSON_OUTPUT_VERSION,
+  getJsonOutputCommand,
+  getJsonOutputCommands,
+  getJsonOutputSchema,
+  resetJsonOutputCommand,
+  setJsonOutputCommand,
+  toJsonEnvelope,
+} from '../../../../src/util/output/json-envelope.mts'
+
+describe('json-envelope', () => {
+  afterEach(() => {
+    resetJsonOutputCommand()
+  })
+
+  describe('toJsonEnvelope', () => {
+    it('returns results unchanged outside a command run', () => {
+      const result = { ok: true, data: 1 }
+
+      expect(toJsonEnvelope(result)).toBe(result)
+    })
+
+    it('leads with the version and command', () => {
+      setJsonOutputCommand('socket organization policy security')
+
+      const envelope = toJsonEnvelope({ ok: false, message: 'Nope' })
+
+      expect(getJsonOutputCommand()).toBe('organization policy security')
+      expect(Object.keys(envelope)).toEqual([
+        'version',
+        'command',
+        'ok',
+        'message',
+      ])
+      expect(envelope).toEqual({
+        version: JSON_OUTPUT_VERSION,
+        command: 'organization policy security',
+        ok: false,
+        message: 'Nope',
+      })
+    })
+  })
+
+  describe('getJsonOutputSchema', () => {
+    it('accepts command paths and requirement keys', () => {
+      expect(getJsonOutputSchema('scan check')).toEqual(
+        getJsonOutputSchema('scan:check'),
+      )
+      expect(getJsonOutputSchema('socket scan check')).toEqual(
+        getJsonOutputSchema('scan:check'),
+      )
+    })
+
+    it('describes the envelope and the published data schema', () => {
+      const schema = getJsonOutputSchema('suppressions list')!
+
+      expect(schema).toMatchObject({
+        $schema: 'https://json-schema.org/draft/2020-12/schema',
+        title: 'socket suppressions list --json',
+        required: ['version', 'command', 'ok'],
+        properties: {
+          version: { const: JSON_OUTPUT_VERSION },
+          command: { const: 'suppressions list' },
+          data: {
+            type: 'object',
+            required: ['path', 'suppressions'],
+          },
+        },
+      })
+    })
+
+    it('leaves data unconstrained for API passthrough commands', () => {
+      const schema = getJsonOutputSchema('scan view')!
+
+      expect(
+        (schema['properties'] as Record<string, unknown>)['data'],
+      ).toEqual({ description: 'The Socket API response, unchanged' })
+    })
+
+    it('returns undefined for commands without JSON output', () => {
+      expect(getJsonOutputSchema('login')).toBeUndefined()
+      expect(getJsonOutputSchema('nope')).toBeUndefined()
+    })
+  })
+
+  describe('getJsonOutputCommands', () => {
+    it('lists command paths', () => {
+      const commands = getJsonOutputCommands()
+
+      expect(commands).toContain('scan report')
+      expect(commands).toContain('whoami')
+      expect(commands).not.toContain('scan:report')
+    })
+  })
+})
@@ -0,0 +1,80 @@
+/**
+ * Unit tests for JUnit XML rendering.
+ *
+ * Purpose: Tests the JUnit report shape read by CI test reporters.
+ *
+ * Test Coverage: - Suite counts - Failures and system output - Placeholder
+ * case for empty suites - Timestamp format.
+ *
+ * Related Files: - util/output/junit.mts (implementation) -
+ * util/output/xml.mts - XML writer.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { renderJunitReport } from '../../../../src/util/output/junit.mts'
+
+const TIMESTAMP = new Date('2024-05-06T07:08:09.123Z')
+
+describe('renderJunitReport', () => {
+  it('counts tests and failures', () => {
+    const xml = renderJunitReport({
+      emptyCaseName: 'Nothing found',
+      name: 'Suite',
+      testCases: [
+        {
+          classname: 'package.json',
+          failure: { details: 'line 1\nline 2', message: 'bad', type: 'x' },
+          name: 'first',
+        },
+        { classname: 'package.json', name: 'second', systemOut: 'fyi' },
+      ],
+      timestamp: TIMESTAMP,
+    })
+
+    expect(xml).toMatch(/^<\?xml version="1.0" encoding="UTF-8"\?>\n/)
+    expect(xml).toContain(
+      '<testsuites name="Suite" tests="2" failures="1" errors="0" time="0">',
+    )
+    expect(xml).toContain(
+      '<testsuite name="Suite" tests="2" failures="1" errors="0" skipped="0" time="0" timestamp="2024-05-06T07:08:09">',
+    )
+    expect(xml).toContain(
+      '<failure message="bad" type="x">line 1\nline 2</failure>',
+    )
+    expect(xml).toContain('<system-out>fyi</system-out>')
+  })
+
+  it('adds a passing case to an empty suite', () => {
+    const xml = renderJunitReport({
+      emptyCaseName: 'Nothing found',
+      name: 'Suite',
+      testCases: [],
+      timestamp: TIMESTAMP,
+    })
+
+    expect(xml).toContain('tests="1" failures="0"')
+    expect(xml).toContain(
+      '<testcase classname="Suite" name="Nothing found" time="0"/>',
+    )
+  })
+
+  it('escapes names and details', () => {
+    const xml = renderJunitReport({
+      emptyCaseName: 'Nothing found',
+      name: 'Suite',
+      testCases: [
+        {
+          classname: 'a',
+          failure: { details: '<script>', message: '"quoted"', type: 'x' },
+          name: 'a & b',
+        },
+      ],
+      timestamp: TIMESTAMP,
+    })
+
+    expect(xml).toContain('name="a &amp; b"')
+    expect(xml).toContain('message="&quot;quoted&quot;"')
+    expect(xml).toContain('&lt;script&gt;')
+  })
+})
@@ -0,0 +1,145 @@
+/**
+ * Unit tests for the progress of long steps.
+ *
+ * Test Coverage: - Progress bars, durations and time estimates - Redrawing on
+ * a terminal - One line per step without one - Nothing under --quiet.
+ *
+ * Related Files: - src/util/output/progress.mts (implementation)
+ */
+
+import { afterEach, describe, expect, it, vi } from 'vitest'
+
+const mockLogger = vi.hoisted(() => ({
+  info: vi.fn(),
+  success: vi.fn(),
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+}))
+
+import {
+  resetMachineOutputMode,
+  setMachineOutputMode,
+} from '../../../../src/util/output/ambient-mode.mts'
+import {
+  estimateRemainingMs,
+  formatDuration,
+  formatProgressBar,
+  formatProgressText,
+  getProgressMode,
+  startProgress,
+} from '../../../../src/util/output/progress.mts'
+
+import type { SpinnerInstance } from '@socketsecurity/lib-stable/spinner/types'
+
+function getSpinner() {
+  return {
+    start: vi.fn(),
+    stop: vi.fn(),
+    text: vi.fn(),
+  }
+}
+
+describe('formatProgressBar', () => {
+  it('formats the progress bar', () => {
+    expect(formatProgressBar(0, 100)).toBe('[--------------------] 0%')
+    expect(formatProgressBar(50, 100)).toBe('[##########----------] 50%')
+    expect(formatProgressBar(0, 0)).toBe('[####################] 100%')
+  })
+})
+
+describe('formatDuration', () => {
+  it('uses the two largest units', () => {
+    expect(formatDuration(400)).toBe('0s')
+    expect(formatDuration(45_000)).toBe('45s')
+    expect(formatDuration(65_000)).toBe('1m 05s')
+    expect(formatDuration(2 * 3_600_000 + 7 * 60_000)).toBe('2h 07m')
+  })
+})
+
+describe('estimateRemainingMs', () => {
+  it('extrapolates the rate so far', () => {
+    expect(estimateRemainingMs(25, 100, 10_000)).toBe(30_000)
+  })
+
+  it('waits for a rate to go by', () => {
+    expect(estimateRemainingMs(0, 100, 10_000)).toBeUndefined()
+    expect(estimateRemainingMs(25, 100, 500)).toBeUndefined()
+    expect(estimateRemainingMs(100, 100, 10_000)).toBeUndefined()
+  })
+})
+
+describe('formatProgressText', () => {
+  it('shows the elapsed time until there is an estimate', () => {
+    expect(formatProgressText({ elapsedMs: 0, label: 'Uploading' })).toBe(
+      'Uploading…',
+    )
+    expect(formatProgressText({ elapsedMs: 75_000, label: 'Uploading' })).toBe(
+      'Uploading, 1m 15s elapsed',
+    )
+  })
+
+  it('shows the bar and time left of a step with a size', () => {
+    expect(
+      formatProgressText({
+        detail: '(1.0 MiB of 4.0 MiB)',
+        done: 1,
+        elapsedMs: 10_000,
+        label: 'Hashing scan files',
+        total: 4,
+      }),
+    ).toBe(
+      'Hashing scan files [#####---------------] 25% (1.0 MiB of 4.0 MiB), ~30s left',
+    )
+  })
+})
+
+describe('startProgress', () => {
+  afterEach(() => {
+    resetMachineOutputMode()
+    vi.clearAllMocks()
+  })
+
+  it('redraws the spinner on a terminal', () => {
+    const spinner = getSpinner()
+    const progress = startProgress('Uploading', {
+      mode: 'interactive',
+      spinner: spinner as unknown as SpinnerInstance,
+    })
+    expect(spinner.start).toHaveBeenCalledWith('Uploading…')
+    progress.update(1, 2)
+    expect(spinner.text).toHaveBeenCalledWith(
+      expect.stringContaining('[##########----------] 50%'),
+    )
+    progress.succeed('Uploaded')
+    expect(spinner.stop).toHaveBeenCalledOnce()
+    expect(mockLogger.success).toHaveBeenCalledWith('Uploaded')
+  })
+
+  it('prints one line per step without a terminal', () => {
+    const spinner = getSpinner()
+    const progress = startProgress('Uploading', {
+      mode: 'plain',
+      spinner: spinner as unknown as SpinnerInstance,
+    })
+    progress.update(1, 2)
+    progress.stop()
+    expect(mockLogger.info).toHaveBeenCalledWith('Uploading…')
+    expect(spinner.start).not.toHaveBeenCalled()
+    expect(spinner.text).not.toHaveBeenCalled()
+  })
+
+  it('prints nothing under --quiet', () => {
+    setMachineOutputMode({ quiet: true })
+    expect(getProgressMode()).toBe('silent')
+
+    const spinner = getSpinner()
+    startProgress('Uploading', {
+      spinner: spinner as unknown as SpinnerInstance,
+    }).succeed('Uploaded')
+    expect(spinner.start).not.toHaveBeenCalled()
+    expect(mockLogger.info).not.toHaveBeenCalled()
+    expect(mockLogger.success).not.toHaveBeenCalled()
+  })
+})
@@ -5,7 +5,8 @@
  * and error serialization.
  *
  * Test Coverage: - CResult to JSON conversion - Error serialization - Data
- * sanitization - Nested object handling - Pretty printing options.
+ * sanitization - Nested object handling - Pretty printing options - Versioned
+ * envelope while a command runs.
  *
  * Testing Approach: Tests JSON output formatting with CResult patterns.
  *
@@ -14,12 +15,17 @@
 
 import { afterEach, describe, expect, it, vi } from 'vitest'
 
+import {
+  resetJsonOutputCommand,
+  setJsonOutputCommand,
+} from '../../../../src/util/output/json-envelope.mts'
 import { serializeResultJson } from '../../../../src/util/output/result-json.mts'
 
 describe('serializeResultJson', () => {
   afterEach(() => {
     // Reset exitCode after each test.
     process.exitCode = undefined
+    resetJsonOutputCommand()
   })
 
   it('serializes simple objects', () => {
@@ -129,4 +135,28 @@ describe('serializeResultJson', () => {
     expect(Array.isArray(parsed)).toBe(true)
     expect(parsed).toEqual([1, 2, 3])
   })
+
+  it('wraps the result in the envelope of the running command', () => {
+    setJsonOutputCommand('socket scan report')
+
+    const result = serializeResultJson({ ok: true, data: { healthy: true } })
+
+    expect(result).toBe(
+      '{\n  "version": "1",\n  "command": "scan report",\n  "ok": true,\n  "data": {\n    "healthy": true\n  }\n}\n',
+    )
+  })
+
+  it('wraps serialization errors in the envelope', () => {
+    setJsonOutputCommand('socket scan report')
+
+    const parsed = JSON.parse(
+      serializeResultJson(null as unknown as { ok: boolean }),
+    )
+
+    expect(parsed).toMatchObject({
+      version: '1',
+      command: 'scan report',
+      ok: false,
+    })
+  })
 })
@@ -0,0 +1,187 @@
+/**
+ * Unit tests for `--output-template` rendering.
+ *
+ * Purpose: Tests the Handlebars-style template subset used to render results
+ * as arbitrary text.
+ *
+ * Test Coverage: - Value lookups - Helpers - each/if/unless blocks with else -
+ * Standalone block lines - Comments - Syntax errors with line numbers -
+ * Template files.
+ *
+ * Related Files: - util/output/template.mts (implementation).
+ */
+
+import { mkdtempSync, rmSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it } from 'vitest'
+
+import {
+  TemplateError,
+  renderTemplate,
+  renderTemplateFile,
+} from '../../../../src/util/output/template.mts'
+
+const context = {
+  alerts: [
+    { purl: 'pkg:npm/a@1.0.0', tags: ['x', 'y'], type: 'malware' },
+    { purl: 'pkg:npm/b@2.0.0', tags: [], type: 'envVars' },
+  ],
+  counts: { error: 1, total: 2 },
+  healthy: false,
+  orgSlug: 'acme',
+}
+
+describe('renderTemplate', () => {
+  it('prints values by path', () => {
+    expect(
+      renderTemplate(
+        '{{orgSlug}}: {{counts.error}}/{{counts.total}} {{alerts.0.type}}',
+        context,
+      ),
+    ).toBe('acme: 1/2 malware')
+  })
+
+  it('prints missing values as nothing and objects as JSON', () => {
+    expect(renderTemplate('[{{missing.value}}] {{counts}}', context)).toBe(
+      '[] {"error":1,"total":2}',
+    )
+  })
+
+  it('does not escape values', () => {
+    expect(renderTemplate('{{text}}', { text: '<b>"&"</b>' })).toBe(
+      '<b>"&"</b>',
+    )
+  })
+
+  it('calls helpers', () => {
+    expect(
+      renderTemplate(
+        [
+          '{{upper orgSlug}} {{lower "ABC"}}',
+          '{{length alerts}} {{length counts}}',
+          '{{join alerts.0.tags}} {{join alerts.0.tags "|"}}',
+          '{{default missing "" "n/a"}}',
+          '{{json orgSlug}}',
+        ].join('\n'),
+        context,
+      ),
+    ).toBe('ACME abc\n2 2\nx, y x|y\nn/a\n"acme"')
+  })
+
+  it('loops over arrays with loop variables', () => {
+    expect(
+      renderTemplate(
+        '{{#each alerts}}{{@index}}:{{type}}{{#unless @last}}, {{/unless}}{{/each}}',
+        context,
+      ),
+    ).toBe('0:malware, 1:envVars')
+  })
+
+  it('loops over objects with @key', () => {
+    expect(
+      renderTemplate('{{#each counts}}{{@key}}={{this}};{{/each}}', context),
+    ).toBe('error=1;total=2;')
+  })
+
+  it('looks up names in enclosing scopes', () => {
+    expect(
+      renderTemplate('{{#each alerts}}{{orgSlug}}/{{purl}} {{/each}}', context),
+    ).toBe('acme/pkg:npm/a@1.0.0 acme/pkg:npm/b@2.0.0 ')
+  })
+
+  it('renders else branches', () => {
+    expect(
+      renderTemplate(
+        '{{#if healthy}}pass{{else}}fail{{/if}} {{#each none}}x{{else}}empty{{/each}}',
+        context,
+      ),
+    ).toBe('fail empty')
+  })
+
+  it('treats empty arrays as false', () => {
+    expect(
+      renderTemplate('{{#if alerts.1.tags}}tags{{else}}no tags{{/if}}', context),
+    ).toBe('no tags')
+  })
+
+  it('supports helpers as block conditions', () => {
+    expect(
+      renderTemplate('{{#if eq orgSlug "acme"}}yes{{/if}}', context),
+    ).toBe('yes')
+  })
+
+  it('drops the lines of standalone block tags and comments', () => {
+    const source = [
+      '{{! Slack message }}',
+      'Report for {{orgSlug}}',
+      '{{#each alerts}}',
+      '  {{#if tags}}',
+      '  - {{type}}',
+      '  {{/if}}',
+      '{{/each}}',
+      'done',
+    ].join('\n')
+
+    expect(renderTemplate(source, context)).toBe(
+      'Report for acme\n  - malware\ndone',
+    )
+  })
+
+  it.each([
+    ['{{#each alerts}}', 'Missing {{/each}} (line 1)'],
+    ['{{#if a}}\n{{/each}}', 'Expected {{/if}} but found {{/each}} (line 2)'],
+    ['\n\n{{/if}}', 'Unexpected {{/if}} (line 3)'],
+    ['{{#with a}}{{/with}}', 'Unknown block "#with" (line 1)'],
+    ['{{shout orgSlug}}', 'Unknown helper "shout" (line 1)'],
+    ['{{#if a}}{{else}}{{else}}{{/if}}', 'Unexpected {{else}} (line 1)'],
+    ['{{ }}', 'Missing expression in {{ }} (line 1)'],
+  ])('rejects %j', (source, message) => {
+    expect(() => renderTemplate(source, context)).toThrow(TemplateError)
+    expect(() => renderTemplate(source, context)).toThrow(message)
+  })
+})
+
+describe('renderTemplateFile', () => {
+  let tmpDir: string
+
+  beforeEach(() => {
+    tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-template-'))
+  })
+
+  afterEach(() => {
+    rmSync(tmpDir, { force: true, recursive: true })
+  })
+
+  it('renders a template file', () => {
+    const filepath = path.join(tmpDir, 'report.hbs')
+    writeFileSync(filepath, 'Org {{orgSlug}}\n')
+
+    expect(renderTemplateFile(filepath, context)).toEqual({
+      ok: true,
+      data: 'Org acme\n',
+    })
+  })
+
+  it('fails for a missing file', () => {
+    const filepath = path.join(tmpDir, 'missing.hbs')
+
+    expect(renderTemplateFile(filepath, context)).toEqual({
+      ok: false,
+      message: 'Template file not found',
+      cause: `Unable to read ${filepath}`,
+    })
+  })
+
+  it('fails for an invalid template', () => {
+    const filepath = path.join(tmpDir, 'bad.hbs')
+    writeFileSync(filepath, 'Org\n{{#if healthy}}')
+
+    expect(renderTemplateFile(filepath, context)).toEqual({
+      ok: false,
+      message: `Invalid template file ${filepath}`,
+      cause: 'Missing {{/if}} (line 2)',
+    })
+  })
+})
@@ -0,0 +1,90 @@
+/**
+ * Unit tests for unified line diffs.
+ *
+ * Related Files: - src/util/output/unified-diff.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  diffLines,
+  formatUnifiedDiff,
+} from '../../../../src/util/output/unified-diff.mts'
+
+const TEN_LINES = 'a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n'
+
+describe('diffLines', () => {
+  it('finds the shortest edit script', () => {
+    expect(diffLines(['a', 'b', 'c'], ['a', 'c', 'd'])).toEqual([
+      { text: 'a', type: ' ' },
+      { text: 'b', type: '-' },
+      { text: 'c', type: ' ' },
+      { text: 'd', type: '+' },
+    ])
+  })
+
+  it('handles empty sides', () => {
+    expect(diffLines([], [])).toEqual([])
+    expect(diffLines([], ['a'])).toEqual([{ text: 'a', type: '+' }])
+    expect(diffLines(['a'], [])).toEqual([{ text: 'a', type: '-' }])
+  })
+})
+
+describe('formatUnifiedDiff', () => {
+  it('is empty for equal files', () => {
+    expect(
+      formatUnifiedDiff({ after: TEN_LINES, before: TEN_LINES, path: 'x' }),
+    ).toBe('')
+  })
+
+  it('splits changes far apart into hunks', () => {
+    expect(
+      formatUnifiedDiff({
+        after: TEN_LINES.replace('b', 'B').replace('j', 'J'),
+        before: TEN_LINES,
+        path: 'index.js',
+      }),
+    ).toBe(
+      [
+        '--- a/index.js',
+        '+++ b/index.js',
+        '@@ -1,5 +1,5 @@',
+        ' a',
+        '-b',
+        '+B',
+        ' c',
+        ' d',
+        ' e',
+        '@@ -7,4 +7,4 @@',
+        ' g',
+        ' h',
+        ' i',
+        '-j',
+        '+J',
+      ].join('\n'),
+    )
+  })
+
+  it('joins changes that share context', () => {
+    const diff = formatUnifiedDiff(
+      {
+        after: TEN_LINES.replace('b', 'B').replace('f', 'F'),
+        before: TEN_LINES,
+        path: 'x',
+      },
+      { context: 2 },
+    )
+    expect(diff?.split('\n').filter(line => line.startsWith('@@'))).toEqual([
+      '@@ -1,8 +1,8 @@',
+    ])
+  })
+
+  it('diffs added and removed files against /dev/null', () => {
+    expect(formatUnifiedDiff({ after: 'a\nb\n', path: 'new.js' })).toBe(
+      '--- /dev/null\n+++ b/new.js\n@@ -0,0 +1,2 @@\n+a\n+b',
+    )
+    expect(formatUnifiedDiff({ before: 'a\n', path: 'old.js' })).toBe(
+      '--- a/old.js\n+++ /dev/null\n@@ -1 +0,0 @@\n-a',
+    )
+  })
+})
@@ -0,0 +1,70 @@
+/**
+ * Unit tests for XML serialization.
+ *
+ * Purpose: Tests the minimal XML writer used by CycloneDX, JUnit and GraphML
+ * output.
+ *
+ * Test Coverage: - Escaping - Attribute rendering - Nested elements - Document
+ * declaration.
+ *
+ * Related Files: - util/output/xml.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  XML_DECLARATION,
+  escapeXml,
+  renderXmlDocument,
+  renderXmlNode,
+} from '../../../../src/util/output/xml.mts'
+
+describe('xml utilities', () => {
+  describe('escapeXml', () => {
+    it('escapes markup characters', () => {
+      expect(escapeXml(`<a href="x">'&'</a>`)).toBe(
+        '&lt;a href=&quot;x&quot;&gt;&apos;&amp;&apos;&lt;/a&gt;',
+      )
+    })
+
+    it('strips characters that are invalid in XML 1.0', () => {
+      expect(escapeXml('a\u0000b\u001Fc')).toBe('abc')
+    })
+
+    it('stringifies numbers and booleans', () => {
+      expect(escapeXml(1)).toBe('1')
+      expect(escapeXml(false)).toBe('false')
+    })
+  })
+
+  describe('renderXmlNode', () => {
+    it('renders self-closing elements with attributes', () => {
+      expect(
+        renderXmlNode({ name: 'dep', attrs: { ref: 'a&b', skip: undefined } }),
+      ).toBe('<dep ref="a&amp;b"/>')
+    })
+
+    it('renders text content', () => {
+      expect(renderXmlNode({ name: 'name', text: 'x<y' })).toBe(
+        '<name>x&lt;y</name>',
+      )
+    })
+
+    it('indents nested elements', () => {
+      expect(
+        renderXmlNode({
+          name: 'a',
+          children: [{ name: 'b', children: [{ name: 'c', text: 1 }] }],
+        }),
+      ).toBe('<a>\n  <b>\n    <c>1</c>\n  </b>\n</a>')
+    })
+  })
+
+  describe('renderXmlDocument', () => {
+    it('prefixes the declaration and ends with a newline', () => {
+      expect(renderXmlDocument({ name: 'root' })).toBe(
+        `${XML_DECLARATION}\n<root/>\n`,
+      )
+    })
+  })
+})
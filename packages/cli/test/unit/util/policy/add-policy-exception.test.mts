@@ -0,0 +1,125 @@
+/**
+ * Unit tests for appending exceptions to a `socket.policy.yml`.
+ *
+ * Purpose: Tests that triage decisions land in the policy file as exceptions
+ * the linter accepts, without losing what the file already had.
+ *
+ * Test Coverage: - Appending to existing exceptions - Adding the first
+ * exception - Creating a missing file - Comment preservation - Refusing
+ * invalid files.
+ *
+ * Testing Approach: Writes to a temp dir and reads the result back with
+ * lintSocketPolicy.
+ *
+ * Related Files: - src/util/policy/add-policy-exception.mts (implementation)
+ */
+
+import { mkdtempSync, readFileSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import { addSocketPolicyException } from '../../../../src/util/policy/add-policy-exception.mts'
+import { lintSocketPolicy } from '../../../../src/util/policy/socket-policy-lint.mts'
+
+const EXCEPTION = {
+  alerts: ['gitDependency'],
+  packages: ['pkg:npm/lodash@4.17.20'],
+  paths: [],
+  reason: 'Pinned fork, reviewed',
+}
+
+describe('addSocketPolicyException', () => {
+  let tmpDir: string
+  let policyPath: string
+
+  beforeEach(() => {
+    tmpDir = path.resolve(mkdtempSync(path.join(os.tmpdir(), 'socket-test-')))
+    policyPath = path.join(tmpDir, 'socket.policy.yml')
+  })
+
+  afterEach(async () => {
+    await safeDelete(tmpDir, { recursive: true })
+  })
+
+  it('appends to the existing exceptions and keeps comments', () => {
+    writeFileSync(
+      policyPath,
+      `# Repository gating
+version: 1
+block:
+  alerts: [malware]
+exceptions:
+  - paths: ['tools/**']
+    alerts: [installScripts]
+    reason: Build tooling, never shipped.
+`,
+      'utf8',
+    )
+
+    const result = addSocketPolicyException(policyPath, EXCEPTION)
+
+    expect(result.ok).toBe(true)
+    const content = readFileSync(policyPath, 'utf8')
+    expect(content).toContain('# Repository gating')
+    const { issues, policy } = lintSocketPolicy(content)
+    expect(issues).toEqual([])
+    expect(policy.block.alerts).toEqual(['malware'])
+    expect(policy.exceptions).toEqual([
+      {
+        alerts: ['installScripts'],
+        packages: [],
+        paths: ['tools/**'],
+        reason: 'Build tooling, never shipped.',
+      },
+      EXCEPTION,
+    ])
+  })
+
+  it('adds an exceptions list when the file has none', () => {
+    writeFileSync(policyPath, 'version: 1\nwarn:\n  severity: high\n', 'utf8')
+
+    const result = addSocketPolicyException(policyPath, EXCEPTION)
+
+    expect(result.ok).toBe(true)
+    const { issues, policy } = lintSocketPolicy(
+      readFileSync(policyPath, 'utf8'),
+    )
+    expect(issues).toEqual([])
+    expect(policy.warn.severity).toBe('high')
+    expect(policy.exceptions).toEqual([EXCEPTION])
+  })
+
+  it('creates a missing policy file', () => {
+    const result = addSocketPolicyException(policyPath, {
+      ...EXCEPTION,
+      expires: '2099-01-31',
+    })
+
+    expect(result.ok).toBe(true)
+    const { issues, policy } = lintSocketPolicy(
+      readFileSync(policyPath, 'utf8'),
+    )
+    expect(issues).toEqual([])
+    expect(policy.version).toBe(1)
+    expect(policy.exceptions).toEqual([
+      { ...EXCEPTION, expires: '2099-01-31' },
+    ])
+  })
+
+  it('leaves an invalid policy file untouched', () => {
+    const content = 'version: 1\nwarn:\n  severity: medium\n'
+    writeFileSync(policyPath, content, 'utf8')
+
+    const result = addSocketPolicyException(policyPath, EXCEPTION)
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.message).toBe(
+      `Invalid policy file ${policyPath}`,
+    )
+    expect(readFileSync(policyPath, 'utf8')).toBe(content)
+  })
+})
@@ -0,0 +1,132 @@
+/**
+ * Unit tests for `socket.baseline.json` handling.
+ *
+ * Purpose: Tests snapshotting scan alerts into a baseline and matching later
+ * alerts against it.
+ *
+ * Test Coverage: - createSocketBaseline dedupe and ordering -
+ * parseSocketBaseline validation - isBaselinedAlert - findSocketBaselineSync
+ * directory walk.
+ *
+ * Testing Approach: Uses a temp dir for discovery.
+ *
+ * Related Files: - src/util/policy/baseline.mts (implementation)
+ */
+
+import { mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import { safeDelete, safeMkdirSync } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  createSocketBaseline,
+  findSocketBaselineSync,
+  isBaselinedAlert,
+  parseSocketBaseline,
+} from '../../../../src/util/policy/baseline.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+const esbuild = artifact('esbuild', { version: '0.19.0' })
+
+describe('createSocketBaseline', () => {
+  it('records each package and alert type once, sorted', () => {
+    const baseline = createSocketBaseline(
+      [
+        artifact('zod', {
+          version: '3.0.0',
+          alerts: [{ type: 'unmaintained' }],
+        }),
+        artifact('esbuild', {
+          version: '0.19.0',
+          alerts: [{ type: 'installScripts' }, { type: 'installScripts' }],
+        }),
+        artifact('esbuild', {
+          version: '0.19.0',
+          alerts: [{ type: 'envVars' }],
+        }),
+      ],
+      'scan-1',
+    )
+
+    expect(baseline.version).toBe(1)
+    expect(baseline.scanId).toBe('scan-1')
+    expect(baseline.alerts).toEqual([
+      { purl: 'pkg:npm/esbuild@0.19.0', type: 'envVars' },
+      { purl: 'pkg:npm/esbuild@0.19.0', type: 'installScripts' },
+      { purl: 'pkg:npm/zod@3.0.0', type: 'unmaintained' },
+    ])
+  })
+})
+
+describe('parseSocketBaseline', () => {
+  it('round-trips a written baseline', () => {
+    const baseline = createSocketBaseline([
+      artifact('esbuild', {
+        version: '0.19.0',
+        alerts: [{ type: 'installScripts' }],
+      }),
+    ])
+
+    const result = parseSocketBaseline(JSON.stringify(baseline))
+
+    expect(result.ok).toBe(true)
+    const found = {
+      keys: result.ok ? result.data : new Set<string>(),
+      path: '/repo/socket.baseline.json',
+    }
+    expect(isBaselinedAlert(found, esbuild, 'installScripts')).toBe(true)
+    // A new version or another alert type is not covered.
+    expect(
+      isBaselinedAlert(
+        found,
+        artifact('esbuild', { version: '0.20.0' }),
+        'installScripts',
+      ),
+    ).toBe(false)
+    expect(isBaselinedAlert(found, esbuild, 'envVars')).toBe(false)
+  })
+
+  it('rejects malformed files', () => {
+    expect(parseSocketBaseline('not json').ok).toBe(false)
+    expect(parseSocketBaseline('{"version":2,"alerts":[]}').ok).toBe(false)
+    const result = parseSocketBaseline('{"version":1,"alerts":[{"purl":1}]}')
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.cause).toContain('alerts[0]')
+  })
+})
+
+describe('findSocketBaselineSync', () => {
+  it('finds the nearest baseline walking up', async () => {
+    const tmpDir = path.resolve(
+      mkdtempSync(path.join(os.tmpdir(), 'socket-test-')),
+    )
+    const baselinePath = path.join(tmpDir, 'socket.baseline.json')
+    const nestedDir = path.join(tmpDir, 'packages', 'app')
+
+    try {
+      safeMkdirSync(nestedDir, { recursive: true })
+      writeFileSync(
+        baselinePath,
+        JSON.stringify(
+          createSocketBaseline([
+            artifact('esbuild', {
+              version: '0.19.0',
+              alerts: [{ type: 'envVars' }],
+            }),
+          ]),
+        ),
+      )
+
+      const result = findSocketBaselineSync(nestedDir)
+
+      expect(result.ok).toBe(true)
+      expect(result.data?.path).toBe(baselinePath)
+      expect(result.data?.keys.size).toBe(1)
+    } finally {
+      await safeDelete(tmpDir, { recursive: true })
+    }
+  })
+})
@@ -0,0 +1,287 @@
+/**
+ * Unit tests for local `socket.policy.yml` evaluation.
+ *
+ * Purpose: Tests how a local policy reassigns alert actions and judges
+ * license expressions.
+ *
+ * Test Coverage: - getLocalPolicyAction precedence (exception, block, warn) -
+ * isPolicyException package, path and expiry matching -
+ * splitSpdxAlternatives - getLocalLicenseViolation allow/deny handling -
+ * getLocalQuarantineViolation windows and scope exceptions - getLocalTrust
+ * and applyLocalTrust.
+ *
+ * Related Files: - src/util/policy/evaluate.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  LOCAL_LICENSE_POLICY_ALERT,
+  LOCAL_QUARANTINE_POLICY_ALERT,
+  applyLocalTrust,
+  getLocalLicenseViolation,
+  getLocalPolicyAction,
+  getLocalQuarantineViolation,
+  getLocalTrust,
+  isPolicyException,
+  splitSpdxAlternatives,
+} from '../../../../src/util/policy/evaluate.mts'
+import { createEmptySocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+import { artifact } from '../../../helpers/test-fixtures.mts'
+
+import type { SocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+
+const lodash = artifact('lodash', { version: '4.17.21' })
+
+function policy(overrides: Partial<SocketPolicy> = {}): SocketPolicy {
+  return { ...createEmptySocketPolicy(), ...overrides }
+}
+
+describe('getLocalPolicyAction', () => {
+  it('returns undefined when no rule matches', () => {
+    expect(
+      getLocalPolicyAction(policy(), lodash, { type: 'installScripts' }),
+    ).toBeUndefined()
+  })
+
+  it('blocks listed alerts and alerts at or above the severity', () => {
+    const p = policy({ block: { alerts: ['malware'], severity: 'high' } })
+
+    expect(getLocalPolicyAction(p, lodash, { type: 'malware' })).toBe('error')
+    expect(
+      getLocalPolicyAction(p, lodash, {
+        type: 'criticalCVE',
+        severity: 'critical',
+      }),
+    ).toBe('error')
+    expect(
+      getLocalPolicyAction(p, lodash, { type: 'mildCVE', severity: 'middle' }),
+    ).toBeUndefined()
+  })
+
+  it('prefers block over warn', () => {
+    const p = policy({
+      block: { alerts: ['installScripts'] },
+      warn: { alerts: ['installScripts'] },
+    })
+
+    expect(
+      getLocalPolicyAction(p, lodash, { type: 'installScripts' }),
+    ).toBe('error')
+  })
+
+  it('ignores alerts waived by an exception', () => {
+    const p = policy({
+      block: { alerts: ['installScripts'] },
+      exceptions: [
+        {
+          alerts: ['installScripts'],
+          packages: ['pkg:npm/lodash'],
+          paths: [],
+          reason: 'Reviewed',
+        },
+      ],
+    })
+
+    expect(
+      getLocalPolicyAction(p, lodash, { type: 'installScripts' }),
+    ).toBe('ignore')
+    expect(
+      getLocalPolicyAction(p, artifact('esbuild'), {
+        type: 'installScripts',
+      }),
+    ).toBe('error')
+  })
+})
+
+describe('isPolicyException', () => {
+  it('matches versioned package globs', () => {
+    const p = policy({
+      exceptions: [
+        {
+          alerts: [],
+          packages: ['pkg:npm/lodash@4.*'],
+          paths: [],
+          reason: 'Reviewed',
+        },
+      ],
+    })
+
+    expect(isPolicyException(p, lodash, 'anything')).toBe(true)
+    expect(
+      isPolicyException(
+        p,
+        artifact('lodash', { version: '3.10.1' }),
+        'anything',
+      ),
+    ).toBe(false)
+  })
+
+  it('skips expired exceptions', () => {
+    const exception = {
+      alerts: [],
+      packages: ['pkg:npm/lodash'],
+      paths: [],
+      reason: 'Reviewed',
+    }
+    const expired = policy({
+      exceptions: [{ ...exception, expires: '2000-01-01' }],
+    })
+    const active = policy({
+      exceptions: [{ ...exception, expires: '2999-12-31' }],
+    })
+
+    expect(isPolicyException(expired, lodash, 'anything')).toBe(false)
+    expect(isPolicyException(active, lodash, 'anything')).toBe(true)
+  })
+
+  it('requires every manifest to be covered by the paths', () => {
+    const p = policy({
+      exceptions: [
+        { alerts: [], packages: [], paths: ['tools/**'], reason: 'Tooling' },
+      ],
+    })
+    const toolsOnly = artifact('lodash', {
+      manifestFiles: [{ file: 'tools/build/package.json' }],
+    })
+    const shared = artifact('lodash', {
+      manifestFiles: [
+        { file: 'tools/build/package.json' },
+        { file: 'package.json' },
+      ],
+    })
+
+    expect(isPolicyException(p, toolsOnly, 'installScripts')).toBe(true)
+    expect(isPolicyException(p, shared, 'installScripts')).toBe(false)
+    expect(isPolicyException(p, lodash, 'installScripts')).toBe(false)
+  })
+})
+
+describe('splitSpdxAlternatives', () => {
+  it('splits OR alternatives into AND groups', () => {
+    expect(
+      splitSpdxAlternatives(
+        '(MIT AND BSD-3-Clause) OR GPL-2.0-only WITH Classpath-exception-2.0',
+      ),
+    ).toEqual([['MIT', 'BSD-3-Clause'], ['GPL-2.0-only']])
+  })
+})
+
+describe('getLocalLicenseViolation', () => {
+  const licensed = (license: string) => artifact('lodash', { license })
+
+  it('passes when any alternative is acceptable', () => {
+    const p = policy({ licenses: { allow: ['MIT'], deny: [] } })
+
+    expect(
+      getLocalLicenseViolation(p, licensed('MIT OR GPL-3.0-only')),
+    ).toBeUndefined()
+  })
+
+  it('reports licenses outside the allow list or in the deny list', () => {
+    const allow = policy({ licenses: { allow: ['MIT'], deny: [] } })
+    const deny = policy({ licenses: { allow: [], deny: ['AGPL-3.0-only'] } })
+
+    expect(getLocalLicenseViolation(allow, licensed('ISC'))).toBe('ISC')
+    expect(
+      getLocalLicenseViolation(deny, licensed('AGPL-3.0-only')),
+    ).toBe('AGPL-3.0-only')
+    expect(getLocalLicenseViolation(deny, licensed('MIT'))).toBeUndefined()
+  })
+
+  it('skips artifacts without license data or with an exception', () => {
+    const p = policy({
+      licenses: { allow: ['MIT'], deny: [] },
+      exceptions: [
+        {
+          alerts: [LOCAL_LICENSE_POLICY_ALERT],
+          packages: ['pkg:npm/lodash'],
+          paths: [],
+          reason: 'Reviewed',
+        },
+      ],
+    })
+
+    expect(getLocalLicenseViolation(p, lodash)).toBeUndefined()
+    expect(getLocalLicenseViolation(p, licensed('ISC'))).toBeUndefined()
+  })
+})
+
+describe('getLocalQuarantineViolation', () => {
+  const now = Date.parse('2026-03-10T00:00:00Z')
+  const day = 24 * 60 * 60 * 1000
+
+  it('reports the age of versions inside the window', () => {
+    const p = policy({ quarantine: { action: 'block', days: 7 } })
+
+    expect(getLocalQuarantineViolation(p, lodash, now - 2.5 * day, now)).toBe(2)
+    expect(
+      getLocalQuarantineViolation(p, lodash, now - 7 * day, now),
+    ).toBeUndefined()
+  })
+
+  it('skips versions without a window, a publish time or with an exception', () => {
+    const p = policy({
+      quarantine: { action: 'warn', days: 7 },
+      exceptions: [
+        {
+          alerts: [LOCAL_QUARANTINE_POLICY_ALERT],
+          packages: ['pkg:npm/@acme/*'],
+          paths: [],
+          reason: 'Published by us',
+        },
+      ],
+    })
+
+    expect(
+      getLocalQuarantineViolation(policy(), lodash, now, now),
+    ).toBeUndefined()
+    expect(
+      getLocalQuarantineViolation(p, lodash, undefined, now),
+    ).toBeUndefined()
+    expect(
+      getLocalQuarantineViolation(
+        p,
+        artifact('utils', { namespace: '@acme' }),
+        now,
+        now,
+      ),
+    ).toBeUndefined()
+    expect(getLocalQuarantineViolation(p, lodash, now, now)).toBe(0)
+  })
+})
+
+describe('getLocalTrust', () => {
+  // Socket reports the publishers of a version as its `author` list.
+  const authoredBy = (...author: string[]) => artifact('lodash', { author })
+  const p = policy({
+    distrust: { maintainers: ['mallory'], packages: ['pkg:npm/@acme/legacy'] },
+    trust: { maintainers: ['Acme-Bot'], packages: ['pkg:npm/@acme/*'] },
+  })
+
+  it('trusts scopes and maintainers, case insensitively for maintainers', () => {
+    expect(
+      getLocalTrust(p, artifact('utils', { namespace: '@acme' })),
+    ).toBe('trusted')
+    expect(getLocalTrust(p, authoredBy('acme-bot'))).toBe('trusted')
+    expect(getLocalTrust(p, lodash)).toBeUndefined()
+  })
+
+  it('prefers distrust over trust', () => {
+    expect(
+      getLocalTrust(p, artifact('legacy', { namespace: '@acme' })),
+    ).toBe('distrusted')
+    expect(getLocalTrust(p, authoredBy('acme-bot', 'mallory'))).toBe(
+      'distrusted',
+    )
+  })
+})
+
+describe('applyLocalTrust', () => {
+  it('only waives warn actions of trusted packages', () => {
+    expect(applyLocalTrust('warn', 'trusted')).toBe('ignore')
+    expect(applyLocalTrust('error', 'trusted')).toBe('error')
+    expect(applyLocalTrust('warn', 'distrusted')).toBe('warn')
+    expect(applyLocalTrust('warn', undefined)).toBe('warn')
+  })
+})
@@ -0,0 +1,159 @@
+/**
+ * Unit tests for `socket.org-policy.json` handling.
+ *
+ * Purpose: Tests snapshotting the org security policy and comparing the local
+ * repo settings against it.
+ *
+ * Test Coverage: - createSocketOrgPolicy ordering and deferred rules -
+ * parseSocketOrgPolicy validation - diffLocalPolicy for socket.policy.yml
+ * rules, exceptions and socket.yml wrapper modes.
+ *
+ * Related Files: - src/util/policy/org-policy.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  createSocketOrgPolicy,
+  diffLocalPolicy,
+  parseSocketOrgPolicy,
+} from '../../../../src/util/policy/org-policy.mts'
+import { createEmptySocketPolicy } from '../../../../src/util/policy/socket-policy.mts'
+
+import type { SocketOrgPolicy } from '../../../../src/util/policy/org-policy.mts'
+
+const orgPolicy: SocketOrgPolicy = {
+  createdAt: '2026-01-01T00:00:00.000Z',
+  default: 'medium',
+  org: 'acme',
+  rules: {
+    gitDependency: 'warn',
+    installScripts: 'warn',
+    malware: 'error',
+    unmaintained: 'monitor',
+  },
+  version: 1,
+}
+
+describe('createSocketOrgPolicy', () => {
+  it('sorts the rules and leaves out deferred ones', () => {
+    const snapshot = createSocketOrgPolicy('acme', {
+      securityPolicyDefault: 'medium',
+      securityPolicyRules: {
+        malware: { action: 'error' },
+        didYouMean: { action: 'defer' },
+        gitDependency: { action: 'warn' },
+      },
+    })
+
+    expect(snapshot).toMatchObject({
+      version: 1,
+      org: 'acme',
+      default: 'medium',
+      rules: { gitDependency: 'warn', malware: 'error' },
+    })
+    expect(Object.keys(snapshot.rules)).toEqual(['gitDependency', 'malware'])
+  })
+})
+
+describe('parseSocketOrgPolicy', () => {
+  it('reads a pulled policy', () => {
+    expect(parseSocketOrgPolicy(JSON.stringify(orgPolicy))).toEqual({
+      ok: true,
+      data: orgPolicy,
+    })
+  })
+
+  it('rejects invalid JSON and unknown shapes', () => {
+    expect(parseSocketOrgPolicy('{')).toMatchObject({
+      ok: false,
+      message: 'Invalid org policy file',
+    })
+    expect(
+      parseSocketOrgPolicy('{"version":1,"org":"acme","rules":{"a":1}}'),
+    ).toMatchObject({ ok: false, cause: 'rules.a needs a string action' })
+    expect(parseSocketOrgPolicy('{"version":2}')).toMatchObject({ ok: false })
+  })
+})
+
+describe('diffLocalPolicy', () => {
+  it('is empty without local settings', () => {
+    expect(diffLocalPolicy(orgPolicy, {})).toEqual([])
+  })
+
+  it('reports socket.policy.yml rules and exceptions', () => {
+    const policy = createEmptySocketPolicy()
+    policy.block.alerts = ['gitDependency', 'malware']
+    policy.warn.severity = 'high'
+    policy.exceptions.push(
+      {
+        alerts: ['malware'],
+        packages: ['pkg:npm/evil@1.0.0'],
+        paths: [],
+        reason: 'False positive',
+      },
+      {
+        alerts: [],
+        packages: [],
+        paths: ['tools/**'],
+        reason: 'Old',
+        expires: '2020-01-01',
+      },
+    )
+
+    expect(
+      diffLocalPolicy(orgPolicy, {
+        policy: { path: '/repo/socket.policy.yml', policy },
+      }),
+    ).toEqual([
+      {
+        alert: 'gitDependency',
+        effect: 'strengthened',
+        local: 'error',
+        org: 'warn',
+        source: 'socket.policy.yml block.alerts',
+      },
+      {
+        alert: '*',
+        effect: 'strengthened',
+        local: 'warn',
+        scope: 'high severity and above',
+        source: 'socket.policy.yml warn.severity',
+      },
+      {
+        alert: 'malware',
+        effect: 'weakened',
+        local: 'ignore',
+        org: 'error',
+        scope: 'pkg:npm/evil@1.0.0',
+        source: 'socket.policy.yml exceptions[0]',
+      },
+    ])
+  })
+
+  it('reports socket.yml wrapper modes', () => {
+    expect(
+      diffLocalPolicy(orgPolicy, {
+        wrapper: {
+          path: '/repo/socket.yml',
+          wrapper: {
+            alerts: {
+              installScripts: 'allow',
+              malware: 'block',
+              unmaintained: 'warn',
+            },
+          },
+        },
+      }),
+    ).toEqual([
+      {
+        alert: 'installScripts',
+        effect: 'weakened',
+        local: 'ignore',
+        org: 'warn',
+        scope: 'install wrappers',
+        source: 'socket.yml wrapper.alerts',
+      },
+    ])
+  })
+})
@@ -0,0 +1,181 @@
+/**
+ * Unit tests for Rego policy evaluation.
+ *
+ * Purpose: Tests the input document handed to Rego policies and how `opa
+ * eval` results and failures are turned into policy results.
+ *
+ * Test Coverage: - buildRegoInput - parseOpaEvalOutput rule shapes -
+ * evaluateRegoPolicy invocation, missing opa, compile errors and undefined
+ * packages.
+ *
+ * Testing Approach: Mocks which and spawn; no real opa binary is needed.
+ *
+ * Related Files: - src/util/policy/rego.mts (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockWhichReal = vi.hoisted(() => vi.fn())
+vi.mock(import('@socketsecurity/lib-stable/bin/which'), () => ({
+  whichReal: mockWhichReal,
+}))
+
+const mockSpawn = vi.hoisted(() => vi.fn())
+vi.mock(import('@socketsecurity/lib-stable/process/spawn/child'), () => ({
+  spawn: mockSpawn,
+}))
+
+import {
+  buildRegoInput,
+  evaluateRegoPolicy,
+  parseOpaEvalOutput,
+} from '../../../../src/util/policy/rego.mts'
+
+import type { SocketArtifact } from '../../../../src/util/alert/artifact.mts'
+
+function opaOutput(value: unknown): string {
+  return JSON.stringify({
+    result: [{ expressions: [{ value, text: 'data.socket' }] }],
+  })
+}
+
+describe('buildRegoInput', () => {
+  it('adds purls and org policy actions to the scan artifacts', () => {
+    const input = buildRegoInput({
+      artifacts: [
+        {
+          type: 'npm',
+          name: 'lodash',
+          version: '4.17.21',
+          dev: false,
+          alerts: [
+            { type: 'envVars', severity: 'low' },
+            { type: 'malware', severity: 'critical', action: 'error' },
+          ],
+        } as unknown as SocketArtifact,
+      ],
+      orgSlug: 'acme',
+      scanId: 'scan-1',
+      securityPolicyRules: { envVars: { action: 'monitor' } },
+    })
+
+    expect(input).toEqual({
+      scan: { id: 'scan-1', org: 'acme' },
+      securityPolicy: { rules: { envVars: { action: 'monitor' } } },
+      packages: [
+        {
+          type: 'npm',
+          name: 'lodash',
+          version: '4.17.21',
+          dev: false,
+          purl: 'pkg:npm/lodash@4.17.21',
+          alerts: [
+            { type: 'envVars', severity: 'low', action: 'monitor' },
+            { type: 'malware', severity: 'critical', action: 'error' },
+          ],
+        },
+      ],
+    })
+  })
+})
+
+describe('parseOpaEvalOutput', () => {
+  it('reads message sets and objects with msg', () => {
+    expect(
+      parseOpaEvalOutput(
+        opaOutput({
+          deny: ['pkg:npm/a is GPL licensed'],
+          warn: [{ msg: 'pkg:npm/b has install scripts', purl: 'pkg:npm/b' }],
+        }),
+      ),
+    ).toEqual({
+      deny: [{ msg: 'pkg:npm/a is GPL licensed' }],
+      warn: [
+        {
+          msg: 'pkg:npm/b has install scripts',
+          details: { purl: 'pkg:npm/b' },
+        },
+      ],
+    })
+  })
+
+  it('treats missing rules as empty and boolean deny as one violation', () => {
+    expect(parseOpaEvalOutput(opaOutput({ deny: true }))).toEqual({
+      deny: [{ msg: 'Denied by policy' }],
+      warn: [],
+    })
+    expect(parseOpaEvalOutput(opaOutput({}))).toEqual({ deny: [], warn: [] })
+  })
+
+  it('returns undefined when the package is undefined', () => {
+    expect(parseOpaEvalOutput('{}')).toBeUndefined()
+  })
+})
+
+describe('evaluateRegoPolicy', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockWhichReal.mockResolvedValue('/usr/local/bin/opa')
+  })
+
+  it('runs opa eval against the policy package', async () => {
+    mockSpawn.mockResolvedValue({ stdout: opaOutput({ deny: ['nope'] }) })
+
+    const result = await evaluateRegoPolicy('/repo/policy.rego', {}, 'acme')
+
+    expect(result).toEqual({
+      ok: true,
+      data: { deny: [{ msg: 'nope' }], warn: [] },
+    })
+    const [bin, args] = mockSpawn.mock.calls[0]!
+    expect(bin).toBe('/usr/local/bin/opa')
+    expect(args).toEqual([
+      'eval',
+      '--format',
+      'json',
+      '--data',
+      '/repo/policy.rego',
+      '--input',
+      expect.stringMatching(/input\.json$/),
+      'data.acme',
+    ])
+  })
+
+  it('fails when opa is not installed', async () => {
+    mockWhichReal.mockResolvedValue(null)
+
+    const result = await evaluateRegoPolicy('/repo/policy.rego', {})
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.message).toBe('Open Policy Agent not found')
+    expect(mockSpawn).not.toHaveBeenCalled()
+  })
+
+  it('reports policy compile errors', async () => {
+    mockSpawn.mockRejectedValue(
+      Object.assign(new Error('command failed'), {
+        stdout: JSON.stringify({
+          errors: [{ message: 'rego_parse_error: unexpected eof token' }],
+        }),
+      }),
+    )
+
+    const result = await evaluateRegoPolicy('/repo/policy.rego', {})
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.cause).toBe(
+      'rego_parse_error: unexpected eof token',
+    )
+  })
+
+  it('fails when the policy does not define the package', async () => {
+    mockSpawn.mockResolvedValue({ stdout: '{}' })
+
+    const result = await evaluateRegoPolicy('/repo/policy.rego', {})
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.cause).toContain(
+      'does not define package socket',
+    )
+  })
+})
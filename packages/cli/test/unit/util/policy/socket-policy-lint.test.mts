@@ -0,0 +1,215 @@
+/**
+ * Unit tests for the `socket.policy.yml` linter.
+ *
+ * Purpose: Tests that policy files are linted field by field and that every
+ * problem is reported with its location.
+ *
+ * Test Coverage: - lintSocketPolicy issues and locations - Exception reasons
+ * and expiry dates - Quarantine windows - Trust lists - Invalid YAML.
+ *
+ * Testing Approach: Lint cases use inline YAML.
+ *
+ * Related Files: - src/util/policy/socket-policy-lint.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import { lintSocketPolicy } from '../../../../src/util/policy/socket-policy-lint.mts'
+
+const VALID_POLICY = `version: 1
+block:
+  alerts: [malware, gitDependency]
+  severity: critical
+warn:
+  alerts: [installScripts]
+licenses:
+  allow: [MIT, Apache-2.0]
+exceptions:
+  - paths: ['tools/**']
+    alerts: [installScripts]
+    reason: Build tooling, never shipped.
+`
+
+describe('lintSocketPolicy', () => {
+  it('accepts a valid policy without issues', () => {
+    const { issues, policy } = lintSocketPolicy(VALID_POLICY)
+
+    expect(issues).toEqual([])
+    expect(policy.block).toEqual({
+      alerts: ['malware', 'gitDependency'],
+      severity: 'critical',
+    })
+    expect(policy.warn).toEqual({ alerts: ['installScripts'] })
+    expect(policy.licenses).toEqual({ allow: ['MIT', 'Apache-2.0'], deny: [] })
+    expect(policy.exceptions).toEqual([
+      {
+        alerts: ['installScripts'],
+        packages: [],
+        paths: ['tools/**'],
+        reason: 'Build tooling, never shipped.',
+      },
+    ])
+  })
+
+  it('warns about unknown keys and a missing version', () => {
+    const { issues } = lintSocketPolicy('blok:\n  alerts: [malware]\n')
+
+    expect(issues).toEqual([
+      {
+        level: 'warning',
+        message: 'Unknown key "blok" is ignored',
+        path: 'blok',
+      },
+      {
+        level: 'warning',
+        message: 'Missing `version`; assuming version 1',
+        path: 'version',
+      },
+    ])
+  })
+
+  it('reports a bad severity with a hint for "medium"', () => {
+    const { issues, policy } = lintSocketPolicy(
+      'version: 1\nwarn:\n  severity: medium\n',
+    )
+
+    expect(issues).toHaveLength(1)
+    expect(issues[0]).toMatchObject({
+      level: 'error',
+      path: 'warn.severity',
+    })
+    expect(issues[0]!.message).toContain('"middle"')
+    expect(policy.warn.severity).toBeUndefined()
+  })
+
+  it('reports a license that is both allowed and denied', () => {
+    const { issues } = lintSocketPolicy(
+      'version: 1\nlicenses:\n  allow: [MIT]\n  deny: [MIT]\n',
+    )
+
+    expect(issues).toEqual([
+      {
+        level: 'error',
+        message: '"MIT" is both allowed and denied',
+        path: 'licenses',
+      },
+    ])
+  })
+
+  it('reads a quarantine window, blocking by default', () => {
+    const { issues, policy } = lintSocketPolicy(
+      'version: 1\nquarantine:\n  days: 7\n',
+    )
+
+    expect(issues).toEqual([])
+    expect(policy.quarantine).toEqual({ action: 'block', days: 7 })
+  })
+
+  it('rejects a quarantine window without positive days', () => {
+    const { issues, policy } = lintSocketPolicy(
+      'version: 1\nquarantine:\n  days: 0\n  action: prompt\n',
+    )
+
+    expect(issues.map(i => i.path)).toEqual([
+      'quarantine.days',
+      'quarantine.action',
+    ])
+    expect(policy.quarantine).toBeUndefined()
+  })
+
+  it('reads trust and distrust lists', () => {
+    const { issues, policy } = lintSocketPolicy(
+      "version: 1\ntrust:\n  packages: ['pkg:npm/@acme/*']\ndistrust:\n  maintainers: [mallory]\n",
+    )
+
+    expect(issues).toEqual([])
+    expect(policy.trust).toEqual({
+      maintainers: [],
+      packages: ['pkg:npm/@acme/*'],
+    })
+    expect(policy.distrust).toEqual({ maintainers: ['mallory'], packages: [] })
+  })
+
+  it('rejects a trust list that matches every package', () => {
+    const { issues, policy } = lintSocketPolicy(
+      "version: 1\ntrust:\n  packages: ['*']\n",
+    )
+
+    expect(issues).toEqual([
+      expect.objectContaining({ level: 'error', path: 'trust.packages' }),
+    ])
+    expect(policy.trust).toBeUndefined()
+  })
+
+  it('rejects an exception that matches everything', () => {
+    const { issues, policy } = lintSocketPolicy(
+      'version: 1\nexceptions:\n  - reason: temporary\n',
+    )
+
+    expect(issues).toHaveLength(1)
+    expect(issues[0]).toMatchObject({ level: 'error', path: 'exceptions[0]' })
+    expect(policy.exceptions).toEqual([])
+  })
+
+  it('requires a reason on exceptions', () => {
+    const { issues, policy } = lintSocketPolicy(
+      "version: 1\nexceptions:\n  - packages: ['pkg:npm/lodash']\n",
+    )
+
+    expect(issues).toEqual([
+      expect.objectContaining({
+        level: 'error',
+        path: 'exceptions[0].reason',
+      }),
+    ])
+    expect(policy.exceptions).toEqual([])
+  })
+
+  it('reads expiry dates, quoted or not, and flags expired ones', () => {
+    const { issues, policy } = lintSocketPolicy(`version: 1
+exceptions:
+  - packages: ['pkg:npm/lodash']
+    reason: Waiting on upstream fix
+    expires: 2999-12-31
+  - packages: ['pkg:npm/left-pad']
+    reason: Old waiver
+    expires: '2000-01-01'
+`)
+
+    expect(policy.exceptions.map(e => e.expires)).toEqual([
+      '2999-12-31',
+      '2000-01-01',
+    ])
+    expect(issues).toEqual([
+      expect.objectContaining({
+        level: 'warning',
+        path: 'exceptions[1].expires',
+      }),
+    ])
+  })
+
+  it('rejects malformed expiry dates', () => {
+    const { issues } = lintSocketPolicy(`version: 1
+exceptions:
+  - alerts: [installScripts]
+    reason: Build tooling
+    expires: next year
+`)
+
+    expect(issues).toEqual([
+      {
+        level: 'error',
+        message: 'Expected a date like 2026-12-31',
+        path: 'exceptions[0].expires',
+      },
+    ])
+  })
+
+  it('reports invalid YAML', () => {
+    const { issues } = lintSocketPolicy('block: [unclosed\n')
+
+    expect(issues).toHaveLength(1)
+    expect(issues[0]!.level).toBe('error')
+    expect(issues[0]!.message).toMatch(/^Invalid YAML/)
+  })
+})
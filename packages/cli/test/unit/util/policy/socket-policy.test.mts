@@ -0,0 +1,103 @@
+/**
+ * Unit tests for the `socket.policy.yml` parser.
+ *
+ * Purpose: Tests that an invalid policy file is rejected rather than
+ * loosened, and that the nearest policy file is found walking up from a
+ * directory.
+ *
+ * Test Coverage: - parseSocketPolicy errors - findSocketPolicySync directory
+ * walk.
+ *
+ * Testing Approach: Parse cases use inline YAML; discovery uses a temp dir.
+ *
+ * Related Files: - src/util/policy/socket-policy.mts (implementation)
+ * - src/util/policy/socket-policy-lint.mts (linter)
+ */
+
+import { mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { describe, expect, it } from 'vitest'
+
+import { safeDelete, safeMkdirSync } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  findSocketPolicySync,
+  parseSocketPolicy,
+} from '../../../../src/util/policy/socket-policy.mts'
+
+const VALID_POLICY = `version: 1
+block:
+  alerts: [malware, gitDependency]
+  severity: critical
+warn:
+  alerts: [installScripts]
+licenses:
+  allow: [MIT, Apache-2.0]
+exceptions:
+  - paths: ['tools/**']
+    alerts: [installScripts]
+    reason: Build tooling, never shipped.
+`
+
+describe('parseSocketPolicy', () => {
+  it('returns the policy when there are only warnings', () => {
+    const result = parseSocketPolicy('warn:\n  alerts: [installScripts]\n')
+
+    expect(result.ok).toBe(true)
+    expect(result.ok && result.data.warn.alerts).toEqual(['installScripts'])
+  })
+
+  it('fails when the linter reports errors', () => {
+    const result = parseSocketPolicy('version: 2\nblock: malware\n')
+
+    expect(result.ok).toBe(false)
+    expect(!result.ok && result.message).toBe('Invalid policy file')
+    expect(!result.ok && result.cause).toContain('version: Unsupported')
+    expect(!result.ok && result.cause).toContain('block: Expected a mapping')
+  })
+})
+
+describe('findSocketPolicySync', () => {
+  it('finds the nearest policy file walking up', async () => {
+    const tmpDir = path.resolve(
+      mkdtempSync(path.join(os.tmpdir(), 'socket-test-')),
+    )
+    const policyPath = path.join(tmpDir, 'socket.policy.yml')
+    const nestedDir = path.join(tmpDir, 'packages', 'app')
+
+    try {
+      safeMkdirSync(nestedDir, { recursive: true })
+      writeFileSync(policyPath, VALID_POLICY, 'utf8')
+
+      const result = findSocketPolicySync(nestedDir)
+
+      expect(result.ok).toBe(true)
+      expect(result.data?.path).toBe(policyPath)
+      expect(result.data?.policy.block.severity).toBe('critical')
+    } finally {
+      await safeDelete(tmpDir, { recursive: true })
+    }
+  })
+
+  it('fails on an invalid policy file instead of ignoring it', async () => {
+    const tmpDir = path.resolve(
+      mkdtempSync(path.join(os.tmpdir(), 'socket-test-')),
+    )
+    const policyPath = path.join(tmpDir, 'socket.policy.yaml')
+
+    try {
+      writeFileSync(policyPath, 'version: 1\nwarn:\n  severity: medium\n')
+
+      const result = findSocketPolicySync(tmpDir)
+
+      expect(result.ok).toBe(false)
+      expect(!result.ok && result.message).toBe(
+        `Invalid policy file ${policyPath}`,
+      )
+    } finally {
+      await safeDelete(tmpDir, { recursive: true })
+    }
+  })
+})
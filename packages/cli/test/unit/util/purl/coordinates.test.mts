@@ -0,0 +1,119 @@
+/**
+ * Unit tests for ecosystem coordinates.
+ *
+ * Purpose: Tests converting between ecosystem coordinates and package URLs,
+ * and splitting purls into their components.
+ *
+ * Related Files: - util/purl/coordinates.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  coordinateToPurl,
+  isCoordinate,
+  normalizePurlOrCoordinate,
+  parsePurlParts,
+  purlToCoordinate,
+} from '../../../../src/util/purl/coordinates.mts'
+
+describe('isCoordinate', () => {
+  it('tells coordinates from purls', () => {
+    expect(isCoordinate('maven:org.example:lib:1.0.0')).toBe(true)
+    expect(isCoordinate('pkg:npm/lodash@4.17.21')).toBe(false)
+    expect(isCoordinate('npm/lodash@4.17.21')).toBe(false)
+  })
+})
+
+describe('coordinateToPurl', () => {
+  it.each([
+    [
+      'maven:org.apache.commons:commons-lang3:3.14.0',
+      'pkg:maven/org.apache.commons/commons-lang3@3.14.0',
+    ],
+    ['maven:org.example:lib', 'pkg:maven/org.example/lib'],
+    ['npm:lodash@4.17.21', 'pkg:npm/lodash@4.17.21'],
+    ['npm:@babel/core@7.24.0', 'pkg:npm/%40babel/core@7.24.0'],
+    ['npm:@babel/core', 'pkg:npm/%40babel/core'],
+    ['pypi:requests==2.31.0', 'pkg:pypi/requests@2.31.0'],
+    [
+      'go:github.com/spf13/cobra@v1.8.0',
+      'pkg:golang/github.com/spf13/cobra@v1.8.0',
+    ],
+  ])('converts %s', (coordinate, purl) => {
+    expect(coordinateToPurl(coordinate)).toEqual({ ok: true, data: purl })
+  })
+
+  it('rejects unknown ecosystems', () => {
+    expect(coordinateToPurl('nope:lodash@1.0.0')).toMatchObject({
+      ok: false,
+      message: 'Invalid coordinate nope:lodash@1.0.0',
+    })
+  })
+
+  it('rejects maven coordinates without an artifact', () => {
+    expect(coordinateToPurl('maven:org.example')).toMatchObject({
+      ok: false,
+      cause: 'Expected maven:<group>:<artifact>[:<version>]',
+    })
+  })
+})
+
+describe('parsePurlParts', () => {
+  it('splits a purl into its components', () => {
+    expect(parsePurlParts('pkg:maven/org.example/lib@1.0.0?type=jar')).toEqual({
+      ok: true,
+      data: {
+        name: 'lib',
+        namespace: 'org.example',
+        purl: 'pkg:maven/org.example/lib@1.0.0?type=jar',
+        qualifiers: { type: 'jar' },
+        type: 'maven',
+        version: '1.0.0',
+      },
+    })
+  })
+
+  it('accepts purls without the pkg: prefix', () => {
+    expect(parsePurlParts('npm/lodash@4.17.21')).toMatchObject({
+      ok: true,
+      data: { purl: 'pkg:npm/lodash@4.17.21' },
+    })
+  })
+
+  it('reports invalid purls', () => {
+    expect(parsePurlParts('pkg:')).toMatchObject({
+      ok: false,
+      message: 'Invalid purl pkg:',
+    })
+  })
+})
+
+describe('purlToCoordinate', () => {
+  it.each([
+    'maven:org.apache.commons:commons-lang3:3.14.0',
+    'npm:@babel/core@7.24.0',
+    'pypi:requests==2.31.0',
+    'golang:github.com/spf13/cobra@v1.8.0',
+  ])('round trips %s', coordinate => {
+    const purlCResult = coordinateToPurl(coordinate)
+    const partsCResult = parsePurlParts(purlCResult.ok ? purlCResult.data : '')
+
+    expect(partsCResult.ok && purlToCoordinate(partsCResult.data)).toBe(
+      coordinate,
+    )
+  })
+})
+
+describe('normalizePurlOrCoordinate', () => {
+  it('normalizes purls and coordinates alike', () => {
+    expect(normalizePurlOrCoordinate('npm/lodash@4.17.21')).toEqual({
+      ok: true,
+      data: 'pkg:npm/lodash@4.17.21',
+    })
+    expect(normalizePurlOrCoordinate('npm:lodash@4.17.21')).toEqual({
+      ok: true,
+      data: 'pkg:npm/lodash@4.17.21',
+    })
+  })
+})
@@ -18,6 +18,7 @@ import { PackageURL } from '@socketregistry/packageurl-js-stable'
 
 import {
   createPurlObject,
+  getArtifactPurlString,
   getPurlObject,
   normalizePurl,
 } from '../../../../src/util/purl/parse.mts'
@@ -209,4 +210,27 @@ describe('purl utilities', () => {
       expect(purl?.version).toBe('4.2')
     })
   })
+
+  describe('getArtifactPurlString', () => {
+    it('builds a purl string from artifact components', () => {
+      expect(
+        getArtifactPurlString({
+          type: 'npm',
+          name: 'lodash',
+          version: '4.17.21',
+        } as Parameters<typeof getArtifactPurlString>[0]),
+      ).toBe('pkg:npm/lodash@4.17.21')
+    })
+
+    it('encodes scoped namespaces', () => {
+      expect(
+        getArtifactPurlString({
+          type: 'npm',
+          namespace: '@babel',
+          name: 'core',
+          version: '7.0.0',
+        } as Parameters<typeof getArtifactPurlString>[0]),
+      ).toBe('pkg:npm/%40babel/core@7.0.0')
+    })
+  })
 })
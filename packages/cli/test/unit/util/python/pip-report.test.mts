@@ -0,0 +1,113 @@
+/**
+ * Unit tests for pip installation report resolution.
+ *
+ * Purpose: Tests turning `pip install --dry-run --report -` output into pypi
+ * purls and the failure modes of running pip.
+ *
+ * Test Coverage: - PEP 503 name normalization - Report parsing and dedupe -
+ * Malformed reports - Spawned argv - Spawn failures and non-JSON output.
+ *
+ * Related Files: - src/util/python/pip-report.mts (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  getPipReportPurls,
+  normalizePypiName,
+  resolvePipInstall,
+} from '../../../../src/util/python/pip-report.mts'
+
+const mockSpawn = vi.hoisted(() => vi.fn())
+
+vi.mock(import('@socketsecurity/lib-stable/process/spawn/child'), () => ({
+  spawn: mockSpawn,
+}))
+
+describe('normalizePypiName', () => {
+  it('lowercases and collapses separators', () => {
+    expect(normalizePypiName('Flask_SQLAlchemy')).toBe('flask-sqlalchemy')
+    expect(normalizePypiName('zope.interface')).toBe('zope-interface')
+    expect(normalizePypiName('a-_.b')).toBe('a-b')
+  })
+})
+
+describe('getPipReportPurls', () => {
+  it('maps installed distributions to purls', () => {
+    expect(
+      getPipReportPurls({
+        install: [
+          { metadata: { name: 'Requests', version: '2.32.3' } },
+          { metadata: { name: 'idna', version: '3.7' } },
+          { metadata: { name: 'requests', version: '2.32.3' } },
+          { metadata: { name: 'broken' } },
+        ],
+      }),
+    ).toEqual(['pkg:pypi/requests@2.32.3', 'pkg:pypi/idna@3.7'])
+  })
+
+  it('returns an empty list for malformed reports', () => {
+    expect(getPipReportPurls(undefined)).toEqual([])
+    expect(getPipReportPurls({ install: 'nope' })).toEqual([])
+  })
+})
+
+describe('resolvePipInstall', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('runs a dry-run install with a JSON report', async () => {
+    mockSpawn.mockResolvedValue({
+      stdout: JSON.stringify({
+        install: [{ metadata: { name: 'black', version: '24.4.2' } }],
+      }),
+    })
+
+    const result = await resolvePipInstall(
+      ['python3', '-m', 'pip'],
+      ['black'],
+      { cwd: '/tmp', ignoreInstalled: true },
+    )
+
+    expect(result).toEqual({ ok: true, data: ['pkg:pypi/black@24.4.2'] })
+    expect(mockSpawn).toHaveBeenCalledWith(
+      'python3',
+      [
+        '-m',
+        'pip',
+        'install',
+        '--dry-run',
+        '--quiet',
+        '--report',
+        '-',
+        '--ignore-installed',
+        'black',
+      ],
+      { cwd: '/tmp', stdio: 'pipe' },
+    )
+  })
+
+  it('fails when pip exits with an error', async () => {
+    mockSpawn.mockRejectedValue(new Error('No matching distribution'))
+
+    const result = await resolvePipInstall(['pip'], ['nope'])
+
+    expect(result.ok).toBe(false)
+    if (!result.ok) {
+      expect(result.message).toBe('Unable to resolve packages')
+      expect(result.cause).toContain('pip 22.2 or newer is required')
+    }
+  })
+
+  it('fails when pip prints no report', async () => {
+    mockSpawn.mockResolvedValue({ stdout: 'no such option: --report' })
+
+    const result = await resolvePipInstall(['pip'], ['black'])
+
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Unable to resolve packages',
+    })
+  })
+})
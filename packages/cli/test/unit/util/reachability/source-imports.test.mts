@@ -0,0 +1,122 @@
+/**
+ * Unit tests for reading package imports from project sources.
+ *
+ * Purpose: Tests that the import specifiers of JavaScript and TypeScript
+ * sources are collected and mapped to the packages they import, for the
+ * import reachability of `socket scan create --reach`.
+ *
+ * Test Coverage: - Static imports, re-exports, dynamic imports and requires -
+ * Type only imports and comments - Package names of bare, scoped, relative,
+ * builtin and URL specifiers - Finding imported packages under a directory.
+ *
+ * Related Files: - src/util/reachability/source-imports.mts (implementation) -
+ * src/commands/scan/import-reachability.mts - Reachability of scan packages.
+ */
+
+import { mkdirSync, mkdtempSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  findImportedPackages,
+  getImportSpecifiers,
+  getImportedPackageName,
+} from '../../../../src/util/reachability/source-imports.mts'
+
+describe('getImportSpecifiers', () => {
+  it('collects static imports and re-exports', () => {
+    const content = `
+import a from 'a'
+import { b1, b2 } from "b"
+import * as c from 'c'
+import 'd'
+export { e } from 'e'
+export * from 'f'
+`
+    expect(getImportSpecifiers(content)).toEqual(['a', 'b', 'c', 'd', 'e', 'f'])
+  })
+
+  it('collects dynamic imports and requires', () => {
+    const content = `
+const g = require('g')
+const h = await import('h')
+const i = require.resolve(\`i/package.json\`)
+const j = require(name)
+const k = import(\`k/\${sub}\`)
+`
+    expect(getImportSpecifiers(content)).toEqual(['g', 'h', 'i/package.json'])
+  })
+
+  it('skips type only imports and comments', () => {
+    const content = `
+import type { A } from 'types-only'
+// import x from 'line-comment'
+/* require('block-comment') */
+const url = 'https://example.com/x' // require('trailing')
+import real from 'real'
+`
+    expect(getImportSpecifiers(content)).toEqual(['real'])
+  })
+})
+
+describe('getImportedPackageName', () => {
+  it('returns the package of bare specifiers', () => {
+    expect(getImportedPackageName('lodash')).toBe('lodash')
+    expect(getImportedPackageName('lodash/fp/map')).toBe('lodash')
+    expect(getImportedPackageName('@scope/pkg')).toBe('@scope/pkg')
+    expect(getImportedPackageName('@scope/pkg/sub')).toBe('@scope/pkg')
+  })
+
+  it('returns undefined for specifiers that are not packages', () => {
+    expect(getImportedPackageName('./local')).toBeUndefined()
+    expect(getImportedPackageName('../up')).toBeUndefined()
+    expect(getImportedPackageName('/abs/path')).toBeUndefined()
+    expect(getImportedPackageName('#internal')).toBeUndefined()
+    expect(getImportedPackageName('@/components')).toBeUndefined()
+    expect(getImportedPackageName('fs')).toBeUndefined()
+    expect(getImportedPackageName('fs/promises')).toBeUndefined()
+    expect(getImportedPackageName('node:path')).toBeUndefined()
+    expect(getImportedPackageName('https://esm.sh/react')).toBeUndefined()
+  })
+})
+
+describe('findImportedPackages', () => {
+  let tmpDir: string
+
+  beforeEach(() => {
+    tmpDir = path.resolve(mkdtempSync(path.join(os.tmpdir(), 'socket-test-')))
+  })
+
+  afterEach(async () => {
+    await safeDelete(tmpDir, { recursive: true })
+  })
+
+  it('reads the sources under cwd, skipping node_modules', async () => {
+    mkdirSync(path.join(tmpDir, 'src'))
+    mkdirSync(path.join(tmpDir, 'node_modules/dep'), { recursive: true })
+    writeFileSync(
+      path.join(tmpDir, 'src/index.ts'),
+      "import express from 'express'\nimport { x } from './x'\n",
+      'utf8',
+    )
+    writeFileSync(
+      path.join(tmpDir, 'src/util.cjs'),
+      "const qs = require('@scope/qs/lib')\n",
+      'utf8',
+    )
+    writeFileSync(
+      path.join(tmpDir, 'node_modules/dep/index.js'),
+      "require('hidden')\n",
+      'utf8',
+    )
+    writeFileSync(path.join(tmpDir, 'README.md'), "import 'docs'\n", 'utf8')
+
+    const names = await findImportedPackages(tmpDir)
+
+    expect([...names].sort()).toEqual(['@scope/qs', 'express'])
+  })
+})
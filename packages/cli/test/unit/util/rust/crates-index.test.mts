@@ -0,0 +1,115 @@
+/**
+ * Unit tests for crates.io sparse index resolution.
+ *
+ * Purpose: Tests index path layout, cargo requirement translation and picking
+ * the version cargo would select.
+ *
+ * Test Coverage: - Index paths for 1, 2, 3 and 4+ character names - Caret
+ * defaults and comma separated comparators - Yanked and malformed index lines
+ * - Highest matching version - Missing crates and HTTP failures.
+ *
+ * Related Files: - src/util/rust/crates-index.mts (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  cargoReqToSemverRange,
+  getCratesIndexPath,
+  parseCratesIndexVersions,
+  resolveCrateVersion,
+} from '../../../../src/util/rust/crates-index.mts'
+
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  socketHttpRequest: mockSocketHttpRequest,
+}))
+
+const INDEX = [
+  '{"name":"serde","vers":"0.9.15","yanked":false}',
+  '{"name":"serde","vers":"1.0.0","yanked":false}',
+  '{"name":"serde","vers":"1.0.203","yanked":false}',
+  '{"name":"serde","vers":"1.0.204","yanked":true}',
+  '{"name":"serde","vers":"2.0.0-alpha.1","yanked":false}',
+  'not json',
+  '',
+].join('\n')
+
+describe('getCratesIndexPath', () => {
+  it('follows the registry index layout', () => {
+    expect(getCratesIndexPath('a')).toBe('1/a')
+    expect(getCratesIndexPath('cc')).toBe('2/cc')
+    expect(getCratesIndexPath('syn')).toBe('3/s/syn')
+    expect(getCratesIndexPath('Serde')).toBe('se/rd/serde')
+  })
+})
+
+describe('cargoReqToSemverRange', () => {
+  it('treats bare versions as caret requirements', () => {
+    expect(cargoReqToSemverRange('1.2')).toBe('^1.2')
+    expect(cargoReqToSemverRange('>=1, <1.5')).toBe('>=1 <1.5')
+    expect(cargoReqToSemverRange('=0.4.2')).toBe('=0.4.2')
+    expect(cargoReqToSemverRange(undefined)).toBe('*')
+  })
+})
+
+describe('parseCratesIndexVersions', () => {
+  it('skips yanked and malformed records', () => {
+    expect(parseCratesIndexVersions(INDEX)).toEqual([
+      '0.9.15',
+      '1.0.0',
+      '1.0.203',
+      '2.0.0-alpha.1',
+    ])
+  })
+})
+
+describe('resolveCrateVersion', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  it('picks the highest matching stable version', async () => {
+    mockSocketHttpRequest.mockResolvedValue({
+      ok: true,
+      status: 200,
+      text: () => INDEX,
+    })
+
+    expect(await resolveCrateVersion('serde')).toEqual({
+      ok: true,
+      data: '1.0.203',
+    })
+    expect(await resolveCrateVersion('serde', '0.9')).toEqual({
+      ok: true,
+      data: '0.9.15',
+    })
+    expect(mockSocketHttpRequest).toHaveBeenCalledWith(
+      'https://index.crates.io/se/rd/serde',
+    )
+  })
+
+  it('resolves to undefined for unknown crates', async () => {
+    mockSocketHttpRequest.mockResolvedValue({ ok: false, status: 404 })
+
+    expect(await resolveCrateVersion('nope-nope')).toEqual({
+      ok: true,
+      data: undefined,
+    })
+  })
+
+  it('fails on HTTP and network errors', async () => {
+    mockSocketHttpRequest.mockResolvedValueOnce({ ok: false, status: 503 })
+    mockSocketHttpRequest.mockRejectedValueOnce(new Error('ECONNRESET'))
+
+    expect(await resolveCrateVersion('serde')).toMatchObject({
+      ok: false,
+      message: 'Unable to resolve crate',
+    })
+    expect(await resolveCrateVersion('serde')).toMatchObject({
+      ok: false,
+      message: 'Unable to resolve crate',
+    })
+  })
+})
@@ -0,0 +1,62 @@
+/**
+ * Unit tests for the in-toto statements and DSSE envelopes of `socket scan
+ * attest`.
+ *
+ * Related Files: - src/util/sigstore/dsse.mts (implementation) -
+ * src/util/sigstore/keyless.mts (consumer)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  IN_TOTO_PAYLOAD_TYPE,
+  IN_TOTO_STATEMENT_TYPE,
+  createDsseEnvelope,
+  createInTotoStatement,
+  dssePae,
+} from '../../../../src/util/sigstore/dsse.mts'
+
+describe('dssePae', () => {
+  it('encodes the type and body with their byte lengths', () => {
+    const pae = dssePae(
+      'http://example.com/HelloWorld',
+      Buffer.from('hello world'),
+    )
+    expect(pae.toString()).toBe(
+      'DSSEv1 29 http://example.com/HelloWorld 11 hello world',
+    )
+  })
+
+  it('counts bytes rather than characters', () => {
+    expect(dssePae('t', Buffer.from('é')).toString()).toBe('DSSEv1 1 t 2 é')
+  })
+})
+
+describe('createDsseEnvelope', () => {
+  it('signs the PAE of the statement', () => {
+    const statement = createInTotoStatement(
+      [{ digest: { gitCommit: 'abc123' }, name: 'acme/api' }],
+      'https://example.com/predicate/v1',
+      { passed: true },
+    )
+    const signed: Buffer[] = []
+
+    const envelope = createDsseEnvelope(statement, pae => {
+      signed.push(pae)
+      return Buffer.from('signature')
+    })
+
+    const payload = Buffer.from(envelope.payload, 'base64')
+    expect(JSON.parse(payload.toString())).toEqual({
+      _type: IN_TOTO_STATEMENT_TYPE,
+      predicate: { passed: true },
+      predicateType: 'https://example.com/predicate/v1',
+      subject: [{ digest: { gitCommit: 'abc123' }, name: 'acme/api' }],
+    })
+    expect(envelope.payloadType).toBe(IN_TOTO_PAYLOAD_TYPE)
+    expect(signed).toEqual([dssePae(IN_TOTO_PAYLOAD_TYPE, payload)])
+    expect(envelope.signatures).toEqual([
+      { keyid: '', sig: Buffer.from('signature').toString('base64') },
+    ])
+  })
+})
@@ -0,0 +1,216 @@
+/**
+ * Unit tests for the Sigstore keyless signing of `socket scan attest`.
+ *
+ * Tests the Fulcio proof of possession, the Rekor DSSE entry and the bundle
+ * assembled from their responses, with the HTTP requests mocked.
+ *
+ * Related Files: - src/util/sigstore/keyless.mts (implementation) -
+ * src/commands/scan/handle-scan-attest.mts (consumer)
+ */
+
+import { createPublicKey, verify } from 'node:crypto'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  socketHttpRequest: mockSocketHttpRequest,
+  tryReadResponseText: (response: { text: () => string }) => response.text(),
+}))
+
+import {
+  IN_TOTO_PAYLOAD_TYPE,
+  createInTotoStatement,
+  dssePae,
+} from '../../../../src/util/sigstore/dsse.mts'
+import {
+  SIGSTORE_BUNDLE_MEDIA_TYPE,
+  getIdentityTokenChallenge,
+  signStatementKeyless,
+} from '../../../../src/util/sigstore/keyless.mts'
+
+function jwt(claims: Record<string, unknown>): string {
+  const encode = (value: unknown) =>
+    Buffer.from(JSON.stringify(value)).toString('base64url')
+  return `${encode({ alg: 'RS256' })}.${encode(claims)}.signature`
+}
+
+function jsonResponse(status: number, body: unknown) {
+  return {
+    ok: status < 300,
+    status,
+    statusText: status < 300 ? 'Created' : 'Bad Request',
+    text: () => JSON.stringify(body),
+  }
+}
+
+const CERTIFICATE_PEM =
+  '-----BEGIN CERTIFICATE-----\nTUlJQ2VydA==\n-----END CERTIFICATE-----\n'
+
+const statement = createInTotoStatement(
+  [{ digest: { gitCommit: 'abc123' }, name: 'acme/api' }],
+  'https://socket.dev/attestation/scan/v1',
+  { result: { passed: true } },
+)
+
+const identityToken = jwt({
+  iss: 'https://token.actions.githubusercontent.com',
+  sub: 'repo:acme/api:ref:refs/heads/main',
+})
+
+describe('getIdentityTokenChallenge', () => {
+  it('is the subject of the token', () => {
+    expect(getIdentityTokenChallenge(identityToken)).toBe(
+      'repo:acme/api:ref:refs/heads/main',
+    )
+  })
+
+  it('is the email for email based issuers', () => {
+    const token = jwt({
+      email: 'jdoe@example.com',
+      iss: 'https://oauth2.sigstore.dev/auth',
+      sub: '12345',
+    })
+    expect(getIdentityTokenChallenge(token)).toBe('jdoe@example.com')
+  })
+
+  it('is empty for a token that is not a JWT', () => {
+    expect(getIdentityTokenChallenge('not-a-jwt')).toBe('')
+  })
+})
+
+describe('signStatementKeyless', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockSocketHttpRequest.mockImplementation(async (url: string) =>
+      url.endsWith('/api/v2/signingCert')
+        ? jsonResponse(201, {
+            signedCertificateEmbeddedSct: {
+              chain: { certificates: [CERTIFICATE_PEM, 'root'] },
+            },
+          })
+        : jsonResponse(201, {
+            'entry-uuid': {
+              body: 'Y2Fub25pY2FsaXplZA==',
+              integratedTime: 1_700_000_000,
+              logID: 'c0d23d6a',
+              logIndex: 42,
+              verification: {
+                inclusionProof: {
+                  checkpoint: 'rekor.sigstore.dev - 1\n',
+                  hashes: ['abcd'],
+                  logIndex: 41,
+                  rootHash: 'ef01',
+                  treeSize: 100,
+                },
+                signedEntryTimestamp: 'c2V0',
+              },
+            },
+          }),
+    )
+  })
+
+  it('signs with Fulcio and records the entry in Rekor', async () => {
+    const result = await signStatementKeyless(statement, { identityToken })
+
+    expect(mockSocketHttpRequest).toHaveBeenCalledTimes(2)
+    const [fulcioUrl, fulcioRequest] = mockSocketHttpRequest.mock.calls[0]!
+    expect(fulcioUrl).toBe('https://fulcio.sigstore.dev/api/v2/signingCert')
+    const { credentials, publicKeyRequest } = JSON.parse(fulcioRequest.body)
+    expect(credentials).toEqual({ oidcIdentityToken: identityToken })
+    const publicKey = createPublicKey(publicKeyRequest.publicKey.content)
+    expect(
+      verify(
+        'sha256',
+        Buffer.from('repo:acme/api:ref:refs/heads/main'),
+        publicKey,
+        Buffer.from(publicKeyRequest.proofOfPossession, 'base64'),
+      ),
+    ).toBe(true)
+
+    const [rekorUrl, rekorRequest] = mockSocketHttpRequest.mock.calls[1]!
+    expect(rekorUrl).toBe('https://rekor.sigstore.dev/api/v1/log/entries')
+    const { proposedContent } = JSON.parse(rekorRequest.body).spec
+    expect(proposedContent.verifiers).toEqual([
+      Buffer.from(CERTIFICATE_PEM).toString('base64'),
+    ])
+    const envelope = JSON.parse(proposedContent.envelope)
+    const payload = Buffer.from(envelope.payload, 'base64')
+    expect(
+      verify(
+        'sha256',
+        dssePae(IN_TOTO_PAYLOAD_TYPE, payload),
+        publicKey,
+        Buffer.from(envelope.signatures[0].sig, 'base64'),
+      ),
+    ).toBe(true)
+
+    expect(result.ok).toBe(true)
+    if (!result.ok) {
+      return
+    }
+    expect(result.data.logIndex).toBe(42)
+    expect(result.data.bundle).toEqual({
+      dsseEnvelope: envelope,
+      mediaType: SIGSTORE_BUNDLE_MEDIA_TYPE,
+      verificationMaterial: {
+        certificate: { rawBytes: 'TUlJQ2VydA==' },
+        tlogEntries: [
+          {
+            canonicalizedBody: 'Y2Fub25pY2FsaXplZA==',
+            inclusionPromise: { signedEntryTimestamp: 'c2V0' },
+            inclusionProof: {
+              checkpoint: { envelope: 'rekor.sigstore.dev - 1\n' },
+              hashes: [Buffer.from('abcd', 'hex').toString('base64')],
+              logIndex: '41',
+              rootHash: Buffer.from('ef01', 'hex').toString('base64'),
+              treeSize: '100',
+            },
+            integratedTime: '1700000000',
+            kindVersion: { kind: 'dsse', version: '0.0.1' },
+            logId: { keyId: Buffer.from('c0d23d6a', 'hex').toString('base64') },
+            logIndex: '42',
+          },
+        ],
+      },
+    })
+  })
+
+  it('uses a private Sigstore instance', async () => {
+    await signStatementKeyless(statement, {
+      fulcioUrl: 'https://fulcio.example.test',
+      identityToken,
+      rekorUrl: 'https://rekor.example.test',
+    })
+
+    expect(mockSocketHttpRequest.mock.calls.map(([url]) => url)).toEqual([
+      'https://fulcio.example.test/api/v2/signingCert',
+      'https://rekor.example.test/api/v1/log/entries',
+    ])
+  })
+
+  it('reports the Fulcio error message', async () => {
+    mockSocketHttpRequest.mockResolvedValue(
+      jsonResponse(400, { message: 'invalid identity token' }),
+    )
+
+    const result = await signStatementKeyless(statement, { identityToken })
+
+    expect(result).toEqual({
+      ok: false,
+      message: 'Fulcio request failed',
+      cause:
+        'https://fulcio.sigstore.dev/api/v2/signingCert answered with 400 Bad Request: invalid identity token',
+      data: { code: 400 },
+    })
+  })
+
+  it('rejects a token without a subject', async () => {
+    const result = await signStatementKeyless(statement, {
+      identityToken: 'not-a-jwt',
+    })
+
+    expect(result.ok).toBe(false)
+    expect(mockSocketHttpRequest).not.toHaveBeenCalled()
+  })
+})
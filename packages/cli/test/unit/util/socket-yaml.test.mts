@@ -0,0 +1,81 @@
+/**
+ * Unit tests for socket.yml merging.
+ *
+ * Purpose: Tests how a nested socket.yml inherits and overrides the config
+ * of a parent directory.
+ *
+ * Test Coverage: - Rebasing anchored projectIgnorePaths - Per-key overrides
+ * of issueRules and wrapper alerts - The inherit key.
+ *
+ * Related Files: - util/socket-yaml.mts (implementation)
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  mergeSocketYml,
+  parseSocketConfig,
+  rebaseProjectIgnorePath,
+} from '../../../src/util/socket-yaml.mts'
+
+describe('util/socket-yaml', () => {
+  describe('rebaseProjectIgnorePath', () => {
+    it('should keep patterns that match at any depth', () => {
+      expect(rebaseProjectIgnorePath('dist/', 'packages/foo')).toBe('dist/')
+      expect(rebaseProjectIgnorePath('!*.log', 'packages/foo')).toBe('!*.log')
+    })
+
+    it('should rebase anchored patterns inside the directory', () => {
+      expect(
+        rebaseProjectIgnorePath('packages/foo/test/fixtures/', 'packages/foo'),
+      ).toBe('/test/fixtures/')
+      expect(rebaseProjectIgnorePath('!packages/*/docs', 'packages/foo')).toBe(
+        '!/docs',
+      )
+      expect(rebaseProjectIgnorePath('packages/**/dist', 'packages/foo')).toBe(
+        '/**/dist',
+      )
+      expect(rebaseProjectIgnorePath('/packages', 'packages/foo')).toBe('**')
+    })
+
+    it('should drop anchored patterns outside the directory', () => {
+      expect(
+        rebaseProjectIgnorePath('packages/bar/dist', 'packages/foo'),
+      ).toBeUndefined()
+      expect(rebaseProjectIgnorePath('/docs', 'packages/foo')).toBeUndefined()
+    })
+  })
+
+  describe('mergeSocketYml', () => {
+    it('should let the child override keys of the parent', () => {
+      const parent = parseSocketConfig(
+        'version: 2\ncli:\n  org: acme\nwrapper:\n  nonInteractive: block\n  alerts:\n    installScripts: prompt\n    telemetry: warn\n',
+      )
+      const child = parseSocketConfig(
+        'version: 2\nwrapper:\n  alerts:\n    installScripts: allow\n',
+      )
+      expect(mergeSocketYml(parent, child, 'packages/foo')).toEqual({
+        cli: { defaultOrg: 'acme' },
+        githubApp: {},
+        issueRules: {},
+        projectIgnorePaths: [],
+        version: 2,
+        wrapper: {
+          alerts: { installScripts: 'allow', telemetry: 'warn' },
+          nonInteractive: 'block',
+        },
+      })
+    })
+  })
+
+  describe('parseSocketConfig', () => {
+    it('should only keep inherit when it is false', () => {
+      expect(parseSocketConfig('version: 2\ninherit: false\n').inherit).toBe(
+        false,
+      )
+      expect(
+        parseSocketConfig('version: 2\ninherit: true\n'),
+      ).not.toHaveProperty('inherit')
+    })
+  })
+})
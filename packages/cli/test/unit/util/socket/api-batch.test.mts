@@ -0,0 +1,153 @@
+/**
+ * Unit tests for batched Socket API lookups.
+ *
+ * Purpose: Tests that long lookups are split into batches, run through a
+ * bounded pool of concurrent requests and retried when rate limited.
+ *
+ * Test Coverage: - Batch splitting and result order - Concurrency limit -
+ * Retrying 429 responses with a smaller pool - Giving up after the retry
+ * limit - Failing with the first failed batch - Thrown errors.
+ *
+ * Related Files: - src/util/socket/api-batch.mts (implementation)
+ * - src/util/socket/rate-limit.mts (rate-limit state)
+ */
+
+import { setTimeout as sleep } from 'node:timers/promises'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  chunkItems,
+  isRateLimitedResult,
+  runApiBatches,
+} from '../../../../src/util/socket/api-batch.mts'
+import { resetRateLimit } from '../../../../src/util/socket/rate-limit.mts'
+
+import type { CResult } from '../../../../src/types.mts'
+
+const rateLimited = {
+  ok: false,
+  message: 'Socket API error',
+  data: { code: 429 },
+} as const
+
+describe('api batch', () => {
+  beforeEach(() => {
+    resetRateLimit()
+  })
+
+  it('chunks items', () => {
+    expect(chunkItems([1, 2, 3, 4, 5], 2)).toEqual([[1, 2], [3, 4], [5]])
+    expect(chunkItems([], 2)).toEqual([])
+  })
+
+  it('recognizes rate-limited results', () => {
+    expect(isRateLimitedResult(rateLimited)).toBe(true)
+    expect(isRateLimitedResult({ ok: false, message: 'x', code: 429 })).toBe(
+      true,
+    )
+    expect(
+      isRateLimitedResult({ ok: false, message: 'x', data: { code: 400 } }),
+    ).toBe(false)
+    expect(isRateLimitedResult({ ok: true, data: [] })).toBe(false)
+  })
+
+  it('keeps results in item order', async () => {
+    const items = Array.from({ length: 10 }, (_, i) => i)
+    const call = vi.fn(async (batch: number[], offset: number) => {
+      // Finish later batches first.
+      await sleep(10 - offset)
+      return { ok: true, data: batch.map(n => n * 2) } as const
+    })
+
+    const result = await runApiBatches(items, call, {
+      batchSize: 3,
+      concurrency: 4,
+    })
+
+    expect(call).toHaveBeenCalledTimes(4)
+    expect(call).toHaveBeenCalledWith([9], 9)
+    expect(result).toEqual({ ok: true, data: items.map(n => n * 2) })
+  })
+
+  it('keeps at most the configured number of requests in flight', async () => {
+    let active = 0
+    let maxActive = 0
+    const result = await runApiBatches(
+      Array.from({ length: 20 }, (_, i) => i),
+      async batch => {
+        active += 1
+        maxActive = Math.max(maxActive, active)
+        await sleep(5)
+        active -= 1
+        return { ok: true, data: batch }
+      },
+      { batchSize: 1, concurrency: 3 },
+    )
+
+    expect(result.ok).toBe(true)
+    expect(maxActive).toBe(3)
+  })
+
+  it('retries rate-limited batches', async () => {
+    const attempts = new Map<number, number>()
+    const call = vi.fn(
+      async (batch: number[], offset: number): Promise<CResult<number[]>> => {
+        const attempt = (attempts.get(offset) ?? 0) + 1
+        attempts.set(offset, attempt)
+        return offset === 2 && attempt < 3
+          ? rateLimited
+          : { ok: true, data: batch }
+      },
+    )
+
+    const result = await runApiBatches([0, 1, 2, 3], call, {
+      batchSize: 2,
+      retryDelayMs: 1,
+    })
+
+    expect(result).toEqual({ ok: true, data: [0, 1, 2, 3] })
+    expect(attempts.get(2)).toBe(3)
+  })
+
+  it('gives up after the retry limit', async () => {
+    const call = vi.fn(async () => rateLimited)
+
+    const result = await runApiBatches([0], call, {
+      maxRetries: 2,
+      retryDelayMs: 1,
+    })
+
+    expect(result).toBe(rateLimited)
+    expect(call).toHaveBeenCalledTimes(3)
+  })
+
+  it('fails with the first failed batch', async () => {
+    const failure = { ok: false, message: 'Socket API error' } as const
+    const call = vi.fn(
+      async (batch: number[], offset: number): Promise<CResult<number[]>> =>
+        offset ? failure : { ok: true, data: batch },
+    )
+
+    const result = await runApiBatches([0, 1, 2], call, {
+      batchSize: 1,
+      concurrency: 1,
+    })
+
+    expect(result).toBe(failure)
+    // No batch starts after the failure.
+    expect(call).toHaveBeenCalledTimes(2)
+  })
+
+  it('turns thrown errors into failures', async () => {
+    const result = await runApiBatches([0], async () => {
+      throw new Error('socket hang up')
+    })
+
+    expect(result).toMatchObject({
+      ok: false,
+      message: 'Socket API error',
+      cause: expect.stringContaining('socket hang up'),
+    })
+  })
+})
@@ -0,0 +1,194 @@
+/**
+ * Unit tests for the Socket API endpoint settings.
+ *
+ * Purpose: Tests how the API base URL is checked and completed and how the
+ * API token is sent with each auth scheme.
+ *
+ * Test Coverage: - Bare origins get the API version appended - Unsupported
+ * API versions and non-http URLs are refused - The --api-base-url flag,
+ * SOCKET_CLI_API_BASE_URL, apiBaseUrl and apiAuthScheme checks - Basic and
+ * Bearer Authorization headers - Replacing the Authorization header through
+ * the agent.
+ *
+ * Related Files: - src/util/socket/api-endpoint.mts (implementation)
+ * - src/util/socket/sdk.mts (SDK agent)
+ * - src/util/cli/with-subcommands.mts (flag parsing)
+ */
+
+import { createServer, get } from 'node:http'
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockGetConfigValueOrUndef = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  getConfigValueOrUndef: mockGetConfigValueOrUndef,
+}))
+
+import {
+  getApiAgent,
+  getApiAuthScheme,
+  getApiAuthorization,
+  getApiBaseUrlFlag,
+  resetApiEndpointFlagsForTesting,
+  resolveApiBaseUrl,
+  setApiEndpointFlags,
+  withApiAuthorization,
+} from '../../../../src/util/socket/api-endpoint.mts'
+
+import type { AddressInfo } from 'node:net'
+
+describe('api endpoint', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    vi.unstubAllEnvs()
+    resetApiEndpointFlagsForTesting()
+    mockGetConfigValueOrUndef.mockReturnValue(undefined)
+  })
+
+  describe('resolveApiBaseUrl', () => {
+    it('appends the API version to a bare origin', () => {
+      expect(resolveApiBaseUrl('https://socket.example.com')).toEqual({
+        ok: true,
+        data: 'https://socket.example.com/v0/',
+      })
+    })
+
+    it('keeps the path of the base URL, adding the trailing slash', () => {
+      expect(resolveApiBaseUrl('https://eu.socket.example/api/v0')).toEqual({
+        ok: true,
+        data: 'https://eu.socket.example/api/v0/',
+      })
+      expect(resolveApiBaseUrl('http://localhost:8866/socket/')).toEqual({
+        ok: true,
+        data: 'http://localhost:8866/socket/',
+      })
+    })
+
+    it('refuses an API version this CLI does not speak', () => {
+      const result = resolveApiBaseUrl('https://socket.example.com/v2/')
+
+      expect(result.ok).toBe(false)
+      if (!result.ok) {
+        expect(result.message).toBe('Unsupported Socket API version')
+        expect(result.cause).toContain('v0')
+      }
+    })
+
+    it('refuses URLs that are not http or https', () => {
+      expect(resolveApiBaseUrl('socket.example.com').ok).toBe(false)
+      expect(resolveApiBaseUrl('ftp://socket.example.com/v0/')).toMatchObject({
+        ok: false,
+        message: 'Invalid Socket API base URL',
+      })
+    })
+  })
+
+  describe('setApiEndpointFlags', () => {
+    it('takes over the resolved --api-base-url', () => {
+      const result = setApiEndpointFlags({
+        apiBaseUrl: 'https://socket.example.com',
+      })
+
+      expect(result.ok).toBe(true)
+      expect(getApiBaseUrlFlag()).toBe('https://socket.example.com/v0/')
+
+      setApiEndpointFlags({})
+      expect(getApiBaseUrlFlag()).toBeUndefined()
+    })
+
+    it('leaves the flag unset on an invalid base URL', () => {
+      setApiEndpointFlags({ apiBaseUrl: 'https://socket.example.com' })
+
+      const result = setApiEndpointFlags({ apiBaseUrl: 'not a url' })
+
+      expect(result.ok).toBe(false)
+      expect(getApiBaseUrlFlag()).toBeUndefined()
+    })
+
+    it('checks SOCKET_CLI_API_BASE_URL without the flag', () => {
+      vi.stubEnv('SOCKET_CLI_API_BASE_URL', 'https://socket.example.com/v2/')
+
+      expect(setApiEndpointFlags({})).toMatchObject({
+        ok: false,
+        message: 'Unsupported Socket API version',
+        cause: expect.stringContaining('from SOCKET_CLI_API_BASE_URL'),
+      })
+      expect(
+        setApiEndpointFlags({ apiBaseUrl: 'https://socket.example.com' }).ok,
+      ).toBe(true)
+    })
+
+    it('checks the apiBaseUrl config value without the flag or env var', () => {
+      mockGetConfigValueOrUndef.mockImplementation((key: string) =>
+        key === 'apiBaseUrl' ? 'socket.example.com/v0/' : undefined,
+      )
+
+      expect(setApiEndpointFlags({})).toMatchObject({
+        ok: false,
+        message: 'Invalid Socket API base URL',
+        cause: expect.stringContaining('from the apiBaseUrl config value'),
+      })
+      expect(getApiBaseUrlFlag()).toBeUndefined()
+    })
+
+    it('rejects an unknown apiAuthScheme', () => {
+      mockGetConfigValueOrUndef.mockReturnValue('digest')
+
+      expect(setApiEndpointFlags({})).toEqual({
+        ok: false,
+        message: 'Invalid apiAuthScheme value',
+        cause: 'Expected basic or bearer (saw: digest)',
+      })
+    })
+  })
+
+  describe('getApiAuthorization', () => {
+    it('sends the token as the Basic auth user by default', () => {
+      expect(getApiAuthScheme()).toBe('basic')
+      expect(getApiAuthorization('sktsec_token')).toBe(
+        `Basic ${btoa('sktsec_token:')}`,
+      )
+    })
+
+    it('sends a Bearer token when configured', () => {
+      mockGetConfigValueOrUndef.mockReturnValue('Bearer')
+
+      expect(getApiAuthScheme()).toBe('bearer')
+      expect(getApiAuthorization('sktsec_token')).toBe('Bearer sktsec_token')
+    })
+  })
+
+  describe('withApiAuthorization', () => {
+    it('replaces the Authorization header of requests through the agent', async () => {
+      const server = createServer((req, res) => {
+        res.end(req.headers.authorization)
+      })
+      await new Promise<void>(resolve => server.listen(0, resolve))
+      const { port } = server.address() as AddressInfo
+      const baseUrl = `http://127.0.0.1:${port}/v0/`
+
+      try {
+        const agent = withApiAuthorization(
+          getApiAgent(baseUrl),
+          'Bearer sktsec_token',
+        )
+        const body = await new Promise<string>((resolve, reject) => {
+          get(
+            baseUrl,
+            { agent, headers: { Authorization: 'Basic c2t0c2VjOg==' } },
+            res => {
+              let data = ''
+              res.on('data', chunk => (data += chunk))
+              res.on('end', () => resolve(data))
+            },
+          ).on('error', reject)
+        })
+
+        expect(body).toBe('Bearer sktsec_token')
+        agent.destroy()
+      } finally {
+        server.close()
+      }
+    })
+  })
+})
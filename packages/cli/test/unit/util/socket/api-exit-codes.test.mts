@@ -0,0 +1,112 @@
+/**
+ * Unit tests for the exit codes of failed Socket API calls.
+ *
+ * Purpose: Tests that failed API calls carry the exit code CI pipelines use to
+ * tell a rejected token from other API errors.
+ *
+ * Test Coverage: - getExitCodeForHttpStatusCode - The code of failed and
+ * thrown handleApiCall results.
+ *
+ * Testing Approach: Passes fake SDK results to handleApiCall.
+ *
+ * Related Files: - util/socket/api.mts (implementation) -
+ * constants/exit-codes.mts - api.test.mts.
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockLogger = vi.hoisted(() => ({
+  error: vi.fn(),
+  fail: vi.fn(),
+  info: vi.fn(),
+  log: vi.fn(),
+  success: vi.fn(),
+  warn: vi.fn(),
+}))
+
+vi.mock(import('@socketsecurity/lib-stable/logger/default'), () => ({
+  getDefaultLogger: () => mockLogger,
+  logger: mockLogger,
+}))
+
+vi.mock(import('../../../../src/util/socket/sdk.mts'), () => ({
+  getDefaultApiToken: vi.fn(),
+  getDefaultProxyUrl: () => undefined,
+  getExtraCaCerts: () => undefined,
+}))
+
+vi.mock(import('../../../../src/util/error/errors.mts'), () => ({
+  buildErrorCause: vi.fn(async (code: number) => `Error code: ${code}`),
+  getNetworkErrorDiagnostics: vi.fn(() => 'Network error diagnostics'),
+}))
+
+import {
+  EXIT_CODE_API_ERROR,
+  EXIT_CODE_AUTH_ERROR,
+} from '../../../../src/constants/exit-codes.mts'
+import {
+  getExitCodeForHttpStatusCode,
+  handleApiCall,
+} from '../../../../src/util/socket/api.mts'
+
+describe('api exit codes', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+  })
+
+  describe('getExitCodeForHttpStatusCode', () => {
+    it('returns the auth error code for rejected tokens', () => {
+      expect(getExitCodeForHttpStatusCode(401)).toBe(EXIT_CODE_AUTH_ERROR)
+      expect(getExitCodeForHttpStatusCode(403)).toBe(EXIT_CODE_AUTH_ERROR)
+    })
+
+    it('returns the API error code for other statuses', () => {
+      expect(getExitCodeForHttpStatusCode(404)).toBe(EXIT_CODE_API_ERROR)
+      expect(getExitCodeForHttpStatusCode(500)).toBe(EXIT_CODE_API_ERROR)
+    })
+  })
+
+  describe('handleApiCall', () => {
+    it('returns the API error code for a failed call', async () => {
+      const result = await handleApiCall(
+        Promise.resolve({
+          success: false,
+          status: 400,
+          error: 'API error',
+        } as unknown),
+      )
+
+      expect(result.ok).toBe(false)
+      if (!result.ok) {
+        expect(result.code).toBe(EXIT_CODE_API_ERROR)
+      }
+    })
+
+    it('returns the auth error code for a rejected token', async () => {
+      const result = await handleApiCall(
+        Promise.resolve({
+          success: false,
+          status: 401,
+          error: 'Unauthorized',
+        } as unknown),
+      )
+
+      expect(result.ok).toBe(false)
+      if (!result.ok) {
+        expect(result.code).toBe(EXIT_CODE_AUTH_ERROR)
+        expect(result.data).toEqual({ code: 401 })
+      }
+    })
+
+    it('returns the API error code when the call throws', async () => {
+      const result = await handleApiCall(
+        Promise.reject(new Error('Network error')),
+      )
+
+      expect(result.ok).toBe(false)
+      if (!result.ok) {
+        expect(result.code).toBe(EXIT_CODE_API_ERROR)
+      }
+    })
+  })
+})
@@ -64,6 +64,7 @@ vi.mock(import('@socketsecurity/lib-stable/spinner/default'), () => ({
 const mockGetDefaultApiToken = vi.hoisted(() => vi.fn())
 vi.mock(import('../../../../src/util/socket/sdk.mts'), () => ({
   getDefaultApiToken: mockGetDefaultApiToken,
+  getDefaultProxyUrl: () => undefined,
   getExtraCaCerts: () => undefined,
 }))
 
@@ -79,26 +80,6 @@ vi.mock(import('@socketsecurity/lib-stable/http-request/request'), () => ({
   httpRequest: mockHttpRequest,
 }))
 
-// Helper to create httpRequest-style response objects (synchronous .text()/.json()).
-function createHttpResponse(opts: {
-  body?: string | undefined
-  ok?: boolean | undefined
-  status?: number | undefined
-  statusText?: string | undefined
-}) {
-  const bodyStr = opts.body ?? ''
-  const bodyBuffer = Buffer.from(bodyStr)
-  return {
-    body: bodyBuffer,
-    headers: {},
-    json: () => JSON.parse(bodyStr),
-    ok: opts.ok ?? true,
-    status: opts.status ?? 200,
-    statusText: opts.statusText ?? 'OK',
-    text: () => bodyStr,
-  }
-}
-
 import { overrideCachedConfig } from '../../../../src/util/config.mts'
 import {
   handleApiCallNoSpinner,
@@ -106,6 +87,7 @@ import {
   queryApiSafeText,
   sendApiRequest,
 } from '../../../../src/util/socket/api.mts'
+import { createHttpResponse } from '../../../helpers/mocks.mts'
 
 describe('api utilities', () => {
   beforeEach(() => {
@@ -13,7 +13,8 @@
  * @socketsecurity/sdk testing utilities for mock responses.
  *
  * Related Files: - util/socket/api.mts (implementation) -
- * api-requests.test.mts.
+ * api-requests.test.mts - api-exit-codes.test.mts (exit codes of failed
+ * calls).
  */
 
 import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
@@ -63,6 +64,7 @@ vi.mock(import('@socketsecurity/lib-stable/spinner/default'), () => ({
 const mockGetDefaultApiToken = vi.hoisted(() => vi.fn())
 vi.mock(import('../../../../src/util/socket/sdk.mts'), () => ({
   getDefaultApiToken: mockGetDefaultApiToken,
+  getDefaultProxyUrl: () => undefined,
   getExtraCaCerts: () => undefined,
 }))
 
@@ -78,26 +80,6 @@ vi.mock(import('@socketsecurity/lib-stable/http-request/request'), () => ({
   httpRequest: mockHttpRequest,
 }))
 
-// Helper to create httpRequest-style response objects (synchronous .text()/.json()).
-function createHttpResponse(opts: {
-  body?: string | undefined
-  ok?: boolean | undefined
-  status?: number | undefined
-  statusText?: string | undefined
-}) {
-  const bodyStr = opts.body ?? ''
-  const bodyBuffer = Buffer.from(bodyStr)
-  return {
-    body: bodyBuffer,
-    headers: {},
-    json: () => JSON.parse(bodyStr),
-    ok: opts.ok ?? true,
-    status: opts.status ?? 200,
-    statusText: opts.statusText ?? 'OK',
-    text: () => bodyStr,
-  }
-}
-
 import { overrideCachedConfig } from '../../../../src/util/config.mts'
 import {
   getDefaultApiBaseUrl,
@@ -107,6 +89,7 @@ import {
   logPermissionsFor403,
   queryApi,
 } from '../../../../src/util/socket/api.mts'
+import { createHttpResponse } from '../../../helpers/mocks.mts'
 
 describe('api utilities', () => {
   beforeEach(() => {
@@ -0,0 +1,133 @@
+/**
+ * Unit tests for proxy and CA certificate settings.
+ *
+ * Purpose: Tests NO_PROXY matching, the CA certificate sources and the
+ * variables exported to spawned tools.
+ *
+ * Test Coverage: - NO_PROXY host, suffix, port, IPv6 and wildcard entries -
+ * --cacert over the caCert config value over SSL_CERT_FILE - Exporting the
+ * proxy and CA without overriding user variables.
+ *
+ * Related Files: - src/util/socket/network.mts (implementation)
+ * - src/util/socket/sdk.mts (API agent)
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockGetConfigValueOrUndef = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  getConfigValueOrUndef: mockGetConfigValueOrUndef,
+}))
+
+import {
+  applyNetworkEnv,
+  getCaCert,
+  isNoProxyUrl,
+  resetNetworkForTesting,
+  setCaCertFlag,
+} from '../../../../src/util/socket/network.mts'
+
+const ENV_KEYS = [
+  'HTTPS_PROXY',
+  'https_proxy',
+  'HTTP_PROXY',
+  'http_proxy',
+  'NODE_EXTRA_CA_CERTS',
+  'SSL_CERT_FILE',
+]
+
+describe('network', () => {
+  const savedEnv = { ...process.env }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    resetNetworkForTesting()
+    for (const key of ENV_KEYS) {
+      delete process.env[key]
+    }
+  })
+
+  afterEach(() => {
+    for (const key of ENV_KEYS) {
+      if (savedEnv[key] === undefined) {
+        delete process.env[key]
+      } else {
+        process.env[key] = savedEnv[key]
+      }
+    }
+  })
+
+  describe('isNoProxyUrl', () => {
+    it('matches hosts and their subdomains', () => {
+      const noProxy = 'localhost, .corp.example,socket.internal'
+      expect(isNoProxyUrl('http://localhost:3000/', noProxy)).toBe(true)
+      expect(isNoProxyUrl('https://api.corp.example/v0/', noProxy)).toBe(true)
+      expect(isNoProxyUrl('https://corp.example/', noProxy)).toBe(true)
+      expect(isNoProxyUrl('https://a.socket.internal/', noProxy)).toBe(true)
+      expect(isNoProxyUrl('https://api.socket.dev/v0/', noProxy)).toBe(false)
+      expect(isNoProxyUrl('https://notcorp.example/', noProxy)).toBe(false)
+    })
+
+    it('matches ports and IPv6 addresses', () => {
+      expect(isNoProxyUrl('https://corp.example/', 'corp.example:443')).toBe(
+        true,
+      )
+      expect(isNoProxyUrl('https://corp.example/', 'corp.example:8443')).toBe(
+        false,
+      )
+      expect(isNoProxyUrl('http://[::1]:8080/', '[::1]:8080')).toBe(true)
+      expect(isNoProxyUrl('http://[::1]:8080/', '::1')).toBe(true)
+    })
+
+    it('matches everything with a wildcard and nothing when empty', () => {
+      expect(isNoProxyUrl('https://api.socket.dev/', '*')).toBe(true)
+      expect(isNoProxyUrl('https://api.socket.dev/', '')).toBe(false)
+      expect(isNoProxyUrl('not a url', '*')).toBe(false)
+    })
+  })
+
+  describe('getCaCert', () => {
+    it('prefers --cacert over config over SSL_CERT_FILE', () => {
+      process.env['SSL_CERT_FILE'] = '/etc/ssl/env.pem'
+      expect(getCaCert()).toEqual({
+        path: '/etc/ssl/env.pem',
+        source: 'SSL_CERT_FILE',
+      })
+
+      mockGetConfigValueOrUndef.mockReturnValue('/etc/ssl/config.pem')
+      expect(getCaCert()).toEqual({
+        path: '/etc/ssl/config.pem',
+        source: 'config',
+      })
+
+      setCaCertFlag('./flag.pem')
+      expect(getCaCert()).toEqual({ path: './flag.pem', source: 'flag' })
+    })
+
+    it('returns undefined without a CA certificate', () => {
+      expect(getCaCert()).toBeUndefined()
+    })
+  })
+
+  describe('applyNetworkEnv', () => {
+    it('exports the proxy and CA certificate for spawned tools', () => {
+      setCaCertFlag('/etc/ssl/corp.pem')
+
+      applyNetworkEnv('http://proxy.corp.example:3128')
+
+      expect(process.env['HTTPS_PROXY']).toBe('http://proxy.corp.example:3128')
+      expect(process.env['HTTP_PROXY']).toBe('http://proxy.corp.example:3128')
+      expect(process.env['NODE_EXTRA_CA_CERTS']).toBe('/etc/ssl/corp.pem')
+    })
+
+    it('keeps proxy variables the user set', () => {
+      process.env['https_proxy'] = 'http://user-proxy:8080'
+
+      applyNetworkEnv('http://proxy.corp.example:3128')
+
+      expect(process.env['HTTPS_PROXY']).toBeUndefined()
+      expect(process.env['HTTP_PROXY']).toBeUndefined()
+      expect(process.env['NODE_EXTRA_CA_CERTS']).toBeUndefined()
+    })
+  })
+})
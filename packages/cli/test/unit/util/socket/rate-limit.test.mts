@@ -0,0 +1,105 @@
+/**
+ * Unit tests for Socket API rate-limit tracking.
+ *
+ * Purpose: Tests how rate-limit response headers pause batched requests.
+ *
+ * Test Coverage: - Retry-After in seconds and as an HTTP date -
+ * X-RateLimit-Reset as a duration and as epoch seconds - Exhausted windows
+ * before a 429 - Capped waits - Pausing only ever extends the window.
+ *
+ * Related Files: - src/util/socket/rate-limit.mts (implementation)
+ * - src/util/socket/sdk.mts (response hook)
+ */
+
+import { beforeEach, describe, expect, it } from 'vitest'
+
+import {
+  getRateLimitWaitMs,
+  MAX_RATE_LIMIT_WAIT_MS,
+  noteRateLimitResponse,
+  parseRateLimitWaitMs,
+  pauseApiRequests,
+  resetRateLimit,
+} from '../../../../src/util/socket/rate-limit.mts'
+
+const now = Date.UTC(2026, 0, 1, 12, 0, 0)
+
+describe('rate limit', () => {
+  beforeEach(() => {
+    resetRateLimit()
+  })
+
+  describe('parseRateLimitWaitMs', () => {
+    it('reads Retry-After seconds of a 429', () => {
+      expect(parseRateLimitWaitMs(429, { 'Retry-After': '3' }, now)).toBe(3000)
+    })
+
+    it('reads Retry-After dates of a 429', () => {
+      const date = new Date(now + 10_000).toUTCString()
+      expect(parseRateLimitWaitMs(429, { 'retry-after': date }, now)).toBe(
+        10_000,
+      )
+    })
+
+    it('reads X-RateLimit-Reset as a duration or epoch seconds', () => {
+      expect(
+        parseRateLimitWaitMs(429, { 'x-ratelimit-reset': '2' }, now),
+      ).toBe(2000)
+      expect(
+        parseRateLimitWaitMs(
+          429,
+          { 'x-ratelimit-reset': String(now / 1000 + 5) },
+          now,
+        ),
+      ).toBe(5000)
+    })
+
+    it('waits for the reset once the window is used up', () => {
+      expect(
+        parseRateLimitWaitMs(
+          200,
+          { 'x-ratelimit-remaining': '0', 'x-ratelimit-reset': '4' },
+          now,
+        ),
+      ).toBe(4000)
+      expect(
+        parseRateLimitWaitMs(
+          200,
+          { 'x-ratelimit-remaining': '12', 'x-ratelimit-reset': '4' },
+          now,
+        ),
+      ).toBeUndefined()
+    })
+
+    it('ignores responses without rate-limit headers', () => {
+      expect(parseRateLimitWaitMs(429, {}, now)).toBeUndefined()
+      expect(parseRateLimitWaitMs(429, undefined, now)).toBeUndefined()
+      expect(
+        parseRateLimitWaitMs(200, { 'retry-after': '3' }, now),
+      ).toBeUndefined()
+    })
+
+    it('caps the wait', () => {
+      expect(parseRateLimitWaitMs(429, { 'retry-after': '86400' }, now)).toBe(
+        MAX_RATE_LIMIT_WAIT_MS,
+      )
+    })
+  })
+
+  describe('pausing requests', () => {
+    it('pauses requests after a rate-limited response', () => {
+      expect(getRateLimitWaitMs()).toBe(0)
+
+      noteRateLimitResponse(429, { 'retry-after': '30' })
+
+      expect(getRateLimitWaitMs()).toBeGreaterThan(29_000)
+    })
+
+    it('only ever extends the pause', () => {
+      pauseApiRequests(60_000)
+      pauseApiRequests(1000)
+
+      expect(getRateLimitWaitMs()).toBeGreaterThan(59_000)
+    })
+  })
+})
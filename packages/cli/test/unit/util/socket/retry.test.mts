@@ -0,0 +1,154 @@
+/**
+ * Unit tests for the retry, backoff and timeout settings of Socket API
+ * requests.
+ *
+ * Purpose: Tests where each setting is read from and the delays between
+ * retries.
+ *
+ * Test Coverage: - Flags over env vars over config values - Rejecting flag
+ * values that are not whole numbers - Jittered exponential backoff and its
+ * cap.
+ *
+ * Related Files: - src/util/socket/retry.mts (implementation)
+ * - src/util/socket/sdk.mts (SDK options)
+ */
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+const mockGetConfigValueOrUndef = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  getConfigValueOrUndef: mockGetConfigValueOrUndef,
+}))
+
+const mockGetSocketCliApiTimeout = vi.hoisted(() => vi.fn())
+vi.mock(import('@socketsecurity/lib-stable/env/socket-cli'), () => ({
+  getSocketCliApiTimeout: mockGetSocketCliApiTimeout,
+}))
+
+import {
+  MAX_API_RETRY_DELAY_MS,
+  getApiMaxRetries,
+  getApiRetryBackoffMs,
+  getApiTimeoutMs,
+  getRetryDelayMs,
+  resetApiRetryFlagsForTesting,
+  setApiRetryFlags,
+} from '../../../../src/util/socket/retry.mts'
+
+const ENV_KEYS = ['SOCKET_CLI_API_MAX_RETRIES', 'SOCKET_CLI_API_RETRY_BACKOFF']
+
+describe('retry settings', () => {
+  const savedEnv = { ...process.env }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    resetApiRetryFlagsForTesting()
+    mockGetConfigValueOrUndef.mockReturnValue(undefined)
+    mockGetSocketCliApiTimeout.mockReturnValue(0)
+    for (const key of ENV_KEYS) {
+      delete process.env[key]
+    }
+  })
+
+  afterEach(() => {
+    for (const key of ENV_KEYS) {
+      if (savedEnv[key] === undefined) {
+        delete process.env[key]
+      } else {
+        process.env[key] = savedEnv[key]
+      }
+    }
+  })
+
+  it('leaves unset settings undefined', () => {
+    expect(getApiMaxRetries()).toBeUndefined()
+    expect(getApiRetryBackoffMs()).toBeUndefined()
+    expect(getApiTimeoutMs()).toBeUndefined()
+  })
+
+  it('reads config values, also as numeric strings', () => {
+    mockGetConfigValueOrUndef.mockImplementation(
+      (key: string) =>
+        ({ apiMaxRetries: '4', apiRetryBackoff: 250, apiTimeout: '90000' })[
+          key
+        ],
+    )
+
+    expect(getApiMaxRetries()).toBe(4)
+    expect(getApiRetryBackoffMs()).toBe(250)
+    expect(getApiTimeoutMs()).toBe(90_000)
+  })
+
+  it('ignores config values that are not whole numbers', () => {
+    mockGetConfigValueOrUndef.mockImplementation(
+      (key: string) => ({ apiMaxRetries: '-1', apiTimeout: 'soon' })[key],
+    )
+
+    expect(getApiMaxRetries()).toBeUndefined()
+    expect(getApiTimeoutMs()).toBeUndefined()
+  })
+
+  it('prefers env vars over config values', () => {
+    mockGetConfigValueOrUndef.mockReturnValue('9')
+    mockGetSocketCliApiTimeout.mockReturnValue(5000)
+    process.env['SOCKET_CLI_API_MAX_RETRIES'] = '0'
+    process.env['SOCKET_CLI_API_RETRY_BACKOFF'] = '100'
+
+    expect(getApiMaxRetries()).toBe(0)
+    expect(getApiRetryBackoffMs()).toBe(100)
+    expect(getApiTimeoutMs()).toBe(5000)
+  })
+
+  it('prefers flags over env vars', () => {
+    process.env['SOCKET_CLI_API_MAX_RETRIES'] = '1'
+    mockGetSocketCliApiTimeout.mockReturnValue(5000)
+
+    const result = setApiRetryFlags({
+      maxRetries: 6,
+      retryBackoff: '500',
+      timeout: 120_000,
+    })
+
+    expect(result.ok).toBe(true)
+    expect(getApiMaxRetries()).toBe(6)
+    expect(getApiRetryBackoffMs()).toBe(500)
+    expect(getApiTimeoutMs()).toBe(120_000)
+  })
+
+  it('rejects flag values that are not whole numbers', () => {
+    setApiRetryFlags({ maxRetries: 2 })
+
+    const result = setApiRetryFlags({ maxRetries: 3, timeout: 'abc' })
+
+    expect(result).toEqual({
+      ok: false,
+      message: 'Invalid --timeout value',
+      cause: 'Expected a whole number of milliseconds, 1 or more (saw: abc)',
+    })
+    // Nothing of the previous or the failed invocation sticks.
+    expect(getApiMaxRetries()).toBeUndefined()
+  })
+
+  it('rejects a timeout of 0', () => {
+    expect(setApiRetryFlags({ timeout: 0 }).ok).toBe(false)
+    expect(setApiRetryFlags({ maxRetries: 0, retryBackoff: 0 }).ok).toBe(true)
+  })
+})
+
+describe('getRetryDelayMs', () => {
+  it('doubles the backoff for each retry', () => {
+    const full = () => 1
+    expect(getRetryDelayMs(1, 1000, full)).toBe(1000)
+    expect(getRetryDelayMs(2, 1000, full)).toBe(2000)
+    expect(getRetryDelayMs(3, 1000, full)).toBe(4000)
+  })
+
+  it('waits at least half the backoff', () => {
+    expect(getRetryDelayMs(1, 1000, () => 0)).toBe(500)
+    expect(getRetryDelayMs(3, 1000, () => 0.5)).toBe(3000)
+  })
+
+  it('caps the backoff', () => {
+    expect(getRetryDelayMs(20, 1000, () => 1)).toBe(MAX_API_RETRY_DELAY_MS)
+  })
+})
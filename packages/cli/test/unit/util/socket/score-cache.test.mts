@@ -0,0 +1,156 @@
+/**
+ * Unit tests for the package score cache.
+ *
+ * Purpose: Tests the on-disk cache behind `socket package shallow` /
+ * `socket package score` and their --offline mode.
+ *
+ * Test Coverage: - Round-tripping entries - TTL expiry and stale reads -
+ * Rejecting entries written for a different purl - Oldest-first eviction
+ * past the size cap - The --no-cache and --cache-ttl overrides.
+ *
+ * Related Files: - src/util/socket/score-cache.mts (implementation)
+ */
+
+import { mkdtempSync, statSync, utimesSync, writeFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import {
+  afterAll,
+  afterEach,
+  beforeEach,
+  describe,
+  expect,
+  it,
+  vi,
+} from 'vitest'
+
+import { safeDeleteSync } from '@socketsecurity/lib-stable/fs/safe'
+
+const mockCacheDir = vi.hoisted(() => ({ path: '' }))
+
+vi.mock(import('../../../../src/constants/paths.mts'), () => ({
+  getScoreCachePath: () => mockCacheDir.path,
+}))
+
+import { setCacheOverrides } from '../../../../src/util/cache/overrides.mts'
+import {
+  getScoreCacheFilePath,
+  pruneScoreCache,
+  readScoreCache,
+  storeScoreCacheEntries,
+  writeScoreCache,
+} from '../../../../src/util/socket/score-cache.mts'
+
+const tmpDir = mkdtempSync(path.join(os.tmpdir(), 'socket-score-cache-test-'))
+
+describe('score cache', () => {
+  beforeEach(() => {
+    mockCacheDir.path = mkdtempSync(path.join(tmpDir, 'scores-'))
+  })
+
+  afterEach(() => {
+    setCacheOverrides({})
+  })
+
+  afterAll(() => {
+    safeDeleteSync(tmpDir, { force: true })
+  })
+
+  it('should round-trip entries by kind and purl', async () => {
+    await writeScoreCache('shallow', 'pkg:npm/lodash@4.17.21', { score: 1 })
+
+    expect(await readScoreCache('shallow', 'pkg:npm/lodash@4.17.21')).toEqual({
+      score: 1,
+    })
+    expect(await readScoreCache('deep', 'pkg:npm/lodash@4.17.21')).toBe(
+      undefined,
+    )
+    expect(await readScoreCache('shallow', 'pkg:npm/lodash')).toBe(undefined)
+  })
+
+  it('should only serve expired entries when stale reads are allowed', async () => {
+    writeFileSync(
+      getScoreCacheFilePath('deep', 'pkg:npm/old@1.0.0'),
+      JSON.stringify({
+        timestamp: Date.now() - 48 * 60 * 60 * 1000,
+        purl: 'pkg:npm/old@1.0.0',
+        data: { score: 0.5 },
+      }),
+    )
+
+    expect(await readScoreCache('deep', 'pkg:npm/old@1.0.0')).toBe(undefined)
+    expect(
+      await readScoreCache('deep', 'pkg:npm/old@1.0.0', { allowStale: true }),
+    ).toEqual({ score: 0.5 })
+  })
+
+  it('should ignore entries written for another purl', async () => {
+    writeFileSync(
+      getScoreCacheFilePath('shallow', 'pkg:npm/a@1.0.0'),
+      JSON.stringify({
+        timestamp: Date.now(),
+        purl: 'pkg:npm/b@1.0.0',
+        data: { score: 1 },
+      }),
+    )
+
+    expect(await readScoreCache('shallow', 'pkg:npm/a@1.0.0')).toBe(undefined)
+  })
+
+  it('should evict the oldest entries past the size cap', async () => {
+    const purls = ['pkg:npm/a@1', 'pkg:npm/b@1', 'pkg:npm/c@1']
+    for (let i = 0, { length } = purls; i < length; i += 1) {
+      const purl = purls[i]!
+      await writeScoreCache('shallow', purl, { pad: 'x'.repeat(100) })
+      const seconds = 1_700_000_000 + i
+      utimesSync(getScoreCacheFilePath('shallow', purl), seconds, seconds)
+    }
+    const sizes = purls.map(
+      purl => statSync(getScoreCacheFilePath('shallow', purl)).size,
+    )
+
+    const evicted = await pruneScoreCache(sizes[1]! + sizes[2]!)
+
+    expect(evicted).toBe(1)
+    expect(await readScoreCache('shallow', 'pkg:npm/a@1')).toBe(undefined)
+    expect(await readScoreCache('shallow', 'pkg:npm/c@1')).toEqual({
+      pad: 'x'.repeat(100),
+    })
+  })
+
+  it('should swallow cache write failures', async () => {
+    mockCacheDir.path = path.join(tmpDir, 'missing', '\0invalid')
+
+    await expect(
+      storeScoreCacheEntries('shallow', [['pkg:npm/a@1', {}]]),
+    ).resolves.toBe(undefined)
+  })
+
+  it('should neither read nor write entries with --no-cache', async () => {
+    await writeScoreCache('shallow', 'pkg:npm/a@1', { score: 1 })
+    setCacheOverrides({ disabled: true })
+
+    await storeScoreCacheEntries('shallow', [['pkg:npm/b@1', { score: 2 }]])
+
+    expect(await readScoreCache('shallow', 'pkg:npm/a@1')).toBe(undefined)
+    setCacheOverrides({})
+    expect(await readScoreCache('shallow', 'pkg:npm/b@1')).toBe(undefined)
+  })
+
+  it('should expire entries by --cache-ttl', async () => {
+    writeFileSync(
+      getScoreCacheFilePath('deep', 'pkg:npm/a@1'),
+      JSON.stringify({
+        timestamp: Date.now() - 2 * 60 * 60 * 1000,
+        purl: 'pkg:npm/a@1',
+        data: { score: 1 },
+      }),
+    )
+
+    setCacheOverrides({ ttlMs: 60 * 60 * 1000 })
+    expect(await readScoreCache('deep', 'pkg:npm/a@1')).toBe(undefined)
+    setCacheOverrides({ ttlMs: 3 * 60 * 60 * 1000 })
+    expect(await readScoreCache('deep', 'pkg:npm/a@1')).toEqual({ score: 1 })
+  })
+})
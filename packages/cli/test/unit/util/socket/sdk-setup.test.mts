@@ -101,6 +101,7 @@ describe('SDK Utilities', () => {
         if (!result.ok) {
           expect(result.message).toBe('Auth Error')
           expect(result.cause).toContain('socket login')
+          expect(result.code).toBe(5)
         }
       })
     })
@@ -260,6 +261,55 @@ describe('SDK Utilities', () => {
       )
     })
 
+    it('skips the default proxy for hosts in NO_PROXY', async () => {
+      mockGetSocketCliApiProxy.mockReturnValue(
+        'http://default-proxy.example.com:8080',
+      )
+      const savedNoProxy = process.env['NO_PROXY']
+      process.env['NO_PROXY'] = 'localhost,.internal.example'
+
+      try {
+        const result = await setupSdk({
+          apiToken: 'mock-sdk-value-12345',
+          apiBaseUrl: 'https://socket.internal.example/v0/',
+        })
+
+        expect(result.ok).toBe(true)
+        expect(mockSocketSdkConstructor).toHaveBeenCalledWith(
+          'mock-sdk-value-12345',
+          expect.not.objectContaining({
+            agent: expect.any(Object),
+          }),
+        )
+      } finally {
+        if (savedNoProxy === undefined) {
+          delete process.env['NO_PROXY']
+        } else {
+          process.env['NO_PROXY'] = savedNoProxy
+        }
+      }
+    })
+
+    it('sends a Bearer token through the agent when configured', async () => {
+      mockGetConfigValueOrUndef.mockImplementation((key: string) =>
+        key === 'apiAuthScheme' ? 'bearer' : undefined,
+      )
+
+      const result = await setupSdk({
+        apiToken: 'mock-sdk-value-12345',
+        apiBaseUrl: 'https://socket.example.com/v0/',
+      })
+
+      expect(result.ok).toBe(true)
+      expect(mockSocketSdkConstructor).toHaveBeenCalledWith(
+        'mock-sdk-value-12345',
+        expect.objectContaining({
+          agent: expect.any(Object),
+          baseUrl: 'https://socket.example.com/v0/',
+        }),
+      )
+    })
+
     it('returns SDK with default token from config', async () => {
       mockGetConfigValueOrUndef.mockReturnValue('mock-config-value-12345')
 
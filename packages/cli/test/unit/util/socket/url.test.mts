@@ -17,6 +17,7 @@ import { describe, expect, it, vi } from 'vitest'
 import {
   getPkgFullNameFromPurl,
   getSocketDevAlertUrl,
+  getSocketDevPackageFileUrl,
   getSocketDevPackageOverviewUrl,
   getSocketDevPackageOverviewUrlFromPurl,
 } from '../../../../src/util/socket/url.mts'
@@ -173,4 +174,24 @@ describe('socket-url utilities', () => {
       )
     })
   })
+
+  describe('getSocketDevPackageFileUrl', () => {
+    it('generates URL of a file inside the package version', () => {
+      mockGetPurlObject.mockReturnValue({
+        type: 'npm',
+        namespace: '@babel',
+        name: 'core',
+        version: '7.0.0',
+      })
+
+      expect(
+        getSocketDevPackageFileUrl(
+          'pkg:npm/@babel/core@7.0.0',
+          'lib/config #1.js',
+        ),
+      ).toBe(
+        'https://socket.dev/npm/package/@babel/core/files/7.0.0/lib/config%20%231.js',
+      )
+    })
+  })
 })
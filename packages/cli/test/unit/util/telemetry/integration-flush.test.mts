@@ -0,0 +1,178 @@
+/* oxlint-disable-next-line socket/no-file-scope-oxlint-disable -- legitimate file-scope: domain-grouped layout or test fixture; per-call would produce many redundant disables. */
+/* oxlint-disable socket/personal-path-placeholders -- "testuser" is a fixture username in test input for tilde-replacement assertions; not a real personal path. */
+/**
+ * Unit tests for flushing Telemetry at exit.
+ *
+ * Purpose: Tests that pending telemetry is flushed when the CLI exits and that
+ * finished commands are recorded in the anonymous usage spool.
+ *
+ * Test Coverage: - finalizeTelemetry function - finalizeTelemetrySync function
+ * - setupTelemetryExitHandlers function - recordUsageEvent calls of
+ * trackCliComplete and trackCliError.
+ *
+ * Related Files: - util/telemetry/integration.mts (implementation) -
+ * util/telemetry/usage.mts (spool) - integration.test.mts (event tracking)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+// Mock TelemetryService.
+const mockFlush = vi.hoisted(() => vi.fn())
+const mockTrack = vi.hoisted(() => vi.fn())
+const mockDestroy = vi.hoisted(() => vi.fn())
+const mockGetCurrentInstance = vi.hoisted(() =>
+  vi.fn(() => ({
+    destroy: mockDestroy,
+    flush: mockFlush,
+    track: mockTrack,
+  })),
+)
+const mockGetTelemetryClient = vi.hoisted(() =>
+  vi.fn(() =>
+    Promise.resolve({
+      destroy: mockDestroy,
+      flush: mockFlush,
+      track: mockTrack,
+    }),
+  ),
+)
+
+vi.mock(import('../../../../src/util/telemetry/service.mts'), () => ({
+  TelemetryService: {
+    getCurrentInstance: mockGetCurrentInstance,
+    getTelemetryClient: mockGetTelemetryClient,
+  },
+}))
+
+// Mock anonymous usage telemetry.
+const mockRecordUsageEvent = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/telemetry/usage.mts'), () => ({
+  recordUsageEvent: mockRecordUsageEvent,
+}))
+
+// Mock config.
+const mockGetConfigValueOrUndef = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  getConfigValueOrUndef: mockGetConfigValueOrUndef,
+}))
+
+// Mock constants - set VITEST to false to enable telemetry tracking.
+vi.mock(import('../../../../src/constants.mts'), () => ({
+  CONFIG_KEY_DEFAULT_ORG: 'defaultOrg',
+  constants: {
+    ENV: {
+      INLINED_VERSION: '1.0.0-test',
+      VITEST: false,
+    },
+  },
+}))
+
+// Mock homedir.
+const mockHomedir = vi.hoisted(() => vi.fn(() => '/Users/testuser'))
+vi.mock(import('node:os'), () => ({
+  homedir: mockHomedir,
+  default: {
+    homedir: mockHomedir,
+  },
+}))
+
+// Mock debug.
+vi.mock(import('@socketsecurity/lib-stable/debug/output'), () => ({
+  debugNs: vi.fn(),
+}))
+
+import {
+  finalizeTelemetry,
+  finalizeTelemetrySync,
+  setupTelemetryExitHandlers,
+  trackCliComplete,
+  trackCliError,
+} from '../../../../src/util/telemetry/integration.mts'
+
+describe('telemetry/integration', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockGetConfigValueOrUndef.mockReturnValue('test-org')
+  })
+
+  describe('finalizeTelemetry', () => {
+    it('flushes telemetry when instance exists', async () => {
+      await finalizeTelemetry()
+
+      expect(mockGetCurrentInstance).toHaveBeenCalled()
+      expect(mockFlush).toHaveBeenCalled()
+    })
+
+    it('does nothing when no instance exists', async () => {
+      mockGetCurrentInstance.mockReturnValueOnce(undefined)
+
+      await finalizeTelemetry()
+
+      expect(mockGetCurrentInstance).toHaveBeenCalled()
+      expect(mockFlush).not.toHaveBeenCalled()
+    })
+  })
+
+  describe('finalizeTelemetrySync', () => {
+    it('triggers flush when instance exists', () => {
+      finalizeTelemetrySync()
+
+      expect(mockGetCurrentInstance).toHaveBeenCalled()
+      expect(mockFlush).toHaveBeenCalled()
+    })
+
+    it('does nothing when no instance exists', () => {
+      mockGetCurrentInstance.mockReturnValueOnce(undefined)
+
+      finalizeTelemetrySync()
+
+      expect(mockGetCurrentInstance).toHaveBeenCalled()
+      expect(mockFlush).not.toHaveBeenCalled()
+    })
+  })
+
+  describe('setupTelemetryExitHandlers', () => {
+    it('registers exit handlers', () => {
+      const processOnSpy = vi.spyOn(process, 'on')
+
+      setupTelemetryExitHandlers()
+
+      expect(processOnSpy).toHaveBeenCalled()
+      processOnSpy.mockRestore()
+    })
+
+    it('skips re-registration on duplicate calls (lines 118-119)', () => {
+      // First call registers (or has already registered from a prior test
+      // in the same module — module-level exitHandlersRegistered persists).
+      setupTelemetryExitHandlers()
+      const processOnSpy = vi.spyOn(process, 'on')
+      // Second call should hit the early-return branch.
+      setupTelemetryExitHandlers()
+      // No new handlers registered on the second call.
+      expect(processOnSpy).not.toHaveBeenCalled()
+      processOnSpy.mockRestore()
+    })
+  })
+
+  describe('anonymous usage spool', () => {
+    it('records the anonymous usage event', async () => {
+      await trackCliComplete(['node', 'socket', 'scan'], Date.now(), 3)
+
+      expect(mockRecordUsageEvent).toHaveBeenCalledWith({
+        durationMs: expect.any(Number),
+        exitCode: 3,
+      })
+    })
+
+    it('records the anonymous usage event with the error', async () => {
+      const error = new TypeError('Test error')
+      await trackCliError(['node', 'socket', 'scan'], Date.now(), error)
+
+      expect(mockRecordUsageEvent).toHaveBeenCalledWith({
+        durationMs: expect.any(Number),
+        error,
+        exitCode: 1,
+      })
+    })
+  })
+})
@@ -5,14 +5,14 @@
  *
  * Purpose: Tests the telemetry integration helper functions.
  *
- * Test Coverage: - finalizeTelemetry function - finalizeTelemetrySync function
- * - setupTelemetryExitHandlers function - trackSubprocessExit function -
- * sanitizeArgv function (via buildContext) - trackEvent function -
- * trackCliStart function - trackCliEvent function - trackCliComplete function -
- * trackCliError function - trackSubprocessStart function -
- * trackSubprocessComplete function - trackSubprocessError function.
+ * Test Coverage: - trackSubprocessExit function - sanitizeArgv function (via
+ * buildContext) - trackEvent function - trackCliStart function - trackCliEvent
+ * function - trackCliComplete function - trackCliError function -
+ * trackSubprocessStart function - trackSubprocessComplete function -
+ * trackSubprocessError function.
  *
- * Related Files: - util/telemetry/integration.mts (implementation)
+ * Related Files: - util/telemetry/integration.mts (implementation) -
+ * integration-flush.test.mts (flushing and the usage spool)
  */
 
 import { beforeEach, describe, expect, it, vi } from 'vitest'
@@ -45,6 +45,12 @@ vi.mock(import('../../../../src/util/telemetry/service.mts'), () => ({
   },
 }))
 
+// Mock anonymous usage telemetry.
+const mockRecordUsageEvent = vi.hoisted(() => vi.fn())
+vi.mock(import('../../../../src/util/telemetry/usage.mts'), () => ({
+  recordUsageEvent: mockRecordUsageEvent,
+}))
+
 // Mock config.
 const mockGetConfigValueOrUndef = vi.hoisted(() => vi.fn())
 vi.mock(import('../../../../src/util/config.mts'), () => ({
@@ -77,9 +83,6 @@ vi.mock(import('@socketsecurity/lib-stable/debug/output'), () => ({
 }))
 
 import {
-  finalizeTelemetry,
-  finalizeTelemetrySync,
-  setupTelemetryExitHandlers,
   trackCliComplete,
   trackCliError,
   trackCliEvent,
@@ -97,65 +100,6 @@ describe('telemetry/integration', () => {
     mockGetConfigValueOrUndef.mockReturnValue('test-org')
   })
 
-  describe('finalizeTelemetry', () => {
-    it('flushes telemetry when instance exists', async () => {
-      await finalizeTelemetry()
-
-      expect(mockGetCurrentInstance).toHaveBeenCalled()
-      expect(mockFlush).toHaveBeenCalled()
-    })
-
-    it('does nothing when no instance exists', async () => {
-      mockGetCurrentInstance.mockReturnValueOnce(undefined)
-
-      await finalizeTelemetry()
-
-      expect(mockGetCurrentInstance).toHaveBeenCalled()
-      expect(mockFlush).not.toHaveBeenCalled()
-    })
-  })
-
-  describe('finalizeTelemetrySync', () => {
-    it('triggers flush when instance exists', () => {
-      finalizeTelemetrySync()
-
-      expect(mockGetCurrentInstance).toHaveBeenCalled()
-      expect(mockFlush).toHaveBeenCalled()
-    })
-
-    it('does nothing when no instance exists', () => {
-      mockGetCurrentInstance.mockReturnValueOnce(undefined)
-
-      finalizeTelemetrySync()
-
-      expect(mockGetCurrentInstance).toHaveBeenCalled()
-      expect(mockFlush).not.toHaveBeenCalled()
-    })
-  })
-
-  describe('setupTelemetryExitHandlers', () => {
-    it('registers exit handlers', () => {
-      const processOnSpy = vi.spyOn(process, 'on')
-
-      setupTelemetryExitHandlers()
-
-      expect(processOnSpy).toHaveBeenCalled()
-      processOnSpy.mockRestore()
-    })
-
-    it('skips re-registration on duplicate calls (lines 118-119)', () => {
-      // First call registers (or has already registered from a prior test
-      // in the same module — module-level exitHandlersRegistered persists).
-      setupTelemetryExitHandlers()
-      const processOnSpy = vi.spyOn(process, 'on')
-      // Second call should hit the early-return branch.
-      setupTelemetryExitHandlers()
-      // No new handlers registered on the second call.
-      expect(processOnSpy).not.toHaveBeenCalled()
-      processOnSpy.mockRestore()
-    })
-  })
-
   describe('trackSubprocessExit', () => {
     it('tracks error when exit code is non-zero', async () => {
       await trackSubprocessExit('npm', Date.now() - 1000, 1)
@@ -0,0 +1,224 @@
+/**
+ * Unit tests for anonymous usage telemetry.
+ *
+ * Purpose: Tests the opt-in, the local spool and the batched flush.
+ *
+ * Test Coverage: - Opt-in through the telemetry config key - DO_NOT_TRACK veto
+ * - Spooled event contents - Error classes - Flush batching, retry and
+ * clearing.
+ *
+ * Related Files: - src/util/telemetry/usage.mts (implementation)
+ */
+
+import { mkdtempSync, readFileSync } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+
+import { afterEach, beforeEach, describe, expect, it, vi } from 'vitest'
+
+import { safeDelete } from '@socketsecurity/lib-stable/fs/safe'
+
+import {
+  clearUsageSpool,
+  flushUsageSpool,
+  getUsageErrorClass,
+  getUsageInstallationId,
+  getUsagePayload,
+  getUsageSpoolPath,
+  getUsageTelemetryState,
+  readUsageSpool,
+  recordUsageEvent,
+  setUsageCommand,
+} from '../../../../src/util/telemetry/usage.mts'
+
+const mockGetConfigValueSource = vi.hoisted(() => vi.fn())
+const mockGetSocketTelemetryPath = vi.hoisted(() => vi.fn())
+const mockSocketHttpRequest = vi.hoisted(() => vi.fn())
+
+vi.mock(import('../../../../src/constants/paths.mts'), async importOriginal => {
+  const actual = await importOriginal()
+  return {
+    ...actual,
+    getSocketTelemetryPath: mockGetSocketTelemetryPath,
+  }
+})
+
+vi.mock(import('../../../../src/util/config.mts'), async importOriginal => {
+  const actual = await importOriginal()
+  return {
+    ...actual,
+    getConfigValueSource: mockGetConfigValueSource,
+  }
+})
+
+vi.mock(import('../../../../src/util/socket/api-http.mts'), () => ({
+  getDefaultApiBaseUrl: () => 'https://api.example.test/v0/',
+  socketHttpRequest: mockSocketHttpRequest,
+}))
+
+describe('telemetry/usage', () => {
+  let tmpDir: string
+  let originalDoNotTrack: string | undefined
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    tmpDir = path.resolve(mkdtempSync(path.join(os.tmpdir(), 'socket-usage-')))
+    mockGetSocketTelemetryPath.mockReturnValue(path.join(tmpDir, 'telemetry'))
+    mockGetConfigValueSource.mockReturnValue({
+      origin: '/home/user/.config/socket/config.json',
+      source: 'user',
+      value: true,
+    })
+    mockSocketHttpRequest.mockResolvedValue({ ok: true, status: 200 })
+    originalDoNotTrack = process.env['DO_NOT_TRACK']
+    delete process.env['DO_NOT_TRACK']
+    setUsageCommand('socket')
+  })
+
+  afterEach(async () => {
+    if (originalDoNotTrack === undefined) {
+      delete process.env['DO_NOT_TRACK']
+    } else {
+      process.env['DO_NOT_TRACK'] = originalDoNotTrack
+    }
+    await safeDelete(tmpDir, { force: true })
+  })
+
+  async function spoolEvents(count: number): Promise<void> {
+    for (let i = 0; i < count; i += 1) {
+      await recordUsageEvent({ durationMs: i, exitCode: 0 })
+    }
+  }
+
+  describe('getUsageTelemetryState', () => {
+    it('is off by default', () => {
+      mockGetConfigValueSource.mockReturnValue(undefined)
+
+      expect(getUsageTelemetryState()).toEqual({
+        enabled: false,
+        reason: 'Not opted in, telemetry is off by default',
+      })
+    })
+
+    it('is on when the telemetry key is true', () => {
+      expect(getUsageTelemetryState()).toMatchObject({ enabled: true })
+    })
+
+    it('is off when DO_NOT_TRACK is set', () => {
+      process.env['DO_NOT_TRACK'] = '1'
+
+      expect(getUsageTelemetryState()).toEqual({
+        enabled: false,
+        reason: 'DO_NOT_TRACK is set',
+      })
+    })
+  })
+
+  describe('getUsageErrorClass', () => {
+    it('uses the class of thrown errors', () => {
+      expect(getUsageErrorClass(new TypeError('x'), 1)).toBe('TypeError')
+      expect(getUsageErrorClass('x', 1)).toBe('Error')
+    })
+
+    it('maps exit codes of failures that did not throw', () => {
+      expect(getUsageErrorClass(undefined, 0)).toBeUndefined()
+      expect(getUsageErrorClass(undefined, 2)).toBe('InputError')
+      expect(getUsageErrorClass(undefined, 3)).toBe('PolicyViolation')
+      expect(getUsageErrorClass(undefined, 5)).toBe('AuthError')
+      expect(getUsageErrorClass(undefined, 42)).toBe('Error')
+    })
+  })
+
+  describe('recordUsageEvent', () => {
+    it('spools the command name without its arguments', async () => {
+      setUsageCommand('socket scan create')
+
+      await recordUsageEvent({ durationMs: 1234, exitCode: 4 })
+
+      const events = await readUsageSpool()
+      expect(events).toEqual([
+        {
+          arch: process.arch,
+          ci: expect.any(Boolean),
+          command: 'socket scan create',
+          created_at: expect.any(String),
+          duration_ms: 1234,
+          error_class: 'ApiError',
+          exit_code: 4,
+          installation_id: await getUsageInstallationId(),
+          node_version: process.version,
+          platform: process.platform,
+          version: expect.any(String),
+        },
+      ])
+    })
+
+    it('keeps the installation ID across events', async () => {
+      await spoolEvents(2)
+
+      const [first, second] = await readUsageSpool()
+      expect(first!.installation_id).toMatch(/^[0-9a-f-]{36}$/)
+      expect(second!.installation_id).toBe(first!.installation_id)
+    })
+
+    it('does nothing when telemetry is off', async () => {
+      process.env['DO_NOT_TRACK'] = '1'
+
+      await recordUsageEvent({ durationMs: 1, exitCode: 0 })
+
+      expect(await readUsageSpool()).toEqual([])
+      expect(await getUsageInstallationId()).toBeUndefined()
+    })
+  })
+
+  describe('flushUsageSpool', () => {
+    it('waits for a full batch', async () => {
+      await spoolEvents(9)
+
+      await flushUsageSpool()
+
+      expect(mockSocketHttpRequest).not.toHaveBeenCalled()
+      expect(await readUsageSpool()).toHaveLength(9)
+    })
+
+    it('sends the payload and empties the spool', async () => {
+      await spoolEvents(10)
+      const payload = await getUsagePayload()
+
+      await flushUsageSpool()
+
+      expect(mockSocketHttpRequest).toHaveBeenCalledWith(
+        'https://api.example.test/v0/cli/telemetry',
+        expect.objectContaining({ method: 'POST' }),
+      )
+      const { body } = mockSocketHttpRequest.mock.calls[0]![1]
+      expect(JSON.parse(body)).toEqual(payload)
+      expect(await readUsageSpool()).toEqual([])
+    })
+
+    it('keeps the events when sending fails', async () => {
+      mockSocketHttpRequest.mockResolvedValue({
+        ok: false,
+        status: 503,
+        statusText: 'Service Unavailable',
+      })
+      await spoolEvents(10)
+
+      await flushUsageSpool()
+
+      expect(await readUsageSpool()).toHaveLength(10)
+    })
+  })
+
+  describe('clearUsageSpool', () => {
+    it('removes the events and the installation ID', async () => {
+      await spoolEvents(1)
+      expect(readFileSync(getUsageSpoolPath(), 'utf8')).not.toBe('')
+
+      await clearUsageSpool()
+
+      expect(await readUsageSpool()).toEqual([])
+      expect(await getUsageInstallationId()).toBeUndefined()
+    })
+  })
+})
@@ -0,0 +1,179 @@
+/**
+ * Unit tests for update channels.
+ *
+ * Purpose: Tests the release channels of `socket self-update` and
+ * `socket version --check`.
+ *
+ * Test Coverage: - Channel validation and dist-tags - The default channel of
+ * stable and prerelease versions - Standalone executable triplets - The
+ * package updates come from - Dist-tag lookups - The managed environment
+ * opt-out.
+ *
+ * Related Files: - src/util/update/channel.mts (implementation)
+ */
+
+import { beforeEach, describe, expect, it, vi } from 'vitest'
+
+import {
+  fetchChannelVersion,
+  getChannelDistTag,
+  getCliExeTriplet,
+  getDefaultUpdateChannel,
+  getSelfUpdateDisabledReason,
+  getUpdatePackageName,
+  isUpdateChannel,
+} from '../../../../src/util/update/channel.mts'
+
+const mockFetchRegistryJson = vi.hoisted(() => vi.fn())
+const mockGetConfigValueOrUndef = vi.hoisted(() => vi.fn())
+const mockGetSocketCliNoSelfUpdate = vi.hoisted(() => vi.fn())
+const mockIsSeaBinary = vi.hoisted(() => vi.fn())
+
+vi.mock(
+  import('../../../../src/commands/verify/fetch-npm-provenance.mts'),
+  () => ({
+    escapePackageName: (name: string) => name.replace('/', '%2f'),
+    fetchRegistryJson: mockFetchRegistryJson,
+  }),
+)
+
+vi.mock(import('../../../../src/util/config.mts'), () => ({
+  getConfigValueOrUndef: mockGetConfigValueOrUndef,
+}))
+
+vi.mock(
+  import('../../../../src/env/socket-cli-no-self-update.mts'),
+  () => ({
+    getSocketCliNoSelfUpdate: mockGetSocketCliNoSelfUpdate,
+  }),
+)
+
+vi.mock(import('../../../../src/util/sea/detect.mts'), () => ({
+  isSeaBinary: mockIsSeaBinary,
+}))
+
+describe('update channels', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockGetSocketCliNoSelfUpdate.mockReturnValue(false)
+    mockGetConfigValueOrUndef.mockReturnValue(undefined)
+    mockIsSeaBinary.mockReturnValue(false)
+  })
+
+  describe('isUpdateChannel', () => {
+    it('should accept beta and stable only', () => {
+      expect(isUpdateChannel('beta')).toBe(true)
+      expect(isUpdateChannel('stable')).toBe(true)
+      expect(isUpdateChannel('latest')).toBe(false)
+      expect(isUpdateChannel(undefined)).toBe(false)
+    })
+  })
+
+  describe('getChannelDistTag', () => {
+    it('should map stable to the latest dist-tag', () => {
+      expect(getChannelDistTag('stable')).toBe('latest')
+      expect(getChannelDistTag('beta')).toBe('beta')
+    })
+  })
+
+  describe('getDefaultUpdateChannel', () => {
+    it('should put prereleases on beta', () => {
+      expect(getDefaultUpdateChannel('2.0.0-beta.3')).toBe('beta')
+      expect(getDefaultUpdateChannel('2.0.0')).toBe('stable')
+      expect(getDefaultUpdateChannel('')).toBe('stable')
+    })
+  })
+
+  describe('getCliExeTriplet', () => {
+    it('should name published platforms', () => {
+      expect(getCliExeTriplet('darwin', 'arm64', undefined)).toBe(
+        'darwin-arm64',
+      )
+      expect(getCliExeTriplet('linux', 'x64', 'glibc')).toBe('linux-x64')
+      expect(getCliExeTriplet('linux', 'x64', 'musl')).toBe('linux-x64-musl')
+      expect(getCliExeTriplet('win32', 'x64', undefined)).toBe('win32-x64')
+    })
+
+    it('should return undefined for other platforms', () => {
+      expect(getCliExeTriplet('freebsd', 'x64', undefined)).toBeUndefined()
+      expect(getCliExeTriplet('linux', 'ia32', 'glibc')).toBeUndefined()
+    })
+  })
+
+  describe('getUpdatePackageName', () => {
+    it('should use the CLI package outside of standalone executables', () => {
+      expect(getUpdatePackageName()).toMatch(/socket/)
+      expect(getUpdatePackageName()).not.toContain('cli.exe')
+    })
+
+    it('should use the platform package for standalone executables', () => {
+      mockIsSeaBinary.mockReturnValue(true)
+      const triplet = getCliExeTriplet()
+
+      expect(getUpdatePackageName()).toBe(
+        triplet ? `@socketsecurity/cli.exe.${triplet}` : undefined,
+      )
+    })
+  })
+
+  describe('fetchChannelVersion', () => {
+    it('should read the dist-tag of the channel', async () => {
+      mockFetchRegistryJson.mockResolvedValue({
+        ok: true,
+        data: { beta: '2.1.0-beta.1', latest: '2.0.4' },
+      })
+
+      const result = await fetchChannelVersion('@socketsecurity/cli', 'stable')
+
+      expect(result).toEqual({ ok: true, data: '2.0.4' })
+      expect(mockFetchRegistryJson).toHaveBeenCalledWith(
+        '/-/package/@socketsecurity%2fcli/dist-tags',
+        expect.any(String),
+      )
+    })
+
+    it('should fail when the channel has no release', async () => {
+      mockFetchRegistryJson.mockResolvedValue({
+        ok: true,
+        data: { latest: '2.0.4' },
+      })
+
+      const result = await fetchChannelVersion('@socketsecurity/cli', 'beta')
+
+      expect(result.ok).toBe(false)
+      expect(!result.ok && result.message).toBe('Release not found')
+    })
+
+    it('should pass registry failures through', async () => {
+      const failure = { ok: false, message: 'npm registry request failed' }
+      mockFetchRegistryJson.mockResolvedValue(failure)
+
+      const result = await fetchChannelVersion('@socketsecurity/cli', 'beta')
+
+      expect(result).toBe(failure)
+    })
+  })
+
+  describe('getSelfUpdateDisabledReason', () => {
+    it('should return undefined by default', () => {
+      expect(getSelfUpdateDisabledReason()).toBeUndefined()
+    })
+
+    it('should report SOCKET_CLI_NO_SELF_UPDATE', () => {
+      mockGetSocketCliNoSelfUpdate.mockReturnValue(true)
+
+      expect(getSelfUpdateDisabledReason()).toContain(
+        'SOCKET_CLI_NO_SELF_UPDATE',
+      )
+    })
+
+    it('should report the disableSelfUpdate config value', () => {
+      mockGetConfigValueOrUndef.mockReturnValue(true)
+
+      expect(getSelfUpdateDisabledReason()).toContain('disableSelfUpdate')
+      expect(mockGetConfigValueOrUndef).toHaveBeenCalledWith(
+        'disableSelfUpdate',
+      )
+    })
+  })
+})
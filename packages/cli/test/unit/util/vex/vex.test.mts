@@ -0,0 +1,201 @@
+/**
+ * Unit tests for reading VEX documents and applying their statements.
+ *
+ * Tests OpenVEX, CSAF VEX and CycloneDX parsing into statements, and which
+ * vulnerability alerts of a scan the statements leave out.
+ */
+
+import { describe, expect, it } from 'vitest'
+
+import {
+  applyVexStatements,
+  getAlertVulnerabilityIds,
+  parseVexDocument,
+} from '../../../../src/util/vex/vex.mts'
+
+import type {
+  SocketArtifact,
+  SocketArtifactAlert,
+} from '../../../../src/util/alert/artifact.mts'
+
+const CVE_ALERT = {
+  key: 'cve-1',
+  props: { cveId: 'CVE-2021-23337', ghsaId: 'GHSA-35jh-r3h4-6jhm' },
+  severity: 'high',
+  type: 'cve',
+} as SocketArtifactAlert
+
+const MALWARE_ALERT = {
+  key: 'malware-1',
+  severity: 'critical',
+  type: 'malware',
+} as SocketArtifactAlert
+
+function lodash(version = '4.17.20'): SocketArtifact {
+  return {
+    alerts: [CVE_ALERT, MALWARE_ALERT],
+    name: 'lodash',
+    type: 'npm',
+    version,
+  } as SocketArtifact
+}
+
+describe('getAlertVulnerabilityIds', () => {
+  it('reads the CVE and GHSA ids of vulnerability alerts', () => {
+    expect(getAlertVulnerabilityIds(CVE_ALERT)).toEqual([
+      'CVE-2021-23337',
+      'GHSA-35jh-r3h4-6jhm',
+    ])
+    expect(getAlertVulnerabilityIds(MALWARE_ALERT)).toEqual([])
+  })
+})
+
+describe('parseVexDocument', () => {
+  it('reads OpenVEX statements with subcomponents and aliases', () => {
+    const result = parseVexDocument(
+      JSON.stringify({
+        '@context': 'https://openvex.dev/ns/v0.2.0',
+        statements: [
+          {
+            vulnerability: {
+              name: 'GHSA-35jh-r3h4-6jhm',
+              aliases: ['CVE-2021-23337'],
+            },
+            products: [
+              {
+                '@id': 'pkg:github/acme/app',
+                subcomponents: [{ '@id': 'pkg:npm/lodash@4.17.20' }],
+              },
+            ],
+            status: 'not_affected',
+            justification: 'vulnerable_code_not_in_execute_path',
+          },
+        ],
+      }),
+    )
+    expect(result).toEqual({
+      ok: true,
+      data: [
+        {
+          ids: ['GHSA-35jh-r3h4-6jhm', 'CVE-2021-23337'],
+          justification: 'vulnerable_code_not_in_execute_path',
+          products: ['pkg:github/acme/app', 'pkg:npm/lodash@4.17.20'],
+          status: 'not_affected',
+        },
+      ],
+    })
+  })
+
+  it('reads CSAF product statuses through the product tree', () => {
+    const result = parseVexDocument(
+      JSON.stringify({
+        document: { category: 'csaf_vex', csaf_version: '2.0' },
+        product_tree: {
+          branches: [
+            {
+              product: {
+                name: 'lodash 4.17.20',
+                product_id: 'CSAFPID-0001',
+                product_identification_helper: {
+                  purl: 'pkg:npm/lodash@4.17.20',
+                },
+              },
+            },
+          ],
+        },
+        vulnerabilities: [
+          {
+            cve: 'CVE-2021-23337',
+            flags: [
+              { label: 'component_not_present', product_ids: ['CSAFPID-0001'] },
+            ],
+            product_status: { known_not_affected: ['CSAFPID-0001'] },
+          },
+        ],
+      }),
+    )
+    expect(result.ok && result.data).toEqual([
+      {
+        ids: ['CVE-2021-23337'],
+        justification: 'component_not_present',
+        products: ['pkg:npm/lodash@4.17.20'],
+        status: 'not_affected',
+      },
+    ])
+  })
+
+  it('reads CycloneDX analyses with bom-ref affects', () => {
+    const result = parseVexDocument(
+      JSON.stringify({
+        bomFormat: 'CycloneDX',
+        components: [{ 'bom-ref': 'lodash', purl: 'pkg:npm/lodash@4.17.20' }],
+        vulnerabilities: [
+          {
+            id: 'CVE-2021-23337',
+            analysis: { state: 'false_positive' },
+            affects: [{ ref: 'lodash' }],
+          },
+        ],
+      }),
+    )
+    expect(result.ok && result.data).toEqual([
+      {
+        ids: ['CVE-2021-23337'],
+        products: ['pkg:npm/lodash@4.17.20'],
+        status: 'not_affected',
+      },
+    ])
+  })
+
+  it('rejects documents that are not VEX', () => {
+    expect(parseVexDocument('{"bomFormat":"SPDX"}')).toMatchObject({
+      ok: false,
+      message: 'Invalid VEX document',
+    })
+    expect(parseVexDocument('not json')).toMatchObject({ ok: false })
+  })
+})
+
+describe('applyVexStatements', () => {
+  it('leaves out vulnerability alerts marked not affected', () => {
+    const { scan, suppressed } = applyVexStatements(
+      [lodash(), lodash('4.17.21')],
+      [
+        {
+          ids: ['ghsa-35jh-r3h4-6jhm'],
+          products: ['pkg:npm/lodash@4.17.20'],
+          status: 'not_affected',
+        },
+      ],
+    )
+    expect(suppressed).toBe(1)
+    expect(scan[0]!.alerts).toEqual([MALWARE_ALERT])
+    expect(scan[1]!.alerts).toEqual([CVE_ALERT, MALWARE_ALERT])
+  })
+
+  it('matches every version for a product purl without one', () => {
+    const { suppressed } = applyVexStatements(
+      [lodash(), lodash('4.17.21')],
+      [
+        {
+          ids: ['CVE-2021-23337'],
+          products: ['pkg:npm/lodash'],
+          status: 'fixed',
+        },
+      ],
+    )
+    expect(suppressed).toBe(2)
+  })
+
+  it('keeps alerts of affected and under investigation statements', () => {
+    const scan = [lodash()]
+    const result = applyVexStatements(scan, [
+      {
+        ids: ['CVE-2021-23337'],
+        products: ['pkg:npm/lodash@4.17.20'],
+        status: 'affected',
+      },
+    ])
+    expect(result).toEqual({ scan, suppressed: 0 })
+  })
+})
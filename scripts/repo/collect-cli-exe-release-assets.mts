@@ -0,0 +1,195 @@
+/**
+ * @file Collect the standalone executables of a release as GitHub release
+ *   assets. Each published `@socketsecurity/cli.exe.<triplet>@<version>`
+ *   tarball is downloaded from the npm registry, checked against the
+ *   integrity the registry lists for it, and its executable is written as
+ *   `socket-<triplet>[.exe]` next to install.sh, install.ps1 and a
+ *   SHA256SUMS file covering all of them. Assets come from the registry
+ *   rather than a fresh build, so the GitHub download is byte-identical to
+ *   the signed npm release that `socket self install` verifies. Triplets
+ *   without a published version, e.g. the win32 pair while it cannot build,
+ *   are skipped with a warning, and a release before any tail is live gets
+ *   the installers only. Under GITHUB_ACTIONS the asset paths are
+ *   written to the `assets` step output, newline separated. Usage: node
+ *   scripts/repo/collect-cli-exe-release-assets.mts --version=2.1.0
+ *   [--out-dir=release-assets].
+ */
+
+import crypto from 'node:crypto'
+import { appendFileSync, promises as fs } from 'node:fs'
+import os from 'node:os'
+import path from 'node:path'
+import process from 'node:process'
+
+import { parseArgs } from '@socketsecurity/lib-stable/argv/parse'
+import { safeDelete, safeMkdir } from '@socketsecurity/lib-stable/fs/safe'
+import { httpRequest } from '@socketsecurity/lib-stable/http-request'
+import { getDefaultLogger } from '@socketsecurity/lib-stable/logger/default'
+import { spawn } from '@socketsecurity/lib-stable/process/spawn/child'
+
+import {
+  CLI_EXE_TRIPLETS,
+  cliExeBinaryName,
+  cliExePackageName,
+} from 'package-builder/scripts/cli-exe-targets.mts'
+import type { CliExeTriplet } from 'package-builder/scripts/cli-exe-targets.mts'
+
+const logger = getDefaultLogger()
+
+const REGISTRY_URL = 'https://registry.npmjs.org'
+
+const rootPath = path.resolve(import.meta.dirname, '..', '..')
+
+interface CollectArgs {
+  'out-dir': string
+  version?: string | undefined
+}
+
+interface RegistryDist {
+  readonly integrity?: string | undefined
+  readonly tarball?: string | undefined
+}
+
+const { values } = parseArgs<CollectArgs>({
+  options: {
+    'out-dir': { default: 'release-assets', type: 'string' },
+    version: { type: 'string' },
+  },
+})
+
+/**
+ * The dist of a published tail version, undefined when the version is not
+ * on the registry.
+ */
+async function fetchDist(
+  name: string,
+  version: string,
+): Promise<RegistryDist | undefined> {
+  const res = await httpRequest(`${REGISTRY_URL}/${name}/${version}`, {
+    timeout: 30_000,
+  })
+  if (res.status === 404) {
+    return undefined
+  }
+  if (!res.ok) {
+    throw new Error(`${name}@${version}: registry responded ${res.status}`)
+  }
+  return res.json<{ dist?: RegistryDist | undefined }>().dist
+}
+
+function getIntegrity(data: Buffer, algorithm: string): string {
+  return `${algorithm}-${crypto.createHash(algorithm).update(data).digest('base64')}`
+}
+
+function getSha256(data: Buffer): string {
+  return crypto.createHash('sha256').update(data).digest('hex')
+}
+
+/**
+ * Download the tail of a triplet, verify it and write its executable into
+ * outDir. Returns the asset file name, undefined when not published.
+ */
+async function collectTriplet(
+  triplet: CliExeTriplet,
+  version: string,
+  outDir: string,
+): Promise<string | undefined> {
+  const name = cliExePackageName(triplet)
+  const dist = await fetchDist(name, version)
+  if (!dist) {
+    logger.warn(`${name}@${version} is not published — skipping ${triplet}`)
+    return undefined
+  }
+  if (!dist.integrity || !dist.tarball) {
+    throw new Error(`${name}@${version}: the registry lists no integrity`)
+  }
+  const res = await httpRequest(dist.tarball, { timeout: 300_000 })
+  if (!res.ok) {
+    throw new Error(`${dist.tarball}: download responded ${res.status}`)
+  }
+  const tgz = res.body
+  const algorithm = dist.integrity.split('-', 1)[0]!
+  if (getIntegrity(tgz, algorithm) !== dist.integrity) {
+    throw new Error(
+      `${name}@${version}: the tarball does not match the published integrity`,
+    )
+  }
+
+  const tmpDir = await fs.mkdtemp(path.join(os.tmpdir(), 'socket-cli-exe-'))
+  try {
+    const tgzPath = path.join(tmpDir, 'package.tgz')
+    await fs.writeFile(tgzPath, tgz)
+    await spawn('tar', ['-xzf', tgzPath, '-C', tmpDir], { stdio: 'inherit' })
+    const binaryName = cliExeBinaryName(triplet)
+    const assetName = binaryName.endsWith('.exe')
+      ? `socket-${triplet}.exe`
+      : `socket-${triplet}`
+    await fs.copyFile(
+      path.join(tmpDir, 'package', 'bin', binaryName),
+      path.join(outDir, assetName),
+    )
+    await fs.chmod(path.join(outDir, assetName), 0o755)
+    logger.success(`${name}@${version} -> ${assetName}`)
+    return assetName
+  } finally {
+    await safeDelete(tmpDir, { force: true })
+  }
+}
+
+async function main(): Promise<void> {
+  if (!values.version) {
+    logger.error('--version is required')
+    process.exitCode = 1
+    return
+  }
+  const version = values.version.replace(/^v/, '')
+  const outDir = path.resolve(values['out-dir'])
+  await safeMkdir(outDir, { recursive: true })
+
+  const assetNames: string[] = []
+  for (const triplet of CLI_EXE_TRIPLETS) {
+    // eslint-disable-next-line no-await-in-loop
+    const assetName = await collectTriplet(triplet, version, outDir)
+    if (assetName) {
+      assetNames.push(assetName)
+    }
+  }
+  if (!assetNames.length) {
+    // Until the tails go live (Phase 1 of docs/cli-exe-migration.md) the
+    // installers still fall back to the legacy binaries.
+    logger.warn(
+      `No @socketsecurity/cli.exe.* tail is published at ${version} — attaching the installers only`,
+    )
+  }
+  for (const script of ['install.sh', 'install.ps1']) {
+    // eslint-disable-next-line no-await-in-loop
+    await fs.copyFile(path.join(rootPath, script), path.join(outDir, script))
+    assetNames.push(script)
+  }
+
+  // The format of `sha256sum -c` and `shasum -a 256 -c`.
+  const sums: string[] = []
+  for (const assetName of assetNames) {
+    // eslint-disable-next-line no-await-in-loop
+    const data = await fs.readFile(path.join(outDir, assetName))
+    sums.push(`${getSha256(data)}  ${assetName}`)
+  }
+  await fs.writeFile(path.join(outDir, 'SHA256SUMS'), `${sums.join('\n')}\n`)
+  assetNames.push('SHA256SUMS')
+
+  const assetPaths = assetNames.map(assetName => path.join(outDir, assetName))
+  const githubOutput = process.env['GITHUB_OUTPUT']
+  if (githubOutput) {
+    appendFileSync(
+      githubOutput,
+      `assets<<__ASSETS__\n${assetPaths.join('\n')}\n__ASSETS__\n`,
+    )
+  }
+  logger.log('')
+  logger.success(`Collected ${assetNames.length} release assets in ${outDir}`)
+}
+
+main().catch((e: unknown) => {
+  logger.error(e)
+  process.exitCode = 1
+})